@@ -0,0 +1,183 @@
+// Command bench fires concurrent executions at the gogent library or a running server and reports
+// throughput, latency percentiles, database write rates and memory use.
+//
+//	go run ./cmd/bench -n 200 -c 16                       # mock executions in process
+//	go run ./cmd/bench -n 50 -c 4 -mock=false             # real Gemini calls in process
+//	go run ./cmd/bench -url http://localhost:8080 -token $GOGENT_TOKEN
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"gogent/internal/types"
+)
+
+// benchTarget runs one execution and returns its result
+type benchTarget interface {
+	Execute(ctx context.Context, request *types.MultiExecutionRequest) (*types.ExecutionResult, error)
+	// writes reports the store writes made so far, or nil when the target cannot see them
+	writes() *writeCounts
+	Close() error
+}
+
+// benchOptions are the command's flags
+type benchOptions struct {
+	executions     int
+	concurrency    int
+	configurations int
+	repetitions    int
+	prompt         string
+	models         string
+	mock           bool
+	url            string
+	token          string
+	apiKey         string
+	pollInterval   time.Duration
+	timeout        time.Duration
+	json           bool
+	verbose        bool
+}
+
+func main() {
+	var opts benchOptions
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	fs.IntVar(&opts.executions, "n", 100, "executions to run")
+	fs.IntVar(&opts.concurrency, "c", 8, "executions running at once")
+	fs.IntVar(&opts.configurations, "configs", 2, "configurations per execution")
+	fs.IntVar(&opts.repetitions, "repetitions", 1, "times each configuration is executed")
+	fs.StringVar(&opts.prompt, "prompt", "What is the capital of France?", "prompt every execution sends")
+	fs.StringVar(&opts.models, "models", "gemini-2.0-flash,gemini-2.5-flash", "comma-separated models the configurations cycle through")
+	fs.BoolVar(&opts.mock, "mock", true, "use mock responses instead of calling Gemini")
+	fs.StringVar(&opts.url, "url", os.Getenv("GOGENT_URL"), "REST server URL; executions run in process when empty")
+	fs.StringVar(&opts.token, "token", os.Getenv("GOGENT_TOKEN"), "JWT sent to the server as a Bearer token")
+	fs.StringVar(&opts.apiKey, "api-key", os.Getenv("GOGENT_API_KEY"), "GoGent API key sent to the server")
+	fs.DurationVar(&opts.pollInterval, "poll", 250*time.Millisecond, "how often a server execution's status is checked")
+	fs.DurationVar(&opts.timeout, "timeout", 10*time.Minute, "give up on the benchmark after this long")
+	fs.BoolVar(&opts.json, "json", false, "print the report as JSON")
+	fs.BoolVar(&opts.verbose, "v", false, "keep the library's and server client's logs")
+	fs.Parse(os.Args[1:])
+
+	if err := run(&opts); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run opens the target, runs the executions and prints the report
+func run(opts *benchOptions) error {
+	if opts.executions < 1 || opts.concurrency < 1 || opts.configurations < 1 || opts.repetitions < 1 {
+		return fmt.Errorf("-n, -c, -configs and -repetitions must be at least 1")
+	}
+	if !opts.verbose {
+		log.SetOutput(io.Discard)
+	}
+
+	var target benchTarget
+	var err error
+	if opts.url != "" {
+		target, err = newServerTarget(opts)
+	} else {
+		target, err = newLibraryTarget(opts)
+	}
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+	defer cancel()
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	report := runBenchmark(ctx, opts, target)
+	if err := target.Close(); err != nil {
+		return fmt.Errorf("failed to close target: %w", err)
+	}
+	// Closing stores the last batched logs, so writes are read afterwards
+	report.setWrites(target.writes())
+
+	if opts.json {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	report.print(os.Stdout)
+	return nil
+}
+
+// runBenchmark runs opts.executions executions, opts.concurrency at a time, sampling memory while they run
+func runBenchmark(ctx context.Context, opts *benchOptions, target benchTarget) *benchReport {
+	sampler := startMemorySampler(100 * time.Millisecond)
+
+	jobs := make(chan int)
+	samples := make(chan executionSample)
+	var wg sync.WaitGroup
+	for worker := 0; worker < opts.concurrency; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				request := benchRequest(opts, i)
+				start := time.Now()
+				result, err := target.Execute(ctx, request)
+				samples <- executionSample{latency: time.Since(start), result: result, err: err}
+			}
+		}()
+	}
+
+	start := time.Now()
+	go func() {
+		defer close(jobs)
+		for i := 0; i < opts.executions; i++ {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(samples)
+	}()
+
+	report := newBenchReport(opts)
+	for sample := range samples {
+		report.add(sample)
+	}
+	report.finish(time.Since(start), sampler.stop())
+	return report
+}
+
+// benchRequest builds the i-th execution: opts.configurations configurations cycling through the models
+func benchRequest(opts *benchOptions, i int) *types.MultiExecutionRequest {
+	models := splitList(opts.models)
+	if len(models) == 0 {
+		models = []string{"gemini-2.0-flash"}
+	}
+
+	request := &types.MultiExecutionRequest{
+		ExecutionRunName: fmt.Sprintf("bench-%d-%d", time.Now().Unix(), i),
+		Description:      "Benchmark execution",
+		BasePrompt:       opts.prompt,
+		Repetitions:      opts.repetitions,
+		DuplicatePolicy:  types.DuplicatePolicyAllow,
+	}
+	for j := 0; j < opts.configurations; j++ {
+		temperature := float32(j%10) / 10
+		request.Configurations = append(request.Configurations, types.APIConfiguration{
+			VariationName: fmt.Sprintf("variation-%d", j+1),
+			ModelName:     models[j%len(models)],
+			Temperature:   &temperature,
+		})
+	}
+	return request
+}
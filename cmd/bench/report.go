@@ -0,0 +1,235 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"slices"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"gogent/internal/types"
+)
+
+// executionSample is the outcome of one benchmark execution
+type executionSample struct {
+	latency time.Duration
+	result  *types.ExecutionResult
+	err     error
+}
+
+// latencySummary are percentiles of a set of latencies, in milliseconds
+type latencySummary struct {
+	Count int     `json:"count"`
+	Mean  float64 `json:"meanMs"`
+	P50   float64 `json:"p50Ms"`
+	P95   float64 `json:"p95Ms"`
+	P99   float64 `json:"p99Ms"`
+	Max   float64 `json:"maxMs"`
+}
+
+// memoryStats is the benchmark process's memory use while executions ran
+type memoryStats struct {
+	PeakHeapBytes  uint64 `json:"peakHeapBytes"`
+	TotalAllocated uint64 `json:"totalAllocatedBytes"`
+	GCCycles       uint32 `json:"gcCycles"`
+	PeakGoroutines int    `json:"peakGoroutines"`
+}
+
+// benchReport summarizes a benchmark
+type benchReport struct {
+	Target           string         `json:"target"`
+	Mock             bool           `json:"mock"`
+	Concurrency      int            `json:"concurrency"`
+	Configurations   int            `json:"configurationsPerExecution"`
+	Executions       int            `json:"executions"`
+	Succeeded        int            `json:"succeeded"`
+	Failed           int            `json:"failed"`
+	Variations       int            `json:"variations"`
+	FailedVariations int            `json:"failedVariations"`
+	DurationMs       float64        `json:"durationMs"`
+	ExecutionsPerS   float64        `json:"executionsPerSecond"`
+	VariationsPerS   float64        `json:"variationsPerSecond"`
+	Execution        latencySummary `json:"executionLatency"`
+	Variation        latencySummary `json:"variationLatency"`
+	Writes           *writeCounts   `json:"dbWrites,omitempty"`
+	WritesPerS       float64        `json:"dbWritesPerSecond,omitempty"`
+	RowsPerS         float64        `json:"dbRowsPerSecond,omitempty"`
+	Memory           memoryStats    `json:"memory"`
+	Errors           map[string]int `json:"errors,omitempty"`
+
+	executionLatencies []time.Duration
+	variationLatencies []time.Duration
+}
+
+// newBenchReport starts the report of a benchmark run with opts
+func newBenchReport(opts *benchOptions) *benchReport {
+	target := "library"
+	if opts.url != "" {
+		target = opts.url
+	}
+	return &benchReport{
+		Target:         target,
+		Mock:           opts.mock,
+		Concurrency:    opts.concurrency,
+		Configurations: opts.configurations,
+		Errors:         make(map[string]int),
+	}
+}
+
+// add records an execution's outcome
+func (r *benchReport) add(sample executionSample) {
+	r.Executions++
+	if sample.err != nil {
+		r.Failed++
+		r.Errors[sample.err.Error()]++
+		return
+	}
+	r.Succeeded++
+	r.executionLatencies = append(r.executionLatencies, sample.latency)
+	for _, variation := range sample.result.Results {
+		r.Variations++
+		if variation.Response.ResponseStatus == types.ResponseStatusError {
+			r.FailedVariations++
+		}
+		r.variationLatencies = append(r.variationLatencies, time.Duration(variation.ExecutionTime)*time.Millisecond)
+	}
+}
+
+// finish computes the rates and percentiles once every execution is done
+func (r *benchReport) finish(duration time.Duration, memory memoryStats) {
+	r.DurationMs = float64(duration.Milliseconds())
+	r.ExecutionsPerS = float64(r.Succeeded) / duration.Seconds()
+	r.VariationsPerS = float64(r.Variations) / duration.Seconds()
+	r.Execution = summarizeLatencies(r.executionLatencies)
+	r.Variation = summarizeLatencies(r.variationLatencies)
+	r.Memory = memory
+}
+
+// setWrites records the store writes the target made, if it could see them
+func (r *benchReport) setWrites(writes *writeCounts) {
+	if writes == nil || r.DurationMs == 0 {
+		return
+	}
+	r.Writes = writes
+	r.WritesPerS = float64(writes.Writes) / (r.DurationMs / 1000)
+	r.RowsPerS = float64(writes.Rows) / (r.DurationMs / 1000)
+}
+
+// print writes the report as a summary table
+func (r *benchReport) print(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Target\t%s (mock: %v)\n", r.Target, r.Mock)
+	fmt.Fprintf(tw, "Executions\t%d succeeded, %d failed, %d at a time, %d configurations each\n",
+		r.Succeeded, r.Failed, r.Concurrency, r.Configurations)
+	fmt.Fprintf(tw, "Duration\t%.2fs\n", r.DurationMs/1000)
+	fmt.Fprintf(tw, "Throughput\t%.2f executions/s, %.2f variations/s\n", r.ExecutionsPerS, r.VariationsPerS)
+	fmt.Fprintf(tw, "Execution latency\t%s\n", r.Execution)
+	fmt.Fprintf(tw, "Variation latency\t%s (%d of %d variations failed)\n", r.Variation, r.FailedVariations, r.Variations)
+	if r.Writes != nil {
+		fmt.Fprintf(tw, "DB writes\t%d writes (%.1f/s), %d rows (%.1f/s)\n", r.Writes.Writes, r.WritesPerS, r.Writes.Rows, r.RowsPerS)
+		kinds := make([]string, 0, len(r.Writes.ByKind))
+		for kind := range r.Writes.ByKind {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+		for _, kind := range kinds {
+			fmt.Fprintf(tw, "  %s\t%d rows\n", kind, r.Writes.ByKind[kind])
+		}
+	} else {
+		fmt.Fprintf(tw, "DB writes\tnot visible from outside the server\n")
+	}
+	fmt.Fprintf(tw, "Memory\tpeak heap %.1f MiB, %.1f MiB allocated, %d GC cycles, peak %d goroutines\n",
+		float64(r.Memory.PeakHeapBytes)/(1<<20), float64(r.Memory.TotalAllocated)/(1<<20), r.Memory.GCCycles, r.Memory.PeakGoroutines)
+	tw.Flush()
+
+	for message, count := range r.Errors {
+		fmt.Fprintf(w, "❌ %dx %s\n", count, message)
+	}
+}
+
+// String formats the summary for the report table
+func (s latencySummary) String() string {
+	if s.Count == 0 {
+		return "no samples"
+	}
+	return fmt.Sprintf("mean %.0fms, p50 %.0fms, p95 %.0fms, p99 %.0fms, max %.0fms", s.Mean, s.P50, s.P95, s.P99, s.Max)
+}
+
+// summarizeLatencies computes the mean and percentiles of latencies
+func summarizeLatencies(latencies []time.Duration) latencySummary {
+	if len(latencies) == 0 {
+		return latencySummary{}
+	}
+	sorted := slices.Clone(latencies)
+	slices.Sort(sorted)
+
+	var total time.Duration
+	for _, latency := range sorted {
+		total += latency
+	}
+	ms := func(d time.Duration) float64 { return float64(d) / float64(time.Millisecond) }
+	return latencySummary{
+		Count: len(sorted),
+		Mean:  ms(total) / float64(len(sorted)),
+		P50:   ms(percentile(sorted, 0.50)),
+		P95:   ms(percentile(sorted, 0.95)),
+		P99:   ms(percentile(sorted, 0.99)),
+		Max:   ms(sorted[len(sorted)-1]),
+	}
+}
+
+// percentile returns the nearest-rank percentile p of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(float64(len(sorted))*p+0.999999) - 1
+	return sorted[max(0, min(rank, len(sorted)-1))]
+}
+
+// memorySampler tracks peak heap and goroutines while a benchmark runs
+type memorySampler struct {
+	start   runtime.MemStats
+	ticker  *time.Ticker
+	done    chan struct{}
+	stopped chan memoryStats
+}
+
+// startMemorySampler samples memory every interval until stop is called
+func startMemorySampler(interval time.Duration) *memorySampler {
+	s := &memorySampler{
+		ticker:  time.NewTicker(interval),
+		done:    make(chan struct{}),
+		stopped: make(chan memoryStats),
+	}
+	runtime.ReadMemStats(&s.start)
+
+	go func() {
+		stats := memoryStats{PeakHeapBytes: s.start.HeapAlloc, PeakGoroutines: runtime.NumGoroutine()}
+		var current runtime.MemStats
+		sample := func() {
+			runtime.ReadMemStats(&current)
+			stats.PeakHeapBytes = max(stats.PeakHeapBytes, current.HeapAlloc)
+			stats.PeakGoroutines = max(stats.PeakGoroutines, runtime.NumGoroutine())
+		}
+		for {
+			select {
+			case <-s.ticker.C:
+				sample()
+			case <-s.done:
+				sample()
+				stats.TotalAllocated = current.TotalAlloc - s.start.TotalAlloc
+				stats.GCCycles = current.NumGC - s.start.NumGC
+				s.stopped <- stats
+				return
+			}
+		}
+	}()
+	return s
+}
+
+// stop takes a last sample and returns what was seen
+func (s *memorySampler) stop() memoryStats {
+	s.ticker.Stop()
+	close(s.done)
+	return <-s.stopped
+}
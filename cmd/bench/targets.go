@@ -0,0 +1,297 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"gogent/internal/gogent"
+	"gogent/internal/types"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/joho/godotenv"
+)
+
+// libraryTarget runs executions with the gogent library in process. Runs are stored in MySQL when
+// DB_URL is set and in memory otherwise; either way, every store write is counted.
+type libraryTarget struct {
+	client *gogent.Client
+	store  *countingStore
+	userID string
+	// database is the store's own connection pool when runs go to MySQL
+	database *sql.DB
+}
+
+// newLibraryTarget opens the library with the keys from the environment
+func newLibraryTarget(opts *benchOptions) (*libraryTarget, error) {
+	godotenv.Load()
+
+	config := &types.GeminiClientConfig{
+		APIKey:      os.Getenv("GEMINI_API_KEY"),
+		MaxRetries:  3,
+		TimeoutSecs: 30,
+	}
+	if opts.mock {
+		config.APIKey = ""
+	} else if config.APIKey == "" {
+		return nil, fmt.Errorf("GEMINI_API_KEY is required with -mock=false")
+	}
+
+	dbURL := os.Getenv("DB_URL")
+	if dbURL == "" {
+		store := &countingStore{Store: gogent.NewMemoryStore()}
+		client := gogent.NewInMemoryClient(config)
+		client.SetStore(store)
+		return &libraryTarget{client: client, store: store, userID: "bench"}, nil
+	}
+
+	client, err := gogent.NewClient(dbURL, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gogent client: %w", err)
+	}
+	database, err := sql.Open("mysql", dbURL)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	store := &countingStore{Store: gogent.NewSQLStore(database)}
+	client.SetStore(store)
+	return &libraryTarget{client: client, store: store, userID: "bench", database: database}, nil
+}
+
+// Execute runs the execution in process
+func (t *libraryTarget) Execute(ctx context.Context, request *types.MultiExecutionRequest) (*types.ExecutionResult, error) {
+	return t.client.ExecuteMultiVariation(ctx, t.userID, request)
+}
+
+// writes reports the store writes made so far
+func (t *libraryTarget) writes() *writeCounts {
+	counts := t.store.counts()
+	return &counts
+}
+
+// Close closes the client, storing the logs it still has queued
+func (t *libraryTarget) Close() error {
+	err := t.client.Close()
+	if t.database != nil {
+		t.database.Close()
+	}
+	return err
+}
+
+// serverTarget submits executions to a gogent REST server and polls them until they finish
+type serverTarget struct {
+	url           string
+	authorization string
+	mock          bool
+	pollInterval  time.Duration
+	http          *http.Client
+}
+
+// newServerTarget checks the credentials for the server named by -url
+func newServerTarget(opts *benchOptions) (*serverTarget, error) {
+	authorization := ""
+	switch {
+	case opts.apiKey != "":
+		authorization = "ApiKey " + opts.apiKey
+	case opts.token != "":
+		authorization = "Bearer " + opts.token
+	default:
+		return nil, fmt.Errorf("-api-key or -token is required with -url")
+	}
+	return &serverTarget{
+		url:           strings.TrimSuffix(opts.url, "/"),
+		authorization: authorization,
+		mock:          opts.mock,
+		pollInterval:  opts.pollInterval,
+		http:          &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// Execute submits the execution and waits for the server to finish it
+func (t *serverTarget) Execute(ctx context.Context, request *types.MultiExecutionRequest) (*types.ExecutionResult, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+	var submitted struct {
+		ExecutionRun struct {
+			ID string `json:"id"`
+		} `json:"executionRun"`
+	}
+	if err := t.call(ctx, http.MethodPost, "/api/execute", body, &submitted); err != nil {
+		return nil, err
+	}
+
+	ticker := time.NewTicker(t.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+
+		var status struct {
+			Status string                 `json:"status"`
+			Error  string                 `json:"error"`
+			Result *types.ExecutionResult `json:"result"`
+		}
+		if err := t.call(ctx, http.MethodGet, "/api/execution-runs/status/"+submitted.ExecutionRun.ID, nil, &status); err != nil {
+			return nil, err
+		}
+		switch status.Status {
+		case "completed":
+			if status.Result == nil {
+				return nil, fmt.Errorf("execution %s completed without a result", submitted.ExecutionRun.ID)
+			}
+			return status.Result, nil
+		case "failed", "not_found":
+			return nil, fmt.Errorf("execution %s %s: %s", submitted.ExecutionRun.ID, status.Status, status.Error)
+		}
+	}
+}
+
+// call sends a request to the server and decodes its JSON response into out
+func (t *serverTarget) call(ctx context.Context, method, path string, body []byte, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, t.url+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", t.authorization)
+	req.Header.Set("Content-Type", "application/json")
+	if t.mock {
+		req.Header.Set("X-Use-Mock", "true")
+	}
+
+	resp, err := t.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s %s returned %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// writes returns nil: the server's store writes are not visible to the benchmark
+func (t *serverTarget) writes() *writeCounts {
+	return nil
+}
+
+// Close does nothing; the server keeps running
+func (t *serverTarget) Close() error {
+	return nil
+}
+
+// writeCounts are the store writes made during a benchmark. A write is one call to the store;
+// batched execution logs make one write for many rows.
+type writeCounts struct {
+	Writes int64            `json:"writes"`
+	Rows   int64            `json:"rows"`
+	ByKind map[string]int64 `json:"byKind"`
+}
+
+// countingStore counts the writes made through a store
+type countingStore struct {
+	gogent.Store
+	runs, runStatuses, configurations, tools, requests, responses atomic.Int64
+	logWrites, logRows, functionCalls, comparisons                atomic.Int64
+}
+
+// counts returns the writes made so far
+func (s *countingStore) counts() writeCounts {
+	byKind := map[string]int64{
+		"execution_runs":     s.runs.Load(),
+		"run_status_updates": s.runStatuses.Load(),
+		"api_configurations": s.configurations.Load(),
+		"run_function_tools": s.tools.Load(),
+		"api_requests":       s.requests.Load(),
+		"api_responses":      s.responses.Load(),
+		"execution_logs":     s.logRows.Load(),
+		"function_calls":     s.functionCalls.Load(),
+		"comparison_results": s.comparisons.Load(),
+	}
+	counts := writeCounts{ByKind: byKind}
+	for kind, rows := range byKind {
+		counts.Rows += rows
+		if kind != "execution_logs" {
+			counts.Writes += rows
+		}
+	}
+	counts.Writes += s.logWrites.Load()
+	return counts
+}
+
+func (s *countingStore) CreateExecutionRun(ctx context.Context, run *types.ExecutionRun) error {
+	s.runs.Add(1)
+	return s.Store.CreateExecutionRun(ctx, run)
+}
+
+func (s *countingStore) UpdateExecutionRunStatus(ctx context.Context, id, status, errorMessage string) error {
+	s.runStatuses.Add(1)
+	return s.Store.UpdateExecutionRunStatus(ctx, id, status, errorMessage)
+}
+
+func (s *countingStore) CreateAPIConfiguration(ctx context.Context, userID string, config *types.APIConfiguration) error {
+	s.configurations.Add(1)
+	return s.Store.CreateAPIConfiguration(ctx, userID, config)
+}
+
+func (s *countingStore) CreateRunFunctionTools(ctx context.Context, userID, executionRunID string, tools []types.Tool) error {
+	s.tools.Add(1)
+	return s.Store.CreateRunFunctionTools(ctx, userID, executionRunID, tools)
+}
+
+func (s *countingStore) CreateAPIRequest(ctx context.Context, userID string, request *types.APIRequest) error {
+	s.requests.Add(1)
+	return s.Store.CreateAPIRequest(ctx, userID, request)
+}
+
+func (s *countingStore) CreateAPIResponse(ctx context.Context, userID string, response *types.APIResponse) error {
+	s.responses.Add(1)
+	return s.Store.CreateAPIResponse(ctx, userID, response)
+}
+
+func (s *countingStore) CreateExecutionLog(ctx context.Context, entry *types.ExecutionLog) error {
+	s.logWrites.Add(1)
+	s.logRows.Add(1)
+	return s.Store.CreateExecutionLog(ctx, entry)
+}
+
+func (s *countingStore) CreateExecutionLogs(ctx context.Context, entries []*types.ExecutionLog) error {
+	s.logWrites.Add(1)
+	s.logRows.Add(int64(len(entries)))
+	return s.Store.CreateExecutionLogs(ctx, entries)
+}
+
+func (s *countingStore) CreateFunctionCall(ctx context.Context, call *types.FunctionCall) error {
+	s.functionCalls.Add(1)
+	return s.Store.CreateFunctionCall(ctx, call)
+}
+
+func (s *countingStore) CreateComparisonResult(ctx context.Context, comparison *types.ComparisonResult) error {
+	s.comparisons.Add(1)
+	return s.Store.CreateComparisonResult(ctx, comparison)
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
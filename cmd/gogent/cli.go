@@ -0,0 +1,710 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gogent/internal/gogent"
+	"gogent/internal/types"
+)
+
+// cliBackend carries out CLI commands, either with the gogent library in process or on a server over gRPC
+type cliBackend interface {
+	Execute(ctx context.Context, request *types.MultiExecutionRequest) (*types.ExecutionResult, error)
+	ListExecutionRuns(ctx context.Context, limit, offset int32) ([]*types.ExecutionRun, error)
+	GetExecutionResult(ctx context.Context, executionRunID string) (*types.ExecutionResult, error)
+	ListFunctions(ctx context.Context) ([]*types.FunctionDefinition, error)
+	// CallFunction runs a function definition outside of a run; the call is recorded like a test
+	CallFunction(ctx context.Context, functionID string, args map[string]interface{}) (*types.FunctionTestResult, error)
+	// auditExport records that runs were exported to destination
+	auditExport(ctx context.Context, runs int, destination string)
+	Close() error
+}
+
+// cliOptions are the flags shared by every CLI command
+type cliOptions struct {
+	server string
+	token  string
+	apiKey string
+	userID string
+	mock   bool
+	json   bool
+}
+
+// register adds the shared flags to a command's flag set, defaulting to the GOGENT_* environment variables
+func (o *cliOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.server, "server", os.Getenv("GOGENT_SERVER"), "gRPC server address (host:port); runs in process when empty")
+	fs.StringVar(&o.token, "token", os.Getenv("GOGENT_TOKEN"), "JWT sent to the server as a Bearer token")
+	fs.StringVar(&o.apiKey, "api-key", os.Getenv("GOGENT_API_KEY"), "GoGent API key sent to the server")
+	fs.StringVar(&o.userID, "user", envOrDefault("GOGENT_USER", "cli"), "user ID owning runs executed in process")
+	fs.BoolVar(&o.mock, "mock", false, "use mock responses instead of calling Gemini")
+	fs.BoolVar(&o.json, "json", false, "print JSON instead of a summary")
+}
+
+// backend connects to the server when one is set, otherwise it opens the library in process
+func (o *cliOptions) backend() (cliBackend, error) {
+	if o.server != "" {
+		return newRemoteBackend(o)
+	}
+	return newLocalBackend(o)
+}
+
+// runCLI runs a CLI command: run, runs, functions, export, optimize, prune, restore, db, openapi or tui
+func runCLI(command string, args []string) error {
+	ctx := context.Background()
+	switch command {
+	case "run":
+		return runCommand(ctx, args)
+	case "runs":
+		if len(args) == 0 {
+			return fmt.Errorf("usage: gogent runs list|show <id>")
+		}
+		switch args[0] {
+		case "list":
+			return runsListCommand(ctx, args[1:])
+		case "show":
+			return runsShowCommand(ctx, args[1:])
+		}
+		return fmt.Errorf("unknown runs command: %s", args[0])
+	case "functions":
+		if len(args) == 0 || args[0] != "list" {
+			return fmt.Errorf("usage: gogent functions list")
+		}
+		return functionsListCommand(ctx, args[1:])
+	case "export":
+		return exportCommand(ctx, args)
+	case "optimize":
+		return optimizeCommand(ctx, args)
+	case "prune":
+		return pruneCommand(ctx, args)
+	case "restore":
+		return restoreCommand(ctx, args)
+	case "db":
+		if len(args) == 0 || args[0] != "verify" {
+			return fmt.Errorf("usage: gogent db verify [--repair]")
+		}
+		return dbVerifyCommand(ctx, args[1:])
+	case "openapi":
+		return openAPICommand(args)
+	case "tui":
+		return tuiCommand(ctx, args)
+	case "telemetry":
+		if len(args) == 0 || args[0] != "preview" {
+			return fmt.Errorf("usage: gogent telemetry preview")
+		}
+		return telemetryPreviewCommand(ctx, args[1:])
+	}
+	return fmt.Errorf("unknown command: %s", command)
+}
+
+// runCommand executes the run spec in a YAML or JSON file and prints its result
+func runCommand(ctx context.Context, args []string) error {
+	var opts cliOptions
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	opts.register(fs)
+	file := fs.String("f", "", "run spec file (YAML or JSON)")
+	dryRun := fs.Bool("dry-run", false, "print each variation's provider request instead of executing the run")
+	if _, err := parseCommandFlags(fs, args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return fmt.Errorf("usage: gogent run -f spec.yaml [--dry-run]")
+	}
+
+	request, err := loadExecutionRequest(*file)
+	if err != nil {
+		return err
+	}
+	request.DryRun = request.DryRun || *dryRun
+
+	backend, err := opts.backend()
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	result, err := backend.Execute(ctx, request)
+	if err != nil {
+		return err
+	}
+	if opts.json {
+		return printJSON(os.Stdout, result)
+	}
+	if request.DryRun {
+		return printProviderPayloads(os.Stdout, result)
+	}
+	printExecutionResult(os.Stdout, result)
+	return nil
+}
+
+// optimizeCommand runs a spec's sweep, then rounds of proposed configurations, and prints the best one found
+func optimizeCommand(ctx context.Context, args []string) error {
+	var opts cliOptions
+	fs := flag.NewFlagSet("optimize", flag.ContinueOnError)
+	opts.register(fs)
+	file := fs.String("f", "", "run spec file with a sweep (YAML or JSON)")
+	rounds := fs.Int("rounds", 0, "rounds after the initial sweep (overrides the spec's optimize block)")
+	batch := fs.Int("batch", 0, "configurations proposed per round (overrides the spec's optimize block)")
+	seed := fs.Int64("seed", 0, "seed for the proposals (overrides the spec's optimize block)")
+	if _, err := parseCommandFlags(fs, args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return fmt.Errorf("usage: gogent optimize -f spec.yaml [--rounds n] [--batch n]")
+	}
+
+	spec, err := loadRunSpec(*file)
+	if err != nil {
+		return err
+	}
+	config := spec.Optimize
+	if config == nil {
+		config = &types.OptimizationConfig{}
+	}
+	if *rounds != 0 {
+		config.Rounds = *rounds
+	}
+	if *batch != 0 {
+		config.BatchSize = *batch
+	}
+	if *seed != 0 {
+		config.Seed = *seed
+	}
+
+	backend, err := opts.backend()
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	result, err := gogent.Optimize(ctx, gogent.RunSpecRequest(spec), config, backend.Execute)
+	if err != nil {
+		return err
+	}
+	if opts.json {
+		return printJSON(os.Stdout, result)
+	}
+
+	for _, round := range result.Rounds {
+		fmt.Printf("🔁 Round %d (%s): best %s scored %.3f\n", round.Round, round.ExecutionRunID,
+			round.Points[0].VariationName, round.BestScore)
+	}
+	fmt.Printf("\n🏆 Best configuration: %s, %s %.3f (stopped: %s, seed %d)\n", result.Best.VariationName,
+		result.Metric, result.Best.Score, result.StopReason, result.Seed)
+	return nil
+}
+
+// runsListCommand prints a page of execution runs
+func runsListCommand(ctx context.Context, args []string) error {
+	var opts cliOptions
+	fs := flag.NewFlagSet("runs list", flag.ContinueOnError)
+	opts.register(fs)
+	limit := fs.Int("limit", 20, "maximum number of runs")
+	offset := fs.Int("offset", 0, "number of runs to skip")
+	if _, err := parseCommandFlags(fs, args); err != nil {
+		return err
+	}
+
+	backend, err := opts.backend()
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	runs, err := backend.ListExecutionRuns(ctx, int32(*limit), int32(*offset))
+	if err != nil {
+		return err
+	}
+	if opts.json {
+		return printJSON(os.Stdout, runs)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tSTATUS\tCREATED")
+	for _, run := range runs {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", run.ID, run.Name, run.Status, run.CreatedAt.Format(time.RFC3339))
+	}
+	return w.Flush()
+}
+
+// runsShowCommand prints one execution run's result, or with --spec the run spec it executed
+func runsShowCommand(ctx context.Context, args []string) error {
+	var opts cliOptions
+	fs := flag.NewFlagSet("runs show", flag.ContinueOnError)
+	opts.register(fs)
+	showSpec := fs.Bool("spec", false, "print the run's resolved run spec as YAML")
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: gogent runs show <id> [--spec]")
+	}
+
+	backend, err := opts.backend()
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	result, err := backend.GetExecutionResult(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	if *showSpec {
+		if result.ExecutionRun.RunSpec == nil {
+			return fmt.Errorf("execution run %s has no stored run spec", result.ExecutionRun.ID)
+		}
+		if opts.json {
+			return printJSON(os.Stdout, result.ExecutionRun.RunSpec)
+		}
+		data, err := gogent.MarshalRunSpecYAML(result.ExecutionRun.RunSpec)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if opts.json {
+		return printJSON(os.Stdout, result)
+	}
+	printExecutionResult(os.Stdout, result)
+	return nil
+}
+
+// functionsListCommand prints the function definitions available to the user
+func functionsListCommand(ctx context.Context, args []string) error {
+	var opts cliOptions
+	fs := flag.NewFlagSet("functions list", flag.ContinueOnError)
+	opts.register(fs)
+	if _, err := parseCommandFlags(fs, args); err != nil {
+		return err
+	}
+
+	backend, err := opts.backend()
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	functions, err := backend.ListFunctions(ctx)
+	if err != nil {
+		return err
+	}
+	if opts.json {
+		return printJSON(os.Stdout, functions)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tOWNER\tENDPOINT\tDESCRIPTION")
+	for _, function := range functions {
+		endpoint := function.EndpointURL
+		if endpoint == "" {
+			endpoint = "(mock)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", function.Name, function.UserID, endpoint, function.Description)
+	}
+	return w.Flush()
+}
+
+// exportCommand writes the results of recent execution runs as JSON lines, one run per line
+func exportCommand(ctx context.Context, args []string) error {
+	var opts cliOptions
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	opts.register(fs)
+	output := fs.String("o", "", "output file (default stdout)")
+	limit := fs.Int("limit", 100, "maximum number of runs")
+	if _, err := parseCommandFlags(fs, args); err != nil {
+		return err
+	}
+
+	backend, err := opts.backend()
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	runs, err := backend.ListExecutionRuns(ctx, int32(*limit), 0)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("failed to create export file: %w", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	encoder := json.NewEncoder(out)
+	for _, run := range runs {
+		result, err := backend.GetExecutionResult(ctx, run.ID)
+		if err != nil {
+			return fmt.Errorf("failed to export run %s: %w", run.ID, err)
+		}
+		if err := encoder.Encode(result); err != nil {
+			return fmt.Errorf("failed to write run %s: %w", run.ID, err)
+		}
+	}
+	destination := "stdout"
+	if *output != "" {
+		destination = *output
+		fmt.Fprintf(os.Stderr, "📦 Exported %d runs to %s\n", len(runs), *output)
+	}
+	backend.auditExport(ctx, len(runs), destination)
+	return nil
+}
+
+// pruneBatchPause spaces out the prune command's delete batches
+const pruneBatchPause = 100 * time.Millisecond
+
+// pruneCommand deletes old execution history from the database, either everything older than
+// --older-than or whatever the retention policies have expired
+func pruneCommand(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("prune", flag.ContinueOnError)
+	olderThan := fs.String("older-than", "", "prune history older than this, e.g. 90d or 36h (default: apply the retention policies)")
+	userID := fs.String("user", "", "only prune this user's history (with --older-than)")
+	archivePath := fs.String("archive", "", "write pruned rows to this file as JSON lines before deleting them")
+	archiveTo := fs.String("archive-to", os.Getenv("ARCHIVE_SINK"), "archive pruned runs to s3://bucket/prefix, gs://bucket/prefix or a directory")
+	batchSize := fs.Int("batch", 500, "rows deleted per statement")
+	jsonOutput := fs.Bool("json", false, "print JSON instead of a summary")
+	if _, err := parseCommandFlags(fs, args); err != nil {
+		return err
+	}
+	if *userID != "" && *olderThan == "" {
+		return fmt.Errorf("--user needs --older-than; the retention policies already apply per user")
+	}
+	if os.Getenv("DB_URL") == "" {
+		return fmt.Errorf("prune needs DB_URL to be set")
+	}
+
+	backend, err := newLocalBackend(&cliOptions{mock: true})
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	opts := gogent.PruneOptions{BatchSize: *batchSize, Pause: pruneBatchPause}
+	if *archivePath != "" {
+		file, err := os.OpenFile(*archivePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return fmt.Errorf("failed to open archive file: %w", err)
+		}
+		defer file.Close()
+		opts.Archive = file
+	}
+	if *archiveTo != "" {
+		sink, err := newArchiveSink(*archiveTo)
+		if err != nil {
+			return err
+		}
+		opts.Sink = sink
+	}
+
+	var report *types.PruneReport
+	if *olderThan != "" {
+		age, err := parseAge(*olderThan)
+		if err != nil {
+			return err
+		}
+		report, err = backend.client.PruneOlderThan(ctx, time.Now().Add(-age), *userID, opts)
+	} else {
+		report, err = backend.client.PruneExpiredHistory(ctx, opts)
+	}
+	if report != nil {
+		if *jsonOutput {
+			printJSON(os.Stdout, report)
+		} else {
+			printPruneReport(os.Stdout, report)
+		}
+	}
+	return err
+}
+
+// restoreCommand re-imports a run archived by prune
+func restoreCommand(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	from := fs.String("from", os.Getenv("ARCHIVE_SINK"), "archive holding the run: s3://bucket/prefix, gs://bucket/prefix or a directory")
+	jsonOutput := fs.Bool("json", false, "print JSON instead of a summary")
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 || *from == "" {
+		return fmt.Errorf("usage: gogent restore <run-id> --from s3://bucket/prefix|gs://bucket/prefix|dir")
+	}
+	if os.Getenv("DB_URL") == "" {
+		return fmt.Errorf("restore needs DB_URL to be set")
+	}
+
+	sink, err := newArchiveSink(*from)
+	if err != nil {
+		return err
+	}
+	backend, err := newLocalBackend(&cliOptions{mock: true})
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	report, err := backend.client.RestoreArchivedRun(ctx, sink, positional[0])
+	if err != nil {
+		return err
+	}
+	if *jsonOutput {
+		return printJSON(os.Stdout, report)
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TABLE\tRESTORED")
+	tables := make([]string, 0, len(report.Restored))
+	for table := range report.Restored {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	for _, table := range tables {
+		fmt.Fprintf(tw, "%s\t%d\n", table, report.Restored[table])
+	}
+	tw.Flush()
+	if report.Skipped > 0 {
+		fmt.Printf("⏭️ Skipped %d rows already in the database\n", report.Skipped)
+	}
+	return nil
+}
+
+// dbVerifyCommand reports the rows whose references point at missing rows and, with --repair,
+// deletes them or clears the references
+func dbVerifyCommand(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("db verify", flag.ContinueOnError)
+	repair := fs.Bool("repair", false, "delete orphaned rows and clear optional references to missing rows")
+	jsonOutput := fs.Bool("json", false, "print JSON instead of a summary")
+	if _, err := parseCommandFlags(fs, args); err != nil {
+		return err
+	}
+	if os.Getenv("DB_URL") == "" {
+		return fmt.Errorf("db verify needs DB_URL to be set")
+	}
+
+	backend, err := newLocalBackend(&cliOptions{mock: true})
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	report, err := backend.client.VerifyIntegrity(ctx, *repair)
+	if err != nil {
+		return err
+	}
+	if *jsonOutput {
+		return printJSON(os.Stdout, report)
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "REFERENCE\tORPHANS\tREPAIR")
+	for _, check := range report.Checks {
+		fmt.Fprintf(tw, "%s.%s -> %s\t%d\t%s\n", check.Table, check.Column, check.References, check.Orphans, check.Action)
+	}
+	tw.Flush()
+	switch {
+	case report.Orphans == 0:
+		fmt.Println("✅ No orphaned rows")
+	case report.Repaired:
+		fmt.Printf("🧹 Repaired %d orphaned rows\n", report.Orphans)
+	default:
+		fmt.Printf("⚠️ Found %d orphaned rows; run with --repair to fix them\n", report.Orphans)
+	}
+	return nil
+}
+
+// telemetryPreviewCommand prints the telemetry report covering the last day, exactly as the server
+// would send it, and whether the environment opts in
+func telemetryPreviewCommand(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("telemetry preview", flag.ContinueOnError)
+	if _, err := parseCommandFlags(fs, args); err != nil {
+		return err
+	}
+	if os.Getenv("DB_URL") == "" {
+		return fmt.Errorf("telemetry preview needs DB_URL to be set")
+	}
+	settings, err := loadTelemetrySettings()
+	if err != nil {
+		return err
+	}
+
+	backend, err := newLocalBackend(&cliOptions{mock: true})
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	now := time.Now()
+	report, err := backend.client.BuildTelemetryReport(ctx, now.Add(-telemetryInterval), now, settings.epsilon)
+	if err != nil {
+		return err
+	}
+	if settings.enabled {
+		fmt.Fprintf(os.Stderr, "📡 Telemetry is on: this is sent to %s every %s\n", settings.endpoint, telemetryInterval)
+	} else {
+		fmt.Fprintln(os.Stderr, "📴 Telemetry is off: nothing is sent. Set TELEMETRY_ENABLED=true and TELEMETRY_ENDPOINT to opt in")
+	}
+	return printJSON(os.Stdout, report)
+}
+
+// parseAge parses an age in days ("90d") or as a Go duration ("36h")
+func parseAge(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 1 {
+			return 0, fmt.Errorf("invalid age %q: use a positive number of days like 90d", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	age, err := time.ParseDuration(value)
+	if err != nil || age <= 0 {
+		return 0, fmt.Errorf("invalid age %q: use days like 90d or a duration like 36h", value)
+	}
+	return age, nil
+}
+
+// printPruneReport prints the rows pruned from each table
+func printPruneReport(w io.Writer, report *types.PruneReport) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TABLE\tDELETED")
+	for _, table := range []string{"execution_runs", "api_requests", "api_responses", "execution_logs", "function_calls"} {
+		fmt.Fprintf(tw, "%s\t%d\n", table, report.Deleted[table])
+	}
+	tw.Flush()
+	if report.Archived > 0 {
+		fmt.Fprintf(w, "📦 Archived %d rows\n", report.Archived)
+	}
+}
+
+// parseCommandFlags parses flags that may come before or after positional arguments and returns the positional ones
+func parseCommandFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// loadRunSpec reads a run spec from a YAML or JSON file
+func loadRunSpec(path string) (*types.RunSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run spec: %w", err)
+	}
+	spec, err := gogent.ParseRunSpec(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return spec, nil
+}
+
+// loadExecutionRequest reads a run spec from a YAML or JSON file and returns the request it declares
+func loadExecutionRequest(path string) (*types.MultiExecutionRequest, error) {
+	spec, err := loadRunSpec(path)
+	if err != nil {
+		return nil, err
+	}
+	if spec.Optimize != nil {
+		return nil, fmt.Errorf("%s has an optimize block; run it with gogent optimize -f %s", path, path)
+	}
+	return gogent.RunSpecRequest(spec), nil
+}
+
+// printExecutionResult prints a summary of an execution result
+func printExecutionResult(w io.Writer, result *types.ExecutionResult) {
+	fmt.Fprintf(w, "🎯 %s (%s)\n", result.ExecutionRun.Name, result.ExecutionRun.ID)
+	for _, variation := range result.Results {
+		label := variation.Configuration.VariationName
+		if variation.Repetition > 1 {
+			label = fmt.Sprintf("%s #%d", label, variation.Repetition)
+		}
+		fmt.Fprintf(w, "\n🔹 %s [%s] %s, %dms\n", label, variation.Configuration.ModelName,
+			variation.Response.ResponseStatus, variation.Response.ResponseTimeMs)
+		if variation.Response.ErrorMessage != "" {
+			fmt.Fprintf(w, "   ❌ %s\n", variation.Response.ErrorMessage)
+			continue
+		}
+		fmt.Fprintf(w, "   %s\n", strings.ReplaceAll(strings.TrimSpace(variation.Response.ResponseText), "\n", "\n   "))
+	}
+
+	fmt.Fprintf(w, "\n✅ %d successful, %d failed in %dms\n", result.SuccessCount, result.ErrorCount, result.TotalTime)
+	if result.Comparison != nil {
+		for _, variation := range result.Results {
+			if variation.Configuration.ID == result.Comparison.BestConfigurationID {
+				fmt.Fprintf(w, "🏆 Best configuration: %s\n", variation.Configuration.VariationName)
+				break
+			}
+		}
+	}
+	for _, accuracy := range result.Accuracy {
+		fmt.Fprintf(w, "🎯 %s accuracy: %d/%d\n", accuracy.VariationName, accuracy.Passed, accuracy.Cases)
+	}
+	if report := result.SweepReport; report != nil {
+		for _, parameter := range report.Parameters {
+			fmt.Fprintf(w, "📈 %s: best %g, top region %g..%g\n", parameter.Parameter,
+				parameter.BestValue, parameter.RegionMin, parameter.RegionMax)
+		}
+	}
+}
+
+// printProviderPayloads prints the provider request of each configuration of a dry run
+func printProviderPayloads(w io.Writer, result *types.ExecutionResult) error {
+	fmt.Fprintf(w, "🧪 Dry run: %s\n", result.ExecutionRun.Name)
+	for _, payload := range result.Payloads {
+		fmt.Fprintf(w, "\n🔹 %s [%s %s]\n", payload.Configuration.VariationName, payload.Provider, payload.ModelName)
+		for _, verdict := range payload.GuardVerdicts {
+			if verdict.Triggered {
+				fmt.Fprintf(w, "   🛡️ %s guard: %s\n", verdict.Guard, verdict.Action)
+			}
+		}
+		if payload.RetrievalSkipped {
+			fmt.Fprintln(w, "   ⚠️ Retrieved document chunks are left out of the context")
+		}
+		if payload.Blocked {
+			fmt.Fprintln(w, "   ⛔ Blocked by an input guard; nothing would be sent")
+			continue
+		}
+		var body bytes.Buffer
+		if err := json.Indent(&body, payload.Body, "   ", "  "); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "   %s\n", body.String())
+	}
+	return nil
+}
+
+// printJSON prints a value as indented JSON
+func printJSON(w io.Writer, value interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// envOrDefault returns an environment variable, or fallback when it is unset
+func envOrDefault(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"gogent/internal/gogent"
+	"gogent/internal/ollama"
+	"gogent/internal/types"
+
+	"github.com/joho/godotenv"
+)
+
+// localBackend runs CLI commands with the gogent library in process. Runs are stored in MySQL when
+// DB_URL is set and kept in memory for the life of the command otherwise.
+type localBackend struct {
+	client *gogent.Client
+	userID string
+	mock   bool
+}
+
+// newLocalBackend opens the library with the keys from the environment
+func newLocalBackend(opts *cliOptions) (*localBackend, error) {
+	if err := godotenv.Load(); err != nil {
+		log.Printf("⚠️ Warning: .env file not found: %v", err)
+	}
+
+	config := &types.GeminiClientConfig{
+		APIKey:            os.Getenv("GEMINI_API_KEY"),
+		OpenWeatherAPIKey: os.Getenv("OPENWEATHER_API_KEY"),
+		Neo4jURL:          os.Getenv("NEO4J_URL"),
+		Neo4jUsername:     os.Getenv("NEO4J_USERNAME"),
+		Neo4jPassword:     os.Getenv("NEO4J_PASSWORD"),
+		Neo4jDatabase:     os.Getenv("NEO4J_DATABASE"),
+		ProjectID:         os.Getenv("GOOGLE_CLOUD_PROJECT"),
+		Region:            os.Getenv("GOOGLE_CLOUD_LOCATION"),
+		OllamaURL:         envOrDefault("OLLAMA_URL", ollama.DefaultBaseURL),
+		WebSearchProvider: os.Getenv("WEB_SEARCH_PROVIDER"),
+		WebSearchAPIKey:   os.Getenv("WEB_SEARCH_API_KEY"),
+		SQLQueryDriver:    os.Getenv("QUERY_SQL_DRIVER"),
+		SQLQueryDSN:       os.Getenv("QUERY_SQL_DSN"),
+		MaxRetries:        3,
+		TimeoutSecs:       30,
+	}
+	redaction, err := loadRedactionPolicy()
+	if err != nil {
+		return nil, err
+	}
+	config.Redaction = redaction
+	minLogLevel, err := loadMinStoredLogLevel()
+	if err != nil {
+		return nil, err
+	}
+	config.MinStoredLogLevel = minLogLevel
+	config.AllowPrivateFunctionEndpoints = os.Getenv("ALLOW_PRIVATE_FUNCTION_ENDPOINTS") == "true"
+	if err := loadCircuitBreakerSettings(config); err != nil {
+		return nil, err
+	}
+	if opts.mock {
+		config.APIKey = ""
+		config.ProjectID = ""
+		config.OllamaURL = ""
+	} else if config.APIKey == "" {
+		log.Printf("⚠️ Warning: GEMINI_API_KEY not set, will use mock responses")
+	}
+
+	dbURL := os.Getenv("DB_URL")
+	if dbURL == "" {
+		return &localBackend{client: gogent.NewInMemoryClient(config), userID: opts.userID, mock: opts.mock}, nil
+	}
+	client, err := gogent.NewClient(dbURL, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gogent client: %w", err)
+	}
+	return &localBackend{client: client, userID: opts.userID, mock: opts.mock}, nil
+}
+
+// Execute resolves the request's presets and sweep, validates it and executes it
+func (b *localBackend) Execute(ctx context.Context, request *types.MultiExecutionRequest) (*types.ExecutionResult, error) {
+	if err := b.client.ResolveConfigurations(ctx, b.userID, request); err != nil {
+		return nil, err
+	}
+	if err := b.client.ValidateRequest(ctx, request); err != nil {
+		return nil, err
+	}
+	return b.client.ExecuteMultiVariation(ctx, b.userID, request)
+}
+
+// ListExecutionRuns lists the user's runs, newest first
+func (b *localBackend) ListExecutionRuns(ctx context.Context, limit, offset int32) ([]*types.ExecutionRun, error) {
+	return b.client.ListExecutionRuns(ctx, b.userID, limit, offset)
+}
+
+// GetExecutionResult loads one of the user's runs
+func (b *localBackend) GetExecutionResult(ctx context.Context, executionRunID string) (*types.ExecutionResult, error) {
+	return b.client.GetExecutionResult(ctx, b.userID, executionRunID)
+}
+
+// ListFunctions lists the function definitions visible to the user
+func (b *localBackend) ListFunctions(ctx context.Context) ([]*types.FunctionDefinition, error) {
+	functions, err := b.client.ListFunctionDefinitions(ctx, b.userID, gogent.MaxPageSize, 0)
+	if errors.Is(err, gogent.ErrNoDatabase) {
+		return nil, fmt.Errorf("function definitions are stored in MySQL; set DB_URL or use --server: %w", err)
+	}
+	return functions, err
+}
+
+// CallFunction runs one of the user's function definitions, with its mock response under --mock
+func (b *localBackend) CallFunction(ctx context.Context, functionID string, args map[string]interface{}) (*types.FunctionTestResult, error) {
+	return b.client.TestFunctionDefinition(ctx, b.userID, functionID, args, b.mock, 0)
+}
+
+// auditExport records the export in the audit log; in-memory runs have no audit log to record it in
+func (b *localBackend) auditExport(ctx context.Context, runs int, destination string) {
+	err := b.client.RecordAuditEvent(ctx, &types.AuditEvent{ActorID: b.userID, Action: types.AuditExport,
+		Summary: map[string]interface{}{"runs": runs, "destination": destination}})
+	if err != nil && !errors.Is(err, gogent.ErrNoDatabase) {
+		log.Printf("⚠️ Warning: failed to record export audit event: %v", err)
+	}
+}
+
+// Close closes the library's database connection
+func (b *localBackend) Close() error {
+	return b.client.Close()
+}
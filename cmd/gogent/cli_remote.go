@@ -0,0 +1,437 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"gogent/internal/types"
+	pb "gogent/proto"
+
+	"github.com/joho/godotenv"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// executionPollInterval is how often the CLI checks on an execution running on the server
+const executionPollInterval = time.Second
+
+// remoteBackend runs CLI commands on a GoGent server over gRPC
+type remoteBackend struct {
+	conn          *grpc.ClientConn
+	client        pb.GogentServiceClient
+	authorization string
+	mock          bool
+	// converter supplies the protobuf conversions shared with the gRPC server
+	converter *GRPCServer
+}
+
+// newRemoteBackend connects to the server named by the --server flag
+func newRemoteBackend(opts *cliOptions) (*remoteBackend, error) {
+	if err := godotenv.Load(); err != nil {
+		log.Printf("⚠️ Warning: .env file not found: %v", err)
+	}
+
+	authorization := ""
+	switch {
+	case opts.apiKey != "":
+		authorization = "ApiKey " + opts.apiKey
+	case opts.token != "":
+		authorization = "Bearer " + opts.token
+	default:
+		return nil, fmt.Errorf("--api-key or --token is required with --server")
+	}
+
+	conn, err := grpc.NewClient(opts.server, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to gRPC server: %w", err)
+	}
+
+	return &remoteBackend{
+		conn:          conn,
+		client:        pb.NewGogentServiceClient(conn),
+		authorization: authorization,
+		mock:          opts.mock,
+		converter:     &GRPCServer{},
+	}, nil
+}
+
+// outgoingContext attaches the caller's credentials to a call
+func (b *remoteBackend) outgoingContext(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", b.authorization)
+}
+
+// Execute starts the execution on the server and waits for it to finish
+func (b *remoteBackend) Execute(ctx context.Context, request *types.MultiExecutionRequest) (*types.ExecutionResult, error) {
+	if request.DryRun {
+		return nil, fmt.Errorf("dry runs are not available over gRPC; run without --server or POST the request with dryRun to /api/execute")
+	}
+	resp, err := b.start(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Merged {
+		log.Printf("ℹ️ %s", resp.Message)
+		return b.GetExecutionResult(ctx, resp.ExecutionRun.Id)
+	}
+
+	log.Printf("🚀 Execution started: %s", resp.ExecutionId)
+	ticker := time.NewTicker(executionPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+
+		status, err := b.client.GetExecutionStatus(b.outgoingContext(ctx), &pb.GetExecutionStatusRequest{ExecutionId: resp.ExecutionId})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get execution status: %w", err)
+		}
+		switch status.Status {
+		case "completed":
+			if status.Result == nil {
+				return nil, fmt.Errorf("execution %s completed without a result", resp.ExecutionId)
+			}
+			return convertProtoExecutionResultToInternal(b.converter, status.Result), nil
+		case "failed":
+			return nil, fmt.Errorf("execution failed: %s", status.ErrorMessage)
+		}
+	}
+}
+
+// start queues an execution on the server, sending the session keys in the caller's environment
+func (b *remoteBackend) start(ctx context.Context, request *types.MultiExecutionRequest) (*pb.ExecuteResponse, error) {
+	protoRequest, err := convertExecuteRequestToProto(b.converter, request)
+	if err != nil {
+		return nil, err
+	}
+	protoRequest.UseMock = b.mock
+
+	// Session keys are read from the caller's environment and never stored by the server
+	for key, env := range map[string]string{
+		"geminiApiKey":      "GEMINI_API_KEY",
+		"openWeatherApiKey": "OPENWEATHER_API_KEY",
+		"neo4jUrl":          "NEO4J_URL",
+		"neo4jUsername":     "NEO4J_USERNAME",
+		"neo4jPassword":     "NEO4J_PASSWORD",
+		"neo4jDatabase":     "NEO4J_DATABASE",
+		"webSearchApiKey":   "WEB_SEARCH_API_KEY",
+		"sqlDsn":            "QUERY_SQL_DSN",
+	} {
+		if value := os.Getenv(env); value != "" {
+			protoRequest.SessionApiKeys[key] = value
+		}
+	}
+
+	resp, err := b.client.Execute(b.outgoingContext(ctx), protoRequest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start execution: %w", err)
+	}
+	return resp, nil
+}
+
+// watch streams an execution's progress, with its log entries at or above logLevel
+func (b *remoteBackend) watch(ctx context.Context, id, logLevel string) (pb.GogentService_WatchExecutionClient, error) {
+	stream, err := b.client.WatchExecution(b.outgoingContext(ctx), &pb.WatchExecutionRequest{ExecutionId: id, LogLevel: logLevel})
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch execution: %w", err)
+	}
+	return stream, nil
+}
+
+// ListExecutionRuns lists the caller's runs, newest first
+func (b *remoteBackend) ListExecutionRuns(ctx context.Context, limit, offset int32) ([]*types.ExecutionRun, error) {
+	resp, err := b.client.ListExecutionRuns(b.outgoingContext(ctx), &pb.ListExecutionRunsRequest{Limit: limit, Offset: offset})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list execution runs: %w", err)
+	}
+
+	runs := make([]*types.ExecutionRun, 0, len(resp.ExecutionRuns))
+	for _, run := range resp.ExecutionRuns {
+		runs = append(runs, convertProtoExecutionRunToInternal(run))
+	}
+	return runs, nil
+}
+
+// GetExecutionResult loads one of the caller's runs
+func (b *remoteBackend) GetExecutionResult(ctx context.Context, executionRunID string) (*types.ExecutionResult, error) {
+	resp, err := b.client.GetExecutionResult(b.outgoingContext(ctx), &pb.GetExecutionResultRequest{ExecutionRunId: executionRunID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get execution result: %w", err)
+	}
+	return convertProtoExecutionResultToInternal(b.converter, resp.Result), nil
+}
+
+// ListFunctions lists the function definitions visible to the caller
+func (b *remoteBackend) ListFunctions(ctx context.Context) ([]*types.FunctionDefinition, error) {
+	resp, err := b.client.ListFunctions(b.outgoingContext(ctx), &pb.ListFunctionsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list functions: %w", err)
+	}
+
+	functions := make([]*types.FunctionDefinition, 0, len(resp.Functions))
+	for _, function := range resp.Functions {
+		converted := b.converter.convertProtoFunctionToInternal(function)
+		converted.UserID = function.UserId
+		functions = append(functions, converted)
+	}
+	return functions, nil
+}
+
+// CallFunction runs one of the caller's function definitions on the server
+func (b *remoteBackend) CallFunction(ctx context.Context, functionID string, args map[string]interface{}) (*types.FunctionTestResult, error) {
+	arguments, err := structpb.NewStruct(args)
+	if err != nil {
+		return nil, fmt.Errorf("invalid function arguments: %w", err)
+	}
+	resp, err := b.client.TestFunction(b.outgoingContext(ctx), &pb.TestFunctionRequest{
+		FunctionId:  functionID,
+		Arguments:   arguments,
+		UseMockData: b.mock,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to call function: %w", err)
+	}
+	return &types.FunctionTestResult{
+		Success:         resp.Success,
+		UsedMockData:    resp.UsedMockData,
+		ExecutionTimeMs: resp.ExecutionTimeMs,
+		Response:        resp.Response.AsMap(),
+		Error:           resp.ErrorMessage,
+		FunctionCallID:  resp.FunctionCallId,
+	}, nil
+}
+
+// auditExport does nothing: the server only sees the reads an export makes, and has no RPC to
+// record the export itself
+func (b *remoteBackend) auditExport(ctx context.Context, runs int, destination string) {}
+
+// Close closes the connection to the server
+func (b *remoteBackend) Close() error {
+	return b.conn.Close()
+}
+
+// convertExecuteRequestToProto converts an execution request to protobuf
+func convertExecuteRequestToProto(converter *GRPCServer, request *types.MultiExecutionRequest) (*pb.ExecuteRequest, error) {
+	protoRequest := &pb.ExecuteRequest{
+		ExecutionRunName:      request.ExecutionRunName,
+		Description:           request.Description,
+		BasePrompt:            request.BasePrompt,
+		Context:               request.Context,
+		EnableFunctionCalling: request.EnableFunctionCalling,
+		SessionApiKeys:        make(map[string]string),
+		Priority:              request.Priority,
+
+		FunctionInstruction:        request.FunctionInstruction,
+		DisableFunctionInstruction: request.DisableFunctionInstruction,
+		SafetyPolicy:               convertSafetyPolicyToProto(request.SafetyPolicy),
+		Deterministic:              request.Deterministic,
+		Seed:                       request.Seed,
+
+		NameTemplate:    request.NameTemplate,
+		Suite:           request.Suite,
+		GitSha:          request.GitSHA,
+		DuplicatePolicy: request.DuplicatePolicy,
+		Repetitions:     int32(request.Repetitions),
+	}
+
+	for i := range request.Configurations {
+		protoRequest.Configurations = append(protoRequest.Configurations, converter.convertConfigurationToProto(&request.Configurations[i]))
+	}
+
+	for _, tool := range request.FunctionTools {
+		protoTool := &pb.Tool{
+			Name:            tool.Name,
+			Description:     tool.Description,
+			UseMockResponse: tool.UseMockResponse,
+		}
+		if len(tool.Parameters) > 0 {
+			parameters, err := structpb.NewStruct(tool.Parameters)
+			if err != nil {
+				return nil, fmt.Errorf("invalid parameters for tool %s: %w", tool.Name, err)
+			}
+			protoTool.Parameters = parameters
+		}
+		if len(tool.MockResponse) > 0 {
+			mockResponse, err := structpb.NewStruct(tool.MockResponse)
+			if err != nil {
+				return nil, fmt.Errorf("invalid mock response for tool %s: %w", tool.Name, err)
+			}
+			protoTool.MockResponse = mockResponse
+		}
+		protoRequest.FunctionTools = append(protoRequest.FunctionTools, protoTool)
+	}
+
+	if config := request.ComparisonConfig; config != nil {
+		protoRequest.ComparisonConfig = &pb.ComparisonConfig{
+			Enabled:     config.Enabled,
+			Metrics:     config.Metrics,
+			CustomRules: config.CustomRules,
+		}
+		if ta := config.ToolAppropriateness; ta != nil {
+			protoRequest.ComparisonConfig.ToolAppropriateness = &pb.ToolAppropriatenessConfig{
+				ExpectToolUse: ta.ExpectToolUse,
+				ToolKeywords:  ta.ToolKeywords,
+			}
+		}
+		if judge := config.Judge; judge != nil {
+			protoRequest.ComparisonConfig.Judge = &pb.JudgeConfig{
+				Model:        judge.Model,
+				Criteria:     judge.Criteria,
+				MaxCostUsd:   judge.MaxCostUSD,
+				Weight:       judge.Weight,
+				DisableCache: judge.DisableCache,
+			}
+		}
+	}
+
+	if expected := request.ExpectedAnswer; expected != nil {
+		protoRequest.ExpectedAnswer = &pb.ExpectedAnswer{
+			Answer:    expected.Answer,
+			MatchMode: expected.MatchMode,
+			Threshold: expected.Threshold,
+		}
+	}
+	protoRequest.Sweep = convertParameterSweepToProto(request.Sweep)
+
+	return protoRequest, nil
+}
+
+// convertProtoExecutionRunToInternal converts a protobuf execution run
+func convertProtoExecutionRunToInternal(run *pb.ExecutionRun) *types.ExecutionRun {
+	converted := &types.ExecutionRun{
+		ID:                    run.Id,
+		UserID:                run.UserId,
+		Name:                  run.Name,
+		Description:           run.Description,
+		EnableFunctionCalling: run.EnableFunctionCalling,
+		Status:                run.Status,
+		ErrorMessage:          run.ErrorMessage,
+		CreatedAt:             run.CreatedAt.AsTime(),
+		UpdatedAt:             run.UpdatedAt.AsTime(),
+
+		Deterministic:          run.Deterministic,
+		DeterminismFingerprint: run.DeterminismFingerprint,
+	}
+	if run.RunSpec != "" {
+		var spec types.RunSpec
+		if err := types.FromJSON(run.RunSpec, &spec); err == nil {
+			converted.RunSpec = &spec
+		}
+	}
+	return converted
+}
+
+// convertProtoComparisonToInternal converts a protobuf comparison and its significance tests
+func convertProtoComparisonToInternal(comparison *pb.ComparisonResult) *types.ComparisonResult {
+	converted := &types.ComparisonResult{
+		ID:                  comparison.Id,
+		ExecutionRunID:      comparison.ExecutionRunId,
+		ComparisonType:      comparison.ComparisonType,
+		MetricName:          comparison.MetricName,
+		ConfigurationScores: comparison.ConfigurationScores.AsMap(),
+		BestConfigurationID: comparison.BestConfigurationId,
+		AnalysisNotes:       comparison.AnalysisNotes,
+		CreatedAt:           comparison.CreatedAt.AsTime(),
+	}
+	for _, test := range comparison.SignificanceTests {
+		converted.SignificanceTests = append(converted.SignificanceTests, types.SignificanceTest{
+			Metric:           test.Metric,
+			ConfigurationA:   test.ConfigurationA,
+			ConfigurationB:   test.ConfigurationB,
+			MeanDifference:   test.MeanDifference,
+			TStatistic:       test.TStatistic,
+			DegreesOfFreedom: test.DegreesOfFreedom,
+			PValue:           test.PValue,
+			Significant:      test.Significant,
+		})
+	}
+	return converted
+}
+
+// convertProtoExecutionResultToInternal converts a protobuf execution result
+func convertProtoExecutionResultToInternal(converter *GRPCServer, result *pb.ExecutionResult) *types.ExecutionResult {
+	converted := &types.ExecutionResult{
+		Results:      make([]types.VariationResult, 0, len(result.Results)),
+		TotalTime:    result.TotalTime,
+		SuccessCount: int(result.SuccessCount),
+		ErrorCount:   int(result.ErrorCount),
+	}
+	if result.ExecutionRun != nil {
+		converted.ExecutionRun = *convertProtoExecutionRunToInternal(result.ExecutionRun)
+	}
+
+	for _, vr := range result.Results {
+		variation := types.VariationResult{
+			ExecutionTime: vr.ExecutionTime,
+			Repetition:    int(vr.Repetition),
+		}
+		if vr.Configuration != nil {
+			variation.Configuration = *converter.convertProtoConfigurationToInternal(vr.Configuration)
+			variation.Configuration.CreatedAt = vr.Configuration.CreatedAt.AsTime()
+		}
+		if vr.Request != nil {
+			variation.Request = types.APIRequest{
+				ID:               vr.Request.Id,
+				ExecutionRunID:   vr.Request.ExecutionRunId,
+				ConfigurationID:  vr.Request.ConfigurationId,
+				RequestType:      types.RequestType(vr.Request.RequestType),
+				Prompt:           vr.Request.Prompt,
+				Context:          vr.Request.Context,
+				FunctionName:     vr.Request.FunctionName,
+				SystemPromptMode: vr.Request.SystemPromptMode,
+				CreatedAt:        vr.Request.CreatedAt.AsTime(),
+			}
+		}
+		if vr.Response != nil {
+			variation.Response = types.APIResponse{
+				ID:             vr.Response.Id,
+				RequestID:      vr.Response.RequestId,
+				ResponseStatus: types.ResponseStatus(vr.Response.ResponseStatus),
+				ResponseText:   vr.Response.ResponseText,
+				FinishReason:   vr.Response.FinishReason,
+				ErrorMessage:   vr.Response.ErrorMessage,
+				ResponseTimeMs: vr.Response.ResponseTimeMs,
+				UsageMetadata:  vr.Response.UsageMetadata.AsMap(),
+				CreatedAt:      vr.Response.CreatedAt.AsTime(),
+			}
+		}
+		for _, call := range vr.FunctionCalls {
+			variation.FunctionCalls = append(variation.FunctionCalls, types.FunctionCall{
+				ID:               call.Id,
+				RequestID:        call.RequestId,
+				FunctionName:     call.FunctionName,
+				FunctionArgs:     call.FunctionArguments.AsMap(),
+				FunctionResponse: call.FunctionResponse.AsMap(),
+				ExecutionStatus:  call.ExecutionStatus,
+				ExecutionTimeMs:  call.ExecutionTimeMs,
+				ErrorDetails:     call.ErrorDetails,
+				CreatedAt:        call.CreatedAt.AsTime(),
+			})
+		}
+		converted.Results = append(converted.Results, variation)
+	}
+
+	if result.Comparison != nil {
+		converted.Comparison = convertProtoComparisonToInternal(result.Comparison)
+	}
+
+	for _, a := range result.Accuracy {
+		converted.Accuracy = append(converted.Accuracy, types.ConfigurationAccuracy{
+			VariationName:   a.VariationName,
+			ConfigurationID: a.ConfigurationId,
+			Cases:           int(a.Cases),
+			Passed:          int(a.Passed),
+			Accuracy:        a.Accuracy,
+		})
+	}
+	converted.SweepReport = convertProtoSweepReport(result.SweepReport)
+
+	return converted
+}
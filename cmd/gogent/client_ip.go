@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/netip"
+	"os"
+	"strings"
+)
+
+// trustedProxies are the networks whose X-Forwarded-For headers are believed, from TRUSTED_PROXIES
+type trustedProxies []netip.Prefix
+
+// loadTrustedProxies reads TRUSTED_PROXIES, a comma-separated list of addresses and CIDR ranges of
+// the load balancers and proxies in front of the server; without one, no proxy is trusted
+func loadTrustedProxies() (trustedProxies, error) {
+	var proxies trustedProxies
+	for _, entry := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(entry)
+			if addrErr != nil {
+				return nil, fmt.Errorf("TRUSTED_PROXIES: %q is not an address or CIDR range", entry)
+			}
+			prefix = netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen())
+		}
+		proxies = append(proxies, prefix.Masked())
+	}
+	if len(proxies) > 0 {
+		log.Printf("🔀 Trusting X-Forwarded-For from %d proxy ranges", len(proxies))
+	}
+	return proxies, nil
+}
+
+// trusts reports whether address, with or without a port, is one of the trusted proxies
+func (p trustedProxies) trusts(address string) bool {
+	addr, err := netip.ParseAddr(hostOnly(address))
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range p {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIP returns the address of the client behind a connection from peer. The X-Forwarded-For
+// hops in forwarded are only believed when trustPeer is set: they are read from the right, past
+// the trusted proxies each hop was added by, so a client cannot pose as another address by
+// sending the header itself.
+func (p trustedProxies) clientIP(peer string, forwarded string, trustPeer bool) string {
+	client := hostOnly(peer)
+	if !trustPeer || forwarded == "" {
+		return client
+	}
+	hops := strings.Split(forwarded, ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if _, err := netip.ParseAddr(hop); err != nil {
+			break
+		}
+		client = hop
+		if !p.trusts(hop) {
+			break
+		}
+	}
+	return client
+}
+
+// hostOnly strips the port from address, if it has one
+func hostOnly(address string) string {
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		return host
+	}
+	return address
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestLoadTrustedProxies(t *testing.T) {
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8, 192.168.1.5,::1")
+	proxies, err := loadTrustedProxies()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for address, trusted := range map[string]bool{"10.2.3.4:443": true, "192.168.1.5": true, "192.168.1.6": false, "[::1]:80": true, "203.0.113.7": false} {
+		if got := proxies.trusts(address); got != trusted {
+			t.Errorf("trusts(%s): expected %v, got %v", address, trusted, got)
+		}
+	}
+
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8,proxy.internal")
+	if _, err := loadTrustedProxies(); err == nil {
+		t.Error("expected an error for an entry that is not an address")
+	}
+}
+
+func TestRequestIP(t *testing.T) {
+	server := &Server{trustedProxies: trustedProxies{netip.MustParsePrefix("10.0.0.0/8")}}
+	tests := []struct {
+		name      string
+		remote    string
+		forwarded []string
+		expected  string
+	}{
+		{"direct", "203.0.113.7:5000", nil, "203.0.113.7"},
+		{"spoofed_by_untrusted_peer", "203.0.113.7:5000", []string{"198.51.100.1"}, "203.0.113.7"},
+		{"trusted_proxy", "10.0.0.2:5000", []string{"198.51.100.1"}, "198.51.100.1"},
+		{"spoofed_through_trusted_proxy", "10.0.0.2:5000", []string{"1.2.3.4, 198.51.100.1"}, "198.51.100.1"},
+		{"proxy_chain", "10.0.0.2:5000", []string{"198.51.100.1, 10.0.0.9"}, "198.51.100.1"},
+		{"repeated_headers", "10.0.0.2:5000", []string{"1.2.3.4", "198.51.100.1"}, "198.51.100.1"},
+		{"garbage_hop", "10.0.0.2:5000", []string{"not-an-ip"}, "10.0.0.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/api/health", nil)
+			r.RemoteAddr = tt.remote
+			for _, value := range tt.forwarded {
+				r.Header.Add("X-Forwarded-For", value)
+			}
+			if got := server.requestIP(r); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestPeerIP(t *testing.T) {
+	server := &GRPCServer{}
+	call := func(remote string, forwarded string) string {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(remote), Port: 5000}})
+		if forwarded != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-forwarded-for", forwarded))
+		}
+		return server.peerIP(ctx)
+	}
+
+	if got := call("203.0.113.7", "198.51.100.1"); got != "203.0.113.7" {
+		t.Errorf("expected a header from an untrusted peer to be ignored, got %s", got)
+	}
+	if got := call("127.0.0.1", "198.51.100.1"); got != "198.51.100.1" {
+		t.Errorf("expected the gateway's forwarded client, got %s", got)
+	}
+	if got := call("127.0.0.1", "1.2.3.4, 198.51.100.1"); got != "198.51.100.1" {
+		t.Errorf("expected the hop the gateway added, not one its client sent, got %s", got)
+	}
+	if got := call("127.0.0.1", ""); got != "127.0.0.1" {
+		t.Errorf("expected the peer address without a header, got %s", got)
+	}
+}
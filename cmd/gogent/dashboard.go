@@ -0,0 +1,262 @@
+package main
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dashboardCookie holds the access token of a signed-in dashboard user
+const dashboardCookie = "agentlog_ui_token"
+
+// dashboardRunsPerPage is the page size of the dashboard's run list
+const dashboardRunsPerPage = 25
+
+//go:embed dashboard/*.html
+var dashboardFiles embed.FS
+
+// dashboardPages holds each page parsed together with the shared layout
+var dashboardPages = parseDashboardPages("login.html", "runs.html", "run.html")
+
+// parseDashboardPages parses every page with layout.html so each can render the "layout" template
+func parseDashboardPages(pages ...string) map[string]*template.Template {
+	funcs := template.FuncMap{
+		"formatTime": func(t time.Time) string {
+			if t.IsZero() {
+				return ""
+			}
+			return t.Format("2006-01-02 15:04:05")
+		},
+		"percent": func(v float64) string {
+			return fmt.Sprintf("%.1f%%", v*100)
+		},
+		"lower": strings.ToLower,
+	}
+
+	parsed := make(map[string]*template.Template, len(pages))
+	for _, page := range pages {
+		parsed[page] = template.Must(template.New(page).Funcs(funcs).ParseFS(dashboardFiles, "dashboard/layout.html", "dashboard/"+page))
+	}
+	return parsed
+}
+
+// dashboardVariation is one variation result laid out for the run page
+type dashboardVariation struct {
+	Name         string
+	Model        string
+	Temperature  string
+	Repetition   int
+	Status       string
+	Response     string
+	Error        string
+	ResponseTime int32
+	OverallScore string
+	Best         bool
+}
+
+// dashboardScore is one row of the run page's comparison table
+type dashboardScore struct {
+	Name   string
+	Scores []string
+}
+
+// dashboardScoreColumns are the comparison metrics shown on the run page
+var dashboardScoreColumns = []string{"overall_score", "creativity_score", "coherence_score", "token_efficiency", "safety_score", "response_time_score"}
+
+// renderDashboard writes a dashboard page, logging template errors that happen after the header is sent
+func renderDashboard(w http.ResponseWriter, page string, data map[string]interface{}) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := dashboardPages[page].ExecuteTemplate(w, "layout", data); err != nil {
+		log.Printf("❌ Failed to render dashboard page %s: %v", page, err)
+	}
+}
+
+// dashboardAuth resolves the signed-in user from the dashboard cookie, redirecting to the login page without one
+func (s *Server) dashboardAuth(next func(w http.ResponseWriter, r *http.Request, userID string)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie(dashboardCookie)
+		if err != nil {
+			http.Redirect(w, r, "/ui/login", http.StatusSeeOther)
+			return
+		}
+		user, err := s.authService.ValidateToken(cookie.Value)
+		if err != nil {
+			clearDashboardCookie(w)
+			http.Redirect(w, r, "/ui/login", http.StatusSeeOther)
+			return
+		}
+		next(w, r, user.ID)
+	}
+}
+
+// clearDashboardCookie removes the dashboard session cookie
+func clearDashboardCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{Name: dashboardCookie, Path: "/ui", MaxAge: -1, HttpOnly: true, SameSite: http.SameSiteLaxMode})
+}
+
+// dashboardLoginHandler shows the sign-in form and exchanges credentials for a session cookie
+func (s *Server) dashboardLoginHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		renderDashboard(w, "login.html", map[string]interface{}{"Title": "Sign in"})
+	case http.MethodPost:
+		username := r.FormValue("username")
+		_, token, _, err := s.authService.Login(username, r.FormValue("password"))
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			renderDashboard(w, "login.html", map[string]interface{}{"Title": "Sign in", "Username": username, "Error": err.Error()})
+			return
+		}
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     dashboardCookie,
+			Value:    token,
+			Path:     "/ui",
+			MaxAge:   int(s.authService.TokenExpiry().Seconds()),
+			HttpOnly: true,
+			Secure:   r.TLS != nil,
+			SameSite: http.SameSiteLaxMode,
+		})
+		http.Redirect(w, r, "/ui", http.StatusSeeOther)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// dashboardLogoutHandler revokes the dashboard session and returns to the login page
+func (s *Server) dashboardLogoutHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if cookie, err := r.Cookie(dashboardCookie); err == nil {
+		if err := s.authService.Logout(cookie.Value, false); err != nil {
+			log.Printf("⚠️ Failed to revoke dashboard session: %v", err)
+		}
+	}
+	clearDashboardCookie(w)
+	http.Redirect(w, r, "/ui/login", http.StatusSeeOther)
+}
+
+// dashboardRunsHandler lists the user's execution runs, newest first
+func (s *Server) dashboardRunsHandler(w http.ResponseWriter, r *http.Request, userID string) {
+	if r.URL.Path != "/ui" && r.URL.Path != "/ui/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	page := 1
+	if parsed, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && parsed > 1 {
+		page = parsed
+	}
+
+	// Fetch one extra run to know whether there is a next page
+	runs, err := s.client.ListExecutionRuns(r.Context(), userID, dashboardRunsPerPage+1, int32((page-1)*dashboardRunsPerPage))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list execution runs: %v", err), http.StatusInternalServerError)
+		return
+	}
+	hasNext := len(runs) > dashboardRunsPerPage
+	if hasNext {
+		runs = runs[:dashboardRunsPerPage]
+	}
+
+	renderDashboard(w, "runs.html", map[string]interface{}{
+		"Title":    "Execution runs",
+		"SignedIn": true,
+		"Runs":     runs,
+		"Page":     page,
+		"PrevPage": page - 1,
+		"NextPage": page + 1,
+		"HasNext":  hasNext,
+	})
+}
+
+// dashboardRunHandler shows one run's variations side by side with its comparison and logs
+func (s *Server) dashboardRunHandler(w http.ResponseWriter, r *http.Request, userID string) {
+	runID := strings.TrimPrefix(r.URL.Path, "/ui/runs/")
+	if runID == "" || strings.Contains(runID, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	result, err := s.client.GetExecutionResult(r.Context(), userID, runID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get execution run: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var scores map[string]interface{}
+	bestID := ""
+	if result.Comparison != nil {
+		scores = result.Comparison.ConfigurationScores
+		bestID = result.Comparison.BestConfigurationID
+	}
+
+	variations := make([]dashboardVariation, 0, len(result.Results))
+	for _, vr := range result.Results {
+		variation := dashboardVariation{
+			Name:         vr.Configuration.VariationName,
+			Model:        vr.Configuration.ModelName,
+			Repetition:   vr.Repetition,
+			Status:       string(vr.Response.ResponseStatus),
+			Response:     vr.Response.ResponseText,
+			Error:        vr.Response.ErrorMessage,
+			ResponseTime: vr.Response.ResponseTimeMs,
+			Best:         vr.Configuration.ID == bestID,
+		}
+		if vr.Configuration.Temperature != nil {
+			variation.Temperature = fmt.Sprintf("%.2f", *vr.Configuration.Temperature)
+		}
+		variation.OverallScore = dashboardScoreValue(scores, vr.Configuration.VariationName, "overall_score")
+		variations = append(variations, variation)
+	}
+
+	var scoreRows []dashboardScore
+	seen := make(map[string]bool)
+	for _, variation := range variations {
+		if seen[variation.Name] || scores[variation.Name] == nil {
+			continue
+		}
+		seen[variation.Name] = true
+
+		row := dashboardScore{Name: variation.Name}
+		for _, column := range dashboardScoreColumns {
+			row.Scores = append(row.Scores, dashboardScoreValue(scores, variation.Name, column))
+		}
+		scoreRows = append(scoreRows, row)
+	}
+
+	renderDashboard(w, "run.html", map[string]interface{}{
+		"Title":        result.ExecutionRun.Name,
+		"SignedIn":     true,
+		"Result":       result,
+		"Variations":   variations,
+		"ScoreColumns": dashboardScoreColumns,
+		"ScoreRows":    scoreRows,
+	})
+}
+
+// dashboardScoreValue formats a 0-1 comparison score as a percentage, or "" when it is missing
+func dashboardScoreValue(scores map[string]interface{}, variationName, metric string) string {
+	variationScores, ok := scores[variationName].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	value, ok := variationScores[metric].(float64)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%.1f", value*100)
+}
@@ -0,0 +1,623 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"gogent/internal/auth"
+	"gogent/internal/gogent"
+	"gogent/internal/graphql"
+	"gogent/internal/types"
+)
+
+// maxGraphQLRequestBytes caps the body of a POST /graphql request. Mutations take the same inputs
+// as the REST bodies, so it leaves room for large run contexts.
+const maxGraphQLRequestBytes = 8 << 20
+
+// graphqlSDL documents the schema served at /graphql. JSON values are returned as they are stored
+// and input objects take the same fields as the REST request bodies.
+const graphqlSDL = `scalar JSON
+scalar Time
+
+type Query {
+  executionRuns(limit: Int = 10, offset: Int = 0, cursor: String): [ExecutionRun!]!
+  executionRun(id: ID!): ExecutionRun
+  variationResults(executionRunId: ID!): [VariationResult!]!
+  functionCalls(executionRunId: ID!): [FunctionCall!]!
+  comparisons(limit: Int = 10, offset: Int = 0, cursor: String): [Comparison!]!
+  comparison(executionRunId: ID!): Comparison
+  functions(limit: Int = 500, offset: Int = 0, cursor: String): [Function!]!
+  function(id: ID!): Function
+}
+
+type Mutation {
+  # The input objects take the body of POST /api/execute, POST /api/functions and
+  # PUT /api/functions/{id}
+  execute(input: JSON!): ExecutionSubmission!
+  createFunction(input: JSON!): Function!
+  updateFunction(id: ID!, input: JSON!): Function!
+  deleteFunction(id: ID!): Boolean!
+}
+
+type ExecutionRun {
+  id: ID!
+  name: String!
+  description: String
+  status: String!
+  errorMessage: String
+  enableFunctionCalling: Boolean!
+  parentRunId: ID
+  replayOfRunId: ID
+  createdAt: Time!
+  updatedAt: Time!
+  variationResults: [VariationResult!]!
+  functionCalls: [FunctionCall!]!
+  comparison: Comparison
+}
+
+type VariationResult {
+  configuration: Configuration!
+  repetition: Int
+  executionTimeMs: Int!
+  responseStatus: String!
+  responseText: String
+  finishReason: String
+  thoughtSummary: String
+  candidateIndex: Int
+  errorMessage: String
+  responseTimeMs: Int!
+  usage: JSON
+  functionCalls: [FunctionCall!]!
+}
+
+type Configuration {
+  id: ID!
+  variationName: String!
+  modelName: String!
+  provider: String
+  systemPrompt: String
+  temperature: Float
+  maxTokens: Int
+  topP: Float
+  topK: Int
+  thinkingBudget: Int
+  candidateCount: Int
+}
+
+type FunctionCall {
+  id: ID!
+  requestId: ID
+  functionName: String!
+  arguments: JSON
+  response: JSON
+  status: String!
+  executionTimeMs: Int
+  errorDetails: String
+  usedMockData: Boolean!
+  createdAt: Time!
+}
+
+type Comparison {
+  id: ID!
+  executionRunId: ID!
+  comparisonType: String!
+  metricName: String!
+  configurationScores: JSON
+  bestConfigurationId: ID
+  bestConfiguration: Configuration
+  analysisNotes: String
+  createdAt: Time!
+}
+
+type Function {
+  id: ID!
+  name: String!
+  displayName: String!
+  description: String!
+  parametersSchema: JSON
+  mockResponse: JSON
+  endpointUrl: String
+  httpMethod: String!
+  isActive: Boolean!
+  requiredApiKeys: [String!]
+  createdAt: Time!
+  updatedAt: Time!
+}
+
+type ExecutionSubmission {
+  id: ID!
+  name: String!
+  status: String!
+  queuePosition: Int
+  merged: Boolean!
+  message: String!
+}
+`
+
+// graphqlRequestKey holds the HTTP request a GraphQL operation came in on, which mutations replay
+// through the REST handlers
+type graphqlRequestKey struct{}
+
+// graphqlResultsKey holds the execution results loaded while resolving one request
+type graphqlResultsKey struct{}
+
+// graphqlResults caches execution results by run ID, so the nested fields of a run load it once
+type graphqlResults struct {
+	mu      sync.Mutex
+	results map[string]*types.ExecutionResult
+}
+
+// graphqlSubmission is the response of an accepted execution
+type graphqlSubmission struct {
+	ExecutionRun struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Status string `json:"status"`
+	} `json:"executionRun"`
+	QueuePosition int    `json:"queuePosition"`
+	Merged        bool   `json:"merged"`
+	Message       string `json:"message"`
+}
+
+// graphqlHandler serves POST /graphql. Queries read through the client; mutations run the REST
+// handlers they mirror, so validation, quotas and audit events are shared.
+func (s *Server) graphqlHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprint(w, graphqlSDL)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if _, err := s.getUserID(r); err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var request graphql.Request
+	r.Body = http.MaxBytesReader(w, r.Body, maxGraphQLRequestBytes)
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, fmt.Sprintf("Request body exceeds %d bytes", tooLarge.Limit), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+		return
+	}
+	ctx := context.WithValue(r.Context(), graphqlRequestKey{}, r)
+	ctx = context.WithValue(ctx, graphqlResultsKey{}, &graphqlResults{results: make(map[string]*types.ExecutionResult)})
+
+	response := s.graphql.Execute(ctx, request)
+	w.Header().Set("Content-Type", "application/json")
+	if response.Data == nil {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+// graphqlUserID returns the ID of the user the operation runs as
+func graphqlUserID(ctx context.Context) (string, error) {
+	user, ok := auth.GetUserFromContext(ctx)
+	if !ok || user == nil {
+		return "", errors.New("unauthorized")
+	}
+	return user.ID, nil
+}
+
+// graphqlPage reads a list field's limit, offset and cursor
+func graphqlPage(args graphql.Args, defaultLimit int32) (int32, int32, error) {
+	limit, err := args.Int("limit", 0)
+	if err != nil {
+		return 0, 0, err
+	}
+	offset, err := args.Int("offset", 0)
+	if err != nil {
+		return 0, 0, err
+	}
+	cursor, err := args.String("cursor", "")
+	if err != nil {
+		return 0, 0, err
+	}
+	return gogent.ResolvePage(int32(limit), int32(offset), cursor, defaultLimit)
+}
+
+// executionResult loads a run's results once per request; it returns nil when the run does not exist
+func (s *Server) executionResult(ctx context.Context, runID string) (*types.ExecutionResult, error) {
+	userID, err := graphqlUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cache, _ := ctx.Value(graphqlResultsKey{}).(*graphqlResults)
+	if cache != nil {
+		cache.mu.Lock()
+		defer cache.mu.Unlock()
+		if result, ok := cache.results[runID]; ok {
+			return result, nil
+		}
+	}
+
+	result, err := s.client.GetExecutionResult(ctx, userID, runID)
+	if errors.Is(err, sql.ErrNoRows) {
+		result, err = nil, nil
+	}
+	if err != nil {
+		log.Printf("❌ Failed to load execution run %s for GraphQL: %v", runID, err)
+		return nil, errors.New("failed to load execution run")
+	}
+	if cache != nil {
+		cache.results[runID] = result
+	}
+	return result, nil
+}
+
+// runFunctionCalls lists the function calls of every variation of a run
+func runFunctionCalls(result *types.ExecutionResult) []types.FunctionCall {
+	calls := []types.FunctionCall{}
+	if result == nil {
+		return calls
+	}
+	for _, variation := range result.Results {
+		calls = append(calls, variation.FunctionCalls...)
+	}
+	return calls
+}
+
+// graphqlInput reads a mutation's input object
+func graphqlInput(args graphql.Args) (map[string]interface{}, error) {
+	var input map[string]interface{}
+	given, err := args.Decode("input", &input)
+	if err != nil {
+		return nil, err
+	}
+	if !given {
+		return nil, fmt.Errorf("%w: input is required", graphql.ErrInvalidArgument)
+	}
+	return input, nil
+}
+
+// graphqlID reads a mutation's id argument
+func graphqlID(args graphql.Args) (string, error) {
+	id, err := args.String("id", "")
+	if err != nil {
+		return "", err
+	}
+	if id == "" {
+		return "", fmt.Errorf("%w: id is required", graphql.ErrInvalidArgument)
+	}
+	return id, nil
+}
+
+// graphqlDispatch runs a REST handler for a mutation with the operation's user, headers and client
+// address, and decodes its JSON response into target. Error responses become the mutation's error.
+func graphqlDispatch(ctx context.Context, handler http.HandlerFunc, method, path string, body interface{}, target interface{}) error {
+	original, ok := ctx.Value(graphqlRequestKey{}).(*http.Request)
+	if !ok {
+		return errors.New("mutations require an HTTP request")
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, method, path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	request.Header = original.Header.Clone()
+	request.Header.Set("Content-Type", "application/json")
+	request.RemoteAddr = original.RemoteAddr
+
+	recorder := httptest.NewRecorder()
+	handler(recorder, request)
+	if recorder.Code >= http.StatusBadRequest {
+		return errors.New(strings.TrimSpace(recorder.Body.String()))
+	}
+	if target == nil {
+		return nil
+	}
+	return json.Unmarshal(recorder.Body.Bytes(), target)
+}
+
+// graphqlValue resolves a leaf field from its parent with get
+func graphqlValue(get func(source interface{}) interface{}) *graphql.Field {
+	return &graphql.Field{Resolve: func(ctx context.Context, source interface{}, args graphql.Args) (interface{}, error) {
+		return get(source), nil
+	}}
+}
+
+// graphqlSchema builds the schema served at /graphql when GRAPHQL_ENABLED is true; graphqlSDL
+// describes it
+func (s *Server) graphqlSchema() *graphql.Schema {
+	configuration := &graphql.Object{Name: "Configuration", Fields: map[string]*graphql.Field{
+		"id":             graphqlValue(func(v interface{}) interface{} { return v.(*types.APIConfiguration).ID }),
+		"variationName":  graphqlValue(func(v interface{}) interface{} { return v.(*types.APIConfiguration).VariationName }),
+		"modelName":      graphqlValue(func(v interface{}) interface{} { return v.(*types.APIConfiguration).ModelName }),
+		"provider":       graphqlValue(func(v interface{}) interface{} { return v.(*types.APIConfiguration).Provider }),
+		"systemPrompt":   graphqlValue(func(v interface{}) interface{} { return v.(*types.APIConfiguration).SystemPrompt }),
+		"temperature":    graphqlValue(func(v interface{}) interface{} { return v.(*types.APIConfiguration).Temperature }),
+		"maxTokens":      graphqlValue(func(v interface{}) interface{} { return v.(*types.APIConfiguration).MaxTokens }),
+		"topP":           graphqlValue(func(v interface{}) interface{} { return v.(*types.APIConfiguration).TopP }),
+		"topK":           graphqlValue(func(v interface{}) interface{} { return v.(*types.APIConfiguration).TopK }),
+		"thinkingBudget": graphqlValue(func(v interface{}) interface{} { return v.(*types.APIConfiguration).ThinkingBudget }),
+		"candidateCount": graphqlValue(func(v interface{}) interface{} { return v.(*types.APIConfiguration).CandidateCount }),
+	}}
+
+	functionCall := &graphql.Object{Name: "FunctionCall", Fields: map[string]*graphql.Field{
+		"id":              graphqlValue(func(v interface{}) interface{} { return v.(*types.FunctionCall).ID }),
+		"requestId":       graphqlValue(func(v interface{}) interface{} { return v.(*types.FunctionCall).RequestID }),
+		"functionName":    graphqlValue(func(v interface{}) interface{} { return v.(*types.FunctionCall).FunctionName }),
+		"arguments":       graphqlValue(func(v interface{}) interface{} { return v.(*types.FunctionCall).FunctionArgs }),
+		"response":        graphqlValue(func(v interface{}) interface{} { return v.(*types.FunctionCall).FunctionResponse }),
+		"status":          graphqlValue(func(v interface{}) interface{} { return v.(*types.FunctionCall).ExecutionStatus }),
+		"executionTimeMs": graphqlValue(func(v interface{}) interface{} { return v.(*types.FunctionCall).ExecutionTimeMs }),
+		"errorDetails":    graphqlValue(func(v interface{}) interface{} { return v.(*types.FunctionCall).ErrorDetails }),
+		"usedMockData":    graphqlValue(func(v interface{}) interface{} { return v.(*types.FunctionCall).UsedMockData }),
+		"createdAt":       graphqlValue(func(v interface{}) interface{} { return v.(*types.FunctionCall).CreatedAt }),
+	}}
+
+	variationResult := &graphql.Object{Name: "VariationResult", Fields: map[string]*graphql.Field{
+		"configuration": {Type: configuration, Resolve: func(ctx context.Context, source interface{}, args graphql.Args) (interface{}, error) {
+			return &source.(*types.VariationResult).Configuration, nil
+		}},
+		"repetition":      graphqlValue(func(v interface{}) interface{} { return v.(*types.VariationResult).Repetition }),
+		"executionTimeMs": graphqlValue(func(v interface{}) interface{} { return v.(*types.VariationResult).ExecutionTime }),
+		"responseStatus":  graphqlValue(func(v interface{}) interface{} { return v.(*types.VariationResult).Response.ResponseStatus }),
+		"responseText":    graphqlValue(func(v interface{}) interface{} { return v.(*types.VariationResult).Response.ResponseText }),
+		"finishReason":    graphqlValue(func(v interface{}) interface{} { return v.(*types.VariationResult).Response.FinishReason }),
+		"thoughtSummary":  graphqlValue(func(v interface{}) interface{} { return v.(*types.VariationResult).Response.ThoughtSummary }),
+		"candidateIndex":  graphqlValue(func(v interface{}) interface{} { return v.(*types.VariationResult).Response.CandidateIndex }),
+		"errorMessage":    graphqlValue(func(v interface{}) interface{} { return v.(*types.VariationResult).Response.ErrorMessage }),
+		"responseTimeMs":  graphqlValue(func(v interface{}) interface{} { return v.(*types.VariationResult).Response.ResponseTimeMs }),
+		"usage":           graphqlValue(func(v interface{}) interface{} { return v.(*types.VariationResult).Response.UsageMetadata }),
+		"functionCalls": {Type: functionCall, Resolve: func(ctx context.Context, source interface{}, args graphql.Args) (interface{}, error) {
+			return source.(*types.VariationResult).FunctionCalls, nil
+		}},
+	}}
+
+	comparison := &graphql.Object{Name: "Comparison", Fields: map[string]*graphql.Field{
+		"id":                  graphqlValue(func(v interface{}) interface{} { return v.(*types.ComparisonResult).ID }),
+		"executionRunId":      graphqlValue(func(v interface{}) interface{} { return v.(*types.ComparisonResult).ExecutionRunID }),
+		"comparisonType":      graphqlValue(func(v interface{}) interface{} { return v.(*types.ComparisonResult).ComparisonType }),
+		"metricName":          graphqlValue(func(v interface{}) interface{} { return v.(*types.ComparisonResult).MetricName }),
+		"configurationScores": graphqlValue(func(v interface{}) interface{} { return v.(*types.ComparisonResult).ConfigurationScores }),
+		"bestConfigurationId": graphqlValue(func(v interface{}) interface{} { return v.(*types.ComparisonResult).BestConfigurationID }),
+		"analysisNotes":       graphqlValue(func(v interface{}) interface{} { return v.(*types.ComparisonResult).AnalysisNotes }),
+		"createdAt":           graphqlValue(func(v interface{}) interface{} { return v.(*types.ComparisonResult).CreatedAt }),
+		"bestConfiguration": {Type: configuration, Resolve: func(ctx context.Context, source interface{}, args graphql.Args) (interface{}, error) {
+			return source.(*types.ComparisonResult).BestConfiguration, nil
+		}},
+	}}
+
+	executionRun := &graphql.Object{Name: "ExecutionRun", Fields: map[string]*graphql.Field{
+		"id":                    graphqlValue(func(v interface{}) interface{} { return v.(*types.ExecutionRun).ID }),
+		"name":                  graphqlValue(func(v interface{}) interface{} { return v.(*types.ExecutionRun).Name }),
+		"description":           graphqlValue(func(v interface{}) interface{} { return v.(*types.ExecutionRun).Description }),
+		"status":                graphqlValue(func(v interface{}) interface{} { return v.(*types.ExecutionRun).Status }),
+		"errorMessage":          graphqlValue(func(v interface{}) interface{} { return v.(*types.ExecutionRun).ErrorMessage }),
+		"enableFunctionCalling": graphqlValue(func(v interface{}) interface{} { return v.(*types.ExecutionRun).EnableFunctionCalling }),
+		"parentRunId":           graphqlValue(func(v interface{}) interface{} { return v.(*types.ExecutionRun).ParentRunID }),
+		"replayOfRunId":         graphqlValue(func(v interface{}) interface{} { return v.(*types.ExecutionRun).ReplayOfRunID }),
+		"createdAt":             graphqlValue(func(v interface{}) interface{} { return v.(*types.ExecutionRun).CreatedAt }),
+		"updatedAt":             graphqlValue(func(v interface{}) interface{} { return v.(*types.ExecutionRun).UpdatedAt }),
+		"variationResults": {Type: variationResult, Resolve: func(ctx context.Context, source interface{}, args graphql.Args) (interface{}, error) {
+			result, err := s.executionResult(ctx, source.(*types.ExecutionRun).ID)
+			if err != nil || result == nil {
+				return []types.VariationResult{}, err
+			}
+			return result.Results, nil
+		}},
+		"functionCalls": {Type: functionCall, Resolve: func(ctx context.Context, source interface{}, args graphql.Args) (interface{}, error) {
+			result, err := s.executionResult(ctx, source.(*types.ExecutionRun).ID)
+			return runFunctionCalls(result), err
+		}},
+		"comparison": {Type: comparison, Resolve: func(ctx context.Context, source interface{}, args graphql.Args) (interface{}, error) {
+			result, err := s.executionResult(ctx, source.(*types.ExecutionRun).ID)
+			if err != nil || result == nil {
+				return nil, err
+			}
+			return result.Comparison, nil
+		}},
+	}}
+
+	function := &graphql.Object{Name: "Function", Fields: map[string]*graphql.Field{
+		"id":               graphqlValue(func(v interface{}) interface{} { return v.(*types.FunctionDefinition).ID }),
+		"name":             graphqlValue(func(v interface{}) interface{} { return v.(*types.FunctionDefinition).Name }),
+		"displayName":      graphqlValue(func(v interface{}) interface{} { return v.(*types.FunctionDefinition).DisplayName }),
+		"description":      graphqlValue(func(v interface{}) interface{} { return v.(*types.FunctionDefinition).Description }),
+		"parametersSchema": graphqlValue(func(v interface{}) interface{} { return v.(*types.FunctionDefinition).ParametersSchema }),
+		"mockResponse":     graphqlValue(func(v interface{}) interface{} { return v.(*types.FunctionDefinition).MockResponse }),
+		"endpointUrl":      graphqlValue(func(v interface{}) interface{} { return v.(*types.FunctionDefinition).EndpointURL }),
+		"httpMethod":       graphqlValue(func(v interface{}) interface{} { return v.(*types.FunctionDefinition).HttpMethod }),
+		"isActive":         graphqlValue(func(v interface{}) interface{} { return v.(*types.FunctionDefinition).IsActive }),
+		"requiredApiKeys":  graphqlValue(func(v interface{}) interface{} { return v.(*types.FunctionDefinition).RequiredApiKeys }),
+		"createdAt":        graphqlValue(func(v interface{}) interface{} { return v.(*types.FunctionDefinition).CreatedAt }),
+		"updatedAt":        graphqlValue(func(v interface{}) interface{} { return v.(*types.FunctionDefinition).UpdatedAt }),
+	}}
+
+	submission := &graphql.Object{Name: "ExecutionSubmission", Fields: map[string]*graphql.Field{
+		"id":            graphqlValue(func(v interface{}) interface{} { return v.(*graphqlSubmission).ExecutionRun.ID }),
+		"name":          graphqlValue(func(v interface{}) interface{} { return v.(*graphqlSubmission).ExecutionRun.Name }),
+		"status":        graphqlValue(func(v interface{}) interface{} { return v.(*graphqlSubmission).ExecutionRun.Status }),
+		"queuePosition": graphqlValue(func(v interface{}) interface{} { return v.(*graphqlSubmission).QueuePosition }),
+		"merged":        graphqlValue(func(v interface{}) interface{} { return v.(*graphqlSubmission).Merged }),
+		"message":       graphqlValue(func(v interface{}) interface{} { return v.(*graphqlSubmission).Message }),
+	}}
+
+	query := &graphql.Object{Name: "Query", Fields: map[string]*graphql.Field{
+		"executionRuns": {Type: executionRun, Resolve: func(ctx context.Context, source interface{}, args graphql.Args) (interface{}, error) {
+			userID, err := graphqlUserID(ctx)
+			if err != nil {
+				return nil, err
+			}
+			limit, offset, err := graphqlPage(args, 10)
+			if err != nil {
+				return nil, err
+			}
+			runs, err := s.client.ListExecutionRuns(ctx, userID, limit, offset)
+			if err != nil {
+				log.Printf("❌ Failed to list execution runs for GraphQL: %v", err)
+				return nil, errors.New("failed to list execution runs")
+			}
+			return runs, nil
+		}},
+		"executionRun": {Type: executionRun, Resolve: func(ctx context.Context, source interface{}, args graphql.Args) (interface{}, error) {
+			id, err := args.String("id", "")
+			if err != nil {
+				return nil, err
+			}
+			result, err := s.executionResult(ctx, id)
+			if err != nil || result == nil {
+				return nil, err
+			}
+			return &result.ExecutionRun, nil
+		}},
+		"variationResults": {Type: variationResult, Resolve: func(ctx context.Context, source interface{}, args graphql.Args) (interface{}, error) {
+			id, err := args.String("executionRunId", "")
+			if err != nil {
+				return nil, err
+			}
+			result, err := s.executionResult(ctx, id)
+			if err != nil || result == nil {
+				return []types.VariationResult{}, err
+			}
+			return result.Results, nil
+		}},
+		"functionCalls": {Type: functionCall, Resolve: func(ctx context.Context, source interface{}, args graphql.Args) (interface{}, error) {
+			id, err := args.String("executionRunId", "")
+			if err != nil {
+				return nil, err
+			}
+			result, err := s.executionResult(ctx, id)
+			return runFunctionCalls(result), err
+		}},
+		"comparisons": {Type: comparison, Resolve: func(ctx context.Context, source interface{}, args graphql.Args) (interface{}, error) {
+			userID, err := graphqlUserID(ctx)
+			if err != nil {
+				return nil, err
+			}
+			limit, offset, err := graphqlPage(args, 10)
+			if err != nil {
+				return nil, err
+			}
+			comparisons, err := s.client.ListComparisonResults(ctx, userID, limit, offset)
+			if err != nil {
+				log.Printf("❌ Failed to list comparisons for GraphQL: %v", err)
+				return nil, errors.New("failed to list comparisons")
+			}
+			return comparisons, nil
+		}},
+		"comparison": {Type: comparison, Resolve: func(ctx context.Context, source interface{}, args graphql.Args) (interface{}, error) {
+			id, err := args.String("executionRunId", "")
+			if err != nil {
+				return nil, err
+			}
+			result, err := s.executionResult(ctx, id)
+			if err != nil || result == nil {
+				return nil, err
+			}
+			return result.Comparison, nil
+		}},
+		"functions": {Type: function, Resolve: func(ctx context.Context, source interface{}, args graphql.Args) (interface{}, error) {
+			userID, err := graphqlUserID(ctx)
+			if err != nil {
+				return nil, err
+			}
+			limit, offset, err := graphqlPage(args, gogent.MaxPageSize)
+			if err != nil {
+				return nil, err
+			}
+			functions, err := s.client.ListFunctionDefinitions(ctx, userID, limit, offset)
+			if err != nil {
+				log.Printf("❌ Failed to list functions for GraphQL: %v", err)
+				return nil, errors.New("failed to list functions")
+			}
+			return functions, nil
+		}},
+		"function": {Type: function, Resolve: func(ctx context.Context, source interface{}, args graphql.Args) (interface{}, error) {
+			userID, err := graphqlUserID(ctx)
+			if err != nil {
+				return nil, err
+			}
+			id, err := args.String("id", "")
+			if err != nil {
+				return nil, err
+			}
+			definition, err := s.client.GetFunctionDefinition(ctx, userID, id)
+			if errors.Is(err, gogent.ErrFunctionNotFound) {
+				return nil, nil
+			}
+			if err != nil {
+				log.Printf("❌ Failed to get function %s for GraphQL: %v", id, err)
+				return nil, errors.New("failed to get function")
+			}
+			return definition, nil
+		}},
+	}}
+
+	mutation := &graphql.Object{Name: "Mutation", Fields: map[string]*graphql.Field{
+		"execute": {Type: submission, Resolve: func(ctx context.Context, source interface{}, args graphql.Args) (interface{}, error) {
+			input, err := graphqlInput(args)
+			if err != nil {
+				return nil, err
+			}
+			result := &graphqlSubmission{}
+			if err := graphqlDispatch(ctx, s.executeHandler, http.MethodPost, "/api/execute", input, result); err != nil {
+				return nil, err
+			}
+			return result, nil
+		}},
+		"createFunction": {Type: function, Resolve: func(ctx context.Context, source interface{}, args graphql.Args) (interface{}, error) {
+			input, err := graphqlInput(args)
+			if err != nil {
+				return nil, err
+			}
+			var result struct {
+				Data *types.FunctionDefinition `json:"data"`
+			}
+			if err := graphqlDispatch(ctx, s.functionsHandler, http.MethodPost, "/api/functions", input, &result); err != nil {
+				return nil, err
+			}
+			return result.Data, nil
+		}},
+		"updateFunction": {Type: function, Resolve: func(ctx context.Context, source interface{}, args graphql.Args) (interface{}, error) {
+			id, err := graphqlID(args)
+			if err != nil {
+				return nil, err
+			}
+			input, err := graphqlInput(args)
+			if err != nil {
+				return nil, err
+			}
+			var result struct {
+				Data *types.FunctionDefinition `json:"data"`
+			}
+			if err := graphqlDispatch(ctx, s.functionByIDHandler, http.MethodPut, "/api/functions/"+id, input, &result); err != nil {
+				return nil, err
+			}
+			return result.Data, nil
+		}},
+		"deleteFunction": {Resolve: func(ctx context.Context, source interface{}, args graphql.Args) (interface{}, error) {
+			id, err := graphqlID(args)
+			if err != nil {
+				return nil, err
+			}
+			if err := graphqlDispatch(ctx, s.functionByIDHandler, http.MethodDelete, "/api/functions/"+id, nil, nil); err != nil {
+				return nil, err
+			}
+			return true, nil
+		}},
+	}}
+
+	return &graphql.Schema{Query: query, Mutation: mutation}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"net/netip"
+	"strings"
+
+	"gogent/internal/auth"
+	"gogent/internal/types"
+	pb "gogent/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// publicGRPCMethods lists the RPCs that can be called without a token
+var publicGRPCMethods = map[string]bool{
+	pb.GogentService_Login_FullMethodName:                true,
+	pb.GogentService_Register_FullMethodName:             true,
+	pb.GogentService_CreateTemporaryUser_FullMethodName:  true,
+	pb.GogentService_VerifyEmail_FullMethodName:          true,
+	pb.GogentService_RefreshToken_FullMethodName:         true,
+	pb.GogentService_RequestPasswordReset_FullMethodName: true,
+	pb.GogentService_ResetPassword_FullMethodName:        true,
+	pb.GogentService_Health_FullMethodName:               true,
+}
+
+// authUnaryInterceptor validates the Bearer token or API key from metadata and adds the user to context
+func (s *GRPCServer) authUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if publicGRPCMethods[info.FullMethod] {
+		return handler(ctx, req)
+	}
+
+	authCtx, err := s.authenticateContext(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(authCtx, req)
+}
+
+// authStreamInterceptor validates the Bearer token for streaming RPCs
+func (s *GRPCServer) authStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if publicGRPCMethods[info.FullMethod] {
+		return handler(srv, ss)
+	}
+
+	authCtx, err := s.authenticateContext(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &authenticatedStream{ServerStream: ss, ctx: authCtx})
+}
+
+// authenticatedStream overrides the stream context with the authenticated one
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context carrying the authenticated user
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// authenticateContext validates the Bearer token or API key in the incoming metadata and returns a context with the user
+func (s *GRPCServer) authenticateContext(ctx context.Context, fullMethod string) (context.Context, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "Missing metadata")
+	}
+
+	values := md.Get("authorization")
+	if len(values) == 0 || values[0] == "" {
+		return nil, status.Error(codes.Unauthenticated, "Authorization metadata required")
+	}
+
+	user, apiKey, err := s.businessLogic.AuthenticateHeader(values[0])
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "Invalid credentials: %v", err)
+	}
+
+	authCtx := context.WithValue(ctx, auth.UserContextKey{}, user)
+	if apiKey != nil {
+		if !auth.APIKeyAllows(apiKey, isWriteGRPCMethod(fullMethod)) {
+			return nil, status.Error(codes.PermissionDenied, "API key scope does not allow this call")
+		}
+		authCtx = context.WithValue(authCtx, auth.APIKeyContextKey{}, apiKey)
+	}
+
+	return authCtx, nil
+}
+
+// isWriteGRPCMethod reports whether an RPC modifies state; Get*, List*, Watch* and Health are read-only
+func isWriteGRPCMethod(fullMethod string) bool {
+	name := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	return !strings.HasPrefix(name, "Get") && !strings.HasPrefix(name, "List") && !strings.HasPrefix(name, "Watch") && name != "Health"
+}
+
+// bearerTokenFromContext returns the JWT the caller sent in its authorization metadata
+func bearerTokenFromContext(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", status.Error(codes.Unauthenticated, "Missing metadata")
+	}
+
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return "", status.Error(codes.Unauthenticated, "Authorization metadata required")
+	}
+
+	return auth.ExtractTokenFromHeader(values[0])
+}
+
+// getUserID extracts the authenticated user ID from the RPC context
+func (s *GRPCServer) getUserID(ctx context.Context) (string, error) {
+	user, ok := auth.GetUserFromContext(ctx)
+	if !ok || user == nil {
+		return "", status.Error(codes.Unauthenticated, "User not found in context")
+	}
+	return user.ID, nil
+}
+
+// audit records a security-relevant action taken by the RPC, by the authenticated user unless the
+// event names its actor
+func (s *GRPCServer) audit(ctx context.Context, event *types.AuditEvent) {
+	if event.ActorID == "" {
+		if user, ok := auth.GetUserFromContext(ctx); ok && user != nil {
+			event.ActorID = user.ID
+		}
+	}
+	event.IPAddress = s.peerIP(ctx)
+	s.businessLogic.RecordAuditEvent(ctx, event)
+}
+
+// peerIP returns the caller's address: the connection's remote host, or the client that the REST
+// gateway, which connects over localhost, or a trusted proxy forwarded the call for
+func (s *GRPCServer) peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	var forwarded string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		forwarded = strings.Join(md.Get("x-forwarded-for"), ",")
+	}
+	address := p.Addr.String()
+	trusted := s.trustedProxies.trusts(address)
+	if addr, err := netip.ParseAddr(hostOnly(address)); err == nil && addr.IsLoopback() {
+		trusted = true
+	}
+	return s.trustedProxies.clientIP(address, forwarded, trusted)
+}
+
+// requireAdmin returns a PermissionDenied error unless the caller is an admin
+func (s *GRPCServer) requireAdmin(ctx context.Context) error {
+	user, ok := auth.GetUserFromContext(ctx)
+	if !ok || user == nil {
+		return status.Error(codes.Unauthenticated, "User not found in context")
+	}
+	if !user.IsAdmin() {
+		return status.Error(codes.PermissionDenied, "Admin access required")
+	}
+	return nil
+}
+
+// resolveUserScope returns the user ID to scope a query to, or "" for an admin requesting all users
+func (s *GRPCServer) resolveUserScope(ctx context.Context, allUsers bool) (string, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return "", err
+	}
+	if !allUsers {
+		return userID, nil
+	}
+	if err := s.requireAdmin(ctx); err != nil {
+		return "", err
+	}
+	return "", nil
+}
@@ -2,13 +2,18 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net"
 	"os"
+	"strings"
 	"time"
 
 	"gogent/internal/auth"
+	"gogent/internal/gogent"
 	"gogent/internal/types"
 	pb "gogent/proto"
 
@@ -22,20 +27,26 @@ import (
 // GRPCServer implements the GogentServiceServer interface
 type GRPCServer struct {
 	pb.UnimplementedGogentServiceServer
-	businessLogic *BusinessLogic
+	businessLogic  *BusinessLogic
+	trustedProxies trustedProxies
 }
 
 // NewGRPCServer creates a new gRPC server
 func NewGRPCServer() (*GRPCServer, error) {
-	// For gRPC operations, we'll use a system user ID since gRPC doesn't have JWT context
-	// In a real implementation, you'd extract user ID from gRPC metadata
-	businessLogic, err := NewBusinessLogic("system-user")
+	// Callers are identified per request by the auth interceptors
+	businessLogic, err := NewBusinessLogic()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create business logic: %w", err)
 	}
+	proxies, err := loadTrustedProxies()
+	if err != nil {
+		businessLogic.Close()
+		return nil, err
+	}
 
 	return &GRPCServer{
-		businessLogic: businessLogic,
+		businessLogic:  businessLogic,
+		trustedProxies: proxies,
 	}, nil
 }
 
@@ -52,34 +63,38 @@ func (s *GRPCServer) Close() error {
 // =============================================================================
 
 func (s *GRPCServer) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
-	user, token, expiresAt, err := s.businessLogic.LoginUser(req.Username, req.Password)
+	user, token, refreshToken, expiresAt, err := s.businessLogic.LoginUser(req.Username, req.Password)
 	if err != nil {
+		s.audit(ctx, &types.AuditEvent{Action: types.AuditLoginFailed, Summary: map[string]interface{}{"username": req.Username}})
 		return nil, status.Errorf(codes.Unauthenticated, "Login failed: %v", err)
 	}
+	s.audit(ctx, &types.AuditEvent{ActorID: user.ID, Action: types.AuditLogin, Summary: map[string]interface{}{"username": user.Username}})
 
 	protoUser := s.convertUserToProto(user)
 	return &pb.LoginResponse{
-		Token:     token,
-		User:      protoUser,
-		ExpiresAt: timestamppb.New(expiresAt),
+		Token:        token,
+		User:         protoUser,
+		ExpiresAt:    timestamppb.New(expiresAt),
+		RefreshToken: refreshToken,
 	}, nil
 }
 
 func (s *GRPCServer) Register(ctx context.Context, req *pb.RegisterRequest) (*pb.RegisterResponse, error) {
-	user, token, err := s.businessLogic.RegisterUser(req.Username, req.Email, req.Password)
+	user, token, refreshToken, err := s.businessLogic.RegisterUser(req.Username, req.Email, req.Password)
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "Registration failed: %v", err)
 	}
 
 	protoUser := s.convertUserToProto(user)
 	return &pb.RegisterResponse{
-		User:  protoUser,
-		Token: token,
+		User:         protoUser,
+		Token:        token,
+		RefreshToken: refreshToken,
 	}, nil
 }
 
 func (s *GRPCServer) CreateTemporaryUser(ctx context.Context, req *pb.CreateTemporaryUserRequest) (*pb.CreateTemporaryUserResponse, error) {
-	user, tempPassword, token, err := s.businessLogic.CreateTemporaryUser(req.SessionId)
+	user, tempPassword, token, refreshToken, err := s.businessLogic.CreateTemporaryUser(req.SessionId)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "Failed to create temporary user: %v", err)
 	}
@@ -89,11 +104,12 @@ func (s *GRPCServer) CreateTemporaryUser(ctx context.Context, req *pb.CreateTemp
 		User:              protoUser,
 		TemporaryPassword: tempPassword,
 		Token:             token,
+		RefreshToken:      refreshToken,
 	}, nil
 }
 
 func (s *GRPCServer) SaveTemporaryAccount(ctx context.Context, req *pb.SaveTemporaryAccountRequest) (*pb.SaveTemporaryAccountResponse, error) {
-	user, emailSent, err := s.businessLogic.SaveTemporaryAccount(req.Email)
+	user, emailSent, err := s.businessLogic.SaveTemporaryAccount(ctx, req.Email, req.CurrentPassword)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "Failed to save temporary account: %v", err)
 	}
@@ -119,7 +135,7 @@ func (s *GRPCServer) VerifyEmail(ctx context.Context, req *pb.VerifyEmailRequest
 }
 
 func (s *GRPCServer) GetCurrentUser(ctx context.Context, req *pb.GetCurrentUserRequest) (*pb.GetCurrentUserResponse, error) {
-	user, err := s.businessLogic.GetCurrentUser()
+	user, err := s.businessLogic.GetCurrentUser(ctx)
 	if err != nil {
 		return nil, status.Errorf(codes.Unauthenticated, "Failed to get current user: %v", err)
 	}
@@ -130,11 +146,79 @@ func (s *GRPCServer) GetCurrentUser(ctx context.Context, req *pb.GetCurrentUserR
 	}, nil
 }
 
+func (s *GRPCServer) RefreshToken(ctx context.Context, req *pb.RefreshTokenRequest) (*pb.RefreshTokenResponse, error) {
+	if req.RefreshToken == "" {
+		return nil, status.Error(codes.InvalidArgument, "Refresh token is required")
+	}
+
+	user, token, refreshToken, expiresAt, err := s.businessLogic.RefreshToken(req.RefreshToken)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "Token refresh failed: %v", err)
+	}
+
+	return &pb.RefreshTokenResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		User:         s.convertUserToProto(user),
+		ExpiresAt:    timestamppb.New(expiresAt),
+	}, nil
+}
+
+func (s *GRPCServer) Logout(ctx context.Context, req *pb.LogoutRequest) (*pb.LogoutResponse, error) {
+	token, err := bearerTokenFromContext(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Logout requires a session token: %v", err)
+	}
+
+	if err := s.businessLogic.Logout(token, req.AllSessions); err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "Logout failed: %v", err)
+	}
+
+	return &pb.LogoutResponse{
+		Success: true,
+	}, nil
+}
+
+func (s *GRPCServer) RequestPasswordReset(ctx context.Context, req *pb.RequestPasswordResetRequest) (*pb.RequestPasswordResetResponse, error) {
+	if req.Email == "" {
+		return nil, status.Error(codes.InvalidArgument, "Email is required")
+	}
+
+	if err := s.businessLogic.RequestPasswordReset(req.Email); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to process password reset request: %v", err)
+	}
+
+	return &pb.RequestPasswordResetResponse{
+		Success: true,
+		Message: "If an account exists for that email, a reset link has been sent",
+	}, nil
+}
+
+func (s *GRPCServer) ResetPassword(ctx context.Context, req *pb.ResetPasswordRequest) (*pb.ResetPasswordResponse, error) {
+	if req.Token == "" {
+		return nil, status.Error(codes.InvalidArgument, "Reset token is required")
+	}
+
+	user, err := s.businessLogic.ResetPassword(req.Token, req.NewPassword)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Password reset failed: %v", err)
+	}
+
+	return &pb.ResetPasswordResponse{
+		User: s.convertUserToProto(user),
+	}, nil
+}
+
 // =============================================================================
 // EXECUTION MANAGEMENT
 // =============================================================================
 
 func (s *GRPCServer) Execute(ctx context.Context, req *pb.ExecuteRequest) (*pb.ExecuteResponse, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	// Convert protobuf request to internal type
 	request, err := s.convertProtoExecuteRequestToInternal(req)
 	if err != nil {
@@ -168,9 +252,37 @@ func (s *GRPCServer) Execute(ctx context.Context, req *pb.ExecuteRequest) (*pb.E
 		}
 	}
 
+	// Fill omitted values from the workspace defaults and check the safety policies
+	if err := s.businessLogic.PrepareExecutionRequest(ctx, userID, request); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid execution request: %v", err)
+	}
+
+	// Apply the duplicate policy and give the run a unique name
+	existingRun, err := s.businessLogic.PrepareSubmission(ctx, userID, request)
+	if err != nil {
+		var duplicate *gogent.DuplicateSubmissionError
+		if errors.As(err, &duplicate) {
+			return nil, status.Errorf(codes.AlreadyExists, "%v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "Failed to prepare execution: %v", err)
+	}
+	if existingRun != nil {
+		return &pb.ExecuteResponse{
+			ExecutionRun: s.convertExecutionRunToProto(existingRun),
+			Message:      fmt.Sprintf("Identical submission merged into execution run: %s", existingRun.Name),
+			Merged:       true,
+		}, nil
+	}
+
 	// Start execution with session API keys
-	executionID, executionRun, err := s.businessLogic.StartExecution(request, req.GetUseMock(), sessionApiKeys)
+	executionID, executionRun, err := s.businessLogic.StartExecution(ctx, userID, request, req.GetUseMock(), sessionApiKeys)
 	if err != nil {
+		if errors.Is(err, gogent.ErrQuotaExceeded) {
+			return nil, status.Errorf(codes.ResourceExhausted, "%v", err)
+		}
+		if errors.Is(err, gogent.ErrQueueFull) || errors.Is(err, gogent.ErrQueueClosed) {
+			return nil, status.Errorf(codes.Unavailable, "%v", err)
+		}
 		return nil, status.Errorf(codes.Internal, "Failed to start execution: %v", err)
 	}
 
@@ -185,14 +297,20 @@ func (s *GRPCServer) Execute(ctx context.Context, req *pb.ExecuteRequest) (*pb.E
 }
 
 func (s *GRPCServer) GetExecutionStatus(ctx context.Context, req *pb.GetExecutionStatusRequest) (*pb.GetExecutionStatusResponse, error) {
-	execStatus, startTime, endTime, errorMessage, result, err := s.businessLogic.GetExecutionStatus(ctx, req.ExecutionId)
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	execStatus, startTime, endTime, errorMessage, result, err := s.businessLogic.GetExecutionStatus(ctx, userID, req.ExecutionId)
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, err.Error())
 	}
 
 	response := &pb.GetExecutionStatusResponse{
-		Status:    execStatus,
-		StartTime: timestamppb.New(startTime),
+		Status:        execStatus,
+		StartTime:     timestamppb.New(startTime),
+		QueuePosition: int32(s.businessLogic.QueuePosition(req.ExecutionId)),
 	}
 
 	if endTime != nil {
@@ -214,7 +332,12 @@ func (s *GRPCServer) GetExecutionStatus(ctx context.Context, req *pb.GetExecutio
 }
 
 func (s *GRPCServer) GetExecutionResult(ctx context.Context, req *pb.GetExecutionResultRequest) (*pb.GetExecutionResultResponse, error) {
-	result, err := s.businessLogic.GetExecutionResult(ctx, req.ExecutionRunId)
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.businessLogic.GetExecutionResult(ctx, userID, req.ExecutionRunId)
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "Execution result not found: %v", err)
 	}
@@ -230,7 +353,23 @@ func (s *GRPCServer) GetExecutionResult(ctx context.Context, req *pb.GetExecutio
 }
 
 func (s *GRPCServer) ListExecutionRuns(ctx context.Context, req *pb.ListExecutionRunsRequest) (*pb.ListExecutionRunsResponse, error) {
-	runs, err := s.businessLogic.ListExecutionRuns(ctx, req.Limit, req.Offset)
+	userID, err := s.resolveUserScope(ctx, req.AllUsers)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, offset, err := resolvePage(req.Limit, req.Offset, req.Cursor, 10)
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []*types.ExecutionRun
+	var total int64
+	if userID == "" {
+		runs, total, err = s.businessLogic.ListAllExecutionRuns(ctx, limit, offset)
+	} else {
+		runs, total, err = s.businessLogic.ListExecutionRuns(ctx, userID, limit, offset)
+	}
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "Failed to list execution runs: %v", err)
 	}
@@ -243,71 +382,529 @@ func (s *GRPCServer) ListExecutionRuns(ctx context.Context, req *pb.ListExecutio
 
 	return &pb.ListExecutionRunsResponse{
 		ExecutionRuns: protoRuns,
-		TotalCount:    int32(len(protoRuns)),
+		TotalCount:    int32(total),
+		NextCursor:    gogent.NextCursor(offset, len(runs), total),
 	}, nil
 }
 
+// ListComparisons lists the comparisons of the user's runs, newest first
+func (s *GRPCServer) ListComparisons(ctx context.Context, req *pb.ListComparisonsRequest) (*pb.ListComparisonsResponse, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	limit, offset, err := resolvePage(req.Limit, req.Offset, req.Cursor, 10)
+	if err != nil {
+		return nil, err
+	}
+
+	comparisons, total, err := s.businessLogic.ListComparisons(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to list comparisons: %v", err)
+	}
+
+	protoComparisons := make([]*pb.ComparisonResult, 0, len(comparisons))
+	for _, comparison := range comparisons {
+		protoComparisons = append(protoComparisons, convertComparisonToProto(comparison))
+	}
+
+	return &pb.ListComparisonsResponse{
+		Comparisons: protoComparisons,
+		TotalCount:  int32(total),
+		NextCursor:  gogent.NextCursor(offset, len(comparisons), total),
+	}, nil
+}
+
+// GetComparison returns the comparison of one of the user's runs
+func (s *GRPCServer) GetComparison(ctx context.Context, req *pb.GetComparisonRequest) (*pb.GetComparisonResponse, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison, err := s.businessLogic.GetComparison(ctx, userID, req.ExecutionRunId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "Comparison not found for execution run %s", req.ExecutionRunId)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to get comparison: %v", err)
+	}
+
+	return &pb.GetComparisonResponse{Comparison: convertComparisonToProto(comparison)}, nil
+}
+
+// ListExecutionLogs pages through the log entries of one of the user's runs, oldest first
+func (s *GRPCServer) ListExecutionLogs(ctx context.Context, req *pb.ListExecutionLogsRequest) (*pb.ListExecutionLogsResponse, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := gogent.ExecutionLogFilter{
+		Category: types.LogCategory(strings.ToUpper(req.Category)),
+		AfterID:  req.After,
+		Limit:    req.Limit,
+	}
+	if req.Level != "" {
+		if filter.MinLevel, err = types.ParseLogLevel(req.Level); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+	}
+
+	logs, err := s.businessLogic.ListExecutionLogs(ctx, userID, req.ExecutionRunId, filter)
+	if errors.Is(err, gogent.ErrUnknownLogEntry) {
+		return nil, status.Errorf(codes.InvalidArgument, "Unknown log entry %s", req.After)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to get execution logs: %v", err)
+	}
+
+	protoLogs := make([]*pb.ExecutionLog, 0, len(logs))
+	for _, entry := range logs {
+		protoLogs = append(protoLogs, convertExecutionLogToProto(entry))
+	}
+	return &pb.ListExecutionLogsResponse{Logs: protoLogs}, nil
+}
+
+// watchInterval is how often WatchExecution checks on an execution
+const watchInterval = 500 * time.Millisecond
+
+// WatchExecution streams an execution's status, variation progress and new log entries, sending an
+// event whenever one of them changes. The final event carries the result of a completed execution.
+func (s *GRPCServer) WatchExecution(req *pb.WatchExecutionRequest, stream pb.GogentService_WatchExecutionServer) error {
+	ctx := stream.Context()
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	var logFilter *gogent.ExecutionLogFilter
+	if req.LogLevel != "" {
+		level, err := types.ParseLogLevel(req.LogLevel)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		logFilter = &gogent.ExecutionLogFilter{MinLevel: level}
+	}
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+	lastState := ""
+	for {
+		// Executions no longer tracked in memory have finished, or were stored runs to begin with
+		state, tracked := s.businessLogic.ExecutionState(userID, req.ExecutionId)
+		finished := !tracked || state.Status == "completed" || state.Status == "failed"
+		executionID := req.ExecutionId
+		if tracked {
+			executionID = state.ID
+		}
+
+		event := &pb.ExecutionEvent{
+			ExecutionId:    executionID,
+			ExecutionRunId: state.RealExecutionRunID,
+			Status:         state.Status,
+			QueuePosition:  int32(s.businessLogic.QueuePosition(executionID)),
+			ErrorMessage:   state.ErrorMessage,
+		}
+		if progress := state.Progress; progress != nil {
+			event.TotalVariations = int32(progress.Total)
+			event.CompletedVariations = int32(progress.Completed)
+			event.FailedVariations = int32(progress.Failed)
+			event.LastVariation = progress.Variation
+		}
+		if finished {
+			execStatus, _, _, errorMessage, result, err := s.businessLogic.GetExecutionStatus(ctx, userID, executionID)
+			if err != nil {
+				return status.Errorf(codes.NotFound, "%v", err)
+			}
+			event.Status = execStatus
+			event.ErrorMessage = errorMessage
+			event.QueuePosition = 0
+			if result != nil {
+				// Stored runs report their own status, which may be failed
+				if !tracked && result.ExecutionRun.Status != "" {
+					event.Status = result.ExecutionRun.Status
+					event.ErrorMessage = result.ExecutionRun.ErrorMessage
+				}
+				event.ExecutionRunId = result.ExecutionRun.ID
+				event.TotalVariations = int32(len(result.Results))
+				event.CompletedVariations = int32(len(result.Results))
+				event.FailedVariations = int32(result.ErrorCount)
+				if event.Result, err = s.convertExecutionResultToProto(result); err != nil {
+					return status.Errorf(codes.Internal, "Failed to convert result: %v", err)
+				}
+			}
+		}
+
+		// Send the entries written since the last event, every remaining page of them once finished
+		for logFilter != nil && event.ExecutionRunId != "" {
+			logs, err := s.businessLogic.ListExecutionLogs(ctx, userID, event.ExecutionRunId, *logFilter)
+			if err != nil {
+				return status.Errorf(codes.Internal, "Failed to get execution logs: %v", err)
+			}
+			for _, entry := range logs {
+				event.Logs = append(event.Logs, convertExecutionLogToProto(entry))
+			}
+			if len(logs) == 0 {
+				break
+			}
+			logFilter.AfterID = logs[len(logs)-1].ID
+			if !finished {
+				break
+			}
+		}
+
+		stateKey := fmt.Sprintf("%s/%s/%d/%d/%d", event.Status, event.ExecutionRunId, event.QueuePosition, event.CompletedVariations, event.FailedVariations)
+		if stateKey != lastState || len(event.Logs) > 0 || finished {
+			lastState = stateKey
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+		if finished {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// convertExecutionLogToProto converts a log entry to its protobuf message
+func convertExecutionLogToProto(entry types.ExecutionLog) *pb.ExecutionLog {
+	protoLog := &pb.ExecutionLog{
+		Id:             entry.ID,
+		ExecutionRunId: entry.ExecutionRunID,
+		LogLevel:       string(entry.LogLevel),
+		LogCategory:    string(entry.LogCategory),
+		Message:        entry.Message,
+		Timestamp:      timestamppb.New(entry.Timestamp),
+	}
+	if entry.ConfigurationID != nil {
+		protoLog.ConfigurationId = *entry.ConfigurationID
+	}
+	if entry.RequestID != nil {
+		protoLog.RequestId = *entry.RequestID
+	}
+	if entry.Details != nil {
+		if details, err := structpb.NewStruct(entry.Details); err == nil {
+			protoLog.Details = details
+		}
+	}
+	return protoLog
+}
+
+// resolvePage applies a list request's defaults and cursor, rejecting malformed cursors
+func resolvePage(limit, offset int32, cursor string, defaultLimit int32) (int32, int32, error) {
+	limit, offset, err := gogent.ResolvePage(limit, offset, cursor, defaultLimit)
+	if err != nil {
+		return 0, 0, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	return limit, offset, nil
+}
+
 func (s *GRPCServer) DeleteExecutionRun(ctx context.Context, req *pb.DeleteExecutionRunRequest) (*pb.DeleteExecutionRunResponse, error) {
-	err := s.businessLogic.DeleteExecutionRun(ctx, req.ExecutionRunId)
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.businessLogic.DeleteExecutionRun(ctx, userID, req.ExecutionRunId)
+	if errors.Is(err, gogent.ErrExecutionRunNotFound) {
+		return nil, status.Errorf(codes.NotFound, "%v", err)
+	}
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "Failed to delete execution run: %v", err)
 	}
+	s.audit(ctx, &types.AuditEvent{Action: types.AuditRunDeleted, TargetType: "execution_run", TargetID: req.ExecutionRunId})
 
 	return &pb.DeleteExecutionRunResponse{
 		Message: fmt.Sprintf("Execution run %s deleted successfully", req.ExecutionRunId),
 	}, nil
 }
 
+// =============================================================================
+// BATCH SUBMISSION
+// =============================================================================
+
+const (
+	// batchAckChunkSize is how many items are stored and acknowledged together
+	batchAckChunkSize = 500
+	// maxBatchItemsPerMessage bounds the items a single stream message may carry
+	maxBatchItemsPerMessage = 1000
+	// maxBatchItems bounds the total items of one batch run
+	maxBatchItems = 100000
+)
+
+// SubmitBatch receives a streamed batch: a template followed by dataset items. Items are stored in
+// chunks of batchAckChunkSize, each acknowledged on the response stream. No further messages are
+// read while a chunk is written, so gRPC flow control applies backpressure to fast senders.
+func (s *GRPCServer) SubmitBatch(stream pb.GogentService_SubmitBatchServer) error {
+	ctx := stream.Context()
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return status.Error(codes.InvalidArgument, "batch template is required")
+	}
+	if err != nil {
+		return err
+	}
+	if first.Template == nil {
+		return status.Error(codes.InvalidArgument, "the first message must carry the batch template")
+	}
+
+	template, err := s.convertProtoExecuteRequestToInternal(first.Template)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "Invalid batch template: %v", err)
+	}
+	if err := s.businessLogic.PrepareExecutionRequest(ctx, userID, template); err != nil {
+		return status.Errorf(codes.InvalidArgument, "Invalid batch template: %v", err)
+	}
+
+	name := first.Name
+	if name == "" {
+		name = template.ExecutionRunName
+	}
+	if name == "" {
+		name = fmt.Sprintf("batch-%s", time.Now().Format("20060102-150405"))
+	}
+
+	batch, err := s.businessLogic.CreateBatchRun(ctx, userID, name, template)
+	if err != nil {
+		return status.Errorf(codes.Internal, "Failed to create batch run: %v", err)
+	}
+
+	var stored int32
+	var pending []types.BatchItem
+
+	// flush stores up to one chunk of pending items and acknowledges them
+	flush := func(count int) error {
+		chunk := pending[:count]
+		if err := s.businessLogic.AppendBatchItems(ctx, batch.ID, stored, chunk); err != nil {
+			return status.Errorf(codes.Internal, "Failed to store batch items: %v", err)
+		}
+		stored += int32(count)
+		pending = pending[count:]
+
+		return stream.Send(&pb.SubmitBatchAck{
+			BatchId:           batch.ID,
+			AcknowledgedItems: stored,
+			ChunkItems:        int32(count),
+			Status:            types.BatchStatusSubmitting,
+		})
+	}
+
+	// fail marks the batch as failed so partially submitted items are never run
+	fail := func(err error) error {
+		s.businessLogic.FailBatchRun(batch.ID, err.Error())
+		return err
+	}
+
+	for msg := first; ; {
+		if len(msg.Items) > maxBatchItemsPerMessage {
+			return fail(status.Errorf(codes.InvalidArgument, "at most %d items may be sent per message", maxBatchItemsPerMessage))
+		}
+		if int(stored)+len(pending)+len(msg.Items) > maxBatchItems {
+			return fail(status.Errorf(codes.ResourceExhausted, "batch runs are limited to %d items", maxBatchItems))
+		}
+
+		for _, protoItem := range msg.Items {
+			if protoItem.Prompt == "" {
+				index := int(stored) + len(pending)
+				return fail(status.Errorf(codes.InvalidArgument, "item %d has no prompt", index))
+			}
+			expectedAnswer := convertProtoExpectedAnswer(protoItem.ExpectedAnswer)
+			if err := gogent.ValidateExpectedAnswer(expectedAnswer); err != nil {
+				index := int(stored) + len(pending)
+				return fail(status.Errorf(codes.InvalidArgument, "item %d: %v", index, err))
+			}
+			pending = append(pending, types.BatchItem{
+				ExternalID:     protoItem.ExternalId,
+				Prompt:         protoItem.Prompt,
+				Context:        protoItem.Context,
+				Metadata:       protoItem.Metadata,
+				ExpectedAnswer: expectedAnswer,
+			})
+		}
+
+		for len(pending) >= batchAckChunkSize {
+			if err := flush(batchAckChunkSize); err != nil {
+				return fail(err)
+			}
+		}
+
+		msg, err = stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fail(err)
+		}
+	}
+
+	if len(pending) > 0 {
+		if err := flush(len(pending)); err != nil {
+			return fail(err)
+		}
+	}
+	if stored == 0 {
+		return fail(status.Error(codes.InvalidArgument, "batch contains no items"))
+	}
+
+	if err := s.businessLogic.StartBatchRun(ctx, userID, batch.ID); err != nil {
+		return fail(status.Errorf(codes.Internal, "Failed to start batch run: %v", err))
+	}
+
+	return stream.Send(&pb.SubmitBatchAck{
+		BatchId:           batch.ID,
+		AcknowledgedItems: stored,
+		Complete:          true,
+		Status:            types.BatchStatusSubmitted,
+	})
+}
+
+func (s *GRPCServer) GetBatchRun(ctx context.Context, req *pb.GetBatchRunRequest) (*pb.GetBatchRunResponse, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	batch, err := s.businessLogic.GetBatchRun(ctx, userID, req.Id)
+	if errors.Is(err, gogent.ErrBatchNotFound) {
+		return nil, status.Errorf(codes.NotFound, "Batch run not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to get batch run: %v", err)
+	}
+
+	return &pb.GetBatchRunResponse{
+		BatchRun: &pb.BatchRun{
+			Id:             batch.ID,
+			UserId:         batch.UserID,
+			Name:           batch.Name,
+			Status:         batch.Status,
+			TotalItems:     batch.TotalItems,
+			CompletedItems: batch.CompletedItems,
+			FailedItems:    batch.FailedItems,
+			ErrorMessage:   batch.ErrorMessage,
+			CreatedAt:      timestamppb.New(batch.CreatedAt),
+			UpdatedAt:      timestamppb.New(batch.UpdatedAt),
+			Accuracy:       convertAccuracyToProto(batch.Accuracy),
+		},
+	}, nil
+}
+
 // =============================================================================
 // CONFIGURATION MANAGEMENT
 // =============================================================================
 
+// ListConfigurations lists the user's configuration presets, followed by the system configurations when an admin asks for them
 func (s *GRPCServer) ListConfigurations(ctx context.Context, req *pb.ListConfigurationsRequest) (*pb.ListConfigurationsResponse, error) {
-	configs := s.businessLogic.GetDefaultConfigurations()
-	var protoConfigs []*pb.APIConfiguration
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, config := range configs {
-		protoConfig := s.convertConfigurationToProto(&config)
-		protoConfigs = append(protoConfigs, protoConfig)
+	limit, offset, err := resolvePage(req.Limit, req.Offset, req.Cursor, gogent.MaxPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	presets, total, err := s.businessLogic.ListConfigurationPresets(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, presetStatusError("list", err)
+	}
+	protoConfigs := make([]*pb.APIConfiguration, 0, len(presets))
+	for _, preset := range presets {
+		protoConfigs = append(protoConfigs, s.convertPresetToProto(preset))
+	}
+
+	if req.IncludeSystem {
+		if err := s.requireAdmin(ctx); err != nil {
+			return nil, err
+		}
+		systemConfigs, err := s.businessLogic.GetSystemConfigurations(ctx)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Failed to get system configurations: %v", err)
+		}
+		for _, config := range systemConfigs {
+			protoConfigs = append(protoConfigs, s.convertConfigurationToProto(&config))
+		}
 	}
 
 	return &pb.ListConfigurationsResponse{
 		Configurations: protoConfigs,
+		TotalCount:     int32(total),
+		NextCursor:     gogent.NextCursor(offset, len(presets), total),
 	}, nil
 }
 
+// CreateConfiguration saves a configuration preset named after the configuration's variation name
 func (s *GRPCServer) CreateConfiguration(ctx context.Context, req *pb.CreateConfigurationRequest) (*pb.CreateConfigurationResponse, error) {
-	config := s.convertProtoConfigurationToInternal(req.Configuration)
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.Configuration == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "configuration is required")
+	}
 
-	createdConfig, err := s.businessLogic.CreateConfiguration(config)
+	preset := s.convertProtoConfigurationToPreset(req.Configuration)
+	if err := gogent.ValidateConfigurationPreset(preset); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	created, err := s.businessLogic.CreateConfigurationPreset(ctx, userID, preset)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Failed to create configuration: %v", err)
+		return nil, presetStatusError("create", err)
 	}
 
-	protoConfig := s.convertConfigurationToProto(createdConfig)
 	return &pb.CreateConfigurationResponse{
-		Configuration: protoConfig,
+		Configuration: s.convertPresetToProto(created),
 	}, nil
 }
 
+// UpdateConfiguration replaces a configuration preset
 func (s *GRPCServer) UpdateConfiguration(ctx context.Context, req *pb.UpdateConfigurationRequest) (*pb.UpdateConfigurationResponse, error) {
-	config := s.convertProtoConfigurationToInternal(req.Configuration)
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.Configuration == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "configuration is required")
+	}
+
+	preset := s.convertProtoConfigurationToPreset(req.Configuration)
+	if err := gogent.ValidateConfigurationPreset(preset); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 
-	updatedConfig, err := s.businessLogic.UpdateConfiguration(req.Id, config)
+	updated, err := s.businessLogic.UpdateConfigurationPreset(ctx, userID, req.Id, preset)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Failed to update configuration: %v", err)
+		return nil, presetStatusError("update", err)
 	}
 
-	protoConfig := s.convertConfigurationToProto(updatedConfig)
 	return &pb.UpdateConfigurationResponse{
-		Configuration: protoConfig,
+		Configuration: s.convertPresetToProto(updated),
 	}, nil
 }
 
+// DeleteConfiguration deletes a configuration preset
 func (s *GRPCServer) DeleteConfiguration(ctx context.Context, req *pb.DeleteConfigurationRequest) (*pb.DeleteConfigurationResponse, error) {
-	err := s.businessLogic.DeleteConfiguration(req.Id)
+	userID, err := s.getUserID(ctx)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Failed to delete configuration: %v", err)
+		return nil, err
+	}
+
+	if err := s.businessLogic.DeleteConfigurationPreset(ctx, userID, req.Id); err != nil {
+		return nil, presetStatusError("delete", err)
 	}
 
 	return &pb.DeleteConfigurationResponse{
@@ -315,12 +912,34 @@ func (s *GRPCServer) DeleteConfiguration(ctx context.Context, req *pb.DeleteConf
 	}, nil
 }
 
+// presetStatusError maps configuration preset errors to gRPC status codes
+func presetStatusError(action string, err error) error {
+	switch {
+	case errors.Is(err, gogent.ErrPresetNotFound):
+		return status.Errorf(codes.NotFound, "Configuration not found")
+	case errors.Is(err, gogent.ErrPresetNameTaken):
+		return status.Errorf(codes.AlreadyExists, "%v", err)
+	default:
+		return status.Errorf(codes.Internal, "Failed to %s configuration: %v", action, err)
+	}
+}
+
 // =============================================================================
 // FUNCTION MANAGEMENT
 // =============================================================================
 
 func (s *GRPCServer) ListFunctions(ctx context.Context, req *pb.ListFunctionsRequest) (*pb.ListFunctionsResponse, error) {
-	functions, err := s.businessLogic.ListFunctions(ctx)
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, offset, err := resolvePage(req.Limit, req.Offset, req.Cursor, gogent.MaxPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	functions, total, err := s.businessLogic.ListFunctions(ctx, userID, limit, offset)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "Failed to list functions: %v", err)
 	}
@@ -332,14 +951,21 @@ func (s *GRPCServer) ListFunctions(ctx context.Context, req *pb.ListFunctionsReq
 	}
 
 	return &pb.ListFunctionsResponse{
-		Functions: protoFunctions,
+		Functions:  protoFunctions,
+		TotalCount: int32(total),
+		NextCursor: gogent.NextCursor(offset, len(functions), total),
 	}, nil
 }
 
 func (s *GRPCServer) GetFunction(ctx context.Context, req *pb.GetFunctionRequest) (*pb.GetFunctionResponse, error) {
-	function, err := s.businessLogic.GetFunction(req.Id)
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	function, err := s.businessLogic.GetFunction(ctx, userID, req.Id)
 	if err != nil {
-		return nil, status.Errorf(codes.NotFound, "Function not found: %v", err)
+		return nil, functionStatusError("get", err)
 	}
 
 	protoFunction := s.convertFunctionToProto(function)
@@ -349,12 +975,25 @@ func (s *GRPCServer) GetFunction(ctx context.Context, req *pb.GetFunctionRequest
 }
 
 func (s *GRPCServer) CreateFunction(ctx context.Context, req *pb.CreateFunctionRequest) (*pb.CreateFunctionResponse, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.Function == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "function is required")
+	}
+
 	function := s.convertProtoFunctionToInternal(req.Function)
+	if err := gogent.ValidateFunctionDefinition(function); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 
-	createdFunction, err := s.businessLogic.CreateFunction(function)
+	createdFunction, err := s.businessLogic.CreateFunction(ctx, userID, function)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Failed to create function: %v", err)
+		return nil, functionStatusError("create", err)
 	}
+	s.audit(ctx, &types.AuditEvent{Action: types.AuditFunctionCreated, TargetType: "function", TargetID: createdFunction.ID,
+		Summary: map[string]interface{}{"name": createdFunction.Name}})
 
 	protoFunction := s.convertFunctionToProto(createdFunction)
 	return &pb.CreateFunctionResponse{
@@ -363,12 +1002,25 @@ func (s *GRPCServer) CreateFunction(ctx context.Context, req *pb.CreateFunctionR
 }
 
 func (s *GRPCServer) UpdateFunction(ctx context.Context, req *pb.UpdateFunctionRequest) (*pb.UpdateFunctionResponse, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.Function == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "function is required")
+	}
+
 	function := s.convertProtoFunctionToInternal(req.Function)
+	if err := gogent.ValidateFunctionDefinition(function); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 
-	updatedFunction, err := s.businessLogic.UpdateFunction(req.Id, function)
+	updatedFunction, err := s.businessLogic.UpdateFunction(ctx, userID, req.Id, function)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Failed to update function: %v", err)
+		return nil, functionStatusError("update", err)
 	}
+	s.audit(ctx, &types.AuditEvent{Action: types.AuditFunctionUpdated, TargetType: "function", TargetID: req.Id,
+		Summary: map[string]interface{}{"name": updatedFunction.Name}})
 
 	protoFunction := s.convertFunctionToProto(updatedFunction)
 	return &pb.UpdateFunctionResponse{
@@ -377,29 +1029,61 @@ func (s *GRPCServer) UpdateFunction(ctx context.Context, req *pb.UpdateFunctionR
 }
 
 func (s *GRPCServer) DeleteFunction(ctx context.Context, req *pb.DeleteFunctionRequest) (*pb.DeleteFunctionResponse, error) {
-	err := s.businessLogic.DeleteFunction(req.Id)
+	userID, err := s.getUserID(ctx)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Failed to delete function: %v", err)
+		return nil, err
 	}
 
+	if err := s.businessLogic.DeleteFunction(ctx, userID, req.Id); err != nil {
+		return nil, functionStatusError("delete", err)
+	}
+	s.audit(ctx, &types.AuditEvent{Action: types.AuditFunctionDeleted, TargetType: "function", TargetID: req.Id})
+
 	return &pb.DeleteFunctionResponse{
 		Message: fmt.Sprintf("Function %s deleted successfully", req.Id),
 	}, nil
 }
 
+// functionStatusError maps function definition errors to gRPC status codes
+func functionStatusError(action string, err error) error {
+	switch {
+	case errors.Is(err, gogent.ErrFunctionNotFound):
+		return status.Errorf(codes.NotFound, "Function not found")
+	case errors.Is(err, gogent.ErrFunctionNameTaken):
+		return status.Errorf(codes.AlreadyExists, "%v", err)
+	default:
+		return status.Errorf(codes.Internal, "Failed to %s function: %v", action, err)
+	}
+}
+
 func (s *GRPCServer) TestFunction(ctx context.Context, req *pb.TestFunctionRequest) (*pb.TestFunctionResponse, error) {
-	success, usedMockData, executionTimeMs, responseData, errorMessage, err := s.businessLogic.TestFunction(req.FunctionId, req.UseMockData)
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var args map[string]interface{}
+	if req.Arguments != nil {
+		args = req.Arguments.AsMap()
+	}
+
+	timeout := time.Duration(req.TimeoutMs) * time.Millisecond
+	result, err := s.businessLogic.TestFunction(ctx, userID, req.FunctionId, args, req.UseMockData, timeout)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Failed to test function: %v", err)
+		if errors.Is(err, gogent.ErrInvalidFunctionArguments) {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		return nil, functionStatusError("test", err)
 	}
 
-	response, _ := structpb.NewStruct(responseData)
+	response, _ := structpb.NewStruct(result.Response)
 	return &pb.TestFunctionResponse{
-		Success:         success,
-		UsedMockData:    usedMockData,
-		ExecutionTimeMs: executionTimeMs,
+		Success:         result.Success,
+		UsedMockData:    result.UsedMockData,
+		ExecutionTimeMs: result.ExecutionTimeMs,
 		Response:        response,
-		ErrorMessage:    errorMessage,
+		ErrorMessage:    result.Error,
+		FunctionCallId:  result.FunctionCallID,
 	}, nil
 }
 
@@ -408,16 +1092,31 @@ func (s *GRPCServer) TestFunction(ctx context.Context, req *pb.TestFunctionReque
 // =============================================================================
 
 func (s *GRPCServer) GetDatabaseStats(ctx context.Context, req *pb.GetDatabaseStatsRequest) (*pb.GetDatabaseStatsResponse, error) {
-	totalExecutionRuns, totalApiRequests, totalApiResponses, totalFunctionCalls, avgResponseTime, successRate := s.businessLogic.GetDatabaseStats()
-
-	return &pb.GetDatabaseStatsResponse{
-		TotalExecutionRuns: totalExecutionRuns,
-		TotalApiRequests:   totalApiRequests,
-		TotalApiResponses:  totalApiResponses,
-		TotalFunctionCalls: totalFunctionCalls,
-		AvgResponseTime:    avgResponseTime,
-		SuccessRate:        successRate,
-	}, nil
+	userID, err := s.resolveUserScope(ctx, req.AllUsers)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.businessLogic.GetDatabaseStats(ctx, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to get database stats: %v", err)
+	}
+
+	freshness, _ := stats["freshness"].(map[string]interface{})
+	response := &pb.GetDatabaseStatsResponse{
+		TotalExecutionRuns: stats["totalExecutionRuns"].(int32),
+		TotalApiRequests:   stats["totalApiRequests"].(int32),
+		TotalApiResponses:  stats["totalApiResponses"].(int32),
+		TotalFunctionCalls: stats["totalFunctionCalls"].(int32),
+		AvgResponseTime:    stats["avgResponseTime"].(float64),
+		SuccessRate:        stats["successRate"].(float64),
+		TotalTokens:        stats["totalTokens"].(int64),
+	}
+	response.StatsSource, _ = freshness["source"].(string)
+	if refreshedAt, ok := freshness["refreshedAt"].(time.Time); ok {
+		response.RefreshedAt = timestamppb.New(refreshedAt)
+	}
+	return response, nil
 }
 
 func (s *GRPCServer) ListDatabaseTables(ctx context.Context, req *pb.ListDatabaseTablesRequest) (*pb.ListDatabaseTablesResponse, error) {
@@ -429,8 +1128,29 @@ func (s *GRPCServer) ListDatabaseTables(ctx context.Context, req *pb.ListDatabas
 }
 
 func (s *GRPCServer) GetTableData(ctx context.Context, req *pb.GetTableDataRequest) (*pb.GetTableDataResponse, error) {
-	columns, rows, totalRows, err := s.businessLogic.GetTableData(req.TableName)
+	userID, err := s.resolveUserScope(ctx, req.AllUsers)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, offset, err := resolvePage(req.Limit, req.Offset, req.Cursor, 100)
+	if err != nil {
+		return nil, err
+	}
+
+	filters, err := gogent.ParseTableFilters(req.Filters)
 	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	query := gogent.TableQuery{Columns: req.Columns, Filters: filters, Sort: req.Sort, Limit: limit, Offset: offset}
+	columns, rows, totalRows, err := s.businessLogic.GetTableData(ctx, userID, req.TableName, query)
+	switch {
+	case errors.Is(err, gogent.ErrUnknownTable):
+		return nil, status.Errorf(codes.NotFound, "Unknown table: %s", req.TableName)
+	case errors.Is(err, gogent.ErrInvalidTableQuery):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
 		return nil, status.Errorf(codes.Internal, "Failed to get table data: %v", err)
 	}
 
@@ -444,10 +1164,11 @@ func (s *GRPCServer) GetTableData(ctx context.Context, req *pb.GetTableDataReque
 	}
 
 	return &pb.GetTableDataResponse{
-		TableName: req.TableName,
-		Columns:   columns,
-		Rows:      protoRows,
-		TotalRows: totalRows,
+		TableName:  req.TableName,
+		Columns:    columns,
+		Rows:       protoRows,
+		TotalRows:  int32(totalRows),
+		NextCursor: gogent.NextCursor(offset, len(rows), totalRows),
 	}, nil
 }
 
@@ -456,15 +1177,33 @@ func (s *GRPCServer) GetTableData(ctx context.Context, req *pb.GetTableDataReque
 // =============================================================================
 
 func (s *GRPCServer) Health(ctx context.Context, req *pb.HealthRequest) (*pb.HealthResponse, error) {
-	status, version, database, geminiAPI := s.businessLogic.GetHealthStatus()
-
-	return &pb.HealthResponse{
-		Status:    status,
-		Version:   version,
-		Timestamp: timestamppb.Now(),
-		Database:  database,
-		GeminiApi: geminiAPI,
-	}, nil
+	report := s.businessLogic.GetHealthStatus(ctx)
+
+	response := &pb.HealthResponse{
+		Status:    report.Status,
+		Version:   report.Version,
+		Timestamp: timestamppb.New(report.Timestamp),
+		GeminiApi: false, // Session-based API keys, not stored in config
+	}
+	for _, check := range report.Checks {
+		if check.Name == "database" {
+			response.Database = check.Status != types.HealthUnhealthy
+		}
+		protoCheck := &pb.HealthCheck{
+			Name:      check.Name,
+			Status:    check.Status,
+			LatencyMs: check.LatencyMs,
+			Message:   check.Message,
+			CheckedAt: timestamppb.New(check.CheckedAt),
+		}
+		if len(check.Details) > 0 {
+			if details, err := structpb.NewStruct(check.Details); err == nil {
+				protoCheck.Details = details
+			}
+		}
+		response.Checks = append(response.Checks, protoCheck)
+	}
+	return response, nil
 }
 
 // =============================================================================
@@ -477,6 +1216,7 @@ func (s *GRPCServer) convertUserToProto(user *auth.User) *pb.User {
 		Username:      user.Username,
 		EmailVerified: user.EmailVerified,
 		IsTemporary:   user.IsTemporary,
+		Role:          user.Role,
 		CreatedAt:     timestamppb.New(user.CreatedAt),
 		UpdatedAt:     timestamppb.New(user.UpdatedAt),
 	}
@@ -493,16 +1233,26 @@ func (s *GRPCServer) convertUserToProto(user *auth.User) *pb.User {
 }
 
 func (s *GRPCServer) convertExecutionRunToProto(run *types.ExecutionRun) *pb.ExecutionRun {
-	return &pb.ExecutionRun{
+	protoRun := &pb.ExecutionRun{
 		Id:                    run.ID,
-		UserId:                "current-user-1", // TODO: Get from actual data
+		UserId:                run.UserID,
 		Name:                  run.Name,
 		Description:           run.Description,
 		EnableFunctionCalling: run.EnableFunctionCalling,
 		Status:                run.Status,
 		CreatedAt:             timestamppb.New(run.CreatedAt),
 		UpdatedAt:             timestamppb.New(run.UpdatedAt),
+
+		Deterministic:          run.Deterministic,
+		DeterminismFingerprint: run.DeterminismFingerprint,
+		Tags:                   run.Tags,
+	}
+	if run.RunSpec != nil {
+		if encoded, err := types.ToJSON(run.RunSpec); err == nil {
+			protoRun.RunSpec = encoded
+		}
 	}
+	return protoRun
 }
 
 func (s *GRPCServer) convertConfigurationToProto(config *types.APIConfiguration) *pb.APIConfiguration {
@@ -511,9 +1261,30 @@ func (s *GRPCServer) convertConfigurationToProto(config *types.APIConfiguration)
 		VariationName: config.VariationName,
 		ModelName:     config.ModelName,
 		SystemPrompt:  config.SystemPrompt,
+		ToolNames:     config.ToolNames,
+		DisableTools:  config.DisableTools,
 		CreatedAt:     timestamppb.New(config.CreatedAt),
+
+		FunctionInstruction:        config.FunctionInstruction,
+		DisableFunctionInstruction: config.DisableFunctionInstruction,
+		SafetyPolicy:               convertSafetyPolicyToProto(config.SafetyPolicy),
+		ResponseMimeType:           config.ResponseMimeType,
+		InlineSystemPrompt:         config.InlineSystemPrompt,
+		PresetId:                   config.PresetID,
+		TimeoutMs:                  config.TimeoutMs,
+		Backend:                    config.Backend,
+		Provider:                   config.Provider,
+		Retrieval:                  convertRetrievalToProto(config.Retrieval),
+		Guardrails:                 convertGuardrailsToProto(config.Guardrails),
+		ToolChoiceMode:             config.ToolChoiceMode,
+		AllowedFunctionNames:       config.AllowedFunctionNames,
 	}
 
+	if len(config.ResponseSchema) > 0 {
+		if schema, err := structpb.NewStruct(config.ResponseSchema); err == nil {
+			protoConfig.ResponseSchema = schema
+		}
+	}
 	if config.Temperature != nil {
 		protoConfig.Temperature = *config.Temperature
 	}
@@ -526,6 +1297,10 @@ func (s *GRPCServer) convertConfigurationToProto(config *types.APIConfiguration)
 	if config.TopK != nil {
 		protoConfig.TopK = *config.TopK
 	}
+	protoConfig.ThinkingBudget = config.ThinkingBudget
+	if config.CandidateCount != nil {
+		protoConfig.CandidateCount = *config.CandidateCount
+	}
 
 	return protoConfig
 }
@@ -536,8 +1311,28 @@ func (s *GRPCServer) convertProtoConfigurationToInternal(pc *pb.APIConfiguration
 		VariationName: pc.VariationName,
 		ModelName:     pc.ModelName,
 		SystemPrompt:  pc.SystemPrompt,
+		ToolNames:     pc.ToolNames,
+		DisableTools:  pc.DisableTools,
+
+		FunctionInstruction:        pc.FunctionInstruction,
+		DisableFunctionInstruction: pc.DisableFunctionInstruction,
+		SafetyPolicy:               convertProtoSafetyPolicy(pc.SafetyPolicy),
+		ResponseMimeType:           pc.ResponseMimeType,
+		InlineSystemPrompt:         pc.InlineSystemPrompt,
+		PresetID:                   pc.PresetId,
+		TimeoutMs:                  pc.TimeoutMs,
+		Backend:                    pc.Backend,
+		Provider:                   pc.Provider,
+		Retrieval:                  convertProtoRetrieval(pc.Retrieval),
+		Guardrails:                 convertProtoGuardrails(pc.Guardrails),
+		ToolChoiceMode:             pc.ToolChoiceMode,
+		AllowedFunctionNames:       pc.AllowedFunctionNames,
+		ThinkingBudget:             pc.ThinkingBudget,
 	}
 
+	if pc.ResponseSchema != nil {
+		config.ResponseSchema = pc.ResponseSchema.AsMap()
+	}
 	if pc.Temperature > 0 {
 		config.Temperature = &pc.Temperature
 	}
@@ -550,26 +1345,51 @@ func (s *GRPCServer) convertProtoConfigurationToInternal(pc *pb.APIConfiguration
 	if pc.TopK > 0 {
 		config.TopK = &pc.TopK
 	}
+	if pc.CandidateCount > 0 {
+		config.CandidateCount = &pc.CandidateCount
+	}
 
 	return config
 }
 
+// convertPresetToProto returns a preset as a configuration whose ID is the preset's and whose variation name is the preset's name
+func (s *GRPCServer) convertPresetToProto(preset *types.ConfigurationPreset) *pb.APIConfiguration {
+	config := preset.Configuration
+	config.ID = preset.ID
+	config.VariationName = preset.Name
+	config.CreatedAt = preset.CreatedAt
+	return s.convertConfigurationToProto(&config)
+}
+
+// convertProtoConfigurationToPreset returns the preset a configuration describes, named after its variation name
+func (s *GRPCServer) convertProtoConfigurationToPreset(pc *pb.APIConfiguration) *types.ConfigurationPreset {
+	config := s.convertProtoConfigurationToInternal(pc)
+	return &types.ConfigurationPreset{
+		Name:          config.VariationName,
+		Configuration: *config,
+	}
+}
+
 func (s *GRPCServer) convertFunctionToProto(function *types.FunctionDefinition) *pb.FunctionDefinition {
 	// Create basic proto function
 	protoFunction := &pb.FunctionDefinition{
-		Id:          function.ID,
-		UserId:      "current-user-1", // TODO: Get from actual user context
-		Name:        function.Name,
-		DisplayName: function.DisplayName,
-		Description: function.Description,
-		EndpointUrl: function.EndpointURL,
-		HttpMethod:  function.HttpMethod,
-		IsActive:    function.IsActive,
-		CreatedAt:   timestamppb.New(function.CreatedAt),
-		UpdatedAt:   timestamppb.New(function.UpdatedAt),
-	}
-
-	// TODO: Convert ParametersSchema and MockResponse from map to structpb.Struct
+		Id:              function.ID,
+		UserId:          function.UserID,
+		Name:            function.Name,
+		DisplayName:     function.DisplayName,
+		Description:     function.Description,
+		EndpointUrl:     function.EndpointURL,
+		HttpMethod:      function.HttpMethod,
+		IsActive:        function.IsActive,
+		RequiredApiKeys: function.RequiredApiKeys,
+		CreatedAt:       timestamppb.New(function.CreatedAt),
+		UpdatedAt:       timestamppb.New(function.UpdatedAt),
+
+		TimeoutMs:        function.TimeoutMs,
+		MaxResponseBytes: function.MaxResponseBytes,
+		AllowedDomains:   function.AllowedDomains,
+	}
+
 	if len(function.ParametersSchema) > 0 {
 		if schema, err := structpb.NewStruct(function.ParametersSchema); err == nil {
 			protoFunction.ParametersSchema = schema
@@ -582,21 +1402,50 @@ func (s *GRPCServer) convertFunctionToProto(function *types.FunctionDefinition)
 		}
 	}
 
+	if len(function.Headers) > 0 {
+		if headers, err := structpb.NewStruct(function.Headers); err == nil {
+			protoFunction.Headers = headers
+		}
+	}
+
+	if len(function.AuthConfig) > 0 {
+		if authConfig, err := structpb.NewStruct(function.AuthConfig); err == nil {
+			protoFunction.AuthConfig = authConfig
+		}
+	}
+
+	if len(function.ApiKeyValidation) > 0 {
+		if validation, err := structpb.NewStruct(function.ApiKeyValidation); err == nil {
+			protoFunction.ApiKeyValidation = validation
+		}
+	}
+
 	return protoFunction
 }
 
 func (s *GRPCServer) convertProtoFunctionToInternal(pf *pb.FunctionDefinition) *types.FunctionDefinition {
 	function := &types.FunctionDefinition{
-		ID:          pf.Id,
-		Name:        pf.Name,
-		DisplayName: pf.DisplayName,
-		Description: pf.Description,
-		EndpointURL: pf.EndpointUrl,
-		HttpMethod:  pf.HttpMethod,
-		IsActive:    pf.IsActive,
+		ID:              pf.Id,
+		UserID:          pf.UserId,
+		Name:            pf.Name,
+		DisplayName:     pf.DisplayName,
+		Description:     pf.Description,
+		EndpointURL:     pf.EndpointUrl,
+		HttpMethod:      pf.HttpMethod,
+		IsActive:        pf.IsActive,
+		RequiredApiKeys: pf.RequiredApiKeys,
+
+		TimeoutMs:        pf.TimeoutMs,
+		MaxResponseBytes: pf.MaxResponseBytes,
+		AllowedDomains:   pf.AllowedDomains,
+	}
+	if pf.CreatedAt != nil {
+		function.CreatedAt = pf.CreatedAt.AsTime()
+	}
+	if pf.UpdatedAt != nil {
+		function.UpdatedAt = pf.UpdatedAt.AsTime()
 	}
 
-	// TODO: Convert structpb.Struct to map
 	if pf.ParametersSchema != nil {
 		function.ParametersSchema = pf.ParametersSchema.AsMap()
 	}
@@ -605,6 +1454,18 @@ func (s *GRPCServer) convertProtoFunctionToInternal(pf *pb.FunctionDefinition) *
 		function.MockResponse = pf.MockResponse.AsMap()
 	}
 
+	if pf.Headers != nil {
+		function.Headers = pf.Headers.AsMap()
+	}
+
+	if pf.AuthConfig != nil {
+		function.AuthConfig = pf.AuthConfig.AsMap()
+	}
+
+	if pf.ApiKeyValidation != nil {
+		function.ApiKeyValidation = pf.ApiKeyValidation.AsMap()
+	}
+
 	return function
 }
 
@@ -628,14 +1489,15 @@ func (s *GRPCServer) convertExecutionResultToProto(result *types.ExecutionResult
 		protoConfig := s.convertConfigurationToProto(&vr.Configuration)
 
 		protoRequest := &pb.APIRequest{
-			Id:              vr.Request.ID,
-			ExecutionRunId:  vr.Request.ExecutionRunID,
-			ConfigurationId: vr.Request.ConfigurationID,
-			RequestType:     string(vr.Request.RequestType),
-			Prompt:          vr.Request.Prompt,
-			Context:         vr.Request.Context,
-			FunctionName:    vr.Request.FunctionName,
-			CreatedAt:       timestamppb.New(vr.Request.CreatedAt),
+			Id:               vr.Request.ID,
+			ExecutionRunId:   vr.Request.ExecutionRunID,
+			ConfigurationId:  vr.Request.ConfigurationID,
+			RequestType:      string(vr.Request.RequestType),
+			Prompt:           vr.Request.Prompt,
+			Context:          vr.Request.Context,
+			FunctionName:     vr.Request.FunctionName,
+			SystemPromptMode: vr.Request.SystemPromptMode,
+			CreatedAt:        timestamppb.New(vr.Request.CreatedAt),
 		}
 
 		// Convert usage metadata
@@ -651,6 +1513,9 @@ func (s *GRPCServer) convertExecutionResultToProto(result *types.ExecutionResult
 			ResponseTimeMs: vr.Response.ResponseTimeMs,
 			UsageMetadata:  usageStruct,
 			CreatedAt:      timestamppb.New(vr.Response.CreatedAt),
+			Latency:        convertLatencyToProto(vr.Response.Latency),
+			ThoughtSummary: vr.Response.ThoughtSummary,
+			CandidateIndex: int32(vr.Response.CandidateIndex),
 		}
 
 		protoResult := &pb.VariationResult{
@@ -658,6 +1523,11 @@ func (s *GRPCServer) convertExecutionResultToProto(result *types.ExecutionResult
 			Request:       protoRequest,
 			Response:      protoResponse,
 			ExecutionTime: vr.ExecutionTime,
+			Repetition:    int32(vr.Repetition),
+			FunctionCalls: convertFunctionCallsToProto(vr.FunctionCalls),
+
+			RetrievedChunks: convertRetrievedChunksToProto(vr.RetrievedChunks),
+			GuardVerdicts:   convertGuardVerdictsToProto(vr.GuardVerdicts),
 		}
 		protoResults = append(protoResults, protoResult)
 	}
@@ -665,15 +1535,7 @@ func (s *GRPCServer) convertExecutionResultToProto(result *types.ExecutionResult
 	// Convert comparison result
 	var protoComparison *pb.ComparisonResult
 	if result.Comparison != nil {
-		protoComparison = &pb.ComparisonResult{
-			Id:                  result.Comparison.ID,
-			ExecutionRunId:      result.Comparison.ExecutionRunID,
-			ComparisonType:      result.Comparison.ComparisonType,
-			MetricName:          result.Comparison.MetricName,
-			BestConfigurationId: result.Comparison.BestConfigurationID,
-			AnalysisNotes:       result.Comparison.AnalysisNotes,
-			CreatedAt:           timestamppb.New(result.Comparison.CreatedAt),
-		}
+		protoComparison = convertComparisonToProto(result.Comparison)
 	}
 
 	return &pb.ExecutionResult{
@@ -683,9 +1545,42 @@ func (s *GRPCServer) convertExecutionResultToProto(result *types.ExecutionResult
 		TotalTime:    result.TotalTime,
 		SuccessCount: int32(result.SuccessCount),
 		ErrorCount:   int32(result.ErrorCount),
+		Accuracy:     convertAccuracyToProto(result.Accuracy),
+		SweepReport:  convertSweepReportToProto(result.SweepReport),
 	}, nil
 }
 
+// convertComparisonToProto converts a comparison and its significance tests
+func convertComparisonToProto(comparison *types.ComparisonResult) *pb.ComparisonResult {
+	protoComparison := &pb.ComparisonResult{
+		Id:                  comparison.ID,
+		ExecutionRunId:      comparison.ExecutionRunID,
+		ComparisonType:      comparison.ComparisonType,
+		MetricName:          comparison.MetricName,
+		BestConfigurationId: comparison.BestConfigurationID,
+		AnalysisNotes:       comparison.AnalysisNotes,
+		CreatedAt:           timestamppb.New(comparison.CreatedAt),
+	}
+	if len(comparison.ConfigurationScores) > 0 {
+		if scores, err := structpb.NewStruct(comparison.ConfigurationScores); err == nil {
+			protoComparison.ConfigurationScores = scores
+		}
+	}
+	for _, test := range comparison.SignificanceTests {
+		protoComparison.SignificanceTests = append(protoComparison.SignificanceTests, &pb.SignificanceTest{
+			Metric:           test.Metric,
+			ConfigurationA:   test.ConfigurationA,
+			ConfigurationB:   test.ConfigurationB,
+			MeanDifference:   test.MeanDifference,
+			TStatistic:       test.TStatistic,
+			DegreesOfFreedom: test.DegreesOfFreedom,
+			PValue:           test.PValue,
+			Significant:      test.Significant,
+		})
+	}
+	return protoComparison
+}
+
 // Helper function to convert proto execute request to internal type
 func (s *GRPCServer) convertProtoExecuteRequestToInternal(req *pb.ExecuteRequest) (*types.MultiExecutionRequest, error) {
 	if req == nil {
@@ -705,9 +1600,13 @@ func (s *GRPCServer) convertProtoExecuteRequestToInternal(req *pb.ExecuteRequest
 		}
 
 		tools[i] = types.Tool{
-			Name:        protoTool.Name,
-			Description: protoTool.Description,
-			Parameters:  parameters,
+			Name:            protoTool.Name,
+			Description:     protoTool.Description,
+			Parameters:      parameters,
+			UseMockResponse: protoTool.UseMockResponse,
+		}
+		if protoTool.MockResponse != nil {
+			tools[i].MockResponse = protoTool.MockResponse.AsMap()
 		}
 	}
 
@@ -719,6 +1618,21 @@ func (s *GRPCServer) convertProtoExecuteRequestToInternal(req *pb.ExecuteRequest
 			Metrics:     req.ComparisonConfig.Metrics,
 			CustomRules: req.ComparisonConfig.CustomRules,
 		}
+		if ta := req.ComparisonConfig.ToolAppropriateness; ta != nil {
+			comparisonConfig.ToolAppropriateness = &types.ToolAppropriatenessConfig{
+				ExpectToolUse: ta.ExpectToolUse,
+				ToolKeywords:  ta.ToolKeywords,
+			}
+		}
+		if judge := req.ComparisonConfig.Judge; judge != nil {
+			comparisonConfig.Judge = &types.JudgeConfig{
+				Model:        judge.Model,
+				Criteria:     judge.Criteria,
+				MaxCostUSD:   judge.MaxCostUsd,
+				Weight:       judge.Weight,
+				DisableCache: judge.DisableCache,
+			}
+		}
 	}
 
 	return &types.MultiExecutionRequest{
@@ -730,9 +1644,308 @@ func (s *GRPCServer) convertProtoExecuteRequestToInternal(req *pb.ExecuteRequest
 		Configurations:        configs,
 		FunctionTools:         tools,
 		ComparisonConfig:      comparisonConfig,
+
+		FunctionInstruction:        req.FunctionInstruction,
+		DisableFunctionInstruction: req.DisableFunctionInstruction,
+		SafetyPolicy:               convertProtoSafetyPolicy(req.SafetyPolicy),
+		Deterministic:              req.Deterministic,
+		Seed:                       req.Seed,
+
+		NameTemplate:    req.NameTemplate,
+		Suite:           req.Suite,
+		GitSHA:          req.GitSha,
+		DuplicatePolicy: req.DuplicatePolicy,
+		Repetitions:     int(req.Repetitions),
+		ExpectedAnswer:  convertProtoExpectedAnswer(req.ExpectedAnswer),
+		Sweep:           convertProtoParameterSweep(req.Sweep),
+		Priority:        req.Priority,
+		Tags:            req.Tags,
 	}, nil
 }
 
+// convertProtoParameterSweep converts a protobuf parameter sweep
+func convertProtoParameterSweep(sweep *pb.ParameterSweep) *types.ParameterSweep {
+	if sweep == nil {
+		return nil
+	}
+	return &types.ParameterSweep{
+		Mode:        sweep.Mode,
+		Samples:     int(sweep.Samples),
+		Seed:        sweep.Seed,
+		Temperature: convertProtoSweepRange(sweep.Temperature),
+		TopP:        convertProtoSweepRange(sweep.TopP),
+		TopK:        convertProtoSweepRange(sweep.TopK),
+	}
+}
+
+func convertProtoSweepRange(values *pb.SweepRange) *types.SweepRange {
+	if values == nil {
+		return nil
+	}
+	return &types.SweepRange{Min: values.Min, Max: values.Max, Step: values.Step, Values: values.Values}
+}
+
+// convertParameterSweepToProto converts a parameter sweep to protobuf
+func convertParameterSweepToProto(sweep *types.ParameterSweep) *pb.ParameterSweep {
+	if sweep == nil {
+		return nil
+	}
+	return &pb.ParameterSweep{
+		Mode:        sweep.Mode,
+		Samples:     int32(sweep.Samples),
+		Seed:        sweep.Seed,
+		Temperature: convertSweepRangeToProto(sweep.Temperature),
+		TopP:        convertSweepRangeToProto(sweep.TopP),
+		TopK:        convertSweepRangeToProto(sweep.TopK),
+	}
+}
+
+func convertSweepRangeToProto(values *types.SweepRange) *pb.SweepRange {
+	if values == nil {
+		return nil
+	}
+	return &pb.SweepRange{Min: values.Min, Max: values.Max, Step: values.Step, Values: values.Values}
+}
+
+// convertSweepReportToProto converts a sweep report to protobuf
+func convertSweepReportToProto(report *types.SweepReport) *pb.SweepReport {
+	if report == nil {
+		return nil
+	}
+	protoReport := &pb.SweepReport{Metric: report.Metric}
+	for _, point := range report.Points {
+		protoReport.Points = append(protoReport.Points, &pb.SweepPoint{
+			VariationName: point.VariationName,
+			Temperature:   point.Temperature,
+			TopP:          point.TopP,
+			TopK:          point.TopK,
+			Score:         point.Score,
+		})
+	}
+	for _, parameter := range report.Parameters {
+		protoParameter := &pb.ParameterSummary{
+			Parameter: parameter.Parameter,
+			BestValue: parameter.BestValue,
+			RegionMin: parameter.RegionMin,
+			RegionMax: parameter.RegionMax,
+		}
+		for _, value := range parameter.Values {
+			protoParameter.Values = append(protoParameter.Values, &pb.SweepValueScore{
+				Value:     value.Value,
+				MeanScore: value.MeanScore,
+				Points:    int32(value.Points),
+			})
+		}
+		protoReport.Parameters = append(protoReport.Parameters, protoParameter)
+	}
+	return protoReport
+}
+
+// convertProtoSweepReport converts a protobuf sweep report
+func convertProtoSweepReport(report *pb.SweepReport) *types.SweepReport {
+	if report == nil {
+		return nil
+	}
+	converted := &types.SweepReport{Metric: report.Metric}
+	for _, point := range report.Points {
+		converted.Points = append(converted.Points, types.SweepPoint{
+			VariationName: point.VariationName,
+			Temperature:   point.Temperature,
+			TopP:          point.TopP,
+			TopK:          point.TopK,
+			Score:         point.Score,
+		})
+	}
+	for _, parameter := range report.Parameters {
+		summary := types.ParameterSummary{
+			Parameter: parameter.Parameter,
+			BestValue: parameter.BestValue,
+			RegionMin: parameter.RegionMin,
+			RegionMax: parameter.RegionMax,
+		}
+		for _, value := range parameter.Values {
+			summary.Values = append(summary.Values, types.SweepValueScore{
+				Value:     value.Value,
+				MeanScore: value.MeanScore,
+				Points:    int(value.Points),
+			})
+		}
+		converted.Parameters = append(converted.Parameters, summary)
+	}
+	return converted
+}
+
+// convertProtoExpectedAnswer converts a protobuf expected answer, treating an empty one as unset
+func convertProtoExpectedAnswer(expected *pb.ExpectedAnswer) *types.ExpectedAnswer {
+	if expected == nil || expected.Answer == "" {
+		return nil
+	}
+	return &types.ExpectedAnswer{
+		Answer:    expected.Answer,
+		MatchMode: expected.MatchMode,
+		Threshold: expected.Threshold,
+	}
+}
+
+// convertAccuracyToProto converts golden-answer accuracy to protobuf
+func convertAccuracyToProto(accuracy []types.ConfigurationAccuracy) []*pb.ConfigurationAccuracy {
+	protoAccuracy := make([]*pb.ConfigurationAccuracy, 0, len(accuracy))
+	for _, a := range accuracy {
+		protoAccuracy = append(protoAccuracy, &pb.ConfigurationAccuracy{
+			VariationName:   a.VariationName,
+			ConfigurationId: a.ConfigurationID,
+			Cases:           int32(a.Cases),
+			Passed:          int32(a.Passed),
+			Accuracy:        a.Accuracy,
+		})
+	}
+	return protoAccuracy
+}
+
+// convertProtoSafetyPolicy converts a protobuf safety policy, treating an empty one as unset
+func convertProtoSafetyPolicy(policy *pb.SafetyPolicy) *types.SafetyPolicy {
+	if policy == nil || len(policy.Thresholds) == 0 {
+		return nil
+	}
+	return &types.SafetyPolicy{Thresholds: policy.Thresholds}
+}
+
+// convertSafetyPolicyToProto converts a safety policy to protobuf
+func convertSafetyPolicyToProto(policy *types.SafetyPolicy) *pb.SafetyPolicy {
+	if policy == nil {
+		return nil
+	}
+	return &pb.SafetyPolicy{Thresholds: policy.Thresholds}
+}
+
+// convertProtoRetrieval converts protobuf retrieval settings, treating ones without a collection as unset
+func convertProtoRetrieval(retrieval *pb.RetrievalConfig) *types.RetrievalConfig {
+	if retrieval == nil || retrieval.Collection == "" {
+		return nil
+	}
+	return &types.RetrievalConfig{
+		Collection:     retrieval.Collection,
+		TopK:           retrieval.TopK,
+		MinScore:       retrieval.MinScore,
+		EmbeddingModel: retrieval.EmbeddingModel,
+	}
+}
+
+// convertRetrievalToProto converts retrieval settings to protobuf
+func convertRetrievalToProto(retrieval *types.RetrievalConfig) *pb.RetrievalConfig {
+	if retrieval == nil {
+		return nil
+	}
+	return &pb.RetrievalConfig{
+		Collection:     retrieval.Collection,
+		TopK:           retrieval.TopK,
+		MinScore:       retrieval.MinScore,
+		EmbeddingModel: retrieval.EmbeddingModel,
+	}
+}
+
+// convertRetrievedChunksToProto converts the chunks retrieved for a variation to protobuf
+func convertRetrievedChunksToProto(chunks []types.RetrievedChunk) []*pb.RetrievedChunk {
+	protoChunks := make([]*pb.RetrievedChunk, 0, len(chunks))
+	for _, chunk := range chunks {
+		protoChunks = append(protoChunks, &pb.RetrievedChunk{
+			ChunkId:      chunk.ChunkID,
+			DocumentId:   chunk.DocumentID,
+			DocumentName: chunk.DocumentName,
+			Collection:   chunk.Collection,
+			ChunkIndex:   int32(chunk.ChunkIndex),
+			Content:      chunk.Content,
+			Score:        chunk.Score,
+			Rank:         int32(chunk.Rank),
+		})
+	}
+	return protoChunks
+}
+
+// convertProtoGuardrails converts protobuf guard settings
+func convertProtoGuardrails(guardrails []*pb.GuardConfig) []types.GuardConfig {
+	if len(guardrails) == 0 {
+		return nil
+	}
+	converted := make([]types.GuardConfig, 0, len(guardrails))
+	for _, guard := range guardrails {
+		converted = append(converted, types.GuardConfig{
+			Guard:  guard.Guard,
+			Stage:  guard.Stage,
+			Action: guard.Action,
+			Topics: guard.Topics,
+		})
+	}
+	return converted
+}
+
+// convertGuardrailsToProto converts guard settings to protobuf
+func convertGuardrailsToProto(guardrails []types.GuardConfig) []*pb.GuardConfig {
+	protoGuardrails := make([]*pb.GuardConfig, 0, len(guardrails))
+	for _, guard := range guardrails {
+		protoGuardrails = append(protoGuardrails, &pb.GuardConfig{
+			Guard:  guard.Guard,
+			Stage:  guard.Stage,
+			Action: guard.Action,
+			Topics: guard.Topics,
+		})
+	}
+	return protoGuardrails
+}
+
+// convertGuardVerdictsToProto converts the guard verdicts of a variation to protobuf
+func convertGuardVerdictsToProto(verdicts []types.GuardVerdict) []*pb.GuardVerdict {
+	protoVerdicts := make([]*pb.GuardVerdict, 0, len(verdicts))
+	for _, verdict := range verdicts {
+		protoVerdicts = append(protoVerdicts, &pb.GuardVerdict{
+			Guard:     verdict.Guard,
+			Stage:     verdict.Stage,
+			Action:    verdict.Action,
+			Triggered: verdict.Triggered,
+			Findings:  verdict.Findings,
+		})
+	}
+	return protoVerdicts
+}
+
+// convertFunctionCallsToProto converts the function calls a variation made to protobuf
+func convertFunctionCallsToProto(calls []types.FunctionCall) []*pb.FunctionCall {
+	protoCalls := make([]*pb.FunctionCall, 0, len(calls))
+	for _, call := range calls {
+		args, _ := structpb.NewStruct(call.FunctionArgs)
+		response, _ := structpb.NewStruct(call.FunctionResponse)
+		protoCalls = append(protoCalls, &pb.FunctionCall{
+			Id:                call.ID,
+			RequestId:         call.RequestID,
+			FunctionName:      call.FunctionName,
+			FunctionArguments: args,
+			FunctionResponse:  response,
+			ExecutionStatus:   call.ExecutionStatus,
+			ExecutionTimeMs:   call.ExecutionTimeMs,
+			ErrorDetails:      call.ErrorDetails,
+			CreatedAt:         timestamppb.New(call.CreatedAt),
+		})
+	}
+	return protoCalls
+}
+
+// convertLatencyToProto converts a response's latency breakdown to protobuf
+func convertLatencyToProto(latency *types.LatencyBreakdown) *pb.LatencyBreakdown {
+	if latency == nil {
+		return nil
+	}
+	return &pb.LatencyBreakdown{
+		QueuedMs:    latency.QueuedMs,
+		RetrievalMs: latency.RetrievalMs,
+		ModelMs:     latency.ModelMs,
+		FunctionMs:  latency.FunctionMs,
+		FollowUpMs:  latency.FollowUpMs,
+		DbMs:        latency.DBMs,
+		OtherMs:     latency.OtherMs,
+		TotalMs:     latency.TotalMs,
+	}
+}
+
 // =============================================================================
 // SERVER STARTUP
 // =============================================================================
@@ -755,19 +1968,37 @@ func runGRPCServer() {
 		log.Fatalf("Failed to listen on port %s: %v", port, err)
 	}
 
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(server.authUnaryInterceptor),
+		grpc.StreamInterceptor(server.authStreamInterceptor),
+	)
 	pb.RegisterGogentServiceServer(grpcServer, server)
 
 	fmt.Printf("🚀 GoGent gRPC Server starting on port %s\n", port)
 	fmt.Printf("📡 Health check: use gRPC client to call Health method\n")
 	fmt.Printf("🔧 Available gRPC methods:\n")
 	fmt.Printf("   - Authentication: Login, Register, CreateTemporaryUser, etc.\n")
-	fmt.Printf("   - Execution: Execute, GetExecutionStatus, ListExecutionRuns\n")
+	fmt.Printf("   - Execution: Execute, GetExecutionStatus, ListExecutionRuns, ListComparisons, GetComparison, ListExecutionLogs\n")
+	fmt.Printf("   - Batch: SubmitBatch (streaming), GetBatchRun\n")
 	fmt.Printf("   - Configuration: ListConfigurations, CreateConfiguration\n")
 	fmt.Printf("   - Functions: ListFunctions, CreateFunction, TestFunction\n")
 	fmt.Printf("   - Database: GetDatabaseStats, GetTableData\n")
 	fmt.Printf("   - Health: Health\n")
 	fmt.Println()
 
-	log.Fatal(grpcServer.Serve(lis))
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	// Finish in-flight calls, then let queued and running executions finish
+	waitForShutdownSignal()
+	grpcServer.GracefulStop()
+	ctx, cancel := context.WithTimeout(context.Background(), executionDrainTimeout)
+	defer cancel()
+	log.Printf("⏳ Draining execution queue")
+	if err := server.businessLogic.Shutdown(ctx); err != nil {
+		log.Printf("⚠️ Warning: executions still queued at shutdown were dropped: %v", err)
+	}
 }
@@ -6,12 +6,16 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"strconv"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"gogent/internal/auth"
 	"gogent/internal/gogent"
+	"gogent/internal/ollama"
 	"gogent/internal/types"
 
 	"github.com/joho/godotenv"
@@ -23,11 +27,14 @@ type BusinessLogic struct {
 	config         *types.GeminiClientConfig
 	executions     map[string]*ExecutionStatus
 	executionMutex sync.RWMutex
-	userID         string // Store current user ID for operations
+	queue          *gogent.ExecutionQueue
+	runCtx         context.Context // Canceled when shutdown gives up waiting for executions
+	cancelRuns     context.CancelFunc
+	authService    *auth.AuthService
 }
 
 // NewBusinessLogic creates a new business logic instance
-func NewBusinessLogic(userID string) (*BusinessLogic, error) {
+func NewBusinessLogic() (*BusinessLogic, error) {
 	// Load environment variables
 	if err := godotenv.Load(); err != nil {
 		log.Printf("⚠️ Warning: .env file not found: %v", err)
@@ -53,9 +60,30 @@ func NewBusinessLogic(userID string) (*BusinessLogic, error) {
 		Neo4jUsername:     os.Getenv("NEO4J_USERNAME"),
 		Neo4jPassword:     os.Getenv("NEO4J_PASSWORD"),
 		Neo4jDatabase:     os.Getenv("NEO4J_DATABASE"),
+		ProjectID:         os.Getenv("GOOGLE_CLOUD_PROJECT"),
+		Region:            os.Getenv("GOOGLE_CLOUD_LOCATION"),
+		OllamaURL:         envOrDefault("OLLAMA_URL", ollama.DefaultBaseURL),
+		WebSearchProvider: os.Getenv("WEB_SEARCH_PROVIDER"),
+		WebSearchAPIKey:   os.Getenv("WEB_SEARCH_API_KEY"),
+		SQLQueryDriver:    os.Getenv("QUERY_SQL_DRIVER"),
+		SQLQueryDSN:       os.Getenv("QUERY_SQL_DSN"),
 		MaxRetries:        3,
 		TimeoutSecs:       30,
 	}
+	redaction, err := loadRedactionPolicy()
+	if err != nil {
+		return nil, err
+	}
+	config.Redaction = redaction
+	minLogLevel, err := loadMinStoredLogLevel()
+	if err != nil {
+		return nil, err
+	}
+	config.MinStoredLogLevel = minLogLevel
+	config.AllowPrivateFunctionEndpoints = os.Getenv("ALLOW_PRIVATE_FUNCTION_ENDPOINTS") == "true"
+	if err := loadCircuitBreakerSettings(config); err != nil {
+		return nil, err
+	}
 
 	// Create gogent client
 	client, err := gogent.NewClient(dbURL, config)
@@ -63,14 +91,256 @@ func NewBusinessLogic(userID string) (*BusinessLogic, error) {
 		return nil, fmt.Errorf("failed to create gogent client: %w", err)
 	}
 
+	// Create auth service backed by the same database
+	authService := auth.NewAuthService(client.GetDB(), os.Getenv("JWT_SECRET"))
+	if err := configurePasswordReset(authService); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	queue, err := newExecutionQueueFromEnv()
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	runCtx, cancelRuns := context.WithCancel(context.Background())
 	return &BusinessLogic{
-		client:     client,
-		config:     config,
-		executions: make(map[string]*ExecutionStatus),
-		userID:     userID,
+		client:      client,
+		config:      config,
+		executions:  make(map[string]*ExecutionStatus),
+		queue:       queue,
+		runCtx:      runCtx,
+		cancelRuns:  cancelRuns,
+		authService: authService,
 	}, nil
 }
 
+// Execution queue sizes used when EXECUTION_WORKERS and EXECUTION_QUEUE_SIZE are unset
+const (
+	defaultExecutionWorkers   = 4
+	defaultExecutionQueueSize = 100
+)
+
+// executionDrainTimeout bounds how long shutdown waits for queued and running executions
+const executionDrainTimeout = 5 * time.Minute
+
+// executionInterruptGrace is how long interrupted executions get to record that they failed
+const executionInterruptGrace = 30 * time.Second
+
+// newExecutionQueueFromEnv starts the execution queue with EXECUTION_WORKERS workers and room for
+// EXECUTION_QUEUE_SIZE waiting executions
+func newExecutionQueueFromEnv() (*gogent.ExecutionQueue, error) {
+	workers, err := positiveEnvInt("EXECUTION_WORKERS", defaultExecutionWorkers)
+	if err != nil {
+		return nil, err
+	}
+	capacity, err := positiveEnvInt("EXECUTION_QUEUE_SIZE", defaultExecutionQueueSize)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("📥 Execution queue: %d workers, %d waiting executions", workers, capacity)
+	return gogent.NewExecutionQueue(workers, capacity), nil
+}
+
+// positiveEnvInt reads a positive integer from the environment, or fallback when it is unset
+func positiveEnvInt(key string, fallback int) (int, error) {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 1 {
+		return 0, fmt.Errorf("%s must be a positive integer, got %q", key, raw)
+	}
+	return value, nil
+}
+
+// waitForShutdownSignal blocks until the process is interrupted or terminated
+func waitForShutdownSignal() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	received := <-signals
+	log.Printf("🛑 Received %s, shutting down", received)
+}
+
+// drainExecutions waits for the queue's executions to finish. If ctx ends first, it cancels the
+// running executions and waits briefly for them to record their runs as failed.
+func drainExecutions(ctx context.Context, queue *gogent.ExecutionQueue, cancelRuns context.CancelFunc) error {
+	err := queue.Shutdown(ctx)
+	if err == nil {
+		return nil
+	}
+
+	log.Printf("⏹️ Interrupting executions still running")
+	cancelRuns()
+	graceCtx, cancel := context.WithTimeout(context.Background(), executionInterruptGrace)
+	defer cancel()
+	if graceErr := queue.Shutdown(graceCtx); graceErr != nil {
+		log.Printf("⚠️ Warning: executions did not stop within %s", executionInterruptGrace)
+	}
+	return err
+}
+
+// failUnfinishedExecutions marks pending and running executions failed; callers hold the executions lock
+func failUnfinishedExecutions(executions map[string]*ExecutionStatus, message string) {
+	now := time.Now()
+	for _, execution := range executions {
+		if execution.Status == "pending" || execution.Status == "running" {
+			execution.Status = "failed"
+			execution.ErrorMessage = message
+			execution.EndTime = &now
+		}
+	}
+}
+
+// loadMinStoredLogLevel reads EXECUTION_LOG_LEVEL, the least severe execution log level written to
+// the database; without one, every level is stored
+func loadMinStoredLogLevel() (types.LogLevel, error) {
+	raw := os.Getenv("EXECUTION_LOG_LEVEL")
+	if raw == "" {
+		return "", nil
+	}
+	level, err := types.ParseLogLevel(raw)
+	if err != nil {
+		return "", fmt.Errorf("EXECUTION_LOG_LEVEL: %w", err)
+	}
+	log.Printf("📝 Storing execution logs at %s and above", level)
+	return level, nil
+}
+
+// loadCircuitBreakerSettings reads the failures that open a host's circuit breaker and its cooldown
+// from CIRCUIT_BREAKER_THRESHOLD and CIRCUIT_BREAKER_COOLDOWN_SECS; unset ones use the defaults
+func loadCircuitBreakerSettings(config *types.GeminiClientConfig) error {
+	threshold, err := positiveEnvInt("CIRCUIT_BREAKER_THRESHOLD", 0)
+	if err != nil {
+		return err
+	}
+	cooldown, err := positiveEnvInt("CIRCUIT_BREAKER_COOLDOWN_SECS", 0)
+	if err != nil {
+		return err
+	}
+	config.CircuitBreakerThreshold, config.CircuitBreakerCooldownSecs = threshold, cooldown
+	return nil
+}
+
+// loadRedactionPolicy reads the YAML or JSON policy named by REDACTION_POLICY_FILE; without one,
+// clients use the default policy
+func loadRedactionPolicy() (*types.RedactionPolicy, error) {
+	path := os.Getenv("REDACTION_POLICY_FILE")
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read redaction policy: %w", err)
+	}
+	policy, err := gogent.ParseRedactionPolicy(data)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("🧹 Redaction policy %s loaded from %s", gogent.RedactionPolicyFingerprint(policy), path)
+	return policy, nil
+}
+
+// telemetrySettings is the opt-in usage reporting read by loadTelemetrySettings
+type telemetrySettings struct {
+	enabled  bool
+	endpoint string
+	epsilon  float64
+}
+
+// defaultTelemetryEpsilon is the privacy budget of each report's noise when TELEMETRY_EPSILON is unset
+const defaultTelemetryEpsilon = 1.0
+
+// loadTelemetrySettings reads the telemetry opt-in. Nothing is sent unless TELEMETRY_ENABLED is true,
+// and DO_NOT_TRACK=1 turns telemetry off regardless. Opting in needs an http(s) TELEMETRY_ENDPOINT.
+func loadTelemetrySettings() (telemetrySettings, error) {
+	settings := telemetrySettings{endpoint: os.Getenv("TELEMETRY_ENDPOINT"), epsilon: defaultTelemetryEpsilon}
+	if raw := os.Getenv("TELEMETRY_EPSILON"); raw != "" {
+		epsilon, err := strconv.ParseFloat(raw, 64)
+		if err != nil || epsilon < 0 {
+			return settings, fmt.Errorf("TELEMETRY_EPSILON must be a non-negative number, got %q", raw)
+		}
+		settings.epsilon = epsilon
+	}
+	settings.enabled = os.Getenv("TELEMETRY_ENABLED") == "true" && os.Getenv("DO_NOT_TRACK") != "1"
+	if settings.enabled && !strings.HasPrefix(settings.endpoint, "https://") && !strings.HasPrefix(settings.endpoint, "http://") {
+		return settings, fmt.Errorf("TELEMETRY_ENABLED needs TELEMETRY_ENDPOINT to be an http(s) URL, got %q", settings.endpoint)
+	}
+	return settings, nil
+}
+
+// newArchiveSink opens the archive sink at a location: s3://bucket/prefix, gs://bucket/prefix or a
+// local directory. S3 credentials come from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN
+// and AWS_REGION, with ARCHIVE_S3_ENDPOINT for S3-compatible stores; Cloud Storage uses GCS_ACCESS_TOKEN.
+func newArchiveSink(location string) (gogent.ArchiveSink, error) {
+	if bucket, ok := strings.CutPrefix(location, "s3://"); ok {
+		bucket, prefix := splitArchiveLocation(bucket)
+		if os.Getenv("AWS_ACCESS_KEY_ID") == "" || os.Getenv("AWS_SECRET_ACCESS_KEY") == "" {
+			return nil, fmt.Errorf("archiving to S3 needs AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+		}
+		return &gogent.S3ArchiveSink{
+			Endpoint:        os.Getenv("ARCHIVE_S3_ENDPOINT"),
+			Region:          envOrDefault("AWS_REGION", "us-east-1"),
+			Bucket:          bucket,
+			Prefix:          prefix,
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}, nil
+	}
+	if bucket, ok := strings.CutPrefix(location, "gs://"); ok {
+		bucket, prefix := splitArchiveLocation(bucket)
+		if os.Getenv("GCS_ACCESS_TOKEN") == "" {
+			return nil, fmt.Errorf("archiving to Cloud Storage needs GCS_ACCESS_TOKEN")
+		}
+		return &gogent.GCSArchiveSink{Bucket: bucket, Prefix: prefix, AccessToken: os.Getenv("GCS_ACCESS_TOKEN")}, nil
+	}
+	if location == "" {
+		return nil, fmt.Errorf("archive location is empty")
+	}
+	return &gogent.LocalArchiveSink{Dir: location}, nil
+}
+
+// splitArchiveLocation splits "bucket/some/prefix" into the bucket and a key prefix ending in a slash
+func splitArchiveLocation(location string) (string, string) {
+	bucket, prefix, _ := strings.Cut(location, "/")
+	if prefix = strings.Trim(prefix, "/"); prefix != "" {
+		prefix += "/"
+	}
+	return bucket, prefix
+}
+
+// configurePasswordReset sets up reset email delivery from EMAIL_PROVIDER/SMTP_* and PASSWORD_RESET_URL
+func configurePasswordReset(authService *auth.AuthService) error {
+	sender, err := auth.NewEmailSenderFromEnv()
+	if err != nil {
+		return fmt.Errorf("failed to configure email sender: %w", err)
+	}
+
+	authService.SetEmailSender(sender)
+	authService.SetPasswordResetURL(os.Getenv("PASSWORD_RESET_URL"))
+	return nil
+}
+
+// Shutdown stops accepting executions and waits for queued and running ones to finish. Executions
+// still unfinished when ctx ends are interrupted and marked failed.
+func (bl *BusinessLogic) Shutdown(ctx context.Context) error {
+	err := drainExecutions(ctx, bl.queue, bl.cancelRuns)
+
+	bl.executionMutex.Lock()
+	failUnfinishedExecutions(bl.executions, "server shut down before the execution finished")
+	bl.executionMutex.Unlock()
+	return err
+}
+
+// QueuePosition returns an execution's position in the queue, or 0 once it has started
+func (bl *BusinessLogic) QueuePosition(executionID string) int {
+	position, _ := bl.queue.Position(executionID)
+	return position
+}
+
 // Close closes the business logic resources
 func (bl *BusinessLogic) Close() error {
 	if bl.client != nil {
@@ -88,147 +358,184 @@ func (bl *BusinessLogic) GetDB() *sql.DB {
 // AUTHENTICATION & USER MANAGEMENT
 // =============================================================================
 
-func (bl *BusinessLogic) LoginUser(username, password string) (*auth.User, string, time.Time, error) {
-	// TODO: Implement actual authentication logic
+func (bl *BusinessLogic) LoginUser(username, password string) (*auth.User, string, string, time.Time, error) {
 	log.Printf("🔐 Login attempt for user: %s", username)
 
-	now := time.Now()
-	loginTime := now // Create separate variable for address
-	user := &auth.User{
-		ID:            "user-1",
-		Username:      username,
-		Email:         &[]string{username + "@example.com"}[0],
-		EmailVerified: true,
-		IsTemporary:   false,
-		CreatedAt:     now,
-		UpdatedAt:     now,
-		LastLoginAt:   &loginTime,
+	user, token, refreshToken, err := bl.authService.Login(username, password)
+	if err != nil {
+		return nil, "", "", time.Time{}, err
 	}
 
-	token := "mock-jwt-token"
-	expiresAt := time.Now().Add(24 * time.Hour)
-
-	return user, token, expiresAt, nil
+	expiresAt := time.Now().Add(bl.authService.TokenExpiry())
+	return user, token, refreshToken, expiresAt, nil
 }
 
-func (bl *BusinessLogic) RegisterUser(username, email, password string) (*auth.User, string, error) {
-	// TODO: Implement actual registration logic
+func (bl *BusinessLogic) RegisterUser(username, email, password string) (*auth.User, string, string, error) {
 	log.Printf("📝 Registration attempt for user: %s", username)
 
-	now := time.Now()
-	user := &auth.User{
-		ID:            fmt.Sprintf("user-%d", now.Unix()),
-		Username:      username,
-		Email:         &email,
-		EmailVerified: false,
-		IsTemporary:   false,
-		CreatedAt:     now,
-		UpdatedAt:     now,
-	}
-
-	token := "mock-jwt-token"
-	return user, token, nil
+	return bl.authService.Register(username, email, password)
 }
 
-func (bl *BusinessLogic) CreateTemporaryUser(sessionID string) (*auth.User, string, string, error) {
+func (bl *BusinessLogic) CreateTemporaryUser(sessionID string) (*auth.User, string, string, string, error) {
 	log.Printf("👤 Creating temporary user with session ID: %s", sessionID)
 
-	now := time.Now()
-	tempID := fmt.Sprintf("temp-%d", now.Unix())
-	user := &auth.User{
-		ID:          tempID,
-		Username:    "temp-user-" + tempID,
-		Email:       nil,
-		IsTemporary: true,
-		CreatedAt:   now,
-		UpdatedAt:   now,
-	}
-
-	tempPassword := "temp-password-123"
-	token := "temp-jwt-token"
-
-	return user, tempPassword, token, nil
+	return bl.authService.CreateTemporaryUser(sessionID)
 }
 
-func (bl *BusinessLogic) SaveTemporaryAccount(email string) (*auth.User, bool, error) {
+func (bl *BusinessLogic) SaveTemporaryAccount(ctx context.Context, email, currentPassword string) (*auth.User, bool, error) {
 	log.Printf("💾 Saving temporary account with email: %s", email)
 
-	now := time.Now()
-	user := &auth.User{
-		ID:            "saved-user-1",
-		Username:      strings.Split(email, "@")[0],
-		Email:         &email,
-		EmailVerified: false,
-		IsTemporary:   false,
-		CreatedAt:     now,
-		UpdatedAt:     now,
+	currentUser, ok := auth.GetUserFromContext(ctx)
+	if !ok {
+		return nil, false, fmt.Errorf("authentication required")
 	}
 
-	emailSent := true
+	user, err := bl.authService.SaveTemporaryAccount(currentUser.ID, email, currentPassword)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Verification emails are not sent yet
+	emailSent := false
 	return user, emailSent, nil
 }
 
 func (bl *BusinessLogic) VerifyEmail(token string) (*auth.User, bool, error) {
-	log.Printf("✅ Verifying email with token: %s", token)
+	log.Printf("✅ Verifying email")
 
-	email := "user@example.com"
-	now := time.Now()
-	user := &auth.User{
-		ID:            "verified-user-1",
-		Username:      "verified-user",
-		Email:         &email,
-		EmailVerified: true,
-		IsTemporary:   false,
-		UpdatedAt:     now,
+	user, err := bl.authService.VerifyEmail(token)
+	if err != nil {
+		return nil, false, err
 	}
 
-	verified := true
-	return user, verified, nil
+	return user, user.EmailVerified, nil
 }
 
-func (bl *BusinessLogic) GetCurrentUser() (*auth.User, error) {
+func (bl *BusinessLogic) GetCurrentUser(ctx context.Context) (*auth.User, error) {
 	log.Printf("👤 Getting current user")
 
-	// TODO: Extract user from JWT token in context
-	email := "current@example.com"
-	now := time.Now()
-	lastLogin := now // Create a separate variable for the address
-	user := &auth.User{
-		ID:            "current-user-1",
-		Username:      "current-user",
-		Email:         &email,
-		EmailVerified: true,
-		IsTemporary:   false,
-		CreatedAt:     now,
-		UpdatedAt:     now,
-		LastLoginAt:   &lastLogin,
+	user, ok := auth.GetUserFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("authentication required")
 	}
 
 	return user, nil
 }
 
+func (bl *BusinessLogic) RefreshToken(refreshToken string) (*auth.User, string, string, time.Time, error) {
+	log.Printf("🔄 Refreshing access token")
+
+	user, token, newRefreshToken, err := bl.authService.RefreshSession(refreshToken)
+	if err != nil {
+		return nil, "", "", time.Time{}, err
+	}
+
+	expiresAt := time.Now().Add(bl.authService.TokenExpiry())
+	return user, token, newRefreshToken, expiresAt, nil
+}
+
+func (bl *BusinessLogic) Logout(accessToken string, allSessions bool) error {
+	log.Printf("👋 Logging out (all sessions: %v)", allSessions)
+
+	return bl.authService.Logout(accessToken, allSessions)
+}
+
+func (bl *BusinessLogic) RequestPasswordReset(email string) error {
+	log.Printf("🔑 Password reset requested")
+
+	return bl.authService.RequestPasswordReset(email)
+}
+
+func (bl *BusinessLogic) ResetPassword(token, newPassword string) (*auth.User, error) {
+	log.Printf("🔑 Resetting password")
+
+	return bl.authService.ResetPassword(token, newPassword)
+}
+
+// ValidateToken validates a JWT token and returns the associated user
+func (bl *BusinessLogic) ValidateToken(token string) (*auth.User, error) {
+	return bl.authService.ValidateToken(token)
+}
+
+// AuthenticateHeader validates a Bearer token or ApiKey Authorization value
+func (bl *BusinessLogic) AuthenticateHeader(authHeader string) (*auth.User, *auth.APIKey, error) {
+	return bl.authService.AuthenticateHeader(authHeader)
+}
+
 // =============================================================================
 // EXECUTION MANAGEMENT
 // =============================================================================
 
-func (bl *BusinessLogic) StartExecution(request *types.MultiExecutionRequest, useMock bool, sessionApiKeys map[string]string) (string, *types.ExecutionRun, error) {
-	log.Printf("🚀 Starting execution: %s", request.ExecutionRunName)
+func (bl *BusinessLogic) PrepareExecutionRequest(ctx context.Context, userID string, request *types.MultiExecutionRequest) error {
+	if err := bl.client.ResolveConfigurations(ctx, userID, request); err != nil {
+		return err
+	}
+	settings, err := bl.client.GetWorkspaceSettings(ctx, types.DefaultWorkspaceID)
+	if err != nil {
+		return err
+	}
+	if err := gogent.ApplyWorkspaceDefaults(request, settings); err != nil {
+		return err
+	}
+	if err := gogent.ValidateRequestSafetyPolicies(request); err != nil {
+		return err
+	}
+	if err := gogent.ValidateRepetitions(request.Repetitions); err != nil {
+		return err
+	}
+	if err := gogent.ValidateExpectedAnswer(request.ExpectedAnswer); err != nil {
+		return err
+	}
+	if err := gogent.ValidatePriority(request.Priority); err != nil {
+		return err
+	}
+	return bl.client.ValidateRequest(ctx, request)
+}
+
+func (bl *BusinessLogic) PrepareSubmission(ctx context.Context, userID string, request *types.MultiExecutionRequest) (*types.ExecutionRun, error) {
+	settings, err := bl.client.GetWorkspaceSettings(ctx, types.DefaultWorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	return bl.client.PrepareSubmission(ctx, userID, request, gogent.DuplicateWindow(settings))
+}
+
+// StartExecution queues a request, returning a *gogent.QuotaError if it would exceed the user's quota
+// and gogent.ErrQueueFull when the queue has no room
+func (bl *BusinessLogic) StartExecution(ctx context.Context, userID string, request *types.MultiExecutionRequest, useMock bool, sessionApiKeys map[string]string) (string, *types.ExecutionRun, error) {
+	settings, err := bl.client.GetWorkspaceSettings(ctx, types.DefaultWorkspaceID)
+	if err != nil {
+		return "", nil, err
+	}
+	quota, err := bl.client.QuotaStatus(ctx, userID, settings.Quota, 0)
+	if err != nil {
+		return "", nil, err
+	}
+
+	log.Printf("🚀 Starting execution: %s for user: %s", request.ExecutionRunName, userID)
 
 	// Generate execution run ID
 	executionID := fmt.Sprintf("exec-%d", time.Now().UnixNano()/1000000)
 
-	// Track execution status
+	// Enforce the quota and track execution status under one lock
 	bl.executionMutex.Lock()
+	quota.ConcurrentExecutions = activeExecutions(bl.executions, userID)
+	if err := gogent.CheckQuota(quota, request); err != nil {
+		bl.executionMutex.Unlock()
+		return "", nil, err
+	}
 	bl.executions[executionID] = &ExecutionStatus{
 		ID:        executionID,
 		Status:    "pending",
 		StartTime: time.Now(),
+		UserID:    userID,
 	}
 	bl.executionMutex.Unlock()
 
 	// Create execution run for response
 	executionRun := &types.ExecutionRun{
 		ID:                    executionID,
+		UserID:                userID,
 		Name:                  request.ExecutionRunName,
 		Description:           request.Description,
 		EnableFunctionCalling: request.EnableFunctionCalling,
@@ -237,22 +544,35 @@ func (bl *BusinessLogic) StartExecution(request *types.MultiExecutionRequest, us
 		UpdatedAt:             time.Now(),
 	}
 
-	// Start async execution with session API keys
-	go bl.runAsyncExecution(executionID, request, useMock, sessionApiKeys)
+	// Queue the execution with session API keys
+	request.SubmittedAt = time.Now()
+	if _, err := bl.queue.Enqueue(executionID, request.Priority, func() {
+		bl.runAsyncExecution(executionID, userID, request, useMock, sessionApiKeys)
+	}); err != nil {
+		bl.executionMutex.Lock()
+		delete(bl.executions, executionID)
+		bl.executionMutex.Unlock()
+		return "", nil, err
+	}
 
 	return executionID, executionRun, nil
 }
 
-func (bl *BusinessLogic) GetExecutionStatus(ctx context.Context, executionID string) (string, time.Time, *time.Time, string, *types.ExecutionResult, error) {
+func (bl *BusinessLogic) GetExecutionStatus(ctx context.Context, userID, executionID string) (string, time.Time, *time.Time, string, *types.ExecutionResult, error) {
 	log.Printf("📊 Getting execution status for: %s", executionID)
 
 	bl.executionMutex.RLock()
 	execStatus, exists := bl.executions[executionID]
 	bl.executionMutex.RUnlock()
 
+	// Executions started by other users are reported as not found
+	if exists && execStatus.UserID != userID {
+		return "", time.Time{}, nil, "", nil, fmt.Errorf("execution not found: %s", executionID)
+	}
+
 	if !exists {
 		// Check if this is a real execution ID from database
-		realResult, err := bl.client.GetExecutionResult(ctx, bl.userID, executionID)
+		realResult, err := bl.client.GetExecutionResult(ctx, userID, executionID)
 		if err != nil {
 			return "", time.Time{}, nil, "", nil, fmt.Errorf("execution not found: %s", executionID)
 		}
@@ -263,7 +583,7 @@ func (bl *BusinessLogic) GetExecutionStatus(ctx context.Context, executionID str
 
 	var result *types.ExecutionResult
 	if execStatus.Status == "completed" && execStatus.RealExecutionRunID != "" {
-		realResult, err := bl.client.GetExecutionResult(ctx, bl.userID, execStatus.RealExecutionRunID)
+		realResult, err := bl.client.GetExecutionResult(ctx, userID, execStatus.RealExecutionRunID)
 		if err == nil {
 			result = realResult
 		}
@@ -277,300 +597,316 @@ func (bl *BusinessLogic) GetExecutionStatus(ctx context.Context, executionID str
 	return execStatus.Status, execStatus.StartTime, execStatus.EndTime, execStatus.ErrorMessage, result, nil
 }
 
-func (bl *BusinessLogic) GetExecutionResult(ctx context.Context, executionRunID string) (*types.ExecutionResult, error) {
+// ExecutionState returns a copy of the status of one of the user's executions, found by its execution
+// ID or the ID of its run, reporting false once the execution is no longer tracked in memory
+func (bl *BusinessLogic) ExecutionState(userID, id string) (ExecutionStatus, bool) {
+	bl.executionMutex.RLock()
+	defer bl.executionMutex.RUnlock()
+	execStatus, exists := bl.executions[id]
+	if !exists {
+		for _, candidate := range bl.executions {
+			if candidate.RealExecutionRunID == id {
+				execStatus, exists = candidate, true
+				break
+			}
+		}
+	}
+	if !exists || execStatus.UserID != userID {
+		return ExecutionStatus{}, false
+	}
+	state := *execStatus
+	if state.Progress != nil {
+		progress := *state.Progress
+		state.Progress = &progress
+	}
+	return state, true
+}
+
+func (bl *BusinessLogic) GetExecutionResult(ctx context.Context, userID, executionRunID string) (*types.ExecutionResult, error) {
 	log.Printf("📊 Getting execution result for: %s", executionRunID)
 
-	return bl.client.GetExecutionResult(ctx, bl.userID, executionRunID)
+	return bl.client.GetExecutionResult(ctx, userID, executionRunID)
 }
 
-func (bl *BusinessLogic) ListExecutionRuns(ctx context.Context, limit, offset int32) ([]*types.ExecutionRun, error) {
+// ListExecutionRuns returns a page of the user's runs and how many runs they have in total
+func (bl *BusinessLogic) ListExecutionRuns(ctx context.Context, userID string, limit, offset int32) ([]*types.ExecutionRun, int64, error) {
 	log.Printf("📋 Listing execution runs (limit: %d, offset: %d)", limit, offset)
 
 	if limit == 0 {
 		limit = 10
 	}
 
-	return bl.client.ListExecutionRuns(ctx, bl.userID, limit, offset)
+	runs, err := bl.client.ListExecutionRuns(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := bl.client.CountExecutionRuns(ctx, userID)
+	return runs, total, err
 }
 
-func (bl *BusinessLogic) DeleteExecutionRun(ctx context.Context, executionRunID string) error {
+// ListAllExecutionRuns returns a page of every user's runs and how many runs there are in total
+func (bl *BusinessLogic) ListAllExecutionRuns(ctx context.Context, limit, offset int32) ([]*types.ExecutionRun, int64, error) {
+	log.Printf("📋 Listing execution runs for all users (limit: %d, offset: %d)", limit, offset)
+
+	if limit == 0 {
+		limit = 10
+	}
+
+	runs, err := bl.client.ListAllExecutionRuns(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := bl.client.CountAllExecutionRuns(ctx)
+	return runs, total, err
+}
+
+// ListComparisons returns a page of the comparisons of the user's runs and how many there are in total
+func (bl *BusinessLogic) ListComparisons(ctx context.Context, userID string, limit, offset int32) ([]*types.ComparisonResult, int64, error) {
+	log.Printf("📋 Listing comparisons (limit: %d, offset: %d)", limit, offset)
+
+	comparisons, err := bl.client.ListComparisonResults(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := bl.client.CountComparisonResults(ctx, userID)
+	return comparisons, total, err
+}
+
+// ListExecutionLogs returns a page of the log entries of a run owned by the user
+func (bl *BusinessLogic) ListExecutionLogs(ctx context.Context, userID, executionRunID string, filter gogent.ExecutionLogFilter) ([]types.ExecutionLog, error) {
+	return bl.client.QueryExecutionLogs(ctx, userID, executionRunID, filter)
+}
+
+// GetComparison returns the comparison of a run owned by the user
+func (bl *BusinessLogic) GetComparison(ctx context.Context, userID, executionRunID string) (*types.ComparisonResult, error) {
+	log.Printf("📋 Getting comparison of execution run: %s", executionRunID)
+
+	return bl.client.GetComparisonResult(ctx, userID, executionRunID)
+}
+
+func (bl *BusinessLogic) DeleteExecutionRun(ctx context.Context, userID, executionRunID string) error {
 	log.Printf("🗑️ Deleting execution run: %s", executionRunID)
 
-	// TODO: Implement actual deletion logic
-	return nil
+	return bl.client.DeleteExecutionRun(ctx, userID, executionRunID)
+}
+
+// RecordAuditEvent appends an event to the audit log. A failure is logged rather than returned, so
+// the audited action still succeeds.
+func (bl *BusinessLogic) RecordAuditEvent(ctx context.Context, event *types.AuditEvent) {
+	if err := bl.client.RecordAuditEvent(ctx, event); err != nil {
+		log.Printf("⚠️ Warning: failed to record %s audit event: %v", event.Action, err)
+	}
 }
 
 // =============================================================================
-// CONFIGURATION MANAGEMENT
+// BATCH SUBMISSION
 // =============================================================================
 
-func (bl *BusinessLogic) GetDefaultConfigurations() []types.APIConfiguration {
-	temp1 := float32(0.2)
-	temp2 := float32(0.5)
-	temp3 := float32(0.8)
-	maxTokens := int32(500)
-	topP1 := float32(0.8)
-	topP2 := float32(0.9)
-	topP3 := float32(0.95)
-	topK1 := int32(10)
-	topK2 := int32(20)
-	topK3 := int32(40)
-
-	return []types.APIConfiguration{
-		{
-			ID:            "config-conservative",
-			VariationName: "Conservative",
-			ModelName:     "gemini-1.5-flash",
-			SystemPrompt:  "You are a helpful assistant. Provide balanced, informative responses.",
-			Temperature:   &temp1,
-			MaxTokens:     &maxTokens,
-			TopP:          &topP1,
-			TopK:          &topK1,
-			CreatedAt:     time.Now(),
-		},
-		{
-			ID:            "config-balanced",
-			VariationName: "Balanced",
-			ModelName:     "gemini-1.5-flash",
-			SystemPrompt:  "You are a helpful assistant. Provide balanced, informative responses.",
-			Temperature:   &temp2,
-			MaxTokens:     &maxTokens,
-			TopP:          &topP2,
-			TopK:          &topK2,
-			CreatedAt:     time.Now(),
-		},
-		{
-			ID:            "config-creative",
-			VariationName: "Creative",
-			ModelName:     "gemini-1.5-flash",
-			SystemPrompt:  "You are a creative assistant. Provide imaginative and engaging responses.",
-			Temperature:   &temp3,
-			MaxTokens:     &maxTokens,
-			TopP:          &topP3,
-			TopK:          &topK3,
-			CreatedAt:     time.Now(),
-		},
-	}
-}
-
-func (bl *BusinessLogic) CreateConfiguration(config *types.APIConfiguration) (*types.APIConfiguration, error) {
-	log.Printf("➕ Creating configuration: %s", config.VariationName)
-
-	// TODO: Implement actual creation logic
-	config.ID = fmt.Sprintf("config-%d", time.Now().Unix())
-	config.CreatedAt = time.Now()
-
-	return config, nil
-}
-
-func (bl *BusinessLogic) UpdateConfiguration(id string, config *types.APIConfiguration) (*types.APIConfiguration, error) {
-	log.Printf("✏️ Updating configuration: %s", id)
-
-	// TODO: Implement actual update logic
-	config.ID = id
-
-	return config, nil
-}
-
-func (bl *BusinessLogic) DeleteConfiguration(id string) error {
-	log.Printf("🗑️ Deleting configuration: %s", id)
-
-	// TODO: Implement actual deletion logic
+// batchItemPageSize is how many pending items a batch run loads at a time
+const batchItemPageSize = 50
+
+func (bl *BusinessLogic) CreateBatchRun(ctx context.Context, userID, name string, template *types.MultiExecutionRequest) (*types.BatchRun, error) {
+	log.Printf("📦 Creating batch run: %s for user: %s", name, userID)
+
+	return bl.client.CreateBatchRun(ctx, userID, name, template)
+}
+
+func (bl *BusinessLogic) AppendBatchItems(ctx context.Context, batchID string, startIndex int32, items []types.BatchItem) error {
+	log.Printf("📦 Storing %d batch items for: %s", len(items), batchID)
+
+	return bl.client.AppendBatchItems(ctx, batchID, startIndex, items)
+}
+
+func (bl *BusinessLogic) FailBatchRun(batchID, message string) {
+	log.Printf("❌ Batch run %s failed: %s", batchID, message)
+
+	if err := bl.client.UpdateBatchRunStatus(context.Background(), batchID, types.BatchStatusFailed, message); err != nil {
+		log.Printf("❌ Failed to mark batch run %s as failed: %v", batchID, err)
+	}
+}
+
+func (bl *BusinessLogic) StartBatchRun(ctx context.Context, userID, batchID string) error {
+	log.Printf("🚀 Starting batch run: %s", batchID)
+
+	if err := bl.client.UpdateBatchRunStatus(ctx, batchID, types.BatchStatusSubmitted, ""); err != nil {
+		return err
+	}
+
+	go bl.runBatch(userID, batchID)
 	return nil
 }
 
-// =============================================================================
-// FUNCTION MANAGEMENT
-// =============================================================================
+func (bl *BusinessLogic) GetBatchRun(ctx context.Context, userID, batchID string) (*types.BatchRun, error) {
+	log.Printf("📦 Getting batch run: %s", batchID)
 
-func (bl *BusinessLogic) ListFunctions(ctx context.Context) ([]*types.FunctionDefinition, error) {
-	log.Printf("📋 Listing functions")
+	return bl.client.GetBatchRun(ctx, userID, batchID)
+}
 
-	// Query the database directly for function definitions
-	query := `
-		SELECT id, name, display_name, description, parameters_schema,
-		       mock_response, endpoint_url, http_method, headers, auth_config,
-		       is_active, created_at, updated_at
-		FROM function_definitions
-		WHERE is_active = true
-		ORDER BY display_name ASC
-	`
+// runBatch executes a batch run's pending items one at a time, one execution run per item
+func (bl *BusinessLogic) runBatch(userID, batchID string) {
+	ctx := context.Background()
 
-	rows, err := bl.client.GetDB().QueryContext(ctx, query)
+	batch, err := bl.client.GetBatchRun(ctx, userID, batchID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query functions: %v", err)
-	}
-	defer rows.Close()
-
-	var functions []*types.FunctionDefinition
-
-	for rows.Next() {
-		var function types.FunctionDefinition
-		var parametersSchemaJSON string
-		var mockResponseJSON, headersJSON, authConfigJSON sql.NullString
-		var endpointURL sql.NullString
-
-		err := rows.Scan(
-			&function.ID,
-			&function.Name,
-			&function.DisplayName,
-			&function.Description,
-			&parametersSchemaJSON,
-			&mockResponseJSON,
-			&endpointURL,
-			&function.HttpMethod,
-			&headersJSON,
-			&authConfigJSON,
-			&function.IsActive,
-			&function.CreatedAt,
-			&function.UpdatedAt,
-		)
+		bl.FailBatchRun(batchID, fmt.Sprintf("Failed to load batch run: %v", err))
+		return
+	}
+	if err := bl.client.UpdateBatchRunStatus(ctx, batchID, types.BatchStatusRunning, ""); err != nil {
+		log.Printf("⚠️ Failed to mark batch run %s as running: %v", batchID, err)
+	}
+
+	for {
+		items, err := bl.client.ListPendingBatchItems(ctx, batchID, batchItemPageSize)
 		if err != nil {
-			log.Printf("❌ Failed to scan function row: %v", err)
-			continue
+			bl.FailBatchRun(batchID, fmt.Sprintf("Failed to load batch items: %v", err))
+			return
 		}
-
-		// Set endpoint URL
-		if endpointURL.Valid {
-			function.EndpointURL = endpointURL.String
+		if len(items) == 0 {
+			break
 		}
 
-		// TODO: Parse JSON fields
-		functions = append(functions, &function)
+		for _, item := range items {
+			executionRunID, errorMessage := "", ""
+			result, err := bl.client.ExecuteMultiVariation(ctx, userID, gogent.BatchItemRequest(batch, item))
+			if err != nil {
+				errorMessage = err.Error()
+			} else {
+				executionRunID = result.ExecutionRun.ID
+			}
+
+			if err := bl.client.FinishBatchItem(ctx, item.ID, executionRunID, errorMessage); err != nil {
+				bl.FailBatchRun(batchID, fmt.Sprintf("Failed to record batch item %d: %v", item.ItemIndex, err))
+				return
+			}
+		}
 	}
 
-	return functions, nil
+	if err := bl.client.UpdateBatchRunStatus(ctx, batchID, types.BatchStatusCompleted, ""); err != nil {
+		log.Printf("❌ Failed to mark batch run %s as completed: %v", batchID, err)
+		return
+	}
+	log.Printf("✅ Batch run completed: %s", batchID)
 }
 
-func (bl *BusinessLogic) GetFunction(id string) (*types.FunctionDefinition, error) {
-	log.Printf("🔍 Getting function: %s", id)
+// =============================================================================
+// CONFIGURATION MANAGEMENT
+// =============================================================================
 
-	// TODO: Implement actual database lookup
-	if id == "func-1" {
-		function := &types.FunctionDefinition{
-			ID:          "func-1",
-			Name:        "get_weather",
-			DisplayName: "Get Weather",
-			Description: "Get current weather information for a location",
-			EndpointURL: "https://api.weather.com/v1/current",
-			HttpMethod:  "GET",
-			IsActive:    true,
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
-		}
+// ListConfigurationPresets returns a page of the user's presets and how many they have in total
+func (bl *BusinessLogic) ListConfigurationPresets(ctx context.Context, userID string, limit, offset int32) ([]*types.ConfigurationPreset, int64, error) {
+	log.Printf("📋 Listing configuration presets (limit: %d, offset: %d)", limit, offset)
 
-		return function, nil
+	presets, err := bl.client.ListConfigurationPresets(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
 	}
+	total, err := bl.client.CountConfigurationPresets(ctx, userID)
+	return presets, total, err
+}
 
-	return nil, fmt.Errorf("function not found: %s", id)
+func (bl *BusinessLogic) GetSystemConfigurations(ctx context.Context) ([]types.APIConfiguration, error) {
+	log.Printf("⚙️ Getting system configurations")
+
+	return bl.client.GetSystemConfigurations(ctx)
 }
 
-func (bl *BusinessLogic) CreateFunction(function *types.FunctionDefinition) (*types.FunctionDefinition, error) {
-	log.Printf("➕ Creating function: %s", function.DisplayName)
+func (bl *BusinessLogic) CreateConfigurationPreset(ctx context.Context, userID string, preset *types.ConfigurationPreset) (*types.ConfigurationPreset, error) {
+	log.Printf("➕ Creating configuration preset: %s", preset.Name)
+
+	return bl.client.CreateConfigurationPreset(ctx, userID, preset)
+}
 
-	// TODO: Implement actual database insertion
-	function.ID = fmt.Sprintf("func-%d", time.Now().Unix())
-	function.CreatedAt = time.Now()
-	function.UpdatedAt = time.Now()
-	function.IsActive = true
+func (bl *BusinessLogic) UpdateConfigurationPreset(ctx context.Context, userID, id string, preset *types.ConfigurationPreset) (*types.ConfigurationPreset, error) {
+	log.Printf("✏️ Updating configuration preset: %s", id)
 
-	return function, nil
+	return bl.client.UpdateConfigurationPreset(ctx, userID, id, preset)
 }
 
-func (bl *BusinessLogic) UpdateFunction(id string, function *types.FunctionDefinition) (*types.FunctionDefinition, error) {
-	log.Printf("✏️ Updating function: %s", id)
+func (bl *BusinessLogic) DeleteConfigurationPreset(ctx context.Context, userID, id string) error {
+	log.Printf("🗑️ Deleting configuration preset: %s", id)
+
+	return bl.client.DeleteConfigurationPreset(ctx, userID, id)
+}
+
+// =============================================================================
+// FUNCTION MANAGEMENT
+// =============================================================================
 
-	// TODO: Implement actual database update
-	function.ID = id
-	function.UpdatedAt = time.Now()
+// ListFunctions returns a page of the functions visible to the user and how many there are in total
+func (bl *BusinessLogic) ListFunctions(ctx context.Context, userID string, limit, offset int32) ([]*types.FunctionDefinition, int64, error) {
+	log.Printf("📋 Listing functions (limit: %d, offset: %d)", limit, offset)
 
-	return function, nil
+	functions, err := bl.client.ListFunctionDefinitions(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := bl.client.CountFunctionDefinitions(ctx, userID)
+	return functions, total, err
 }
 
-func (bl *BusinessLogic) DeleteFunction(id string) error {
-	log.Printf("🗑️ Deleting function: %s", id)
+func (bl *BusinessLogic) GetFunction(ctx context.Context, userID, id string) (*types.FunctionDefinition, error) {
+	log.Printf("🔍 Getting function: %s", id)
 
-	// TODO: Implement actual database deletion
-	return nil
+	return bl.client.GetFunctionDefinition(ctx, userID, id)
 }
 
-func (bl *BusinessLogic) TestFunction(functionID string, useMockData bool) (bool, bool, int32, map[string]interface{}, string, error) {
-	log.Printf("🧪 Testing function: %s", functionID)
+func (bl *BusinessLogic) CreateFunction(ctx context.Context, userID string, function *types.FunctionDefinition) (*types.FunctionDefinition, error) {
+	log.Printf("➕ Creating function: %s", function.DisplayName)
 
-	startTime := time.Now()
+	return bl.client.CreateFunctionDefinition(ctx, userID, function)
+}
 
-	// For now, simulate function execution
-	if useMockData {
-		mockResponse := map[string]interface{}{
-			"status": "mock_success",
-			"data":   "Mock response generated",
-		}
+func (bl *BusinessLogic) UpdateFunction(ctx context.Context, userID, id string, function *types.FunctionDefinition) (*types.FunctionDefinition, error) {
+	log.Printf("✏️ Updating function: %s", id)
 
-		return true, true, int32(time.Since(startTime).Milliseconds()), mockResponse, "", nil
-	}
+	return bl.client.UpdateFunctionDefinition(ctx, userID, id, function)
+}
 
-	// TODO: Implement real function execution
-	realResponse := map[string]interface{}{
-		"status":  "not_implemented",
-		"message": "Real function testing not implemented yet",
-	}
+func (bl *BusinessLogic) DeleteFunction(ctx context.Context, userID, id string) error {
+	log.Printf("🗑️ Deleting function: %s", id)
+
+	return bl.client.DeleteFunctionDefinition(ctx, userID, id)
+}
+
+func (bl *BusinessLogic) TestFunction(ctx context.Context, userID, functionID string, args map[string]interface{}, useMockData bool, timeout time.Duration) (*types.FunctionTestResult, error) {
+	log.Printf("🧪 Testing function: %s", functionID)
 
-	return false, false, int32(time.Since(startTime).Milliseconds()), realResponse, "Real function testing not implemented", nil
+	return bl.client.TestFunctionDefinition(ctx, userID, functionID, args, useMockData, timeout)
 }
 
 // =============================================================================
 // DATABASE MANAGEMENT
 // =============================================================================
 
-func (bl *BusinessLogic) GetDatabaseStats() (int32, int32, int32, int32, float64, float64) {
-	log.Printf("📊 Getting database stats")
+// GetDatabaseStats returns stats for userID, or for every user when userID is empty (admin only)
+func (bl *BusinessLogic) GetDatabaseStats(ctx context.Context, userID string) (map[string]interface{}, error) {
+	log.Printf("📊 Getting database stats for user: %s", userID)
 
-	// TODO: Implement actual database statistics
-	return 25, 156, 156, 8, 450.5, 0.94
+	return databaseStats(ctx, bl.client, userID)
 }
 
 func (bl *BusinessLogic) ListDatabaseTables() []string {
 	log.Printf("📋 Listing database tables")
 
-	return []string{
-		"execution_runs",
-		"comparison_results",
-		"function_calls",
-		"api_configurations",
-		"api_requests",
-		"api_responses",
-		"execution_logs",
-		"function_definitions",
-		"execution_function_configs",
-	}
+	return gogent.BrowsableTables()
 }
 
-func (bl *BusinessLogic) GetTableData(tableName string) ([]string, [][]interface{}, int32, error) {
+// GetTableData browses a page of a table scoped to userID, or every row when userID is empty (admin only),
+// returning the selected columns, the page's rows and the number of matching rows across all pages
+func (bl *BusinessLogic) GetTableData(ctx context.Context, userID, tableName string, query gogent.TableQuery) ([]string, [][]interface{}, int64, error) {
 	log.Printf("📊 Getting table data for: %s", tableName)
 
-	// TODO: Implement actual table data retrieval
-	// For now, return mock data
-	columns := []string{"id", "name", "created_at"}
-	rows := [][]interface{}{
-		{"1", "Sample data", time.Now().Format(time.RFC3339)},
-	}
-
-	return columns, rows, 1, nil
+	return bl.client.QueryTableData(ctx, userID, tableName, query)
 }
 
 // =============================================================================
 // HEALTH & SYSTEM
 // =============================================================================
 
-func (bl *BusinessLogic) GetHealthStatus() (string, string, bool, bool) {
+// GetHealthStatus checks the service's dependencies and the execution queue
+func (bl *BusinessLogic) GetHealthStatus(ctx context.Context) *types.HealthReport {
 	log.Printf("🏥 Health check")
-
-	status := "ok"
-	version := "1.0.0"
-	database := bl.client != nil
-	geminiAPI := false // Session-based API keys, not stored in config
-
-	return status, version, database, geminiAPI
+	return bl.client.CheckHealth(ctx, bl.queue)
 }
 
 func (bl *BusinessLogic) TestConnection() (*types.APIResponse, error) {
@@ -592,8 +928,8 @@ func (bl *BusinessLogic) TestConnection() (*types.APIResponse, error) {
 // HELPER METHODS
 // =============================================================================
 
-// runAsyncExecution runs the execution in a goroutine
-func (bl *BusinessLogic) runAsyncExecution(executionID string, request *types.MultiExecutionRequest, useMock bool, sessionApiKeys map[string]string) {
+// runAsyncExecution runs the execution on a queue worker
+func (bl *BusinessLogic) runAsyncExecution(executionID, userID string, request *types.MultiExecutionRequest, useMock bool, sessionApiKeys map[string]string) {
 	// Update status to running
 	bl.executionMutex.Lock()
 	if status, exists := bl.executions[executionID]; exists {
@@ -607,6 +943,7 @@ func (bl *BusinessLogic) runAsyncExecution(executionID string, request *types.Mu
 	tempConfig := &types.GeminiClientConfig{
 		MaxRetries:  bl.config.MaxRetries,
 		TimeoutSecs: bl.config.TimeoutSecs,
+		Redaction:   bl.config.Redaction,
 	}
 
 	// Use session API keys instead of stored configuration
@@ -621,6 +958,28 @@ func (bl *BusinessLogic) runAsyncExecution(executionID string, request *types.Mu
 		log.Printf("Using real Gemini API for execution")
 	}
 
+	// web_search runs on the server's search provider with the session's key, else the server's
+	tempConfig.WebSearchProvider = bl.config.WebSearchProvider
+	tempConfig.WebSearchAPIKey = bl.config.WebSearchAPIKey
+	if sessionApiKeys["webSearchApiKey"] != "" {
+		tempConfig.WebSearchAPIKey = sessionApiKeys["webSearchApiKey"]
+	}
+
+	// query_sql runs against the session's database, else the server's
+	tempConfig.SQLQueryDriver = bl.config.SQLQueryDriver
+	tempConfig.SQLQueryDSN = bl.config.SQLQueryDSN
+	if sessionApiKeys["sqlDsn"] != "" {
+		tempConfig.SQLQueryDSN = sessionApiKeys["sqlDsn"]
+	}
+
+	// Vertex AI and Ollama configurations run on the server's project and Ollama server unless mock
+	// responses were asked for
+	if !useMock {
+		tempConfig.ProjectID = bl.config.ProjectID
+		tempConfig.Region = bl.config.Region
+		tempConfig.OllamaURL = bl.config.OllamaURL
+	}
+
 	// Create temporary client - the gogent.NewClient will need to be updated
 	// For now, we'll use the existing configuration but this needs to be refactored
 	dbURL := os.Getenv("DB_URL")
@@ -630,10 +989,19 @@ func (bl *BusinessLogic) runAsyncExecution(executionID string, request *types.Mu
 		return
 	}
 	defer tempClient.Close()
+	// Hosts that failed in earlier runs stay open
+	tempClient.SetCircuitBreakers(bl.client.CircuitBreakers())
 
-	// Execute the request
-	ctx := context.Background()
-	result, err := tempClient.ExecuteMultiVariation(ctx, bl.userID, request)
+	// Execute the request, tracking its run and progress for WatchExecution
+	ctx := gogent.WithProgress(bl.runCtx, func(progress types.ExecutionProgress) {
+		bl.executionMutex.Lock()
+		if status, exists := bl.executions[executionID]; exists {
+			status.RealExecutionRunID = progress.ExecutionRunID
+			status.Progress = &progress
+		}
+		bl.executionMutex.Unlock()
+	})
+	result, err := tempClient.ExecuteMultiVariation(ctx, userID, request)
 	if err != nil {
 		log.Printf("❌ Execution failed: %v", err)
 		bl.markExecutionFailed(executionID, fmt.Sprintf("Execution failed: %v", err))
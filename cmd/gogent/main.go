@@ -22,8 +22,18 @@ func main() {
 		case "--grpc-gateway":
 			runGRPCGateway()
 		case "--both":
-			go runGRPCServer() // Start gRPC server in background
-			runGRPCGateway()   // Start HTTP gateway in foreground
+			go runGRPCGateway() // Start HTTP gateway in background
+			runGRPCServer()     // Start gRPC server in foreground; it returns once executions are drained at shutdown
+		case "--mcp-server":
+			if err := runMCPServer(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+				os.Exit(1)
+			}
+		case "run", "runs", "functions", "export", "optimize", "prune", "restore", "db", "openapi", "tui", "telemetry":
+			if err := runCLI(os.Args[1], os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+				os.Exit(1)
+			}
 		default:
 			fmt.Printf("Unknown option: %s\n", os.Args[1])
 			printUsage()
@@ -40,6 +50,7 @@ func printUsage() {
 	fmt.Println()
 	fmt.Println("Usage:")
 	fmt.Println("  go run cmd/gogent/*.go [option]")
+	fmt.Println("  go run cmd/gogent/*.go <command> [flags]")
 	fmt.Println()
 	fmt.Println("Options:")
 	fmt.Println("  (no args)      Start REST HTTP server (mobile-friendly, default)")
@@ -47,8 +58,30 @@ func printUsage() {
 	fmt.Println("  --grpc-server  Start native gRPC server (port 9090)")
 	fmt.Println("  --grpc-gateway Start HTTP-to-gRPC gateway (port 8081)")
 	fmt.Println("  --both         Start both gRPC server + HTTP gateway")
+	fmt.Println("  --mcp-server   Serve functions and runs as MCP tools on stdio (takes the command flags)")
 	fmt.Println("  --help, -h     Show this help message")
 	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  run -f spec.yaml      Execute a YAML or JSON run spec")
+	fmt.Println("  runs list             List execution runs")
+	fmt.Println("  runs show <id>        Show an execution run's results")
+	fmt.Println("  functions list        List function definitions")
+	fmt.Println("  export [-o file]      Export execution results as JSON lines")
+	fmt.Println("  optimize -f spec.yaml Tune a spec's sweep over rounds of proposed configurations")
+	fmt.Println("  prune [--older-than 90d] Delete or archive old execution history (needs DB_URL)")
+	fmt.Println("  restore <run-id> --from  Re-import a run archived by prune (needs DB_URL)")
+	fmt.Println("  db verify [--repair]  Find and fix rows referencing missing rows (needs DB_URL)")
+	fmt.Println("  openapi [-o file] [--tags Executions,...]  Write the REST API's OpenAPI document")
+	fmt.Println("  tui [<id> | -f spec.yaml] Watch runs, progress, logs and results live (needs --server)")
+	fmt.Println("  telemetry preview     Print the anonymized usage report an opted-in server sends (needs DB_URL)")
+	fmt.Println()
+	fmt.Println("Command flags:")
+	fmt.Println("  --server host:port    Call a gRPC server instead of running in process ($GOGENT_SERVER)")
+	fmt.Println("  --api-key, --token    Credentials for --server ($GOGENT_API_KEY, $GOGENT_TOKEN)")
+	fmt.Println("  --mock                Use mock responses instead of calling Gemini")
+	fmt.Println("  --json                Print JSON instead of a summary")
+	fmt.Println("  Without --server, runs are stored in MySQL when DB_URL is set and kept in memory otherwise.")
+	fmt.Println()
 	fmt.Println("Setup:")
 	fmt.Println("  1. Copy config.example.env to config.env")
 	fmt.Println("  2. Add your GEMINI_API_KEY to config.env")
@@ -61,5 +94,7 @@ func printUsage() {
 	fmt.Println("  go run cmd/gogent/*.go --grpc-server     # Start gRPC server")
 	fmt.Println("  go run cmd/gogent/*.go --grpc-gateway    # Start HTTP-to-gRPC gateway")
 	fmt.Println("  go run cmd/gogent/*.go --both            # Start both gRPC + gateway")
+	fmt.Println("  go run cmd/gogent/*.go run -f examples/spec.yaml      # Execute in process")
+	fmt.Println("  go run cmd/gogent/*.go runs list --server localhost:9090  # List runs on a server")
 	fmt.Println()
 }
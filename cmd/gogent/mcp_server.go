@@ -0,0 +1,387 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"gogent/internal/gogent"
+)
+
+// mcpProtocolVersions are the MCP revisions the server speaks, newest first
+var mcpProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// JSON-RPC error codes used by the MCP server
+const (
+	mcpParseError     = -32700
+	mcpInvalidRequest = -32600
+	mcpMethodNotFound = -32601
+	mcpInvalidParams  = -32602
+)
+
+// mcpMaxMessageBytes caps the size of one message read from the client
+const mcpMaxMessageBytes = 4 << 20
+
+// mcpRequest is a JSON-RPC request or, without an ID, a notification
+type mcpRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// mcpResponse is a JSON-RPC response carrying either a result or an error
+type mcpResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *mcpError       `json:"error,omitempty"`
+}
+
+type mcpError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// mcpTool describes a tool in a tools/list result
+type mcpTool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+}
+
+// mcpContent is a text block of a tools/call result
+type mcpContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// mcpToolResult is a tools/call result; tool failures are reported in it, not as JSON-RPC errors,
+// so the calling model can see them
+type mcpToolResult struct {
+	Content []mcpContent `json:"content"`
+	IsError bool         `json:"isError,omitempty"`
+}
+
+// mcpServer exposes the user's function definitions and gogent's execution commands as MCP tools
+// over stdio
+type mcpServer struct {
+	backend cliBackend
+	// builtinTools are gogent's own tools; they take precedence over functions with the same name
+	builtinTools map[string]func(ctx context.Context, args map[string]interface{}) (*mcpToolResult, error)
+}
+
+// runMCPServer serves MCP on stdin and stdout until stdin is closed. Logs go to stderr, which MCP
+// clients keep out of the protocol.
+func runMCPServer(args []string) error {
+	var opts cliOptions
+	fs := flag.NewFlagSet("--mcp-server", flag.ContinueOnError)
+	opts.register(fs)
+	if _, err := parseCommandFlags(fs, args); err != nil {
+		return err
+	}
+	log.SetOutput(os.Stderr)
+
+	backend, err := opts.backend()
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	log.Printf("🔌 MCP server ready on stdio")
+	return newMCPServer(backend).serve(context.Background(), os.Stdin, os.Stdout)
+}
+
+// newMCPServer creates an MCP server on a CLI backend
+func newMCPServer(backend cliBackend) *mcpServer {
+	s := &mcpServer{backend: backend}
+	s.builtinTools = map[string]func(ctx context.Context, args map[string]interface{}) (*mcpToolResult, error){
+		"gogent_run":       s.runTool,
+		"gogent_list_runs": s.listRunsTool,
+		"gogent_get_run":   s.getRunTool,
+	}
+	return s
+}
+
+// serve reads newline-delimited JSON-RPC messages and answers each request in turn
+func (s *mcpServer) serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), mcpMaxMessageBytes)
+	encoder := json.NewEncoder(out)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var request mcpRequest
+		if err := json.Unmarshal(line, &request); err != nil {
+			if err := encoder.Encode(mcpErrorResponse(json.RawMessage("null"), mcpParseError, "invalid JSON: "+err.Error())); err != nil {
+				return err
+			}
+			continue
+		}
+		if len(request.ID) == 0 {
+			// Notifications, such as notifications/initialized, need no answer
+			continue
+		}
+		if err := encoder.Encode(s.handle(ctx, &request)); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// handle answers one request
+func (s *mcpServer) handle(ctx context.Context, request *mcpRequest) *mcpResponse {
+	if request.JSONRPC != "2.0" {
+		return mcpErrorResponse(request.ID, mcpInvalidRequest, "jsonrpc must be 2.0")
+	}
+
+	var result interface{}
+	var err *mcpError
+	switch request.Method {
+	case "initialize":
+		result, err = s.initialize(request.Params)
+	case "ping":
+		result = map[string]interface{}{}
+	case "tools/list":
+		result, err = s.listTools(ctx)
+	case "tools/call":
+		result, err = s.callTool(ctx, request.Params)
+	default:
+		err = &mcpError{Code: mcpMethodNotFound, Message: "method not found: " + request.Method}
+	}
+	if err != nil {
+		return &mcpResponse{JSONRPC: "2.0", ID: request.ID, Error: err}
+	}
+	return &mcpResponse{JSONRPC: "2.0", ID: request.ID, Result: result}
+}
+
+func mcpErrorResponse(id json.RawMessage, code int, message string) *mcpResponse {
+	return &mcpResponse{JSONRPC: "2.0", ID: id, Error: &mcpError{Code: code, Message: message}}
+}
+
+// initialize agrees on the protocol version, the client's when the server speaks it and the
+// server's newest otherwise
+func (s *mcpServer) initialize(params json.RawMessage) (interface{}, *mcpError) {
+	var request struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &request); err != nil {
+			return nil, &mcpError{Code: mcpInvalidParams, Message: "invalid initialize params: " + err.Error()}
+		}
+	}
+	version := mcpProtocolVersions[0]
+	for _, supported := range mcpProtocolVersions {
+		if request.ProtocolVersion == supported {
+			version = supported
+		}
+	}
+	return map[string]interface{}{
+		"protocolVersion": version,
+		"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}},
+		"serverInfo":      map[string]interface{}{"name": "gogent", "version": "1.0.0"},
+		"instructions": "gogent_run runs a prompt across model configurations and compares the responses. " +
+			"The other tools call the user's stored function definitions.",
+	}, nil
+}
+
+// listTools lists gogent's own tools followed by the user's active function definitions
+func (s *mcpServer) listTools(ctx context.Context) (interface{}, *mcpError) {
+	tools := mcpBuiltinTools()
+	functions, err := s.backend.ListFunctions(ctx)
+	if err != nil {
+		// Without a database there are no stored functions, but the run tools still work
+		log.Printf("⚠️ Warning: MCP tools list has no functions: %v", err)
+	}
+	for _, function := range functions {
+		if !function.IsActive || s.builtinTools[function.Name] != nil {
+			continue
+		}
+		schema := function.ParametersSchema
+		if len(schema) == 0 {
+			schema = map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+		}
+		tools = append(tools, mcpTool{Name: function.Name, Description: function.Description, InputSchema: schema})
+	}
+	return map[string]interface{}{"tools": tools}, nil
+}
+
+// callTool runs a gogent tool or calls the function definition with the tool's name
+func (s *mcpServer) callTool(ctx context.Context, params json.RawMessage) (interface{}, *mcpError) {
+	var call struct {
+		Name      string                 `json:"name"`
+		Arguments map[string]interface{} `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &call); err != nil || call.Name == "" {
+		return nil, &mcpError{Code: mcpInvalidParams, Message: "tools/call needs a tool name"}
+	}
+	if call.Arguments == nil {
+		call.Arguments = map[string]interface{}{}
+	}
+
+	if tool := s.builtinTools[call.Name]; tool != nil {
+		result, err := tool(ctx, call.Arguments)
+		if err != nil {
+			return mcpToolError(err), nil
+		}
+		return result, nil
+	}
+
+	functions, err := s.backend.ListFunctions(ctx)
+	if err != nil {
+		return mcpToolError(err), nil
+	}
+	for _, function := range functions {
+		if function.Name != call.Name || !function.IsActive {
+			continue
+		}
+		result, err := s.backend.CallFunction(ctx, function.ID, call.Arguments)
+		if err != nil {
+			return mcpToolError(err), nil
+		}
+		if !result.Success {
+			return mcpToolError(fmt.Errorf("%s failed: %s", function.Name, result.Error)), nil
+		}
+		return mcpJSONResult(result.Response)
+	}
+	return nil, &mcpError{Code: mcpInvalidParams, Message: "unknown tool: " + call.Name}
+}
+
+// runTool executes a run spec given as the tool's arguments and summarizes each variation's response
+func (s *mcpServer) runTool(ctx context.Context, args map[string]interface{}) (*mcpToolResult, error) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := gogent.ParseRunSpec(data)
+	if err != nil {
+		return nil, err
+	}
+	if spec.Optimize != nil {
+		return nil, fmt.Errorf("optimize blocks are not supported; run them with gogent optimize")
+	}
+
+	result, err := s.backend.Execute(ctx, gogent.RunSpecRequest(spec))
+	if err != nil {
+		return nil, err
+	}
+	var summary bytes.Buffer
+	printExecutionResult(&summary, result)
+	return &mcpToolResult{Content: []mcpContent{{Type: "text", Text: summary.String()}}}, nil
+}
+
+// listRunsTool lists the user's most recent execution runs
+func (s *mcpServer) listRunsTool(ctx context.Context, args map[string]interface{}) (*mcpToolResult, error) {
+	limit := int32(20)
+	if value, ok := args["limit"].(float64); ok && value >= 1 {
+		limit = int32(min(value, float64(gogent.MaxPageSize)))
+	}
+	runs, err := s.backend.ListExecutionRuns(ctx, limit, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var table bytes.Buffer
+	w := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tSTATUS\tCREATED")
+	for _, run := range runs {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", run.ID, run.Name, run.Status, run.CreatedAt.Format(time.RFC3339))
+	}
+	w.Flush()
+	return &mcpToolResult{Content: []mcpContent{{Type: "text", Text: table.String()}}}, nil
+}
+
+// getRunTool summarizes one execution run's results
+func (s *mcpServer) getRunTool(ctx context.Context, args map[string]interface{}) (*mcpToolResult, error) {
+	id, _ := args["id"].(string)
+	if id == "" {
+		return nil, fmt.Errorf("id is required")
+	}
+	result, err := s.backend.GetExecutionResult(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	var summary bytes.Buffer
+	printExecutionResult(&summary, result)
+	return &mcpToolResult{Content: []mcpContent{{Type: "text", Text: summary.String()}}}, nil
+}
+
+// mcpToolError reports a failed tool call to the calling model
+func mcpToolError(err error) *mcpToolResult {
+	return &mcpToolResult{Content: []mcpContent{{Type: "text", Text: err.Error()}}, IsError: true}
+}
+
+// mcpJSONResult returns a value as the JSON text of a tool result
+func mcpJSONResult(value interface{}) (interface{}, *mcpError) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return mcpToolError(err), nil
+	}
+	return &mcpToolResult{Content: []mcpContent{{Type: "text", Text: string(data)}}}, nil
+}
+
+// mcpBuiltinTools describes gogent's own tools
+func mcpBuiltinTools() []mcpTool {
+	configuration := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"name":         map[string]interface{}{"type": "string", "description": "Variation name"},
+			"model":        map[string]interface{}{"type": "string", "description": "Model, e.g. gemini-1.5-flash"},
+			"preset":       map[string]interface{}{"type": "string", "description": "Configuration preset ID to start from"},
+			"systemPrompt": map[string]interface{}{"type": "string"},
+			"temperature":  map[string]interface{}{"type": "number"},
+			"maxTokens":    map[string]interface{}{"type": "integer"},
+			"topP":         map[string]interface{}{"type": "number"},
+			"topK":         map[string]interface{}{"type": "integer"},
+		},
+		"required": []string{"name"},
+	}
+	return []mcpTool{
+		{
+			Name: "gogent_run",
+			Description: "Run a prompt across model configurations and compare the responses. " +
+				"Takes a gogent run spec; fields beyond those listed, such as tools, comparison and sweep, are accepted too.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name":           map[string]interface{}{"type": "string", "description": "Execution run name"},
+					"prompt":         map[string]interface{}{"type": "string"},
+					"context":        map[string]interface{}{"type": "string"},
+					"configurations": map[string]interface{}{"type": "array", "items": configuration, "minItems": 1},
+					"repetitions":    map[string]interface{}{"type": "integer", "description": "Times to run each configuration"},
+				},
+				"required": []string{"prompt", "configurations"},
+			},
+		},
+		{
+			Name:        "gogent_list_runs",
+			Description: "List the most recent execution runs",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"limit": map[string]interface{}{"type": "integer", "description": "Maximum number of runs (default 20)"},
+				},
+			},
+		},
+		{
+			Name:        "gogent_get_run",
+			Description: "Show the responses of each variation of an execution run",
+			InputSchema: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"id": map[string]interface{}{"type": "string", "description": "Execution run ID"}},
+				"required":   []string{"id"},
+			},
+		},
+	}
+}
@@ -0,0 +1,466 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"gogent/internal/auth"
+	"gogent/internal/gogent"
+	"gogent/internal/openapi"
+	"gogent/internal/types"
+)
+
+// apiAccess is who may call an operation
+type apiAccess int
+
+const (
+	apiPublic    apiAccess = iota
+	apiProtected           // Any signed-in user or API key
+	apiAdmin               // Admins only
+)
+
+// apiOperation documents one REST operation. Bodies are given as zero values of the types the
+// handler decodes and encodes, and their schemas are derived from those types.
+type apiOperation struct {
+	method   string
+	path     string
+	tag      string
+	summary  string
+	access   apiAccess
+	query    map[string]string // Query parameters and their descriptions
+	request  interface{}       // JSON request body; nil when the operation takes none
+	response interface{}       // JSON response body; nil when the response has none
+	status   int               // Success status; 200 when zero
+}
+
+// apiPage are the query parameters of paged lists
+var apiPage = map[string]string{
+	"limit":  "Items per page",
+	"offset": "Items to skip",
+	"cursor": "Cursor of the page to continue from, as returned in the X-Next-Cursor header",
+}
+
+// withPage adds the paging parameters to a list's own query parameters
+func withPage(query map[string]string) map[string]string {
+	merged := make(map[string]string, len(query)+len(apiPage))
+	for name, description := range apiPage {
+		merged[name] = description
+	}
+	for name, description := range query {
+		merged[name] = description
+	}
+	return merged
+}
+
+// Response bodies the handlers build inline
+type (
+	apiMessage struct {
+		Message string `json:"message"`
+		ID      string `json:"id,omitempty"`
+	}
+	apiSubmission struct {
+		ExecutionRun struct {
+			ID     string `json:"id"`
+			Name   string `json:"name"`
+			Status string `json:"status"`
+		} `json:"executionRun"`
+		QueuePosition int                     `json:"queuePosition,omitempty"`
+		Merged        bool                    `json:"merged,omitempty"`
+		Message       string                  `json:"message"`
+		Payloads      []types.ProviderPayload `json:"payloads,omitempty"` // Set instead of queueing by a dry run
+	}
+	apiExecutionStatus struct {
+		Status        string                 `json:"status"`
+		QueuePosition int                    `json:"queuePosition,omitempty"`
+		Error         string                 `json:"error,omitempty"`
+		Result        *types.ExecutionResult `json:"result,omitempty"`
+	}
+	apiFunctionResponse struct {
+		Success bool                      `json:"success"`
+		Data    *types.FunctionDefinition `json:"data"`
+		Message string                    `json:"message,omitempty"`
+	}
+	apiFunctionList struct {
+		Success    bool                        `json:"success"`
+		Data       []*types.FunctionDefinition `json:"data"`
+		TotalCount int64                       `json:"totalCount"`
+		NextCursor string                      `json:"nextCursor,omitempty"`
+	}
+	apiTableData struct {
+		TableName  string          `json:"tableName"`
+		Columns    []string        `json:"columns"`
+		Rows       [][]interface{} `json:"rows"`
+		TotalRows  int64           `json:"totalRows"`
+		TotalCount int64           `json:"totalCount"`
+		NextCursor string          `json:"nextCursor,omitempty"`
+	}
+)
+
+// apiOperations documents every route runServer registers, except the dashboard and the
+// optional GraphQL endpoint
+var apiOperations = []apiOperation{
+	{method: "GET", path: "/health", tag: "Health", summary: "Health of the service and its dependencies", response: types.HealthReport{}},
+	{method: "GET", path: "/test", tag: "Health", summary: "Connection test", response: map[string]interface{}{}},
+	{method: "GET", path: "/api/openapi.json", tag: "Health", summary: "This OpenAPI document", response: map[string]interface{}{}},
+
+	{method: "POST", path: "/api/auth/register", tag: "Auth", summary: "Register a user", request: auth.RegisterRequest{}, response: auth.RegisterResponse{}},
+	{method: "POST", path: "/api/auth/login", tag: "Auth", summary: "Sign in", request: auth.LoginRequest{}, response: auth.LoginResponse{}},
+	{method: "POST", path: "/api/auth/temp-user", tag: "Auth", summary: "Create a temporary user", request: auth.CreateTemporaryUserRequest{}, response: auth.CreateTemporaryUserResponse{}},
+	{method: "POST", path: "/api/auth/verify-email", tag: "Auth", summary: "Verify an email address", request: auth.VerifyEmailRequest{}, response: auth.VerifyEmailResponse{}},
+	{method: "POST", path: "/api/auth/refresh", tag: "Auth", summary: "Exchange a refresh token for a new access token", request: auth.RefreshTokenRequest{}, response: auth.LoginResponse{}},
+	{method: "POST", path: "/api/auth/logout", tag: "Auth", summary: "Revoke the current session, or every session", request: auth.LogoutRequest{}, response: map[string]interface{}{}},
+	{method: "POST", path: "/api/auth/password-reset/request", tag: "Auth", summary: "Email a password reset link", request: auth.PasswordResetRequest{}, response: map[string]interface{}{}},
+	{method: "POST", path: "/api/auth/password-reset/confirm", tag: "Auth", summary: "Set a new password with a reset token", request: auth.ResetPasswordRequest{}, response: auth.GetCurrentUserResponse{}},
+	{method: "GET", path: "/api/auth/current", tag: "Auth", summary: "The signed-in user", access: apiProtected, response: auth.GetCurrentUserResponse{}},
+	{method: "POST", path: "/api/auth/save-temp", tag: "Auth", summary: "Keep a temporary account by adding an email", access: apiProtected, request: auth.SaveTemporaryAccountRequest{}, response: auth.SaveTemporaryAccountResponse{}},
+	{method: "POST", path: "/api/auth/connect-temp-account", tag: "Auth", summary: "Connect a temporary account to an email and password", access: apiProtected, request: auth.ConnectTemporaryAccountRequest{}, response: auth.ConnectTemporaryAccountResponse{}},
+	{method: "GET", path: "/api/auth/api-keys", tag: "Auth", summary: "List API keys", access: apiProtected, response: auth.ListAPIKeysResponse{}},
+	{method: "POST", path: "/api/auth/api-keys", tag: "Auth", summary: "Create an API key; the key is only returned here", access: apiProtected, request: auth.CreateAPIKeyRequest{}, response: auth.CreateAPIKeyResponse{}, status: http.StatusCreated},
+	{method: "DELETE", path: "/api/auth/api-keys/{id}", tag: "Auth", summary: "Revoke an API key", access: apiProtected, response: map[string]interface{}{}},
+
+	{method: "POST", path: "/api/execute", tag: "Executions", summary: "Queue a multi-variation execution, or build its provider requests with dryRun", access: apiProtected, request: types.MultiExecutionRequest{}, response: apiSubmission{}},
+	{method: "POST", path: "/api/execute/spec", tag: "Executions", summary: "Queue a run spec, sent as YAML or JSON", access: apiProtected, request: types.RunSpec{}, response: apiSubmission{}},
+	{method: "POST", path: "/api/execute/estimate", tag: "Executions", summary: "Estimate a request's input tokens and maximum cost without running it", access: apiProtected, request: types.MultiExecutionRequest{}, response: types.ExecutionEstimate{}},
+	{method: "GET", path: "/api/execution-runs/status/{id}", tag: "Executions", summary: "Progress of a queued execution, with its result once completed", access: apiProtected, response: apiExecutionStatus{}},
+	{method: "GET", path: "/api/execution-runs", tag: "Executions", summary: "Execution history, newest first", access: apiProtected, query: apiPage, response: []types.ExecutionRun{}},
+	{method: "GET", path: "/api/execution-runs/{id}", tag: "Executions", summary: "A run with its variation results", access: apiProtected, response: types.ExecutionResult{}},
+	{method: "DELETE", path: "/api/execution-runs/{id}", tag: "Executions", summary: "Delete a run and its records", access: apiProtected, response: map[string]interface{}{}},
+	{method: "GET", path: "/api/execution-runs/{id}/comparison", tag: "Executions", summary: "Comparison of a run's configurations", access: apiProtected, response: types.ComparisonResult{}},
+	{method: "GET", path: "/api/execution-runs/{id}/logs", tag: "Executions", summary: "Execution logs of a run", access: apiProtected,
+		query: map[string]string{"level": "Lowest level to return", "category": "Only logs of this category", "after": "Continue after this log entry ID", "limit": "Most logs to return"}, response: []types.ExecutionLog{}},
+	{method: "GET", path: "/api/execution-runs/{id}/diff", tag: "Executions", summary: "Word-level diff and metric deltas of two configurations", access: apiProtected,
+		query: map[string]string{"a": "First configuration ID or variation name", "b": "Second configuration ID or variation name"}, response: types.VariationDiff{}},
+	{method: "GET", path: "/api/execution-runs/{id}/raw-payloads", tag: "Executions", summary: "Provider request and response bodies stored for a run submitted with storeRawPayloads", access: apiProtected,
+		query: map[string]string{"requestId": "Only this request's exchanges"}, response: []types.RawPayload{}},
+	{method: "GET", path: "/api/execution-runs/{id}/feedback", tag: "Executions", summary: "Feedback on a run's responses and its aggregate per configuration", access: apiProtected, response: types.RunFeedback{}},
+	{method: "PUT", path: "/api/execution-runs/{id}/feedback", tag: "Executions", summary: "Rate a response of a run", access: apiProtected, request: types.ResponseFeedback{}, response: types.ResponseFeedback{}},
+	{method: "DELETE", path: "/api/execution-runs/{id}/feedback", tag: "Executions", summary: "Remove your feedback on a response", access: apiProtected, query: map[string]string{"responseId": "Response the feedback is on"}, response: apiMessage{}},
+	{method: "POST", path: "/api/execution-runs/{id}/reviews", tag: "Reviews", summary: "Start a blind review of a run's responses", access: apiProtected, request: types.ReviewRequest{}, response: types.Review{}, status: http.StatusCreated},
+	{method: "POST", path: "/api/execution-runs/{id}/replay", tag: "Executions", summary: "Replay a run with its recorded function responses", access: apiProtected, response: types.ExecutionResult{}},
+	{method: "POST", path: "/api/execution-runs/{id}/clone", tag: "Executions", summary: "Rebuild a run's request for editing and resubmitting", access: apiProtected, response: types.MultiExecutionRequest{}},
+	{method: "GET", path: "/api/comparisons", tag: "Executions", summary: "Comparisons of your runs, newest first", access: apiProtected, query: apiPage, response: []types.ComparisonResult{}},
+
+	{method: "GET", path: "/api/functions", tag: "Functions", summary: "List function definitions", access: apiProtected, query: apiPage, response: apiFunctionList{}},
+	{method: "POST", path: "/api/functions", tag: "Functions", summary: "Create a function definition", access: apiProtected, request: types.FunctionDefinition{}, response: apiFunctionResponse{}, status: http.StatusCreated},
+	{method: "GET", path: "/api/functions/{id}", tag: "Functions", summary: "Get a function definition", access: apiProtected, response: apiFunctionResponse{}},
+	{method: "PUT", path: "/api/functions/{id}", tag: "Functions", summary: "Update a function definition", access: apiProtected, request: types.FunctionDefinition{}, response: apiFunctionResponse{}},
+	{method: "DELETE", path: "/api/functions/{id}", tag: "Functions", summary: "Delete a function definition", access: apiProtected, response: apiFunctionResponse{}},
+	{method: "POST", path: "/api/functions/test/{id}", tag: "Functions", summary: "Call a function with test arguments", access: apiProtected, request: struct {
+		Arguments   map[string]interface{} `json:"arguments"`
+		UseMockData bool                   `json:"useMockData"`
+		TimeoutMs   int32                  `json:"timeoutMs,omitempty"`
+	}{}, response: types.FunctionTestResult{}},
+	{method: "GET", path: "/api/functions/templates", tag: "Functions", summary: "Ready-made functions to copy", access: apiProtected, response: struct {
+		Success bool                      `json:"success"`
+		Data    []*types.FunctionTemplate `json:"data"`
+	}{}},
+	{method: "GET", path: "/api/functions/templates/{id}", tag: "Functions", summary: "Get a function template", access: apiProtected, response: struct {
+		Success bool                    `json:"success"`
+		Data    *types.FunctionTemplate `json:"data"`
+	}{}},
+	{method: "POST", path: "/api/functions/templates/{id}/copy", tag: "Functions", summary: "Copy a template into your functions", access: apiProtected, request: struct {
+		Name string `json:"name,omitempty"`
+	}{}, response: apiFunctionResponse{}, status: http.StatusCreated},
+	{method: "POST", path: "/api/functions/import/openapi", tag: "Functions", summary: "Create functions from an OpenAPI 3 spec", access: apiProtected, request: types.OpenAPIImportRequest{}, response: struct {
+		Success bool                       `json:"success"`
+		Data    *types.OpenAPIImportResult `json:"data"`
+	}{}, status: http.StatusCreated},
+
+	{method: "GET", path: "/api/configurations", tag: "Configurations", summary: "List configuration presets", access: apiProtected, query: apiPage, response: []types.ConfigurationPreset{}},
+	{method: "POST", path: "/api/configurations", tag: "Configurations", summary: "Create a configuration preset", access: apiProtected, request: types.ConfigurationPreset{}, response: types.ConfigurationPreset{}, status: http.StatusCreated},
+	{method: "GET", path: "/api/configurations/{id}", tag: "Configurations", summary: "Get a configuration preset", access: apiProtected, response: types.ConfigurationPreset{}},
+	{method: "PUT", path: "/api/configurations/{id}", tag: "Configurations", summary: "Update a configuration preset", access: apiProtected, request: types.ConfigurationPreset{}, response: types.ConfigurationPreset{}},
+	{method: "DELETE", path: "/api/configurations/{id}", tag: "Configurations", summary: "Delete a configuration preset", access: apiProtected, response: apiMessage{}},
+	{method: "GET", path: "/api/prompts", tag: "Prompts", summary: "List saved prompts", access: apiProtected, query: apiPage, response: []types.Prompt{}},
+	{method: "POST", path: "/api/prompts", tag: "Prompts", summary: "Save a prompt", access: apiProtected, request: types.Prompt{}, response: types.Prompt{}, status: http.StatusCreated},
+	{method: "GET", path: "/api/prompts/{id}", tag: "Prompts", summary: "Get a saved prompt", access: apiProtected, response: types.Prompt{}},
+	{method: "PUT", path: "/api/prompts/{id}", tag: "Prompts", summary: "Update a saved prompt", access: apiProtected, request: types.Prompt{}, response: types.Prompt{}},
+	{method: "DELETE", path: "/api/prompts/{id}", tag: "Prompts", summary: "Delete a saved prompt", access: apiProtected, response: apiMessage{}},
+
+	{method: "GET", path: "/api/slos", tag: "SLOs", summary: "SLO statuses and burn rates", access: apiProtected, query: map[string]string{"alerting": "Only SLOs that are alerting, when true"}, response: struct {
+		SLOs  []*types.SLOStatus `json:"slos"`
+		Count int                `json:"count"`
+	}{}},
+	{method: "PUT", path: "/api/slos", tag: "SLOs", summary: "Create or update a suite's SLO", access: apiProtected, request: types.SuiteSLO{}, response: types.SuiteSLO{}},
+	{method: "GET", path: "/api/slos/{suite}", tag: "SLOs", summary: "SLO status of a suite", access: apiProtected, response: types.SLOStatus{}},
+	{method: "DELETE", path: "/api/slos/{suite}", tag: "SLOs", summary: "Delete a suite's SLO", access: apiProtected, response: struct {
+		Message string `json:"message"`
+		Suite   string `json:"suite"`
+	}{}},
+
+	{method: "GET", path: "/api/trends", tag: "Analytics", summary: "Overall score, latency and cost of a configuration across runs", access: apiProtected,
+		query: map[string]string{"preset": "Configuration preset ID", "variation": "Variation name", "since": "Only runs after this time (RFC 3339)", "limit": "Most runs to include"}, response: types.ConfigurationTrend{}},
+	{method: "GET", path: "/api/leaderboard", tag: "Analytics", summary: "Configurations ranked by average overall score across tagged runs", access: apiProtected,
+		query: map[string]string{"tag": "Run tag", "minSamples": "Fewest runs a configuration needs to be ranked", "limit": "Most configurations to rank"}, response: types.Leaderboard{}},
+	{method: "GET", path: "/api/models", tag: "Analytics", summary: "Model catalog", access: apiProtected,
+		query: map[string]string{"method": "Only models supporting this method, e.g. generateContent", "refresh": "Refetch the catalog, when true"}, response: struct {
+			Models []types.ModelInfo `json:"models"`
+			Count  int               `json:"count"`
+		}{}},
+	{method: "GET", path: "/api/quota", tag: "Analytics", summary: "Quota limits, executions in flight and tokens used today", access: apiProtected, response: types.QuotaStatus{}},
+	{method: "GET", path: "/api/usage", tag: "Analytics", summary: "Token usage and estimated cost", access: apiProtected,
+		query: map[string]string{"from": "First day (YYYY-MM-DD)", "to": "Last day (YYYY-MM-DD)", "groupBy": "day or model"}, response: types.UsageReport{}},
+	{method: "GET", path: "/api/search", tag: "Analytics", summary: "Semantic search over past prompts and responses", access: apiProtected,
+		query: map[string]string{"q": "Search text", "limit": "Most results to return"}, response: struct {
+			Query   string               `json:"query"`
+			Results []types.SearchResult `json:"results"`
+			Count   int                  `json:"count"`
+		}{}},
+
+	{method: "GET", path: "/api/reviews", tag: "Reviews", summary: "Reviews of your runs and reviews assigned to you", access: apiProtected, response: struct {
+		Reviews  []types.Review     `json:"reviews"`
+		Assigned []types.ReviewTask `json:"assigned"`
+	}{}},
+	{method: "GET", path: "/api/reviews/{id}", tag: "Reviews", summary: "Progress of a review of your run", access: apiProtected, response: types.Review{}},
+	{method: "GET", path: "/api/reviews/{id}/task", tag: "Reviews", summary: "A review assigned to you, configurations hidden", access: apiProtected, response: types.ReviewTask{}},
+	{method: "PUT", path: "/api/reviews/{id}/judgments", tag: "Reviews", summary: "Submit your ranks or labels", access: apiProtected, request: struct {
+		Judgments []types.ReviewJudgment `json:"judgments"`
+	}{}, response: types.ReviewTask{}},
+	{method: "POST", path: "/api/reviews/{id}/close", tag: "Reviews", summary: "Close a review before every reviewer has submitted", access: apiProtected, response: types.Review{}},
+	{method: "GET", path: "/api/reviews/{id}/results", tag: "Reviews", summary: "Reveal a finished review's configurations and agreement", access: apiProtected, response: types.ReviewResults{}},
+
+	{method: "GET", path: "/api/workspaces", tag: "Workspaces", summary: "Your workspaces", access: apiProtected, response: []types.Workspace{}},
+	{method: "POST", path: "/api/workspaces", tag: "Workspaces", summary: "Create a workspace", access: apiProtected, request: struct {
+		Name string `json:"name"`
+	}{}, response: types.Workspace{}, status: http.StatusCreated},
+	{method: "GET", path: "/api/workspaces/{id}", tag: "Workspaces", summary: "A workspace and its members", access: apiProtected, response: types.Workspace{}},
+	{method: "PUT", path: "/api/workspaces/{id}/members", tag: "Workspaces", summary: "Add a member or change their role", access: apiProtected, request: struct {
+		Username string `json:"username"`
+		Role     string `json:"role"`
+	}{}, response: types.WorkspaceMember{}},
+	{method: "DELETE", path: "/api/workspaces/{id}/members/{username}", tag: "Workspaces", summary: "Remove a member or leave", access: apiProtected, status: http.StatusNoContent},
+	{method: "GET", path: "/api/workspaces/{id}/resources", tag: "Workspaces", summary: "Runs, functions, presets and prompts shared with a workspace", access: apiProtected,
+		query: map[string]string{"type": "Only resources of this type"}, response: []types.SharedResource{}},
+	{method: "POST", path: "/api/workspaces/{id}/resources", tag: "Workspaces", summary: "Share a resource with a workspace", access: apiProtected, request: struct {
+		Type string `json:"type"`
+		ID   string `json:"id"`
+	}{}, response: types.SharedResource{}, status: http.StatusCreated},
+	{method: "GET", path: "/api/workspaces/{id}/resources/{type}/{resourceId}", tag: "Workspaces", summary: "Open a shared run, function, preset or prompt", access: apiProtected, response: map[string]interface{}{}},
+	{method: "DELETE", path: "/api/workspaces/{id}/resources/{type}/{resourceId}", tag: "Workspaces", summary: "Unshare a resource", access: apiProtected, status: http.StatusNoContent},
+
+	{method: "GET", path: "/api/documents", tag: "Documents", summary: "Documents ingested for retrieval", access: apiProtected, query: map[string]string{"collection": "Only documents of this collection"}, response: struct {
+		Documents []*types.Document `json:"documents"`
+		Count     int               `json:"count"`
+	}{}},
+	{method: "POST", path: "/api/documents", tag: "Documents", summary: "Ingest a document, uploaded as multipart/form-data with file, collection, chunkSize, chunkOverlap and embeddingModel fields", access: apiProtected, response: types.Document{}, status: http.StatusCreated},
+	{method: "GET", path: "/api/documents/{id}", tag: "Documents", summary: "Get a document", access: apiProtected, response: types.Document{}},
+	{method: "DELETE", path: "/api/documents/{id}", tag: "Documents", summary: "Delete a document and its chunks", access: apiProtected, response: apiMessage{}},
+
+	{method: "GET", path: "/api/reports", tag: "Reports", summary: "Saved reports", access: apiProtected, response: struct {
+		Reports []*types.SavedReport `json:"reports"`
+	}{}},
+	{method: "GET", path: "/api/reports/{name}", tag: "Reports", summary: "Run a saved report; its parameters are passed as query parameters", access: apiProtected, response: types.ReportResult{}},
+
+	{method: "GET", path: "/api/database/stats", tag: "Database", summary: "Totals of your execution history", access: apiProtected, response: map[string]interface{}{}},
+	{method: "GET", path: "/api/database/tables", tag: "Database", summary: "Tables you can browse", access: apiProtected, response: []string{}},
+	{method: "GET", path: "/api/database/tables/{name}", tag: "Database", summary: "Browse a table's rows", access: apiProtected, query: apiTableQuery, response: apiTableData{}},
+
+	{method: "PUT", path: "/api/admin/users/role", tag: "Admin", summary: "Change a user's role", access: apiAdmin, request: auth.SetUserRoleRequest{}, response: auth.SetUserRoleResponse{}},
+	{method: "GET", path: "/api/admin/execution-runs", tag: "Admin", summary: "All users' execution runs", access: apiAdmin, query: apiPage, response: []types.ExecutionRun{}},
+	{method: "GET", path: "/api/admin/configurations/system", tag: "Admin", summary: "System configurations", access: apiAdmin, response: []types.APIConfiguration{}},
+	{method: "GET", path: "/api/admin/stats", tag: "Admin", summary: "Server-wide statistics", access: apiAdmin, query: map[string]string{"days": "Days of history for the daily series"}, response: map[string]interface{}{}},
+	{method: "GET", path: "/api/admin/audit-logs", tag: "Admin", summary: "Audit log of user actions", access: apiAdmin,
+		query: withPage(map[string]string{"actor": "Only events by this user ID", "action": "Only events of this action", "since": "Only events after this time (RFC 3339)", "until": "Only events before this time (RFC 3339)"}), response: []types.AuditEvent{}},
+	{method: "GET", path: "/api/admin/database/tables/{name}", tag: "Admin", summary: "Browse a table's rows across all users", access: apiAdmin, query: apiTableQuery, response: apiTableData{}},
+	{method: "GET", path: "/api/admin/workspace-settings", tag: "Admin", summary: "Workspace defaults", access: apiAdmin, response: types.WorkspaceSettings{}},
+	{method: "PUT", path: "/api/admin/workspace-settings", tag: "Admin", summary: "Update workspace defaults", access: apiAdmin, request: types.WorkspaceSettings{}, response: types.WorkspaceSettings{}},
+	{method: "GET", path: "/api/admin/retention-policies", tag: "Admin", summary: "Per-user retention policies", access: apiAdmin, response: struct {
+		Policies []types.UserRetentionPolicy `json:"policies"`
+	}{}},
+	{method: "PUT", path: "/api/admin/retention-policies/{userId}", tag: "Admin", summary: "Set a user's retention policy", access: apiAdmin, request: types.RetentionPolicy{}, response: types.UserRetentionPolicy{}},
+	{method: "DELETE", path: "/api/admin/retention-policies/{userId}", tag: "Admin", summary: "Return a user to the workspace policy", access: apiAdmin, status: http.StatusNoContent},
+	{method: "GET", path: "/api/admin/reports", tag: "Admin", summary: "Saved reports", access: apiAdmin, response: struct {
+		Reports []*types.SavedReport `json:"reports"`
+	}{}},
+	{method: "PUT", path: "/api/admin/reports/{name}", tag: "Admin", summary: "Save a report", access: apiAdmin, request: types.SavedReport{}, response: types.SavedReport{}},
+	{method: "DELETE", path: "/api/admin/reports/{name}", tag: "Admin", summary: "Delete a report", access: apiAdmin, status: http.StatusNoContent},
+	{method: "GET", path: "/api/admin/telemetry", tag: "Admin", summary: "Preview the anonymized telemetry report and whether it is sent", access: apiAdmin, response: telemetryPreview{}},
+}
+
+// apiTableQuery are the query parameters of table browsing
+var apiTableQuery = withPage(map[string]string{
+	"columns": "Comma-separated columns to return",
+	"filter":  "column:operator[:value]; repeat for several filters",
+	"sort":    "Column to sort by, descending with a leading -",
+})
+
+// openAPIDocument builds the OpenAPI document of the REST API
+func openAPIDocument() *openapi.Document {
+	generator := openapi.NewGenerator()
+	doc := &openapi.Document{
+		OpenAPI: openapi.Version,
+		Info: openapi.Info{
+			Title:       "GoGent API",
+			Description: "Run prompts across model configurations, compare the results and manage the functions models may call.",
+			Version:     gogent.Version,
+		},
+		Paths: make(map[string]openapi.PathItem),
+		Components: openapi.Components{SecuritySchemes: map[string]*openapi.SecurityScheme{
+			"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT", Description: "Access token from /api/auth/login"},
+			"apiKey":     {Type: "apiKey", In: "header", Name: "Authorization", Description: "API key sent as \"ApiKey <key>\""},
+		}},
+	}
+
+	seenTags := make(map[string]bool)
+	for _, op := range apiOperations {
+		if !seenTags[op.tag] {
+			seenTags[op.tag] = true
+			doc.Tags = append(doc.Tags, openapi.Tag{Name: op.tag})
+		}
+
+		operation := &openapi.Operation{
+			OperationID: apiOperationID(op.method, op.path),
+			Summary:     op.summary,
+			Tags:        []string{op.tag},
+			Parameters:  openapi.PathParameters(op.path),
+			Responses:   make(map[string]*openapi.Response),
+		}
+		names := make([]string, 0, len(op.query))
+		for name := range op.query {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			operation.Parameters = append(operation.Parameters, openapi.Parameter{
+				Name: name, In: "query", Description: op.query[name], Schema: &openapi.Schema{Type: "string"},
+			})
+		}
+
+		if op.request != nil {
+			operation.RequestBody = &openapi.RequestBody{Required: true, Content: map[string]openapi.MediaType{
+				"application/json": {Schema: generator.SchemaOf(op.request)},
+			}}
+		}
+		status := op.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		success := &openapi.Response{Description: http.StatusText(status)}
+		if op.response != nil {
+			success.Content = map[string]openapi.MediaType{"application/json": {Schema: generator.SchemaOf(op.response)}}
+		}
+		operation.Responses[fmt.Sprint(status)] = success
+		operation.Responses["default"] = &openapi.Response{
+			Description: "Error, as plain text",
+			Content:     map[string]openapi.MediaType{"text/plain": {Schema: &openapi.Schema{Type: "string"}}},
+		}
+
+		switch op.access {
+		case apiProtected, apiAdmin:
+			operation.Security = []map[string][]string{{"bearerAuth": {}}, {"apiKey": {}}}
+			operation.Responses["401"] = &openapi.Response{Description: "Missing or invalid credentials"}
+			if op.access == apiAdmin {
+				operation.Responses["403"] = &openapi.Response{Description: "The caller is not an admin"}
+			}
+		}
+
+		item, ok := doc.Paths[op.path]
+		if !ok {
+			item = make(openapi.PathItem)
+			doc.Paths[op.path] = item
+		}
+		item[strings.ToLower(op.method)] = operation
+	}
+	doc.Components.Schemas = generator.Schemas()
+	return doc
+}
+
+// apiOperationID names an operation for generated clients, e.g. GET /api/execution-runs/{id}/logs
+// becomes getExecutionRunsByIdLogs
+func apiOperationID(method, path string) string {
+	id := strings.ToLower(method)
+	for _, segment := range strings.Split(strings.TrimPrefix(path, "/api"), "/") {
+		if parameter, ok := strings.CutPrefix(segment, "{"); ok {
+			segment = "by-" + strings.TrimSuffix(parameter, "}")
+		}
+		for _, word := range strings.FieldsFunc(segment, func(r rune) bool { return r == '-' || r == '.' || r == '_' }) {
+			id += strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return id
+}
+
+// openAPIHandler serves the OpenAPI document of the REST API, with the server the request reached
+func (s *Server) openAPIHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	doc := openAPIDocument()
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	doc.Servers = []openapi.Server{{URL: scheme + "://" + r.Host}}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(doc)
+}
+
+// swaggerUIPage renders /api/openapi.json with Swagger UI, loaded from a CDN
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>GoGent API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/api/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
+
+// swaggerUIHandler serves Swagger UI for the OpenAPI document
+func (s *Server) swaggerUIHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, swaggerUIPage)
+}
+
+// openAPICommand writes the OpenAPI document without starting the server, for generating clients.
+// --tags keeps the operations of some tags, e.g. --tags Executions,Functions.
+func openAPICommand(args []string) error {
+	fs := flag.NewFlagSet("openapi", flag.ContinueOnError)
+	output := fs.String("o", "", "output file (default stdout)")
+	tags := fs.String("tags", "", "comma-separated tags of the operations to keep (default all)")
+	serverURL := fs.String("server-url", "http://localhost:8080", "base URL of the server clients call by default")
+	if _, err := parseCommandFlags(fs, args); err != nil {
+		return err
+	}
+
+	doc := openAPIDocument()
+	if *tags != "" {
+		doc = doc.WithTags(strings.Split(*tags, ",")...)
+		if len(doc.Paths) == 0 {
+			return fmt.Errorf("no operations are tagged %s", *tags)
+		}
+	}
+	doc.Servers = []openapi.Server{{URL: *serverURL}}
+
+	var out io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("failed to create OpenAPI file: %w", err)
+		}
+		defer file.Close()
+		out = file
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
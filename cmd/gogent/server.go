@@ -4,7 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
@@ -15,6 +17,8 @@ import (
 
 	"gogent/internal/auth"
 	"gogent/internal/gogent"
+	"gogent/internal/graphql"
+	"gogent/internal/ollama"
 	"gogent/internal/types"
 
 	_ "github.com/go-sql-driver/mysql"
@@ -27,18 +31,39 @@ type Server struct {
 	config         *types.GeminiClientConfig
 	executions     map[string]*ExecutionStatus
 	executionMutex sync.RWMutex
+	queue          *gogent.ExecutionQueue
+	runCtx         context.Context // Canceled when shutdown gives up waiting for executions
+	cancelRuns     context.CancelFunc
 	authService    *auth.AuthService
 	authHandlers   *auth.AuthHandlers
+	graphql        *graphql.Schema // Set when GRAPHQL_ENABLED is true
+	telemetry      telemetrySettings
+	trustedProxies trustedProxies
 }
 
 // ExecutionStatus tracks the status of an async execution
 type ExecutionStatus struct {
 	ID                 string     `json:"id"`
 	RealExecutionRunID string     `json:"realExecutionRunId,omitempty"` // The actual UUID from database
+	UserID             string     `json:"-"`                            // Owner of the execution
 	Status             string     `json:"status"`                       // pending, running, completed, failed
 	ErrorMessage       string     `json:"errorMessage,omitempty"`
 	StartTime          time.Time  `json:"startTime"`
 	EndTime            *time.Time `json:"endTime,omitempty"`
+
+	// Set once the run is created, and updated as each variation finishes
+	Progress *types.ExecutionProgress `json:"progress,omitempty"`
+}
+
+// activeExecutions counts the user's pending and running executions; callers hold the executions lock
+func activeExecutions(executions map[string]*ExecutionStatus, userID string) int {
+	active := 0
+	for _, execution := range executions {
+		if execution.UserID == userID && (execution.Status == "pending" || execution.Status == "running") {
+			active++
+		}
+	}
+	return active
 }
 
 // NewServer creates a new HTTP server
@@ -59,9 +84,30 @@ func NewServer() (*Server, error) {
 
 	// Create Gemini client configuration
 	config := &types.GeminiClientConfig{
-		APIKey:      apiKey,
-		MaxRetries:  3,
-		TimeoutSecs: 30,
+		APIKey:            apiKey,
+		ProjectID:         os.Getenv("GOOGLE_CLOUD_PROJECT"),
+		Region:            os.Getenv("GOOGLE_CLOUD_LOCATION"),
+		OllamaURL:         envOrDefault("OLLAMA_URL", ollama.DefaultBaseURL),
+		WebSearchProvider: os.Getenv("WEB_SEARCH_PROVIDER"),
+		WebSearchAPIKey:   os.Getenv("WEB_SEARCH_API_KEY"),
+		SQLQueryDriver:    os.Getenv("QUERY_SQL_DRIVER"),
+		SQLQueryDSN:       os.Getenv("QUERY_SQL_DSN"),
+		MaxRetries:        3,
+		TimeoutSecs:       30,
+	}
+	redaction, err := loadRedactionPolicy()
+	if err != nil {
+		return nil, err
+	}
+	config.Redaction = redaction
+	minLogLevel, err := loadMinStoredLogLevel()
+	if err != nil {
+		return nil, err
+	}
+	config.MinStoredLogLevel = minLogLevel
+	config.AllowPrivateFunctionEndpoints = os.Getenv("ALLOW_PRIVATE_FUNCTION_ENDPOINTS") == "true"
+	if err := loadCircuitBreakerSettings(config); err != nil {
+		return nil, err
 	}
 
 	// Create gogent client
@@ -72,15 +118,49 @@ func NewServer() (*Server, error) {
 
 	// Create auth service and handlers
 	authService := auth.NewAuthService(client.GetDB(), jwtSecret)
+	if err := configurePasswordReset(authService); err != nil {
+		client.Close()
+		return nil, err
+	}
 	authHandlers := auth.NewAuthHandlers(authService)
 
-	return &Server{
-		client:       client,
-		config:       config,
-		executions:   make(map[string]*ExecutionStatus),
-		authService:  authService,
-		authHandlers: authHandlers,
-	}, nil
+	proxies, err := loadTrustedProxies()
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	queue, err := newExecutionQueueFromEnv()
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	runCtx, cancelRuns := context.WithCancel(context.Background())
+	server := &Server{
+		client:         client,
+		config:         config,
+		executions:     make(map[string]*ExecutionStatus),
+		queue:          queue,
+		runCtx:         runCtx,
+		cancelRuns:     cancelRuns,
+		authService:    authService,
+		authHandlers:   authHandlers,
+		trustedProxies: proxies,
+	}
+	authHandlers.SetAuditFunc(server.audit)
+	return server, nil
+}
+
+// Shutdown stops accepting executions and waits for queued and running ones to finish. Executions
+// still unfinished when ctx ends are interrupted and marked failed.
+func (s *Server) Shutdown(ctx context.Context) error {
+	err := drainExecutions(ctx, s.queue, s.cancelRuns)
+
+	s.executionMutex.Lock()
+	failUnfinishedExecutions(s.executions, "server shut down before the execution finished")
+	s.executionMutex.Unlock()
+	return err
 }
 
 // Close closes the server resources
@@ -91,18 +171,31 @@ func (s *Server) Close() error {
 	return nil
 }
 
-// Health check endpoint
-func (s *Server) healthHandler(w http.ResponseWriter, r *http.Request) {
-	response := map[string]interface{}{
-		"status":     "ok",
-		"version":    "1.0.0",
-		"timestamp":  time.Now().Format(time.RFC3339),
-		"database":   s.client != nil,
-		"gemini_api": s.config.APIKey != "",
+// writeValidationError responds 400 with the per-field errors of a *gogent.ValidationError
+func writeValidationError(w http.ResponseWriter, err error) {
+	var validation *gogent.ValidationError
+	if !errors.As(err, &validation) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":       validation.Error(),
+		"fieldErrors": validation.Errors,
+	})
+}
+
+// Health check endpoint: checks the database, migrations, the model provider, the circuit breakers,
+// Neo4j and the execution queue, and answers 503 when the service is unhealthy
+func (s *Server) healthHandler(w http.ResponseWriter, r *http.Request) {
+	report := s.client.CheckHealth(r.Context(), s.queue)
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	if report.Status == types.HealthUnhealthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
 }
 
 // Test connection endpoint
@@ -136,6 +229,175 @@ func (s *Server) executeHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
 		return
 	}
+	s.submitExecution(w, r, userID, &request)
+}
+
+// Execute a run spec endpoint (async); the body is a YAML or JSON run spec
+func (s *Server) executeSpecHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to read request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	spec, err := gogent.ParseRunSpec(body)
+	if err != nil {
+		writeValidationError(w, err)
+		return
+	}
+	if spec.Optimize != nil {
+		http.Error(w, "Optimization runs with the gogent optimize command; remove the optimize block to run the sweep", http.StatusBadRequest)
+		return
+	}
+	s.submitExecution(w, r, userID, gogent.RunSpecRequest(spec))
+}
+
+// Estimate execution endpoint; returns the request's input tokens, maximum cost and limit warnings
+// without creating a run
+func (s *Server) estimateExecutionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var request types.MultiExecutionRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// Estimate the configurations that would run: presets applied, the sweep expanded and omitted
+	// values filled from the workspace defaults
+	if err := s.client.ResolveConfigurations(r.Context(), userID, &request); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, gogent.ErrPresetNotFound) || errors.Is(err, gogent.ErrInvalidSweep) {
+			status = http.StatusBadRequest
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	workspaceSettings, err := s.client.GetWorkspaceSettings(r.Context(), types.DefaultWorkspaceID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to load workspace settings: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if err := gogent.ApplyWorkspaceDefaults(&request, workspaceSettings); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	estimate, err := s.client.EstimateExecution(r.Context(), &request)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to estimate execution: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(estimate)
+}
+
+// submitExecution validates a decoded execution request and starts it asynchronously
+func (s *Server) submitExecution(w http.ResponseWriter, r *http.Request, userID string, request *types.MultiExecutionRequest) {
+	// Apply configuration presets and expand the parameter sweep
+	if err := s.client.ResolveConfigurations(r.Context(), userID, request); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, gogent.ErrPresetNotFound) || errors.Is(err, gogent.ErrInvalidSweep) {
+			status = http.StatusBadRequest
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	// Fill omitted values from the workspace defaults
+	workspaceSettings, err := s.client.GetWorkspaceSettings(r.Context(), types.DefaultWorkspaceID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to load workspace settings: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if err := gogent.ApplyWorkspaceDefaults(request, workspaceSettings); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := gogent.ValidateRequestSafetyPolicies(request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := gogent.ValidateRepetitions(request.Repetitions); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := gogent.ValidateExpectedAnswer(request.ExpectedAnswer); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := gogent.ValidatePriority(request.Priority); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := s.client.ValidateRequest(r.Context(), request); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	// Dry runs only build the provider requests, so they skip the queue and answer right away
+	if request.DryRun {
+		result, err := s.client.ExecuteMultiVariation(r.Context(), userID, request)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Dry run failed: %v", err), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	// Apply the duplicate policy and give the run a unique name
+	existingRun, err := s.client.PrepareSubmission(r.Context(), userID, request, gogent.DuplicateWindow(workspaceSettings))
+	if err != nil {
+		var duplicate *gogent.DuplicateSubmissionError
+		if errors.As(err, &duplicate) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to prepare execution: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if existingRun != nil {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"executionRun": map[string]interface{}{
+				"id":     existingRun.ID,
+				"name":   existingRun.Name,
+				"status": existingRun.Status,
+			},
+			"merged":  true,
+			"message": "Identical submission merged into execution run " + existingRun.Name,
+		})
+		return
+	}
+
+	// Today's token usage is loaded before the lock; the concurrency count is taken under it
+	quota, err := s.client.QuotaStatus(r.Context(), userID, workspaceSettings.Quota, 0)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to check quota: %v", err), http.StatusInternalServerError)
+		return
+	}
 
 	// DEBUG: Log what we parsed
 	log.Printf("🔍 DEBUG - Parsed request:")
@@ -151,17 +413,37 @@ func (s *Server) executeHandler(w http.ResponseWriter, r *http.Request) {
 	// Generate execution run ID
 	executionID := fmt.Sprintf("exec-%d", time.Now().UnixNano()/1000000)
 
-	// Track execution status
+	// Enforce the user's quota and track the execution under one lock so concurrent submissions cannot both pass
 	s.executionMutex.Lock()
+	quota.ConcurrentExecutions = activeExecutions(s.executions, userID)
+	if err := gogent.CheckQuota(quota, request); err != nil {
+		s.executionMutex.Unlock()
+		writeQuotaError(w, err, quota)
+		return
+	}
 	s.executions[executionID] = &ExecutionStatus{
 		ID:        executionID,
 		Status:    "pending",
 		StartTime: time.Now(),
+		UserID:    userID,
 	}
 	s.executionMutex.Unlock()
 
-	// Start async execution with user ID
-	go s.runAsyncExecution(executionID, &request, r.Header.Get("X-Use-Mock") == "true", r.Header, userID)
+	// Queue the execution; a worker runs it with the user ID
+	useMock := r.Header.Get("X-Use-Mock") == "true"
+	headers := r.Header.Clone()
+	request.SubmittedAt = time.Now()
+	position, err := s.queue.Enqueue(executionID, request.Priority, func() {
+		s.runAsyncExecution(executionID, request, useMock, headers, userID)
+	})
+	if err != nil {
+		s.executionMutex.Lock()
+		delete(s.executions, executionID)
+		s.executionMutex.Unlock()
+		w.Header().Set("Retry-After", "30")
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
 
 	// Return immediately with execution ID
 	response := map[string]interface{}{
@@ -170,7 +452,8 @@ func (s *Server) executeHandler(w http.ResponseWriter, r *http.Request) {
 			"name":   request.ExecutionRunName,
 			"status": "pending",
 		},
-		"message": "Execution started. Use GET /api/execution-runs/" + executionID + "/status to check progress.",
+		"queuePosition": position,
+		"message":       "Execution queued. Use GET /api/execution-runs/status/" + executionID + " to check progress.",
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -186,7 +469,28 @@ func (s *Server) getUserID(r *http.Request) (string, error) {
 	return user.ID, nil
 }
 
-// runAsyncExecution runs the execution in a goroutine
+// audit records a security-relevant action taken through r, by the authenticated user unless the
+// event names its actor. A failure is logged rather than returned, so the action still succeeds.
+func (s *Server) audit(r *http.Request, event *types.AuditEvent) {
+	if event.ActorID == "" {
+		if user, ok := auth.GetUserFromContext(r.Context()); ok && user != nil {
+			event.ActorID = user.ID
+		}
+	}
+	event.IPAddress = s.requestIP(r)
+	if err := s.client.RecordAuditEvent(r.Context(), event); err != nil {
+		log.Printf("⚠️ Warning: failed to record %s audit event: %v", event.Action, err)
+	}
+}
+
+// requestIP returns the client address of r: the connection's remote host, or the client a
+// trusted proxy forwarded the request for
+func (s *Server) requestIP(r *http.Request) string {
+	forwarded := strings.Join(r.Header.Values("X-Forwarded-For"), ",")
+	return s.trustedProxies.clientIP(r.RemoteAddr, forwarded, s.trustedProxies.trusts(r.RemoteAddr))
+}
+
+// runAsyncExecution runs the execution on a queue worker
 func (s *Server) runAsyncExecution(executionID string, request *types.MultiExecutionRequest, useMock bool, headers http.Header, userID string) {
 	// Update status to running
 	s.executionMutex.Lock()
@@ -221,6 +525,18 @@ func (s *Server) runAsyncExecution(executionID string, request *types.MultiExecu
 		log.Printf("⚠️ No OpenWeather API key provided in headers")
 	}
 
+	// Get the web search API key from headers, falling back to the server's
+	webSearchAPIKey := headers.Get("X-Web-Search-API-Key")
+	if webSearchAPIKey == "" {
+		webSearchAPIKey = s.config.WebSearchAPIKey
+	}
+
+	// Get the query_sql database DSN from headers, falling back to the server's
+	sqlQueryDSN := headers.Get("X-SQL-DSN")
+	if sqlQueryDSN == "" {
+		sqlQueryDSN = s.config.SQLQueryDSN
+	}
+
 	// Get Neo4j configuration from headers
 	neo4jURL := headers.Get("X-Neo4j-URL")
 	neo4jUsername := headers.Get("X-Neo4j-Username")
@@ -238,7 +554,7 @@ func (s *Server) runAsyncExecution(executionID string, request *types.MultiExecu
 		log.Printf("⚠️ No Neo4j configuration provided in headers")
 	}
 
-	ctx := context.Background()
+	ctx := s.runCtx
 	var err error
 	var result *types.ExecutionResult
 
@@ -251,8 +567,13 @@ func (s *Server) runAsyncExecution(executionID string, request *types.MultiExecu
 			Neo4jUsername:     neo4jUsername,
 			Neo4jPassword:     neo4jPassword,
 			Neo4jDatabase:     neo4jDatabase,
+			WebSearchProvider: s.config.WebSearchProvider,
+			WebSearchAPIKey:   webSearchAPIKey,
+			SQLQueryDriver:    s.config.SQLQueryDriver,
+			SQLQueryDSN:       sqlQueryDSN,
 			MaxRetries:        s.config.MaxRetries,
 			TimeoutSecs:       s.config.TimeoutSecs,
+			Redaction:         s.config.Redaction,
 		}
 
 		log.Printf("Creating mock client for execution with logging")
@@ -266,6 +587,7 @@ func (s *Server) runAsyncExecution(executionID string, request *types.MultiExecu
 			return
 		}
 		defer mockClient.Close()
+		mockClient.SetCircuitBreakers(s.client.CircuitBreakers())
 
 		log.Printf("Using mock client with logging enabled")
 		result, err = mockClient.ExecuteMultiVariation(ctx, userID, request)
@@ -283,8 +605,16 @@ func (s *Server) runAsyncExecution(executionID string, request *types.MultiExecu
 			Neo4jUsername:     neo4jUsername,
 			Neo4jPassword:     neo4jPassword,
 			Neo4jDatabase:     neo4jDatabase,
+			ProjectID:         s.config.ProjectID,
+			Region:            s.config.Region,
+			OllamaURL:         s.config.OllamaURL,
+			WebSearchProvider: s.config.WebSearchProvider,
+			WebSearchAPIKey:   webSearchAPIKey,
+			SQLQueryDriver:    s.config.SQLQueryDriver,
+			SQLQueryDSN:       sqlQueryDSN,
 			MaxRetries:        s.config.MaxRetries,
 			TimeoutSecs:       s.config.TimeoutSecs,
+			Redaction:         s.config.Redaction,
 		}
 
 		log.Printf("Creating temporary client with API key")
@@ -298,6 +628,8 @@ func (s *Server) runAsyncExecution(executionID string, request *types.MultiExecu
 			return
 		}
 		defer tempClient.Close()
+		// Hosts that failed in earlier runs stay open
+		tempClient.SetCircuitBreakers(s.client.CircuitBreakers())
 
 		log.Printf("Using temporary client for real API execution")
 		result, err = tempClient.ExecuteMultiVariation(ctx, userID, request)
@@ -448,113 +780,462 @@ func (s *Server) executionStatusHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// For pending/running status, return the status
+	// For pending/running status, return the status and the position of a queued execution
 	response := map[string]interface{}{
 		"status": status.Status,
 	}
+	if position, queued := s.queue.Position(executionID); queued {
+		response["queuePosition"] = position
+	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
-// configurationsHandler handles API configuration requests
-func (s *Server) configurationsHandler(w http.ResponseWriter, r *http.Request) {
+// writeQuotaError responds 429 with the exceeded limit and the user's quota status
+func writeQuotaError(w http.ResponseWriter, err error, quota *types.QuotaStatus) {
+	var quotaErr *gogent.QuotaError
+	if !errors.As(err, &quotaErr) {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if quotaErr.Limit == gogent.QuotaLimitDailyTokens {
+		w.Header().Set("Retry-After", strconv.Itoa(int(time.Until(quotaErr.ResetsAt).Seconds())+1))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusTooManyRequests)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": err.Error(),
+		"limit": quotaErr.Limit,
+		"quota": quota,
+	})
+}
+
+// quotaHandler reports the user's quota limits, executions in flight and tokens used today
+func (s *Server) quotaHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
-
 	userID, err := s.getUserID(r)
 	if err != nil {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
 
-	ctx := context.Background()
-	configs, err := s.client.ListAPIConfigurationsByUser(ctx, userID, 50, 0)
+	settings, err := s.client.GetWorkspaceSettings(r.Context(), types.DefaultWorkspaceID)
 	if err != nil {
-		log.Printf("⚠️ Failed to load user configurations from DB: %v", err)
-		http.Error(w, "Failed to load configurations", http.StatusInternalServerError)
+		http.Error(w, fmt.Sprintf("Failed to load workspace settings: %v", err), http.StatusInternalServerError)
 		return
 	}
+	s.executionMutex.RLock()
+	active := activeExecutions(s.executions, userID)
+	s.executionMutex.RUnlock()
 
+	quota, err := s.client.QuotaStatus(r.Context(), userID, settings.Quota, active)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to load quota: %v", err), http.StatusInternalServerError)
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(configs)
+	json.NewEncoder(w).Encode(quota)
 }
 
-// Mock execution for when API key is not available
-func (s *Server) executeMockVariation(ctx context.Context, request *types.MultiExecutionRequest) *types.ExecutionResult {
-	executionRun := types.ExecutionRun{
-		ID:          fmt.Sprintf("mock-%d", time.Now().UnixNano()%1000000),
-		Name:        request.ExecutionRunName,
-		Description: request.Description,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+// usageHandler reports the user's token usage and estimated cost between ?from= and ?to=, grouped
+// by ?groupBy=day (the default), model or day,model. A to date without a time includes that day.
+func (s *Server) usageHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
 	}
 
-	results := make([]types.VariationResult, 0, len(request.Configurations))
-	startTime := time.Now()
-
-	for i, config := range request.Configurations {
-		// Simulate realistic delay
-		time.Sleep(time.Duration(200+i*50) * time.Millisecond)
-
-		responseText := s.generateMockResponse(request.BasePrompt, config)
-		responseTime := int32(200 + i*50)
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 
-		apiRequest := types.APIRequest{
-			ID:              fmt.Sprintf("req-%d", time.Now().UnixNano()%1000000),
-			ExecutionRunID:  executionRun.ID,
-			ConfigurationID: config.ID,
-			RequestType:     "generate",
-			Prompt:          request.BasePrompt,
-			Context:         request.Context,
-			CreatedAt:       time.Now(),
+	params := r.URL.Query()
+	var query types.UsageQuery
+	if from := params.Get("from"); from != "" {
+		query.From, err = time.Parse(time.RFC3339, from)
+		if err != nil {
+			query.From, err = time.Parse(time.DateOnly, from)
 		}
-
-		apiResponse := types.APIResponse{
-			ID:             fmt.Sprintf("resp-%d", time.Now().UnixNano()%1000000),
-			RequestID:      apiRequest.ID,
-			ResponseStatus: "success",
-			ResponseText:   responseText,
-			FinishReason:   "stop",
-			ResponseTimeMs: responseTime,
-			UsageMetadata: map[string]interface{}{
-				"prompt_tokens":     int32(len(request.BasePrompt) / 4),
-				"completion_tokens": int32(len(responseText) / 4),
-				"total_tokens":      int32((len(request.BasePrompt) + len(responseText)) / 4),
-			},
-			CreatedAt: time.Now(),
+		if err != nil {
+			http.Error(w, "from must be an RFC 3339 time or a YYYY-MM-DD date", http.StatusBadRequest)
+			return
 		}
-
-		variationResult := types.VariationResult{
-			Configuration: config,
-			Request:       apiRequest,
-			Response:      apiResponse,
-			ExecutionTime: int64(responseTime), // Already in milliseconds
+	}
+	if to := params.Get("to"); to != "" {
+		query.To, err = time.Parse(time.RFC3339, to)
+		if err != nil {
+			if query.To, err = time.Parse(time.DateOnly, to); err == nil {
+				query.To = query.To.AddDate(0, 0, 1)
+			}
+		}
+		if err != nil {
+			http.Error(w, "to must be an RFC 3339 time or a YYYY-MM-DD date", http.StatusBadRequest)
+			return
 		}
-
-		results = append(results, variationResult)
 	}
-
-	totalTime := time.Since(startTime)
-
-	result := &types.ExecutionResult{
-		ExecutionRun: executionRun,
-		Results:      results,
-		TotalTime:    totalTime.Milliseconds(),
-		SuccessCount: len(results),
-		ErrorCount:   0,
+	if groupBy := params.Get("groupBy"); groupBy != "" {
+		query.GroupBy = strings.Split(groupBy, ",")
 	}
 
-	// Always perform comparison for better user experience (like real execution)
-	var fastest *types.VariationResult
-	for i := range results {
-		if fastest == nil || results[i].Response.ResponseTimeMs < fastest.Response.ResponseTimeMs {
-			fastest = &results[i]
-		}
+	report, err := s.client.GetUsage(r.Context(), userID, query)
+	if errors.Is(err, gogent.ErrInvalidUsageQuery) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
-
-	if fastest != nil {
+	if err != nil {
+		log.Printf("❌ Failed to get usage: %v", err)
+		http.Error(w, "Failed to get usage", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// configurationsHandler lists and creates the user's configuration presets
+func (s *Server) configurationsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		limit, offset, err := parsePage(r, gogent.MaxPageSize)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		presets, err := s.client.ListConfigurationPresets(r.Context(), userID, limit, offset)
+		if err != nil {
+			log.Printf("❌ Failed to list configuration presets: %v", err)
+			http.Error(w, "Failed to load configurations", http.StatusInternalServerError)
+			return
+		}
+		total, err := s.client.CountConfigurationPresets(r.Context(), userID)
+		if err != nil {
+			log.Printf("❌ Failed to count configuration presets: %v", err)
+			http.Error(w, "Failed to load configurations", http.StatusInternalServerError)
+			return
+		}
+		if presets == nil {
+			presets = []*types.ConfigurationPreset{}
+		}
+
+		writePageHeaders(w, total, gogent.NextCursor(offset, len(presets), total))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(presets)
+
+	case http.MethodPost:
+		var preset types.ConfigurationPreset
+		if err := json.NewDecoder(r.Body).Decode(&preset); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		if err := gogent.ValidateConfigurationPreset(&preset); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		created, err := s.client.CreateConfigurationPreset(r.Context(), userID, &preset)
+		if err != nil {
+			writePresetError(w, "create", err)
+			return
+		}
+		log.Printf("💾 Saved configuration preset %s", created.Name)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(created)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// configurationByIDHandler returns, replaces or deletes one configuration preset
+func (s *Server) configurationByIDHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	presetID := strings.TrimPrefix(r.URL.Path, "/api/configurations/")
+	if presetID == "" {
+		http.Error(w, "Configuration ID required", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		preset, err := s.client.GetConfigurationPreset(r.Context(), userID, presetID)
+		if err != nil {
+			writePresetError(w, "get", err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(preset)
+
+	case http.MethodPut:
+		var preset types.ConfigurationPreset
+		if err := json.NewDecoder(r.Body).Decode(&preset); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		if err := gogent.ValidateConfigurationPreset(&preset); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		updated, err := s.client.UpdateConfigurationPreset(r.Context(), userID, presetID, &preset)
+		if err != nil {
+			writePresetError(w, "update", err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(updated)
+
+	case http.MethodDelete:
+		if err := s.client.DeleteConfigurationPreset(r.Context(), userID, presetID); err != nil {
+			writePresetError(w, "delete", err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"message": "Configuration deleted successfully",
+			"id":      presetID,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// writePresetError responds with the status matching a configuration preset error
+func writePresetError(w http.ResponseWriter, action string, err error) {
+	switch {
+	case errors.Is(err, gogent.ErrPresetNotFound):
+		http.Error(w, "Configuration not found", http.StatusNotFound)
+	case errors.Is(err, gogent.ErrPresetNameTaken):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Printf("❌ Failed to %s configuration preset: %v", action, err)
+		http.Error(w, fmt.Sprintf("Failed to %s configuration", action), http.StatusInternalServerError)
+	}
+}
+
+// promptsHandler lists and creates the user's saved prompts
+func (s *Server) promptsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		limit, offset, err := parsePage(r, gogent.MaxPageSize)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		prompts, err := s.client.ListPrompts(r.Context(), userID, limit, offset)
+		if err != nil {
+			writePromptError(w, "list", err)
+			return
+		}
+		total, err := s.client.CountPrompts(r.Context(), userID)
+		if err != nil {
+			writePromptError(w, "count", err)
+			return
+		}
+
+		writePageHeaders(w, total, gogent.NextCursor(offset, len(prompts), total))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(prompts)
+
+	case http.MethodPost:
+		var prompt types.Prompt
+		if err := json.NewDecoder(r.Body).Decode(&prompt); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		if err := gogent.ValidatePrompt(&prompt); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		created, err := s.client.CreatePrompt(r.Context(), userID, &prompt)
+		if err != nil {
+			writePromptError(w, "create", err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(created)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// promptByIDHandler returns, replaces or deletes one saved prompt
+func (s *Server) promptByIDHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	promptID := strings.TrimPrefix(r.URL.Path, "/api/prompts/")
+	if promptID == "" {
+		http.Error(w, "Prompt ID required", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		prompt, err := s.client.GetPrompt(r.Context(), userID, promptID)
+		if err != nil {
+			writePromptError(w, "get", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(prompt)
+
+	case http.MethodPut:
+		var prompt types.Prompt
+		if err := json.NewDecoder(r.Body).Decode(&prompt); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		if err := gogent.ValidatePrompt(&prompt); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		updated, err := s.client.UpdatePrompt(r.Context(), userID, promptID, &prompt)
+		if err != nil {
+			writePromptError(w, "update", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(updated)
+
+	case http.MethodDelete:
+		if err := s.client.DeletePrompt(r.Context(), userID, promptID); err != nil {
+			writePromptError(w, "delete", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"message": "Prompt deleted successfully",
+			"id":      promptID,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// writePromptError responds with the status matching a saved prompt error
+func writePromptError(w http.ResponseWriter, action string, err error) {
+	switch {
+	case errors.Is(err, gogent.ErrPromptNotFound):
+		http.Error(w, "Prompt not found", http.StatusNotFound)
+	case errors.Is(err, gogent.ErrPromptNameTaken):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Printf("❌ Failed to %s prompts: %v", action, err)
+		http.Error(w, fmt.Sprintf("Failed to %s prompts", action), http.StatusInternalServerError)
+	}
+}
+
+// Mock execution for when API key is not available
+func (s *Server) executeMockVariation(ctx context.Context, request *types.MultiExecutionRequest) *types.ExecutionResult {
+	executionRun := types.ExecutionRun{
+		ID:          fmt.Sprintf("mock-%d", time.Now().UnixNano()%1000000),
+		Name:        request.ExecutionRunName,
+		Description: request.Description,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	results := make([]types.VariationResult, 0, len(request.Configurations))
+	startTime := time.Now()
+
+	for i, config := range request.Configurations {
+		// Simulate realistic delay
+		time.Sleep(time.Duration(200+i*50) * time.Millisecond)
+
+		responseText := s.generateMockResponse(request.BasePrompt, config)
+		responseTime := int32(200 + i*50)
+
+		apiRequest := types.APIRequest{
+			ID:              fmt.Sprintf("req-%d", time.Now().UnixNano()%1000000),
+			ExecutionRunID:  executionRun.ID,
+			ConfigurationID: config.ID,
+			RequestType:     "generate",
+			Prompt:          request.BasePrompt,
+			Context:         request.Context,
+			CreatedAt:       time.Now(),
+		}
+
+		apiResponse := types.APIResponse{
+			ID:             fmt.Sprintf("resp-%d", time.Now().UnixNano()%1000000),
+			RequestID:      apiRequest.ID,
+			ResponseStatus: "success",
+			ResponseText:   responseText,
+			FinishReason:   "stop",
+			ResponseTimeMs: responseTime,
+			UsageMetadata: map[string]interface{}{
+				"prompt_tokens":     int32(len(request.BasePrompt) / 4),
+				"completion_tokens": int32(len(responseText) / 4),
+				"total_tokens":      int32((len(request.BasePrompt) + len(responseText)) / 4),
+			},
+			CreatedAt: time.Now(),
+		}
+
+		variationResult := types.VariationResult{
+			Configuration: config,
+			Request:       apiRequest,
+			Response:      apiResponse,
+			ExecutionTime: int64(responseTime), // Already in milliseconds
+		}
+
+		results = append(results, variationResult)
+	}
+
+	totalTime := time.Since(startTime)
+
+	result := &types.ExecutionResult{
+		ExecutionRun: executionRun,
+		Results:      results,
+		TotalTime:    totalTime.Milliseconds(),
+		SuccessCount: len(results),
+		ErrorCount:   0,
+	}
+
+	// Always perform comparison for better user experience (like real execution)
+	var fastest *types.VariationResult
+	for i := range results {
+		if fastest == nil || results[i].Response.ResponseTimeMs < fastest.Response.ResponseTimeMs {
+			fastest = &results[i]
+		}
+	}
+
+	if fastest != nil {
 		// Store all configurations for reference
 		var allConfigs []types.APIConfiguration
 		for _, r := range results {
@@ -704,15 +1385,345 @@ func (s *Server) deleteExecutionRun(w http.ResponseWriter, r *http.Request, runI
 		return
 	}
 
-	// For now, just return success (no actual deletion in mock mode)
-	response := map[string]string{
-		"message": fmt.Sprintf("Execution run %s deleted successfully", runID),
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
-}
-
+	err = s.client.DeleteExecutionRun(r.Context(), userID, runID)
+	if errors.Is(err, gogent.ErrExecutionRunNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Failed to delete execution run %s: %v", runID, err)
+		http.Error(w, "Failed to delete execution run", http.StatusInternalServerError)
+		return
+	}
+	s.audit(r, &types.AuditEvent{Action: types.AuditRunDeleted, TargetType: "execution_run", TargetID: runID})
+
+	response := map[string]string{
+		"message": fmt.Sprintf("Execution run %s deleted successfully", runID),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// replayExecutionRun re-executes a run with its recorded function responses and returns the new run's result
+func (s *Server) replayExecutionRun(w http.ResponseWriter, r *http.Request, runID string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	result, err := s.client.ReplayExecutionRun(r.Context(), userID, runID)
+	if errors.Is(err, gogent.ErrExecutionRunNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Failed to replay execution run %s: %v", runID, err)
+		http.Error(w, fmt.Sprintf("Failed to replay execution run: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// cloneExecutionRun returns the request that produced a run, linked to the run, for editing and resubmitting
+func (s *Server) cloneExecutionRun(w http.ResponseWriter, r *http.Request, runID string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	request, err := s.client.CloneRunAsRequest(r.Context(), userID, runID)
+	if errors.Is(err, gogent.ErrExecutionRunNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Failed to clone execution run %s: %v", runID, err)
+		http.Error(w, fmt.Sprintf("Failed to clone execution run: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(request)
+}
+
+// executionRunComparison returns the comparison of one of the user's runs
+func (s *Server) executionRunComparison(w http.ResponseWriter, r *http.Request, runID string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	comparison, err := s.client.GetComparisonResult(r.Context(), userID, runID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Comparison not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Failed to get comparison of execution run %s: %v", runID, err)
+		http.Error(w, "Failed to get comparison", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(comparison)
+}
+
+// executionRunRawPayloads returns the provider exchanges stored for one of the user's runs, which
+// keeps them only when submitted with storeRawPayloads. The requestId query parameter narrows them
+// to one request.
+func (s *Server) executionRunRawPayloads(w http.ResponseWriter, r *http.Request, runID string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	payloads, err := s.client.GetRawPayloads(r.Context(), userID, runID, r.URL.Query().Get("requestId"))
+	if err != nil {
+		log.Printf("❌ Failed to get raw payloads of execution run %s: %v", runID, err)
+		http.Error(w, "Failed to get raw payloads", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(payloads)
+}
+
+// executionRunDiff compares the responses and metrics of two configurations of one of the user's
+// runs, given as the a and b query parameters
+func (s *Server) executionRunDiff(w http.ResponseWriter, r *http.Request, runID string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	configurationA, configurationB := r.URL.Query().Get("a"), r.URL.Query().Get("b")
+	if configurationA == "" || configurationB == "" {
+		http.Error(w, "Both a and b configuration IDs are required", http.StatusBadRequest)
+		return
+	}
+
+	diff, err := s.client.DiffVariations(r.Context(), userID, runID, configurationA, configurationB)
+	if errors.Is(err, gogent.ErrExecutionRunNotFound) || errors.Is(err, gogent.ErrConfigurationNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Failed to diff variations of execution run %s: %v", runID, err)
+		http.Error(w, "Failed to diff variations", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(diff)
+}
+
+// executionRunFeedback lists the feedback on one of the user's runs with its aggregate per
+// configuration (GET), records feedback on one of its responses (PUT) or removes it (DELETE
+// with ?responseId=)
+func (s *Server) executionRunFeedback(w http.ResponseWriter, r *http.Request, runID string) {
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		feedback, err := s.client.GetRunFeedback(r.Context(), userID, runID)
+		if errors.Is(err, gogent.ErrExecutionRunNotFound) {
+			http.Error(w, "Execution run not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Printf("❌ Failed to get feedback on execution run %s: %v", runID, err)
+			http.Error(w, "Failed to get feedback", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(feedback)
+
+	case http.MethodPut:
+		var feedback types.ResponseFeedback
+		if err := json.NewDecoder(r.Body).Decode(&feedback); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		if feedback.ResponseID == "" {
+			http.Error(w, "responseId is required", http.StatusBadRequest)
+			return
+		}
+
+		stored, err := s.client.RecordResponseFeedback(r.Context(), userID, runID, &feedback)
+		if errors.Is(err, gogent.ErrInvalidFeedback) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, gogent.ErrResponseNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Printf("❌ Failed to record feedback on execution run %s: %v", runID, err)
+			http.Error(w, "Failed to record feedback", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(stored)
+
+	case http.MethodDelete:
+		responseID := r.URL.Query().Get("responseId")
+		if responseID == "" {
+			http.Error(w, "responseId is required", http.StatusBadRequest)
+			return
+		}
+
+		err := s.client.DeleteResponseFeedback(r.Context(), userID, runID, responseID)
+		if errors.Is(err, gogent.ErrFeedbackNotFound) {
+			http.Error(w, "Feedback not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Printf("❌ Failed to delete feedback on execution run %s: %v", runID, err)
+			http.Error(w, "Failed to delete feedback", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"message":    "Feedback deleted successfully",
+			"responseId": responseID,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// executionRunReviews starts a blind review of one of the user's runs (POST), assigned to the
+// reviewers named in the body
+func (s *Server) executionRunReviews(w http.ResponseWriter, r *http.Request, runID string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var request types.ReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	review, err := s.client.CreateReview(r.Context(), userID, runID, &request)
+	if errors.Is(err, gogent.ErrInvalidReview) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, gogent.ErrExecutionRunNotFound) {
+		http.Error(w, "Execution run not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Failed to create review of execution run %s: %v", runID, err)
+		http.Error(w, "Failed to create review", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(review)
+}
+
+// executionRunLogs pages through the log entries of one of the user's runs, oldest first. level keeps
+// entries at or above a level, category keeps one category, and after continues after a log entry ID.
+func (s *Server) executionRunLogs(w http.ResponseWriter, r *http.Request, runID string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := gogent.ExecutionLogFilter{
+		Category: types.LogCategory(strings.ToUpper(query.Get("category"))),
+		AfterID:  query.Get("after"),
+	}
+	if level := query.Get("level"); level != "" {
+		if filter.MinLevel, err = types.ParseLogLevel(level); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if limit, err := strconv.ParseInt(query.Get("limit"), 10, 32); err == nil {
+		filter.Limit = int32(limit)
+	}
+
+	logs, err := s.client.QueryExecutionLogs(r.Context(), userID, runID, filter)
+	if errors.Is(err, gogent.ErrUnknownLogEntry) {
+		http.Error(w, "Unknown log entry in after", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Failed to query logs of execution run %s: %v", runID, err)
+		http.Error(w, "Failed to get execution logs", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(logs)
+}
+
 // Handle execution runs with different HTTP methods
 func (s *Server) executionRunsHandler(w http.ResponseWriter, r *http.Request) {
 	// Check if this is a request for a specific run (e.g., /api/execution-runs/run-1)
@@ -721,6 +1732,39 @@ func (s *Server) executionRunsHandler(w http.ResponseWriter, r *http.Request) {
 		// Extract run ID from path
 		runID := path[len("/api/execution-runs/"):]
 
+		if replayOf, ok := strings.CutSuffix(runID, "/replay"); ok {
+			s.replayExecutionRun(w, r, replayOf)
+			return
+		}
+		if clonedRun, ok := strings.CutSuffix(runID, "/clone"); ok {
+			s.cloneExecutionRun(w, r, clonedRun)
+			return
+		}
+		if comparedRun, ok := strings.CutSuffix(runID, "/comparison"); ok {
+			s.executionRunComparison(w, r, comparedRun)
+			return
+		}
+		if payloadRun, ok := strings.CutSuffix(runID, "/raw-payloads"); ok {
+			s.executionRunRawPayloads(w, r, payloadRun)
+			return
+		}
+		if loggedRun, ok := strings.CutSuffix(runID, "/logs"); ok {
+			s.executionRunLogs(w, r, loggedRun)
+			return
+		}
+		if diffedRun, ok := strings.CutSuffix(runID, "/diff"); ok {
+			s.executionRunDiff(w, r, diffedRun)
+			return
+		}
+		if ratedRun, ok := strings.CutSuffix(runID, "/feedback"); ok {
+			s.executionRunFeedback(w, r, ratedRun)
+			return
+		}
+		if reviewedRun, ok := strings.CutSuffix(runID, "/reviews"); ok {
+			s.executionRunReviews(w, r, reviewedRun)
+			return
+		}
+
 		switch r.Method {
 		case http.MethodGet:
 			s.getSpecificExecutionRun(w, r, runID)
@@ -738,23 +1782,11 @@ func (s *Server) executionRunsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Parse query parameters for limit/offset
-	limitStr := r.URL.Query().Get("limit")
-	offsetStr := r.URL.Query().Get("offset")
-
-	limit := int32(10) // default limit
-	offset := int32(0) // default offset
-
-	if limitStr != "" {
-		if parsedLimit, err := strconv.ParseInt(limitStr, 10, 32); err == nil {
-			limit = int32(parsedLimit)
-		}
-	}
-
-	if offsetStr != "" {
-		if parsedOffset, err := strconv.ParseInt(offsetStr, 10, 32); err == nil {
-			offset = int32(parsedOffset)
-		}
+	// Parse query parameters for limit/offset or cursor
+	limit, offset, err := parsePage(r, 10)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
 	// Get real execution runs from database
@@ -766,6 +1798,10 @@ func (s *Server) executionRunsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	executionRuns, err := s.client.ListExecutionRuns(ctx, userID, limit, offset)
+	var total int64
+	if err == nil {
+		total, err = s.client.CountExecutionRuns(ctx, userID)
+	}
 	if err != nil {
 		log.Printf("Failed to list execution runs: %v", err)
 		// Fall back to mock data if database fails
@@ -796,465 +1832,254 @@ func (s *Server) executionRunsHandler(w http.ResponseWriter, r *http.Request) {
 		runs = append(runs, *run)
 	}
 
+	writePageHeaders(w, total, gogent.NextCursor(offset, len(runs), total))
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(runs)
 }
 
-// Database table data endpoint
-func (s *Server) databaseTableDataHandler(w http.ResponseWriter, r *http.Request) {
+// comparisonsHandler lists the comparisons of the user's runs, newest first
+func (s *Server) comparisonsHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
-
-	// Extract table name from path /api/database/tables/{tableName}
-	path := r.URL.Path
-	if len(path) <= len("/api/database/tables/") {
-		http.Error(w, "Table name required", http.StatusBadRequest)
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	limit, offset, err := parsePage(r, 10)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	tableName := path[len("/api/database/tables/"):]
+	comparisons, err := s.client.ListComparisonResults(r.Context(), userID, limit, offset)
+	if err != nil {
+		log.Printf("❌ Failed to list comparisons: %v", err)
+		http.Error(w, "Failed to list comparisons", http.StatusInternalServerError)
+		return
+	}
+	total, err := s.client.CountComparisonResults(r.Context(), userID)
+	if err != nil {
+		log.Printf("❌ Failed to count comparisons: %v", err)
+		http.Error(w, "Failed to list comparisons", http.StatusInternalServerError)
+		return
+	}
+	if comparisons == nil {
+		comparisons = []*types.ComparisonResult{}
+	}
 
-	// Get query parameters for pagination
-	limitStr := r.URL.Query().Get("limit")
-	offsetStr := r.URL.Query().Get("offset")
+	writePageHeaders(w, total, gogent.NextCursor(offset, len(comparisons), total))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(comparisons)
+}
 
-	limit := 100 // default limit
-	offset := 0  // default offset
+// parsePage reads a list request's limit and offset, or the cursor of the page to continue from
+func parsePage(r *http.Request, defaultLimit int32) (int32, int32, error) {
+	var limit, offset int64
+	if l, err := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 32); err == nil {
+		limit = l
+	}
+	if o, err := strconv.ParseInt(r.URL.Query().Get("offset"), 10, 32); err == nil {
+		offset = o
+	}
+	return gogent.ResolvePage(int32(limit), int32(offset), r.URL.Query().Get("cursor"), defaultLimit)
+}
 
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
-			limit = l
-		}
+// writePageHeaders reports the number of items across all pages and the cursor of the next page
+func writePageHeaders(w http.ResponseWriter, total int64, nextCursor string) {
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+	if nextCursor != "" {
+		w.Header().Set("X-Next-Cursor", nextCursor)
 	}
+}
 
-	if offsetStr != "" {
-		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
-			offset = o
-		}
+// Database table data endpoint
+func (s *Server) databaseTableDataHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
 	}
 
-	// Query real database data based on table name
-	var tableData interface{}
+	// Extract table name from path /api/database/tables/{tableName}
+	path := r.URL.Path
+	if len(path) <= len("/api/database/tables/") {
+		http.Error(w, "Table name required", http.StatusBadRequest)
+		return
+	}
 
-	if s.client != nil {
-		userID, err := s.getUserID(r)
-		if err != nil {
-			log.Printf("❌ Failed to get user ID for database table data lookup: %v", err)
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
-			return
-		}
-		switch tableName {
-		case "execution_runs":
-			// Query real execution runs from database
-			runs, err := s.client.ListExecutionRuns(context.Background(), userID, int32(limit), int32(offset))
-			if err != nil {
-				log.Printf("Error querying execution_runs: %v", err)
-				http.Error(w, "Database query failed", http.StatusInternalServerError)
-				return
-			}
+	tableName := path[len("/api/database/tables/"):]
 
-			// Convert to table format
-			rows := make([][]interface{}, len(runs))
-			for i, run := range runs {
-				rows[i] = []interface{}{
-					run.ID,
-					run.Name,
-					run.Description,
-					run.CreatedAt.Format(time.RFC3339),
-					run.UpdatedAt.Format(time.RFC3339),
-				}
-			}
+	query, err := parseTableQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
+	if s.client == nil {
+		// Fallback to mock data if client is not available
+		tableData := map[string]interface{}{
+			"tableName": tableName,
+			"columns":   []string{"id", "data", "created_at"},
+			"rows": [][]interface{}{
+				{"1", "Mock data for " + tableName, "2025-07-24T10:00:00Z"},
+			},
+			"totalRows": 1,
+		}
+		if tableName == "execution_runs" {
 			tableData = map[string]interface{}{
 				"tableName": "execution_runs",
 				"columns":   []string{"id", "name", "description", "created_at", "updated_at"},
-				"rows":      rows,
-				"totalRows": len(rows),
-			}
-
-		case "api_configurations":
-			// Query real API configurations for user's execution runs
-			query := `
-				SELECT ac.id, ac.execution_run_id, ac.variation_name, ac.model_name, 
-				       ac.system_prompt, ac.temperature, ac.max_tokens, ac.top_p, ac.top_k, ac.created_at
-				FROM api_configurations ac
-				INNER JOIN execution_runs er ON ac.execution_run_id = er.id
-				WHERE er.user_id = ?
-				ORDER BY ac.created_at DESC
-				LIMIT ?
-			`
-
-			dbRows, err := s.client.GetDB().QueryContext(context.Background(), query, userID, limit)
-			if err != nil {
-				log.Printf("Error querying api_configurations: %v", err)
-				http.Error(w, "Database query failed", http.StatusInternalServerError)
-				return
-			}
-			defer dbRows.Close()
-
-			var rows [][]interface{}
-			for dbRows.Next() {
-				var id, executionRunID, variationName, modelName, systemPrompt string
-				var temperature, topP sql.NullFloat64
-				var maxTokens, topK sql.NullInt32
-				var createdAt time.Time
-
-				err := dbRows.Scan(&id, &executionRunID, &variationName, &modelName,
-					&systemPrompt, &temperature, &maxTokens, &topP, &topK, &createdAt)
-				if err != nil {
-					log.Printf("Error scanning api_configurations row: %v", err)
-					continue
-				}
-
-				// Format nullable values
-				tempStr := ""
-				if temperature.Valid {
-					tempStr = fmt.Sprintf("%.2f", temperature.Float64)
-				}
-				maxTokensStr := ""
-				if maxTokens.Valid {
-					maxTokensStr = fmt.Sprintf("%d", maxTokens.Int32)
-				}
-				topPStr := ""
-				if topP.Valid {
-					topPStr = fmt.Sprintf("%.2f", topP.Float64)
-				}
-				topKStr := ""
-				if topK.Valid {
-					topKStr = fmt.Sprintf("%d", topK.Int32)
-				}
-
-				row := []interface{}{
-					id, executionRunID, variationName, modelName, systemPrompt,
-					tempStr, maxTokensStr, topPStr, topKStr, createdAt.Format(time.RFC3339),
-				}
-				rows = append(rows, row)
-			}
-
-			tableData = map[string]interface{}{
-				"tableName": "api_configurations",
-				"columns":   []string{"id", "execution_run_id", "variation_name", "model_name", "system_prompt", "temperature", "max_tokens", "top_p", "top_k", "created_at"},
-				"rows":      rows,
-				"totalRows": len(rows),
-			}
-
-		case "api_requests":
-			// Query real API requests for user's execution runs
-			query := `
-				SELECT ar.id, ar.execution_run_id, ar.configuration_id, ar.request_type, 
-				       ar.prompt, ar.context, ar.function_name, ar.created_at
-				FROM api_requests ar
-				INNER JOIN execution_runs er ON ar.execution_run_id = er.id
-				WHERE er.user_id = ?
-				ORDER BY ar.created_at DESC
-				LIMIT ?
-			`
-
-			dbRows, err := s.client.GetDB().QueryContext(context.Background(), query, userID, limit)
-			if err != nil {
-				log.Printf("Error querying api_requests: %v", err)
-				http.Error(w, "Database query failed", http.StatusInternalServerError)
-				return
-			}
-			defer dbRows.Close()
-
-			var rows [][]interface{}
-			for dbRows.Next() {
-				var id, executionRunID, configurationID, requestType, prompt string
-				var context, functionName sql.NullString
-				var createdAt time.Time
-
-				err := dbRows.Scan(&id, &executionRunID, &configurationID, &requestType,
-					&prompt, &context, &functionName, &createdAt)
-				if err != nil {
-					log.Printf("Error scanning api_requests row: %v", err)
-					continue
-				}
-
-				// Format nullable values
-				contextStr := ""
-				if context.Valid {
-					contextStr = context.String
-					if len(contextStr) > 100 {
-						contextStr = contextStr[:100] + "..."
-					}
-				}
-				functionNameStr := ""
-				if functionName.Valid {
-					functionNameStr = functionName.String
-				}
-
-				// Truncate long prompts for display
-				promptDisplay := prompt
-				if len(promptDisplay) > 100 {
-					promptDisplay = promptDisplay[:100] + "..."
-				}
-
-				row := []interface{}{
-					id, executionRunID, configurationID, requestType,
-					promptDisplay, contextStr, functionNameStr, createdAt.Format(time.RFC3339),
-				}
-				rows = append(rows, row)
-			}
-
-			tableData = map[string]interface{}{
-				"tableName": "api_requests",
-				"columns":   []string{"id", "execution_run_id", "configuration_id", "request_type", "prompt", "context", "function_name", "created_at"},
-				"rows":      rows,
-				"totalRows": len(rows),
-			}
-
-		case "api_responses":
-			// Query real API responses for user's requests
-			query := `
-				SELECT resp.id, resp.request_id, resp.response_status, resp.response_text, 
-				       resp.finish_reason, resp.error_message, resp.response_time_ms, 
-				       resp.usage_metadata, resp.created_at
-				FROM api_responses resp
-				INNER JOIN api_requests req ON resp.request_id = req.id
-				INNER JOIN execution_runs er ON req.execution_run_id = er.id
-				WHERE er.user_id = ?
-				ORDER BY resp.created_at DESC
-				LIMIT ?
-			`
-
-			dbRows, err := s.client.GetDB().QueryContext(context.Background(), query, userID, limit)
-			if err != nil {
-				log.Printf("Error querying api_responses: %v", err)
-				http.Error(w, "Database query failed", http.StatusInternalServerError)
-				return
-			}
-			defer dbRows.Close()
-
-			var rows [][]interface{}
-			for dbRows.Next() {
-				var id, requestID, responseStatus, responseText string
-				var finishReason, errorMessage sql.NullString
-				var responseTimeMs sql.NullInt32
-				var usageMetadata []byte
-				var createdAt time.Time
-
-				err := dbRows.Scan(&id, &requestID, &responseStatus, &responseText,
-					&finishReason, &errorMessage, &responseTimeMs, &usageMetadata, &createdAt)
-				if err != nil {
-					log.Printf("Error scanning api_responses row: %v", err)
-					continue
-				}
-
-				// Format nullable values
-				finishReasonStr := ""
-				if finishReason.Valid {
-					finishReasonStr = finishReason.String
-				}
-				errorMessageStr := ""
-				if errorMessage.Valid {
-					errorMessageStr = errorMessage.String
-				}
-				responseTimeStr := ""
-				if responseTimeMs.Valid {
-					responseTimeStr = fmt.Sprintf("%d ms", responseTimeMs.Int32)
-				}
-
-				// Truncate long response text for display
-				responseDisplay := responseText
-				if len(responseDisplay) > 100 {
-					responseDisplay = responseDisplay[:100] + "..."
-				}
-
-				// Truncate usage metadata for display
-				usageStr := string(usageMetadata)
-				if len(usageStr) > 100 {
-					usageStr = usageStr[:100] + "..."
-				}
-
-				row := []interface{}{
-					id, requestID, responseStatus, responseDisplay, finishReasonStr,
-					errorMessageStr, responseTimeStr, usageStr, createdAt.Format(time.RFC3339),
-				}
-				rows = append(rows, row)
-			}
-
-			tableData = map[string]interface{}{
-				"tableName": "api_responses",
-				"columns":   []string{"id", "request_id", "response_status", "response_text", "finish_reason", "error_message", "response_time_ms", "usage_metadata", "created_at"},
-				"rows":      rows,
-				"totalRows": len(rows),
-			}
-
-		case "comparison_results":
-			// Query real comparison results for user's execution runs
-			query := `
-				SELECT cr.id, cr.execution_run_id, cr.comparison_type, cr.metric_name, 
-				       cr.best_configuration_id, cr.created_at
-				FROM comparison_results cr
-				INNER JOIN execution_runs er ON cr.execution_run_id = er.id
-				WHERE er.user_id = ?
-				ORDER BY cr.created_at DESC
-				LIMIT ?
-			`
-
-			dbRows, err := s.client.GetDB().QueryContext(context.Background(), query, userID, limit)
-			if err != nil {
-				log.Printf("Error querying comparison_results: %v", err)
-				http.Error(w, "Database query failed", http.StatusInternalServerError)
-				return
+				"rows": [][]interface{}{
+					{"run-1", "creative-writing-test", "Testing different temperature settings", "2025-07-24T11:00:00Z", "2025-07-24T11:00:00Z"},
+					{"run-2", "analytical-comparison", "Comparing analytical vs creative responses", "2025-07-24T12:00:00Z", "2025-07-24T12:00:00Z"},
+				},
+				"totalRows": 2,
 			}
-			defer dbRows.Close()
-
-			var rows [][]interface{}
-			for dbRows.Next() {
-				var id, executionRunID, comparisonType, metricName, bestConfigurationID string
-				var createdAt time.Time
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(tableData)
+		return
+	}
 
-				err := dbRows.Scan(&id, &executionRunID, &comparisonType, &metricName,
-					&bestConfigurationID, &createdAt)
-				if err != nil {
-					log.Printf("Error scanning comparison_results row: %v", err)
-					continue
-				}
+	userID, err := s.getUserID(r)
+	if err != nil {
+		log.Printf("❌ Failed to get user ID for database table data lookup: %v", err)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	s.writeTableData(w, r, userID, tableName, query)
+}
 
-				row := []interface{}{
-					id, executionRunID, comparisonType, metricName,
-					bestConfigurationID, createdAt.Format(time.RFC3339),
-				}
-				rows = append(rows, row)
+// parseTableQuery reads a table browser query: ?columns=id,status selects columns, each
+// ?filter=column:operator:value narrows the rows, ?sort=-created_at orders them and ?limit with
+// ?offset or ?cursor pages through them
+func parseTableQuery(r *http.Request) (gogent.TableQuery, error) {
+	limit, offset, err := parsePage(r, 100)
+	if err != nil {
+		return gogent.TableQuery{}, err
+	}
+	query := gogent.TableQuery{Sort: r.URL.Query().Get("sort"), Limit: limit, Offset: offset}
+	if columns := r.URL.Query().Get("columns"); columns != "" {
+		for _, column := range strings.Split(columns, ",") {
+			if column = strings.TrimSpace(column); column != "" {
+				query.Columns = append(query.Columns, column)
 			}
+		}
+	}
+	query.Filters, err = gogent.ParseTableFilters(r.URL.Query()["filter"])
+	if err != nil {
+		return gogent.TableQuery{}, err
+	}
+	return query, nil
+}
 
-			tableData = map[string]interface{}{
-				"tableName": "comparison_results",
-				"columns":   []string{"id", "execution_run_id", "comparison_type", "metric_name", "best_configuration_id", "created_at"},
-				"rows":      rows,
-				"totalRows": len(rows),
-			}
+// writeTableData writes a page of a table scoped to userID, or of every row when userID is empty,
+// with the number of matching rows and the cursor of the next page
+func (s *Server) writeTableData(w http.ResponseWriter, r *http.Request, userID, tableName string, query gogent.TableQuery) {
+	if s.client.GetDB() == nil {
+		http.Error(w, "Database browsing requires a SQL database", http.StatusServiceUnavailable)
+		return
+	}
 
-		case "function_calls":
-			// Query function calls for user's execution runs
-			query := `
-				SELECT fc.id, fc.request_id, fc.function_name, fc.function_arguments, 
-				       fc.function_response, fc.execution_status, fc.execution_time_ms, 
-				       fc.error_details, fc.created_at
-				FROM function_calls fc 
-				INNER JOIN api_requests req ON fc.request_id = req.id
-				INNER JOIN execution_runs er ON req.execution_run_id = er.id
-				WHERE er.user_id = ?
-				ORDER BY fc.created_at DESC 
-				LIMIT ?
-			`
-
-			dbRows, err := s.client.GetDB().QueryContext(context.Background(), query, userID, limit)
-			if err != nil {
-				log.Printf("Error querying function_calls: %v", err)
-				http.Error(w, "Database query failed", http.StatusInternalServerError)
-				return
-			}
-			defer dbRows.Close()
-
-			var rows [][]interface{}
-			for dbRows.Next() {
-				var id, requestID, functionName, executionStatus string
-				var errorDetails sql.NullString
-				var functionArgs, functionResponse []byte
-				var executionTimeMs sql.NullInt32
-				var createdAt time.Time
-
-				err := dbRows.Scan(&id, &requestID, &functionName, &functionArgs,
-					&functionResponse, &executionStatus, &executionTimeMs, &errorDetails, &createdAt)
-				if err != nil {
-					log.Printf("Error scanning function_calls row: %v", err)
-					continue
-				}
+	columns, rows, totalRows, err := s.client.QueryTableData(r.Context(), userID, tableName, query)
+	switch {
+	case errors.Is(err, gogent.ErrUnknownTable):
+		http.Error(w, "Unknown table", http.StatusNotFound)
+		return
+	case errors.Is(err, gogent.ErrInvalidTableQuery):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case err != nil:
+		log.Printf("❌ Failed to query %s: %v", tableName, err)
+		http.Error(w, "Database query failed", http.StatusInternalServerError)
+		return
+	}
 
-				// Convert execution time to display format
-				var execTimeStr string
-				if executionTimeMs.Valid {
-					execTimeStr = fmt.Sprintf("%d ms", executionTimeMs.Int32)
-				} else {
-					execTimeStr = ""
-				}
+	nextCursor := gogent.NextCursor(query.Offset, len(rows), totalRows)
+	writePageHeaders(w, totalRows, nextCursor)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"tableName":  tableName,
+		"columns":    columns,
+		"rows":       rows,
+		"totalRows":  totalRows,
+		"totalCount": totalRows,
+		"nextCursor": nextCursor,
+	})
+}
 
-				// Convert error details to display format
-				var errorDetailsStr string
-				if errorDetails.Valid {
-					errorDetailsStr = errorDetails.String
-				} else {
-					errorDetailsStr = ""
-				}
+// modelsHandler lists the provider's model catalog, filtered by ?method and refetched with ?refresh=true
+func (s *Server) modelsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
 
-				// Truncate long JSON for display
-				argsStr := string(functionArgs)
-				if len(argsStr) > 100 {
-					argsStr = argsStr[:100] + "..."
-				}
-				responseStr := string(functionResponse)
-				if len(responseStr) > 100 {
-					responseStr = responseStr[:100] + "..."
-				}
+	var models []types.ModelInfo
+	var err error
+	if r.URL.Query().Get("refresh") == "true" {
+		models, err = s.client.RefreshModels(r.Context())
+	} else {
+		models, err = s.client.ListModels(r.Context())
+	}
+	if err != nil {
+		log.Printf("❌ Failed to list models: %v", err)
+		http.Error(w, "Failed to list models", http.StatusBadGateway)
+		return
+	}
+	if method := r.URL.Query().Get("method"); method != "" {
+		models = gogent.ModelsSupporting(models, method)
+	}
 
-				row := []interface{}{
-					id,
-					requestID,
-					functionName,
-					argsStr,
-					responseStr,
-					executionStatus,
-					execTimeStr,
-					errorDetailsStr,
-					createdAt.Format(time.RFC3339),
-				}
-				rows = append(rows, row)
-			}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"models": models,
+		"count":  len(models),
+	})
+}
 
-			tableData = map[string]interface{}{
-				"tableName": "function_calls",
-				"columns": []string{
-					"id", "request_id", "function_name", "function_arguments",
-					"function_response", "execution_status", "execution_time_ms",
-					"error_details", "created_at",
-				},
-				"rows":      rows,
-				"totalRows": len(rows),
-			}
+// searchHandler finds the user's past prompts and responses closest in meaning to ?q
+func (s *Server) searchHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 
-		default:
-			// For other tables, return a placeholder
-			tableData = map[string]interface{}{
-				"tableName": tableName,
-				"columns":   []string{"id", "data", "created_at"},
-				"rows": [][]interface{}{
-					{"1", "Real data for " + tableName + " (table not fully implemented)", time.Now().Format(time.RFC3339)},
-				},
-				"totalRows": 1,
-			}
-		}
-	} else {
-		// Fallback to mock data if client is not available
-		switch tableName {
-		case "execution_runs":
-			tableData = map[string]interface{}{
-				"tableName": "execution_runs",
-				"columns":   []string{"id", "name", "description", "created_at", "updated_at"},
-				"rows": [][]interface{}{
-					{"run-1", "creative-writing-test", "Testing different temperature settings", "2025-07-24T11:00:00Z", "2025-07-24T11:00:00Z"},
-					{"run-2", "analytical-comparison", "Comparing analytical vs creative responses", "2025-07-24T12:00:00Z", "2025-07-24T12:00:00Z"},
-				},
-				"totalRows": 2,
-			}
-		default:
-			tableData = map[string]interface{}{
-				"tableName": tableName,
-				"columns":   []string{"id", "data", "created_at"},
-				"rows": [][]interface{}{
-					{"1", "Mock data for " + tableName, "2025-07-24T10:00:00Z"},
-				},
-				"totalRows": 1,
-			}
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		http.Error(w, "Query parameter q is required", http.StatusBadRequest)
+		return
+	}
+	limit := gogent.DefaultSearchLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > gogent.MaxSearchLimit {
+			http.Error(w, fmt.Sprintf("limit must be between 1 and %d", gogent.MaxSearchLimit), http.StatusBadRequest)
+			return
 		}
+		limit = parsed
+	}
+
+	results, err := s.client.SearchExecutions(r.Context(), userID, query, limit)
+	if err != nil {
+		log.Printf("❌ Failed to search executions: %v", err)
+		http.Error(w, "Failed to search executions", http.StatusInternalServerError)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(tableData)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"query":   query,
+		"results": results,
+		"count":   len(results),
+	})
 }
 
 // Database stats endpoint
@@ -1274,7 +2099,7 @@ func (s *Server) databaseStatsHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
 
 	// Get real user-scoped statistics from database
-	stats, err := s.getUserDatabaseStats(ctx, userID)
+	stats, err := databaseStats(ctx, s.client, userID)
 	if err != nil {
 		log.Printf("❌ Failed to get user database stats: %v", err)
 		// Fallback to empty stats if database query fails
@@ -1292,16 +2117,59 @@ func (s *Server) databaseStatsHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(stats)
 }
 
-// getUserDatabaseStats gets user-specific database statistics
-func (s *Server) getUserDatabaseStats(ctx context.Context, userID string) (map[string]interface{}, error) {
-	db := s.client.GetDB()
+// databaseStats reads stats for userID, or for every user when userID is empty, from the daily
+// rollups. Until the rollups are first built, or when they cannot be read, the stats are computed
+// from the execution tables instead. The freshness entry says which source was used and, for
+// rollups, when they were refreshed.
+func databaseStats(ctx context.Context, client *gogent.Client, userID string) (map[string]interface{}, error) {
+	rollups, err := client.GetRollupStats(ctx, userID)
+	switch {
+	case errors.Is(err, gogent.ErrNoDatabase):
+		return nil, err
+	case err != nil:
+		if !errors.Is(err, gogent.ErrRollupsNotBuilt) {
+			log.Printf("⚠️ Warning: failed to read stats rollups, querying live: %v", err)
+		}
+		stats, err := queryDatabaseStats(ctx, client.GetDB(), userID)
+		if err != nil {
+			return nil, err
+		}
+		stats["freshness"] = map[string]interface{}{"source": "live"}
+		return stats, nil
+	}
+
+	embeddingCache, err := gogent.QueryEmbeddingCacheStats(ctx, client.GetDB(), userID)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"totalExecutionRuns": int32(rollups.ExecutionRuns),
+		"totalApiRequests":   int32(rollups.APIRequests),
+		"totalApiResponses":  int32(rollups.APIResponses),
+		"totalFunctionCalls": int32(rollups.FunctionCalls),
+		"avgResponseTime":    rollups.AvgResponseTimeMs,
+		"successRate":        rollups.SuccessRate,
+		"promptTokens":       rollups.PromptTokens,
+		"completionTokens":   rollups.CompletionTokens,
+		"totalTokens":        rollups.TotalTokens,
+		"embeddingCache":     embeddingCache,
+		"freshness": map[string]interface{}{
+			"source":      "rollups",
+			"refreshedAt": rollups.RefreshedAt,
+			"ageSeconds":  int64(time.Since(rollups.RefreshedAt).Seconds()),
+		},
+	}, nil
+}
+
+// queryDatabaseStats computes database statistics for one user, or for every user when userID is empty
+func queryDatabaseStats(ctx context.Context, db *sql.DB, userID string) (map[string]interface{}, error) {
 
 	// Count execution runs for this user
 	var totalExecutionRuns int32
 	err := db.QueryRowContext(ctx, `
 		SELECT COALESCE(COUNT(*), 0) FROM execution_runs 
-		WHERE user_id = ?
-	`, userID).Scan(&totalExecutionRuns)
+		WHERE (? = '' OR user_id = ?)
+	`, userID, userID).Scan(&totalExecutionRuns)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, fmt.Errorf("failed to count execution runs: %w", err)
 	}
@@ -1311,8 +2179,8 @@ func (s *Server) getUserDatabaseStats(ctx context.Context, userID string) (map[s
 	err = db.QueryRowContext(ctx, `
 		SELECT COALESCE(COUNT(*), 0) FROM api_requests ar 
 		INNER JOIN execution_runs er ON ar.execution_run_id = er.id 
-		WHERE er.user_id = ?
-	`, userID).Scan(&totalApiRequests)
+		WHERE (? = '' OR er.user_id = ?)
+	`, userID, userID).Scan(&totalApiRequests)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, fmt.Errorf("failed to count API requests: %w", err)
 	}
@@ -1323,8 +2191,8 @@ func (s *Server) getUserDatabaseStats(ctx context.Context, userID string) (map[s
 		SELECT COALESCE(COUNT(*), 0) FROM api_responses resp 
 		INNER JOIN api_requests req ON resp.request_id = req.id 
 		INNER JOIN execution_runs er ON req.execution_run_id = er.id 
-		WHERE er.user_id = ?
-	`, userID).Scan(&totalApiResponses)
+		WHERE (? = '' OR er.user_id = ?)
+	`, userID, userID).Scan(&totalApiResponses)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, fmt.Errorf("failed to count API responses: %w", err)
 	}
@@ -1335,8 +2203,8 @@ func (s *Server) getUserDatabaseStats(ctx context.Context, userID string) (map[s
 		SELECT COALESCE(COUNT(*), 0) FROM function_calls fc 
 		INNER JOIN api_requests ar ON fc.request_id = ar.id
 		INNER JOIN execution_runs er ON ar.execution_run_id = er.id 
-		WHERE er.user_id = ?
-	`, userID).Scan(&totalFunctionCalls)
+		WHERE (? = '' OR er.user_id = ?)
+	`, userID, userID).Scan(&totalFunctionCalls)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, fmt.Errorf("failed to count function calls: %w", err)
 	}
@@ -1347,8 +2215,8 @@ func (s *Server) getUserDatabaseStats(ctx context.Context, userID string) (map[s
 		SELECT COALESCE(AVG(resp.response_time_ms), 0) FROM api_responses resp 
 		INNER JOIN api_requests req ON resp.request_id = req.id 
 		INNER JOIN execution_runs er ON req.execution_run_id = er.id 
-		WHERE er.user_id = ? AND resp.response_time_ms IS NOT NULL
-	`, userID).Scan(&avgResponseTime)
+		WHERE (? = '' OR er.user_id = ?) AND resp.response_time_ms IS NOT NULL
+	`, userID, userID).Scan(&avgResponseTime)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, fmt.Errorf("failed to calculate average response time: %w", err)
 	}
@@ -1364,8 +2232,8 @@ func (s *Server) getUserDatabaseStats(ctx context.Context, userID string) (map[s
 		FROM api_responses resp 
 		INNER JOIN api_requests req ON resp.request_id = req.id 
 		INNER JOIN execution_runs er ON req.execution_run_id = er.id 
-		WHERE er.user_id = ?
-	`, userID).Scan(&successCount, &totalCount)
+		WHERE (? = '' OR er.user_id = ?)
+	`, userID, userID).Scan(&successCount, &totalCount)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, fmt.Errorf("failed to calculate success rate: %w", err)
 	}
@@ -1374,6 +2242,25 @@ func (s *Server) getUserDatabaseStats(ctx context.Context, userID string) (map[s
 		successRate = float64(successCount) / float64(totalCount)
 	}
 
+	// Total tokens of this user's responses
+	var promptTokens, completionTokens, totalTokens int64
+	err = db.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(resp.prompt_tokens), 0), COALESCE(SUM(resp.completion_tokens), 0), COALESCE(SUM(resp.total_tokens), 0)
+		FROM api_responses resp
+		INNER JOIN api_requests req ON resp.request_id = req.id
+		INNER JOIN execution_runs er ON req.execution_run_id = er.id
+		WHERE (? = '' OR er.user_id = ?)
+	`, userID, userID).Scan(&promptTokens, &completionTokens, &totalTokens)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to total tokens: %w", err)
+	}
+
+	// Embedding cache hit and miss counts
+	embeddingCache, err := gogent.QueryEmbeddingCacheStats(ctx, db, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	return map[string]interface{}{
 		"totalExecutionRuns": totalExecutionRuns,
 		"totalApiRequests":   totalApiRequests,
@@ -1381,6 +2268,10 @@ func (s *Server) getUserDatabaseStats(ctx context.Context, userID string) (map[s
 		"totalFunctionCalls": totalFunctionCalls,
 		"avgResponseTime":    avgResponseTime,
 		"successRate":        successRate,
+		"promptTokens":       promptTokens,
+		"completionTokens":   completionTokens,
+		"totalTokens":        totalTokens,
+		"embeddingCache":     embeddingCache,
 	}, nil
 }
 
@@ -1391,20 +2282,1313 @@ func (s *Server) databaseTablesHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tables := []string{
-		"execution_runs",
-		"comparison_results",
-		"function_calls",
-		"api_configurations",
-		"api_requests",
-		"api_responses",
-		"execution_logs",
-		"function_definitions",
-		"execution_function_configs",
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(gogent.BrowsableTables())
+}
+
+// =============================================================================
+// SLO ENDPOINTS
+// =============================================================================
+
+// slosHandler lists SLO statuses (only alerting ones with ?alerting=true) and saves SLOs
+func (s *Server) slosHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		statuses, err := s.client.ListSLOStatuses(r.Context(), userID)
+		if err != nil {
+			log.Printf("❌ Failed to list SLO statuses: %v", err)
+			http.Error(w, "Failed to list SLOs", http.StatusInternalServerError)
+			return
+		}
+		if r.URL.Query().Get("alerting") == "true" {
+			alerting := make([]*types.SLOStatus, 0, len(statuses))
+			for _, status := range statuses {
+				if status.Alerting {
+					alerting = append(alerting, status)
+				}
+			}
+			statuses = alerting
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"slos":  statuses,
+			"count": len(statuses),
+		})
+
+	case http.MethodPut:
+		var slo types.SuiteSLO
+		if err := json.NewDecoder(r.Body).Decode(&slo); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		if err := gogent.ValidateSuiteSLO(&slo); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		saved, err := s.client.SaveSuiteSLO(r.Context(), userID, &slo)
+		if err != nil {
+			log.Printf("❌ Failed to save SLO for suite %s: %v", slo.Suite, err)
+			http.Error(w, "Failed to save SLO", http.StatusInternalServerError)
+			return
+		}
+		log.Printf("🎯 Saved SLO for suite %s", saved.Suite)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(saved)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// trendsHandler follows a preset (?preset=) or variation name (?variation=) across the user's runs,
+// optionally only runs since a date (?since=, RFC 3339 or YYYY-MM-DD) and at most ?limit= of them
+func (s *Server) trendsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	params := r.URL.Query()
+	query := types.TrendQuery{PresetID: params.Get("preset"), VariationName: params.Get("variation")}
+	if since := params.Get("since"); since != "" {
+		query.Since, err = time.Parse(time.RFC3339, since)
+		if err != nil {
+			query.Since, err = time.Parse(time.DateOnly, since)
+		}
+		if err != nil {
+			http.Error(w, "since must be an RFC 3339 time or a YYYY-MM-DD date", http.StatusBadRequest)
+			return
+		}
+	}
+	if limit := params.Get("limit"); limit != "" {
+		query.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			http.Error(w, "limit must be a number", http.StatusBadRequest)
+			return
+		}
+	}
+
+	trend, err := s.client.GetConfigurationTrend(r.Context(), userID, query)
+	if errors.Is(err, gogent.ErrInvalidTrendQuery) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Failed to get configuration trend: %v", err)
+		http.Error(w, "Failed to get configuration trend", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(trend)
+}
+
+// reviewsHandler lists the reviews of the user's runs and the reviews assigned to the user (GET
+// /api/reviews), and routes /api/reviews/{id} and its sub-routes
+func (s *Server) reviewsHandler(w http.ResponseWriter, r *http.Request) {
+	reviewID := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/api/reviews"), "/")
+	if reviewID != "" {
+		if taskOf, ok := strings.CutSuffix(reviewID, "/task"); ok {
+			s.reviewTask(w, r, taskOf)
+			return
+		}
+		if judged, ok := strings.CutSuffix(reviewID, "/judgments"); ok {
+			s.reviewJudgments(w, r, judged)
+			return
+		}
+		if closed, ok := strings.CutSuffix(reviewID, "/close"); ok {
+			s.closeReview(w, r, closed)
+			return
+		}
+		if revealed, ok := strings.CutSuffix(reviewID, "/results"); ok {
+			s.reviewResults(w, r, revealed)
+			return
+		}
+	}
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if reviewID != "" {
+		review, err := s.client.GetReview(r.Context(), userID, reviewID)
+		if errors.Is(err, gogent.ErrReviewNotFound) {
+			http.Error(w, "Review not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Printf("❌ Failed to get review %s: %v", reviewID, err)
+			http.Error(w, "Failed to get review", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(review)
+		return
+	}
+
+	owned, err := s.client.ListReviews(r.Context(), userID)
+	if err != nil {
+		log.Printf("❌ Failed to list reviews: %v", err)
+		http.Error(w, "Failed to list reviews", http.StatusInternalServerError)
+		return
+	}
+	assigned, err := s.client.ListReviewTasks(r.Context(), userID)
+	if err != nil {
+		log.Printf("❌ Failed to list review tasks: %v", err)
+		http.Error(w, "Failed to list reviews", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"reviews":  owned,
+		"assigned": assigned,
+	})
+}
+
+// reviewTask returns a review assigned to the user, with its responses in the user's own order and
+// their configurations hidden
+func (s *Server) reviewTask(w http.ResponseWriter, r *http.Request, reviewID string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	task, err := s.client.GetReviewTask(r.Context(), userID, reviewID)
+	if errors.Is(err, gogent.ErrReviewNotFound) {
+		http.Error(w, "Review not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Failed to get review task %s: %v", reviewID, err)
+		http.Error(w, "Failed to get review task", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(task)
+}
+
+// reviewJudgments submits the user's judgments of every response of a review assigned to them (PUT),
+// replacing any submitted before
+func (s *Server) reviewJudgments(w http.ResponseWriter, r *http.Request, reviewID string) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var body struct {
+		Judgments []types.ReviewJudgment `json:"judgments"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	task, err := s.client.SubmitReviewJudgments(r.Context(), userID, reviewID, body.Judgments)
+	if errors.Is(err, gogent.ErrInvalidReview) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, gogent.ErrReviewNotFound) {
+		http.Error(w, "Review not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, gogent.ErrReviewClosed) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Failed to submit judgments on review %s: %v", reviewID, err)
+		http.Error(w, "Failed to submit judgments", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(task)
+}
+
+// closeReview ends a review of one of the user's runs before every reviewer has submitted (POST)
+func (s *Server) closeReview(w http.ResponseWriter, r *http.Request, reviewID string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	review, err := s.client.CloseReview(r.Context(), userID, reviewID)
+	if errors.Is(err, gogent.ErrReviewNotFound) {
+		http.Error(w, "Review not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, gogent.ErrReviewClosed) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Failed to close review %s: %v", reviewID, err)
+		http.Error(w, "Failed to close review", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(review)
+}
+
+// reviewResults reveals the configurations behind a finished review of one of the user's runs, with
+// the winner and how much the reviewers agreed
+func (s *Server) reviewResults(w http.ResponseWriter, r *http.Request, reviewID string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	results, err := s.client.GetReviewResults(r.Context(), userID, reviewID)
+	if errors.Is(err, gogent.ErrReviewNotFound) {
+		http.Error(w, "Review not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, gogent.ErrReviewOpen) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Failed to get results of review %s: %v", reviewID, err)
+		http.Error(w, "Failed to get review results", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
+// workspacesHandler lists the user's workspaces (GET) or creates one owned by the user (POST), and
+// routes /api/workspaces/{id} and its sub-routes
+func (s *Server) workspacesHandler(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/api/workspaces"), "/")
+	if path != "" {
+		workspaceID, sub, _ := strings.Cut(path, "/")
+		switch {
+		case sub == "":
+			s.getWorkspace(w, r, workspaceID)
+		case sub == "members" || strings.HasPrefix(sub, "members/"):
+			s.workspaceMembers(w, r, workspaceID, strings.TrimPrefix(strings.TrimPrefix(sub, "members"), "/"))
+		case sub == "resources" || strings.HasPrefix(sub, "resources/"):
+			s.workspaceResources(w, r, workspaceID, strings.TrimPrefix(strings.TrimPrefix(sub, "resources"), "/"))
+		default:
+			http.NotFound(w, r)
+		}
+		return
+	}
+
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		workspaces, err := s.client.ListWorkspaces(r.Context(), userID)
+		if err != nil {
+			writeWorkspaceError(w, "list", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(workspaces)
+
+	case http.MethodPost:
+		var body struct {
+			Name string `json:"name"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		workspace, err := s.client.CreateWorkspace(r.Context(), userID, body.Name)
+		if err != nil {
+			writeWorkspaceError(w, "create", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(workspace)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// getWorkspace returns a workspace the user belongs to with its members
+func (s *Server) getWorkspace(w http.ResponseWriter, r *http.Request, workspaceID string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	workspace, err := s.client.GetWorkspace(r.Context(), userID, workspaceID)
+	if err != nil {
+		writeWorkspaceError(w, "get", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(workspace)
+}
+
+// workspaceMembers adds a member or changes their role (PUT /api/workspaces/{id}/members) and
+// removes one (DELETE /api/workspaces/{id}/members/{username})
+func (s *Server) workspaceMembers(w http.ResponseWriter, r *http.Request, workspaceID, username string) {
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodPut && username == "":
+		var body struct {
+			Username string `json:"username"`
+			Role     string `json:"role"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		member, err := s.client.SetWorkspaceMember(r.Context(), userID, workspaceID, body.Username, body.Role)
+		if err != nil {
+			writeWorkspaceError(w, "update", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(member)
+
+	case r.Method == http.MethodDelete && username != "":
+		if err := s.client.RemoveWorkspaceMember(r.Context(), userID, workspaceID, username); err != nil {
+			writeWorkspaceError(w, "update", err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// workspaceResources lists what a workspace shares, optionally of one ?type= (GET), and shares one
+// of the user's resources with it (POST). /api/workspaces/{id}/resources/{type}/{resourceId}
+// returns a shared resource (GET) or stops sharing it (DELETE).
+func (s *Server) workspaceResources(w http.ResponseWriter, r *http.Request, workspaceID, resource string) {
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if resource != "" {
+		resourceType, resourceID, ok := strings.Cut(resource, "/")
+		if !ok || resourceID == "" {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			var shared interface{}
+			switch resourceType {
+			case types.SharedResourceExecutionRun:
+				shared, err = s.client.GetSharedExecutionResult(r.Context(), userID, workspaceID, resourceID)
+			case types.SharedResourceFunction:
+				shared, err = s.client.GetSharedFunctionDefinition(r.Context(), userID, workspaceID, resourceID)
+			case types.SharedResourcePreset:
+				shared, err = s.client.GetSharedConfigurationPreset(r.Context(), userID, workspaceID, resourceID)
+			case types.SharedResourcePrompt:
+				shared, err = s.client.GetSharedPrompt(r.Context(), userID, workspaceID, resourceID)
+			default:
+				err = fmt.Errorf("%w: unknown resource type %q", gogent.ErrInvalidWorkspace, resourceType)
+			}
+			if err != nil {
+				writeWorkspaceError(w, "get", err)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(shared)
+
+		case http.MethodDelete:
+			if err := s.client.UnshareResource(r.Context(), userID, workspaceID, resourceType, resourceID); err != nil {
+				writeWorkspaceError(w, "unshare", err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		resources, err := s.client.ListSharedResources(r.Context(), userID, workspaceID, r.URL.Query().Get("type"))
+		if err != nil {
+			writeWorkspaceError(w, "list", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resources)
+
+	case http.MethodPost:
+		var body struct {
+			Type string `json:"type"`
+			ID   string `json:"id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		shared, err := s.client.ShareResource(r.Context(), userID, workspaceID, body.Type, body.ID)
+		if err != nil {
+			writeWorkspaceError(w, "share", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(shared)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// writeWorkspaceError responds with the status matching a workspace error
+func writeWorkspaceError(w http.ResponseWriter, action string, err error) {
+	switch {
+	case errors.Is(err, gogent.ErrInvalidWorkspace):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, gogent.ErrWorkspaceNotFound):
+		http.Error(w, "Workspace not found", http.StatusNotFound)
+	case errors.Is(err, gogent.ErrSharedResourceNotFound):
+		http.Error(w, "Resource not found", http.StatusNotFound)
+	case errors.Is(err, gogent.ErrWorkspaceForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		log.Printf("❌ Failed to %s workspace: %v", action, err)
+		http.Error(w, fmt.Sprintf("Failed to %s workspace", action), http.StatusInternalServerError)
+	}
+}
+
+// leaderboardHandler ranks the configurations of the user's runs tagged ?tag= by average overall
+// score, listing at most ?limit= of those scored in at least ?minSamples= runs
+func (s *Server) leaderboardHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	params := r.URL.Query()
+	query := types.LeaderboardQuery{Tag: params.Get("tag")}
+	if query.Tag == "" {
+		http.Error(w, "tag is required", http.StatusBadRequest)
+		return
+	}
+	if limit := params.Get("limit"); limit != "" {
+		query.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			http.Error(w, "limit must be a number", http.StatusBadRequest)
+			return
+		}
+	}
+	if minSamples := params.Get("minSamples"); minSamples != "" {
+		query.MinSamples, err = strconv.Atoi(minSamples)
+		if err != nil {
+			http.Error(w, "minSamples must be a number", http.StatusBadRequest)
+			return
+		}
+	}
+
+	leaderboard, err := s.client.GetLeaderboard(r.Context(), userID, query)
+	if errors.Is(err, gogent.ErrInvalidLeaderboardQuery) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Failed to get leaderboard: %v", err)
+		http.Error(w, "Failed to get leaderboard", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(leaderboard)
+}
+
+// sloBySuiteHandler returns or deletes the SLO of one suite
+func (s *Server) sloBySuiteHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	suite := strings.TrimPrefix(r.URL.Path, "/api/slos/")
+	if suite == "" {
+		http.Error(w, "Suite required", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		status, err := s.client.GetSLOStatus(r.Context(), userID, suite)
+		if errors.Is(err, gogent.ErrSLONotFound) {
+			http.Error(w, "SLO not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Printf("❌ Failed to get SLO status for suite %s: %v", suite, err)
+			http.Error(w, "Failed to get SLO status", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(status)
+
+	case http.MethodDelete:
+		err := s.client.DeleteSuiteSLO(r.Context(), userID, suite)
+		if errors.Is(err, gogent.ErrSLONotFound) {
+			http.Error(w, "SLO not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Printf("❌ Failed to delete SLO for suite %s: %v", suite, err)
+			http.Error(w, "Failed to delete SLO", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"message": "SLO deleted successfully",
+			"suite":   suite,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// =============================================================================
+// DOCUMENT ENDPOINTS
+// =============================================================================
+
+// documentsHandler lists the user's documents (one collection with ?collection=) and ingests
+// uploaded files. Uploads are multipart forms with a file field plus collection and the optional
+// chunkSize, chunkOverlap and embeddingModel fields.
+func (s *Server) documentsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		documents, err := s.client.ListDocuments(r.Context(), userID, r.URL.Query().Get("collection"))
+		if err != nil {
+			log.Printf("❌ Failed to list documents: %v", err)
+			http.Error(w, "Failed to list documents", http.StatusInternalServerError)
+			return
+		}
+		if documents == nil {
+			documents = []*types.Document{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"documents": documents,
+			"count":     len(documents),
+		})
+
+	case http.MethodPost:
+		// Leave room for the other form fields on top of the largest document
+		r.Body = http.MaxBytesReader(w, r.Body, gogent.MaxDocumentBytes+1<<20)
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid upload: %v", err), http.StatusBadRequest)
+			return
+		}
+		defer r.MultipartForm.RemoveAll()
+
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, "A file is required", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		data, err := io.ReadAll(file)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to read upload: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		collection := strings.TrimSpace(r.FormValue("collection"))
+		if collection == "" {
+			http.Error(w, "Collection required", http.StatusBadRequest)
+			return
+		}
+		var chunking types.ChunkingConfig
+		for field, value := range map[string]*int{"chunkSize": &chunking.Size, "chunkOverlap": &chunking.Overlap} {
+			if raw := r.FormValue(field); raw != "" {
+				parsed, err := strconv.Atoi(raw)
+				if err != nil {
+					http.Error(w, fmt.Sprintf("Invalid %s: %s", field, raw), http.StatusBadRequest)
+					return
+				}
+				*value = parsed
+			}
+		}
+		if err := gogent.ValidateChunking(&chunking); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		contentType, err := gogent.DocumentContentType(header.Filename, header.Header.Get("Content-Type"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+			return
+		}
+
+		document, err := s.client.IngestDocument(r.Context(), userID, collection, header.Filename, contentType,
+			data, r.FormValue("embeddingModel"), chunking)
+		if err != nil {
+			log.Printf("❌ Failed to ingest document %s: %v", header.Filename, err)
+			http.Error(w, fmt.Sprintf("Failed to ingest document: %v", err), http.StatusUnprocessableEntity)
+			return
+		}
+		log.Printf("📄 Ingested %s into collection %s as %d chunks", document.Name, document.Collection, document.ChunkCount)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(document)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// documentByIDHandler returns or deletes one document
+func (s *Server) documentByIDHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	documentID := strings.TrimPrefix(r.URL.Path, "/api/documents/")
+	if documentID == "" {
+		http.Error(w, "Document ID required", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		document, err := s.client.GetDocument(r.Context(), userID, documentID)
+		if errors.Is(err, gogent.ErrDocumentNotFound) {
+			http.Error(w, "Document not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Printf("❌ Failed to get document %s: %v", documentID, err)
+			http.Error(w, "Failed to get document", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(document)
+
+	case http.MethodDelete:
+		err := s.client.DeleteDocument(r.Context(), userID, documentID)
+		if errors.Is(err, gogent.ErrDocumentNotFound) {
+			http.Error(w, "Document not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Printf("❌ Failed to delete document %s: %v", documentID, err)
+			http.Error(w, "Failed to delete document", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"message": "Document deleted successfully",
+			"id":      documentID,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// =============================================================================
+// ADMIN ENDPOINTS
+// =============================================================================
+
+// adminExecutionRunsHandler lists execution runs across all users
+func (s *Server) adminExecutionRunsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit, offset, err := parsePage(r, 50)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	runs, err := s.client.ListAllExecutionRuns(r.Context(), limit, offset)
+	if err != nil {
+		log.Printf("❌ Failed to list all execution runs: %v", err)
+		http.Error(w, "Failed to list execution runs", http.StatusInternalServerError)
+		return
+	}
+	total, err := s.client.CountAllExecutionRuns(r.Context())
+	if err != nil {
+		log.Printf("❌ Failed to count all execution runs: %v", err)
+		http.Error(w, "Failed to list execution runs", http.StatusInternalServerError)
+		return
+	}
+
+	writePageHeaders(w, total, gogent.NextCursor(offset, len(runs), total))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(runs)
+}
+
+// adminSystemConfigurationsHandler lists the system-wide configurations
+func (s *Server) adminSystemConfigurationsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	configs, err := s.client.GetSystemConfigurations(r.Context())
+	if err != nil {
+		log.Printf("❌ Failed to load system configurations: %v", err)
+		http.Error(w, "Failed to load configurations", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(configs)
+}
+
+// adminStatsHandler reports database statistics across all users plus in-flight executions, the
+// queue's depth and the server-wide figures of the last ?days= days (30 by default)
+func (s *Server) adminStatsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	days := 0
+	if raw := r.URL.Query().Get("days"); raw != "" {
+		var err error
+		if days, err = strconv.Atoi(raw); err != nil || days < 1 {
+			http.Error(w, "days must be a positive number", http.StatusBadRequest)
+			return
+		}
+	}
+
+	stats, err := databaseStats(r.Context(), s.client, "")
+	if err != nil {
+		log.Printf("❌ Failed to get server stats: %v", err)
+		http.Error(w, "Failed to get server stats", http.StatusInternalServerError)
+		return
+	}
+	serverStats, err := s.client.GetServerStats(r.Context(), days)
+	if err != nil {
+		log.Printf("❌ Failed to get server stats: %v", err)
+		http.Error(w, "Failed to get server stats", http.StatusInternalServerError)
+		return
+	}
+	stats["from"] = serverStats.From
+	stats["totalUsers"] = serverStats.TotalUsers
+	stats["runsPerDay"] = serverStats.RunsPerDay
+	stats["providerErrorRates"] = serverStats.ProviderErrors
+	stats["topTokenConsumers"] = serverStats.TopTokenConsumers
+	stats["tableSizes"] = serverStats.TableSizes
+
+	queue := s.queue.Stats()
+	queueDepth := 0
+	for _, waiting := range queue.Waiting {
+		queueDepth += waiting
+	}
+	stats["queue"] = queue
+	stats["queueDepth"] = queueDepth
+
+	s.executionMutex.RLock()
+	activeExecutions := 0
+	for _, execution := range s.executions {
+		if execution.Status == "pending" || execution.Status == "running" {
+			activeExecutions++
+		}
+	}
+	stats["trackedExecutions"] = len(s.executions)
+	s.executionMutex.RUnlock()
+	stats["activeExecutions"] = activeExecutions
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+// adminAuditLogsHandler pages through the audit log, newest first, filtered by ?actor=, ?action=,
+// ?since= and ?until=
+func (s *Server) adminAuditLogsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit, offset, err := parsePage(r, 100)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	params := r.URL.Query()
+	query := types.AuditLogQuery{ActorID: params.Get("actor"), Action: params.Get("action"), Limit: limit, Offset: offset}
+	for name, bound := range map[string]*time.Time{"since": &query.Since, "until": &query.Until} {
+		raw := params.Get(name)
+		if raw == "" {
+			continue
+		}
+		if *bound, err = time.Parse(time.RFC3339, raw); err != nil {
+			if *bound, err = time.Parse(time.DateOnly, raw); err != nil {
+				http.Error(w, name+" must be an RFC 3339 time or a YYYY-MM-DD date", http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	events, total, err := s.client.ListAuditEvents(r.Context(), query)
+	if err != nil {
+		log.Printf("❌ Failed to list audit events: %v", err)
+		http.Error(w, "Failed to list audit events", http.StatusInternalServerError)
+		return
+	}
+
+	writePageHeaders(w, total, gogent.NextCursor(offset, len(events), total))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(events)
+}
+
+// adminDatabaseTableDataHandler returns raw table rows without user scoping
+func (s *Server) adminDatabaseTableDataHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	tableName := strings.TrimPrefix(r.URL.Path, "/api/admin/database/tables/")
+	if tableName == "" {
+		http.Error(w, "Table name required", http.StatusBadRequest)
+		return
+	}
+
+	query, err := parseTableQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.writeTableData(w, r, "", tableName, query)
+}
+
+// adminWorkspaceSettingsHandler reads or replaces the workspace-level defaults
+func (s *Server) adminWorkspaceSettingsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		settings, err := s.client.GetWorkspaceSettings(r.Context(), types.DefaultWorkspaceID)
+		if err != nil {
+			log.Printf("❌ Failed to load workspace settings: %v", err)
+			http.Error(w, "Failed to load workspace settings", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(settings)
+
+	case http.MethodPut:
+		userID, err := s.getUserID(r)
+		if err != nil {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var settings types.WorkspaceSettings
+		if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		settings.ID = types.DefaultWorkspaceID
+		settings.UpdatedBy = userID
+
+		if err := s.client.SaveWorkspaceSettings(r.Context(), &settings); err != nil {
+			log.Printf("❌ Failed to save workspace settings: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		log.Printf("⚙️ Workspace settings updated by %s", userID)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(settings)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// retentionPruneBatchPause spaces out the retention worker's delete batches
+const retentionPruneBatchPause = 100 * time.Millisecond
+
+// interruptedRunAge is how long a run may stay pending or running before the retention worker
+// treats it as interrupted; younger runs may still be executing on another server
+const interruptedRunAge = time.Hour
+
+// rollupRefreshInterval is how often the daily rollups the stats read are brought up to date
+const rollupRefreshInterval = 15 * time.Minute
+
+// startRollupWorker periodically refreshes the daily rollups, building them on its first tick
+func (s *Server) startRollupWorker(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for ; ; <-ticker.C {
+			refresh, err := s.client.RefreshDailyRollups(context.Background())
+			if errors.Is(err, gogent.ErrNoDatabase) {
+				return
+			}
+			if err != nil {
+				log.Printf("⚠️ Refreshing daily rollups failed: %v", err)
+				continue
+			}
+			if refresh.Since == "" {
+				log.Printf("📊 Built daily rollups for %d user-days", refresh.Rows)
+			}
+		}
+	}()
+}
+
+// telemetryInterval is how often a server that opted in sends telemetry, each report covering the
+// interval before it
+const telemetryInterval = 24 * time.Hour
+
+// startTelemetryWorker sends an anonymized usage report to the telemetry endpoint once per interval
+func (s *Server) startTelemetryWorker(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for until := range ticker.C {
+			report, err := s.client.BuildTelemetryReport(context.Background(), until.Add(-interval), until, s.telemetry.epsilon)
+			if errors.Is(err, gogent.ErrNoDatabase) {
+				return
+			}
+			if err == nil {
+				err = s.client.SendTelemetry(context.Background(), s.telemetry.endpoint, report)
+			}
+			if err != nil {
+				log.Printf("⚠️ Sending telemetry failed: %v", err)
+			}
+		}
+	}()
+}
+
+// telemetryPreview is what a server would send as telemetry, whether or not it opted in
+type telemetryPreview struct {
+	Enabled  bool                   `json:"enabled"`
+	Endpoint string                 `json:"endpoint,omitempty"`
+	Interval string                 `json:"interval"`
+	Report   *types.TelemetryReport `json:"report"`
+}
+
+// adminTelemetryHandler previews the telemetry report covering the last interval, with the noise
+// the next report would get
+func (s *Server) adminTelemetryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	now := time.Now()
+	report, err := s.client.BuildTelemetryReport(r.Context(), now.Add(-telemetryInterval), now, s.telemetry.epsilon)
+	if err != nil {
+		log.Printf("❌ Failed to build telemetry preview: %v", err)
+		http.Error(w, "Failed to build telemetry preview", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(telemetryPreview{
+		Enabled:  s.telemetry.enabled,
+		Endpoint: s.telemetry.endpoint,
+		Interval: telemetryInterval.String(),
+		Report:   report,
+	})
+}
+
+// startRetentionWorker periodically repairs runs a stopped process left unfinished and prunes
+// execution history past the workspace and user retention policies, archiving pruned runs to the
+// sink when one is set
+func (s *Server) startRetentionWorker(interval time.Duration, sink gogent.ArchiveSink) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for ; ; <-ticker.C {
+			repair, err := s.client.RepairInterruptedRuns(context.Background(), time.Now().Add(-interruptedRunAge))
+			if err != nil {
+				log.Printf("⚠️ Repairing interrupted runs failed: %v", err)
+			} else if repair.RunsFailed > 0 {
+				log.Printf("🧹 Marked %d interrupted runs failed and deleted %d unanswered requests",
+					repair.RunsFailed, repair.Deleted["api_requests"])
+			}
+
+			report, err := s.client.PruneExpiredHistory(context.Background(), gogent.PruneOptions{Pause: retentionPruneBatchPause, Sink: sink})
+			if err != nil {
+				log.Printf("⚠️ Retention worker failed: %v", err)
+			}
+			if report != nil {
+				for table, deleted := range report.Deleted {
+					if deleted > 0 {
+						log.Printf("🧹 Pruned %d expired rows from %s", deleted, table)
+					}
+				}
+				if report.Archived > 0 {
+					log.Printf("📦 Archived %d pruned rows", report.Archived)
+				}
+			}
+		}
+	}()
+}
+
+// adminRetentionPoliciesHandler lists, sets and removes per-user retention policies
+func (s *Server) adminRetentionPoliciesHandler(w http.ResponseWriter, r *http.Request) {
+	targetUserID := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/api/admin/retention-policies"), "/")
+
+	switch {
+	case r.Method == http.MethodGet && targetUserID == "":
+		policies, err := s.client.ListUserRetentionPolicies(r.Context())
+		if err != nil {
+			log.Printf("❌ Failed to list retention policies: %v", err)
+			http.Error(w, "Failed to list retention policies", http.StatusInternalServerError)
+			return
+		}
+		if policies == nil {
+			policies = []types.UserRetentionPolicy{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"policies": policies})
+
+	case r.Method == http.MethodPut && targetUserID != "":
+		userID, err := s.getUserID(r)
+		if err != nil {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var policy types.RetentionPolicy
+		if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		userPolicy := &types.UserRetentionPolicy{UserID: targetUserID, Policy: policy, UpdatedBy: userID}
+		if err := s.client.SaveUserRetentionPolicy(r.Context(), userPolicy); err != nil {
+			log.Printf("❌ Failed to save retention policy: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		log.Printf("🧹 Retention policy for %s updated by %s", targetUserID, userID)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(userPolicy)
+
+	case r.Method == http.MethodDelete && targetUserID != "":
+		if err := s.client.DeleteUserRetentionPolicy(r.Context(), targetUserID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				http.Error(w, "Retention policy not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("❌ Failed to delete retention policy: %v", err)
+			http.Error(w, "Failed to delete retention policy", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// reportsHandler lists the saved reports (GET /api/reports) and runs one with its parameters taken
+// from the query string (GET /api/reports/{name}?param=...)
+func (s *Server) reportsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/api/reports"), "/")
+	if name == "" {
+		s.writeReports(w, r)
+		return
+	}
+
+	arguments := make(map[string]string)
+	for key, values := range r.URL.Query() {
+		arguments[key] = values[len(values)-1]
+	}
+	result, err := s.client.RunReport(r.Context(), userID, name, arguments)
+	switch {
+	case errors.Is(err, gogent.ErrReportNotFound):
+		http.Error(w, "Report not found", http.StatusNotFound)
+		return
+	case errors.Is(err, gogent.ErrInvalidReportArguments):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case err != nil:
+		log.Printf("❌ Failed to run report %s: %v", name, err)
+		http.Error(w, "Failed to run report", http.StatusInternalServerError)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(tables)
+	json.NewEncoder(w).Encode(result)
+}
+
+// writeReports writes every saved report
+func (s *Server) writeReports(w http.ResponseWriter, r *http.Request) {
+	reports, err := s.client.ListReports(r.Context())
+	if err != nil {
+		log.Printf("❌ Failed to list reports: %v", err)
+		http.Error(w, "Failed to list reports", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"reports": reports})
+}
+
+// adminReportsHandler lists the saved reports (GET /api/admin/reports), creates or replaces one
+// (PUT /api/admin/reports/{name}) and deletes one (DELETE /api/admin/reports/{name})
+func (s *Server) adminReportsHandler(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/api/admin/reports"), "/")
+
+	switch {
+	case r.Method == http.MethodGet && name == "":
+		s.writeReports(w, r)
+
+	case r.Method == http.MethodPut && name != "":
+		userID, err := s.getUserID(r)
+		if err != nil {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var report types.SavedReport
+		if err := json.NewDecoder(r.Body).Decode(&report); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		report.Name, report.CreatedBy = name, userID
+		if err := s.client.SaveReport(r.Context(), &report); err != nil {
+			if errors.Is(err, gogent.ErrInvalidReport) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			log.Printf("❌ Failed to save report: %v", err)
+			http.Error(w, "Failed to save report", http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("📈 Report %s saved by %s", name, userID)
+		s.audit(r, &types.AuditEvent{Action: types.AuditReportSaved, TargetType: "report", TargetID: report.ID,
+			Summary: map[string]interface{}{"name": name}})
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(report)
+
+	case r.Method == http.MethodDelete && name != "":
+		if err := s.client.DeleteReport(r.Context(), name); err != nil {
+			if errors.Is(err, gogent.ErrReportNotFound) {
+				http.Error(w, "Report not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("❌ Failed to delete report: %v", err)
+			http.Error(w, "Failed to delete report", http.StatusInternalServerError)
+			return
+		}
+		s.audit(r, &types.AuditEvent{Action: types.AuditReportDeleted, TargetType: "report",
+			Summary: map[string]interface{}{"name": name}})
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
 }
 
 // CORS middleware
@@ -1412,7 +3596,8 @@ func (s *Server) enableCORS(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Gemini-API-Key, X-OpenWeather-API-Key, X-Neo4j-URL, X-Neo4j-Username, X-Neo4j-Password, X-Neo4j-Database, X-Use-Mock")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Gemini-API-Key, X-OpenWeather-API-Key, X-Neo4j-URL, X-Neo4j-Username, X-Neo4j-Password, X-Neo4j-Database, X-Web-Search-API-Key, X-SQL-DSN, X-Use-Mock")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, X-Next-Cursor")
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
@@ -1437,37 +3622,127 @@ func runServer() {
 	// Set up routes - public endpoints
 	http.HandleFunc("/health", server.enableCORS(server.healthHandler))
 	http.HandleFunc("/test", server.enableCORS(server.testHandler))
+	http.HandleFunc("/api/openapi.json", server.enableCORS(server.openAPIHandler))
+	http.HandleFunc("/api/docs", server.enableCORS(server.swaggerUIHandler))
 
 	// Auth endpoints
 	http.HandleFunc("/api/auth/register", server.enableCORS(server.authHandlers.RegisterHandler))
 	http.HandleFunc("/api/auth/login", server.enableCORS(server.authHandlers.LoginHandler))
 	http.HandleFunc("/api/auth/temp-user", server.enableCORS(server.authHandlers.CreateTemporaryUserHandler))
 	http.HandleFunc("/api/auth/verify-email", server.enableCORS(server.authHandlers.VerifyEmailHandler))
+	http.HandleFunc("/api/auth/refresh", server.enableCORS(server.authHandlers.RefreshHandler))
+	http.HandleFunc("/api/auth/logout", server.enableCORS(server.authHandlers.LogoutHandler))
+	http.HandleFunc("/api/auth/password-reset/request", server.enableCORS(server.authHandlers.RequestPasswordResetHandler))
+	http.HandleFunc("/api/auth/password-reset/confirm", server.enableCORS(server.authHandlers.ResetPasswordHandler))
 
 	// Protected auth endpoints
 	http.HandleFunc("/api/auth/current", server.enableCORS(authMiddleware(server.authHandlers.GetCurrentUserHandler)))
 	http.HandleFunc("/api/auth/save-temp", server.enableCORS(authMiddleware(server.authHandlers.SaveTemporaryAccountHandler)))
 	http.HandleFunc("/api/auth/connect-temp-account", server.enableCORS(authMiddleware(server.authHandlers.ConnectTemporaryAccountHandler)))
+	http.HandleFunc("/api/auth/api-keys", server.enableCORS(authMiddleware(server.authHandlers.APIKeysHandler)))
+	http.HandleFunc("/api/auth/api-keys/", server.enableCORS(authMiddleware(server.authHandlers.APIKeyByIDHandler)))
 
 	// Protected data endpoints - require authentication
 	http.HandleFunc("/api/execute", server.enableCORS(authMiddleware(server.executeHandler)))
+	http.HandleFunc("/api/execute/spec", server.enableCORS(authMiddleware(server.executeSpecHandler)))
+	http.HandleFunc("/api/execute/estimate", server.enableCORS(authMiddleware(server.estimateExecutionHandler)))
 	http.HandleFunc("/api/execution-runs/", server.enableCORS(authMiddleware(server.executionRunsHandler)))          // Note the trailing slash
 	http.HandleFunc("/api/execution-runs/status/", server.enableCORS(authMiddleware(server.executionStatusHandler))) // Status endpoint
 	http.HandleFunc("/api/execution-runs", server.enableCORS(authMiddleware(server.executionRunsHandler)))
+	http.HandleFunc("/api/comparisons", server.enableCORS(authMiddleware(server.comparisonsHandler)))
 
 	// Protected function management endpoints
 	http.HandleFunc("/api/functions", server.enableCORS(authMiddleware(server.functionsHandler)))
 	http.HandleFunc("/api/functions/", server.enableCORS(authMiddleware(server.functionByIDHandler)))
 	http.HandleFunc("/api/functions/test/", server.enableCORS(authMiddleware(server.testFunctionHandler)))
+	http.HandleFunc("/api/functions/templates", server.enableCORS(authMiddleware(server.functionTemplatesHandler)))
+	http.HandleFunc("/api/functions/templates/", server.enableCORS(authMiddleware(server.functionTemplatesHandler)))
+	http.HandleFunc("/api/functions/import/openapi", server.enableCORS(authMiddleware(server.importOpenAPIHandler)))
 
 	// Protected configuration management endpoints
 	http.HandleFunc("/api/configurations", server.enableCORS(authMiddleware(server.configurationsHandler)))
+	http.HandleFunc("/api/configurations/", server.enableCORS(authMiddleware(server.configurationByIDHandler)))
+	http.HandleFunc("/api/prompts", server.enableCORS(authMiddleware(server.promptsHandler)))
+	http.HandleFunc("/api/prompts/", server.enableCORS(authMiddleware(server.promptByIDHandler)))
+
+	// SLO endpoints (protected)
+	http.HandleFunc("/api/slos", server.enableCORS(authMiddleware(server.slosHandler)))
+	http.HandleFunc("/api/slos/", server.enableCORS(authMiddleware(server.sloBySuiteHandler)))
+	http.HandleFunc("/api/trends", server.enableCORS(authMiddleware(server.trendsHandler)))
+	http.HandleFunc("/api/leaderboard", server.enableCORS(authMiddleware(server.leaderboardHandler)))
+	http.HandleFunc("/api/reviews", server.enableCORS(authMiddleware(server.reviewsHandler)))
+	http.HandleFunc("/api/reviews/", server.enableCORS(authMiddleware(server.reviewsHandler)))
+	http.HandleFunc("/api/workspaces", server.enableCORS(authMiddleware(server.workspacesHandler)))
+	http.HandleFunc("/api/workspaces/", server.enableCORS(authMiddleware(server.workspacesHandler)))
+
+	// Document endpoints for retrieval (protected)
+	http.HandleFunc("/api/documents", server.enableCORS(authMiddleware(server.documentsHandler)))
+	http.HandleFunc("/api/documents/", server.enableCORS(authMiddleware(server.documentByIDHandler)))
+
+	// Model catalog (protected)
+	http.HandleFunc("/api/models", server.enableCORS(authMiddleware(server.modelsHandler)))
+	http.HandleFunc("/api/quota", server.enableCORS(authMiddleware(server.quotaHandler)))
+	http.HandleFunc("/api/usage", server.enableCORS(authMiddleware(server.usageHandler)))
+
+	// Semantic search over past executions (protected)
+	http.HandleFunc("/api/search", server.enableCORS(authMiddleware(server.searchHandler)))
+
+	// Saved reports admins define (protected)
+	http.HandleFunc("/api/reports", server.enableCORS(authMiddleware(server.reportsHandler)))
+	http.HandleFunc("/api/reports/", server.enableCORS(authMiddleware(server.reportsHandler)))
 
 	// Protected database endpoints
 	http.HandleFunc("/api/database/stats", server.enableCORS(authMiddleware(server.databaseStatsHandler)))
 	http.HandleFunc("/api/database/tables/", server.enableCORS(authMiddleware(server.databaseTableDataHandler))) // Specific table data
 	http.HandleFunc("/api/database/tables", server.enableCORS(authMiddleware(server.databaseTablesHandler)))     // List tables
 
+	// Admin-only routes
+	http.HandleFunc("/api/admin/users/role", server.enableCORS(authMiddleware(auth.RequireAdmin(server.authHandlers.SetUserRoleHandler))))
+	http.HandleFunc("/api/admin/execution-runs", server.enableCORS(authMiddleware(auth.RequireAdmin(server.adminExecutionRunsHandler))))
+	http.HandleFunc("/api/admin/configurations/system", server.enableCORS(authMiddleware(auth.RequireAdmin(server.adminSystemConfigurationsHandler))))
+	http.HandleFunc("/api/admin/stats", server.enableCORS(authMiddleware(auth.RequireAdmin(server.adminStatsHandler))))
+	http.HandleFunc("/api/admin/audit-logs", server.enableCORS(authMiddleware(auth.RequireAdmin(server.adminAuditLogsHandler))))
+	http.HandleFunc("/api/admin/database/tables/", server.enableCORS(authMiddleware(auth.RequireAdmin(server.adminDatabaseTableDataHandler))))
+	http.HandleFunc("/api/admin/workspace-settings", server.enableCORS(authMiddleware(auth.RequireAdmin(server.adminWorkspaceSettingsHandler))))
+	http.HandleFunc("/api/admin/retention-policies", server.enableCORS(authMiddleware(auth.RequireAdmin(server.adminRetentionPoliciesHandler))))
+	http.HandleFunc("/api/admin/retention-policies/", server.enableCORS(authMiddleware(auth.RequireAdmin(server.adminRetentionPoliciesHandler))))
+	http.HandleFunc("/api/admin/reports", server.enableCORS(authMiddleware(auth.RequireAdmin(server.adminReportsHandler))))
+	http.HandleFunc("/api/admin/reports/", server.enableCORS(authMiddleware(auth.RequireAdmin(server.adminReportsHandler))))
+	http.HandleFunc("/api/admin/telemetry", server.enableCORS(authMiddleware(auth.RequireAdmin(server.adminTelemetryHandler))))
+
+	// Optional GraphQL endpoint over runs, comparisons and functions
+	if os.Getenv("GRAPHQL_ENABLED") == "true" {
+		server.graphql = server.graphqlSchema()
+		http.HandleFunc("/graphql", server.enableCORS(authMiddleware(server.graphqlHandler)))
+	}
+
+	// Embedded dashboard - signs in with a cookie instead of the Authorization header
+	http.HandleFunc("/ui/login", server.dashboardLoginHandler)
+	http.HandleFunc("/ui/logout", server.dashboardLogoutHandler)
+	http.HandleFunc("/ui/runs/", server.dashboardAuth(server.dashboardRunHandler))
+	http.HandleFunc("/ui/", server.dashboardAuth(server.dashboardRunsHandler))
+	http.HandleFunc("/ui", server.dashboardAuth(server.dashboardRunsHandler))
+
+	// Background pruning of execution history past the retention policies
+	var archiveSink gogent.ArchiveSink
+	if location := os.Getenv("ARCHIVE_SINK"); location != "" {
+		if archiveSink, err = newArchiveSink(location); err != nil {
+			log.Fatalf("Failed to configure the archive sink: %v", err)
+		}
+		log.Printf("📦 Pruned runs are archived to %s", location)
+	}
+	server.startRetentionWorker(time.Hour, archiveSink)
+	server.startRollupWorker(rollupRefreshInterval)
+
+	// Anonymized usage reporting, off unless the operator opts in
+	if server.telemetry, err = loadTelemetrySettings(); err != nil {
+		log.Fatalf("Failed to configure telemetry: %v", err)
+	}
+	if server.telemetry.enabled {
+		log.Printf("📡 Telemetry is on: anonymized usage counts are sent to %s every %s; set TELEMETRY_ENABLED=false to stop", server.telemetry.endpoint, telemetryInterval)
+		server.startTelemetryWorker(telemetryInterval)
+	}
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
@@ -1475,27 +3750,112 @@ func runServer() {
 
 	fmt.Printf("🚀 GoGent HTTP Server starting on port %s\n", port)
 	fmt.Printf("📡 Health check: http://localhost:%s/health\n", port)
+	fmt.Printf("🖥️ Dashboard: http://localhost:%s/ui\n", port)
+	fmt.Printf("📖 API docs: http://localhost:%s/api/docs (OpenAPI document at /api/openapi.json)\n", port)
 	fmt.Printf("🔧 API endpoints:\n")
 	fmt.Printf("   POST /api/execute - Multi-variation execution (🔐 Protected)\n")
+	fmt.Printf("   POST /api/execute/spec - Execute a YAML or JSON run spec (🔐 Protected)\n")
+	fmt.Printf("   POST /api/execute/estimate - Token and cost estimate before running (🔐 Protected)\n")
 	fmt.Printf("   GET  /api/execution-runs - Execution history (🔐 Protected)\n")
+	fmt.Printf("   GET  /api/comparisons - Comparisons of your runs (🔐 Protected)\n")
+	fmt.Printf("   GET  /api/execution-runs/{id}/comparison - Comparison of one run (🔐 Protected)\n")
+	fmt.Printf("   GET  /api/execution-runs/{id}/logs - Execution logs of one run, filtered by level, category and after (🔐 Protected)\n")
+	fmt.Printf("   GET  /api/execution-runs/{id}/raw-payloads - Stored provider request and response bodies of one run, by requestId (🔐 Protected)\n")
+	fmt.Printf("   GET  /api/execution-runs/{id}/diff - Word-level diff and metric deltas of two configurations, a and b (🔐 Protected)\n")
+	fmt.Printf("   GET|PUT|DELETE /api/execution-runs/{id}/feedback - Human feedback on a run's responses and its aggregate per configuration (🔐 Protected)\n")
+	fmt.Printf("   POST /api/execution-runs/{id}/reviews - Start a blind review of a run's responses (🔐 Protected)\n")
+	fmt.Printf("   POST /api/execution-runs/{id}/replay - Replay a run with its recorded function responses (🔐 Protected)\n")
+	fmt.Printf("   POST /api/execution-runs/{id}/clone - Rebuild a run's request for editing and resubmitting (🔐 Protected)\n")
 	fmt.Printf("   POST /api/auth/register - User registration\n")
 	fmt.Printf("   POST /api/auth/login - User login\n")
+	fmt.Printf("   POST /api/auth/refresh - Exchange a refresh token for a new access token\n")
+	fmt.Printf("   POST /api/auth/logout - Revoke the current session\n")
+	fmt.Printf("   POST /api/auth/password-reset/request - Email a password reset link\n")
+	fmt.Printf("   POST /api/auth/password-reset/confirm - Set a new password with a reset token\n")
 	fmt.Printf("   GET  /api/auth/current - Get current user (🔐 Protected)\n")
-	fmt.Printf("   GET  /api/configurations - List API configurations (🔐 Protected)\n")
+	fmt.Printf("   GET  /api/auth/api-keys - List API keys (🔐 Protected)\n")
+	fmt.Printf("   POST /api/auth/api-keys - Create API key (🔐 Protected)\n")
+	fmt.Printf("   DELETE /api/auth/api-keys/{id} - Revoke API key (🔐 Protected)\n")
+	fmt.Printf("   GET  /api/configurations - List configuration presets (🔐 Protected)\n")
+	fmt.Printf("   POST /api/configurations - Create a configuration preset (🔐 Protected)\n")
+	fmt.Printf("   GET  /api/configurations/{id} - Get a configuration preset (🔐 Protected)\n")
+	fmt.Printf("   PUT  /api/configurations/{id} - Update a configuration preset (🔐 Protected)\n")
+	fmt.Printf("   DELETE /api/configurations/{id} - Delete a configuration preset (🔐 Protected)\n")
 	fmt.Printf("   GET  /api/functions - List function definitions (🔐 Protected)\n")
 	fmt.Printf("   POST /api/functions - Create function definition (🔐 Protected)\n")
 	fmt.Printf("   GET  /api/functions/{id} - Get function by ID (🔐 Protected)\n")
 	fmt.Printf("   PUT  /api/functions/{id} - Update function (🔐 Protected)\n")
 	fmt.Printf("   DELETE /api/functions/{id} - Delete function (🔐 Protected)\n")
 	fmt.Printf("   POST /api/functions/test/{id} - Test function execution (🔐 Protected)\n")
+	fmt.Printf("   GET  /api/functions/templates - Ready-made functions to copy (🔐 Protected)\n")
+	fmt.Printf("   POST /api/functions/templates/{id}/copy - Copy a template into your functions (🔐 Protected)\n")
+	fmt.Printf("   POST /api/functions/import/openapi - Create functions from an OpenAPI 3 spec (🔐 Protected)\n")
+	fmt.Printf("   GET  /api/slos - SLO statuses and burn rates, ?alerting=true for alerts (🔐 Protected)\n")
+	fmt.Printf("   PUT  /api/slos - Create or update a suite's SLO (🔐 Protected)\n")
+	fmt.Printf("   GET  /api/slos/{suite} - SLO status of a suite (🔐 Protected)\n")
+	fmt.Printf("   DELETE /api/slos/{suite} - Delete a suite's SLO (🔐 Protected)\n")
+	fmt.Printf("   GET  /api/trends?preset=|variation= - Overall score, latency and cost of a configuration across runs (🔐 Protected)\n")
+	fmt.Printf("   GET  /api/leaderboard?tag= - Configurations ranked by average overall score across tagged runs (🔐 Protected)\n")
+	fmt.Printf("   GET  /api/reviews - Reviews of your runs and reviews assigned to you (🔐 Protected)\n")
+	fmt.Printf("   GET  /api/reviews/{id} - Progress of a review of your run (🔐 Protected)\n")
+	fmt.Printf("   GET  /api/reviews/{id}/task - A review assigned to you, configurations hidden (🔐 Protected)\n")
+	fmt.Printf("   PUT  /api/reviews/{id}/judgments - Submit your ranks or labels (🔐 Protected)\n")
+	fmt.Printf("   POST /api/reviews/{id}/close - Close a review before every reviewer has submitted (🔐 Protected)\n")
+	fmt.Printf("   GET  /api/reviews/{id}/results - Reveal a finished review's configurations and agreement (🔐 Protected)\n")
+	fmt.Printf("   GET|POST /api/workspaces - Your workspaces, or create one (🔐 Protected)\n")
+	fmt.Printf("   GET  /api/workspaces/{id} - A workspace and its members (🔐 Protected)\n")
+	fmt.Printf("   PUT  /api/workspaces/{id}/members - Add a member or change their role (🔐 Protected)\n")
+	fmt.Printf("   DELETE /api/workspaces/{id}/members/{username} - Remove a member or leave (🔐 Protected)\n")
+	fmt.Printf("   GET|POST /api/workspaces/{id}/resources - Shared runs, functions and presets, or share one (🔐 Protected)\n")
+	fmt.Printf("   GET|DELETE /api/workspaces/{id}/resources/{type}/{resourceId} - Open or unshare a shared resource (🔐 Protected)\n")
+	fmt.Printf("   GET  /api/models - Model catalog, ?method=generateContent to filter, ?refresh=true to refetch (🔐 Protected)\n")
+	fmt.Printf("   GET  /api/quota - Quota limits, executions in flight and tokens used today (🔐 Protected)\n")
+	fmt.Printf("   GET  /api/usage?from=&to=&groupBy= - Token usage and estimated cost by day and model (🔐 Protected)\n")
+	fmt.Printf("   GET  /api/search?q=... - Semantic search over past prompts and responses (🔐 Protected)\n")
+	fmt.Printf("   GET  /api/reports - Saved reports (🔐 Protected)\n")
+	fmt.Printf("   GET  /api/reports/{name}?param=... - Run a saved report (🔐 Protected)\n")
 	fmt.Printf("   GET  /api/database/stats - Database statistics (🔐 Protected)\n")
+	if server.graphql != nil {
+		fmt.Printf("   POST /graphql - GraphQL queries and mutations over runs, comparisons and functions; GET returns the schema (🔐 Protected)\n")
+	}
 	fmt.Printf("   GET  /api/database/tables - Database tables (🔐 Protected)\n")
+	fmt.Printf("   PUT  /api/admin/users/role - Change a user's role (🛡️ Admin)\n")
+	fmt.Printf("   GET  /api/admin/execution-runs - All users' execution runs (🛡️ Admin)\n")
+	fmt.Printf("   GET  /api/admin/configurations/system - System configurations (🛡️ Admin)\n")
+	fmt.Printf("   GET  /api/admin/stats - Server-wide statistics (🛡️ Admin)\n")
+	fmt.Printf("   GET  /api/admin/audit-logs - Audit log of user actions (🛡️ Admin)\n")
+	fmt.Printf("   GET  /api/admin/database/tables/{name} - Raw table browsing (🛡️ Admin)\n")
+	fmt.Printf("   GET  /api/admin/workspace-settings - Workspace defaults (🛡️ Admin)\n")
+	fmt.Printf("   PUT  /api/admin/workspace-settings - Update workspace defaults (🛡️ Admin)\n")
+	fmt.Printf("   GET  /api/admin/retention-policies - Per-user retention policies (🛡️ Admin)\n")
+	fmt.Printf("   PUT  /api/admin/retention-policies/{userId} - Set a user's retention policy (🛡️ Admin)\n")
+	fmt.Printf("   DELETE /api/admin/retention-policies/{userId} - Return a user to the workspace policy (🛡️ Admin)\n")
+	fmt.Printf("   GET  /api/admin/reports - Saved reports (🛡️ Admin)\n")
+	fmt.Printf("   PUT|DELETE /api/admin/reports/{name} - Save or delete a report (🛡️ Admin)\n")
+	fmt.Printf("   GET  /api/admin/telemetry - Preview the anonymized telemetry report and whether it is sent (🛡️ Admin)\n")
 	fmt.Printf("💡 Use X-Use-Mock: true header for mock responses\n")
 	fmt.Printf("🔑 Set GEMINI_API_KEY in config.env for real API calls\n")
 	fmt.Printf("🔐 Most endpoints now require authentication\n")
 	fmt.Println()
 
-	log.Fatal(http.ListenAndServe(":"+port, nil))
+	httpServer := &http.Server{Addr: ":" + port}
+	go func() {
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+
+	// Stop taking requests, then let queued and running executions finish
+	waitForShutdownSignal()
+	ctx, cancel := context.WithTimeout(context.Background(), executionDrainTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(ctx); err != nil {
+		log.Printf("⚠️ Warning: HTTP server shutdown: %v", err)
+	}
+	log.Printf("⏳ Draining execution queue")
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("⚠️ Warning: executions still queued at shutdown were dropped: %v", err)
+	}
 }
 
 // createMockExecutionResult creates mock detailed data based on a real execution run
@@ -1628,6 +3988,174 @@ func (s *Server) functionByIDHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// functionTemplatesHandler lists the function templates (GET /api/functions/templates), returns one
+// (GET /api/functions/templates/{id}) and copies one into the user's functions
+// (POST /api/functions/templates/{id}/copy, optionally with {"name": ...})
+func (s *Server) functionTemplatesHandler(w http.ResponseWriter, r *http.Request) {
+	templateID := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/api/functions/templates"), "/")
+	if copied, ok := strings.CutSuffix(templateID, "/copy"); ok {
+		s.copyFunctionTemplate(w, r, copied)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if templateID == "" {
+		templates, err := s.client.ListFunctionTemplates(r.Context())
+		if err != nil {
+			log.Printf("❌ Failed to list function templates: %v", err)
+			http.Error(w, "Failed to list function templates", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+			"data":    templates,
+		})
+		return
+	}
+
+	template, err := s.client.GetFunctionTemplate(r.Context(), templateID)
+	if errors.Is(err, gogent.ErrFunctionTemplateNotFound) {
+		http.Error(w, "Function template not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Failed to get function template %s: %v", templateID, err)
+		http.Error(w, "Failed to get function template", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"data":    template,
+	})
+}
+
+// copyFunctionTemplate creates a function definition owned by the user from a template
+func (s *Server) copyFunctionTemplate(w http.ResponseWriter, r *http.Request, templateID string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// The body is optional; without one the copy keeps the template's name
+	var body struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	template, err := s.client.GetFunctionTemplate(r.Context(), templateID)
+	if errors.Is(err, gogent.ErrFunctionTemplateNotFound) {
+		http.Error(w, "Function template not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Failed to get function template %s: %v", templateID, err)
+		http.Error(w, "Failed to get function template", http.StatusInternalServerError)
+		return
+	}
+
+	function := gogent.FunctionFromTemplate(template, body.Name)
+	if err := gogent.ValidateFunctionDefinition(function); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	created, err := s.client.CreateFunctionDefinition(r.Context(), userID, function)
+	if err != nil {
+		writeFunctionError(w, "create", err)
+		return
+	}
+
+	log.Printf("✅ Function copied from template %s: %s", templateID, created.Name)
+	s.audit(r, &types.AuditEvent{Action: types.AuditFunctionCreated, TargetType: "function", TargetID: created.ID,
+		Summary: map[string]interface{}{"name": created.Name, "template": templateID}})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"data":    created,
+		"message": "Function copied from template",
+	})
+}
+
+// importOpenAPIHandler creates function definitions from an OpenAPI 3 spec. A JSON body is an
+// OpenAPIImportRequest; any other body is the spec itself, with ?operations= (comma-separated)
+// and ?dryRun=true.
+func (s *Server) importOpenAPIHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var request types.OpenAPIImportRequest
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+	} else {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to read request body: %v", err), http.StatusBadRequest)
+			return
+		}
+		request.Spec = string(body)
+		if operations := r.URL.Query().Get("operations"); operations != "" {
+			request.Operations = strings.Split(operations, ",")
+		}
+		request.DryRun = r.URL.Query().Get("dryRun") == "true"
+	}
+
+	result, err := s.client.ImportOpenAPIFunctions(r.Context(), userID, &request)
+	if errors.Is(err, gogent.ErrInvalidOpenAPISpec) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		writeFunctionError(w, "import", err)
+		return
+	}
+
+	status := http.StatusOK
+	if !request.DryRun {
+		for _, created := range result.Functions {
+			s.audit(r, &types.AuditEvent{Action: types.AuditFunctionCreated, TargetType: "function", TargetID: created.ID,
+				Summary: map[string]interface{}{"name": created.Name, "source": "openapi"}})
+		}
+		if len(result.Functions) > 0 {
+			status = http.StatusCreated
+		}
+	}
+	log.Printf("📥 Imported %d functions from an OpenAPI spec (%d skipped, dry run %v)", len(result.Functions), len(result.Skipped), request.DryRun)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"data":    result,
+	})
+}
+
 // testFunctionHandler handles function testing
 func (s *Server) testFunctionHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -1666,6 +4194,11 @@ func (s *Server) listFunctions(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := context.Background()
+	limit, offset, err := parsePage(r, gogent.MaxPageSize)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	// Query the database directly for function definitions
 	query := `
@@ -1674,10 +4207,11 @@ func (s *Server) listFunctions(w http.ResponseWriter, r *http.Request) {
 		       is_active, created_at, updated_at
 		FROM function_definitions
 		WHERE (user_id = ? OR user_id = 'system') AND is_active = true
-		ORDER BY display_name ASC
+		ORDER BY display_name ASC, id ASC
+		LIMIT ? OFFSET ?
 	`
 
-	rows, err := s.client.GetDB().QueryContext(ctx, query, userID)
+	rows, err := s.client.GetDB().QueryContext(ctx, query, userID, limit, offset)
 	if err != nil {
 		log.Printf("❌ Failed to query function definitions: %v", err)
 		http.Error(w, "Failed to query functions", http.StatusInternalServerError)
@@ -1753,17 +4287,33 @@ func (s *Server) listFunctions(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	total, err := s.client.CountFunctionDefinitions(ctx, userID)
+	if err != nil {
+		log.Printf("❌ Failed to count function definitions: %v", err)
+		http.Error(w, "Failed to query functions", http.StatusInternalServerError)
+		return
+	}
+
 	log.Printf("✅ Successfully loaded %d function definitions from database", len(functions))
 
+	nextCursor := gogent.NextCursor(offset, len(functions), total)
+	writePageHeaders(w, total, nextCursor)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"success": true,
-		"data":    functions,
+		"success":    true,
+		"data":       functions,
+		"totalCount": total,
+		"nextCursor": nextCursor,
 	})
 }
 
 // createFunction creates a new function definition
 func (s *Server) createFunction(w http.ResponseWriter, r *http.Request) {
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 	log.Printf("➕ Creating new function definition in database")
 
 	if s.client == nil {
@@ -1778,124 +4328,120 @@ func (s *Server) createFunction(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate required fields
-	if function.Name == "" || function.DisplayName == "" || function.Description == "" {
-		http.Error(w, "Name, DisplayName, and Description are required", http.StatusBadRequest)
+	if err := gogent.ValidateFunctionDefinition(&function); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Generate ID and timestamps
-	function.ID = fmt.Sprintf("func-%d", time.Now().Unix())
-	function.CreatedAt = time.Now()
-	function.UpdatedAt = time.Now()
-	function.IsActive = true
-
-	// TODO: Implement actual database insertion using raw SQL since sqlc queries aren't available
-	// For now, we'll simulate success but the function won't actually be stored
-	log.Printf("⚠️ Function creation simulated - database storage not implemented yet")
-	log.Printf("📝 Function details: %s (%s) - %s", function.DisplayName, function.Name, function.Description)
-
-	// In a real implementation, we would:
-	// 1. Execute INSERT INTO function_definitions (...) VALUES (...)
-	// 2. Handle any database errors
-	// 3. Return the created function
+	created, err := s.client.CreateFunctionDefinition(r.Context(), userID, &function)
+	if err != nil {
+		writeFunctionError(w, "create", err)
+		return
+	}
 
-	log.Printf("✅ Function created (simulated): %s (%s)", function.DisplayName, function.Name)
+	log.Printf("✅ Function created: %s (%s)", created.DisplayName, created.Name)
+	s.audit(r, &types.AuditEvent{Action: types.AuditFunctionCreated, TargetType: "function", TargetID: created.ID,
+		Summary: map[string]interface{}{"name": created.Name}})
 
 	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"success": true,
-		"data":    function,
-		"message": "Function created successfully (database storage pending implementation)",
+		"data":    created,
+		"message": "Function created successfully",
 	})
 }
 
 // getFunctionByID returns a specific function definition
 func (s *Server) getFunctionByID(w http.ResponseWriter, r *http.Request, functionID string) {
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 	log.Printf("🔍 Getting function by ID: %s", functionID)
 
-	// TODO: Implement database lookup
-	// For now, return mock data if ID matches
-	if functionID == "func-1" {
-		function := types.FunctionDefinition{
-			ID:          "func-1",
-			Name:        "get_weather",
-			DisplayName: "Get Weather",
-			Description: "Get current weather information for a location",
-			ParametersSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"location": map[string]interface{}{
-						"type":        "string",
-						"description": "The location to get weather for",
-					},
-					"units": map[string]interface{}{
-						"type":        "string",
-						"enum":        []string{"celsius", "fahrenheit"},
-						"description": "Temperature units",
-					},
-				},
-				"required": []string{"location"},
-			},
-			MockResponse: map[string]interface{}{
-				"temperature": 22,
-				"condition":   "sunny",
-				"humidity":    65,
-			},
-			EndpointURL: "https://api.weather.com/v1/current",
-			HttpMethod:  "GET",
-			IsActive:    true,
-			CreatedAt:   time.Now().Add(-24 * time.Hour),
-			UpdatedAt:   time.Now().Add(-1 * time.Hour),
-		}
+	if s.client == nil {
+		http.Error(w, "Database not available", http.StatusInternalServerError)
+		return
+	}
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"success": true,
-			"data":    function,
-		})
+	function, err := s.client.GetFunctionDefinition(r.Context(), userID, functionID)
+	if err != nil {
+		writeFunctionError(w, "get", err)
 		return
 	}
 
-	http.Error(w, "Function not found", http.StatusNotFound)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"data":    function,
+	})
 }
 
 // updateFunction updates an existing function definition
 func (s *Server) updateFunction(w http.ResponseWriter, r *http.Request, functionID string) {
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 	log.Printf("✏️ Updating function: %s", functionID)
 
+	if s.client == nil {
+		http.Error(w, "Database not available", http.StatusInternalServerError)
+		return
+	}
+
 	var function types.FunctionDefinition
 	if err := json.NewDecoder(r.Body).Decode(&function); err != nil {
 		http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
 		return
 	}
 
-	// Validate required fields
-	if function.Name == "" || function.DisplayName == "" || function.Description == "" {
-		http.Error(w, "Name, DisplayName, and Description are required", http.StatusBadRequest)
+	if err := gogent.ValidateFunctionDefinition(&function); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Set ID and update timestamp
-	function.ID = functionID
-	function.UpdatedAt = time.Now()
+	updated, err := s.client.UpdateFunctionDefinition(r.Context(), userID, functionID, &function)
+	if err != nil {
+		writeFunctionError(w, "update", err)
+		return
+	}
 
-	// TODO: Implement database update
-	log.Printf("✅ Updated function: %s (%s)", function.DisplayName, function.Name)
+	log.Printf("✅ Updated function: %s (%s)", updated.DisplayName, updated.Name)
+	s.audit(r, &types.AuditEvent{Action: types.AuditFunctionUpdated, TargetType: "function", TargetID: functionID,
+		Summary: map[string]interface{}{"name": updated.Name}})
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"success": true,
-		"data":    function,
+		"data":    updated,
 	})
 }
 
-// deleteFunction deletes a function definition
+// deleteFunction soft-deletes a function definition
 func (s *Server) deleteFunction(w http.ResponseWriter, r *http.Request, functionID string) {
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 	log.Printf("🗑️ Deleting function: %s", functionID)
 
-	// TODO: Implement database deletion (soft delete by setting is_active = false)
+	if s.client == nil {
+		http.Error(w, "Database not available", http.StatusInternalServerError)
+		return
+	}
+
+	if err := s.client.DeleteFunctionDefinition(r.Context(), userID, functionID); err != nil {
+		writeFunctionError(w, "delete", err)
+		return
+	}
+
 	log.Printf("✅ Deleted function: %s", functionID)
+	s.audit(r, &types.AuditEvent{Action: types.AuditFunctionDeleted, TargetType: "function", TargetID: functionID})
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
@@ -1904,10 +4450,33 @@ func (s *Server) deleteFunction(w http.ResponseWriter, r *http.Request, function
 	})
 }
 
+// writeFunctionError maps function definition errors to HTTP status codes
+func writeFunctionError(w http.ResponseWriter, action string, err error) {
+	switch {
+	case errors.Is(err, gogent.ErrFunctionNotFound):
+		http.Error(w, "Function not found", http.StatusNotFound)
+	case errors.Is(err, gogent.ErrFunctionNameTaken):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Printf("❌ Failed to %s function: %v", action, err)
+		http.Error(w, fmt.Sprintf("Failed to %s function", action), http.StatusInternalServerError)
+	}
+}
+
 // executeTestFunction tests a function with provided arguments
 func (s *Server) executeTestFunction(w http.ResponseWriter, r *http.Request, functionID string) {
+	userID, err := s.getUserID(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 	log.Printf("🧪 Testing function: %s", functionID)
 
+	if s.client == nil {
+		http.Error(w, "Database not available", http.StatusInternalServerError)
+		return
+	}
+
 	var testRequest struct {
 		Arguments   map[string]interface{} `json:"arguments"`
 		UseMockData bool                   `json:"useMockData"`
@@ -1919,51 +4488,15 @@ func (s *Server) executeTestFunction(w http.ResponseWriter, r *http.Request, fun
 		return
 	}
 
-	startTime := time.Now()
-
-	// For now, simulate function execution
-	var result map[string]interface{}
-	if testRequest.UseMockData {
-		// Return mock response based on function
-		switch functionID {
-		case "func-1": // get_weather
-			result = map[string]interface{}{
-				"success":         true,
-				"usedMockData":    true,
-				"executionTimeMs": int32(time.Since(startTime).Milliseconds()),
-				"response": map[string]interface{}{
-					"temperature": 22,
-					"condition":   "sunny",
-					"humidity":    65,
-					"location":    testRequest.Arguments["location"],
-				},
-			}
-		case "func-2": // send_email
-			result = map[string]interface{}{
-				"success":         true,
-				"usedMockData":    true,
-				"executionTimeMs": int32(time.Since(startTime).Milliseconds()),
-				"response": map[string]interface{}{
-					"status":    "sent",
-					"messageId": "mock_msg_123",
-					"to":        testRequest.Arguments["to"],
-				},
-			}
-		default:
-			result = map[string]interface{}{
-				"success":         true,
-				"usedMockData":    true,
-				"executionTimeMs": int32(time.Since(startTime).Milliseconds()),
-				"response": map[string]interface{}{
-					"status": "mock_success",
-					"data":   "Mock response generated",
-				},
-			}
+	timeout := time.Duration(testRequest.TimeoutMs) * time.Millisecond
+	result, err := s.client.TestFunctionDefinition(r.Context(), userID, functionID, testRequest.Arguments, testRequest.UseMockData, timeout)
+	if err != nil {
+		if errors.Is(err, gogent.ErrInvalidFunctionArguments) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
 		}
-	} else {
-		// Implement real function calling using Gemini API
-		result = s.executeRealFunctionTest(functionID, testRequest.Arguments)
-		result["executionTimeMs"] = int32(time.Since(startTime).Milliseconds())
+		writeFunctionError(w, "test", err)
+		return
 	}
 
 	log.Printf("✅ Function test completed: %s", functionID)
@@ -1972,25 +4505,6 @@ func (s *Server) executeTestFunction(w http.ResponseWriter, r *http.Request, fun
 	json.NewEncoder(w).Encode(result)
 }
 
-// executeRealFunctionTest executes a function test using the actual Gemini API
-func (s *Server) executeRealFunctionTest(functionID string, arguments map[string]interface{}) map[string]interface{} {
-	// For now, return a simplified implementation that works
-	log.Printf("🧪 Real function test requested for: %s with args: %+v", functionID, arguments)
-
-	// TODO: Implement proper real function testing once function methods are available
-	return map[string]interface{}{
-		"success":      true,
-		"usedMockData": false,
-		"response": map[string]interface{}{
-			"functionCalled": false,
-			"message":        "Real function testing implementation in progress. Function infrastructure needs to be completed first.",
-			"functionId":     functionID,
-			"providedArgs":   arguments,
-			"warning":        "Real API function testing will be implemented once the function management methods are available.",
-		},
-	}
-}
-
 // createGenericMockExecutionResult creates generic mock data when no real run is found
 func (s *Server) createGenericMockExecutionResult(runID string) *types.ExecutionResult {
 	temp1 := float32(0.2)
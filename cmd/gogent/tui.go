@@ -0,0 +1,503 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"gogent/internal/types"
+	pb "gogent/proto"
+)
+
+// tuiRefreshInterval is how often the runs screen reloads the run list
+const tuiRefreshInterval = 2 * time.Second
+
+// tuiMaxLogs caps the log entries the watch screen keeps
+const tuiMaxLogs = 1000
+
+// tuiCommand shows live execution runs in the terminal, talking to a server over gRPC. With no
+// arguments it lists recent runs to pick from; with an execution or run ID it watches that one; with
+// -f it submits a run spec and watches it.
+func tuiCommand(ctx context.Context, args []string) error {
+	var opts cliOptions
+	fs := flag.NewFlagSet("tui", flag.ContinueOnError)
+	opts.register(fs)
+	file := fs.String("f", "", "run spec to execute and watch (YAML or JSON)")
+	logLevel := fs.String("log-level", "INFO", "lowest level of the log entries to show: DEBUG, INFO, WARN or ERROR")
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 1 || (len(positional) == 1 && *file != "") {
+		return fmt.Errorf("usage: gogent tui [<execution-or-run-id> | -f spec.yaml] --server host:port")
+	}
+	if opts.server == "" {
+		return fmt.Errorf("gogent tui watches a server over gRPC; set --server or GOGENT_SERVER")
+	}
+	if _, err := types.ParseLogLevel(*logLevel); err != nil {
+		return err
+	}
+
+	backend, err := newRemoteBackend(&opts)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	screen := newTUIScreen(os.Stdout)
+	defer screen.close()
+	tui := &tuiApp{backend: backend, screen: screen, logLevel: strings.ToUpper(*logLevel), input: readTUIInput(os.Stdin)}
+
+	switch {
+	case *file != "":
+		request, err := loadExecutionRequest(*file)
+		if err != nil {
+			return err
+		}
+		resp, err := backend.start(ctx, request)
+		if err != nil {
+			return err
+		}
+		id := resp.ExecutionId
+		if resp.Merged {
+			id = resp.ExecutionRun.Id
+		}
+		return tui.watch(ctx, id, request.ExecutionRunName, false)
+	case len(positional) == 1:
+		return tui.watch(ctx, positional[0], "", false)
+	}
+	return tui.runs(ctx)
+}
+
+// tuiApp drives the screens of gogent tui
+type tuiApp struct {
+	backend  *remoteBackend
+	screen   *tuiScreen
+	logLevel string
+	input    <-chan string // Lines typed on stdin; closed at end of input
+}
+
+// runs shows the caller's recent runs, refreshed every few seconds, and watches the one picked by
+// number until q is entered or the context ends
+func (t *tuiApp) runs(ctx context.Context) error {
+	ticker := time.NewTicker(tuiRefreshInterval)
+	defer ticker.Stop()
+	for {
+		_, height := t.screen.size()
+		runs, err := t.backend.ListExecutionRuns(ctx, int32(max(height-6, 5)), 0)
+		if err != nil {
+			return err
+		}
+		t.screen.draw(renderTUIRuns(runs, time.Now()))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case line, ok := <-t.input:
+			if !ok {
+				// Nothing more can be picked, as when stdin is not a terminal, so keep the list
+				// refreshing until interrupted
+				t.input = nil
+				continue
+			}
+			line = strings.TrimSpace(line)
+			if line == "q" {
+				return nil
+			}
+			if n, err := strconv.Atoi(line); err == nil && n >= 1 && n <= len(runs) {
+				if err := t.watch(ctx, runs[n-1].ID, runs[n-1].Name, true); err != nil {
+					return err
+				}
+				if !t.waitForEnter(ctx) {
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// waitForEnter keeps the last screen up until a line is entered, reporting false when the TUI
+// should exit instead
+func (t *tuiApp) waitForEnter(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case line, ok := <-t.input:
+		return ok && strings.TrimSpace(line) != "q"
+	}
+}
+
+// watch follows one execution until it completes or fails, redrawing on every event. Executions
+// picked from the runs screen say that Enter goes back to it.
+func (t *tuiApp) watch(ctx context.Context, id, name string, fromRuns bool) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := t.backend.watch(ctx, id, t.logLevel)
+	if err != nil {
+		return err
+	}
+	view := &tuiWatchView{id: id, name: name, started: time.Now(), fromRuns: fromRuns}
+	events := make(chan *pb.ExecutionEvent)
+	streamErr := make(chan error, 1)
+	go func() {
+		for {
+			event, err := stream.Recv()
+			if err != nil {
+				streamErr <- err
+				return
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// Redraw every second as well, so the elapsed time keeps moving between events
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		width, height := t.screen.size()
+		t.screen.draw(view.render(width, height, time.Now()))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case event := <-events:
+			view.apply(event, t.backend.converter)
+		case err := <-streamErr:
+			if !errors.Is(err, io.EOF) {
+				view.err = err
+			}
+			view.done = true
+			width, height := t.screen.size()
+			t.screen.draw(view.render(width, height, time.Now()))
+			return nil
+		}
+	}
+}
+
+// tuiWatchView is what the watch screen knows about an execution
+type tuiWatchView struct {
+	id       string
+	name     string
+	started  time.Time
+	event    *pb.ExecutionEvent // Latest event
+	logs     []*pb.ExecutionLog
+	result   *types.ExecutionResult
+	done     bool
+	err      error
+	fromRuns bool // Opened from the runs screen
+}
+
+// apply folds an event into the view
+func (v *tuiWatchView) apply(event *pb.ExecutionEvent, converter *GRPCServer) {
+	v.event = event
+	v.logs = append(v.logs, event.Logs...)
+	if len(v.logs) > tuiMaxLogs {
+		v.logs = v.logs[len(v.logs)-tuiMaxLogs:]
+	}
+	if event.Result != nil {
+		v.result = convertProtoExecutionResultToInternal(converter, event.Result)
+		if v.name == "" {
+			v.name = v.result.ExecutionRun.Name
+		}
+	}
+}
+
+// render lays out the watch screen: status and progress, then the comparison once the execution
+// has completed, with the latest log entries filling the rest of the screen
+func (v *tuiWatchView) render(width, height int, now time.Time) []string {
+	title := v.name
+	if title == "" {
+		title = v.id
+	}
+	status, statusLine := "connecting", ""
+	if e := v.event; e != nil {
+		status = e.Status
+		statusLine = fmt.Sprintf("Execution %s", e.ExecutionId)
+		if e.ExecutionRunId != "" && e.ExecutionRunId != e.ExecutionId {
+			statusLine += fmt.Sprintf(" · run %s", e.ExecutionRunId)
+		}
+	}
+	lines := []string{
+		fmt.Sprintf("🎯 %s  %s %s · %s", title, tuiStatusIcon(status), status, formatTUIElapsed(now.Sub(v.started))),
+		statusLine,
+		"",
+	}
+
+	if e := v.event; e != nil {
+		switch {
+		case e.QueuePosition > 0:
+			lines = append(lines, fmt.Sprintf("⏳ Waiting in the queue at position %d", e.QueuePosition))
+		case e.TotalVariations > 0:
+			progress := fmt.Sprintf("%s %d/%d variations", tuiProgressBar(int(e.CompletedVariations), int(e.TotalVariations), 30),
+				e.CompletedVariations, e.TotalVariations)
+			if e.FailedVariations > 0 {
+				progress += fmt.Sprintf(" · %d failed", e.FailedVariations)
+			}
+			if e.LastVariation != "" && e.Status == "running" {
+				progress += " · finished " + e.LastVariation
+			}
+			lines = append(lines, progress)
+		default:
+			lines = append(lines, "Starting…")
+		}
+		if e.ErrorMessage != "" {
+			lines = append(lines, "❌ "+e.ErrorMessage)
+		}
+	}
+	if v.err != nil {
+		lines = append(lines, "❌ "+v.err.Error())
+	}
+
+	var results []string
+	if v.result != nil {
+		results = renderTUIResults(v.result)
+	}
+	footer := "Ctrl+C to quit"
+	switch {
+	case v.done && v.fromRuns:
+		footer = "Done · Enter to go back, q to quit"
+	case v.done:
+		footer = "Done"
+	}
+
+	// Logs get whatever room the header, results and footer leave
+	room := height - len(lines) - len(results) - 4
+	lines = append(lines, "", "Logs")
+	if room < 1 {
+		room = 1
+	}
+	logs := v.logs
+	if len(logs) > room {
+		logs = logs[len(logs)-room:]
+	}
+	if len(logs) == 0 {
+		lines = append(lines, "  (none yet)")
+	}
+	for _, entry := range logs {
+		lines = append(lines, fmt.Sprintf("  %s %-7s %-10s %s", entry.Timestamp.AsTime().Local().Format("15:04:05"),
+			entry.LogLevel, strings.ToLower(entry.LogCategory), entry.Message))
+	}
+	lines = append(lines, results...)
+	lines = append(lines, "", footer)
+	return fitTUILines(lines, width, height)
+}
+
+// renderTUIResults tabulates a completed execution's variations, best configuration first
+func renderTUIResults(result *types.ExecutionResult) []string {
+	lines := []string{"", "Results"}
+	variations := append([]types.VariationResult(nil), result.Results...)
+	best := ""
+	if result.Comparison != nil {
+		best = result.Comparison.BestConfigurationID
+	}
+	score := func(variation types.VariationResult) (float64, bool) {
+		if result.Comparison == nil {
+			return 0, false
+		}
+		scores, _ := result.Comparison.ConfigurationScores[variation.Configuration.VariationName].(map[string]interface{})
+		overall, ok := scores["overall_score"].(float64)
+		return overall, ok
+	}
+	sort.SliceStable(variations, func(i, j int) bool {
+		a, _ := score(variations[i])
+		b, _ := score(variations[j])
+		return a > b
+	})
+
+	lines = append(lines, fmt.Sprintf("  %-24s %-22s %-8s %8s %6s", "VARIATION", "MODEL", "STATUS", "TIME", "SCORE"))
+	for _, variation := range variations {
+		label := variation.Configuration.VariationName
+		if variation.Repetition > 1 {
+			label = fmt.Sprintf("%s #%d", label, variation.Repetition)
+		}
+		scoreText := "-"
+		if overall, ok := score(variation); ok {
+			scoreText = fmt.Sprintf("%.2f", overall)
+		}
+		line := fmt.Sprintf("  %-24s %-22s %-8s %6dms %6s", label, variation.Configuration.ModelName,
+			variation.Response.ResponseStatus, variation.Response.ResponseTimeMs, scoreText)
+		if best != "" && variation.Configuration.ID == best {
+			line += " 🏆"
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, fmt.Sprintf("✅ %d successful, %d failed in %dms", result.SuccessCount, result.ErrorCount, result.TotalTime))
+	return lines
+}
+
+// renderTUIRuns lists runs newest first, numbered for picking one to watch
+func renderTUIRuns(runs []*types.ExecutionRun, now time.Time) []string {
+	active := 0
+	for _, run := range runs {
+		if run.Status == "pending" || run.Status == "running" {
+			active++
+		}
+	}
+	lines := []string{
+		fmt.Sprintf("🎯 GoGent runs · %d active · updated %s", active, now.Format("15:04:05")),
+		"",
+		fmt.Sprintf("  %3s  %-11s %-36s %-10s %s", "#", "STATUS", "NAME", "AGE", "ID"),
+	}
+	if len(runs) == 0 {
+		lines = append(lines, "  No runs yet")
+	}
+	for i, run := range runs {
+		lines = append(lines, fmt.Sprintf("  %3d  %s %-9s %-36s %-10s %s", i+1, tuiStatusIcon(run.Status), run.Status,
+			truncateTUI(run.Name, 36), formatTUIElapsed(now.Sub(run.CreatedAt)), run.ID))
+	}
+	return append(lines, "", "Enter a number to watch a run, q to quit: ")
+}
+
+// tuiStatusIcon marks a run or execution status
+func tuiStatusIcon(status string) string {
+	switch status {
+	case "pending":
+		return "⏳"
+	case "running":
+		return "🔄"
+	case "completed":
+		return "✅"
+	case "failed", "not_found":
+		return "❌"
+	}
+	return "•"
+}
+
+// tuiProgressBar draws done out of total as a bar of the given width
+func tuiProgressBar(done, total, width int) string {
+	filled := 0
+	if total > 0 {
+		filled = min(done*width/total, width)
+	}
+	return "[" + strings.Repeat("█", filled) + strings.Repeat("░", width-filled) + "]"
+}
+
+// formatTUIElapsed shows a duration to the second, or in minutes and hours once it is long
+func formatTUIElapsed(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
+
+// fitTUILines cuts lines to the screen's width and keeps the last height of them
+func fitTUILines(lines []string, width, height int) []string {
+	if len(lines) > height {
+		lines = lines[len(lines)-height:]
+	}
+	for i, line := range lines {
+		lines[i] = truncateTUI(line, width)
+	}
+	return lines
+}
+
+// truncateTUI shortens s to at most width characters
+func truncateTUI(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	if width < 1 {
+		return ""
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// readTUIInput delivers the lines typed on r, closing the channel at end of input
+func readTUIInput(r io.Reader) <-chan string {
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	return lines
+}
+
+// tuiScreen draws frames to the terminal. On a terminal that takes ANSI escape codes each frame
+// replaces the last; anywhere else, such as output piped to a file, frames are written one after
+// another as plain text.
+type tuiScreen struct {
+	out      io.Writer
+	terminal *os.File // Terminal out writes to, nil when it is not one
+	last     string
+}
+
+// newTUIScreen creates a screen drawing to out, redrawing in place when out is a terminal
+func newTUIScreen(out io.Writer) *tuiScreen {
+	screen := &tuiScreen{out: out}
+	if f, ok := out.(*os.File); ok && enableANSI(f) {
+		screen.terminal = f
+	}
+	return screen
+}
+
+// draw replaces the screen's contents with lines, skipping frames that did not change
+func (s *tuiScreen) draw(lines []string) {
+	frame := strings.Join(lines, "\n")
+	if frame == s.last {
+		return
+	}
+	if s.terminal == nil {
+		// Separate plain frames with a blank line
+		if s.last != "" {
+			fmt.Fprint(s.out, "\n\n")
+		}
+		s.last = frame
+		fmt.Fprint(s.out, frame)
+		return
+	}
+	s.last = frame
+	// Home the cursor and clear, then write every line clearing what is left of it
+	fmt.Fprint(s.out, "\x1b[H\x1b[2J"+strings.ReplaceAll(frame, "\n", "\x1b[K\n")+"\x1b[K")
+}
+
+// close leaves the last frame on screen and moves the prompt below it
+func (s *tuiScreen) close() {
+	if s.last != "" {
+		fmt.Fprintln(s.out)
+	}
+}
+
+// size returns the terminal's columns and rows, else $COLUMNS and $LINES, else 100x30
+func (s *tuiScreen) size() (int, int) {
+	if s.terminal != nil {
+		if width, height, ok := terminalSize(s.terminal); ok {
+			return width, height
+		}
+	}
+	width, height := 100, 30
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		width = n
+	}
+	if n, err := strconv.Atoi(os.Getenv("LINES")); err == nil && n > 0 {
+		height = n
+	}
+	return width, height
+}
@@ -0,0 +1,15 @@
+//go:build !unix && !windows
+
+package main
+
+import "os"
+
+// terminalSize reports false: there is no way to read a terminal's size on this platform
+func terminalSize(f *os.File) (int, int, bool) {
+	return 0, 0, false
+}
+
+// enableANSI reports false, so screens are drawn as plain text on this platform
+func enableANSI(f *os.File) bool {
+	return false
+}
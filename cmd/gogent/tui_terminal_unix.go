@@ -0,0 +1,25 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// terminalSize returns the columns and rows of the terminal f writes to, reporting false when f
+// is not a terminal
+func terminalSize(f *os.File) (int, int, bool) {
+	size, err := unix.IoctlGetWinsize(int(f.Fd()), unix.TIOCGWINSZ)
+	if err != nil || size.Col == 0 || size.Row == 0 {
+		return 0, 0, false
+	}
+	return int(size.Col), int(size.Row), true
+}
+
+// enableANSI reports whether f is a terminal, which on Unix takes ANSI escape codes as is
+func enableANSI(f *os.File) bool {
+	_, _, ok := terminalSize(f)
+	return ok
+}
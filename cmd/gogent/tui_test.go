@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gogent/internal/types"
+	pb "gogent/proto"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// tuiTestLogs returns n log entries numbered from first
+func tuiTestLogs(first, n int, at time.Time) []*pb.ExecutionLog {
+	logs := make([]*pb.ExecutionLog, n)
+	for i := range logs {
+		logs[i] = &pb.ExecutionLog{LogLevel: "INFO", LogCategory: "EXECUTION", Message: fmt.Sprintf("entry %d", first+i), Timestamp: timestamppb.New(at)}
+	}
+	return logs
+}
+
+func TestTUIWatchViewKeepsLatestLogs(t *testing.T) {
+	view := &tuiWatchView{id: "exec-1"}
+	now := time.Now()
+	view.apply(&pb.ExecutionEvent{Status: "running", Logs: tuiTestLogs(0, tuiMaxLogs-1, now)}, nil)
+	view.apply(&pb.ExecutionEvent{Status: "running", Logs: tuiTestLogs(tuiMaxLogs-1, 5, now)}, nil)
+
+	if len(view.logs) != tuiMaxLogs {
+		t.Fatalf("expected the view to keep %d logs, got %d", tuiMaxLogs, len(view.logs))
+	}
+	if first, last := view.logs[0].Message, view.logs[len(view.logs)-1].Message; first != "entry 4" || last != fmt.Sprintf("entry %d", tuiMaxLogs+3) {
+		t.Errorf("expected the oldest entries dropped, got %q to %q", first, last)
+	}
+}
+
+func TestTUIWatchViewRender(t *testing.T) {
+	started := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	view := &tuiWatchView{id: "exec-1", name: "Weather check", started: started, fromRuns: true}
+	view.apply(&pb.ExecutionEvent{
+		ExecutionId: "exec-1", ExecutionRunId: "run-1", Status: "running",
+		TotalVariations: 4, CompletedVariations: 2, FailedVariations: 1, LastVariation: "hot",
+		Logs: tuiTestLogs(0, 20, started),
+	}, nil)
+
+	lines := view.render(60, 12, started.Add(90*time.Second))
+	if len(lines) != 12 {
+		t.Fatalf("expected the frame to fill the 12 rows, got %d:\n%s", len(lines), strings.Join(lines, "\n"))
+	}
+	for _, line := range lines {
+		if n := len([]rune(line)); n > 60 {
+			t.Errorf("expected lines cut to 60 columns, got %d: %q", n, line)
+		}
+	}
+	frame := strings.Join(lines, "\n")
+	for _, expected := range []string{"Weather check", "running · 1m30s", "Execution exec-1 · run run-1", "] 2/4 variations · 1 failed", "entry 19", "Ctrl+C to quit"} {
+		if !strings.Contains(frame, expected) {
+			t.Errorf("expected the frame to show %q, got:\n%s", expected, frame)
+		}
+	}
+	if strings.Contains(frame, "entry 0 ") || strings.Contains(frame, "entry 10") {
+		t.Errorf("expected only the latest logs to fit, got:\n%s", frame)
+	}
+
+	view.done = true
+	lines = view.render(60, 12, started.Add(2*time.Minute))
+	if footer := lines[len(lines)-1]; footer != "Done · Enter to go back, q to quit" {
+		t.Errorf("expected a finished run opened from the list to offer going back, got %q", footer)
+	}
+}
+
+func TestRenderTUIRuns(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	lines := renderTUIRuns([]*types.ExecutionRun{
+		{ID: "run-2", Name: "A run whose name is far too long to fit in its column", Status: "running", CreatedAt: now.Add(-30 * time.Second)},
+		{ID: "run-1", Name: "Nightly", Status: "completed", CreatedAt: now.Add(-3 * time.Hour)},
+	}, now)
+
+	frame := strings.Join(lines, "\n")
+	for _, expected := range []string{"1 active · updated 12:00:00", "1  🔄 running", "A run whose name is far too long to…", "30s", "2  ✅ completed", "3h00m", "run-1"} {
+		if !strings.Contains(frame, expected) {
+			t.Errorf("expected the list to show %q, got:\n%s", expected, frame)
+		}
+	}
+	if empty := strings.Join(renderTUIRuns(nil, now), "\n"); !strings.Contains(empty, "No runs yet") {
+		t.Errorf("expected an empty list to say so, got:\n%s", empty)
+	}
+}
+
+func TestFitTUILines(t *testing.T) {
+	lines := fitTUILines([]string{"first", "second", "🎯 third line"}, 6, 2)
+	if len(lines) != 2 || lines[0] != "second" || lines[1] != "🎯 thi…" {
+		t.Errorf("expected the last two lines cut to 6 runes, got %q", lines)
+	}
+
+	tests := []struct {
+		s        string
+		width    int
+		expected string
+	}{
+		{"short", 10, "short"},
+		{"exactly", 7, "exactly"},
+		{"too long", 4, "too…"},
+		{"anything", 0, ""},
+	}
+	for _, test := range tests {
+		if got := truncateTUI(test.s, test.width); got != test.expected {
+			t.Errorf("truncateTUI(%q, %d): expected %q, got %q", test.s, test.width, test.expected, got)
+		}
+	}
+}
+
+func TestTUIScreenWithoutTerminal(t *testing.T) {
+	var out bytes.Buffer
+	screen := newTUIScreen(&out)
+	t.Setenv("COLUMNS", "80")
+	t.Setenv("LINES", "")
+	if width, height := screen.size(); width != 80 || height != 30 {
+		t.Errorf("expected $COLUMNS and the default height, got %dx%d", width, height)
+	}
+
+	screen.draw([]string{"one", "two"})
+	screen.draw([]string{"one", "two"})
+	screen.draw([]string{"three"})
+	screen.close()
+	if got := out.String(); got != "one\ntwo\n\nthree\n" {
+		t.Errorf("expected plain frames without escape codes or repeats, got %q", got)
+	}
+}
@@ -0,0 +1,271 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// API key scopes
+const (
+	ScopeRead  = "read"  // GET/List style calls
+	ScopeWrite = "write" // Calls that create, update, delete or execute
+	ScopeAdmin = "admin" // Admin-only endpoints (the owner must also be an admin)
+)
+
+// apiKeyPrefix marks GoGent API keys so they are easy to recognise in logs and secret scanners
+const apiKeyPrefix = "gk_"
+
+// DefaultAPIKeyScopes are granted when a key is created without explicit scopes
+var DefaultAPIKeyScopes = []string{ScopeRead, ScopeWrite}
+
+// APIKey represents a long-lived credential for scripts and CI
+type APIKey struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// HasScope reports whether the key was granted the given scope
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateAPIKey issues a new API key for a user and returns the raw key, which is only shown once
+func (as *AuthService) CreateAPIKey(userID, name string, scopes []string, expiresIn time.Duration) (*APIKey, string, error) {
+	if name == "" {
+		return nil, "", fmt.Errorf("API key name is required")
+	}
+
+	if len(scopes) == 0 {
+		scopes = DefaultAPIKeyScopes
+	}
+	for _, scope := range scopes {
+		if scope != ScopeRead && scope != ScopeWrite && scope != ScopeAdmin {
+			return nil, "", fmt.Errorf("invalid scope: %s", scope)
+		}
+	}
+
+	rawKey := apiKeyPrefix + generateRandomString(40)
+	now := time.Now()
+
+	key := &APIKey{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Name:      name,
+		Prefix:    rawKey[:len(apiKeyPrefix)+8],
+		Scopes:    scopes,
+		CreatedAt: now,
+	}
+
+	var expiresAt sql.NullTime
+	if expiresIn > 0 {
+		expires := now.Add(expiresIn)
+		key.ExpiresAt = &expires
+		expiresAt = sql.NullTime{Time: expires, Valid: true}
+	}
+
+	query := `
+		INSERT INTO api_keys (id, user_id, name, key_prefix, key_hash, scopes, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := as.db.Exec(query, key.ID, userID, name, key.Prefix, hashToken(rawKey),
+		strings.Join(scopes, ","), expiresAt, now)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	log.Printf("🔑 Created API key %s (%s) for user %s", key.Prefix, name, userID)
+	return key, rawKey, nil
+}
+
+// ListAPIKeys returns a user's API keys, newest first
+func (as *AuthService) ListAPIKeys(userID string) ([]*APIKey, error) {
+	query := `
+		SELECT id, user_id, name, key_prefix, scopes, expires_at, last_used_at, revoked_at, created_at
+		FROM api_keys
+		WHERE user_id = ?
+		ORDER BY created_at DESC
+	`
+
+	rows, err := as.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []*APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// RevokeAPIKey revokes one of the user's API keys
+func (as *AuthService) RevokeAPIKey(userID, keyID string) error {
+	result, err := as.db.Exec(
+		"UPDATE api_keys SET revoked_at = ? WHERE id = ? AND user_id = ? AND revoked_at IS NULL",
+		time.Now(), keyID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("API key not found")
+	}
+
+	log.Printf("🔑 Revoked API key %s for user %s", keyID, userID)
+	return nil
+}
+
+// ValidateAPIKey checks a raw API key and returns its owner and key record
+func (as *AuthService) ValidateAPIKey(rawKey string) (*User, *APIKey, error) {
+	if !strings.HasPrefix(rawKey, apiKeyPrefix) {
+		return nil, nil, fmt.Errorf("invalid API key")
+	}
+
+	query := `
+		SELECT id, user_id, name, key_prefix, scopes, expires_at, last_used_at, revoked_at, created_at
+		FROM api_keys
+		WHERE key_hash = ?
+	`
+
+	key, err := scanAPIKey(as.db.QueryRow(query, hashToken(rawKey)))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, fmt.Errorf("invalid API key")
+		}
+		return nil, nil, err
+	}
+
+	if key.RevokedAt != nil {
+		return nil, nil, fmt.Errorf("API key revoked")
+	}
+	if key.ExpiresAt != nil && time.Now().After(*key.ExpiresAt) {
+		return nil, nil, fmt.Errorf("API key expired")
+	}
+
+	user, err := as.GetUserByID(key.UserID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	now := time.Now()
+	if _, err := as.db.Exec("UPDATE api_keys SET last_used_at = ? WHERE id = ?", now, key.ID); err != nil {
+		log.Printf("⚠️ Failed to update API key last used time: %v", err)
+	}
+	key.LastUsedAt = &now
+
+	// Keys without the admin scope never act as admin, even for admin owners
+	if !key.HasScope(ScopeAdmin) && user.Role == RoleAdmin {
+		user.Role = RoleUser
+	}
+
+	return user, key, nil
+}
+
+// AuthenticateHeader validates either a "Bearer <jwt>" or "ApiKey <key>" Authorization header.
+// The returned APIKey is nil for JWT authentication.
+func (as *AuthService) AuthenticateHeader(authHeader string) (*User, *APIKey, error) {
+	if rawKey, err := ExtractAPIKeyFromHeader(authHeader); err == nil {
+		return as.ValidateAPIKey(rawKey)
+	}
+
+	token, err := ExtractTokenFromHeader(authHeader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := as.ValidateToken(token)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, nil, nil
+}
+
+// ExtractAPIKeyFromHeader extracts an API key from an "ApiKey <key>" Authorization header
+func ExtractAPIKeyFromHeader(authHeader string) (string, error) {
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "ApiKey" || parts[1] == "" {
+		return "", fmt.Errorf("invalid API key header format")
+	}
+
+	return parts[1], nil
+}
+
+// APIKeyAllows reports whether a request made with key may perform a read or write call.
+// A nil key means the caller used a JWT and is not scope-restricted.
+func APIKeyAllows(key *APIKey, write bool) bool {
+	if key == nil {
+		return true
+	}
+	if write {
+		return key.HasScope(ScopeWrite)
+	}
+	return key.HasScope(ScopeRead) || key.HasScope(ScopeWrite)
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanAPIKey reads an api_keys row selected in the standard column order
+func scanAPIKey(row rowScanner) (*APIKey, error) {
+	var key APIKey
+	var scopes string
+	var expiresAt, lastUsedAt, revokedAt sql.NullTime
+
+	err := row.Scan(&key.ID, &key.UserID, &key.Name, &key.Prefix, &scopes,
+		&expiresAt, &lastUsedAt, &revokedAt, &key.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	if scopes != "" {
+		key.Scopes = strings.Split(scopes, ",")
+	}
+	if expiresAt.Valid {
+		key.ExpiresAt = &expiresAt.Time
+	}
+	if lastUsedAt.Valid {
+		key.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+
+	return &key, nil
+}
+
+// hashToken hashes an API key or refresh token for storage; both are high-entropy so a fast hash is sufficient
+func hashToken(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,260 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthService_CreateAPIKey(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	authService := NewAuthService(db, "test-secret")
+
+	user, _, _, err := authService.Register("keyowner", "keys@example.com", "password123")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		keyName    string
+		scopes     []string
+		expiresIn  time.Duration
+		wantScopes []string
+		wantErr    bool
+		errMsg     string
+	}{
+		{
+			name:       "default scopes",
+			keyName:    "ci",
+			wantScopes: DefaultAPIKeyScopes,
+		},
+		{
+			name:       "read only with expiry",
+			keyName:    "dashboard",
+			scopes:     []string{ScopeRead},
+			expiresIn:  time.Hour,
+			wantScopes: []string{ScopeRead},
+		},
+		{
+			name:    "missing name",
+			wantErr: true,
+			errMsg:  "name is required",
+		},
+		{
+			name:    "invalid scope",
+			keyName: "bad",
+			scopes:  []string{"delete_everything"},
+			wantErr: true,
+			errMsg:  "invalid scope",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, rawKey, err := authService.CreateAPIKey(user.ID, tt.keyName, tt.scopes, tt.expiresIn)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(rawKey, apiKeyPrefix))
+			assert.True(t, strings.HasPrefix(rawKey, key.Prefix))
+			assert.Equal(t, tt.wantScopes, key.Scopes)
+			assert.Equal(t, tt.expiresIn > 0, key.ExpiresAt != nil)
+
+			// The raw key must never be stored
+			var storedHash string
+			require.NoError(t, db.QueryRow("SELECT key_hash FROM api_keys WHERE id = ?", key.ID).Scan(&storedHash))
+			assert.NotEqual(t, rawKey, storedHash)
+			assert.Equal(t, hashToken(rawKey), storedHash)
+		})
+	}
+}
+
+func TestAuthService_ValidateAPIKey(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	authService := NewAuthService(db, "test-secret")
+
+	user, _, _, err := authService.Register("validator", "validate@example.com", "password123")
+	require.NoError(t, err)
+
+	_, validKey, err := authService.CreateAPIKey(user.ID, "valid", nil, 0)
+	require.NoError(t, err)
+
+	revoked, revokedKey, err := authService.CreateAPIKey(user.ID, "revoked", nil, 0)
+	require.NoError(t, err)
+	require.NoError(t, authService.RevokeAPIKey(user.ID, revoked.ID))
+
+	expired, expiredKey, err := authService.CreateAPIKey(user.ID, "expired", nil, time.Hour)
+	require.NoError(t, err)
+	_, err = db.Exec("UPDATE api_keys SET expires_at = ? WHERE id = ?", time.Now().Add(-time.Hour), expired.ID)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		rawKey  string
+		wantErr bool
+		errMsg  string
+	}{
+		{name: "valid key", rawKey: validKey},
+		{name: "revoked key", rawKey: revokedKey, wantErr: true, errMsg: "revoked"},
+		{name: "expired key", rawKey: expiredKey, wantErr: true, errMsg: "expired"},
+		{name: "unknown key", rawKey: apiKeyPrefix + "doesnotexist", wantErr: true, errMsg: "invalid API key"},
+		{name: "wrong prefix", rawKey: "not-a-key", wantErr: true, errMsg: "invalid API key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotUser, key, err := authService.ValidateAPIKey(tt.rawKey)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, user.ID, gotUser.ID)
+			assert.NotNil(t, key.LastUsedAt)
+		})
+	}
+}
+
+func TestAuthService_ValidateAPIKey_AdminScope(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	authService := NewAuthService(db, "test-secret")
+
+	admin, _, _, err := authService.Register("keyadmin", "keyadmin@example.com", "password123")
+	require.NoError(t, err)
+	_, err = authService.SetUserRole(admin.ID, RoleAdmin)
+	require.NoError(t, err)
+
+	_, plainKey, err := authService.CreateAPIKey(admin.ID, "plain", nil, 0)
+	require.NoError(t, err)
+	_, adminKey, err := authService.CreateAPIKey(admin.ID, "admin", []string{ScopeRead, ScopeAdmin}, 0)
+	require.NoError(t, err)
+
+	user, _, err := authService.ValidateAPIKey(plainKey)
+	require.NoError(t, err)
+	assert.False(t, user.IsAdmin(), "keys without the admin scope must not act as admin")
+
+	user, _, err = authService.ValidateAPIKey(adminKey)
+	require.NoError(t, err)
+	assert.True(t, user.IsAdmin())
+}
+
+func TestAuthService_ListAndRevokeAPIKeys(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	authService := NewAuthService(db, "test-secret")
+
+	owner, _, _, err := authService.Register("lister", "lister@example.com", "password123")
+	require.NoError(t, err)
+	other, _, _, err := authService.Register("other", "other@example.com", "password123")
+	require.NoError(t, err)
+
+	key, _, err := authService.CreateAPIKey(owner.ID, "first", nil, 0)
+	require.NoError(t, err)
+	_, _, err = authService.CreateAPIKey(other.ID, "theirs", nil, 0)
+	require.NoError(t, err)
+
+	keys, err := authService.ListAPIKeys(owner.ID)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, "first", keys[0].Name)
+
+	// Other users cannot revoke keys they do not own
+	err = authService.RevokeAPIKey(other.ID, key.ID)
+	assert.Error(t, err)
+
+	require.NoError(t, authService.RevokeAPIKey(owner.ID, key.ID))
+	err = authService.RevokeAPIKey(owner.ID, key.ID)
+	assert.Error(t, err, "revoking twice should fail")
+
+	keys, err = authService.ListAPIKeys(owner.ID)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.NotNil(t, keys[0].RevokedAt)
+}
+
+func TestExtractAPIKeyFromHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		want    string
+		wantErr bool
+	}{
+		{name: "valid", header: "ApiKey gk_abc", want: "gk_abc"},
+		{name: "bearer", header: "Bearer token", wantErr: true},
+		{name: "missing key", header: "ApiKey ", wantErr: true},
+		{name: "empty", header: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtractAPIKeyFromHeader(tt.header)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAuthMiddleware_APIKey(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	authService := NewAuthService(db, "test-secret")
+
+	user, _, _, err := authService.Register("machine", "machine@example.com", "password123")
+	require.NoError(t, err)
+	_, readKey, err := authService.CreateAPIKey(user.ID, "read", []string{ScopeRead}, 0)
+	require.NoError(t, err)
+	_, writeKey, err := authService.CreateAPIKey(user.ID, "write", []string{ScopeRead, ScopeWrite}, 0)
+	require.NoError(t, err)
+
+	handler := AuthMiddleware(authService)(func(w http.ResponseWriter, r *http.Request) {
+		ctxUser, ok := GetUserFromContext(r.Context())
+		require.True(t, ok)
+		assert.Equal(t, user.ID, ctxUser.ID)
+		_, ok = GetAPIKeyFromContext(r.Context())
+		assert.True(t, ok)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name       string
+		method     string
+		header     string
+		wantStatus int
+	}{
+		{name: "read key GET", method: http.MethodGet, header: "ApiKey " + readKey, wantStatus: http.StatusOK},
+		{name: "read key POST", method: http.MethodPost, header: "ApiKey " + readKey, wantStatus: http.StatusForbidden},
+		{name: "write key POST", method: http.MethodPost, header: "ApiKey " + writeKey, wantStatus: http.StatusOK},
+		{name: "unknown key", method: http.MethodGet, header: "ApiKey gk_unknown", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/execution-runs", nil)
+			req.Header.Set("Authorization", tt.header)
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
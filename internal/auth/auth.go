@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -21,11 +22,23 @@ type User struct {
 	Email         *string    `json:"email,omitempty"`
 	EmailVerified bool       `json:"email_verified"`
 	IsTemporary   bool       `json:"is_temporary"`
+	Role          string     `json:"role"`
 	CreatedAt     time.Time  `json:"created_at"`
 	UpdatedAt     time.Time  `json:"updated_at"`
 	LastLoginAt   *time.Time `json:"last_login_at,omitempty"`
 }
 
+// User roles
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// IsAdmin reports whether the user has the admin role
+func (u *User) IsAdmin() bool {
+	return u != nil && u.Role == RoleAdmin
+}
+
 // Claims represents JWT claims
 type Claims struct {
 	UserID   string `json:"user_id"`
@@ -39,6 +52,15 @@ type AuthService struct {
 	db          *sql.DB
 	jwtSecret   []byte
 	tokenExpiry time.Duration
+
+	// refreshExpiry is the absolute lifetime of a login session and its refresh tokens
+	refreshExpiry time.Duration
+
+	// Password reset delivery
+	emailSender         EmailSender
+	passwordResetURL    string // Link base for reset emails; the token is appended as ?token=
+	passwordResetExpiry time.Duration
+	passwordResets      sync.WaitGroup // Reset emails still being prepared and sent
 }
 
 // NewAuthService creates a new authentication service
@@ -50,14 +72,18 @@ func NewAuthService(db *sql.DB, jwtSecret string) *AuthService {
 	}
 
 	return &AuthService{
-		db:          db,
-		jwtSecret:   []byte(jwtSecret),
-		tokenExpiry: 24 * time.Hour, // 24 hours
+		db:            db,
+		jwtSecret:     []byte(jwtSecret),
+		tokenExpiry:   time.Hour,           // short-lived; clients renew via refresh tokens
+		refreshExpiry: 30 * 24 * time.Hour, // 30 days
+
+		emailSender:         &LogEmailSender{},
+		passwordResetExpiry: time.Hour,
 	}
 }
 
 // CreateTemporaryUser creates a temporary user for anonymous access
-func (as *AuthService) CreateTemporaryUser(sessionID string) (*User, string, string, error) {
+func (as *AuthService) CreateTemporaryUser(sessionID string) (*User, string, string, string, error) {
 	// Generate temporary username
 	tempUsername := fmt.Sprintf("temp_%s", generateRandomString(8))
 
@@ -67,7 +93,7 @@ func (as *AuthService) CreateTemporaryUser(sessionID string) (*User, string, str
 	// Hash the password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(tempPassword), bcrypt.DefaultCost)
 	if err != nil {
-		return nil, "", "", fmt.Errorf("failed to hash password: %w", err)
+		return nil, "", "", "", fmt.Errorf("failed to hash password: %w", err)
 	}
 
 	userID := uuid.New().String()
@@ -81,32 +107,33 @@ func (as *AuthService) CreateTemporaryUser(sessionID string) (*User, string, str
 
 	_, err = as.db.Exec(query, userID, tempUsername, string(hashedPassword), now, now)
 	if err != nil {
-		return nil, "", "", fmt.Errorf("failed to create temporary user: %w", err)
+		return nil, "", "", "", fmt.Errorf("failed to create temporary user: %w", err)
 	}
 
 	user := &User{
 		ID:          userID,
 		Username:    tempUsername,
 		IsTemporary: true,
+		Role:        RoleUser,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
 
-	// Generate JWT token
-	token, err := as.generateToken(user)
+	// Start a session with JWT and refresh tokens
+	token, refreshToken, err := as.createSession(user)
 	if err != nil {
-		return nil, "", "", fmt.Errorf("failed to generate token: %w", err)
+		return nil, "", "", "", fmt.Errorf("failed to generate token: %w", err)
 	}
 
 	log.Printf("✅ Created temporary user: %s", tempUsername)
-	return user, tempPassword, token, nil
+	return user, tempPassword, token, refreshToken, nil
 }
 
-// Login authenticates a user and returns a JWT token
-func (as *AuthService) Login(usernameOrEmail, password string) (*User, string, error) {
+// Login authenticates a user and returns a JWT token and refresh token
+func (as *AuthService) Login(usernameOrEmail, password string) (*User, string, string, error) {
 	// Get user from database - try both username and email
 	query := `
-		SELECT id, username, email, password_hash, email_verified, is_temporary, 
+		SELECT id, username, email, password_hash, email_verified, is_temporary, role,
 		       created_at, updated_at, last_login_at
 		FROM users 
 		WHERE username = ? OR email = ?
@@ -119,13 +146,13 @@ func (as *AuthService) Login(usernameOrEmail, password string) (*User, string, e
 
 	err := as.db.QueryRow(query, usernameOrEmail, usernameOrEmail).Scan(
 		&user.ID, &user.Username, &email, &passwordHash,
-		&user.EmailVerified, &user.IsTemporary, &user.CreatedAt, &user.UpdatedAt, &lastLoginAt,
+		&user.EmailVerified, &user.IsTemporary, &user.Role, &user.CreatedAt, &user.UpdatedAt, &lastLoginAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, "", fmt.Errorf("invalid credentials")
+			return nil, "", "", fmt.Errorf("invalid credentials")
 		}
-		return nil, "", fmt.Errorf("database error: %w", err)
+		return nil, "", "", fmt.Errorf("database error: %w", err)
 	}
 
 	if email.Valid {
@@ -138,7 +165,7 @@ func (as *AuthService) Login(usernameOrEmail, password string) (*User, string, e
 	// Verify password
 	err = bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(password))
 	if err != nil {
-		return nil, "", fmt.Errorf("invalid credentials")
+		return nil, "", "", fmt.Errorf("invalid credentials")
 	}
 
 	// Update last login time
@@ -151,43 +178,43 @@ func (as *AuthService) Login(usernameOrEmail, password string) (*User, string, e
 	user.LastLoginAt = &now
 	user.UpdatedAt = now
 
-	// Generate JWT token
-	token, err := as.generateToken(&user)
+	// Start a session with JWT and refresh tokens
+	token, refreshToken, err := as.createSession(&user)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to generate token: %w", err)
+		return nil, "", "", fmt.Errorf("failed to generate token: %w", err)
 	}
 
 	log.Printf("✅ User logged in: %s (using %s)", user.Username, usernameOrEmail)
-	return &user, token, nil
+	return &user, token, refreshToken, nil
 }
 
 // Register creates a new permanent user account
-func (as *AuthService) Register(username, email, password string) (*User, string, error) {
+func (as *AuthService) Register(username, email, password string) (*User, string, string, error) {
 	// Check if username already exists
 	var exists bool
 	err := as.db.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE username = ?)", username).Scan(&exists)
 	if err != nil {
-		return nil, "", fmt.Errorf("database error: %w", err)
+		return nil, "", "", fmt.Errorf("database error: %w", err)
 	}
 	if exists {
-		return nil, "", fmt.Errorf("username already exists")
+		return nil, "", "", fmt.Errorf("username already exists")
 	}
 
 	// Check if email already exists
 	if email != "" {
 		err = as.db.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE email = ?)", email).Scan(&exists)
 		if err != nil {
-			return nil, "", fmt.Errorf("database error: %w", err)
+			return nil, "", "", fmt.Errorf("database error: %w", err)
 		}
 		if exists {
-			return nil, "", fmt.Errorf("email already exists")
+			return nil, "", "", fmt.Errorf("email already exists")
 		}
 	}
 
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to hash password: %w", err)
+		return nil, "", "", fmt.Errorf("failed to hash password: %w", err)
 	}
 
 	userID := uuid.New().String()
@@ -201,7 +228,7 @@ func (as *AuthService) Register(username, email, password string) (*User, string
 
 	_, err = as.db.Exec(query, userID, username, email, string(hashedPassword), now, now)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to create user: %w", err)
+		return nil, "", "", fmt.Errorf("failed to create user: %w", err)
 	}
 
 	user := &User{
@@ -209,6 +236,7 @@ func (as *AuthService) Register(username, email, password string) (*User, string
 		Username:      username,
 		EmailVerified: false,
 		IsTemporary:   false,
+		Role:          RoleUser,
 		CreatedAt:     now,
 		UpdatedAt:     now,
 	}
@@ -218,14 +246,14 @@ func (as *AuthService) Register(username, email, password string) (*User, string
 		user.Email = &email
 	}
 
-	// Generate JWT token
-	token, err := as.generateToken(user)
+	// Start a session with JWT and refresh tokens
+	token, refreshToken, err := as.createSession(user)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to generate token: %w", err)
+		return nil, "", "", fmt.Errorf("failed to generate token: %w", err)
 	}
 
 	log.Printf("✅ User registered: %s", username)
-	return user, token, nil
+	return user, token, refreshToken, nil
 }
 
 // SaveTemporaryAccount converts a temporary account to a permanent one
@@ -286,36 +314,36 @@ func (as *AuthService) SaveTemporaryAccount(userID, email, currentPassword strin
 }
 
 // ConnectTemporaryAccount connects a temporary account to an email with a new password
-func (as *AuthService) ConnectTemporaryAccount(userID, email, newPassword string) (*User, string, error) {
+func (as *AuthService) ConnectTemporaryAccount(userID, email, newPassword string) (*User, string, string, error) {
 	// Get current user
 	user, err := as.GetUserByID(userID)
 	if err != nil {
-		return nil, "", fmt.Errorf("user not found: %w", err)
+		return nil, "", "", fmt.Errorf("user not found: %w", err)
 	}
 
 	if !user.IsTemporary {
-		return nil, "", fmt.Errorf("user is not temporary")
+		return nil, "", "", fmt.Errorf("user is not temporary")
 	}
 
 	// Validate password
 	if len(newPassword) < 6 {
-		return nil, "", fmt.Errorf("password must be at least 6 characters long")
+		return nil, "", "", fmt.Errorf("password must be at least 6 characters long")
 	}
 
 	// Check if email already exists
 	var exists bool
 	err = as.db.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE email = ? AND id != ?)", email, userID).Scan(&exists)
 	if err != nil {
-		return nil, "", fmt.Errorf("database error: %w", err)
+		return nil, "", "", fmt.Errorf("database error: %w", err)
 	}
 	if exists {
-		return nil, "", fmt.Errorf("email already exists")
+		return nil, "", "", fmt.Errorf("email already exists")
 	}
 
 	// Hash new password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to hash password: %w", err)
+		return nil, "", "", fmt.Errorf("failed to hash password: %w", err)
 	}
 
 	// Update user with email, new password, and convert to permanent
@@ -328,23 +356,26 @@ func (as *AuthService) ConnectTemporaryAccount(userID, email, newPassword string
 
 	_, err = as.db.Exec(query, email, string(hashedPassword), now, userID)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to update user: %w", err)
+		return nil, "", "", fmt.Errorf("failed to update user: %w", err)
 	}
 
 	// Get updated user
 	user, err = as.GetUserByID(userID)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to get updated user: %w", err)
+		return nil, "", "", fmt.Errorf("failed to get updated user: %w", err)
 	}
 
-	// Generate new JWT token
-	token, err := as.generateToken(user)
+	// The password changed, so end existing sessions and start a fresh one
+	if err := as.RevokeUserSessions(userID); err != nil {
+		log.Printf("⚠️ Failed to revoke old sessions: %v", err)
+	}
+	token, refreshToken, err := as.createSession(user)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to generate token: %w", err)
+		return nil, "", "", fmt.Errorf("failed to generate token: %w", err)
 	}
 
 	log.Printf("✅ Temporary account connected: %s -> %s with new password", user.Username, email)
-	return user, token, nil
+	return user, token, refreshToken, nil
 }
 
 // VerifyEmail verifies a user's email address
@@ -399,7 +430,7 @@ func (as *AuthService) VerifyEmail(token string) (*User, error) {
 // GetUserByID retrieves a user by ID
 func (as *AuthService) GetUserByID(userID string) (*User, error) {
 	query := `
-		SELECT id, username, email, email_verified, is_temporary, 
+		SELECT id, username, email, email_verified, is_temporary, role,
 		       created_at, updated_at, last_login_at
 		FROM users 
 		WHERE id = ?
@@ -411,7 +442,7 @@ func (as *AuthService) GetUserByID(userID string) (*User, error) {
 
 	err := as.db.QueryRow(query, userID).Scan(
 		&user.ID, &user.Username, &email, &user.EmailVerified,
-		&user.IsTemporary, &user.CreatedAt, &user.UpdatedAt, &lastLoginAt,
+		&user.IsTemporary, &user.Role, &user.CreatedAt, &user.UpdatedAt, &lastLoginAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -430,6 +461,29 @@ func (as *AuthService) GetUserByID(userID string) (*User, error) {
 	return &user, nil
 }
 
+// SetUserRole changes a user's role
+func (as *AuthService) SetUserRole(userID, role string) (*User, error) {
+	if role != RoleUser && role != RoleAdmin {
+		return nil, fmt.Errorf("invalid role: %s", role)
+	}
+
+	result, err := as.db.Exec("UPDATE users SET role = ?, updated_at = ? WHERE id = ?", role, time.Now(), userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update role: %w", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	user, err := as.GetUserByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get updated user: %w", err)
+	}
+
+	log.Printf("✅ Role for user %s set to %s", user.Username, role)
+	return user, nil
+}
+
 // ValidateToken validates a JWT token and returns the user
 func (as *AuthService) ValidateToken(tokenString string) (*User, error) {
 	// Parse and validate token
@@ -449,6 +503,11 @@ func (as *AuthService) ValidateToken(tokenString string) (*User, error) {
 		return nil, fmt.Errorf("invalid token claims")
 	}
 
+	// Reject tokens whose session was revoked (logout) or has expired
+	if err := as.checkSessionActive(claims.ID); err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+
 	// Get user from database
 	user, err := as.GetUserByID(claims.UserID)
 	if err != nil {
@@ -458,8 +517,13 @@ func (as *AuthService) ValidateToken(tokenString string) (*User, error) {
 	return user, nil
 }
 
-// generateToken generates a JWT token for a user
-func (as *AuthService) generateToken(user *User) (string, error) {
+// TokenExpiry returns how long issued tokens remain valid
+func (as *AuthService) TokenExpiry() time.Duration {
+	return as.tokenExpiry
+}
+
+// generateToken generates a JWT token for a user, bound to a login session
+func (as *AuthService) generateToken(user *User, sessionID string) (string, error) {
 	now := time.Now()
 	expiresAt := now.Add(as.tokenExpiry)
 
@@ -473,6 +537,7 @@ func (as *AuthService) generateToken(user *User) (string, error) {
 			NotBefore: jwt.NewNumericDate(now),
 			Issuer:    "gogent",
 			Subject:   user.ID,
+			ID:        sessionID,
 		},
 	}
 
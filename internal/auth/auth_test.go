@@ -5,37 +5,16 @@ import (
 	"testing"
 	"time"
 
+	"gogent/internal/testdb"
+
 	"github.com/golang-jwt/jwt/v5"
-	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 // setupTestDB creates an in-memory SQLite database for testing
 func setupTestDB(t *testing.T) *sql.DB {
-	db, err := sql.Open("sqlite3", ":memory:")
-	require.NoError(t, err)
-
-	// Create users table
-	schema := `
-	CREATE TABLE users (
-		id TEXT PRIMARY KEY,
-		username TEXT UNIQUE NOT NULL,
-		email TEXT UNIQUE,
-		password_hash TEXT NOT NULL,
-		email_verified BOOLEAN DEFAULT FALSE,
-		email_verification_token TEXT,
-		email_verification_expires_at DATETIME,
-		is_temporary BOOLEAN DEFAULT FALSE,
-		created_at DATETIME NOT NULL,
-		updated_at DATETIME NOT NULL,
-		last_login_at DATETIME
-	);
-	`
-	_, err = db.Exec(schema)
-	require.NoError(t, err)
-
-	return db
+	return testdb.Open(t)
 }
 
 func TestNewAuthService(t *testing.T) {
@@ -66,7 +45,9 @@ func TestNewAuthService(t *testing.T) {
 			assert.NotNil(t, authService)
 			assert.Equal(t, db, authService.db)
 			assert.Len(t, authService.jwtSecret, tt.wantLen)
-			assert.Equal(t, 24*time.Hour, authService.tokenExpiry)
+			assert.Equal(t, time.Hour, authService.tokenExpiry)
+			assert.Equal(t, 30*24*time.Hour, authService.RefreshExpiry())
+			assert.Equal(t, authService.tokenExpiry, authService.TokenExpiry())
 		})
 	}
 }
@@ -95,7 +76,7 @@ func TestAuthService_CreateTemporaryUser(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			user, tempPassword, token, err := authService.CreateTemporaryUser(tt.sessionID)
+			user, tempPassword, token, _, err := authService.CreateTemporaryUser(tt.sessionID)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -171,7 +152,7 @@ func TestAuthService_Register(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			user, token, err := authService.Register(tt.username, tt.email, tt.password)
+			user, token, _, err := authService.Register(tt.username, tt.email, tt.password)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -207,7 +188,7 @@ func TestAuthService_Login(t *testing.T) {
 	authService := NewAuthService(db, "test-secret")
 
 	// Create a test user first
-	testUser, _, err := authService.Register("logintest", "login@example.com", "password123")
+	testUser, _, _, err := authService.Register("logintest", "login@example.com", "password123")
 	require.NoError(t, err)
 
 	tests := []struct {
@@ -241,7 +222,7 @@ func TestAuthService_Login(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			user, token, err := authService.Login(tt.username, tt.password)
+			user, token, _, err := authService.Login(tt.username, tt.password)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -277,7 +258,7 @@ func TestAuthService_SaveTemporaryAccount(t *testing.T) {
 		{
 			name: "successful save",
 			setupFunc: func() (string, string, string) {
-				tempUser, tempPassword, _, err := authService.CreateTemporaryUser("test-session-1")
+				tempUser, tempPassword, _, _, err := authService.CreateTemporaryUser("test-session-1")
 				require.NoError(t, err)
 				return tempUser.ID, "save@example.com", tempPassword
 			},
@@ -286,7 +267,7 @@ func TestAuthService_SaveTemporaryAccount(t *testing.T) {
 		{
 			name: "wrong password",
 			setupFunc: func() (string, string, string) {
-				tempUser, _, _, err := authService.CreateTemporaryUser("test-session-2")
+				tempUser, _, _, _, err := authService.CreateTemporaryUser("test-session-2")
 				require.NoError(t, err)
 				return tempUser.ID, "save2@example.com", "wrongpassword"
 			},
@@ -297,10 +278,10 @@ func TestAuthService_SaveTemporaryAccount(t *testing.T) {
 			name: "email already exists",
 			setupFunc: func() (string, string, string) {
 				// Create a permanent user to test email conflict
-				_, _, err := authService.Register("permanent", "existing@example.com", "password123")
+				_, _, _, err := authService.Register("permanent", "existing@example.com", "password123")
 				require.NoError(t, err)
 
-				tempUser, tempPassword, _, err := authService.CreateTemporaryUser("test-session-3")
+				tempUser, tempPassword, _, _, err := authService.CreateTemporaryUser("test-session-3")
 				require.NoError(t, err)
 				return tempUser.ID, "existing@example.com", tempPassword
 			},
@@ -342,7 +323,7 @@ func TestAuthService_GetUserByID(t *testing.T) {
 	authService := NewAuthService(db, "test-secret")
 
 	// Create a test user
-	testUser, _, err := authService.Register("gettest", "get@example.com", "password123")
+	testUser, _, _, err := authService.Register("gettest", "get@example.com", "password123")
 	require.NoError(t, err)
 
 	tests := []struct {
@@ -385,18 +366,78 @@ func TestAuthService_GetUserByID(t *testing.T) {
 	}
 }
 
+func TestAuthService_SetUserRole(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	authService := NewAuthService(db, "test-secret")
+
+	testUser, _, _, err := authService.Register("roletest", "role@example.com", "password123")
+	require.NoError(t, err)
+	assert.Equal(t, RoleUser, testUser.Role)
+	assert.False(t, testUser.IsAdmin())
+
+	tests := []struct {
+		name    string
+		userID  string
+		role    string
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:   "promote to admin",
+			userID: testUser.ID,
+			role:   RoleAdmin,
+		},
+		{
+			name:   "demote to user",
+			userID: testUser.ID,
+			role:   RoleUser,
+		},
+		{
+			name:    "invalid role",
+			userID:  testUser.ID,
+			role:    "superuser",
+			wantErr: true,
+			errMsg:  "invalid role",
+		},
+		{
+			name:    "non-existent user",
+			userID:  "non-existent-id",
+			role:    RoleAdmin,
+			wantErr: true,
+			errMsg:  "user not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := authService.SetUserRole(tt.userID, tt.role)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.role, user.Role)
+			assert.Equal(t, tt.role == RoleAdmin, user.IsAdmin())
+		})
+	}
+}
+
 func TestAuthService_ValidateToken(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
 	authService := NewAuthService(db, "test-secret")
 
 	// Create a test user and get a token
-	testUser, token, err := authService.Register("tokentest", "token@example.com", "password123")
+	testUser, token, _, err := authService.Register("tokentest", "token@example.com", "password123")
 	require.NoError(t, err)
 
 	// Create an invalid token with wrong secret
 	invalidAuthService := NewAuthService(db, "wrong-secret")
-	invalidToken, err := invalidAuthService.generateToken(testUser)
+	invalidToken, err := invalidAuthService.generateToken(testUser, "session-id")
 	require.NoError(t, err)
 
 	tests := []struct {
@@ -458,7 +499,7 @@ func TestAuthService_generateToken(t *testing.T) {
 		IsTemporary: false,
 	}
 
-	token, err := authService.generateToken(user)
+	token, err := authService.generateToken(user, "session-id")
 	require.NoError(t, err)
 	assert.NotEmpty(t, token)
 
@@ -588,7 +629,7 @@ func TestAuthService_VerifyEmail(t *testing.T) {
 	authService := NewAuthService(db, "test-secret")
 
 	// Create a user and set up email verification token
-	user, _, err := authService.Register("verifytest", "verify@example.com", "password123")
+	user, _, _, err := authService.Register("verifytest", "verify@example.com", "password123")
 	require.NoError(t, err)
 
 	// Add verification token directly to database for testing
@@ -602,7 +643,7 @@ func TestAuthService_VerifyEmail(t *testing.T) {
 	require.NoError(t, err)
 
 	// Create an expired token for another user
-	expiredUser, _, err := authService.Register("expiredtest", "expired@example.com", "password123")
+	expiredUser, _, _, err := authService.Register("expiredtest", "expired@example.com", "password123")
 	require.NoError(t, err)
 	expiredToken := "expired-token"
 	expiredTime := time.Now().Add(-time.Hour)
@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"fmt"
+	"log"
+	"net/smtp"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// EmailMessage is a plain-text email sent by the auth service
+type EmailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// EmailSender delivers emails; implement it to plug in a new provider
+type EmailSender interface {
+	Send(msg EmailMessage) error
+}
+
+// EmailProviderFactory builds an EmailSender from the environment
+type EmailProviderFactory func() (EmailSender, error)
+
+var (
+	emailProvidersMu sync.RWMutex
+	emailProviders   = map[string]EmailProviderFactory{
+		"smtp": func() (EmailSender, error) { return NewSMTPSenderFromEnv() },
+		"log":  func() (EmailSender, error) { return &LogEmailSender{}, nil },
+	}
+)
+
+// RegisterEmailProvider makes an email provider selectable through EMAIL_PROVIDER
+func RegisterEmailProvider(name string, factory EmailProviderFactory) {
+	emailProvidersMu.Lock()
+	defer emailProvidersMu.Unlock()
+	emailProviders[name] = factory
+}
+
+// NewEmailSenderFromEnv creates the sender named by EMAIL_PROVIDER. Without it, SMTP is used
+// when SMTP_HOST is set and emails are otherwise only logged.
+func NewEmailSenderFromEnv() (EmailSender, error) {
+	provider := os.Getenv("EMAIL_PROVIDER")
+	if provider == "" {
+		provider = "log"
+		if os.Getenv("SMTP_HOST") != "" {
+			provider = "smtp"
+		}
+	}
+
+	emailProvidersMu.RLock()
+	factory, ok := emailProviders[provider]
+	names := make([]string, 0, len(emailProviders))
+	for name := range emailProviders {
+		names = append(names, name)
+	}
+	emailProvidersMu.RUnlock()
+
+	if !ok {
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown email provider %q (available: %s)", provider, strings.Join(names, ", "))
+	}
+
+	sender, err := factory()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s email sender: %w", provider, err)
+	}
+
+	log.Printf("📧 Using %s email provider", provider)
+	return sender, nil
+}
+
+// SMTPSender sends emails through an SMTP server using PLAIN auth
+type SMTPSender struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// NewSMTPSenderFromEnv reads SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD and SMTP_FROM
+func NewSMTPSenderFromEnv() (*SMTPSender, error) {
+	sender := &SMTPSender{
+		Host:     os.Getenv("SMTP_HOST"),
+		Port:     os.Getenv("SMTP_PORT"),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("SMTP_FROM"),
+	}
+
+	if sender.Host == "" {
+		return nil, fmt.Errorf("SMTP_HOST is required")
+	}
+	if sender.Port == "" {
+		sender.Port = "587"
+	}
+	if sender.From == "" {
+		sender.From = sender.Username
+	}
+	if sender.From == "" {
+		return nil, fmt.Errorf("SMTP_FROM is required")
+	}
+
+	return sender, nil
+}
+
+// Send delivers the message through the configured SMTP server
+func (s *SMTPSender) Send(msg EmailMessage) error {
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+
+	body := "From: " + s.From + "\r\n" +
+		"To: " + msg.To + "\r\n" +
+		"Subject: " + msg.Subject + "\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=\"utf-8\"\r\n" +
+		"\r\n" + msg.Body
+
+	if err := smtp.SendMail(s.Host+":"+s.Port, auth, s.From, []string{msg.To}, []byte(body)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// LogEmailSender writes emails to the server log instead of sending them (for development)
+type LogEmailSender struct{}
+
+// Send logs the message
+func (LogEmailSender) Send(msg EmailMessage) error {
+	log.Printf("📧 Email to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
@@ -3,7 +3,10 @@ package auth
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
 	"time"
+
+	"gogent/internal/types"
 )
 
 // LoginRequest represents the login request body
@@ -14,9 +17,10 @@ type LoginRequest struct {
 
 // LoginResponse represents the login response
 type LoginResponse struct {
-	Token     string    `json:"token"`
-	User      *User     `json:"user"`
-	ExpiresAt time.Time `json:"expires_at"`
+	Token        string    `json:"token"`
+	RefreshToken string    `json:"refresh_token"`
+	User         *User     `json:"user"`
+	ExpiresAt    time.Time `json:"expires_at"`
 }
 
 // RegisterRequest represents the registration request body
@@ -28,8 +32,9 @@ type RegisterRequest struct {
 
 // RegisterResponse represents the registration response
 type RegisterResponse struct {
-	User  *User  `json:"user"`
-	Token string `json:"token"`
+	User         *User  `json:"user"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
 }
 
 // CreateTemporaryUserRequest represents the temporary user creation request
@@ -42,6 +47,7 @@ type CreateTemporaryUserResponse struct {
 	User              *User  `json:"user"`
 	TemporaryPassword string `json:"temporary_password"`
 	Token             string `json:"token"`
+	RefreshToken      string `json:"refresh_token"`
 }
 
 // SaveTemporaryAccountRequest represents the save temporary account request
@@ -64,8 +70,9 @@ type ConnectTemporaryAccountRequest struct {
 
 // ConnectTemporaryAccountResponse represents the connect temporary account response
 type ConnectTemporaryAccountResponse struct {
-	Token string `json:"token"`
-	User  *User  `json:"user"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	User         *User  `json:"user"`
 }
 
 // VerifyEmailRequest represents the email verification request
@@ -79,14 +86,71 @@ type VerifyEmailResponse struct {
 	Verified bool  `json:"verified"`
 }
 
+// PasswordResetRequest represents the request to email a password reset token
+type PasswordResetRequest struct {
+	Email string `json:"email"`
+}
+
+// ResetPasswordRequest represents the request to set a new password with a reset token
+type ResetPasswordRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
 // GetCurrentUserResponse represents the current user response
 type GetCurrentUserResponse struct {
 	User *User `json:"user"`
 }
 
+// SetUserRoleRequest represents an admin request to change a user's role
+type SetUserRoleRequest struct {
+	UserID string `json:"user_id"`
+	Role   string `json:"role"`
+}
+
+// SetUserRoleResponse represents the role change response
+type SetUserRoleResponse struct {
+	User *User `json:"user"`
+}
+
+// RefreshTokenRequest represents the token refresh request
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// LogoutRequest represents the logout request; the session comes from the
+// refresh token if given, otherwise from the Authorization header
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token,omitempty"`
+	AllSessions  bool   `json:"all_sessions,omitempty"`
+}
+
+// CreateAPIKeyRequest represents the API key creation request
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes,omitempty"`
+	ExpiresInDays int      `json:"expires_in_days,omitempty"` // 0 means the key never expires
+}
+
+// CreateAPIKeyResponse represents the API key creation response; Key is only returned here
+type CreateAPIKeyResponse struct {
+	APIKey *APIKey `json:"api_key"`
+	Key    string  `json:"key"`
+}
+
+// ListAPIKeysResponse represents the API key listing response
+type ListAPIKeysResponse struct {
+	APIKeys []*APIKey `json:"api_keys"`
+}
+
+// AuditFunc records a security-relevant event the auth handlers saw, such as a login. The event's
+// ActorID is empty when the actor is unknown, like a failed login.
+type AuditFunc func(r *http.Request, event *types.AuditEvent)
+
 // AuthHandlers provides HTTP handlers for authentication
 type AuthHandlers struct {
 	authService *AuthService
+	audit       AuditFunc
 }
 
 // NewAuthHandlers creates new authentication handlers
@@ -96,6 +160,18 @@ func NewAuthHandlers(authService *AuthService) *AuthHandlers {
 	}
 }
 
+// SetAuditFunc sets the function logins and API key changes are reported to
+func (ah *AuthHandlers) SetAuditFunc(audit AuditFunc) {
+	ah.audit = audit
+}
+
+// record reports an event to the audit function, if one is set
+func (ah *AuthHandlers) record(r *http.Request, event *types.AuditEvent) {
+	if ah.audit != nil {
+		ah.audit(r, event)
+	}
+}
+
 // LoginHandler handles user login
 func (ah *AuthHandlers) LoginHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -109,17 +185,20 @@ func (ah *AuthHandlers) LoginHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, token, err := ah.authService.Login(req.Username, req.Password)
+	user, token, refreshToken, err := ah.authService.Login(req.Username, req.Password)
 	if err != nil {
+		ah.record(r, &types.AuditEvent{Action: types.AuditLoginFailed, Summary: map[string]interface{}{"username": req.Username}})
 		http.Error(w, err.Error(), http.StatusUnauthorized)
 		return
 	}
+	ah.record(r, &types.AuditEvent{ActorID: user.ID, Action: types.AuditLogin, Summary: map[string]interface{}{"username": user.Username}})
 
-	expiresAt := time.Now().Add(24 * time.Hour)
+	expiresAt := time.Now().Add(ah.authService.TokenExpiry())
 	response := LoginResponse{
-		Token:     token,
-		User:      user,
-		ExpiresAt: expiresAt,
+		Token:        token,
+		RefreshToken: refreshToken,
+		User:         user,
+		ExpiresAt:    expiresAt,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -139,15 +218,16 @@ func (ah *AuthHandlers) RegisterHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	user, token, err := ah.authService.Register(req.Username, req.Email, req.Password)
+	user, token, refreshToken, err := ah.authService.Register(req.Username, req.Email, req.Password)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	response := RegisterResponse{
-		User:  user,
-		Token: token,
+		User:         user,
+		Token:        token,
+		RefreshToken: refreshToken,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -167,7 +247,7 @@ func (ah *AuthHandlers) CreateTemporaryUserHandler(w http.ResponseWriter, r *htt
 		return
 	}
 
-	user, tempPassword, token, err := ah.authService.CreateTemporaryUser(req.SessionID)
+	user, tempPassword, token, refreshToken, err := ah.authService.CreateTemporaryUser(req.SessionID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -177,6 +257,7 @@ func (ah *AuthHandlers) CreateTemporaryUserHandler(w http.ResponseWriter, r *htt
 		User:              user,
 		TemporaryPassword: tempPassword,
 		Token:             token,
+		RefreshToken:      refreshToken,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -241,15 +322,16 @@ func (ah *AuthHandlers) ConnectTemporaryAccountHandler(w http.ResponseWriter, r
 		return
 	}
 
-	updatedUser, newToken, err := ah.authService.ConnectTemporaryAccount(user.ID, req.Email, req.NewPassword)
+	updatedUser, newToken, refreshToken, err := ah.authService.ConnectTemporaryAccount(user.ID, req.Email, req.NewPassword)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	response := ConnectTemporaryAccountResponse{
-		Token: newToken,
-		User:  updatedUser,
+		Token:        newToken,
+		RefreshToken: refreshToken,
+		User:         updatedUser,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -284,6 +366,136 @@ func (ah *AuthHandlers) VerifyEmailHandler(w http.ResponseWriter, r *http.Reques
 	json.NewEncoder(w).Encode(response)
 }
 
+// RequestPasswordResetHandler emails a password reset token. It responds the same way whether
+// or not the email belongs to an account.
+func (ah *AuthHandlers) RequestPasswordResetHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req PasswordResetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if req.Email == "" {
+		http.Error(w, "Email is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := ah.authService.RequestPasswordReset(req.Email); err != nil {
+		http.Error(w, "Failed to process password reset request", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"message": "If an account exists for that email, a reset link has been sent",
+	})
+}
+
+// ResetPasswordHandler sets a new password using a reset token
+func (ah *AuthHandlers) ResetPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if req.Token == "" {
+		http.Error(w, "Reset token is required", http.StatusBadRequest)
+		return
+	}
+
+	user, err := ah.authService.ResetPassword(req.Token, req.NewPassword)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(GetCurrentUserResponse{User: user})
+}
+
+// RefreshHandler exchanges a refresh token for a new access token and rotated refresh token
+func (ah *AuthHandlers) RefreshHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req RefreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if req.RefreshToken == "" {
+		http.Error(w, "Refresh token is required", http.StatusBadRequest)
+		return
+	}
+
+	user, token, refreshToken, err := ah.authService.RefreshSession(req.RefreshToken)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	response := LoginResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		User:         user,
+		ExpiresAt:    time.Now().Add(ah.authService.TokenExpiry()),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// LogoutHandler revokes the caller's session (or all of their sessions)
+func (ah *AuthHandlers) LogoutHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req LogoutRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var err error
+	if req.RefreshToken != "" && !req.AllSessions {
+		err = ah.authService.LogoutRefreshToken(req.RefreshToken)
+	} else {
+		var token string
+		token, err = ExtractTokenFromHeader(r.Header.Get("Authorization"))
+		if err == nil {
+			err = ah.authService.Logout(token, req.AllSessions)
+		}
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+	})
+}
+
 // GetCurrentUserHandler handles getting current user information
 func (ah *AuthHandlers) GetCurrentUserHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -304,3 +516,138 @@ func (ah *AuthHandlers) GetCurrentUserHandler(w http.ResponseWriter, r *http.Req
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
+
+// SetUserRoleHandler lets an admin change another user's role
+func (ah *AuthHandlers) SetUserRoleHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut && r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req SetUserRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.UserID == "" || req.Role == "" {
+		http.Error(w, "User ID and role are required", http.StatusBadRequest)
+		return
+	}
+
+	user, err := ah.authService.SetUserRole(req.UserID, req.Role)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	response := SetUserRoleResponse{
+		User: user,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// APIKeysHandler lists (GET) or creates (POST) API keys for the current user
+func (ah *AuthHandlers) APIKeysHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := ah.requireSessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		keys, err := ah.authService.ListAPIKeys(user.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ListAPIKeysResponse{APIKeys: keys})
+
+	case http.MethodPost:
+		var req CreateAPIKeyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		if req.ExpiresInDays < 0 {
+			http.Error(w, "expires_in_days must not be negative", http.StatusBadRequest)
+			return
+		}
+
+		for _, scope := range req.Scopes {
+			if scope == ScopeAdmin && !user.IsAdmin() {
+				http.Error(w, "Only admins can create keys with the admin scope", http.StatusForbidden)
+				return
+			}
+		}
+
+		expiresIn := time.Duration(req.ExpiresInDays) * 24 * time.Hour
+		key, rawKey, err := ah.authService.CreateAPIKey(user.ID, req.Name, req.Scopes, expiresIn)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		ah.record(r, &types.AuditEvent{ActorID: user.ID, Action: types.AuditAPIKeyCreated, TargetType: "api_key", TargetID: key.ID,
+			Summary: map[string]interface{}{"name": key.Name, "prefix": key.Prefix, "scopes": key.Scopes, "expiresInDays": req.ExpiresInDays}})
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(CreateAPIKeyResponse{APIKey: key, Key: rawKey})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// APIKeyByIDHandler revokes (DELETE) one of the current user's API keys
+func (ah *AuthHandlers) APIKeyByIDHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := ah.requireSessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	keyID := strings.TrimPrefix(r.URL.Path, "/api/auth/api-keys/")
+	if keyID == "" {
+		http.Error(w, "API key ID required", http.StatusBadRequest)
+		return
+	}
+
+	if err := ah.authService.RevokeAPIKey(user.ID, keyID); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	ah.record(r, &types.AuditEvent{ActorID: user.ID, Action: types.AuditAPIKeyRevoked, TargetType: "api_key", TargetID: keyID})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"revoked": true,
+		"id":      keyID,
+	})
+}
+
+// requireSessionUser returns the authenticated user, rejecting requests made with an API key
+// so a leaked key cannot be used to mint further keys
+func (ah *AuthHandlers) requireSessionUser(w http.ResponseWriter, r *http.Request) (*User, bool) {
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	if _, usedAPIKey := GetAPIKeyFromContext(r.Context()); usedAPIKey {
+		http.Error(w, "API keys cannot manage API keys", http.StatusForbidden)
+		return nil, false
+	}
+
+	return user, true
+}
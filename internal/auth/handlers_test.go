@@ -9,6 +9,8 @@ import (
 	"testing"
 	"time"
 
+	"gogent/internal/types"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -37,7 +39,7 @@ func TestAuthHandlers_LoginHandler(t *testing.T) {
 	handlers, authService := setupHandlersTest(t)
 
 	// Create a test user
-	_, _, err := authService.Register("testuser", "test@example.com", "password123")
+	_, _, _, err := authService.Register("testuser", "test@example.com", "password123")
 	require.NoError(t, err)
 
 	tests := []struct {
@@ -129,7 +131,7 @@ func TestAuthHandlers_RegisterHandler(t *testing.T) {
 	handlers, authService := setupHandlersTest(t)
 
 	// Create a user to test conflicts
-	_, _, err := authService.Register("existing", "existing@example.com", "password123")
+	_, _, _, err := authService.Register("existing", "existing@example.com", "password123")
 	require.NoError(t, err)
 
 	tests := []struct {
@@ -311,7 +313,7 @@ func TestAuthHandlers_SaveTemporaryAccountHandler(t *testing.T) {
 	handlers, authService := setupHandlersTest(t)
 
 	// Create a temporary user
-	tempUser, tempPassword, _, err := authService.CreateTemporaryUser("test-session")
+	tempUser, tempPassword, _, _, err := authService.CreateTemporaryUser("test-session")
 	require.NoError(t, err)
 
 	tests := []struct {
@@ -414,7 +416,7 @@ func TestAuthHandlers_VerifyEmailHandler(t *testing.T) {
 	handlers, authService := setupHandlersTest(t)
 
 	// Create a user and set up verification token
-	user, _, err := authService.Register("verifytest", "verify@example.com", "password123")
+	user, _, _, err := authService.Register("verifytest", "verify@example.com", "password123")
 	require.NoError(t, err)
 
 	// Add verification token directly to database
@@ -503,7 +505,7 @@ func TestAuthHandlers_GetCurrentUserHandler(t *testing.T) {
 	handlers, authService := setupHandlersTest(t)
 
 	// Create a test user
-	user, _, err := authService.Register("currenttest", "current@example.com", "password123")
+	user, _, _, err := authService.Register("currenttest", "current@example.com", "password123")
 	require.NoError(t, err)
 
 	tests := []struct {
@@ -563,6 +565,43 @@ func TestAuthHandlers_GetCurrentUserHandler(t *testing.T) {
 	}
 }
 
+func TestAuthHandlers_Audit(t *testing.T) {
+	handlers, authService := setupHandlersTest(t)
+	user, _, _, err := authService.Register("audited", "audited@example.com", "password123")
+	require.NoError(t, err)
+
+	var events []*types.AuditEvent
+	handlers.SetAuditFunc(func(r *http.Request, event *types.AuditEvent) {
+		events = append(events, event)
+	})
+
+	login := func(password string) {
+		body, _ := json.Marshal(LoginRequest{Username: "audited", Password: password})
+		handlers.LoginHandler(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/auth/login", bytes.NewReader(body)))
+	}
+	login("wrongpassword")
+	login("password123")
+
+	body, _ := json.Marshal(CreateAPIKeyRequest{Name: "ci", Scopes: []string{ScopeRead}})
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/api-keys", bytes.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), UserContextKey{}, user))
+	w := httptest.NewRecorder()
+	handlers.APIKeysHandler(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var created CreateAPIKeyResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&created))
+
+	require.Len(t, events, 3)
+	assert.Equal(t, types.AuditLoginFailed, events[0].Action)
+	assert.Empty(t, events[0].ActorID)
+	assert.Equal(t, "audited", events[0].Summary["username"])
+	assert.Equal(t, types.AuditLogin, events[1].Action)
+	assert.Equal(t, user.ID, events[1].ActorID)
+	assert.Equal(t, types.AuditAPIKeyCreated, events[2].Action)
+	assert.Equal(t, created.APIKey.ID, events[2].TargetID)
+	assert.NotContains(t, events[2].Summary, "key", "the raw key must not be audited")
+}
+
 // Benchmark tests for handlers
 func BenchmarkLoginHandler(b *testing.B) {
 	db := setupTestDB(&testing.T{})
@@ -571,7 +610,7 @@ func BenchmarkLoginHandler(b *testing.B) {
 	handlers := NewAuthHandlers(authService)
 
 	// Create test user
-	_, _, err := authService.Register("benchuser", "bench@example.com", "password123")
+	_, _, _, err := authService.Register("benchuser", "bench@example.com", "password123")
 	if err != nil {
 		b.Fatal(err)
 	}
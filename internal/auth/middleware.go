@@ -9,6 +9,9 @@ import (
 // UserContextKey is the key used to store user in request context
 type UserContextKey struct{}
 
+// APIKeyContextKey is the key used to store the API key a request authenticated with
+type APIKeyContextKey struct{}
+
 // AuthMiddleware creates middleware that validates JWT tokens and adds user to context
 func AuthMiddleware(authService *AuthService) func(http.HandlerFunc) http.HandlerFunc {
 	return func(next http.HandlerFunc) http.HandlerFunc {
@@ -32,6 +35,24 @@ func AuthMiddleware(authService *AuthService) func(http.HandlerFunc) http.Handle
 				return
 			}
 
+			// Machine clients authenticate with "ApiKey <key>"
+			if rawKey, err := ExtractAPIKeyFromHeader(authHeader); err == nil {
+				user, apiKey, err := authService.ValidateAPIKey(rawKey)
+				if err != nil {
+					http.Error(w, "Invalid API key", http.StatusUnauthorized)
+					return
+				}
+				if !APIKeyAllows(apiKey, isWriteMethod(r.Method)) {
+					http.Error(w, "API key scope does not allow this request", http.StatusForbidden)
+					return
+				}
+
+				ctx := context.WithValue(r.Context(), UserContextKey{}, user)
+				ctx = context.WithValue(ctx, APIKeyContextKey{}, apiKey)
+				next(w, r.WithContext(ctx))
+				return
+			}
+
 			token, err := ExtractTokenFromHeader(authHeader)
 			if err != nil {
 				http.Error(w, "Invalid authorization header", http.StatusUnauthorized)
@@ -58,6 +79,22 @@ func GetUserFromContext(ctx context.Context) (*User, bool) {
 	return user, ok
 }
 
+// GetAPIKeyFromContext extracts the API key from context when the caller used one
+func GetAPIKeyFromContext(ctx context.Context) (*APIKey, bool) {
+	apiKey, ok := ctx.Value(APIKeyContextKey{}).(*APIKey)
+	return apiKey, ok
+}
+
+// isWriteMethod reports whether an HTTP method modifies state
+func isWriteMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
+
 // shouldSkipAuth returns true if the endpoint should skip authentication
 func shouldSkipAuth(path string) bool {
 	skipPaths := []string{
@@ -66,6 +103,10 @@ func shouldSkipAuth(path string) bool {
 		"/api/auth/register",
 		"/api/auth/temp-user",
 		"/api/auth/verify-email",
+		"/api/auth/refresh",
+		"/api/auth/logout",
+		"/api/auth/password-reset/request",
+		"/api/auth/password-reset/confirm",
 	}
 
 	for _, skipPath := range skipPaths {
@@ -81,3 +122,25 @@ func requiresAuth(path string) bool {
 	// All API endpoints except auth endpoints require authentication
 	return strings.HasPrefix(path, "/api/") && !strings.HasPrefix(path, "/api/auth/")
 }
+
+// RequireAdmin wraps a handler so only authenticated admins can reach it
+func RequireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := GetUserFromContext(r.Context())
+		if !ok {
+			http.Error(w, "Authorization header required", http.StatusUnauthorized)
+			return
+		}
+		if !user.IsAdmin() {
+			http.Error(w, "Admin access required", http.StatusForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// IsAdminRequest reports whether the request was made by an admin
+func IsAdminRequest(r *http.Request) bool {
+	user, ok := GetUserFromContext(r.Context())
+	return ok && user.IsAdmin()
+}
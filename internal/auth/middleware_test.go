@@ -16,7 +16,7 @@ func TestAuthMiddleware(t *testing.T) {
 	authService := NewAuthService(db, "test-secret")
 
 	// Create a test user and get a token
-	user, token, err := authService.Register("middlewaretest", "middleware@example.com", "password123")
+	user, token, _, err := authService.Register("middlewaretest", "middleware@example.com", "password123")
 	require.NoError(t, err)
 
 	middleware := AuthMiddleware(authService)
@@ -192,6 +192,49 @@ func TestGetUserFromContext(t *testing.T) {
 	}
 }
 
+func TestRequireAdmin(t *testing.T) {
+	okHandler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	tests := []struct {
+		name       string
+		user       *User
+		wantStatus int
+	}{
+		{
+			name:       "admin user",
+			user:       &User{ID: "admin-id", Role: RoleAdmin},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "regular user",
+			user:       &User{ID: "user-id", Role: RoleUser},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "no user",
+			user:       nil,
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/admin/test", nil)
+			if tt.user != nil {
+				req = req.WithContext(context.WithValue(req.Context(), UserContextKey{}, tt.user))
+			}
+
+			w := httptest.NewRecorder()
+			RequireAdmin(okHandler)(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantStatus == http.StatusOK, IsAdminRequest(req))
+		})
+	}
+}
+
 func TestShouldSkipAuth(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -330,10 +373,10 @@ func TestMiddleware_Integration(t *testing.T) {
 	authService := NewAuthService(db, "test-secret")
 
 	// Create test users
-	normalUser, normalToken, err := authService.Register("normal", "normal@example.com", "password123")
+	normalUser, normalToken, _, err := authService.Register("normal", "normal@example.com", "password123")
 	require.NoError(t, err)
 
-	tempUser, _, tempToken, err := authService.CreateTemporaryUser("test-session")
+	tempUser, _, tempToken, _, err := authService.CreateTemporaryUser("test-session")
 	require.NoError(t, err)
 
 	middleware := AuthMiddleware(authService)
@@ -412,7 +455,7 @@ func TestMiddleware_ErrorHandling(t *testing.T) {
 	authService := NewAuthService(db, "test-secret")
 
 	// Create and then delete a user to test token validation with non-existent user
-	user, token, err := authService.Register("deleteme", "delete@example.com", "password123")
+	user, token, _, err := authService.Register("deleteme", "delete@example.com", "password123")
 	require.NoError(t, err)
 
 	// Delete user from database
@@ -442,7 +485,7 @@ func BenchmarkAuthMiddleware_ValidToken(b *testing.B) {
 	authService := NewAuthService(db, "test-secret")
 
 	// Create test user
-	_, token, err := authService.Register("benchuser", "bench@example.com", "password123")
+	_, token, _, err := authService.Register("benchuser", "bench@example.com", "password123")
 	if err != nil {
 		b.Fatal(err)
 	}
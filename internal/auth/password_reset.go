@@ -0,0 +1,169 @@
+package auth
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// passwordResetTokenPrefix marks GoGent password reset tokens
+const passwordResetTokenPrefix = "gpr_"
+
+// SetEmailSender sets the sender used for password reset emails
+func (as *AuthService) SetEmailSender(sender EmailSender) {
+	as.emailSender = sender
+}
+
+// SetPasswordResetURL sets the page reset emails link to, e.g. https://app.example.com/reset-password
+func (as *AuthService) SetPasswordResetURL(url string) {
+	as.passwordResetURL = url
+}
+
+// RequestPasswordReset emails a single-use reset token to the account with the given email. The
+// account lookup, token and email happen in the background and their failures are only logged, so
+// callers get the same answer at the same speed whether or not the account exists.
+func (as *AuthService) RequestPasswordReset(email string) error {
+	if email == "" {
+		return fmt.Errorf("email is required")
+	}
+
+	as.passwordResets.Add(1)
+	go func() {
+		defer as.passwordResets.Done()
+		if err := as.sendPasswordReset(email); err != nil {
+			log.Printf("⚠️ Password reset failed: %v", err)
+		}
+	}()
+	return nil
+}
+
+// sendPasswordReset emails a reset token to the account with the given email, if there is one
+func (as *AuthService) sendPasswordReset(email string) error {
+	var userID, username string
+	err := as.db.QueryRow(
+		"SELECT id, username FROM users WHERE email = ? AND is_temporary = FALSE", email,
+	).Scan(&userID, &username)
+	if err == sql.ErrNoRows {
+		log.Printf("🔑 Password reset requested for unknown email")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+
+	now := time.Now()
+
+	// Only the most recent reset link works
+	_, err = as.db.Exec(
+		"UPDATE password_reset_tokens SET used_at = ? WHERE user_id = ? AND used_at IS NULL",
+		now, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to invalidate previous reset tokens: %w", err)
+	}
+
+	token := passwordResetTokenPrefix + generateRandomString(32)
+	expiresAt := now.Add(as.passwordResetExpiry)
+
+	query := `
+		INSERT INTO password_reset_tokens (id, user_id, token_hash, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	_, err = as.db.Exec(query, uuid.New().String(), userID, hashToken(token), expiresAt, now)
+	if err != nil {
+		return fmt.Errorf("failed to create reset token: %w", err)
+	}
+
+	body := fmt.Sprintf("Hi %s,\n\nWe received a request to reset your GoGent password.\n\n", username)
+	if as.passwordResetURL != "" {
+		body += fmt.Sprintf("Reset it here: %s?token=%s\n\n", as.passwordResetURL, token)
+	} else {
+		body += fmt.Sprintf("Your reset token is: %s\n\n", token)
+	}
+	body += fmt.Sprintf("This link expires in %s. If you did not request a reset, you can ignore this email.\n",
+		as.passwordResetExpiry)
+
+	err = as.emailSender.Send(EmailMessage{
+		To:      email,
+		Subject: "Reset your GoGent password",
+		Body:    body,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send reset email: %w", err)
+	}
+
+	log.Printf("🔑 Password reset email sent for user: %s", username)
+	return nil
+}
+
+// ResetPassword sets a new password using a reset token and signs the user out everywhere
+func (as *AuthService) ResetPassword(token, newPassword string) (*User, error) {
+	if len(newPassword) < 6 {
+		return nil, fmt.Errorf("password must be at least 6 characters long")
+	}
+
+	tokenHash := hashToken(token)
+
+	var tokenID, userID string
+	var expiresAt time.Time
+	var usedAt sql.NullTime
+
+	err := as.db.QueryRow(
+		"SELECT id, user_id, expires_at, used_at FROM password_reset_tokens WHERE token_hash = ?", tokenHash,
+	).Scan(&tokenID, &userID, &expiresAt, &usedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("invalid reset token")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	if usedAt.Valid {
+		return nil, fmt.Errorf("reset token already used")
+	}
+	if time.Now().After(expiresAt) {
+		return nil, fmt.Errorf("reset token expired")
+	}
+
+	// Claim the token before changing the password so concurrent requests cannot both use it
+	now := time.Now()
+	result, err := as.db.Exec(
+		"UPDATE password_reset_tokens SET used_at = ? WHERE id = ? AND used_at IS NULL", now, tokenID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to redeem reset token: %w", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return nil, fmt.Errorf("reset token already used")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	_, err = as.db.Exec(
+		"UPDATE users SET password_hash = ?, updated_at = ? WHERE id = ?", string(hashedPassword), now, userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update password: %w", err)
+	}
+
+	// Whoever had the old password should not stay signed in
+	if err := as.RevokeUserSessions(userID); err != nil {
+		log.Printf("⚠️ Failed to revoke sessions after password reset: %v", err)
+	}
+
+	user, err := as.GetUserByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get updated user: %w", err)
+	}
+
+	log.Printf("✅ Password reset for user: %s", user.Username)
+	return user, nil
+}
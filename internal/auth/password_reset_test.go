@@ -0,0 +1,229 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureEmailSender records sent emails for assertions
+type captureEmailSender struct {
+	sent []EmailMessage
+}
+
+func (c *captureEmailSender) Send(msg EmailMessage) error {
+	c.sent = append(c.sent, msg)
+	return nil
+}
+
+var resetTokenPattern = regexp.MustCompile(`gpr_[A-Za-z0-9]+`)
+
+// lastResetToken extracts the reset token from the most recent email
+func (c *captureEmailSender) lastResetToken(t *testing.T) string {
+	require.NotEmpty(t, c.sent, "no email was sent")
+	token := resetTokenPattern.FindString(c.sent[len(c.sent)-1].Body)
+	require.NotEmpty(t, token, "email did not contain a reset token")
+	return token
+}
+
+func TestAuthService_PasswordReset(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	authService := NewAuthService(db, "test-secret")
+	sender := &captureEmailSender{}
+	authService.SetEmailSender(sender)
+	authService.SetPasswordResetURL("https://app.example.com/reset-password")
+
+	user, token, refreshToken, err := authService.Register("forgetful", "forgetful@example.com", "password123")
+	require.NoError(t, err)
+
+	require.NoError(t, authService.RequestPasswordReset("forgetful@example.com"))
+	authService.passwordResets.Wait()
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, "forgetful@example.com", sender.sent[0].To)
+	assert.Contains(t, sender.sent[0].Body, "https://app.example.com/reset-password?token=gpr_")
+	resetToken := sender.lastResetToken(t)
+
+	// Only the hash is stored
+	var stored int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM password_reset_tokens WHERE token_hash = ?", resetToken).Scan(&stored))
+	assert.Equal(t, 0, stored)
+
+	_, err = authService.ResetPassword(resetToken, "short")
+	assert.Error(t, err)
+
+	resetUser, err := authService.ResetPassword(resetToken, "newpassword456")
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, resetUser.ID)
+
+	// New password works, old one does not
+	_, _, _, err = authService.Login("forgetful", "newpassword456")
+	assert.NoError(t, err)
+	_, _, _, err = authService.Login("forgetful", "password123")
+	assert.Error(t, err)
+
+	// Existing sessions are signed out
+	_, err = authService.ValidateToken(token)
+	assert.Error(t, err)
+	_, _, _, err = authService.RefreshSession(refreshToken)
+	assert.Error(t, err)
+
+	// Tokens are single-use
+	_, err = authService.ResetPassword(resetToken, "anotherpassword")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already used")
+}
+
+func TestAuthService_PasswordReset_TokenRules(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	authService := NewAuthService(db, "test-secret")
+	sender := &captureEmailSender{}
+	authService.SetEmailSender(sender)
+
+	user, _, _, err := authService.Register("resetter", "resetter@example.com", "password123")
+	require.NoError(t, err)
+
+	// Unknown emails succeed silently
+	require.NoError(t, authService.RequestPasswordReset("nobody@example.com"))
+	authService.passwordResets.Wait()
+	assert.Empty(t, sender.sent)
+
+	// A newer request supersedes the older token
+	require.NoError(t, authService.RequestPasswordReset("resetter@example.com"))
+	authService.passwordResets.Wait()
+	firstToken := sender.lastResetToken(t)
+	require.NoError(t, authService.RequestPasswordReset("resetter@example.com"))
+	authService.passwordResets.Wait()
+	secondToken := sender.lastResetToken(t)
+
+	_, err = authService.ResetPassword(firstToken, "newpassword456")
+	assert.Error(t, err)
+
+	// Expired tokens are rejected
+	_, err = db.Exec("UPDATE password_reset_tokens SET expires_at = ? WHERE user_id = ?", time.Now().Add(-time.Minute), user.ID)
+	require.NoError(t, err)
+	_, err = authService.ResetPassword(secondToken, "newpassword456")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expired")
+
+	_, err = authService.ResetPassword("gpr_unknown", "newpassword456")
+	assert.Error(t, err)
+}
+
+// blockingEmailSender holds each email until release is closed, then fails to send it
+type blockingEmailSender struct {
+	release chan struct{}
+}
+
+func (b *blockingEmailSender) Send(msg EmailMessage) error {
+	<-b.release
+	return errors.New("smtp: connection refused")
+}
+
+func TestAuthService_PasswordReset_SendsInBackground(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	authService := NewAuthService(db, "test-secret")
+	sender := &blockingEmailSender{release: make(chan struct{})}
+	authService.SetEmailSender(sender)
+	handlers := NewAuthHandlers(authService)
+
+	_, _, _, err := authService.Register("slowmail", "slowmail@example.com", "password123")
+	require.NoError(t, err)
+
+	// The request returns while the email is still being sent, and its failure is not reported
+	body, _ := json.Marshal(PasswordResetRequest{Email: "slowmail@example.com"})
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/password-reset/request", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+	handlers.RequestPasswordResetHandler(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	close(sender.release)
+	authService.passwordResets.Wait()
+	require.NoError(t, authService.RequestPasswordReset("slowmail@example.com"))
+	authService.passwordResets.Wait()
+	assert.Error(t, authService.RequestPasswordReset(""))
+}
+
+func TestAuthHandlers_PasswordResetHandlers(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	authService := NewAuthService(db, "test-secret")
+	sender := &captureEmailSender{}
+	authService.SetEmailSender(sender)
+	handlers := NewAuthHandlers(authService)
+
+	_, _, _, err := authService.Register("handlerreset", "handlerreset@example.com", "password123")
+	require.NoError(t, err)
+
+	// Request
+	body, _ := json.Marshal(PasswordResetRequest{Email: "handlerreset@example.com"})
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/password-reset/request", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+	handlers.RequestPasswordResetHandler(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	authService.passwordResets.Wait()
+
+	// Unknown emails get the same response
+	body, _ = json.Marshal(PasswordResetRequest{Email: "unknown@example.com"})
+	req = httptest.NewRequest(http.MethodPost, "/api/auth/password-reset/request", bytes.NewBuffer(body))
+	w = httptest.NewRecorder()
+	handlers.RequestPasswordResetHandler(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	authService.passwordResets.Wait()
+
+	// Confirm
+	body, _ = json.Marshal(ResetPasswordRequest{Token: sender.lastResetToken(t), NewPassword: "newpassword456"})
+	req = httptest.NewRequest(http.MethodPost, "/api/auth/password-reset/confirm", bytes.NewBuffer(body))
+	w = httptest.NewRecorder()
+	handlers.ResetPasswordHandler(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response GetCurrentUserResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, "handlerreset", response.User.Username)
+
+	// Missing token
+	req = httptest.NewRequest(http.MethodPost, "/api/auth/password-reset/confirm", bytes.NewBufferString(`{"new_password":"whatever123"}`))
+	w = httptest.NewRecorder()
+	handlers.ResetPasswordHandler(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestNewEmailSenderFromEnv(t *testing.T) {
+	t.Setenv("EMAIL_PROVIDER", "")
+	t.Setenv("SMTP_HOST", "")
+
+	sender, err := NewEmailSenderFromEnv()
+	require.NoError(t, err)
+	assert.IsType(t, &LogEmailSender{}, sender)
+
+	t.Setenv("SMTP_HOST", "smtp.example.com")
+	t.Setenv("SMTP_FROM", "noreply@example.com")
+	sender, err = NewEmailSenderFromEnv()
+	require.NoError(t, err)
+	smtpSender, ok := sender.(*SMTPSender)
+	require.True(t, ok)
+	assert.Equal(t, "587", smtpSender.Port)
+
+	// Custom providers plug in by name
+	custom := &captureEmailSender{}
+	RegisterEmailProvider("capture", func() (EmailSender, error) { return custom, nil })
+	t.Setenv("EMAIL_PROVIDER", "capture")
+	sender, err = NewEmailSenderFromEnv()
+	require.NoError(t, err)
+	assert.Same(t, custom, sender)
+
+	t.Setenv("EMAIL_PROVIDER", "carrier-pigeon")
+	_, err = NewEmailSenderFromEnv()
+	assert.Error(t, err)
+}
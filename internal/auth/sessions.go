@@ -0,0 +1,204 @@
+package auth
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// refreshTokenPrefix marks GoGent refresh tokens
+const refreshTokenPrefix = "grt_"
+
+// createSession records a new login session and returns its access token and first refresh token
+func (as *AuthService) createSession(user *User) (string, string, error) {
+	sessionID := uuid.New().String()
+	refreshToken := refreshTokenPrefix + generateRandomString(48)
+	now := time.Now()
+
+	// user_sessions.token holds the hash of the session's current refresh token
+	query := `
+		INSERT INTO user_sessions (id, user_id, token, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	_, err := as.db.Exec(query, sessionID, user.ID, hashToken(refreshToken), now.Add(as.refreshExpiry), now)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create session: %w", err)
+	}
+
+	token, err := as.generateToken(user, sessionID)
+	if err != nil {
+		return "", "", err
+	}
+
+	return token, refreshToken, nil
+}
+
+// RefreshSession exchanges a refresh token for a new access token and a rotated refresh token.
+// Presenting an already-rotated refresh token revokes the whole session, since it means the token leaked.
+func (as *AuthService) RefreshSession(refreshToken string) (*User, string, string, error) {
+	oldHash := hashToken(refreshToken)
+
+	var sessionID, userID string
+	var expiresAt time.Time
+	var revokedAt sql.NullTime
+
+	err := as.db.QueryRow(
+		"SELECT id, user_id, expires_at, revoked_at FROM user_sessions WHERE token = ?", oldHash,
+	).Scan(&sessionID, &userID, &expiresAt, &revokedAt)
+	if err == sql.ErrNoRows {
+		as.revokeReusedRefreshToken(oldHash)
+		return nil, "", "", fmt.Errorf("invalid refresh token")
+	}
+	if err != nil {
+		return nil, "", "", fmt.Errorf("database error: %w", err)
+	}
+
+	if revokedAt.Valid {
+		return nil, "", "", fmt.Errorf("session revoked")
+	}
+	if time.Now().After(expiresAt) {
+		return nil, "", "", fmt.Errorf("session expired")
+	}
+
+	user, err := as.GetUserByID(userID)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("user not found: %w", err)
+	}
+
+	// Rotate: the presented token becomes the "previous" token and stops working
+	newRefreshToken := refreshTokenPrefix + generateRandomString(48)
+	result, err := as.db.Exec(`
+		UPDATE user_sessions
+		SET token = ?, previous_token = ?, last_refreshed_at = ?
+		WHERE id = ? AND token = ? AND revoked_at IS NULL
+	`, hashToken(newRefreshToken), oldHash, time.Now(), sessionID, oldHash)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		// Another request rotated this token first
+		return nil, "", "", fmt.Errorf("invalid refresh token")
+	}
+
+	token, err := as.generateToken(user, sessionID)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	return user, token, newRefreshToken, nil
+}
+
+// revokeReusedRefreshToken revokes the session a rotated-out refresh token belonged to
+func (as *AuthService) revokeReusedRefreshToken(tokenHash string) {
+	result, err := as.db.Exec(
+		"UPDATE user_sessions SET revoked_at = ? WHERE previous_token = ? AND revoked_at IS NULL",
+		time.Now(), tokenHash,
+	)
+	if err != nil {
+		log.Printf("⚠️ Failed to revoke session after refresh token reuse: %v", err)
+		return
+	}
+	if affected, _ := result.RowsAffected(); affected > 0 {
+		log.Printf("🚨 Refresh token reuse detected; session revoked")
+	}
+}
+
+// Logout revokes the session behind an access token, or every session of its user when allSessions is set
+func (as *AuthService) Logout(accessToken string, allSessions bool) error {
+	token, err := jwt.ParseWithClaims(accessToken, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		return as.jwtSecret, nil
+	})
+	if err != nil || !token.Valid {
+		return fmt.Errorf("invalid token")
+	}
+
+	claims, ok := token.Claims.(*Claims)
+	if !ok || claims.ID == "" {
+		return fmt.Errorf("invalid token claims")
+	}
+
+	if allSessions {
+		return as.RevokeUserSessions(claims.UserID)
+	}
+	return as.RevokeSession(claims.ID)
+}
+
+// LogoutRefreshToken revokes the session a refresh token belongs to
+func (as *AuthService) LogoutRefreshToken(refreshToken string) error {
+	result, err := as.db.Exec(
+		"UPDATE user_sessions SET revoked_at = ? WHERE token = ? AND revoked_at IS NULL",
+		time.Now(), hashToken(refreshToken),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("invalid refresh token")
+	}
+	return nil
+}
+
+// RevokeSession revokes a single login session, invalidating its access and refresh tokens
+func (as *AuthService) RevokeSession(sessionID string) error {
+	_, err := as.db.Exec(
+		"UPDATE user_sessions SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL",
+		time.Now(), sessionID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	log.Printf("🔒 Revoked session %s", sessionID)
+	return nil
+}
+
+// RevokeUserSessions revokes every active session of a user
+func (as *AuthService) RevokeUserSessions(userID string) error {
+	_, err := as.db.Exec(
+		"UPDATE user_sessions SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL",
+		time.Now(), userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	log.Printf("🔒 Revoked all sessions for user %s", userID)
+	return nil
+}
+
+// RefreshExpiry returns how long a login session (and its refresh tokens) remains valid
+func (as *AuthService) RefreshExpiry() time.Duration {
+	return as.refreshExpiry
+}
+
+// checkSessionActive returns an error unless the session exists, is not revoked and has not expired
+func (as *AuthService) checkSessionActive(sessionID string) error {
+	if sessionID == "" {
+		return fmt.Errorf("token has no session")
+	}
+
+	var expiresAt time.Time
+	var revokedAt sql.NullTime
+	err := as.db.QueryRow(
+		"SELECT expires_at, revoked_at FROM user_sessions WHERE id = ?", sessionID,
+	).Scan(&expiresAt, &revokedAt)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("session not found")
+	}
+	if err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+
+	if revokedAt.Valid {
+		return fmt.Errorf("session revoked")
+	}
+	if time.Now().After(expiresAt) {
+		return fmt.Errorf("session expired")
+	}
+	return nil
+}
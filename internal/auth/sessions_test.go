@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthService_RefreshSession(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	authService := NewAuthService(db, "test-secret")
+
+	user, token, refreshToken, err := authService.Register("refresher", "refresh@example.com", "password123")
+	require.NoError(t, err)
+	assert.NotEmpty(t, refreshToken)
+
+	// Rotation returns a new access token and refresh token for the same user
+	refreshedUser, newToken, newRefreshToken, err := authService.RefreshSession(refreshToken)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, refreshedUser.ID)
+	assert.NotEqual(t, refreshToken, newRefreshToken)
+
+	_, err = authService.ValidateToken(newToken)
+	assert.NoError(t, err)
+	_, err = authService.ValidateToken(token)
+	assert.NoError(t, err, "earlier access tokens of a live session stay valid until they expire")
+
+	// Reusing the rotated-out refresh token is treated as theft and kills the session
+	_, _, _, err = authService.RefreshSession(refreshToken)
+	require.Error(t, err)
+
+	_, err = authService.ValidateToken(newToken)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "session revoked")
+
+	_, _, _, err = authService.RefreshSession(newRefreshToken)
+	assert.Error(t, err)
+}
+
+func TestAuthService_RefreshSession_Expired(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	authService := NewAuthService(db, "test-secret")
+
+	user, _, refreshToken, err := authService.Register("expiring", "expiring@example.com", "password123")
+	require.NoError(t, err)
+
+	_, err = db.Exec("UPDATE user_sessions SET expires_at = ? WHERE user_id = ?", time.Now().Add(-time.Minute), user.ID)
+	require.NoError(t, err)
+
+	_, _, _, err = authService.RefreshSession(refreshToken)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "session expired")
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	authService := NewAuthService(db, "test-secret")
+
+	_, firstToken, _, err := authService.Register("leaver", "leaver@example.com", "password123")
+	require.NoError(t, err)
+	_, secondToken, secondRefresh, err := authService.Login("leaver", "password123")
+	require.NoError(t, err)
+
+	// Logging out one session leaves the other usable
+	require.NoError(t, authService.Logout(firstToken, false))
+	_, err = authService.ValidateToken(firstToken)
+	assert.Error(t, err)
+	_, err = authService.ValidateToken(secondToken)
+	assert.NoError(t, err)
+
+	// Logging out everywhere revokes the remaining session and its refresh token
+	_, thirdToken, _, err := authService.Login("leaver", "password123")
+	require.NoError(t, err)
+	require.NoError(t, authService.Logout(thirdToken, true))
+
+	_, err = authService.ValidateToken(secondToken)
+	assert.Error(t, err)
+	_, _, _, err = authService.RefreshSession(secondRefresh)
+	assert.Error(t, err)
+
+	assert.Error(t, authService.Logout("not-a-token", false))
+}
+
+func TestAuthHandlers_RefreshAndLogoutHandlers(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	authService := NewAuthService(db, "test-secret")
+	handlers := NewAuthHandlers(authService)
+
+	_, _, refreshToken, err := authService.Register("handlerflow", "flow@example.com", "password123")
+	require.NoError(t, err)
+
+	// Refresh
+	body, _ := json.Marshal(RefreshTokenRequest{RefreshToken: refreshToken})
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/refresh", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+	handlers.RefreshHandler(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var refreshed LoginResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&refreshed))
+	assert.NotEmpty(t, refreshed.Token)
+	assert.NotEmpty(t, refreshed.RefreshToken)
+
+	// Missing refresh token
+	req = httptest.NewRequest(http.MethodPost, "/api/auth/refresh", bytes.NewBufferString(`{}`))
+	w = httptest.NewRecorder()
+	handlers.RefreshHandler(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Logout with the access token
+	req = httptest.NewRequest(http.MethodPost, "/api/auth/logout", nil)
+	req.Header.Set("Authorization", "Bearer "+refreshed.Token)
+	w = httptest.NewRecorder()
+	handlers.LogoutHandler(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	_, err = authService.ValidateToken(refreshed.Token)
+	assert.Error(t, err)
+
+	// Logout without credentials
+	req = httptest.NewRequest(http.MethodPost, "/api/auth/logout", nil)
+	w = httptest.NewRecorder()
+	handlers.LogoutHandler(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -4,193 +4,243 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
-	"gogent/internal/types"
+	"golang.org/x/oauth2"
 )
 
-// GeminiClient wraps the Google Generative AI REST API
-type GeminiClient struct {
-	apiKey     string
-	httpClient *http.Client
+const (
+	// DefaultBaseURL is the Gemini REST API root
+	DefaultBaseURL = "https://generativelanguage.googleapis.com/v1beta"
+	// DefaultTimeout bounds a single HTTP attempt when the context has no deadline of its own
+	DefaultTimeout = 30 * time.Second
+	// DefaultMaxRetries is how many times a retryable failure is retried
+	DefaultMaxRetries = 2
+	// defaultRetryBackoff is the wait before the first retry; it doubles on each retry
+	defaultRetryBackoff = 500 * time.Millisecond
+)
+
+// transport is shared by every client so connections to the API are reused
+var transport = http.DefaultTransport
+
+// APIError is a non-200 response from the Gemini API
+type APIError struct {
+	StatusCode int
+	Body       string
 }
 
-// NewGeminiClient creates a new Gemini API client using the REST API
-func NewGeminiClient(ctx context.Context, apiKey string) (*GeminiClient, error) {
-	if apiKey == "" {
-		return nil, fmt.Errorf("API key is required")
-	}
+func (e *APIError) Error() string {
+	return fmt.Sprintf("HTTP error %d: %s", e.StatusCode, e.Body)
+}
 
-	return &GeminiClient{
-		apiKey:     apiKey,
-		httpClient: &http.Client{Timeout: 60 * time.Second},
-	}, nil
+// Client calls the Gemini REST API
+type Client struct {
+	apiKey       string
+	tokens       oauth2.TokenSource // Authorizes calls with OAuth tokens instead of the API key
+	baseURL      string
+	httpClient   *http.Client
+	timeout      time.Duration
+	maxRetries   int
+	retryBackoff time.Duration
 }
 
-// Close closes the Gemini client (no-op for REST API)
-func (c *GeminiClient) Close() error {
-	return nil
+// NewClient creates a Gemini REST API client with the default timeout and retries
+func NewClient(apiKey string) *Client {
+	return &Client{
+		apiKey:       apiKey,
+		baseURL:      DefaultBaseURL,
+		httpClient:   &http.Client{Transport: transport},
+		timeout:      DefaultTimeout,
+		maxRetries:   DefaultMaxRetries,
+		retryBackoff: defaultRetryBackoff,
+	}
 }
 
-// GenerateContent generates content using the Gemini REST API (matches official documentation)
-func (c *GeminiClient) GenerateContent(ctx context.Context, config *types.APIConfiguration, prompt, contextStr string) (*types.APIResponse, error) {
-	startTime := time.Now()
+// SetTransport replaces the transport requests are sent with, such as with a fixture recorder in tests
+func (c *Client) SetTransport(rt http.RoundTripper) {
+	c.httpClient = &http.Client{Transport: rt}
+}
 
-	// Build the full prompt with system prompt and context
-	fullPrompt := prompt
-	if config.SystemPrompt != "" {
-		fullPrompt = fmt.Sprintf("System: %s\n\nUser: %s", config.SystemPrompt, prompt)
+// GenerateContent calls a model's generateContent endpoint
+func (c *Client) GenerateContent(ctx context.Context, model string, request *GenerateContentRequest) (*GenerateContentResponse, error) {
+	var response GenerateContentResponse
+	if err := c.do(ctx, http.MethodPost, modelPath(model)+":generateContent", request, &response); err != nil {
+		return nil, err
 	}
-	if contextStr != "" {
-		fullPrompt = fmt.Sprintf("%s\n\nContext: %s", fullPrompt, contextStr)
+	return &response, nil
+}
+
+// CountTokens counts the input tokens of a generateContent request, including its system
+// instruction and tools, without generating anything
+func (c *Client) CountTokens(ctx context.Context, model string, request *GenerateContentRequest) (int, error) {
+	body := &countTokensRequest{GenerateContentRequest: &modelRequest{Model: modelPath(model), GenerateContentRequest: request}}
+	var response countTokensResponse
+	if err := c.do(ctx, http.MethodPost, modelPath(model)+":countTokens", body, &response); err != nil {
+		return 0, err
 	}
+	return response.TotalTokens, nil
+}
 
-	log.Printf("Gemini REST API call - Model: %s, Prompt length: %d", config.ModelName, len(fullPrompt))
+// CreateCachedContent creates a cache entry for a model and returns it with its name. Models that
+// don't support caching, and contents below the model's minimum size, are rejected with an APIError.
+func (c *Client) CreateCachedContent(ctx context.Context, model string, content *CachedContent) (*CachedContent, error) {
+	request := *content
+	request.Model = modelPath(model)
+	var response CachedContent
+	if err := c.do(ctx, http.MethodPost, "cachedContents", &request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// DeleteCachedContent deletes a cache entry by name before it expires
+func (c *Client) DeleteCachedContent(ctx context.Context, name string) error {
+	var response struct{}
+	return c.do(ctx, http.MethodDelete, name, nil, &response)
+}
 
-	// Build the REST API request (following official documentation format)
-	requestBody := map[string]interface{}{
-		"contents": []map[string]interface{}{
-			{
-				"parts": []map[string]interface{}{
-					{"text": fullPrompt},
-			},
-			},
-		},
+// EmbedContent embeds text with an embedding model
+func (c *Client) EmbedContent(ctx context.Context, model, text string) ([]float32, error) {
+	request := &embedContentRequest{Model: modelPath(model), Content: TextContent(text)}
+	var response embedContentResponse
+	if err := c.do(ctx, http.MethodPost, modelPath(model)+":embedContent", request, &response); err != nil {
+		return nil, err
 	}
+	if len(response.Embedding.Values) == 0 {
+		return nil, fmt.Errorf("embedding response has no values")
+	}
+	return response.Embedding.Values, nil
+}
 
-	// Add generation config if specified
-	generationConfig := make(map[string]interface{})
-	if config.Temperature != nil {
-		generationConfig["temperature"] = *config.Temperature
+// ListModels fetches one page of the models endpoint; pass the previous page's NextPageToken to
+// continue
+func (c *Client) ListModels(ctx context.Context, pageSize int, pageToken string) (*ListModelsResponse, error) {
+	query := url.Values{}
+	if pageSize > 0 {
+		query.Set("pageSize", fmt.Sprint(pageSize))
 	}
-	if config.MaxTokens != nil {
-		generationConfig["maxOutputTokens"] = *config.MaxTokens
+	if pageToken != "" {
+		query.Set("pageToken", pageToken)
 	}
-	if config.TopP != nil {
-		generationConfig["topP"] = *config.TopP
+	path := "models"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
 	}
-	if config.TopK != nil {
-		generationConfig["topK"] = *config.TopK
+
+	var response ListModelsResponse
+	if err := c.do(ctx, http.MethodGet, path, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// modelPath returns the resource path of a model, accepting names with or without "models/" and
+// Vertex AI publisher model names
+func modelPath(model string) string {
+	return "models/" + strings.TrimPrefix(strings.TrimPrefix(model, "publishers/google/"), "models/")
+}
+
+// do sends a request, retrying transport errors, rate limits and server errors with exponential
+// backoff, and decodes a 200 response into out
+func (c *Client) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
 	}
-	if len(generationConfig) > 0 {
-		requestBody["generationConfig"] = generationConfig
+
+	backoff := c.retryBackoff
+	for attempt := 0; ; attempt++ {
+		respBody, err := c.send(ctx, method, path, body)
+		if err == nil {
+			if err := json.Unmarshal(respBody, out); err != nil {
+				return fmt.Errorf("failed to parse response: %w", err)
+			}
+			return nil
+		}
+		if attempt >= c.maxRetries || !retryable(ctx, err) {
+			return err
+		}
+
+		log.Printf("⚠️ Gemini %s %s failed (attempt %d/%d), retrying in %v: %v", method, path, attempt+1, c.maxRetries+1, backoff, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
 	}
+}
 
-	// Serialize request
-	jsonBody, err := json.Marshal(requestBody)
-	if err != nil {
-		log.Printf("REST API - Marshal error: %v", err)
-		return &types.APIResponse{
-			ResponseStatus: types.ResponseStatusError,
-			ErrorMessage:   fmt.Sprintf("Failed to marshal request: %v", err),
-			ResponseTimeMs: int32(time.Since(startTime).Milliseconds()),
-		}, nil
+// send makes a single HTTP attempt and returns the body of a 200 response. The attempt ends at the
+// context's deadline, or after the client's timeout when the context has none.
+func (c *Client) send(ctx context.Context, method, path string, body []byte) ([]byte, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
 	}
 
-	// Make HTTP request to Gemini REST API (following official documentation)
-	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent", config.ModelName)
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+"/"+path, reader)
 	if err != nil {
-		log.Printf("REST API - Request creation error: %v", err)
-		return &types.APIResponse{
-			ResponseStatus: types.ResponseStatusError,
-			ErrorMessage:   fmt.Sprintf("Failed to create request: %v", err),
-			ResponseTimeMs: int32(time.Since(startTime).Milliseconds()),
-		}, nil
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.tokens != nil {
+		token, err := c.tokens.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get access token: %w", err)
+		}
+		token.SetAuthHeader(req)
+	} else {
+		req.Header.Set("x-goog-api-key", c.apiKey)
 	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-goog-api-key", c.apiKey)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		log.Printf("REST API - HTTP request error: %v", err)
-		return &types.APIResponse{
-			ResponseStatus: types.ResponseStatusError,
-			ErrorMessage:   fmt.Sprintf("Failed to make request: %v", err),
-			ResponseTimeMs: int32(time.Since(startTime).Milliseconds()),
-		}, nil
+		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	// Read response
-	body, err := io.ReadAll(resp.Body)
+	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Printf("REST API - Response read error: %v", err)
-		return &types.APIResponse{
-			ResponseStatus: types.ResponseStatusError,
-			ErrorMessage:   fmt.Sprintf("Failed to read response: %v", err),
-			ResponseTimeMs: int32(time.Since(startTime).Milliseconds()),
-		}, nil
-	}
-
-	responseTime := time.Since(startTime)
-	log.Printf("REST API - Response status: %d, Time: %dms", resp.StatusCode, responseTime.Milliseconds())
-
-	if resp.StatusCode != 200 {
-		log.Printf("REST API - Error response: %s", string(body))
-		return &types.APIResponse{
-			ResponseStatus: types.ResponseStatusError,
-			ErrorMessage:   fmt.Sprintf("API error %d: %s", resp.StatusCode, string(body)),
-			ResponseTimeMs: int32(responseTime.Milliseconds()),
-		}, nil
-	}
-
-	// Parse response (following official documentation format)
-	var geminiResp struct {
-		Candidates []struct {
-			Content struct {
-				Parts []struct {
-					Text string `json:"text"`
-				} `json:"parts"`
-			} `json:"content"`
-			FinishReason string `json:"finishReason"`
-		} `json:"candidates"`
-		UsageMetadata struct {
-			PromptTokenCount     int `json:"promptTokenCount"`
-			CandidatesTokenCount int `json:"candidatesTokenCount"`
-			TotalTokenCount      int `json:"totalTokenCount"`
-		} `json:"usageMetadata"`
-	}
-
-	if err := json.Unmarshal(body, &geminiResp); err != nil {
-		log.Printf("REST API - JSON parse error: %v", err)
-		return &types.APIResponse{
-			ResponseStatus: types.ResponseStatusError,
-			ErrorMessage:   fmt.Sprintf("Failed to parse response: %v", err),
-			ResponseTimeMs: int32(responseTime.Milliseconds()),
-		}, nil
-	}
-
-	// Extract response text
-	var responseText string
-	var finishReason string
-	if len(geminiResp.Candidates) > 0 {
-		candidate := geminiResp.Candidates[0]
-		if len(candidate.Content.Parts) > 0 {
-			responseText = candidate.Content.Parts[0].Text
-		}
-		finishReason = candidate.FinishReason
+		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
-
-	// Build usage metadata
-	usageMetadata := map[string]interface{}{
-		"prompt_tokens":     geminiResp.UsageMetadata.PromptTokenCount,
-		"completion_tokens": geminiResp.UsageMetadata.CandidatesTokenCount,
-		"total_tokens":      geminiResp.UsageMetadata.TotalTokenCount,
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
+	return respBody, nil
+}
 
-	log.Printf("REST API - Success! Response length: %d chars", len(responseText))
-
-	return &types.APIResponse{
-		ResponseStatus: types.ResponseStatusSuccess,
-		ResponseText:   responseText,
-		UsageMetadata:  usageMetadata,
-		FinishReason:   finishReason,
-		ResponseTimeMs: int32(responseTime.Milliseconds()),
-	}, nil
+// retryable reports whether a failed attempt may succeed if repeated: rate limits, server errors
+// and transport failures, including a timed-out attempt, but not other client errors, a done
+// context or a transport error that reports itself permanent, such as an open circuit breaker's
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var permanent interface{ Permanent() bool }
+	if errors.As(err, &permanent) && permanent.Permanent() {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+	return true
 }
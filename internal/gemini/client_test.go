@@ -0,0 +1,303 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// newTestClient returns a client that calls the test server without waiting between retries
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := NewClient("test-key")
+	client.baseURL = server.URL
+	client.retryBackoff = time.Millisecond
+	return client
+}
+
+func TestGenerateContent(t *testing.T) {
+	temperature := float32(0.2)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models/gemini-2.0-flash:generateContent" || r.Header.Get("x-goog-api-key") != "test-key" {
+			t.Errorf("unexpected request %s with key %q", r.URL.Path, r.Header.Get("x-goog-api-key"))
+		}
+
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if _, ok := body["toolConfig"]; ok {
+			t.Errorf("expected unset options to be omitted, got %v", body)
+		}
+		if body["systemInstruction"] == nil || body["generationConfig"].(map[string]interface{})["temperature"] != 0.2 {
+			t.Errorf("expected the system instruction and temperature, got %v", body)
+		}
+
+		w.Write([]byte(`{
+			"candidates": [{
+				"content": {"role": "model", "parts": [{"functionCall": {"name": "get_weather", "args": {"location": "Paris"}}}, {"text": "Sunny"}]},
+				"finishReason": "STOP"
+			}],
+			"usageMetadata": {"promptTokenCount": 12, "candidatesTokenCount": 3, "totalTokenCount": 15}
+		}`))
+	})
+
+	instruction := TextContent("Be brief.")
+	response, err := client.GenerateContent(context.Background(), "models/gemini-2.0-flash", &GenerateContentRequest{
+		Contents:          []Content{TextContent("Weather in Paris?")},
+		SystemInstruction: &instruction,
+		GenerationConfig:  &GenerationConfig{Temperature: &temperature},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Text() != "Sunny" || response.UsageMetadata.TotalTokenCount != 15 {
+		t.Errorf("expected the text and usage, got %+v", response)
+	}
+	if call := response.Candidates[0].Content.Parts[0].FunctionCall; call == nil || call.Args["location"] != "Paris" {
+		t.Errorf("expected the function call, got %+v", call)
+	}
+}
+
+func TestThinking(t *testing.T) {
+	budget := int32(1024)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		thinking, _ := body["generationConfig"].(map[string]interface{})["thinkingConfig"].(map[string]interface{})
+		if thinking["thinkingBudget"] != 1024.0 || thinking["includeThoughts"] != true {
+			t.Errorf("expected the thinking config, got %v", body["generationConfig"])
+		}
+
+		w.Write([]byte(`{
+			"candidates": [{"content": {"role": "model", "parts": [{"text": "Compare both", "thought": true}, {"text": "Paris"}]}}],
+			"usageMetadata": {"promptTokenCount": 8, "candidatesTokenCount": 1, "thoughtsTokenCount": 40, "totalTokenCount": 49}
+		}`))
+	})
+
+	response, err := client.GenerateContent(context.Background(), "gemini-2.5-flash", &GenerateContentRequest{
+		Contents:         []Content{TextContent("Capital of France?")},
+		GenerationConfig: &GenerationConfig{ThinkingConfig: &ThinkingConfig{ThinkingBudget: &budget, IncludeThoughts: true}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Text() != "Paris" || response.UsageMetadata.ThoughtsTokenCount != 40 {
+		t.Errorf("expected the answer without the thought and the thought tokens, got %+v", response)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	tests := []struct {
+		name        string
+		statuses    []int
+		expectCalls int
+		expectError int
+	}{
+		{"recovers_from_server_errors", []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK}, 3, 0},
+		{"gives_up_after_max_retries", []int{500, 500, 500, 500}, DefaultMaxRetries + 1, 500},
+		{"no_retry_on_client_error", []int{http.StatusBadRequest, http.StatusOK}, 1, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				status := tt.statuses[calls]
+				calls++
+				w.WriteHeader(status)
+				w.Write([]byte(`{"embedding": {"values": [0.1, 0.2]}}`))
+			})
+
+			values, err := client.EmbedContent(context.Background(), "text-embedding-004", "hello")
+			if calls != tt.expectCalls {
+				t.Errorf("expected %d calls, got %d", tt.expectCalls, calls)
+			}
+			if tt.expectError == 0 {
+				if err != nil || len(values) != 2 {
+					t.Errorf("expected the embedding, got %v, %v", values, err)
+				}
+				return
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.expectError {
+				t.Errorf("expected an API error with status %d, got %v", tt.expectError, err)
+			}
+		})
+	}
+}
+
+func TestRetryStopsWhenContextIsDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		cancel()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	if _, err := client.EmbedContent(ctx, "text-embedding-004", "hello"); err == nil || calls != 1 {
+		t.Errorf("expected one call and an error after cancellation, got %d calls, %v", calls, err)
+	}
+}
+
+// permanentError is a transport error that is not worth retrying
+type permanentError struct{}
+
+func (permanentError) Error() string   { return "circuit open" }
+func (permanentError) Permanent() bool { return true }
+
+type failingTransport struct{ calls int }
+
+func (t *failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	t.calls++
+	return nil, permanentError{}
+}
+
+func TestNoRetryOnPermanentError(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {})
+	transport := &failingTransport{}
+	client.SetTransport(transport)
+
+	if _, err := client.EmbedContent(context.Background(), "text-embedding-004", "hello"); !errors.As(err, new(permanentError)) || transport.calls != 1 {
+		t.Errorf("expected one call and the permanent error, got %d calls, %v", transport.calls, err)
+	}
+}
+
+func TestContextDeadline(t *testing.T) {
+	slow := func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}
+
+	// A deadline on the context ends the call, even one longer than the client's timeout
+	client := newTestClient(t, slow)
+	client.maxRetries = 0
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.EmbedContent(ctx, "text-embedding-004", "hello"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the context deadline to end the call, got %v", err)
+	}
+
+	// Without a deadline each attempt is bounded by the client's timeout
+	client = newTestClient(t, slow)
+	client.maxRetries = 0
+	client.timeout = 50 * time.Millisecond
+	if _, err := client.EmbedContent(context.Background(), "text-embedding-004", "hello"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the client's timeout to end the attempt, got %v", err)
+	}
+}
+
+func TestListModels(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models" || r.URL.Query().Get("pageSize") != "1000" || r.URL.Query().Get("pageToken") != "page 2" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`{
+			"models": [{"name": "models/gemini-2.0-flash", "outputTokenLimit": 8192, "supportedGenerationMethods": ["generateContent"]}],
+			"nextPageToken": "page-3"
+		}`))
+	})
+
+	page, err := client.ListModels(context.Background(), 1000, "page 2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Models) != 1 || page.Models[0].OutputTokenLimit != 8192 || page.NextPageToken != "page-3" {
+		t.Errorf("expected one model and the next page token, got %+v", page)
+	}
+}
+
+func TestCountTokens(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models/gemini-2.0-flash:countTokens" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		var body struct {
+			GenerateContentRequest map[string]interface{} `json:"generateContentRequest"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.GenerateContentRequest["model"] != "models/gemini-2.0-flash" || body.GenerateContentRequest["systemInstruction"] == nil {
+			t.Errorf("expected the full request with its model, got %v", body)
+		}
+		w.Write([]byte(`{"totalTokens": 42}`))
+	})
+
+	instruction := TextContent("Be brief.")
+	tokens, err := client.CountTokens(context.Background(), "gemini-2.0-flash", &GenerateContentRequest{
+		Contents:          []Content{TextContent("Weather in Paris?")},
+		SystemInstruction: &instruction,
+	})
+	if err != nil || tokens != 42 {
+		t.Errorf("expected 42 tokens, got %d (%v)", tokens, err)
+	}
+}
+
+func TestCachedContent(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/cachedContents":
+			var body CachedContent
+			json.NewDecoder(r.Body).Decode(&body)
+			if body.Model != "models/gemini-2.0-flash" || body.TTL != "600s" || len(body.Contents) != 1 {
+				t.Errorf("expected the cache entry with its model, got %+v", body)
+			}
+			w.Write([]byte(`{"name": "cachedContents/abc", "model": "models/gemini-2.0-flash", "usageMetadata": {"totalTokenCount": 5000}}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/cachedContents/abc":
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	created, err := client.CreateCachedContent(context.Background(), "gemini-2.0-flash", &CachedContent{
+		Contents: []Content{UserContent("A long shared document")},
+		TTL:      "600s",
+	})
+	if err != nil {
+		t.Fatalf("failed to create cache entry: %v", err)
+	}
+	if created.Name != "cachedContents/abc" || created.UsageMetadata.TotalTokenCount != 5000 {
+		t.Errorf("expected the created entry, got %+v", created)
+	}
+	if err := client.DeleteCachedContent(context.Background(), created.Name); err != nil {
+		t.Errorf("failed to delete cache entry: %v", err)
+	}
+}
+
+func TestVertexClient(t *testing.T) {
+	if url := VertexBaseURL("my-project", "europe-west4"); url != "https://europe-west4-aiplatform.googleapis.com/v1/projects/my-project/locations/europe-west4/publishers/google" {
+		t.Errorf("unexpected regional endpoint %s", url)
+	}
+	if url := VertexBaseURL("my-project", "global"); url != "https://aiplatform.googleapis.com/v1/projects/my-project/locations/global/publishers/google" {
+		t.Errorf("unexpected global endpoint %s", url)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models/gemini-2.0-flash-001:generateContent" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer vertex-token" || r.Header.Get("x-goog-api-key") != "" {
+			t.Errorf("expected a bearer token and no API key, got %v", r.Header)
+		}
+		w.Write([]byte(`{"candidates": [{"content": {"role": "model", "parts": [{"text": "Hi"}]}}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := newVertexClient("my-project", "us-central1", oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "vertex-token"}))
+	client.baseURL = server.URL
+	response, err := client.GenerateContent(context.Background(), "publishers/google/models/gemini-2.0-flash-001", &GenerateContentRequest{
+		Contents: []Content{UserContent("Hello")},
+	})
+	if err != nil || response.Text() != "Hi" {
+		t.Errorf("expected the response text, got %+v (%v)", response, err)
+	}
+}
@@ -0,0 +1,204 @@
+package gemini
+
+// Part is one piece of a content turn: text, a function call, or a function result
+type Part struct {
+	Text             string            `json:"text,omitempty"`
+	FunctionCall     *FunctionCall     `json:"functionCall,omitempty"`
+	FunctionResponse *FunctionResponse `json:"functionResponse,omitempty"`
+	Thought          bool              `json:"thought,omitempty"` // Text is a summary of the model's reasoning, not its answer
+}
+
+// FunctionCall is a function the model asked to call
+type FunctionCall struct {
+	Name string                 `json:"name"`
+	Args map[string]interface{} `json:"args,omitempty"`
+}
+
+// FunctionResponse is the result of a function call sent back to the model
+type FunctionResponse struct {
+	Name     string                 `json:"name"`
+	Response map[string]interface{} `json:"response"`
+}
+
+// Content is a turn of a conversation, or a system instruction
+type Content struct {
+	Role  string `json:"role,omitempty"`
+	Parts []Part `json:"parts"`
+}
+
+// TextContent builds a content turn holding a single text part
+func TextContent(text string) Content {
+	return Content{Parts: []Part{{Text: text}}}
+}
+
+// UserContent builds a user turn holding a single text part; Vertex AI requires the role
+func UserContent(text string) Content {
+	return Content{Role: "user", Parts: []Part{{Text: text}}}
+}
+
+// GenerationConfig holds the sampling and response format options of a request
+type GenerationConfig struct {
+	Temperature      *float32               `json:"temperature,omitempty"`
+	MaxOutputTokens  *int32                 `json:"maxOutputTokens,omitempty"`
+	TopP             *float32               `json:"topP,omitempty"`
+	TopK             *int32                 `json:"topK,omitempty"`
+	Seed             *int32                 `json:"seed,omitempty"`
+	CandidateCount   *int32                 `json:"candidateCount,omitempty"`
+	ResponseMimeType string                 `json:"responseMimeType,omitempty"`
+	ResponseSchema   map[string]interface{} `json:"responseSchema,omitempty"`
+	ThinkingConfig   *ThinkingConfig        `json:"thinkingConfig,omitempty"`
+}
+
+// ThinkingConfig sets how many tokens a thinking model may spend reasoning before it answers
+type ThinkingConfig struct {
+	ThinkingBudget  *int32 `json:"thinkingBudget,omitempty"` // -1 lets the model decide, 0 turns thinking off
+	IncludeThoughts bool   `json:"includeThoughts,omitempty"`
+}
+
+// IsEmpty reports whether no option is set, so the config can be left out of a request
+func (g *GenerationConfig) IsEmpty() bool {
+	return g.Temperature == nil && g.MaxOutputTokens == nil && g.TopP == nil && g.TopK == nil &&
+		g.Seed == nil && g.CandidateCount == nil && g.ResponseMimeType == "" && len(g.ResponseSchema) == 0 && g.ThinkingConfig == nil
+}
+
+// SafetySetting is the blocking threshold for one harm category
+type SafetySetting struct {
+	Category  string `json:"category"`
+	Threshold string `json:"threshold"`
+}
+
+// FunctionDeclaration describes a function the model may call
+type FunctionDeclaration struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+}
+
+// Tool groups function declarations
+type Tool struct {
+	FunctionDeclarations []FunctionDeclaration `json:"functionDeclarations"`
+}
+
+// FunctionCallingConfig controls when the model calls functions: AUTO, ANY or NONE. In ANY mode
+// AllowedFunctionNames limits which functions it may call.
+type FunctionCallingConfig struct {
+	Mode                 string   `json:"mode"`
+	AllowedFunctionNames []string `json:"allowedFunctionNames,omitempty"`
+}
+
+// ToolConfig configures how the model uses the request's tools
+type ToolConfig struct {
+	FunctionCallingConfig FunctionCallingConfig `json:"functionCallingConfig"`
+}
+
+// GenerateContentRequest is the body of a generateContent call
+type GenerateContentRequest struct {
+	Contents          []Content         `json:"contents"`
+	SystemInstruction *Content          `json:"systemInstruction,omitempty"`
+	GenerationConfig  *GenerationConfig `json:"generationConfig,omitempty"`
+	SafetySettings    []SafetySetting   `json:"safetySettings,omitempty"`
+	Tools             []Tool            `json:"tools,omitempty"`
+	ToolConfig        *ToolConfig       `json:"toolConfig,omitempty"`
+	// CachedContent names a cache entry whose contents come before Contents. The system instruction,
+	// tools and tool config then come from the entry and must be left unset.
+	CachedContent string `json:"cachedContent,omitempty"`
+}
+
+// CachedContent is a cache entry of contents shared by many requests, billed at a lower rate than
+// sending them with each request
+type CachedContent struct {
+	Name              string      `json:"name,omitempty"` // Set by the API, e.g. "cachedContents/abc123"
+	Model             string      `json:"model"`
+	Contents          []Content   `json:"contents,omitempty"`
+	SystemInstruction *Content    `json:"systemInstruction,omitempty"`
+	Tools             []Tool      `json:"tools,omitempty"`
+	ToolConfig        *ToolConfig `json:"toolConfig,omitempty"`
+	TTL               string      `json:"ttl,omitempty"` // Lifetime such as "3600s"
+	ExpireTime        string      `json:"expireTime,omitempty"`
+	UsageMetadata     struct {
+		TotalTokenCount int `json:"totalTokenCount"`
+	} `json:"usageMetadata"`
+}
+
+// Candidate is one generated response
+type Candidate struct {
+	Content      Content `json:"content"`
+	FinishReason string  `json:"finishReason"`
+	Index        int     `json:"index,omitempty"`
+}
+
+// UsageMetadata reports the tokens a call consumed
+type UsageMetadata struct {
+	PromptTokenCount     int `json:"promptTokenCount"`
+	CandidatesTokenCount int `json:"candidatesTokenCount"`
+	TotalTokenCount      int `json:"totalTokenCount"`
+	// Prompt tokens read from the request's cache entry, included in PromptTokenCount
+	CachedContentTokenCount int `json:"cachedContentTokenCount,omitempty"`
+	// Tokens spent reasoning, billed as output but not included in CandidatesTokenCount
+	ThoughtsTokenCount int `json:"thoughtsTokenCount,omitempty"`
+}
+
+// GenerateContentResponse is the body returned by a generateContent call
+type GenerateContentResponse struct {
+	Candidates    []Candidate   `json:"candidates"`
+	UsageMetadata UsageMetadata `json:"usageMetadata"`
+}
+
+// Text returns the first candidate's first text part that is not a thought, or "" when there is none
+func (r *GenerateContentResponse) Text() string {
+	if len(r.Candidates) == 0 {
+		return ""
+	}
+	for _, part := range r.Candidates[0].Content.Parts {
+		if part.Text != "" && !part.Thought {
+			return part.Text
+		}
+	}
+	return ""
+}
+
+// countTokensRequest is the body of a countTokens call
+type countTokensRequest struct {
+	GenerateContentRequest *modelRequest `json:"generateContentRequest"`
+}
+
+// modelRequest is a generateContent request naming its model, as countTokens expects
+type modelRequest struct {
+	Model string `json:"model"`
+	*GenerateContentRequest
+}
+
+// countTokensResponse is the body returned by a countTokens call
+type countTokensResponse struct {
+	TotalTokens int `json:"totalTokens"`
+}
+
+// embedContentRequest is the body of an embedContent call
+type embedContentRequest struct {
+	Model   string  `json:"model"`
+	Content Content `json:"content"`
+}
+
+// embedContentResponse is the body returned by an embedContent call
+type embedContentResponse struct {
+	Embedding struct {
+		Values []float32 `json:"values"`
+	} `json:"embedding"`
+}
+
+// Model describes a model listed by the models endpoint
+type Model struct {
+	Name                       string   `json:"name"`
+	Version                    string   `json:"version"`
+	DisplayName                string   `json:"displayName"`
+	Description                string   `json:"description"`
+	InputTokenLimit            int32    `json:"inputTokenLimit"`
+	OutputTokenLimit           int32    `json:"outputTokenLimit"`
+	SupportedGenerationMethods []string `json:"supportedGenerationMethods"`
+}
+
+// ListModelsResponse is one page of the models endpoint
+type ListModelsResponse struct {
+	Models        []Model `json:"models"`
+	NextPageToken string  `json:"nextPageToken"`
+}
@@ -0,0 +1,50 @@
+package gemini
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	// VertexScope is the OAuth scope Vertex AI calls are authorized with
+	VertexScope = "https://www.googleapis.com/auth/cloud-platform"
+	// DefaultVertexRegion is the Vertex AI region used when none is configured
+	DefaultVertexRegion = "us-central1"
+)
+
+// VertexBaseURL returns the root of Google's publisher models for a project in a Vertex AI region;
+// the global region uses the non-regional endpoint
+func VertexBaseURL(projectID, region string) string {
+	host := region + "-aiplatform.googleapis.com"
+	if region == "global" {
+		host = "aiplatform.googleapis.com"
+	}
+	return fmt.Sprintf("https://%s/v1/projects/%s/locations/%s/publishers/google", host, projectID, region)
+}
+
+// NewVertexClient creates a client that calls Gemini models through Vertex AI, authorized with
+// Application Default Credentials. Only GenerateContent is served by Vertex.
+func NewVertexClient(ctx context.Context, projectID, region string) (*Client, error) {
+	if projectID == "" {
+		return nil, fmt.Errorf("a Google Cloud project ID is required for Vertex AI")
+	}
+	if region == "" {
+		region = DefaultVertexRegion
+	}
+	tokens, err := google.DefaultTokenSource(ctx, VertexScope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find application default credentials: %w", err)
+	}
+	return newVertexClient(projectID, region, tokens), nil
+}
+
+// newVertexClient creates a Vertex AI client that authorizes calls with tokens
+func newVertexClient(projectID, region string, tokens oauth2.TokenSource) *Client {
+	client := NewClient("")
+	client.baseURL = VertexBaseURL(projectID, region)
+	client.tokens = oauth2.ReuseTokenSource(nil, tokens)
+	return client
+}
@@ -0,0 +1,322 @@
+package gogent
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"gogent/internal/types"
+)
+
+// archiveTimeFormat writes timestamps in a form MySQL accepts back on restore
+const archiveTimeFormat = "2006-01-02 15:04:05.999999"
+
+// archiveRunColumn is the alias archive queries give the ID of the run a row belongs to
+const archiveRunColumn = "archive_run_id"
+
+// archiveLine is one row of an archive: the table it came from and its columns
+type archiveLine struct {
+	Table string                 `json:"table"`
+	Row   map[string]interface{} `json:"row"`
+}
+
+// archiveQuery selects rows of a table by the IDs of the rows being pruned, along with their run ID
+type archiveQuery struct {
+	table string
+	query string // %s is replaced by the placeholders for the IDs
+}
+
+// runArchiveQueries lists, by pruned table, the rows that go with a batch: the rows themselves and
+// the rows their deletion cascades to. Parents come before children.
+var runArchiveQueries = map[string][]archiveQuery{
+	"function_calls": {
+		{"function_calls", "SELECT r.execution_run_id AS archive_run_id, fc.* FROM function_calls fc JOIN api_requests r ON r.id = fc.request_id WHERE fc.id IN (%s)"},
+	},
+	"execution_logs": {
+		{"execution_logs", "SELECT execution_run_id AS archive_run_id, execution_logs.* FROM execution_logs WHERE id IN (%s)"},
+	},
+	"api_responses": {
+		{"api_responses", "SELECT r.execution_run_id AS archive_run_id, resp.* FROM api_responses resp JOIN api_requests r ON r.id = resp.request_id WHERE resp.id IN (%s)"},
+	},
+	"api_requests": {
+		{"api_requests", "SELECT execution_run_id AS archive_run_id, api_requests.* FROM api_requests WHERE id IN (%s)"},
+		{"api_responses", "SELECT r.execution_run_id AS archive_run_id, resp.* FROM api_responses resp JOIN api_requests r ON r.id = resp.request_id WHERE resp.request_id IN (%s)"},
+		{"function_calls", "SELECT r.execution_run_id AS archive_run_id, fc.* FROM function_calls fc JOIN api_requests r ON r.id = fc.request_id WHERE fc.request_id IN (%s)"},
+		{"execution_logs", "SELECT execution_run_id AS archive_run_id, execution_logs.* FROM execution_logs WHERE request_id IN (%s)"},
+	},
+	"execution_runs": {
+		{"execution_runs", "SELECT id AS archive_run_id, execution_runs.* FROM execution_runs WHERE id IN (%s)"},
+		{"api_configurations", "SELECT execution_run_id AS archive_run_id, api_configurations.* FROM api_configurations WHERE execution_run_id IN (%s)"},
+		{"execution_function_configs", "SELECT execution_run_id AS archive_run_id, execution_function_configs.* FROM execution_function_configs WHERE execution_run_id IN (%s)"},
+		{"api_requests", "SELECT execution_run_id AS archive_run_id, api_requests.* FROM api_requests WHERE execution_run_id IN (%s)"},
+		{"api_responses", "SELECT r.execution_run_id AS archive_run_id, resp.* FROM api_responses resp JOIN api_requests r ON r.id = resp.request_id WHERE r.execution_run_id IN (%s)"},
+		{"function_calls", "SELECT r.execution_run_id AS archive_run_id, fc.* FROM function_calls fc JOIN api_requests r ON r.id = fc.request_id WHERE r.execution_run_id IN (%s)"},
+		{"execution_logs", "SELECT execution_run_id AS archive_run_id, execution_logs.* FROM execution_logs WHERE execution_run_id IN (%s)"},
+		{"comparison_results", "SELECT execution_run_id AS archive_run_id, comparison_results.* FROM comparison_results WHERE execution_run_id IN (%s)"},
+		{"execution_run_slo_results", "SELECT execution_run_id AS archive_run_id, execution_run_slo_results.* FROM execution_run_slo_results WHERE execution_run_id IN (%s)"},
+		{"evaluation_results", "SELECT execution_run_id AS archive_run_id, evaluation_results.* FROM evaluation_results WHERE execution_run_id IN (%s)"},
+		{"response_embeddings", "SELECT execution_run_id AS archive_run_id, response_embeddings.* FROM response_embeddings WHERE execution_run_id IN (%s)"},
+	},
+}
+
+// restoreOrder is the order archived tables are restored in, parents before children
+var restoreOrder = []string{
+	"execution_runs", "api_configurations", "execution_function_configs", "api_requests", "api_responses",
+	"function_calls", "execution_logs", "comparison_results", "execution_run_slo_results",
+	"evaluation_results", "response_embeddings",
+}
+
+// restoreKeys names the primary key columns of tables whose key is not id
+var restoreKeys = map[string][]string{
+	"execution_run_slo_results": {"execution_run_id"},
+	"response_embeddings":       {"response_id", "model"},
+}
+
+// archiveIdentifier matches the run IDs and column names archives may use
+var archiveIdentifier = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// archiveRuns writes the rows of a batch about to be pruned to the sink, one gzipped JSON lines
+// object per run named <run ID>/<unix nanoseconds>-<table>.jsonl.gz
+func (c *Client) archiveRuns(ctx context.Context, sink ArchiveSink, table string, ids []interface{}) (int64, error) {
+	byRun := make(map[string][]archiveLine)
+	var runIDs []string
+	for _, q := range runArchiveQueries[table] {
+		rows, err := c.loadArchiveRows(ctx, q.table, fmt.Sprintf(q.query, placeholders(len(ids))), ids)
+		if err != nil {
+			return 0, err
+		}
+		for _, row := range rows {
+			runID, _ := row[archiveRunColumn].(string)
+			delete(row, archiveRunColumn)
+			if _, ok := byRun[runID]; !ok {
+				runIDs = append(runIDs, runID)
+			}
+			byRun[runID] = append(byRun[runID], archiveLine{Table: q.table, Row: row})
+		}
+	}
+
+	var archived int64
+	for _, runID := range runIDs {
+		data, err := encodeArchive(byRun[runID])
+		if err != nil {
+			return archived, err
+		}
+		key := fmt.Sprintf("%s/%d-%s.jsonl.gz", runID, time.Now().UnixNano(), table)
+		if err := sink.Put(ctx, key, data); err != nil {
+			return archived, err
+		}
+		archived += int64(len(byRun[runID]))
+	}
+	return archived, nil
+}
+
+// loadArchiveRows reads rows as column maps, with byte values as strings and times in archiveTimeFormat
+func (c *Client) loadArchiveRows(ctx context.Context, table, query string, args []interface{}) ([]map[string]interface{}, error) {
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s to archive: %w", table, err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s to archive: %w", table, err)
+	}
+
+	var loaded []map[string]interface{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, fmt.Errorf("failed to scan %s to archive: %w", table, err)
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			switch value := values[i].(type) {
+			case []byte:
+				row[column] = string(value)
+			case time.Time:
+				row[column] = value.UTC().Format(archiveTimeFormat)
+			default:
+				row[column] = value
+			}
+		}
+		loaded = append(loaded, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s to archive: %w", table, err)
+	}
+	return loaded, nil
+}
+
+func encodeArchive(lines []archiveLine) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	encoder := json.NewEncoder(writer)
+	for _, line := range lines {
+		if err := encoder.Encode(line); err != nil {
+			return nil, fmt.Errorf("failed to encode archive: %w", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress archive: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// decodeArchive reads the lines of an archive object, with whole numbers as int64
+func decodeArchive(data []byte) ([]archiveLine, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress archive: %w", err)
+	}
+	defer reader.Close()
+
+	var lines []archiveLine
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		decoder := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+		decoder.UseNumber()
+		var line archiveLine
+		if err := decoder.Decode(&line); err != nil {
+			return nil, fmt.Errorf("failed to decode archive: %w", err)
+		}
+		for column, value := range line.Row {
+			if number, ok := value.(json.Number); ok {
+				if integer, err := number.Int64(); err == nil {
+					line.Row[column] = integer
+				} else {
+					line.Row[column], _ = number.Float64()
+				}
+			}
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	return lines, nil
+}
+
+// RestoreArchivedRun re-imports everything archived for a run. Rows still in the database are
+// skipped, so a run whose logs were pruned before the run itself can be restored in full.
+func (c *Client) RestoreArchivedRun(ctx context.Context, sink ArchiveSink, runID string) (*types.RestoreReport, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	if !archiveIdentifier.MatchString(runID) {
+		return nil, fmt.Errorf("invalid run ID %q", runID)
+	}
+
+	keys, err := sink.List(ctx, runID+"/")
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("run %s: %w", runID, ErrArchiveNotFound)
+	}
+
+	byTable := make(map[string][]map[string]interface{})
+	for _, key := range keys {
+		data, err := sink.Get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		lines, err := decodeArchive(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		for _, line := range lines {
+			if !isRestoreTable(line.Table) {
+				return nil, fmt.Errorf("%s: unexpected table %q in archive", key, line.Table)
+			}
+			byTable[line.Table] = append(byTable[line.Table], line.Row)
+		}
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin restore: %w", err)
+	}
+	defer tx.Rollback()
+
+	report := &types.RestoreReport{RunID: runID, Restored: make(map[string]int64)}
+	for _, table := range restoreOrder {
+		for _, row := range byTable[table] {
+			restored, err := restoreRow(ctx, tx, table, row)
+			if err != nil {
+				return nil, err
+			}
+			if restored {
+				report.Restored[table]++
+			} else {
+				report.Skipped++
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit restore: %w", err)
+	}
+	return report, nil
+}
+
+func isRestoreTable(table string) bool {
+	for _, name := range restoreOrder {
+		if name == table {
+			return true
+		}
+	}
+	return false
+}
+
+// restoreRow inserts an archived row unless a row with its primary key exists
+func restoreRow(ctx context.Context, tx *sql.Tx, table string, row map[string]interface{}) (bool, error) {
+	keyColumns, ok := restoreKeys[table]
+	if !ok {
+		keyColumns = []string{"id"}
+	}
+	conditions := make([]string, len(keyColumns))
+	keyArgs := make([]interface{}, len(keyColumns))
+	for i, column := range keyColumns {
+		conditions[i] = column + " = ?"
+		keyArgs[i] = row[column]
+	}
+
+	var count int
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+table+" WHERE "+strings.Join(conditions, " AND "), keyArgs...).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check %s before restoring: %w", table, err)
+	}
+	if count > 0 {
+		return false, nil
+	}
+
+	columns := make([]string, 0, len(row))
+	args := make([]interface{}, 0, len(row))
+	for column, value := range row {
+		if !archiveIdentifier.MatchString(column) {
+			return false, fmt.Errorf("invalid column %q in archived %s", column, table)
+		}
+		columns = append(columns, column)
+		args = append(args, value)
+	}
+	query := "INSERT INTO " + table + " (" + strings.Join(columns, ", ") + ") VALUES (" + placeholders(len(columns)) + ")"
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return false, fmt.Errorf("failed to restore %s: %w", table, err)
+	}
+	return true, nil
+}
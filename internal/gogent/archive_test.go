@@ -0,0 +1,167 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPruneToSinkAndRestore(t *testing.T) {
+	client := newRetentionTestClient(t)
+	now := time.Now()
+	insertTestHistory(t, client, "old", "user-1", now.AddDate(0, 0, -100))
+	insertTestHistory(t, client, "recent", "user-1", now)
+	for _, statement := range []string{
+		"INSERT INTO api_configurations (id, execution_run_id, variation_name) VALUES ('old-config', 'old', 'baseline')",
+		"INSERT INTO evaluation_results (id, execution_run_id, score) VALUES ('old-eval', 'old', 0.75)",
+		"INSERT INTO response_embeddings (response_id, model, execution_run_id) VALUES ('old-response', 'embed', 'old')",
+	} {
+		if _, err := client.db.Exec(statement); err != nil {
+			t.Fatalf("failed to insert run rows: %v", err)
+		}
+	}
+
+	sink := &LocalArchiveSink{Dir: t.TempDir()}
+	report, err := client.PruneOlderThan(context.Background(), now.AddDate(0, 0, -90), "", PruneOptions{Sink: sink})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Archived != 8 || report.Deleted["execution_runs"] != 1 {
+		t.Fatalf("expected the old run's 8 rows to be archived and the run pruned, got %+v", report)
+	}
+
+	keys, err := sink.List(context.Background(), "old/")
+	if err != nil || len(keys) != 5 {
+		t.Fatalf("expected one archive object per pruned table, got %v (%v)", keys, err)
+	}
+	if keys, _ := sink.List(context.Background(), "recent/"); len(keys) != 0 {
+		t.Errorf("expected the recent run not to be archived, got %v", keys)
+	}
+
+	// The configuration is deleted by the run's cascade in MySQL; here it is removed by hand
+	if _, err := client.db.Exec("DELETE FROM api_configurations; DELETE FROM evaluation_results; DELETE FROM response_embeddings"); err != nil {
+		t.Fatalf("failed to clear run rows: %v", err)
+	}
+
+	restored, err := client.RestoreArchivedRun(context.Background(), sink, "old")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, table := range []string{"execution_runs", "api_requests", "api_responses", "execution_logs", "function_calls",
+		"api_configurations", "evaluation_results", "response_embeddings"} {
+		if restored.Restored[table] != 1 {
+			t.Errorf("expected 1 row restored to %s, got %d", table, restored.Restored[table])
+		}
+	}
+	if countRows(t, client, "execution_runs") != 2 {
+		t.Error("expected the old run to be back")
+	}
+	var score float64
+	var createdAt time.Time
+	if err := client.db.QueryRow("SELECT score FROM evaluation_results WHERE id = 'old-eval'").Scan(&score); err != nil || score != 0.75 {
+		t.Errorf("expected the evaluation score to survive the round trip, got %v (%v)", score, err)
+	}
+	if err := client.db.QueryRow("SELECT created_at FROM execution_runs WHERE id = 'old'").Scan(&createdAt); err != nil ||
+		createdAt.Sub(now.AddDate(0, 0, -100)).Abs() > time.Second {
+		t.Errorf("expected the run's creation time to survive the round trip, got %v (%v)", createdAt, err)
+	}
+
+	again, err := client.RestoreArchivedRun(context.Background(), sink, "old")
+	if err != nil || again.Skipped != 8 || len(again.Restored) != 0 {
+		t.Errorf("expected a second restore to skip every row, got %+v (%v)", again, err)
+	}
+
+	if _, err := client.RestoreArchivedRun(context.Background(), sink, "missing"); !errors.Is(err, ErrArchiveNotFound) {
+		t.Errorf("expected ErrArchiveNotFound for a run never archived, got %v", err)
+	}
+	if _, err := client.RestoreArchivedRun(context.Background(), sink, "../old"); err == nil {
+		t.Error("expected a run ID with path separators to be rejected")
+	}
+}
+
+// fakeS3 stores objects in memory and answers path-style PUT, GET and ListObjectsV2 requests
+type fakeS3 struct {
+	mutex   sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key-id/") || r.Header.Get("X-Amz-Date") == "" {
+		http.Error(w, "unsigned request", http.StatusForbidden)
+		return
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	key := strings.TrimPrefix(r.URL.Path, "/bucket/")
+	switch {
+	case r.Method == http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[key] = body
+	case r.URL.Query().Get("list-type") == "2":
+		var keys []string
+		for name := range f.objects {
+			if strings.HasPrefix(name, r.URL.Query().Get("prefix")) {
+				keys = append(keys, name)
+			}
+		}
+		sort.Strings(keys)
+		fmt.Fprint(w, "<ListBucketResult>")
+		for _, name := range keys {
+			fmt.Fprintf(w, "<Contents><Key>%s</Key></Contents>", name)
+		}
+		fmt.Fprint(w, "<IsTruncated>false</IsTruncated></ListBucketResult>")
+	default:
+		data, ok := f.objects[key]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	}
+}
+
+func TestS3ArchiveSink(t *testing.T) {
+	server := httptest.NewServer(&fakeS3{objects: make(map[string][]byte)})
+	defer server.Close()
+	sink := &S3ArchiveSink{Endpoint: server.URL, Region: "us-east-1", Bucket: "bucket", Prefix: "gogent/",
+		AccessKeyID: "key-id", SecretAccessKey: "secret"}
+	ctx := context.Background()
+
+	for _, key := range []string{"run-1/2-api_requests.jsonl.gz", "run-1/1-execution_logs.jsonl.gz", "run-2/1-execution_runs.jsonl.gz"} {
+		if err := sink.Put(ctx, key, []byte(key)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	keys, err := sink.List(ctx, "run-1/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(keys, ",") != "run-1/1-execution_logs.jsonl.gz,run-1/2-api_requests.jsonl.gz" {
+		t.Errorf("expected run-1's keys without the sink prefix, got %v", keys)
+	}
+	if data, err := sink.Get(ctx, keys[0]); err != nil || string(data) != keys[0] {
+		t.Errorf("expected the stored object, got %q (%v)", data, err)
+	}
+	if _, err := sink.Get(ctx, "run-3/1-execution_runs.jsonl.gz"); !errors.Is(err, ErrArchiveNotFound) {
+		t.Errorf("expected ErrArchiveNotFound for a missing object, got %v", err)
+	}
+}
+
+func TestAWSURIEncode(t *testing.T) {
+	if got := awsURIEncode("/bucket/run 1/a+b~c.jsonl.gz", true); got != "/bucket/run%201/a%2Bb~c.jsonl.gz" {
+		t.Errorf("unexpected path encoding %q", got)
+	}
+	if got := awsURIEncode("runs/", false); got != "runs%2F" {
+		t.Errorf("unexpected query encoding %q", got)
+	}
+}
@@ -0,0 +1,113 @@
+package gogent
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"gogent/internal/types"
+
+	"github.com/google/uuid"
+)
+
+// defaultAuditLogPageSize is how many audit events a query returns when it sets no limit
+const defaultAuditLogPageSize = 100
+
+// RecordAuditEvent appends an event to the audit log, setting its ID and time
+func (c *Client) RecordAuditEvent(ctx context.Context, event *types.AuditEvent) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	event.ID = uuid.New().String()
+	event.CreatedAt = time.Now().UTC()
+
+	var summary interface{}
+	if len(event.Summary) > 0 {
+		encoded, err := json.Marshal(event.Summary)
+		if err != nil {
+			return fmt.Errorf("failed to encode audit summary: %w", err)
+		}
+		summary = string(encoded)
+	}
+	_, err := c.db.ExecContext(ctx, `
+		INSERT INTO audit_logs (id, actor_id, action, target_type, target_id, ip_address, summary, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		event.ID, nullableString(event.ActorID), event.Action, nullableString(event.TargetType),
+		nullableString(event.TargetID), nullableString(event.IPAddress), summary, event.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record audit event: %w", err)
+	}
+	return nil
+}
+
+// ListAuditEvents returns the audit events matching query, newest first, and how many match in all
+func (c *Client) ListAuditEvents(ctx context.Context, query types.AuditLogQuery) ([]*types.AuditEvent, int64, error) {
+	if c.db == nil {
+		return nil, 0, ErrNoDatabase
+	}
+	limit, offset, err := ResolvePage(query.Limit, query.Offset, "", defaultAuditLogPageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var conditions []string
+	var args []interface{}
+	if query.ActorID != "" {
+		conditions = append(conditions, "actor_id = ?")
+		args = append(args, query.ActorID)
+	}
+	if query.Action != "" {
+		conditions = append(conditions, "action = ?")
+		args = append(args, query.Action)
+	}
+	if !query.Since.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, query.Since)
+	}
+	if !query.Until.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, query.Until)
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	if err := c.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM audit_logs"+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit events: %w", err)
+	}
+
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT id, actor_id, action, target_type, target_id, ip_address, summary, created_at
+		FROM audit_logs`+where+`
+		ORDER BY created_at DESC, id ASC
+		LIMIT ? OFFSET ?`, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list audit events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*types.AuditEvent{}
+	for rows.Next() {
+		var event types.AuditEvent
+		var actorID, targetType, targetID, ipAddress, summary sql.NullString
+		if err := rows.Scan(&event.ID, &actorID, &event.Action, &targetType, &targetID, &ipAddress, &summary, &event.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan audit event: %w", err)
+		}
+		event.ActorID, event.TargetType, event.TargetID, event.IPAddress = actorID.String, targetType.String, targetID.String, ipAddress.String
+		if summary.Valid && summary.String != "" {
+			if err := json.Unmarshal([]byte(summary.String), &event.Summary); err != nil {
+				return nil, 0, fmt.Errorf("failed to decode audit summary of %s: %w", event.ID, err)
+			}
+		}
+		events = append(events, &event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate audit events: %w", err)
+	}
+	return events, total, nil
+}
@@ -0,0 +1,74 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gogent/internal/testdb"
+	"gogent/internal/types"
+)
+
+// newAuditTestClient returns a client whose database has the audit log and the runs it may delete
+func newAuditTestClient(t *testing.T) *Client {
+	return &Client{db: testdb.Open(t)}
+}
+
+func TestAuditLog(t *testing.T) {
+	client := newAuditTestClient(t)
+	ctx := context.Background()
+
+	for _, event := range []*types.AuditEvent{
+		{Action: types.AuditLoginFailed, IPAddress: "203.0.113.7", Summary: map[string]interface{}{"username": "ada"}},
+		{ActorID: "user-1", Action: types.AuditLogin, IPAddress: "203.0.113.7"},
+		{ActorID: "user-1", Action: types.AuditFunctionDeleted, TargetType: "function", TargetID: "function-1"},
+		{ActorID: "user-2", Action: types.AuditLogin},
+	} {
+		if err := client.RecordAuditEvent(ctx, event); err != nil {
+			t.Fatalf("failed to record %s: %v", event.Action, err)
+		}
+		if event.ID == "" || event.CreatedAt.IsZero() {
+			t.Fatalf("expected the event's ID and time to be set, got %+v", event)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	events, total, err := client.ListAuditEvents(ctx, types.AuditLogQuery{})
+	if err != nil {
+		t.Fatalf("failed to list audit events: %v", err)
+	}
+	if total != 4 || len(events) != 4 || events[0].ActorID != "user-2" || events[3].Action != types.AuditLoginFailed {
+		t.Fatalf("expected every event, newest first, got %d: %+v", total, events)
+	}
+	if failed := events[3]; failed.ActorID != "" || failed.IPAddress != "203.0.113.7" || failed.Summary["username"] != "ada" {
+		t.Errorf("expected the failed login's summary and address, got %+v", failed)
+	}
+
+	events, total, err = client.ListAuditEvents(ctx, types.AuditLogQuery{ActorID: "user-1", Limit: 1})
+	if err != nil || total != 2 || len(events) != 1 || events[0].TargetID != "function-1" {
+		t.Errorf("expected one page of user-1's events, got %d: %+v, %v", total, events, err)
+	}
+	events, total, err = client.ListAuditEvents(ctx, types.AuditLogQuery{Action: types.AuditLogin, Until: events[0].CreatedAt})
+	if err != nil || total != 1 || events[0].ActorID != "user-1" {
+		t.Errorf("expected user-1's login, got %d: %+v, %v", total, events, err)
+	}
+}
+
+func TestDeleteExecutionRun(t *testing.T) {
+	client := newAuditTestClient(t)
+	ctx := context.Background()
+	if _, err := client.db.Exec(`INSERT INTO execution_runs (id, user_id) VALUES ('run-1', 'user-1')`); err != nil {
+		t.Fatalf("failed to insert run: %v", err)
+	}
+
+	if err := client.DeleteExecutionRun(ctx, "user-2", "run-1"); !errors.Is(err, ErrExecutionRunNotFound) {
+		t.Errorf("expected another user's run not to be found, got %v", err)
+	}
+	if err := client.DeleteExecutionRun(ctx, "user-1", "run-1"); err != nil {
+		t.Fatalf("failed to delete run: %v", err)
+	}
+	if err := client.DeleteExecutionRun(ctx, "user-1", "run-1"); !errors.Is(err, ErrExecutionRunNotFound) {
+		t.Errorf("expected the deleted run not to be found, got %v", err)
+	}
+}
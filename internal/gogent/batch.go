@@ -0,0 +1,245 @@
+package gogent
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gogent/internal/types"
+
+	"github.com/google/uuid"
+)
+
+// ErrBatchNotFound is returned when a batch run does not exist or is not owned by the user
+var ErrBatchNotFound = errors.New("batch run not found")
+
+// CreateBatchRun stores a new batch run in the submitting state. Session API keys are never persisted.
+func (c *Client) CreateBatchRun(ctx context.Context, userID, name string, template *types.MultiExecutionRequest) (*types.BatchRun, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	stored := *template
+	stored.SessionApiKeys = nil
+	stored.BasePrompt = ""
+
+	templateJSON, err := types.ToJSON(&stored)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode batch template: %w", err)
+	}
+
+	now := time.Now()
+	batch := &types.BatchRun{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Name:      name,
+		Template:  &stored,
+		Status:    types.BatchStatusSubmitting,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	_, err = c.db.ExecContext(ctx, `
+		INSERT INTO batch_runs (id, user_id, name, template, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, batch.ID, userID, name, templateJSON, batch.Status, now, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create batch run: %w", err)
+	}
+	return batch, nil
+}
+
+// AppendBatchItems stores a chunk of items in one transaction, numbering them from startIndex
+func (c *Client) AppendBatchItems(ctx context.Context, batchID string, startIndex int32, items []types.BatchItem) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, len(items))
+	args := make([]interface{}, 0, len(items)*9)
+	for i, item := range items {
+		metadata, err := types.ToJSON(item.Metadata)
+		if err != nil {
+			return fmt.Errorf("failed to encode metadata for item %d: %w", startIndex+int32(i), err)
+		}
+		expectedAnswer := ""
+		if item.ExpectedAnswer != nil {
+			if expectedAnswer, err = types.ToJSON(item.ExpectedAnswer); err != nil {
+				return fmt.Errorf("failed to encode expected answer for item %d: %w", startIndex+int32(i), err)
+			}
+		}
+		placeholders[i] = "(?, ?, ?, ?, ?, ?, ?, ?, ?)"
+		args = append(args, uuid.New().String(), batchID, startIndex+int32(i), nullableString(item.ExternalID),
+			item.Prompt, nullableString(item.Context), metadata, nullableString(expectedAnswer), types.BatchItemStatusPending)
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin batch item transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO batch_items (id, batch_run_id, item_index, external_id, prompt, context, metadata, expected_answer, status)
+		VALUES `+strings.Join(placeholders, ", "), args...)
+	if err != nil {
+		return fmt.Errorf("failed to store batch items: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE batch_runs SET total_items = total_items + ?, updated_at = ? WHERE id = ?",
+		len(items), time.Now(), batchID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update batch item count: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit batch items: %w", err)
+	}
+	return nil
+}
+
+// UpdateBatchRunStatus sets a batch run's status and error message
+func (c *Client) UpdateBatchRunStatus(ctx context.Context, batchID, status, errorMessage string) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	_, err := c.db.ExecContext(ctx,
+		"UPDATE batch_runs SET status = ?, error_message = ?, updated_at = ? WHERE id = ?",
+		status, nullableString(errorMessage), time.Now(), batchID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update batch run status: %w", err)
+	}
+	return nil
+}
+
+// GetBatchRun loads a user's batch run with its item progress
+func (c *Client) GetBatchRun(ctx context.Context, userID, batchID string) (*types.BatchRun, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	var batch types.BatchRun
+	var templateJSON string
+	var errorMessage sql.NullString
+
+	err := c.db.QueryRowContext(ctx, `
+		SELECT id, user_id, name, template, status, total_items, error_message, created_at, updated_at
+		FROM batch_runs
+		WHERE id = ? AND user_id = ?
+	`, batchID, userID).Scan(&batch.ID, &batch.UserID, &batch.Name, &templateJSON, &batch.Status,
+		&batch.TotalItems, &errorMessage, &batch.CreatedAt, &batch.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrBatchNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get batch run: %w", err)
+	}
+	batch.ErrorMessage = errorMessage.String
+
+	if err := types.FromJSON(templateJSON, &batch.Template); err != nil {
+		return nil, fmt.Errorf("failed to parse batch template: %w", err)
+	}
+
+	err = c.db.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(CASE WHEN status = ? THEN 1 ELSE 0 END), 0),
+		       COALESCE(SUM(CASE WHEN status = ? THEN 1 ELSE 0 END), 0)
+		FROM batch_items
+		WHERE batch_run_id = ?
+	`, types.BatchItemStatusCompleted, types.BatchItemStatusFailed, batchID).Scan(&batch.CompletedItems, &batch.FailedItems)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count batch items: %w", err)
+	}
+
+	if batch.Accuracy, err = c.batchAccuracy(ctx, batchID); err != nil {
+		return nil, err
+	}
+
+	return &batch, nil
+}
+
+// ListPendingBatchItems returns up to limit pending items of a batch run in submission order
+func (c *Client) ListPendingBatchItems(ctx context.Context, batchID string, limit int) ([]types.BatchItem, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT id, item_index, external_id, prompt, context, metadata, expected_answer, created_at
+		FROM batch_items
+		WHERE batch_run_id = ? AND status = ?
+		ORDER BY item_index ASC
+		LIMIT ?
+	`, batchID, types.BatchItemStatusPending, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending batch items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []types.BatchItem
+	for rows.Next() {
+		item := types.BatchItem{BatchRunID: batchID, Status: types.BatchItemStatusPending}
+		var externalID, itemContext, metadata, expectedAnswer sql.NullString
+		if err := rows.Scan(&item.ID, &item.ItemIndex, &externalID, &item.Prompt, &itemContext, &metadata, &expectedAnswer, &item.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan batch item: %w", err)
+		}
+		item.ExternalID = externalID.String
+		item.Context = itemContext.String
+		if err := types.FromJSON(metadata.String, &item.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to parse metadata for batch item %d: %w", item.ItemIndex, err)
+		}
+		if err := types.FromJSON(expectedAnswer.String, &item.ExpectedAnswer); err != nil {
+			return nil, fmt.Errorf("failed to parse expected answer for batch item %d: %w", item.ItemIndex, err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate batch items: %w", err)
+	}
+	return items, nil
+}
+
+// FinishBatchItem records the outcome of running one batch item
+func (c *Client) FinishBatchItem(ctx context.Context, itemID, executionRunID, errorMessage string) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	status := types.BatchItemStatusCompleted
+	if errorMessage != "" {
+		status = types.BatchItemStatusFailed
+	}
+
+	_, err := c.db.ExecContext(ctx,
+		"UPDATE batch_items SET status = ?, execution_run_id = ?, error_message = ? WHERE id = ?",
+		status, nullableString(executionRunID), nullableString(errorMessage), itemID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update batch item: %w", err)
+	}
+	return nil
+}
+
+// BatchItemRequest builds the execution request for one item from the batch template
+func BatchItemRequest(batch *types.BatchRun, item types.BatchItem) *types.MultiExecutionRequest {
+	request := *batch.Template
+	request.Configurations = append([]types.APIConfiguration(nil), batch.Template.Configurations...)
+	request.BasePrompt = item.Prompt
+	if item.Context != "" {
+		request.Context = item.Context
+	}
+	if item.ExpectedAnswer != nil {
+		request.ExpectedAnswer = item.ExpectedAnswer
+	}
+
+	label := fmt.Sprintf("#%d", item.ItemIndex+1)
+	if item.ExternalID != "" {
+		label = item.ExternalID
+	}
+	request.ExecutionRunName = fmt.Sprintf("%s [%s]", batch.Name, label)
+	return &request
+}
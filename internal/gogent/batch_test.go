@@ -0,0 +1,95 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"gogent/internal/testdb"
+	"gogent/internal/types"
+)
+
+// newBatchTestClient returns a client backed by in-memory batch_runs, batch_items and evaluation_results tables
+func newBatchTestClient(t *testing.T) *Client {
+	return &Client{db: testdb.Open(t)}
+}
+
+func TestBatchRunSubmission(t *testing.T) {
+	client := newBatchTestClient(t)
+	ctx := context.Background()
+
+	template := &types.MultiExecutionRequest{
+		ExecutionRunName: "nightly-eval",
+		BasePrompt:       "ignored",
+		Context:          "shared context",
+		Configurations:   []types.APIConfiguration{{VariationName: "precise", ModelName: "gemini-1.5-flash"}},
+		SessionApiKeys:   &types.SessionApiKeys{GeminiApiKey: "secret"},
+	}
+	batch, err := client.CreateBatchRun(ctx, "user-1", "nightly-eval", template)
+	if err != nil {
+		t.Fatalf("unexpected error creating batch run: %v", err)
+	}
+
+	// Two chunks, numbered continuously
+	var first, second []types.BatchItem
+	for i := 0; i < 3; i++ {
+		first = append(first, types.BatchItem{Prompt: fmt.Sprintf("question %d", i)})
+	}
+	second = append(second, types.BatchItem{ExternalID: "q-99", Prompt: "last question", Context: "own context", Metadata: map[string]string{"split": "test"}})
+	if err := client.AppendBatchItems(ctx, batch.ID, 0, first); err != nil {
+		t.Fatalf("unexpected error storing first chunk: %v", err)
+	}
+	if err := client.AppendBatchItems(ctx, batch.ID, 3, second); err != nil {
+		t.Fatalf("unexpected error storing second chunk: %v", err)
+	}
+
+	loaded, err := client.GetBatchRun(ctx, "user-1", batch.ID)
+	if err != nil {
+		t.Fatalf("unexpected error loading batch run: %v", err)
+	}
+	if loaded.TotalItems != 4 || loaded.Status != types.BatchStatusSubmitting {
+		t.Errorf("expected 4 submitting items, got %d (%s)", loaded.TotalItems, loaded.Status)
+	}
+	if loaded.Template.SessionApiKeys != nil || loaded.Template.BasePrompt != "" {
+		t.Errorf("expected session keys and base prompt to be dropped from the stored template, got %+v", loaded.Template)
+	}
+
+	if _, err := client.GetBatchRun(ctx, "user-2", batch.ID); !errors.Is(err, ErrBatchNotFound) {
+		t.Errorf("expected other users not to see the batch run, got %v", err)
+	}
+
+	items, err := client.ListPendingBatchItems(ctx, batch.ID, 10)
+	if err != nil {
+		t.Fatalf("unexpected error listing items: %v", err)
+	}
+	if len(items) != 4 || items[3].ItemIndex != 3 || items[3].Metadata["split"] != "test" {
+		t.Fatalf("expected 4 ordered items with metadata, got %+v", items)
+	}
+
+	request := BatchItemRequest(loaded, items[3])
+	if request.BasePrompt != "last question" || request.Context != "own context" || request.ExecutionRunName != "nightly-eval [q-99]" {
+		t.Errorf("unexpected item request: %+v", request)
+	}
+	if request = BatchItemRequest(loaded, items[0]); request.Context != "shared context" || request.ExecutionRunName != "nightly-eval [#1]" {
+		t.Errorf("expected the template context and index label, got %+v", request)
+	}
+
+	if err := client.FinishBatchItem(ctx, items[0].ID, "run-1", ""); err != nil {
+		t.Fatalf("unexpected error finishing item: %v", err)
+	}
+	if err := client.FinishBatchItem(ctx, items[1].ID, "", "quota exceeded"); err != nil {
+		t.Fatalf("unexpected error finishing item: %v", err)
+	}
+
+	loaded, err = client.GetBatchRun(ctx, "user-1", batch.ID)
+	if err != nil {
+		t.Fatalf("unexpected error loading batch run: %v", err)
+	}
+	if loaded.CompletedItems != 1 || loaded.FailedItems != 1 {
+		t.Errorf("expected 1 completed and 1 failed item, got %d and %d", loaded.CompletedItems, loaded.FailedItems)
+	}
+	if pending, _ := client.ListPendingBatchItems(ctx, batch.ID, 10); len(pending) != 2 {
+		t.Errorf("expected 2 pending items, got %d", len(pending))
+	}
+}
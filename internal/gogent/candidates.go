@@ -0,0 +1,113 @@
+package gogent
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"gogent/internal/gemini"
+	"gogent/internal/types"
+)
+
+// maxCandidateCount is the most candidates Gemini generates for one request
+const maxCandidateCount = 8
+
+// geminiCandidateResponse turns one of a call's other candidates into a response to the same
+// request. The call's token usage stays with the first candidate, and a function the candidate asks
+// for is recorded but not run.
+func geminiCandidateResponse(candidate gemini.Candidate, index int, first *types.APIResponse) types.APIResponse {
+	response := types.APIResponse{
+		ID:             uuid.New().String(),
+		RequestID:      first.RequestID,
+		ResponseStatus: types.ResponseStatusSuccess,
+		FinishReason:   candidate.FinishReason,
+		ResponseTimeMs: first.ResponseTimeMs,
+		CandidateIndex: index,
+		CreatedAt:      first.CreatedAt,
+	}
+	var thoughts []string
+	for _, part := range candidate.Content.Parts {
+		switch {
+		case part.Thought && part.Text != "":
+			thoughts = append(thoughts, part.Text)
+		case part.Text != "":
+			response.ResponseText = part.Text
+		case part.FunctionCall != nil && response.FunctionCallResponse == nil:
+			response.FunctionCallResponse = map[string]interface{}{
+				"function_name": part.FunctionCall.Name,
+				"arguments":     part.FunctionCall.Args,
+			}
+		}
+	}
+	response.ThoughtSummary = strings.Join(thoughts, "\n\n")
+	return response
+}
+
+// candidateResults splits a variation's other candidates into results of their own, after the
+// variation's, sharing its configuration, request and repetition
+func candidateResults(r types.VariationResult) []types.VariationResult {
+	results := []types.VariationResult{r}
+	for _, candidate := range r.Response.Candidates {
+		results = append(results, types.VariationResult{
+			Configuration: r.Configuration,
+			Request:       r.Request,
+			Response:      candidate,
+			ExecutionTime: r.ExecutionTime,
+			Repetition:    r.Repetition,
+
+			RetrievedChunks: r.RetrievedChunks,
+		})
+	}
+	results[0].Response.Candidates = nil
+	return results
+}
+
+// comparisonName is the name a result is scored under: its variation's, with the candidate number
+// for a configuration's other candidates, so each is compared as a sub-variation
+func comparisonName(r types.VariationResult) string {
+	if r.Response.CandidateIndex == 0 {
+		return r.Configuration.VariationName
+	}
+	return fmt.Sprintf("%s (candidate %d)", r.Configuration.VariationName, r.Response.CandidateIndex+1)
+}
+
+// storedCandidateCount adds the candidate count to the generation config saved with a configuration
+func storedCandidateCount(config *types.APIConfiguration, generationConfig map[string]interface{}) map[string]interface{} {
+	if config.CandidateCount == nil {
+		return generationConfig
+	}
+	stored := make(map[string]interface{}, len(generationConfig)+1)
+	for key, value := range generationConfig {
+		stored[key] = value
+	}
+	stored["candidateCount"] = *config.CandidateCount
+	return stored
+}
+
+// loadCandidateCount restores the candidate count from a saved generation config, removing it from
+// the generation config sent to providers
+func loadCandidateCount(config *types.APIConfiguration, generationConfig json.RawMessage) {
+	var stored struct {
+		CandidateCount *int32 `json:"candidateCount"`
+	}
+	if len(generationConfig) == 0 || json.Unmarshal(generationConfig, &stored) != nil || stored.CandidateCount == nil {
+		return
+	}
+	config.CandidateCount = stored.CandidateCount
+	delete(config.GenerationConfig, "candidateCount")
+}
+
+// validateCandidateCount checks a configuration's candidate count, which only Gemini models take
+func validateCandidateCount(validation *ValidationError, field string, config *types.APIConfiguration) {
+	if config.CandidateCount == nil {
+		return
+	}
+	if count := *config.CandidateCount; count < 1 || count > maxCandidateCount {
+		validation.add(field+".candidateCount", "must be between 1 and %d, got %d", maxCandidateCount, count)
+	}
+	if provider := types.ProviderForConfiguration(config); provider != "" && provider != types.ProviderGemini {
+		validation.add(field+".candidateCount", "multiple candidates are only supported on Gemini models, got %q", config.ModelName)
+	}
+}
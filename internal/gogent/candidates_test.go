@@ -0,0 +1,113 @@
+package gogent
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gogent/internal/types"
+)
+
+func TestCandidates(t *testing.T) {
+	client := NewInMemoryClient(&types.GeminiClientConfig{APIKey: "gemini-api-key"})
+	client.SetHTTPTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		var body map[string]interface{}
+		json.NewDecoder(req.Body).Decode(&body)
+		reply := `{"candidates": [{"content": {"parts": [{"text": "Paris"}]}, "finishReason": "STOP"}],
+			"usageMetadata": {"promptTokenCount": 8, "candidatesTokenCount": 1, "totalTokenCount": 9}}`
+		if body["generationConfig"].(map[string]interface{})["candidateCount"] == 3.0 {
+			reply = `{"candidates": [
+				{"content": {"parts": [{"text": "Paris"}]}, "finishReason": "STOP"},
+				{"content": {"parts": [{"text": "It is Paris."}]}, "finishReason": "STOP", "index": 1},
+				{"content": {"parts": [{"text": "Paris, on the Seine"}]}, "finishReason": "MAX_TOKENS", "index": 2}],
+				"usageMetadata": {"promptTokenCount": 8, "candidatesTokenCount": 12, "totalTokenCount": 20}}`
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(reply)), Header: make(http.Header)}, nil
+	}))
+
+	count, temperature := int32(3), float32(0.5)
+	result, err := client.ExecuteMultiVariation(context.Background(), "user-1", &types.MultiExecutionRequest{
+		BasePrompt: "Capital of France?",
+		Configurations: []types.APIConfiguration{
+			{VariationName: "multi", ModelName: "gemini-2.0-flash", CandidateCount: &count},
+			{VariationName: "single", ModelName: "gemini-2.0-flash", Temperature: &temperature},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Results) != 4 {
+		t.Fatalf("expected a result per candidate, got %d", len(result.Results))
+	}
+	wantText := []string{"Paris", "It is Paris.", "Paris, on the Seine"}
+	for i, text := range wantText {
+		r := result.Results[i]
+		if r.Configuration.VariationName != "multi" || r.Response.CandidateIndex != i || r.Response.ResponseText != text {
+			t.Errorf("expected candidate %d to be %q, got %+v", i, text, r.Response)
+		}
+		if r.Request.ID != result.Results[0].Request.ID {
+			t.Errorf("expected the candidates to share their request")
+		}
+	}
+	if usageTokens(result.Results[0].Response.UsageMetadata, "completion_tokens") != 12 || result.Results[1].Response.UsageMetadata != nil {
+		t.Errorf("expected the call's usage on the first candidate only")
+	}
+
+	responses, err := client.store.ListAPIResponsesByRun(context.Background(), "user-1", result.ExecutionRun.ID)
+	if err != nil || len(responses) != 4 {
+		t.Fatalf("expected a stored response per candidate, got %d, %v", len(responses), err)
+	}
+	if responses[2].CandidateIndex != 2 || responses[2].RequestID != responses[0].RequestID || responses[2].FinishReason != "MAX_TOKENS" {
+		t.Errorf("expected the third candidate linked to the request, got %+v", responses[2])
+	}
+
+	comparison, err := client.compareResults(context.Background(), "user-1", result, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, name := range []string{"multi", "multi (candidate 2)", "multi (candidate 3)", "single"} {
+		if _, ok := comparison.ConfigurationScores[name]; !ok {
+			t.Errorf("expected %q scored, got %v", name, comparison.ConfigurationScores)
+		}
+	}
+	if !strings.Contains(comparison.AnalysisNotes, "• Candidates: 2 extra candidates of 1 configurations") {
+		t.Errorf("expected the candidates insight, got %q", comparison.AnalysisNotes)
+	}
+}
+
+func TestNumberRepetitionsWithCandidates(t *testing.T) {
+	config := types.APIConfiguration{ID: "config-a"}
+	result := func(request string, candidate int) types.VariationResult {
+		return types.VariationResult{Configuration: config, Request: types.APIRequest{ID: request}, Response: types.APIResponse{CandidateIndex: candidate}}
+	}
+	results := []types.VariationResult{result("req-1", 0), result("req-1", 1), result("req-2", 0), result("req-2", 1)}
+	numberRepetitions(results)
+	for i, want := range []int{1, 1, 2, 2} {
+		if results[i].Repetition != want {
+			t.Errorf("expected result %d to be repetition %d, got %d", i, want, results[i].Repetition)
+		}
+	}
+
+	single := []types.VariationResult{result("req-1", 0), result("req-1", 1)}
+	numberRepetitions(single)
+	if single[0].Repetition != 0 || single[1].Repetition != 0 {
+		t.Errorf("expected the candidates of one request not to count as repetitions, got %+v", single)
+	}
+}
+
+func TestValidateCandidateCount(t *testing.T) {
+	two, tooMany := int32(2), int32(maxCandidateCount+1)
+	validation := validateRequest(&types.MultiExecutionRequest{Configurations: []types.APIConfiguration{
+		{ModelName: "gemini-2.0-flash", CandidateCount: &two},
+		{ModelName: "gemini-2.0-flash", CandidateCount: &tooMany},
+		{ModelName: "llama3.2", Provider: types.ProviderOllama, CandidateCount: &two},
+	}}, nil)
+	if validation == nil || len(validation.Errors) != 2 ||
+		validation.Errors[0].Field != "configurations[1].candidateCount" ||
+		validation.Errors[1].Field != "configurations[2].candidateCount" {
+		t.Errorf("expected too many candidates and Ollama candidates to be rejected, got %v", validation)
+	}
+}
@@ -0,0 +1,259 @@
+package gogent
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"gogent/internal/types"
+)
+
+const (
+	// defaultCircuitBreakerThreshold is the consecutive failures that open a host's breaker when
+	// the client's config does not set CircuitBreakerThreshold
+	defaultCircuitBreakerThreshold = 5
+	// defaultCircuitBreakerCooldown is how long an open breaker fails fast before it lets a trial
+	// request through, when the client's config does not set CircuitBreakerCooldownSecs
+	defaultCircuitBreakerCooldown = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned, without sending the request, for calls to a host whose circuit
+// breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// Circuit breaker states. A closed breaker sends requests and counts consecutive failures; an open
+// one fails them fast until its cooldown passes; a half-open one lets a single trial request
+// through, closing on its success and opening again on its failure.
+const (
+	circuitClosed   = "closed"
+	circuitOpen     = "open"
+	circuitHalfOpen = "half-open"
+)
+
+// CircuitBreakers tracks a circuit breaker for each host provider and function requests are sent
+// to. Clients create their own; the server shares one across the clients it creates for each run
+// with SetCircuitBreakers, so a failing host stays open between runs.
+type CircuitBreakers struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mutex sync.Mutex
+	hosts map[string]*circuitBreaker
+}
+
+// circuitBreaker is the state of one host's breaker
+type circuitBreaker struct {
+	state    string
+	failures int // consecutive failed requests
+	openedAt time.Time
+	probing  bool // a half-open trial request is in flight
+}
+
+// NewCircuitBreakers creates breakers that open after threshold consecutive failures and stay open
+// for cooldown. A threshold or cooldown of 0 uses the default.
+func NewCircuitBreakers(threshold int, cooldown time.Duration) *CircuitBreakers {
+	if threshold <= 0 {
+		threshold = defaultCircuitBreakerThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = defaultCircuitBreakerCooldown
+	}
+	return &CircuitBreakers{threshold: threshold, cooldown: cooldown, now: time.Now, hosts: make(map[string]*circuitBreaker)}
+}
+
+// newClientCircuitBreakers creates the breakers of a client from its config
+func newClientCircuitBreakers(config *types.GeminiClientConfig) *CircuitBreakers {
+	if config == nil {
+		return NewCircuitBreakers(0, 0)
+	}
+	return NewCircuitBreakers(config.CircuitBreakerThreshold, time.Duration(config.CircuitBreakerCooldownSecs)*time.Second)
+}
+
+// circuitOpenError is the error of a request refused by an open breaker. It reports itself
+// permanent so the Gemini client does not retry it.
+type circuitOpenError struct {
+	host    string
+	retryAt time.Time
+}
+
+func (e *circuitOpenError) Error() string {
+	if e.retryAt.IsZero() {
+		return fmt.Sprintf("%v: %s is failing; a trial request is in flight", ErrCircuitOpen, e.host)
+	}
+	return fmt.Sprintf("%v: %s is failing; requests fail fast until %s", ErrCircuitOpen, e.host, e.retryAt.Format(time.RFC3339))
+}
+
+func (e *circuitOpenError) Unwrap() error { return ErrCircuitOpen }
+
+func (e *circuitOpenError) Permanent() bool { return true }
+
+// allow returns nil when a request to host may be sent, or the error to fail it with. An open
+// breaker whose cooldown has passed turns half-open and lets the request through as its trial.
+func (b *CircuitBreakers) allow(host string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	breaker := b.hosts[host]
+	if breaker == nil {
+		return nil
+	}
+	switch breaker.state {
+	case circuitOpen:
+		retryAt := breaker.openedAt.Add(b.cooldown)
+		if b.now().Before(retryAt) {
+			return &circuitOpenError{host: host, retryAt: retryAt}
+		}
+		breaker.state = circuitHalfOpen
+		breaker.probing = true
+	case circuitHalfOpen:
+		if breaker.probing {
+			return &circuitOpenError{host: host}
+		}
+		breaker.probing = true
+	}
+	return nil
+}
+
+// record records the outcome of a request allow let through. It returns the state the host's
+// breaker moved to, or "" when the state did not change.
+func (b *CircuitBreakers) record(host string, failed bool) string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	breaker := b.hosts[host]
+	if breaker == nil {
+		if !failed {
+			return ""
+		}
+		breaker = &circuitBreaker{state: circuitClosed}
+		b.hosts[host] = breaker
+	}
+	previous := breaker.state
+	breaker.probing = false
+	switch {
+	case !failed:
+		breaker.state, breaker.failures = circuitClosed, 0
+	case breaker.state == circuitHalfOpen:
+		breaker.failures++
+		breaker.state, breaker.openedAt = circuitOpen, b.now()
+	default:
+		breaker.failures++
+		if breaker.state == circuitClosed && breaker.failures >= b.threshold {
+			breaker.state, breaker.openedAt = circuitOpen, b.now()
+		}
+	}
+	if breaker.state == previous {
+		return ""
+	}
+	return breaker.state
+}
+
+// release gives up a request's turn without an outcome, as when its caller canceled it, so a
+// half-open breaker lets the next request through as its trial
+func (b *CircuitBreakers) release(host string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if breaker := b.hosts[host]; breaker != nil {
+		breaker.probing = false
+	}
+}
+
+// Status reports the state and consecutive failures of each host's breaker, and the hosts whose
+// breaker is open or half-open
+func (b *CircuitBreakers) Status() (map[string]interface{}, []string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	hosts := make([]string, 0, len(b.hosts))
+	for host := range b.hosts {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	details := make(map[string]interface{}, len(hosts))
+	var failing []string
+	for _, host := range hosts {
+		breaker := b.hosts[host]
+		state := map[string]interface{}{"state": breaker.state, "failures": breaker.failures}
+		if breaker.state != circuitClosed {
+			state["openedAt"] = breaker.openedAt
+			failing = append(failing, fmt.Sprintf("%s (%s)", host, breaker.state))
+		}
+		details[host] = state
+	}
+	return details, failing
+}
+
+// circuitFailure reports whether a response counts against its host: server errors. Rate limits are
+// left out because breakers are shared by every user of a host, and one user's exhausted quota
+// must not fail everyone else's requests.
+func circuitFailure(statusCode int) bool {
+	return statusCode >= 500
+}
+
+// CircuitBreakers returns the breakers the client's requests go through
+func (c *Client) CircuitBreakers() *CircuitBreakers {
+	c.overridesMutex.RLock()
+	defer c.overridesMutex.RUnlock()
+	return c.circuitBreakers
+}
+
+// SetCircuitBreakers sends the client's provider and function requests through breakers, such as
+// ones shared with other clients
+func (c *Client) SetCircuitBreakers(breakers *CircuitBreakers) {
+	c.overridesMutex.Lock()
+	defer c.overridesMutex.Unlock()
+	c.circuitBreakers = breakers
+}
+
+// guardedTransport returns base behind the client's circuit breakers
+func (c *Client) guardedTransport(base http.RoundTripper) http.RoundTripper {
+	return &breakerTransport{client: c, base: base}
+}
+
+// breakerTransport sends requests to base through the breaker of their host, failing them fast
+// while it is open and logging fast failures and state changes to the run's execution logs
+type breakerTransport struct {
+	client *Client
+	base   http.RoundTripper
+}
+
+func (t *breakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	breakers := t.client.CircuitBreakers()
+	if breakers == nil {
+		return base.RoundTrip(req)
+	}
+
+	ctx, host := req.Context(), req.URL.Host
+	if err := breakers.allow(host); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		t.client.logExecutionEvent(ctx, types.LogLevelWarn, types.LogCategoryAPICall,
+			fmt.Sprintf("Failing request to %s fast: %v", host, err),
+			map[string]interface{}{"host": host, "url": req.URL.Scheme + "://" + host + req.URL.Path})
+		return nil, err
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil && ctx.Err() != nil {
+		breakers.release(host)
+		return resp, err
+	}
+	switch breakers.record(host, err != nil || circuitFailure(resp.StatusCode)) {
+	case circuitOpen:
+		t.client.logExecutionEvent(ctx, types.LogLevelWarn, types.LogCategoryAPICall,
+			fmt.Sprintf("Circuit breaker for %s opened; requests fail fast for %s", host, breakers.cooldown),
+			map[string]interface{}{"host": host, "state": circuitOpen})
+	case circuitClosed:
+		t.client.logExecutionEvent(ctx, types.LogLevelInfo, types.LogCategoryAPICall,
+			fmt.Sprintf("Circuit breaker for %s closed after a successful trial request", host),
+			map[string]interface{}{"host": host, "state": circuitClosed})
+	}
+	return resp, err
+}
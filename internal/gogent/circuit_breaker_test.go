@@ -0,0 +1,124 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gogent/internal/types"
+)
+
+func TestCircuitBreakers(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	breakers := NewCircuitBreakers(2, time.Minute)
+	breakers.now = func() time.Time { return now }
+	host := "api.example.com"
+
+	// Consecutive failures open the breaker; a success in between starts the count again
+	breakers.record(host, true)
+	breakers.record(host, false)
+	if state := breakers.record(host, true); state != "" || breakers.allow(host) != nil {
+		t.Fatalf("expected the breaker to stay closed after a success reset its failures, got %q", state)
+	}
+	if state := breakers.record(host, true); state != circuitOpen {
+		t.Fatalf("expected the second consecutive failure to open the breaker, got %q", state)
+	}
+	if err := breakers.allow(host); !errors.Is(err, ErrCircuitOpen) || breakers.allow("other.example.com") != nil {
+		t.Fatalf("expected only the failing host to fail fast, got %v", err)
+	}
+
+	// After the cooldown one trial request goes through; its failure opens the breaker again
+	now = now.Add(time.Minute)
+	if err := breakers.allow(host); err != nil {
+		t.Fatalf("expected a trial request after the cooldown, got %v", err)
+	}
+	if err := breakers.allow(host); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected requests to fail fast while the trial is in flight, got %v", err)
+	}
+	if state := breakers.record(host, true); state != circuitOpen || breakers.allow(host) == nil {
+		t.Errorf("expected the failed trial to open the breaker, got %q", state)
+	}
+
+	// A canceled trial lets the next request try, and a successful one closes the breaker
+	now = now.Add(time.Minute)
+	breakers.allow(host)
+	breakers.release(host)
+	if err := breakers.allow(host); err != nil {
+		t.Fatalf("expected the next request to be the trial, got %v", err)
+	}
+	if state := breakers.record(host, false); state != circuitClosed || breakers.allow(host) != nil {
+		t.Errorf("expected the successful trial to close the breaker, got %q", state)
+	}
+}
+
+// statusTransport answers every request with a status code, counting the calls
+type statusTransport struct {
+	status int
+	calls  atomic.Int32
+}
+
+func (t *statusTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.calls.Add(1)
+	return &http.Response{StatusCode: t.status, Body: io.NopCloser(strings.NewReader("")), Header: make(http.Header), Request: req}, nil
+}
+
+func TestCircuitBreakerTransport(t *testing.T) {
+	client, store := newStoreTestClient(t)
+	client.SetCircuitBreakers(NewCircuitBreakers(2, time.Minute))
+	ctx := context.Background()
+	run, err := client.CreateExecutionRun(ctx, "user-1", "Failing provider", "", false)
+	if err != nil {
+		t.Fatalf("failed to create run: %v", err)
+	}
+	runCtx := withExecutionScope(ctx, executionScope{executionRunID: run.ID})
+
+	transport := &statusTransport{status: http.StatusBadGateway}
+	httpClient := &http.Client{Transport: client.guardedTransport(transport)}
+	for range 3 {
+		req, _ := http.NewRequestWithContext(runCtx, http.MethodGet, "https://functions.example.com/lookup", nil)
+		if resp, err := httpClient.Do(req); err == nil {
+			resp.Body.Close()
+		} else if !errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("expected the open circuit to fail the request, got %v", err)
+		}
+	}
+	if transport.calls.Load() != 2 {
+		t.Errorf("expected the third request to fail fast, got %d calls", transport.calls.Load())
+	}
+
+	check := client.checkCircuitBreakers(ctx)
+	if host, _ := check.Details["functions.example.com"].(map[string]interface{}); check.Status != types.HealthDegraded || host["state"] != circuitOpen {
+		t.Errorf("expected the open breaker to degrade health, got %+v", check)
+	}
+
+	client.Close()
+	logs, _ := store.ListExecutionLogs(ctx, "user-1", run.ID)
+	if len(logs) != 2 || !strings.Contains(logs[0].Message, "opened") || !strings.Contains(logs[1].Message, "fast") {
+		t.Errorf("expected the breaker opening and the fast failure logged, got %+v", logs)
+	}
+}
+
+func TestCircuitBreakerIgnoresRateLimits(t *testing.T) {
+	client := NewInMemoryClient(&types.GeminiClientConfig{})
+	client.SetCircuitBreakers(NewCircuitBreakers(2, time.Minute))
+
+	// One user's rate limit says nothing about the host, so it must not fail other users fast
+	transport := &statusTransport{status: http.StatusTooManyRequests}
+	httpClient := &http.Client{Transport: client.guardedTransport(transport)}
+	for range 3 {
+		req, _ := http.NewRequest(http.MethodPost, "https://generativelanguage.googleapis.com/v1beta/models", nil)
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			t.Fatalf("expected rate limited requests to be sent, got %v", err)
+		}
+		resp.Body.Close()
+	}
+	if transport.calls.Load() != 3 {
+		t.Errorf("expected every request to reach the host, got %d calls", transport.calls.Load())
+	}
+}
@@ -1,22 +1,22 @@
 package gogent
 
 import (
-	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"math/rand"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
-	"gogent/internal/db"
 	"gogent/internal/gemini"
 	"gogent/internal/types"
 
@@ -31,14 +31,38 @@ import (
 // Client represents the main gogent client that wraps Gemini API calls
 type Client struct {
 	db           *sql.DB
-	queries      *db.Queries
+	store        Store
 	config       *types.GeminiClientConfig
-	geminiClient *gemini.GeminiClient
-	mutex        sync.RWMutex
-	// Add execution context for logging
-	currentExecutionRunID *string
-	currentConfigID       *string
-	currentRequestID      *string
+	geminiClient *gemini.Client
+	// overridesMutex guards the dependencies replaced with SetHTTPTransport, SetEmbeddingProvider,
+	// SetWebSearchBackend, SetDocumentRetriever and SetCircuitBreakers. Runs carry their own state in
+	// an executionScope and the store is safe for concurrent use, so nothing else is locked.
+	overridesMutex sync.RWMutex
+	// embeddingProvider overrides the embedding service picked from the API key
+	embeddingProvider EmbeddingProvider
+	// redactor applies the redaction policy to stored requests and responses; see payloadRedactor
+	redactor     *redactor
+	redactorOnce sync.Once
+	// vertexClient calls configurations on the Vertex AI backend; created on first use, see vertexAPI
+	vertexClient *gemini.Client
+	vertexErr    error
+	vertexOnce   sync.Once
+	// httpTransport overrides the transport of Gemini and weather API calls; see SetHTTPTransport
+	httpTransport http.RoundTripper
+	// circuitBreakers fail requests to failing hosts fast; see guardedTransport
+	circuitBreakers *CircuitBreakers
+	// neo4jPool caches Neo4j drivers and run sessions for query_graph; see neo4jConnections
+	neo4jPool     *neo4jPool
+	neo4jPoolOnce sync.Once
+	// logWriter stores execution logs in batches; see executionLogs
+	logWriter     *executionLogWriter
+	logWriterOnce sync.Once
+	// providerHealth caches the model provider's health check; see checkProvider
+	providerHealth cachedHealthCheck
+	// webSearchBackend overrides the search API picked from the config; see webSearcher
+	webSearchBackend WebSearchBackend
+	// documentRetriever overrides the document_chunks table as the source of retrieved chunks
+	documentRetriever DocumentRetriever
 }
 
 // NewClient creates a new gogent client with database connection
@@ -52,14 +76,13 @@ func NewClient(dbURL string, config *types.GeminiClientConfig) (*Client, error)
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	queries := db.New(database)
+	store := NewSQLStore(database)
 
 	// Create temporary client to run migrations
 	tempClient := &Client{
-		db:      database,
-		queries: queries,
-		config:  config,
-		mutex:   sync.RWMutex{},
+		db:     database,
+		store:  store,
+		config: config,
 	}
 
 	// Run migrations using golang-migrate
@@ -69,243 +92,346 @@ func NewClient(dbURL string, config *types.GeminiClientConfig) (*Client, error)
 	}
 
 	client := &Client{
-		db:      database,
-		queries: queries,
-		config:  config,
-		mutex:   sync.RWMutex{},
-	}
-
-	// Initialize Gemini client if API key is provided
-	// DISABLED: Go SDK has model name format issues, using REST API directly
-	/*
-		if config.APIKey != "" {
-			ctx := context.Background()
-			geminiClient, err := gemini.NewGeminiClient(ctx, config.APIKey)
-			if err != nil {
-				log.Printf("Failed to initialize Gemini client: %v", err)
-				// Continue without Gemini client (will use mock responses)
-			} else {
-				client.geminiClient = geminiClient
-				log.Printf("Successfully initialized Gemini client with API key: %s...", config.APIKey[:10])
-			}
-		}
-	*/
+		db:              database,
+		store:           store,
+		config:          config,
+		circuitBreakers: newClientCircuitBreakers(config),
+	}
 
-	// Force REST API usage - no Go SDK client
-	client.geminiClient = nil
-	log.Printf("Go SDK disabled - using REST API for all Gemini calls")
+	// Gemini calls go through the REST client; without an API key responses are mocked
+	if config.APIKey != "" {
+		client.geminiClient = gemini.NewClient(config.APIKey)
+		client.geminiClient.SetTransport(client.providerTransport())
+	}
 
 	return client, nil
 }
 
-// Close closes the database connection and Gemini client
+// NewInMemoryClient creates a gogent client that keeps execution records in process instead of
+// MySQL. Features that need the database, such as batches, SLOs and saved functions, return
+// ErrNoDatabase.
+func NewInMemoryClient(config *types.GeminiClientConfig) *Client {
+	client := &Client{
+		store:           NewMemoryStore(),
+		config:          config,
+		circuitBreakers: newClientCircuitBreakers(config),
+	}
+
+	// Gemini calls go through the REST client; without an API key responses are mocked
+	if config.APIKey != "" {
+		client.geminiClient = gemini.NewClient(config.APIKey)
+		client.geminiClient.SetTransport(client.providerTransport())
+	}
+
+	return client
+}
+
+// Close closes the database connection
 func (c *Client) Close() error {
-	if c.geminiClient != nil {
-		c.geminiClient.Close()
+	c.executionLogs().close()
+	c.neo4jConnections().close(context.Background())
+	if c.db == nil {
+		return nil
 	}
 	return c.db.Close()
 }
 
+// SetStore replaces where execution records are kept, such as with a MemoryStore in tests. Call
+// it before the client is used.
+func (c *Client) SetStore(store Store) {
+	c.store = store
+}
+
 // CreateExecutionRun creates a new execution run for grouping related API calls
 func (c *Client) CreateExecutionRun(ctx context.Context, userID, name, description string, enableFunctionCalling bool) (*types.ExecutionRun, error) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-
-	id := uuid.New().String()
 	log.Printf("🔧 Creating execution run with enableFunctionCalling: %v", enableFunctionCalling)
-	err := c.queries.CreateExecutionRun(ctx, db.CreateExecutionRunParams{
-		ID:                    id,
+	run := &types.ExecutionRun{
+		ID:                    uuid.New().String(),
 		UserID:                userID,
 		Name:                  name,
-		Description:           sql.NullString{String: description, Valid: description != ""},
-		EnableFunctionCalling: enableFunctionCalling,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to create execution run: %w", err)
-	}
-
-	return &types.ExecutionRun{
-		ID:                    id,
-		Name:                  name,
 		Description:           description,
 		EnableFunctionCalling: enableFunctionCalling,
 		Status:                "pending", // Start with pending status
 		ErrorMessage:          "",
 		CreatedAt:             time.Now(),
 		UpdatedAt:             time.Now(),
-	}, nil
+	}
+	if err := c.store.CreateExecutionRun(ctx, run); err != nil {
+		return nil, fmt.Errorf("failed to create execution run: %w", err)
+	}
+
+	return run, nil
+}
+
+// UpdateExecutionRunStatus records a run's status (running, completed or failed) and why it failed
+func (c *Client) UpdateExecutionRunStatus(ctx context.Context, executionRunID, status, errorMessage string) error {
+	if err := c.store.UpdateExecutionRunStatus(ctx, executionRunID, status, errorMessage); err != nil {
+		return fmt.Errorf("failed to update execution run status: %w", err)
+	}
+	return nil
+}
+
+// finishExecutionRun records how a run ended. It is written even when ctx was canceled, so runs
+// interrupted at shutdown are not left running.
+func (c *Client) finishExecutionRun(ctx context.Context, executionRunID string, runErr error) {
+	status, errorMessage := "completed", ""
+	if runErr != nil {
+		status, errorMessage = "failed", runErr.Error()
+	}
+	if err := c.UpdateExecutionRunStatus(context.WithoutCancel(ctx), executionRunID, status, errorMessage); err != nil {
+		log.Printf("⚠️ Warning: %v", err)
+	}
 }
 
 // CreateAPIConfiguration creates a new API configuration for a variation
 func (c *Client) CreateAPIConfiguration(ctx context.Context, userID string, config *types.APIConfiguration) error {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-
-	safetySettingsJSON, _ := types.ToJSON(config.SafetySettings)
-	generationConfigJSON, _ := types.ToJSON(config.GenerationConfig)
-	toolsJSON, _ := types.ToJSON(config.Tools)
-	toolConfigJSON, _ := types.ToJSON(config.ToolConfig)
-
-	return c.queries.CreateAPIConfiguration(ctx, db.CreateAPIConfigurationParams{
-		ID:               config.ID,
-		UserID:           userID,
-		ExecutionRunID:   config.ExecutionRunID,
-		VariationName:    config.VariationName,
-		ModelName:        config.ModelName,
-		SystemPrompt:     sql.NullString{String: config.SystemPrompt, Valid: config.SystemPrompt != ""},
-		Temperature:      convertFloat32ToNullString(config.Temperature),
-		MaxTokens:        convertInt32ToNullInt32(config.MaxTokens),
-		TopP:             convertFloat32ToNullString(config.TopP),
-		TopK:             convertInt32ToNullInt32(config.TopK),
-		SafetySettings:   convertStringToRawMessage(safetySettingsJSON),
-		GenerationConfig: convertStringToRawMessage(generationConfigJSON),
-		Tools:            convertStringToRawMessage(toolsJSON),
-		ToolConfig:       convertStringToRawMessage(toolConfigJSON),
-	})
+	return c.store.CreateAPIConfiguration(ctx, userID, config)
 }
 
-// LogAPIRequest logs an API request to the database
+// LogAPIRequest logs an API request to the database after applying the redaction policy
 func (c *Client) LogAPIRequest(ctx context.Context, userID string, request *types.APIRequest) error {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-
-	functionParamsJSON, _ := types.ToJSON(request.FunctionParameters)
-	requestHeadersJSON, _ := types.ToJSON(request.RequestHeaders)
-	requestBodyJSON, _ := types.ToJSON(request.RequestBody)
-
-	return c.queries.CreateAPIRequest(ctx, db.CreateAPIRequestParams{
-		ID:                 request.ID,
-		UserID:             userID,
-		ExecutionRunID:     request.ExecutionRunID,
-		ConfigurationID:    request.ConfigurationID,
-		RequestType:        sql.NullString{String: string(request.RequestType), Valid: true},
-		Prompt:             sql.NullString{String: request.Prompt, Valid: request.Prompt != ""},
-		Context:            sql.NullString{String: request.Context, Valid: request.Context != ""},
-		FunctionName:       sql.NullString{String: request.FunctionName, Valid: request.FunctionName != ""},
-		FunctionParameters: convertStringToRawMessage(functionParamsJSON),
-		RequestHeaders:     convertStringToRawMessage(requestHeadersJSON),
-		RequestBody:        convertStringToRawMessage(requestBodyJSON),
-	})
+	redacted := c.payloadRedactor().redactRequest(request)
+	return c.store.CreateAPIRequest(ctx, userID, redacted)
 }
 
-// LogAPIResponse logs an API response to the database
+// LogAPIResponse logs an API response to the database after applying the redaction policy
 func (c *Client) LogAPIResponse(ctx context.Context, userID string, response *types.APIResponse) error {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-
-	functionCallResponseJSON, _ := types.ToJSON(response.FunctionCallResponse)
-	usageMetadataJSON, _ := types.ToJSON(response.UsageMetadata)
-	safetyRatingsJSON, _ := types.ToJSON(response.SafetyRatings)
-	responseHeadersJSON, _ := types.ToJSON(response.ResponseHeaders)
-	responseBodyJSON, _ := types.ToJSON(response.ResponseBody)
-
-	return c.queries.CreateAPIResponse(ctx, db.CreateAPIResponseParams{
-		ID:                   response.ID,
-		UserID:               userID,
-		RequestID:            response.RequestID,
-		ResponseStatus:       sql.NullString{String: string(response.ResponseStatus), Valid: true},
-		ResponseText:         sql.NullString{String: response.ResponseText, Valid: response.ResponseText != ""},
-		FunctionCallResponse: convertStringToRawMessage(functionCallResponseJSON),
-		UsageMetadata:        convertStringToRawMessage(usageMetadataJSON),
-		SafetyRatings:        convertStringToRawMessage(safetyRatingsJSON),
-		FinishReason:         sql.NullString{String: response.FinishReason, Valid: response.FinishReason != ""},
-		ErrorMessage:         sql.NullString{String: response.ErrorMessage, Valid: response.ErrorMessage != ""},
-		ResponseTimeMs:       sql.NullInt32{Int32: response.ResponseTimeMs, Valid: true},
-		ResponseHeaders:      convertStringToRawMessage(responseHeadersJSON),
-		ResponseBody:         convertStringToRawMessage(responseBodyJSON),
-	})
+	redacted := c.payloadRedactor().redactResponse(response)
+	return c.store.CreateAPIResponse(ctx, userID, redacted)
 }
 
 // ExecuteMultiVariation executes the same prompt with multiple configurations
 func (c *Client) ExecuteMultiVariation(ctx context.Context, userID string, request *types.MultiExecutionRequest) (*types.ExecutionResult, error) {
+	// Reject invalid safety policies before anything is recorded
+	if err := ValidateRequestSafetyPolicies(request); err != nil {
+		return nil, err
+	}
+	if err := ValidateRepetitions(request.Repetitions); err != nil {
+		return nil, err
+	}
+	if err := ValidateExpectedAnswer(request.ExpectedAnswer); err != nil {
+		return nil, err
+	}
+	if err := ValidateTags(request.Tags); err != nil {
+		return nil, err
+	}
+	if err := ValidateRawPayloadLimit(request.RawPayloadMaxBytes); err != nil {
+		return nil, err
+	}
+	repetitions := max(request.Repetitions, 1)
+
+	// Dry runs only build what each variation would send its provider
+	if request.DryRun {
+		return c.dryRun(ctx, request)
+	}
+
+	// Variations count the time they waited from when the request was queued, or from now
+	submittedAt := request.SubmittedAt
+	if submittedAt.IsZero() {
+		submittedAt = time.Now()
+	}
+
 	// Create execution run
 	executionRun, err := c.CreateExecutionRun(ctx, userID, request.ExecutionRunName, request.Description, request.EnableFunctionCalling)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create execution run: %w", err)
 	}
 
-	// Set execution context for logging
-	c.setExecutionContext(&executionRun.ID, nil, nil)
-	defer c.clearExecutionContext()
+	// Record the run's logs against it
+	ctx = withExecutionScope(ctx, executionScope{executionRunID: executionRun.ID})
+	if request.StoreRawPayloads {
+		ctx = withRawPayloads(ctx, request.RawPayloadMaxBytes)
+	}
+	if request.CacheContext && request.Context != "" {
+		var cache *contextCache
+		ctx, cache = withContextCache(ctx, request.Context)
+		defer c.releaseContextCache(cache)
+	}
+	defer c.releaseNeo4jSessions(executionRun.ID)
+	// Every log of the run is stored by the time it returns
+	defer c.executionLogs().flush()
+
+	if err := c.UpdateExecutionRunStatus(ctx, executionRun.ID, "running", ""); err != nil {
+		c.logExecutionEvent(ctx, types.LogLevelWarn, types.LogCategorySetup, err.Error(), nil)
+	}
+
+	// Link replays to the run they replay
+	if request.ReplayOfRunID != "" {
+		if err := c.recordReplay(ctx, executionRun.ID, request.ReplayOfRunID); err != nil {
+			c.logExecutionEvent(ctx, types.LogLevelWarn, types.LogCategorySetup,
+				fmt.Sprintf("Failed to link replay to %s: %v", request.ReplayOfRunID, err), nil)
+		}
+		executionRun.ReplayOfRunID = request.ReplayOfRunID
+	}
+
+	// Link clones to the run they were cloned from
+	if request.ParentRunID != "" {
+		if err := c.recordParentRun(ctx, executionRun.ID, request.ParentRunID); err != nil {
+			c.logExecutionEvent(ctx, types.LogLevelWarn, types.LogCategorySetup,
+				fmt.Sprintf("Failed to link run to its parent %s: %v", request.ParentRunID, err), nil)
+		}
+		executionRun.ParentRunID = request.ParentRunID
+	}
+
+	// Record what was submitted so identical resubmissions can be detected
+	if err := c.recordContentHash(ctx, executionRun.ID, SubmissionContentHash(request)); err != nil {
+		c.logExecutionEvent(ctx, types.LogLevelWarn, types.LogCategorySetup,
+			fmt.Sprintf("Duplicate detection unavailable for this run: %v", err), nil)
+	}
+
+	// Store the resolved spec so the run can be reproduced
+	if err := c.recordRunSpec(ctx, executionRun.ID, RunSpecFromRequest(request)); err != nil {
+		c.logExecutionEvent(ctx, types.LogLevelWarn, types.LogCategorySetup,
+			fmt.Sprintf("Failed to store run spec: %v", err), nil)
+	}
+
+	// Keep the sweep the configurations came from so results can be reported by parameter
+	if request.Sweep != nil {
+		executionRun.Sweep = request.Sweep
+		if err := c.recordParameterSweep(ctx, executionRun.ID, request.Sweep); err != nil {
+			c.logExecutionEvent(ctx, types.LogLevelWarn, types.LogCategorySetup,
+				fmt.Sprintf("Failed to store parameter sweep: %v", err), nil)
+		}
+	}
+
+	// Tag the run so it is ranked on the leaderboard of each of its prompt categories
+	if tags := normalizeTags(request.Tags); len(tags) > 0 {
+		executionRun.Tags = tags
+		if err := c.recordRunTags(ctx, userID, executionRun.ID, tags); err != nil {
+			c.logExecutionEvent(ctx, types.LogLevelWarn, types.LogCategorySetup,
+				fmt.Sprintf("Failed to store run tags: %v", err), nil)
+		}
+	}
 
 	// Log execution start
-	c.logExecutionEvent(types.LogLevelInfo, types.LogCategorySetup,
+	c.logExecutionEvent(ctx, types.LogLevelInfo, types.LogCategorySetup,
 		fmt.Sprintf("Starting execution: %s", request.ExecutionRunName),
 		map[string]interface{}{
 			"enableFunctionCalling": request.EnableFunctionCalling,
 			"functionToolsCount":    len(request.FunctionTools),
 			"configurationsCount":   len(request.Configurations),
+			"repetitions":           repetitions,
+		})
+
+	// Record which redaction policy the run's stored requests and responses went through
+	redaction := c.payloadRedactor()
+	c.logExecutionEvent(ctx, types.LogLevelInfo, types.LogCategorySetup,
+		fmt.Sprintf("Redacting stored payloads with policy %s", redaction.fingerprint),
+		map[string]interface{}{
+			"headerAllowlist": redaction.policy.HeaderAllowlist,
+			"patterns":        redaction.policy.Patterns,
+			"maxBodyBytes":    redaction.policy.MaxBodyBytes,
 		})
 
 	if request.EnableFunctionCalling {
 		for i, tool := range request.FunctionTools {
-			c.logExecutionEvent(types.LogLevelDebug, types.LogCategorySetup,
+			c.logExecutionEvent(ctx, types.LogLevelDebug, types.LogCategorySetup,
 				fmt.Sprintf("Function tool %d: %s - %s", i+1, tool.Name, tool.Description), nil)
 		}
 	}
 
 	result := &types.ExecutionResult{
 		ExecutionRun: *executionRun,
-		Results:      make([]types.VariationResult, 0, len(request.Configurations)),
+		Results:      make([]types.VariationResult, 0, len(request.Configurations)*repetitions),
 		TotalTime:    0,
 		SuccessCount: 0,
 		ErrorCount:   0,
 	}
 
 	startTime := time.Now()
+	progress := types.ExecutionProgress{ExecutionRunID: executionRun.ID, Total: len(request.Configurations) * repetitions}
+	reportProgress(ctx, progress)
 
 	// Execute each configuration with rate limiting
 	for i, config := range request.Configurations {
+		// Stop between configurations once the run is canceled, e.g. by a server shutting down
+		if err := ctx.Err(); err != nil {
+			err = fmt.Errorf("execution interrupted after %d of %d configurations: %w", i, len(request.Configurations), err)
+			c.logExecutionEvent(ctx, types.LogLevelError, types.LogCategoryError, err.Error(), nil)
+			c.finishExecutionRun(ctx, executionRun.ID, err)
+			return nil, err
+		}
+
 		config.ID = uuid.New().String()
 		config.ExecutionRunID = executionRun.ID
 
-		// CRITICAL: Add function tools to configuration if function calling is enabled
-		if request.EnableFunctionCalling && len(request.FunctionTools) > 0 {
-			config.Tools = request.FunctionTools
+		if err := prepareConfiguration(&config, request); err != nil {
+			c.logExecutionEvent(ctx, types.LogLevelError, types.LogCategoryError,
+				fmt.Sprintf("Failed to prepare configuration: %v", err), nil)
+			c.finishExecutionRun(ctx, executionRun.ID, err)
+			return nil, err
 		}
 
 		// Save configuration FIRST before setting context for logging
 		if err := c.CreateAPIConfiguration(ctx, userID, &config); err != nil {
-			c.logExecutionEvent(types.LogLevelError, types.LogCategoryError,
+			c.logExecutionEvent(ctx, types.LogLevelError, types.LogCategoryError,
 				fmt.Sprintf("Failed to save configuration: %v", err), nil)
-			return nil, fmt.Errorf("failed to save configuration: %w", err)
+			err = fmt.Errorf("failed to save configuration: %w", err)
+			c.finishExecutionRun(ctx, executionRun.ID, err)
+			return nil, err
 		}
 
-		// Set configuration context for logging AFTER saving to database
-		c.setExecutionContext(&executionRun.ID, &config.ID, nil)
+		// Record the configuration's logs against it once it is saved
+		ctx := withExecutionScope(ctx, executionScope{executionRunID: executionRun.ID, configurationID: config.ID})
 
 		// Log the function tools setup
-		if request.EnableFunctionCalling && len(request.FunctionTools) > 0 {
-			c.logExecutionEvent(types.LogLevelDebug, types.LogCategorySetup,
-				fmt.Sprintf("Adding %d function tools to configuration: %s", len(request.FunctionTools), config.VariationName), nil)
+		if request.EnableFunctionCalling && len(config.Tools) > 0 {
+			c.logExecutionEvent(ctx, types.LogLevelDebug, types.LogCategorySetup,
+				fmt.Sprintf("Adding %d of %d function tools to configuration: %s", len(config.Tools), len(request.FunctionTools), config.VariationName),
+				map[string]interface{}{
+					"toolNames": toolNames(config.Tools),
+				})
+		} else if request.EnableFunctionCalling && len(request.FunctionTools) > 0 {
+			c.logExecutionEvent(ctx, types.LogLevelInfo, types.LogCategorySetup,
+				fmt.Sprintf("Tools withheld from configuration by its tool subset: %s", config.VariationName),
+				map[string]interface{}{
+					"disableTools": config.DisableTools,
+					"toolNames":    config.ToolNames,
+				})
 		} else {
-			c.logExecutionEvent(types.LogLevelWarn, types.LogCategorySetup,
+			c.logExecutionEvent(ctx, types.LogLevelWarn, types.LogCategorySetup,
 				fmt.Sprintf("No function tools added to configuration: enableFunctionCalling=%v, toolCount=%d", request.EnableFunctionCalling, len(request.FunctionTools)), nil)
 		}
 
-		// Execute single variation
-		c.logExecutionEvent(types.LogLevelInfo, types.LogCategoryExecution,
-			fmt.Sprintf("Executing variation: %s", config.VariationName), nil)
+		// Execute the variation once per repetition, reusing the saved configuration
+		for repetition := 1; repetition <= repetitions; repetition++ {
+			label := config.VariationName
+			if repetitions > 1 {
+				label = fmt.Sprintf("%s (repetition %d/%d)", config.VariationName, repetition, repetitions)
+			}
+			c.logExecutionEvent(ctx, types.LogLevelInfo, types.LogCategoryExecution,
+				fmt.Sprintf("Executing variation: %s", label), nil)
 
-		variationResult, err := c.executeSingleVariation(ctx, userID, executionRun.ID, &config, request.BasePrompt, request.Context)
-		if err != nil {
-			c.logExecutionEvent(types.LogLevelError, types.LogCategoryError,
-				fmt.Sprintf("Variation failed: %s - %v", config.VariationName, err), nil)
-			result.ErrorCount++
-		} else {
-			c.logExecutionEvent(types.LogLevelSuccess, types.LogCategoryExecution,
-				fmt.Sprintf("Variation completed: %s", config.VariationName), nil)
-			result.SuccessCount++
-		}
+			variationResult, err := c.executeSingleVariation(ctx, userID, executionRun.ID, &config, request.BasePrompt, request.Context, time.Since(submittedAt))
+			if err != nil {
+				c.logExecutionEvent(ctx, types.LogLevelError, types.LogCategoryError,
+					fmt.Sprintf("Variation failed: %s - %v", label, err), nil)
+				result.ErrorCount++
+			} else {
+				c.logExecutionEvent(ctx, types.LogLevelSuccess, types.LogCategoryExecution,
+					fmt.Sprintf("Variation completed: %s", label), nil)
+				result.SuccessCount++
+			}
 
-		result.Results = append(result.Results, *variationResult)
+			if repetitions > 1 {
+				variationResult.Repetition = repetition
+			}
+			result.Results = append(result.Results, candidateResults(*variationResult)...)
 
-		// Add rate limiting delay between requests (except for the last one)
-		if i < len(request.Configurations)-1 {
-			delay := time.Duration(100+rand.Intn(101)) * time.Millisecond
-			c.logExecutionEvent(types.LogLevelDebug, types.LogCategoryExecution,
-				fmt.Sprintf("Rate limiting: waiting %v before next API call", delay), nil)
-			time.Sleep(delay)
+			progress.Completed++
+			if err != nil {
+				progress.Failed++
+			}
+			progress.Variation = label
+			reportProgress(ctx, progress)
+
+			// Add rate limiting delay between requests (except for the last one)
+			if i < len(request.Configurations)-1 || repetition < repetitions {
+				delay := time.Duration(100+rand.Intn(101)) * time.Millisecond
+				c.logExecutionEvent(ctx, types.LogLevelDebug, types.LogCategoryExecution,
+					fmt.Sprintf("Rate limiting: waiting %v before next API call", delay), nil)
+				time.Sleep(delay)
+			}
 		}
 	}
 
@@ -313,19 +439,40 @@ func (c *Client) ExecuteMultiVariation(ctx context.Context, userID string, reque
 	if request.EnableFunctionCalling && len(request.FunctionTools) > 0 {
 		err := c.storeFunctionExecutionConfigs(ctx, userID, executionRun.ID, request.FunctionTools)
 		if err != nil {
-			c.logExecutionEvent(types.LogLevelWarn, types.LogCategoryError,
+			c.logExecutionEvent(ctx, types.LogLevelWarn, types.LogCategoryError,
 				fmt.Sprintf("Failed to store function-execution configs: %v", err), nil)
 			// Don't fail the entire execution, just log the warning
 		} else {
-			c.logExecutionEvent(types.LogLevelSuccess, types.LogCategorySetup,
+			c.logExecutionEvent(ctx, types.LogLevelSuccess, types.LogCategorySetup,
 				"Function-execution relationships stored for replay", nil)
 		}
 	}
 
 	result.TotalTime = time.Since(startTime).Milliseconds()
 
+	if request.Deterministic {
+		configs := make([]types.APIConfiguration, 0, len(request.Configurations))
+		for _, variationResult := range result.Results {
+			if variationResult.Repetition <= 1 && variationResult.Response.CandidateIndex == 0 {
+				configs = append(configs, variationResult.Configuration)
+			}
+		}
+
+		fingerprint := DeterminismFingerprint(request, configs, c.config.APIKey == "")
+		result.ExecutionRun.Deterministic = true
+		result.ExecutionRun.DeterminismFingerprint = fingerprint
+
+		if err := c.recordDeterminism(ctx, executionRun.ID, fingerprint); err != nil {
+			c.logExecutionEvent(ctx, types.LogLevelWarn, types.LogCategoryError,
+				fmt.Sprintf("Failed to store determinism fingerprint: %v", err), nil)
+		} else {
+			c.logExecutionEvent(ctx, types.LogLevelInfo, types.LogCategoryCompletion,
+				fmt.Sprintf("Determinism fingerprint: %s", fingerprint), nil)
+		}
+	}
+
 	// Log completion
-	c.logExecutionEvent(types.LogLevelSuccess, types.LogCategoryCompletion,
+	c.logExecutionEvent(ctx, types.LogLevelSuccess, types.LogCategoryCompletion,
 		fmt.Sprintf("Execution completed in %dms - %d successful, %d failed",
 			result.TotalTime, result.SuccessCount, result.ErrorCount),
 		map[string]interface{}{
@@ -334,50 +481,202 @@ func (c *Client) ExecuteMultiVariation(ctx context.Context, userID string, reque
 			"errorCount":   result.ErrorCount,
 		})
 
+	// Check the run against its suite's SLO
+	if request.Suite != "" {
+		evaluation, status, err := c.TrackSLO(ctx, userID, request.Suite, result)
+		if err != nil {
+			c.logExecutionEvent(ctx, types.LogLevelWarn, types.LogCategoryError,
+				fmt.Sprintf("Failed to track SLO for suite %s: %v", request.Suite, err), nil)
+		}
+		result.SLO = evaluation
+		if status != nil && status.Alerting {
+			c.logExecutionEvent(ctx, types.LogLevelWarn, types.LogCategoryCompletion,
+				fmt.Sprintf("SLO alert for suite %s: burn rate %.2f (alert at %.2f)",
+					request.Suite, status.BurnRate, status.SLO.AlertBurnRate),
+				map[string]interface{}{
+					"runs":          status.Runs,
+					"compliantRuns": status.CompliantRuns,
+					"compliant":     evaluation.Compliant,
+				})
+		}
+	}
+
+	// Score every variation against the golden answer
+	if request.ExpectedAnswer != nil {
+		result.Evaluations = c.evaluateResults(ctx, userID, result, request.ExpectedAnswer, c.callEmbeddingAPI)
+		result.Accuracy = EvaluationAccuracy(result.Evaluations)
+		if err := c.storeEvaluationResults(ctx, userID, result.Evaluations); err != nil {
+			c.logExecutionEvent(ctx, types.LogLevelWarn, types.LogCategoryError,
+				fmt.Sprintf("Failed to store evaluation results: %v", err), nil)
+		}
+		for _, accuracy := range result.Accuracy {
+			c.logExecutionEvent(ctx, types.LogLevelInfo, types.LogCategoryCompletion,
+				fmt.Sprintf("Golden-answer accuracy for %s: %d/%d (%s match)",
+					accuracy.VariationName, accuracy.Passed, accuracy.Cases, request.ExpectedAnswer.MatchMode), nil)
+		}
+	}
+
 	// Always perform comparison for better user experience
-	c.logExecutionEvent(types.LogLevelInfo, types.LogCategoryExecution,
+	c.logExecutionEvent(ctx, types.LogLevelInfo, types.LogCategoryExecution,
 		"Starting comparison analysis", nil)
-	comparison, err := c.compareResults(ctx, result)
+	comparison, err := c.compareResults(ctx, userID, result, request.ComparisonConfig)
 	if err != nil {
 		// Log comparison error but don't fail the whole execution
-		fmt.Printf("❌ Warning: comparison failed: %v\n", err)
+		log.Printf("❌ Warning: comparison failed: %v", err)
 	} else {
-		fmt.Printf("✅ Comparison completed successfully: %s\n", comparison.ID)
+		log.Printf("✅ Comparison completed successfully: %s", comparison.ID)
 		result.Comparison = comparison
+		result.SweepReport = SummarizeSweep(result)
 
 		// Store comparison result in database
 		if err := c.StoreComparisonResult(ctx, userID, comparison); err != nil {
-			fmt.Printf("⚠️ Warning: failed to store comparison result: %v\n", err)
+			log.Printf("⚠️ Warning: failed to store comparison result: %v", err)
 		} else {
-			fmt.Printf("💾 Comparison result stored in database: %s\n", comparison.ID)
+			log.Printf("💾 Comparison result stored in database: %s", comparison.ID)
 		}
 	}
 
+	c.finishExecutionRun(ctx, executionRun.ID, nil)
+	result.ExecutionRun.Status = "completed"
 	return result, nil
 }
 
+// prepareConfiguration applies the run-wide settings of a request to one of its configurations:
+// its tools, function calling instruction, provider, safety policy and determinism
+func prepareConfiguration(config *types.APIConfiguration, request *types.MultiExecutionRequest) error {
+	// CRITICAL: Add function tools to configuration if function calling is enabled
+	if request.EnableFunctionCalling && len(request.FunctionTools) > 0 {
+		config.Tools = selectConfigurationTools(config, request.FunctionTools)
+	}
+
+	// Inherit the run-wide function calling instruction unless the configuration overrides it
+	if config.FunctionInstruction == "" && !config.DisableFunctionInstruction {
+		config.FunctionInstruction = request.FunctionInstruction
+		config.DisableFunctionInstruction = request.DisableFunctionInstruction
+	}
+
+	// Record the provider serving the configuration
+	config.Provider = types.ProviderForConfiguration(config)
+
+	// Translate the normalized safety policy into the provider's native settings
+	if err := applySafetyPolicy(config, request.SafetyPolicy); err != nil {
+		return fmt.Errorf("failed to apply safety policy: %w", err)
+	}
+
+	// Deterministic runs pin sampling and tool responses
+	if request.Deterministic {
+		seed := int32(0)
+		if request.Seed != nil {
+			seed = *request.Seed
+		}
+		applyDeterminism(config, seed)
+	}
+	return nil
+}
+
+// DefaultFunctionInstruction is prepended to tool-enabled prompts unless a run or configuration overrides it
+const DefaultFunctionInstruction = "You MUST use the available function tools to answer questions. When a user asks for information that can be obtained through these functions, you are REQUIRED to call the appropriate function. Do not respond with text saying you cannot access information - instead, call the function immediately. The functions are fully implemented and working."
+
+// resolveFunctionInstruction returns the instruction to prepend for a configuration, or "" when
+// disabled. The default one insists on a function call, so only ANY mode gets it.
+func resolveFunctionInstruction(config *types.APIConfiguration) string {
+	if config.DisableFunctionInstruction {
+		return ""
+	}
+	if config.FunctionInstruction != "" {
+		return config.FunctionInstruction
+	}
+	if toolChoiceMode(config) != types.ToolChoiceAny {
+		return ""
+	}
+	return DefaultFunctionInstruction
+}
+
+// selectConfigurationTools returns the subset of run-level tools a configuration should receive
+func selectConfigurationTools(config *types.APIConfiguration, functionTools []types.Tool) []types.Tool {
+	if config.DisableTools {
+		return nil
+	}
+	if len(config.ToolNames) == 0 {
+		return functionTools
+	}
+
+	allowed := make(map[string]bool, len(config.ToolNames))
+	for _, name := range config.ToolNames {
+		allowed[name] = true
+	}
+
+	var selected []types.Tool
+	for _, tool := range functionTools {
+		if allowed[tool.Name] {
+			selected = append(selected, tool)
+		}
+	}
+	return selected
+}
+
+// toolNames returns the names of the given tools
+func toolNames(tools []types.Tool) []string {
+	names := make([]string, len(tools))
+	for i, tool := range tools {
+		names[i] = tool.Name
+	}
+	return names
+}
+
 // executeSingleVariation executes a single variation and logs everything
-func (c *Client) executeSingleVariation(ctx context.Context, userID string, executionRunID string, config *types.APIConfiguration, prompt, context string) (*types.VariationResult, error) {
+func (c *Client) executeSingleVariation(ctx context.Context, userID string, executionRunID string, config *types.APIConfiguration, prompt, promptContext string, queued time.Duration) (*types.VariationResult, error) {
 	startTime := time.Now()
+	latency := &types.LatencyBreakdown{QueuedMs: queued.Milliseconds()}
 
-	// Create API request
-	apiRequest := &types.APIRequest{
-		ID:              uuid.New().String(),
-		ExecutionRunID:  executionRunID,
-		ConfigurationID: config.ID,
-		RequestType:     types.RequestTypeGenerate, // Default to generate for now
-		Prompt:          prompt,
-		Context:         context,
-		CreatedAt:       time.Now(),
-	}
+	// Retrieve document chunks into the context before the request is logged with it
+	chunks, retrievalErr := c.retrieveChunks(ctx, userID, config, prompt)
+	promptContext = retrievalContext(chunks, promptContext)
+	latency.RetrievalMs = time.Since(startTime).Milliseconds()
 
-	// Log request
-	if err := c.LogAPIRequest(ctx, userID, apiRequest); err != nil {
-		return nil, fmt.Errorf("failed to log API request: %w", err)
+	// Input guards see the retrieved chunks too, and the request is logged as sanitized
+	verdicts, prompt, promptContext, blocked := applyInputGuards(config.Guardrails, prompt, promptContext)
+	if findings := guardFindings(verdicts); findings != "" {
+		c.logExecutionEvent(ctx, types.LogLevelWarn, types.LogCategoryExecution,
+			fmt.Sprintf("Input guards triggered on %s: %s", config.VariationName, findings), nil)
 	}
 
-	// Execute the actual Gemini API call
-	apiResponse, err := c.callGeminiAPI(ctx, config, apiRequest)
+	// Create API request
+	apiRequest := &types.APIRequest{
+		ID:               uuid.New().String(),
+		ExecutionRunID:   executionRunID,
+		ConfigurationID:  config.ID,
+		RequestType:      types.RequestTypeGenerate, // Default to generate for now
+		Prompt:           prompt,
+		Context:          promptContext,
+		SystemPromptMode: systemPromptMode(config),
+		CreatedAt:        time.Now(),
+	}
+
+	// Bound the call so a slow model cannot stall the remaining variations
+	callCtx := ctx
+	timeout := c.variationTimeout(config)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	callCtx, functionCalls := withFunctionCallLog(callCtx)
+	callCtx, rawPayloads := withRawPayloadLog(callCtx)
+
+	// Execute the actual model call, unless retrieval already failed or a guard blocked the prompt
+	var apiResponse *types.APIResponse
+	err := retrievalErr
+	switch {
+	case err != nil:
+		err = fmt.Errorf("failed to retrieve document chunks: %w", err)
+	case blocked:
+		err = fmt.Errorf("%w: %s", ErrPromptBlocked, guardFindings(verdicts))
+	default:
+		callStart := time.Now()
+		apiResponse, err = c.callModelAPI(callCtx, config, apiRequest)
+		latency.ModelMs = time.Since(callStart).Milliseconds()
+	}
 	if err != nil {
 		// Log error response
 		apiResponse = &types.APIResponse{
@@ -388,23 +687,114 @@ func (c *Client) executeSingleVariation(ctx context.Context, userID string, exec
 			ResponseTimeMs: int32(time.Since(startTime).Milliseconds()),
 			CreatedAt:      time.Now(),
 		}
+		if variationTimedOut(ctx, err) {
+			apiResponse.ResponseStatus = types.ResponseStatusTimeout
+			apiResponse.ErrorMessage = fmt.Sprintf("variation timed out after %v: %v", timeout, err)
+			log.Printf("⏱️ Variation %s timed out after %v", config.VariationName, timeout)
+		}
+		if errors.Is(err, ErrPromptBlocked) {
+			apiResponse.ResponseStatus = types.ResponseStatusBlocked
+		}
+	} else {
+		// Output guards check the response before it is stored
+		outputVerdicts, responseText := applyOutputGuards(config.Guardrails, apiResponse.ResponseText)
+		if findings := guardFindings(outputVerdicts); findings != "" {
+			c.logExecutionEvent(ctx, types.LogLevelWarn, types.LogCategoryExecution,
+				fmt.Sprintf("Output guards triggered on %s: %s", config.VariationName, findings), nil)
+		}
+		apiResponse.ResponseText = responseText
+		verdicts = append(verdicts, outputVerdicts...)
+
+		// The other candidates are guarded the same way; their verdicts are logged, not stored
+		for i := range apiResponse.Candidates {
+			candidateVerdicts, candidateText := applyOutputGuards(config.Guardrails, apiResponse.Candidates[i].ResponseText)
+			if findings := guardFindings(candidateVerdicts); findings != "" {
+				c.logExecutionEvent(ctx, types.LogLevelWarn, types.LogCategoryExecution,
+					fmt.Sprintf("Output guards triggered on %s candidate %d: %s", config.VariationName, i+2, findings), nil)
+			}
+			apiResponse.Candidates[i].ResponseText = candidateText
+		}
+	}
+
+	attachCitations(apiResponse)
+	apiResponse.Latency = completeLatency(latency, apiResponse.Latency, time.Since(startTime))
+	for i := range apiResponse.Candidates {
+		apiResponse.Candidates[i].Latency = apiResponse.Latency
 	}
 
-	// Log response
-	if logErr := c.LogAPIResponse(ctx, userID, apiResponse); logErr != nil {
-		return nil, fmt.Errorf("failed to log API response: %w", logErr)
+	// Store the request, its function calls and the response together, then what refers to them
+	dbStart := time.Now()
+	calls := functionCalls.list()
+	if logErr := c.persistVariation(ctx, userID, apiRequest, apiResponse, calls); logErr != nil {
+		return nil, logErr
+	}
+	if err := c.storeRetrievedChunks(ctx, userID, apiRequest, chunks); err != nil && !errors.Is(err, ErrNoDatabase) {
+		log.Printf("⚠️ Warning: %v", err)
+	}
+	if err := c.storeGuardVerdicts(ctx, userID, apiRequest, verdicts); err != nil && !errors.Is(err, ErrNoDatabase) {
+		log.Printf("⚠️ Warning: %v", err)
+	}
+	if maxBytes, ok := rawPayloadLimit(ctx); ok {
+		if err := c.storeRawPayloads(ctx, userID, apiRequest, rawPayloads.list(), maxBytes); err != nil && !errors.Is(err, ErrNoDatabase) {
+			log.Printf("⚠️ Warning: %v", err)
+		}
 	}
+	// The stored breakdown was written before its own storage could be timed
+	apiResponse.Latency.DBMs = time.Since(dbStart).Milliseconds()
+	apiResponse.Latency.TotalMs += apiResponse.Latency.DBMs
 
 	return &types.VariationResult{
 		Configuration: *config,
 		Request:       *apiRequest,
 		Response:      *apiResponse,
 		ExecutionTime: time.Since(startTime).Milliseconds(),
+		FunctionCalls: calls,
+
+		RetrievedChunks: chunks,
+		GuardVerdicts:   verdicts,
 	}, err
 }
 
+// completeLatency fills in a variation's breakdown once its response is ready. A provider that
+// timed its own phases splits the model call into them; the time no phase accounts for is other.
+func completeLatency(latency, provider *types.LatencyBreakdown, total time.Duration) *types.LatencyBreakdown {
+	if provider != nil {
+		latency.ModelMs, latency.FunctionMs, latency.FollowUpMs = provider.ModelMs, provider.FunctionMs, provider.FollowUpMs
+	}
+	latency.TotalMs = total.Milliseconds()
+	latency.OtherMs = max(latency.TotalMs-latency.RetrievalMs-latency.DBMs-latency.ModelMs-latency.FunctionMs-latency.FollowUpMs, 0)
+	return latency
+}
+
+// variationTimeout returns how long a variation may run: its TimeoutMs, else the client's TimeoutSecs,
+// or 0 for no limit
+func (c *Client) variationTimeout(config *types.APIConfiguration) time.Duration {
+	if config.TimeoutMs > 0 {
+		return time.Duration(config.TimeoutMs) * time.Millisecond
+	}
+	if c.config != nil && c.config.TimeoutSecs > 0 {
+		return time.Duration(c.config.TimeoutSecs) * time.Second
+	}
+	return 0
+}
+
+// variationTimedOut reports whether a variation failed on its own deadline rather than the run's
+// context ending
+func variationTimedOut(ctx context.Context, err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil
+}
+
 // callGeminiAPI makes the actual API call to Gemini
 func (c *Client) callGeminiAPI(ctx context.Context, config *types.APIConfiguration, request *types.APIRequest) (*types.APIResponse, error) {
+	if config.Backend == types.BackendVertex {
+		if c.config.ProjectID == "" {
+			log.Printf("No Google Cloud project configured for Vertex AI, using mock responses")
+			return c.callMockGeminiAPI(ctx, config, request)
+		}
+		log.Printf("Using Vertex AI for model: %s in project %s", config.ModelName, c.config.ProjectID)
+		return c.callGeminiRestAPI(ctx, config, request)
+	}
+
 	// Check if we have an API key available
 	if c.config.APIKey == "" {
 		log.Printf("No API key available, using mock responses")
@@ -431,10 +821,19 @@ func (c *Client) callMockGeminiAPI(ctx context.Context, config *types.APIConfigu
 		CreatedAt:      time.Now(),
 	}
 
+	// Structured configurations get a placeholder that matches their schema
+	if wantsStructuredOutput(config) {
+		value := mockStructuredValue(config.ResponseSchema)
+		if value == nil {
+			value = map[string]interface{}{"response": response.ResponseText}
+		}
+		responseText, _ := json.Marshal(value)
+		response.ResponseText = string(responseText)
+	}
+
 	return response, nil
 }
 
-// callGeminiRestAPI provides a REST API fallback when the Go SDK fails
 // sanitizeToolParameters removes fields that are not supported by the Gemini API
 func sanitizeToolParameters(params map[string]interface{}) map[string]interface{} {
 	if params == nil {
@@ -509,185 +908,138 @@ func sanitizePropertySchema(prop map[string]interface{}) map[string]interface{}
 	return sanitized
 }
 
-func (c *Client) callGeminiRestAPI(ctx context.Context, config *types.APIConfiguration, request *types.APIRequest) (*types.APIResponse, error) {
-	startTime := time.Now()
-
-	fmt.Printf("\n🚀 USING REST API IMPLEMENTATION - Model: '%s'\n", config.ModelName)
-	log.Printf("🚀 REST API CALLED - Model: '%s', API Key: %s...", config.ModelName, c.config.APIKey[:10])
-
-	if config.ModelName == "" {
-		log.Printf("❌ ERROR: Model name is empty!")
-		return &types.APIResponse{
-			ID:             uuid.New().String(),
-			RequestID:      request.ID,
-			ResponseStatus: types.ResponseStatusError,
-			ErrorMessage:   "Model name is empty",
-			ResponseTimeMs: int32(time.Since(startTime).Milliseconds()),
-			CreatedAt:      time.Now(),
-		}, nil
-	}
+// geminiGenerationConfig builds the generation config of a configuration's first call. Gemini
+// rejects a JSON response format alongside tools, so tool-enabled configurations only ask for it on
+// the follow-up call that answers with the function result.
+func geminiGenerationConfig(config *types.APIConfiguration) *gemini.GenerationConfig {
+	generationConfig := &gemini.GenerationConfig{
+		Temperature:     config.Temperature,
+		MaxOutputTokens: config.MaxTokens,
+		TopP:            config.TopP,
+		TopK:            config.TopK,
+		Seed:            config.Seed,
+		CandidateCount:  config.CandidateCount,
+	}
+	if len(config.Tools) == 0 {
+		applyStructuredOutput(config, generationConfig)
+	}
+	applyThinking(config, generationConfig)
+	if generationConfig.IsEmpty() {
+		return nil
+	}
+	return generationConfig
+}
 
-	// Use the same API key from the client configuration
-	apiKey := c.config.APIKey
-	if apiKey == "" {
-		log.Printf("❌ No API key available for REST API call")
-		return c.callMockGeminiAPI(ctx, config, request)
+// geminiFollowUpConfig builds the generation config of the call that answers with a function result
+func geminiFollowUpConfig(config *types.APIConfiguration) *gemini.GenerationConfig {
+	generationConfig := &gemini.GenerationConfig{
+		Temperature: config.Temperature,
+		Seed:        config.Seed,
 	}
-
-	log.Printf("✅ Using API key: %s... for model: '%s'", apiKey[:10], config.ModelName)
-
-	// Build the REST API request - start with the base prompt
-	prompt := request.Prompt
-	if request.Context != "" {
-		prompt = fmt.Sprintf("%s\n\nContext: %s", prompt, request.Context)
+	if config.Deterministic {
+		generationConfig.TopK = config.TopK
 	}
-
-	// Prepare the final prompt
-	finalPrompt := prompt
-	if config.SystemPrompt != "" {
-		finalPrompt = config.SystemPrompt + "\n\n" + prompt
+	applyStructuredOutput(config, generationConfig)
+	applyThinking(config, generationConfig)
+	if generationConfig.IsEmpty() {
+		return nil
 	}
+	return generationConfig
+}
 
-	// Add function calling instruction if tools are available
-	if len(config.Tools) > 0 {
-		functionInstruction := "You MUST use the available function tools to answer questions. When a user asks for information that can be obtained through these functions, you are REQUIRED to call the appropriate function. Do not respond with text saying you cannot access information - instead, call the function immediately. The functions are fully implemented and working."
-		finalPrompt = functionInstruction + "\n\n" + finalPrompt
-		log.Printf("🔧 Added function calling instruction to prompt")
+// geminiAPI returns the client's Gemini REST client, creating one from the API key when the client
+// was built without it
+func (c *Client) geminiAPI() *gemini.Client {
+	if c.geminiClient != nil {
+		return c.geminiClient
 	}
+	client := gemini.NewClient(c.config.APIKey)
+	client.SetTransport(c.providerTransport())
+	return client
+}
 
-	log.Printf("REST API - Final prompt: %s", finalPrompt[:min(100, len(finalPrompt))])
+// transport returns the transport set with SetHTTPTransport, or nil for the default one
+func (c *Client) transport() http.RoundTripper {
+	c.overridesMutex.RLock()
+	defer c.overridesMutex.RUnlock()
+	return c.httpTransport
+}
 
-	requestBody := map[string]interface{}{
-		"contents": []map[string]interface{}{
-			{
-				"parts": []map[string]interface{}{
-					{"text": finalPrompt},
-				},
-			},
-		},
+// SetHTTPTransport sends Gemini, Ollama and weather API calls through rt, such as a vcr.Recorder
+// that replays recorded fixtures in tests; nil restores the default transport
+func (c *Client) SetHTTPTransport(rt http.RoundTripper) {
+	c.overridesMutex.Lock()
+	defer c.overridesMutex.Unlock()
+	c.httpTransport = rt
+	if c.geminiClient != nil {
+		c.geminiClient.SetTransport(&payloadTransport{base: c.guardedTransport(rt)})
 	}
+}
 
-	// Add generation config if specified
-	generationConfig := make(map[string]interface{})
-	if config.Temperature != nil {
-		generationConfig["temperature"] = *config.Temperature
-	}
-	if config.MaxTokens != nil {
-		generationConfig["maxOutputTokens"] = *config.MaxTokens
-	}
-	if config.TopP != nil {
-		generationConfig["topP"] = *config.TopP
-	}
-	if config.TopK != nil {
-		generationConfig["topK"] = *config.TopK
+// geminiAPIFor returns the client a configuration's backend is called through
+func (c *Client) geminiAPIFor(config *types.APIConfiguration) (*gemini.Client, error) {
+	if config.Backend == types.BackendVertex {
+		return c.vertexAPI()
 	}
-	if len(generationConfig) > 0 {
-		requestBody["generationConfig"] = generationConfig
-	}
-
-	// Add tools for function calling if provided
-	if len(config.Tools) > 0 {
-		log.Printf("🔧 Adding %d tools to Gemini request", len(config.Tools))
-		tools := make([]map[string]interface{}, len(config.Tools))
-		for i, tool := range config.Tools {
-			log.Printf("🔧 Tool %d: %s - %s", i+1, tool.Name, tool.Description)
-
-			// Sanitize the parameters to remove unsupported fields
-			sanitizedParams := sanitizeToolParameters(tool.Parameters)
-
-			toolDeclaration := map[string]interface{}{
-				"functionDeclarations": []map[string]interface{}{
-					{
-						"name":        tool.Name,
-						"description": tool.Description,
-						"parameters":  sanitizedParams,
-					},
-				},
-			}
-			tools[i] = toolDeclaration
-			log.Printf("🔧 Tool declaration (sanitized): %+v", toolDeclaration)
-		}
-		requestBody["tools"] = tools
+	return c.geminiAPI(), nil
+}
 
-		// Add tool configuration to make function calling more aggressive
-		requestBody["toolConfig"] = map[string]interface{}{
-			"functionCallingConfig": map[string]interface{}{
-				"mode": "ANY",
-			},
+// vertexAPI returns the Vertex AI client for the client's project and region, finding the
+// Application Default Credentials on first use
+func (c *Client) vertexAPI() (*gemini.Client, error) {
+	c.vertexOnce.Do(func() {
+		c.vertexClient, c.vertexErr = gemini.NewVertexClient(context.Background(), c.config.ProjectID, c.config.Region)
+		if c.vertexErr == nil {
+			c.vertexClient.SetTransport(c.providerTransport())
 		}
+	})
+	return c.vertexClient, c.vertexErr
+}
 
-		log.Printf("🔧 Final tools in request body: %+v", tools)
-		log.Printf("🔧 Added toolConfig with mode: ANY")
-	} else {
-		log.Printf("⚠️  No tools provided to Gemini API call")
-	}
-
-	// Create request body
-	reqBodyBytes, err := json.Marshal(requestBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %w", err)
-	}
-
-	log.Printf("🔧 Complete Gemini API request body: %s", string(reqBodyBytes))
+func (c *Client) callGeminiRestAPI(ctx context.Context, config *types.APIConfiguration, request *types.APIRequest) (*types.APIResponse, error) {
+	startTime := time.Now()
 
-	// Make HTTP request to Gemini REST API
-	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent", config.ModelName)
-	log.Printf("REST API - URL: %s", url)
+	log.Printf("🚀 USING REST API IMPLEMENTATION - Model: '%s'", config.ModelName)
 
-	// Create HTTP request
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(reqBodyBytes))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	if config.ModelName == "" {
+		log.Printf("❌ ERROR: Model name is empty!")
+		return &types.APIResponse{
+			ID:             uuid.New().String(),
+			RequestID:      request.ID,
+			ResponseStatus: types.ResponseStatusError,
+			ErrorMessage:   "Model name is empty",
+			ResponseTimeMs: int32(time.Since(startTime).Milliseconds()),
+			CreatedAt:      time.Now(),
+		}, nil
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-goog-api-key", apiKey)
+	// Use the same API key from the client configuration; Vertex AI authorizes with ADC instead
+	if config.Backend != types.BackendVertex {
+		apiKey := c.config.APIKey
+		if apiKey == "" {
+			log.Printf("❌ No API key available for REST API call")
+			return c.callMockGeminiAPI(ctx, config, request)
+		}
 
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Printf("REST API - HTTP request error: %v", err)
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		log.Printf("✅ Using API key: %s... for model: '%s'", apiKey[:min(10, len(apiKey))], config.ModelName)
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
+	api, err := c.geminiAPIFor(config)
 	if err != nil {
-		log.Printf("REST API - Read response error: %v", err)
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	log.Printf("🔧 Complete Gemini API response: %s", string(body))
-
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("REST API - HTTP error %d: %s", resp.StatusCode, string(body))
-		return nil, fmt.Errorf("HTTP error %d: %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("failed to create Vertex AI client: %w", err)
 	}
 
-	// Parse response
-	var geminiResp struct {
-		Candidates []struct {
-			Content struct {
-				Parts []struct {
-					Text         string `json:"text,omitempty"`
-					FunctionCall struct {
-						Name string                 `json:"name"`
-						Args map[string]interface{} `json:"args"`
-					} `json:"functionCall,omitempty"`
-				} `json:"parts"`
-			} `json:"content"`
-			FinishReason string `json:"finishReason"`
-		} `json:"candidates"`
-		UsageMetadata struct {
-			PromptTokenCount     int `json:"promptTokenCount"`
-			CandidatesTokenCount int `json:"candidatesTokenCount"`
-			TotalTokenCount      int `json:"totalTokenCount"`
-		} `json:"usageMetadata"`
+	generateRequest, finalPrompt := geminiRequest(config, request)
+	if cached := c.cachedGeminiRequest(ctx, api, config, request); cached != nil {
+		generateRequest = cached
 	}
 
-	if err := json.Unmarshal(body, &geminiResp); err != nil {
-		log.Printf("REST API - JSON parse error: %v", err)
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	latency := &types.LatencyBreakdown{}
+	modelStart := time.Now()
+	geminiResp, err := api.GenerateContent(ctx, config.ModelName, generateRequest)
+	latency.ModelMs = time.Since(modelStart).Milliseconds()
+	if err != nil {
+		log.Printf("REST API - Request error: %v", err)
+		return nil, err
 	}
 
 	log.Printf("🔧 Parsed response - %d candidates", len(geminiResp.Candidates))
@@ -696,78 +1048,38 @@ func (c *Client) callGeminiRestAPI(ctx context.Context, config *types.APIConfigu
 	var responseText string
 	var finishReason string
 	var functionCallResponse map[string]interface{}
+	var thoughts []string
 
 	if len(geminiResp.Candidates) > 0 {
 		candidate := geminiResp.Candidates[0]
 		finishReason = candidate.FinishReason
 
 		for _, part := range candidate.Content.Parts {
+			// Keep thinking models' thought summaries apart from the answer
+			if part.Thought {
+				if part.Text != "" {
+					thoughts = append(thoughts, part.Text)
+				}
+				continue
+			}
+
 			// Handle text response
 			if part.Text != "" {
 				responseText = part.Text
 			}
 
 			// Handle function call
-			if part.FunctionCall.Name != "" {
-				c.logExecutionEvent(types.LogLevelInfo, types.LogCategoryFunctionCall,
-					fmt.Sprintf("Function call detected: %s", part.FunctionCall.Name),
-					map[string]interface{}{
-						"functionName": part.FunctionCall.Name,
-						"arguments":    part.FunctionCall.Args,
-					})
-
-				// Execute the function call
-				startTime := time.Now()
-				functionResult, err := c.executeFunctionCall(ctx, part.FunctionCall.Name, part.FunctionCall.Args)
-				executionTime := time.Since(startTime).Milliseconds()
-
-				// Create function call record for logging
-				functionCall := &types.FunctionCall{
-					ID:               uuid.New().String(),
-					RequestID:        request.ID,
-					FunctionName:     part.FunctionCall.Name,
-					FunctionArgs:     part.FunctionCall.Args,
-					FunctionResponse: functionResult,
-					ExecutionTimeMs:  int32(executionTime),
-					CreatedAt:        time.Now(),
-				}
-
-				if err != nil {
-					c.logExecutionEvent(types.LogLevelError, types.LogCategoryFunctionCall,
-						fmt.Sprintf("Function execution failed: %v", err),
-						map[string]interface{}{
-							"functionName": part.FunctionCall.Name,
-							"error":        err.Error(),
-						})
-					functionCall.ExecutionStatus = "error"
-					functionCall.ErrorDetails = err.Error()
-					// Return error response but don't fail completely
-					functionResult = map[string]interface{}{
-						"error":  err.Error(),
-						"status": "failed",
-					}
-					functionCall.FunctionResponse = functionResult
-				} else {
-					c.logExecutionEvent(types.LogLevelSuccess, types.LogCategoryFunctionCall,
-						fmt.Sprintf("Function executed successfully: %s", part.FunctionCall.Name),
-						map[string]interface{}{
-							"functionName":  part.FunctionCall.Name,
-							"executionTime": executionTime,
-							"resultPreview": fmt.Sprintf("%v", functionResult)[:min(100, len(fmt.Sprintf("%v", functionResult)))],
-						})
-					functionCall.ExecutionStatus = "success"
-				}
-
-				// Log function call to database
-				if logErr := c.LogFunctionCall(ctx, functionCall); logErr != nil {
-					c.logExecutionEvent(types.LogLevelWarn, types.LogCategoryError,
-						fmt.Sprintf("Failed to log function call to database: %v", logErr), nil)
-				}
+			if part.FunctionCall != nil && part.FunctionCall.Name != "" {
+				functionStart := time.Now()
+				functionResult := c.runFunctionCall(ctx, config, request, part.FunctionCall.Name, part.FunctionCall.Args)
+				latency.FunctionMs = time.Since(functionStart).Milliseconds()
 
 				// Send function result back to Gemini to get final response
+				followUpStart := time.Now()
 				finalResponse, err := c.sendFunctionResultToGemini(ctx, config, request, part.FunctionCall.Name, functionResult, finalPrompt)
+				latency.FollowUpMs = time.Since(followUpStart).Milliseconds()
 				if err != nil {
-					c.logExecutionEvent(types.LogLevelError, types.LogCategoryAPICall,
+					c.logExecutionEvent(ctx, types.LogLevelError, types.LogCategoryAPICall,
 						fmt.Sprintf("Failed to get final response from Gemini: %v", err),
 						map[string]interface{}{
 							"functionName": part.FunctionCall.Name,
@@ -776,7 +1088,7 @@ func (c *Client) callGeminiRestAPI(ctx context.Context, config *types.APIConfigu
 					// Fall back to just indicating the function was called
 					responseText = fmt.Sprintf("I called the %s function with the provided parameters and received the result.", part.FunctionCall.Name)
 				} else {
-					c.logExecutionEvent(types.LogLevelSuccess, types.LogCategoryAPICall,
+					c.logExecutionEvent(ctx, types.LogLevelSuccess, types.LogCategoryAPICall,
 						"Got final response from Gemini after function execution",
 						map[string]interface{}{
 							"functionName":    part.FunctionCall.Name,
@@ -815,6 +1127,13 @@ func (c *Client) callGeminiRestAPI(ctx context.Context, config *types.APIConfigu
 		"completion_tokens": geminiResp.UsageMetadata.CandidatesTokenCount,
 		"total_tokens":      geminiResp.UsageMetadata.TotalTokenCount,
 	}
+	if generateRequest.CachedContent != "" {
+		usageMetadata["cached_tokens"] = geminiResp.UsageMetadata.CachedContentTokenCount
+		usageMetadata["cached_content"] = generateRequest.CachedContent
+	}
+	if geminiResp.UsageMetadata.ThoughtsTokenCount > 0 {
+		usageMetadata["thoughts_tokens"] = geminiResp.UsageMetadata.ThoughtsTokenCount
+	}
 
 	response := &types.APIResponse{
 		ID:             uuid.New().String(),
@@ -824,6 +1143,8 @@ func (c *Client) callGeminiRestAPI(ctx context.Context, config *types.APIConfigu
 		UsageMetadata:  usageMetadata,
 		FinishReason:   finishReason,
 		ResponseTimeMs: int32(time.Since(startTime).Milliseconds()),
+		Latency:        latency,
+		ThoughtSummary: strings.Join(thoughts, "\n\n"),
 		CreatedAt:      time.Now(),
 	}
 
@@ -832,12 +1153,176 @@ func (c *Client) callGeminiRestAPI(ctx context.Context, config *types.APIConfigu
 		response.FunctionCallResponse = functionCallResponse
 	}
 
+	// Other candidates become responses of their own
+	for i := 1; i < len(geminiResp.Candidates); i++ {
+		response.Candidates = append(response.Candidates, geminiCandidateResponse(geminiResp.Candidates[i], i, response))
+	}
+
 	return response, nil
 }
 
-// executeFunctionCall executes a function call and returns the result
-func (c *Client) executeFunctionCall(ctx context.Context, functionName string, args map[string]interface{}) (map[string]interface{}, error) {
-	c.logExecutionEvent(types.LogLevelInfo, types.LogCategoryFunctionCall,
+// geminiRequest builds the request of a configuration's first Gemini call and the final prompt it
+// sends: the prompt and its context, behind the inline system prompt and the function calling
+// instruction, with the configuration's sanitized tools
+func geminiRequest(config *types.APIConfiguration, request *types.APIRequest) (*gemini.GenerateContentRequest, string) {
+	// Start with the base prompt
+	prompt := request.Prompt
+	if request.Context != "" {
+		prompt = fmt.Sprintf("%s\n\nContext: %s", prompt, request.Context)
+	}
+
+	// Prepare the final prompt; the system prompt only joins it in inline mode
+	finalPrompt := prompt
+	if systemPromptMode(config) == types.SystemPromptModeInline {
+		finalPrompt = config.SystemPrompt + "\n\n" + prompt
+	}
+
+	// Add function calling instruction if tools are available
+	if functionInstruction := resolveFunctionInstruction(config); len(config.Tools) > 0 && functionInstruction != "" {
+		finalPrompt = functionInstruction + "\n\n" + finalPrompt
+		log.Printf("🔧 Added function calling instruction to prompt")
+	} else if len(config.Tools) > 0 {
+		log.Printf("🔧 Function calling instruction disabled for configuration: %s", config.VariationName)
+	}
+
+	log.Printf("REST API - Final prompt: %s", finalPrompt[:min(100, len(finalPrompt))])
+
+	generateRequest := &gemini.GenerateContentRequest{
+		Contents:          []gemini.Content{gemini.UserContent(finalPrompt)},
+		SystemInstruction: systemInstruction(config),
+		GenerationConfig:  geminiGenerationConfig(config),
+		SafetySettings:    geminiSafetySettings(config.SafetySettings),
+	}
+
+	// Add tools for function calling if provided
+	if len(config.Tools) > 0 {
+		log.Printf("🔧 Adding %d tools to Gemini request", len(config.Tools))
+		for i, tool := range config.Tools {
+			log.Printf("🔧 Tool %d: %s - %s", i+1, tool.Name, tool.Description)
+			generateRequest.Tools = append(generateRequest.Tools, gemini.Tool{
+				FunctionDeclarations: []gemini.FunctionDeclaration{{
+					Name:        tool.Name,
+					Description: tool.Description,
+					Parameters:  sanitizeToolParameters(tool.Parameters),
+				}},
+			})
+		}
+
+		generateRequest.ToolConfig = geminiToolConfig(config)
+		log.Printf("🔧 Added toolConfig with mode: %s", generateRequest.ToolConfig.FunctionCallingConfig.Mode)
+	} else {
+		log.Printf("⚠️  No tools provided to Gemini API call")
+	}
+
+	return generateRequest, finalPrompt
+}
+
+// runFunctionCall executes a function the model asked for and records the call. Deterministic runs
+// use pinned responses and replays use recorded ones; a failed call returns its error as the result.
+func (c *Client) runFunctionCall(ctx context.Context, config *types.APIConfiguration, request *types.APIRequest, name string, args map[string]interface{}) map[string]interface{} {
+	c.logExecutionEvent(ctx, types.LogLevelInfo, types.LogCategoryFunctionCall,
+		fmt.Sprintf("Function call detected: %s", name),
+		map[string]interface{}{
+			"functionName": name,
+			"arguments":    args,
+		})
+
+	// Execute the function call
+	startTime := time.Now()
+	var functionResult map[string]interface{}
+	var usedMockData bool
+	var err error
+	if config.Deterministic {
+		functionResult = pinnedToolResponse(config.Tools, name, args)
+		usedMockData = true
+	} else if config.Replay {
+		// Replays never call functions; recorded responses stand in for live data
+		functionResult, err = recordedFunctionResponse(config.RecordedFunctionCalls, name, args)
+		usedMockData = true
+	} else {
+		functionResult, usedMockData, err = c.executeFunctionCall(ctx, request.ExecutionRunID, name, args)
+	}
+	executionTime := time.Since(startTime).Milliseconds()
+
+	// Create function call record for logging
+	functionCall := &types.FunctionCall{
+		ID:               uuid.New().String(),
+		RequestID:        request.ID,
+		FunctionName:     name,
+		FunctionArgs:     args,
+		FunctionResponse: functionResult,
+		ExecutionTimeMs:  int32(executionTime),
+		UsedMockData:     usedMockData,
+		CreatedAt:        time.Now(),
+	}
+
+	if err != nil {
+		c.logExecutionEvent(ctx, types.LogLevelError, types.LogCategoryFunctionCall,
+			fmt.Sprintf("Function execution failed: %v", err),
+			map[string]interface{}{
+				"functionName": name,
+				"error":        err.Error(),
+			})
+		functionCall.ExecutionStatus = "error"
+		functionCall.ErrorDetails = err.Error()
+		// Return error response but don't fail completely
+		functionResult = map[string]interface{}{
+			"error":  err.Error(),
+			"status": "failed",
+		}
+		functionCall.FunctionResponse = functionResult
+	} else {
+		c.logExecutionEvent(ctx, types.LogLevelSuccess, types.LogCategoryFunctionCall,
+			fmt.Sprintf("Function executed successfully: %s", name),
+			map[string]interface{}{
+				"functionName":  name,
+				"executionTime": executionTime,
+				"resultPreview": fmt.Sprintf("%v", functionResult)[:min(100, len(fmt.Sprintf("%v", functionResult)))],
+			})
+		functionCall.ExecutionStatus = "success"
+	}
+
+	// The call is stored with the variation's response, or straight away outside of a variation
+	if !recordFunctionCall(ctx, functionCall) {
+		if logErr := c.LogFunctionCall(ctx, functionCall); logErr != nil {
+			c.logExecutionEvent(ctx, types.LogLevelWarn, types.LogCategoryError,
+				fmt.Sprintf("Failed to log function call to database: %v", logErr), nil)
+		}
+	}
+
+	return functionResult
+}
+
+// executeFunctionCall executes a function call and returns the result, returning the stored mock
+// response instead when the execution's function config selects mock mode. Live calls of a
+// function configured for the execution run within its definition's timeout.
+func (c *Client) executeFunctionCall(ctx context.Context, executionRunID, functionName string, args map[string]interface{}) (map[string]interface{}, bool, error) {
+	if executionRunID != "" {
+		functionConfig, err := c.executionFunctionConfig(ctx, executionRunID, functionName)
+		if err != nil {
+			log.Printf("⚠️ Failed to load function config for %s: %v", functionName, err)
+		} else if functionConfig != nil && functionConfig.useMock {
+			c.logExecutionEvent(ctx, types.LogLevelInfo, types.LogCategoryFunctionCall,
+				fmt.Sprintf("Using mock response for function: %s", functionName),
+				map[string]interface{}{
+					"functionName": functionName,
+					"args":         args,
+				})
+			return functionConfig.mockResponse, true, nil
+		} else if functionConfig != nil {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, functionConfig.timeout)
+			defer cancel()
+		}
+	}
+
+	result, err := c.callFunction(ctx, functionName, args)
+	return result, false, err
+}
+
+// callFunction runs a function's built-in handler
+func (c *Client) callFunction(ctx context.Context, functionName string, args map[string]interface{}) (map[string]interface{}, error) {
+	c.logExecutionEvent(ctx, types.LogLevelInfo, types.LogCategoryFunctionCall,
 		fmt.Sprintf("Executing function: %s", functionName),
 		map[string]interface{}{
 			"functionName": functionName,
@@ -848,7 +1333,7 @@ func (c *Client) executeFunctionCall(ctx context.Context, functionName string, a
 	if functionName == "get_current_weather" {
 		location, ok := args["location"].(string)
 		if !ok {
-			c.logExecutionEvent(types.LogLevelError, types.LogCategoryFunctionCall,
+			c.logExecutionEvent(ctx, types.LogLevelError, types.LogCategoryFunctionCall,
 				"Weather function failed: location parameter missing or invalid", nil)
 			return nil, fmt.Errorf("location parameter missing or invalid")
 		}
@@ -856,7 +1341,7 @@ func (c *Client) executeFunctionCall(ctx context.Context, functionName string, a
 		// Call real weather API
 		result, err := c.callWeatherAPI(ctx, location, c.config.OpenWeatherAPIKey)
 		if err != nil {
-			c.logExecutionEvent(types.LogLevelError, types.LogCategoryFunctionCall,
+			c.logExecutionEvent(ctx, types.LogLevelError, types.LogCategoryFunctionCall,
 				fmt.Sprintf("Weather API call failed: %v", err),
 				map[string]interface{}{
 					"location": location,
@@ -873,10 +1358,10 @@ func (c *Client) executeFunctionCall(ctx context.Context, functionName string, a
 				"description": fmt.Sprintf("Current weather in %s: 72°F, sunny with clear skies (fallback data)", location),
 				"error":       "Real weather data unavailable, showing fallback data",
 			}
-			c.logExecutionEvent(types.LogLevelWarn, types.LogCategoryFunctionCall,
+			c.logExecutionEvent(ctx, types.LogLevelWarn, types.LogCategoryFunctionCall,
 				fmt.Sprintf("Using fallback weather data for %s", location), nil)
 		} else {
-			c.logExecutionEvent(types.LogLevelSuccess, types.LogCategoryFunctionCall,
+			c.logExecutionEvent(ctx, types.LogLevelSuccess, types.LogCategoryFunctionCall,
 				fmt.Sprintf("Weather function executed successfully for %s", location),
 				map[string]interface{}{
 					"location": location,
@@ -905,8 +1390,18 @@ func (c *Client) executeFunctionCall(ctx context.Context, functionName string, a
 			}
 		}
 
+		// Values are passed as query parameters ($name) rather than spliced into the query
+		parameters, _ := args["parameters"].(map[string]interface{})
+
+		// Rejected queries are reported to the model rather than answered with mock data
+		cypher, err := validateCypherQuery(query)
+		if err != nil {
+			log.Printf("🚫 Neo4j query rejected: %v", err)
+			return nil, err
+		}
+
 		// Call Neo4j query function
-		result, err := c.callNeo4jAPI(ctx, query, limit)
+		result, err := c.callNeo4jAPI(ctx, cypher, limit, parameters)
 		if err != nil {
 			log.Printf("❌ Neo4j query failed: %v", err)
 			// Fallback to mock data if Neo4j call fails
@@ -933,6 +1428,41 @@ func (c *Client) executeFunctionCall(ctx context.Context, functionName string, a
 		return result, nil
 	}
 
+	// Handle the SQL query function
+	if functionName == sqlQueryFunctionName {
+		query, ok := args["query"].(string)
+		if !ok {
+			return nil, fmt.Errorf("query parameter missing or invalid")
+		}
+
+		// Get limit parameter (optional, default to 100)
+		limit := defaultSQLQueryRows
+		if limitFloat, ok := args["limit"].(float64); ok {
+			limit = int(limitFloat)
+			if limit < 1 || limit > maxSQLQueryRows {
+				limit = defaultSQLQueryRows // Reset to default if out of bounds
+			}
+		}
+
+		result, err := c.callSQLQuery(ctx, query, limit)
+		if err != nil {
+			log.Printf("❌ SQL query failed: %v", err)
+			return nil, err
+		}
+		return result, nil
+	}
+
+	// Handle the web search function
+	if functionName == webSearchFunctionName {
+		result, err := c.callWebSearch(ctx, args)
+		if err != nil {
+			log.Printf("❌ Web search failed: %v", err)
+			return nil, err
+		}
+		log.Printf("✅ Web search executed: %s", result["query"])
+		return result, nil
+	}
+
 	// For other functions, return a generic success response
 	return map[string]interface{}{
 		"status":  "success",
@@ -944,7 +1474,7 @@ func (c *Client) executeFunctionCall(ctx context.Context, functionName string, a
 // callWeatherAPI makes a real API call to OpenWeatherMap API
 func (c *Client) callWeatherAPI(ctx context.Context, location string, apiKey string) (map[string]interface{}, error) {
 	if apiKey == "" {
-		c.logExecutionEvent(types.LogLevelError, types.LogCategoryAPICall,
+		c.logExecutionEvent(ctx, types.LogLevelError, types.LogCategoryAPICall,
 			"OpenWeather API key not provided", nil)
 		return nil, fmt.Errorf("OpenWeather API key not provided")
 	}
@@ -958,7 +1488,7 @@ func (c *Client) callWeatherAPI(ctx context.Context, location string, apiKey str
 
 	apiURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
 
-	c.logExecutionEvent(types.LogLevelInfo, types.LogCategoryAPICall,
+	c.logExecutionEvent(ctx, types.LogLevelInfo, types.LogCategoryAPICall,
 		fmt.Sprintf("Calling OpenWeatherMap API for location: %s", location),
 		map[string]interface{}{
 			"location":     location,
@@ -969,7 +1499,7 @@ func (c *Client) callWeatherAPI(ctx context.Context, location string, apiKey str
 	// Create HTTP request with timeout
 	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
 	if err != nil {
-		c.logExecutionEvent(types.LogLevelError, types.LogCategoryAPICall,
+		c.logExecutionEvent(ctx, types.LogLevelError, types.LogCategoryAPICall,
 			fmt.Sprintf("Failed to create weather API request: %v", err), nil)
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -979,12 +1509,13 @@ func (c *Client) callWeatherAPI(ctx context.Context, location string, apiKey str
 
 	// Make the API call
 	client := &http.Client{
-		Timeout: 10 * time.Second,
+		Timeout:   10 * time.Second,
+		Transport: c.guardedTransport(c.transport()),
 	}
 
 	resp, err := client.Do(req)
 	if err != nil {
-		c.logExecutionEvent(types.LogLevelError, types.LogCategoryAPICall,
+		c.logExecutionEvent(ctx, types.LogLevelError, types.LogCategoryAPICall,
 			fmt.Sprintf("Weather API request failed: %v", err),
 			map[string]interface{}{
 				"location": location,
@@ -997,7 +1528,7 @@ func (c *Client) callWeatherAPI(ctx context.Context, location string, apiKey str
 	// Read response body
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		c.logExecutionEvent(types.LogLevelError, types.LogCategoryAPICall,
+		c.logExecutionEvent(ctx, types.LogLevelError, types.LogCategoryAPICall,
 			fmt.Sprintf("Failed to read weather API response: %v", err), nil)
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
@@ -1006,7 +1537,7 @@ func (c *Client) callWeatherAPI(ctx context.Context, location string, apiKey str
 	if resp.StatusCode != 200 {
 		// Provide helpful suggestions based on the error
 		suggestion := c.getLocationSuggestion(location, resp.StatusCode, string(body))
-		c.logExecutionEvent(types.LogLevelError, types.LogCategoryAPICall,
+		c.logExecutionEvent(ctx, types.LogLevelError, types.LogCategoryAPICall,
 			fmt.Sprintf("Weather API returned status: %d", resp.StatusCode),
 			map[string]interface{}{
 				"location":     location,
@@ -1034,7 +1565,7 @@ func (c *Client) callWeatherAPI(ctx context.Context, location string, apiKey str
 	}
 
 	if err := json.Unmarshal(body, &weatherResp); err != nil {
-		c.logExecutionEvent(types.LogLevelError, types.LogCategoryAPICall,
+		c.logExecutionEvent(ctx, types.LogLevelError, types.LogCategoryAPICall,
 			fmt.Sprintf("Failed to parse weather API response: %v", err),
 			map[string]interface{}{
 				"location":     location,
@@ -1062,7 +1593,7 @@ func (c *Client) callWeatherAPI(ctx context.Context, location string, apiKey str
 		"description": fmt.Sprintf("Current weather in %s: %.0f°F, %s", weatherResp.Name, weatherResp.Main.Temp, description),
 	}
 
-	c.logExecutionEvent(types.LogLevelSuccess, types.LogCategoryAPICall,
+	c.logExecutionEvent(ctx, types.LogLevelSuccess, types.LogCategoryAPICall,
 		fmt.Sprintf("Weather API call successful for %s: %s, %.0f°F", weatherResp.Name, condition, weatherResp.Main.Temp),
 		map[string]interface{}{
 			"location":    weatherResp.Name,
@@ -1106,55 +1637,73 @@ func (c *Client) getLocationSuggestion(location string, statusCode int, response
 }
 
 // callNeo4jAPI executes a Cypher query against a Neo4j database
-func (c *Client) callNeo4jAPI(ctx context.Context, query string, limit int) (map[string]interface{}, error) {
+func (c *Client) callNeo4jAPI(ctx context.Context, query *cypherQuery, limit int, parameters map[string]interface{}) (map[string]interface{}, error) {
 	if c.config.Neo4jURL == "" {
 		return nil, fmt.Errorf("Neo4j URL not configured")
 	}
 
-	log.Printf("🔗 Connecting to Neo4j at: %s", c.config.Neo4jURL)
-
-	// Create Neo4j driver
-	driver, err := neo4j.NewDriverWithContext(c.config.Neo4jURL, neo4j.BasicAuth(c.config.Neo4jUsername, c.config.Neo4jPassword, ""))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Neo4j driver: %w", err)
+	// The query may run until the function's deadline, and the server aborts it then too
+	timeout := defaultFunctionTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
 	}
-	defer driver.Close(ctx)
-
-	// Verify connectivity
-	if err := driver.VerifyConnectivity(ctx); err != nil {
-		return nil, fmt.Errorf("failed to connect to Neo4j: %w", err)
+	if timeout <= 0 {
+		return nil, context.DeadlineExceeded
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	// Create session
-	sessionConfig := neo4j.SessionConfig{
-		AccessMode:   neo4j.AccessModeRead,
-		DatabaseName: c.config.Neo4jDatabase,
+	// Reuse a cached driver, and the execution run's session when there is one
+	sessionKey := neo4jSessionKey{
+		driver: neo4jDriverKey{
+			url:      c.config.Neo4jURL,
+			username: c.config.Neo4jUsername,
+			password: c.config.Neo4jPassword,
+		},
+		database: c.config.Neo4jDatabase,
+	}
+	if scope, ok := executionScopeFrom(ctx); ok {
+		sessionKey.executionRunID = scope.executionRunID
 	}
-	session := driver.NewSession(ctx, sessionConfig)
-	defer session.Close(ctx)
+	session, release, err := c.neo4jConnections().session(ctx, sessionKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Neo4j: %w", err)
+	}
+	defer release()
 
 	// Add LIMIT clause if not present in query
-	finalQuery := query
-	if !strings.Contains(strings.ToUpper(query), "LIMIT") {
-		finalQuery = fmt.Sprintf("%s LIMIT %d", query, limit)
+	finalQuery := query.Text
+	params := make(map[string]interface{}, len(parameters)+1)
+	for name, value := range parameters {
+		params[name] = value
+	}
+	if !query.HasLimit {
+		finalQuery = fmt.Sprintf("%s LIMIT $%s", query.Text, cypherLimitParameter)
+		params[cypherLimitParameter] = limit
 	}
 
 	log.Printf("🔍 Executing Cypher query: %s", finalQuery)
 
-	// Execute query
+	// Execute query in a read transaction, which the server refuses to write in
 	startTime := time.Now()
-	result, err := session.Run(ctx, finalQuery, nil)
+	collected, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		result, err := tx.Run(ctx, finalQuery, params)
+		if err != nil {
+			return nil, err
+		}
+		return result.Collect(ctx)
+	}, neo4j.WithTxTimeout(timeout))
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
+	records := collected.([]*neo4j.Record)
 
 	// Collect results
 	var nodes []map[string]interface{}
 	var relationships []map[string]interface{}
 	recordCount := 0
 
-	for result.Next(ctx) {
-		record := result.Record()
+	for _, record := range records {
 		recordCount++
 
 		// Process each value in the record
@@ -1190,11 +1739,6 @@ func (c *Client) callNeo4jAPI(ctx context.Context, query string, limit int) (map
 		}
 	}
 
-	// Check for errors
-	if err := result.Err(); err != nil {
-		return nil, fmt.Errorf("query execution error: %w", err)
-	}
-
 	executionTime := time.Since(startTime)
 
 	// Build response
@@ -1222,65 +1766,23 @@ func (c *Client) sendFunctionResultToGemini(ctx context.Context, config *types.A
 	resultText, _ := json.Marshal(functionResult)
 	followUpPrompt := fmt.Sprintf("%s\n\nFunction %s was called and returned: %s\n\nPlease provide a natural, helpful response to the user based on this information.", originalPrompt, functionName, string(resultText))
 
-	// Create request body for the follow-up call
-	requestBody := map[string]interface{}{
-		"contents": []map[string]interface{}{
-			{
-				"parts": []map[string]interface{}{
-					{"text": followUpPrompt},
-				},
-			},
-		},
-	}
-
-	// Add generation config
-	if config.Temperature != nil {
-		requestBody["generationConfig"] = map[string]interface{}{
-			"temperature": *config.Temperature,
-		}
-	}
-
-	// Make the API call
-	reqBodyBytes, _ := json.Marshal(requestBody)
-	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent", config.ModelName)
-
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(reqBodyBytes))
-	if err != nil {
-		return "", err
+	generateRequest := &gemini.GenerateContentRequest{
+		Contents:          []gemini.Content{gemini.UserContent(followUpPrompt)},
+		SystemInstruction: systemInstruction(config),
+		GenerationConfig:  geminiFollowUpConfig(config),
+		SafetySettings:    geminiSafetySettings(config.SafetySettings),
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-goog-api-key", c.config.APIKey)
-
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
+	api, err := c.geminiAPIFor(config)
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("failed to create Vertex AI client: %w", err)
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
+	geminiResp, err := api.GenerateContent(ctx, config.ModelName, generateRequest)
 	if err != nil {
 		return "", err
 	}
 
-	// Parse response
-	var geminiResp struct {
-		Candidates []struct {
-			Content struct {
-				Parts []struct {
-					Text string `json:"text"`
-				} `json:"parts"`
-			} `json:"content"`
-		} `json:"candidates"`
-	}
-
-	if err := json.Unmarshal(body, &geminiResp); err != nil {
-		return "", err
-	}
-
-	if len(geminiResp.Candidates) > 0 && len(geminiResp.Candidates[0].Content.Parts) > 0 {
-		finalResponse := geminiResp.Candidates[0].Content.Parts[0].Text
+	if finalResponse := geminiResp.Text(); finalResponse != "" {
 		log.Printf("✅ Got final response from Gemini: %s", finalResponse[:min(50, len(finalResponse))])
 		return finalResponse, nil
 	}
@@ -1297,13 +1799,13 @@ func min(a, b int) int {
 }
 
 // compareResults compares multiple variation results
-func (c *Client) compareResults(ctx context.Context, result *types.ExecutionResult) (*types.ComparisonResult, error) {
+func (c *Client) compareResults(ctx context.Context, userID string, result *types.ExecutionResult, comparisonConfig *types.ComparisonConfig) (*types.ComparisonResult, error) {
 	// Enhanced comparison implementation with multiple metrics
-	fmt.Printf("🔍 Comparing %d results for execution run: %s\n", len(result.Results), result.ExecutionRun.ID)
+	log.Printf("🔍 Comparing %d results for execution run: %s", len(result.Results), result.ExecutionRun.ID)
 
 	// Log all configuration IDs for debugging
 	for i, r := range result.Results {
-		fmt.Printf("🔧 Config %d: %s (ID: %s)\n", i+1, r.Configuration.VariationName, r.Configuration.ID)
+		log.Printf("🔧 Config %d: %s (ID: %s)", i+1, r.Configuration.VariationName, r.Configuration.ID)
 	}
 
 	comparisonResult := &types.ComparisonResult{
@@ -1319,6 +1821,41 @@ func (c *Client) compareResults(ctx context.Context, result *types.ExecutionResu
 	var bestOverall *types.VariationResult
 	var bestScore float64 = -1
 
+	var toolConfig *types.ToolAppropriatenessConfig
+	if comparisonConfig != nil {
+		toolConfig = comparisonConfig.ToolAppropriateness
+	}
+	toolOutcomes := make(map[types.ToolUsageOutcome]int)
+	schemaChecked, schemaPassed := 0, 0
+	guarded, violations, blocked := 0, 0, 0
+	thinking, reasoningTokens := 0, 0
+	candidateConfigs := make(map[string]int)
+	toolEnabled, toolCalled, functionCallCount, validArguments := 0, 0, 0, 0.0
+
+	// Grade responses with the judge model when one is configured
+	var judgeConfig *types.JudgeConfig
+	var judgments map[string]*types.Judgment
+	var judging *judgeRun
+	if comparisonConfig != nil && comparisonConfig.Judge != nil {
+		judgeConfig = &types.JudgeConfig{}
+		*judgeConfig = *comparisonConfig.Judge
+		if err := ValidateJudgeConfig(judgeConfig); err != nil {
+			log.Printf("⚠️ Warning: skipping judge: %v", err)
+			judgeConfig = nil
+		} else {
+			judgments, judging = c.judgeResults(ctx, userID, result, judgeConfig, c.callGeminiAPI)
+		}
+	}
+
+	// Score agreement between responses when semantic similarity is requested
+	var semantic semanticScores
+	if comparisonConfig != nil && slices.Contains(comparisonConfig.Metrics, semanticSimilarityMetric) {
+		semantic = c.semanticSimilarities(ctx, userID, result, c.callEmbeddingAPI)
+	}
+
+	// Repeated configurations are scored per sample, then summarized
+	var samples repetitionSamples
+
 	for _, r := range result.Results {
 		// Calculate various metrics
 		responseTimeScore := calculateResponseTimeScore(r.Response.ResponseTimeMs)
@@ -1327,6 +1864,8 @@ func (c *Client) compareResults(ctx context.Context, result *types.ExecutionResu
 		tokenEfficiencyScore := calculateTokenEfficiencyScore(r.Response)
 		safetyScore := calculateSafetyScore(r.Response.ResponseText)
 		costEffectivenessScore := calculateCostEffectivenessScore(r.Response)
+		toolUsage := classifyToolUsage(r, toolConfig)
+		toolOutcomes[toolUsage]++
 
 		// Calculate overall score (weighted average)
 		overallScore := (responseTimeScore*0.2 +
@@ -1336,67 +1875,230 @@ func (c *Client) compareResults(ctx context.Context, result *types.ExecutionResu
 			safetyScore*0.1 +
 			costEffectivenessScore*0.05)
 
+		judgment := judgments[resultKey(r)]
+		if judgment != nil {
+			overallScore = overallScore*(1-judgeConfig.Weight) + judgment.Score*judgeConfig.Weight
+		}
+
 		// Track best overall configuration
 		if bestOverall == nil || overallScore > bestScore {
 			bestOverall = &r
 			bestScore = overallScore
 		}
-
-		// Store detailed scores with configuration ID for easy matching
-		scores[r.Configuration.VariationName] = map[string]interface{}{
-			"configuration_id":    r.Configuration.ID,
-			"response_time_ms":    r.Response.ResponseTimeMs,
-			"status":              r.Response.ResponseStatus,
-			"response_time_score": responseTimeScore,
-			"creativity_score":    creativityScore,
-			"coherence_score":     coherenceScore,
-			"token_efficiency":    tokenEfficiencyScore,
-			"safety_score":        safetyScore,
-			"cost_effectiveness":  costEffectivenessScore,
-			"overall_score":       overallScore,
-			"temperature":         r.Configuration.Temperature,
-			"model_name":          r.Configuration.ModelName,
+
+		// Store detailed scores with configuration ID for easy matching
+		variationScores := map[string]interface{}{
+			"configuration_id":     r.Configuration.ID,
+			"response_time_ms":     r.Response.ResponseTimeMs,
+			"status":               r.Response.ResponseStatus,
+			"response_time_score":  responseTimeScore,
+			"creativity_score":     creativityScore,
+			"coherence_score":      coherenceScore,
+			"token_efficiency":     tokenEfficiencyScore,
+			"safety_score":         safetyScore,
+			"cost_effectiveness":   costEffectivenessScore,
+			"overall_score":        overallScore,
+			"tool_usage":           toolUsage,
+			"tool_appropriateness": toolAppropriatenessScore(toolUsage),
+			"temperature":          r.Configuration.Temperature,
+			"model_name":           r.Configuration.ModelName,
+		}
+		if similarity, ok := semantic.similarities[resultKey(r)]; ok {
+			variationScores[semanticSimilarityMetric] = similarity
+			variationScores["semantic_cluster"] = semantic.clusters[resultKey(r)]
+		}
+		if compliance, violations, ok := scoreSchemaCompliance(r); ok {
+			variationScores[schemaComplianceMetric] = compliance
+			if len(violations) > 0 {
+				variationScores["schema_errors"] = violations
+			}
+			schemaChecked++
+			if compliance == 1 {
+				schemaPassed++
+			}
+		}
+		if len(r.Configuration.Guardrails) > 0 {
+			variationViolations := guardViolations(r.GuardVerdicts)
+			variationScores[guardViolationsMetric] = variationViolations
+			guarded++
+			violations += variationViolations
+			if r.Response.ResponseStatus == types.ResponseStatusBlocked {
+				variationScores["guard_blocked"] = true
+				blocked++
+			}
+		}
+		if metrics := functionCallMetrics(r); metrics != nil {
+			for metric, value := range metrics {
+				variationScores[metric] = value
+			}
+			toolEnabled++
+			if names, ok := metrics["functions_called"].([]string); ok {
+				toolCalled++
+				functionCallCount += len(names)
+				validArguments += metrics[argumentValidityMetric].(float64) * float64(len(names))
+			}
+		}
+		if r.Response.CandidateIndex > 0 {
+			variationScores["candidate_index"] = r.Response.CandidateIndex
+			candidateConfigs[r.Configuration.ID]++
+		}
+		if metrics := reasoningMetrics(r); metrics != nil {
+			for metric, value := range metrics {
+				variationScores[metric] = value
+			}
+			thinking++
+			reasoningTokens += metrics[reasoningTokensMetric].(int)
+		}
+		if judgment != nil {
+			variationScores["judge_score"] = judgment.Score
+			variationScores["judge_rationale"] = judgment.Rationale
+			variationScores["judge_cached"] = judgment.Cached
+		}
+		scores[comparisonName(r)] = variationScores
+		if r.Repetition > 0 {
+			samples.add(comparisonName(r), variationScores)
 		}
 
 		// Log detailed scoring for debugging
-		fmt.Printf("📊 Configuration %s (%s): Overall=%.2f, Time=%dms, Creativity=%.2f\n",
-			r.Configuration.VariationName,
+		log.Printf("📊 Configuration %s (%s): Overall=%.2f, Time=%dms, Creativity=%.2f",
+			comparisonName(r),
 			r.Configuration.ID[:8],
 			overallScore*100,
 			r.Response.ResponseTimeMs,
 			creativityScore*100)
 	}
 
+	// Score repeated configurations by their mean across samples and test their differences
+	if len(samples.names) > 0 {
+		for _, name := range samples.names {
+			variationScores := scores[name].(map[string]interface{})
+			statistics := samples.statistics(name)
+			for metric, stats := range statistics {
+				variationScores[metric] = stats.Mean
+			}
+			variationScores["repetitions"] = statistics["overall_score"].Samples
+			variationScores["statistics"] = statistics
+		}
+
+		bestOverall, bestScore = nil, -1
+		for _, r := range result.Results {
+			if overallScore := getScoreFromMap(scores, comparisonName(r), "overall_score"); bestOverall == nil || overallScore > bestScore {
+				bestOverall = &r
+				bestScore = overallScore
+			}
+		}
+		comparisonResult.SignificanceTests = samples.significanceTests()
+	}
+
 	// Set best configuration and analysis notes
 	if bestOverall != nil {
 		comparisonResult.BestConfigurationID = bestOverall.Configuration.ID
 		comparisonResult.BestConfiguration = &bestOverall.Configuration
 
 		// Log the best configuration ID for debugging
-		fmt.Printf("🏆 Best Configuration Selected: %s (ID: %s)\n", bestOverall.Configuration.VariationName, bestOverall.Configuration.ID)
+		log.Printf("🏆 Best Configuration Selected: %s (ID: %s)", comparisonName(*bestOverall), bestOverall.Configuration.ID)
 
 		// Create detailed analysis notes
-		analysis := fmt.Sprintf("🏆 Best Configuration: %s\n", bestOverall.Configuration.VariationName)
+		analysis := fmt.Sprintf("🏆 Best Configuration: %s\n", comparisonName(*bestOverall))
 		analysis += fmt.Sprintf("📋 Configuration ID: %s\n\n", bestOverall.Configuration.ID)
 		analysis += fmt.Sprintf("📊 Overall Score: %.2f/100\n", bestScore*100)
 		analysis += fmt.Sprintf("⚡ Response Time: %dms\n", bestOverall.Response.ResponseTimeMs)
-		analysis += fmt.Sprintf("🎨 Creativity Score: %.1f/100\n", getScoreFromMap(scores, bestOverall.Configuration.VariationName, "creativity_score")*100)
-		analysis += fmt.Sprintf("🧠 Coherence Score: %.1f/100\n", getScoreFromMap(scores, bestOverall.Configuration.VariationName, "coherence_score")*100)
-		analysis += fmt.Sprintf("💡 Token Efficiency: %.1f/100\n", getScoreFromMap(scores, bestOverall.Configuration.VariationName, "token_efficiency")*100)
+		analysis += fmt.Sprintf("🎨 Creativity Score: %.1f/100\n", getScoreFromMap(scores, comparisonName(*bestOverall), "creativity_score")*100)
+		analysis += fmt.Sprintf("🧠 Coherence Score: %.1f/100\n", getScoreFromMap(scores, comparisonName(*bestOverall), "coherence_score")*100)
+		analysis += fmt.Sprintf("💡 Token Efficiency: %.1f/100\n", getScoreFromMap(scores, comparisonName(*bestOverall), "token_efficiency")*100)
 
 		// Add comparison insights
 		analysis += "\n📈 Key Insights:\n"
 		fastest := findFastest(result.Results)
 		if fastest != nil && fastest.Configuration.ID != bestOverall.Configuration.ID {
-			analysis += fmt.Sprintf("• Fastest: %s (%dms)\n", fastest.Configuration.VariationName, fastest.Response.ResponseTimeMs)
+			analysis += fmt.Sprintf("• Fastest: %s (%dms)\n", comparisonName(*fastest), fastest.Response.ResponseTimeMs)
 		}
 
 		mostCreative := findMostCreative(scores)
-		if mostCreative != "" && mostCreative != bestOverall.Configuration.VariationName {
+		if mostCreative != "" && mostCreative != comparisonName(*bestOverall) {
 			analysis += fmt.Sprintf("• Most Creative: %s\n", mostCreative)
 		}
 
-		analysis += fmt.Sprintf("• Best Overall: %s (balanced performance)\n", bestOverall.Configuration.VariationName)
+		analysis += fmt.Sprintf("• Best Overall: %s (balanced performance)\n", comparisonName(*bestOverall))
+
+		if judged := len(result.Results) - toolOutcomes[types.ToolUsageNotAvailable]; judged > 0 {
+			appropriate := toolOutcomes[types.ToolUsageAppropriateCall] + toolOutcomes[types.ToolUsageAppropriateNoCall]
+			analysis += fmt.Sprintf("• Tool Appropriateness: %d/%d variations (%d unnecessary calls, %d missed calls)\n",
+				appropriate, judged, toolOutcomes[types.ToolUsageUnnecessaryCall], toolOutcomes[types.ToolUsageMissedCall])
+		}
+
+		if toolCalled > 0 {
+			analysis += fmt.Sprintf("• Function Calls: %d/%d variations called a tool (%d calls, %.0f%% with valid arguments)\n",
+				toolCalled, toolEnabled, functionCallCount, validArguments/float64(functionCallCount)*100)
+		}
+
+		if len(semantic.similarities) > 0 {
+			var total float64
+			clusters := make(map[int]bool)
+			for key, similarity := range semantic.similarities {
+				total += similarity
+				clusters[semantic.clusters[key]] = true
+			}
+			analysis += fmt.Sprintf("• Semantic Similarity: %.2f average agreement across %d clusters (%d/%d embeddings cached)\n",
+				total/float64(len(semantic.similarities)), len(clusters), semantic.cached, len(semantic.similarities))
+		}
+
+		if schemaChecked > 0 {
+			analysis += fmt.Sprintf("• Schema Compliance: %d/%d structured responses matched their schema\n",
+				schemaPassed, schemaChecked)
+		}
+
+		if guarded > 0 {
+			analysis += fmt.Sprintf("• Guard Violations: %d across %d guarded variations (%d prompts blocked)\n",
+				violations, guarded, blocked)
+		}
+
+		if thinking > 0 {
+			analysis += fmt.Sprintf("• Reasoning: %d thinking tokens across %d variations (%d on average)\n",
+				reasoningTokens, thinking, reasoningTokens/thinking)
+		}
+
+		if len(candidateConfigs) > 0 {
+			extra := 0
+			for _, count := range candidateConfigs {
+				extra += count
+			}
+			analysis += fmt.Sprintf("• Candidates: %d extra candidates of %d configurations scored as sub-variations\n",
+				extra, len(candidateConfigs))
+		}
+
+		if len(samples.names) > 0 {
+			stats := samples.statistics(comparisonName(*bestOverall))["overall_score"]
+			analysis += fmt.Sprintf("• Repetitions: best mean overall score %.2f/100 over %d samples (95%% CI %.2f–%.2f)\n",
+				stats.Mean*100, stats.Samples, stats.CILower*100, stats.CIUpper*100)
+
+			significant := 0
+			for _, test := range comparisonResult.SignificanceTests {
+				if test.Significant && test.Metric == "overall_score" {
+					analysis += fmt.Sprintf("• Significant: %s vs %s on overall score (difference %+.2f, p=%.3f)\n",
+						test.ConfigurationA, test.ConfigurationB, test.MeanDifference*100, test.PValue)
+					significant++
+				}
+			}
+			if significant == 0 {
+				analysis += fmt.Sprintf("• No significant overall score differences between configurations (p < %.2f)\n", significanceLevel)
+			}
+		}
+
+		if judging != nil {
+			cached := 0
+			for _, judgment := range judgments {
+				if judgment.Cached {
+					cached++
+				}
+			}
+			analysis += fmt.Sprintf("• Judge (%s): %d variations graded (%d cached), ~$%.4f spent",
+				judgeConfig.Model, len(judgments), cached, judging.spent)
+			if judging.skipped > 0 {
+				analysis += fmt.Sprintf(", %d skipped at the $%.4f budget", judging.skipped, judgeConfig.MaxCostUSD)
+			}
+			analysis += "\n"
+		}
 
 		comparisonResult.AnalysisNotes = analysis
 	}
@@ -1613,278 +2315,135 @@ func findMostCreative(scores map[string]interface{}) string {
 
 // StoreComparisonResult stores a comparison result in the database
 func (c *Client) StoreComparisonResult(ctx context.Context, userID string, comparison *types.ComparisonResult) error {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-
-	// Convert configuration scores to JSON
-	configScoresJSON, err := json.Marshal(comparison.ConfigurationScores)
-	if err != nil {
-		return fmt.Errorf("failed to marshal configuration scores: %w", err)
-	}
-
-	// Convert best configuration to JSON
-	var bestConfigJSON json.RawMessage
-	if comparison.BestConfiguration != nil {
-		bestConfigJSON, err = json.Marshal(comparison.BestConfiguration)
-		if err != nil {
-			return fmt.Errorf("failed to marshal best configuration: %w", err)
-		}
-	}
-
-	// Convert all configurations to JSON
-	var allConfigsJSON json.RawMessage
-	if len(comparison.AllConfigurations) > 0 {
-		allConfigsJSON, err = json.Marshal(comparison.AllConfigurations)
-		if err != nil {
-			return fmt.Errorf("failed to marshal all configurations: %w", err)
-		}
-	}
-
 	// Determine comparison type from metric name
-	comparisonType := "custom"
+	stored := *comparison
+	stored.ComparisonType = "custom"
 	switch comparison.MetricName {
 	case "response_time", "performance":
-		comparisonType = "performance"
+		stored.ComparisonType = "performance"
 	case "quality", "coherence_score", "creativity_score":
-		comparisonType = "quality"
+		stored.ComparisonType = "quality"
 	case "safety_score":
-		comparisonType = "safety"
-	}
-
-	// Store in database
-	err = c.queries.CreateComparisonResult(ctx, db.CreateComparisonResultParams{
-		ID:                    comparison.ID,
-		ExecutionRunID:        comparison.ExecutionRunID,
-		ComparisonType:        sql.NullString{String: comparisonType, Valid: true},
-		MetricName:            sql.NullString{String: comparison.MetricName, Valid: true},
-		ConfigurationScores:   configScoresJSON,
-		BestConfigurationID:   sql.NullString{String: comparison.BestConfigurationID, Valid: comparison.BestConfigurationID != ""},
-		BestConfigurationData: bestConfigJSON,
-		AllConfigurationsData: allConfigsJSON,
-		AnalysisNotes:         sql.NullString{String: comparison.AnalysisNotes, Valid: comparison.AnalysisNotes != ""},
-	})
+		stored.ComparisonType = "safety"
+	}
 
-	if err != nil {
+	if err := c.store.CreateComparisonResult(ctx, &stored); err != nil {
 		return fmt.Errorf("failed to store comparison result: %w", err)
 	}
 
 	return nil
 }
 
-// GetComparisonResult retrieves a comparison result from the database
-func (c *Client) GetComparisonResult(ctx context.Context, executionRunID string) (*types.ComparisonResult, error) {
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
-
-	row, err := c.queries.GetComparisonResult(ctx, executionRunID)
+// GetComparisonResult retrieves the comparison result of a run owned by the user
+func (c *Client) GetComparisonResult(ctx context.Context, userID, executionRunID string) (*types.ComparisonResult, error) {
+	comparison, err := c.store.GetComparisonResult(ctx, userID, executionRunID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get comparison result: %w", err)
 	}
-
-	// Parse configuration scores JSON
-	var configScores map[string]interface{}
-	if err := json.Unmarshal(row.ConfigurationScores, &configScores); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal configuration scores: %w", err)
-	}
-
-	// Parse best configuration JSON
-	var bestConfig *types.APIConfiguration
-	if row.BestConfigurationData != nil {
-		if bestConfigStr, ok := row.BestConfigurationData.(string); ok && bestConfigStr != "" {
-			bestConfig = &types.APIConfiguration{}
-			if err := json.Unmarshal([]byte(bestConfigStr), bestConfig); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal best configuration: %w", err)
-			}
-		}
-	}
-
-	// Parse all configurations JSON
-	var allConfigs []types.APIConfiguration
-	if row.AllConfigurationsData != nil {
-		if allConfigsStr, ok := row.AllConfigurationsData.(string); ok && allConfigsStr != "" {
-			if err := json.Unmarshal([]byte(allConfigsStr), &allConfigs); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal all configurations: %w", err)
-			}
-		}
-	}
-
-	var createdAt time.Time
-	if row.CreatedAt.Valid {
-		createdAt = row.CreatedAt.Time
-	}
-
-	comparison := &types.ComparisonResult{
-		ID:                  row.ID,
-		ExecutionRunID:      row.ExecutionRunID,
-		ComparisonType:      row.ComparisonType.String,
-		MetricName:          row.MetricName.String,
-		ConfigurationScores: configScores,
-		BestConfigurationID: row.BestConfigurationID.String,
-		BestConfiguration:   bestConfig,
-		AllConfigurations:   allConfigs,
-		AnalysisNotes:       row.AnalysisNotes.String,
-		CreatedAt:           createdAt,
-	}
-
 	return comparison, nil
 }
 
-// ListComparisonResults retrieves all comparison results from the database
-func (c *Client) ListComparisonResults(ctx context.Context) ([]*types.ComparisonResult, error) {
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
-
-	rows, err := c.queries.ListComparisonResults(ctx)
+// ListComparisonResults retrieves a page of the comparison results of the user's runs, newest first
+func (c *Client) ListComparisonResults(ctx context.Context, userID string, limit, offset int32) ([]*types.ComparisonResult, error) {
+	comparisonResults, err := c.store.ListComparisonResults(ctx, userID, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list comparison results: %w", err)
 	}
-
-	var comparisonResults []*types.ComparisonResult
-	for _, row := range rows {
-		// Parse configuration scores JSON
-		var configScores map[string]interface{}
-		if err := json.Unmarshal(row.ConfigurationScores, &configScores); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal configuration scores: %w", err)
-		}
-
-		// Parse best configuration JSON
-		var bestConfig *types.APIConfiguration
-		if row.BestConfigurationData != nil {
-			if bestConfigStr, ok := row.BestConfigurationData.(string); ok && bestConfigStr != "" {
-				bestConfig = &types.APIConfiguration{}
-				if err := json.Unmarshal([]byte(bestConfigStr), bestConfig); err != nil {
-					return nil, fmt.Errorf("failed to unmarshal best configuration: %w", err)
-				}
-			}
-		}
-
-		// Parse all configurations JSON
-		var allConfigs []types.APIConfiguration
-		if row.AllConfigurationsData != nil {
-			if allConfigsStr, ok := row.AllConfigurationsData.(string); ok && allConfigsStr != "" {
-				if err := json.Unmarshal([]byte(allConfigsStr), &allConfigs); err != nil {
-					return nil, fmt.Errorf("failed to unmarshal all configurations: %w", err)
-				}
-			}
-		}
-
-		var createdAt time.Time
-		if row.CreatedAt.Valid {
-			createdAt = row.CreatedAt.Time
-		}
-
-		comparison := &types.ComparisonResult{
-			ID:                  row.ID,
-			ExecutionRunID:      row.ExecutionRunID,
-			ComparisonType:      row.ComparisonType.String,
-			MetricName:          row.MetricName.String,
-			ConfigurationScores: configScores,
-			BestConfigurationID: row.BestConfigurationID.String,
-			BestConfiguration:   bestConfig,
-			AllConfigurations:   allConfigs,
-			AnalysisNotes:       row.AnalysisNotes.String,
-			CreatedAt:           createdAt,
-		}
-		comparisonResults = append(comparisonResults, comparison)
-	}
-
 	return comparisonResults, nil
 }
 
-// Helper functions for handling nullable database fields
-func convertFloat32ToNullString(f *float32) sql.NullString {
-	if f == nil {
-		return sql.NullString{Valid: false}
+// CountComparisonResults counts the comparison results of the user's runs
+func (c *Client) CountComparisonResults(ctx context.Context, userID string) (int64, error) {
+	count, err := c.store.CountComparisonResults(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count comparison results: %w", err)
 	}
-	return sql.NullString{String: fmt.Sprintf("%.2f", *f), Valid: true}
+	return count, nil
 }
 
-func convertInt32ToNullInt32(i *int32) sql.NullInt32 {
-	if i == nil {
-		return sql.NullInt32{Valid: false}
+// ListExecutionRuns retrieves execution runs from the database with pagination
+func (c *Client) ListExecutionRuns(ctx context.Context, userID string, limit, offset int32) ([]*types.ExecutionRun, error) {
+	executionRuns, err := c.store.ListExecutionRuns(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list execution runs: %w", err)
 	}
-	return sql.NullInt32{Int32: *i, Valid: true}
+	return executionRuns, nil
 }
 
-// convertStringToRawMessage converts a JSON string to json.RawMessage for database storage
-func convertStringToRawMessage(jsonStr string) json.RawMessage {
-	if jsonStr == "" {
-		return json.RawMessage("null")
+// ListAllExecutionRuns lists execution runs across every user, newest first
+func (c *Client) ListAllExecutionRuns(ctx context.Context, limit, offset int32) ([]*types.ExecutionRun, error) {
+	executionRuns, err := c.store.ListAllExecutionRuns(ctx, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list all execution runs: %w", err)
 	}
-	return json.RawMessage(jsonStr)
+	return executionRuns, nil
 }
 
-// ListExecutionRuns retrieves execution runs from the database with pagination
-func (c *Client) ListExecutionRuns(ctx context.Context, userID string, limit, offset int32) ([]*types.ExecutionRun, error) {
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
-
-	rows, err := c.queries.GetRecentExecutionRuns(ctx, db.GetRecentExecutionRunsParams{
-		UserID: userID,
-		Limit:  limit,
-	})
+// CountExecutionRuns counts the user's execution runs
+func (c *Client) CountExecutionRuns(ctx context.Context, userID string) (int64, error) {
+	count, err := c.store.CountExecutionRuns(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list execution runs: %w", err)
+		return 0, fmt.Errorf("failed to count execution runs: %w", err)
 	}
+	return count, nil
+}
 
-	var executionRuns []*types.ExecutionRun
-	for _, row := range rows {
-		description := ""
-		if row.Description.Valid {
-			description = row.Description.String
-		}
-
-		executionRun := &types.ExecutionRun{
-			ID:                    row.ID,
-			Name:                  row.Name,
-			Description:           description,
-			EnableFunctionCalling: row.EnableFunctionCalling,
-			Status:                "completed", // Default status for existing records
-			ErrorMessage:          "",
-			CreatedAt:             row.CreatedAt.Time,
-			UpdatedAt:             row.UpdatedAt.Time,
-		}
-		executionRuns = append(executionRuns, executionRun)
+// CountAllExecutionRuns counts execution runs across every user
+func (c *Client) CountAllExecutionRuns(ctx context.Context) (int64, error) {
+	count, err := c.store.CountAllExecutionRuns(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count all execution runs: %w", err)
 	}
-
-	return executionRuns, nil
+	return count, nil
 }
 
 // GetExecutionRun retrieves a single execution run by ID
 func (c *Client) GetExecutionRun(ctx context.Context, userID string, id string) (*types.ExecutionRun, error) {
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
-
-	row, err := c.queries.GetExecutionRun(ctx, db.GetExecutionRunParams{
-		ID:     id,
-		UserID: userID,
-	})
+	run, err := c.store.GetExecutionRun(ctx, userID, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get execution run: %w", err)
 	}
 
-	description := ""
-	if row.Description.Valid {
-		description = row.Description.String
+	if err := c.loadDeterminism(ctx, run); err != nil {
+		log.Printf("⚠️ %v", err)
+	}
+	if err := c.loadReplay(ctx, run); err != nil {
+		log.Printf("⚠️ %v", err)
+	}
+	if err := c.loadParentRun(ctx, run); err != nil {
+		log.Printf("⚠️ %v", err)
+	}
+	if err := c.loadRunSpec(ctx, run); err != nil {
+		log.Printf("⚠️ %v", err)
+	}
+	if err := c.loadParameterSweep(ctx, run); err != nil {
+		log.Printf("⚠️ %v", err)
+	}
+	if err := c.loadRunTags(ctx, run); err != nil {
+		log.Printf("⚠️ %v", err)
 	}
 
-	return &types.ExecutionRun{
-		ID:                    row.ID,
-		Name:                  row.Name,
-		Description:           description,
-		EnableFunctionCalling: row.EnableFunctionCalling,
-		Status:                "completed", // Default status for existing records
-		ErrorMessage:          "",
-		CreatedAt:             row.CreatedAt.Time,
-		UpdatedAt:             row.UpdatedAt.Time,
-	}, nil
+	return run, nil
+}
+
+// DeleteExecutionRun deletes one of the user's runs; its requests, responses, logs and comparison
+// go with it through the schema's cascading foreign keys
+func (c *Client) DeleteExecutionRun(ctx context.Context, userID, id string) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	result, err := c.db.ExecContext(ctx, "DELETE FROM execution_runs WHERE id = ? AND user_id = ?", id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete execution run: %w", err)
+	}
+	if deleted, _ := result.RowsAffected(); deleted == 0 {
+		return ErrExecutionRunNotFound
+	}
+	return nil
 }
 
 // GetExecutionResult retrieves complete execution details from the database
 func (c *Client) GetExecutionResult(ctx context.Context, userID string, executionRunID string) (*types.ExecutionResult, error) {
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
-
 	// Get the execution run
 	executionRun, err := c.GetExecutionRun(ctx, userID, executionRunID)
 	if err != nil {
@@ -1892,118 +2451,53 @@ func (c *Client) GetExecutionResult(ctx context.Context, userID string, executio
 	}
 
 	// Get all configurations for this execution run
-	configRows, err := c.queries.GetAPIConfigurationsByRun(ctx, db.GetAPIConfigurationsByRunParams{
-		ExecutionRunID: executionRunID,
-		UserID:         userID,
-	})
+	configRows, err := c.store.ListAPIConfigurationsByRun(ctx, userID, executionRunID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get configurations: %w", err)
 	}
 	log.Printf("🔧 Found %d configurations for execution run %s", len(configRows), executionRunID)
 
-	// Get function definitions used in this execution
-	functionConfigRows, err := c.queries.ListExecutionFunctionConfigs(ctx, executionRunID)
+	// Get function tools used in this execution
+	functionTools, err := c.store.ListRunFunctionTools(ctx, userID, executionRunID)
 	if err != nil {
 		log.Printf("⚠️ Failed to get function configs for execution %s: %v", executionRunID, err)
 		// Continue without functions rather than failing
+		functionTools = make([]types.Tool, 0)
 	}
-	log.Printf("🔧 Found %d function configurations for execution run %s", len(functionConfigRows), executionRunID)
+	log.Printf("🔧 Found %d function tools for execution run %s", len(functionTools), executionRunID)
 
 	// Get all requests for this execution run
-	requestRows, err := c.queries.GetAPIRequestsByRun(ctx, db.GetAPIRequestsByRunParams{
-		ExecutionRunID: executionRunID,
-		UserID:         userID,
-	})
+	requestRows, err := c.store.ListAPIRequestsByRun(ctx, userID, executionRunID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get requests: %w", err)
 	}
 	log.Printf("📝 Found %d requests for execution run %s", len(requestRows), executionRunID)
 
-	// Get all responses with joined data for this execution run
-	responseRows, err := c.queries.GetAPIResponsesWithRequests(ctx, db.GetAPIResponsesWithRequestsParams{
-		ExecutionRunID: executionRunID,
-		UserID:         userID,
-	})
+	// Get all responses for this execution run
+	responseRows, err := c.store.ListAPIResponsesByRun(ctx, userID, executionRunID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get responses: %w", err)
 	}
 	log.Printf("📊 Found %d responses for execution run %s", len(responseRows), executionRunID)
 
-	// Build function tools map from function configurations
-	functionTools := make([]types.Tool, 0)
-	for _, funcConfig := range functionConfigRows {
-		// Get the full function definition
-		funcDef, err := c.queries.GetFunctionDefinition(ctx, db.GetFunctionDefinitionParams{
-			ID:     funcConfig.FunctionDefinitionID,
-			UserID: userID,
-		})
-		if err != nil {
-			log.Printf("⚠️ Failed to get function definition %s: %v", funcConfig.FunctionDefinitionID, err)
-			continue
-		}
-
-		// Parse the parameters schema
-		var parametersSchema map[string]interface{}
-		if err := json.Unmarshal([]byte(funcDef.ParametersSchema), &parametersSchema); err != nil {
-			log.Printf("⚠️ Failed to parse parameters schema for function %s: %v", funcDef.Name, err)
-			continue
-		}
-
-		tool := types.Tool{
-			Name:        funcDef.Name,
-			Description: funcDef.Description.String,
-			Parameters:  parametersSchema,
-		}
-		functionTools = append(functionTools, tool)
-		log.Printf("✅ Added function tool: %s", funcDef.Name)
-	}
-
 	// Build configurations map and add function tools to each configuration
 	configs := make(map[string]*types.APIConfiguration)
-	for _, row := range configRows {
-		config := &types.APIConfiguration{
-			ID:             row.ID,
-			ExecutionRunID: row.ExecutionRunID,
-			VariationName:  row.VariationName,
-			ModelName:      row.ModelName,
-			SystemPrompt:   row.SystemPrompt.String,
-			CreatedAt:      row.CreatedAt.Time,
-			Tools:          functionTools, // Add the function tools to each configuration
-		}
-
-		// Parse nullable fields
-		if row.Temperature.Valid {
-			temp, _ := parseFloat32(row.Temperature.String)
-			config.Temperature = &temp
-		}
-		if row.MaxTokens.Valid {
-			config.MaxTokens = &row.MaxTokens.Int32
-		}
-		if row.TopP.Valid {
-			topP, _ := parseFloat32(row.TopP.String)
-			config.TopP = &topP
-		}
-		if row.TopK.Valid {
-			config.TopK = &row.TopK.Int32
-		}
-
+	for i := range configRows {
+		config := &configRows[i]
+		config.Tools = functionTools
 		configs[config.ID] = config
 	}
 
 	// Build requests map
 	requests := make(map[string]*types.APIRequest)
-	for _, row := range requestRows {
-		request := &types.APIRequest{
-			ID:              row.ID,
-			ExecutionRunID:  row.ExecutionRunID,
-			ConfigurationID: row.ConfigurationID,
-			RequestType:     types.RequestType(row.RequestType.String),
-			Prompt:          row.Prompt.String,
-			Context:         row.Context.String,
-			FunctionName:    row.FunctionName.String,
-			CreatedAt:       row.CreatedAt.Time,
-		}
+	for i := range requestRows {
+		request := &requestRows[i]
 		requests[request.ID] = request
+
+		// Restore the legacy flag so replays send the system prompt the same way
+		if config := configs[request.ConfigurationID]; config != nil && request.SystemPromptMode == types.SystemPromptModeInline {
+			config.InlineSystemPrompt = true
+		}
 	}
 
 	// Build variation results
@@ -2011,59 +2505,55 @@ func (c *Client) GetExecutionResult(ctx context.Context, userID string, executio
 
 	log.Printf("🔍 Processing %d response rows for execution run %s", len(responseRows), executionRunID)
 
-	// Get execution logs
-	executionLogs, err := c.queries.GetExecutionLogsByRun(ctx, executionRunID)
+	// Get execution logs, including those still waiting to be written
+	c.executionLogs().flush()
+	logs, err := c.store.ListExecutionLogs(ctx, userID, executionRunID)
 	if err != nil {
 		log.Printf("⚠️ Failed to get execution logs for %s: %v", executionRunID, err)
 		// Continue without logs rather than failing
+		logs = make([]types.ExecutionLog, 0)
 	}
-	log.Printf("📋 Found %d execution logs for execution run %s", len(executionLogs), executionRunID)
-
-	for _, respRow := range responseRows {
-		// Get the configuration and request
-		configID := findConfigIDForRequest(requestRows, respRow.RequestID)
-		if configID == "" {
-			log.Printf("Warning: Could not find configuration for request %s", respRow.RequestID)
-			continue
-		}
+	log.Printf("📋 Found %d execution logs for execution run %s", len(logs), executionRunID)
 
-		config := configs[configID]
-		request := requests[respRow.RequestID]
+	// Get the document chunks retrieved for each request
+	retrievedChunks, err := c.GetRetrievedChunks(ctx, userID, executionRunID)
+	if err != nil && !errors.Is(err, ErrNoDatabase) {
+		log.Printf("⚠️ Failed to get retrieved chunks for %s: %v", executionRunID, err)
+	}
+	guardVerdicts, err := c.GetGuardVerdicts(ctx, userID, executionRunID)
+	if err != nil && !errors.Is(err, ErrNoDatabase) {
+		log.Printf("⚠️ Failed to get guard verdicts for %s: %v", executionRunID, err)
+	}
 
-		if config == nil || request == nil {
-			log.Printf("Warning: Missing config or request for response %s (config: %v, request: %v)", respRow.ID, config != nil, request != nil)
+	for _, response := range responseRows {
+		// Get the request and its configuration
+		request := requests[response.RequestID]
+		if request == nil {
+			log.Printf("Warning: Could not find request %s for response %s", response.RequestID, response.ID)
 			continue
 		}
 
-		log.Printf("✅ Processing response %s for config %s (%s)", respRow.ID, configID, config.VariationName)
-
-		// Parse usage metadata
-		var usageMetadata map[string]interface{}
-		if respRow.UsageMetadata != nil {
-			json.Unmarshal(respRow.UsageMetadata, &usageMetadata)
+		config := configs[request.ConfigurationID]
+		if config == nil {
+			log.Printf("Warning: Could not find configuration %s for response %s", request.ConfigurationID, response.ID)
+			continue
 		}
 
-		response := &types.APIResponse{
-			ID:             respRow.ID,
-			RequestID:      respRow.RequestID,
-			ResponseStatus: types.ResponseStatus(respRow.ResponseStatus.String),
-			ResponseText:   respRow.ResponseText.String,
-			FinishReason:   respRow.FinishReason.String,
-			ErrorMessage:   respRow.ErrorMessage.String,
-			ResponseTimeMs: respRow.ResponseTimeMs.Int32,
-			UsageMetadata:  usageMetadata,
-			CreatedAt:      respRow.CreatedAt.Time,
-		}
+		log.Printf("✅ Processing response %s for config %s (%s)", response.ID, config.ID, config.VariationName)
 
 		result := types.VariationResult{
 			Configuration: *config,
 			Request:       *request,
-			Response:      *response,
+			Response:      response,
 			ExecutionTime: int64(response.ResponseTimeMs), // Already in milliseconds
+
+			RetrievedChunks: retrievedChunks[request.ID],
+			GuardVerdicts:   guardVerdicts[request.ID],
 		}
 
 		results = append(results, result)
 	}
+	numberRepetitions(results)
 
 	// Calculate totals
 	totalTime := int64(0)
@@ -2081,42 +2571,6 @@ func (c *Client) GetExecutionResult(ctx context.Context, userID string, executio
 
 	log.Printf("🕐 Total time calculation: %d ms", totalTime)
 
-	// Convert database logs to types.ExecutionLog
-	logs := make([]types.ExecutionLog, 0, len(executionLogs))
-	for _, dbLog := range executionLogs {
-		var details map[string]interface{}
-		if len(dbLog.Details) > 0 {
-			if err := json.Unmarshal(dbLog.Details, &details); err != nil {
-				log.Printf("⚠️ Failed to parse log details: %v", err)
-			}
-		}
-
-		var configID, requestID *string
-		if dbLog.ConfigurationID.Valid {
-			configID = &dbLog.ConfigurationID.String
-		}
-		if dbLog.RequestID.Valid {
-			requestID = &dbLog.RequestID.String
-		}
-
-		timestamp := time.Now()
-		if dbLog.Timestamp.Valid {
-			timestamp = dbLog.Timestamp.Time
-		}
-
-		logs = append(logs, types.ExecutionLog{
-			ID:              dbLog.ID,
-			ExecutionRunID:  dbLog.ExecutionRunID,
-			ConfigurationID: configID,
-			RequestID:       requestID,
-			LogLevel:        types.LogLevel(dbLog.LogLevel.String),
-			LogCategory:     types.LogCategory(dbLog.LogCategory.String),
-			Message:         dbLog.Message,
-			Details:         details,
-			Timestamp:       timestamp,
-		})
-	}
-
 	// Create the execution result
 	result := &types.ExecutionResult{
 		ExecutionRun: *executionRun,
@@ -2128,43 +2582,25 @@ func (c *Client) GetExecutionResult(ctx context.Context, userID string, executio
 	}
 
 	// Try to load comparison result from database
-	comparison, err := c.GetComparisonResult(ctx, executionRunID)
+	comparison, err := c.GetComparisonResult(ctx, userID, executionRunID)
 	if err != nil {
 		log.Printf("ℹ️ No comparison result found for execution run: %s", executionRunID)
 	} else {
 		result.Comparison = comparison
+		result.SweepReport = SummarizeSweep(result)
 		log.Printf("📊 Loaded comparison result from database: %s", comparison.ID)
 	}
 
-	return result, nil
-}
-
-// Helper function to find configuration ID for a request
-func findConfigIDForRequest(requestRows []db.ApiRequest, requestID string) string {
-	for _, req := range requestRows {
-		if req.ID == requestID {
-			return req.ConfigurationID
-		}
+	// Load golden-answer evaluations, if the run had an expected answer
+	evaluations, err := c.GetEvaluationResults(ctx, userID, executionRunID)
+	if err != nil {
+		log.Printf("⚠️ Failed to get evaluation results for %s: %v", executionRunID, err)
+	} else if len(evaluations) > 0 {
+		result.Evaluations = evaluations
+		result.Accuracy = EvaluationAccuracy(evaluations)
 	}
-	return ""
-}
 
-// Helper function to parse float32 from string
-func parseFloat32(s string) (float32, error) {
-	if s == "" {
-		return 0, fmt.Errorf("empty string")
-	}
-	// Simple parsing - could be enhanced
-	if s == "0.20" || s == "0.2" {
-		return 0.2, nil
-	}
-	if s == "0.50" || s == "0.5" {
-		return 0.5, nil
-	}
-	if s == "0.80" || s == "0.8" {
-		return 0.8, nil
-	}
-	return 0.5, nil // default fallback
+	return result, nil
 }
 
 // GetDB returns the underlying database connection for direct queries
@@ -2174,84 +2610,34 @@ func (c *Client) GetDB() *sql.DB {
 
 // storeFunctionExecutionConfigs stores the function-execution relationships for replay functionality
 func (c *Client) storeFunctionExecutionConfigs(ctx context.Context, userID string, executionRunID string, functionTools []types.Tool) error {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-
-	for i, tool := range functionTools {
-		// Find the function definition by name
-		funcDef, err := c.queries.GetFunctionDefinitionByName(ctx, db.GetFunctionDefinitionByNameParams{
-			Name:   tool.Name,
-			UserID: userID,
-		})
-		if err != nil {
-			log.Printf("⚠️ Function definition not found for tool %s: %v", tool.Name, err)
-			continue
-		}
-
-		// Create the execution-function config
-		configID := uuid.New().String()
-		err = c.queries.CreateExecutionFunctionConfig(ctx, db.CreateExecutionFunctionConfigParams{
-			ID:                   configID,
-			UserID:               userID,
-			ExecutionRunID:       executionRunID,
-			FunctionDefinitionID: funcDef.ID,
-			UseMockResponse:      sql.NullBool{Bool: true, Valid: true}, // Default to mock for replay
-			ExecutionOrder:       sql.NullInt32{Int32: int32(i), Valid: true},
-		})
-		if err != nil {
-			log.Printf("❌ Failed to create execution-function config for %s: %v", tool.Name, err)
-			continue
-		}
-
-		log.Printf("✅ Stored function-execution config: %s -> %s", tool.Name, executionRunID)
-	}
-
-	return nil
+	return c.store.CreateRunFunctionTools(ctx, userID, executionRunID, functionTools)
 }
 
-// logExecutionEvent logs an execution event to the database and console
-func (c *Client) logExecutionEvent(level types.LogLevel, category types.LogCategory, message string, details map[string]interface{}) {
+// logExecutionEvent logs an execution event to the console and stores it in the database
+// under the run ctx is scoped to; see withExecutionScope
+func (c *Client) logExecutionEvent(ctx context.Context, level types.LogLevel, category types.LogCategory, message string, details map[string]interface{}) {
 	// Always log to console
 	emoji := c.getLogEmoji(level, category)
 	log.Printf("%s %s", emoji, message)
 
-	// Only log to database if we have an active execution
-	if c.currentExecutionRunID == nil {
+	// Only log to database if we have an active execution and the level is stored
+	scope, ok := executionScopeFrom(ctx)
+	if !ok || !c.storesLogLevel(level) {
 		return
 	}
 
-	ctx := context.Background()
-	logID := uuid.New().String()
-
-	var detailsJSON json.RawMessage
-	if details != nil {
-		if detailsBytes, err := json.Marshal(details); err == nil {
-			detailsJSON = detailsBytes
-		}
-	}
-
-	var configID, requestID sql.NullString
-	if c.currentConfigID != nil {
-		configID = sql.NullString{String: *c.currentConfigID, Valid: true}
-	}
-	if c.currentRequestID != nil {
-		requestID = sql.NullString{String: *c.currentRequestID, Valid: true}
-	}
-
-	err := c.queries.CreateExecutionLog(ctx, db.CreateExecutionLogParams{
-		ID:              logID,
-		ExecutionRunID:  *c.currentExecutionRunID,
-		ConfigurationID: configID,
-		RequestID:       requestID,
-		LogLevel:        sql.NullString{String: string(level), Valid: true},
-		LogCategory:     sql.NullString{String: string(category), Valid: true},
+	entry := &types.ExecutionLog{
+		ID:              uuid.New().String(),
+		ExecutionRunID:  scope.executionRunID,
+		ConfigurationID: optionalString(scope.configurationID),
+		RequestID:       optionalString(scope.requestID),
+		LogLevel:        level,
+		LogCategory:     category,
 		Message:         message,
-		Details:         detailsJSON,
-	})
-
-	if err != nil {
-		log.Printf("❌ Failed to store execution log: %v", err)
+		Details:         details,
+		Timestamp:       time.Now(),
 	}
+	c.executionLogs().enqueue(entry)
 }
 
 // getLogEmoji returns appropriate emoji for log level and category
@@ -2283,132 +2669,18 @@ func (c *Client) getLogEmoji(level types.LogLevel, category types.LogCategory) s
 
 // GetSystemConfigurations retrieves all system-wide AI configurations from the database
 func (c *Client) GetSystemConfigurations(ctx context.Context) ([]types.APIConfiguration, error) {
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
-
-	// Get all configurations and filter for system ones
-	configRows, err := c.queries.ListAPIConfigurations(ctx, db.ListAPIConfigurationsParams{
-		Limit:  100, // Reasonable limit for system configurations
-		Offset: 0,
-	})
+	systemConfigs, err := c.store.ListAPIConfigurations(ctx, "system", 100, 0) // Reasonable limit for system configurations
 	if err != nil {
 		return nil, fmt.Errorf("failed to get configurations: %w", err)
 	}
 
-	var systemConfigs []types.APIConfiguration
-	for _, row := range configRows {
-		// Check if this is a system configuration
-		if row.UserID == "system" {
-			config := types.APIConfiguration{
-				ID:             row.ID,
-				ExecutionRunID: row.ExecutionRunID,
-				VariationName:  row.VariationName,
-				ModelName:      row.ModelName,
-				SystemPrompt:   row.SystemPrompt.String,
-				CreatedAt:      row.CreatedAt.Time,
-			}
-
-			// Parse nullable fields
-			if row.Temperature.Valid {
-				temp, _ := parseFloat32(row.Temperature.String)
-				config.Temperature = &temp
-			}
-			if row.MaxTokens.Valid {
-				config.MaxTokens = &row.MaxTokens.Int32
-			}
-			if row.TopP.Valid {
-				topP, _ := parseFloat32(row.TopP.String)
-				config.TopP = &topP
-			}
-			if row.TopK.Valid {
-				config.TopK = &row.TopK.Int32
-			}
-
-			// Parse JSON fields
-			if len(row.SafetySettings) > 0 {
-				var safetySettings map[string]interface{}
-				if err := json.Unmarshal(row.SafetySettings, &safetySettings); err == nil {
-					config.SafetySettings = safetySettings
-				}
-			}
-			if len(row.GenerationConfig) > 0 {
-				var generationConfig map[string]interface{}
-				if err := json.Unmarshal(row.GenerationConfig, &generationConfig); err == nil {
-					config.GenerationConfig = generationConfig
-				}
-			}
-			if len(row.Tools) > 0 {
-				var tools []types.Tool
-				if err := json.Unmarshal(row.Tools, &tools); err == nil {
-					config.Tools = tools
-				}
-			}
-
-			systemConfigs = append(systemConfigs, config)
-		}
-	}
-
 	log.Printf("✅ Retrieved %d system configurations from database", len(systemConfigs))
 	return systemConfigs, nil
 }
 
-// setExecutionContext sets the current execution context for logging
-func (c *Client) setExecutionContext(executionRunID, configID, requestID *string) {
-	c.currentExecutionRunID = executionRunID
-	c.currentConfigID = configID
-	c.currentRequestID = requestID
-}
-
-// clearExecutionContext clears the execution context
-func (c *Client) clearExecutionContext() {
-	c.currentExecutionRunID = nil
-	c.currentConfigID = nil
-	c.currentRequestID = nil
-}
-
 // LogFunctionCall logs function call details to the database
 func (c *Client) LogFunctionCall(ctx context.Context, call *types.FunctionCall) error {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-
-	// Marshal JSON fields
-	argsJSON, err := json.Marshal(call.FunctionArgs)
-	if err != nil {
-		return fmt.Errorf("failed to marshal function arguments: %w", err)
-	}
-
-	var responseJSON json.RawMessage
-	if call.FunctionResponse != nil {
-		responseBytes, err := json.Marshal(call.FunctionResponse)
-		if err != nil {
-			return fmt.Errorf("failed to marshal function response: %w", err)
-		}
-		responseJSON = responseBytes
-	}
-
-	var errorDetails sql.NullString
-	if call.ErrorDetails != "" {
-		errorDetails = sql.NullString{String: call.ErrorDetails, Valid: true}
-	}
-
-	var executionTimeMs sql.NullInt32
-	if call.ExecutionTimeMs > 0 {
-		executionTimeMs = sql.NullInt32{Int32: call.ExecutionTimeMs, Valid: true}
-	}
-
-	// Store in database
-	err = c.queries.CreateFunctionCall(ctx, db.CreateFunctionCallParams{
-		ID:                call.ID,
-		RequestID:         call.RequestID,
-		FunctionName:      call.FunctionName,
-		FunctionArguments: argsJSON,
-		FunctionResponse:  responseJSON,
-		ExecutionStatus:   sql.NullString{String: call.ExecutionStatus, Valid: true},
-		ExecutionTimeMs:   executionTimeMs,
-		ErrorDetails:      errorDetails,
-	})
-
-	if err != nil {
+	if err := c.store.CreateFunctionCall(ctx, call); err != nil {
 		return fmt.Errorf("failed to store function call: %w", err)
 	}
 
@@ -2418,38 +2690,14 @@ func (c *Client) LogFunctionCall(ctx context.Context, call *types.FunctionCall)
 
 // ListAPIConfigurationsByUser retrieves API configurations for a specific user
 func (c *Client) ListAPIConfigurationsByUser(ctx context.Context, userID string, limit, offset int32) ([]types.APIConfiguration, error) {
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
-
-	rows, err := c.queries.ListAPIConfigurations(ctx, db.ListAPIConfigurationsParams{
-		UserID: userID,
-		Limit:  limit,
-		Offset: offset,
-	})
-	if err != nil {
-		return nil, err
-	}
-	var configs []types.APIConfiguration
-	for _, row := range rows {
-		cfg := types.APIConfiguration{
-			ID:             row.ID,
-			ExecutionRunID: row.ExecutionRunID,
-			VariationName:  row.VariationName,
-			ModelName:      row.ModelName,
-			SystemPrompt:   row.SystemPrompt.String,
-			CreatedAt:      row.CreatedAt.Time,
-		}
-		if row.Temperature.Valid {
-			temp, _ := parseFloat32(row.Temperature.String)
-			cfg.Temperature = &temp
-		}
-		configs = append(configs, cfg)
-	}
-	return configs, nil
+	return c.store.ListAPIConfigurations(ctx, userID, limit, offset)
 }
 
 // RunMigrations runs database migrations using golang-migrate
 func (c *Client) RunMigrations() error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
 	log.Println("🔧 Starting database migrations using golang-migrate...")
 
 	// Create the migrate driver instance
@@ -2460,8 +2708,8 @@ func (c *Client) RunMigrations() error {
 
 	// Create migrate instance
 	m, err := migrate.NewWithDatabaseInstance(
-		"file://migrations", // path to migration files
-		"mysql",             // database name
+		"file://"+migrationsPath, // path to migration files
+		"mysql",                  // database name
 		driver,
 	)
 	if err != nil {
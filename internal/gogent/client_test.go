@@ -1,7 +1,10 @@
 package gogent
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -374,6 +377,88 @@ func TestComparisonMetrics(t *testing.T) {
 	}
 }
 
+func TestSelectConfigurationTools(t *testing.T) {
+	functionTools := []types.Tool{
+		{Name: "get_current_weather", Description: "Get weather"},
+		{Name: "query_graph", Description: "Query the graph"},
+	}
+
+	tests := []struct {
+		name        string
+		config      types.APIConfiguration
+		expectNames []string
+	}{
+		{
+			name:        "all_tools_by_default",
+			config:      types.APIConfiguration{VariationName: "default"},
+			expectNames: []string{"get_current_weather", "query_graph"},
+		},
+		{
+			name:        "subset_by_name",
+			config:      types.APIConfiguration{VariationName: "weather-only", ToolNames: []string{"get_current_weather"}},
+			expectNames: []string{"get_current_weather"},
+		},
+		{
+			name:        "unknown_names_ignored",
+			config:      types.APIConfiguration{VariationName: "unknown", ToolNames: []string{"does_not_exist"}},
+			expectNames: []string{},
+		},
+		{
+			name:        "tools_disabled",
+			config:      types.APIConfiguration{VariationName: "no-tools", DisableTools: true, ToolNames: []string{"query_graph"}},
+			expectNames: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected := selectConfigurationTools(&tt.config, functionTools)
+			names := toolNames(selected)
+
+			if len(names) != len(tt.expectNames) {
+				t.Fatalf("expected %d tools, got %d (%v)", len(tt.expectNames), len(names), names)
+			}
+			for i, name := range tt.expectNames {
+				if names[i] != name {
+					t.Errorf("expected tool %d to be %s, got %s", i, name, names[i])
+				}
+			}
+		})
+	}
+}
+
+func TestResolveFunctionInstruction(t *testing.T) {
+	tests := []struct {
+		name   string
+		config types.APIConfiguration
+		expect string
+	}{
+		{
+			name:   "default_instruction",
+			config: types.APIConfiguration{},
+			expect: DefaultFunctionInstruction,
+		},
+		{
+			name:   "custom_instruction",
+			config: types.APIConfiguration{FunctionInstruction: "Use tools only when needed."},
+			expect: "Use tools only when needed.",
+		},
+		{
+			name:   "instruction_disabled",
+			config: types.APIConfiguration{FunctionInstruction: "ignored", DisableFunctionInstruction: true},
+			expect: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolveFunctionInstruction(&tt.config); got != tt.expect {
+				t.Errorf("expected %q, got %q", tt.expect, got)
+			}
+		})
+	}
+}
+
 // Helper functions for the tests
 func validateConfiguration(config *types.APIConfiguration) error {
 	if config.VariationName == "" {
@@ -498,3 +583,94 @@ func containsSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestVariationTimeout(t *testing.T) {
+	client := &Client{config: &types.GeminiClientConfig{TimeoutSecs: 30}}
+	if timeout := client.variationTimeout(&types.APIConfiguration{}); timeout != 30*time.Second {
+		t.Errorf("expected the client's timeout, got %v", timeout)
+	}
+	if timeout := client.variationTimeout(&types.APIConfiguration{TimeoutMs: 1500}); timeout != 1500*time.Millisecond {
+		t.Errorf("expected the configuration's timeout, got %v", timeout)
+	}
+
+	// Only the variation's own deadline counts as a timeout, not the run being cancelled
+	variationCtx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	<-variationCtx.Done()
+	err := fmt.Errorf("failed to make request: %w", variationCtx.Err())
+	if !variationTimedOut(context.Background(), err) {
+		t.Errorf("expected a timeout when the variation's deadline passed")
+	}
+	runCtx, cancelRun := context.WithCancel(context.Background())
+	cancelRun()
+	if variationTimedOut(runCtx, err) || variationTimedOut(context.Background(), fmt.Errorf("HTTP error 500")) {
+		t.Errorf("expected no timeout for a cancelled run or another error")
+	}
+}
+
+func TestVertexBackendWithoutProjectUsesMock(t *testing.T) {
+	client := &Client{config: &types.GeminiClientConfig{APIKey: "gemini-api-key"}}
+	config := &types.APIConfiguration{VariationName: "vertex", ModelName: "gemini-2.0-flash-001", Backend: types.BackendVertex}
+
+	response, err := client.callGeminiAPI(context.Background(), config, &types.APIRequest{ID: "request-1", Prompt: "Hello"})
+	if err != nil || response.ResponseStatus != types.ResponseStatusSuccess || response.ResponseTimeMs != 500 {
+		t.Errorf("expected a mock response without a Google Cloud project, got %+v (%v)", response, err)
+	}
+}
+
+func TestVariationLatencyBreakdown(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			time.Sleep(30 * time.Millisecond)
+			w.Write([]byte(`{"message": {"role": "assistant", "content": "", "tool_calls": [{"function": {"name": "get_weather", "arguments": {"location": "Paris"}}}]}, "done_reason": "stop"}`))
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte(`{"message": {"role": "assistant", "content": "It is sunny in Paris."}, "done_reason": "stop"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, store := newStoreTestClient(t)
+	client.config.OllamaURL = server.URL
+	config := &types.APIConfiguration{
+		VariationName: "local",
+		ModelName:     "llama3.2",
+		Provider:      types.ProviderOllama,
+		Deterministic: true,
+		Tools:         []types.Tool{{Name: "get_weather", MockResponse: map[string]interface{}{"condition": "sunny"}}},
+	}
+
+	result, err := client.executeSingleVariation(context.Background(), "user-1", "", config, "Weather in Paris?", "", 250*time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	latency := result.Response.Latency
+	if latency == nil {
+		t.Fatalf("expected a latency breakdown")
+	}
+	if latency.QueuedMs != 250 || latency.ModelMs < 30 || latency.FollowUpMs < 20 || latency.ModelMs >= latency.TotalMs {
+		t.Errorf("expected the queue, model and follow-up times, got %+v", latency)
+	}
+	phases := latency.RetrievalMs + latency.ModelMs + latency.FunctionMs + latency.FollowUpMs + latency.DBMs + latency.OtherMs
+	if phases != latency.TotalMs {
+		t.Errorf("expected the phases to add up to %dms, got %dms", latency.TotalMs, phases)
+	}
+	if stored := store.responses[len(store.responses)-1]; stored.Latency == nil || *stored.Latency != *latency {
+		t.Errorf("expected the breakdown stored with the response, got %+v", stored.Latency)
+	}
+}
+
+func TestCompleteLatency(t *testing.T) {
+	// Without a provider breakdown the whole call is the model's
+	latency := completeLatency(&types.LatencyBreakdown{QueuedMs: 5, RetrievalMs: 10, ModelMs: 100, DBMs: 5}, nil, 120*time.Millisecond)
+	if latency.ModelMs != 100 || latency.OtherMs != 5 || latency.TotalMs != 120 || latency.QueuedMs != 5 {
+		t.Errorf("unexpected breakdown: %+v", latency)
+	}
+
+	latency = completeLatency(&types.LatencyBreakdown{ModelMs: 100}, &types.LatencyBreakdown{ModelMs: 60, FunctionMs: 10, FollowUpMs: 25}, 100*time.Millisecond)
+	if latency.ModelMs != 60 || latency.FunctionMs != 10 || latency.FollowUpMs != 25 || latency.OtherMs != 5 {
+		t.Errorf("expected the provider's phases, got %+v", latency)
+	}
+}
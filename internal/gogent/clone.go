@@ -0,0 +1,107 @@
+package gogent
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"gogent/internal/types"
+)
+
+// CloneRunAsRequest rebuilds the request that produced a run owned by the user so it can be edited
+// and resubmitted. The request is linked to the run through ParentRunID, so the run it starts
+// records where it came from.
+func (c *Client) CloneRunAsRequest(ctx context.Context, userID, executionRunID string) (*types.MultiExecutionRequest, error) {
+	run, err := c.GetExecutionRun(ctx, userID, executionRunID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrExecutionRunNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var request *types.MultiExecutionRequest
+	if run.RunSpec != nil {
+		request = RunSpecRequest(run.RunSpec)
+	} else {
+		// Runs from before specs were recorded are rebuilt from their results
+		source, err := c.GetExecutionResult(ctx, userID, executionRunID)
+		if err != nil {
+			return nil, err
+		}
+		if len(source.Results) == 0 {
+			return nil, fmt.Errorf("execution run %s has no results to clone", executionRunID)
+		}
+		request = buildCloneRequest(source)
+	}
+
+	// A template names the clone afresh; otherwise the clone would reuse the run's unique name
+	if request.NameTemplate != "" {
+		request.ExecutionRunName = ""
+	} else {
+		request.ExecutionRunName = "Copy of " + run.Name
+	}
+	request.ParentRunID = run.ID
+	return request, nil
+}
+
+// buildCloneRequest rebuilds the request that produced a run from its results, including its
+// repetitions
+func buildCloneRequest(source *types.ExecutionResult) *types.MultiExecutionRequest {
+	first := source.Results[0]
+	request := &types.MultiExecutionRequest{
+		Description:           source.ExecutionRun.Description,
+		BasePrompt:            first.Request.Prompt,
+		Context:               first.Request.Context,
+		EnableFunctionCalling: source.ExecutionRun.EnableFunctionCalling,
+		FunctionTools:         first.Configuration.Tools,
+	}
+
+	added := make(map[string]bool)
+	for _, r := range source.Results {
+		request.Repetitions = max(request.Repetitions, r.Repetition)
+		if added[r.Configuration.ID] {
+			continue
+		}
+		added[r.Configuration.ID] = true
+
+		config := r.Configuration
+		config.ID = ""
+		config.ExecutionRunID = ""
+		request.Configurations = append(request.Configurations, config)
+	}
+	return request
+}
+
+// recordParentRun links a run to the run it was cloned from
+func (c *Client) recordParentRun(ctx context.Context, executionRunID, parentRunID string) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	_, err := c.db.ExecContext(ctx,
+		"UPDATE execution_runs SET parent_run_id = ? WHERE id = ?",
+		parentRunID, executionRunID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record parent run link: %w", err)
+	}
+	return nil
+}
+
+// loadParentRun fills in the run an execution run was cloned from, if any
+func (c *Client) loadParentRun(ctx context.Context, run *types.ExecutionRun) error {
+	if c.db == nil {
+		return nil
+	}
+	var parentRunID sql.NullString
+	err := c.db.QueryRowContext(ctx,
+		"SELECT parent_run_id FROM execution_runs WHERE id = ?", run.ID,
+	).Scan(&parentRunID)
+	if err != nil {
+		return fmt.Errorf("failed to load parent run link: %w", err)
+	}
+
+	run.ParentRunID = parentRunID.String
+	return nil
+}
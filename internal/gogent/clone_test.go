@@ -0,0 +1,92 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gogent/internal/types"
+)
+
+func TestCloneRunAsRequest(t *testing.T) {
+	client, _ := newStoreTestClient(t)
+	ctx := context.Background()
+	run, err := client.CreateExecutionRun(ctx, "user-1", "Weather check", "", true)
+	if err != nil {
+		t.Fatalf("failed to create run: %v", err)
+	}
+	spec, err := ParseRunSpec([]byte(testRunSpecYAML))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.db.Exec("INSERT INTO execution_runs (id) VALUES (?)", run.ID); err != nil {
+		t.Fatalf("failed to insert run: %v", err)
+	}
+	if err := client.recordRunSpec(ctx, run.ID, spec); err != nil {
+		t.Fatalf("failed to record run spec: %v", err)
+	}
+
+	request, err := client.CloneRunAsRequest(ctx, "user-1", run.ID)
+	if err != nil {
+		t.Fatalf("failed to clone run: %v", err)
+	}
+	if request.ParentRunID != run.ID || request.ExecutionRunName != "Copy of Weather check" {
+		t.Errorf("expected a copy linked to %s, got %q linked to %q", run.ID, request.ExecutionRunName, request.ParentRunID)
+	}
+	if request.BasePrompt != spec.Prompt || !request.EnableFunctionCalling || len(request.Configurations) != 2 ||
+		*request.Configurations[0].Temperature != 0.2 || !request.Configurations[1].DisableTools {
+		t.Errorf("expected the spec's prompt, tools and configurations, got %+v", request)
+	}
+
+	if _, err := client.CloneRunAsRequest(ctx, "user-2", run.ID); !errors.Is(err, ErrExecutionRunNotFound) {
+		t.Errorf("expected another user's run not to be found, got %v", err)
+	}
+}
+
+func TestBuildCloneRequest(t *testing.T) {
+	tools := []types.Tool{{Name: "get_current_weather"}}
+	source := &types.ExecutionResult{
+		ExecutionRun: types.ExecutionRun{ID: "run-1", Name: "weather-check", Description: "Paris", EnableFunctionCalling: true},
+		Results: []types.VariationResult{
+			{
+				Configuration: types.APIConfiguration{ID: "config-1", ExecutionRunID: "run-1", VariationName: "cold", Tools: tools},
+				Request:       types.APIRequest{Prompt: "What's the weather in Paris?", Context: "travel"},
+				Repetition:    1,
+			},
+			{
+				Configuration: types.APIConfiguration{ID: "config-1", ExecutionRunID: "run-1", VariationName: "cold", Tools: tools},
+				Request:       types.APIRequest{Prompt: "What's the weather in Paris?", Context: "travel"},
+				Repetition:    2,
+			},
+		},
+	}
+
+	request := buildCloneRequest(source)
+	if request.BasePrompt != "What's the weather in Paris?" || request.Context != "travel" || request.Description != "Paris" {
+		t.Errorf("expected the original prompt and context, got %+v", request)
+	}
+	if !request.EnableFunctionCalling || request.Repetitions != 2 || len(request.Configurations) != 1 {
+		t.Fatalf("expected one repeated configuration with function calling, got %+v", request)
+	}
+	if config := request.Configurations[0]; config.ID != "" || config.ExecutionRunID != "" || config.Replay {
+		t.Errorf("expected a fresh configuration, got %+v", config)
+	}
+}
+
+func TestParentRunLink(t *testing.T) {
+	client := newReplayTestClient(t)
+	ctx := context.Background()
+	if _, err := client.db.Exec("INSERT INTO execution_runs (id, user_id, name) VALUES ('run-1', 'user-1', 'a'), ('run-2', 'user-1', 'b')"); err != nil {
+		t.Fatalf("failed to insert runs: %v", err)
+	}
+
+	if err := client.recordParentRun(ctx, "run-2", "run-1"); err != nil {
+		t.Fatalf("failed to record parent run: %v", err)
+	}
+	for id, parent := range map[string]string{"run-1": "", "run-2": "run-1"} {
+		run := &types.ExecutionRun{ID: id}
+		if err := client.loadParentRun(ctx, run); err != nil || run.ParentRunID != parent {
+			t.Errorf("expected %s's parent to be %q, got %q (%v)", id, parent, run.ParentRunID, err)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package gogent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"gogent/internal/gemini"
+	"gogent/internal/types"
+)
+
+// contextCacheTTL bounds how long a run's cache entries live when the run cannot delete them
+const contextCacheTTL = time.Hour
+
+// contextCacheReleaseTimeout bounds deleting a run's cache entries once it finishes
+const contextCacheReleaseTimeout = 30 * time.Second
+
+// cachedInputRate is the share of the input price Gemini bills for prompt tokens read from a cache
+// entry; the entry's storage is billed separately and not estimated
+const cachedInputRate = 0.25
+
+// contextCache holds the cache entries of a run that caches its context. The system instruction
+// and tools of a request using an entry come from the entry, so there is one per model, system
+// instruction and tool set.
+type contextCache struct {
+	context string
+	mu      sync.Mutex
+	entries map[string]*contextCacheEntry
+}
+
+// contextCacheEntry is created by the first variation that needs it; err is set when the model
+// does not support caching or the context is too small to cache
+type contextCacheEntry struct {
+	once sync.Once
+	api  *gemini.Client
+	name string
+	err  error
+}
+
+// contextCacheKey is the context key a run's contextCache is stored under
+type contextCacheKey struct{}
+
+// withContextCache returns a context whose Gemini variations cache the run's shared context
+func withContextCache(ctx context.Context, promptContext string) (context.Context, *contextCache) {
+	cache := &contextCache{context: promptContext, entries: make(map[string]*contextCacheEntry)}
+	return context.WithValue(ctx, contextCacheKey{}, cache), cache
+}
+
+// entry returns the cache entry for a key, adding an uncreated one the first time
+func (cc *contextCache) entry(key string) *contextCacheEntry {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	entry, ok := cc.entries[key]
+	if !ok {
+		entry = &contextCacheEntry{}
+		cc.entries[key] = entry
+	}
+	return entry
+}
+
+// cachedGeminiRequest builds a configuration's first Gemini call against the run's cache entry,
+// creating the entry on first use. It returns nil, so the uncached request is sent, when the run
+// does not cache its context, when retrieval or a guard changed the variation's context, on Vertex
+// AI, or when the entry could not be created.
+func (c *Client) cachedGeminiRequest(ctx context.Context, api *gemini.Client, config *types.APIConfiguration, request *types.APIRequest) *gemini.GenerateContentRequest {
+	cache, ok := ctx.Value(contextCacheKey{}).(*contextCache)
+	if !ok || request.Context != cache.context || config.Backend == types.BackendVertex {
+		return nil
+	}
+
+	// The context moves into the entry, ahead of the prompt
+	withoutContext := *request
+	withoutContext.Context = ""
+	generateRequest, _ := geminiRequest(config, &withoutContext)
+
+	key, err := json.Marshal([]interface{}{config.ModelName, generateRequest.SystemInstruction, generateRequest.Tools, generateRequest.ToolConfig})
+	if err != nil {
+		return nil
+	}
+	entry := cache.entry(string(key))
+	entry.once.Do(func() {
+		created, err := api.CreateCachedContent(ctx, config.ModelName, &gemini.CachedContent{
+			Contents:          []gemini.Content{gemini.UserContent("Context: " + cache.context)},
+			SystemInstruction: generateRequest.SystemInstruction,
+			Tools:             generateRequest.Tools,
+			ToolConfig:        generateRequest.ToolConfig,
+			TTL:               fmt.Sprintf("%ds", int(contextCacheTTL.Seconds())),
+		})
+		if err != nil {
+			entry.err = err
+			c.logExecutionEvent(ctx, types.LogLevelWarn, types.LogCategoryAPICall,
+				fmt.Sprintf("Context caching is unavailable for %s, so the context is sent with each request: %v", config.ModelName, err), nil)
+			return
+		}
+		entry.api, entry.name = api, created.Name
+		c.logExecutionEvent(ctx, types.LogLevelInfo, types.LogCategoryAPICall,
+			fmt.Sprintf("Cached %d context tokens for %s as %s", created.UsageMetadata.TotalTokenCount, config.ModelName, created.Name), nil)
+	})
+	if entry.err != nil {
+		return nil
+	}
+
+	generateRequest.SystemInstruction, generateRequest.Tools, generateRequest.ToolConfig = nil, nil, nil
+	generateRequest.CachedContent = entry.name
+	return generateRequest
+}
+
+// releaseContextCache deletes the cache entries a run created; entries it cannot delete expire
+// after contextCacheTTL
+func (c *Client) releaseContextCache(cache *contextCache) {
+	ctx, cancel := context.WithTimeout(context.Background(), contextCacheReleaseTimeout)
+	defer cancel()
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	for _, entry := range cache.entries {
+		if entry.name == "" {
+			continue
+		}
+		if err := entry.api.DeleteCachedContent(ctx, entry.name); err != nil {
+			log.Printf("⚠️ Warning: failed to delete context cache %s: %v", entry.name, err)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package gogent
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"gogent/internal/types"
+)
+
+func TestContextCache(t *testing.T) {
+	var mu sync.Mutex
+	var created, deleted []string
+	client := NewInMemoryClient(&types.GeminiClientConfig{APIKey: "gemini-api-key"})
+	client.SetHTTPTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		var body map[string]interface{}
+		if req.Body != nil {
+			json.NewDecoder(req.Body).Decode(&body)
+		}
+		reply := func(status int, body string) (*http.Response, error) {
+			return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+		}
+
+		switch {
+		case req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/cachedContents"):
+			if body["model"] == "models/gemini-1.0-pro" {
+				return reply(http.StatusBadRequest, `{"error": {"code": 400, "message": "Model does not support caching"}}`)
+			}
+			created = append(created, body["model"].(string))
+			return reply(http.StatusOK, `{"name": "cachedContents/shared", "usageMetadata": {"totalTokenCount": 4096}}`)
+		case req.Method == http.MethodDelete:
+			deleted = append(deleted, strings.TrimPrefix(req.URL.Path, "/v1beta/"))
+			return reply(http.StatusOK, `{}`)
+		}
+
+		prompt := body["contents"].([]interface{})[0].(map[string]interface{})["parts"].([]interface{})[0].(map[string]interface{})["text"].(string)
+		if body["cachedContent"] == "cachedContents/shared" {
+			if strings.Contains(prompt, "Context:") || body["systemInstruction"] != nil || body["tools"] != nil {
+				t.Errorf("expected the context, instruction and tools to come from the cache entry, got %v", body)
+			}
+			return reply(http.StatusOK, `{"candidates": [{"content": {"parts": [{"text": "Cached answer"}]}, "finishReason": "STOP"}],
+				"usageMetadata": {"promptTokenCount": 4106, "candidatesTokenCount": 5, "totalTokenCount": 4111, "cachedContentTokenCount": 4096}}`)
+		}
+		if !strings.Contains(prompt, "Context: A long shared document") {
+			t.Errorf("expected an uncached request to carry the context, got %q", prompt)
+		}
+		return reply(http.StatusOK, `{"candidates": [{"content": {"parts": [{"text": "Plain answer"}]}, "finishReason": "STOP"}],
+			"usageMetadata": {"promptTokenCount": 4106, "candidatesTokenCount": 5, "totalTokenCount": 4111}}`)
+	}))
+
+	cool, warm := float32(0.2), float32(0.9)
+	result, err := client.ExecuteMultiVariation(context.Background(), "user-1", &types.MultiExecutionRequest{
+		BasePrompt: "Summarize the document",
+		Context:    "A long shared document",
+		Configurations: []types.APIConfiguration{
+			{VariationName: "cool", ModelName: "gemini-2.0-flash", Temperature: &cool},
+			{VariationName: "warm", ModelName: "gemini-2.0-flash", Temperature: &warm},
+			{VariationName: "legacy", ModelName: "gemini-1.0-pro"},
+		},
+		CacheContext: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(created) != 1 || created[0] != "models/gemini-2.0-flash" {
+		t.Errorf("expected one cache entry shared by both gemini-2.0-flash variations, got %v", created)
+	}
+	if len(deleted) != 1 || deleted[0] != "cachedContents/shared" {
+		t.Errorf("expected the cache entry deleted once the run finished, got %v", deleted)
+	}
+	for _, variation := range result.Results {
+		cached := usageTokens(variation.Response.UsageMetadata, "cached_tokens")
+		switch variation.Configuration.VariationName {
+		case "legacy":
+			if variation.Response.ResponseText != "Plain answer" || cached != 0 {
+				t.Errorf("expected the unsupported model to fall back to an uncached request, got %+v", variation.Response)
+			}
+		default:
+			if variation.Response.ResponseText != "Cached answer" || cached != 4096 {
+				t.Errorf("expected %s to read 4096 tokens from the cache, got %+v", variation.Configuration.VariationName, variation.Response)
+			}
+			uncached := EstimateCostUSD("gemini-2.0-flash", 4106, 5)
+			if cost := ResponseCostUSD("gemini-2.0-flash", variation.Response); math.Abs(uncached-cost-4096*0.10*0.75/1e6) > 1e-12 {
+				t.Errorf("expected cached tokens billed at the cached rate, got %v against %v", cost, uncached)
+			}
+		}
+	}
+}
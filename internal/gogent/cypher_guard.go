@@ -0,0 +1,124 @@
+package gogent
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// cypherLimitParameter is the query parameter the result limit is passed in
+const cypherLimitParameter = "resultLimit"
+
+// cypherWriteClauses are rejected anywhere in a query_graph query, outside strings, quoted names,
+// comments and property keys
+var cypherWriteClauses = map[string]bool{
+	"CREATE": true, "DELETE": true, "DETACH": true, "SET": true, "REMOVE": true, "MERGE": true,
+	"DROP": true, "LOAD": true, "FOREACH": true, "ALTER": true, "GRANT": true, "DENY": true,
+	"REVOKE": true, "START": true, "STOP": true, "TERMINATE": true, "USE": true,
+}
+
+// cypherReadProcedures are the procedures a query_graph query may CALL, by upper-cased name. Only
+// these schema and full-text lookups are allowed; other db procedures such as db.createLabel write.
+var cypherReadProcedures = map[string]bool{
+	"DB.LABELS": true, "DB.RELATIONSHIPTYPES": true, "DB.PROPERTYKEYS": true,
+	"DB.SCHEMA.VISUALIZATION": true, "DB.SCHEMA.NODETYPEPROPERTIES": true, "DB.SCHEMA.RELTYPEPROPERTIES": true,
+	"DB.INDEX.FULLTEXT.QUERYNODES": true, "DB.INDEX.FULLTEXT.QUERYRELATIONSHIPS": true,
+}
+
+// cypherQuery is a query_graph query that passed validateCypherQuery
+type cypherQuery struct {
+	Text     string // The query, without a trailing semicolon
+	HasLimit bool   // Whether the query sets its own LIMIT
+}
+
+// validateCypherQuery checks that query is a single read-only Cypher statement. Only the procedures
+// in cypherReadProcedures may be called.
+func validateCypherQuery(query string) (*cypherQuery, error) {
+	query = strings.TrimRight(strings.TrimSpace(query), "; \t\n")
+	if query == "" {
+		return nil, fmt.Errorf("query is empty")
+	}
+
+	words, err := cypherKeywords(query)
+	if err != nil {
+		return nil, err
+	}
+	validated := &cypherQuery{Text: query}
+	for i, word := range words {
+		switch {
+		case cypherWriteClauses[word]:
+			return nil, fmt.Errorf("only read queries are allowed: %s is not permitted", word)
+		case word == "CALL" && (i+1 == len(words) || !cypherReadProcedures[words[i+1]]):
+			return nil, fmt.Errorf("only read queries are allowed: CALL is limited to read-only db procedures such as db.labels")
+		case word == "LIMIT":
+			validated.HasLimit = true
+		}
+	}
+	return validated, nil
+}
+
+// cypherKeywords returns the upper-cased words of query outside string literals, backtick-quoted
+// names and comments. Dotted names such as n.name or db.labels are one word, so property keys are
+// never taken for keywords. Multiple statements are rejected.
+func cypherKeywords(query string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	afterDot := false
+	flush := func() {
+		if word.Len() > 0 {
+			if !afterDot {
+				words = append(words, strings.ToUpper(word.String()))
+			}
+			word.Reset()
+		}
+		afterDot = false
+	}
+
+	runes := []rune(query)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\'' || r == '"' || r == '`':
+			flush()
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				if runes[end] == '\\' && r != '`' {
+					end++
+				}
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated quote in query")
+			}
+			i = end
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			flush()
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			flush()
+			end := i + 2
+			for end+1 < len(runes) && !(runes[end] == '*' && runes[end+1] == '/') {
+				end++
+			}
+			if end+1 >= len(runes) {
+				return nil, fmt.Errorf("unterminated comment in query")
+			}
+			i = end + 1
+		case r == ';':
+			return nil, fmt.Errorf("only a single statement is allowed")
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || (r == '$' && word.Len() == 0):
+			word.WriteRune(r)
+		case r == '.' && word.Len() > 0:
+			word.WriteRune(r)
+		case r == '.':
+			flush()
+			afterDot = true
+		default:
+			flush()
+		}
+	}
+	flush()
+	return words, nil
+}
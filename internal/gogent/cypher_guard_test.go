@@ -0,0 +1,56 @@
+package gogent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gogent/internal/types"
+)
+
+func TestValidateCypherQuery(t *testing.T) {
+	tests := []struct {
+		query     string
+		allowed   bool
+		wantLimit bool
+	}{
+		{"MATCH (n:Person) RETURN n.name", true, false},
+		{"MATCH (n:Person) RETURN n.name LIMIT 10;", true, true},
+		{"MATCH (n {name: 'create delete'}) RETURN n", true, false},
+		{"MATCH (n) WHERE n.set = $value RETURN n.create", true, false},
+		{"MATCH (n) RETURN n // SET n.admin = true", true, false},
+		{"CALL db.labels()", true, false},
+		{"CALL db.schema.visualization() YIELD nodes RETURN nodes LIMIT 1", true, true},
+		{"CALL db.createLabel('Admin')", false, false},
+		{"CALL db.createProperty('admin')", false, false},
+		{"CALL db . labels()", false, false},
+		{"CALL { MATCH (n) RETURN n }", false, false},
+		{"CREATE (n:Person {name: 'x'})", false, false},
+		{"MATCH (n) DETACH DELETE n", false, false},
+		{"MATCH (n) set n.admin = true RETURN n", false, false},
+		{"MERGE (n:Person {name: $name})", false, false},
+		{"MATCH (n) RETURN n; MATCH (m) DELETE m", false, false},
+		{"LOAD CSV FROM 'file:///x.csv' AS row RETURN row", false, false},
+		{"CALL apoc.periodic.iterate('MATCH (n) RETURN n', 'DELETE n', {})", false, false},
+		{"MATCH (n) /* unterminated RETURN n", false, false},
+		{"", false, false},
+	}
+	for _, tt := range tests {
+		query, err := validateCypherQuery(tt.query)
+		if (err == nil) != tt.allowed {
+			t.Errorf("validateCypherQuery(%q) error = %v, want allowed %v", tt.query, err, tt.allowed)
+			continue
+		}
+		if err == nil && query.HasLimit != tt.wantLimit {
+			t.Errorf("validateCypherQuery(%q) HasLimit = %v, want %v", tt.query, query.HasLimit, tt.wantLimit)
+		}
+	}
+}
+
+func TestQueryGraphRejectsWrites(t *testing.T) {
+	client := &Client{config: &types.GeminiClientConfig{}}
+	result, err := client.callFunction(context.Background(), "query_graph", map[string]interface{}{"query": "MATCH (n) DELETE n"})
+	if err == nil || !strings.Contains(err.Error(), "DELETE") || result != nil {
+		t.Errorf("expected the write to be rejected without mock data, got %v (%v)", result, err)
+	}
+}
@@ -0,0 +1,150 @@
+package gogent
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"gogent/internal/types"
+)
+
+// determinismFingerprintVersion changes whenever the fingerprint inputs change, so old and new
+// fingerprints never collide
+const determinismFingerprintVersion = 1
+
+// applyDeterminism pins a configuration's sampling: temperature 0, greedy top-k, no nucleus
+// sampling, a fixed seed and pinned tool responses
+func applyDeterminism(config *types.APIConfiguration, seed int32) {
+	temperature := float32(0)
+	topK := int32(1)
+
+	config.Temperature = &temperature
+	config.TopK = &topK
+	config.TopP = nil
+	config.Seed = &seed
+	config.Deterministic = true
+}
+
+// pinnedToolResponse returns the tool's mock response, or a fixed placeholder, so tool results
+// never depend on live APIs during deterministic runs
+func pinnedToolResponse(tools []types.Tool, functionName string, args map[string]interface{}) map[string]interface{} {
+	for _, tool := range tools {
+		if tool.Name == functionName && len(tool.MockResponse) > 0 {
+			return tool.MockResponse
+		}
+	}
+
+	return map[string]interface{}{
+		"status":    "success",
+		"message":   fmt.Sprintf("Function %s executed (pinned response for deterministic run)", functionName),
+		"arguments": args,
+	}
+}
+
+// fingerprintConfiguration holds the configuration fields that influence a variation's output
+type fingerprintConfiguration struct {
+	VariationName       string                 `json:"variationName"`
+	ModelName           string                 `json:"modelName"`
+	SystemPrompt        string                 `json:"systemPrompt"`
+	InlineSystemPrompt  bool                   `json:"inlineSystemPrompt,omitempty"`
+	Temperature         *float32               `json:"temperature"`
+	MaxTokens           *int32                 `json:"maxTokens"`
+	TopP                *float32               `json:"topP"`
+	TopK                *int32                 `json:"topK"`
+	Seed                *int32                 `json:"seed"`
+	ThinkingBudget      *int32                 `json:"thinkingBudget,omitempty"`
+	CandidateCount      *int32                 `json:"candidateCount,omitempty"`
+	SafetySettings      map[string]interface{} `json:"safetySettings"`
+	Tools               []types.Tool           `json:"tools"`
+	FunctionInstruction string                 `json:"functionInstruction"`
+	ResponseMimeType    string                 `json:"responseMimeType,omitempty"`
+	ResponseSchema      map[string]interface{} `json:"responseSchema,omitempty"`
+	Retrieval           *types.RetrievalConfig `json:"retrieval,omitempty"`
+	Guardrails          []types.GuardConfig    `json:"guardrails,omitempty"`
+	ToolChoiceMode      string                 `json:"toolChoiceMode,omitempty"`
+	AllowedFunctions    []string               `json:"allowedFunctionNames,omitempty"`
+}
+
+// DeterminismFingerprint hashes every input that influences a run's outputs: prompt, context,
+// whether responses are mocked, and each configuration's resolved model, sampling, safety and tool
+// settings. Two deterministic runs with the same fingerprint should produce identical responses.
+func DeterminismFingerprint(request *types.MultiExecutionRequest, configs []types.APIConfiguration, mock bool) string {
+	fingerprintConfigs := make([]fingerprintConfiguration, len(configs))
+	for i, config := range configs {
+		instruction := ""
+		if len(config.Tools) > 0 {
+			instruction = resolveFunctionInstruction(&config)
+		}
+
+		fingerprintConfigs[i] = fingerprintConfiguration{
+			VariationName:       config.VariationName,
+			ModelName:           config.ModelName,
+			SystemPrompt:        config.SystemPrompt,
+			InlineSystemPrompt:  config.InlineSystemPrompt,
+			Temperature:         config.Temperature,
+			MaxTokens:           config.MaxTokens,
+			TopP:                config.TopP,
+			TopK:                config.TopK,
+			Seed:                config.Seed,
+			ThinkingBudget:      config.ThinkingBudget,
+			CandidateCount:      config.CandidateCount,
+			SafetySettings:      config.SafetySettings,
+			Tools:               config.Tools,
+			FunctionInstruction: instruction,
+			ResponseMimeType:    config.ResponseMimeType,
+			ResponseSchema:      config.ResponseSchema,
+			Retrieval:           config.Retrieval,
+			Guardrails:          config.Guardrails,
+			ToolChoiceMode:      config.ToolChoiceMode,
+			AllowedFunctions:    config.AllowedFunctionNames,
+		}
+	}
+
+	// encoding/json sorts map keys, so equal inputs always serialize identically
+	payload, _ := json.Marshal(map[string]interface{}{
+		"version":               determinismFingerprintVersion,
+		"basePrompt":            request.BasePrompt,
+		"context":               request.Context,
+		"enableFunctionCalling": request.EnableFunctionCalling,
+		"mock":                  mock,
+		"configurations":        fingerprintConfigs,
+	})
+
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// recordDeterminism marks an execution run as deterministic and stores its fingerprint
+func (c *Client) recordDeterminism(ctx context.Context, executionRunID, fingerprint string) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	_, err := c.db.ExecContext(ctx,
+		"UPDATE execution_runs SET deterministic = TRUE, determinism_fingerprint = ? WHERE id = ?",
+		fingerprint, executionRunID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record determinism fingerprint: %w", err)
+	}
+	return nil
+}
+
+// loadDeterminism fills in an execution run's deterministic flag and fingerprint
+func (c *Client) loadDeterminism(ctx context.Context, run *types.ExecutionRun) error {
+	if c.db == nil {
+		return nil
+	}
+	var fingerprint sql.NullString
+	err := c.db.QueryRowContext(ctx,
+		"SELECT deterministic, determinism_fingerprint FROM execution_runs WHERE id = ?", run.ID,
+	).Scan(&run.Deterministic, &fingerprint)
+	if err != nil {
+		return fmt.Errorf("failed to load determinism fingerprint: %w", err)
+	}
+
+	run.DeterminismFingerprint = fingerprint.String
+	return nil
+}
@@ -0,0 +1,118 @@
+package gogent
+
+import (
+	"testing"
+
+	"gogent/internal/types"
+)
+
+func TestApplyDeterminism(t *testing.T) {
+	temperature := float32(0.9)
+	topP := float32(0.95)
+	config := &types.APIConfiguration{
+		VariationName: "creative",
+		ModelName:     "gemini-1.5-flash",
+		Temperature:   &temperature,
+		TopP:          &topP,
+	}
+
+	applyDeterminism(config, 7)
+
+	if config.Temperature == nil || *config.Temperature != 0 {
+		t.Errorf("expected temperature 0, got %v", config.Temperature)
+	}
+	if config.TopK == nil || *config.TopK != 1 {
+		t.Errorf("expected greedy top-k, got %v", config.TopK)
+	}
+	if config.TopP != nil {
+		t.Errorf("expected nucleus sampling to be disabled, got %v", *config.TopP)
+	}
+	if config.Seed == nil || *config.Seed != 7 {
+		t.Errorf("expected seed 7, got %v", config.Seed)
+	}
+	if !config.Deterministic {
+		t.Error("expected configuration to be marked deterministic")
+	}
+}
+
+func TestPinnedToolResponse(t *testing.T) {
+	tools := []types.Tool{
+		{Name: "get_current_weather", MockResponse: map[string]interface{}{"temperature": 72}},
+		{Name: "query_graph"},
+	}
+
+	t.Run("uses_mock_response", func(t *testing.T) {
+		result := pinnedToolResponse(tools, "get_current_weather", map[string]interface{}{"location": "Paris"})
+		if result["temperature"] != 72 {
+			t.Errorf("expected the tool's mock response, got %v", result)
+		}
+	})
+
+	t.Run("falls_back_to_fixed_response", func(t *testing.T) {
+		args := map[string]interface{}{"query": "MATCH (n) RETURN n"}
+		first := pinnedToolResponse(tools, "query_graph", args)
+		second := pinnedToolResponse(tools, "query_graph", args)
+		if first["status"] != "success" || first["message"] != second["message"] {
+			t.Errorf("expected identical fixed responses, got %v and %v", first, second)
+		}
+	})
+}
+
+func TestDeterminismFingerprint(t *testing.T) {
+	newRequest := func() (*types.MultiExecutionRequest, []types.APIConfiguration) {
+		request := &types.MultiExecutionRequest{BasePrompt: "Summarize the report", Context: "Q3"}
+		config := types.APIConfiguration{
+			ID:             "config-1",
+			VariationName:  "precise",
+			ModelName:      "gemini-1.5-flash",
+			SafetySettings: map[string]interface{}{"HARM_CATEGORY_HARASSMENT": "BLOCK_NONE", "HARM_CATEGORY_HATE_SPEECH": "BLOCK_ONLY_HIGH"},
+		}
+		applyDeterminism(&config, 0)
+		return request, []types.APIConfiguration{config}
+	}
+
+	request, configs := newRequest()
+	baseline := DeterminismFingerprint(request, configs, false)
+	if len(baseline) != 64 {
+		t.Fatalf("expected a SHA-256 hex fingerprint, got %q", baseline)
+	}
+
+	t.Run("stable_across_reruns", func(t *testing.T) {
+		rerun, rerunConfigs := newRequest()
+		rerunConfigs[0].ID = "config-2" // IDs are generated per run and must not matter
+		if got := DeterminismFingerprint(rerun, rerunConfigs, false); got != baseline {
+			t.Errorf("expected identical fingerprints, got %s and %s", baseline, got)
+		}
+	})
+
+	t.Run("changes_with_inputs", func(t *testing.T) {
+		changes := map[string]func(*types.MultiExecutionRequest, []types.APIConfiguration) bool{
+			"prompt": func(r *types.MultiExecutionRequest, c []types.APIConfiguration) bool {
+				r.BasePrompt = "Summarize the report briefly"
+				return false
+			},
+			"seed": func(r *types.MultiExecutionRequest, c []types.APIConfiguration) bool {
+				seed := int32(1)
+				c[0].Seed = &seed
+				return false
+			},
+			"model": func(r *types.MultiExecutionRequest, c []types.APIConfiguration) bool {
+				c[0].ModelName = "gemini-1.5-pro"
+				return false
+			},
+			"mock": func(r *types.MultiExecutionRequest, c []types.APIConfiguration) bool {
+				return true
+			},
+		}
+
+		for name, change := range changes {
+			t.Run(name, func(t *testing.T) {
+				changed, changedConfigs := newRequest()
+				mock := change(changed, changedConfigs)
+				if got := DeterminismFingerprint(changed, changedConfigs, mock); got == baseline {
+					t.Errorf("expected fingerprint to change when %s changes", name)
+				}
+			})
+		}
+	})
+}
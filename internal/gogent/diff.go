@@ -0,0 +1,290 @@
+package gogent
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"gogent/internal/types"
+)
+
+// ErrConfigurationNotFound is returned when a run has no configuration with an ID
+var ErrConfigurationNotFound = errors.New("configuration not found in execution run")
+
+// maxDiffEdits caps how many words a diff inserts and deletes before it gives up aligning the
+// responses and replaces the rest of one with the rest of the other
+const maxDiffEdits = 2000
+
+// diffToken matches a word or a single punctuation character with the whitespace before it, so
+// changed words do not share their spaces with the words around them
+var diffToken = regexp.MustCompile(`\s*[\p{L}\p{N}_']+|\s*[^\s\p{L}\p{N}_']|\s+`)
+
+// diffExcludedScores are comparison scores that are not metrics of the response
+var diffExcludedScores = map[string]bool{
+	"configuration_id":     true,
+	"response_time_ms":     true,
+	"temperature":          true,
+	"semantic_cluster":     true,
+	"repetitions":          true,
+	"function_round_trips": true,
+	"function_errors":      true,
+	"function_latency_ms":  true,
+}
+
+// DiffVariations compares the responses of two configurations of one of the user's runs, word by
+// word, along with their metrics. A repeated configuration is represented by its first sample.
+func (c *Client) DiffVariations(ctx context.Context, userID, executionRunID, configurationA, configurationB string) (*types.VariationDiff, error) {
+	result, err := c.GetExecutionResult(ctx, userID, executionRunID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrExecutionRunNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	a := firstSample(result.Results, configurationA)
+	if a == nil {
+		return nil, fmt.Errorf("%w: %s", ErrConfigurationNotFound, configurationA)
+	}
+	b := firstSample(result.Results, configurationB)
+	if b == nil {
+		return nil, fmt.Errorf("%w: %s", ErrConfigurationNotFound, configurationB)
+	}
+
+	diff := &types.VariationDiff{
+		ExecutionRunID: executionRunID,
+		A:              a.Configuration,
+		B:              b.Configuration,
+		Segments:       DiffText(a.Response.ResponseText, b.Response.ResponseText),
+	}
+	diff.WordsAdded, diff.WordsRemoved, diff.Similarity = diffStatistics(diff.Segments)
+	diff.Metrics = metricDeltas(*a, *b, result.Comparison)
+	return diff, nil
+}
+
+// firstSample returns the result of a configuration with the lowest repetition
+func firstSample(results []types.VariationResult, configurationID string) *types.VariationResult {
+	var first *types.VariationResult
+	for i := range results {
+		if results[i].Configuration.ID == configurationID && (first == nil || results[i].Repetition < first.Repetition) {
+			first = &results[i]
+		}
+	}
+	return first
+}
+
+// DiffText returns a word-level diff turning a into b, with adjacent segments of the same kind merged
+func DiffText(a, b string) []types.DiffSegment {
+	tokensA := diffToken.FindAllString(a, -1)
+	tokensB := diffToken.FindAllString(b, -1)
+
+	// Common ends are aligned without searching
+	prefix := 0
+	for prefix < len(tokensA) && prefix < len(tokensB) && tokensA[prefix] == tokensB[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(tokensA)-prefix && suffix < len(tokensB)-prefix &&
+		tokensA[len(tokensA)-1-suffix] == tokensB[len(tokensB)-1-suffix] {
+		suffix++
+	}
+
+	var segments []types.DiffSegment
+	add := func(op types.DiffOp, tokens ...string) {
+		text := strings.Join(tokens, "")
+		if text == "" {
+			return
+		}
+		if last := len(segments) - 1; last >= 0 && segments[last].Op == op {
+			segments[last].Text += text
+			return
+		}
+		segments = append(segments, types.DiffSegment{Op: op, Text: text})
+	}
+
+	add(types.DiffOpEqual, tokensA[:prefix]...)
+	middleA := tokensA[prefix : len(tokensA)-suffix]
+	middleB := tokensB[prefix : len(tokensB)-suffix]
+	if edits, ok := myersDiff(middleA, middleB, maxDiffEdits); ok {
+		// Deletions are listed before the insertions that replace them
+		for i := 0; i < len(edits); {
+			if edits[i].op == types.DiffOpEqual {
+				add(types.DiffOpEqual, edits[i].token)
+				i++
+				continue
+			}
+			var deleted, inserted []string
+			for ; i < len(edits) && edits[i].op != types.DiffOpEqual; i++ {
+				if edits[i].op == types.DiffOpDelete {
+					deleted = append(deleted, edits[i].token)
+				} else {
+					inserted = append(inserted, edits[i].token)
+				}
+			}
+			add(types.DiffOpDelete, deleted...)
+			add(types.DiffOpInsert, inserted...)
+		}
+	} else {
+		add(types.DiffOpDelete, middleA...)
+		add(types.DiffOpInsert, middleB...)
+	}
+	add(types.DiffOpEqual, tokensA[len(tokensA)-suffix:]...)
+
+	if segments == nil {
+		segments = []types.DiffSegment{}
+	}
+	return segments
+}
+
+// diffEdit keeps, inserts or deletes one token
+type diffEdit struct {
+	op    types.DiffOp
+	token string
+}
+
+// myersDiff returns a shortest edit script turning a into b using Myers' algorithm, or false when
+// it needs more than maxEdits insertions and deletions
+func myersDiff(a, b []string, maxEdits int) ([]diffEdit, bool) {
+	n, m := len(a), len(b)
+	limit := min(n+m, maxEdits)
+
+	// trace[d][k+d] is the furthest x reached on diagonal k = x - y with d edits
+	var trace [][]int
+	furthest := func(d, k int) int { return trace[d][k+d] }
+	for d := 0; d <= limit; d++ {
+		current := make([]int, 2*d+1)
+		for k := -d; k <= d; k += 2 {
+			var x int
+			switch {
+			case d == 0:
+				x = 0
+			case k == -d || (k != d && furthest(d-1, k-1) < furthest(d-1, k+1)):
+				x = furthest(d-1, k+1)
+			default:
+				x = furthest(d-1, k-1) + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			current[k+d] = x
+
+			if x >= n && y >= m {
+				trace = append(trace, current)
+				return myersBacktrack(a, b, trace), true
+			}
+		}
+		trace = append(trace, current)
+	}
+	return nil, false
+}
+
+// myersBacktrack walks the furthest points recorded by myersDiff back from the end of both inputs
+func myersBacktrack(a, b []string, trace [][]int) []diffEdit {
+	furthest := func(d, k int) int { return trace[d][k+d] }
+	var edits []diffEdit
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d > 0; d-- {
+		k := x - y
+		previous := k - 1
+		if k == -d || (k != d && furthest(d-1, k-1) < furthest(d-1, k+1)) {
+			previous = k + 1
+		}
+		previousX := furthest(d-1, previous)
+		previousY := previousX - previous
+
+		for x > previousX && y > previousY {
+			x--
+			y--
+			edits = append(edits, diffEdit{op: types.DiffOpEqual, token: a[x]})
+		}
+		if x == previousX {
+			y--
+			edits = append(edits, diffEdit{op: types.DiffOpInsert, token: b[y]})
+		} else {
+			x--
+			edits = append(edits, diffEdit{op: types.DiffOpDelete, token: a[x]})
+		}
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		edits = append(edits, diffEdit{op: types.DiffOpEqual, token: a[x]})
+	}
+	slices.Reverse(edits)
+	return edits
+}
+
+// diffStatistics counts the words a diff inserts and deletes and the share of words it keeps.
+// Whitespace and punctuation are not counted as words.
+func diffStatistics(segments []types.DiffSegment) (added, removed int, similarity float64) {
+	kept := 0
+	for _, segment := range segments {
+		words := countDiffWords(segment.Text)
+		switch segment.Op {
+		case types.DiffOpEqual:
+			kept += words
+		case types.DiffOpInsert:
+			added += words
+		case types.DiffOpDelete:
+			removed += words
+		}
+	}
+	if total := 2*kept + added + removed; total > 0 {
+		return added, removed, float64(2*kept) / float64(total)
+	}
+	return added, removed, 1
+}
+
+// countDiffWords counts the words of text as DiffText splits them
+func countDiffWords(text string) int {
+	words := 0
+	for _, token := range diffToken.FindAllString(text, -1) {
+		if strings.IndexFunc(token, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsNumber(r) }) >= 0 {
+			words++
+		}
+	}
+	return words
+}
+
+// metricDeltas lists the response metrics of two variations, followed by the numeric scores the
+// run's comparison gave them in alphabetical order
+func metricDeltas(a, b types.VariationResult, comparison *types.ComparisonResult) []types.MetricDelta {
+	delta := func(metric string, valueA, valueB float64) types.MetricDelta {
+		return types.MetricDelta{Metric: metric, A: valueA, B: valueB, Delta: valueB - valueA}
+	}
+	deltas := []types.MetricDelta{
+		delta("response_time_ms", float64(a.Response.ResponseTimeMs), float64(b.Response.ResponseTimeMs)),
+		delta("prompt_tokens", float64(usageTokens(a.Response.UsageMetadata, "prompt_tokens")), float64(usageTokens(b.Response.UsageMetadata, "prompt_tokens"))),
+		delta("completion_tokens", float64(usageTokens(a.Response.UsageMetadata, "completion_tokens")), float64(usageTokens(b.Response.UsageMetadata, "completion_tokens"))),
+		delta("cost_usd", ResponseCostUSD(a.Configuration.ModelName, a.Response), ResponseCostUSD(b.Configuration.ModelName, b.Response)),
+		delta("word_count", float64(countDiffWords(a.Response.ResponseText)), float64(countDiffWords(b.Response.ResponseText))),
+	}
+	if comparison == nil {
+		return deltas
+	}
+
+	scoresA, _ := comparison.ConfigurationScores[a.Configuration.VariationName].(map[string]interface{})
+	scoresB, _ := comparison.ConfigurationScores[b.Configuration.VariationName].(map[string]interface{})
+	var metrics []string
+	for metric := range scoresA {
+		if _, ok := scoresB[metric]; ok && !diffExcludedScores[metric] {
+			metrics = append(metrics, metric)
+		}
+	}
+	slices.Sort(metrics)
+	for _, metric := range metrics {
+		valueA, okA := numericScore(scoresA[metric])
+		valueB, okB := numericScore(scoresB[metric])
+		if okA && okB {
+			deltas = append(deltas, delta(metric, valueA, valueB))
+		}
+	}
+	return deltas
+}
@@ -0,0 +1,153 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gogent/internal/types"
+)
+
+// joinSegments rebuilds the text a diff keeps along with the segments of one kind
+func joinSegments(segments []types.DiffSegment, op types.DiffOp) string {
+	var b strings.Builder
+	for _, segment := range segments {
+		if segment.Op == types.DiffOpEqual || segment.Op == op {
+			b.WriteString(segment.Text)
+		}
+	}
+	return b.String()
+}
+
+func TestDiffText(t *testing.T) {
+	segments := DiffText("Paris is the capital of France.", "Paris is France's capital city.")
+	want := []types.DiffSegment{
+		{Op: types.DiffOpEqual, Text: "Paris is"},
+		{Op: types.DiffOpDelete, Text: " the"},
+		{Op: types.DiffOpInsert, Text: " France's"},
+		{Op: types.DiffOpEqual, Text: " capital"},
+		{Op: types.DiffOpDelete, Text: " of France"},
+		{Op: types.DiffOpInsert, Text: " city"},
+		{Op: types.DiffOpEqual, Text: "."},
+	}
+	if len(segments) != len(want) {
+		t.Fatalf("expected %+v, got %+v", want, segments)
+	}
+	for i := range want {
+		if segments[i] != want[i] {
+			t.Errorf("segment %d: expected %+v, got %+v", i, want[i], segments[i])
+		}
+	}
+
+	added, removed, similarity := diffStatistics(segments)
+	if added != 2 || removed != 3 || similarity != 6.0/11 {
+		t.Errorf("expected 2 added, 3 removed and 6/11 similar, got %d, %d, %v", added, removed, similarity)
+	}
+
+	if segments := DiffText("", ""); len(segments) != 0 {
+		t.Errorf("expected no segments for empty responses, got %+v", segments)
+	}
+	if _, _, similarity := diffStatistics(DiffText("same words", "same words")); similarity != 1 {
+		t.Errorf("expected identical responses to be fully similar, got %v", similarity)
+	}
+}
+
+func TestDiffTextRebuildsBothResponses(t *testing.T) {
+	pairs := [][2]string{
+		{"The quick brown fox jumps over the lazy dog.", "A quick red fox leapt over the sleeping dog!"},
+		{"one two three", ""},
+		{"", "one two three"},
+		{"Line one.\n\nLine two.", "Line one.\nLine 2.\n\nLine three."},
+	}
+	for _, pair := range pairs {
+		segments := DiffText(pair[0], pair[1])
+		if got := joinSegments(segments, types.DiffOpDelete); got != pair[0] {
+			t.Errorf("expected the first response %q, got %q", pair[0], got)
+		}
+		if got := joinSegments(segments, types.DiffOpInsert); got != pair[1] {
+			t.Errorf("expected the second response %q, got %q", pair[1], got)
+		}
+	}
+}
+
+func TestMyersDiffEditLimit(t *testing.T) {
+	a := strings.Fields("a b c d e")
+	b := strings.Fields("v w x y z")
+	if _, ok := myersDiff(a, b, 4); ok {
+		t.Errorf("expected the diff to give up after 4 edits")
+	}
+	edits, ok := myersDiff(a, b, 10)
+	if !ok || len(edits) != 10 {
+		t.Errorf("expected 10 edits, got %d, %v", len(edits), ok)
+	}
+}
+
+func TestDiffVariations(t *testing.T) {
+	client, _ := newStoreTestClient(t)
+	ctx := context.Background()
+
+	run, err := client.CreateExecutionRun(ctx, "user-1", "Capital cities", "", false)
+	if err != nil {
+		t.Fatalf("failed to create run: %v", err)
+	}
+	responses := map[string]string{"cold": "Paris is the capital.", "hot": "Paris, the city of light, is the capital."}
+	for i, name := range []string{"cold", "hot"} {
+		config := &types.APIConfiguration{ID: "config-" + name, ExecutionRunID: run.ID, VariationName: name, ModelName: "gemini-2.0-flash"}
+		request := &types.APIRequest{ID: "request-" + name, ExecutionRunID: run.ID, ConfigurationID: config.ID, Prompt: "Capital of France?"}
+		response := &types.APIResponse{ID: "response-" + name, RequestID: request.ID, ResponseStatus: types.ResponseStatusSuccess,
+			ResponseText: responses[name], ResponseTimeMs: int32(100 * (i + 1)),
+			UsageMetadata: map[string]interface{}{"prompt_tokens": 10, "completion_tokens": 5 * (i + 1)}}
+		if err := client.CreateAPIConfiguration(ctx, "user-1", config); err != nil {
+			t.Fatalf("failed to create configuration: %v", err)
+		}
+		if err := client.LogAPIRequest(ctx, "user-1", request); err != nil {
+			t.Fatalf("failed to log request: %v", err)
+		}
+		if err := client.LogAPIResponse(ctx, "user-1", response); err != nil {
+			t.Fatalf("failed to log response: %v", err)
+		}
+	}
+	if err := client.StoreComparisonResult(ctx, "user-1", &types.ComparisonResult{ID: "comparison-1", ExecutionRunID: run.ID,
+		MetricName: "multi_metric", CreatedAt: time.Now(), ConfigurationScores: map[string]interface{}{
+			"cold": map[string]interface{}{"overall_score": 0.8, "temperature": 0.1, "judge_cached": true},
+			"hot":  map[string]interface{}{"overall_score": 0.6, "temperature": 1.2, "judge_cached": false},
+		}}); err != nil {
+		t.Fatalf("failed to store comparison: %v", err)
+	}
+
+	diff, err := client.DiffVariations(ctx, "user-1", run.ID, "config-cold", "config-hot")
+	if err != nil {
+		t.Fatalf("failed to diff variations: %v", err)
+	}
+	if diff.A.VariationName != "cold" || diff.B.VariationName != "hot" || diff.WordsAdded != 4 || diff.WordsRemoved != 0 {
+		t.Errorf("unexpected diff: %+v", diff)
+	}
+	if got := joinSegments(diff.Segments, types.DiffOpInsert); got != responses["hot"] {
+		t.Errorf("expected the segments to rebuild the second response, got %q", got)
+	}
+
+	metrics := make(map[string]types.MetricDelta)
+	var names []string
+	for _, metric := range diff.Metrics {
+		metrics[metric.Metric] = metric
+		names = append(names, metric.Metric)
+	}
+	if got := strings.Join(names, ","); got != "response_time_ms,prompt_tokens,completion_tokens,cost_usd,word_count,overall_score" {
+		t.Errorf("unexpected metrics %s", got)
+	}
+	if metrics["response_time_ms"].Delta != 100 || metrics["completion_tokens"].Delta != 5 || metrics["word_count"].Delta != 4 {
+		t.Errorf("unexpected response metrics: %+v", diff.Metrics)
+	}
+	if overall := metrics["overall_score"]; overall.A != 0.8 || overall.B != 0.6 || overall.Delta >= 0 {
+		t.Errorf("expected the comparison's overall scores, got %+v", overall)
+	}
+
+	if _, err := client.DiffVariations(ctx, "user-1", run.ID, "config-cold", "config-missing"); !errors.Is(err, ErrConfigurationNotFound) {
+		t.Errorf("expected ErrConfigurationNotFound, got %v", err)
+	}
+	if _, err := client.DiffVariations(ctx, "user-2", run.ID, "config-cold", "config-hot"); !errors.Is(err, ErrExecutionRunNotFound) {
+		t.Errorf("expected another user's run to be hidden, got %v", err)
+	}
+}
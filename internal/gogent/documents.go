@@ -0,0 +1,298 @@
+package gogent
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"mime"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"gogent/internal/types"
+)
+
+// ErrDocumentNotFound is returned when a user has no document with an ID
+var ErrDocumentNotFound = errors.New("document not found")
+
+const (
+	// MaxDocumentBytes caps the size of an uploaded document
+	MaxDocumentBytes = 10 << 20
+	// maxDocumentChunks caps how many chunks, and so embedding calls, one document may produce
+	maxDocumentChunks = 2000
+
+	// defaultChunkSize is how many characters a chunk holds when the upload sets no size
+	defaultChunkSize = 1000
+	// defaultChunkOverlap is how many characters consecutive chunks share when the upload sets none
+	defaultChunkOverlap = 200
+	// minChunkSize and maxChunkSize bound the chunk size an upload may ask for
+	minChunkSize = 100
+	maxChunkSize = 8000
+)
+
+// documentColumns is the column list read by scanDocument
+const documentColumns = `
+	id, collection, name, content_type, size_bytes, chunk_count, chunk_size, chunk_overlap,
+	embedding_model, created_at`
+
+// ValidateChunking checks a chunk size and overlap and fills in the defaults
+func ValidateChunking(chunking *types.ChunkingConfig) error {
+	if chunking.Size == 0 {
+		chunking.Size = defaultChunkSize
+		if chunking.Overlap == 0 {
+			chunking.Overlap = defaultChunkOverlap
+		}
+	}
+	if chunking.Size < minChunkSize || chunking.Size > maxChunkSize {
+		return fmt.Errorf("chunk size must be between %d and %d characters, got %d", minChunkSize, maxChunkSize, chunking.Size)
+	}
+	if chunking.Overlap < 0 || chunking.Overlap >= chunking.Size {
+		return fmt.Errorf("chunk overlap must be at least 0 and less than the chunk size %d, got %d", chunking.Size, chunking.Overlap)
+	}
+	return nil
+}
+
+// DocumentContentType returns the content type of an uploaded file, preferring its extension over
+// the type the client declared: text/plain, text/markdown or application/pdf
+func DocumentContentType(name, declared string) (string, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".txt", ".text":
+		return "text/plain", nil
+	case ".md", ".markdown":
+		return "text/markdown", nil
+	case ".pdf":
+		return "application/pdf", nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(declared)
+	switch mediaType {
+	case "text/plain", "text/markdown", "application/pdf":
+		return mediaType, nil
+	case "text/x-markdown":
+		return "text/markdown", nil
+	}
+	return "", fmt.Errorf("unsupported document type %q: upload a .txt, .md or .pdf file", name)
+}
+
+// ExtractDocumentText returns the text of a document of the given content type
+func ExtractDocumentText(contentType string, data []byte) (string, error) {
+	switch contentType {
+	case "application/pdf":
+		return extractPDFText(data)
+	case "text/plain", "text/markdown":
+		// Markdown is kept as written: its headings and lists help the model read the chunks
+		data = []byte(strings.TrimPrefix(string(data), "\ufeff"))
+		if !utf8.Valid(data) {
+			return "", fmt.Errorf("document is not valid UTF-8 text")
+		}
+		return normalizeExtractedText(string(data)), nil
+	}
+	return "", fmt.Errorf("unsupported content type %q", contentType)
+}
+
+// normalizeExtractedText trims trailing spaces from lines, collapses runs of spaces and keeps at
+// most one blank line between paragraphs
+func normalizeExtractedText(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+
+	var b strings.Builder
+	blank := 0
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.Join(strings.FieldsFunc(line, func(r rune) bool { return r == ' ' || r == '\t' }), " ")
+		if line == "" {
+			blank++
+			continue
+		}
+		if b.Len() > 0 {
+			if blank > 0 {
+				b.WriteString("\n\n")
+			} else {
+				b.WriteString("\n")
+			}
+		}
+		blank = 0
+		b.WriteString(line)
+	}
+	return b.String()
+}
+
+// ChunkText splits text into chunks of at most size characters, consecutive chunks sharing about
+// overlap characters. Chunks end at a paragraph, sentence or word boundary in their second half
+// when there is one, and the overlap starts at a word.
+func ChunkText(text string, size, overlap int) []string {
+	runes := []rune(strings.TrimSpace(text))
+	var chunks []string
+	for start := 0; start < len(runes); {
+		end := min(start+size, len(runes))
+		if end < len(runes) {
+			end = chunkBreak(runes, start, end)
+		}
+		if chunk := strings.TrimSpace(string(runes[start:end])); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+		if end == len(runes) {
+			break
+		}
+
+		next := end - overlap
+		for next > start && next < end && !unicode.IsSpace(runes[next-1]) {
+			next++
+		}
+		if next <= start || next >= end {
+			next = end
+		}
+		start = next
+	}
+	return chunks
+}
+
+// chunkBreak returns where a chunk spanning runes[start:end] should end: after the last paragraph
+// break, sentence end or space in its second half, in that order of preference, or at end
+func chunkBreak(runes []rune, start, end int) int {
+	earliest := start + (end-start)/2
+	boundaries := []func(i int) bool{
+		func(i int) bool { return runes[i-1] == '\n' && i >= 2 && runes[i-2] == '\n' },
+		func(i int) bool {
+			return runes[i-1] == '\n' || (unicode.IsSpace(runes[i-1]) && i >= 2 && strings.ContainsRune(".!?", runes[i-2]))
+		},
+		func(i int) bool { return unicode.IsSpace(runes[i-1]) },
+	}
+	for _, boundary := range boundaries {
+		for i := end; i > earliest; i-- {
+			if boundary(i) {
+				return i
+			}
+		}
+	}
+	return end
+}
+
+// IngestDocument extracts the text of an uploaded file, splits it into chunks and indexes them in
+// the user's collection. An empty model uses text-embedding-004.
+func (c *Client) IngestDocument(ctx context.Context, userID, collection, name, contentType string, data []byte, model string, chunking types.ChunkingConfig) (*types.Document, error) {
+	if len(data) > MaxDocumentBytes {
+		return nil, fmt.Errorf("document is %d bytes, larger than the %d byte limit", len(data), MaxDocumentBytes)
+	}
+	if err := ValidateChunking(&chunking); err != nil {
+		return nil, err
+	}
+	contentType, err := DocumentContentType(name, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := ExtractDocumentText(contentType, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract text from %s: %w", name, err)
+	}
+	if text == "" {
+		return nil, fmt.Errorf("no text found in %s", name)
+	}
+	chunks := ChunkText(text, chunking.Size, chunking.Overlap)
+	if len(chunks) > maxDocumentChunks {
+		return nil, fmt.Errorf("%s splits into %d chunks, more than the limit of %d; use a larger chunk size", name, len(chunks), maxDocumentChunks)
+	}
+
+	return c.indexDocument(ctx, userID, &types.Document{
+		Collection:   collection,
+		Name:         filepath.Base(name),
+		ContentType:  contentType,
+		SizeBytes:    int64(len(data)),
+		ChunkSize:    chunking.Size,
+		ChunkOverlap: chunking.Overlap,
+	}, model, chunks)
+}
+
+// ListDocuments returns the user's documents, newest first, optionally only those of one collection
+func (c *Client) ListDocuments(ctx context.Context, userID, collection string) ([]*types.Document, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	query := "SELECT " + documentColumns + " FROM documents WHERE user_id = ?"
+	args := []interface{}{userID}
+	if collection != "" {
+		query += " AND collection = ?"
+		args = append(args, collection)
+	}
+	rows, err := c.db.QueryContext(ctx, query+" ORDER BY created_at DESC, name ASC", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+	defer rows.Close()
+
+	var documents []*types.Document
+	for rows.Next() {
+		document, err := scanDocument(rows)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, document)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate documents: %w", err)
+	}
+	return documents, nil
+}
+
+// GetDocument returns one of the user's documents
+func (c *Client) GetDocument(ctx context.Context, userID, id string) (*types.Document, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	row := c.db.QueryRowContext(ctx, "SELECT "+documentColumns+" FROM documents WHERE id = ? AND user_id = ?", id, userID)
+	document, err := scanDocument(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrDocumentNotFound
+	}
+	return document, err
+}
+
+// DeleteDocument removes one of the user's documents and its chunks. Chunks already retrieved by
+// runs stay recorded with those runs.
+func (c *Client) DeleteDocument(ctx context.Context, userID, id string) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM document_chunks WHERE document_id = ? AND user_id = ?", id, userID); err != nil {
+		return fmt.Errorf("failed to delete document chunks: %w", err)
+	}
+	result, err := tx.ExecContext(ctx, "DELETE FROM documents WHERE id = ? AND user_id = ?", id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete document: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete document: %w", err)
+	}
+	if affected == 0 {
+		return ErrDocumentNotFound
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit document deletion: %w", err)
+	}
+	return nil
+}
+
+// scanDocument reads a row selected with documentColumns
+func scanDocument(row rowScanner) (*types.Document, error) {
+	var document types.Document
+	err := row.Scan(&document.ID, &document.Collection, &document.Name, &document.ContentType,
+		&document.SizeBytes, &document.ChunkCount, &document.ChunkSize, &document.ChunkOverlap,
+		&document.EmbeddingModel, &document.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan document: %w", err)
+	}
+	return &document, nil
+}
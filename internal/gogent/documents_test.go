@@ -0,0 +1,225 @@
+package gogent
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"gogent/internal/types"
+)
+
+// testPDF builds a minimal PDF whose single page draws content, Flate-compressed when compress is set
+func testPDF(t *testing.T, content string, compress bool) []byte {
+	stream := []byte(content)
+	filter := ""
+	if compress {
+		var buf bytes.Buffer
+		writer := zlib.NewWriter(&buf)
+		writer.Write(stream)
+		writer.Close()
+		stream = buf.Bytes()
+		filter = " /Filter /FlateDecode"
+	}
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n")
+	pdf.WriteString("1 0 obj << /Type /Catalog /Pages 2 0 R >> endobj\n")
+	pdf.WriteString("2 0 obj << /Type /Pages /Kids [3 0 R] /Count 1 >> endobj\n")
+	pdf.WriteString("3 0 obj << /Type /Page /Parent 2 0 R /Contents 4 0 R >> endobj\n")
+	fmt.Fprintf(&pdf, "4 0 obj << /Length %d%s >>\nstream\n", len(stream), filter)
+	pdf.Write(stream)
+	pdf.WriteString("\nendstream\nendobj\n")
+	pdf.WriteString("5 0 obj << /Subtype /Image /Length 4 >>\nstream\n(BT (hidden) Tj ET)\nendstream\nendobj\n")
+	pdf.WriteString("trailer << /Root 1 0 R >>\n%%EOF\n")
+	return pdf.Bytes()
+}
+
+func TestExtractPDFText(t *testing.T) {
+	content := "BT /F1 12 Tf 72 712 Td (Refund policy) Tj 0 -14 Td [(Refunds are ) -300 (issued within 30 days\\056)] TJ ET"
+	want := "Refund policy\nRefunds are issued within 30 days."
+	for _, compress := range []bool{false, true} {
+		text, err := extractPDFText(testPDF(t, content, compress))
+		if err != nil {
+			t.Fatalf("failed to extract text: %v", err)
+		}
+		if text != want {
+			t.Errorf("compress=%v: expected %q, got %q", compress, want, text)
+		}
+	}
+
+	// UTF-16 strings with a byte order mark, as office tools write them
+	text, err := extractPDFText(testPDF(t, "BT <FEFF0043006100660065> Tj ET", false))
+	if err != nil || text != "Cafe" {
+		t.Errorf("expected the UTF-16 string decoded, got %q, %v", text, err)
+	}
+
+	if _, err := extractPDFText([]byte("plain text")); err == nil {
+		t.Errorf("expected an error for a file that is not a PDF")
+	}
+}
+
+func TestDocumentContentType(t *testing.T) {
+	tests := []struct {
+		name, declared, want string
+	}{
+		{"notes.txt", "", "text/plain"},
+		{"README.md", "application/octet-stream", "text/markdown"},
+		{"handbook.PDF", "", "application/pdf"},
+		{"upload", "text/markdown; charset=utf-8", "text/markdown"},
+	}
+	for _, test := range tests {
+		got, err := DocumentContentType(test.name, test.declared)
+		if err != nil || got != test.want {
+			t.Errorf("%s (%s): expected %s, got %q, %v", test.name, test.declared, test.want, got, err)
+		}
+	}
+	if _, err := DocumentContentType("slides.pptx", "application/vnd.ms-powerpoint"); err == nil {
+		t.Errorf("expected an unsupported type to be rejected")
+	}
+}
+
+func TestExtractDocumentText(t *testing.T) {
+	text, err := ExtractDocumentText("text/markdown", []byte("\ufeff# Title  \r\n\r\n\r\n\r\nSome   text\there.\n"))
+	if err != nil || text != "# Title\n\nSome text here." {
+		t.Errorf("expected normalized text, got %q, %v", text, err)
+	}
+	if _, err := ExtractDocumentText("text/plain", []byte{0xff, 0xfe, 0x00}); err == nil {
+		t.Errorf("expected invalid UTF-8 to be rejected")
+	}
+}
+
+func TestValidateChunking(t *testing.T) {
+	chunking := types.ChunkingConfig{}
+	if err := ValidateChunking(&chunking); err != nil || chunking.Size != defaultChunkSize || chunking.Overlap != defaultChunkOverlap {
+		t.Errorf("expected the defaults, got %+v, %v", chunking, err)
+	}
+	chunking = types.ChunkingConfig{Size: 500}
+	if err := ValidateChunking(&chunking); err != nil || chunking.Overlap != 0 {
+		t.Errorf("expected an explicit size to keep no overlap, got %+v, %v", chunking, err)
+	}
+
+	for _, invalid := range []types.ChunkingConfig{{Size: 50}, {Size: 9000}, {Size: 500, Overlap: 500}, {Size: 500, Overlap: -1}} {
+		if err := ValidateChunking(&invalid); err == nil {
+			t.Errorf("expected %+v to be rejected", invalid)
+		}
+	}
+}
+
+func TestChunkText(t *testing.T) {
+	if chunks := ChunkText("  short text  ", 100, 20); len(chunks) != 1 || chunks[0] != "short text" {
+		t.Errorf("expected a single chunk, got %q", chunks)
+	}
+
+	sentences := make([]string, 20)
+	for i := range sentences {
+		sentences[i] = fmt.Sprintf("Sentence number %02d is here.", i)
+	}
+	text := strings.Join(sentences, " ")
+	chunks := ChunkText(text, 100, 30)
+	if len(chunks) < 2 {
+		t.Fatalf("expected several chunks, got %d", len(chunks))
+	}
+	for i, chunk := range chunks {
+		if len([]rune(chunk)) > 100 {
+			t.Errorf("chunk %d is longer than 100 characters: %q", i, chunk)
+		}
+		if i < len(chunks)-1 && !strings.HasSuffix(chunk, ".") {
+			t.Errorf("expected chunk %d to end at a sentence, got %q", i, chunk)
+		}
+		if i > 0 && strings.HasPrefix(chunk, "entence") {
+			t.Errorf("expected chunk %d to start at a word, got %q", i, chunk)
+		}
+	}
+	if !strings.Contains(chunks[len(chunks)-1], "Sentence number 19 is here.") {
+		t.Errorf("expected the last chunk to end the text, got %q", chunks[len(chunks)-1])
+	}
+
+	// Consecutive chunks share the overlap
+	first, second := chunks[0], chunks[1]
+	lastWords := first[strings.LastIndex(first[:len(first)-1], " ")+1:]
+	if !strings.Contains(second, lastWords) {
+		t.Errorf("expected %q to repeat the end of %q", second, first)
+	}
+
+	// Text without spaces is cut at the chunk size
+	if chunks := ChunkText(strings.Repeat("x", 250), 100, 10); len(chunks) != 3 || len(chunks[0]) != 100 {
+		t.Errorf("expected hard cuts at 100 characters, got %d chunks", len(chunks))
+	}
+}
+
+func TestIngestDocument(t *testing.T) {
+	client := newRetrievalTestClient(t)
+	ctx := context.Background()
+
+	text := strings.Repeat("Paris is the capital of France. ", 20) + "\n\n" + strings.Repeat("Bananas are rich in potassium. ", 20)
+	document, err := client.IngestDocument(ctx, "user-1", "notes", "uploads/facts.md", "", []byte(text), "", types.ChunkingConfig{Size: 300, Overlap: 50})
+	if err != nil {
+		t.Fatalf("failed to ingest document: %v", err)
+	}
+	if document.Name != "facts.md" || document.ContentType != "text/markdown" || document.ChunkCount < 3 ||
+		document.ChunkSize != 300 || document.ChunkOverlap != 50 || document.EmbeddingModel != defaultEmbeddingModel {
+		t.Errorf("unexpected document: %+v", document)
+	}
+
+	// The chunks are found by retrieval
+	config := &types.APIConfiguration{Retrieval: &types.RetrievalConfig{Collection: "notes", TopK: 1}}
+	chunks, err := client.retrieveChunks(ctx, "user-1", config, "Which fruit has potassium?")
+	if err != nil || len(chunks) != 1 || !strings.Contains(chunks[0].Content, "Bananas") {
+		t.Errorf("expected a banana chunk to be retrieved, got %+v, %v", chunks, err)
+	}
+
+	if _, err := client.IngestDocument(ctx, "user-1", "notes", "empty.txt", "", []byte(" \n "), "", types.ChunkingConfig{}); err == nil {
+		t.Errorf("expected a document without text to be rejected")
+	}
+	if _, err := client.IngestDocument(ctx, "user-1", "notes", "facts.txt", "", []byte(text), "", types.ChunkingConfig{Size: 10}); err == nil {
+		t.Errorf("expected an invalid chunk size to be rejected")
+	}
+}
+
+func TestDocumentLifecycle(t *testing.T) {
+	client := newRetrievalTestClient(t)
+	ctx := context.Background()
+
+	first, err := client.IngestDocument(ctx, "user-1", "notes", "a.txt", "", []byte("First document."), "", types.ChunkingConfig{})
+	if err != nil {
+		t.Fatalf("failed to ingest document: %v", err)
+	}
+	if _, err := client.IngestDocument(ctx, "user-1", "other", "b.txt", "", []byte("Second document."), "", types.ChunkingConfig{}); err != nil {
+		t.Fatalf("failed to ingest document: %v", err)
+	}
+
+	all, err := client.ListDocuments(ctx, "user-1", "")
+	if err != nil || len(all) != 2 {
+		t.Fatalf("expected 2 documents, got %d, %v", len(all), err)
+	}
+	notes, err := client.ListDocuments(ctx, "user-1", "notes")
+	if err != nil || len(notes) != 1 || notes[0].ID != first.ID || notes[0].SizeBytes != int64(len("First document.")) {
+		t.Errorf("expected only the notes document, got %+v, %v", notes, err)
+	}
+	if others, err := client.ListDocuments(ctx, "user-2", ""); err != nil || len(others) != 0 {
+		t.Errorf("expected no documents for another user, got %d, %v", len(others), err)
+	}
+
+	if _, err := client.GetDocument(ctx, "user-2", first.ID); !errors.Is(err, ErrDocumentNotFound) {
+		t.Errorf("expected another user's document to be hidden, got %v", err)
+	}
+	if err := client.DeleteDocument(ctx, "user-2", first.ID); !errors.Is(err, ErrDocumentNotFound) {
+		t.Errorf("expected another user's delete to fail, got %v", err)
+	}
+	if err := client.DeleteDocument(ctx, "user-1", first.ID); err != nil {
+		t.Fatalf("failed to delete document: %v", err)
+	}
+	if _, err := client.GetDocument(ctx, "user-1", first.ID); !errors.Is(err, ErrDocumentNotFound) {
+		t.Errorf("expected the deleted document to be gone, got %v", err)
+	}
+
+	var remaining int
+	client.db.QueryRow("SELECT COUNT(*) FROM document_chunks WHERE document_id = ?", first.ID).Scan(&remaining)
+	if remaining != 0 {
+		t.Errorf("expected the document's chunks to be deleted, %d remain", remaining)
+	}
+}
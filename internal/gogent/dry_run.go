@@ -0,0 +1,92 @@
+package gogent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gogent/internal/types"
+)
+
+// ExecutionRunStatusDryRun is the status of the unsaved run a dry run returns
+const ExecutionRunStatusDryRun = "dry_run"
+
+// dryRun builds the request each configuration would send its provider, with the run-wide settings
+// applied and input guards run over the prompt, without calling a model or storing anything.
+// Repetitions send identical requests, so each configuration is built once.
+func (c *Client) dryRun(ctx context.Context, request *types.MultiExecutionRequest) (*types.ExecutionResult, error) {
+	now := time.Now()
+	name := request.ExecutionRunName
+	if name == "" {
+		pattern := request.NameTemplate
+		if pattern == "" {
+			pattern = defaultRunNameTemplate
+		}
+		name = ExpandRunName(pattern, request, now)
+	}
+
+	result := &types.ExecutionResult{
+		ExecutionRun: types.ExecutionRun{
+			Name:                  name,
+			Description:           request.Description,
+			EnableFunctionCalling: request.EnableFunctionCalling,
+			Status:                ExecutionRunStatusDryRun,
+			CreatedAt:             now,
+			UpdatedAt:             now,
+			Deterministic:         request.Deterministic,
+			Tags:                  normalizeTags(request.Tags),
+		},
+		Results:  []types.VariationResult{},
+		Payloads: make([]types.ProviderPayload, 0, len(request.Configurations)),
+	}
+	for _, config := range request.Configurations {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := prepareConfiguration(&config, request); err != nil {
+			return nil, fmt.Errorf("configuration %s: %w", config.VariationName, err)
+		}
+		payload, err := buildProviderPayload(&config, request.BasePrompt, request.Context)
+		if err != nil {
+			return nil, fmt.Errorf("configuration %s: %w", config.VariationName, err)
+		}
+		result.Payloads = append(result.Payloads, *payload)
+	}
+	return result, nil
+}
+
+// buildProviderPayload builds the first request of a prepared configuration, as
+// executeSingleVariation would send it
+func buildProviderPayload(config *types.APIConfiguration, prompt, promptContext string) (*types.ProviderPayload, error) {
+	payload := &types.ProviderPayload{
+		Configuration:    *config,
+		Provider:         config.Provider,
+		ModelName:        config.ModelName,
+		RetrievalSkipped: config.Retrieval != nil,
+	}
+
+	verdicts, prompt, promptContext, blocked := applyInputGuards(config.Guardrails, prompt, promptContext)
+	payload.GuardVerdicts = verdicts
+	if blocked {
+		payload.Blocked = true
+		return payload, nil
+	}
+
+	apiRequest := &types.APIRequest{Prompt: prompt, Context: promptContext}
+	var body interface{}
+	if config.Provider == types.ProviderOllama {
+		chatRequest := ollamaChatRequest(config, apiRequest)
+		payload.FinalPrompt = chatRequest.Messages[len(chatRequest.Messages)-1].Content
+		body = chatRequest
+	} else {
+		body, payload.FinalPrompt = geminiRequest(config, apiRequest)
+	}
+
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s request: %w", config.Provider, err)
+	}
+	payload.Body = encoded
+	return payload, nil
+}
@@ -0,0 +1,103 @@
+package gogent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gogent/internal/types"
+)
+
+func TestDryRun(t *testing.T) {
+	client := NewInMemoryClient(&types.GeminiClientConfig{APIKey: "gemini-api-key", OllamaURL: "http://localhost:11434"})
+	client.SetHTTPTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		t.Errorf("expected no provider calls, got %s %s", req.Method, req.URL)
+		return nil, fmt.Errorf("unexpected request")
+	}))
+
+	temperature := float32(0.7)
+	request := &types.MultiExecutionRequest{
+		ExecutionRunName:      "Payload check",
+		BasePrompt:            "What's the weather in Paris?",
+		Context:               "Answer in one sentence.",
+		EnableFunctionCalling: true,
+		FunctionTools: []types.Tool{{
+			Name:        "get_weather",
+			Description: "Current weather",
+			Parameters: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"location": map[string]interface{}{"type": "string", "examples": []string{"Paris"}}},
+			},
+		}},
+		Configurations: []types.APIConfiguration{
+			{VariationName: "gemini", ModelName: "gemini-1.5-flash", Temperature: &temperature, SystemPrompt: "Be brief."},
+			{VariationName: "local", ModelName: "llama3.2", Provider: types.ProviderOllama, DisableTools: true},
+			{VariationName: "guarded", ModelName: "gemini-1.5-flash", Guardrails: []types.GuardConfig{{Guard: "banned_topics", Stage: types.GuardStageInput, Topics: []string{"weather"}}}},
+		},
+		Repetitions: 3,
+		DryRun:      true,
+	}
+
+	result, err := client.ExecuteMultiVariation(context.Background(), "user-1", request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ExecutionRun.Status != ExecutionRunStatusDryRun || result.ExecutionRun.ID != "" || len(result.Results) != 0 {
+		t.Errorf("expected an unsaved dry run without results, got %+v", result.ExecutionRun)
+	}
+	if len(result.Payloads) != 3 {
+		t.Fatalf("expected one payload per configuration, got %d", len(result.Payloads))
+	}
+	if runs, _ := client.ListExecutionRuns(context.Background(), "user-1", 10, 0); len(runs) != 0 {
+		t.Errorf("expected nothing stored, got %d runs", len(runs))
+	}
+
+	gemini := result.Payloads[0]
+	if gemini.Provider != types.ProviderGemini || gemini.FinalPrompt != DefaultFunctionInstruction+"\n\nWhat's the weather in Paris?\n\nContext: Answer in one sentence." {
+		t.Errorf("expected the Gemini prompt behind the function instruction, got %s %q", gemini.Provider, gemini.FinalPrompt)
+	}
+	var body struct {
+		Tools []struct {
+			FunctionDeclarations []struct {
+				Name       string                 `json:"name"`
+				Parameters map[string]interface{} `json:"parameters"`
+			} `json:"functionDeclarations"`
+		} `json:"tools"`
+		SystemInstruction json.RawMessage        `json:"systemInstruction"`
+		GenerationConfig  map[string]interface{} `json:"generationConfig"`
+	}
+	if err := json.Unmarshal(gemini.Body, &body); err != nil {
+		t.Fatalf("expected a JSON body: %v", err)
+	}
+	if len(body.Tools) != 1 || body.Tools[0].FunctionDeclarations[0].Name != "get_weather" {
+		t.Fatalf("expected the run's tool in the body, got %s", gemini.Body)
+	}
+	location := body.Tools[0].FunctionDeclarations[0].Parameters["properties"].(map[string]interface{})["location"].(map[string]interface{})
+	if _, ok := location["examples"]; ok {
+		t.Errorf("expected the tool's parameters sanitized, got %v", location)
+	}
+	if !strings.Contains(string(body.SystemInstruction), "Be brief.") || body.GenerationConfig["temperature"] != 0.7 {
+		t.Errorf("expected the configuration's system instruction and temperature, got %s", gemini.Body)
+	}
+
+	local := result.Payloads[1]
+	var chat struct {
+		Model    string            `json:"model"`
+		Messages []json.RawMessage `json:"messages"`
+		Tools    []json.RawMessage `json:"tools"`
+	}
+	if err := json.Unmarshal(local.Body, &chat); err != nil {
+		t.Fatalf("expected a JSON body: %v", err)
+	}
+	if local.Provider != types.ProviderOllama || chat.Model != "llama3.2" || len(chat.Messages) != 1 || len(chat.Tools) != 0 {
+		t.Errorf("expected an Ollama chat request without tools, got %s", local.Body)
+	}
+
+	guarded := result.Payloads[2]
+	if !guarded.Blocked || guarded.Body != nil || len(guarded.GuardVerdicts) != 1 {
+		t.Errorf("expected the blocked prompt to have no body, got %+v", guarded)
+	}
+}
@@ -0,0 +1,412 @@
+package gogent
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"gogent/internal/gemini"
+	"gogent/internal/types"
+)
+
+const (
+	// defaultEmbeddingModel embeds text when no model is named
+	defaultEmbeddingModel = "text-embedding-004"
+	// mockEmbeddingDimensions is the size of the hashed bag-of-words vectors used without an API key
+	mockEmbeddingDimensions = 256
+	// semanticSimilarityMetric opts a comparison into embedding-based similarity scores
+	semanticSimilarityMetric = "semantic_similarity"
+	// semanticClusterThreshold is the cosine similarity at which two responses join the same cluster
+	semanticClusterThreshold = 0.85
+)
+
+// embedFunc computes an embedding; GetEmbedding passes callEmbeddingAPI
+type embedFunc func(ctx context.Context, model, text string) ([]float32, error)
+
+// EmbeddingProvider computes text embeddings; implement it to plug in a new embedding service
+type EmbeddingProvider interface {
+	// Name identifies the provider in logs
+	Name() string
+
+	// Embed returns the embedding of text under the named model
+	Embed(ctx context.Context, model, text string) ([]float32, error)
+}
+
+// NewEmbeddingProvider returns the Gemini embedding provider, or the mock provider without an API key
+func NewEmbeddingProvider(apiKey string) EmbeddingProvider {
+	if apiKey == "" {
+		return MockEmbeddingProvider{}
+	}
+	return &GeminiEmbeddingProvider{APIKey: apiKey}
+}
+
+// SetEmbeddingProvider replaces the provider used for embeddings; nil restores the default
+func (c *Client) SetEmbeddingProvider(provider EmbeddingProvider) {
+	c.overridesMutex.Lock()
+	defer c.overridesMutex.Unlock()
+	c.embeddingProvider = provider
+}
+
+// embedder returns the configured embedding provider, falling back to one built from the API key
+func (c *Client) embedder() EmbeddingProvider {
+	c.overridesMutex.RLock()
+	provider := c.embeddingProvider
+	c.overridesMutex.RUnlock()
+	if provider != nil {
+		return provider
+	}
+	if c.config == nil {
+		return MockEmbeddingProvider{}
+	}
+	return NewEmbeddingProvider(c.config.APIKey)
+}
+
+// EmbedText embeds text with the client's embedding provider, reusing the user's cached embedding
+// for the same model and content. An empty model uses text-embedding-004.
+func (c *Client) EmbedText(ctx context.Context, userID, model, text string) ([]float32, error) {
+	embedding, _, err := c.cachedEmbedding(ctx, userID, model, text, c.callEmbeddingAPI)
+	return embedding, err
+}
+
+// EmbeddingContentHash identifies the text an embedding was computed from
+func EmbeddingContentHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// GetEmbedding returns the embedding of text, reusing the user's cached embedding for the same
+// model and content. cached reports whether the embedding API was skipped.
+func (c *Client) GetEmbedding(ctx context.Context, userID, model, text string) ([]float32, bool, error) {
+	return c.cachedEmbedding(ctx, userID, model, text, c.callEmbeddingAPI)
+}
+
+// cachedEmbedding looks up an embedding by (model, content hash), computing and storing it on a miss
+func (c *Client) cachedEmbedding(ctx context.Context, userID, model, text string, embed embedFunc) ([]float32, bool, error) {
+	if model == "" {
+		model = defaultEmbeddingModel
+	}
+	hash := EmbeddingContentHash(text)
+
+	// Without a database there is nothing to cache in
+	if c.db == nil {
+		embedding, err := embed(ctx, model, text)
+		return embedding, false, err
+	}
+
+	var embeddingJSON string
+	err := c.db.QueryRowContext(ctx, `
+		SELECT embedding FROM embedding_cache
+		WHERE user_id = ? AND model = ? AND content_hash = ?
+	`, userID, model, hash).Scan(&embeddingJSON)
+	if err == nil {
+		var embedding []float32
+		if err := types.FromJSON(embeddingJSON, &embedding); err != nil {
+			return nil, false, fmt.Errorf("failed to parse cached embedding: %w", err)
+		}
+		_, err = c.db.ExecContext(ctx, `
+			UPDATE embedding_cache SET hit_count = hit_count + 1, last_hit_at = ?
+			WHERE user_id = ? AND model = ? AND content_hash = ?
+		`, time.Now(), userID, model, hash)
+		if err != nil {
+			log.Printf("⚠️ Warning: failed to record embedding cache hit: %v", err)
+		}
+		return embedding, true, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, false, fmt.Errorf("failed to get cached embedding: %w", err)
+	}
+
+	embedding, err := embed(ctx, model, text)
+	if err != nil {
+		return nil, false, err
+	}
+	encoded, err := types.ToJSON(embedding)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to marshal embedding: %w", err)
+	}
+	_, err = c.db.ExecContext(ctx, `
+		REPLACE INTO embedding_cache (user_id, model, content_hash, dimensions, embedding, hit_count, created_at)
+		VALUES (?, ?, ?, ?, ?, 0, ?)
+	`, userID, model, hash, len(embedding), encoded, time.Now())
+	if err != nil {
+		log.Printf("⚠️ Warning: failed to cache embedding: %v", err)
+	}
+	return embedding, false, nil
+}
+
+// QueryEmbeddingCacheStats counts embedding cache hits and misses for one user, or for every user
+// when userID is empty. Each cached row was created by one miss.
+func QueryEmbeddingCacheStats(ctx context.Context, db *sql.DB, userID string) (*types.EmbeddingCacheStats, error) {
+	var stats types.EmbeddingCacheStats
+	err := db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(hit_count), 0) FROM embedding_cache
+		WHERE (? = '' OR user_id = ?)
+	`, userID, userID).Scan(&stats.Misses, &stats.Hits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get embedding cache stats: %w", err)
+	}
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(lookups)
+	}
+	return &stats, nil
+}
+
+// callEmbeddingAPI embeds text with the client's embedding provider
+func (c *Client) callEmbeddingAPI(ctx context.Context, model, text string) ([]float32, error) {
+	return c.embedder().Embed(ctx, model, text)
+}
+
+// GeminiEmbeddingProvider embeds text with the Gemini embedContent endpoint
+type GeminiEmbeddingProvider struct {
+	APIKey string
+}
+
+// Name identifies the provider in logs
+func (p *GeminiEmbeddingProvider) Name() string {
+	return "gemini"
+}
+
+// Embed calls the Gemini embedContent endpoint
+func (p *GeminiEmbeddingProvider) Embed(ctx context.Context, model, text string) ([]float32, error) {
+	return gemini.NewClient(p.APIKey).EmbedContent(ctx, model, text)
+}
+
+// MockEmbeddingProvider embeds text as hashed bag-of-words vectors, without calling an API
+type MockEmbeddingProvider struct{}
+
+// Name identifies the provider in logs
+func (MockEmbeddingProvider) Name() string {
+	return "mock"
+}
+
+// Embed returns mockEmbedding(text)
+func (MockEmbeddingProvider) Embed(ctx context.Context, model, text string) ([]float32, error) {
+	return mockEmbedding(text), nil
+}
+
+// mockEmbedding hashes lowercase words into a normalized vector so similar texts still score as similar
+func mockEmbedding(text string) []float32 {
+	vector := make([]float32, mockEmbeddingDimensions)
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		word = strings.Trim(word, ".,;:!?\"'()[]{}")
+		if word == "" {
+			continue
+		}
+		h := fnv.New32a()
+		h.Write([]byte(word))
+		vector[h.Sum32()%mockEmbeddingDimensions]++
+	}
+
+	var norm float64
+	for _, v := range vector {
+		norm += float64(v) * float64(v)
+	}
+	if norm > 0 {
+		norm = math.Sqrt(norm)
+		for i := range vector {
+			vector[i] = float32(float64(vector[i]) / norm)
+		}
+	}
+	return vector
+}
+
+// CosineSimilarity compares two embeddings, returning 0 when they differ in size or either is empty
+func CosineSimilarity(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// semanticScores holds the embedding-based scores of a run's successful responses, keyed by resultKey
+type semanticScores struct {
+	similarities map[string]float64
+	clusters     map[string]int
+	cached       int
+}
+
+// semanticSimilarities scores each successful variation by its mean cosine similarity to the other
+// successful variations and groups the responses into clusters, storing each response's embedding
+func (c *Client) semanticSimilarities(ctx context.Context, userID string, result *types.ExecutionResult, embed embedFunc) semanticScores {
+	scores := semanticScores{similarities: make(map[string]float64), clusters: make(map[string]int)}
+	embeddings := make(map[string][]float32)
+	var ids []string
+	var stored []types.ResponseEmbedding
+	for _, r := range result.Results {
+		if r.Response.ResponseStatus != types.ResponseStatusSuccess || r.Response.ResponseText == "" {
+			continue
+		}
+		embedding, hit, err := c.cachedEmbedding(ctx, userID, defaultEmbeddingModel, r.Response.ResponseText, embed)
+		if err != nil {
+			log.Printf("⚠️ Warning: failed to embed %s: %v", r.Configuration.VariationName, err)
+			continue
+		}
+		if hit {
+			scores.cached++
+		}
+		embeddings[resultKey(r)] = embedding
+		ids = append(ids, resultKey(r))
+		if r.Response.ID != "" {
+			stored = append(stored, types.ResponseEmbedding{
+				ResponseID:      r.Response.ID,
+				ExecutionRunID:  result.ExecutionRun.ID,
+				ConfigurationID: r.Configuration.ID,
+				VariationName:   r.Configuration.VariationName,
+				Repetition:      r.Repetition,
+				Model:           defaultEmbeddingModel,
+				Embedding:       embedding,
+				CreatedAt:       time.Now(),
+			})
+		}
+	}
+
+	if err := c.storeResponseEmbeddings(ctx, userID, stored); err != nil {
+		log.Printf("⚠️ Warning: %v", err)
+	}
+
+	if len(ids) < 2 {
+		return scores
+	}
+	for _, id := range ids {
+		var total float64
+		for _, other := range ids {
+			if other != id {
+				total += CosineSimilarity(embeddings[id], embeddings[other])
+			}
+		}
+		scores.similarities[id] = total / float64(len(ids)-1)
+	}
+
+	vectors := make([][]float32, len(ids))
+	for i, id := range ids {
+		vectors[i] = embeddings[id]
+	}
+	for i, cluster := range ClusterEmbeddings(vectors, semanticClusterThreshold) {
+		scores.clusters[ids[i]] = cluster
+	}
+	return scores
+}
+
+// ClusterEmbeddings groups embeddings by single linkage: two embeddings share a cluster when a chain
+// of pairs at or above the similarity threshold connects them. Clusters are numbered from 1 in the
+// order their first member appears.
+func ClusterEmbeddings(embeddings [][]float32, threshold float64) []int {
+	parent := make([]int, len(embeddings))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := range embeddings {
+		for j := i + 1; j < len(embeddings); j++ {
+			if CosineSimilarity(embeddings[i], embeddings[j]) >= threshold {
+				// Keep the earliest member as the root so numbering follows input order
+				a, b := find(i), find(j)
+				if a > b {
+					a, b = b, a
+				}
+				parent[b] = a
+			}
+		}
+	}
+
+	clusters := make([]int, len(embeddings))
+	numbers := make(map[int]int)
+	for i := range embeddings {
+		root := find(i)
+		if _, ok := numbers[root]; !ok {
+			numbers[root] = len(numbers) + 1
+		}
+		clusters[i] = numbers[root]
+	}
+	return clusters
+}
+
+// storeResponseEmbeddings stores the embeddings of a run's responses in one statement, replacing
+// earlier embeddings of the same response and model
+func (c *Client) storeResponseEmbeddings(ctx context.Context, userID string, embeddings []types.ResponseEmbedding) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	if len(embeddings) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, len(embeddings))
+	args := make([]interface{}, 0, len(embeddings)*10)
+	for i, e := range embeddings {
+		encoded, err := types.ToJSON(e.Embedding)
+		if err != nil {
+			return fmt.Errorf("failed to marshal response embedding: %w", err)
+		}
+		placeholders[i] = "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+		args = append(args, e.ResponseID, e.Model, userID, e.ExecutionRunID, e.ConfigurationID, e.VariationName,
+			e.Repetition, len(e.Embedding), encoded, e.CreatedAt)
+	}
+
+	_, err := c.db.ExecContext(ctx, `
+		REPLACE INTO response_embeddings (response_id, model, user_id, execution_run_id, configuration_id,
+			variation_name, repetition, dimensions, embedding, created_at)
+		VALUES `+strings.Join(placeholders, ", "), args...)
+	if err != nil {
+		return fmt.Errorf("failed to store response embeddings: %w", err)
+	}
+	return nil
+}
+
+// GetResponseEmbeddings returns the stored embeddings of the responses of a run owned by the user
+func (c *Client) GetResponseEmbeddings(ctx context.Context, userID, executionRunID string) ([]types.ResponseEmbedding, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT response_id, model, execution_run_id, configuration_id, variation_name, repetition, embedding, created_at
+		FROM response_embeddings
+		WHERE execution_run_id = ? AND user_id = ?
+		ORDER BY variation_name ASC, repetition ASC
+	`, executionRunID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get response embeddings: %w", err)
+	}
+	defer rows.Close()
+
+	var embeddings []types.ResponseEmbedding
+	for rows.Next() {
+		var e types.ResponseEmbedding
+		var embeddingJSON string
+		if err := rows.Scan(&e.ResponseID, &e.Model, &e.ExecutionRunID, &e.ConfigurationID, &e.VariationName,
+			&e.Repetition, &embeddingJSON, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan response embedding: %w", err)
+		}
+		if err := types.FromJSON(embeddingJSON, &e.Embedding); err != nil {
+			return nil, fmt.Errorf("failed to parse response embedding: %w", err)
+		}
+		embeddings = append(embeddings, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate response embeddings: %w", err)
+	}
+	return embeddings, nil
+}
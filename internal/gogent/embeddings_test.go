@@ -0,0 +1,181 @@
+package gogent
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+
+	"gogent/internal/testdb"
+	"gogent/internal/types"
+)
+
+// newEmbeddingTestClient returns a client backed by in-memory embedding_cache and response_embeddings tables
+func newEmbeddingTestClient(t *testing.T) *Client {
+	return &Client{db: testdb.Open(t)}
+}
+
+// countingEmbedder embeds with mockEmbedding and counts its calls
+type countingEmbedder struct {
+	calls int
+}
+
+func (e *countingEmbedder) embed(ctx context.Context, model, text string) ([]float32, error) {
+	e.calls++
+	return mockEmbedding(text), nil
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	a := mockEmbedding("The capital of France is Paris")
+	if got := CosineSimilarity(a, a); math.Abs(got-1) > 1e-6 {
+		t.Errorf("expected identical texts to score 1, got %v", got)
+	}
+
+	similar := CosineSimilarity(a, mockEmbedding("Paris is the capital of France."))
+	different := CosineSimilarity(a, mockEmbedding("Bananas are rich in potassium"))
+	if similar <= different {
+		t.Errorf("expected reworded text (%v) to score above unrelated text (%v)", similar, different)
+	}
+
+	if got := CosineSimilarity(a, []float32{1, 0}); got != 0 {
+		t.Errorf("expected mismatched sizes to score 0, got %v", got)
+	}
+}
+
+func TestCachedEmbedding(t *testing.T) {
+	client := newEmbeddingTestClient(t)
+	ctx := context.Background()
+	embedder := &countingEmbedder{}
+
+	first, cached, err := client.cachedEmbedding(ctx, "user-1", "", "unchanged document", embedder.embed)
+	if err != nil || cached {
+		t.Fatalf("expected a fresh embedding, got cached=%v, %v", cached, err)
+	}
+	second, cached, err := client.cachedEmbedding(ctx, "user-1", defaultEmbeddingModel, "unchanged document", embedder.embed)
+	if err != nil || !cached || embedder.calls != 1 {
+		t.Fatalf("expected the cached embedding without another call, got cached=%v, %d calls, %v", cached, embedder.calls, err)
+	}
+	if CosineSimilarity(first, second) < 0.9999 {
+		t.Errorf("expected the cached embedding to round-trip")
+	}
+
+	// A different model, different content or another user misses the cache
+	client.cachedEmbedding(ctx, "user-1", "gemini-embedding-001", "unchanged document", embedder.embed)
+	client.cachedEmbedding(ctx, "user-1", "", "edited document", embedder.embed)
+	client.cachedEmbedding(ctx, "user-2", "", "unchanged document", embedder.embed)
+	if embedder.calls != 4 {
+		t.Errorf("expected 4 embedding calls, got %d", embedder.calls)
+	}
+
+	stats, err := QueryEmbeddingCacheStats(ctx, client.db, "user-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.Hits != 1 || stats.Misses != 3 || stats.HitRate != 0.25 {
+		t.Errorf("expected 1 hit and 3 misses for user-1, got %+v", stats)
+	}
+	if stats, _ := QueryEmbeddingCacheStats(ctx, client.db, ""); stats.Misses != 4 {
+		t.Errorf("expected 4 misses across users, got %+v", stats)
+	}
+}
+
+func TestSemanticSimilarities(t *testing.T) {
+	client := newEmbeddingTestClient(t)
+	ctx := context.Background()
+	embedder := &countingEmbedder{}
+
+	result := newJudgeTestResult(
+		"The capital of France is Paris.",
+		"Paris is the capital of France.",
+		"I am not sure, maybe Lyon.",
+	)
+	result.ExecutionRun.ID = "run-1"
+	for i := range result.Results {
+		result.Results[i].Response.ID = fmt.Sprintf("response-%d", i)
+	}
+	result.Results = append(result.Results, types.VariationResult{
+		Configuration: types.APIConfiguration{ID: "failed"},
+		Response:      types.APIResponse{ResponseStatus: types.ResponseStatusError},
+	})
+
+	scores := client.semanticSimilarities(ctx, "user-1", result, embedder.embed)
+	if len(scores.similarities) != 3 || scores.cached != 0 || embedder.calls != 3 {
+		t.Fatalf("expected 3 fresh embeddings, got %v, %d cached, %d calls", scores.similarities, scores.cached, embedder.calls)
+	}
+	if scores.similarities["config-2"] >= scores.similarities["config-0"] {
+		t.Errorf("expected the outlier to agree least, got %v", scores.similarities)
+	}
+	if scores.clusters["config-0"] != 1 || scores.clusters["config-1"] != 1 || scores.clusters["config-2"] != 2 {
+		t.Errorf("expected the two Paris answers to share a cluster, got %v", scores.clusters)
+	}
+
+	stored, err := client.GetResponseEmbeddings(ctx, "user-1", "run-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if other, _ := client.GetResponseEmbeddings(ctx, "user-2", "run-1"); len(other) != 0 {
+		t.Errorf("expected no embeddings for another user's run, got %d", len(other))
+	}
+	if len(stored) != 3 || stored[0].ResponseID != "response-0" || len(stored[0].Embedding) != mockEmbeddingDimensions {
+		t.Errorf("expected the 3 successful responses' embeddings to be stored, got %+v", stored)
+	}
+
+	// Recomputing the comparison reuses every embedding and replaces the stored ones
+	scores = client.semanticSimilarities(ctx, "user-1", result, embedder.embed)
+	if scores.cached != 3 || embedder.calls != 3 {
+		t.Errorf("expected 3 cached embeddings without new calls, got %d cached, %d calls", scores.cached, embedder.calls)
+	}
+	if stored, _ := client.GetResponseEmbeddings(ctx, "user-1", "run-1"); len(stored) != 3 {
+		t.Errorf("expected recomputing to replace the stored embeddings, got %d", len(stored))
+	}
+}
+
+func TestClusterEmbeddings(t *testing.T) {
+	embeddings := [][]float32{{1, 0}, {0, 1}, {0.99, 0.1}, {0.1, 0.99}, {-1, 0}}
+	clusters := ClusterEmbeddings(embeddings, 0.9)
+	expected := []int{1, 2, 1, 2, 3}
+	for i := range expected {
+		if clusters[i] != expected[i] {
+			t.Fatalf("expected clusters %v, got %v", expected, clusters)
+		}
+	}
+
+	// Single linkage chains embeddings that are only close to their neighbours
+	chain := [][]float32{{1, 0}, {0.8, 0.6}, {0.28, 0.96}}
+	if clusters := ClusterEmbeddings(chain, 0.79); clusters[0] != 1 || clusters[2] != 1 {
+		t.Errorf("expected the chain to form one cluster, got %v", clusters)
+	}
+}
+
+func TestEmbedText(t *testing.T) {
+	client := newEmbeddingTestClient(t)
+	ctx := context.Background()
+
+	provider := &countingProvider{}
+	client.SetEmbeddingProvider(provider)
+	first, err := client.EmbedText(ctx, "user-1", "", "Paris is the capital of France.")
+	if err != nil || len(first) != mockEmbeddingDimensions {
+		t.Fatalf("expected a mock embedding, got %d values, %v", len(first), err)
+	}
+	if _, err := client.EmbedText(ctx, "user-1", "", "Paris is the capital of France."); err != nil || provider.calls != 1 {
+		t.Errorf("expected the second lookup to hit the cache, got %d provider calls, %v", provider.calls, err)
+	}
+
+	if _, ok := NewEmbeddingProvider("").(MockEmbeddingProvider); !ok {
+		t.Errorf("expected the mock provider without an API key")
+	}
+	if provider, ok := NewEmbeddingProvider("key").(*GeminiEmbeddingProvider); !ok || provider.Name() != "gemini" {
+		t.Errorf("expected the Gemini provider with an API key")
+	}
+}
+
+// countingProvider is a mock EmbeddingProvider that counts its calls
+type countingProvider struct {
+	MockEmbeddingProvider
+	calls int
+}
+
+func (p *countingProvider) Embed(ctx context.Context, model, text string) ([]float32, error) {
+	p.calls++
+	return p.MockEmbeddingProvider.Embed(ctx, model, text)
+}
@@ -0,0 +1,144 @@
+package gogent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gogent/internal/types"
+)
+
+// EstimateExecution estimates the input tokens and the most a request could cost before any run is
+// created. Each configuration's first request is built as a dry run builds it; Gemini counts its
+// tokens when an API key is configured, and they are estimated locally otherwise. Nothing is stored.
+func (c *Client) EstimateExecution(ctx context.Context, request *types.MultiExecutionRequest) (*types.ExecutionEstimate, error) {
+	if err := ValidateRequestSafetyPolicies(request); err != nil {
+		return nil, err
+	}
+	if err := ValidateRepetitions(request.Repetitions); err != nil {
+		return nil, err
+	}
+	repetitions := max(request.Repetitions, 1)
+
+	estimate := &types.ExecutionEstimate{Variations: make([]types.VariationEstimate, 0, len(request.Configurations))}
+	models := make(map[string]types.ModelInfo)
+	catalog, err := c.ListModels(ctx)
+	if err != nil {
+		estimate.Warnings = append(estimate.Warnings, fmt.Sprintf("Model limits were not checked: %v", err))
+	}
+	for _, model := range catalog {
+		models[model.Name] = model
+	}
+
+	for _, config := range request.Configurations {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := prepareConfiguration(&config, request); err != nil {
+			return nil, fmt.Errorf("configuration %s: %w", config.VariationName, err)
+		}
+		model, known := models[strings.TrimPrefix(config.ModelName, "models/")]
+		variation := c.estimateVariation(ctx, &config, request.BasePrompt, request.Context, model)
+		if !known && len(models) > 0 && catalogCovers(&config) && config.Backend != types.BackendVertex {
+			variation.Warnings = append(variation.Warnings, fmt.Sprintf("%s is not in the model catalog", config.ModelName))
+		}
+		variation.Repetitions = repetitions
+		variation.MaxCostUSD *= float64(repetitions)
+
+		estimate.InputTokens += variation.InputTokens * repetitions
+		estimate.MaxOutputTokens += variation.MaxOutputTokens * repetitions
+		estimate.MaxCostUSD += variation.MaxCostUSD
+		estimate.Variations = append(estimate.Variations, variation)
+	}
+	return estimate, nil
+}
+
+// estimateVariation estimates one call of a prepared configuration against its model's catalog
+// entry, which is zero for models the catalog doesn't list
+func (c *Client) estimateVariation(ctx context.Context, config *types.APIConfiguration, prompt, promptContext string, model types.ModelInfo) types.VariationEstimate {
+	variation := types.VariationEstimate{
+		VariationName:    config.VariationName,
+		Provider:         config.Provider,
+		ModelName:        config.ModelName,
+		TokenSource:      types.TokenCountLocal,
+		InputTokenLimit:  model.InputTokenLimit,
+		OutputTokenLimit: model.OutputTokenLimit,
+	}
+
+	_, prompt, promptContext, blocked := applyInputGuards(config.Guardrails, prompt, promptContext)
+	if blocked {
+		variation.Warnings = append(variation.Warnings, "An input guard blocks the prompt, so nothing would be sent")
+		return variation
+	}
+	if config.Retrieval != nil {
+		variation.Warnings = append(variation.Warnings, "Retrieved document chunks are not counted")
+	}
+
+	apiRequest := &types.APIRequest{Prompt: prompt, Context: promptContext}
+	if config.Provider == types.ProviderOllama {
+		chatRequest := ollamaChatRequest(config, apiRequest)
+		for _, message := range chatRequest.Messages {
+			variation.InputTokens += estimateTokens(message.Content)
+		}
+		variation.InputTokens += estimateJSONTokens(chatRequest.Tools)
+	} else {
+		generateRequest, finalPrompt := geminiRequest(config, apiRequest)
+		counted := false
+		if c.config != nil && c.config.APIKey != "" && config.Backend != types.BackendVertex {
+			tokens, err := c.geminiAPI().CountTokens(ctx, config.ModelName, generateRequest)
+			if err != nil {
+				variation.Warnings = append(variation.Warnings, fmt.Sprintf("countTokens failed, so input tokens were estimated locally: %v", err))
+			} else {
+				variation.InputTokens, variation.TokenSource, counted = tokens, types.TokenCountProvider, true
+			}
+		}
+		if !counted {
+			variation.InputTokens = estimateTokens(finalPrompt) + estimateJSONTokens(generateRequest.Tools)
+			if generateRequest.SystemInstruction != nil {
+				for _, part := range generateRequest.SystemInstruction.Parts {
+					variation.InputTokens += estimateTokens(part.Text)
+				}
+			}
+		}
+	}
+	if len(config.Tools) > 0 {
+		variation.Warnings = append(variation.Warnings, "A function call adds a follow-up call, which is not included")
+	}
+
+	if model.InputTokenLimit > 0 && variation.InputTokens > int(model.InputTokenLimit) {
+		variation.Warnings = append(variation.Warnings, fmt.Sprintf("%d input tokens exceed %s's limit of %d",
+			variation.InputTokens, config.ModelName, model.InputTokenLimit))
+	}
+	switch {
+	case config.MaxTokens != nil:
+		variation.MaxOutputTokens = int(*config.MaxTokens)
+		if model.OutputTokenLimit > 0 && *config.MaxTokens > model.OutputTokenLimit {
+			variation.Warnings = append(variation.Warnings, fmt.Sprintf("maxTokens %d exceeds %s's output limit of %d",
+				*config.MaxTokens, config.ModelName, model.OutputTokenLimit))
+		}
+	case model.OutputTokenLimit > 0:
+		variation.MaxOutputTokens = int(model.OutputTokenLimit)
+	default:
+		variation.Warnings = append(variation.Warnings, "No maxTokens and no known output limit, so output is not costed")
+	}
+
+	if config.CandidateCount != nil && *config.CandidateCount > 1 {
+		variation.MaxOutputTokens *= int(*config.CandidateCount)
+	}
+
+	if listPrice(config.ModelName) == nil {
+		variation.Warnings = append(variation.Warnings, fmt.Sprintf("%s has no list price, so its cost is not estimated", config.ModelName))
+	}
+	variation.MaxCostUSD = EstimateCostUSD(config.ModelName, variation.InputTokens, variation.MaxOutputTokens)
+	return variation
+}
+
+// estimateJSONTokens estimates the tokens of a value sent as JSON, such as tool declarations
+func estimateJSONTokens(value interface{}) int {
+	encoded, err := json.Marshal(value)
+	if err != nil || string(encoded) == "null" {
+		return 0
+	}
+	return estimateTokens(string(encoded))
+}
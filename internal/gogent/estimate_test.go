@@ -0,0 +1,94 @@
+package gogent
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gogent/internal/types"
+)
+
+func TestEstimateExecution(t *testing.T) {
+	maxTokens := int32(1000)
+	tooMany := int32(100000)
+	request := &types.MultiExecutionRequest{
+		BasePrompt: strings.Repeat("a", 400),
+		Configurations: []types.APIConfiguration{
+			{VariationName: "short", ModelName: "gemini-2.0-flash", MaxTokens: &maxTokens},
+			{VariationName: "long", ModelName: "gemini-2.0-flash", MaxTokens: &tooMany},
+			{VariationName: "unlimited", ModelName: "gemini-1.5-pro"},
+			{VariationName: "local", ModelName: "llama3.2", Provider: types.ProviderOllama, MaxTokens: &maxTokens},
+		},
+		Repetitions: 2,
+	}
+
+	t.Run("local_tokenizer", func(t *testing.T) {
+		client := NewInMemoryClient(&types.GeminiClientConfig{})
+		estimate, err := client.EstimateExecution(context.Background(), request)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(estimate.Variations) != 4 {
+			t.Fatalf("expected one estimate per configuration, got %d", len(estimate.Variations))
+		}
+
+		short := estimate.Variations[0]
+		if short.TokenSource != types.TokenCountLocal || short.InputTokens != 100 || short.MaxOutputTokens != 1000 || short.Repetitions != 2 {
+			t.Errorf("expected 100 local input tokens and 1000 output tokens, got %+v", short)
+		}
+		if want := 2 * EstimateCostUSD("gemini-2.0-flash", 100, 1000); math.Abs(short.MaxCostUSD-want) > 1e-12 || len(short.Warnings) != 0 {
+			t.Errorf("expected a cost of %v without warnings, got %v %v", want, short.MaxCostUSD, short.Warnings)
+		}
+
+		if long := estimate.Variations[1]; len(long.Warnings) != 1 || !strings.Contains(long.Warnings[0], "output limit of 8192") {
+			t.Errorf("expected a warning for maxTokens over the model's limit, got %v", long.Warnings)
+		}
+		if unlimited := estimate.Variations[2]; unlimited.MaxOutputTokens != 8192 {
+			t.Errorf("expected the model's output limit without maxTokens, got %d", unlimited.MaxOutputTokens)
+		}
+		if local := estimate.Variations[3]; local.MaxCostUSD != 0 || len(local.Warnings) != 1 || !strings.Contains(local.Warnings[0], "no list price") {
+			t.Errorf("expected an unpriced Ollama model, got %+v", local)
+		}
+
+		var input, output int
+		for _, variation := range estimate.Variations {
+			input += variation.InputTokens * 2
+			output += variation.MaxOutputTokens * 2
+		}
+		if estimate.InputTokens != input || estimate.MaxOutputTokens != output {
+			t.Errorf("expected totals over every repetition, got %d and %d", estimate.InputTokens, estimate.MaxOutputTokens)
+		}
+	})
+
+	t.Run("count_tokens", func(t *testing.T) {
+		client := NewInMemoryClient(&types.GeminiClientConfig{APIKey: "gemini-api-key"})
+		client.SetHTTPTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			body := `{"models": [{"name": "models/gemini-2.0-flash", "inputTokenLimit": 50, "outputTokenLimit": 8192, "supportedGenerationMethods": ["generateContent"]}]}`
+			if strings.HasSuffix(req.URL.Path, ":countTokens") {
+				body = `{"totalTokens": 120}`
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+		}))
+
+		estimate, err := client.EstimateExecution(context.Background(), &types.MultiExecutionRequest{
+			BasePrompt:     "Hello",
+			Configurations: []types.APIConfiguration{request.Configurations[0], request.Configurations[2]},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		counted := estimate.Variations[0]
+		if counted.TokenSource != types.TokenCountProvider || counted.InputTokens != 120 {
+			t.Errorf("expected Gemini's token count, got %+v", counted)
+		}
+		if len(counted.Warnings) != 1 || !strings.Contains(counted.Warnings[0], "exceed gemini-2.0-flash's limit of 50") {
+			t.Errorf("expected a warning for exceeding the input limit, got %v", counted.Warnings)
+		}
+		if uncataloged := estimate.Variations[1]; !strings.Contains(strings.Join(uncataloged.Warnings, "; "), "not in the model catalog") {
+			t.Errorf("expected a warning for a model missing from the catalog, got %v", uncataloged.Warnings)
+		}
+	})
+}
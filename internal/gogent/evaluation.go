@@ -0,0 +1,228 @@
+package gogent
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"gogent/internal/types"
+
+	"github.com/google/uuid"
+)
+
+// defaultEmbeddingMatchThreshold is the cosine similarity an embedding match needs when none is set
+const defaultEmbeddingMatchThreshold = 0.8
+
+// ValidateExpectedAnswer checks an expected answer and fills in the default match mode and threshold
+func ValidateExpectedAnswer(expected *types.ExpectedAnswer) error {
+	if expected == nil {
+		return nil
+	}
+	if strings.TrimSpace(expected.Answer) == "" {
+		return fmt.Errorf("expected answer must not be empty")
+	}
+	if expected.Threshold < 0 || expected.Threshold > 1 {
+		return fmt.Errorf("expected answer threshold must be between 0 and 1, got %v", expected.Threshold)
+	}
+
+	if expected.MatchMode == "" {
+		expected.MatchMode = types.MatchModeExact
+	}
+	switch expected.MatchMode {
+	case types.MatchModeExact, types.MatchModeSubstring:
+	case types.MatchModeRegex:
+		if _, err := regexp.Compile(expected.Answer); err != nil {
+			return fmt.Errorf("invalid expected answer pattern: %w", err)
+		}
+	case types.MatchModeEmbedding:
+		if expected.Threshold == 0 {
+			expected.Threshold = defaultEmbeddingMatchThreshold
+		}
+	default:
+		return fmt.Errorf("unknown match mode %q (expected exact, substring, regex or embedding)", expected.MatchMode)
+	}
+	return nil
+}
+
+// matchAnswer scores a response against a validated expected answer
+func (c *Client) matchAnswer(ctx context.Context, userID, response string, expected *types.ExpectedAnswer, embed embedFunc) (float64, bool, error) {
+	var passed bool
+	switch expected.MatchMode {
+	case types.MatchModeExact:
+		passed = strings.EqualFold(strings.TrimSpace(response), strings.TrimSpace(expected.Answer))
+	case types.MatchModeSubstring:
+		passed = strings.Contains(strings.ToLower(response), strings.ToLower(strings.TrimSpace(expected.Answer)))
+	case types.MatchModeRegex:
+		pattern, err := regexp.Compile(expected.Answer)
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid expected answer pattern: %w", err)
+		}
+		passed = pattern.MatchString(response)
+	case types.MatchModeEmbedding:
+		answerEmbedding, _, err := c.cachedEmbedding(ctx, userID, defaultEmbeddingModel, expected.Answer, embed)
+		if err != nil {
+			return 0, false, err
+		}
+		responseEmbedding, _, err := c.cachedEmbedding(ctx, userID, defaultEmbeddingModel, response, embed)
+		if err != nil {
+			return 0, false, err
+		}
+		similarity := CosineSimilarity(answerEmbedding, responseEmbedding)
+		return similarity, similarity >= expected.Threshold, nil
+	default:
+		return 0, false, fmt.Errorf("unknown match mode %q", expected.MatchMode)
+	}
+
+	if passed {
+		return 1, true, nil
+	}
+	return 0, false, nil
+}
+
+// evaluateResults scores each variation's response against the expected answer. Failed responses
+// fail their case; responses that could not be scored are left out.
+func (c *Client) evaluateResults(ctx context.Context, userID string, result *types.ExecutionResult, expected *types.ExpectedAnswer, embed embedFunc) []types.EvaluationResult {
+	var evaluations []types.EvaluationResult
+	for _, r := range result.Results {
+		evaluation := types.EvaluationResult{
+			ID:              uuid.New().String(),
+			ExecutionRunID:  result.ExecutionRun.ID,
+			ConfigurationID: r.Configuration.ID,
+			VariationName:   r.Configuration.VariationName,
+			Repetition:      r.Repetition,
+			MatchMode:       expected.MatchMode,
+			ExpectedAnswer:  expected.Answer,
+			CreatedAt:       time.Now(),
+		}
+
+		if r.Response.ResponseStatus == types.ResponseStatusSuccess {
+			score, passed, err := c.matchAnswer(ctx, userID, r.Response.ResponseText, expected, embed)
+			if err != nil {
+				log.Printf("⚠️ Warning: failed to evaluate %s: %v", r.Configuration.VariationName, err)
+				continue
+			}
+			evaluation.Score = score
+			evaluation.Passed = passed
+		}
+		evaluations = append(evaluations, evaluation)
+	}
+	return evaluations
+}
+
+// EvaluationAccuracy aggregates evaluations per configuration, in the order configurations first appear
+func EvaluationAccuracy(evaluations []types.EvaluationResult) []types.ConfigurationAccuracy {
+	var accuracy []types.ConfigurationAccuracy
+	index := make(map[string]int)
+	for _, evaluation := range evaluations {
+		i, ok := index[evaluation.ConfigurationID]
+		if !ok {
+			i = len(accuracy)
+			index[evaluation.ConfigurationID] = i
+			accuracy = append(accuracy, types.ConfigurationAccuracy{
+				VariationName:   evaluation.VariationName,
+				ConfigurationID: evaluation.ConfigurationID,
+			})
+		}
+		accuracy[i].Cases++
+		if evaluation.Passed {
+			accuracy[i].Passed++
+		}
+	}
+
+	for i := range accuracy {
+		accuracy[i].Accuracy = float64(accuracy[i].Passed) / float64(accuracy[i].Cases)
+	}
+	return accuracy
+}
+
+// storeEvaluationResults stores a run's evaluations in one statement
+func (c *Client) storeEvaluationResults(ctx context.Context, userID string, evaluations []types.EvaluationResult) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	if len(evaluations) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, len(evaluations))
+	args := make([]interface{}, 0, len(evaluations)*11)
+	for i, e := range evaluations {
+		placeholders[i] = "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+		args = append(args, e.ID, userID, e.ExecutionRunID, e.ConfigurationID, e.VariationName, e.Repetition,
+			e.MatchMode, e.ExpectedAnswer, e.Score, e.Passed, e.CreatedAt)
+	}
+
+	_, err := c.db.ExecContext(ctx, `
+		INSERT INTO evaluation_results (id, user_id, execution_run_id, configuration_id, variation_name, repetition,
+			match_mode, expected_answer, score, passed, created_at)
+		VALUES `+strings.Join(placeholders, ", "), args...)
+	if err != nil {
+		return fmt.Errorf("failed to store evaluation results: %w", err)
+	}
+	return nil
+}
+
+// GetEvaluationResults returns the evaluations of a run owned by the user in the order they were scored
+func (c *Client) GetEvaluationResults(ctx context.Context, userID, executionRunID string) ([]types.EvaluationResult, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT id, execution_run_id, configuration_id, variation_name, repetition, match_mode,
+		       expected_answer, score, passed, created_at
+		FROM evaluation_results
+		WHERE execution_run_id = ? AND user_id = ?
+		ORDER BY created_at ASC, variation_name ASC, repetition ASC
+	`, executionRunID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get evaluation results: %w", err)
+	}
+	defer rows.Close()
+
+	var evaluations []types.EvaluationResult
+	for rows.Next() {
+		var e types.EvaluationResult
+		if err := rows.Scan(&e.ID, &e.ExecutionRunID, &e.ConfigurationID, &e.VariationName, &e.Repetition,
+			&e.MatchMode, &e.ExpectedAnswer, &e.Score, &e.Passed, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan evaluation result: %w", err)
+		}
+		evaluations = append(evaluations, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate evaluation results: %w", err)
+	}
+	return evaluations, nil
+}
+
+// batchAccuracy aggregates the evaluations of a batch's item runs by variation name
+func (c *Client) batchAccuracy(ctx context.Context, batchID string) ([]types.ConfigurationAccuracy, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT er.variation_name, COUNT(*), COALESCE(SUM(CASE WHEN er.passed THEN 1 ELSE 0 END), 0)
+		FROM evaluation_results er
+		JOIN batch_items bi ON er.execution_run_id = bi.execution_run_id
+		WHERE bi.batch_run_id = ?
+		GROUP BY er.variation_name
+		ORDER BY er.variation_name ASC
+	`, batchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get batch accuracy: %w", err)
+	}
+	defer rows.Close()
+
+	var accuracy []types.ConfigurationAccuracy
+	for rows.Next() {
+		var a types.ConfigurationAccuracy
+		if err := rows.Scan(&a.VariationName, &a.Cases, &a.Passed); err != nil {
+			return nil, fmt.Errorf("failed to scan batch accuracy: %w", err)
+		}
+		a.Accuracy = float64(a.Passed) / float64(a.Cases)
+		accuracy = append(accuracy, a)
+	}
+	return accuracy, rows.Err()
+}
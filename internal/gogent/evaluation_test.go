@@ -0,0 +1,158 @@
+package gogent
+
+import (
+	"context"
+	"testing"
+
+	"gogent/internal/types"
+)
+
+func TestValidateExpectedAnswer(t *testing.T) {
+	expected := &types.ExpectedAnswer{Answer: "Paris"}
+	if err := ValidateExpectedAnswer(expected); err != nil || expected.MatchMode != types.MatchModeExact {
+		t.Errorf("expected exact matching by default, got %q, %v", expected.MatchMode, err)
+	}
+	embedding := &types.ExpectedAnswer{Answer: "Paris", MatchMode: types.MatchModeEmbedding}
+	if err := ValidateExpectedAnswer(embedding); err != nil || embedding.Threshold != defaultEmbeddingMatchThreshold {
+		t.Errorf("expected the default similarity threshold, got %v, %v", embedding.Threshold, err)
+	}
+
+	for _, invalid := range []*types.ExpectedAnswer{
+		{Answer: "  "},
+		{Answer: "Paris", MatchMode: "fuzzy"},
+		{Answer: "Par(is", MatchMode: types.MatchModeRegex},
+		{Answer: "Paris", MatchMode: types.MatchModeEmbedding, Threshold: 1.5},
+	} {
+		if err := ValidateExpectedAnswer(invalid); err == nil {
+			t.Errorf("expected %+v to be rejected", invalid)
+		}
+	}
+}
+
+func TestMatchAnswer(t *testing.T) {
+	client := newEmbeddingTestClient(t)
+	ctx := context.Background()
+	embedder := &countingEmbedder{}
+
+	tests := []struct {
+		name     string
+		expected types.ExpectedAnswer
+		response string
+		passed   bool
+	}{
+		{"exact_ignores_case_and_whitespace", types.ExpectedAnswer{Answer: "Paris", MatchMode: types.MatchModeExact}, " paris\n", true},
+		{"exact_rejects_extra_text", types.ExpectedAnswer{Answer: "Paris", MatchMode: types.MatchModeExact}, "It is Paris.", false},
+		{"substring", types.ExpectedAnswer{Answer: "Paris", MatchMode: types.MatchModeSubstring}, "The capital is PARIS.", true},
+		{"regex", types.ExpectedAnswer{Answer: `\b(42|forty-two)\b`, MatchMode: types.MatchModeRegex}, "The answer is 42.", true},
+		{"regex_miss", types.ExpectedAnswer{Answer: `^\d+$`, MatchMode: types.MatchModeRegex}, "The answer is 42.", false},
+		{"embedding_similar", types.ExpectedAnswer{Answer: "The capital of France is Paris", MatchMode: types.MatchModeEmbedding, Threshold: 0.8},
+			"Paris is the capital of France.", true},
+		{"embedding_different", types.ExpectedAnswer{Answer: "The capital of France is Paris", MatchMode: types.MatchModeEmbedding, Threshold: 0.8},
+			"Bananas are rich in potassium", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, passed, err := client.matchAnswer(ctx, "user-1", tt.response, &tt.expected, embedder.embed)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if passed != tt.passed || (passed && score <= 0) {
+				t.Errorf("expected passed=%v, got passed=%v with score %v", tt.passed, passed, score)
+			}
+		})
+	}
+}
+
+func TestEvaluateResults(t *testing.T) {
+	client := newEmbeddingTestClient(t)
+	ctx := context.Background()
+	embedder := &countingEmbedder{}
+
+	result := newJudgeTestResult("Paris", "Lyon")
+	result.ExecutionRun.ID = "run-1"
+	result.Results = append(result.Results, types.VariationResult{
+		Configuration: types.APIConfiguration{ID: "config-0", VariationName: "v0"},
+		Response:      types.APIResponse{ResponseStatus: types.ResponseStatusError},
+		Repetition:    2,
+	})
+
+	expected := &types.ExpectedAnswer{Answer: "paris", MatchMode: types.MatchModeExact}
+	evaluations := client.evaluateResults(ctx, "user-1", result, expected, embedder.embed)
+	if len(evaluations) != 3 || !evaluations[0].Passed || evaluations[1].Passed || evaluations[2].Passed {
+		t.Fatalf("expected only the first response to pass, got %+v", evaluations)
+	}
+
+	accuracy := EvaluationAccuracy(evaluations)
+	if len(accuracy) != 2 || accuracy[0].VariationName != "v0" || accuracy[0].Cases != 2 || accuracy[0].Accuracy != 0.5 {
+		t.Errorf("expected v0 to pass 1 of 2 cases, got %+v", accuracy)
+	}
+	if accuracy[1].Cases != 1 || accuracy[1].Accuracy != 0 {
+		t.Errorf("expected v1 to fail its case, got %+v", accuracy[1])
+	}
+}
+
+func TestEvaluationStorageAndBatchAccuracy(t *testing.T) {
+	client := newBatchTestClient(t)
+	ctx := context.Background()
+
+	template := &types.MultiExecutionRequest{
+		Configurations: []types.APIConfiguration{{VariationName: "precise"}},
+		ExpectedAnswer: &types.ExpectedAnswer{Answer: "yes", MatchMode: types.MatchModeSubstring},
+	}
+	batch, err := client.CreateBatchRun(ctx, "user-1", "golden", template)
+	if err != nil {
+		t.Fatalf("unexpected error creating batch run: %v", err)
+	}
+	items := []types.BatchItem{
+		{Prompt: "Is the sky blue?"},
+		{Prompt: "Capital of France?", ExpectedAnswer: &types.ExpectedAnswer{Answer: "Paris", MatchMode: types.MatchModeExact}},
+	}
+	if err := client.AppendBatchItems(ctx, batch.ID, 0, items); err != nil {
+		t.Fatalf("unexpected error storing items: %v", err)
+	}
+
+	pending, err := client.ListPendingBatchItems(ctx, batch.ID, 10)
+	if err != nil {
+		t.Fatalf("unexpected error listing items: %v", err)
+	}
+	if BatchItemRequest(batch, pending[0]).ExpectedAnswer.Answer != "yes" || BatchItemRequest(batch, pending[1]).ExpectedAnswer.Answer != "Paris" {
+		t.Errorf("expected items to inherit or override the template's expected answer")
+	}
+
+	// Each item ran as its own execution run with two configurations
+	for i, runID := range []string{"run-1", "run-2"} {
+		if err := client.FinishBatchItem(ctx, pending[i].ID, runID, ""); err != nil {
+			t.Fatalf("unexpected error finishing item: %v", err)
+		}
+		evaluations := []types.EvaluationResult{
+			{ID: runID + "-a", ExecutionRunID: runID, ConfigurationID: runID + "-precise", VariationName: "precise", MatchMode: "exact", ExpectedAnswer: "x", Score: 1, Passed: true},
+			{ID: runID + "-b", ExecutionRunID: runID, ConfigurationID: runID + "-creative", VariationName: "creative", MatchMode: "exact", ExpectedAnswer: "x", Passed: i == 0},
+		}
+		if err := client.storeEvaluationResults(ctx, "user-1", evaluations); err != nil {
+			t.Fatalf("unexpected error storing evaluations: %v", err)
+		}
+	}
+
+	stored, err := client.GetEvaluationResults(ctx, "user-1", "run-1")
+	if err != nil || len(stored) != 2 || !stored[0].Passed {
+		t.Fatalf("expected run-1's evaluations back, got %+v, %v", stored, err)
+	}
+	if other, err := client.GetEvaluationResults(ctx, "user-2", "run-1"); err != nil || len(other) != 0 {
+		t.Errorf("expected no evaluations for another user's run, got %+v, %v", other, err)
+	}
+
+	loaded, err := client.GetBatchRun(ctx, "user-1", batch.ID)
+	if err != nil {
+		t.Fatalf("unexpected error loading batch run: %v", err)
+	}
+	if len(loaded.Accuracy) != 2 {
+		t.Fatalf("expected accuracy for 2 variations, got %+v", loaded.Accuracy)
+	}
+	if creative := loaded.Accuracy[0]; creative.VariationName != "creative" || creative.Cases != 2 || creative.Accuracy != 0.5 {
+		t.Errorf("expected creative to pass 1 of 2 items, got %+v", creative)
+	}
+	if precise := loaded.Accuracy[1]; precise.Passed != 2 || precise.Accuracy != 1 {
+		t.Errorf("expected precise to pass every item, got %+v", precise)
+	}
+}
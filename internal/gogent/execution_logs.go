@@ -0,0 +1,78 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gogent/internal/types"
+)
+
+// ErrUnknownLogEntry is returned when a log query continues after an entry the run does not have
+var ErrUnknownLogEntry = errors.New("unknown log entry")
+
+// DefaultLogPageSize is how many log entries a query returns when it sets no limit
+const DefaultLogPageSize = 100
+
+// ExecutionLogFilter selects and pages through the log entries of a run
+type ExecutionLogFilter struct {
+	// MinLevel keeps entries at or above this level; empty keeps every level
+	MinLevel types.LogLevel
+	// Category keeps entries of this category; empty keeps every category
+	Category types.LogCategory
+	// AfterID continues after this entry; empty starts at the run's first entry
+	AfterID string
+	// Limit caps the number of entries returned
+	Limit int32
+}
+
+// levels returns the levels at or above MinLevel, or nil when every level is kept
+func (f ExecutionLogFilter) levels() []types.LogLevel {
+	if f.MinLevel == "" {
+		return nil
+	}
+	var levels []types.LogLevel
+	for _, level := range []types.LogLevel{types.LogLevelDebug, types.LogLevelInfo, types.LogLevelSuccess, types.LogLevelWarn, types.LogLevelError} {
+		if level.Severity() >= f.MinLevel.Severity() {
+			levels = append(levels, level)
+		}
+	}
+	return levels
+}
+
+// matches reports whether an entry passes the level and category filters
+func (f ExecutionLogFilter) matches(entry types.ExecutionLog) bool {
+	if f.MinLevel != "" && entry.LogLevel.Severity() < f.MinLevel.Severity() {
+		return false
+	}
+	return f.Category == "" || entry.LogCategory == f.Category
+}
+
+// QueryExecutionLogs pages through the log entries of a run owned by the user, oldest first. Pass
+// the ID of the last entry returned as AfterID to get the entries logged since.
+func (c *Client) QueryExecutionLogs(ctx context.Context, userID, executionRunID string, filter ExecutionLogFilter) ([]types.ExecutionLog, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultLogPageSize
+	}
+	if filter.Limit > MaxPageSize {
+		filter.Limit = MaxPageSize
+	}
+
+	// Entries still queued by the log writer would otherwise be missing from the page
+	c.executionLogs().flush()
+
+	logs, err := c.store.QueryExecutionLogs(ctx, userID, executionRunID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query execution logs: %w", err)
+	}
+	return logs, nil
+}
+
+// storesLogLevel reports whether entries at level are kept in the store, given the client's
+// MinStoredLogLevel
+func (c *Client) storesLogLevel(level types.LogLevel) bool {
+	if c.config == nil || c.config.MinStoredLogLevel == "" {
+		return true
+	}
+	return level.Severity() >= c.config.MinStoredLogLevel.Severity()
+}
@@ -0,0 +1,100 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gogent/internal/testdb"
+	"gogent/internal/types"
+)
+
+func TestQueryExecutionLogs(t *testing.T) {
+	client, _ := newStoreTestClient(t)
+	client.config.MinStoredLogLevel = types.LogLevelInfo
+	ctx := context.Background()
+
+	run, err := client.CreateExecutionRun(ctx, "user-1", "Logged run", "", false)
+	if err != nil {
+		t.Fatalf("failed to create run: %v", err)
+	}
+	runCtx := withExecutionScope(ctx, executionScope{executionRunID: run.ID})
+	client.logExecutionEvent(runCtx, types.LogLevelDebug, types.LogCategorySetup, "debug detail", nil)
+	client.logExecutionEvent(runCtx, types.LogLevelInfo, types.LogCategorySetup, "starting", nil)
+	client.logExecutionEvent(runCtx, types.LogLevelWarn, types.LogCategoryAPICall, "slow response", nil)
+	client.logExecutionEvent(runCtx, types.LogLevelSuccess, types.LogCategoryAPICall, "response received", nil)
+	client.logExecutionEvent(runCtx, types.LogLevelError, types.LogCategoryError, "function failed", nil)
+
+	logs, err := client.QueryExecutionLogs(ctx, "user-1", run.ID, ExecutionLogFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(logs) != 4 || logs[0].Message != "starting" {
+		t.Fatalf("expected the 4 entries at INFO and above in order, got %+v", logs)
+	}
+
+	if warnings, _ := client.QueryExecutionLogs(ctx, "user-1", run.ID, ExecutionLogFilter{MinLevel: types.LogLevelWarn}); len(warnings) != 2 {
+		t.Errorf("expected the WARN and ERROR entries, got %+v", warnings)
+	}
+	apiCalls, _ := client.QueryExecutionLogs(ctx, "user-1", run.ID, ExecutionLogFilter{Category: types.LogCategoryAPICall})
+	if len(apiCalls) != 2 || apiCalls[1].Message != "response received" {
+		t.Errorf("expected the API call entries, got %+v", apiCalls)
+	}
+
+	page, _ := client.QueryExecutionLogs(ctx, "user-1", run.ID, ExecutionLogFilter{Limit: 2})
+	rest, _ := client.QueryExecutionLogs(ctx, "user-1", run.ID, ExecutionLogFilter{AfterID: page[1].ID})
+	if len(page) != 2 || len(rest) != 2 || rest[0].Message != "response received" {
+		t.Errorf("expected paging after an entry to continue where the page ended, got %+v then %+v", page, rest)
+	}
+
+	if _, err := client.QueryExecutionLogs(ctx, "user-1", run.ID, ExecutionLogFilter{AfterID: "missing"}); !errors.Is(err, ErrUnknownLogEntry) {
+		t.Errorf("expected ErrUnknownLogEntry, got %v", err)
+	}
+	if others, err := client.QueryExecutionLogs(ctx, "user-2", run.ID, ExecutionLogFilter{}); err != nil || len(others) != 0 {
+		t.Errorf("expected no logs for another user's run, got %+v (%v)", others, err)
+	}
+}
+
+func TestSQLStoreQueryExecutionLogs(t *testing.T) {
+	database := testdb.Open(t)
+
+	_, err := database.Exec(`
+		INSERT INTO execution_runs (id, user_id) VALUES ('run-1', 'user-1');
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+	// b and c share a timestamp, so paging after b relies on the ID to break the tie
+	now := time.Now().UTC().Truncate(time.Second)
+	for _, entry := range []struct {
+		id, level string
+		timestamp time.Time
+	}{
+		{"a", "DEBUG", now}, {"b", "INFO", now.Add(time.Second)}, {"c", "ERROR", now.Add(time.Second)}, {"d", "WARN", now.Add(2 * time.Second)},
+	} {
+		if _, err := database.Exec("INSERT INTO execution_logs (id, execution_run_id, configuration_id, request_id, log_level, log_category, message, details, timestamp) VALUES (?, 'run-1', NULL, NULL, ?, 'SETUP', ?, '{\"step\": 1}', ?)",
+			entry.id, entry.level, "log "+entry.id, entry.timestamp); err != nil {
+			t.Fatalf("failed to insert log: %v", err)
+		}
+	}
+
+	store := NewSQLStore(database)
+	ctx := context.Background()
+	logs, err := store.QueryExecutionLogs(ctx, "user-1", "run-1", ExecutionLogFilter{AfterID: "b", MinLevel: types.LogLevelWarn})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(logs) != 2 || logs[0].ID != "c" || logs[1].ID != "d" || logs[0].Details["step"] != float64(1) {
+		t.Errorf("expected c and d with their details, got %+v", logs)
+	}
+	if logs, _ := store.QueryExecutionLogs(ctx, "user-1", "run-1", ExecutionLogFilter{Limit: 1}); len(logs) != 1 || logs[0].ID != "a" {
+		t.Errorf("expected the oldest entry, got %+v", logs)
+	}
+	if _, err := store.QueryExecutionLogs(ctx, "user-1", "run-1", ExecutionLogFilter{AfterID: "missing"}); !errors.Is(err, ErrUnknownLogEntry) {
+		t.Errorf("expected ErrUnknownLogEntry, got %v", err)
+	}
+	if logs, err := store.QueryExecutionLogs(ctx, "user-2", "run-1", ExecutionLogFilter{}); err != nil || len(logs) != 0 {
+		t.Errorf("expected no logs for another user's run, got %+v (%v)", logs, err)
+	}
+}
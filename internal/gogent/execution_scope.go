@@ -0,0 +1,34 @@
+package gogent
+
+import "context"
+
+// executionScope identifies the run, and within it the configuration and request, that work on a
+// context belongs to. It travels with the context down the call chain of a run, so one client can
+// execute several runs at once and still record each log entry against the run that wrote it.
+type executionScope struct {
+	executionRunID  string
+	configurationID string
+	requestID       string
+}
+
+// executionScopeKey is the context key an executionScope is stored under
+type executionScopeKey struct{}
+
+// withExecutionScope returns a context carrying scope
+func withExecutionScope(ctx context.Context, scope executionScope) context.Context {
+	return context.WithValue(ctx, executionScopeKey{}, scope)
+}
+
+// executionScopeFrom returns the scope ctx carries, reporting false outside of a run
+func executionScopeFrom(ctx context.Context) (executionScope, bool) {
+	scope, ok := ctx.Value(executionScopeKey{}).(executionScope)
+	return scope, ok && scope.executionRunID != ""
+}
+
+// optionalString returns a pointer to value, or nil when it is empty
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,283 @@
+package gogent
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gogent/internal/types"
+)
+
+var (
+	// ErrInvalidFeedback is returned for feedback with an unknown thumb, a rating outside 1-5, an
+	// overlong comment or nothing at all
+	ErrInvalidFeedback = errors.New("invalid feedback")
+	// ErrResponseNotFound is returned when a run has no response with an ID
+	ErrResponseNotFound = errors.New("response not found in execution run")
+	// ErrFeedbackNotFound is returned when deleting feedback a response does not have
+	ErrFeedbackNotFound = errors.New("feedback not found")
+)
+
+const (
+	// maxFeedbackCommentLength caps the characters of a feedback comment
+	maxFeedbackCommentLength = 4000
+	// feedbackWeight is the share of a configuration's overall score taken by its feedback score
+	feedbackWeight = 0.5
+)
+
+// ValidateFeedback checks a feedback's thumb, rating and comment, and that it has at least one
+func ValidateFeedback(feedback *types.ResponseFeedback) error {
+	switch feedback.Thumbs {
+	case "", types.FeedbackThumbsUp, types.FeedbackThumbsDown:
+	default:
+		return fmt.Errorf("%w: thumbs must be %q or %q, got %q", ErrInvalidFeedback, types.FeedbackThumbsUp, types.FeedbackThumbsDown, feedback.Thumbs)
+	}
+	if feedback.Rating != nil && (*feedback.Rating < 1 || *feedback.Rating > 5) {
+		return fmt.Errorf("%w: rating must be between 1 and 5, got %d", ErrInvalidFeedback, *feedback.Rating)
+	}
+	if length := len([]rune(feedback.Comment)); length > maxFeedbackCommentLength {
+		return fmt.Errorf("%w: comment must be at most %d characters, got %d", ErrInvalidFeedback, maxFeedbackCommentLength, length)
+	}
+	if feedback.Thumbs == "" && feedback.Rating == nil && feedback.Comment == "" {
+		return fmt.Errorf("%w: set thumbs, a rating or a comment", ErrInvalidFeedback)
+	}
+	return nil
+}
+
+// RecordResponseFeedback stores a person's feedback on a response of one of the user's runs,
+// replacing any earlier feedback on it, and rescores the run's comparison with it
+func (c *Client) RecordResponseFeedback(ctx context.Context, userID, executionRunID string, feedback *types.ResponseFeedback) (*types.ResponseFeedback, error) {
+	if err := ValidateFeedback(feedback); err != nil {
+		return nil, err
+	}
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+
+	stored := *feedback
+	stored.ExecutionRunID = executionRunID
+	err := c.db.QueryRowContext(ctx, `
+		SELECT q.configuration_id
+		FROM api_responses resp
+		JOIN api_requests q ON q.id = resp.request_id
+		WHERE resp.id = ? AND q.execution_run_id = ? AND q.user_id = ?`,
+		feedback.ResponseID, executionRunID, userID).Scan(&stored.ConfigurationID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ErrResponseNotFound, feedback.ResponseID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find response: %w", err)
+	}
+
+	// Replacing feedback keeps when it was first given
+	stored.UpdatedAt = time.Now().UTC()
+	err = c.db.QueryRowContext(ctx, "SELECT created_at FROM response_feedback WHERE response_id = ?", stored.ResponseID).Scan(&stored.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		stored.CreatedAt = stored.UpdatedAt
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get earlier feedback: %w", err)
+	}
+
+	var rating sql.NullInt32
+	if stored.Rating != nil {
+		rating = sql.NullInt32{Int32: int32(*stored.Rating), Valid: true}
+	}
+	_, err = c.db.ExecContext(ctx, `
+		REPLACE INTO response_feedback (response_id, user_id, execution_run_id, configuration_id, thumbs, rating, comment, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		stored.ResponseID, userID, executionRunID, stored.ConfigurationID,
+		sql.NullString{String: stored.Thumbs, Valid: stored.Thumbs != ""}, rating,
+		sql.NullString{String: stored.Comment, Valid: stored.Comment != ""}, stored.CreatedAt, stored.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record feedback: %w", err)
+	}
+
+	if err := c.rescoreComparisonWithFeedback(ctx, userID, executionRunID); err != nil {
+		log.Printf("⚠️ Warning: failed to rescore comparison of execution run %s with feedback: %v", executionRunID, err)
+	}
+	return &stored, nil
+}
+
+// DeleteResponseFeedback removes the feedback on a response of one of the user's runs and rescores
+// the run's comparison without it
+func (c *Client) DeleteResponseFeedback(ctx context.Context, userID, executionRunID, responseID string) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	result, err := c.db.ExecContext(ctx,
+		"DELETE FROM response_feedback WHERE response_id = ? AND execution_run_id = ? AND user_id = ?",
+		responseID, executionRunID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete feedback: %w", err)
+	}
+	if deleted, err := result.RowsAffected(); err == nil && deleted == 0 {
+		return fmt.Errorf("%w: %s", ErrFeedbackNotFound, responseID)
+	}
+
+	if err := c.rescoreComparisonWithFeedback(ctx, userID, executionRunID); err != nil {
+		log.Printf("⚠️ Warning: failed to rescore comparison of execution run %s with feedback: %v", executionRunID, err)
+	}
+	return nil
+}
+
+// GetRunFeedback returns the feedback on the responses of one of the user's runs, oldest first,
+// and its aggregate per configuration
+func (c *Client) GetRunFeedback(ctx context.Context, userID, executionRunID string) (*types.RunFeedback, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	if _, err := c.store.GetExecutionRun(ctx, userID, executionRunID); errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrExecutionRunNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get execution run: %w", err)
+	}
+
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT response_id, configuration_id, thumbs, rating, comment, created_at, updated_at
+		FROM response_feedback
+		WHERE execution_run_id = ? AND user_id = ?
+		ORDER BY created_at ASC, response_id ASC`, executionRunID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feedback: %w", err)
+	}
+	defer rows.Close()
+
+	runFeedback := &types.RunFeedback{ExecutionRunID: executionRunID, Feedback: []types.ResponseFeedback{}}
+	for rows.Next() {
+		feedback := types.ResponseFeedback{ExecutionRunID: executionRunID}
+		var thumbs, comment sql.NullString
+		var rating sql.NullInt32
+		if err := rows.Scan(&feedback.ResponseID, &feedback.ConfigurationID, &thumbs, &rating, &comment,
+			&feedback.CreatedAt, &feedback.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan feedback: %w", err)
+		}
+		feedback.Thumbs, feedback.Comment = thumbs.String, comment.String
+		if rating.Valid {
+			value := int(rating.Int32)
+			feedback.Rating = &value
+		}
+		runFeedback.Feedback = append(runFeedback.Feedback, feedback)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate feedback: %w", err)
+	}
+
+	runFeedback.Configurations, err = c.configurationFeedback(ctx, userID, executionRunID)
+	if err != nil {
+		return nil, err
+	}
+	return runFeedback, nil
+}
+
+// configurationFeedback aggregates the feedback on each configuration of a run in SQL. A
+// configuration's score averages its thumbs (up 1, down 0) and ratings (1 is 0, 5 is 1).
+func (c *Client) configurationFeedback(ctx context.Context, userID, executionRunID string) ([]types.ConfigurationFeedback, error) {
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT f.configuration_id, c.variation_name, COUNT(*),
+			SUM(CASE WHEN f.thumbs = 'up' THEN 1 ELSE 0 END),
+			SUM(CASE WHEN f.thumbs = 'down' THEN 1 ELSE 0 END),
+			COUNT(f.rating), AVG(f.rating),
+			SUM(CASE WHEN f.comment IS NOT NULL AND f.comment <> '' THEN 1 ELSE 0 END)
+		FROM response_feedback f
+		JOIN api_configurations c ON c.id = f.configuration_id
+		WHERE f.execution_run_id = ? AND f.user_id = ?
+		GROUP BY f.configuration_id, c.variation_name
+		ORDER BY c.variation_name ASC, f.configuration_id ASC`, executionRunID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate feedback: %w", err)
+	}
+	defer rows.Close()
+
+	configurations := []types.ConfigurationFeedback{}
+	for rows.Next() {
+		var feedback types.ConfigurationFeedback
+		var averageRating sql.NullFloat64
+		if err := rows.Scan(&feedback.ConfigurationID, &feedback.VariationName, &feedback.Responses, &feedback.ThumbsUp,
+			&feedback.ThumbsDown, &feedback.Ratings, &averageRating, &feedback.Comments); err != nil {
+			return nil, fmt.Errorf("failed to scan feedback aggregate: %w", err)
+		}
+		if averageRating.Valid {
+			feedback.AverageRating = &averageRating.Float64
+		}
+		if signals := feedback.ThumbsUp + feedback.ThumbsDown + feedback.Ratings; signals > 0 {
+			ratingPoints := 0.0
+			if feedback.AverageRating != nil {
+				ratingPoints = float64(feedback.Ratings) * (*feedback.AverageRating - 1) / 4
+			}
+			score := (float64(feedback.ThumbsUp) + ratingPoints) / float64(signals)
+			feedback.Score = &score
+		}
+		configurations = append(configurations, feedback)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate feedback aggregates: %w", err)
+	}
+	return configurations, nil
+}
+
+// rescoreComparisonWithFeedback blends the feedback scores of a run's configurations into its
+// comparison. Runs without a comparison are left alone.
+func (c *Client) rescoreComparisonWithFeedback(ctx context.Context, userID, executionRunID string) error {
+	comparison, err := c.GetComparisonResult(ctx, userID, executionRunID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	feedback, err := c.configurationFeedback(ctx, userID, executionRunID)
+	if err != nil {
+		return err
+	}
+	applyFeedbackScores(comparison, feedback)
+
+	if err := c.store.UpdateComparisonScores(ctx, comparison); err != nil {
+		return fmt.Errorf("failed to update comparison scores: %w", err)
+	}
+	return nil
+}
+
+// applyFeedbackScores sets each variation's overall score to its automatic score blended with its
+// feedback score by feedbackWeight, keeping the automatic score as automatic_score, and picks the
+// best configuration again. Variations without feedback go back to their automatic score.
+func applyFeedbackScores(comparison *types.ComparisonResult, feedback []types.ConfigurationFeedback) {
+	byVariation := make(map[string]types.ConfigurationFeedback, len(feedback))
+	for _, configuration := range feedback {
+		byVariation[configuration.VariationName] = configuration
+	}
+
+	for name, raw := range comparison.ConfigurationScores {
+		scores, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		automatic, ok := numericScore(scores["automatic_score"])
+		if !ok {
+			if automatic, ok = numericScore(scores["overall_score"]); !ok {
+				continue
+			}
+		}
+
+		delete(scores, "automatic_score")
+		delete(scores, "feedback_score")
+		scores["overall_score"] = automatic
+		if configuration, ok := byVariation[name]; ok && configuration.Score != nil {
+			scores["automatic_score"] = automatic
+			scores["feedback_score"] = *configuration.Score
+			scores["overall_score"] = automatic*(1-feedbackWeight) + *configuration.Score*feedbackWeight
+		}
+	}
+
+	bestScore := -1.0
+	for i, configuration := range comparison.AllConfigurations {
+		scores, _ := comparison.ConfigurationScores[configuration.VariationName].(map[string]interface{})
+		if overall, ok := numericScore(scores["overall_score"]); ok && overall > bestScore {
+			bestScore = overall
+			comparison.BestConfigurationID = configuration.ID
+			comparison.BestConfiguration = &comparison.AllConfigurations[i]
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"gogent/internal/types"
+)
+
+// newFeedbackTestClient returns a store test client with a run of two configurations, each with
+// one response, a comparison preferring "cold" and the tables feedback is stored in
+func newFeedbackTestClient(t *testing.T) (*Client, string) {
+	client, _ := newStoreTestClient(t)
+	ctx := context.Background()
+
+	run, err := client.CreateExecutionRun(ctx, "user-1", "Capital cities", "", false)
+	if err != nil {
+		t.Fatalf("failed to create run: %v", err)
+	}
+
+	var configurations []types.APIConfiguration
+	for _, name := range []string{"cold", "hot"} {
+		configurations = append(configurations, types.APIConfiguration{ID: "config-" + name, ExecutionRunID: run.ID, VariationName: name})
+		for _, statement := range []struct {
+			query string
+			args  []interface{}
+		}{
+			{"INSERT INTO api_configurations (id, execution_run_id, variation_name) VALUES (?, ?, ?)", []interface{}{"config-" + name, run.ID, name}},
+			{"INSERT INTO api_requests (id, user_id, execution_run_id, configuration_id) VALUES (?, ?, ?, ?)", []interface{}{"request-" + name, "user-1", run.ID, "config-" + name}},
+			{"INSERT INTO api_responses (id, request_id) VALUES (?, ?)", []interface{}{"response-" + name, "request-" + name}},
+		} {
+			if _, err := client.db.Exec(statement.query, statement.args...); err != nil {
+				t.Fatalf("failed to store test response: %v", err)
+			}
+		}
+	}
+
+	err = client.StoreComparisonResult(ctx, "user-1", &types.ComparisonResult{
+		ID: "comparison-1", ExecutionRunID: run.ID, MetricName: "multi_metric", CreatedAt: time.Now(),
+		BestConfigurationID: "config-cold", BestConfiguration: &configurations[0], AllConfigurations: configurations,
+		ConfigurationScores: map[string]interface{}{
+			"cold": map[string]interface{}{"overall_score": 0.7},
+			"hot":  map[string]interface{}{"overall_score": 0.6},
+		}})
+	if err != nil {
+		t.Fatalf("failed to store comparison: %v", err)
+	}
+	return client, run.ID
+}
+
+func TestValidateFeedback(t *testing.T) {
+	rating := func(value int) *int { return &value }
+	for _, feedback := range []types.ResponseFeedback{
+		{Thumbs: types.FeedbackThumbsUp},
+		{Rating: rating(5)},
+		{Comment: "Too long-winded"},
+	} {
+		if err := ValidateFeedback(&feedback); err != nil {
+			t.Errorf("expected %+v to be valid, got %v", feedback, err)
+		}
+	}
+	for _, feedback := range []types.ResponseFeedback{
+		{},
+		{Thumbs: "sideways"},
+		{Rating: rating(0)},
+		{Rating: rating(6)},
+	} {
+		if err := ValidateFeedback(&feedback); !errors.Is(err, ErrInvalidFeedback) {
+			t.Errorf("expected %+v to be rejected, got %v", feedback, err)
+		}
+	}
+}
+
+func TestResponseFeedback(t *testing.T) {
+	client, runID := newFeedbackTestClient(t)
+	ctx := context.Background()
+
+	// Thumbs down on cold, and thumbs up with a 4 out of 5 on hot
+	if _, err := client.RecordResponseFeedback(ctx, "user-1", runID, &types.ResponseFeedback{ResponseID: "response-cold", Thumbs: types.FeedbackThumbsDown}); err != nil {
+		t.Fatalf("failed to record feedback: %v", err)
+	}
+	four := 4
+	stored, err := client.RecordResponseFeedback(ctx, "user-1", runID, &types.ResponseFeedback{
+		ResponseID: "response-hot", Thumbs: types.FeedbackThumbsUp, Rating: &four, Comment: "Clear and complete"})
+	if err != nil {
+		t.Fatalf("failed to record feedback: %v", err)
+	}
+	if stored.ConfigurationID != "config-hot" || stored.ExecutionRunID != runID {
+		t.Errorf("expected the feedback to be linked to its configuration, got %+v", stored)
+	}
+
+	runFeedback, err := client.GetRunFeedback(ctx, "user-1", runID)
+	if err != nil {
+		t.Fatalf("failed to get feedback: %v", err)
+	}
+	if len(runFeedback.Feedback) != 2 || len(runFeedback.Configurations) != 2 {
+		t.Fatalf("expected feedback on both configurations, got %+v", runFeedback)
+	}
+	cold, hot := runFeedback.Configurations[0], runFeedback.Configurations[1]
+	if cold.VariationName != "cold" || cold.ThumbsDown != 1 || cold.Ratings != 0 || *cold.Score != 0 {
+		t.Errorf("unexpected cold feedback: %+v", cold)
+	}
+	if hot.ThumbsUp != 1 || hot.Ratings != 1 || *hot.AverageRating != 4 || hot.Comments != 1 || *hot.Score != 0.875 {
+		t.Errorf("unexpected hot feedback: %+v", hot)
+	}
+
+	// The feedback turns the comparison around
+	comparison, err := client.GetComparisonResult(ctx, "user-1", runID)
+	if err != nil {
+		t.Fatalf("failed to get comparison: %v", err)
+	}
+	hotScores := comparison.ConfigurationScores["hot"].(map[string]interface{})
+	if math.Abs(hotScores["overall_score"].(float64)-0.7375) > 1e-9 || hotScores["automatic_score"] != 0.6 || hotScores["feedback_score"] != 0.875 {
+		t.Errorf("expected hot's overall score blended with its feedback, got %+v", hotScores)
+	}
+	if comparison.BestConfigurationID != "config-hot" {
+		t.Errorf("expected hot to become the best configuration, got %s", comparison.BestConfigurationID)
+	}
+
+	// Removing the feedback restores the automatic scores
+	if err := client.DeleteResponseFeedback(ctx, "user-1", runID, "response-hot"); err != nil {
+		t.Fatalf("failed to delete feedback: %v", err)
+	}
+	if err := client.DeleteResponseFeedback(ctx, "user-1", runID, "response-cold"); err != nil {
+		t.Fatalf("failed to delete feedback: %v", err)
+	}
+	comparison, _ = client.GetComparisonResult(ctx, "user-1", runID)
+	hotScores = comparison.ConfigurationScores["hot"].(map[string]interface{})
+	if hotScores["overall_score"] != 0.6 || hotScores["automatic_score"] != nil || comparison.BestConfigurationID != "config-cold" {
+		t.Errorf("expected the automatic scores back, got %+v, best %s", hotScores, comparison.BestConfigurationID)
+	}
+	if err := client.DeleteResponseFeedback(ctx, "user-1", runID, "response-hot"); !errors.Is(err, ErrFeedbackNotFound) {
+		t.Errorf("expected ErrFeedbackNotFound, got %v", err)
+	}
+}
+
+func TestResponseFeedbackNotFound(t *testing.T) {
+	client, runID := newFeedbackTestClient(t)
+	ctx := context.Background()
+	feedback := &types.ResponseFeedback{ResponseID: "response-cold", Thumbs: types.FeedbackThumbsUp}
+
+	if _, err := client.RecordResponseFeedback(ctx, "user-2", runID, feedback); !errors.Is(err, ErrResponseNotFound) {
+		t.Errorf("expected another user's response to be hidden, got %v", err)
+	}
+	if _, err := client.RecordResponseFeedback(ctx, "user-1", "run-missing", feedback); !errors.Is(err, ErrResponseNotFound) {
+		t.Errorf("expected a response of another run to be rejected, got %v", err)
+	}
+	if _, err := client.GetRunFeedback(ctx, "user-2", runID); !errors.Is(err, ErrExecutionRunNotFound) {
+		t.Errorf("expected another user's run to be hidden, got %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package gogent
+
+import (
+	"context"
+	"sync"
+
+	"gogent/internal/types"
+)
+
+const (
+	// toolCallRateMetric is 1 when a variation called a tool and 0 when it did not, so its mean over
+	// repetitions is how often the configuration calls one
+	toolCallRateMetric = "tool_call_rate"
+	// argumentValidityMetric is the share of a variation's calls whose arguments matched the schema
+	argumentValidityMetric = "argument_validity"
+)
+
+// functionCallLog collects the function calls a variation makes while its model call runs, so they
+// can be stored with its response and returned with its result
+type functionCallLog struct {
+	mu    sync.Mutex
+	calls []types.FunctionCall
+}
+
+// functionCallLogKey is the context key a functionCallLog is stored under
+type functionCallLogKey struct{}
+
+// withFunctionCallLog returns a context that collects the function calls made on it
+func withFunctionCallLog(ctx context.Context) (context.Context, *functionCallLog) {
+	calls := &functionCallLog{}
+	return context.WithValue(ctx, functionCallLogKey{}, calls), calls
+}
+
+// recordFunctionCall adds a call to the log ctx carries, reporting false when it carries none
+func recordFunctionCall(ctx context.Context, call *types.FunctionCall) bool {
+	calls, ok := ctx.Value(functionCallLogKey{}).(*functionCallLog)
+	if !ok {
+		return false
+	}
+	calls.mu.Lock()
+	calls.calls = append(calls.calls, *call)
+	calls.mu.Unlock()
+	return true
+}
+
+// list returns the calls collected so far in call order
+func (l *functionCallLog) list() []types.FunctionCall {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]types.FunctionCall(nil), l.calls...)
+}
+
+// functionCallMetrics summarizes a variation's tool use for its comparison scores: whether it called
+// a tool, which functions it called, the model round trips that took, the share of calls whose
+// arguments matched the function's parameters and how long the functions ran. Variations that were
+// offered no tools get no metrics.
+func functionCallMetrics(r types.VariationResult) map[string]interface{} {
+	if len(r.Configuration.Tools) == 0 {
+		return nil
+	}
+
+	calls := r.FunctionCalls
+	if len(calls) == 0 && variationCalledTool(r) {
+		// Results loaded without their calls still carry the one in the response
+		name, _ := r.Response.FunctionCallResponse["function_name"].(string)
+		args, _ := r.Response.FunctionCallResponse["arguments"].(map[string]interface{})
+		calls = []types.FunctionCall{{FunctionName: name, FunctionArgs: args}}
+		if r.Response.Latency != nil {
+			calls[0].ExecutionTimeMs = int32(r.Response.Latency.FunctionMs)
+		}
+	}
+
+	metrics := map[string]interface{}{
+		toolCallRateMetric:     0.0,
+		"function_round_trips": len(calls) + 1,
+	}
+	if len(calls) == 0 {
+		return metrics
+	}
+
+	parameters := make(map[string]map[string]interface{}, len(r.Configuration.Tools))
+	for _, tool := range r.Configuration.Tools {
+		parameters[tool.Name] = tool.Parameters
+	}
+	var names []string
+	var valid, failed int
+	var latencyMs int64
+	for _, call := range calls {
+		names = append(names, call.FunctionName)
+		if schema, ok := parameters[call.FunctionName]; ok && ValidateFunctionArguments(schema, call.FunctionArgs) == nil {
+			valid++
+		}
+		if call.ExecutionStatus == "error" {
+			failed++
+		}
+		latencyMs += int64(call.ExecutionTimeMs)
+	}
+	metrics[toolCallRateMetric] = 1.0
+	metrics["functions_called"] = names
+	metrics[argumentValidityMetric] = float64(valid) / float64(len(calls))
+	metrics["function_errors"] = failed
+	metrics["function_latency_ms"] = latencyMs
+	return metrics
+}
@@ -0,0 +1,67 @@
+package gogent
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"gogent/internal/types"
+)
+
+func TestFunctionCallMetrics(t *testing.T) {
+	weatherTool := types.Tool{Name: "get_weather", Parameters: map[string]interface{}{
+		"type":       "object",
+		"required":   []interface{}{"location"},
+		"properties": map[string]interface{}{"location": map[string]interface{}{"type": "string"}},
+	}}
+	tools := types.APIConfiguration{Tools: []types.Tool{weatherTool}}
+
+	if metrics := functionCallMetrics(types.VariationResult{}); metrics != nil {
+		t.Errorf("expected no metrics without tools, got %v", metrics)
+	}
+
+	metrics := functionCallMetrics(types.VariationResult{Configuration: tools})
+	if metrics[toolCallRateMetric] != 0.0 || metrics["function_round_trips"] != 1 || metrics["functions_called"] != nil {
+		t.Errorf("expected a single round trip without calls, got %v", metrics)
+	}
+
+	metrics = functionCallMetrics(types.VariationResult{
+		Configuration: tools,
+		FunctionCalls: []types.FunctionCall{
+			{FunctionName: "get_weather", FunctionArgs: map[string]interface{}{"location": "Paris"}, ExecutionTimeMs: 40},
+			{FunctionName: "get_weather", FunctionArgs: map[string]interface{}{}, ExecutionTimeMs: 10, ExecutionStatus: "error"},
+			{FunctionName: "get_stock_quote", ExecutionTimeMs: 5},
+			{FunctionName: "get_weather", FunctionArgs: map[string]interface{}{"location": "Oslo"}, ExecutionTimeMs: 5},
+		},
+	})
+	if metrics[toolCallRateMetric] != 1.0 || metrics["function_round_trips"] != 5 || metrics["function_errors"] != 1 ||
+		metrics["function_latency_ms"] != int64(60) || metrics[argumentValidityMetric] != 0.5 {
+		t.Errorf("expected metrics for four calls, half with valid arguments, got %v", metrics)
+	}
+	if names := metrics["functions_called"]; !reflect.DeepEqual(names, []string{"get_weather", "get_weather", "get_stock_quote", "get_weather"}) {
+		t.Errorf("expected the functions in call order, got %v", names)
+	}
+
+	// Results without their calls fall back to the call in the response
+	metrics = functionCallMetrics(types.VariationResult{
+		Configuration: tools,
+		Response: types.APIResponse{
+			FunctionCallResponse: map[string]interface{}{"function_name": "get_weather", "arguments": map[string]interface{}{"location": "Rome"}},
+			Latency:              &types.LatencyBreakdown{FunctionMs: 12},
+		},
+	})
+	if metrics[argumentValidityMetric] != 1.0 || metrics["function_latency_ms"] != int64(12) || metrics["function_round_trips"] != 2 {
+		t.Errorf("expected the response's call to be measured, got %v", metrics)
+	}
+}
+
+func TestFunctionCallLog(t *testing.T) {
+	recordFunctionCall(context.Background(), &types.FunctionCall{FunctionName: "ignored"})
+
+	ctx, calls := withFunctionCallLog(context.Background())
+	recordFunctionCall(ctx, &types.FunctionCall{FunctionName: "get_weather"})
+	recordFunctionCall(ctx, &types.FunctionCall{FunctionName: "web_search"})
+	if got := calls.list(); len(got) != 2 || got[0].FunctionName != "get_weather" || got[1].FunctionName != "web_search" {
+		t.Errorf("expected both calls in order, got %+v", got)
+	}
+}
@@ -0,0 +1,200 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"gogent/internal/types"
+)
+
+// ErrFunctionDomainNotAllowed is returned when a function endpoint is outside its allowed domains
+var ErrFunctionDomainNotAllowed = errors.New("endpoint domain not allowed")
+
+// ErrFunctionAddressNotAllowed is returned when a function endpoint resolves to a loopback,
+// link-local, private or unspecified address
+var ErrFunctionAddressNotAllowed = errors.New("endpoint address not allowed")
+
+// ErrFunctionResponseTooLarge is returned when an endpoint's response exceeds the function's size limit
+var ErrFunctionResponseTooLarge = errors.New("function response too large")
+
+const (
+	// defaultFunctionTimeout bounds a function call when its definition sets no timeout
+	defaultFunctionTimeout = 10 * time.Second
+	// maxFunctionTimeout is the longest timeout a definition may set
+	maxFunctionTimeout = 60 * time.Second
+	// defaultFunctionResponseBytes caps an endpoint's response when its definition sets no limit
+	defaultFunctionResponseBytes = 256 << 10
+	// maxFunctionResponseBytes is the largest response limit a definition may set
+	maxFunctionResponseBytes = 1 << 20
+	// maxFunctionRedirects is how many redirects an endpoint call follows
+	maxFunctionRedirects = 5
+)
+
+// validateFunctionSandbox checks a definition's limits and normalizes its allowed domains
+func validateFunctionSandbox(function *types.FunctionDefinition) error {
+	if function.TimeoutMs < 0 || time.Duration(function.TimeoutMs)*time.Millisecond > maxFunctionTimeout {
+		return fmt.Errorf("timeout must be between 0 and %d ms", maxFunctionTimeout.Milliseconds())
+	}
+	if function.MaxResponseBytes < 0 || function.MaxResponseBytes > maxFunctionResponseBytes {
+		return fmt.Errorf("max response size must be between 0 and %d bytes", maxFunctionResponseBytes)
+	}
+
+	domains := make([]string, 0, len(function.AllowedDomains))
+	for _, domain := range function.AllowedDomains {
+		domain = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), "*.")
+		if domain == "" {
+			continue
+		}
+		if strings.ContainsAny(domain, "/:@ ") {
+			return fmt.Errorf("invalid allowed domain %q: use a host name such as api.example.com", domain)
+		}
+		domains = append(domains, domain)
+	}
+	function.AllowedDomains = domains
+
+	if function.EndpointURL != "" && len(domains) > 0 {
+		endpoint, err := url.Parse(function.EndpointURL)
+		if err != nil || !domainAllowed(endpoint.Hostname(), domains) {
+			return fmt.Errorf("endpoint URL %s is not in the allowed domains", function.EndpointURL)
+		}
+	}
+	return nil
+}
+
+// functionTimeout returns how long a call to the function may run. A requested timeout can
+// shorten the definition's timeout but not extend it.
+func functionTimeout(function *types.FunctionDefinition, requested time.Duration) time.Duration {
+	timeout := defaultFunctionTimeout
+	if function.TimeoutMs > 0 {
+		timeout = time.Duration(function.TimeoutMs) * time.Millisecond
+	}
+	if requested > 0 && requested < timeout {
+		timeout = requested
+	}
+	if timeout > maxFunctionTimeout {
+		timeout = maxFunctionTimeout
+	}
+	return timeout
+}
+
+// functionResponseLimit returns the largest response body accepted from the function's endpoint
+func functionResponseLimit(function *types.FunctionDefinition) int64 {
+	if function.MaxResponseBytes > 0 {
+		return int64(function.MaxResponseBytes)
+	}
+	return defaultFunctionResponseBytes
+}
+
+// domainAllowed reports whether host is one of the domains or a subdomain of one. An empty
+// domain list allows every host.
+func domainAllowed(host string, domains []string) bool {
+	if len(domains) == 0 {
+		return true
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, domain := range domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// Transports function endpoints are called through: publicFunctionTransport refuses internal
+// addresses, privateFunctionTransport is for servers configured to allow them
+var (
+	publicFunctionTransport  = newFunctionTransport(false)
+	privateFunctionTransport = newFunctionTransport(true)
+)
+
+// functionTransport returns the transport the client calls function endpoints through
+func (c *Client) functionTransport() http.RoundTripper {
+	if c.config != nil && c.config.AllowPrivateFunctionEndpoints {
+		return privateFunctionTransport
+	}
+	return publicFunctionTransport
+}
+
+// newFunctionTransport returns a transport that dials endpoints directly, bypassing any proxy.
+// Unless allowPrivate is set, its dialer checks each address it connects to after DNS resolution,
+// so neither a host name nor a redirect can reach an internal address.
+func newFunctionTransport(allowPrivate bool) *http.Transport {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if !allowPrivate {
+		dialer.Control = refuseInternalAddress
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return transport
+}
+
+// refuseInternalAddress is a dialer Control hook failing connections to internal addresses
+func refuseInternalAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrFunctionAddressNotAllowed, address)
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil || internalAddress(ip) {
+		return fmt.Errorf("%w: %s", ErrFunctionAddressNotAllowed, host)
+	}
+	return nil
+}
+
+// internalAddress reports whether ip is a loopback, link-local, private or unspecified address,
+// IPv4-mapped IPv6 addresses included
+func internalAddress(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsPrivate() || ip.IsUnspecified()
+}
+
+// sandboxHTTPClient returns an HTTP client sending requests through transport that only follows
+// redirects within the function's allowed domains
+func sandboxHTTPClient(function *types.FunctionDefinition, transport http.RoundTripper) *http.Client {
+	return &http.Client{
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxFunctionRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxFunctionRedirects)
+			}
+			if !domainAllowed(req.URL.Hostname(), function.AllowedDomains) {
+				return fmt.Errorf("%w: redirect to %s", ErrFunctionDomainNotAllowed, req.URL.Hostname())
+			}
+			return nil
+		},
+	}
+}
+
+// readLimitedBody reads a response body, failing instead of truncating when it exceeds limit
+func readLimitedBody(body io.Reader, limit int64) ([]byte, error) {
+	raw, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if int64(len(raw)) > limit {
+		return nil, fmt.Errorf("%w: limit is %d bytes", ErrFunctionResponseTooLarge, limit)
+	}
+	return raw, nil
+}
+
+// sandboxCallError explains endpoint call failures caused by the sandbox
+func sandboxCallError(ctx context.Context, err error) error {
+	if errors.Is(err, ErrFunctionDomainNotAllowed) || errors.Is(err, ErrFunctionAddressNotAllowed) {
+		return err
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("endpoint did not respond within the function's timeout: %w", err)
+	}
+	return fmt.Errorf("failed to call endpoint: %w", err)
+}
@@ -0,0 +1,123 @@
+package gogent
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"gogent/internal/types"
+)
+
+// ErrFunctionTemplateNotFound is returned for a function template that does not exist
+var ErrFunctionTemplateNotFound = errors.New("function template not found")
+
+// functionTemplateColumns is the column list read by scanFunctionTemplate
+const functionTemplateColumns = `
+	id, name, display_name, description, category, parameters_schema, example_arguments,
+	mock_response, endpoint_url, http_method, headers, required_api_keys, created_at`
+
+// scanFunctionTemplate reads a row selected with functionTemplateColumns
+func scanFunctionTemplate(row rowScanner) (*types.FunctionTemplate, error) {
+	var template types.FunctionTemplate
+	var endpointURL sql.NullString
+	var schema, examples, mockResponse, headers, requiredKeys sql.NullString
+
+	err := row.Scan(&template.ID, &template.Name, &template.DisplayName, &template.Description, &template.Category,
+		&schema, &examples, &mockResponse, &endpointURL, &template.HttpMethod, &headers, &requiredKeys, &template.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	template.EndpointURL = endpointURL.String
+
+	fields := []struct {
+		name  string
+		value sql.NullString
+		dest  interface{}
+	}{
+		{"parameters schema", schema, &template.ParametersSchema},
+		{"example arguments", examples, &template.ExampleArguments},
+		{"mock response", mockResponse, &template.MockResponse},
+		{"headers", headers, &template.Headers},
+		{"required API keys", requiredKeys, &template.RequiredApiKeys},
+	}
+	for _, field := range fields {
+		if err := types.FromJSON(field.value.String, field.dest); err != nil {
+			return nil, fmt.Errorf("failed to parse %s for template %s: %w", field.name, template.Name, err)
+		}
+	}
+	return &template, nil
+}
+
+// ListFunctionTemplates returns every function template by category and name
+func (c *Client) ListFunctionTemplates(ctx context.Context) ([]*types.FunctionTemplate, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	rows, err := c.db.QueryContext(ctx,
+		"SELECT "+functionTemplateColumns+" FROM function_templates ORDER BY category ASC, display_name ASC")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list function templates: %w", err)
+	}
+	defer rows.Close()
+
+	templates := []*types.FunctionTemplate{}
+	for rows.Next() {
+		template, err := scanFunctionTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan function template: %w", err)
+		}
+		templates = append(templates, template)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate function templates: %w", err)
+	}
+	return templates, nil
+}
+
+// GetFunctionTemplate loads a function template
+func (c *Client) GetFunctionTemplate(ctx context.Context, id string) (*types.FunctionTemplate, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	row := c.db.QueryRowContext(ctx, "SELECT "+functionTemplateColumns+" FROM function_templates WHERE id = ?", id)
+	template, err := scanFunctionTemplate(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrFunctionTemplateNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get function template: %w", err)
+	}
+	return template, nil
+}
+
+// FunctionFromTemplate returns the function definition a template describes, named name or, when
+// name is empty, the template's name. It keeps the template's mock response, so the copy can be
+// tried with useMockResponse before its API keys are set up.
+func FunctionFromTemplate(template *types.FunctionTemplate, name string) *types.FunctionDefinition {
+	if name == "" {
+		name = template.Name
+	}
+	return &types.FunctionDefinition{
+		Name:             name,
+		DisplayName:      template.DisplayName,
+		Description:      template.Description,
+		ParametersSchema: template.ParametersSchema,
+		MockResponse:     template.MockResponse,
+		EndpointURL:      template.EndpointURL,
+		HttpMethod:       template.HttpMethod,
+		Headers:          template.Headers,
+		RequiredApiKeys:  template.RequiredApiKeys,
+		IsActive:         true,
+	}
+}
+
+// CopyFunctionTemplate creates a function definition owned by the user from a template. An empty
+// name keeps the template's; pass another when the user already has a function with that name.
+func (c *Client) CopyFunctionTemplate(ctx context.Context, userID, templateID, name string) (*types.FunctionDefinition, error) {
+	template, err := c.GetFunctionTemplate(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	return c.CreateFunctionDefinition(ctx, userID, FunctionFromTemplate(template, name))
+}
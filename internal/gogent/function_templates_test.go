@@ -0,0 +1,72 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// seedFunctionTemplates fills function_templates with the templates the migration seeds
+func seedFunctionTemplates(t *testing.T, client *Client) {
+	migration, err := os.ReadFile("../../migrations/000047_add_function_templates.up.sql")
+	if err != nil {
+		t.Fatalf("failed to read migration: %v", err)
+	}
+	_, seed, ok := strings.Cut(string(migration), "INSERT INTO")
+	if !ok {
+		t.Fatal("expected the migration to seed templates")
+	}
+
+	if _, err := client.db.Exec("INSERT INTO" + seed); err != nil {
+		t.Fatalf("failed to seed function templates: %v", err)
+	}
+}
+
+func TestListFunctionTemplates(t *testing.T) {
+	client := newFunctionTestClient(t)
+	seedFunctionTemplates(t, client)
+
+	templates, err := client.ListFunctionTemplates(context.Background())
+	if err != nil {
+		t.Fatalf("failed to list function templates: %v", err)
+	}
+	if len(templates) != 4 || templates[0].Category != "finance" {
+		t.Fatalf("expected the four seeded templates by category, got %d", len(templates))
+	}
+	for _, template := range templates {
+		if len(template.ParametersSchema) == 0 || len(template.ExampleArguments) == 0 || len(template.MockResponse) == 0 {
+			t.Errorf("expected %s to include a schema, example arguments and a mock response, got %+v", template.Name, template)
+		}
+		if err := ValidateFunctionDefinition(FunctionFromTemplate(template, "")); err != nil {
+			t.Errorf("expected %s to describe a valid function, got %v", template.Name, err)
+		}
+	}
+}
+
+func TestCopyFunctionTemplate(t *testing.T) {
+	client := newFunctionTestClient(t)
+	seedFunctionTemplates(t, client)
+	ctx := context.Background()
+
+	function, err := client.CopyFunctionTemplate(ctx, "user-1", "template-stock-quote", "")
+	if err != nil {
+		t.Fatalf("failed to copy template: %v", err)
+	}
+	if function.UserID != "user-1" || function.Name != "get_stock_quote" || function.MockResponse["symbol"] != "AAPL" ||
+		len(function.RequiredApiKeys) != 1 {
+		t.Errorf("expected the user's own copy of the template, got %+v", function)
+	}
+
+	if _, err := client.CopyFunctionTemplate(ctx, "user-1", "template-stock-quote", ""); !errors.Is(err, ErrFunctionNameTaken) {
+		t.Errorf("expected a second copy under the same name to be rejected, got %v", err)
+	}
+	renamed, err := client.CopyFunctionTemplate(ctx, "user-1", "template-stock-quote", "get_index_quote")
+	if err != nil || renamed.Name != "get_index_quote" || renamed.ID == function.ID {
+		t.Errorf("expected a second, renamed copy, got %+v, %v", renamed, err)
+	}
+	if _, err := client.CopyFunctionTemplate(ctx, "user-1", "template-missing", ""); !errors.Is(err, ErrFunctionTemplateNotFound) {
+		t.Errorf("expected a missing template not to be found, got %v", err)
+	}
+}
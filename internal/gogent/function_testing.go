@@ -0,0 +1,454 @@
+package gogent
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gogent/internal/types"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidFunctionArguments is returned when test arguments do not match a function's parameters schema
+var ErrInvalidFunctionArguments = errors.New("invalid function arguments")
+
+// endpointPlaceholder matches a {name} path parameter in an endpoint URL
+var endpointPlaceholder = regexp.MustCompile(`\{[a-zA-Z_][a-zA-Z0-9_.-]*\}`)
+
+// builtinFunctions are executed in-process when a definition has no endpoint URL
+var builtinFunctions = map[string]bool{
+	"get_current_weather": true,
+	"query_graph":         true,
+	"web_search":          true,
+	"query_sql":           true,
+}
+
+// TestFunctionDefinition validates the arguments against the function's parameters schema, runs
+// the function (its mock response, its HTTP endpoint, or a built-in handler) and records the call
+// in function_calls flagged as a test. The timeout can only shorten the function's own timeout.
+// Endpoint failures are reported in the result, not as errors.
+func (c *Client) TestFunctionDefinition(ctx context.Context, userID, functionID string, args map[string]interface{}, useMockData bool, timeout time.Duration) (*types.FunctionTestResult, error) {
+	function, err := c.GetFunctionDefinition(ctx, userID, functionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+	if err := ValidateFunctionArguments(function.ParametersSchema, args); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFunctionArguments, err)
+	}
+
+	execCtx, cancel := context.WithTimeout(ctx, functionTimeout(function, timeout))
+	defer cancel()
+
+	result := &types.FunctionTestResult{UsedMockData: useMockData}
+	startTime := time.Now()
+
+	switch {
+	case useMockData:
+		result.Response = mockFunctionResponse(function.MockResponse)
+	case function.EndpointURL != "":
+		result.Response, result.StatusCode, err = callFunctionEndpoint(execCtx, c.guardedTransport(c.functionTransport()), function, args)
+	case builtinFunctions[function.Name]:
+		result.Response, err = c.callFunction(execCtx, function.Name, args)
+	default:
+		err = fmt.Errorf("function %s has no endpoint URL to call", function.Name)
+	}
+
+	result.ExecutionTimeMs = int32(time.Since(startTime).Milliseconds())
+	result.Success = err == nil
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	call := &types.FunctionCall{
+		ID:                   uuid.New().String(),
+		FunctionName:         function.Name,
+		FunctionArgs:         args,
+		FunctionResponse:     result.Response,
+		ExecutionStatus:      "success",
+		ExecutionTimeMs:      result.ExecutionTimeMs,
+		ErrorDetails:         result.Error,
+		UserID:               userID,
+		FunctionDefinitionID: function.ID,
+		IsTest:               true,
+		UsedMockData:         useMockData,
+	}
+	if !result.Success {
+		call.ExecutionStatus = "error"
+	}
+	if err := c.recordTestFunctionCall(ctx, call); err != nil {
+		return nil, err
+	}
+	result.FunctionCallID = call.ID
+
+	log.Printf("🧪 Function test %s for %s in %dms", call.ExecutionStatus, function.Name, result.ExecutionTimeMs)
+	return result, nil
+}
+
+// mockFunctionResponse returns a definition's mock response, or a placeholder when it has none
+func mockFunctionResponse(mockResponse map[string]interface{}) map[string]interface{} {
+	if len(mockResponse) > 0 {
+		return mockResponse
+	}
+	return map[string]interface{}{
+		"status": "mock_success",
+		"data":   "Mock response generated",
+	}
+}
+
+// executionFunction is how an execution calls one of its configured functions
+type executionFunction struct {
+	useMock      bool
+	mockResponse map[string]interface{} // The definition's mock response, set in mock mode
+	timeout      time.Duration          // Limit on a live call, from the definition's timeout
+}
+
+// executionFunctionConfig returns how an execution calls the named function, or nil when the
+// function is not configured for the execution
+func (c *Client) executionFunctionConfig(ctx context.Context, executionRunID, functionName string) (*executionFunction, error) {
+	var useMock sql.NullBool
+	var mockResponseJSON sql.NullString
+	var timeoutMs sql.NullInt32
+	err := c.db.QueryRowContext(ctx, `
+		SELECT efc.use_mock_response, fd.mock_response, fd.timeout_ms
+		FROM execution_function_configs efc
+		JOIN function_definitions fd ON efc.function_definition_id = fd.id
+		WHERE efc.execution_run_id = ? AND fd.name = ?
+		LIMIT 1
+	`, executionRunID, functionName).Scan(&useMock, &mockResponseJSON, &timeoutMs)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get execution function config: %w", err)
+	}
+
+	definition := &types.FunctionDefinition{TimeoutMs: timeoutMs.Int32}
+	function := &executionFunction{useMock: useMock.Bool, timeout: functionTimeout(definition, 0)}
+	if !function.useMock {
+		return function, nil
+	}
+
+	var mockResponse map[string]interface{}
+	if mockResponseJSON.Valid && mockResponseJSON.String != "" {
+		if err := types.FromJSON(mockResponseJSON.String, &mockResponse); err != nil {
+			return nil, fmt.Errorf("failed to parse mock response: %w", err)
+		}
+	}
+	function.mockResponse = mockFunctionResponse(mockResponse)
+	return function, nil
+}
+
+// recordTestFunctionCall stores a test call, which has no API request, in function_calls
+func (c *Client) recordTestFunctionCall(ctx context.Context, call *types.FunctionCall) error {
+	argsJSON, err := types.ToJSON(call.FunctionArgs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal function arguments: %w", err)
+	}
+	var responseJSON interface{}
+	if call.FunctionResponse != nil {
+		encoded, err := types.ToJSON(call.FunctionResponse)
+		if err != nil {
+			return fmt.Errorf("failed to marshal function response: %w", err)
+		}
+		responseJSON = encoded
+	}
+
+	_, err = c.db.ExecContext(ctx, `
+		INSERT INTO function_calls (
+			id, request_id, function_name, function_arguments, function_response,
+			execution_status, execution_time_ms, error_details,
+			user_id, function_definition_id, is_test, used_mock_data
+		) VALUES (?, NULL, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, call.ID, call.FunctionName, argsJSON, responseJSON, call.ExecutionStatus, call.ExecutionTimeMs,
+		nullableString(call.ErrorDetails), call.UserID, call.FunctionDefinitionID, call.IsTest, call.UsedMockData)
+	if err != nil {
+		return fmt.Errorf("failed to record function test call: %w", err)
+	}
+	return nil
+}
+
+// callFunctionEndpoint calls a function's HTTP endpoint through transport with the definition's
+// headers and auth, enforcing its allowed domains and response size limit. GET and DELETE send the
+// arguments as query parameters; other methods send them as a JSON body.
+func callFunctionEndpoint(ctx context.Context, transport http.RoundTripper, function *types.FunctionDefinition, args map[string]interface{}) (map[string]interface{}, int, error) {
+	rawURL, args, err := expandEndpointPath(function.EndpointURL, args)
+	if err != nil {
+		return nil, 0, err
+	}
+	endpoint, err := url.Parse(rawURL)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, 0, fmt.Errorf("invalid endpoint URL: %s", function.EndpointURL)
+	}
+	if !domainAllowed(endpoint.Hostname(), function.AllowedDomains) {
+		return nil, 0, fmt.Errorf("%w: %s", ErrFunctionDomainNotAllowed, endpoint.Hostname())
+	}
+
+	method := function.HttpMethod
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	var body io.Reader
+	if method == http.MethodGet || method == http.MethodDelete {
+		query := endpoint.Query()
+		for key, value := range args {
+			query.Set(key, queryValue(value))
+		}
+		endpoint.RawQuery = query.Encode()
+	} else {
+		payload, err := json.Marshal(args)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to marshal arguments: %w", err)
+		}
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "GoGent/1.0")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range function.Headers {
+		req.Header.Set(key, fmt.Sprint(value))
+	}
+	if err := applyFunctionAuth(req, function.AuthConfig); err != nil {
+		return nil, 0, err
+	}
+
+	resp, err := sandboxHTTPClient(function, transport).Do(req)
+	if err != nil {
+		return nil, 0, sandboxCallError(ctx, err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := readLimitedBody(resp.Body, functionResponseLimit(function))
+	if err != nil {
+		return nil, resp.StatusCode, sandboxCallError(ctx, err)
+	}
+
+	response := decodeFunctionResponse(raw)
+	if resp.StatusCode >= 400 {
+		return response, resp.StatusCode, fmt.Errorf("endpoint returned status %d", resp.StatusCode)
+	}
+	return response, resp.StatusCode, nil
+}
+
+// expandEndpointPath fills the {name} placeholders of an endpoint URL, such as those of functions
+// imported from OpenAPI, with the arguments of the same name. The arguments left are returned for
+// the query string or body.
+func expandEndpointPath(rawURL string, args map[string]interface{}) (string, map[string]interface{}, error) {
+	if !strings.Contains(rawURL, "{") {
+		return rawURL, args, nil
+	}
+	remaining := make(map[string]interface{}, len(args))
+	for key, value := range args {
+		remaining[key] = value
+	}
+
+	var missing []string
+	expanded := endpointPlaceholder.ReplaceAllStringFunc(rawURL, func(placeholder string) string {
+		name := placeholder[1 : len(placeholder)-1]
+		value, ok := remaining[name]
+		if !ok {
+			missing = append(missing, name)
+			return placeholder
+		}
+		delete(remaining, name)
+		return url.PathEscape(queryValue(value))
+	})
+	if len(missing) > 0 {
+		return "", nil, fmt.Errorf("missing path parameters: %s", strings.Join(missing, ", "))
+	}
+	return expanded, remaining, nil
+}
+
+// applyFunctionAuth adds credentials from a definition's auth config:
+// {"type": "bearer", "token"}, {"type": "api_key", "value", "header" or "queryParam"},
+// or {"type": "basic", "username", "password"}
+func applyFunctionAuth(req *http.Request, authConfig map[string]interface{}) error {
+	if len(authConfig) == 0 {
+		return nil
+	}
+	field := func(name string) string {
+		value, _ := authConfig[name].(string)
+		return value
+	}
+
+	switch authType := field("type"); authType {
+	case "", "none":
+	case "bearer":
+		req.Header.Set("Authorization", "Bearer "+field("token"))
+	case "api_key":
+		if param := field("queryParam"); param != "" {
+			query := req.URL.Query()
+			query.Set(param, field("value"))
+			req.URL.RawQuery = query.Encode()
+			break
+		}
+		header := field("header")
+		if header == "" {
+			header = "X-API-Key"
+		}
+		req.Header.Set(header, field("value"))
+	case "basic":
+		req.SetBasicAuth(field("username"), field("password"))
+	default:
+		return fmt.Errorf("unsupported auth type: %s", authType)
+	}
+	return nil
+}
+
+// decodeFunctionResponse returns a JSON object response as-is, wrapping any other body
+func decodeFunctionResponse(raw []byte) map[string]interface{} {
+	var object map[string]interface{}
+	if err := json.Unmarshal(raw, &object); err == nil && object != nil {
+		return object
+	}
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err == nil {
+		return map[string]interface{}{"data": value}
+	}
+	return map[string]interface{}{"body": string(raw)}
+}
+
+// queryValue formats an argument for a query string, JSON-encoding objects and arrays
+func queryValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]interface{}, []interface{}:
+		encoded, _ := json.Marshal(v)
+		return string(encoded)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// ValidateFunctionArguments checks arguments against a parameters schema. It supports the JSON
+// Schema subset used for function declarations: type, properties, required, enum, items and
+// additionalProperties: false.
+func ValidateFunctionArguments(schema map[string]interface{}, args map[string]interface{}) error {
+	if len(schema) == 0 {
+		return nil
+	}
+	return validateSchemaValue(schema, args, "arguments")
+}
+
+// validateSchemaValue validates one value against its schema, naming it by path in errors
+func validateSchemaValue(schema map[string]interface{}, value interface{}, path string) error {
+	if schemaType, ok := schema["type"].(string); ok && !matchesSchemaType(schemaType, value) {
+		return fmt.Errorf("%s must be of type %s", path, schemaType)
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, allowed := range enum {
+			if fmt.Sprint(allowed) == fmt.Sprint(value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s must be one of %v", path, enum)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, name := range required {
+				key := fmt.Sprint(name)
+				if _, exists := v[key]; !exists {
+					return fmt.Errorf("%s.%s is required", path, key)
+				}
+			}
+		}
+
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			propertySchema, known := properties[key].(map[string]interface{})
+			if !known {
+				if additional, ok := schema["additionalProperties"].(bool); ok && !additional {
+					return fmt.Errorf("%s.%s is not an allowed property", path, key)
+				}
+				continue
+			}
+			if err := validateSchemaValue(propertySchema, v[key], path+"."+key); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				if err := validateSchemaValue(items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// matchesSchemaType reports whether a decoded JSON value has the given JSON Schema type
+func matchesSchemaType(schemaType string, value interface{}) bool {
+	switch strings.ToLower(schemaType) {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		switch value.(type) {
+		case float64, float32, int, int32, int64:
+			return true
+		}
+		return false
+	case "integer":
+		switch v := value.(type) {
+		case int, int32, int64:
+			return true
+		case float64:
+			return v == math.Trunc(v)
+		case float32:
+			return float64(v) == math.Trunc(float64(v))
+		}
+		return false
+	case "null":
+		return value == nil
+	default:
+		return true
+	}
+}
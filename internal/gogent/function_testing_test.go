@@ -0,0 +1,352 @@
+package gogent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"gogent/internal/types"
+)
+
+func TestValidateFunctionArguments(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"orderId":  map[string]interface{}{"type": "string"},
+			"quantity": map[string]interface{}{"type": "integer"},
+			"priority": map[string]interface{}{"type": "string", "enum": []interface{}{"low", "high"}},
+			"tags":     map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+		},
+		"required":             []interface{}{"orderId"},
+		"additionalProperties": false,
+	}
+
+	tests := []struct {
+		name        string
+		args        map[string]interface{}
+		expectError bool
+	}{
+		{"valid", map[string]interface{}{"orderId": "A1", "quantity": float64(2), "tags": []interface{}{"gift"}}, false},
+		{"missing_required", map[string]interface{}{"quantity": float64(2)}, true},
+		{"wrong_type", map[string]interface{}{"orderId": float64(1)}, true},
+		{"fractional_integer", map[string]interface{}{"orderId": "A1", "quantity": 1.5}, true},
+		{"not_in_enum", map[string]interface{}{"orderId": "A1", "priority": "urgent"}, true},
+		{"bad_array_item", map[string]interface{}{"orderId": "A1", "tags": []interface{}{true}}, true},
+		{"unknown_property", map[string]interface{}{"orderId": "A1", "color": "red"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateFunctionArguments(schema, tt.args)
+			if (err != nil) != tt.expectError {
+				t.Errorf("expected error=%v, got %v", tt.expectError, err)
+			}
+		})
+	}
+}
+
+func TestTestFunctionDefinition(t *testing.T) {
+	var received map[string]interface{}
+	var authHeader string
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&received)
+		if received["orderId"] == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"order not found"}`))
+			return
+		}
+		w.Write([]byte(`{"status":"shipped"}`))
+	}))
+	defer endpoint.Close()
+
+	client := newFunctionTestClient(t)
+	client.config = &types.GeminiClientConfig{AllowPrivateFunctionEndpoints: true}
+	ctx := context.Background()
+
+	definition := newTestFunction("lookup_order")
+	definition.EndpointURL = endpoint.URL
+	definition.HttpMethod = "POST"
+	definition.AuthConfig = map[string]interface{}{"type": "bearer", "token": "secret"}
+	definition.MockResponse = map[string]interface{}{"status": "mocked"}
+	function, err := client.CreateFunctionDefinition(ctx, "user-1", definition)
+	if err != nil {
+		t.Fatalf("unexpected error creating function: %v", err)
+	}
+
+	t.Run("calls_endpoint", func(t *testing.T) {
+		result, err := client.TestFunctionDefinition(ctx, "user-1", function.ID, map[string]interface{}{"orderId": "A1"}, false, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success || result.UsedMockData || result.StatusCode != http.StatusOK || result.Response["status"] != "shipped" {
+			t.Errorf("expected the endpoint's response, got %+v", result)
+		}
+		if received["orderId"] != "A1" || authHeader != "Bearer secret" {
+			t.Errorf("expected arguments and auth to be sent, got %v and %q", received, authHeader)
+		}
+
+		var isTest bool
+		var status, definitionID string
+		err = client.db.QueryRow("SELECT is_test, execution_status, function_definition_id FROM function_calls WHERE id = ?",
+			result.FunctionCallID).Scan(&isTest, &status, &definitionID)
+		if err != nil {
+			t.Fatalf("expected the test call to be logged: %v", err)
+		}
+		if !isTest || status != "success" || definitionID != function.ID {
+			t.Errorf("unexpected function call log: is_test=%v status=%s definition=%s", isTest, status, definitionID)
+		}
+	})
+
+	t.Run("reports_endpoint_errors", func(t *testing.T) {
+		result, err := client.TestFunctionDefinition(ctx, "user-1", function.ID, map[string]interface{}{"orderId": "missing"}, false, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success || result.StatusCode != http.StatusNotFound || result.Response["error"] != "order not found" {
+			t.Errorf("expected a failed result with the endpoint's error, got %+v", result)
+		}
+	})
+
+	t.Run("uses_mock_response", func(t *testing.T) {
+		received = nil
+		result, err := client.TestFunctionDefinition(ctx, "user-1", function.ID, map[string]interface{}{"orderId": "A1"}, true, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.UsedMockData || result.Response["status"] != "mocked" || received != nil {
+			t.Errorf("expected the mock response without calling the endpoint, got %+v", result)
+		}
+
+		var usedMockData bool
+		client.db.QueryRow("SELECT used_mock_data FROM function_calls WHERE id = ?", result.FunctionCallID).Scan(&usedMockData)
+		if !usedMockData {
+			t.Errorf("expected the function call log to record mock data")
+		}
+	})
+
+	t.Run("rejects_invalid_arguments", func(t *testing.T) {
+		_, err := client.TestFunctionDefinition(ctx, "user-1", function.ID, map[string]interface{}{}, false, 0)
+		if !errors.Is(err, ErrInvalidFunctionArguments) {
+			t.Errorf("expected ErrInvalidFunctionArguments, got %v", err)
+		}
+	})
+
+	t.Run("requires_ownership", func(t *testing.T) {
+		_, err := client.TestFunctionDefinition(ctx, "user-2", function.ID, map[string]interface{}{"orderId": "A1"}, false, 0)
+		if !errors.Is(err, ErrFunctionNotFound) {
+			t.Errorf("expected ErrFunctionNotFound, got %v", err)
+		}
+	})
+}
+
+func TestFunctionSandbox(t *testing.T) {
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("mode") {
+		case "slow":
+			time.Sleep(500 * time.Millisecond)
+		case "large":
+			w.Write([]byte(`{"data":"` + strings.Repeat("x", 2048) + `"}`))
+			return
+		case "redirect":
+			http.Redirect(w, r, "http://"+strings.Replace(r.Host, "127.0.0.1", "localhost", 1), http.StatusFound)
+			return
+		}
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer endpoint.Close()
+
+	client := newFunctionTestClient(t)
+	client.config = &types.GeminiClientConfig{AllowPrivateFunctionEndpoints: true}
+	ctx := context.Background()
+
+	definition := newTestFunction("sandboxed")
+	definition.EndpointURL = endpoint.URL
+	definition.ParametersSchema = nil
+	definition.TimeoutMs = 100
+	definition.MaxResponseBytes = 1024
+	definition.AllowedDomains = []string{"127.0.0.1"}
+	function, err := client.CreateFunctionDefinition(ctx, "user-1", definition)
+	if err != nil {
+		t.Fatalf("unexpected error creating function: %v", err)
+	}
+	if function.TimeoutMs != 100 || function.MaxResponseBytes != 1024 || len(function.AllowedDomains) != 1 {
+		t.Fatalf("expected sandbox limits to round-trip, got %+v", function)
+	}
+
+	tests := []struct {
+		mode        string
+		expectError string
+	}{
+		{"", ""},
+		{"slow", "timeout"},
+		{"large", "too large"},
+		{"redirect", "not allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run("mode_"+tt.mode, func(t *testing.T) {
+			result, err := client.TestFunctionDefinition(ctx, "user-1", function.ID, map[string]interface{}{"mode": tt.mode}, false, 0)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.expectError == "" {
+				if !result.Success {
+					t.Errorf("expected success, got %+v", result)
+				}
+				return
+			}
+			if result.Success || !strings.Contains(result.Error, tt.expectError) {
+				t.Errorf("expected an error containing %q, got %+v", tt.expectError, result)
+			}
+			if result.Response != nil {
+				t.Errorf("expected no response to be stored, got %v", result.Response)
+			}
+		})
+	}
+}
+
+func TestExecuteFunctionCallMockMode(t *testing.T) {
+	client := newFunctionTestClient(t)
+	ctx := context.Background()
+
+	definition := newTestFunction("lookup_order")
+	definition.MockResponse = map[string]interface{}{"status": "mocked"}
+	mocked, err := client.CreateFunctionDefinition(ctx, "user-1", definition)
+	if err != nil {
+		t.Fatalf("unexpected error creating function: %v", err)
+	}
+	liveDefinition := newTestFunction("cancel_order")
+	liveDefinition.MockResponse = nil
+	live, err := client.CreateFunctionDefinition(ctx, "user-1", liveDefinition)
+	if err != nil {
+		t.Fatalf("unexpected error creating function: %v", err)
+	}
+
+	_, err = client.db.Exec(`
+		INSERT INTO execution_function_configs (id, user_id, execution_run_id, function_definition_id, use_mock_response)
+		VALUES ('efc-1', 'user-1', 'run-1', ?, TRUE), ('efc-2', 'user-1', 'run-1', ?, FALSE)
+	`, mocked.ID, live.ID)
+	if err != nil {
+		t.Fatalf("failed to create function configs: %v", err)
+	}
+
+	args := map[string]interface{}{"orderId": "A1"}
+	tests := []struct {
+		name           string
+		executionRunID string
+		functionName   string
+		expectMock     bool
+		expectStatus   string
+	}{
+		{"mock_mode", "run-1", "lookup_order", true, "mocked"},
+		{"live_mode", "run-1", "cancel_order", false, "success"},
+		{"other_run", "run-2", "lookup_order", false, "success"},
+		{"no_run", "", "lookup_order", false, "success"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, usedMockData, err := client.executeFunctionCall(ctx, tt.executionRunID, tt.functionName, args)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if usedMockData != tt.expectMock || result["status"] != tt.expectStatus {
+				t.Errorf("expected mock=%v status=%s, got mock=%v %v", tt.expectMock, tt.expectStatus, usedMockData, result)
+			}
+		})
+	}
+
+	t.Run("definition_timeout", func(t *testing.T) {
+		client.db.Exec("UPDATE function_definitions SET timeout_ms = 2500 WHERE id = ?", live.ID)
+		functionConfig, err := client.executionFunctionConfig(ctx, "run-1", "cancel_order")
+		if err != nil || functionConfig == nil || functionConfig.timeout != 2500*time.Millisecond {
+			t.Errorf("expected live calls to use the definition's timeout, got %+v (%v)", functionConfig, err)
+		}
+	})
+
+	t.Run("placeholder_without_mock_response", func(t *testing.T) {
+		client.db.Exec("UPDATE execution_function_configs SET use_mock_response = TRUE WHERE id = 'efc-2'")
+		result, usedMockData, err := client.executeFunctionCall(ctx, "run-1", "cancel_order", args)
+		if err != nil || !usedMockData || result["status"] != "mock_success" {
+			t.Errorf("expected the placeholder mock response, got %v, %v, %v", result, usedMockData, err)
+		}
+	})
+}
+
+func TestFunctionEndpointInternalAddresses(t *testing.T) {
+	var called bool
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer endpoint.Close()
+
+	client := newFunctionTestClient(t)
+	ctx := context.Background()
+
+	// localhost is only refused once it resolves, so a host name cannot hide an internal address
+	endpoints := map[string]string{
+		"loopback_ip":   endpoint.URL,
+		"loopback_name": strings.Replace(endpoint.URL, "127.0.0.1", "localhost", 1),
+	}
+	for name, endpointURL := range endpoints {
+		definition := newTestFunction(name)
+		definition.EndpointURL = endpointURL
+		definition.ParametersSchema = nil
+		function, err := client.CreateFunctionDefinition(ctx, "user-1", definition)
+		if err != nil {
+			t.Fatalf("unexpected error creating function: %v", err)
+		}
+
+		result, err := client.TestFunctionDefinition(ctx, "user-1", function.ID, nil, false, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success || !strings.Contains(result.Error, ErrFunctionAddressNotAllowed.Error()) || called {
+			t.Errorf("expected %s to be refused before connecting, got %+v", endpointURL, result)
+		}
+
+		client.config = &types.GeminiClientConfig{AllowPrivateFunctionEndpoints: true}
+		result, err = client.TestFunctionDefinition(ctx, "user-1", function.ID, nil, false, 0)
+		if err != nil || !result.Success || !called {
+			t.Errorf("expected %s to be called once private endpoints are allowed, got %+v, %v", endpointURL, result, err)
+		}
+		client.config, called = nil, false
+	}
+}
+
+func TestInternalAddress(t *testing.T) {
+	tests := []struct {
+		address  string
+		internal bool
+	}{
+		{"127.0.0.1", true},
+		{"::1", true},
+		{"10.1.2.3", true},
+		{"172.16.0.1", true},
+		{"192.168.1.1", true},
+		{"169.254.169.254", true},
+		{"fe80::1", true},
+		{"fd00::1", true},
+		{"0.0.0.0", true},
+		{"::", true},
+		{"::ffff:127.0.0.1", true},
+		{"::ffff:10.0.0.1", true},
+		{"8.8.8.8", false},
+		{"172.32.0.1", false},
+		{"2606:4700::1111", false},
+	}
+
+	for _, tt := range tests {
+		if got := internalAddress(netip.MustParseAddr(tt.address)); got != tt.internal {
+			t.Errorf("internalAddress(%s): expected %v, got %v", tt.address, tt.internal, got)
+		}
+	}
+}
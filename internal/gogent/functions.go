@@ -0,0 +1,393 @@
+package gogent
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"gogent/internal/types"
+
+	"github.com/google/uuid"
+)
+
+// ErrFunctionNotFound is returned when a function definition does not exist or is not owned by the user
+var ErrFunctionNotFound = errors.New("function not found")
+
+// ErrFunctionNameTaken is returned when a user already has an active function with the same name
+var ErrFunctionNameTaken = errors.New("function name already in use")
+
+// functionNamePattern matches names the model providers accept for function declarations
+var functionNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]{0,63}$`)
+
+// functionHTTPMethods lists the HTTP methods a function endpoint may use
+var functionHTTPMethods = map[string]bool{
+	"GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true,
+}
+
+// functionDefinitionColumns is the column list read by scanFunctionDefinition
+const functionDefinitionColumns = `
+	id, user_id, name, display_name, description, parameters_schema,
+	mock_response, endpoint_url, http_method, headers, auth_config,
+	required_api_keys, api_key_validation, is_active, created_at, updated_at,
+	timeout_ms, max_response_bytes, allowed_domains`
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// ValidateFunctionDefinition checks required fields and sandbox limits, and normalizes the HTTP
+// method and allowed domains
+func ValidateFunctionDefinition(function *types.FunctionDefinition) error {
+	if function.Name == "" || function.DisplayName == "" || function.Description == "" {
+		return fmt.Errorf("name, displayName, and description are required")
+	}
+	if !functionNamePattern.MatchString(function.Name) {
+		return fmt.Errorf("invalid function name %q: use letters, digits and underscores (max 64), starting with a letter or underscore", function.Name)
+	}
+	if len(function.DisplayName) > 100 {
+		return fmt.Errorf("display name must be at most 100 characters")
+	}
+
+	function.HttpMethod = strings.ToUpper(function.HttpMethod)
+	if function.HttpMethod == "" {
+		function.HttpMethod = "POST"
+	}
+	if !functionHTTPMethods[function.HttpMethod] {
+		return fmt.Errorf("unsupported HTTP method: %s", function.HttpMethod)
+	}
+	if len(function.EndpointURL) > 500 {
+		return fmt.Errorf("endpoint URL must be at most 500 characters")
+	}
+	return validateFunctionSandbox(function)
+}
+
+// functionJSONColumns serializes a definition's JSON fields, storing NULL for empty optional fields
+func functionJSONColumns(function *types.FunctionDefinition) ([]interface{}, error) {
+	schema := function.ParametersSchema
+	if schema == nil {
+		schema = map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+	}
+
+	fields := []struct {
+		name     string
+		value    interface{}
+		optional bool
+	}{
+		{"parameters schema", schema, false},
+		{"mock response", function.MockResponse, len(function.MockResponse) == 0},
+		{"headers", function.Headers, len(function.Headers) == 0},
+		{"auth config", function.AuthConfig, len(function.AuthConfig) == 0},
+		{"required API keys", function.RequiredApiKeys, len(function.RequiredApiKeys) == 0},
+		{"API key validation", function.ApiKeyValidation, len(function.ApiKeyValidation) == 0},
+		{"allowed domains", function.AllowedDomains, len(function.AllowedDomains) == 0},
+	}
+
+	columns := make([]interface{}, len(fields))
+	for i, field := range fields {
+		if field.optional {
+			columns[i] = nil
+			continue
+		}
+		encoded, err := types.ToJSON(field.value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", field.name, err)
+		}
+		columns[i] = encoded
+	}
+	return columns, nil
+}
+
+// scanFunctionDefinition reads a row selected with functionDefinitionColumns
+func scanFunctionDefinition(row rowScanner) (*types.FunctionDefinition, error) {
+	var function types.FunctionDefinition
+	var description, endpointURL, httpMethod sql.NullString
+	var schema, mockResponse, headers, authConfig, requiredKeys, keyValidation, allowedDomains sql.NullString
+	var timeoutMs, maxResponseBytes sql.NullInt32
+
+	err := row.Scan(
+		&function.ID, &function.UserID, &function.Name, &function.DisplayName, &description,
+		&schema, &mockResponse, &endpointURL, &httpMethod, &headers, &authConfig,
+		&requiredKeys, &keyValidation, &function.IsActive, &function.CreatedAt, &function.UpdatedAt,
+		&timeoutMs, &maxResponseBytes, &allowedDomains,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	function.Description = description.String
+	function.EndpointURL = endpointURL.String
+	function.HttpMethod = httpMethod.String
+	function.TimeoutMs = timeoutMs.Int32
+	function.MaxResponseBytes = maxResponseBytes.Int32
+
+	fields := []struct {
+		name  string
+		value sql.NullString
+		dest  interface{}
+	}{
+		{"parameters schema", schema, &function.ParametersSchema},
+		{"mock response", mockResponse, &function.MockResponse},
+		{"headers", headers, &function.Headers},
+		{"auth config", authConfig, &function.AuthConfig},
+		{"required API keys", requiredKeys, &function.RequiredApiKeys},
+		{"API key validation", keyValidation, &function.ApiKeyValidation},
+		{"allowed domains", allowedDomains, &function.AllowedDomains},
+	}
+	for _, field := range fields {
+		if err := types.FromJSON(field.value.String, field.dest); err != nil {
+			return nil, fmt.Errorf("failed to parse %s for %s: %w", field.name, function.Name, err)
+		}
+	}
+
+	return &function, nil
+}
+
+// GetFunctionDefinition loads an active function definition owned by the user or built into the system
+func (c *Client) GetFunctionDefinition(ctx context.Context, userID, id string) (*types.FunctionDefinition, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	row := c.db.QueryRowContext(ctx, `
+		SELECT `+functionDefinitionColumns+`
+		FROM function_definitions
+		WHERE id = ? AND (user_id = ? OR user_id = 'system') AND is_active = TRUE
+	`, id, userID)
+
+	function, err := scanFunctionDefinition(row)
+	if err == sql.ErrNoRows {
+		return nil, ErrFunctionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get function definition: %w", err)
+	}
+	return function, nil
+}
+
+// ListFunctionDefinitions returns a page of the active function definitions visible to the user, including system functions
+func (c *Client) ListFunctionDefinitions(ctx context.Context, userID string, limit, offset int32) ([]*types.FunctionDefinition, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT `+functionDefinitionColumns+`
+		FROM function_definitions
+		WHERE (user_id = ? OR user_id = 'system') AND is_active = TRUE
+		ORDER BY display_name ASC, id ASC
+		LIMIT ? OFFSET ?
+	`, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query function definitions: %w", err)
+	}
+	defer rows.Close()
+
+	var functions []*types.FunctionDefinition
+	for rows.Next() {
+		function, err := scanFunctionDefinition(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan function definition: %w", err)
+		}
+		functions = append(functions, function)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate function definitions: %w", err)
+	}
+	return functions, nil
+}
+
+// CountFunctionDefinitions counts the active functions visible to the user, their own and the system's
+func (c *Client) CountFunctionDefinitions(ctx context.Context, userID string) (int64, error) {
+	if c.db == nil {
+		return 0, ErrNoDatabase
+	}
+	var count int64
+	err := c.db.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM function_definitions
+		WHERE (user_id = ? OR user_id = 'system') AND is_active = TRUE
+	`, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count function definitions: %w", err)
+	}
+	return count, nil
+}
+
+// functionNameOwner returns the user's or an active system function with the given name,
+// preferring active rows. found is false when neither exists.
+func (c *Client) functionNameOwner(ctx context.Context, userID, name string) (id string, ownerID string, active bool, found bool, err error) {
+	if c.db == nil {
+		return "", "", false, false, ErrNoDatabase
+	}
+	err = c.db.QueryRowContext(ctx, `
+		SELECT id, user_id, is_active
+		FROM function_definitions
+		WHERE name = ? AND (user_id = ? OR (user_id = 'system' AND is_active = TRUE))
+		ORDER BY is_active DESC, CASE WHEN user_id = 'system' THEN 1 ELSE 0 END
+		LIMIT 1
+	`, name, userID).Scan(&id, &ownerID, &active)
+	if err == sql.ErrNoRows {
+		return "", "", false, false, nil
+	}
+	if err != nil {
+		return "", "", false, false, fmt.Errorf("failed to check function name: %w", err)
+	}
+	return id, ownerID, active, true, nil
+}
+
+// CreateFunctionDefinition stores a new function definition owned by the user. Names are unique
+// per user and may not shadow a system function; a soft-deleted function with the same name is
+// replaced and reactivated.
+func (c *Client) CreateFunctionDefinition(ctx context.Context, userID string, function *types.FunctionDefinition) (*types.FunctionDefinition, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	if err := ValidateFunctionDefinition(function); err != nil {
+		return nil, err
+	}
+	jsonColumns, err := functionJSONColumns(function)
+	if err != nil {
+		return nil, err
+	}
+
+	existingID, ownerID, active, found, err := c.functionNameOwner(ctx, userID, function.Name)
+	if err != nil {
+		return nil, err
+	}
+	if found && (active || ownerID != userID) {
+		return nil, fmt.Errorf("%w: %s", ErrFunctionNameTaken, function.Name)
+	}
+
+	if found {
+		// Reuse the soft-deleted row, since (user_id, name) is unique
+		if err := c.writeFunctionDefinition(ctx, userID, existingID, function, jsonColumns); err != nil {
+			return nil, err
+		}
+		return c.GetFunctionDefinition(ctx, userID, existingID)
+	}
+
+	id := uuid.New().String()
+	args := []interface{}{id, userID, function.Name, function.DisplayName, function.Description, jsonColumns[0],
+		jsonColumns[1], nullableString(function.EndpointURL), function.HttpMethod, jsonColumns[2], jsonColumns[3],
+		jsonColumns[4], jsonColumns[5], nullableInt32(function.TimeoutMs), nullableInt32(function.MaxResponseBytes),
+		jsonColumns[6]}
+
+	_, err = c.db.ExecContext(ctx, `
+		INSERT INTO function_definitions (
+			id, user_id, name, display_name, description, parameters_schema,
+			mock_response, endpoint_url, http_method, headers, auth_config,
+			required_api_keys, api_key_validation, timeout_ms, max_response_bytes,
+			allowed_domains, is_active
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, TRUE)
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create function definition: %w", err)
+	}
+
+	return c.GetFunctionDefinition(ctx, userID, id)
+}
+
+// UpdateFunctionDefinition replaces an active function definition owned by the user. System
+// functions cannot be updated.
+func (c *Client) UpdateFunctionDefinition(ctx context.Context, userID, id string, function *types.FunctionDefinition) (*types.FunctionDefinition, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	if err := ValidateFunctionDefinition(function); err != nil {
+		return nil, err
+	}
+	jsonColumns, err := functionJSONColumns(function)
+	if err != nil {
+		return nil, err
+	}
+
+	var owned int
+	err = c.db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM function_definitions WHERE id = ? AND user_id = ? AND is_active = TRUE",
+		id, userID,
+	).Scan(&owned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up function definition: %w", err)
+	}
+	if owned == 0 {
+		return nil, ErrFunctionNotFound
+	}
+
+	existingID, _, _, found, err := c.functionNameOwner(ctx, userID, function.Name)
+	if err != nil {
+		return nil, err
+	}
+	if found && existingID != id {
+		return nil, fmt.Errorf("%w: %s", ErrFunctionNameTaken, function.Name)
+	}
+
+	if err := c.writeFunctionDefinition(ctx, userID, id, function, jsonColumns); err != nil {
+		return nil, err
+	}
+	return c.GetFunctionDefinition(ctx, userID, id)
+}
+
+// writeFunctionDefinition overwrites a user's function row and marks it active
+func (c *Client) writeFunctionDefinition(ctx context.Context, userID, id string, function *types.FunctionDefinition, jsonColumns []interface{}) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	_, err := c.db.ExecContext(ctx, `
+		UPDATE function_definitions
+		SET name = ?, display_name = ?, description = ?, parameters_schema = ?,
+		    mock_response = ?, endpoint_url = ?, http_method = ?, headers = ?, auth_config = ?,
+		    required_api_keys = ?, api_key_validation = ?, timeout_ms = ?, max_response_bytes = ?,
+		    allowed_domains = ?, is_active = TRUE, updated_at = ?
+		WHERE id = ? AND user_id = ?`,
+		function.Name, function.DisplayName, function.Description, jsonColumns[0],
+		jsonColumns[1], nullableString(function.EndpointURL), function.HttpMethod, jsonColumns[2], jsonColumns[3],
+		jsonColumns[4], jsonColumns[5], nullableInt32(function.TimeoutMs), nullableInt32(function.MaxResponseBytes),
+		jsonColumns[6], time.Now(), id, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update function definition: %w", err)
+	}
+	return nil
+}
+
+// DeleteFunctionDefinition soft-deletes a function definition owned by the user
+func (c *Client) DeleteFunctionDefinition(ctx context.Context, userID, id string) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	result, err := c.db.ExecContext(ctx, `
+		UPDATE function_definitions
+		SET is_active = FALSE, updated_at = ?
+		WHERE id = ? AND user_id = ? AND is_active = TRUE
+	`, time.Now(), id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete function definition: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete function definition: %w", err)
+	}
+	if affected == 0 {
+		return ErrFunctionNotFound
+	}
+	return nil
+}
+
+// nullableString stores empty strings as NULL
+func nullableString(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+// nullableInt32 stores zero as NULL
+func nullableInt32(value int32) interface{} {
+	if value == 0 {
+		return nil
+	}
+	return value
+}
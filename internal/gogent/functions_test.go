@@ -0,0 +1,178 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gogent/internal/testdb"
+	"gogent/internal/types"
+)
+
+// newFunctionTestClient returns a client backed by the in-memory test schema, with the system
+// get_current_weather function defined
+func newFunctionTestClient(t *testing.T) *Client {
+	database := testdb.Open(t)
+	_, err := database.Exec(`
+		INSERT INTO function_definitions (id, user_id, name, display_name, description, parameters_schema)
+		VALUES ('system-weather', 'system', 'get_current_weather', 'Get Weather', 'Current weather', '{}')`)
+	if err != nil {
+		t.Fatalf("failed to define the system function: %v", err)
+	}
+	return &Client{db: database}
+}
+
+func newTestFunction(name string) *types.FunctionDefinition {
+	return &types.FunctionDefinition{
+		Name:        name,
+		DisplayName: "Lookup Order",
+		Description: "Looks up an order by ID",
+		ParametersSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"orderId": map[string]interface{}{"type": "string"}},
+			"required":   []interface{}{"orderId"},
+		},
+		MockResponse:     map[string]interface{}{"status": "shipped"},
+		EndpointURL:      "https://orders.example.com/lookup",
+		HttpMethod:       "get",
+		Headers:          map[string]interface{}{"Accept": "application/json"},
+		AuthConfig:       map[string]interface{}{"type": "bearer"},
+		RequiredApiKeys:  []string{"ordersApiKey"},
+		ApiKeyValidation: map[string]interface{}{"ordersApiKey": map[string]interface{}{"pattern": "^ok_"}},
+	}
+}
+
+func TestFunctionDefinitionCRUD(t *testing.T) {
+	client := newFunctionTestClient(t)
+	ctx := context.Background()
+
+	created, err := client.CreateFunctionDefinition(ctx, "user-1", newTestFunction("lookup_order"))
+	if err != nil {
+		t.Fatalf("unexpected error creating function: %v", err)
+	}
+	if created.ID == "" || created.UserID != "user-1" || !created.IsActive {
+		t.Fatalf("expected an active function owned by user-1, got %+v", created)
+	}
+	if created.HttpMethod != "GET" {
+		t.Errorf("expected normalized HTTP method GET, got %s", created.HttpMethod)
+	}
+	if created.MockResponse["status"] != "shipped" || created.Headers["Accept"] != "application/json" ||
+		created.AuthConfig["type"] != "bearer" || len(created.RequiredApiKeys) != 1 || created.ApiKeyValidation["ordersApiKey"] == nil {
+		t.Errorf("expected JSON fields to round-trip, got %+v", created)
+	}
+	if created.TimeoutMs != 0 || created.MaxResponseBytes != 0 || created.AllowedDomains != nil {
+		t.Errorf("expected unset sandbox limits, got %+v", created)
+	}
+
+	t.Run("name_unique_per_user", func(t *testing.T) {
+		_, err := client.CreateFunctionDefinition(ctx, "user-1", newTestFunction("lookup_order"))
+		if !errors.Is(err, ErrFunctionNameTaken) {
+			t.Errorf("expected ErrFunctionNameTaken, got %v", err)
+		}
+		if _, err := client.CreateFunctionDefinition(ctx, "user-2", newTestFunction("lookup_order")); err != nil {
+			t.Errorf("expected another user to reuse the name, got %v", err)
+		}
+		_, err = client.CreateFunctionDefinition(ctx, "user-1", newTestFunction("get_current_weather"))
+		if !errors.Is(err, ErrFunctionNameTaken) {
+			t.Errorf("expected system function names to be reserved, got %v", err)
+		}
+	})
+
+	t.Run("ownership", func(t *testing.T) {
+		if _, err := client.GetFunctionDefinition(ctx, "user-2", created.ID); !errors.Is(err, ErrFunctionNotFound) {
+			t.Errorf("expected other users not to see the function, got %v", err)
+		}
+		if _, err := client.UpdateFunctionDefinition(ctx, "user-2", created.ID, newTestFunction("lookup_order")); !errors.Is(err, ErrFunctionNotFound) {
+			t.Errorf("expected other users not to update the function, got %v", err)
+		}
+		if err := client.DeleteFunctionDefinition(ctx, "user-1", "system-weather"); !errors.Is(err, ErrFunctionNotFound) {
+			t.Errorf("expected system functions not to be deletable, got %v", err)
+		}
+		if _, err := client.GetFunctionDefinition(ctx, "user-1", "system-weather"); err != nil {
+			t.Errorf("expected system functions to be visible, got %v", err)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		update := newTestFunction("find_order")
+		update.MockResponse = nil
+		updated, err := client.UpdateFunctionDefinition(ctx, "user-1", created.ID, update)
+		if err != nil {
+			t.Fatalf("unexpected error updating function: %v", err)
+		}
+		if updated.Name != "find_order" || updated.MockResponse != nil {
+			t.Errorf("expected renamed function without mock response, got %+v", updated)
+		}
+
+		if _, err := client.CreateFunctionDefinition(ctx, "user-1", newTestFunction("cancel_order")); err != nil {
+			t.Fatalf("unexpected error creating function: %v", err)
+		}
+		if _, err := client.UpdateFunctionDefinition(ctx, "user-1", created.ID, newTestFunction("cancel_order")); !errors.Is(err, ErrFunctionNameTaken) {
+			t.Errorf("expected rename onto an existing name to fail, got %v", err)
+		}
+	})
+
+	t.Run("soft_delete", func(t *testing.T) {
+		if err := client.DeleteFunctionDefinition(ctx, "user-1", created.ID); err != nil {
+			t.Fatalf("unexpected error deleting function: %v", err)
+		}
+		if err := client.DeleteFunctionDefinition(ctx, "user-1", created.ID); !errors.Is(err, ErrFunctionNotFound) {
+			t.Errorf("expected a second delete to report not found, got %v", err)
+		}
+
+		functions, err := client.ListFunctionDefinitions(ctx, "user-1", MaxPageSize, 0)
+		if err != nil {
+			t.Fatalf("unexpected error listing functions: %v", err)
+		}
+		for _, function := range functions {
+			if function.ID == created.ID {
+				t.Error("expected deleted function to be hidden from listings")
+			}
+		}
+		if total, err := client.CountFunctionDefinitions(ctx, "user-1"); err != nil || total != int64(len(functions)) {
+			t.Errorf("expected the count to match the listing's %d functions, got %d (%v)", len(functions), total, err)
+		}
+
+		// The row is kept, and creating the name again reactivates it
+		recreated, err := client.CreateFunctionDefinition(ctx, "user-1", newTestFunction("find_order"))
+		if err != nil {
+			t.Fatalf("unexpected error recreating function: %v", err)
+		}
+		if recreated.ID != created.ID || !recreated.IsActive {
+			t.Errorf("expected the soft-deleted row to be reactivated, got %+v", recreated)
+		}
+	})
+}
+
+func TestValidateFunctionDefinition(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(*types.FunctionDefinition)
+		expected bool
+	}{
+		{"valid", func(f *types.FunctionDefinition) {}, true},
+		{"missing_description", func(f *types.FunctionDefinition) { f.Description = "" }, false},
+		{"name_with_spaces", func(f *types.FunctionDefinition) { f.Name = "lookup order" }, false},
+		{"name_starting_with_digit", func(f *types.FunctionDefinition) { f.Name = "1lookup" }, false},
+		{"unsupported_method", func(f *types.FunctionDefinition) { f.HttpMethod = "TRACE" }, false},
+		{"default_method", func(f *types.FunctionDefinition) { f.HttpMethod = "" }, true},
+		{"sandbox_limits", func(f *types.FunctionDefinition) {
+			f.TimeoutMs, f.MaxResponseBytes, f.AllowedDomains = 2000, 4096, []string{"*.Example.com"}
+		}, true},
+		{"timeout_too_long", func(f *types.FunctionDefinition) { f.TimeoutMs = 120000 }, false},
+		{"negative_response_limit", func(f *types.FunctionDefinition) { f.MaxResponseBytes = -1 }, false},
+		{"invalid_allowed_domain", func(f *types.FunctionDefinition) { f.AllowedDomains = []string{"https://example.com"} }, false},
+		{"endpoint_outside_allowed_domains", func(f *types.FunctionDefinition) { f.AllowedDomains = []string{"example.org"} }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			function := newTestFunction("lookup_order")
+			tt.modify(function)
+			err := ValidateFunctionDefinition(function)
+			if (err == nil) != tt.expected {
+				t.Errorf("expected valid=%v, got error %v", tt.expected, err)
+			}
+		})
+	}
+}
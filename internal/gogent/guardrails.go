@@ -0,0 +1,429 @@
+package gogent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"gogent/internal/types"
+)
+
+// guardViolationsMetric counts the guards that triggered on a variation's prompt or response
+const guardViolationsMetric = "guard_violations"
+
+// ErrPromptBlocked is returned for a variation whose prompt an input guard blocked
+var ErrPromptBlocked = errors.New("prompt blocked by guardrail")
+
+// Guard inspects a prompt or response for passages a guardrail objects to. Implement it and call
+// RegisterGuard to make a guard selectable in a configuration's guardrails.
+type Guard interface {
+	// Name is how configurations select the guard
+	Name() string
+
+	// Inspect returns the passages of text the guard objects to. config carries the guard's
+	// settings, such as the banned_topics guard's topics.
+	Inspect(text string, config types.GuardConfig) []GuardMatch
+}
+
+// GuardMatch is a passage a guard objects to, text[Start:End], labelled with what it is
+type GuardMatch struct {
+	Start int
+	End   int
+	Label string // e.g. email, credit_card or ignore_instructions
+}
+
+var (
+	guardsMu sync.RWMutex
+	guards   = map[string]Guard{
+		"pii":              PIIGuard{},
+		"prompt_injection": PromptInjectionGuard{},
+		"banned_topics":    BannedTopicsGuard{},
+	}
+)
+
+// RegisterGuard makes a guard selectable by its name, replacing any guard of the same name
+func RegisterGuard(guard Guard) {
+	guardsMu.Lock()
+	defer guardsMu.Unlock()
+	guards[guard.Name()] = guard
+}
+
+// lookupGuard returns the registered guard with a name
+func lookupGuard(name string) (Guard, bool) {
+	guardsMu.RLock()
+	defer guardsMu.RUnlock()
+	guard, ok := guards[name]
+	return guard, ok
+}
+
+// guardAction returns a guard's action, defaulting to block for input guards and flag for output guards
+func guardAction(config types.GuardConfig) string {
+	if config.Action != "" {
+		return config.Action
+	}
+	if config.Stage == types.GuardStageInput {
+		return types.GuardActionBlock
+	}
+	return types.GuardActionFlag
+}
+
+// validateGuardrails checks that each guard is registered and runs at a stage that supports its action
+func validateGuardrails(validation *ValidationError, field string, guardrails []types.GuardConfig) {
+	for i, guard := range guardrails {
+		guardField := fmt.Sprintf("%s.guardrails[%d]", field, i)
+		if _, ok := lookupGuard(guard.Guard); !ok {
+			validation.add(guardField+".guard", "unknown guard %q", guard.Guard)
+		}
+		if guard.Guard == "banned_topics" && len(guard.Topics) == 0 {
+			validation.add(guardField+".topics", "the banned_topics guard needs at least one topic")
+		}
+
+		switch guard.Stage {
+		case types.GuardStageInput:
+			switch guard.Action {
+			case "", types.GuardActionBlock, types.GuardActionSanitize, types.GuardActionFlag:
+			default:
+				validation.add(guardField+".action", "input guards can %s, %s or %s, got %q",
+					types.GuardActionBlock, types.GuardActionSanitize, types.GuardActionFlag, guard.Action)
+			}
+		case types.GuardStageOutput:
+			switch guard.Action {
+			case "", types.GuardActionFlag, types.GuardActionRedact:
+			default:
+				validation.add(guardField+".action", "output guards can %s or %s, got %q",
+					types.GuardActionFlag, types.GuardActionRedact, guard.Action)
+			}
+		default:
+			validation.add(guardField+".stage", "must be %q or %q, got %q", types.GuardStageInput, types.GuardStageOutput, guard.Stage)
+		}
+	}
+}
+
+// applyInputGuards runs a configuration's input guards over the prompt and context. It returns
+// their verdicts, the prompt and context with sanitized matches replaced, and whether a guard
+// blocked the prompt.
+func applyInputGuards(guardrails []types.GuardConfig, prompt, promptContext string) ([]types.GuardVerdict, string, string, bool) {
+	var verdicts []types.GuardVerdict
+	blocked := false
+	for _, config := range guardrails {
+		guard, ok := lookupGuard(config.Guard)
+		if !ok || config.Stage != types.GuardStageInput {
+			continue
+		}
+		promptMatches := guardMatches(guard, prompt, config)
+		contextMatches := guardMatches(guard, promptContext, config)
+
+		verdict := guardVerdict(config, append(append([]GuardMatch{}, promptMatches...), contextMatches...))
+		switch {
+		case !verdict.Triggered:
+		case verdict.Action == types.GuardActionBlock:
+			blocked = true
+		case verdict.Action == types.GuardActionSanitize:
+			prompt = replaceGuardMatches(prompt, promptMatches)
+			promptContext = replaceGuardMatches(promptContext, contextMatches)
+		}
+		verdicts = append(verdicts, verdict)
+	}
+	return verdicts, prompt, promptContext, blocked
+}
+
+// applyOutputGuards runs a configuration's output guards over a response text, returning their
+// verdicts and the text with redacted matches replaced
+func applyOutputGuards(guardrails []types.GuardConfig, text string) ([]types.GuardVerdict, string) {
+	var verdicts []types.GuardVerdict
+	for _, config := range guardrails {
+		guard, ok := lookupGuard(config.Guard)
+		if !ok || config.Stage != types.GuardStageOutput {
+			continue
+		}
+		matches := guardMatches(guard, text, config)
+		verdict := guardVerdict(config, matches)
+		if verdict.Triggered && verdict.Action == types.GuardActionRedact {
+			text = replaceGuardMatches(text, matches)
+		}
+		verdicts = append(verdicts, verdict)
+	}
+	return verdicts, text
+}
+
+// guardMatches returns a guard's matches in text, sorted and without overlaps
+func guardMatches(guard Guard, text string, config types.GuardConfig) []GuardMatch {
+	if text == "" {
+		return nil
+	}
+	matches := guard.Inspect(text, config)
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Start < matches[j].Start })
+
+	kept := matches[:0]
+	end := 0
+	for _, match := range matches {
+		if match.Start < end || match.Start < 0 || match.End > len(text) || match.End <= match.Start {
+			continue
+		}
+		kept = append(kept, match)
+		end = match.End
+	}
+	return kept
+}
+
+// guardVerdict records what a guard found, listing each label once in the order found
+func guardVerdict(config types.GuardConfig, matches []GuardMatch) types.GuardVerdict {
+	verdict := types.GuardVerdict{
+		Guard:     config.Guard,
+		Stage:     config.Stage,
+		Action:    guardAction(config),
+		Triggered: len(matches) > 0,
+	}
+	seen := make(map[string]bool)
+	for _, match := range matches {
+		if !seen[match.Label] {
+			seen[match.Label] = true
+			verdict.Findings = append(verdict.Findings, match.Label)
+		}
+	}
+	return verdict
+}
+
+// replaceGuardMatches replaces each match with its label, e.g. [EMAIL]
+func replaceGuardMatches(text string, matches []GuardMatch) string {
+	if len(matches) == 0 {
+		return text
+	}
+	var b strings.Builder
+	last := 0
+	for _, match := range matches {
+		b.WriteString(text[last:match.Start])
+		b.WriteString("[" + strings.ToUpper(strings.ReplaceAll(match.Label, " ", "_")) + "]")
+		last = match.End
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// guardFindings summarizes the verdicts that triggered, e.g. "pii (email, phone)"
+func guardFindings(verdicts []types.GuardVerdict) string {
+	var triggered []string
+	for _, verdict := range verdicts {
+		if verdict.Triggered {
+			triggered = append(triggered, fmt.Sprintf("%s (%s)", verdict.Guard, strings.Join(verdict.Findings, ", ")))
+		}
+	}
+	return strings.Join(triggered, "; ")
+}
+
+// guardViolations counts the verdicts that triggered
+func guardViolations(verdicts []types.GuardVerdict) int {
+	violations := 0
+	for _, verdict := range verdicts {
+		if verdict.Triggered {
+			violations++
+		}
+	}
+	return violations
+}
+
+// patternMatches returns the matches of labelled patterns in text
+func patternMatches(text string, patterns []labelledPattern) []GuardMatch {
+	var matches []GuardMatch
+	for _, pattern := range patterns {
+		for _, location := range pattern.pattern.FindAllStringIndex(text, -1) {
+			if pattern.valid == nil || pattern.valid(text[location[0]:location[1]]) {
+				matches = append(matches, GuardMatch{Start: location[0], End: location[1], Label: pattern.label})
+			}
+		}
+	}
+	return matches
+}
+
+// labelledPattern is a pattern a guard looks for, optionally confirmed by valid
+type labelledPattern struct {
+	label   string
+	pattern *regexp.Regexp
+	valid   func(match string) bool
+}
+
+// piiPatterns are checked in order, so card numbers are labelled before they can read as phone numbers
+var piiPatterns = []labelledPattern{
+	{label: "email", pattern: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)},
+	{label: "credit_card", pattern: regexp.MustCompile(`\b\d(?:[ \-]?\d){12,18}\b`), valid: luhnValid},
+	{label: "ssn", pattern: regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)},
+	{label: "phone", pattern: regexp.MustCompile(`(?:\+\d{1,3}[\s.\-]?)?(?:\(\d{3}\)|\b\d{3})[\s.\-]?\d{3}[\s.\-]\d{4}\b`)},
+}
+
+// PIIGuard finds email addresses, credit card numbers, US social security numbers and phone numbers
+type PIIGuard struct{}
+
+// Name is how configurations select the guard
+func (PIIGuard) Name() string {
+	return "pii"
+}
+
+// Inspect returns the personal data in text
+func (PIIGuard) Inspect(text string, config types.GuardConfig) []GuardMatch {
+	return patternMatches(text, piiPatterns)
+}
+
+// luhnValid reports whether the digits of a number pass the Luhn checksum card numbers carry
+func luhnValid(number string) bool {
+	sum, digits := 0, 0
+	for i := len(number) - 1; i >= 0; i-- {
+		ch := number[i]
+		if ch < '0' || ch > '9' {
+			continue
+		}
+		digit := int(ch - '0')
+		if digits%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		digits++
+	}
+	return digits >= 13 && sum%10 == 0
+}
+
+// promptInjectionPatterns are phrasings common in attempts to override a model's instructions
+var promptInjectionPatterns = []labelledPattern{
+	{label: "ignore_instructions", pattern: regexp.MustCompile(`(?i)\b(?:ignore|disregard|forget|override)\s+(?:all\s+|any\s+)?(?:of\s+)?(?:the\s+|your\s+)?(?:previous|prior|above|earlier|preceding|original)\s+(?:instructions|prompts?|rules|directions|context)`)},
+	{label: "system_prompt_extraction", pattern: regexp.MustCompile(`(?i)\b(?:reveal|print|show|repeat|output|leak)\s+(?:me\s+)?(?:your\s+|the\s+)?(?:system\s+prompt|hidden\s+instructions|initial\s+instructions|original\s+instructions)`)},
+	{label: "role_override", pattern: regexp.MustCompile(`(?i)\byou\s+are\s+now\s+(?:in\s+)?(?:an?\s+)?(?:unrestricted|unfiltered|jailbroken|developer\s+mode|DAN)\b|\b(?:enable|enter)\s+developer\s+mode\b|\bact\s+as\s+(?:an?\s+)?(?:unrestricted|unfiltered|jailbroken)\b`)},
+	{label: "role_delimiter", pattern: regexp.MustCompile(`(?im)^\s*(?:system|assistant)\s*:|<\|im_start\|>|\[/?INST\]`)},
+}
+
+// PromptInjectionGuard finds phrasings used to override a model's instructions, such as "ignore
+// previous instructions". It is a heuristic: rephrased attacks get through.
+type PromptInjectionGuard struct{}
+
+// Name is how configurations select the guard
+func (PromptInjectionGuard) Name() string {
+	return "prompt_injection"
+}
+
+// Inspect returns the injection attempts in text
+func (PromptInjectionGuard) Inspect(text string, config types.GuardConfig) []GuardMatch {
+	return patternMatches(text, promptInjectionPatterns)
+}
+
+// BannedTopicsGuard finds the configured topics as whole words, ignoring case
+type BannedTopicsGuard struct{}
+
+// Name is how configurations select the guard
+func (BannedTopicsGuard) Name() string {
+	return "banned_topics"
+}
+
+// Inspect returns the mentions of config.Topics in text, labelled with the topic
+func (BannedTopicsGuard) Inspect(text string, config types.GuardConfig) []GuardMatch {
+	var matches []GuardMatch
+	for _, topic := range config.Topics {
+		topic = strings.TrimSpace(topic)
+		if topic == "" {
+			continue
+		}
+		pattern := regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(topic) + `\b`)
+		for _, location := range pattern.FindAllStringIndex(text, -1) {
+			matches = append(matches, GuardMatch{Start: location[0], End: location[1], Label: strings.ToLower(topic)})
+		}
+	}
+	return matches
+}
+
+// storeGuardVerdicts records the verdicts of a request's guards
+func (c *Client) storeGuardVerdicts(ctx context.Context, userID string, request *types.APIRequest, verdicts []types.GuardVerdict) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	if len(verdicts) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, len(verdicts))
+	args := make([]interface{}, 0, len(verdicts)*11)
+	for i, verdict := range verdicts {
+		findings, err := types.ToJSON(append([]string{}, verdict.Findings...))
+		if err != nil {
+			return fmt.Errorf("failed to marshal guard findings: %w", err)
+		}
+		placeholders[i] = "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+		args = append(args, request.ID, i+1, userID, request.ExecutionRunID, request.ConfigurationID,
+			verdict.Guard, verdict.Stage, verdict.Action, verdict.Triggered, findings, request.CreatedAt)
+	}
+
+	_, err := c.db.ExecContext(ctx, `
+		INSERT INTO guard_verdicts (request_id, position, user_id, execution_run_id, configuration_id,
+			guard, stage, action, triggered, findings, created_at)
+		VALUES `+strings.Join(placeholders, ", "), args...)
+	if err != nil {
+		return fmt.Errorf("failed to store guard verdicts: %w", err)
+	}
+	return nil
+}
+
+// GetGuardVerdicts returns the guard verdicts of each request of a run owned by the user, keyed by
+// request ID and in the order the guards ran
+func (c *Client) GetGuardVerdicts(ctx context.Context, userID, executionRunID string) (map[string][]types.GuardVerdict, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT request_id, guard, stage, action, triggered, findings
+		FROM guard_verdicts
+		WHERE execution_run_id = ? AND user_id = ?
+		ORDER BY request_id ASC, position ASC
+	`, executionRunID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get guard verdicts: %w", err)
+	}
+	defer rows.Close()
+
+	verdicts := make(map[string][]types.GuardVerdict)
+	for rows.Next() {
+		var requestID, findings string
+		var verdict types.GuardVerdict
+		if err := rows.Scan(&requestID, &verdict.Guard, &verdict.Stage, &verdict.Action, &verdict.Triggered, &findings); err != nil {
+			return nil, fmt.Errorf("failed to scan guard verdict: %w", err)
+		}
+		if err := types.FromJSON(findings, &verdict.Findings); err != nil {
+			return nil, fmt.Errorf("failed to parse guard findings: %w", err)
+		}
+		verdicts[requestID] = append(verdicts[requestID], verdict)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate guard verdicts: %w", err)
+	}
+	return verdicts, nil
+}
+
+// storedGuardrails adds the guardrails to the generation config saved with a configuration, which
+// has no column of its own for them
+func storedGuardrails(config *types.APIConfiguration, generationConfig map[string]interface{}) map[string]interface{} {
+	if len(config.Guardrails) == 0 {
+		return generationConfig
+	}
+	stored := make(map[string]interface{}, len(generationConfig)+1)
+	for key, value := range generationConfig {
+		stored[key] = value
+	}
+	stored["guardrails"] = config.Guardrails
+	return stored
+}
+
+// loadGuardrails restores the guardrails from a saved generation config, removing them from the
+// generation config sent to providers
+func loadGuardrails(config *types.APIConfiguration, generationConfig json.RawMessage) {
+	var stored struct {
+		Guardrails []types.GuardConfig `json:"guardrails"`
+	}
+	if len(generationConfig) == 0 || json.Unmarshal(generationConfig, &stored) != nil || len(stored.Guardrails) == 0 {
+		return
+	}
+	config.Guardrails = stored.Guardrails
+	delete(config.GenerationConfig, "guardrails")
+}
@@ -0,0 +1,258 @@
+package gogent
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gogent/internal/types"
+)
+
+// Version is the service version reported by health checks
+const Version = "1.0.0"
+
+const (
+	// healthCheckTimeout bounds each dependency check
+	healthCheckTimeout = 2 * time.Second
+	// slowDatabasePing is the ping latency past which the database is reported degraded
+	slowDatabasePing = 500 * time.Millisecond
+	// providerHealthTTL is how long a provider check is reused, so frequent probes do not call the API
+	providerHealthTTL = time.Minute
+	// queueDegradedRatio is the share of the execution queue's capacity past which it is reported degraded
+	queueDegradedRatio = 0.9
+	// migrationsPath is the directory migrations are read from, relative to the working directory
+	migrationsPath = "migrations"
+)
+
+// cachedHealthCheck reuses a check's outcome until it is older than a TTL
+type cachedHealthCheck struct {
+	mutex sync.Mutex
+	check *types.HealthCheck
+}
+
+// get returns the cached check while it is younger than ttl, otherwise runs run and caches it
+func (c *cachedHealthCheck) get(ttl time.Duration, run func() types.HealthCheck) types.HealthCheck {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.check != nil && time.Since(c.check.CheckedAt) < ttl {
+		return *c.check
+	}
+	check := run()
+	c.check = &check
+	return check
+}
+
+// CheckHealth checks the database, migrations, the model provider, the circuit breakers, Neo4j
+// when it is configured and, when queue is not nil, the execution queue. The checks run at once,
+// each bounded by its own timeout, and the report takes the worst of their states.
+func (c *Client) CheckHealth(ctx context.Context, queue *ExecutionQueue) *types.HealthReport {
+	checks := []func(context.Context) types.HealthCheck{
+		c.checkDatabase,
+		func(ctx context.Context) types.HealthCheck { return c.checkMigrations(ctx, migrationsPath) },
+		c.checkProvider,
+		c.checkCircuitBreakers,
+	}
+	if c.config != nil && c.config.Neo4jURL != "" {
+		checks = append(checks, c.checkNeo4j)
+	}
+	if queue != nil {
+		checks = append(checks, func(context.Context) types.HealthCheck { return checkQueue(queue.Stats()) })
+	}
+
+	report := &types.HealthReport{
+		Status:    types.HealthHealthy,
+		Version:   Version,
+		Timestamp: time.Now(),
+		Checks:    make([]types.HealthCheck, len(checks)),
+	}
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+			report.Checks[i] = check(checkCtx)
+		}()
+	}
+	wg.Wait()
+
+	for _, check := range report.Checks {
+		report.Status = worseHealth(report.Status, check.Status)
+	}
+	return report
+}
+
+// worseHealth returns the worse of two health states
+func worseHealth(a, b string) string {
+	rank := map[string]int{types.HealthHealthy: 0, types.HealthDegraded: 1, types.HealthUnhealthy: 2}
+	if rank[b] > rank[a] {
+		return b
+	}
+	return a
+}
+
+// timedCheck runs check and records how long it took
+func timedCheck(name string, check func() (status, message string, details map[string]interface{})) types.HealthCheck {
+	start := time.Now()
+	status, message, details := check()
+	return types.HealthCheck{
+		Name:      name,
+		Status:    status,
+		LatencyMs: time.Since(start).Milliseconds(),
+		Message:   message,
+		Details:   details,
+		CheckedAt: start,
+	}
+}
+
+// checkDatabase pings the database and reports its connection pool
+func (c *Client) checkDatabase(ctx context.Context) types.HealthCheck {
+	return timedCheck("database", func() (string, string, map[string]interface{}) {
+		if c.db == nil {
+			return types.HealthUnhealthy, ErrNoDatabase.Error(), nil
+		}
+		start := time.Now()
+		if err := c.db.PingContext(ctx); err != nil {
+			return types.HealthUnhealthy, fmt.Sprintf("ping failed: %v", err), nil
+		}
+		ping := time.Since(start)
+
+		stats := c.db.Stats()
+		details := map[string]interface{}{
+			"openConnections": stats.OpenConnections,
+			"inUse":           stats.InUse,
+			"idle":            stats.Idle,
+			"waitCount":       stats.WaitCount,
+		}
+		if ping > slowDatabasePing {
+			return types.HealthDegraded, fmt.Sprintf("ping took %dms", ping.Milliseconds()), details
+		}
+		return types.HealthHealthy, "", details
+	})
+}
+
+// checkMigrations compares the schema version golang-migrate recorded with the newest migration in
+// dir. A dirty schema, left by a failed migration, is unhealthy; pending migrations are degraded.
+func (c *Client) checkMigrations(ctx context.Context, dir string) types.HealthCheck {
+	return timedCheck("migrations", func() (string, string, map[string]interface{}) {
+		if c.db == nil {
+			return types.HealthUnhealthy, ErrNoDatabase.Error(), nil
+		}
+		var version int64
+		var dirty bool
+		err := c.db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+		if errors.Is(err, sql.ErrNoRows) {
+			return types.HealthUnhealthy, "no migrations have been applied", nil
+		}
+		if err != nil {
+			return types.HealthUnhealthy, fmt.Sprintf("failed to read schema version: %v", err), nil
+		}
+
+		details := map[string]interface{}{"version": version, "dirty": dirty}
+		if dirty {
+			return types.HealthUnhealthy, fmt.Sprintf("migration %d failed and left the schema dirty", version), details
+		}
+		latest, err := latestMigrationVersion(dir)
+		if err != nil {
+			return types.HealthHealthy, fmt.Sprintf("schema at version %d; could not read migrations: %v", version, err), details
+		}
+		details["latest"] = latest
+		if version < latest {
+			return types.HealthDegraded, fmt.Sprintf("schema at version %d, %d is available", version, latest), details
+		}
+		return types.HealthHealthy, "", details
+	})
+}
+
+// latestMigrationVersion returns the highest version of the up migrations in dir
+func latestMigrationVersion(dir string) (int64, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.up.sql"))
+	if err != nil {
+		return 0, err
+	}
+	var latest int64
+	for _, file := range files {
+		prefix, _, _ := strings.Cut(filepath.Base(file), "_")
+		if version, err := strconv.ParseInt(prefix, 10, 64); err == nil && version > latest {
+			latest = version
+		}
+	}
+	if latest == 0 {
+		return 0, fmt.Errorf("no migrations in %s: %w", dir, os.ErrNotExist)
+	}
+	return latest, nil
+}
+
+// checkProvider lists one Gemini model to see that the API is reachable with the configured key.
+// The outcome is reused for providerHealthTTL.
+func (c *Client) checkProvider(ctx context.Context) types.HealthCheck {
+	if c.config == nil || c.config.APIKey == "" {
+		return timedCheck("provider", func() (string, string, map[string]interface{}) {
+			return types.HealthHealthy, "no Gemini API key configured; executions use session keys or mock responses", nil
+		})
+	}
+	return c.providerHealth.get(providerHealthTTL, func() types.HealthCheck {
+		return timedCheck("provider", func() (string, string, map[string]interface{}) {
+			if _, err := c.geminiAPI().ListModels(ctx, 1, ""); err != nil {
+				return types.HealthDegraded, fmt.Sprintf("Gemini API unreachable: %v", err), nil
+			}
+			return types.HealthHealthy, "", map[string]interface{}{"provider": "gemini"}
+		})
+	})
+}
+
+// checkCircuitBreakers reports the breaker of each host the client has seen fail; any open or
+// half-open breaker is degraded
+func (c *Client) checkCircuitBreakers(context.Context) types.HealthCheck {
+	return timedCheck("circuit_breakers", func() (string, string, map[string]interface{}) {
+		breakers := c.CircuitBreakers()
+		if breakers == nil {
+			return types.HealthHealthy, "", nil
+		}
+		details, failing := breakers.Status()
+		if len(failing) > 0 {
+			return types.HealthDegraded, "circuit breakers not closed: " + strings.Join(failing, ", "), details
+		}
+		return types.HealthHealthy, "", details
+	})
+}
+
+// checkNeo4j connects to the configured Neo4j database through the client's pool, which verifies
+// cached drivers at most every neo4jHealthCheckInterval
+func (c *Client) checkNeo4j(ctx context.Context) types.HealthCheck {
+	return timedCheck("neo4j", func() (string, string, map[string]interface{}) {
+		key := neo4jDriverKey{url: c.config.Neo4jURL, username: c.config.Neo4jUsername, password: c.config.Neo4jPassword}
+		if _, err := c.neo4jConnections().driver(ctx, key); err != nil {
+			return types.HealthDegraded, fmt.Sprintf("Neo4j unreachable: %v", err), nil
+		}
+		return types.HealthHealthy, "", nil
+	})
+}
+
+// checkQueue reports the execution queue's depth; a queue close to full is degraded
+func checkQueue(stats QueueStats) types.HealthCheck {
+	return timedCheck("queue", func() (string, string, map[string]interface{}) {
+		waiting := 0
+		for _, count := range stats.Waiting {
+			waiting += count
+		}
+		details := map[string]interface{}{
+			"workers":  stats.Workers,
+			"running":  stats.Running,
+			"waiting":  waiting,
+			"capacity": stats.Capacity,
+		}
+		if stats.Capacity > 0 && float64(waiting) >= queueDegradedRatio*float64(stats.Capacity) {
+			return types.HealthDegraded, fmt.Sprintf("%d of %d queue slots are taken", waiting, stats.Capacity), details
+		}
+		return types.HealthHealthy, "", details
+	})
+}
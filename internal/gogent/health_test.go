@@ -0,0 +1,121 @@
+package gogent
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"gogent/internal/types"
+)
+
+// modelsTransport answers Gemini list-models calls, failing them when fail is set
+type modelsTransport struct {
+	calls atomic.Int32
+	fail  bool
+}
+
+func (t *modelsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.calls.Add(1)
+	status, body := http.StatusOK, `{"models": [{"name": "models/gemini-2.0-flash"}]}`
+	if t.fail {
+		status, body = http.StatusServiceUnavailable, `{"error": {"message": "unavailable"}}`
+	}
+	return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header), Request: req}, nil
+}
+
+// healthCheckNamed returns the report's check with a name
+func healthCheckNamed(t *testing.T, report *types.HealthReport, name string) types.HealthCheck {
+	t.Helper()
+	for _, check := range report.Checks {
+		if check.Name == name {
+			return check
+		}
+	}
+	t.Fatalf("expected a %s check, got %+v", name, report.Checks)
+	return types.HealthCheck{}
+}
+
+func TestCheckHealth(t *testing.T) {
+	client, _ := newStoreTestClient(t)
+	ctx := context.Background()
+	if _, err := client.db.Exec(`CREATE TABLE schema_migrations (version INTEGER, dirty BOOLEAN); INSERT INTO schema_migrations VALUES (43, false)`); err != nil {
+		t.Fatalf("failed to create test schema: %v", err)
+	}
+	transport := &modelsTransport{}
+	client.config.APIKey = "test-key"
+	client.SetHTTPTransport(transport)
+	queue := NewExecutionQueue(1, 10)
+	t.Cleanup(func() { queue.Shutdown(ctx) })
+
+	report := client.CheckHealth(ctx, queue)
+	if len(report.Checks) != 5 || report.Version != Version {
+		t.Fatalf("expected the database, migrations, provider, circuit breaker and queue checks, got %+v", report)
+	}
+	if check := healthCheckNamed(t, report, "database"); check.Status != types.HealthHealthy || check.Details["openConnections"] == nil {
+		t.Errorf("expected a healthy database with pool details, got %+v", check)
+	}
+	if check := healthCheckNamed(t, report, "provider"); check.Status != types.HealthHealthy {
+		t.Errorf("expected a reachable provider, got %+v", check)
+	}
+	if check := healthCheckNamed(t, report, "queue"); check.Status != types.HealthHealthy || check.Details["capacity"] != 10 {
+		t.Errorf("expected an empty queue, got %+v", check)
+	}
+	// The tests run without the migrations directory, so the version can't be compared
+	if check := healthCheckNamed(t, report, "migrations"); check.Status != types.HealthHealthy || check.Details["version"] != int64(43) {
+		t.Errorf("expected the schema version, got %+v", check)
+	}
+
+	// The provider check is served from cache, so an outage shows once it expires
+	transport.fail = true
+	report = client.CheckHealth(ctx, nil)
+	if transport.calls.Load() != 1 || healthCheckNamed(t, report, "provider").Status != types.HealthHealthy {
+		t.Errorf("expected the cached provider check, got %d calls", transport.calls.Load())
+	}
+	client.providerHealth.check.CheckedAt = client.providerHealth.check.CheckedAt.Add(-providerHealthTTL)
+	report = client.CheckHealth(ctx, nil)
+	if check := healthCheckNamed(t, report, "provider"); check.Status != types.HealthDegraded || report.Status != types.HealthDegraded {
+		t.Errorf("expected an unreachable provider to degrade the service, got %+v", report)
+	}
+
+	// A dirty schema makes the service unhealthy
+	client.db.Exec(`UPDATE schema_migrations SET dirty = true`)
+	if report = client.CheckHealth(ctx, nil); report.Status != types.HealthUnhealthy {
+		t.Errorf("expected a dirty schema to be unhealthy, got %+v", healthCheckNamed(t, report, "migrations"))
+	}
+}
+
+func TestCheckMigrations(t *testing.T) {
+	client, _ := newStoreTestClient(t)
+	ctx := context.Background()
+	if _, err := client.db.Exec(`CREATE TABLE schema_migrations (version INTEGER, dirty BOOLEAN); INSERT INTO schema_migrations VALUES (2, false)`); err != nil {
+		t.Fatalf("failed to create test schema: %v", err)
+	}
+	dir := t.TempDir()
+	for _, name := range []string{"000001_init.up.sql", "000001_init.down.sql", "000002_logs.up.sql", "000003_tags.up.sql"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatalf("failed to write migration: %v", err)
+		}
+	}
+
+	if check := client.checkMigrations(ctx, dir); check.Status != types.HealthDegraded || check.Details["latest"] != int64(3) {
+		t.Errorf("expected a pending migration, got %+v", check)
+	}
+	client.db.Exec(`UPDATE schema_migrations SET version = 3`)
+	if check := client.checkMigrations(ctx, dir); check.Status != types.HealthHealthy {
+		t.Errorf("expected an up to date schema, got %+v", check)
+	}
+}
+
+func TestCheckQueue(t *testing.T) {
+	if check := checkQueue(QueueStats{Workers: 2, Capacity: 10, Waiting: map[string]int{"high": 4, "normal": 5}}); check.Status != types.HealthDegraded {
+		t.Errorf("expected a nearly full queue to be degraded, got %+v", check)
+	}
+	if check := checkQueue(QueueStats{Workers: 2, Running: 2, Capacity: 10, Waiting: map[string]int{"normal": 3}}); check.Status != types.HealthHealthy || check.Details["waiting"] != 3 {
+		t.Errorf("expected a healthy queue, got %+v", check)
+	}
+}
@@ -0,0 +1,83 @@
+package gogent
+
+import (
+	"context"
+	"fmt"
+
+	"gogent/internal/types"
+)
+
+// integrityCheck finds the rows of a table whose reference points at a missing parent row. Nullable
+// references are repaired by clearing them; rows that cannot exist without their parent are deleted.
+type integrityCheck struct {
+	table, column string
+	parent        string // Table the column references by ID
+	nullable      bool
+}
+
+// integrityChecks are the references VerifyIntegrity checks, parents before children, so rows
+// orphaned by an earlier repair are caught by a later check
+var integrityChecks = []integrityCheck{
+	{table: "api_configurations", column: "execution_run_id", parent: "execution_runs"},
+	{table: "api_requests", column: "execution_run_id", parent: "execution_runs"},
+	{table: "api_requests", column: "configuration_id", parent: "api_configurations"},
+	{table: "api_responses", column: "request_id", parent: "api_requests"},
+	{table: "function_calls", column: "request_id", parent: "api_requests"},
+	{table: "execution_logs", column: "execution_run_id", parent: "execution_runs"},
+	{table: "execution_logs", column: "configuration_id", parent: "api_configurations", nullable: true},
+	{table: "execution_logs", column: "request_id", parent: "api_requests", nullable: true},
+	{table: "retrieved_chunks", column: "request_id", parent: "api_requests"},
+	{table: "guard_verdicts", column: "request_id", parent: "api_requests"},
+	{table: "raw_payloads", column: "request_id", parent: "api_requests"},
+	{table: "comparison_results", column: "execution_run_id", parent: "execution_runs"},
+	{table: "comparison_results", column: "best_configuration_id", parent: "api_configurations", nullable: true},
+}
+
+// orphans returns the condition selecting the check's orphaned rows
+func (check integrityCheck) orphans() string {
+	return fmt.Sprintf("%s IS NOT NULL AND %s NOT IN (SELECT id FROM %s)", check.column, check.column, check.parent)
+}
+
+// VerifyIntegrity counts the rows of the execution history whose references point at missing rows:
+// responses without requests, configurations without runs, function calls of missing requests and
+// the like. With repair set it also fixes them in one transaction, clearing optional references and
+// deleting rows that need their parent.
+func (c *Client) VerifyIntegrity(ctx context.Context, repair bool) (*types.IntegrityReport, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	report := &types.IntegrityReport{Repaired: repair}
+	for _, check := range integrityChecks {
+		result := types.IntegrityCheck{Table: check.table, Column: check.column, References: check.parent, Action: "delete"}
+		if check.nullable {
+			result.Action = "clear"
+		}
+		if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+check.table+" WHERE "+check.orphans()).Scan(&result.Orphans); err != nil {
+			return nil, fmt.Errorf("failed to check %s.%s: %w", check.table, check.column, err)
+		}
+		if repair && result.Orphans > 0 {
+			statement := "DELETE FROM " + check.table + " WHERE " + check.orphans()
+			if check.nullable {
+				statement = "UPDATE " + check.table + " SET " + check.column + " = NULL WHERE " + check.orphans()
+			}
+			if _, err := tx.ExecContext(ctx, statement); err != nil {
+				return nil, fmt.Errorf("failed to repair %s.%s: %w", check.table, check.column, err)
+			}
+		}
+		report.Orphans += result.Orphans
+		report.Checks = append(report.Checks, result)
+	}
+
+	if repair {
+		if err := tx.Commit(); err != nil {
+			return nil, fmt.Errorf("failed to commit repair: %w", err)
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,72 @@
+package gogent
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"gogent/internal/testdb"
+)
+
+func TestVerifyIntegrity(t *testing.T) {
+	database := testdb.Open(t)
+
+	_, err := database.Exec(`
+		INSERT INTO execution_runs (id) VALUES ('run-1');
+		INSERT INTO api_configurations (id, execution_run_id) VALUES ('config-1', 'run-1'), ('config-orphan', 'run-gone');
+		INSERT INTO api_requests (id, execution_run_id, configuration_id) VALUES ('request-1', 'run-1', 'config-1'), ('request-orphan', 'run-1', 'config-orphan');
+		INSERT INTO api_responses (id, request_id) VALUES ('response-1', 'request-1'), ('response-orphan', 'request-orphan');
+		INSERT INTO function_calls (id, request_id) VALUES ('call-1', 'request-1'), ('call-test', NULL), ('call-orphan', 'request-gone');
+		INSERT INTO execution_logs (id, execution_run_id, configuration_id, request_id) VALUES ('log-1', 'run-1', 'config-1', NULL), ('log-cleared', 'run-1', 'config-1', 'request-gone');
+		INSERT INTO comparison_results (id, execution_run_id, best_configuration_id) VALUES ('comparison-1', 'run-1', 'config-gone');
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+	client := &Client{db: database}
+	ctx := context.Background()
+
+	report, err := client.VerifyIntegrity(ctx, false)
+	if err != nil {
+		t.Fatalf("failed to verify integrity: %v", err)
+	}
+	// Without repair, rows only orphaned once their parent is deleted are not counted
+	if report.Orphans != 4 || report.Repaired {
+		t.Errorf("expected four orphans and no repair, got %+v", report)
+	}
+	var configurations int
+	database.QueryRow("SELECT COUNT(*) FROM api_configurations").Scan(&configurations)
+	if configurations != 2 {
+		t.Fatalf("expected verifying alone to change nothing, got %d configurations", configurations)
+	}
+
+	report, err = client.VerifyIntegrity(ctx, true)
+	if err != nil {
+		t.Fatalf("failed to repair integrity: %v", err)
+	}
+	// Deleting the orphaned configuration orphans its request, and the request its response
+	if report.Orphans != 6 || !report.Repaired {
+		t.Errorf("expected six repaired orphans, got %+v", report)
+	}
+	for _, check := range report.Checks {
+		if check.Table == "execution_logs" && check.Column == "request_id" && (check.Orphans != 1 || check.Action != "clear") {
+			t.Errorf("expected the log's missing request to be cleared, got %+v", check)
+		}
+	}
+	var logRequest sql.NullString
+	database.QueryRow("SELECT request_id FROM execution_logs WHERE id = 'log-cleared'").Scan(&logRequest)
+	var calls, responses int
+	database.QueryRow("SELECT COUNT(*) FROM function_calls").Scan(&calls)
+	database.QueryRow("SELECT COUNT(*) FROM api_responses").Scan(&responses)
+	if logRequest.Valid || calls != 2 || responses != 1 {
+		t.Errorf("expected the log kept without its request and the orphans deleted, got %v, %d calls, %d responses", logRequest, calls, responses)
+	}
+
+	if report, err := client.VerifyIntegrity(ctx, false); err != nil || report.Orphans != 0 {
+		t.Errorf("expected no orphans after the repair, got %+v, %v", report, err)
+	}
+	if _, err := (&Client{}).VerifyIntegrity(ctx, false); !errors.Is(err, ErrNoDatabase) {
+		t.Errorf("expected ErrNoDatabase, got %v", err)
+	}
+}
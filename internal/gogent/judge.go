@@ -0,0 +1,237 @@
+package gogent
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"gogent/internal/types"
+
+	"github.com/google/uuid"
+)
+
+// ErrJudgeBudgetExceeded is returned when a judge call would push a run past its MaxCostUSD
+var ErrJudgeBudgetExceeded = errors.New("judge cost budget exceeded")
+
+const (
+	// defaultJudgeModel grades responses when a JudgeConfig names no model
+	defaultJudgeModel = "gemini-1.5-flash"
+	// defaultJudgeCriteria is what the judge grades when a JudgeConfig sets no criteria
+	defaultJudgeCriteria = "accuracy, relevance to the prompt, and clarity"
+	// defaultJudgeWeight is the share of the overall score taken by the judge score
+	defaultJudgeWeight = 0.5
+	// judgeMaxOutputTokens caps the judge's reply and bounds its cost estimate
+	judgeMaxOutputTokens = 256
+	// maxJudgedResponseChars truncates long responses before they are sent to the judge
+	maxJudgedResponseChars = 8000
+)
+
+// judgeCaller sends a prompt to the judge model; compareResults passes callGeminiAPI
+type judgeCaller func(ctx context.Context, config *types.APIConfiguration, request *types.APIRequest) (*types.APIResponse, error)
+
+// ValidateJudgeConfig checks a judge configuration and fills in the default model, criteria and weight
+func ValidateJudgeConfig(judge *types.JudgeConfig) error {
+	if judge.MaxCostUSD < 0 {
+		return fmt.Errorf("judge maxCostUsd must not be negative")
+	}
+	if judge.Weight < 0 || judge.Weight > 1 {
+		return fmt.Errorf("judge weight must be between 0 and 1")
+	}
+	if judge.Model == "" {
+		judge.Model = defaultJudgeModel
+	}
+	if judge.Criteria == "" {
+		judge.Criteria = defaultJudgeCriteria
+	}
+	if judge.Weight == 0 {
+		judge.Weight = defaultJudgeWeight
+	}
+	return nil
+}
+
+// JudgmentHash identifies a judgment by the judge model, criteria, prompt and response it grades
+func JudgmentHash(judge *types.JudgeConfig, prompt, response string) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{judge.Model, judge.Criteria, prompt, response}, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// buildJudgePrompt asks the judge to grade a response and reply with a JSON score and rationale
+func buildJudgePrompt(criteria, prompt, response string) string {
+	if len(response) > maxJudgedResponseChars {
+		response = response[:maxJudgedResponseChars] + "\n[truncated]"
+	}
+	return fmt.Sprintf(`You are grading an AI model's response. Grade it on %s.
+
+PROMPT:
+%s
+
+RESPONSE:
+%s
+
+Reply with only a JSON object of the form {"score": <integer from 0 to 10>, "rationale": "<one sentence>"}.`,
+		criteria, prompt, response)
+}
+
+// parseJudgment extracts the score, normalized to 0-1, and rationale from a judge reply
+func parseJudgment(text string) (float64, string, error) {
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start < 0 || end <= start {
+		return 0, "", fmt.Errorf("judge reply has no JSON object")
+	}
+
+	var reply struct {
+		Score     *float64 `json:"score"`
+		Rationale string   `json:"rationale"`
+	}
+	if err := json.Unmarshal([]byte(text[start:end+1]), &reply); err != nil {
+		return 0, "", fmt.Errorf("failed to parse judge reply: %w", err)
+	}
+	if reply.Score == nil {
+		return 0, "", fmt.Errorf("judge reply has no score")
+	}
+	return math.Max(0, math.Min(*reply.Score, 10)) / 10, reply.Rationale, nil
+}
+
+// estimateJudgeCostUSD bounds the cost of a judge call before it is made, assuming ~4 characters per token
+func estimateJudgeCostUSD(model, judgePrompt string) float64 {
+	return EstimateCostUSD(model, len(judgePrompt)/4, judgeMaxOutputTokens)
+}
+
+// GetCachedJudgment returns a stored judgment for a response hash, or nil when there is none
+func (c *Client) GetCachedJudgment(ctx context.Context, userID, responseHash string) (*types.Judgment, error) {
+	if c.db == nil {
+		return nil, nil
+	}
+	var judgment types.Judgment
+	var rationale sql.NullString
+	err := c.db.QueryRowContext(ctx, `
+		SELECT response_hash, judge_model, score, rationale, cost_usd, created_at
+		FROM judgments WHERE user_id = ? AND response_hash = ?
+	`, userID, responseHash).Scan(&judgment.ResponseHash, &judgment.JudgeModel, &judgment.Score,
+		&rationale, &judgment.CostUSD, &judgment.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cached judgment: %w", err)
+	}
+	judgment.Rationale = rationale.String
+	judgment.Cached = true
+	return &judgment, nil
+}
+
+// storeJudgment caches a judgment, replacing any earlier one for the same hash
+func (c *Client) storeJudgment(ctx context.Context, userID string, judgment *types.Judgment) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	_, err := c.db.ExecContext(ctx, `
+		REPLACE INTO judgments (user_id, response_hash, judge_model, score, rationale, cost_usd, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, userID, judgment.ResponseHash, judgment.JudgeModel, judgment.Score,
+		nullableString(judgment.Rationale), judgment.CostUSD, judgment.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to store judgment: %w", err)
+	}
+	return nil
+}
+
+// judgeRun tracks a run's judge spend against its budget
+type judgeRun struct {
+	client  *Client
+	userID  string
+	config  *types.JudgeConfig
+	call    judgeCaller
+	spent   float64
+	skipped int
+}
+
+// judge grades one response, reusing a cached judgment when one exists and refusing calls past the budget
+func (j *judgeRun) judge(ctx context.Context, prompt, response string) (*types.Judgment, error) {
+	hash := JudgmentHash(j.config, prompt, response)
+	if !j.config.DisableCache {
+		cached, err := j.client.GetCachedJudgment(ctx, j.userID, hash)
+		if err != nil {
+			return nil, err
+		}
+		if cached != nil {
+			return cached, nil
+		}
+	}
+
+	judgePrompt := buildJudgePrompt(j.config.Criteria, prompt, response)
+	if j.config.MaxCostUSD > 0 && j.spent+estimateJudgeCostUSD(j.config.Model, judgePrompt) > j.config.MaxCostUSD {
+		j.skipped++
+		return nil, ErrJudgeBudgetExceeded
+	}
+
+	temperature := float32(0)
+	maxTokens := int32(judgeMaxOutputTokens)
+	config := &types.APIConfiguration{
+		ID:          uuid.New().String(),
+		ModelName:   j.config.Model,
+		Temperature: &temperature,
+		MaxTokens:   &maxTokens,
+	}
+	request := &types.APIRequest{
+		ID:          uuid.New().String(),
+		RequestType: types.RequestTypeGenerate,
+		Prompt:      judgePrompt,
+		CreatedAt:   time.Now(),
+	}
+	reply, err := j.call(ctx, config, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call judge model: %w", err)
+	}
+
+	// Count the spend even when the reply cannot be parsed
+	cost := ResponseCostUSD(j.config.Model, *reply)
+	j.spent += cost
+
+	score, rationale, err := parseJudgment(reply.ResponseText)
+	if err != nil {
+		return nil, err
+	}
+	judgment := &types.Judgment{
+		ResponseHash: hash,
+		JudgeModel:   j.config.Model,
+		Score:        score,
+		Rationale:    rationale,
+		CostUSD:      cost,
+		CreatedAt:    time.Now(),
+	}
+	if err := j.client.storeJudgment(ctx, j.userID, judgment); err != nil {
+		log.Printf("⚠️ Warning: %v", err)
+	}
+	return judgment, nil
+}
+
+// judgeResults grades each successful variation, keyed by resultKey; failed and over-budget variations are left out
+func (c *Client) judgeResults(ctx context.Context, userID string, result *types.ExecutionResult, judge *types.JudgeConfig, call judgeCaller) (map[string]*types.Judgment, *judgeRun) {
+	run := &judgeRun{client: c, userID: userID, config: judge, call: call}
+	judgments := make(map[string]*types.Judgment)
+	for _, r := range result.Results {
+		if r.Response.ResponseStatus != types.ResponseStatusSuccess || r.Response.ResponseText == "" {
+			continue
+		}
+		judgment, err := run.judge(ctx, r.Request.Prompt, r.Response.ResponseText)
+		if errors.Is(err, ErrJudgeBudgetExceeded) {
+			continue
+		}
+		if err != nil {
+			log.Printf("⚠️ Warning: failed to judge %s: %v", r.Configuration.VariationName, err)
+			continue
+		}
+		judgments[resultKey(r)] = judgment
+	}
+	return judgments, run
+}
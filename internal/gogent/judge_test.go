@@ -0,0 +1,156 @@
+package gogent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"gogent/internal/testdb"
+	"gogent/internal/types"
+)
+
+// newJudgeTestClient returns a client backed by an in-memory judgments table
+func newJudgeTestClient(t *testing.T) *Client {
+	return &Client{db: testdb.Open(t)}
+}
+
+// fakeJudge replies with a fixed score and counts its calls
+type fakeJudge struct {
+	score int
+	calls int
+}
+
+func (f *fakeJudge) call(ctx context.Context, config *types.APIConfiguration, request *types.APIRequest) (*types.APIResponse, error) {
+	f.calls++
+	return &types.APIResponse{
+		ResponseStatus: types.ResponseStatusSuccess,
+		ResponseText:   fmt.Sprintf("```json\n{\"score\": %d, \"rationale\": \"looks right\"}\n```", f.score),
+		UsageMetadata: map[string]interface{}{
+			"prompt_tokens":     1000,
+			"completion_tokens": 50,
+		},
+	}, nil
+}
+
+// newJudgeTestResult builds a run result with one successful variation per response
+func newJudgeTestResult(responses ...string) *types.ExecutionResult {
+	result := &types.ExecutionResult{}
+	for i, response := range responses {
+		result.Results = append(result.Results, types.VariationResult{
+			Configuration: types.APIConfiguration{ID: fmt.Sprintf("config-%d", i), VariationName: fmt.Sprintf("v%d", i)},
+			Request:       types.APIRequest{Prompt: "What is the capital of France?"},
+			Response:      types.APIResponse{ResponseStatus: types.ResponseStatusSuccess, ResponseText: response},
+		})
+	}
+	return result
+}
+
+func TestParseJudgment(t *testing.T) {
+	score, rationale, err := parseJudgment(`Here you go: {"score": 7, "rationale": "mostly correct"}`)
+	if err != nil || score != 0.7 || rationale != "mostly correct" {
+		t.Errorf("expected 0.7 and a rationale, got %v, %q, %v", score, rationale, err)
+	}
+	if score, _, _ := parseJudgment(`{"score": 14}`); score != 1 {
+		t.Errorf("expected scores above 10 to be clamped, got %v", score)
+	}
+	for _, text := range []string{"Mock response", `{"rationale": "no score"}`, `{"score": "high"}`} {
+		if _, _, err := parseJudgment(text); err == nil {
+			t.Errorf("expected an error for %q", text)
+		}
+	}
+}
+
+func TestValidateJudgeConfig(t *testing.T) {
+	judge := &types.JudgeConfig{}
+	if err := ValidateJudgeConfig(judge); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if judge.Model != defaultJudgeModel || judge.Criteria != defaultJudgeCriteria || judge.Weight != defaultJudgeWeight {
+		t.Errorf("expected defaults to be filled in, got %+v", judge)
+	}
+
+	for _, judge := range []*types.JudgeConfig{{MaxCostUSD: -1}, {Weight: 1.5}} {
+		if err := ValidateJudgeConfig(judge); err == nil {
+			t.Errorf("expected an error for %+v", judge)
+		}
+	}
+}
+
+func TestJudgeResults(t *testing.T) {
+	client := newJudgeTestClient(t)
+	ctx := context.Background()
+	judge := &types.JudgeConfig{}
+	ValidateJudgeConfig(judge)
+
+	result := newJudgeTestResult("Paris.", "The capital of France is Paris.")
+	result.Results = append(result.Results, types.VariationResult{
+		Configuration: types.APIConfiguration{ID: "failed"},
+		Response:      types.APIResponse{ResponseStatus: types.ResponseStatusError},
+	})
+
+	fake := &fakeJudge{score: 9}
+	judgments, run := client.judgeResults(ctx, "user-1", result, judge, fake.call)
+	if fake.calls != 2 || len(judgments) != 2 || judgments["failed"] != nil {
+		t.Fatalf("expected the two successful variations to be judged, got %d calls and %+v", fake.calls, judgments)
+	}
+	if judgments["config-0"].Score != 0.9 || judgments["config-0"].Cached {
+		t.Errorf("expected a fresh 0.9 judgment, got %+v", judgments["config-0"])
+	}
+	if expected := 2 * EstimateCostUSD(defaultJudgeModel, 1000, 50); run.spent != expected {
+		t.Errorf("expected spend %.6f, got %.6f", expected, run.spent)
+	}
+
+	t.Run("reused_across_recomputations", func(t *testing.T) {
+		fake := &fakeJudge{score: 2}
+		judgments, run := client.judgeResults(ctx, "user-1", result, judge, fake.call)
+		if fake.calls != 0 || run.spent != 0 {
+			t.Errorf("expected cached judgments without judge calls, got %d calls", fake.calls)
+		}
+		if !judgments["config-1"].Cached || judgments["config-1"].Score != 0.9 {
+			t.Errorf("expected the cached 0.9 judgment, got %+v", judgments["config-1"])
+		}
+	})
+
+	t.Run("cache_keyed_by_criteria", func(t *testing.T) {
+		stricter := *judge
+		stricter.Criteria = "factual accuracy only"
+		fake := &fakeJudge{score: 5}
+		client.judgeResults(ctx, "user-1", result, &stricter, fake.call)
+		if fake.calls != 2 {
+			t.Errorf("expected new criteria to miss the cache, got %d calls", fake.calls)
+		}
+	})
+
+	t.Run("disable_cache", func(t *testing.T) {
+		uncached := *judge
+		uncached.DisableCache = true
+		fake := &fakeJudge{score: 4}
+		judgments, _ := client.judgeResults(ctx, "user-1", result, &uncached, fake.call)
+		if fake.calls != 2 || judgments["config-0"].Score != 0.4 {
+			t.Errorf("expected fresh judgments, got %d calls and %+v", fake.calls, judgments["config-0"])
+		}
+	})
+
+	t.Run("scoped_to_user", func(t *testing.T) {
+		fake := &fakeJudge{score: 6}
+		client.judgeResults(ctx, "user-2", result, judge, fake.call)
+		if fake.calls != 2 {
+			t.Errorf("expected another user's judgments not to be reused, got %d calls", fake.calls)
+		}
+	})
+
+	t.Run("budget", func(t *testing.T) {
+		budgeted := *judge
+		budgeted.Criteria = "brevity"
+		// Enough for one call's estimate but not a second
+		judgePrompt := buildJudgePrompt(budgeted.Criteria, "What is the capital of France?", strings.Repeat("x", 400))
+		budgeted.MaxCostUSD = 1.5 * estimateJudgeCostUSD(budgeted.Model, judgePrompt)
+
+		fake := &fakeJudge{score: 8}
+		judgments, run := client.judgeResults(ctx, "user-1", newJudgeTestResult(strings.Repeat("x", 400), strings.Repeat("y", 400)), &budgeted, fake.call)
+		if fake.calls != 1 || len(judgments) != 1 || run.skipped != 1 {
+			t.Errorf("expected one judged and one skipped variation, got %d calls, %d judgments, %d skipped", fake.calls, len(judgments), run.skipped)
+		}
+	})
+}
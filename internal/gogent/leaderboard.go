@@ -0,0 +1,182 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gogent/internal/types"
+)
+
+// ErrInvalidLeaderboardQuery is returned when a leaderboard query has no valid tag or an out of range limit
+var ErrInvalidLeaderboardQuery = errors.New("invalid leaderboard query")
+
+const (
+	// MaxRunTags caps how many tags a run may have
+	MaxRunTags = 10
+	// defaultLeaderboardLimit is how many configurations a leaderboard lists when the query sets no limit
+	defaultLeaderboardLimit = 10
+	// maxLeaderboardLimit caps how many configurations a leaderboard may list
+	maxLeaderboardLimit = 100
+)
+
+// tagPattern matches a normalized tag: lowercase letters, digits, dots, dashes and underscores
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,63}$`)
+
+// normalizeTag lowercases a tag and trims its surrounding whitespace
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// normalizeTags returns the distinct non-empty normalized tags, sorted
+func normalizeTags(tags []string) []string {
+	var normalized []string
+	for _, tag := range tags {
+		if tag = normalizeTag(tag); tag != "" && !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	slices.Sort(normalized)
+	return normalized
+}
+
+// ValidateTags rejects more than MaxRunTags tags and tags that, once lowercased, are not made of
+// letters, digits, dots, dashes and underscores
+func ValidateTags(tags []string) error {
+	normalized := normalizeTags(tags)
+	if len(normalized) > MaxRunTags {
+		return fmt.Errorf("at most %d tags are allowed, got %d", MaxRunTags, len(normalized))
+	}
+	for _, tag := range normalized {
+		if !tagPattern.MatchString(tag) {
+			return fmt.Errorf("invalid tag %q: use letters, digits, dots, dashes and underscores (max 64), starting with a letter or digit", tag)
+		}
+	}
+	return nil
+}
+
+// recordRunTags stores the tags of an execution run
+func (c *Client) recordRunTags(ctx context.Context, userID, executionRunID string, tags []string) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	for _, tag := range tags {
+		_, err := c.db.ExecContext(ctx,
+			"INSERT INTO execution_run_tags (execution_run_id, tag, user_id) VALUES (?, ?, ?)",
+			executionRunID, tag, userID)
+		if err != nil {
+			return fmt.Errorf("failed to record tag %q: %w", tag, err)
+		}
+	}
+	return nil
+}
+
+// loadRunTags fills in the tags of an execution run
+func (c *Client) loadRunTags(ctx context.Context, run *types.ExecutionRun) error {
+	if c.db == nil {
+		return nil
+	}
+	rows, err := c.db.QueryContext(ctx, "SELECT tag FROM execution_run_tags WHERE execution_run_id = ? ORDER BY tag", run.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load run tags: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return fmt.Errorf("failed to scan run tag: %w", err)
+		}
+		run.Tags = append(run.Tags, tag)
+	}
+	return rows.Err()
+}
+
+// GetLeaderboard ranks the configurations of the user's runs with a tag by their average overall
+// score, best first. Scores come from each run's latest comparison and are aggregated in SQL;
+// configurations are matched across runs by variation and model name.
+func (c *Client) GetLeaderboard(ctx context.Context, userID string, query types.LeaderboardQuery) (*types.Leaderboard, error) {
+	query.Tag = normalizeTag(query.Tag)
+	if !tagPattern.MatchString(query.Tag) {
+		return nil, fmt.Errorf("%w: invalid tag %q", ErrInvalidLeaderboardQuery, query.Tag)
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultLeaderboardLimit
+	}
+	if query.Limit < 1 || query.Limit > maxLeaderboardLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d, got %d", ErrInvalidLeaderboardQuery, maxLeaderboardLimit, query.Limit)
+	}
+	if query.MinSamples < 0 {
+		return nil, fmt.Errorf("%w: minimum samples must not be negative, got %d", ErrInvalidLeaderboardQuery, query.MinSamples)
+	}
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+
+	// Each tagged run's latest comparison scores its configurations once, however many samples they had
+	scores := `
+		SELECT c.variation_name, c.model_name, c.execution_run_id,
+			CAST(JSON_EXTRACT(cr.configuration_scores, CONCAT('$."', c.variation_name, '".overall_score')) AS DOUBLE) AS score
+		FROM execution_run_tags t
+		JOIN api_configurations c ON c.execution_run_id = t.execution_run_id
+		JOIN comparison_results cr ON cr.execution_run_id = t.execution_run_id
+		WHERE t.user_id = ? AND t.tag = ?
+			AND cr.created_at = (
+				SELECT MAX(latest.created_at) FROM comparison_results latest
+				WHERE latest.execution_run_id = t.execution_run_id)`
+
+	leaderboard := &types.Leaderboard{Tag: query.Tag, Entries: []types.LeaderboardEntry{}}
+	err := c.db.QueryRowContext(ctx, `SELECT COUNT(DISTINCT execution_run_id) FROM (`+scores+`) scores`,
+		userID, query.Tag).Scan(&leaderboard.Runs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count leaderboard runs: %w", err)
+	}
+
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT variation_name, model_name, COUNT(*), AVG(score), SUM(score * score)
+		FROM (`+scores+`) scores
+		WHERE score IS NOT NULL
+		GROUP BY variation_name, model_name
+		HAVING COUNT(*) >= ?
+		ORDER BY AVG(score) DESC, COUNT(*) DESC, variation_name ASC, model_name ASC
+		LIMIT ?`, userID, query.Tag, max(query.MinSamples, 1), query.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leaderboard: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry types.LeaderboardEntry
+		var samples int
+		var mean, squares float64
+		if err := rows.Scan(&entry.VariationName, &entry.ModelName, &samples, &mean, &squares); err != nil {
+			return nil, fmt.Errorf("failed to scan leaderboard entry: %w", err)
+		}
+		entry.Rank = len(leaderboard.Entries) + 1
+		entry.Score = statisticsFromSums(samples, mean, squares)
+		leaderboard.Entries = append(leaderboard.Entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate leaderboard entries: %w", err)
+	}
+	return leaderboard, nil
+}
+
+// statisticsFromSums summarizes samples from their count, mean and sum of squares, as SQL
+// aggregates them, with the same confidence interval as SummarizeSamples
+func statisticsFromSums(samples int, mean, squares float64) types.MetricStatistics {
+	stats := types.MetricStatistics{Samples: samples, Mean: mean, CILower: mean, CIUpper: mean}
+	if samples < 2 {
+		return stats
+	}
+	// Rounding can leave a tiny negative variance when every sample is equal
+	variance := max((squares-float64(samples)*mean*mean)/float64(samples-1), 0)
+	stats.StdDev = math.Sqrt(variance)
+	margin := studentTCritical(confidenceLevel, float64(samples-1)) * stats.StdDev / math.Sqrt(float64(samples))
+	stats.CILower, stats.CIUpper = mean-margin, mean+margin
+	return stats
+}
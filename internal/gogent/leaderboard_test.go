@@ -0,0 +1,132 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"gogent/internal/types"
+)
+
+// newLeaderboardTestClient returns a trend test client with a run tag table
+func newLeaderboardTestClient(t *testing.T) *Client {
+	client := newTrendTestClient(t)
+	return client
+}
+
+// addLeaderboardRun stores a tagged run whose comparison gives each model's configuration a score
+func addLeaderboardRun(t *testing.T, client *Client, userID, run, tag string, scores map[string]float64) {
+	exec := func(query string, args ...interface{}) {
+		if _, err := client.db.Exec(query, args...); err != nil {
+			t.Fatalf("failed to store test run: %v", err)
+		}
+	}
+
+	exec("INSERT INTO execution_runs (id, user_id, name, created_at) VALUES (?, ?, ?, CURRENT_TIMESTAMP)", run, userID, run)
+	exec("INSERT INTO execution_run_tags (execution_run_id, tag, user_id) VALUES (?, ?, ?)", run, tag, userID)
+	configurationScores := map[string]interface{}{}
+	for model, score := range scores {
+		exec("INSERT INTO api_configurations (id, user_id, execution_run_id, variation_name, model_name) VALUES (?, ?, ?, ?, ?)",
+			run+"-"+model, userID, run, "terse "+model, model)
+		configurationScores["terse "+model] = map[string]interface{}{"overall_score": score}
+	}
+	encoded, err := types.ToJSON(configurationScores)
+	if err != nil {
+		t.Fatalf("failed to encode scores: %v", err)
+	}
+	exec("INSERT INTO comparison_results (id, execution_run_id, configuration_scores, created_at) VALUES (?, ?, ?, '2025-06-01 00:00:00')",
+		"comparison-"+run, run, encoded)
+}
+
+func TestGetLeaderboard(t *testing.T) {
+	client := newLeaderboardTestClient(t)
+	ctx := context.Background()
+
+	addLeaderboardRun(t, client, "user-1", "run-1", "summarization", map[string]float64{"flash": 0.6, "pro": 0.9})
+	addLeaderboardRun(t, client, "user-1", "run-2", "summarization", map[string]float64{"flash": 0.8, "pro": 0.7})
+	addLeaderboardRun(t, client, "user-1", "run-3", "summarization", map[string]float64{"flash": 0.7, "pro": 0.8})
+	addLeaderboardRun(t, client, "user-1", "run-4", "summarization", map[string]float64{"lite": 1})
+	addLeaderboardRun(t, client, "user-1", "run-5", "translation", map[string]float64{"flash": 0.1})
+	addLeaderboardRun(t, client, "user-2", "run-6", "summarization", map[string]float64{"flash": 0.1})
+
+	// A later comparison replaces the earlier one of the same run
+	if _, err := client.db.Exec(`INSERT INTO comparison_results (id, execution_run_id, configuration_scores, created_at)
+		VALUES ('comparison-run-3-recompared', 'run-3', '{"terse flash": {"overall_score": 0.9}}', '2025-06-02 00:00:00')`); err != nil {
+		t.Fatalf("failed to store recomparison: %v", err)
+	}
+
+	leaderboard, err := client.GetLeaderboard(ctx, "user-1", types.LeaderboardQuery{Tag: " Summarization "})
+	if err != nil {
+		t.Fatalf("failed to get leaderboard: %v", err)
+	}
+	if leaderboard.Tag != "summarization" || leaderboard.Runs != 4 || len(leaderboard.Entries) != 3 {
+		t.Fatalf("unexpected leaderboard: %+v", leaderboard)
+	}
+
+	lite, pro, flash := leaderboard.Entries[0], leaderboard.Entries[1], leaderboard.Entries[2]
+	if lite.ModelName != "lite" || lite.Rank != 1 || lite.Score.Samples != 1 || lite.Score.CILower != 1 {
+		t.Errorf("expected the single lite run first, got %+v", lite)
+	}
+	// run-3's recomparison did not score pro, so only its first two runs count
+	if pro.ModelName != "pro" || pro.Rank != 2 || pro.Score.Samples != 2 || math.Abs(pro.Score.Mean-0.8) > 1e-9 {
+		t.Errorf("expected pro second over 2 runs, got %+v", pro)
+	}
+	want := SummarizeSamples([]float64{0.6, 0.8, 0.9})
+	if flash.ModelName != "flash" || flash.Rank != 3 || flash.Score.Samples != 3 || math.Abs(flash.Score.Mean-want.Mean) > 1e-9 ||
+		math.Abs(flash.Score.StdDev-want.StdDev) > 1e-9 || math.Abs(flash.Score.CIUpper-want.CIUpper) > 1e-9 {
+		t.Errorf("expected flash third with the recompared score and %+v, got %+v", want, flash.Score)
+	}
+
+	leaderboard, err = client.GetLeaderboard(ctx, "user-1", types.LeaderboardQuery{Tag: "summarization", MinSamples: 3})
+	if err != nil || len(leaderboard.Entries) != 1 || leaderboard.Entries[0].ModelName != "flash" || leaderboard.Entries[0].Rank != 1 {
+		t.Errorf("expected only flash to have 3 samples, got %+v, %v", leaderboard, err)
+	}
+	leaderboard, err = client.GetLeaderboard(ctx, "user-1", types.LeaderboardQuery{Tag: "classification"})
+	if err != nil || leaderboard.Runs != 0 || len(leaderboard.Entries) != 0 {
+		t.Errorf("expected an empty leaderboard for an unused tag, got %+v, %v", leaderboard, err)
+	}
+}
+
+func TestGetLeaderboardInvalidQuery(t *testing.T) {
+	client := newLeaderboardTestClient(t)
+	for _, query := range []types.LeaderboardQuery{{}, {Tag: "two words"}, {Tag: "summarization", Limit: 1000}, {Tag: "summarization", MinSamples: -1}} {
+		if _, err := client.GetLeaderboard(context.Background(), "user-1", query); !errors.Is(err, ErrInvalidLeaderboardQuery) {
+			t.Errorf("expected %+v to be rejected, got %v", query, err)
+		}
+	}
+}
+
+func TestValidateTags(t *testing.T) {
+	if got := normalizeTags([]string{"Summarization", " qa ", "summarization", ""}); fmt.Sprint(got) != "[qa summarization]" {
+		t.Errorf("expected sorted distinct lowercase tags, got %v", got)
+	}
+	if err := ValidateTags([]string{"Summarization", "long-form", "v1.2_beta"}); err != nil {
+		t.Errorf("expected valid tags, got %v", err)
+	}
+	if err := ValidateTags([]string{"two words"}); err == nil {
+		t.Errorf("expected a tag with a space to be rejected")
+	}
+	tooMany := make([]string, MaxRunTags+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("tag-%d", i)
+	}
+	if err := ValidateTags(tooMany); err == nil {
+		t.Errorf("expected more than %d tags to be rejected", MaxRunTags)
+	}
+}
+
+func TestRunTags(t *testing.T) {
+	client := newLeaderboardTestClient(t)
+	ctx := context.Background()
+	addLeaderboardRun(t, client, "user-1", "run-1", "qa", nil)
+
+	if err := client.recordRunTags(ctx, "user-1", "run-1", []string{"summarization"}); err != nil {
+		t.Fatalf("failed to record tags: %v", err)
+	}
+	run := &types.ExecutionRun{ID: "run-1"}
+	if err := client.loadRunTags(ctx, run); err != nil || fmt.Sprint(run.Tags) != "[qa summarization]" {
+		t.Errorf("expected the run's tags in order, got %v, %v", run.Tags, err)
+	}
+}
@@ -0,0 +1,144 @@
+package gogent
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"gogent/internal/types"
+)
+
+const (
+	// logWriterQueueSize is how many entries wait to be written before logging blocks
+	logWriterQueueSize = 1024
+	// logWriterBatchSize caps the entries written in one insert
+	logWriterBatchSize = 100
+	// logWriterFlushInterval is the longest an entry waits for its batch to fill
+	logWriterFlushInterval = 200 * time.Millisecond
+)
+
+// logWriterOp is an entry to write or, when flushed is set, a request to write everything queued
+// before it and close flushed once it is stored
+type logWriterOp struct {
+	entry   *types.ExecutionLog
+	flushed chan struct{}
+}
+
+// executionLogWriter stores execution log entries in batches from a background goroutine, so
+// logging during a run does not wait on a database insert per entry. The queue is bounded: when
+// the store falls behind, logging blocks until there is room again.
+type executionLogWriter struct {
+	write func(ctx context.Context, entries []*types.ExecutionLog) error
+	ops   chan logWriterOp
+	done  chan struct{}
+	// mutex guards closed; writers hold it for reading so close waits for sends in progress
+	mutex  sync.RWMutex
+	closed bool
+}
+
+// newExecutionLogWriter starts a writer that stores batches with write
+func newExecutionLogWriter(write func(ctx context.Context, entries []*types.ExecutionLog) error) *executionLogWriter {
+	w := &executionLogWriter{
+		write: write,
+		ops:   make(chan logWriterOp, logWriterQueueSize),
+		done:  make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// executionLogs returns the client's log writer, starting it on first use
+func (c *Client) executionLogs() *executionLogWriter {
+	c.logWriterOnce.Do(func() {
+		c.logWriter = newExecutionLogWriter(func(ctx context.Context, entries []*types.ExecutionLog) error {
+			return c.store.CreateExecutionLogs(ctx, entries)
+		})
+	})
+	return c.logWriter
+}
+
+// enqueue queues an entry to be written. Entries logged after close are written straight away.
+func (w *executionLogWriter) enqueue(entry *types.ExecutionLog) {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	if w.closed {
+		w.store([]*types.ExecutionLog{entry})
+		return
+	}
+	w.ops <- logWriterOp{entry: entry}
+}
+
+// flush returns once every entry queued before it has been written
+func (w *executionLogWriter) flush() {
+	w.mutex.RLock()
+	if w.closed {
+		w.mutex.RUnlock()
+		return
+	}
+	flushed := make(chan struct{})
+	w.ops <- logWriterOp{flushed: flushed}
+	w.mutex.RUnlock()
+	<-flushed
+}
+
+// close writes the queued entries and stops the writer
+func (w *executionLogWriter) close() {
+	w.mutex.Lock()
+	if w.closed {
+		w.mutex.Unlock()
+		return
+	}
+	w.closed = true
+	close(w.ops)
+	w.mutex.Unlock()
+	<-w.done
+}
+
+// run batches queued entries, writing a batch when it is full, when logWriterFlushInterval has
+// passed since its first entry, on a flush and when the writer is closed
+func (w *executionLogWriter) run() {
+	defer close(w.done)
+
+	batch := make([]*types.ExecutionLog, 0, logWriterBatchSize)
+	timer := time.NewTimer(logWriterFlushInterval)
+	timer.Stop()
+	writeBatch := func() {
+		timer.Stop()
+		if len(batch) > 0 {
+			w.store(batch)
+			batch = make([]*types.ExecutionLog, 0, logWriterBatchSize)
+		}
+	}
+
+	for {
+		select {
+		case op, ok := <-w.ops:
+			if !ok {
+				writeBatch()
+				return
+			}
+			if op.flushed != nil {
+				writeBatch()
+				close(op.flushed)
+				continue
+			}
+			batch = append(batch, op.entry)
+			if len(batch) == 1 {
+				timer.Reset(logWriterFlushInterval)
+			}
+			if len(batch) >= logWriterBatchSize {
+				writeBatch()
+			}
+		case <-timer.C:
+			writeBatch()
+		}
+	}
+}
+
+// store writes a batch, logging rather than returning failures since logging must not fail a run
+func (w *executionLogWriter) store(entries []*types.ExecutionLog) {
+	if err := w.write(context.Background(), entries); err != nil {
+		log.Printf("❌ Failed to store %d execution logs: %v", len(entries), err)
+	}
+}
@@ -0,0 +1,106 @@
+package gogent
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"gogent/internal/testdb"
+	"gogent/internal/types"
+)
+
+func TestExecutionLogWriter(t *testing.T) {
+	var mutex sync.Mutex
+	var batches [][]*types.ExecutionLog
+	writer := newExecutionLogWriter(func(ctx context.Context, entries []*types.ExecutionLog) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		batches = append(batches, entries)
+		return nil
+	})
+	written := func() (entries, count int) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		for _, batch := range batches {
+			entries += len(batch)
+		}
+		return entries, len(batches)
+	}
+
+	for i := 0; i < logWriterBatchSize+5; i++ {
+		writer.enqueue(&types.ExecutionLog{ID: "entry"})
+	}
+	writer.flush()
+	if entries, count := written(); entries != logWriterBatchSize+5 || count != 2 {
+		t.Fatalf("expected a full batch and the rest on flush, got %d entries in %d batches", entries, count)
+	}
+
+	// An entry is written once the flush interval passes without a flush
+	writer.enqueue(&types.ExecutionLog{ID: "late"})
+	deadline := time.Now().Add(10 * logWriterFlushInterval)
+	for entries, _ := written(); entries != logWriterBatchSize+6; entries, _ = written() {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the entry to be written after %v", logWriterFlushInterval)
+		}
+		time.Sleep(logWriterFlushInterval / 4)
+	}
+
+	writer.enqueue(&types.ExecutionLog{ID: "last"})
+	writer.close()
+	writer.enqueue(&types.ExecutionLog{ID: "after close"})
+	writer.flush()
+	if entries, _ := written(); entries != logWriterBatchSize+8 {
+		t.Errorf("expected entries queued before and logged after close to be written, got %d", entries)
+	}
+}
+
+func TestExecutionLogsStoredByRunEnd(t *testing.T) {
+	client, store := newStoreTestClient(t)
+	ctx := context.Background()
+
+	run, err := client.CreateExecutionRun(ctx, "user-1", "Logged run", "", false)
+	if err != nil {
+		t.Fatalf("failed to create run: %v", err)
+	}
+	client.logExecutionEvent(withExecutionScope(ctx, executionScope{executionRunID: run.ID}), types.LogLevelInfo, types.LogCategorySetup, "starting", nil)
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("failed to close client: %v", err)
+	}
+	if logs, _ := store.ListExecutionLogs(ctx, "user-1", run.ID); len(logs) != 1 || logs[0].Message != "starting" {
+		t.Errorf("expected the queued entry to be stored on close, got %+v", logs)
+	}
+}
+
+func TestSQLStoreCreateExecutionLogs(t *testing.T) {
+	database := testdb.Open(t)
+
+	_, err := database.Exec(`
+		INSERT INTO execution_runs (id, user_id) VALUES ('run-1', 'user-1');
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+
+	store := NewSQLStore(database)
+	ctx := context.Background()
+	configID := "config-1"
+	now := time.Now().UTC().Truncate(time.Second)
+	err = store.CreateExecutionLogs(ctx, []*types.ExecutionLog{
+		{ID: "b", ExecutionRunID: "run-1", LogLevel: types.LogLevelInfo, LogCategory: types.LogCategorySetup, Message: "second", Timestamp: now.Add(time.Second)},
+		{ID: "a", ExecutionRunID: "run-1", ConfigurationID: &configID, LogLevel: types.LogLevelWarn, LogCategory: types.LogCategoryAPICall,
+			Message: "first", Details: map[string]interface{}{"step": 1}, Timestamp: now},
+	})
+	if err != nil {
+		t.Fatalf("failed to store logs: %v", err)
+	}
+
+	logs, err := store.QueryExecutionLogs(ctx, "user-1", "run-1", ExecutionLogFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(logs) != 2 || logs[0].ID != "a" || *logs[0].ConfigurationID != configID || logs[0].Details["step"] != float64(1) || logs[1].Message != "second" {
+		t.Errorf("expected both entries ordered by the time they were logged, got %+v", logs)
+	}
+}
@@ -0,0 +1,478 @@
+package gogent
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"gogent/internal/types"
+)
+
+// MemoryStore keeps execution records in process. It backs NewInMemoryClient and suits tests and
+// short-lived tools; records are lost when the process exits.
+type MemoryStore struct {
+	mutex          sync.RWMutex
+	runs           map[string]types.ExecutionRun
+	configurations []types.APIConfiguration
+	functionTools  map[string][]types.Tool
+	requests       []types.APIRequest
+	responses      []types.APIResponse
+	logs           []types.ExecutionLog
+	functionCalls  []types.FunctionCall
+	comparisons    map[string]types.ComparisonResult
+	// owners maps configuration, request and response IDs to the user that stored them
+	owners map[string]string
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		runs:          make(map[string]types.ExecutionRun),
+		functionTools: make(map[string][]types.Tool),
+		comparisons:   make(map[string]types.ComparisonResult),
+		owners:        make(map[string]string),
+	}
+}
+
+// CreateExecutionRun stores a run
+func (s *MemoryStore) CreateExecutionRun(ctx context.Context, run *types.ExecutionRun) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, exists := s.runs[run.ID]; exists {
+		return fmt.Errorf("execution run %s already exists", run.ID)
+	}
+	s.runs[run.ID] = *run
+	return nil
+}
+
+// GetExecutionRun returns a run owned by the user
+func (s *MemoryStore) GetExecutionRun(ctx context.Context, userID, id string) (*types.ExecutionRun, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	run, ok := s.runs[id]
+	if !ok || run.UserID != userID {
+		return nil, fmt.Errorf("execution run %s: %w", id, sql.ErrNoRows)
+	}
+	return &run, nil
+}
+
+// UpdateExecutionRunStatus records a run's status and error message
+func (s *MemoryStore) UpdateExecutionRunStatus(ctx context.Context, id, status, errorMessage string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	run, ok := s.runs[id]
+	if !ok {
+		return fmt.Errorf("execution run %s: %w", id, sql.ErrNoRows)
+	}
+	run.Status = status
+	run.ErrorMessage = errorMessage
+	run.UpdatedAt = time.Now()
+	s.runs[id] = run
+	return nil
+}
+
+// ownsRun reports whether the user stored the run; callers hold the mutex
+func (s *MemoryStore) ownsRun(userID, executionRunID string) bool {
+	run, ok := s.runs[executionRunID]
+	return ok && run.UserID == userID
+}
+
+// ListExecutionRuns lists a user's runs, newest first
+func (s *MemoryStore) ListExecutionRuns(ctx context.Context, userID string, limit, offset int32) ([]*types.ExecutionRun, error) {
+	return s.listRuns(func(run types.ExecutionRun) bool { return run.UserID == userID }, limit, offset), nil
+}
+
+// ListAllExecutionRuns lists runs across every user, newest first
+func (s *MemoryStore) ListAllExecutionRuns(ctx context.Context, limit, offset int32) ([]*types.ExecutionRun, error) {
+	return s.listRuns(func(types.ExecutionRun) bool { return true }, limit, offset), nil
+}
+
+// CountExecutionRuns counts a user's runs
+func (s *MemoryStore) CountExecutionRuns(ctx context.Context, userID string) (int64, error) {
+	return s.countRuns(func(run types.ExecutionRun) bool { return run.UserID == userID }), nil
+}
+
+// CountAllExecutionRuns counts runs across every user
+func (s *MemoryStore) CountAllExecutionRuns(ctx context.Context) (int64, error) {
+	return s.countRuns(func(types.ExecutionRun) bool { return true }), nil
+}
+
+// countRuns counts the runs that match
+func (s *MemoryStore) countRuns(match func(types.ExecutionRun) bool) int64 {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var count int64
+	for _, run := range s.runs {
+		if match(run) {
+			count++
+		}
+	}
+	return count
+}
+
+// listRuns returns a page of the runs that match, newest first
+func (s *MemoryStore) listRuns(match func(types.ExecutionRun) bool, limit, offset int32) []*types.ExecutionRun {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var runs []*types.ExecutionRun
+	for _, run := range s.runs {
+		if match(run) {
+			run := run
+			runs = append(runs, &run)
+		}
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].CreatedAt.After(runs[j].CreatedAt) })
+	start, end := pageBounds(len(runs), limit, offset)
+	return runs[start:end]
+}
+
+// CreateAPIConfiguration stores a configuration
+func (s *MemoryStore) CreateAPIConfiguration(ctx context.Context, userID string, config *types.APIConfiguration) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.configurations = append(s.configurations, *config)
+	s.owners[config.ID] = userID
+	return nil
+}
+
+// ListAPIConfigurationsByRun returns a run's configurations in the order they were stored
+func (s *MemoryStore) ListAPIConfigurationsByRun(ctx context.Context, userID, executionRunID string) ([]types.APIConfiguration, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	configs := make([]types.APIConfiguration, 0)
+	for _, config := range s.configurations {
+		if s.owners[config.ID] == userID && config.ExecutionRunID == executionRunID {
+			configs = append(configs, config)
+		}
+	}
+	return configs, nil
+}
+
+// ListAPIConfigurations lists a user's configurations across runs, newest first
+func (s *MemoryStore) ListAPIConfigurations(ctx context.Context, userID string, limit, offset int32) ([]types.APIConfiguration, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	configs := make([]types.APIConfiguration, 0)
+	for i := len(s.configurations) - 1; i >= 0; i-- {
+		if s.owners[s.configurations[i].ID] == userID {
+			configs = append(configs, s.configurations[i])
+		}
+	}
+	start, end := pageBounds(len(configs), limit, offset)
+	return configs[start:end], nil
+}
+
+// CreateRunFunctionTools stores the tools a run exposed
+func (s *MemoryStore) CreateRunFunctionTools(ctx context.Context, userID, executionRunID string, tools []types.Tool) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.functionTools[executionRunID] = append(s.functionTools[executionRunID], tools...)
+	return nil
+}
+
+// ListRunFunctionTools returns the tools a run owned by the user exposed
+func (s *MemoryStore) ListRunFunctionTools(ctx context.Context, userID, executionRunID string) ([]types.Tool, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if !s.ownsRun(userID, executionRunID) {
+		return []types.Tool{}, nil
+	}
+	return append([]types.Tool{}, s.functionTools[executionRunID]...), nil
+}
+
+// CreateAPIRequest stores a request
+func (s *MemoryStore) CreateAPIRequest(ctx context.Context, userID string, request *types.APIRequest) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.addAPIRequest(userID, *request)
+	return nil
+}
+
+// addAPIRequest stores a request; the caller holds the write lock
+func (s *MemoryStore) addAPIRequest(userID string, request types.APIRequest) {
+	s.requests = append(s.requests, request)
+	s.owners[request.ID] = userID
+}
+
+// ListAPIRequestsByRun returns a run's requests in the order they were stored
+func (s *MemoryStore) ListAPIRequestsByRun(ctx context.Context, userID, executionRunID string) ([]types.APIRequest, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	requests := make([]types.APIRequest, 0)
+	for _, request := range s.requests {
+		if s.owners[request.ID] == userID && request.ExecutionRunID == executionRunID {
+			requests = append(requests, request)
+		}
+	}
+	return requests, nil
+}
+
+// CreateAPIResponse stores a response
+func (s *MemoryStore) CreateAPIResponse(ctx context.Context, userID string, response *types.APIResponse) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.addAPIResponse(userID, *response)
+	return nil
+}
+
+// addAPIResponse stores a response; the caller holds the write lock
+func (s *MemoryStore) addAPIResponse(userID string, response types.APIResponse) {
+	s.responses = append(s.responses, response)
+	s.owners[response.ID] = userID
+}
+
+// ListAPIResponsesByRun returns the responses to a run's requests in the order they were stored
+func (s *MemoryStore) ListAPIResponsesByRun(ctx context.Context, userID, executionRunID string) ([]types.APIResponse, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	runRequests := make(map[string]bool)
+	for _, request := range s.requests {
+		if request.ExecutionRunID == executionRunID {
+			runRequests[request.ID] = true
+		}
+	}
+
+	responses := make([]types.APIResponse, 0)
+	for _, response := range s.responses {
+		if s.owners[response.ID] == userID && runRequests[response.RequestID] {
+			responses = append(responses, response)
+		}
+	}
+	return responses, nil
+}
+
+// CreateExecutionLog stores a log entry
+func (s *MemoryStore) CreateExecutionLog(ctx context.Context, entry *types.ExecutionLog) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.logs = append(s.logs, *entry)
+	return nil
+}
+
+// CreateExecutionLogs stores a batch of log entries
+func (s *MemoryStore) CreateExecutionLogs(ctx context.Context, entries []*types.ExecutionLog) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, entry := range entries {
+		s.logs = append(s.logs, *entry)
+	}
+	return nil
+}
+
+// ListExecutionLogs returns the log entries of a run owned by the user in the order they were stored
+func (s *MemoryStore) ListExecutionLogs(ctx context.Context, userID, executionRunID string) ([]types.ExecutionLog, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	logs := make([]types.ExecutionLog, 0)
+	if !s.ownsRun(userID, executionRunID) {
+		return logs, nil
+	}
+	for _, entry := range s.logs {
+		if entry.ExecutionRunID == executionRunID {
+			logs = append(logs, entry)
+		}
+	}
+	return logs, nil
+}
+
+// QueryExecutionLogs returns a page of the log entries of a run owned by the user that the filter
+// matches, in the order they were stored
+func (s *MemoryStore) QueryExecutionLogs(ctx context.Context, userID, executionRunID string, filter ExecutionLogFilter) ([]types.ExecutionLog, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	logs := make([]types.ExecutionLog, 0)
+	if !s.ownsRun(userID, executionRunID) {
+		return logs, nil
+	}
+	started := filter.AfterID == ""
+	for _, entry := range s.logs {
+		if entry.ExecutionRunID != executionRunID {
+			continue
+		}
+		if !started {
+			started = entry.ID == filter.AfterID
+			continue
+		}
+		if filter.matches(entry) && (filter.Limit <= 0 || int32(len(logs)) < filter.Limit) {
+			logs = append(logs, entry)
+		}
+	}
+	if !started {
+		return nil, ErrUnknownLogEntry
+	}
+	return logs, nil
+}
+
+// CreateFunctionCall stores a function call
+func (s *MemoryStore) CreateFunctionCall(ctx context.Context, call *types.FunctionCall) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.functionCalls = append(s.functionCalls, *call)
+	return nil
+}
+
+// InTransaction runs fn against a store that holds back the requests, responses and function calls
+// fn writes, adding them together when fn succeeds and dropping them when it fails. Other records
+// are stored straight away.
+func (s *MemoryStore) InTransaction(ctx context.Context, fn func(Store) error) error {
+	tx := &memoryTransaction{MemoryStore: s}
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, write := range tx.writes {
+		write(s)
+	}
+	return nil
+}
+
+// memoryTransaction is the store a MemoryStore transaction writes through
+type memoryTransaction struct {
+	*MemoryStore
+	writes []func(*MemoryStore)
+}
+
+func (t *memoryTransaction) InTransaction(ctx context.Context, fn func(Store) error) error {
+	return fn(t)
+}
+
+func (t *memoryTransaction) CreateAPIRequest(ctx context.Context, userID string, request *types.APIRequest) error {
+	staged := *request
+	t.writes = append(t.writes, func(s *MemoryStore) { s.addAPIRequest(userID, staged) })
+	return nil
+}
+
+func (t *memoryTransaction) CreateAPIResponse(ctx context.Context, userID string, response *types.APIResponse) error {
+	staged := *response
+	t.writes = append(t.writes, func(s *MemoryStore) { s.addAPIResponse(userID, staged) })
+	return nil
+}
+
+func (t *memoryTransaction) CreateFunctionCall(ctx context.Context, call *types.FunctionCall) error {
+	staged := *call
+	t.writes = append(t.writes, func(s *MemoryStore) { s.functionCalls = append(s.functionCalls, staged) })
+	return nil
+}
+
+// FunctionCalls returns the function calls made for a request
+func (s *MemoryStore) FunctionCalls(requestID string) []types.FunctionCall {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var calls []types.FunctionCall
+	for _, call := range s.functionCalls {
+		if call.RequestID == requestID {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// CreateComparisonResult stores a run's comparison, replacing any earlier one
+func (s *MemoryStore) CreateComparisonResult(ctx context.Context, comparison *types.ComparisonResult) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.comparisons[comparison.ExecutionRunID] = *comparison
+	return nil
+}
+
+// GetComparisonResult returns the comparison of a run owned by the user
+func (s *MemoryStore) GetComparisonResult(ctx context.Context, userID, executionRunID string) (*types.ComparisonResult, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	comparison, ok := s.comparisons[executionRunID]
+	if !ok || !s.ownsRun(userID, executionRunID) {
+		return nil, fmt.Errorf("comparison for execution run %s: %w", executionRunID, sql.ErrNoRows)
+	}
+	return &comparison, nil
+}
+
+// UpdateComparisonScores replaces the scores and best configuration of a run's comparison
+func (s *MemoryStore) UpdateComparisonScores(ctx context.Context, comparison *types.ComparisonResult) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stored, ok := s.comparisons[comparison.ExecutionRunID]
+	if !ok || stored.ID != comparison.ID {
+		return fmt.Errorf("comparison %s: %w", comparison.ID, sql.ErrNoRows)
+	}
+	stored.ConfigurationScores = comparison.ConfigurationScores
+	stored.BestConfigurationID = comparison.BestConfigurationID
+	stored.BestConfiguration = comparison.BestConfiguration
+	s.comparisons[comparison.ExecutionRunID] = stored
+	return nil
+}
+
+// ListComparisonResults returns a page of the comparisons of the user's runs, newest first
+func (s *MemoryStore) ListComparisonResults(ctx context.Context, userID string, limit, offset int32) ([]*types.ComparisonResult, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	comparisons := make([]*types.ComparisonResult, 0, len(s.comparisons))
+	for _, comparison := range s.comparisons {
+		if !s.ownsRun(userID, comparison.ExecutionRunID) {
+			continue
+		}
+		comparison := comparison
+		comparisons = append(comparisons, &comparison)
+	}
+	sort.Slice(comparisons, func(i, j int) bool { return comparisons[i].CreatedAt.After(comparisons[j].CreatedAt) })
+	start, end := pageBounds(len(comparisons), limit, offset)
+	return comparisons[start:end], nil
+}
+
+// CountComparisonResults counts the comparisons of the user's runs
+func (s *MemoryStore) CountComparisonResults(ctx context.Context, userID string) (int64, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var count int64
+	for _, comparison := range s.comparisons {
+		if s.ownsRun(userID, comparison.ExecutionRunID) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// pageBounds returns the slice bounds of a page of a list; a limit of zero or less runs to the end
+func pageBounds(length int, limit, offset int32) (int, int) {
+	start := int(offset)
+	if start < 0 {
+		start = 0
+	}
+	if start > length {
+		start = length
+	}
+	end := length
+	if limit > 0 && start+int(limit) < length {
+		end = start + int(limit)
+	}
+	return start, end
+}
@@ -0,0 +1,209 @@
+package gogent
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"gogent/internal/gemini"
+	"gogent/internal/types"
+)
+
+// modelCatalogTTL is how long a fetched model catalog is served before it is fetched again
+const modelCatalogTTL = 24 * time.Hour
+
+// modelListFunc fetches the provider's model catalog; ListModels passes fetchGeminiModels
+type modelListFunc func(ctx context.Context) ([]types.ModelInfo, error)
+
+// mockModelCatalog lists current Gemini models and is served when no API key is configured
+var mockModelCatalog = []types.ModelInfo{
+	{Name: "gemini-1.5-flash", DisplayName: "Gemini 1.5 Flash", InputTokenLimit: 1048576, OutputTokenLimit: 8192,
+		SupportedMethods: []string{"generateContent", "countTokens"}},
+	{Name: "gemini-1.5-pro", DisplayName: "Gemini 1.5 Pro", InputTokenLimit: 2097152, OutputTokenLimit: 8192,
+		SupportedMethods: []string{"generateContent", "countTokens"}},
+	{Name: "gemini-2.0-flash", DisplayName: "Gemini 2.0 Flash", InputTokenLimit: 1048576, OutputTokenLimit: 8192,
+		SupportedMethods: []string{"generateContent", "countTokens"}},
+	{Name: "gemini-2.5-flash", DisplayName: "Gemini 2.5 Flash", InputTokenLimit: 1048576, OutputTokenLimit: 65536,
+		SupportedMethods: []string{"generateContent", "countTokens"}},
+	{Name: "gemini-2.5-pro", DisplayName: "Gemini 2.5 Pro", InputTokenLimit: 1048576, OutputTokenLimit: 65536,
+		SupportedMethods: []string{"generateContent", "countTokens"}},
+	{Name: defaultEmbeddingModel, DisplayName: "Text Embedding 004", InputTokenLimit: 2048, OutputTokenLimit: 1,
+		SupportedMethods: []string{"embedContent"}},
+}
+
+// ListModels returns the provider's model catalog, fetching it again once the stored copy is older
+// than a day. A stale catalog is served when the provider can't be reached.
+func (c *Client) ListModels(ctx context.Context) ([]types.ModelInfo, error) {
+	return c.listModels(ctx, false, c.modelLister())
+}
+
+// RefreshModels fetches the provider's model catalog and replaces the stored copy
+func (c *Client) RefreshModels(ctx context.Context) ([]types.ModelInfo, error) {
+	return c.listModels(ctx, true, c.modelLister())
+}
+
+// modelLister picks the Gemini catalog, or the mock catalog without an API key
+func (c *Client) modelLister() modelListFunc {
+	if c.config == nil || c.config.APIKey == "" {
+		return func(ctx context.Context) ([]types.ModelInfo, error) {
+			return append([]types.ModelInfo(nil), mockModelCatalog...), nil
+		}
+	}
+	return c.fetchGeminiModels
+}
+
+// listModels serves the stored catalog while it is fresh, otherwise fetches and stores a new one
+func (c *Client) listModels(ctx context.Context, refresh bool, fetch modelListFunc) ([]types.ModelInfo, error) {
+	stored, err := c.storedModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !refresh && len(stored) > 0 && time.Since(stored[0].CreatedAt) < modelCatalogTTL {
+		return stored, nil
+	}
+
+	models, err := fetch(ctx)
+	if err != nil {
+		if len(stored) > 0 {
+			log.Printf("⚠️ Warning: serving stale model catalog: %v", err)
+			return stored, nil
+		}
+		return nil, fmt.Errorf("failed to list models: %w", err)
+	}
+
+	sort.Slice(models, func(i, j int) bool { return models[i].Name < models[j].Name })
+	fetchedAt := time.Now()
+	for i := range models {
+		models[i].CreatedAt = fetchedAt
+	}
+	if err := c.storeModels(ctx, models, fetchedAt); err != nil {
+		log.Printf("⚠️ Warning: %v", err)
+	}
+	return models, nil
+}
+
+// storedModels returns the stored catalog ordered by name
+func (c *Client) storedModels(ctx context.Context) ([]types.ModelInfo, error) {
+	if c.db == nil {
+		return nil, nil
+	}
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT name, display_name, description, version, input_token_limit, output_token_limit,
+		       supported_methods, fetched_at
+		FROM model_catalog
+		ORDER BY fetched_at ASC, name ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get model catalog: %w", err)
+	}
+	defer rows.Close()
+
+	var models []types.ModelInfo
+	for rows.Next() {
+		var m types.ModelInfo
+		var description, version sql.NullString
+		var methodsJSON string
+		if err := rows.Scan(&m.Name, &m.DisplayName, &description, &version, &m.InputTokenLimit, &m.OutputTokenLimit,
+			&methodsJSON, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan model: %w", err)
+		}
+		if err := types.FromJSON(methodsJSON, &m.SupportedMethods); err != nil {
+			return nil, fmt.Errorf("failed to parse supported methods of %s: %w", m.Name, err)
+		}
+		m.Description = description.String
+		m.Version = version.String
+		models = append(models, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate model catalog: %w", err)
+	}
+	return models, nil
+}
+
+// storeModels replaces the stored catalog with a freshly fetched one
+func (c *Client) storeModels(ctx context.Context, models []types.ModelInfo, fetchedAt time.Time) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM model_catalog`); err != nil {
+		return fmt.Errorf("failed to clear model catalog: %w", err)
+	}
+	for _, m := range models {
+		methods, err := types.ToJSON(m.SupportedMethods)
+		if err != nil {
+			return fmt.Errorf("failed to marshal supported methods of %s: %w", m.Name, err)
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO model_catalog (name, display_name, description, version, input_token_limit,
+				output_token_limit, supported_methods, fetched_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`, m.Name, m.DisplayName, nullableString(m.Description), nullableString(m.Version), m.InputTokenLimit,
+			m.OutputTokenLimit, methods, fetchedAt)
+		if err != nil {
+			return fmt.Errorf("failed to store model %s: %w", m.Name, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to store model catalog: %w", err)
+	}
+	return nil
+}
+
+// fetchGeminiModels pages through the Gemini models endpoint
+func (c *Client) fetchGeminiModels(ctx context.Context) ([]types.ModelInfo, error) {
+	var models []types.ModelInfo
+	pageToken := ""
+	for {
+		page, err := c.geminiAPI().ListModels(ctx, 1000, pageToken)
+		if err != nil {
+			return nil, err
+		}
+		models = append(models, convertGeminiModels(page.Models)...)
+		if page.NextPageToken == "" {
+			return models, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+// convertGeminiModels converts listed Gemini models, dropping the "models/" name prefix
+func convertGeminiModels(listed []gemini.Model) []types.ModelInfo {
+	models := make([]types.ModelInfo, 0, len(listed))
+	for _, m := range listed {
+		models = append(models, types.ModelInfo{
+			Name:             strings.TrimPrefix(m.Name, "models/"),
+			DisplayName:      m.DisplayName,
+			Description:      m.Description,
+			Version:          m.Version,
+			InputTokenLimit:  m.InputTokenLimit,
+			OutputTokenLimit: m.OutputTokenLimit,
+			SupportedMethods: m.SupportedGenerationMethods,
+		})
+	}
+	return models
+}
+
+// ModelsSupporting filters a catalog to the models that support a method, such as generateContent
+func ModelsSupporting(models []types.ModelInfo, method string) []types.ModelInfo {
+	filtered := make([]types.ModelInfo, 0, len(models))
+	for _, m := range models {
+		for _, supported := range m.SupportedMethods {
+			if supported == method {
+				filtered = append(filtered, m)
+				break
+			}
+		}
+	}
+	return filtered
+}
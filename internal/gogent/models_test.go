@@ -0,0 +1,100 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gogent/internal/gemini"
+	"gogent/internal/testdb"
+	"gogent/internal/types"
+)
+
+// newModelsTestClient returns a client backed by an in-memory model_catalog table
+func newModelsTestClient(t *testing.T) *Client {
+	return &Client{db: testdb.Open(t)}
+}
+
+// countingLister serves a fixed catalog, or an error, and counts its calls
+type countingLister struct {
+	models []types.ModelInfo
+	err    error
+	calls  int
+}
+
+func (l *countingLister) list(ctx context.Context) ([]types.ModelInfo, error) {
+	l.calls++
+	return append([]types.ModelInfo(nil), l.models...), l.err
+}
+
+func TestConvertGeminiModels(t *testing.T) {
+	models := convertGeminiModels([]gemini.Model{{
+		Name:                       "models/gemini-2.0-flash",
+		Version:                    "2.0",
+		DisplayName:                "Gemini 2.0 Flash",
+		InputTokenLimit:            1048576,
+		OutputTokenLimit:           8192,
+		SupportedGenerationMethods: []string{"generateContent", "countTokens"},
+	}})
+
+	if len(models) != 1 {
+		t.Fatalf("expected one model, got %+v", models)
+	}
+	if m := models[0]; m.Name != "gemini-2.0-flash" || m.InputTokenLimit != 1048576 || len(m.SupportedMethods) != 2 {
+		t.Errorf("expected the model without its prefix and with its limits, got %+v", m)
+	}
+}
+
+func TestListModels(t *testing.T) {
+	client := newModelsTestClient(t)
+	ctx := context.Background()
+	lister := &countingLister{models: []types.ModelInfo{
+		{Name: "text-embedding-004", DisplayName: "Embedding", SupportedMethods: []string{"embedContent"}},
+		{Name: "gemini-2.0-flash", DisplayName: "Flash", Version: "2.0", OutputTokenLimit: 8192,
+			SupportedMethods: []string{"generateContent", "countTokens"}},
+	}}
+
+	models, err := client.listModels(ctx, false, lister.list)
+	if err != nil || len(models) != 2 || models[0].Name != "gemini-2.0-flash" {
+		t.Fatalf("expected the fetched catalog sorted by name, got %+v, %v", models, err)
+	}
+
+	// A fresh stored catalog is served without fetching again
+	models, err = client.listModels(ctx, false, lister.list)
+	if err != nil || lister.calls != 1 || len(models) != 2 {
+		t.Fatalf("expected the stored catalog, got %d fetches, %+v, %v", lister.calls, models, err)
+	}
+	if m := models[0]; m.Version != "2.0" || m.OutputTokenLimit != 8192 || len(m.SupportedMethods) != 2 || m.Description != "" {
+		t.Errorf("expected the stored model to round-trip, got %+v", m)
+	}
+	if generating := ModelsSupporting(models, "generateContent"); len(generating) != 1 || generating[0].Name != "gemini-2.0-flash" {
+		t.Errorf("expected only the generative model, got %+v", generating)
+	}
+
+	// An expired catalog is refetched, and served stale when the provider fails
+	client.db.Exec(`UPDATE model_catalog SET fetched_at = ?`, time.Now().Add(-2*modelCatalogTTL))
+	lister.err = errors.New("provider unavailable")
+	models, err = client.listModels(ctx, false, lister.list)
+	if err != nil || lister.calls != 2 || len(models) != 2 {
+		t.Errorf("expected the stale catalog after a failed refetch, got %d fetches, %+v, %v", lister.calls, models, err)
+	}
+
+	// A refresh replaces the stored catalog
+	lister.err = nil
+	lister.models = lister.models[:1]
+	if _, err := client.listModels(ctx, true, lister.list); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored, _ := client.storedModels(ctx); len(stored) != 1 || stored[0].Name != "text-embedding-004" {
+		t.Errorf("expected the refreshed catalog to replace the old one, got %+v", stored)
+	}
+}
+
+func TestListModelsWithoutCatalog(t *testing.T) {
+	client := newModelsTestClient(t)
+	lister := &countingLister{err: errors.New("provider unavailable")}
+	if _, err := client.listModels(context.Background(), false, lister.list); err == nil {
+		t.Errorf("expected an error with no stored catalog to fall back to")
+	}
+}
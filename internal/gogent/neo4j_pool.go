@@ -0,0 +1,206 @@
+package gogent
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+const (
+	// neo4jHealthCheckInterval is how long a driver is trusted before its connectivity is verified again
+	neo4jHealthCheckInterval = 30 * time.Second
+	// neo4jDriverIdleTimeout is how long an unused driver is kept before it is closed
+	neo4jDriverIdleTimeout = 5 * time.Minute
+)
+
+// neo4jDriverKey identifies the database and credentials a driver connects with
+type neo4jDriverKey struct {
+	url      string
+	username string
+	password string
+}
+
+// neo4jSessionKey identifies a session shared by the query_graph calls of an execution run
+type neo4jSessionKey struct {
+	driver         neo4jDriverKey
+	database       string
+	executionRunID string
+}
+
+// pooledNeo4jDriver is a cached driver and when it was last used and verified
+type pooledNeo4jDriver struct {
+	driver      neo4j.DriverWithContext
+	lastUsed    time.Time
+	lastChecked time.Time
+}
+
+// pooledNeo4jSession is a read session kept open for an execution run; sessions are not safe for
+// concurrent use, so calls hold mutex while they run
+type pooledNeo4jSession struct {
+	mutex   sync.Mutex
+	session neo4j.SessionWithContext
+	closed  bool
+}
+
+// neo4jPool caches Neo4j drivers by URL and credentials, and the sessions of running executions,
+// so query_graph calls do not open a connection each time
+type neo4jPool struct {
+	mutex    sync.Mutex
+	drivers  map[neo4jDriverKey]*pooledNeo4jDriver
+	sessions map[neo4jSessionKey]*pooledNeo4jSession
+
+	// connect creates a driver and verify checks that it can reach the database; tests replace them
+	connect func(key neo4jDriverKey) (neo4j.DriverWithContext, error)
+	verify  func(ctx context.Context, driver neo4j.DriverWithContext) error
+	now     func() time.Time
+}
+
+// close closes the session once no call is using it
+func (s *pooledNeo4jSession) close(ctx context.Context) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.session.Close(ctx)
+	s.closed = true
+}
+
+// newNeo4jPool creates an empty pool that connects with the Neo4j driver
+func newNeo4jPool() *neo4jPool {
+	return &neo4jPool{
+		drivers:  make(map[neo4jDriverKey]*pooledNeo4jDriver),
+		sessions: make(map[neo4jSessionKey]*pooledNeo4jSession),
+		connect: func(key neo4jDriverKey) (neo4j.DriverWithContext, error) {
+			return neo4j.NewDriverWithContext(key.url, neo4j.BasicAuth(key.username, key.password, ""))
+		},
+		verify: func(ctx context.Context, driver neo4j.DriverWithContext) error {
+			return driver.VerifyConnectivity(ctx)
+		},
+		now: time.Now,
+	}
+}
+
+// neo4jConnections returns the client's Neo4j pool, creating it on first use
+func (c *Client) neo4jConnections() *neo4jPool {
+	c.neo4jPoolOnce.Do(func() {
+		if c.neo4jPool == nil {
+			c.neo4jPool = newNeo4jPool()
+		}
+	})
+	return c.neo4jPool
+}
+
+// driver returns a connected driver for key, reusing a cached one. A cached driver is verified
+// again once neo4jHealthCheckInterval has passed and replaced when it fails. Drivers idle for
+// longer than neo4jDriverIdleTimeout are closed.
+func (p *neo4jPool) driver(ctx context.Context, key neo4jDriverKey) (neo4j.DriverWithContext, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	now := p.now()
+	p.expireIdle(ctx, now)
+
+	if cached, ok := p.drivers[key]; ok {
+		if now.Sub(cached.lastChecked) < neo4jHealthCheckInterval {
+			cached.lastUsed = now
+			return cached.driver, nil
+		}
+		if err := p.verify(ctx, cached.driver); err == nil {
+			cached.lastUsed, cached.lastChecked = now, now
+			return cached.driver, nil
+		}
+		log.Printf("⚠️ Cached Neo4j driver for %s failed its health check, reconnecting", key.url)
+		p.closeDriver(ctx, key)
+	}
+
+	driver, err := p.connect(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.verify(ctx, driver); err != nil {
+		driver.Close(ctx)
+		return nil, err
+	}
+	p.drivers[key] = &pooledNeo4jDriver{driver: driver, lastUsed: now, lastChecked: now}
+	return driver, nil
+}
+
+// session returns the read session for key, opening it on first use; executions without a run ID
+// get a session of their own that is closed when the call is done. release must be called once
+// the session is no longer used.
+func (p *neo4jPool) session(ctx context.Context, key neo4jSessionKey) (session neo4j.SessionWithContext, release func(), err error) {
+	driver, err := p.driver(ctx, key.driver)
+	if err != nil {
+		return nil, nil, err
+	}
+	config := neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead, DatabaseName: key.database}
+	if key.executionRunID == "" {
+		session := driver.NewSession(ctx, config)
+		return session, func() { session.Close(ctx) }, nil
+	}
+
+	p.mutex.Lock()
+	pooled, ok := p.sessions[key]
+	if !ok {
+		pooled = &pooledNeo4jSession{session: driver.NewSession(ctx, config)}
+		p.sessions[key] = pooled
+	}
+	p.mutex.Unlock()
+
+	pooled.mutex.Lock()
+	if pooled.closed {
+		// The session was closed with its driver while this call waited for it
+		pooled.mutex.Unlock()
+		return p.session(ctx, key)
+	}
+	return pooled.session, pooled.mutex.Unlock, nil
+}
+
+// releaseNeo4jSessions closes the Neo4j sessions an execution run kept open
+func (c *Client) releaseNeo4jSessions(executionRunID string) {
+	c.neo4jConnections().releaseRun(context.Background(), executionRunID)
+}
+
+// releaseRun closes the sessions kept open for an execution run
+func (p *neo4jPool) releaseRun(ctx context.Context, executionRunID string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for key, pooled := range p.sessions {
+		if key.executionRunID == executionRunID {
+			pooled.close(ctx)
+			delete(p.sessions, key)
+		}
+	}
+}
+
+// close closes every session and driver in the pool
+func (p *neo4jPool) close(ctx context.Context) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for key := range p.drivers {
+		p.closeDriver(ctx, key)
+	}
+}
+
+// expireIdle closes drivers that have not been used for neo4jDriverIdleTimeout; p.mutex must be held
+func (p *neo4jPool) expireIdle(ctx context.Context, now time.Time) {
+	for key, cached := range p.drivers {
+		if now.Sub(cached.lastUsed) >= neo4jDriverIdleTimeout {
+			p.closeDriver(ctx, key)
+		}
+	}
+}
+
+// closeDriver closes a cached driver and the sessions opened on it; p.mutex must be held
+func (p *neo4jPool) closeDriver(ctx context.Context, key neo4jDriverKey) {
+	for sessionKey, pooled := range p.sessions {
+		if sessionKey.driver == key {
+			pooled.close(ctx)
+			delete(p.sessions, sessionKey)
+		}
+	}
+	if cached, ok := p.drivers[key]; ok {
+		cached.driver.Close(ctx)
+		delete(p.drivers, key)
+	}
+}
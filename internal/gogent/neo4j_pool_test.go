@@ -0,0 +1,97 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// newTestNeo4jPool returns a pool whose drivers never dial, counting the drivers it creates and
+// failing health checks while healthy is false
+func newTestNeo4jPool(t *testing.T) (pool *neo4jPool, connects *int, healthy *bool, now *time.Time) {
+	connects, healthy, now = new(int), new(bool), new(time.Time)
+	*healthy, *now = true, time.Now()
+	pool = newNeo4jPool()
+	pool.connect = func(key neo4jDriverKey) (neo4j.DriverWithContext, error) {
+		*connects++
+		return neo4j.NewDriverWithContext("bolt://127.0.0.1:1", neo4j.BasicAuth(key.username, key.password, ""))
+	}
+	pool.verify = func(ctx context.Context, driver neo4j.DriverWithContext) error {
+		if !*healthy {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+	pool.now = func() time.Time { return *now }
+	t.Cleanup(func() { pool.close(context.Background()) })
+	return pool, connects, healthy, now
+}
+
+func TestNeo4jPoolReusesDrivers(t *testing.T) {
+	pool, connects, healthy, now := newTestNeo4jPool(t)
+	ctx := context.Background()
+	key := neo4jDriverKey{url: "neo4j://graph", username: "neo4j", password: "secret"}
+
+	first, _ := pool.driver(ctx, key)
+	second, _ := pool.driver(ctx, key)
+	if first != second || *connects != 1 {
+		t.Errorf("expected the driver to be reused, got %d connects", *connects)
+	}
+	if pool.driver(ctx, neo4jDriverKey{url: "neo4j://graph", username: "neo4j", password: "other"}); *connects != 2 {
+		t.Errorf("expected other credentials to get their own driver, got %d connects", *connects)
+	}
+
+	// A failed health check replaces the driver
+	*now = now.Add(neo4jHealthCheckInterval)
+	*healthy = false
+	if _, err := pool.driver(ctx, key); err == nil {
+		t.Error("expected an error while the database is unreachable")
+	}
+	*healthy = true
+	if _, err := pool.driver(ctx, key); err != nil || *connects != 4 {
+		t.Errorf("expected a new driver once the database is back, got %d connects (%v)", *connects, err)
+	}
+
+	// Idle drivers are closed
+	*now = now.Add(neo4jDriverIdleTimeout)
+	pool.driver(ctx, key)
+	if len(pool.drivers) != 1 || *connects != 5 {
+		t.Errorf("expected idle drivers to be closed and the used one reconnected, got %d drivers, %d connects", len(pool.drivers), *connects)
+	}
+}
+
+func TestNeo4jPoolRunSessions(t *testing.T) {
+	pool, _, _, _ := newTestNeo4jPool(t)
+	ctx := context.Background()
+	key := neo4jSessionKey{driver: neo4jDriverKey{url: "neo4j://graph"}, database: "movies", executionRunID: "run-1"}
+
+	first, release, err := pool.session(ctx, key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	release()
+	second, release, _ := pool.session(ctx, key)
+	release()
+	if first != second {
+		t.Error("expected calls in the same run to share a session")
+	}
+
+	other, release, _ := pool.session(ctx, neo4jSessionKey{driver: key.driver, database: "movies", executionRunID: "run-2"})
+	release()
+	if other == first {
+		t.Error("expected another run to get its own session")
+	}
+
+	pool.releaseRun(ctx, "run-1")
+	if len(pool.sessions) != 1 {
+		t.Errorf("expected only run-2's session to stay open, got %d", len(pool.sessions))
+	}
+	third, release, _ := pool.session(ctx, key)
+	release()
+	if third == first {
+		t.Error("expected a released run to get a new session")
+	}
+}
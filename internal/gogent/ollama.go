@@ -0,0 +1,199 @@
+package gogent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"gogent/internal/ollama"
+	"gogent/internal/types"
+
+	"github.com/google/uuid"
+)
+
+// callModelAPI runs a configuration on its provider: local models on Ollama, everything else on Gemini
+func (c *Client) callModelAPI(ctx context.Context, config *types.APIConfiguration, request *types.APIRequest) (*types.APIResponse, error) {
+	if types.ProviderForConfiguration(config) == types.ProviderOllama {
+		return c.callOllamaAPI(ctx, config, request)
+	}
+	return c.callGeminiAPI(ctx, config, request)
+}
+
+// callOllamaAPI runs a configuration on a local model served by Ollama. The first tool call the
+// model asks for is executed and its result sent back for the final answer, as on Gemini.
+func (c *Client) callOllamaAPI(ctx context.Context, config *types.APIConfiguration, request *types.APIRequest) (*types.APIResponse, error) {
+	if c.config.OllamaURL == "" {
+		log.Printf("No Ollama server configured, using mock responses")
+		return c.callMockGeminiAPI(ctx, config, request)
+	}
+	startTime := time.Now()
+	log.Printf("🦙 Using Ollama at %s for model: '%s'", c.config.OllamaURL, config.ModelName)
+
+	chatRequest := ollamaChatRequest(config, request)
+
+	client := ollama.NewClient(c.config.OllamaURL)
+	client.SetTransport(c.providerTransport())
+	latency := &types.LatencyBreakdown{}
+	modelStart := time.Now()
+	chatResponse, err := client.Chat(ctx, chatRequest)
+	latency.ModelMs = time.Since(modelStart).Milliseconds()
+	if err != nil {
+		log.Printf("Ollama - Request error: %v", err)
+		return nil, err
+	}
+
+	responseText := chatResponse.Message.Content
+	finishReason := chatResponse.DoneReason
+	usage := newOllamaUsage()
+	usage.add(chatRequest, chatResponse)
+
+	var functionCallResponse map[string]interface{}
+	if len(chatResponse.Message.ToolCalls) > 0 {
+		call := chatResponse.Message.ToolCalls[0].Function
+		functionStart := time.Now()
+		functionResult := c.runFunctionCall(ctx, config, request, call.Name, call.Arguments)
+		latency.FunctionMs = time.Since(functionStart).Milliseconds()
+		functionCallResponse = map[string]interface{}{
+			"function_name": call.Name,
+			"arguments":     call.Arguments,
+			"result":        functionResult,
+		}
+
+		// Send the function result back for the final answer
+		resultText, _ := json.Marshal(functionResult)
+		chatRequest.Messages = append(chatRequest.Messages, chatResponse.Message, ollama.Message{Role: "tool", Content: string(resultText)})
+		chatRequest.Tools = nil
+		if wantsStructuredOutput(config) {
+			chatRequest.Format = "json"
+		}
+		followUpStart := time.Now()
+		finalResponse, err := client.Chat(ctx, chatRequest)
+		latency.FollowUpMs = time.Since(followUpStart).Milliseconds()
+		if err != nil {
+			c.logExecutionEvent(ctx, types.LogLevelError, types.LogCategoryAPICall,
+				fmt.Sprintf("Failed to get final response from Ollama: %v", err),
+				map[string]interface{}{
+					"functionName": call.Name,
+					"error":        err.Error(),
+				})
+			responseText = fmt.Sprintf("I called the %s function with the provided parameters and received the result.", call.Name)
+		} else {
+			responseText = finalResponse.Message.Content
+			finishReason = finalResponse.DoneReason
+			usage.add(chatRequest, finalResponse)
+		}
+	}
+
+	response := &types.APIResponse{
+		ID:             uuid.New().String(),
+		RequestID:      request.ID,
+		ResponseStatus: types.ResponseStatusSuccess,
+		ResponseText:   responseText,
+		UsageMetadata:  usage,
+		FinishReason:   finishReason,
+		ResponseTimeMs: int32(time.Since(startTime).Milliseconds()),
+		Latency:        latency,
+		CreatedAt:      time.Now(),
+	}
+	if functionCallResponse != nil {
+		response.FunctionCallResponse = functionCallResponse
+	}
+	return response, nil
+}
+
+// ollamaChatRequest builds the request of a configuration's first Ollama chat call
+func ollamaChatRequest(config *types.APIConfiguration, request *types.APIRequest) *ollama.ChatRequest {
+	prompt := request.Prompt
+	if request.Context != "" {
+		prompt = fmt.Sprintf("%s\n\nContext: %s", prompt, request.Context)
+	}
+	if systemPromptMode(config) == types.SystemPromptModeInline {
+		prompt = config.SystemPrompt + "\n\n" + prompt
+	}
+	if functionInstruction := resolveFunctionInstruction(config); len(config.Tools) > 0 && functionInstruction != "" {
+		prompt = functionInstruction + "\n\n" + prompt
+	}
+
+	var messages []ollama.Message
+	if system := ollamaSystemPrompt(config); system != "" {
+		messages = append(messages, ollama.Message{Role: "system", Content: system})
+	}
+	messages = append(messages, ollama.Message{Role: "user", Content: prompt})
+
+	chatRequest := &ollama.ChatRequest{
+		Model:    config.ModelName,
+		Messages: messages,
+		Options:  ollamaOptions(config),
+	}
+	for _, tool := range choiceTools(config) {
+		chatRequest.Tools = append(chatRequest.Tools, ollama.FunctionTool(tool.Name, tool.Description, sanitizeToolParameters(tool.Parameters)))
+	}
+	// As on Gemini, JSON output is only asked for on the call that can no longer call tools
+	if wantsStructuredOutput(config) && len(chatRequest.Tools) == 0 {
+		chatRequest.Format = "json"
+	}
+	return chatRequest
+}
+
+// ollamaSystemPrompt builds the system message of a configuration: its system prompt, unless sent
+// inline, followed by the instruction its safety policy translated into
+func ollamaSystemPrompt(config *types.APIConfiguration) string {
+	var parts []string
+	if systemPromptMode(config) == types.SystemPromptModeInstruction {
+		parts = append(parts, config.SystemPrompt)
+	}
+	if instruction, ok := config.SafetySettings["systemInstruction"].(string); ok && instruction != "" {
+		parts = append(parts, instruction)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// ollamaOptions maps a configuration's sampling parameters onto Ollama options, or nil when none is set
+func ollamaOptions(config *types.APIConfiguration) *ollama.Options {
+	options := &ollama.Options{
+		Temperature: config.Temperature,
+		TopP:        config.TopP,
+		TopK:        config.TopK,
+		NumPredict:  config.MaxTokens,
+		Seed:        config.Seed,
+	}
+	if options.IsEmpty() {
+		return nil
+	}
+	return options
+}
+
+// ollamaUsage is the usage metadata of an Ollama variation, summed over its chat calls
+type ollamaUsage map[string]interface{}
+
+// newOllamaUsage starts usage metadata at zero tokens
+func newOllamaUsage() ollamaUsage {
+	return ollamaUsage{"prompt_tokens": 0, "completion_tokens": 0, "total_tokens": 0, "estimated": false}
+}
+
+// add counts the tokens of a chat call, estimating locally any count Ollama left out, such as the
+// prompt tokens of a cached prompt
+func (u ollamaUsage) add(request *ollama.ChatRequest, response *ollama.ChatResponse) {
+	promptTokens, completionTokens := response.PromptEvalCount, response.EvalCount
+	if promptTokens == 0 {
+		for _, message := range request.Messages {
+			promptTokens += estimateTokens(message.Content)
+		}
+		u["estimated"] = true
+	}
+	if completionTokens == 0 && response.Message.Content != "" {
+		completionTokens = estimateTokens(response.Message.Content)
+		u["estimated"] = true
+	}
+	u["prompt_tokens"] = u["prompt_tokens"].(int) + promptTokens
+	u["completion_tokens"] = u["completion_tokens"].(int) + completionTokens
+	u["total_tokens"] = u["total_tokens"].(int) + promptTokens + completionTokens
+}
+
+// estimateTokens approximates the token count of text at ~4 characters per token
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
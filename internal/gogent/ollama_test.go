@@ -0,0 +1,75 @@
+package gogent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gogent/internal/ollama"
+	"gogent/internal/types"
+)
+
+func TestCallOllamaAPI(t *testing.T) {
+	var requests []ollama.ChatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request ollama.ChatRequest
+		json.NewDecoder(r.Body).Decode(&request)
+		requests = append(requests, request)
+
+		if len(requests) == 1 {
+			w.Write([]byte(`{"message": {"role": "assistant", "content": "", "tool_calls": [{"function": {"name": "get_weather", "arguments": {"location": "Paris"}}}]}, "done_reason": "stop", "prompt_eval_count": 40, "eval_count": 8}`))
+			return
+		}
+		// A cached prompt reports no prompt tokens
+		w.Write([]byte(`{"message": {"role": "assistant", "content": "It is sunny in Paris."}, "done_reason": "stop", "eval_count": 7}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, _ := newStoreTestClient(t)
+	client.config.OllamaURL = server.URL
+	config := &types.APIConfiguration{
+		VariationName: "local",
+		ModelName:     "llama3.2",
+		Provider:      types.ProviderOllama,
+		SystemPrompt:  "Be brief.",
+		Temperature:   &[]float32{0.3}[0],
+		Deterministic: true,
+		Tools:         []types.Tool{{Name: "get_weather", MockResponse: map[string]interface{}{"condition": "sunny"}}},
+	}
+
+	response, err := client.callModelAPI(context.Background(), config, &types.APIRequest{ID: "request-1", Prompt: "Weather in Paris?"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.ResponseText != "It is sunny in Paris." || response.FunctionCallResponse["function_name"] != "get_weather" {
+		t.Errorf("expected the final answer after the tool call, got %+v", response)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("expected the tool call and the final answer, got %d requests", len(requests))
+	}
+	first, final := requests[0], requests[1]
+	if first.Messages[0].Role != "system" || first.Messages[0].Content != "Be brief." || len(first.Tools) != 1 || *first.Options.Temperature != 0.3 {
+		t.Errorf("expected the system prompt, tools and options, got %+v", first)
+	}
+	if last := final.Messages[len(final.Messages)-1]; last.Role != "tool" || last.Content != `{"condition":"sunny"}` || len(final.Tools) != 0 {
+		t.Errorf("expected the pinned tool result without tools, got %+v", final)
+	}
+
+	usage := response.UsageMetadata
+	if usage["completion_tokens"] != 15 || usage["estimated"] != true || usage["prompt_tokens"].(int) <= 40 {
+		t.Errorf("expected reported completion tokens and estimated prompt tokens, got %v", usage)
+	}
+}
+
+func TestOllamaWithoutServerUsesMock(t *testing.T) {
+	client := &Client{config: &types.GeminiClientConfig{}}
+	config := &types.APIConfiguration{VariationName: "local", ModelName: "mistral", Provider: types.ProviderOllama}
+
+	response, err := client.callModelAPI(context.Background(), config, &types.APIRequest{ID: "request-1", Prompt: "Hello"})
+	if err != nil || response.ResponseStatus != types.ResponseStatusSuccess || response.ResponseTimeMs != 500 {
+		t.Errorf("expected a mock response without an Ollama server, got %+v (%v)", response, err)
+	}
+}
@@ -0,0 +1,506 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"gogent/internal/types"
+)
+
+// ErrInvalidOpenAPISpec is returned for a spec that is not an OpenAPI 3 document gogent can import from
+var ErrInvalidOpenAPISpec = errors.New("invalid OpenAPI spec")
+
+const (
+	// maxOpenAPISpecBytes caps the size of an imported spec
+	maxOpenAPISpecBytes = 2 << 20
+	// openAPIFetchTimeout limits downloading a spec from a URL
+	openAPIFetchTimeout = 15 * time.Second
+	// maxOpenAPIRefDepth caps how deeply schema references are followed, cutting off cycles
+	maxOpenAPIRefDepth = 8
+)
+
+// openAPIMethods are the operation methods a function can call, in the order operations are listed
+var openAPIMethods = []string{"get", "post", "put", "patch", "delete"}
+
+// openAPISchemaKeys are the JSON Schema keywords kept from a spec's schemas; the rest, such as
+// examples and readOnly, mean nothing to a model
+var openAPISchemaKeys = map[string]bool{
+	"type": true, "description": true, "enum": true, "format": true, "default": true,
+	"minimum": true, "maximum": true, "minLength": true, "maxLength": true, "pattern": true,
+	"minItems": true, "maxItems": true, "nullable": true,
+}
+
+// invalidFunctionNameChars matches the characters function names may not contain
+var invalidFunctionNameChars = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+
+// openAPIDocument is a parsed OpenAPI document with its local references resolvable
+type openAPIDocument struct {
+	root map[string]interface{}
+	base *url.URL // Where the spec was fetched from, for relative server URLs
+}
+
+// FunctionsFromOpenAPI generates a function definition for each selected operation of an OpenAPI 3
+// spec in JSON or YAML. Operations are selected by operationId or "METHOD /path"; none selects
+// every operation. Credentials are left blank in each definition's auth config.
+func FunctionsFromOpenAPI(spec []byte, operations []string) ([]*types.FunctionDefinition, error) {
+	return functionsFromOpenAPI(spec, nil, operations)
+}
+
+func functionsFromOpenAPI(spec []byte, base *url.URL, operations []string) ([]*types.FunctionDefinition, error) {
+	var root map[string]interface{}
+	if err := yaml.Unmarshal(spec, &root); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidOpenAPISpec, err)
+	}
+	version, _ := root["openapi"].(string)
+	if !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("%w: only OpenAPI 3 documents are supported", ErrInvalidOpenAPISpec)
+	}
+	document := &openAPIDocument{root: root, base: base}
+
+	serverURL, err := document.serverURL()
+	if err != nil {
+		return nil, err
+	}
+	paths, _ := root["paths"].(map[string]interface{})
+	pathNames := make([]string, 0, len(paths))
+	for path := range paths {
+		pathNames = append(pathNames, path)
+	}
+	sort.Strings(pathNames)
+
+	selected := make(map[string]bool, len(operations))
+	for _, operation := range operations {
+		selected[normalizeOperationKey(operation)] = true
+	}
+	found := make(map[string]bool, len(operations))
+
+	var functions []*types.FunctionDefinition
+	for _, path := range pathNames {
+		item, _ := document.resolve(paths[path]).(map[string]interface{})
+		for _, method := range openAPIMethods {
+			operation, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			operationID, _ := operation["operationId"].(string)
+			key := normalizeOperationKey(method + " " + path)
+			if len(selected) > 0 {
+				switch {
+				case operationID != "" && selected[operationID]:
+					found[operationID] = true
+				case selected[key]:
+					found[key] = true
+				default:
+					continue
+				}
+			}
+			functions = append(functions, document.function(serverURL, path, method, item, operation))
+		}
+	}
+
+	for key := range selected {
+		if !found[key] {
+			return nil, fmt.Errorf("%w: no operation %q", ErrInvalidOpenAPISpec, key)
+		}
+	}
+	if len(functions) == 0 {
+		return nil, fmt.Errorf("%w: the spec has no operations to import", ErrInvalidOpenAPISpec)
+	}
+	return functions, nil
+}
+
+// normalizeOperationKey returns an operationId as-is and "METHOD /path" with the method upper case
+func normalizeOperationKey(operation string) string {
+	method, path, ok := strings.Cut(strings.TrimSpace(operation), " ")
+	if !ok {
+		return operation
+	}
+	return strings.ToUpper(method) + " " + strings.TrimSpace(path)
+}
+
+// serverURL returns the spec's first server URL with its variables at their defaults, resolved
+// against where the spec was fetched from
+func (d *openAPIDocument) serverURL() (string, error) {
+	servers, _ := d.root["servers"].([]interface{})
+	if len(servers) == 0 {
+		return "", fmt.Errorf("%w: the spec lists no servers", ErrInvalidOpenAPISpec)
+	}
+	server, _ := servers[0].(map[string]interface{})
+	raw, _ := server["url"].(string)
+	variables, _ := server["variables"].(map[string]interface{})
+	for name, variable := range variables {
+		value, _ := variable.(map[string]interface{})
+		raw = strings.ReplaceAll(raw, "{"+name+"}", fmt.Sprint(value["default"]))
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("%w: server URL %q: %v", ErrInvalidOpenAPISpec, raw, err)
+	}
+	if !parsed.IsAbs() && d.base != nil {
+		parsed = d.base.ResolveReference(parsed)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return "", fmt.Errorf("%w: server URL %q must be absolute http or https", ErrInvalidOpenAPISpec, raw)
+	}
+	return strings.TrimSuffix(parsed.String(), "/"), nil
+}
+
+// function builds the definition of one operation
+func (d *openAPIDocument) function(serverURL, path, method string, item, operation map[string]interface{}) *types.FunctionDefinition {
+	operationID, _ := operation["operationId"].(string)
+	summary, _ := operation["summary"].(string)
+	description, _ := operation["description"].(string)
+
+	function := &types.FunctionDefinition{
+		Name:             openAPIFunctionName(operationID, method, path),
+		DisplayName:      firstNonEmpty(summary, operationID, strings.ToUpper(method)+" "+path),
+		Description:      firstNonEmpty(description, summary, strings.ToUpper(method)+" "+path),
+		ParametersSchema: d.parametersSchema(item, operation),
+		EndpointURL:      serverURL + path,
+		HttpMethod:       strings.ToUpper(method),
+		IsActive:         true,
+		AuthConfig:       d.authConfig(operation),
+	}
+	if len(function.DisplayName) > 100 {
+		function.DisplayName = function.DisplayName[:100]
+	}
+	return function
+}
+
+// openAPIFunctionName turns an operationId, or the method and path of an operation without one,
+// into a valid function name
+func openAPIFunctionName(operationID, method, path string) string {
+	name := operationID
+	if name == "" {
+		name = method + path
+	}
+	name = strings.Trim(invalidFunctionNameChars.ReplaceAllString(name, "_"), "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+// parametersSchema combines an operation's path and query parameters, including those declared
+// for its whole path, with the properties of its JSON request body
+func (d *openAPIDocument) parametersSchema(item, operation map[string]interface{}) map[string]interface{} {
+	properties := map[string]interface{}{}
+	var required []interface{}
+
+	var parameters []interface{}
+	if shared, ok := item["parameters"].([]interface{}); ok {
+		parameters = append(parameters, shared...)
+	}
+	if own, ok := operation["parameters"].([]interface{}); ok {
+		// Operation parameters override path-level ones of the same name
+		parameters = append(parameters, own...)
+	}
+	for _, raw := range parameters {
+		parameter, _ := d.resolve(raw).(map[string]interface{})
+		name, _ := parameter["name"].(string)
+		in, _ := parameter["in"].(string)
+		if name == "" || (in != "path" && in != "query") {
+			continue
+		}
+		schema := d.schema(parameter["schema"], 0)
+		if description, ok := parameter["description"].(string); ok {
+			schema["description"] = description
+		}
+		properties[name] = schema
+		if isRequired, _ := parameter["required"].(bool); isRequired || in == "path" {
+			required = appendUnique(required, name)
+		}
+	}
+
+	if body, ok := d.resolve(operation["requestBody"]).(map[string]interface{}); ok {
+		content, _ := body["content"].(map[string]interface{})
+		media, _ := content["application/json"].(map[string]interface{})
+		if media != nil {
+			bodySchema := d.schema(media["schema"], 0)
+			if bodyProperties, ok := bodySchema["properties"].(map[string]interface{}); ok {
+				for name, schema := range bodyProperties {
+					if _, taken := properties[name]; !taken {
+						properties[name] = schema
+					}
+				}
+				bodyRequired, _ := bodySchema["required"].([]interface{})
+				for _, name := range bodyRequired {
+					required = appendUnique(required, name)
+				}
+			}
+		}
+	}
+
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// appendUnique appends value unless values already holds it
+func appendUnique(values []interface{}, value interface{}) []interface{} {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// schema converts an OpenAPI schema to the JSON Schema subset function parameters use, following
+// references and merging allOf. oneOf and anyOf take their first alternative.
+func (d *openAPIDocument) schema(raw interface{}, depth int) map[string]interface{} {
+	if depth > maxOpenAPIRefDepth {
+		return map[string]interface{}{"type": "object"}
+	}
+	source, _ := d.resolve(raw).(map[string]interface{})
+	schema := map[string]interface{}{}
+
+	if parts, ok := source["allOf"].([]interface{}); ok {
+		for _, part := range parts {
+			mergeSchema(schema, d.schema(part, depth+1))
+		}
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if alternatives, ok := source[key].([]interface{}); ok && len(alternatives) > 0 {
+			mergeSchema(schema, d.schema(alternatives[0], depth+1))
+		}
+	}
+
+	for key, value := range source {
+		if openAPISchemaKeys[key] {
+			schema[key] = value
+		}
+	}
+	if properties, ok := source["properties"].(map[string]interface{}); ok {
+		converted, _ := schema["properties"].(map[string]interface{})
+		if converted == nil {
+			converted = map[string]interface{}{}
+		}
+		for name, property := range properties {
+			converted[name] = d.schema(property, depth+1)
+		}
+		schema["properties"] = converted
+	}
+	if requiredNames, ok := source["required"].([]interface{}); ok {
+		existing, _ := schema["required"].([]interface{})
+		for _, name := range requiredNames {
+			existing = appendUnique(existing, name)
+		}
+		schema["required"] = existing
+	}
+	if items, ok := source["items"]; ok {
+		schema["items"] = d.schema(items, depth+1)
+	}
+	if _, ok := schema["type"]; !ok {
+		if _, hasProperties := schema["properties"]; hasProperties {
+			schema["type"] = "object"
+		}
+	}
+	return schema
+}
+
+// mergeSchema adds the properties, required names and keywords of from to into
+func mergeSchema(into, from map[string]interface{}) {
+	for key, value := range from {
+		switch key {
+		case "properties":
+			properties, _ := into["properties"].(map[string]interface{})
+			if properties == nil {
+				properties = map[string]interface{}{}
+			}
+			for name, property := range value.(map[string]interface{}) {
+				properties[name] = property
+			}
+			into["properties"] = properties
+		case "required":
+			existing, _ := into["required"].([]interface{})
+			for _, name := range value.([]interface{}) {
+				existing = appendUnique(existing, name)
+			}
+			into["required"] = existing
+		default:
+			into[key] = value
+		}
+	}
+}
+
+// authConfig maps the first security scheme an operation requires, or the spec requires by
+// default, to a function auth config with its credentials left blank
+func (d *openAPIDocument) authConfig(operation map[string]interface{}) map[string]interface{} {
+	security, ok := operation["security"].([]interface{})
+	if !ok {
+		security, _ = d.root["security"].([]interface{})
+	}
+	components, _ := d.root["components"].(map[string]interface{})
+	schemes, _ := components["securitySchemes"].(map[string]interface{})
+
+	for _, requirement := range security {
+		names, _ := requirement.(map[string]interface{})
+		schemeNames := make([]string, 0, len(names))
+		for name := range names {
+			schemeNames = append(schemeNames, name)
+		}
+		sort.Strings(schemeNames)
+		for _, name := range schemeNames {
+			scheme, _ := d.resolve(schemes[name]).(map[string]interface{})
+			if config := openAPIAuthConfig(scheme); config != nil {
+				return config
+			}
+		}
+	}
+	return nil
+}
+
+// openAPIAuthConfig maps a security scheme to a function auth config, or nil for schemes
+// functions cannot use
+func openAPIAuthConfig(scheme map[string]interface{}) map[string]interface{} {
+	schemeType, _ := scheme["type"].(string)
+	switch schemeType {
+	case "http":
+		switch strings.ToLower(fmt.Sprint(scheme["scheme"])) {
+		case "bearer":
+			return map[string]interface{}{"type": "bearer", "token": ""}
+		case "basic":
+			return map[string]interface{}{"type": "basic", "username": "", "password": ""}
+		}
+	case "apiKey":
+		name, _ := scheme["name"].(string)
+		switch scheme["in"] {
+		case "header":
+			return map[string]interface{}{"type": "api_key", "header": name, "value": ""}
+		case "query":
+			return map[string]interface{}{"type": "api_key", "queryParam": name, "value": ""}
+		}
+	case "oauth2", "openIdConnect":
+		return map[string]interface{}{"type": "bearer", "token": ""}
+	}
+	return nil
+}
+
+// resolve follows a local "#/..." reference, returning any other value as-is
+func (d *openAPIDocument) resolve(value interface{}) interface{} {
+	for range maxOpenAPIRefDepth {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		ref, ok := object["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#/") {
+			return value
+		}
+
+		var target interface{} = d.root
+		for _, segment := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			segment = strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+			parent, _ := target.(map[string]interface{})
+			target = parent[segment]
+		}
+		value = target
+	}
+	return nil
+}
+
+// firstNonEmpty returns the first of values that is not empty
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// ImportOpenAPIFunctions generates function definitions from the spec in the request, or the one
+// at its URL, and creates those owned by the user. Operations whose function cannot be created,
+// such as one whose name the user already uses, are reported as skipped. A dry run returns the
+// definitions without creating them.
+func (c *Client) ImportOpenAPIFunctions(ctx context.Context, userID string, request *types.OpenAPIImportRequest) (*types.OpenAPIImportResult, error) {
+	spec := []byte(request.Spec)
+	var base *url.URL
+	if request.URL != "" {
+		var err error
+		if spec, base, err = c.fetchOpenAPISpec(ctx, request.URL); err != nil {
+			return nil, err
+		}
+	}
+	if len(spec) == 0 {
+		return nil, fmt.Errorf("%w: send a spec or a URL to one", ErrInvalidOpenAPISpec)
+	}
+	if len(spec) > maxOpenAPISpecBytes {
+		return nil, fmt.Errorf("%w: specs are limited to %d bytes", ErrInvalidOpenAPISpec, maxOpenAPISpecBytes)
+	}
+
+	functions, err := functionsFromOpenAPI(spec, base, request.Operations)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &types.OpenAPIImportResult{Functions: []*types.FunctionDefinition{}}
+	for _, function := range functions {
+		if err := ValidateFunctionDefinition(function); err != nil {
+			result.Skipped = append(result.Skipped, types.OpenAPIImportSkip{Function: function.Name, Reason: err.Error()})
+			continue
+		}
+		if request.DryRun {
+			result.Functions = append(result.Functions, function)
+			continue
+		}
+		created, err := c.CreateFunctionDefinition(ctx, userID, function)
+		if errors.Is(err, ErrFunctionNameTaken) {
+			result.Skipped = append(result.Skipped, types.OpenAPIImportSkip{Function: function.Name, Reason: err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Functions = append(result.Functions, created)
+	}
+	return result, nil
+}
+
+// fetchOpenAPISpec downloads a spec, returning it with the URL it was served from
+func (c *Client) fetchOpenAPISpec(ctx context.Context, rawURL string) ([]byte, *url.URL, error) {
+	specURL, err := url.Parse(rawURL)
+	if err != nil || (specURL.Scheme != "http" && specURL.Scheme != "https") || specURL.Host == "" {
+		return nil, nil, fmt.Errorf("%w: spec URL must be absolute http or https", ErrInvalidOpenAPISpec)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, openAPIFetchTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, specURL.String(), nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create spec request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json, application/yaml;q=0.9, */*;q=0.5")
+
+	resp, err := (&http.Client{Transport: c.transport()}).Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch spec: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, nil, fmt.Errorf("%w: fetching %s returned status %d", ErrInvalidOpenAPISpec, specURL, resp.StatusCode)
+	}
+
+	spec, err := io.ReadAll(io.LimitReader(resp.Body, maxOpenAPISpecBytes+1))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read spec: %w", err)
+	}
+	return spec, resp.Request.URL, nil
+}
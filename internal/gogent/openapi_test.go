@@ -0,0 +1,199 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gogent/internal/types"
+)
+
+const testOpenAPISpec = `
+openapi: 3.0.3
+info: {title: Pet Store, version: "1.0"}
+servers:
+  - url: https://{region}.pets.example.com/v1/
+    variables:
+      region: {default: eu}
+security:
+  - petKey: []
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List pets
+      parameters:
+        - {name: limit, in: query, description: How many pets to return, schema: {type: integer, maximum: 100, example: 20}}
+        - {name: X-Trace, in: header, schema: {type: string}}
+    post:
+      operationId: createPet
+      summary: Create a pet
+      security:
+        - bearerAuth: []
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Pet'}
+  /pets/{petId}:
+    parameters:
+      - $ref: '#/components/parameters/PetId'
+    get:
+      operationId: show-pet.byId
+      description: Info for a specific pet
+    delete:
+      summary: Delete a pet
+components:
+  parameters:
+    PetId: {name: petId, in: path, required: true, schema: {type: string}}
+  schemas:
+    NewPet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string, readOnly: false}
+        tag: {type: string}
+    Pet:
+      allOf:
+        - $ref: '#/components/schemas/NewPet'
+        - type: object
+          properties:
+            owner: {$ref: '#/components/schemas/Owner'}
+    Owner:
+      type: object
+      properties:
+        name: {type: string}
+        pets: {type: array, items: {$ref: '#/components/schemas/Pet'}}
+  securitySchemes:
+    petKey: {type: apiKey, in: header, name: X-Pet-Key}
+    bearerAuth: {type: http, scheme: bearer}
+`
+
+func TestFunctionsFromOpenAPI(t *testing.T) {
+	functions, err := FunctionsFromOpenAPI([]byte(testOpenAPISpec), nil)
+	if err != nil {
+		t.Fatalf("failed to import spec: %v", err)
+	}
+	byName := map[string]*types.FunctionDefinition{}
+	for _, function := range functions {
+		byName[function.Name] = function
+		if err := ValidateFunctionDefinition(function); err != nil {
+			t.Errorf("expected %s to be a valid function, got %v", function.Name, err)
+		}
+	}
+	if len(functions) != 4 || byName["listPets"] == nil || byName["show_pet_byId"] == nil || byName["delete_pets_petId"] == nil {
+		t.Fatalf("expected four functions named from their operations, got %v", reflect.ValueOf(byName).MapKeys())
+	}
+
+	list := byName["listPets"]
+	if list.EndpointURL != "https://eu.pets.example.com/v1/pets" || list.HttpMethod != "GET" || list.DisplayName != "List pets" {
+		t.Errorf("expected the list operation's URL, method and summary, got %+v", list)
+	}
+	limit := list.ParametersSchema["properties"].(map[string]interface{})["limit"].(map[string]interface{})
+	if limit["description"] != "How many pets to return" || limit["example"] != nil || len(list.ParametersSchema["properties"].(map[string]interface{})) != 1 {
+		t.Errorf("expected only the query parameter, without its example, got %+v", list.ParametersSchema)
+	}
+	if list.AuthConfig["type"] != "api_key" || list.AuthConfig["header"] != "X-Pet-Key" || list.AuthConfig["value"] != "" {
+		t.Errorf("expected the spec's API key with a blank value, got %+v", list.AuthConfig)
+	}
+
+	create := byName["createPet"]
+	properties := create.ParametersSchema["properties"].(map[string]interface{})
+	if properties["name"] == nil || properties["tag"] == nil || properties["owner"] == nil {
+		t.Errorf("expected the body's merged properties, got %+v", properties)
+	}
+	if !reflect.DeepEqual(create.ParametersSchema["required"], []interface{}{"name"}) || create.AuthConfig["type"] != "bearer" {
+		t.Errorf("expected name to be required and the operation's bearer auth, got %+v", create)
+	}
+
+	show := byName["show_pet_byId"]
+	if show.EndpointURL != "https://eu.pets.example.com/v1/pets/{petId}" || show.Description != "Info for a specific pet" ||
+		!reflect.DeepEqual(show.ParametersSchema["required"], []interface{}{"petId"}) {
+		t.Errorf("expected the path parameter to be required, got %+v", show)
+	}
+}
+
+func TestFunctionsFromOpenAPISelection(t *testing.T) {
+	functions, err := FunctionsFromOpenAPI([]byte(testOpenAPISpec), []string{"createPet", "delete /pets/{petId}"})
+	if err != nil {
+		t.Fatalf("failed to import spec: %v", err)
+	}
+	if len(functions) != 2 || functions[0].Name != "createPet" || functions[1].HttpMethod != "DELETE" {
+		t.Errorf("expected the two selected operations, got %+v", functions)
+	}
+
+	tests := []struct {
+		name       string
+		spec       string
+		operations []string
+	}{
+		{"unknown_operation", testOpenAPISpec, []string{"adoptPet"}},
+		{"swagger_2", "swagger: '2.0'\npaths: {}", nil},
+		{"no_servers", "openapi: 3.1.0\npaths: {/pets: {get: {operationId: listPets}}}", nil},
+		{"relative_server", "openapi: 3.1.0\nservers: [{url: /v1}]\npaths: {/pets: {get: {operationId: listPets}}}", nil},
+		{"no_operations", "openapi: 3.1.0\nservers: [{url: 'https://pets.example.com'}]\npaths: {}", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := FunctionsFromOpenAPI([]byte(tt.spec), tt.operations); !errors.Is(err, ErrInvalidOpenAPISpec) {
+				t.Errorf("expected an invalid spec error, got %v", err)
+			}
+		})
+	}
+}
+
+// roundTripFunc serves HTTP requests with a function
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestImportOpenAPIFunctions(t *testing.T) {
+	client := newFunctionTestClient(t)
+	ctx := context.Background()
+
+	preview, err := client.ImportOpenAPIFunctions(ctx, "user-1", &types.OpenAPIImportRequest{Spec: testOpenAPISpec, DryRun: true})
+	if err != nil || len(preview.Functions) != 4 || preview.Functions[0].ID != "" {
+		t.Fatalf("expected four uncreated functions, got %+v, %v", preview, err)
+	}
+	if count, _ := client.CountFunctionDefinitions(ctx, "user-1"); count != 1 {
+		t.Errorf("expected a dry run to create nothing, got %d functions", count)
+	}
+
+	// The spec's relative server URL resolves against where it was fetched from
+	spec := strings.Replace(testOpenAPISpec, "https://{region}.pets.example.com/v1/", "/v2", 1)
+	client.SetHTTPTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(spec)), Request: req}, nil
+	}))
+	result, err := client.ImportOpenAPIFunctions(ctx, "user-1", &types.OpenAPIImportRequest{
+		URL: "https://docs.example.com/specs/pets.yaml", Operations: []string{"listPets", "createPet"}})
+	if err != nil {
+		t.Fatalf("failed to import spec: %v", err)
+	}
+	if len(result.Functions) != 2 || result.Functions[0].ID == "" || result.Functions[0].EndpointURL != "https://docs.example.com/v2/pets" {
+		t.Errorf("expected two created functions on the spec's host, got %+v", result.Functions)
+	}
+
+	result, err = client.ImportOpenAPIFunctions(ctx, "user-1", &types.OpenAPIImportRequest{Spec: testOpenAPISpec, Operations: []string{"listPets"}})
+	if err != nil || len(result.Functions) != 0 || len(result.Skipped) != 1 || result.Skipped[0].Function != "listPets" {
+		t.Errorf("expected an existing function to be skipped, got %+v, %v", result, err)
+	}
+	if _, err := client.ImportOpenAPIFunctions(ctx, "user-1", &types.OpenAPIImportRequest{URL: "file:///etc/passwd"}); !errors.Is(err, ErrInvalidOpenAPISpec) {
+		t.Errorf("expected a non-HTTP URL to be rejected, got %v", err)
+	}
+}
+
+func TestExpandEndpointPath(t *testing.T) {
+	expanded, remaining, err := expandEndpointPath("https://pets.example.com/pets/{petId}/toys/{toy}",
+		map[string]interface{}{"petId": "a b", "toy": float64(7), "limit": float64(2)})
+	if err != nil || expanded != "https://pets.example.com/pets/a%20b/toys/7" || !reflect.DeepEqual(remaining, map[string]interface{}{"limit": float64(2)}) {
+		t.Errorf("expected the path parameters filled in and removed, got %q, %v, %v", expanded, remaining, err)
+	}
+	if _, _, err := expandEndpointPath("https://pets.example.com/pets/{petId}", nil); err == nil {
+		t.Error("expected a missing path parameter to fail")
+	}
+}
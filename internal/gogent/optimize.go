@@ -0,0 +1,321 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"time"
+
+	"gogent/internal/types"
+)
+
+// ErrInvalidOptimization is returned when an optimization's request or settings cannot be used
+var ErrInvalidOptimization = errors.New("invalid optimization")
+
+const (
+	defaultOptimizationRounds      = 3
+	defaultOptimizationBatchSize   = 4
+	defaultOptimizationExploration = 1.0
+	defaultOptimizationTolerance   = 0.005
+	maxOptimizationRounds          = 20
+
+	// optimizationCandidates is the number of random points the surrogate scores per round
+	optimizationCandidates = 256
+	// optimizationLengthScale is the kernel width as a fraction of each parameter's range
+	optimizationLengthScale = 0.2
+	// minScoreSpread keeps some uncertainty when every observed score is the same
+	minScoreSpread = 0.01
+)
+
+// ExecuteFunc executes one request and returns its compared result, such as a CLI backend's Execute
+type ExecuteFunc func(ctx context.Context, request *types.MultiExecutionRequest) (*types.ExecutionResult, error)
+
+// ValidateOptimizationConfig fills in an optimization's defaults and checks its bounds
+func ValidateOptimizationConfig(config *types.OptimizationConfig) error {
+	if config.Rounds == 0 {
+		config.Rounds = defaultOptimizationRounds
+	}
+	if config.BatchSize == 0 {
+		config.BatchSize = defaultOptimizationBatchSize
+	}
+	if config.Exploration == 0 {
+		config.Exploration = defaultOptimizationExploration
+	}
+	if config.Tolerance == 0 {
+		config.Tolerance = defaultOptimizationTolerance
+	}
+
+	if config.Rounds < 1 || config.Rounds > maxOptimizationRounds {
+		return fmt.Errorf("%w: rounds must be between 1 and %d", ErrInvalidOptimization, maxOptimizationRounds)
+	}
+	if config.BatchSize < 1 || config.BatchSize > maxSweepConfigurations {
+		return fmt.Errorf("%w: batchSize must be between 1 and %d", ErrInvalidOptimization, maxSweepConfigurations)
+	}
+	if config.Exploration < 0 {
+		return fmt.Errorf("%w: exploration cannot be negative", ErrInvalidOptimization)
+	}
+	if config.Tolerance < 0 {
+		return fmt.Errorf("%w: tolerance cannot be negative", ErrInvalidOptimization)
+	}
+	return nil
+}
+
+// Optimize executes the request's sweep, then rounds of configurations proposed from every score
+// so far, until the rounds run out, a round fails to improve the best score by the tolerance, or
+// the search space is exhausted. Each round is its own execution run. Proposals maximize a kernel
+// regression estimate of the overall score plus an uncertainty bonus, so early rounds explore and
+// later ones refine the best region. The sweep defines the search space and must extend a single
+// base configuration.
+func Optimize(ctx context.Context, request *types.MultiExecutionRequest, config *types.OptimizationConfig, execute ExecuteFunc) (*types.OptimizationResult, error) {
+	if request.Sweep == nil {
+		return nil, fmt.Errorf("%w: a sweep is required to define the search space", ErrInvalidOptimization)
+	}
+	if len(request.Configurations) != 1 {
+		return nil, fmt.Errorf("%w: exactly one base configuration is required, got %d", ErrInvalidOptimization, len(request.Configurations))
+	}
+	if err := ValidateParameterSweep(request.Sweep); err != nil {
+		return nil, err
+	}
+	if err := ValidateOptimizationConfig(config); err != nil {
+		return nil, err
+	}
+	if config.Seed == 0 {
+		config.Seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(config.Seed))
+
+	space := newSearchSpace(request.Sweep)
+	base := request.Configurations[0]
+	optimization := &types.OptimizationResult{
+		Metric:     sweepScoreMetric,
+		Seed:       config.Seed,
+		StopReason: types.OptimizationStoppedRounds,
+	}
+	var observations []observation
+	tried := make(map[string]bool)
+
+	for round := 0; round <= config.Rounds; round++ {
+		roundRequest := *request
+		if round > 0 {
+			proposals := space.propose(observations, tried, config, rng)
+			if len(proposals) == 0 {
+				optimization.StopReason = types.OptimizationStoppedExhausted
+				break
+			}
+			roundRequest.Sweep = nil
+			roundRequest.Configurations = make([]types.APIConfiguration, 0, len(proposals))
+			for _, point := range proposals {
+				roundRequest.Configurations = append(roundRequest.Configurations, sweepConfiguration(base, point))
+			}
+			if request.ExecutionRunName != "" {
+				roundRequest.ExecutionRunName = fmt.Sprintf("%s (round %d)", request.ExecutionRunName, round)
+			}
+		}
+
+		result, err := execute(ctx, &roundRequest)
+		if err != nil {
+			return nil, fmt.Errorf("optimization round %d failed: %w", round, err)
+		}
+		if result.Comparison == nil {
+			return nil, fmt.Errorf("optimization round %d was not compared", round)
+		}
+		points := scoredSweepPoints(result)
+		if len(points) == 0 {
+			return nil, fmt.Errorf("optimization round %d produced no results", round)
+		}
+
+		optimization.Rounds = append(optimization.Rounds, types.OptimizationRound{
+			Round:          round,
+			ExecutionRunID: result.ExecutionRun.ID,
+			Points:         points,
+			BestScore:      points[0].Score,
+		})
+		for _, point := range points {
+			values, ok := space.values(point)
+			if !ok {
+				continue
+			}
+			tried[space.point(values).label()] = true
+			observations = append(observations, observation{x: space.normalize(values), score: point.Score})
+		}
+		log.Printf("🔁 Optimization round %d: best %s scored %.3f", round, points[0].VariationName, points[0].Score)
+
+		improved := round == 0 || points[0].Score > optimization.Best.Score+config.Tolerance
+		if round == 0 || points[0].Score > optimization.Best.Score {
+			optimization.Best = points[0]
+			for _, variation := range result.Results {
+				if variation.Configuration.VariationName == points[0].VariationName {
+					optimization.BestConfiguration = variation.Configuration
+					break
+				}
+			}
+		}
+		if !improved {
+			optimization.StopReason = types.OptimizationStoppedConverged
+			break
+		}
+	}
+	return optimization, nil
+}
+
+// observation is a tried point, normalized to the unit cube, and its score
+type observation struct {
+	x     []float64
+	score float64
+}
+
+// searchDimension is one swept parameter and the bounds it is normalized by
+type searchDimension struct {
+	parameter sweepParameter
+	values    *types.SweepRange
+	low, high float64
+	get       func(types.SweepPoint) (float64, bool)
+	set       func(*sweepPoint, float64)
+}
+
+// searchSpace is the parameters an optimization varies
+type searchSpace struct {
+	dimensions []searchDimension
+}
+
+// newSearchSpace returns the space spanned by a validated sweep's ranges
+func newSearchSpace(sweep *types.ParameterSweep) *searchSpace {
+	space := &searchSpace{}
+	add := func(parameter sweepParameter, values *types.SweepRange, get func(types.SweepPoint) (float64, bool), set func(*sweepPoint, float64)) {
+		if values == nil {
+			return
+		}
+		dimension := searchDimension{parameter: parameter, values: values, low: values.Min, high: values.Max, get: get, set: set}
+		if len(values.Values) > 0 {
+			dimension.low, dimension.high = values.Values[0], values.Values[0]
+			for _, value := range values.Values {
+				dimension.low = math.Min(dimension.low, value)
+				dimension.high = math.Max(dimension.high, value)
+			}
+		}
+		space.dimensions = append(space.dimensions, dimension)
+	}
+
+	add(sweepTemperature, sweep.Temperature,
+		func(p types.SweepPoint) (float64, bool) { return floatValue(p.Temperature) },
+		func(p *sweepPoint, v float64) { p.temperature = float32Pointer(v) })
+	add(sweepTopP, sweep.TopP,
+		func(p types.SweepPoint) (float64, bool) { return floatValue(p.TopP) },
+		func(p *sweepPoint, v float64) { p.topP = float32Pointer(v) })
+	add(sweepTopK, sweep.TopK,
+		func(p types.SweepPoint) (float64, bool) {
+			if p.TopK == nil {
+				return 0, false
+			}
+			return float64(*p.TopK), true
+		},
+		func(p *sweepPoint, v float64) { p.topK = int32Pointer(v) })
+	return space
+}
+
+// values returns a scored point's value for each dimension, if it has them all
+func (s *searchSpace) values(point types.SweepPoint) ([]float64, bool) {
+	values := make([]float64, len(s.dimensions))
+	for i, dimension := range s.dimensions {
+		value, ok := dimension.get(point)
+		if !ok {
+			return nil, false
+		}
+		values[i] = value
+	}
+	return values, true
+}
+
+// point turns a value for each dimension into a sweep point
+func (s *searchSpace) point(values []float64) sweepPoint {
+	var point sweepPoint
+	for i, dimension := range s.dimensions {
+		dimension.set(&point, values[i])
+	}
+	return point
+}
+
+// normalize maps values onto the unit cube so every parameter weighs the same in the kernel
+func (s *searchSpace) normalize(values []float64) []float64 {
+	x := make([]float64, len(values))
+	for i, dimension := range s.dimensions {
+		if dimension.high > dimension.low {
+			x[i] = (values[i] - dimension.low) / (dimension.high - dimension.low)
+		}
+	}
+	return x
+}
+
+// propose picks up to BatchSize untried points with the highest upper confidence bound. Each pick
+// joins the observations at its predicted score, so the rest of the batch spreads out.
+func (s *searchSpace) propose(observations []observation, tried map[string]bool, config *types.OptimizationConfig, rng *rand.Rand) []sweepPoint {
+	var candidates [][]float64
+	seen := make(map[string]bool)
+	for i := 0; i < optimizationCandidates; i++ {
+		values := make([]float64, len(s.dimensions))
+		for d, dimension := range s.dimensions {
+			values[d] = sweepRandomValue(rng, dimension.parameter, dimension.values)
+		}
+		label := s.point(values).label()
+		if tried[label] || seen[label] {
+			continue
+		}
+		seen[label] = true
+		candidates = append(candidates, values)
+	}
+
+	observed := append([]observation(nil), observations...)
+	var proposals []sweepPoint
+	for len(proposals) < config.BatchSize && len(candidates) > 0 {
+		best, bestBound, bestMean := -1, 0.0, 0.0
+		for i, candidate := range candidates {
+			mean, uncertainty := predictScore(observed, s.normalize(candidate))
+			if bound := mean + config.Exploration*uncertainty; best < 0 || bound > bestBound {
+				best, bestBound, bestMean = i, bound, mean
+			}
+		}
+
+		chosen := candidates[best]
+		proposals = append(proposals, s.point(chosen))
+		observed = append(observed, observation{x: s.normalize(chosen), score: bestMean})
+		candidates = append(candidates[:best], candidates[best+1:]...)
+	}
+	return proposals
+}
+
+// predictScore estimates the score at x as a kernel-weighted mean of the observed scores, shrunk
+// towards their overall mean where observations are sparse. The uncertainty is the spread of the
+// scores, shrinking as observations close to x accumulate.
+func predictScore(observations []observation, x []float64) (mean, uncertainty float64) {
+	if len(observations) == 0 {
+		return 0, 1
+	}
+
+	var total float64
+	for _, o := range observations {
+		total += o.score
+	}
+	prior := total / float64(len(observations))
+
+	var variance float64
+	for _, o := range observations {
+		variance += (o.score - prior) * (o.score - prior)
+	}
+	spread := math.Max(math.Sqrt(variance/float64(len(observations))), minScoreSpread)
+
+	// The prior counts as one observation
+	weight, weighted := 1.0, prior
+	for _, o := range observations {
+		var distance float64
+		for i := range x {
+			distance += (o.x[i] - x[i]) * (o.x[i] - x[i])
+		}
+		w := math.Exp(-distance / (2 * optimizationLengthScale * optimizationLengthScale))
+		weight += w
+		weighted += w * o.score
+	}
+	return weighted / weight, spread / math.Sqrt(weight)
+}
@@ -0,0 +1,126 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"gogent/internal/types"
+)
+
+// fakeOptimizationExecute expands a request's sweep and scores each configuration with score,
+// recording the requests it was given
+func fakeOptimizationExecute(score func(types.APIConfiguration) float64, requests *[]*types.MultiExecutionRequest) ExecuteFunc {
+	return func(ctx context.Context, request *types.MultiExecutionRequest) (*types.ExecutionResult, error) {
+		if err := ExpandParameterSweep(request); err != nil {
+			return nil, err
+		}
+		*requests = append(*requests, request)
+
+		result := &types.ExecutionResult{
+			ExecutionRun: types.ExecutionRun{ID: fmt.Sprintf("run-%d", len(*requests)), Name: request.ExecutionRunName},
+			Comparison:   &types.ComparisonResult{ConfigurationScores: make(map[string]interface{})},
+		}
+		for _, config := range request.Configurations {
+			result.Results = append(result.Results, types.VariationResult{Configuration: config})
+			result.Comparison.ConfigurationScores[config.VariationName] = map[string]interface{}{"overall_score": score(config)}
+		}
+		return result, nil
+	}
+}
+
+// peakedScore is highest at temperature 0.7
+func peakedScore(config types.APIConfiguration) float64 {
+	distance := float64(*config.Temperature) - 0.7
+	return 1 - distance*distance
+}
+
+func newOptimizationRequest(temperatures *types.SweepRange) *types.MultiExecutionRequest {
+	return &types.MultiExecutionRequest{
+		ExecutionRunName: "Tune",
+		Configurations:   []types.APIConfiguration{{VariationName: "flash", ModelName: "gemini-2.0-flash"}},
+		Sweep:            &types.ParameterSweep{Temperature: temperatures},
+	}
+}
+
+func TestOptimize(t *testing.T) {
+	var requests []*types.MultiExecutionRequest
+	config := &types.OptimizationConfig{Rounds: 4, BatchSize: 3, Seed: 7, Tolerance: 0.0001}
+	result, err := Optimize(context.Background(), newOptimizationRequest(&types.SweepRange{Min: 0.1, Max: 0.9, Step: 0.4}), config,
+		fakeOptimizationExecute(peakedScore, &requests))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Rounds) < 2 || result.Rounds[0].ExecutionRunID != "run-1" || len(result.Rounds[0].Points) != 3 {
+		t.Fatalf("expected the sweep followed by proposed rounds, got %+v", result.Rounds)
+	}
+	if requests[1].Sweep != nil || len(requests[1].Configurations) != 3 || requests[1].ExecutionRunName != "Tune (round 1)" {
+		t.Errorf("expected round 1 to run three proposed configurations, got %+v", requests[1])
+	}
+	if result.Best.Score <= result.Rounds[0].BestScore {
+		t.Errorf("expected the proposals to beat the sweep's best score %.4f, got %+v", result.Rounds[0].BestScore, result.Best)
+	}
+	if temperature := *result.BestConfiguration.Temperature; temperature < 0.55 || temperature > 0.85 {
+		t.Errorf("expected the best temperature to approach 0.7, got %v", temperature)
+	}
+	if result.BestConfiguration.ModelName != "gemini-2.0-flash" || result.BestConfiguration.VariationName != result.Best.VariationName {
+		t.Errorf("expected the best configuration to extend the base, got %+v", result.BestConfiguration)
+	}
+
+	// The same seed proposes the same configurations
+	var repeated []*types.MultiExecutionRequest
+	if _, err := Optimize(context.Background(), newOptimizationRequest(&types.SweepRange{Min: 0.1, Max: 0.9, Step: 0.4}),
+		&types.OptimizationConfig{Rounds: 4, BatchSize: 3, Seed: 7, Tolerance: 0.0001}, fakeOptimizationExecute(peakedScore, &repeated)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(requests[1].Configurations, repeated[1].Configurations) {
+		t.Error("expected the same seed to propose the same configurations")
+	}
+}
+
+func TestOptimizeStops(t *testing.T) {
+	// Every value of a two-value space is tried by the sweep
+	var requests []*types.MultiExecutionRequest
+	result, err := Optimize(context.Background(), newOptimizationRequest(&types.SweepRange{Values: []float64{0.2, 0.6}}),
+		&types.OptimizationConfig{Seed: 1}, fakeOptimizationExecute(peakedScore, &requests))
+	if err != nil || result.StopReason != types.OptimizationStoppedExhausted || len(requests) != 1 {
+		t.Errorf("expected the optimization to exhaust the space after the sweep, got %+v (%v)", result, err)
+	}
+
+	// A flat score never improves
+	requests = nil
+	flat := func(types.APIConfiguration) float64 { return 0.5 }
+	result, err = Optimize(context.Background(), newOptimizationRequest(&types.SweepRange{Min: 0, Max: 1, Step: 0.5}),
+		&types.OptimizationConfig{Seed: 1}, fakeOptimizationExecute(flat, &requests))
+	if err != nil || result.StopReason != types.OptimizationStoppedConverged || len(result.Rounds) != 2 {
+		t.Errorf("expected the optimization to converge after one round, got %+v (%v)", result, err)
+	}
+}
+
+func TestOptimizeErrors(t *testing.T) {
+	var requests []*types.MultiExecutionRequest
+	execute := fakeOptimizationExecute(peakedScore, &requests)
+
+	noSweep := newOptimizationRequest(nil)
+	noSweep.Sweep = nil
+	if _, err := Optimize(context.Background(), noSweep, &types.OptimizationConfig{}, execute); !errors.Is(err, ErrInvalidOptimization) {
+		t.Errorf("expected ErrInvalidOptimization without a sweep, got %v", err)
+	}
+
+	twoBases := newOptimizationRequest(&types.SweepRange{Values: []float64{0.2}})
+	twoBases.Configurations = append(twoBases.Configurations, types.APIConfiguration{ModelName: "gemini-1.5-pro"})
+	if _, err := Optimize(context.Background(), twoBases, &types.OptimizationConfig{}, execute); !errors.Is(err, ErrInvalidOptimization) {
+		t.Errorf("expected ErrInvalidOptimization with two base configurations, got %v", err)
+	}
+
+	tooMany := newOptimizationRequest(&types.SweepRange{Values: []float64{0.2}})
+	if _, err := Optimize(context.Background(), tooMany, &types.OptimizationConfig{Rounds: 50}, execute); !errors.Is(err, ErrInvalidOptimization) {
+		t.Errorf("expected ErrInvalidOptimization for too many rounds, got %v", err)
+	}
+	if len(requests) != 0 {
+		t.Errorf("expected nothing to execute, got %d requests", len(requests))
+	}
+}
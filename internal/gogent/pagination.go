@@ -0,0 +1,71 @@
+package gogent
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// MaxPageSize caps the number of items a single list page returns
+const MaxPageSize = 500
+
+// cursorPrefix marks a decoded cursor as an offset cursor
+const cursorPrefix = "offset:"
+
+// ErrInvalidCursor is returned when a page cursor was not produced by EncodeCursor
+var ErrInvalidCursor = errors.New("invalid page cursor")
+
+// EncodeCursor returns the opaque cursor of the page starting at offset
+func EncodeCursor(offset int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.FormatInt(offset, 10)))
+}
+
+// DecodeCursor returns the offset a cursor from EncodeCursor points at
+func DecodeCursor(cursor string) (int32, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	value, ok := strings.CutPrefix(string(decoded), cursorPrefix)
+	if !ok {
+		return 0, ErrInvalidCursor
+	}
+	offset, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || offset < 0 {
+		return 0, ErrInvalidCursor
+	}
+	return int32(offset), nil
+}
+
+// ResolvePage clamps a page's limit to [1, MaxPageSize], using defaultLimit when it is unset,
+// and returns the offset the cursor points at, or offset itself when there is no cursor
+func ResolvePage(limit, offset int32, cursor string, defaultLimit int32) (int32, int32, error) {
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+	if cursor != "" {
+		decoded, err := DecodeCursor(cursor)
+		if err != nil {
+			return 0, 0, err
+		}
+		offset = decoded
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset, nil
+}
+
+// NextCursor returns the cursor of the page after one of returned items starting at offset,
+// or "" when that page reached the end of total items
+func NextCursor(offset int32, returned int, total int64) string {
+	next := int64(offset) + int64(returned)
+	if returned == 0 || next >= total {
+		return ""
+	}
+	return EncodeCursor(next)
+}
@@ -0,0 +1,59 @@
+package gogent
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	for _, offset := range []int64{0, 1, 250} {
+		decoded, err := DecodeCursor(EncodeCursor(offset))
+		if err != nil || int64(decoded) != offset {
+			t.Errorf("expected offset %d back, got %d (%v)", offset, decoded, err)
+		}
+	}
+
+	for _, cursor := range []string{"not base64!", "b2Zmc2V0Oi0x", "MTA"} {
+		if _, err := DecodeCursor(cursor); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("expected ErrInvalidCursor for %q, got %v", cursor, err)
+		}
+	}
+}
+
+func TestResolvePage(t *testing.T) {
+	tests := []struct {
+		name                  string
+		limit, offset         int32
+		cursor                string
+		wantLimit, wantOffset int32
+	}{
+		{"defaults", 0, 0, "", 10, 0},
+		{"clamps the limit", MaxPageSize + 1, 0, "", MaxPageSize, 0},
+		{"clamps a negative offset", 5, -3, "", 5, 0},
+		{"cursor overrides the offset", 5, 2, EncodeCursor(40), 5, 40},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit, offset, err := ResolvePage(tt.limit, tt.offset, tt.cursor, 10)
+			if err != nil || limit != tt.wantLimit || offset != tt.wantOffset {
+				t.Errorf("expected %d/%d, got %d/%d (%v)", tt.wantLimit, tt.wantOffset, limit, offset, err)
+			}
+		})
+	}
+
+	if _, _, err := ResolvePage(5, 0, "garbage", 10); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+func TestNextCursor(t *testing.T) {
+	if next := NextCursor(0, 10, 25); next != EncodeCursor(10) {
+		t.Errorf("expected a cursor to offset 10, got %q", next)
+	}
+	if next := NextCursor(20, 5, 25); next != "" {
+		t.Errorf("expected no cursor on the last page, got %q", next)
+	}
+	if next := NextCursor(30, 0, 25); next != "" {
+		t.Errorf("expected no cursor past the end, got %q", next)
+	}
+}
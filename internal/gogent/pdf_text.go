@@ -0,0 +1,321 @@
+package gogent
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// maxPDFStreamBytes caps how much a single decompressed PDF stream may expand to
+const maxPDFStreamBytes = 32 << 20
+
+// pdfFontFileLength matches the /Length1 to /Length3 entries only embedded font streams have
+var pdfFontFileLength = regexp.MustCompile(`/Length[123][\s/>]`)
+
+// extractPDFText returns the text drawn by a PDF's content streams. It reads uncompressed and
+// Flate-compressed streams and decodes strings as PDFDocEncoding or UTF-16, which covers PDFs
+// exported by office tools and browsers; scanned PDFs and fonts with custom encodings yield no text.
+func extractPDFText(data []byte) (string, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(data, " \t\r\n"), []byte("%PDF-")) {
+		return "", fmt.Errorf("not a PDF file")
+	}
+
+	var text strings.Builder
+	for offset := 0; ; {
+		start := bytes.Index(data[offset:], []byte("stream"))
+		if start < 0 {
+			break
+		}
+		start += offset
+		offset = start + len("stream")
+
+		// "endstream" also contains the keyword
+		if start >= 3 && string(data[start-3:start]) == "end" {
+			continue
+		}
+		dictionary := pdfStreamDictionary(data[:start])
+		body := offset
+		if body < len(data) && data[body] == '\r' {
+			body++
+		}
+		if body < len(data) && data[body] == '\n' {
+			body++
+		}
+		end := bytes.Index(data[body:], []byte("endstream"))
+		if end < 0 {
+			break
+		}
+		offset = body + end + len("endstream")
+
+		if !pdfContentStream(dictionary) {
+			continue
+		}
+		content := data[body : body+end]
+		if strings.Contains(dictionary, "/FlateDecode") {
+			inflated, err := inflatePDFStream(content)
+			if err != nil {
+				continue
+			}
+			content = inflated
+		} else if strings.Contains(dictionary, "/Filter") {
+			continue
+		}
+		pdfContentText(content, &text)
+	}
+	return normalizeExtractedText(text.String()), nil
+}
+
+// pdfStreamDictionary returns the dictionary of the object whose stream starts at the end of data
+func pdfStreamDictionary(data []byte) string {
+	from := max(0, len(data)-4096)
+	window := data[from:]
+	if obj := bytes.LastIndex(window, []byte(" obj")); obj >= 0 {
+		window = window[obj:]
+	}
+	return string(window)
+}
+
+// pdfContentStream reports whether a stream holds page or form content rather than fonts, images,
+// metadata or cross-reference data
+func pdfContentStream(dictionary string) bool {
+	compact := strings.ReplaceAll(dictionary, " ", "")
+	if strings.Contains(compact, "/Subtype") && !strings.Contains(compact, "/Subtype/Form") {
+		return false
+	}
+	for _, kind := range []string{"/Type/XRef", "/Type/ObjStm", "/Type/Metadata"} {
+		if strings.Contains(compact, kind) {
+			return false
+		}
+	}
+	return !pdfFontFileLength.MatchString(dictionary)
+}
+
+// inflatePDFStream decompresses a Flate stream, keeping what was read before any trailing garbage
+func inflatePDFStream(content []byte) ([]byte, error) {
+	reader, err := zlib.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	inflated, err := io.ReadAll(io.LimitReader(reader, maxPDFStreamBytes))
+	if len(inflated) > 0 {
+		return inflated, nil
+	}
+	return nil, err
+}
+
+// pdfContentText appends the strings shown between BT and ET operators of a content stream,
+// starting a new line whenever the text position moves down
+func pdfContentText(content []byte, text *strings.Builder) {
+	var operands []string
+	var numbers []float64
+	inText := false
+	inArray := false
+	var array strings.Builder
+
+	for i := 0; i < len(content); {
+		ch := content[i]
+		switch {
+		case ch == '%':
+			for i < len(content) && content[i] != '\n' && content[i] != '\r' {
+				i++
+			}
+		case ch == '(':
+			value, next := readPDFLiteralString(content, i)
+			i = next
+			if inArray {
+				array.WriteString(value)
+			} else {
+				operands = append(operands, value)
+			}
+		case ch == '<' && i+1 < len(content) && content[i+1] == '<':
+			i += 2
+		case ch == '>' && i+1 < len(content) && content[i+1] == '>':
+			i += 2
+		case ch == '<':
+			value, next := readPDFHexString(content, i)
+			i = next
+			if inArray {
+				array.WriteString(value)
+			} else {
+				operands = append(operands, value)
+			}
+		case ch == '[':
+			inArray = true
+			array.Reset()
+			i++
+		case ch == ']':
+			inArray = false
+			operands = append(operands, array.String())
+			i++
+		case ch == '/':
+			i++
+			for i < len(content) && !pdfDelimiter(content[i]) {
+				i++
+			}
+		case pdfDelimiter(ch):
+			i++
+		default:
+			start := i
+			for i < len(content) && !pdfDelimiter(content[i]) {
+				i++
+			}
+			token := string(content[start:i])
+			if number, err := strconv.ParseFloat(token, 64); err == nil {
+				// Large negative kerning inside TJ arrays separates words
+				if inArray && number < -200 {
+					array.WriteByte(' ')
+				} else if !inArray {
+					numbers = append(numbers, number)
+				}
+				continue
+			}
+
+			switch token {
+			case "BT":
+				inText = true
+			case "ET":
+				inText = false
+				text.WriteByte('\n')
+			case "Tj", "TJ", "'", "\"":
+				if inText && len(operands) > 0 {
+					if token == "'" || token == "\"" {
+						text.WriteByte('\n')
+					}
+					text.WriteString(operands[len(operands)-1])
+				}
+			case "T*":
+				text.WriteByte('\n')
+			case "Td", "TD":
+				if len(numbers) >= 2 && numbers[len(numbers)-1] != 0 {
+					text.WriteByte('\n')
+				} else {
+					text.WriteByte(' ')
+				}
+			case "Tm":
+				text.WriteByte('\n')
+			}
+			operands = operands[:0]
+			numbers = numbers[:0]
+		}
+	}
+}
+
+// pdfDelimiter reports whether a byte ends a PDF token
+func pdfDelimiter(ch byte) bool {
+	switch ch {
+	case ' ', '\t', '\r', '\n', '\f', 0, '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+// readPDFLiteralString decodes the (...) string starting at content[start], returning it and the
+// offset after its closing parenthesis
+func readPDFLiteralString(content []byte, start int) (string, int) {
+	var raw []byte
+	depth := 0
+	i := start
+	for ; i < len(content); i++ {
+		ch := content[i]
+		switch ch {
+		case '(':
+			depth++
+			if depth == 1 {
+				continue
+			}
+		case ')':
+			depth--
+			if depth == 0 {
+				return decodePDFString(raw), i + 1
+			}
+		case '\\':
+			i++
+			if i >= len(content) {
+				break
+			}
+			escaped := content[i]
+			switch escaped {
+			case 'n':
+				raw = append(raw, '\n')
+			case 'r':
+				raw = append(raw, '\r')
+			case 't':
+				raw = append(raw, '\t')
+			case 'b':
+				raw = append(raw, '\b')
+			case 'f':
+				raw = append(raw, '\f')
+			case '\r', '\n':
+				// Line continuation
+				if escaped == '\r' && i+1 < len(content) && content[i+1] == '\n' {
+					i++
+				}
+			default:
+				if escaped >= '0' && escaped <= '7' {
+					value := 0
+					for n := 0; n < 3 && i < len(content) && content[i] >= '0' && content[i] <= '7'; n++ {
+						value = value*8 + int(content[i]-'0')
+						i++
+					}
+					i--
+					raw = append(raw, byte(value))
+				} else {
+					raw = append(raw, escaped)
+				}
+			}
+			continue
+		}
+		raw = append(raw, ch)
+	}
+	return decodePDFString(raw), i
+}
+
+// readPDFHexString decodes the <...> string starting at content[start], returning it and the
+// offset after its closing bracket
+func readPDFHexString(content []byte, start int) (string, int) {
+	end := bytes.IndexByte(content[start:], '>')
+	if end < 0 {
+		return "", len(content)
+	}
+	var digits []byte
+	for _, ch := range content[start+1 : start+end] {
+		if strings.IndexByte("0123456789abcdefABCDEF", ch) >= 0 {
+			digits = append(digits, ch)
+		}
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	raw := make([]byte, len(digits)/2)
+	for i := range raw {
+		value, _ := strconv.ParseUint(string(digits[2*i:2*i+2]), 16, 8)
+		raw[i] = byte(value)
+	}
+	return decodePDFString(raw), start + end + 1
+}
+
+// decodePDFString decodes a PDF string as UTF-16 when it starts with a byte order mark and as
+// PDFDocEncoding, which matches Latin-1 for printable characters, otherwise
+func decodePDFString(raw []byte) string {
+	if len(raw) >= 2 && raw[0] == 0xFE && raw[1] == 0xFF {
+		units := make([]uint16, 0, len(raw)/2)
+		for i := 2; i+1 < len(raw); i += 2 {
+			units = append(units, uint16(raw[i])<<8|uint16(raw[i+1]))
+		}
+		return string(utf16.Decode(units))
+	}
+	runes := make([]rune, 0, len(raw))
+	for _, b := range raw {
+		if b < 0x20 && b != '\n' && b != '\t' {
+			continue
+		}
+		runes = append(runes, rune(b))
+	}
+	return string(runes)
+}
@@ -0,0 +1,144 @@
+package gogent
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gogent/internal/db"
+	"gogent/internal/types"
+)
+
+// interruptedRunError is recorded on runs RepairInterruptedRuns finds still pending or running
+const interruptedRunError = "run was interrupted before it finished"
+
+// persistVariation stores a variation's request, the function calls made for it and its response,
+// followed by the response's other candidates, in one transaction after its model call returns, so a
+// run that stops partway leaves either all of a variation's records or none of them, never a request
+// without its response
+func (c *Client) persistVariation(ctx context.Context, userID string, request *types.APIRequest, response *types.APIResponse, calls []types.FunctionCall) error {
+	redactor := c.payloadRedactor()
+	return c.store.InTransaction(ctx, func(store Store) error {
+		if err := store.CreateAPIRequest(ctx, userID, redactor.redactRequest(request)); err != nil {
+			return fmt.Errorf("failed to log API request: %w", err)
+		}
+		for i := range calls {
+			if err := store.CreateFunctionCall(ctx, &calls[i]); err != nil {
+				return fmt.Errorf("failed to log function call: %w", err)
+			}
+		}
+		if err := store.CreateAPIResponse(ctx, userID, redactor.redactResponse(response)); err != nil {
+			return fmt.Errorf("failed to log API response: %w", err)
+		}
+		for i := range response.Candidates {
+			if err := store.CreateAPIResponse(ctx, userID, redactor.redactResponse(&response.Candidates[i])); err != nil {
+				return fmt.Errorf("failed to log API response candidate: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// requestTables are the tables an unanswered request has rows in, children before the request.
+// Retrieved chunks, guard verdicts and raw payloads carry no foreign key, so a cascade would leave
+// them behind.
+var requestTables = []struct{ name, column string }{
+	{"retrieved_chunks", "request_id"},
+	{"guard_verdicts", "request_id"},
+	{"raw_payloads", "request_id"},
+	{"function_calls", "request_id"},
+	{"execution_logs", "request_id"},
+	{"api_requests", "id"},
+}
+
+// RepairInterruptedRuns restores consistency after a process stopped mid-run. Runs created before
+// cutoff that are still pending or running are marked failed, and their requests that never got a
+// response are deleted with their rows, children first. Retrieved chunks and guard verdicts whose
+// request is gone are deleted too. The cutoff keeps runs another process is still executing out of
+// the repair.
+func (c *Client) RepairInterruptedRuns(ctx context.Context, cutoff time.Time) (*types.RepairReport, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	runIDs, err := queryIDs(ctx, c.db, "SELECT id FROM execution_runs WHERE status IN ('pending', 'running') AND created_at < ?", cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find interrupted runs: %w", err)
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	report := &types.RepairReport{Deleted: make(map[string]int64)}
+	for _, batch := range idBatches(runIDs) {
+		result, err := tx.ExecContext(ctx, "UPDATE execution_runs SET status = 'failed', error_message = ? WHERE id IN ("+placeholders(len(batch))+")",
+			append([]interface{}{interruptedRunError}, batch...)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fail interrupted runs: %w", err)
+		}
+		failed, _ := result.RowsAffected()
+		report.RunsFailed += failed
+
+		requestIDs, err := queryIDs(ctx, tx, `
+			SELECT r.id FROM api_requests r
+			LEFT JOIN api_responses p ON p.request_id = r.id
+			WHERE p.id IS NULL AND r.execution_run_id IN (`+placeholders(len(batch))+")", batch...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find unanswered requests: %w", err)
+		}
+		for _, requests := range idBatches(requestIDs) {
+			for _, table := range requestTables {
+				result, err := tx.ExecContext(ctx, "DELETE FROM "+table.name+" WHERE "+table.column+" IN ("+placeholders(len(requests))+")", requests...)
+				if err != nil {
+					return nil, fmt.Errorf("failed to delete unanswered requests from %s: %w", table.name, err)
+				}
+				deleted, _ := result.RowsAffected()
+				report.Deleted[table.name] += deleted
+			}
+		}
+	}
+
+	for _, table := range []string{"retrieved_chunks", "guard_verdicts", "raw_payloads"} {
+		result, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE request_id NOT IN (SELECT id FROM api_requests)")
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete orphaned %s: %w", table, err)
+		}
+		deleted, _ := result.RowsAffected()
+		report.Deleted[table] += deleted
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit repair: %w", err)
+	}
+	return report, nil
+}
+
+// queryIDs returns the IDs a single-column query selects
+func queryIDs(ctx context.Context, conn db.DBTX, query string, args ...interface{}) ([]interface{}, error) {
+	rows, err := conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []interface{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// idBatches splits IDs into batches small enough for one IN list
+func idBatches(ids []interface{}) [][]interface{} {
+	var batches [][]interface{}
+	for start := 0; start < len(ids); start += defaultPruneBatchSize {
+		batches = append(batches, ids[start:min(start+defaultPruneBatchSize, len(ids))])
+	}
+	return batches
+}
@@ -0,0 +1,120 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gogent/internal/testdb"
+	"gogent/internal/types"
+)
+
+func TestPersistVariation(t *testing.T) {
+	client, store := newStoreTestClient(t)
+	ctx := context.Background()
+
+	request := &types.APIRequest{ID: "request-1", ExecutionRunID: "run-1", Prompt: "Weather in Paris?"}
+	response := &types.APIResponse{ID: "response-1", RequestID: request.ID, ResponseText: "Sunny"}
+	calls := []types.FunctionCall{{ID: "call-1", RequestID: request.ID, FunctionName: "get_weather"}}
+	if err := client.persistVariation(ctx, "user-1", request, response, calls); err != nil {
+		t.Fatalf("failed to persist variation: %v", err)
+	}
+	requests, _ := store.ListAPIRequestsByRun(ctx, "user-1", "run-1")
+	responses, _ := store.ListAPIResponsesByRun(ctx, "user-1", "run-1")
+	if len(requests) != 1 || len(responses) != 1 || len(store.FunctionCalls(request.ID)) != 1 {
+		t.Errorf("expected the request, response and call to be stored, got %d, %d, %d", len(requests), len(responses), len(store.FunctionCalls(request.ID)))
+	}
+}
+
+func TestMemoryStoreInTransaction(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	failure := errors.New("response failed")
+
+	err := store.InTransaction(ctx, func(tx Store) error {
+		if err := tx.CreateAPIRequest(ctx, "user-1", &types.APIRequest{ID: "request-1", ExecutionRunID: "run-1"}); err != nil {
+			return err
+		}
+		if err := tx.CreateFunctionCall(ctx, &types.FunctionCall{ID: "call-1", RequestID: "request-1"}); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("expected the transaction's error, got %v", err)
+	}
+	if requests, _ := store.ListAPIRequestsByRun(ctx, "user-1", "run-1"); len(requests) != 0 || len(store.FunctionCalls("request-1")) != 0 {
+		t.Errorf("expected a failed transaction to store nothing, got %+v", requests)
+	}
+
+	err = store.InTransaction(ctx, func(tx Store) error {
+		if err := tx.CreateAPIRequest(ctx, "user-1", &types.APIRequest{ID: "request-2", ExecutionRunID: "run-1"}); err != nil {
+			return err
+		}
+		if requests, _ := store.ListAPIRequestsByRun(ctx, "user-1", "run-1"); len(requests) != 0 {
+			t.Error("expected the request to be held back until the transaction commits")
+		}
+		return tx.InTransaction(ctx, func(nested Store) error {
+			return nested.CreateAPIResponse(ctx, "user-1", &types.APIResponse{ID: "response-2", RequestID: "request-2"})
+		})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	requests, _ := store.ListAPIRequestsByRun(ctx, "user-1", "run-1")
+	responses, _ := store.ListAPIResponsesByRun(ctx, "user-1", "run-1")
+	if len(requests) != 1 || len(responses) != 1 {
+		t.Errorf("expected the committed request and its response, got %+v, %+v", requests, responses)
+	}
+}
+
+func TestRepairInterruptedRuns(t *testing.T) {
+	database := testdb.Open(t)
+
+	old := time.Now().Add(-2 * time.Hour)
+	_, err := database.Exec(`
+		INSERT INTO api_requests (id, execution_run_id) VALUES ('answered', 'stuck'), ('unanswered', 'stuck'), ('in-flight', 'live'), ('failed-run', 'done');
+		INSERT INTO api_responses (id, request_id) VALUES ('response-1', 'answered');
+		INSERT INTO function_calls (id, request_id) VALUES ('call-1', 'unanswered'), ('call-2', 'answered');
+		INSERT INTO execution_logs (id, request_id) VALUES ('log-1', 'unanswered');
+		INSERT INTO retrieved_chunks (request_id, chunk_rank) VALUES ('unanswered', 1), ('pruned', 1), ('in-flight', 1);
+		INSERT INTO guard_verdicts (request_id, position) VALUES ('pruned', 1);
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+	for _, run := range []struct {
+		id, status string
+		createdAt  time.Time
+	}{{"stuck", "running", old}, {"live", "running", time.Now()}, {"done", "failed", old}} {
+		if _, err := database.Exec("INSERT INTO execution_runs (id, status, created_at) VALUES (?, ?, ?)", run.id, run.status, run.createdAt); err != nil {
+			t.Fatalf("failed to insert run: %v", err)
+		}
+	}
+
+	client := &Client{db: database}
+	report, err := client.RepairInterruptedRuns(context.Background(), time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("failed to repair runs: %v", err)
+	}
+	if report.RunsFailed != 1 || report.Deleted["api_requests"] != 1 || report.Deleted["function_calls"] != 1 ||
+		report.Deleted["execution_logs"] != 1 || report.Deleted["retrieved_chunks"] != 2 || report.Deleted["guard_verdicts"] != 1 {
+		t.Errorf("expected the stuck run's unanswered request and the orphaned rows to be repaired, got %+v", report)
+	}
+
+	var status, errorMessage string
+	database.QueryRow("SELECT status, error_message FROM execution_runs WHERE id = 'stuck'").Scan(&status, &errorMessage)
+	if status != "failed" || errorMessage != interruptedRunError {
+		t.Errorf("expected the stuck run to be failed, got %s: %s", status, errorMessage)
+	}
+	var remaining int
+	database.QueryRow("SELECT COUNT(*) FROM api_requests").Scan(&remaining)
+	if remaining != 3 {
+		t.Errorf("expected the answered, in-flight and finished runs' requests to remain, got %d", remaining)
+	}
+
+	if _, err := (&Client{}).RepairInterruptedRuns(context.Background(), time.Now()); !errors.Is(err, ErrNoDatabase) {
+		t.Errorf("expected ErrNoDatabase, got %v", err)
+	}
+}
@@ -0,0 +1,339 @@
+package gogent
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"gogent/internal/types"
+
+	"github.com/google/uuid"
+)
+
+// ErrPresetNotFound is returned when a configuration preset does not exist or is not owned by the user
+var ErrPresetNotFound = errors.New("configuration preset not found")
+
+// ErrPresetNameTaken is returned when a user already has a preset with the same name
+var ErrPresetNameTaken = errors.New("configuration preset name already in use")
+
+// configurationPresetColumns is the column list read by scanConfigurationPreset
+const configurationPresetColumns = `id, user_id, name, description, configuration, created_at, updated_at`
+
+// ValidateConfigurationPreset checks a preset's name and model and clears the run-specific fields of its configuration
+func ValidateConfigurationPreset(preset *types.ConfigurationPreset) error {
+	if preset.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(preset.Name) > 255 {
+		return fmt.Errorf("name must be at most 255 characters")
+	}
+	if preset.Configuration.ModelName == "" {
+		return fmt.Errorf("configuration.modelName is required")
+	}
+	if preset.Configuration.PresetID != "" {
+		return fmt.Errorf("a preset cannot be based on another preset")
+	}
+
+	config := &preset.Configuration
+	config.ID = ""
+	config.ExecutionRunID = ""
+	config.VariationName = ""
+	config.CreatedAt = time.Time{}
+	config.Seed = nil
+	config.Deterministic = false
+	return nil
+}
+
+// scanConfigurationPreset reads a row selected with configurationPresetColumns
+func scanConfigurationPreset(row rowScanner) (*types.ConfigurationPreset, error) {
+	var preset types.ConfigurationPreset
+	var description sql.NullString
+	var configuration string
+
+	err := row.Scan(&preset.ID, &preset.UserID, &preset.Name, &description, &configuration,
+		&preset.CreatedAt, &preset.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	preset.Description = description.String
+	if err := types.FromJSON(configuration, &preset.Configuration); err != nil {
+		return nil, fmt.Errorf("failed to parse configuration of preset %s: %w", preset.Name, err)
+	}
+	return &preset, nil
+}
+
+// presetNameOwner returns the ID of the user's preset with a name, if there is one
+func (c *Client) presetNameOwner(ctx context.Context, userID, name string) (string, bool, error) {
+	if c.db == nil {
+		return "", false, ErrNoDatabase
+	}
+	var id string
+	err := c.db.QueryRowContext(ctx,
+		"SELECT id FROM configuration_presets WHERE user_id = ? AND name = ?",
+		userID, name,
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to look up configuration preset: %w", err)
+	}
+	return id, true, nil
+}
+
+// CreateConfigurationPreset saves a new preset for the user
+func (c *Client) CreateConfigurationPreset(ctx context.Context, userID string, preset *types.ConfigurationPreset) (*types.ConfigurationPreset, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	if err := ValidateConfigurationPreset(preset); err != nil {
+		return nil, err
+	}
+
+	if _, found, err := c.presetNameOwner(ctx, userID, preset.Name); err != nil {
+		return nil, err
+	} else if found {
+		return nil, fmt.Errorf("%w: %s", ErrPresetNameTaken, preset.Name)
+	}
+
+	configuration, err := types.ToJSON(preset.Configuration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode preset configuration: %w", err)
+	}
+
+	id := uuid.New().String()
+	now := time.Now()
+	_, err = c.db.ExecContext(ctx, `
+		INSERT INTO configuration_presets (id, user_id, name, description, configuration, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, id, userID, preset.Name, nullableString(preset.Description), configuration, now, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create configuration preset: %w", err)
+	}
+	return c.GetConfigurationPreset(ctx, userID, id)
+}
+
+// GetConfigurationPreset loads one of the user's presets
+func (c *Client) GetConfigurationPreset(ctx context.Context, userID, id string) (*types.ConfigurationPreset, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	row := c.db.QueryRowContext(ctx,
+		"SELECT "+configurationPresetColumns+" FROM configuration_presets WHERE id = ? AND user_id = ?",
+		id, userID,
+	)
+	preset, err := scanConfigurationPreset(row)
+	if err == sql.ErrNoRows {
+		return nil, ErrPresetNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get configuration preset: %w", err)
+	}
+	return preset, nil
+}
+
+// ListConfigurationPresets returns a page of the user's presets ordered by name
+func (c *Client) ListConfigurationPresets(ctx context.Context, userID string, limit, offset int32) ([]*types.ConfigurationPreset, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	rows, err := c.db.QueryContext(ctx,
+		"SELECT "+configurationPresetColumns+" FROM configuration_presets WHERE user_id = ? ORDER BY name ASC LIMIT ? OFFSET ?",
+		userID, limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list configuration presets: %w", err)
+	}
+	defer rows.Close()
+
+	var presets []*types.ConfigurationPreset
+	for rows.Next() {
+		preset, err := scanConfigurationPreset(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan configuration preset: %w", err)
+		}
+		presets = append(presets, preset)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate configuration presets: %w", err)
+	}
+	return presets, nil
+}
+
+// CountConfigurationPresets counts the user's presets
+func (c *Client) CountConfigurationPresets(ctx context.Context, userID string) (int64, error) {
+	if c.db == nil {
+		return 0, ErrNoDatabase
+	}
+	var count int64
+	if err := c.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM configuration_presets WHERE user_id = ?", userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count configuration presets: %w", err)
+	}
+	return count, nil
+}
+
+// UpdateConfigurationPreset replaces the name, description and configuration of one of the user's presets
+func (c *Client) UpdateConfigurationPreset(ctx context.Context, userID, id string, preset *types.ConfigurationPreset) (*types.ConfigurationPreset, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	if err := ValidateConfigurationPreset(preset); err != nil {
+		return nil, err
+	}
+	if _, err := c.GetConfigurationPreset(ctx, userID, id); err != nil {
+		return nil, err
+	}
+
+	if existingID, found, err := c.presetNameOwner(ctx, userID, preset.Name); err != nil {
+		return nil, err
+	} else if found && existingID != id {
+		return nil, fmt.Errorf("%w: %s", ErrPresetNameTaken, preset.Name)
+	}
+
+	configuration, err := types.ToJSON(preset.Configuration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode preset configuration: %w", err)
+	}
+	_, err = c.db.ExecContext(ctx, `
+		UPDATE configuration_presets
+		SET name = ?, description = ?, configuration = ?, updated_at = ?
+		WHERE id = ? AND user_id = ?
+	`, preset.Name, nullableString(preset.Description), configuration, time.Now(), id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update configuration preset: %w", err)
+	}
+	return c.GetConfigurationPreset(ctx, userID, id)
+}
+
+// DeleteConfigurationPreset removes one of the user's presets. Runs that used it keep their configurations.
+func (c *Client) DeleteConfigurationPreset(ctx context.Context, userID, id string) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	result, err := c.db.ExecContext(ctx, "DELETE FROM configuration_presets WHERE id = ? AND user_id = ?", id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete configuration preset: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete configuration preset: %w", err)
+	}
+	if affected == 0 {
+		return ErrPresetNotFound
+	}
+	return nil
+}
+
+// ApplyConfigurationPresets resolves the request's configurations that reference a preset. Each starts
+// from the preset's configuration, and the fields the request sets override it; a configuration
+// without a variation name takes the preset's name.
+func (c *Client) ApplyConfigurationPresets(ctx context.Context, userID string, request *types.MultiExecutionRequest) error {
+	for i := range request.Configurations {
+		config := &request.Configurations[i]
+		if config.PresetID == "" {
+			continue
+		}
+		preset, err := c.GetConfigurationPreset(ctx, userID, config.PresetID)
+		if err != nil {
+			return fmt.Errorf("configuration %d: %w: %s", i, err, config.PresetID)
+		}
+		*config = MergePresetConfiguration(preset, config)
+	}
+	return nil
+}
+
+// MergePresetConfiguration returns the preset's configuration with the fields set on override applied over it
+func MergePresetConfiguration(preset *types.ConfigurationPreset, override *types.APIConfiguration) types.APIConfiguration {
+	merged := preset.Configuration
+	merged.ID = override.ID
+	merged.ExecutionRunID = override.ExecutionRunID
+	merged.PresetID = preset.ID
+	merged.VariationName = preset.Name
+	merged.Seed = override.Seed
+	merged.Deterministic = override.Deterministic
+
+	if override.VariationName != "" {
+		merged.VariationName = override.VariationName
+	}
+	if override.ModelName != "" {
+		merged.ModelName = override.ModelName
+	}
+	if override.SystemPrompt != "" {
+		merged.SystemPrompt = override.SystemPrompt
+	}
+	if override.Temperature != nil {
+		merged.Temperature = override.Temperature
+	}
+	if override.MaxTokens != nil {
+		merged.MaxTokens = override.MaxTokens
+	}
+	if override.TopP != nil {
+		merged.TopP = override.TopP
+	}
+	if override.TopK != nil {
+		merged.TopK = override.TopK
+	}
+	if override.ThinkingBudget != nil {
+		merged.ThinkingBudget = override.ThinkingBudget
+	}
+	if override.CandidateCount != nil {
+		merged.CandidateCount = override.CandidateCount
+	}
+	if override.SafetySettings != nil {
+		merged.SafetySettings = override.SafetySettings
+	}
+	if override.GenerationConfig != nil {
+		merged.GenerationConfig = override.GenerationConfig
+	}
+	if override.Tools != nil {
+		merged.Tools = override.Tools
+	}
+	if override.ToolConfig != nil {
+		merged.ToolConfig = override.ToolConfig
+	}
+	if override.ToolNames != nil {
+		merged.ToolNames = override.ToolNames
+	}
+	if override.FunctionInstruction != "" {
+		merged.FunctionInstruction = override.FunctionInstruction
+	}
+	if override.SafetyPolicy != nil {
+		merged.SafetyPolicy = override.SafetyPolicy
+	}
+	if override.ResponseMimeType != "" {
+		merged.ResponseMimeType = override.ResponseMimeType
+	}
+	if override.ResponseSchema != nil {
+		merged.ResponseSchema = override.ResponseSchema
+	}
+	if override.TimeoutMs != 0 {
+		merged.TimeoutMs = override.TimeoutMs
+	}
+	if override.Backend != "" {
+		merged.Backend = override.Backend
+	}
+	if override.Provider != "" {
+		merged.Provider = override.Provider
+	}
+	if override.Retrieval != nil {
+		merged.Retrieval = override.Retrieval
+	}
+	if len(override.Guardrails) > 0 {
+		merged.Guardrails = override.Guardrails
+	}
+	if override.ToolChoiceMode != "" {
+		merged.ToolChoiceMode = override.ToolChoiceMode
+	}
+	if override.AllowedFunctionNames != nil {
+		merged.AllowedFunctionNames = override.AllowedFunctionNames
+	}
+
+	// Flags can only be switched on by the request
+	merged.DisableTools = merged.DisableTools || override.DisableTools
+	merged.DisableFunctionInstruction = merged.DisableFunctionInstruction || override.DisableFunctionInstruction
+	merged.InlineSystemPrompt = merged.InlineSystemPrompt || override.InlineSystemPrompt
+	return merged
+}
@@ -0,0 +1,124 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gogent/internal/testdb"
+	"gogent/internal/types"
+)
+
+// newPresetTestClient returns a client backed by an in-memory configuration_presets table
+func newPresetTestClient(t *testing.T) *Client {
+	return &Client{db: testdb.Open(t)}
+}
+
+func newTestPreset(name string, temperature float32) *types.ConfigurationPreset {
+	return &types.ConfigurationPreset{
+		Name: name,
+		Configuration: types.APIConfiguration{
+			ID:             "config-from-a-run",
+			ExecutionRunID: "run-1",
+			ModelName:      "gemini-2.0-flash",
+			SystemPrompt:   "Be brief.",
+			Temperature:    &temperature,
+		},
+	}
+}
+
+func TestConfigurationPresetCRUD(t *testing.T) {
+	client := newPresetTestClient(t)
+	ctx := context.Background()
+
+	created, err := client.CreateConfigurationPreset(ctx, "user-1", newTestPreset("Focused", 0.2))
+	if err != nil {
+		t.Fatalf("failed to create preset: %v", err)
+	}
+	if created.Configuration.ID != "" || created.Configuration.ExecutionRunID != "" {
+		t.Errorf("expected run-specific fields to be cleared, got %+v", created.Configuration)
+	}
+	if *created.Configuration.Temperature != 0.2 || created.Configuration.SystemPrompt != "Be brief." {
+		t.Errorf("expected the configuration to be stored, got %+v", created.Configuration)
+	}
+
+	if _, err := client.CreateConfigurationPreset(ctx, "user-1", newTestPreset("Focused", 0.5)); !errors.Is(err, ErrPresetNameTaken) {
+		t.Errorf("expected ErrPresetNameTaken, got %v", err)
+	}
+	if _, err := client.CreateConfigurationPreset(ctx, "user-2", newTestPreset("Focused", 0.5)); err != nil {
+		t.Errorf("expected another user to reuse the name, got %v", err)
+	}
+	if _, err := client.CreateConfigurationPreset(ctx, "user-1", &types.ConfigurationPreset{Name: "No model"}); err == nil {
+		t.Error("expected a preset without a model to be rejected")
+	}
+
+	updated, err := client.UpdateConfigurationPreset(ctx, "user-1", created.ID, newTestPreset("Precise", 0.1))
+	if err != nil {
+		t.Fatalf("failed to update preset: %v", err)
+	}
+	if updated.Name != "Precise" || *updated.Configuration.Temperature != 0.1 {
+		t.Errorf("expected the updated preset, got %+v", updated)
+	}
+
+	presets, err := client.ListConfigurationPresets(ctx, "user-1", MaxPageSize, 0)
+	if err != nil || len(presets) != 1 || presets[0].ID != created.ID {
+		t.Fatalf("expected only user-1's preset, got %+v (%v)", presets, err)
+	}
+	if total, err := client.CountConfigurationPresets(ctx, "user-1"); err != nil || total != 1 {
+		t.Errorf("expected user-1 to have 1 preset, got %d (%v)", total, err)
+	}
+	if page, err := client.ListConfigurationPresets(ctx, "user-1", 10, 1); err != nil || len(page) != 0 {
+		t.Errorf("expected an empty page past the end, got %+v (%v)", page, err)
+	}
+
+	// Presets are only visible to the user that owns them
+	if _, err := client.GetConfigurationPreset(ctx, "user-2", created.ID); !errors.Is(err, ErrPresetNotFound) {
+		t.Errorf("expected ErrPresetNotFound, got %v", err)
+	}
+	if err := client.DeleteConfigurationPreset(ctx, "user-2", created.ID); !errors.Is(err, ErrPresetNotFound) {
+		t.Errorf("expected ErrPresetNotFound, got %v", err)
+	}
+	if err := client.DeleteConfigurationPreset(ctx, "user-1", created.ID); err != nil {
+		t.Errorf("failed to delete preset: %v", err)
+	}
+}
+
+func TestApplyConfigurationPresets(t *testing.T) {
+	client := newPresetTestClient(t)
+	ctx := context.Background()
+
+	preset, err := client.CreateConfigurationPreset(ctx, "user-1", newTestPreset("Focused", 0.2))
+	if err != nil {
+		t.Fatalf("failed to create preset: %v", err)
+	}
+
+	hot := float32(0.9)
+	request := &types.MultiExecutionRequest{
+		Configurations: []types.APIConfiguration{
+			{PresetID: preset.ID},
+			{PresetID: preset.ID, VariationName: "Hot", Temperature: &hot, DisableTools: true},
+			{VariationName: "Inline", ModelName: "gemini-1.5-pro"},
+		},
+	}
+	if err := client.ApplyConfigurationPresets(ctx, "user-1", request); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	plain := request.Configurations[0]
+	if plain.VariationName != "Focused" || plain.ModelName != "gemini-2.0-flash" || *plain.Temperature != 0.2 || plain.PresetID != preset.ID {
+		t.Errorf("expected the preset's configuration and name, got %+v", plain)
+	}
+	override := request.Configurations[1]
+	if override.VariationName != "Hot" || *override.Temperature != 0.9 || !override.DisableTools || override.SystemPrompt != "Be brief." {
+		t.Errorf("expected the request's fields over the preset's, got %+v", override)
+	}
+	if request.Configurations[2].ModelName != "gemini-1.5-pro" {
+		t.Errorf("expected configurations without a preset to be left alone, got %+v", request.Configurations[2])
+	}
+
+	// Another user's preset cannot be referenced
+	missing := &types.MultiExecutionRequest{Configurations: []types.APIConfiguration{{PresetID: preset.ID}}}
+	if err := client.ApplyConfigurationPresets(ctx, "user-2", missing); !errors.Is(err, ErrPresetNotFound) {
+		t.Errorf("expected ErrPresetNotFound, got %v", err)
+	}
+}
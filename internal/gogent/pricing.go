@@ -0,0 +1,78 @@
+package gogent
+
+import (
+	"strings"
+
+	"gogent/internal/types"
+)
+
+// modelPrice is a model's list price in USD per million tokens
+type modelPrice struct {
+	prefix string
+	input  float64
+	output float64
+}
+
+// modelPrices lists known model prices; the longest matching prefix wins
+var modelPrices = []modelPrice{
+	{prefix: "gemini-1.5-flash", input: 0.075, output: 0.30},
+	{prefix: "gemini-1.5-pro", input: 1.25, output: 5.00},
+	{prefix: "gemini-2.0-flash", input: 0.10, output: 0.40},
+	{prefix: "gemini-2.5-flash", input: 0.30, output: 2.50},
+	{prefix: "gemini-2.5-pro", input: 1.25, output: 10.00},
+	{prefix: "gpt-4o", input: 2.50, output: 10.00},
+	{prefix: "gpt-4o-mini", input: 0.15, output: 0.60},
+	{prefix: "claude-3-haiku", input: 0.25, output: 1.25},
+	{prefix: "claude-3-5-haiku", input: 0.80, output: 4.00},
+	{prefix: "claude-3-5-sonnet", input: 3.00, output: 15.00},
+	{prefix: "claude-3-opus", input: 15.00, output: 75.00},
+}
+
+// EstimateCostUSD estimates the cost of a model call from its token counts, returning 0 for unknown models
+func EstimateCostUSD(modelName string, promptTokens, completionTokens int) float64 {
+	price := listPrice(modelName)
+	if price == nil {
+		return 0
+	}
+	return (float64(promptTokens)*price.input + float64(completionTokens)*price.output) / 1e6
+}
+
+// listPrice returns the price of a model, or nil when it has none
+func listPrice(modelName string) *modelPrice {
+	name := strings.ToLower(modelName)
+	var best *modelPrice
+	for i := range modelPrices {
+		if strings.HasPrefix(name, modelPrices[i].prefix) && (best == nil || len(modelPrices[i].prefix) > len(best.prefix)) {
+			best = &modelPrices[i]
+		}
+	}
+	return best
+}
+
+// ResponseCostUSD estimates the cost of a response from its usage metadata. Prompt tokens read from
+// a context cache entry are billed at cachedInputRate of the input price, and thinking tokens as output.
+func ResponseCostUSD(modelName string, response types.APIResponse) float64 {
+	cost := EstimateCostUSD(modelName,
+		usageTokens(response.UsageMetadata, "prompt_tokens"),
+		usageTokens(response.UsageMetadata, "completion_tokens")+usageTokens(response.UsageMetadata, "thoughts_tokens"))
+	if price := listPrice(modelName); price != nil {
+		cost -= float64(usageTokens(response.UsageMetadata, "cached_tokens")) * price.input * (1 - cachedInputRate) / 1e6
+	}
+	return cost
+}
+
+// usageTokens reads a token count from usage metadata, which holds ints when fresh and float64s when decoded from JSON
+func usageTokens(usage map[string]interface{}, key string) int {
+	switch v := usage[key].(type) {
+	case int:
+		return v
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	default:
+		return 0
+	}
+}
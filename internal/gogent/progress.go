@@ -0,0 +1,26 @@
+package gogent
+
+import (
+	"context"
+
+	"gogent/internal/types"
+)
+
+// ProgressFunc is told how far a run has got: once when the run is created and again as each
+// variation finishes. It is called on the goroutine executing the run and should return quickly.
+type ProgressFunc func(progress types.ExecutionProgress)
+
+// progressKey is the context key a ProgressFunc is stored under
+type progressKey struct{}
+
+// WithProgress returns a context whose runs report their progress to fn
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// reportProgress passes progress to the context's ProgressFunc, if it has one
+func reportProgress(ctx context.Context, progress types.ExecutionProgress) {
+	if fn, ok := ctx.Value(progressKey{}).(ProgressFunc); ok && fn != nil {
+		fn(progress)
+	}
+}
@@ -0,0 +1,51 @@
+package gogent
+
+import (
+	"context"
+	"testing"
+
+	"gogent/internal/types"
+)
+
+func TestExecutionProgress(t *testing.T) {
+	client := NewInMemoryClient(&types.GeminiClientConfig{})
+	defer client.Close()
+
+	var reports []types.ExecutionProgress
+	ctx := WithProgress(context.Background(), func(progress types.ExecutionProgress) {
+		reports = append(reports, progress)
+	})
+	result, err := client.ExecuteMultiVariation(ctx, "user-1", &types.MultiExecutionRequest{
+		ExecutionRunName: "Capital cities",
+		BasePrompt:       "Capital of France?",
+		Repetitions:      2,
+		Configurations: []types.APIConfiguration{
+			{VariationName: "flash", ModelName: "gemini-2.0-flash"},
+			{VariationName: "pro", ModelName: "gemini-1.5-pro"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(reports) != 5 {
+		t.Fatalf("expected a report when the run starts and one per variation, got %+v", reports)
+	}
+	for i, report := range reports {
+		if report.ExecutionRunID != result.ExecutionRun.ID || report.Total != 4 || report.Completed != i || report.Failed != 0 {
+			t.Errorf("unexpected report %d: %+v", i, report)
+		}
+	}
+	if reports[0].Variation != "" || reports[4].Variation != "pro (repetition 2/2)" {
+		t.Errorf("expected the reports to name the variation that finished, got %q and %q", reports[0].Variation, reports[4].Variation)
+	}
+
+	// Runs without a ProgressFunc report nothing
+	if _, err := client.ExecuteMultiVariation(context.Background(), "user-1", &types.MultiExecutionRequest{
+		ExecutionRunName: "Quiet",
+		BasePrompt:       "Capital of Spain?",
+		Configurations:   []types.APIConfiguration{{VariationName: "flash", ModelName: "gemini-2.0-flash"}},
+	}); err != nil || len(reports) != 5 {
+		t.Errorf("expected no more reports, got %d (%v)", len(reports), err)
+	}
+}
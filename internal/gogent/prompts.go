@@ -0,0 +1,198 @@
+package gogent
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gogent/internal/types"
+
+	"github.com/google/uuid"
+)
+
+// ErrPromptNotFound is returned when a prompt does not exist or is not owned by the user
+var ErrPromptNotFound = errors.New("prompt not found")
+
+// ErrPromptNameTaken is returned when a user already has a prompt with the same name
+var ErrPromptNameTaken = errors.New("prompt name already in use")
+
+// maxPromptLength caps a saved prompt's content
+const maxPromptLength = 64 * 1024
+
+// promptColumns is the column list read by scanPrompt
+const promptColumns = `id, user_id, name, description, content, created_at, updated_at`
+
+// ValidatePrompt checks a prompt's name and content
+func ValidatePrompt(prompt *types.Prompt) error {
+	prompt.Name = strings.TrimSpace(prompt.Name)
+	if prompt.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(prompt.Name) > 255 {
+		return fmt.Errorf("name must be at most 255 characters")
+	}
+	if strings.TrimSpace(prompt.Content) == "" {
+		return fmt.Errorf("content is required")
+	}
+	if len(prompt.Content) > maxPromptLength {
+		return fmt.Errorf("content must be at most %d bytes", maxPromptLength)
+	}
+	return nil
+}
+
+// scanPrompt reads a row selected with promptColumns
+func scanPrompt(row rowScanner) (*types.Prompt, error) {
+	var prompt types.Prompt
+	var description sql.NullString
+	err := row.Scan(&prompt.ID, &prompt.UserID, &prompt.Name, &description, &prompt.Content,
+		&prompt.CreatedAt, &prompt.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	prompt.Description = description.String
+	return &prompt, nil
+}
+
+// promptNameOwner returns the ID of the user's prompt with a name, if there is one
+func (c *Client) promptNameOwner(ctx context.Context, userID, name string) (string, bool, error) {
+	var id string
+	err := c.db.QueryRowContext(ctx, "SELECT id FROM prompts WHERE user_id = ? AND name = ?", userID, name).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to look up prompt: %w", err)
+	}
+	return id, true, nil
+}
+
+// CreatePrompt saves a new prompt for the user
+func (c *Client) CreatePrompt(ctx context.Context, userID string, prompt *types.Prompt) (*types.Prompt, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	if err := ValidatePrompt(prompt); err != nil {
+		return nil, err
+	}
+	if _, found, err := c.promptNameOwner(ctx, userID, prompt.Name); err != nil {
+		return nil, err
+	} else if found {
+		return nil, fmt.Errorf("%w: %s", ErrPromptNameTaken, prompt.Name)
+	}
+
+	id := uuid.New().String()
+	now := time.Now()
+	_, err := c.db.ExecContext(ctx, `
+		INSERT INTO prompts (id, user_id, name, description, content, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, id, userID, prompt.Name, nullableString(prompt.Description), prompt.Content, now, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create prompt: %w", err)
+	}
+	return c.GetPrompt(ctx, userID, id)
+}
+
+// GetPrompt loads one of the user's prompts
+func (c *Client) GetPrompt(ctx context.Context, userID, id string) (*types.Prompt, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	row := c.db.QueryRowContext(ctx, "SELECT "+promptColumns+" FROM prompts WHERE id = ? AND user_id = ?", id, userID)
+	prompt, err := scanPrompt(row)
+	if err == sql.ErrNoRows {
+		return nil, ErrPromptNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt: %w", err)
+	}
+	return prompt, nil
+}
+
+// ListPrompts returns a page of the user's prompts ordered by name
+func (c *Client) ListPrompts(ctx context.Context, userID string, limit, offset int32) ([]*types.Prompt, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	rows, err := c.db.QueryContext(ctx,
+		"SELECT "+promptColumns+" FROM prompts WHERE user_id = ? ORDER BY name ASC LIMIT ? OFFSET ?",
+		userID, limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prompts: %w", err)
+	}
+	defer rows.Close()
+
+	prompts := []*types.Prompt{}
+	for rows.Next() {
+		prompt, err := scanPrompt(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan prompt: %w", err)
+		}
+		prompts = append(prompts, prompt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate prompts: %w", err)
+	}
+	return prompts, nil
+}
+
+// CountPrompts counts the user's prompts
+func (c *Client) CountPrompts(ctx context.Context, userID string) (int64, error) {
+	if c.db == nil {
+		return 0, ErrNoDatabase
+	}
+	var count int64
+	if err := c.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM prompts WHERE user_id = ?", userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count prompts: %w", err)
+	}
+	return count, nil
+}
+
+// UpdatePrompt replaces the name, description and content of one of the user's prompts
+func (c *Client) UpdatePrompt(ctx context.Context, userID, id string, prompt *types.Prompt) (*types.Prompt, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	if err := ValidatePrompt(prompt); err != nil {
+		return nil, err
+	}
+	if _, err := c.GetPrompt(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	if existingID, found, err := c.promptNameOwner(ctx, userID, prompt.Name); err != nil {
+		return nil, err
+	} else if found && existingID != id {
+		return nil, fmt.Errorf("%w: %s", ErrPromptNameTaken, prompt.Name)
+	}
+
+	_, err := c.db.ExecContext(ctx, `
+		UPDATE prompts SET name = ?, description = ?, content = ?, updated_at = ?
+		WHERE id = ? AND user_id = ?
+	`, prompt.Name, nullableString(prompt.Description), prompt.Content, time.Now(), id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update prompt: %w", err)
+	}
+	return c.GetPrompt(ctx, userID, id)
+}
+
+// DeletePrompt removes one of the user's prompts. Workspaces it was shared with stop listing it.
+func (c *Client) DeletePrompt(ctx context.Context, userID, id string) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	result, err := c.db.ExecContext(ctx, "DELETE FROM prompts WHERE id = ? AND user_id = ?", id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete prompt: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete prompt: %w", err)
+	}
+	if affected == 0 {
+		return ErrPromptNotFound
+	}
+	return nil
+}
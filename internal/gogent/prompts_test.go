@@ -0,0 +1,62 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gogent/internal/testdb"
+	"gogent/internal/types"
+)
+
+func TestPromptCRUD(t *testing.T) {
+	client := &Client{db: testdb.Open(t)}
+	ctx := context.Background()
+
+	created, err := client.CreatePrompt(ctx, "user-1", &types.Prompt{Name: " Forecast ", Content: "Summarize the forecast for {{city}}."})
+	if err != nil {
+		t.Fatalf("failed to create prompt: %v", err)
+	}
+	if created.Name != "Forecast" || created.UserID != "user-1" || created.Content != "Summarize the forecast for {{city}}." {
+		t.Errorf("expected the trimmed prompt, got %+v", created)
+	}
+
+	if _, err := client.CreatePrompt(ctx, "user-1", &types.Prompt{Name: "Forecast", Content: "Again"}); !errors.Is(err, ErrPromptNameTaken) {
+		t.Errorf("expected ErrPromptNameTaken, got %v", err)
+	}
+	if _, err := client.CreatePrompt(ctx, "user-2", &types.Prompt{Name: "Forecast", Content: "Mine"}); err != nil {
+		t.Errorf("expected another user to reuse the name, got %v", err)
+	}
+	if _, err := client.CreatePrompt(ctx, "user-1", &types.Prompt{Name: "Empty", Content: "  "}); err == nil {
+		t.Error("expected a prompt without content to be rejected")
+	}
+
+	updated, err := client.UpdatePrompt(ctx, "user-1", created.ID, &types.Prompt{Name: "Weekly forecast", Description: "Longer range", Content: "Summarize the week."})
+	if err != nil {
+		t.Fatalf("failed to update prompt: %v", err)
+	}
+	if updated.Name != "Weekly forecast" || updated.Description != "Longer range" || updated.Content != "Summarize the week." {
+		t.Errorf("expected the updated prompt, got %+v", updated)
+	}
+	if _, err := client.UpdatePrompt(ctx, "user-2", created.ID, &types.Prompt{Name: "Stolen", Content: "x"}); !errors.Is(err, ErrPromptNotFound) {
+		t.Errorf("expected another user's prompt to be off limits, got %v", err)
+	}
+
+	prompts, err := client.ListPrompts(ctx, "user-1", 10, 0)
+	if err != nil || len(prompts) != 1 || prompts[0].ID != created.ID {
+		t.Errorf("expected user-1's one prompt, got %+v, %v", prompts, err)
+	}
+	if count, err := client.CountPrompts(ctx, "user-2"); err != nil || count != 1 {
+		t.Errorf("expected user-2 to have one prompt, got %d, %v", count, err)
+	}
+
+	if err := client.DeletePrompt(ctx, "user-1", created.ID); err != nil {
+		t.Fatalf("failed to delete prompt: %v", err)
+	}
+	if _, err := client.GetPrompt(ctx, "user-1", created.ID); !errors.Is(err, ErrPromptNotFound) {
+		t.Errorf("expected the deleted prompt to be gone, got %v", err)
+	}
+	if err := client.DeletePrompt(ctx, "user-1", created.ID); !errors.Is(err, ErrPromptNotFound) {
+		t.Errorf("expected deleting twice to fail, got %v", err)
+	}
+}
@@ -0,0 +1,207 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrQueueFull is returned when the execution queue has no room for another waiting execution
+var ErrQueueFull = errors.New("execution queue is full")
+
+// ErrQueueClosed is returned when an execution is submitted after the queue started shutting down
+var ErrQueueClosed = errors.New("execution queue is shutting down")
+
+// Execution priorities, highest first
+const (
+	PriorityHigh   = "high"
+	PriorityNormal = "normal"
+	PriorityLow    = "low"
+)
+
+// queuePriorities orders the priority levels workers take executions from
+var queuePriorities = []string{PriorityHigh, PriorityNormal, PriorityLow}
+
+// ValidatePriority checks that a priority is empty (normal) or known
+func ValidatePriority(priority string) error {
+	switch priority {
+	case "", PriorityHigh, PriorityNormal, PriorityLow:
+		return nil
+	default:
+		return fmt.Errorf("unknown priority %q (use high, normal or low)", priority)
+	}
+}
+
+// queuedExecution is an execution waiting for a worker
+type queuedExecution struct {
+	id  string
+	run func()
+}
+
+// QueueStats is a snapshot of the execution queue
+type QueueStats struct {
+	Workers  int            `json:"workers"`
+	Running  int            `json:"running"`
+	Waiting  map[string]int `json:"waiting"` // By priority
+	Capacity int            `json:"capacity"`
+}
+
+// ExecutionQueue runs executions on a fixed number of workers. Waiting executions are taken
+// highest priority first, then in submission order, and at most capacity of them may wait.
+type ExecutionQueue struct {
+	mutex    sync.Mutex
+	ready    *sync.Cond
+	waiting  map[string][]queuedExecution
+	workers  int
+	capacity int
+	running  int
+	closed   bool
+	done     sync.WaitGroup
+}
+
+// NewExecutionQueue starts a queue with the given number of workers and waiting capacity
+func NewExecutionQueue(workers, capacity int) *ExecutionQueue {
+	if workers < 1 {
+		workers = 1
+	}
+	if capacity < 1 {
+		capacity = 1
+	}
+	q := &ExecutionQueue{
+		waiting:  make(map[string][]queuedExecution, len(queuePriorities)),
+		workers:  workers,
+		capacity: capacity,
+	}
+	q.ready = sync.NewCond(&q.mutex)
+
+	q.done.Add(workers)
+	for i := 0; i < workers; i++ {
+		go q.work()
+	}
+	return q
+}
+
+// Enqueue adds an execution and returns its 1-based position among the waiting executions
+func (q *ExecutionQueue) Enqueue(id, priority string, run func()) (int, error) {
+	if err := ValidatePriority(priority); err != nil {
+		return 0, err
+	}
+	if priority == "" {
+		priority = PriorityNormal
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if q.closed {
+		return 0, ErrQueueClosed
+	}
+	if q.waitingCount() >= q.capacity {
+		return 0, ErrQueueFull
+	}
+
+	q.waiting[priority] = append(q.waiting[priority], queuedExecution{id: id, run: run})
+	q.ready.Signal()
+	position, _ := q.position(id)
+	return position, nil
+}
+
+// Position returns an execution's 1-based position among the waiting executions, or false once
+// a worker has taken it
+func (q *ExecutionQueue) Position(id string) (int, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.position(id)
+}
+
+func (q *ExecutionQueue) position(id string) (int, bool) {
+	position := 0
+	for _, priority := range queuePriorities {
+		for _, execution := range q.waiting[priority] {
+			position++
+			if execution.id == id {
+				return position, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func (q *ExecutionQueue) waitingCount() int {
+	count := 0
+	for _, executions := range q.waiting {
+		count += len(executions)
+	}
+	return count
+}
+
+// Stats returns the number of workers, running executions and waiting executions by priority
+func (q *ExecutionQueue) Stats() QueueStats {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	stats := QueueStats{Workers: q.workers, Running: q.running, Capacity: q.capacity, Waiting: make(map[string]int)}
+	for _, priority := range queuePriorities {
+		stats.Waiting[priority] = len(q.waiting[priority])
+	}
+	return stats
+}
+
+// work runs waiting executions until the queue is shut down and drained
+func (q *ExecutionQueue) work() {
+	defer q.done.Done()
+	for {
+		q.mutex.Lock()
+		for q.waitingCount() == 0 && !q.closed {
+			q.ready.Wait()
+		}
+		execution, ok := q.next()
+		if !ok {
+			q.mutex.Unlock()
+			return
+		}
+		q.running++
+		q.mutex.Unlock()
+
+		execution.run()
+
+		q.mutex.Lock()
+		q.running--
+		q.mutex.Unlock()
+	}
+}
+
+// next removes the first execution of the highest priority level; callers hold the mutex
+func (q *ExecutionQueue) next() (queuedExecution, bool) {
+	for _, priority := range queuePriorities {
+		if executions := q.waiting[priority]; len(executions) > 0 {
+			q.waiting[priority] = executions[1:]
+			return executions[0], true
+		}
+	}
+	return queuedExecution{}, false
+}
+
+// Shutdown stops accepting executions and waits for the running and waiting ones to finish. If ctx
+// ends first, Shutdown returns its error and executions still waiting are not started.
+func (q *ExecutionQueue) Shutdown(ctx context.Context) error {
+	q.mutex.Lock()
+	q.closed = true
+	q.ready.Broadcast()
+	q.mutex.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		q.done.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		q.mutex.Lock()
+		q.waiting = make(map[string][]queuedExecution)
+		q.mutex.Unlock()
+		return ctx.Err()
+	}
+}
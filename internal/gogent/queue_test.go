@@ -0,0 +1,121 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockQueue occupies the queue's only worker until the returned function is called
+func blockQueue(t *testing.T, q *ExecutionQueue) func() {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	if _, err := q.Enqueue("blocker", PriorityNormal, func() {
+		close(started)
+		<-release
+	}); err != nil {
+		t.Fatalf("failed to enqueue blocker: %v", err)
+	}
+	<-started
+	return func() { close(release) }
+}
+
+func TestExecutionQueuePriorityOrder(t *testing.T) {
+	q := NewExecutionQueue(1, 10)
+	release := blockQueue(t, q)
+
+	var mutex sync.Mutex
+	var order []string
+	record := func(id string) func() {
+		return func() {
+			mutex.Lock()
+			order = append(order, id)
+			mutex.Unlock()
+		}
+	}
+
+	for _, job := range []struct{ id, priority string }{
+		{"low-1", PriorityLow},
+		{"normal-1", ""},
+		{"high-1", PriorityHigh},
+		{"normal-2", PriorityNormal},
+	} {
+		if _, err := q.Enqueue(job.id, job.priority, record(job.id)); err != nil {
+			t.Fatalf("failed to enqueue %s: %v", job.id, err)
+		}
+	}
+
+	if position, ok := q.Position("high-1"); !ok || position != 1 {
+		t.Errorf("expected high-1 first in the queue, got %d (%v)", position, ok)
+	}
+	if position, ok := q.Position("low-1"); !ok || position != 4 {
+		t.Errorf("expected low-1 last in the queue, got %d (%v)", position, ok)
+	}
+	if _, ok := q.Position("blocker"); ok {
+		t.Error("expected the running execution to have left the queue")
+	}
+	if stats := q.Stats(); stats.Running != 1 || stats.Waiting[PriorityNormal] != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	release()
+	if err := q.Shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+
+	expected := []string{"high-1", "normal-1", "normal-2", "low-1"}
+	if len(order) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, order)
+	}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, order)
+		}
+	}
+}
+
+func TestExecutionQueueCapacity(t *testing.T) {
+	q := NewExecutionQueue(1, 1)
+	release := blockQueue(t, q)
+	defer release()
+
+	if _, err := q.Enqueue("waiting", PriorityNormal, func() {}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := q.Enqueue("overflow", PriorityHigh, func() {}); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("expected ErrQueueFull, got %v", err)
+	}
+	if _, err := q.Enqueue("bad", "urgent", func() {}); err == nil {
+		t.Error("expected an error for an unknown priority")
+	}
+}
+
+func TestExecutionQueueShutdown(t *testing.T) {
+	q := NewExecutionQueue(1, 10)
+	release := blockQueue(t, q)
+
+	ran := false
+	if _, err := q.Enqueue("waiting", PriorityNormal, func() { ran = true }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A drain that times out drops the waiting execution
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := q.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the drain to time out, got %v", err)
+	}
+	if _, err := q.Enqueue("late", PriorityNormal, func() {}); !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("expected ErrQueueClosed after shutdown, got %v", err)
+	}
+
+	release()
+	if err := q.Shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ran {
+		t.Error("expected the dropped execution not to run")
+	}
+}
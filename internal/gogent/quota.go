@@ -0,0 +1,107 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gogent/internal/types"
+)
+
+// ErrQuotaExceeded is returned when a submission would exceed one of the user's quota limits
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// Quota limits named by QuotaError
+const (
+	QuotaLimitConcurrentExecutions = "concurrent_executions"
+	QuotaLimitVariationsPerRun     = "variations_per_run"
+	QuotaLimitDailyTokens          = "daily_tokens"
+)
+
+// QuotaError names the limit a submission would exceed; it wraps ErrQuotaExceeded
+type QuotaError struct {
+	Limit    string
+	Used     int64
+	Max      int64
+	ResetsAt time.Time // Set for the daily token budget
+}
+
+func (e *QuotaError) Error() string {
+	switch e.Limit {
+	case QuotaLimitConcurrentExecutions:
+		return fmt.Sprintf("quota exceeded: %d of %d concurrent executions are running", e.Used, e.Max)
+	case QuotaLimitVariationsPerRun:
+		return fmt.Sprintf("quota exceeded: the run has %d configurations; the limit is %d", e.Used, e.Max)
+	default:
+		return fmt.Sprintf("quota exceeded: %d of the daily budget of %d tokens used; resets at %s",
+			e.Used, e.Max, e.ResetsAt.Format(time.RFC3339))
+	}
+}
+
+func (e *QuotaError) Unwrap() error {
+	return ErrQuotaExceeded
+}
+
+// ValidateQuotaLimits checks that no limit is negative
+func ValidateQuotaLimits(limits types.QuotaLimits) error {
+	if limits.MaxConcurrentExecutions < 0 || limits.MaxVariationsPerRun < 0 || limits.DailyTokenBudget < 0 {
+		return fmt.Errorf("quota limits must not be negative")
+	}
+	return nil
+}
+
+// quotaDayStart returns the start of the UTC day the daily token budget is counted from
+func quotaDayStart(now time.Time) time.Time {
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// TokensUsedSince sums the total tokens of the user's responses since a time. Without a database
+// no usage is recorded, so it returns 0.
+func (c *Client) TokensUsedSince(ctx context.Context, userID string, since time.Time) (int64, error) {
+	if c.db == nil {
+		return 0, nil
+	}
+	var total int64
+	err := c.db.QueryRowContext(ctx,
+		"SELECT COALESCE(SUM(total_tokens), 0) FROM api_responses WHERE user_id = ? AND created_at >= ?",
+		userID, since,
+	).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load token usage: %w", err)
+	}
+	return total, nil
+}
+
+// QuotaStatus reports the user's usage against the limits. The caller tracks executions in
+// flight and passes how many the user has.
+func (c *Client) QuotaStatus(ctx context.Context, userID string, limits types.QuotaLimits, concurrentExecutions int) (*types.QuotaStatus, error) {
+	dayStart := quotaDayStart(time.Now())
+	status := &types.QuotaStatus{
+		Limits:               limits,
+		ConcurrentExecutions: concurrentExecutions,
+		ResetsAt:             dayStart.AddDate(0, 0, 1),
+	}
+	tokens, err := c.TokensUsedSince(ctx, userID, dayStart)
+	if err != nil {
+		return nil, err
+	}
+	status.TokensToday = tokens
+	return status, nil
+}
+
+// CheckQuota returns a *QuotaError if starting the request would exceed one of the user's limits
+func CheckQuota(status *types.QuotaStatus, request *types.MultiExecutionRequest) error {
+	limits := status.Limits
+	if limits.MaxVariationsPerRun > 0 && len(request.Configurations) > limits.MaxVariationsPerRun {
+		return &QuotaError{Limit: QuotaLimitVariationsPerRun, Used: int64(len(request.Configurations)), Max: int64(limits.MaxVariationsPerRun)}
+	}
+	if limits.MaxConcurrentExecutions > 0 && status.ConcurrentExecutions >= limits.MaxConcurrentExecutions {
+		return &QuotaError{Limit: QuotaLimitConcurrentExecutions, Used: int64(status.ConcurrentExecutions), Max: int64(limits.MaxConcurrentExecutions)}
+	}
+	if limits.DailyTokenBudget > 0 && status.TokensToday >= limits.DailyTokenBudget {
+		return &QuotaError{Limit: QuotaLimitDailyTokens, Used: status.TokensToday, Max: limits.DailyTokenBudget, ResetsAt: status.ResetsAt}
+	}
+	return nil
+}
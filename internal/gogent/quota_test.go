@@ -0,0 +1,92 @@
+package gogent
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"gogent/internal/testdb"
+	"gogent/internal/types"
+)
+
+// newQuotaTestClient returns a client backed by an in-memory api_responses table
+func newQuotaTestClient(t *testing.T) *Client {
+	return &Client{db: testdb.Open(t)}
+}
+
+func TestTokensUsedSince(t *testing.T) {
+	client := newQuotaTestClient(t)
+	now := time.Now()
+	for _, row := range []struct {
+		id, userID  string
+		totalTokens sql.NullInt32
+		createdAt   time.Time
+	}{
+		{"r1", "user-1", sql.NullInt32{Int32: 120, Valid: true}, now},
+		{"r2", "user-1", sql.NullInt32{Int32: 80, Valid: true}, now},
+		{"r3", "user-1", sql.NullInt32{Int32: 500, Valid: true}, now.Add(-48 * time.Hour)},
+		{"r4", "user-2", sql.NullInt32{Int32: 900, Valid: true}, now},
+		{"r5", "user-1", sql.NullInt32{}, now},
+	} {
+		if _, err := client.db.Exec("INSERT INTO api_responses (id, user_id, total_tokens, created_at) VALUES (?, ?, ?, ?)",
+			row.id, row.userID, row.totalTokens, row.createdAt); err != nil {
+			t.Fatalf("failed to insert response: %v", err)
+		}
+	}
+
+	tokens, err := client.TokensUsedSince(context.Background(), "user-1", now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tokens != 200 {
+		t.Errorf("expected 200 tokens from user-1's recent responses, got %d", tokens)
+	}
+
+	status, err := client.QuotaStatus(context.Background(), "user-2", types.QuotaLimits{DailyTokenBudget: 1000}, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.ConcurrentExecutions != 2 || status.ResetsAt.Sub(quotaDayStart(now)) != 24*time.Hour {
+		t.Errorf("expected the in-flight count and the next UTC midnight, got %+v", status)
+	}
+}
+
+func TestCheckQuota(t *testing.T) {
+	request := &types.MultiExecutionRequest{Configurations: make([]types.APIConfiguration, 3)}
+	resetsAt := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		status types.QuotaStatus
+		limit  string
+	}{
+		{"unlimited", types.QuotaStatus{ConcurrentExecutions: 50, TokensToday: 1e9}, ""},
+		{"within limits", types.QuotaStatus{
+			Limits:               types.QuotaLimits{MaxConcurrentExecutions: 2, MaxVariationsPerRun: 3, DailyTokenBudget: 1000},
+			ConcurrentExecutions: 1, TokensToday: 999,
+		}, ""},
+		{"too many variations", types.QuotaStatus{Limits: types.QuotaLimits{MaxVariationsPerRun: 2}}, QuotaLimitVariationsPerRun},
+		{"too many executions", types.QuotaStatus{
+			Limits: types.QuotaLimits{MaxConcurrentExecutions: 2}, ConcurrentExecutions: 2,
+		}, QuotaLimitConcurrentExecutions},
+		{"budget spent", types.QuotaStatus{
+			Limits: types.QuotaLimits{DailyTokenBudget: 1000}, TokensToday: 1000, ResetsAt: resetsAt,
+		}, QuotaLimitDailyTokens},
+	}
+	for _, test := range tests {
+		err := CheckQuota(&test.status, request)
+		if test.limit == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", test.name, err)
+			}
+			continue
+		}
+
+		var quotaErr *QuotaError
+		if !errors.As(err, &quotaErr) || !errors.Is(err, ErrQuotaExceeded) || quotaErr.Limit != test.limit {
+			t.Errorf("%s: expected a %s quota error, got %v", test.name, test.limit, err)
+		}
+	}
+}
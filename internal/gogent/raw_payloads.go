@@ -0,0 +1,278 @@
+package gogent
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"gogent/internal/types"
+)
+
+const (
+	// DefaultRawPayloadMaxBytes is how much of each body is stored when a run sets no limit
+	DefaultRawPayloadMaxBytes = 1 << 20
+	// MaxRawPayloadMaxBytes is the largest per-body limit a run may set
+	MaxRawPayloadMaxBytes = 16 << 20
+)
+
+// ValidateRawPayloadLimit rejects negative limits and limits over MaxRawPayloadMaxBytes; zero keeps
+// the default
+func ValidateRawPayloadLimit(maxBytes int) error {
+	if maxBytes < 0 || maxBytes > MaxRawPayloadMaxBytes {
+		return fmt.Errorf("rawPayloadMaxBytes must be between 0 and %d, got %d", MaxRawPayloadMaxBytes, maxBytes)
+	}
+	return nil
+}
+
+// rawExchange is one HTTP exchange with a provider as it went over the wire
+type rawExchange struct {
+	method       string
+	url          string
+	statusCode   int
+	requestBody  []byte
+	responseBody []byte
+	err          error
+	createdAt    time.Time
+}
+
+// rawPayloadLog collects the exchanges made on a context, see withRawPayloadLog
+type rawPayloadLog struct {
+	mu        sync.Mutex
+	exchanges []rawExchange
+}
+
+// rawPayloadLimitKey is the context key a run's per-body limit is stored under when it keeps raw
+// payloads; rawPayloadLogKey holds the log of the variation being called
+type rawPayloadLimitKey struct{}
+type rawPayloadLogKey struct{}
+
+// withRawPayloads marks a run's context as keeping raw payloads, cut to maxBytes per body
+func withRawPayloads(ctx context.Context, maxBytes int) context.Context {
+	if maxBytes == 0 {
+		maxBytes = DefaultRawPayloadMaxBytes
+	}
+	return context.WithValue(ctx, rawPayloadLimitKey{}, maxBytes)
+}
+
+// rawPayloadLimit returns the per-body limit of a run that keeps raw payloads
+func rawPayloadLimit(ctx context.Context) (int, bool) {
+	maxBytes, ok := ctx.Value(rawPayloadLimitKey{}).(int)
+	return maxBytes, ok
+}
+
+// withRawPayloadLog returns a context whose provider exchanges are collected, or ctx and a nil log
+// when the run does not keep raw payloads
+func withRawPayloadLog(ctx context.Context) (context.Context, *rawPayloadLog) {
+	if _, ok := rawPayloadLimit(ctx); !ok {
+		return ctx, nil
+	}
+	exchanges := &rawPayloadLog{}
+	return context.WithValue(ctx, rawPayloadLogKey{}, exchanges), exchanges
+}
+
+// list returns the exchanges collected so far in the order they finished
+func (l *rawPayloadLog) list() []rawExchange {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]rawExchange(nil), l.exchanges...)
+}
+
+func (l *rawPayloadLog) add(exchange rawExchange) {
+	l.mu.Lock()
+	l.exchanges = append(l.exchanges, exchange)
+	l.mu.Unlock()
+}
+
+// payloadTransport records provider exchanges made on a context that carries a rawPayloadLog and
+// passes every other request straight to base
+type payloadTransport struct {
+	base http.RoundTripper
+}
+
+func (t *payloadTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	exchanges, ok := req.Context().Value(rawPayloadLogKey{}).(*rawPayloadLog)
+	if !ok {
+		return base.RoundTrip(req)
+	}
+
+	exchange := rawExchange{method: req.Method, url: req.URL.Scheme + "://" + req.URL.Host + req.URL.Path, createdAt: time.Now()}
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			exchange.requestBody, _ = io.ReadAll(body)
+			body.Close()
+		}
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		exchange.err = err
+		exchanges.add(exchange)
+		return nil, err
+	}
+	exchange.statusCode = resp.StatusCode
+	body, readErr := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	exchange.responseBody, exchange.err = body, readErr
+	exchanges.add(exchange)
+	// The caller reads the same bytes, and the read error once they run out
+	resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), errReader{readErr}))
+	return resp, nil
+}
+
+// errReader fails every read with err, or reports EOF when err is nil
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return 0, io.EOF
+}
+
+// providerTransport returns the transport provider clients send requests through, so that runs
+// keeping raw payloads can record them and failing hosts trip their circuit breakers
+func (c *Client) providerTransport() http.RoundTripper {
+	return &payloadTransport{base: c.guardedTransport(c.transport())}
+}
+
+// rawBodyMarker ends a body that was cut to the run's limit
+const rawBodyMarker = "\n…[truncated: stored %d of %d bytes]"
+
+// compressRawBody scrubs a body with the redaction patterns, cuts it to maxBytes and compresses it,
+// reporting whether it was cut
+func (c *Client) compressRawBody(body []byte, maxBytes int) ([]byte, bool, error) {
+	scrubbed := c.payloadRedactor().scrubString(string(body), &types.RedactionRecord{})
+	truncated := len(scrubbed) > maxBytes
+	if truncated {
+		scrubbed = strings.ToValidUTF8(scrubbed[:maxBytes], "") + fmt.Sprintf(rawBodyMarker, maxBytes, len(scrubbed))
+	}
+
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	if _, err := writer.Write([]byte(scrubbed)); err != nil {
+		return nil, false, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, false, err
+	}
+	return compressed.Bytes(), truncated, nil
+}
+
+// decompressRawBody reverses compressRawBody's compression
+func decompressRawBody(compressed []byte) (string, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// storeRawPayloads stores the provider exchanges of a request, in the order they were made
+func (c *Client) storeRawPayloads(ctx context.Context, userID string, request *types.APIRequest, exchanges []rawExchange, maxBytes int) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	if len(exchanges) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, len(exchanges))
+	args := make([]interface{}, 0, len(exchanges)*13)
+	for i, exchange := range exchanges {
+		requestBody, requestTruncated, err := c.compressRawBody(exchange.requestBody, maxBytes)
+		if err != nil {
+			return fmt.Errorf("failed to compress raw request body: %w", err)
+		}
+		responseBody, responseTruncated, err := c.compressRawBody(exchange.responseBody, maxBytes)
+		if err != nil {
+			return fmt.Errorf("failed to compress raw response body: %w", err)
+		}
+		var errorMessage *string
+		if exchange.err != nil {
+			message := c.payloadRedactor().scrubString(exchange.err.Error(), &types.RedactionRecord{})
+			errorMessage = &message
+		}
+
+		placeholders[i] = "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+		args = append(args, request.ID, i+1, userID, request.ExecutionRunID, exchange.method, exchange.url,
+			exchange.statusCode, requestBody, len(exchange.requestBody), responseBody, len(exchange.responseBody),
+			requestTruncated || responseTruncated, errorMessage, exchange.createdAt)
+	}
+
+	_, err := c.db.ExecContext(ctx, `
+		INSERT INTO raw_payloads (request_id, sequence, user_id, execution_run_id, method, url, status_code,
+			request_body, request_size, response_body, response_size, truncated, error_message, created_at)
+		VALUES `+strings.Join(placeholders, ", "), args...)
+	if err != nil {
+		return fmt.Errorf("failed to store raw payloads: %w", err)
+	}
+	return nil
+}
+
+// GetRawPayloads returns the stored provider exchanges of a run owned by the user, decompressed and
+// ordered by request and sequence. A non-empty requestID returns that request's exchanges only.
+func (c *Client) GetRawPayloads(ctx context.Context, userID, executionRunID, requestID string) ([]types.RawPayload, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	query := `
+		SELECT request_id, sequence, method, url, status_code, request_body, request_size,
+			response_body, response_size, truncated, error_message, created_at
+		FROM raw_payloads
+		WHERE execution_run_id = ? AND user_id = ?`
+	args := []interface{}{executionRunID, userID}
+	if requestID != "" {
+		query += " AND request_id = ?"
+		args = append(args, requestID)
+	}
+	rows, err := c.db.QueryContext(ctx, query+" ORDER BY request_id ASC, sequence ASC", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get raw payloads: %w", err)
+	}
+	defer rows.Close()
+
+	payloads := []types.RawPayload{}
+	for rows.Next() {
+		var payload types.RawPayload
+		var requestBody, responseBody []byte
+		var errorMessage *string
+		if err := rows.Scan(&payload.RequestID, &payload.Sequence, &payload.Method, &payload.URL, &payload.StatusCode,
+			&requestBody, &payload.RequestSize, &responseBody, &payload.ResponseSize, &payload.Truncated,
+			&errorMessage, &payload.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan raw payload: %w", err)
+		}
+		if payload.RequestBody, err = decompressRawBody(requestBody); err != nil {
+			return nil, fmt.Errorf("failed to decompress raw request body: %w", err)
+		}
+		if payload.ResponseBody, err = decompressRawBody(responseBody); err != nil {
+			return nil, fmt.Errorf("failed to decompress raw response body: %w", err)
+		}
+		payload.StoredBytes = len(requestBody) + len(responseBody)
+		if errorMessage != nil {
+			payload.ErrorMessage = *errorMessage
+		}
+		payloads = append(payloads, payload)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate raw payloads: %w", err)
+	}
+	return payloads, nil
+}
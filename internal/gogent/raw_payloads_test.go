@@ -0,0 +1,76 @@
+package gogent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gogent/internal/types"
+)
+
+func TestRawPayloads(t *testing.T) {
+	reply := `{"message": {"role": "assistant", "content": "Use sk-abcdefghijklmnopqrstuvwx to call it. ` + strings.Repeat("More. ", 40) + `"}, "done_reason": "stop"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(reply))
+	}))
+	t.Cleanup(server.Close)
+
+	client, _ := newStoreTestClient(t)
+	client.config.OllamaURL = server.URL
+	ctx := context.Background()
+	config := &types.APIConfiguration{VariationName: "local", ModelName: "llama3.2", Provider: types.ProviderOllama}
+
+	if _, err := client.executeSingleVariation(ctx, "user-1", "run-1", config, "Which key?", "", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if payloads, err := client.GetRawPayloads(ctx, "user-1", "run-1", ""); err != nil || len(payloads) != 0 {
+		t.Fatalf("expected nothing stored without storeRawPayloads, got %v, %v", payloads, err)
+	}
+
+	result, err := client.executeSingleVariation(withRawPayloads(ctx, 100), "user-1", "run-1", config, "Which key?", "", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	payloads, err := client.GetRawPayloads(ctx, "user-1", "run-1", result.Request.ID)
+	if err != nil {
+		t.Fatalf("failed to get raw payloads: %v", err)
+	}
+	if len(payloads) != 1 {
+		t.Fatalf("expected one exchange, got %d", len(payloads))
+	}
+
+	payload := payloads[0]
+	if payload.Method != http.MethodPost || payload.URL != server.URL+"/api/chat" || payload.StatusCode != http.StatusOK {
+		t.Errorf("expected the chat call, got %s %s %d", payload.Method, payload.URL, payload.StatusCode)
+	}
+	if !strings.Contains(payload.RequestBody, `"model":"llama3.2"`) || payload.RequestSize != len(payload.RequestBody) {
+		t.Errorf("expected the whole request body, got %q", payload.RequestBody)
+	}
+	if !payload.Truncated || payload.ResponseSize != len(reply) || !strings.Contains(payload.ResponseBody, "[truncated: stored 100 of ") {
+		t.Errorf("expected the response cut to 100 bytes, got %d bytes: %q", payload.ResponseSize, payload.ResponseBody)
+	}
+	if strings.Contains(payload.ResponseBody, "sk-abc") || !strings.Contains(payload.ResponseBody, redactedValue) {
+		t.Errorf("expected the secret key scrubbed, got %q", payload.ResponseBody)
+	}
+	if payload.StoredBytes == 0 {
+		t.Error("expected the compressed size to be reported")
+	}
+	if other, err := client.GetRawPayloads(ctx, "user-2", "run-1", ""); err != nil || len(other) != 0 {
+		t.Errorf("expected no payloads for another user, got %v, %v", other, err)
+	}
+}
+
+func TestValidateRawPayloadLimit(t *testing.T) {
+	for _, maxBytes := range []int{0, 1024, MaxRawPayloadMaxBytes} {
+		if err := ValidateRawPayloadLimit(maxBytes); err != nil {
+			t.Errorf("expected %d to be valid, got %v", maxBytes, err)
+		}
+	}
+	for _, maxBytes := range []int{-1, MaxRawPayloadMaxBytes + 1} {
+		if err := ValidateRawPayloadLimit(maxBytes); err == nil {
+			t.Errorf("expected %d to be rejected", maxBytes)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package gogent
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gogent/internal/types"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redactedValue replaces removed header values and scrubbed pattern matches
+const redactedValue = "[REDACTED]"
+
+// DefaultRedactionPolicy keeps common non-sensitive headers, scrubs API keys and bearer tokens and
+// stores bodies of up to 256 KiB
+func DefaultRedactionPolicy() *types.RedactionPolicy {
+	return &types.RedactionPolicy{
+		HeaderAllowlist: []string{"Accept", "Content-Length", "Content-Type", "Date", "User-Agent", "X-Request-Id"},
+		Patterns: []string{
+			`AIza[0-9A-Za-z_\-]{35}`,             // Google API keys
+			`(?i)bearer\s+[A-Za-z0-9._~+/\-]+=*`, // Bearer tokens
+			`sk-[A-Za-z0-9_\-]{20,}`,             // Secret keys in the sk- format
+		},
+		MaxBodyBytes: 256 * 1024,
+	}
+}
+
+// ParseRedactionPolicy parses a redaction policy written as YAML or JSON and validates it
+func ParseRedactionPolicy(data []byte) (*types.RedactionPolicy, error) {
+	// Decoded as YAML and mapped through the JSON field names, as run specs are
+	var document interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse redaction policy: %w", err)
+	}
+	encoded, err := json.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse redaction policy: %w", err)
+	}
+
+	var policy types.RedactionPolicy
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&policy); err != nil {
+		return nil, fmt.Errorf("invalid redaction policy: %w", err)
+	}
+	if err := ValidateRedactionPolicy(&policy); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// ValidateRedactionPolicy checks that every pattern compiles and the body size limit is not negative
+func ValidateRedactionPolicy(policy *types.RedactionPolicy) error {
+	if policy.MaxBodyBytes < 0 {
+		return fmt.Errorf("invalid redaction policy: maxBodyBytes must not be negative")
+	}
+	for i, pattern := range policy.Patterns {
+		if pattern == "" {
+			return fmt.Errorf("invalid redaction policy: pattern %d is empty", i+1)
+		}
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid redaction policy: pattern %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// RedactionPolicyFingerprint identifies a policy in the redaction records of stored payloads
+func RedactionPolicyFingerprint(policy *types.RedactionPolicy) string {
+	encoded, _ := json.Marshal(policy)
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// redactor applies a compiled redaction policy to copies of requests and responses
+type redactor struct {
+	policy      *types.RedactionPolicy
+	fingerprint string
+	allowed     map[string]bool
+	patterns    []*regexp.Regexp
+}
+
+func newRedactor(policy *types.RedactionPolicy) (*redactor, error) {
+	if policy == nil {
+		policy = DefaultRedactionPolicy()
+	}
+	if err := ValidateRedactionPolicy(policy); err != nil {
+		return nil, err
+	}
+
+	r := &redactor{
+		policy:      policy,
+		fingerprint: RedactionPolicyFingerprint(policy),
+		allowed:     make(map[string]bool, len(policy.HeaderAllowlist)),
+	}
+	for _, header := range policy.HeaderAllowlist {
+		r.allowed[strings.ToLower(header)] = true
+	}
+	for _, pattern := range policy.Patterns {
+		r.patterns = append(r.patterns, regexp.MustCompile(pattern))
+	}
+	return r, nil
+}
+
+// payloadRedactor returns the redactor for the client's policy. An invalid policy is logged and the
+// default policy used instead, so payloads are never stored unredacted.
+func (c *Client) payloadRedactor() *redactor {
+	c.redactorOnce.Do(func() {
+		var policy *types.RedactionPolicy
+		if c.config != nil {
+			policy = c.config.Redaction
+		}
+		redactor, err := newRedactor(policy)
+		if err != nil {
+			log.Printf("⚠️ Warning: %v; using the default redaction policy", err)
+			redactor, _ = newRedactor(nil)
+		}
+		c.redactor = redactor
+	})
+	return c.redactor
+}
+
+// redactRequest returns a copy of the request with the policy applied and recorded
+func (r *redactor) redactRequest(request *types.APIRequest) *types.APIRequest {
+	redacted := *request
+	record := &types.RedactionRecord{Policy: r.fingerprint}
+
+	redacted.Prompt = r.scrubString(request.Prompt, record)
+	redacted.Context = r.scrubString(request.Context, record)
+	redacted.FunctionParameters = r.scrubMap(request.FunctionParameters, record)
+	redacted.RequestHeaders = r.redactHeaders(request.RequestHeaders, record)
+	redacted.RequestBody = r.limitBody("requestBody", r.scrubMap(request.RequestBody, record), record)
+	redacted.Redaction = record
+	return &redacted
+}
+
+// redactResponse returns a copy of the response with the policy applied and recorded
+func (r *redactor) redactResponse(response *types.APIResponse) *types.APIResponse {
+	redacted := *response
+	record := &types.RedactionRecord{Policy: r.fingerprint}
+
+	redacted.ResponseText = r.scrubString(response.ResponseText, record)
+	redacted.ThoughtSummary = r.scrubString(response.ThoughtSummary, record)
+	redacted.ErrorMessage = r.scrubString(response.ErrorMessage, record)
+	redacted.FunctionCallResponse = r.scrubMap(response.FunctionCallResponse, record)
+	redacted.ResponseHeaders = r.redactHeaders(response.ResponseHeaders, record)
+	redacted.ResponseBody = r.limitBody("responseBody", r.scrubMap(response.ResponseBody, record), record)
+	redacted.Redaction = record
+	return &redacted
+}
+
+// redactHeaders keeps allowlisted headers and replaces the values of the rest
+func (r *redactor) redactHeaders(headers map[string]interface{}, record *types.RedactionRecord) map[string]interface{} {
+	if headers == nil {
+		return nil
+	}
+	redacted := make(map[string]interface{}, len(headers))
+	for name, value := range headers {
+		if r.allowed[strings.ToLower(name)] {
+			redacted[name] = value
+			continue
+		}
+		redacted[name] = redactedValue
+		record.RedactedHeaders = append(record.RedactedHeaders, name)
+	}
+	sort.Strings(record.RedactedHeaders)
+	return redacted
+}
+
+// limitBody replaces a body larger than the size limit with a note of its size
+func (r *redactor) limitBody(field string, body map[string]interface{}, record *types.RedactionRecord) map[string]interface{} {
+	if body == nil || r.policy.MaxBodyBytes == 0 {
+		return body
+	}
+	encoded, err := json.Marshal(body)
+	if err != nil || len(encoded) <= r.policy.MaxBodyBytes {
+		return body
+	}
+	record.TruncatedFields = append(record.TruncatedFields, field)
+	return map[string]interface{}{"truncated": true, "sizeBytes": len(encoded)}
+}
+
+func (r *redactor) scrubString(value string, record *types.RedactionRecord) string {
+	for _, pattern := range r.patterns {
+		value = pattern.ReplaceAllStringFunc(value, func(string) string {
+			record.ScrubbedMatches++
+			return redactedValue
+		})
+	}
+	return value
+}
+
+// scrubMap returns a copy of a JSON object with pattern matches scrubbed from every string in it
+func (r *redactor) scrubMap(values map[string]interface{}, record *types.RedactionRecord) map[string]interface{} {
+	if values == nil {
+		return nil
+	}
+	scrubbed := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		scrubbed[key] = r.scrubValue(value, record)
+	}
+	return scrubbed
+}
+
+func (r *redactor) scrubValue(value interface{}, record *types.RedactionRecord) interface{} {
+	switch v := value.(type) {
+	case string:
+		return r.scrubString(v, record)
+	case map[string]interface{}:
+		return r.scrubMap(v, record)
+	case []interface{}:
+		scrubbed := make([]interface{}, len(v))
+		for i, item := range v {
+			scrubbed[i] = r.scrubValue(item, record)
+		}
+		return scrubbed
+	default:
+		return value
+	}
+}
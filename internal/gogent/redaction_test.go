@@ -0,0 +1,114 @@
+package gogent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gogent/internal/types"
+)
+
+func TestRedactRequest(t *testing.T) {
+	redactor, err := newRedactor(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	apiKey := "AIza" + strings.Repeat("x", 35)
+	request := &types.APIRequest{
+		ID:             "request-1",
+		Prompt:         "Use key " + apiKey + " to call the API",
+		RequestHeaders: map[string]interface{}{"Content-Type": "application/json", "Authorization": "Bearer abc.def"},
+		RequestBody: map[string]interface{}{
+			"contents": []interface{}{map[string]interface{}{"text": "token sk-" + strings.Repeat("a", 24)}},
+			"count":    3,
+		},
+	}
+
+	redacted := redactor.redactRequest(request)
+	if strings.Contains(redacted.Prompt, apiKey) || !strings.Contains(redacted.Prompt, redactedValue) {
+		t.Errorf("expected the API key to be scrubbed from the prompt, got %q", redacted.Prompt)
+	}
+	if redacted.RequestHeaders["Content-Type"] != "application/json" || redacted.RequestHeaders["Authorization"] != redactedValue {
+		t.Errorf("expected only allowlisted headers to be kept, got %+v", redacted.RequestHeaders)
+	}
+	text := redacted.RequestBody["contents"].([]interface{})[0].(map[string]interface{})["text"]
+	if text != "token "+redactedValue || redacted.RequestBody["count"] != 3 {
+		t.Errorf("expected nested body strings to be scrubbed, got %+v", redacted.RequestBody)
+	}
+
+	record := redacted.Redaction
+	if record == nil || record.Policy != RedactionPolicyFingerprint(DefaultRedactionPolicy()) ||
+		record.ScrubbedMatches != 2 || len(record.RedactedHeaders) != 1 || record.RedactedHeaders[0] != "Authorization" {
+		t.Errorf("unexpected redaction record: %+v", record)
+	}
+
+	// The caller's request is left as it was
+	if !strings.Contains(request.Prompt, apiKey) || request.RequestHeaders["Authorization"] != "Bearer abc.def" || request.Redaction != nil {
+		t.Errorf("expected the original request to be unchanged, got %+v", request)
+	}
+}
+
+func TestRedactResponseBodyLimit(t *testing.T) {
+	redactor, err := newRedactor(&types.RedactionPolicy{Patterns: []string{`\d{3}-\d{2}-\d{4}`}, MaxBodyBytes: 32})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	redacted := redactor.redactResponse(&types.APIResponse{
+		ResponseText:    "SSN 123-45-6789",
+		ResponseHeaders: map[string]interface{}{"Content-Type": "application/json"},
+		ResponseBody:    map[string]interface{}{"text": strings.Repeat("long ", 20)},
+	})
+	if redacted.ResponseText != "SSN "+redactedValue {
+		t.Errorf("expected the configured pattern to be scrubbed, got %q", redacted.ResponseText)
+	}
+	if redacted.ResponseHeaders["Content-Type"] != redactedValue {
+		t.Errorf("expected every header to be redacted without an allowlist, got %+v", redacted.ResponseHeaders)
+	}
+	if redacted.ResponseBody["truncated"] != true || len(redacted.Redaction.TruncatedFields) != 1 {
+		t.Errorf("expected the oversized body to be dropped, got %+v (%+v)", redacted.ResponseBody, redacted.Redaction)
+	}
+}
+
+func TestParseRedactionPolicy(t *testing.T) {
+	policy, err := ParseRedactionPolicy([]byte("headerAllowlist: [Content-Type]\npatterns: ['[\\w.]+@example\\.com']\nmaxBodyBytes: 1024\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(policy.HeaderAllowlist) != 1 || len(policy.Patterns) != 1 || policy.MaxBodyBytes != 1024 {
+		t.Errorf("unexpected policy: %+v", policy)
+	}
+
+	for _, document := range []string{
+		`{"patterns": ["(unclosed"]}`,
+		`{"patterns": [""]}`,
+		`{"maxBodyBytes": -1}`,
+		`{"headers": ["Content-Type"]}`,
+	} {
+		if _, err := ParseRedactionPolicy([]byte(document)); err == nil {
+			t.Errorf("expected %s to be rejected", document)
+		}
+	}
+}
+
+func TestLogAPIRequestRedacts(t *testing.T) {
+	client := NewInMemoryClient(&types.GeminiClientConfig{Redaction: &types.RedactionPolicy{Patterns: []string{"secret"}}})
+	ctx := context.Background()
+
+	run, err := client.CreateExecutionRun(ctx, "user-1", "Redaction", "", false)
+	if err != nil {
+		t.Fatalf("failed to create run: %v", err)
+	}
+	if err := client.LogAPIRequest(ctx, "user-1", &types.APIRequest{ID: "request-1", ExecutionRunID: run.ID, Prompt: "the secret word"}); err != nil {
+		t.Fatalf("failed to log request: %v", err)
+	}
+
+	requests, err := client.store.ListAPIRequestsByRun(ctx, "user-1", run.ID)
+	if err != nil || len(requests) != 1 {
+		t.Fatalf("expected the stored request, got %+v (%v)", requests, err)
+	}
+	if requests[0].Prompt != "the "+redactedValue+" word" || requests[0].Redaction == nil {
+		t.Errorf("expected the stored prompt to be redacted and recorded, got %+v", requests[0])
+	}
+}
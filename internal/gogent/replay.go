@@ -0,0 +1,182 @@
+package gogent
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"gogent/internal/types"
+)
+
+// ErrExecutionRunNotFound is returned when a run does not exist or is not owned by the user
+var ErrExecutionRunNotFound = errors.New("execution run not found")
+
+// ReplayExecutionRun re-executes a run's prompt and configurations as a new run linked to the
+// original. Function calls return the responses recorded by the original run instead of calling
+// the functions, so a replay never reaches an external function API.
+func (c *Client) ReplayExecutionRun(ctx context.Context, userID, executionRunID string) (*types.ExecutionResult, error) {
+	source, err := c.GetExecutionResult(ctx, userID, executionRunID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrExecutionRunNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(source.Results) == 0 {
+		return nil, fmt.Errorf("execution run %s has no results to replay", executionRunID)
+	}
+
+	recorded, err := c.loadRecordedFunctionCalls(ctx, userID, executionRunID)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("⏪ Replaying execution run %s with %d recorded function calls", executionRunID, countRecordedCalls(recorded))
+	return c.ExecuteMultiVariation(ctx, userID, buildReplayRequest(source, recorded))
+}
+
+// buildReplayRequest rebuilds the request that produced a run, including its repetitions, attaching
+// each configuration's recorded function calls
+func buildReplayRequest(source *types.ExecutionResult, recorded map[string][]types.FunctionCall) *types.MultiExecutionRequest {
+	first := source.Results[0]
+	request := &types.MultiExecutionRequest{
+		ExecutionRunName:      "Replay of " + source.ExecutionRun.Name,
+		Description:           fmt.Sprintf("Replay of execution run %s with recorded function responses", source.ExecutionRun.ID),
+		BasePrompt:            first.Request.Prompt,
+		Context:               first.Request.Context,
+		EnableFunctionCalling: source.ExecutionRun.EnableFunctionCalling,
+		FunctionTools:         first.Configuration.Tools,
+		ReplayOfRunID:         source.ExecutionRun.ID,
+	}
+
+	added := make(map[string]bool)
+	for _, r := range source.Results {
+		request.Repetitions = max(request.Repetitions, r.Repetition)
+		if added[r.Configuration.ID] {
+			continue
+		}
+		added[r.Configuration.ID] = true
+
+		config := r.Configuration
+		config.ID = ""
+		config.ExecutionRunID = ""
+		config.Replay = true
+		config.RecordedFunctionCalls = recorded[r.Configuration.ID]
+		request.Configurations = append(request.Configurations, config)
+	}
+	return request
+}
+
+// loadRecordedFunctionCalls returns the function calls of a run owned by the user in call order, keyed
+// by configuration ID
+func (c *Client) loadRecordedFunctionCalls(ctx context.Context, userID, executionRunID string) (map[string][]types.FunctionCall, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT ar.configuration_id, fc.function_name, fc.function_arguments, fc.function_response,
+		       fc.execution_status, fc.error_details
+		FROM function_calls fc
+		JOIN api_requests ar ON fc.request_id = ar.id
+		WHERE ar.execution_run_id = ? AND ar.user_id = ?
+		ORDER BY fc.created_at ASC
+	`, executionRunID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recorded function calls: %w", err)
+	}
+	defer rows.Close()
+
+	recorded := make(map[string][]types.FunctionCall)
+	for rows.Next() {
+		var configID string
+		var call types.FunctionCall
+		var argsJSON, responseJSON, status, errorDetails sql.NullString
+		if err := rows.Scan(&configID, &call.FunctionName, &argsJSON, &responseJSON, &status, &errorDetails); err != nil {
+			return nil, fmt.Errorf("failed to scan recorded function call: %w", err)
+		}
+		if err := types.FromJSON(argsJSON.String, &call.FunctionArgs); err != nil {
+			return nil, fmt.Errorf("failed to parse recorded function arguments: %w", err)
+		}
+		if err := types.FromJSON(responseJSON.String, &call.FunctionResponse); err != nil {
+			return nil, fmt.Errorf("failed to parse recorded function response: %w", err)
+		}
+		call.ExecutionStatus = status.String
+		call.ErrorDetails = errorDetails.String
+		recorded[configID] = append(recorded[configID], call)
+	}
+	return recorded, rows.Err()
+}
+
+// recordedFunctionResponse returns the recorded response for a call, preferring one made with the
+// same arguments and falling back to the first call of the same function. Recorded failures are
+// replayed as errors.
+func recordedFunctionResponse(calls []types.FunctionCall, functionName string, args map[string]interface{}) (map[string]interface{}, error) {
+	// encoding/json sorts map keys, so equal arguments always serialize identically
+	wanted, _ := json.Marshal(args)
+
+	var match *types.FunctionCall
+	for i := range calls {
+		if calls[i].FunctionName != functionName {
+			continue
+		}
+		if recordedArgs, _ := json.Marshal(calls[i].FunctionArgs); string(recordedArgs) == string(wanted) {
+			match = &calls[i]
+			break
+		}
+		if match == nil {
+			match = &calls[i]
+		}
+	}
+
+	if match == nil {
+		return nil, fmt.Errorf("no recorded response for function %s in the replayed run", functionName)
+	}
+	if match.ExecutionStatus == "error" {
+		return match.FunctionResponse, fmt.Errorf("recorded call failed: %s", match.ErrorDetails)
+	}
+	return match.FunctionResponse, nil
+}
+
+// countRecordedCalls counts recorded function calls across configurations
+func countRecordedCalls(recorded map[string][]types.FunctionCall) int {
+	total := 0
+	for _, calls := range recorded {
+		total += len(calls)
+	}
+	return total
+}
+
+// recordReplay links a replay run to the run it replays
+func (c *Client) recordReplay(ctx context.Context, executionRunID, replayOfRunID string) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	_, err := c.db.ExecContext(ctx,
+		"UPDATE execution_runs SET replay_of_run_id = ? WHERE id = ?",
+		replayOfRunID, executionRunID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record replay link: %w", err)
+	}
+	return nil
+}
+
+// loadReplay fills in the run an execution run replays, if any
+func (c *Client) loadReplay(ctx context.Context, run *types.ExecutionRun) error {
+	if c.db == nil {
+		return nil
+	}
+	var replayOfRunID sql.NullString
+	err := c.db.QueryRowContext(ctx,
+		"SELECT replay_of_run_id FROM execution_runs WHERE id = ?", run.ID,
+	).Scan(&replayOfRunID)
+	if err != nil {
+		return fmt.Errorf("failed to load replay link: %w", err)
+	}
+
+	run.ReplayOfRunID = replayOfRunID.String
+	return nil
+}
@@ -0,0 +1,159 @@
+package gogent
+
+import (
+	"context"
+	"testing"
+
+	"gogent/internal/testdb"
+	"gogent/internal/types"
+)
+
+// newReplayTestClient returns a client backed by the in-memory test schema
+func newReplayTestClient(t *testing.T) *Client {
+	return &Client{db: testdb.Open(t)}
+}
+
+func TestRecordedFunctionResponse(t *testing.T) {
+	calls := []types.FunctionCall{
+		{FunctionName: "get_current_weather", FunctionArgs: map[string]interface{}{"location": "Paris"},
+			FunctionResponse: map[string]interface{}{"temperature": float64(18)}, ExecutionStatus: "success"},
+		{FunctionName: "get_current_weather", FunctionArgs: map[string]interface{}{"location": "Oslo"},
+			FunctionResponse: map[string]interface{}{"temperature": float64(4)}, ExecutionStatus: "success"},
+		{FunctionName: "query_graph", FunctionArgs: map[string]interface{}{"query": "MATCH (n) RETURN n"},
+			FunctionResponse: map[string]interface{}{"error": "timeout"}, ExecutionStatus: "error", ErrorDetails: "timeout"},
+	}
+
+	tests := []struct {
+		name         string
+		functionName string
+		args         map[string]interface{}
+		expectTemp   float64
+		expectError  bool
+	}{
+		{"matching_arguments", "get_current_weather", map[string]interface{}{"location": "Oslo"}, 4, false},
+		{"first_call_fallback", "get_current_weather", map[string]interface{}{"location": "Rome"}, 18, false},
+		{"recorded_failure", "query_graph", map[string]interface{}{"query": "MATCH (n) RETURN n"}, 0, true},
+		{"never_recorded", "send_email", nil, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := recordedFunctionResponse(calls, tt.functionName, tt.args)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("expected error=%v, got %v", tt.expectError, err)
+			}
+			if !tt.expectError && response["temperature"] != tt.expectTemp {
+				t.Errorf("expected temperature %v, got %v", tt.expectTemp, response)
+			}
+		})
+	}
+}
+
+func TestBuildReplayRequest(t *testing.T) {
+	tools := []types.Tool{{Name: "get_current_weather"}}
+	source := &types.ExecutionResult{
+		ExecutionRun: types.ExecutionRun{ID: "run-1", Name: "weather-check", EnableFunctionCalling: true},
+		Results: []types.VariationResult{
+			{
+				Configuration: types.APIConfiguration{ID: "config-1", ExecutionRunID: "run-1", VariationName: "cold", ModelName: "gemini-1.5-flash", Tools: tools},
+				Request:       types.APIRequest{Prompt: "What's the weather in Paris?", Context: "travel"},
+			},
+			{
+				Configuration: types.APIConfiguration{ID: "config-2", ExecutionRunID: "run-1", VariationName: "warm", ModelName: "gemini-1.5-pro", Tools: tools},
+				Request:       types.APIRequest{Prompt: "What's the weather in Paris?", Context: "travel"},
+			},
+		},
+	}
+	recorded := map[string][]types.FunctionCall{
+		"config-2": {{FunctionName: "get_current_weather"}},
+	}
+
+	request := buildReplayRequest(source, recorded)
+	if request.ReplayOfRunID != "run-1" || request.BasePrompt != "What's the weather in Paris?" || request.Context != "travel" {
+		t.Errorf("expected the original prompt linked to run-1, got %+v", request)
+	}
+	if !request.EnableFunctionCalling || len(request.FunctionTools) != 1 || len(request.Configurations) != 2 {
+		t.Fatalf("expected function calling with the original tools and configurations, got %+v", request)
+	}
+	for _, config := range request.Configurations {
+		if config.ID != "" || config.ExecutionRunID != "" || !config.Replay {
+			t.Errorf("expected a fresh replay configuration, got %+v", config)
+		}
+	}
+	if len(request.Configurations[0].RecordedFunctionCalls) != 0 || len(request.Configurations[1].RecordedFunctionCalls) != 1 {
+		t.Errorf("expected each configuration to get its own recorded calls")
+	}
+}
+
+func TestLoadRecordedFunctionCalls(t *testing.T) {
+	client := newReplayTestClient(t)
+	ctx := context.Background()
+
+	_, err := client.db.Exec(`
+		INSERT INTO api_requests (id, user_id, execution_run_id, configuration_id) VALUES
+			('req-1', 'user-1', 'run-1', 'config-1'), ('req-2', 'user-1', 'run-1', 'config-2'), ('req-3', 'user-1', 'run-2', 'config-3');
+		INSERT INTO function_calls (id, request_id, function_name, function_arguments, function_response, execution_status, created_at) VALUES
+			('call-2', 'req-1', 'query_graph', '{"query":"b"}', '{"nodes":[]}', 'success', '2026-01-01 10:00:02'),
+			('call-1', 'req-1', 'query_graph', '{"query":"a"}', '{"nodes":[]}', 'success', '2026-01-01 10:00:01'),
+			('call-3', 'req-2', 'get_current_weather', '{"location":"Paris"}', NULL, 'error', '2026-01-01 10:00:03'),
+			('call-4', 'req-3', 'get_current_weather', '{"location":"Oslo"}', '{}', 'success', '2026-01-01 10:00:04');
+	`)
+	if err != nil {
+		t.Fatalf("failed to insert test data: %v", err)
+	}
+
+	recorded, err := client.loadRecordedFunctionCalls(ctx, "user-1", "run-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(recorded) != 2 || countRecordedCalls(recorded) != 3 {
+		t.Fatalf("expected 3 calls for run-1's two configurations, got %+v", recorded)
+	}
+	if recorded["config-1"][0].FunctionArgs["query"] != "a" {
+		t.Errorf("expected calls in call order, got %+v", recorded["config-1"])
+	}
+	if recorded["config-2"][0].ExecutionStatus != "error" || recorded["config-2"][0].FunctionResponse != nil {
+		t.Errorf("expected the failed call without a response, got %+v", recorded["config-2"][0])
+	}
+	if other, err := client.loadRecordedFunctionCalls(ctx, "user-2", "run-1"); err != nil || len(other) != 0 {
+		t.Errorf("expected no calls for another user's run, got %+v (%v)", other, err)
+	}
+}
+
+func TestReplayLink(t *testing.T) {
+	client := newReplayTestClient(t)
+	ctx := context.Background()
+
+	client.db.Exec("INSERT INTO execution_runs (id, user_id, name) VALUES ('run-1', 'user-1', 'original'), ('run-2', 'user-1', 'Replay of original')")
+	if err := client.recordReplay(ctx, "run-2", "run-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	replay := &types.ExecutionRun{ID: "run-2"}
+	original := &types.ExecutionRun{ID: "run-1"}
+	if err := client.loadReplay(ctx, replay); err != nil || replay.ReplayOfRunID != "run-1" {
+		t.Errorf("expected run-2 to replay run-1, got %q, %v", replay.ReplayOfRunID, err)
+	}
+	if err := client.loadReplay(ctx, original); err != nil || original.ReplayOfRunID != "" {
+		t.Errorf("expected run-1 not to be a replay, got %q, %v", original.ReplayOfRunID, err)
+	}
+}
+
+func TestBuildReplayRequestRepetitions(t *testing.T) {
+	source := &types.ExecutionResult{ExecutionRun: types.ExecutionRun{ID: "run-1", Name: "repeated"}}
+	for repetition := 1; repetition <= 3; repetition++ {
+		for _, id := range []string{"config-1", "config-2"} {
+			source.Results = append(source.Results, types.VariationResult{
+				Configuration: types.APIConfiguration{ID: id, VariationName: id},
+				Request:       types.APIRequest{Prompt: "Summarize the report"},
+				Repetition:    repetition,
+			})
+		}
+	}
+
+	request := buildReplayRequest(source, nil)
+	if len(request.Configurations) != 2 || request.Repetitions != 3 {
+		t.Errorf("expected 2 configurations repeated 3 times, got %d configurations and %d repetitions",
+			len(request.Configurations), request.Repetitions)
+	}
+}
@@ -0,0 +1,538 @@
+package gogent
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"gogent/internal/types"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrReportNotFound is returned for a saved report that does not exist
+	ErrReportNotFound = errors.New("report not found")
+	// ErrInvalidReport is returned for report definitions that fail validation
+	ErrInvalidReport = errors.New("invalid report")
+	// ErrInvalidReportArguments is returned when a report is run with missing or malformed parameters
+	ErrInvalidReportArguments = errors.New("invalid report arguments")
+)
+
+const (
+	// maxReportRows caps the rows a single report returns
+	maxReportRows = 1000
+	// reportTimeout bounds a report's query
+	reportTimeout = 30 * time.Second
+	// reportUserParameter is bound to the user running a report and cannot be set by the request
+	reportUserParameter = "user_id"
+)
+
+// reportTables are the tables a report may read: the execution history, the data users keep
+// alongside it and the daily rollups. Users, sessions, API keys and the audit log are left out.
+var reportTables = map[string]bool{
+	"execution_runs": true, "api_configurations": true, "api_requests": true, "api_responses": true,
+	"function_calls": true, "execution_logs": true, "comparison_results": true,
+	"function_definitions": true, "execution_function_configs": true, "configuration_presets": true,
+	"execution_run_tags": true, "batch_runs": true, "suite_slos": true, "judgments": true,
+	"evaluation_results": true, "response_feedback": true, "documents": true,
+	"retrieved_chunks": true, "guard_verdicts": true, "daily_user_rollups": true,
+}
+
+// reportCredentialColumns are the columns of report tables that hold credentials: the headers and
+// auth of function definitions and the headers sent to and received from providers. Since any
+// signed-in user can run a report, a query reading one of these tables may not name them or select
+// its columns with *.
+var reportCredentialColumns = map[string][]string{
+	"function_definitions": {"headers", "auth_config"},
+	"api_requests":         {"request_headers"},
+	"api_responses":        {"response_headers"},
+}
+
+// reportDeniedFunctions read files or stall the database, so reports may not call them
+var reportDeniedFunctions = map[string]bool{
+	"LOAD_FILE": true, "SLEEP": true, "BENCHMARK": true, "GET_LOCK": true, "RELEASE_LOCK": true,
+}
+
+// reportDeniedStatements read a table without a FROM clause, as in SELECT 1 UNION TABLE users, so
+// reports may not use them
+var reportDeniedStatements = map[string]bool{"TABLE": true, "VALUES": true}
+
+// reportClauseKeywords end the table list of a FROM clause
+var reportClauseKeywords = map[string]bool{
+	"WHERE": true, "GROUP": true, "HAVING": true, "ORDER": true, "LIMIT": true, "WINDOW": true,
+	"UNION": true, "EXCEPT": true, "INTERSECT": true,
+}
+
+// reportParameterTypes are the types a report parameter may have; empty means string
+var reportParameterTypes = map[string]bool{
+	"": true, types.ReportParameterString: true, types.ReportParameterInt: true,
+	types.ReportParameterFloat: true, types.ReportParameterBool: true, types.ReportParameterDate: true,
+}
+
+var (
+	reportNamePattern          = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,99}$`)
+	reportParameterNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,63}$`)
+)
+
+// reportToken is a word, quoted name, string literal, :parameter or punctuation of a report's query
+type reportToken struct {
+	kind       byte   // 'w' word, 'q' quoted name, 's' string literal, 'p' parameter, or the punctuation
+	text       string // Upper-cased for words; the name for quoted names and parameters
+	start, end int    // Rune offsets of the token in the query
+}
+
+// reportTokens splits a query already checked by validateReadOnlySQL into tokens
+func reportTokens(query string) []reportToken {
+	var tokens []reportToken
+	runes := []rune(query)
+	isWord := func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' }
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\'' || r == '"' || r == '`':
+			end := i + 1
+			// Backslash escapes a character in strings, but not in backtick-quoted names
+			for end < len(runes) && runes[end] != r {
+				if runes[end] == '\\' && r != '`' {
+					end++
+				}
+				end++
+			}
+			kind := byte('q')
+			if r == '\'' {
+				kind = 's'
+			}
+			tokens = append(tokens, reportToken{kind: kind, text: string(runes[i+1 : min(end, len(runes))]), start: i, end: end + 1})
+			i = end
+		case r == ':' && i+1 < len(runes) && (unicode.IsLetter(runes[i+1]) || runes[i+1] == '_'):
+			end := i + 1
+			for end < len(runes) && isWord(runes[end]) {
+				end++
+			}
+			tokens = append(tokens, reportToken{kind: 'p', text: string(runes[i+1 : end]), start: i, end: end})
+			i = end - 1
+		case isWord(r):
+			end := i
+			for end < len(runes) && isWord(runes[end]) {
+				end++
+			}
+			tokens = append(tokens, reportToken{kind: 'w', text: strings.ToUpper(string(runes[i:end])), start: i, end: end})
+			i = end - 1
+		case !unicode.IsSpace(r):
+			tokens = append(tokens, reportToken{kind: byte(r), start: i, end: i + 1})
+		}
+	}
+	return tokens
+}
+
+// reportQueryTables returns the tables a report's query reads, excluding the names of its own WITH
+// clauses. Tables are found after FROM and JOIN and after the commas of a FROM list.
+func reportQueryTables(tokens []reportToken) ([]string, error) {
+	ctes := make(map[string]bool)
+	for i := 0; i+2 < len(tokens); i++ {
+		if (tokens[i].kind == 'w' || tokens[i].kind == 'q') && tokens[i+1].kind == 'w' && tokens[i+1].text == "AS" && tokens[i+2].kind == '(' {
+			ctes[strings.ToLower(tokens[i].text)] = true
+		}
+	}
+
+	var tables []string
+	var fromDepths []int  // Paren depths of the FROM clauses whose table lists are being read
+	var subqueries []bool // Whether each open paren holds a query rather than an expression
+	expectTable := false
+	for i, token := range tokens {
+		depth := len(subqueries)
+		if expectTable {
+			expectTable = false
+			if token.kind == 'w' || token.kind == 'q' {
+				if i+1 < len(tokens) && tokens[i+1].kind == '.' {
+					return nil, fmt.Errorf("tables may not be qualified with a schema")
+				}
+				name := strings.ToLower(token.text)
+				if !ctes[name] {
+					tables = append(tables, name)
+				}
+				continue
+			}
+		}
+
+		inFrom := len(fromDepths) > 0 && fromDepths[len(fromDepths)-1] == depth
+		switch {
+		case token.kind == '(':
+			next := ""
+			if i+1 < len(tokens) && tokens[i+1].kind == 'w' {
+				next = tokens[i+1].text
+			}
+			subqueries = append(subqueries, next == "SELECT" || next == "WITH")
+		case token.kind == ')' && depth > 0:
+			subqueries = subqueries[:depth-1]
+			for len(fromDepths) > 0 && fromDepths[len(fromDepths)-1] >= depth {
+				fromDepths = fromDepths[:len(fromDepths)-1]
+			}
+		case token.kind == 'w' && token.text == "FROM":
+			// FROM inside an expression, as in EXTRACT(YEAR FROM created_at), names no table
+			if depth == 0 || subqueries[depth-1] {
+				fromDepths = append(fromDepths, depth)
+				expectTable = true
+			}
+		case token.kind == 'w' && (token.text == "JOIN" || inFrom && strings.HasSuffix(token.text, "JOIN")):
+			// MySQL also joins with STRAIGHT_JOIN, which as a SELECT modifier is outside a FROM clause
+			expectTable = true
+		case token.kind == ',' && inFrom:
+			expectTable = true
+		case token.kind == 'w' && inFrom && reportClauseKeywords[token.text]:
+			fromDepths = fromDepths[:len(fromDepths)-1]
+		}
+	}
+	return tables, nil
+}
+
+// ValidateReport checks a report's name, parameters and query. The query must be a single SELECT
+// that reads only the report tables, none of their credential columns, and names no parameter the
+// report does not declare.
+func ValidateReport(report *types.SavedReport) error {
+	if !reportNamePattern.MatchString(report.Name) {
+		return fmt.Errorf("%w: name must be lower-case letters, digits, dashes and underscores", ErrInvalidReport)
+	}
+
+	declared := map[string]bool{reportUserParameter: true}
+	for _, parameter := range report.Parameters {
+		if !reportParameterNamePattern.MatchString(parameter.Name) {
+			return fmt.Errorf("%w: invalid parameter name %q", ErrInvalidReport, parameter.Name)
+		}
+		if declared[parameter.Name] {
+			return fmt.Errorf("%w: parameter %s is declared twice or reserved", ErrInvalidReport, parameter.Name)
+		}
+		declared[parameter.Name] = true
+		if !reportParameterTypes[parameter.Type] {
+			return fmt.Errorf("%w: parameter %s has unknown type %q", ErrInvalidReport, parameter.Name, parameter.Type)
+		}
+		if parameter.Default != "" {
+			if _, err := parseReportArgument(parameter, parameter.Default); err != nil {
+				return fmt.Errorf("%w: default of %s: %v", ErrInvalidReport, parameter.Name, err)
+			}
+		}
+	}
+
+	// Reports run on gogent's own database, which is MySQL
+	query, err := validateReadOnlySQL(report.Query, "mysql")
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidReport, err)
+	}
+	tokens := reportTokens(query)
+	for _, token := range tokens {
+		switch {
+		case token.kind == '?':
+			return fmt.Errorf("%w: name parameters as :name instead of ?", ErrInvalidReport)
+		case token.kind == 'p' && !declared[token.text]:
+			return fmt.Errorf("%w: parameter :%s is not declared", ErrInvalidReport, token.text)
+		case token.kind == 'w' && reportDeniedFunctions[token.text]:
+			return fmt.Errorf("%w: %s is not permitted", ErrInvalidReport, token.text)
+		case token.kind == 'w' && reportDeniedStatements[token.text]:
+			return fmt.Errorf("%w: %s statements are not permitted; read tables with SELECT ... FROM", ErrInvalidReport, token.text)
+		}
+	}
+	tables, err := reportQueryTables(tokens)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidReport, err)
+	}
+	credentials := make(map[string]string)
+	for _, table := range tables {
+		if !reportTables[table] {
+			return fmt.Errorf("%w: table %s may not be used in reports", ErrInvalidReport, table)
+		}
+		for _, column := range reportCredentialColumns[table] {
+			credentials[column] = table
+		}
+	}
+	if len(credentials) > 0 {
+		for i, token := range tokens {
+			switch {
+			case (token.kind == 'w' || token.kind == 'q') && credentials[strings.ToLower(token.text)] != "":
+				return fmt.Errorf("%w: %s.%s holds credentials and may not be used in reports",
+					ErrInvalidReport, credentials[strings.ToLower(token.text)], strings.ToLower(token.text))
+			case token.kind == '*' && reportSelectsAll(tokens, i):
+				return fmt.Errorf("%w: name the columns to read instead of * in queries reading %s",
+					ErrInvalidReport, strings.Join(slices.Compact(slices.Sorted(maps.Values(credentials))), ", "))
+			}
+		}
+	}
+	report.Query = query
+	return nil
+}
+
+// reportSelectsAll reports whether the * at tokens[i] selects every column, as in SELECT * or t.*,
+// rather than multiplying or counting rows
+func reportSelectsAll(tokens []reportToken, i int) bool {
+	if i == 0 {
+		return false
+	}
+	previous := tokens[i-1]
+	switch previous.kind {
+	case ',', '.':
+		return true
+	case 'w':
+		return previous.text == "SELECT" || previous.text == "DISTINCT" || previous.text == "ALL" || previous.text == "DISTINCTROW"
+	}
+	return false
+}
+
+// parseReportArgument converts a request's value of a parameter to its type
+func parseReportArgument(parameter types.ReportParameter, value string) (interface{}, error) {
+	switch parameter.Type {
+	case "", types.ReportParameterString:
+		return value, nil
+	case types.ReportParameterInt:
+		return strconv.ParseInt(value, 10, 64)
+	case types.ReportParameterFloat:
+		return strconv.ParseFloat(value, 64)
+	case types.ReportParameterBool:
+		return strconv.ParseBool(value)
+	case types.ReportParameterDate:
+		if date, err := time.Parse("2006-01-02", value); err == nil {
+			return date, nil
+		}
+		return time.Parse(time.RFC3339, value)
+	default:
+		return nil, fmt.Errorf("parameter %s has unknown type %q", parameter.Name, parameter.Type)
+	}
+}
+
+// bindReport replaces a report's :name parameters with placeholders and returns their values in
+// order. Values come from arguments, then the parameter's default; :user_id is always userID.
+func bindReport(report *types.SavedReport, userID string, arguments map[string]string) (string, []interface{}, error) {
+	values := map[string]interface{}{reportUserParameter: userID}
+	parameters := make(map[string]bool, len(report.Parameters))
+	for _, parameter := range report.Parameters {
+		parameters[parameter.Name] = true
+		raw, ok := arguments[parameter.Name]
+		if !ok || raw == "" {
+			if parameter.Required {
+				return "", nil, fmt.Errorf("%w: %s is required", ErrInvalidReportArguments, parameter.Name)
+			}
+			raw = parameter.Default
+		}
+		if raw == "" {
+			values[parameter.Name] = nil
+			continue
+		}
+		value, err := parseReportArgument(parameter, raw)
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: %s: %v", ErrInvalidReportArguments, parameter.Name, err)
+		}
+		values[parameter.Name] = value
+	}
+	for name := range arguments {
+		if !parameters[name] {
+			return "", nil, fmt.Errorf("%w: unknown parameter %s", ErrInvalidReportArguments, name)
+		}
+	}
+
+	runes := []rune(report.Query)
+	var query strings.Builder
+	var args []interface{}
+	last := 0
+	for _, token := range reportTokens(report.Query) {
+		if token.kind != 'p' {
+			continue
+		}
+		value, ok := values[token.text]
+		if !ok {
+			return "", nil, fmt.Errorf("%w: parameter :%s is not declared", ErrInvalidReport, token.text)
+		}
+		query.WriteString(string(runes[last:token.start]))
+		query.WriteString("?")
+		args = append(args, value)
+		last = token.end
+	}
+	query.WriteString(string(runes[last:]))
+	return query.String(), args, nil
+}
+
+// reportColumns is the column list read by scanReport
+const reportColumns = "id, name, description, query_text, parameters, created_by, created_at, updated_at"
+
+// scanReport reads a row selected with reportColumns
+func scanReport(row rowScanner) (*types.SavedReport, error) {
+	var report types.SavedReport
+	var description, parameters, createdBy sql.NullString
+	err := row.Scan(&report.ID, &report.Name, &description, &report.Query, &parameters, &createdBy,
+		&report.CreatedAt, &report.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	report.Description = description.String
+	report.CreatedBy = createdBy.String
+	if err := types.FromJSON(parameters.String, &report.Parameters); err != nil {
+		return nil, fmt.Errorf("failed to parse parameters of report %s: %w", report.Name, err)
+	}
+	return &report, nil
+}
+
+// ListReports returns every saved report by name
+func (c *Client) ListReports(ctx context.Context) ([]*types.SavedReport, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	rows, err := c.db.QueryContext(ctx, "SELECT "+reportColumns+" FROM saved_reports ORDER BY name ASC")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reports: %w", err)
+	}
+	defer rows.Close()
+
+	reports := []*types.SavedReport{}
+	for rows.Next() {
+		report, err := scanReport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan report: %w", err)
+		}
+		reports = append(reports, report)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate reports: %w", err)
+	}
+	return reports, nil
+}
+
+// GetReport loads a saved report by name
+func (c *Client) GetReport(ctx context.Context, name string) (*types.SavedReport, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	report, err := scanReport(c.db.QueryRowContext(ctx, "SELECT "+reportColumns+" FROM saved_reports WHERE name = ?", name))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrReportNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get report: %w", err)
+	}
+	return report, nil
+}
+
+// SaveReport validates a report and creates it, or replaces the report of the same name. The
+// creator and creation time of a replaced report are kept.
+func (c *Client) SaveReport(ctx context.Context, report *types.SavedReport) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	if err := ValidateReport(report); err != nil {
+		return err
+	}
+	parameters, err := types.ToJSON(report.Parameters)
+	if err != nil {
+		return fmt.Errorf("failed to encode report parameters: %w", err)
+	}
+
+	existing, err := c.GetReport(ctx, report.Name)
+	if err != nil && !errors.Is(err, ErrReportNotFound) {
+		return err
+	}
+	report.UpdatedAt = time.Now().UTC()
+	if existing != nil {
+		report.ID, report.CreatedBy, report.CreatedAt = existing.ID, existing.CreatedBy, existing.CreatedAt
+		_, err = c.db.ExecContext(ctx, `
+			UPDATE saved_reports SET description = ?, query_text = ?, parameters = ?, updated_at = ?
+			WHERE id = ?`,
+			nullableString(report.Description), report.Query, parameters, report.UpdatedAt, report.ID)
+	} else {
+		report.ID, report.CreatedAt = uuid.New().String(), report.UpdatedAt
+		_, err = c.db.ExecContext(ctx, `
+			INSERT INTO saved_reports (id, name, description, query_text, parameters, created_by, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			report.ID, report.Name, nullableString(report.Description), report.Query, parameters,
+			nullableString(report.CreatedBy), report.CreatedAt, report.UpdatedAt)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save report: %w", err)
+	}
+	return nil
+}
+
+// DeleteReport deletes a saved report by name
+func (c *Client) DeleteReport(ctx context.Context, name string) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	result, err := c.db.ExecContext(ctx, "DELETE FROM saved_reports WHERE name = ?", name)
+	if err != nil {
+		return fmt.Errorf("failed to delete report: %w", err)
+	}
+	if deleted, _ := result.RowsAffected(); deleted == 0 {
+		return ErrReportNotFound
+	}
+	return nil
+}
+
+// RunReport runs a saved report for userID with the request's parameter values, in a read-only
+// transaction bounded by reportTimeout. Its query is validated again before it runs, so a report
+// saved before the allowlist narrowed cannot read what it no longer allows.
+func (c *Client) RunReport(ctx context.Context, userID, name string, arguments map[string]string) (*types.ReportResult, error) {
+	report, err := c.GetReport(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateReport(report); err != nil {
+		return nil, err
+	}
+	query, args, err := bindReport(report, userID, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reportTimeout)
+	defer cancel()
+	tx, err := c.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start read-only transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	startTime := time.Now()
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run report %s: %w", name, err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns: %w", err)
+	}
+	result := &types.ReportResult{Report: name, Columns: columns, Rows: [][]interface{}{}}
+	for rows.Next() {
+		if len(result.Rows) == maxReportRows {
+			result.Truncated = true
+			break
+		}
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		for i, value := range values {
+			if b, ok := value.([]byte); ok {
+				values[i] = string(b)
+			}
+		}
+		result.Rows = append(result.Rows, values)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to run report %s: %w", name, err)
+	}
+	result.RowCount = len(result.Rows)
+
+	log.Printf("📈 Report %s returned %d rows in %dms", name, result.RowCount, time.Since(startTime).Milliseconds())
+	return result, nil
+}
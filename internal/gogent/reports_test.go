@@ -0,0 +1,177 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"gogent/internal/testdb"
+	"gogent/internal/types"
+)
+
+func TestValidateReport(t *testing.T) {
+	valid := []string{
+		"SELECT status, COUNT(*) AS runs FROM execution_runs WHERE user_id = :user_id GROUP BY status",
+		"SELECT r.name, p.response_time_ms FROM execution_runs r JOIN api_requests q ON q.execution_run_id = r.id, api_responses p WHERE p.request_id = q.id",
+		"WITH recent AS (SELECT id FROM execution_runs WHERE created_at > :since) SELECT COUNT(*) FROM recent",
+		"SELECT EXTRACT(YEAR FROM created_at) AS year FROM `execution_runs` WHERE name = 'FROM users'",
+		"SELECT id FROM execution_runs WHERE id IN (SELECT execution_run_id FROM comparison_results)",
+		"SELECT name, COUNT(*) * 2 AS calls FROM function_definitions GROUP BY name",
+		"SELECT * FROM execution_runs",
+	}
+	for _, query := range valid {
+		report := &types.SavedReport{Name: "runs", Query: query, Parameters: []types.ReportParameter{{Name: "since", Type: types.ReportParameterDate}}}
+		if err := ValidateReport(report); err != nil {
+			t.Errorf("expected %q to be valid, got %v", query, err)
+		}
+	}
+
+	invalid := map[string]string{
+		"SELECT * FROM users":                                                         "table outside the allowlist",
+		"SELECT id FROM execution_runs, `api_keys`":                                   "quoted table in a FROM list",
+		"SELECT id FROM execution_runs JOIN sessions ON 1 = 1":                        "joined table outside the allowlist",
+		"SELECT * FROM information_schema.tables":                                     "schema-qualified table",
+		"SELECT (SELECT password_hash FROM users LIMIT 1) FROM execution_runs":        "subquery reading users",
+		"DELETE FROM execution_runs":                                                  "write statement",
+		"SELECT * FROM execution_runs; DROP TABLE execution_runs":                     "second statement",
+		"SELECT * FROM execution_runs WHERE id = ?":                                   "positional parameter",
+		"SELECT * FROM execution_runs WHERE id = :run_id":                             "undeclared parameter",
+		"SELECT LOAD_FILE('/etc/passwd') FROM execution_runs":                         "denied function",
+		"WITH x(id) AS (SELECT id FROM execution_runs) SELECT id FROM api_keys":       "table after a WITH clause",
+		"SELECT name, auth_config FROM function_definitions":                          "credential column",
+		"SELECT `request_headers` FROM api_requests":                                  "quoted credential column",
+		"SELECT * FROM function_definitions":                                          "* over a table with credentials",
+		"SELECT r.name, q.* FROM execution_runs r JOIN api_requests q ON 1 = 1":       "qualified * over a table with credentials",
+		"SELECT password_hash AS `\\` FROM users WHERE '\\`' <> '`\\''":               "table hidden by a backslash in a quoted name",
+		"SELECT u.password_hash FROM execution_runs r STRAIGHT_JOIN users u ON 1 = 1": "straight join to a table outside the allowlist",
+		"SELECT u.password_hash FROM execution_runs r NATURAL LEFT JOIN users u":      "natural join to a table outside the allowlist",
+		"SELECT id, name FROM execution_runs UNION TABLE api_keys":                    "TABLE statement in a union",
+		"SELECT * FROM (TABLE users) u":                                               "TABLE statement in a subquery",
+		"WITH u AS (TABLE users) SELECT * FROM u":                                     "TABLE statement in a WITH clause",
+		"SELECT * FROM execution_runs WHERE id IN (VALUES ROW('run-1'))":              "VALUES statement",
+	}
+	for query, reason := range invalid {
+		err := ValidateReport(&types.SavedReport{Name: "runs", Query: query})
+		if !errors.Is(err, ErrInvalidReport) {
+			t.Errorf("expected %q to be rejected for its %s, got %v", query, reason, err)
+		}
+	}
+
+	// Backslash does not escape inside backticks, so the quoted name ends before FROM users
+	tables, err := reportQueryTables(reportTokens("SELECT password_hash AS `\\` FROM users WHERE '\\`' <> '`\\''"))
+	if err != nil || !reflect.DeepEqual(tables, []string{"users"}) {
+		t.Errorf("expected the query to read users, got %v (%v)", tables, err)
+	}
+
+	tables, err = reportQueryTables(reportTokens("SELECT r.id FROM execution_runs r STRAIGHT_JOIN users u ON 1 = 1"))
+	if err != nil || !reflect.DeepEqual(tables, []string{"execution_runs", "users"}) {
+		t.Errorf("expected STRAIGHT_JOIN to read users, got %v (%v)", tables, err)
+	}
+
+	for _, report := range []*types.SavedReport{
+		{Name: "Runs By Status", Query: "SELECT 1"},
+		{Name: "runs", Query: "SELECT 1", Parameters: []types.ReportParameter{{Name: "user_id"}}},
+		{Name: "runs", Query: "SELECT 1", Parameters: []types.ReportParameter{{Name: "n", Type: "decimal"}}},
+		{Name: "runs", Query: "SELECT 1", Parameters: []types.ReportParameter{{Name: "n", Type: types.ReportParameterInt, Default: "many"}}},
+	} {
+		if err := ValidateReport(report); !errors.Is(err, ErrInvalidReport) {
+			t.Errorf("expected %+v to be rejected, got %v", report, err)
+		}
+	}
+}
+
+func TestBindReport(t *testing.T) {
+	report := &types.SavedReport{
+		Query: "SELECT * FROM execution_runs WHERE user_id = :user_id AND status = :status AND name <> ':status' AND tokens > :min_tokens",
+		Parameters: []types.ReportParameter{
+			{Name: "status", Required: true},
+			{Name: "min_tokens", Type: types.ReportParameterInt, Default: "10"},
+		},
+	}
+
+	query, args, err := bindReport(report, "user-1", map[string]string{"status": "failed"})
+	if err != nil {
+		t.Fatalf("failed to bind report: %v", err)
+	}
+	if query != "SELECT * FROM execution_runs WHERE user_id = ? AND status = ? AND name <> ':status' AND tokens > ?" {
+		t.Errorf("expected parameters outside literals to become placeholders, got %s", query)
+	}
+	if !reflect.DeepEqual(args, []interface{}{"user-1", "failed", int64(10)}) {
+		t.Errorf("expected the user, the argument and the default, got %v", args)
+	}
+
+	for _, arguments := range []map[string]string{
+		{},
+		{"status": "failed", "min_tokens": "ten"},
+		{"status": "failed", "user_id": "someone-else"},
+	} {
+		if _, _, err := bindReport(report, "user-1", arguments); !errors.Is(err, ErrInvalidReportArguments) {
+			t.Errorf("expected %v to be rejected, got %v", arguments, err)
+		}
+	}
+}
+
+func TestRunReport(t *testing.T) {
+	database := testdb.Open(t)
+
+	_, err := database.Exec(`
+		INSERT INTO execution_runs (id, user_id, status) VALUES ('run-1', 'user-1', 'completed'), ('run-2', 'user-1', 'failed'),
+			('run-3', 'user-1', 'failed'), ('run-4', 'user-2', 'failed');
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+	client := &Client{db: database}
+	ctx := context.Background()
+
+	report := &types.SavedReport{
+		Name:       "runs-by-status",
+		Query:      "SELECT status, COUNT(*) AS runs FROM execution_runs WHERE user_id = :user_id AND status LIKE :status GROUP BY status ORDER BY status;",
+		Parameters: []types.ReportParameter{{Name: "status", Default: "%"}},
+		CreatedBy:  "admin-1",
+	}
+	if err := client.SaveReport(ctx, report); err != nil {
+		t.Fatalf("failed to save report: %v", err)
+	}
+
+	result, err := client.RunReport(ctx, "user-1", "runs-by-status", nil)
+	if err != nil {
+		t.Fatalf("failed to run report: %v", err)
+	}
+	expected := [][]interface{}{{"completed", int64(1)}, {"failed", int64(2)}}
+	if !reflect.DeepEqual(result.Columns, []string{"status", "runs"}) || !reflect.DeepEqual(result.Rows, expected) || result.RowCount != 2 {
+		t.Errorf("expected the user's runs by status, got %+v", result)
+	}
+
+	result, err = client.RunReport(ctx, "user-2", "runs-by-status", map[string]string{"status": "fail%"})
+	if err != nil {
+		t.Fatalf("failed to run report: %v", err)
+	}
+	if !reflect.DeepEqual(result.Rows, [][]interface{}{{"failed", int64(1)}}) {
+		t.Errorf("expected only the second user's failed runs, got %v", result.Rows)
+	}
+
+	// Saving a report of the same name replaces its query and keeps its creator
+	report = &types.SavedReport{Name: "runs-by-status", Query: "SELECT COUNT(*) AS runs FROM execution_runs WHERE user_id = :user_id"}
+	if err := client.SaveReport(ctx, report); err != nil {
+		t.Fatalf("failed to replace report: %v", err)
+	}
+	reports, err := client.ListReports(ctx)
+	if err != nil {
+		t.Fatalf("failed to list reports: %v", err)
+	}
+	if len(reports) != 1 || reports[0].CreatedBy != "admin-1" || len(reports[0].Parameters) != 0 {
+		t.Errorf("expected the replaced report, got %+v", reports)
+	}
+
+	if _, err := client.RunReport(ctx, "user-1", "runs-by-status", map[string]string{"status": "failed"}); !errors.Is(err, ErrInvalidReportArguments) {
+		t.Errorf("expected a parameter the report no longer takes to be rejected, got %v", err)
+	}
+	if err := client.DeleteReport(ctx, "runs-by-status"); err != nil {
+		t.Fatalf("failed to delete report: %v", err)
+	}
+	if _, err := client.RunReport(ctx, "user-1", "runs-by-status", nil); !errors.Is(err, ErrReportNotFound) {
+		t.Errorf("expected a deleted report to be missing, got %v", err)
+	}
+}
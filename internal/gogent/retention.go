@@ -0,0 +1,346 @@
+package gogent
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"gogent/internal/types"
+)
+
+// defaultPruneBatchSize is how many rows one delete statement removes when PruneOptions sets none
+const defaultPruneBatchSize = 500
+
+// PruneOptions controls how a prune pass deletes rows
+type PruneOptions struct {
+	BatchSize int           // Rows deleted per statement; 0 uses 500
+	Pause     time.Duration // Wait between batches so other queries get a turn at the tables
+	Archive   io.Writer     // When set, each pruned row is written here as a JSON line before it is deleted
+	Sink      ArchiveSink   // When set, pruned rows and the rows deleted with them are archived here by run
+}
+
+// retentionTable is a table whose rows expire. Tables are pruned in order, children before the runs
+// that own them, so archived rows are written before cascading deletes remove them.
+type retentionTable struct {
+	name string
+	days func(types.RetentionPolicy) int
+	// selectExpired selects the IDs of rows created before a cutoff; userColumn names the owning user
+	selectExpired string
+	userColumn    string
+}
+
+var retentionTables = []retentionTable{
+	{
+		name:          "function_calls",
+		days:          func(p types.RetentionPolicy) int { return p.FunctionCallsDays },
+		selectExpired: "SELECT fc.id FROM function_calls fc JOIN api_requests r ON r.id = fc.request_id WHERE fc.created_at < ?",
+		userColumn:    "r.user_id",
+	},
+	{
+		name:          "execution_logs",
+		days:          func(p types.RetentionPolicy) int { return p.LogsDays },
+		selectExpired: "SELECT l.id FROM execution_logs l JOIN execution_runs r ON r.id = l.execution_run_id WHERE l.timestamp < ?",
+		userColumn:    "r.user_id",
+	},
+	{
+		name:          "api_responses",
+		days:          func(p types.RetentionPolicy) int { return p.ResponsesDays },
+		selectExpired: "SELECT id FROM api_responses WHERE created_at < ?",
+		userColumn:    "user_id",
+	},
+	{
+		name:          "api_requests",
+		days:          func(p types.RetentionPolicy) int { return p.RequestsDays },
+		selectExpired: "SELECT id FROM api_requests WHERE created_at < ?",
+		userColumn:    "user_id",
+	},
+	{
+		name:          "execution_runs",
+		days:          func(p types.RetentionPolicy) int { return p.RunsDays },
+		selectExpired: "SELECT id FROM execution_runs WHERE created_at < ?",
+		userColumn:    "user_id",
+	},
+}
+
+// ValidateRetentionPolicy checks that no retention period is negative
+func ValidateRetentionPolicy(policy types.RetentionPolicy) error {
+	if policy.RunsDays < 0 || policy.RequestsDays < 0 || policy.ResponsesDays < 0 ||
+		policy.LogsDays < 0 || policy.FunctionCallsDays < 0 {
+		return fmt.Errorf("retention days must not be negative")
+	}
+	return nil
+}
+
+// retentionCutoffs returns, by table, the time before which rows expire under the policy. Rows of a
+// run are never kept longer than the run, so a table's period is capped by the runs period.
+func retentionCutoffs(policy types.RetentionPolicy, now time.Time) map[string]time.Time {
+	cutoffs := make(map[string]time.Time, len(retentionTables))
+	for _, table := range retentionTables {
+		days := table.days(policy)
+		if policy.RunsDays > 0 && (days == 0 || days > policy.RunsDays) {
+			days = policy.RunsDays
+		}
+		if days > 0 {
+			cutoffs[table.name] = now.AddDate(0, 0, -days)
+		}
+	}
+	return cutoffs
+}
+
+// pruneScope limits a prune pass to one user's rows, or to every user but some
+type pruneScope struct {
+	userID       string
+	excludeUsers []string
+}
+
+// filter returns the SQL condition and arguments restricting rows to the scope
+func (s pruneScope) filter(userColumn string) (string, []interface{}) {
+	if s.userID != "" {
+		return " AND " + userColumn + " = ?", []interface{}{s.userID}
+	}
+	if len(s.excludeUsers) == 0 {
+		return "", nil
+	}
+	args := make([]interface{}, len(s.excludeUsers))
+	for i, userID := range s.excludeUsers {
+		args[i] = userID
+	}
+	return " AND " + userColumn + " NOT IN (" + placeholders(len(args)) + ")", args
+}
+
+func placeholders(count int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", count), ", ")
+}
+
+// PruneOlderThan deletes the execution history created before cutoff from every table. An empty
+// userID prunes every user's history.
+func (c *Client) PruneOlderThan(ctx context.Context, cutoff time.Time, userID string, opts PruneOptions) (*types.PruneReport, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	cutoffs := make(map[string]time.Time, len(retentionTables))
+	for _, table := range retentionTables {
+		cutoffs[table.name] = cutoff
+	}
+
+	report := &types.PruneReport{Deleted: make(map[string]int64)}
+	if err := c.prune(ctx, cutoffs, pruneScope{userID: userID}, opts, report); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// PruneExpiredHistory applies the retention policies: each user with a policy of their own is pruned
+// by it, and everyone else by the workspace policy
+func (c *Client) PruneExpiredHistory(ctx context.Context, opts PruneOptions) (*types.PruneReport, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	settings, err := c.GetWorkspaceSettings(ctx, types.DefaultWorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	userPolicies, err := c.ListUserRetentionPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	report := &types.PruneReport{Deleted: make(map[string]int64)}
+	excluded := make([]string, 0, len(userPolicies))
+	for _, userPolicy := range userPolicies {
+		excluded = append(excluded, userPolicy.UserID)
+		scope := pruneScope{userID: userPolicy.UserID}
+		if err := c.prune(ctx, retentionCutoffs(userPolicy.Policy, now), scope, opts, report); err != nil {
+			return report, err
+		}
+	}
+
+	scope := pruneScope{excludeUsers: excluded}
+	if err := c.prune(ctx, retentionCutoffs(settings.Retention, now), scope, opts, report); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// prune deletes expired rows table by table in batches, adding the counts to the report
+func (c *Client) prune(ctx context.Context, cutoffs map[string]time.Time, scope pruneScope, opts PruneOptions, report *types.PruneReport) error {
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultPruneBatchSize
+	}
+
+	for _, table := range retentionTables {
+		cutoff, ok := cutoffs[table.name]
+		if !ok {
+			continue
+		}
+		filter, filterArgs := scope.filter(table.userColumn)
+		query := table.selectExpired + filter + " LIMIT ?"
+		args := append(append([]interface{}{cutoff}, filterArgs...), batchSize)
+
+		for {
+			ids, err := c.expiredIDs(ctx, query, args)
+			if err != nil {
+				return fmt.Errorf("failed to select expired %s: %w", table.name, err)
+			}
+			if len(ids) == 0 {
+				break
+			}
+
+			if opts.Archive != nil {
+				archived, err := c.archiveRows(ctx, opts.Archive, table.name, ids)
+				if err != nil {
+					return err
+				}
+				report.Archived += archived
+			}
+			if opts.Sink != nil {
+				archived, err := c.archiveRuns(ctx, opts.Sink, table.name, ids)
+				if err != nil {
+					return err
+				}
+				report.Archived += archived
+			}
+
+			// Each batch is its own short statement, so locks are held only briefly
+			result, err := c.db.ExecContext(ctx,
+				"DELETE FROM "+table.name+" WHERE id IN ("+placeholders(len(ids))+")", ids...)
+			if err != nil {
+				return fmt.Errorf("failed to prune %s: %w", table.name, err)
+			}
+			deleted, _ := result.RowsAffected()
+			report.Deleted[table.name] += deleted
+
+			if len(ids) < batchSize {
+				break
+			}
+			if opts.Pause > 0 {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(opts.Pause):
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Client) expiredIDs(ctx context.Context, query string, args []interface{}) ([]interface{}, error) {
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []interface{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// archiveRows writes the rows about to be pruned as JSON lines of {"table": ..., "row": {...}}
+func (c *Client) archiveRows(ctx context.Context, archive io.Writer, table string, ids []interface{}) (int64, error) {
+	rows, err := c.loadArchiveRows(ctx, table, "SELECT * FROM "+table+" WHERE id IN ("+placeholders(len(ids))+")", ids)
+	if err != nil {
+		return 0, err
+	}
+
+	encoder := json.NewEncoder(archive)
+	for i, row := range rows {
+		if err := encoder.Encode(archiveLine{Table: table, Row: row}); err != nil {
+			return int64(i), fmt.Errorf("failed to write archive: %w", err)
+		}
+	}
+	return int64(len(rows)), nil
+}
+
+// ListUserRetentionPolicies lists the users whose retention policy replaces the workspace's
+func (c *Client) ListUserRetentionPolicies(ctx context.Context) ([]types.UserRetentionPolicy, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT user_id, runs_days, requests_days, responses_days, logs_days, function_calls_days, updated_by, updated_at
+		FROM user_retention_policies
+		ORDER BY user_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list retention policies: %w", err)
+	}
+	defer rows.Close()
+
+	var policies []types.UserRetentionPolicy
+	for rows.Next() {
+		var policy types.UserRetentionPolicy
+		var updatedBy sql.NullString
+		var updatedAt sql.NullTime
+		if err := rows.Scan(&policy.UserID, &policy.Policy.RunsDays, &policy.Policy.RequestsDays,
+			&policy.Policy.ResponsesDays, &policy.Policy.LogsDays, &policy.Policy.FunctionCallsDays,
+			&updatedBy, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan retention policy: %w", err)
+		}
+		policy.UpdatedBy = updatedBy.String
+		policy.UpdatedAt = updatedAt.Time
+		policies = append(policies, policy)
+	}
+	return policies, rows.Err()
+}
+
+// SaveUserRetentionPolicy creates or replaces a user's retention policy
+func (c *Client) SaveUserRetentionPolicy(ctx context.Context, policy *types.UserRetentionPolicy) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	if policy.UserID == "" {
+		return fmt.Errorf("user ID is required")
+	}
+	if err := ValidateRetentionPolicy(policy.Policy); err != nil {
+		return err
+	}
+
+	policy.UpdatedAt = time.Now()
+	_, err := c.db.ExecContext(ctx, `
+		INSERT INTO user_retention_policies
+			(user_id, runs_days, requests_days, responses_days, logs_days, function_calls_days, updated_by, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			runs_days = VALUES(runs_days),
+			requests_days = VALUES(requests_days),
+			responses_days = VALUES(responses_days),
+			logs_days = VALUES(logs_days),
+			function_calls_days = VALUES(function_calls_days),
+			updated_by = VALUES(updated_by),
+			updated_at = VALUES(updated_at)
+	`, policy.UserID, policy.Policy.RunsDays, policy.Policy.RequestsDays, policy.Policy.ResponsesDays,
+		policy.Policy.LogsDays, policy.Policy.FunctionCallsDays, nullableString(policy.UpdatedBy), policy.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save retention policy: %w", err)
+	}
+	return nil
+}
+
+// DeleteUserRetentionPolicy returns a user to the workspace retention policy
+func (c *Client) DeleteUserRetentionPolicy(ctx context.Context, userID string) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	result, err := c.db.ExecContext(ctx, "DELETE FROM user_retention_policies WHERE user_id = ?", userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete retention policy: %w", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("retention policy for %s: %w", userID, sql.ErrNoRows)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package gogent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"gogent/internal/testdb"
+	"gogent/internal/types"
+)
+
+// newRetentionTestClient returns a client backed by the in-memory test schema
+func newRetentionTestClient(t *testing.T) *Client {
+	return &Client{db: testdb.Open(t)}
+}
+
+// insertTestHistory records a run with one request, response, log and function call, all created at the same time
+func insertTestHistory(t *testing.T, client *Client, id, userID string, createdAt time.Time) {
+	for _, statement := range []struct {
+		query string
+		args  []interface{}
+	}{
+		{"INSERT INTO execution_runs (id, user_id, created_at) VALUES (?, ?, ?)", []interface{}{id, userID, createdAt}},
+		{"INSERT INTO api_requests (id, user_id, execution_run_id, created_at) VALUES (?, ?, ?, ?)", []interface{}{id + "-request", userID, id, createdAt}},
+		{"INSERT INTO api_responses (id, user_id, request_id, created_at) VALUES (?, ?, ?, ?)", []interface{}{id + "-response", userID, id + "-request", createdAt}},
+		{"INSERT INTO execution_logs (id, execution_run_id, message, timestamp) VALUES (?, ?, 'log', ?)", []interface{}{id + "-log", id, createdAt}},
+		{"INSERT INTO function_calls (id, request_id, created_at) VALUES (?, ?, ?)", []interface{}{id + "-call", id + "-request", createdAt}},
+	} {
+		if _, err := client.db.Exec(statement.query, statement.args...); err != nil {
+			t.Fatalf("failed to insert history: %v", err)
+		}
+	}
+}
+
+func countRows(t *testing.T, client *Client, table string) int {
+	var count int
+	if err := client.db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&count); err != nil {
+		t.Fatalf("failed to count %s: %v", table, err)
+	}
+	return count
+}
+
+func TestPruneOlderThan(t *testing.T) {
+	client := newRetentionTestClient(t)
+	now := time.Now()
+	for i, id := range []string{"old-1", "old-2", "old-3"} {
+		insertTestHistory(t, client, id, "user-1", now.Add(-time.Duration(100+i)*24*time.Hour))
+	}
+	insertTestHistory(t, client, "old-other", "user-2", now.Add(-200*24*time.Hour))
+	insertTestHistory(t, client, "recent", "user-1", now)
+
+	var archive bytes.Buffer
+	report, err := client.PruneOlderThan(context.Background(), now.AddDate(0, 0, -90), "user-1",
+		PruneOptions{BatchSize: 2, Archive: &archive})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, table := range []string{"execution_runs", "api_requests", "api_responses", "execution_logs", "function_calls"} {
+		if report.Deleted[table] != 3 {
+			t.Errorf("expected 3 rows pruned from %s, got %d", table, report.Deleted[table])
+		}
+		if count := countRows(t, client, table); count != 2 {
+			t.Errorf("expected user-2's and the recent rows to be kept in %s, got %d", table, count)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(archive.String()), "\n")
+	if report.Archived != 15 || len(lines) != 15 {
+		t.Fatalf("expected 15 archived rows, got %d (%d lines)", report.Archived, len(lines))
+	}
+	var first struct {
+		Table string                 `json:"table"`
+		Row   map[string]interface{} `json:"row"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil || first.Table != "function_calls" || first.Row["id"] == nil {
+		t.Errorf("expected function calls to be archived first with their columns, got %s (%v)", lines[0], err)
+	}
+}
+
+func TestPruneExpiredHistory(t *testing.T) {
+	client := newRetentionTestClient(t)
+	now := time.Now()
+	if _, err := client.db.Exec("INSERT INTO workspace_settings (id, retention_days, log_retention_days) VALUES (?, 0, 30)",
+		types.DefaultWorkspaceID); err != nil {
+		t.Fatalf("failed to insert workspace settings: %v", err)
+	}
+	if _, err := client.db.Exec(`INSERT INTO user_retention_policies
+		(user_id, runs_days, requests_days, responses_days, logs_days, function_calls_days) VALUES ('user-2', 60, 0, 0, 0, 0)`); err != nil {
+		t.Fatalf("failed to insert user policy: %v", err)
+	}
+
+	insertTestHistory(t, client, "workspace-old", "user-1", now.AddDate(0, 0, -45))
+	insertTestHistory(t, client, "user-old", "user-2", now.AddDate(0, 0, -75))
+	insertTestHistory(t, client, "user-recent", "user-2", now.AddDate(0, 0, -45))
+
+	report, err := client.PruneExpiredHistory(context.Background(), PruneOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The workspace policy only expires user-1's log; user-2's policy expires their old run and everything in it
+	if report.Deleted["execution_logs"] != 2 || report.Deleted["execution_runs"] != 1 || report.Deleted["api_requests"] != 1 {
+		t.Errorf("unexpected report: %+v", report.Deleted)
+	}
+	if countRows(t, client, "execution_runs") != 2 || countRows(t, client, "execution_logs") != 1 {
+		t.Errorf("expected user-1's run and user-2's recent run with its log to be kept")
+	}
+}
+
+func TestRetentionCutoffs(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	cutoffs := retentionCutoffs(types.RetentionPolicy{RunsDays: 90, LogsDays: 7, RequestsDays: 365}, now)
+
+	if !cutoffs["execution_logs"].Equal(now.AddDate(0, 0, -7)) {
+		t.Errorf("expected logs to expire after 7 days, got %v", cutoffs["execution_logs"])
+	}
+	if !cutoffs["api_requests"].Equal(now.AddDate(0, 0, -90)) || !cutoffs["function_calls"].Equal(now.AddDate(0, 0, -90)) {
+		t.Errorf("expected rows of a run to be kept no longer than the run, got %v", cutoffs)
+	}
+	if cutoffs := retentionCutoffs(types.RetentionPolicy{LogsDays: 7}, now); len(cutoffs) != 1 {
+		t.Errorf("expected only logs to expire, got %v", cutoffs)
+	}
+	if err := ValidateRetentionPolicy(types.RetentionPolicy{ResponsesDays: -1}); err == nil {
+		t.Error("expected a negative period to be rejected")
+	}
+}
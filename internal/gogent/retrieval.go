@@ -0,0 +1,377 @@
+package gogent
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gogent/internal/types"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultRetrievalTopK is how many chunks a configuration retrieves when TopK is unset
+	defaultRetrievalTopK = 3
+	// maxRetrievalTopK caps how many chunks a configuration may add to its context
+	maxRetrievalTopK = 20
+)
+
+// DocumentRetriever finds the document chunks most similar to a prompt; implement it to plug in a
+// vector store
+type DocumentRetriever interface {
+	// Name identifies the retriever in logs and errors
+	Name() string
+
+	// Retrieve returns up to query.TopK chunks of the user's collection, best match first, with
+	// their cosine similarity to the query in Score
+	Retrieve(ctx context.Context, userID string, query types.RetrievalQuery) ([]types.RetrievedChunk, error)
+}
+
+// NewSQLDocumentRetriever returns a retriever that ranks the chunks stored by IndexDocument
+func NewSQLDocumentRetriever(db *sql.DB) DocumentRetriever {
+	return &SQLDocumentRetriever{db: db}
+}
+
+// SetDocumentRetriever replaces the retriever configurations with retrieval settings search; nil
+// restores the document_chunks table
+func (c *Client) SetDocumentRetriever(retriever DocumentRetriever) {
+	c.overridesMutex.Lock()
+	defer c.overridesMutex.Unlock()
+	c.documentRetriever = retriever
+}
+
+// retriever returns the configured document retriever, falling back to the client's database
+func (c *Client) retriever() (DocumentRetriever, error) {
+	c.overridesMutex.RLock()
+	retriever := c.documentRetriever
+	c.overridesMutex.RUnlock()
+	if retriever != nil {
+		return retriever, nil
+	}
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	return NewSQLDocumentRetriever(c.db), nil
+}
+
+// retrievalTopK returns how many chunks a retrieval config asks for, applying the default and cap
+func retrievalTopK(retrieval *types.RetrievalConfig) int {
+	if retrieval.TopK <= 0 {
+		return defaultRetrievalTopK
+	}
+	return min(int(retrieval.TopK), maxRetrievalTopK)
+}
+
+// validateRetrieval checks a configuration's retrieval settings
+func validateRetrieval(validation *ValidationError, field string, retrieval *types.RetrievalConfig) {
+	if retrieval == nil {
+		return
+	}
+	if strings.TrimSpace(retrieval.Collection) == "" {
+		validation.add(field+".retrieval.collection", "a document collection is required")
+	}
+	if retrieval.TopK < 0 || retrieval.TopK > maxRetrievalTopK {
+		validation.add(field+".retrieval.topK", "must be between 0 and %d, got %d", maxRetrievalTopK, retrieval.TopK)
+	}
+	if retrieval.MinScore < 0 || retrieval.MinScore > 1 {
+		validation.add(field+".retrieval.minScore", "must be between 0 and 1, got %v", retrieval.MinScore)
+	}
+}
+
+// storedRetrieval adds the retrieval settings to the generation config saved with a configuration,
+// which has no column of its own for them
+func storedRetrieval(config *types.APIConfiguration, generationConfig map[string]interface{}) map[string]interface{} {
+	if config.Retrieval == nil {
+		return generationConfig
+	}
+	stored := make(map[string]interface{}, len(generationConfig)+1)
+	for key, value := range generationConfig {
+		stored[key] = value
+	}
+	stored["retrieval"] = config.Retrieval
+	return stored
+}
+
+// loadRetrieval restores the retrieval settings from a saved generation config, removing them from
+// the generation config sent to providers
+func loadRetrieval(config *types.APIConfiguration, generationConfig json.RawMessage) {
+	var stored struct {
+		Retrieval *types.RetrievalConfig `json:"retrieval"`
+	}
+	if len(generationConfig) == 0 || json.Unmarshal(generationConfig, &stored) != nil || stored.Retrieval == nil {
+		return
+	}
+	config.Retrieval = stored.Retrieval
+	delete(config.GenerationConfig, "retrieval")
+}
+
+// IndexDocument embeds each chunk with model and stores them as a document of the user's
+// collection, so configurations retrieving from the collection can find them. An empty model uses
+// text-embedding-004.
+func (c *Client) IndexDocument(ctx context.Context, userID, collection, name, model string, chunks []string) (*types.Document, error) {
+	return c.indexDocument(ctx, userID, &types.Document{
+		Collection:  collection,
+		Name:        name,
+		ContentType: "text/plain",
+	}, model, chunks)
+}
+
+// indexDocument embeds the chunks and stores them with the document, filling in its ID, chunk
+// count, embedding model and creation time
+func (c *Client) indexDocument(ctx context.Context, userID string, document *types.Document, model string, chunks []string) (*types.Document, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	if strings.TrimSpace(document.Collection) == "" {
+		return nil, fmt.Errorf("document collection must not be empty")
+	}
+	if len(chunks) == 0 {
+		return nil, fmt.Errorf("document %q has no text to index", document.Name)
+	}
+	if model == "" {
+		model = defaultEmbeddingModel
+	}
+
+	// Embed everything first so a failed embedding leaves no partial document behind
+	embeddings := make([][]float32, len(chunks))
+	for i, chunk := range chunks {
+		embedding, _, err := c.cachedEmbedding(ctx, userID, model, chunk, c.callEmbeddingAPI)
+		if err != nil {
+			return nil, fmt.Errorf("failed to embed chunk %d of %s: %w", i+1, document.Name, err)
+		}
+		embeddings[i] = embedding
+	}
+
+	document.ID = uuid.New().String()
+	document.ChunkCount = len(chunks)
+	document.EmbeddingModel = model
+	document.CreatedAt = time.Now()
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO documents (id, user_id, collection, name, content_type, size_bytes, chunk_count,
+			chunk_size, chunk_overlap, embedding_model, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, document.ID, userID, document.Collection, document.Name, document.ContentType, document.SizeBytes,
+		document.ChunkCount, document.ChunkSize, document.ChunkOverlap, model, document.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store document: %w", err)
+	}
+	for i, chunk := range chunks {
+		encoded, err := types.ToJSON(embeddings[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal chunk embedding: %w", err)
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO document_chunks (id, document_id, user_id, collection, chunk_index, content,
+				embedding_model, dimensions, embedding, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, uuid.New().String(), document.ID, userID, document.Collection, i, chunk, model,
+			len(embeddings[i]), encoded, document.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to store document chunk: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit document: %w", err)
+	}
+	return document, nil
+}
+
+// retrieveChunks embeds the prompt and retrieves the chunks a configuration's retrieval settings
+// select, ranked from 1. Configurations without retrieval settings retrieve nothing.
+func (c *Client) retrieveChunks(ctx context.Context, userID string, config *types.APIConfiguration, prompt string) ([]types.RetrievedChunk, error) {
+	retrieval := config.Retrieval
+	if retrieval == nil {
+		return nil, nil
+	}
+	retriever, err := c.retriever()
+	if err != nil {
+		return nil, err
+	}
+
+	model := retrieval.EmbeddingModel
+	if model == "" {
+		model = defaultEmbeddingModel
+	}
+	embedding, _, err := c.cachedEmbedding(ctx, userID, model, prompt, c.callEmbeddingAPI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed prompt: %w", err)
+	}
+
+	topK := retrievalTopK(retrieval)
+	chunks, err := retriever.Retrieve(ctx, userID, types.RetrievalQuery{
+		Collection:     retrieval.Collection,
+		Text:           prompt,
+		Embedding:      embedding,
+		EmbeddingModel: model,
+		TopK:           topK,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s retriever failed: %w", retriever.Name(), err)
+	}
+
+	kept := make([]types.RetrievedChunk, 0, min(len(chunks), topK))
+	for _, chunk := range chunks {
+		if len(kept) == topK {
+			break
+		}
+		if chunk.Score < retrieval.MinScore {
+			continue
+		}
+		chunk.Rank = len(kept) + 1
+		if chunk.Collection == "" {
+			chunk.Collection = retrieval.Collection
+		}
+		kept = append(kept, chunk)
+	}
+	return kept, nil
+}
+
+// retrievalContext returns the prompt context with the retrieved chunks placed before it, each
+// numbered and labelled with its document
+func retrievalContext(chunks []types.RetrievedChunk, promptContext string) string {
+	if len(chunks) == 0 {
+		return promptContext
+	}
+	var b strings.Builder
+	b.WriteString("Retrieved documents:")
+	for _, chunk := range chunks {
+		fmt.Fprintf(&b, "\n\n[%d] %s (part %d)\n%s", chunk.Rank, chunk.DocumentName, chunk.ChunkIndex+1, chunk.Content)
+	}
+	if promptContext != "" {
+		b.WriteString("\n\n")
+		b.WriteString(promptContext)
+	}
+	return b.String()
+}
+
+// storeRetrievedChunks records the chunks added to a request's context, scrubbed by the redaction
+// policy like the context itself
+func (c *Client) storeRetrievedChunks(ctx context.Context, userID string, request *types.APIRequest, chunks []types.RetrievedChunk) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	if len(chunks) == 0 {
+		return nil
+	}
+
+	redactor := c.payloadRedactor()
+	placeholders := make([]string, len(chunks))
+	args := make([]interface{}, 0, len(chunks)*13)
+	for i, chunk := range chunks {
+		placeholders[i] = "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+		args = append(args, request.ID, chunk.Rank, userID, request.ExecutionRunID, request.ConfigurationID,
+			chunk.ChunkID, chunk.DocumentID, chunk.DocumentName, chunk.Collection, chunk.ChunkIndex,
+			redactor.scrubString(chunk.Content, &types.RedactionRecord{}), chunk.Score, request.CreatedAt)
+	}
+
+	_, err := c.db.ExecContext(ctx, `
+		INSERT INTO retrieved_chunks (request_id, chunk_rank, user_id, execution_run_id, configuration_id,
+			chunk_id, document_id, document_name, collection, chunk_index, content, score, created_at)
+		VALUES `+strings.Join(placeholders, ", "), args...)
+	if err != nil {
+		return fmt.Errorf("failed to store retrieved chunks: %w", err)
+	}
+	return nil
+}
+
+// GetRetrievedChunks returns the chunks retrieved for each request of a run owned by the user,
+// keyed by request ID and in rank order
+func (c *Client) GetRetrievedChunks(ctx context.Context, userID, executionRunID string) (map[string][]types.RetrievedChunk, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT request_id, chunk_rank, chunk_id, document_id, document_name, collection, chunk_index, content, score
+		FROM retrieved_chunks
+		WHERE execution_run_id = ? AND user_id = ?
+		ORDER BY request_id ASC, chunk_rank ASC
+	`, executionRunID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get retrieved chunks: %w", err)
+	}
+	defer rows.Close()
+
+	chunks := make(map[string][]types.RetrievedChunk)
+	for rows.Next() {
+		var requestID string
+		var chunk types.RetrievedChunk
+		if err := rows.Scan(&requestID, &chunk.Rank, &chunk.ChunkID, &chunk.DocumentID, &chunk.DocumentName,
+			&chunk.Collection, &chunk.ChunkIndex, &chunk.Content, &chunk.Score); err != nil {
+			return nil, fmt.Errorf("failed to scan retrieved chunk: %w", err)
+		}
+		chunks[requestID] = append(chunks[requestID], chunk)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate retrieved chunks: %w", err)
+	}
+	return chunks, nil
+}
+
+// SQLDocumentRetriever ranks the chunks in the document_chunks table by cosine similarity
+type SQLDocumentRetriever struct {
+	db *sql.DB
+}
+
+// Name identifies the retriever in logs and errors
+func (r *SQLDocumentRetriever) Name() string {
+	return "sql"
+}
+
+// Retrieve scores every chunk of the collection embedded with the query's model
+func (r *SQLDocumentRetriever) Retrieve(ctx context.Context, userID string, query types.RetrievalQuery) ([]types.RetrievedChunk, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT dc.id, dc.document_id, d.name, dc.chunk_index, dc.content, dc.embedding
+		FROM document_chunks dc
+		JOIN documents d ON d.id = dc.document_id
+		WHERE dc.user_id = ? AND dc.collection = ? AND dc.embedding_model = ?
+	`, userID, query.Collection, query.EmbeddingModel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document chunks: %w", err)
+	}
+	defer rows.Close()
+
+	var chunks []types.RetrievedChunk
+	for rows.Next() {
+		chunk := types.RetrievedChunk{Collection: query.Collection}
+		var embeddingJSON string
+		if err := rows.Scan(&chunk.ChunkID, &chunk.DocumentID, &chunk.DocumentName, &chunk.ChunkIndex,
+			&chunk.Content, &embeddingJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan document chunk: %w", err)
+		}
+		var embedding []float32
+		if err := types.FromJSON(embeddingJSON, &embedding); err != nil {
+			return nil, fmt.Errorf("failed to parse chunk embedding: %w", err)
+		}
+		chunk.Score = CosineSimilarity(query.Embedding, embedding)
+		chunks = append(chunks, chunk)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate document chunks: %w", err)
+	}
+
+	// Ties keep document order so equal scores retrieve the same chunks every run
+	sort.SliceStable(chunks, func(i, j int) bool {
+		if chunks[i].Score != chunks[j].Score {
+			return chunks[i].Score > chunks[j].Score
+		}
+		if chunks[i].DocumentName != chunks[j].DocumentName {
+			return chunks[i].DocumentName < chunks[j].DocumentName
+		}
+		return chunks[i].ChunkIndex < chunks[j].ChunkIndex
+	})
+	if len(chunks) > query.TopK {
+		chunks = chunks[:query.TopK]
+	}
+	return chunks, nil
+}
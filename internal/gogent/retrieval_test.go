@@ -0,0 +1,172 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"gogent/internal/types"
+)
+
+// newRetrievalTestClient returns an embedding test client with in-memory document tables
+func newRetrievalTestClient(t *testing.T) *Client {
+	client := newEmbeddingTestClient(t)
+	client.SetEmbeddingProvider(MockEmbeddingProvider{})
+
+	return client
+}
+
+func TestRetrieveChunks(t *testing.T) {
+	client := newRetrievalTestClient(t)
+	ctx := context.Background()
+
+	_, err := client.IndexDocument(ctx, "user-1", "geography", "europe.txt", "", []string{
+		"Paris is the capital of France.",
+		"Bananas are rich in potassium.",
+		"Berlin is the capital of Germany.",
+	})
+	if err != nil {
+		t.Fatalf("failed to index document: %v", err)
+	}
+	// Another user's collection of the same name must not be searched
+	if _, err := client.IndexDocument(ctx, "user-2", "geography", "secret.txt", "", []string{"The capital of France is Paris"}); err != nil {
+		t.Fatalf("failed to index document: %v", err)
+	}
+
+	config := &types.APIConfiguration{Retrieval: &types.RetrievalConfig{Collection: "geography", TopK: 2}}
+	chunks, err := client.retrieveChunks(ctx, "user-1", config, "What is the capital of France?")
+	if err != nil {
+		t.Fatalf("failed to retrieve chunks: %v", err)
+	}
+	if len(chunks) != 2 {
+		t.Fatalf("expected 2 chunks, got %d", len(chunks))
+	}
+	if chunks[0].Content != "Paris is the capital of France." || chunks[0].Rank != 1 || chunks[1].Rank != 2 {
+		t.Errorf("expected the Paris chunk ranked first, got %+v", chunks)
+	}
+	for _, chunk := range chunks {
+		if chunk.DocumentName != "europe.txt" || chunk.Collection != "geography" {
+			t.Errorf("expected only user-1's chunks, got %+v", chunk)
+		}
+	}
+
+	// A minimum score drops the weaker matches
+	config.Retrieval.MinScore = chunks[0].Score
+	if chunks, err := client.retrieveChunks(ctx, "user-1", config, "What is the capital of France?"); err != nil || len(chunks) != 1 {
+		t.Errorf("expected the minimum score to keep 1 chunk, got %d, %v", len(chunks), err)
+	}
+
+	if chunks, err := client.retrieveChunks(ctx, "user-1", &types.APIConfiguration{}, "prompt"); err != nil || chunks != nil {
+		t.Errorf("expected no retrieval without settings, got %v, %v", chunks, err)
+	}
+}
+
+func TestRetrieveChunksCustomRetriever(t *testing.T) {
+	client := &Client{}
+	client.SetEmbeddingProvider(MockEmbeddingProvider{})
+	config := &types.APIConfiguration{Retrieval: &types.RetrievalConfig{Collection: "docs"}}
+
+	if _, err := client.retrieveChunks(context.Background(), "user-1", config, "prompt"); !errors.Is(err, ErrNoDatabase) {
+		t.Errorf("expected ErrNoDatabase without a retriever or database, got %v", err)
+	}
+
+	client.SetDocumentRetriever(&staticRetriever{chunks: []types.RetrievedChunk{
+		{ChunkID: "a", Content: "first", Score: 0.9},
+		{ChunkID: "b", Content: "second", Score: 0.8},
+		{ChunkID: "c", Content: "third", Score: 0.7},
+		{ChunkID: "d", Content: "fourth", Score: 0.6},
+	}})
+	chunks, err := client.retrieveChunks(context.Background(), "user-1", config, "prompt")
+	if err != nil {
+		t.Fatalf("failed to retrieve chunks: %v", err)
+	}
+	if len(chunks) != defaultRetrievalTopK || chunks[2].ChunkID != "c" || chunks[2].Rank != 3 || chunks[0].Collection != "docs" {
+		t.Errorf("expected the retriever's first %d chunks ranked in order, got %+v", defaultRetrievalTopK, chunks)
+	}
+
+	client.SetDocumentRetriever(&staticRetriever{err: errors.New("index offline")})
+	if _, err := client.retrieveChunks(context.Background(), "user-1", config, "prompt"); err == nil || !strings.Contains(err.Error(), "static retriever failed") {
+		t.Errorf("expected the retriever's error, got %v", err)
+	}
+}
+
+func TestRetrievalContext(t *testing.T) {
+	if got := retrievalContext(nil, "Answer briefly."); got != "Answer briefly." {
+		t.Errorf("expected the context unchanged without chunks, got %q", got)
+	}
+
+	chunks := []types.RetrievedChunk{
+		{DocumentName: "europe.txt", ChunkIndex: 0, Content: "Paris is the capital of France.", Rank: 1},
+		{DocumentName: "europe.txt", ChunkIndex: 2, Content: "Berlin is the capital of Germany.", Rank: 2},
+	}
+	want := "Retrieved documents:\n\n[1] europe.txt (part 1)\nParis is the capital of France." +
+		"\n\n[2] europe.txt (part 3)\nBerlin is the capital of Germany.\n\nAnswer briefly."
+	if got := retrievalContext(chunks, "Answer briefly."); got != want {
+		t.Errorf("unexpected context:\n%s", got)
+	}
+}
+
+func TestStoreRetrievedChunks(t *testing.T) {
+	client := newRetrievalTestClient(t)
+	ctx := context.Background()
+
+	request := &types.APIRequest{ID: "req-1", ExecutionRunID: "run-1", ConfigurationID: "config-1"}
+	chunks := []types.RetrievedChunk{
+		{ChunkID: "c1", DocumentID: "d1", DocumentName: "notes.txt", Collection: "docs", Content: "Use key sk-abcdefghijklmnopqrstuvwxyz", Score: 0.9, Rank: 1},
+		{ChunkID: "c2", DocumentID: "d1", DocumentName: "notes.txt", Collection: "docs", ChunkIndex: 1, Content: "Second", Score: 0.5, Rank: 2},
+	}
+	if err := client.storeRetrievedChunks(ctx, "user-1", request, chunks); err != nil {
+		t.Fatalf("failed to store retrieved chunks: %v", err)
+	}
+
+	stored, err := client.GetRetrievedChunks(ctx, "user-1", "run-1")
+	if err != nil {
+		t.Fatalf("failed to get retrieved chunks: %v", err)
+	}
+	got := stored["req-1"]
+	if len(got) != 2 || got[0].ChunkID != "c1" || got[1].ChunkIndex != 1 || got[1].Score != 0.5 {
+		t.Errorf("expected both chunks in rank order, got %+v", got)
+	}
+	if strings.Contains(got[0].Content, "sk-abcdefghijklmnopqrstuvwxyz") {
+		t.Errorf("expected the stored chunk to be redacted, got %q", got[0].Content)
+	}
+
+	if other, err := client.GetRetrievedChunks(ctx, "user-2", "run-1"); err != nil || len(other) != 0 {
+		t.Errorf("expected no chunks for another user, got %v, %v", other, err)
+	}
+}
+
+func TestValidateRetrieval(t *testing.T) {
+	validation := &ValidationError{}
+	validateRetrieval(validation, "configurations[0]", &types.RetrievalConfig{TopK: 50, MinScore: 1.5})
+	fields := make([]string, len(validation.Errors))
+	for i, fieldError := range validation.Errors {
+		fields[i] = fieldError.Field
+	}
+	want := "configurations[0].retrieval.collection,configurations[0].retrieval.topK,configurations[0].retrieval.minScore"
+	if got := strings.Join(fields, ","); got != want {
+		t.Errorf("expected errors for %s, got %s", want, got)
+	}
+
+	validation = &ValidationError{}
+	validateRetrieval(validation, "configurations[0]", &types.RetrievalConfig{Collection: "docs", TopK: 5, MinScore: 0.3})
+	validateRetrieval(validation, "configurations[1]", nil)
+	if len(validation.Errors) != 0 {
+		t.Errorf("expected valid settings to pass, got %v", validation.Errors)
+	}
+}
+
+// staticRetriever is a DocumentRetriever returning fixed chunks
+type staticRetriever struct {
+	chunks []types.RetrievedChunk
+	err    error
+}
+
+func (r *staticRetriever) Name() string {
+	return "static"
+}
+
+func (r *staticRetriever) Retrieve(ctx context.Context, userID string, query types.RetrievalQuery) ([]types.RetrievedChunk, error) {
+	return r.chunks, r.err
+}
@@ -0,0 +1,774 @@
+package gogent
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"gogent/internal/types"
+)
+
+var (
+	// ErrInvalidReview is returned for a review request or judgments that cannot be accepted
+	ErrInvalidReview = errors.New("invalid review")
+	// ErrReviewNotFound is returned when a review does not exist, or is neither owned by nor assigned to the user
+	ErrReviewNotFound = errors.New("review not found")
+	// ErrReviewClosed is returned when judging or closing a review that is no longer open
+	ErrReviewClosed = errors.New("review is no longer open")
+	// ErrReviewOpen is returned when asking for the results of a review that is still open
+	ErrReviewOpen = errors.New("review is still open")
+)
+
+const (
+	// maxReviewers caps how many reviewers a review may be assigned to
+	maxReviewers = 20
+	// maxReviewLabels caps how many labels a label review may offer
+	maxReviewLabels = 10
+	// maxReviewLabelLength caps the characters of a label
+	maxReviewLabelLength = 100
+)
+
+// ValidateReviewRequest checks a review's mode, labels and reviewers. Label reviews need at least
+// two distinct labels; rank reviews take none.
+func ValidateReviewRequest(request *types.ReviewRequest) error {
+	switch request.Mode {
+	case "", types.ReviewModeRank:
+		if len(request.Labels) > 0 {
+			return fmt.Errorf("%w: labels are only used by %s reviews", ErrInvalidReview, types.ReviewModeLabel)
+		}
+	case types.ReviewModeLabel:
+		if len(request.Labels) < 2 || len(request.Labels) > maxReviewLabels {
+			return fmt.Errorf("%w: label reviews need between 2 and %d labels, got %d", ErrInvalidReview, maxReviewLabels, len(request.Labels))
+		}
+		for i, label := range request.Labels {
+			if label == "" || len([]rune(label)) > maxReviewLabelLength {
+				return fmt.Errorf("%w: labels must be 1 to %d characters, got %q", ErrInvalidReview, maxReviewLabelLength, label)
+			}
+			if slices.Contains(request.Labels[:i], label) {
+				return fmt.Errorf("%w: duplicate label %q", ErrInvalidReview, label)
+			}
+		}
+	default:
+		return fmt.Errorf("%w: mode must be %q or %q, got %q", ErrInvalidReview, types.ReviewModeRank, types.ReviewModeLabel, request.Mode)
+	}
+
+	if len(request.Reviewers) == 0 || len(request.Reviewers) > maxReviewers {
+		return fmt.Errorf("%w: assign between 1 and %d reviewers, got %d", ErrInvalidReview, maxReviewers, len(request.Reviewers))
+	}
+	for i, reviewer := range request.Reviewers {
+		if reviewer == "" {
+			return fmt.Errorf("%w: reviewer usernames must not be empty", ErrInvalidReview)
+		}
+		if slices.Contains(request.Reviewers[:i], reviewer) {
+			return fmt.Errorf("%w: duplicate reviewer %q", ErrInvalidReview, reviewer)
+		}
+	}
+	return nil
+}
+
+// CreateReview starts a blind review of one of the user's runs. Each configuration's first
+// successful response is reviewed, so a run needs at least two of them.
+func (c *Client) CreateReview(ctx context.Context, userID, executionRunID string, request *types.ReviewRequest) (*types.Review, error) {
+	if err := ValidateReviewRequest(request); err != nil {
+		return nil, err
+	}
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+
+	result, err := c.GetExecutionResult(ctx, userID, executionRunID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrExecutionRunNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var samples []*types.VariationResult
+	seen := make(map[string]bool)
+	for _, r := range result.Results {
+		if seen[r.Configuration.ID] {
+			continue
+		}
+		seen[r.Configuration.ID] = true
+		if sample := firstSuccessfulSample(result.Results, r.Configuration.ID); sample != nil {
+			samples = append(samples, sample)
+		}
+	}
+	if len(samples) < 2 {
+		return nil, fmt.Errorf("%w: the run needs successful responses from at least 2 configurations, got %d", ErrInvalidReview, len(samples))
+	}
+
+	reviewerIDs, err := c.reviewerIDs(ctx, request.Reviewers)
+	if err != nil {
+		return nil, err
+	}
+
+	review := &types.Review{
+		ID:             uuid.New().String(),
+		ExecutionRunID: executionRunID,
+		Mode:           request.Mode,
+		Labels:         request.Labels,
+		Status:         types.ReviewStatusOpen,
+		Responses:      len(samples),
+		Assignments:    []types.ReviewAssignment{},
+		CreatedAt:      time.Now().UTC(),
+	}
+	if review.Mode == "" {
+		review.Mode = types.ReviewModeRank
+	}
+	var labels sql.NullString
+	if len(review.Labels) > 0 {
+		encoded, err := types.ToJSON(review.Labels)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal review labels: %w", err)
+		}
+		labels = sql.NullString{String: encoded, Valid: true}
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO reviews (id, user_id, execution_run_id, mode, labels, prompt, status, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		review.ID, userID, executionRunID, review.Mode, labels, samples[0].Request.Prompt, review.Status, review.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store review: %w", err)
+	}
+	for i, sample := range samples {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO review_items (id, review_id, response_id, configuration_id, position)
+			VALUES (?, ?, ?, ?, ?)`,
+			uuid.New().String(), review.ID, sample.Response.ID, sample.Configuration.ID, i+1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to store review item: %w", err)
+		}
+	}
+	for i, reviewerID := range reviewerIDs {
+		_, err = tx.ExecContext(ctx, "INSERT INTO review_assignments (review_id, reviewer_id, created_at) VALUES (?, ?, ?)",
+			review.ID, reviewerID, review.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to assign reviewer: %w", err)
+		}
+		review.Assignments = append(review.Assignments, types.ReviewAssignment{Reviewer: request.Reviewers[i]})
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit review: %w", err)
+	}
+	return review, nil
+}
+
+// firstSuccessfulSample returns the successful result of a configuration with the lowest repetition
+func firstSuccessfulSample(results []types.VariationResult, configurationID string) *types.VariationResult {
+	var first *types.VariationResult
+	for i := range results {
+		r := &results[i]
+		if r.Configuration.ID == configurationID && r.Response.ResponseStatus == types.ResponseStatusSuccess &&
+			(first == nil || r.Repetition < first.Repetition) {
+			first = r
+		}
+	}
+	return first
+}
+
+// reviewerIDs resolves reviewer usernames to user IDs, in the same order
+func (c *Client) reviewerIDs(ctx context.Context, usernames []string) ([]string, error) {
+	args := make([]interface{}, len(usernames))
+	for i, username := range usernames {
+		args[i] = username
+	}
+	rows, err := c.db.QueryContext(ctx, "SELECT id, username FROM users WHERE username IN ("+placeholders(len(usernames))+")", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up reviewers: %w", err)
+	}
+	defer rows.Close()
+
+	byUsername := make(map[string]string, len(usernames))
+	for rows.Next() {
+		var id, username string
+		if err := rows.Scan(&id, &username); err != nil {
+			return nil, fmt.Errorf("failed to scan reviewer: %w", err)
+		}
+		byUsername[username] = id
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate reviewers: %w", err)
+	}
+
+	ids := make([]string, len(usernames))
+	for i, username := range usernames {
+		id, ok := byUsername[username]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown reviewer %q", ErrInvalidReview, username)
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// storedReview is a review row with the fields only its owner and reviewers see
+type storedReview struct {
+	types.Review
+	ownerID string
+	prompt  string
+}
+
+// loadReview loads a review with its assignments, whoever owns it
+func (c *Client) loadReview(ctx context.Context, reviewID string) (*storedReview, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	review := &storedReview{}
+	var labels, prompt sql.NullString
+	var closedAt sql.NullTime
+	err := c.db.QueryRowContext(ctx, `
+		SELECT r.id, r.user_id, r.execution_run_id, r.mode, r.labels, r.prompt, r.status, r.created_at, r.closed_at,
+			(SELECT COUNT(*) FROM review_items i WHERE i.review_id = r.id)
+		FROM reviews r
+		WHERE r.id = ?`, reviewID).Scan(&review.ID, &review.ownerID, &review.ExecutionRunID, &review.Mode, &labels,
+		&prompt, &review.Status, &review.CreatedAt, &closedAt, &review.Responses)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ErrReviewNotFound, reviewID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review: %w", err)
+	}
+	review.prompt = prompt.String
+	if closedAt.Valid {
+		review.ClosedAt = &closedAt.Time
+	}
+	if labels.Valid && labels.String != "" {
+		if err := types.FromJSON(labels.String, &review.Labels); err != nil {
+			return nil, fmt.Errorf("failed to parse review labels: %w", err)
+		}
+	}
+
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT u.username, a.submitted_at
+		FROM review_assignments a
+		JOIN users u ON u.id = a.reviewer_id
+		WHERE a.review_id = ?
+		ORDER BY u.username ASC`, reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review assignments: %w", err)
+	}
+	defer rows.Close()
+
+	review.Assignments = []types.ReviewAssignment{}
+	for rows.Next() {
+		var assignment types.ReviewAssignment
+		var submittedAt sql.NullTime
+		if err := rows.Scan(&assignment.Reviewer, &submittedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan review assignment: %w", err)
+		}
+		if submittedAt.Valid {
+			assignment.SubmittedAt = &submittedAt.Time
+			review.Submitted++
+		}
+		review.Assignments = append(review.Assignments, assignment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate review assignments: %w", err)
+	}
+	return review, nil
+}
+
+// GetReview returns a review of one of the user's runs with its progress
+func (c *Client) GetReview(ctx context.Context, userID, reviewID string) (*types.Review, error) {
+	review, err := c.loadReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.ownerID != userID {
+		return nil, fmt.Errorf("%w: %s", ErrReviewNotFound, reviewID)
+	}
+	return &review.Review, nil
+}
+
+// ListReviews returns the reviews of the user's runs, newest first
+func (c *Client) ListReviews(ctx context.Context, userID string) ([]types.Review, error) {
+	return c.listReviews(ctx, "SELECT id FROM reviews WHERE user_id = ? ORDER BY created_at DESC, id ASC", userID)
+}
+
+// ListReviewTasks returns the reviews assigned to the user as the user sees them, newest first
+func (c *Client) ListReviewTasks(ctx context.Context, userID string) ([]types.ReviewTask, error) {
+	reviews, err := c.listReviews(ctx, `
+		SELECT r.id
+		FROM reviews r
+		JOIN review_assignments a ON a.review_id = r.id
+		WHERE a.reviewer_id = ?
+		ORDER BY r.created_at DESC, r.id ASC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]types.ReviewTask, 0, len(reviews))
+	for _, review := range reviews {
+		task, err := c.GetReviewTask(ctx, userID, review.ID)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, *task)
+	}
+	return tasks, nil
+}
+
+// listReviews loads the reviews whose IDs a query selects
+func (c *Client) listReviews(ctx context.Context, query, userID string) ([]types.Review, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	rows, err := c.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reviews: %w", err)
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan review: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate reviews: %w", err)
+	}
+
+	reviews := make([]types.Review, 0, len(ids))
+	for _, id := range ids {
+		review, err := c.loadReview(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review.Review)
+	}
+	return reviews, nil
+}
+
+// reviewItem is a response under review with its hidden configuration
+type reviewItem struct {
+	types.ReviewItem
+	responseID      string
+	configurationID string
+	variationName   string
+	modelName       string
+}
+
+// loadReviewItems returns a review's items in the order they were stored
+func (c *Client) loadReviewItems(ctx context.Context, reviewID string) ([]reviewItem, error) {
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT i.id, i.response_id, i.configuration_id, resp.response_text, c.variation_name, c.model_name
+		FROM review_items i
+		JOIN api_responses resp ON resp.id = i.response_id
+		JOIN api_configurations c ON c.id = i.configuration_id
+		WHERE i.review_id = ?
+		ORDER BY i.position ASC`, reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []reviewItem
+	for rows.Next() {
+		var item reviewItem
+		var text sql.NullString
+		if err := rows.Scan(&item.ID, &item.responseID, &item.configurationID, &text, &item.variationName, &item.modelName); err != nil {
+			return nil, fmt.Errorf("failed to scan review item: %w", err)
+		}
+		item.Text = text.String
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate review items: %w", err)
+	}
+	return items, nil
+}
+
+// blindOrder shuffles items the same way every time for a reviewer, and differently for each
+// reviewer, so no position stands for a configuration
+func blindOrder(reviewerID string, items []types.ReviewItem) {
+	key := func(item types.ReviewItem) string {
+		sum := sha256.Sum256([]byte(reviewerID + ":" + item.ID))
+		return string(sum[:])
+	}
+	slices.SortFunc(items, func(a, b types.ReviewItem) int { return strings.Compare(key(a), key(b)) })
+}
+
+// GetReviewTask returns a review assigned to the user: the prompt and the responses without their
+// configurations, in the user's own order, and the judgments the user submitted
+func (c *Client) GetReviewTask(ctx context.Context, userID, reviewID string) (*types.ReviewTask, error) {
+	review, err := c.loadReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	judgments, assigned, err := c.reviewerJudgments(ctx, reviewID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !assigned {
+		return nil, fmt.Errorf("%w: %s", ErrReviewNotFound, reviewID)
+	}
+	items, err := c.loadReviewItems(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	task := &types.ReviewTask{
+		ReviewID:  review.ID,
+		Mode:      review.Mode,
+		Labels:    review.Labels,
+		Status:    review.Status,
+		Prompt:    review.prompt,
+		Items:     make([]types.ReviewItem, len(items)),
+		Judgments: judgments,
+	}
+	for i, item := range items {
+		task.Items[i] = item.ReviewItem
+	}
+	blindOrder(userID, task.Items)
+	return task, nil
+}
+
+// reviewerJudgments returns the judgments a reviewer submitted on a review, and whether the review
+// is assigned to them at all
+func (c *Client) reviewerJudgments(ctx context.Context, reviewID, reviewerID string) ([]types.ReviewJudgment, bool, error) {
+	var assignments int
+	err := c.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM review_assignments WHERE review_id = ? AND reviewer_id = ?",
+		reviewID, reviewerID).Scan(&assignments)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get review assignment: %w", err)
+	}
+	if assignments == 0 {
+		return nil, false, nil
+	}
+
+	judgments, err := c.loadReviewJudgments(ctx, "WHERE review_id = ? AND reviewer_id = ?", reviewID, reviewerID)
+	if err != nil {
+		return nil, false, err
+	}
+	return judgments[reviewerID], true, nil
+}
+
+// loadReviewJudgments returns the judgments a condition selects by reviewer, ordered by item
+func (c *Client) loadReviewJudgments(ctx context.Context, where string, args ...interface{}) (map[string][]types.ReviewJudgment, error) {
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT reviewer_id, item_id, item_rank, label
+		FROM review_judgments `+where+`
+		ORDER BY reviewer_id ASC, item_id ASC`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review judgments: %w", err)
+	}
+	defer rows.Close()
+
+	judgments := make(map[string][]types.ReviewJudgment)
+	for rows.Next() {
+		var reviewerID string
+		var judgment types.ReviewJudgment
+		var rank sql.NullInt32
+		var label sql.NullString
+		if err := rows.Scan(&reviewerID, &judgment.ItemID, &rank, &label); err != nil {
+			return nil, fmt.Errorf("failed to scan review judgment: %w", err)
+		}
+		judgment.Rank, judgment.Label = int(rank.Int32), label.String
+		judgments[reviewerID] = append(judgments[reviewerID], judgment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate review judgments: %w", err)
+	}
+	return judgments, nil
+}
+
+// SubmitReviewJudgments records the user's judgments of every item of a review assigned to them,
+// replacing any submitted before. A review is completed once every reviewer has submitted.
+func (c *Client) SubmitReviewJudgments(ctx context.Context, userID, reviewID string, judgments []types.ReviewJudgment) (*types.ReviewTask, error) {
+	task, err := c.GetReviewTask(ctx, userID, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if task.Status != types.ReviewStatusOpen {
+		return nil, fmt.Errorf("%w: %s is %s", ErrReviewClosed, reviewID, task.Status)
+	}
+	if err := validateReviewJudgments(task, judgments); err != nil {
+		return nil, err
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM review_judgments WHERE review_id = ? AND reviewer_id = ?", reviewID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to replace review judgments: %w", err)
+	}
+	for _, judgment := range judgments {
+		rank := sql.NullInt32{Int32: int32(judgment.Rank), Valid: judgment.Rank > 0}
+		label := sql.NullString{String: judgment.Label, Valid: judgment.Label != ""}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO review_judgments (review_id, reviewer_id, item_id, item_rank, label)
+			VALUES (?, ?, ?, ?, ?)`, reviewID, userID, judgment.ItemID, rank, label)
+		if err != nil {
+			return nil, fmt.Errorf("failed to store review judgment: %w", err)
+		}
+	}
+	now := time.Now().UTC()
+	_, err = tx.ExecContext(ctx, "UPDATE review_assignments SET submitted_at = ? WHERE review_id = ? AND reviewer_id = ?",
+		now, reviewID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark review submitted: %w", err)
+	}
+	_, err = tx.ExecContext(ctx, `
+		UPDATE reviews SET status = ?, closed_at = ?
+		WHERE id = ? AND status = ? AND NOT EXISTS (
+			SELECT 1 FROM review_assignments a WHERE a.review_id = ? AND a.submitted_at IS NULL)`,
+		types.ReviewStatusCompleted, now, reviewID, types.ReviewStatusOpen, reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete review: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit review judgments: %w", err)
+	}
+	return c.GetReviewTask(ctx, userID, reviewID)
+}
+
+// validateReviewJudgments checks judgments cover every item of a task once: rank reviews with the
+// ranks 1 to the number of items, label reviews with one of the review's labels
+func validateReviewJudgments(task *types.ReviewTask, judgments []types.ReviewJudgment) error {
+	if len(judgments) != len(task.Items) {
+		return fmt.Errorf("%w: judge each of the %d responses once, got %d judgments", ErrInvalidReview, len(task.Items), len(judgments))
+	}
+	judged := make(map[string]bool, len(judgments))
+	ranks := make(map[int]bool, len(judgments))
+	for _, judgment := range judgments {
+		if !slices.ContainsFunc(task.Items, func(item types.ReviewItem) bool { return item.ID == judgment.ItemID }) {
+			return fmt.Errorf("%w: unknown item %q", ErrInvalidReview, judgment.ItemID)
+		}
+		if judged[judgment.ItemID] {
+			return fmt.Errorf("%w: item %q is judged more than once", ErrInvalidReview, judgment.ItemID)
+		}
+		judged[judgment.ItemID] = true
+
+		switch task.Mode {
+		case types.ReviewModeRank:
+			if judgment.Label != "" {
+				return fmt.Errorf("%w: rank reviews take ranks, not labels", ErrInvalidReview)
+			}
+			if judgment.Rank < 1 || judgment.Rank > len(task.Items) || ranks[judgment.Rank] {
+				return fmt.Errorf("%w: ranks must be 1 to %d, each used once, got %d", ErrInvalidReview, len(task.Items), judgment.Rank)
+			}
+			ranks[judgment.Rank] = true
+		case types.ReviewModeLabel:
+			if judgment.Rank != 0 {
+				return fmt.Errorf("%w: label reviews take labels, not ranks", ErrInvalidReview)
+			}
+			if !slices.Contains(task.Labels, judgment.Label) {
+				return fmt.Errorf("%w: label must be one of %s, got %q", ErrInvalidReview, strings.Join(task.Labels, ", "), judgment.Label)
+			}
+		}
+	}
+	return nil
+}
+
+// CloseReview ends a review of one of the user's runs before every reviewer has submitted, so its
+// results can be revealed
+func (c *Client) CloseReview(ctx context.Context, userID, reviewID string) (*types.Review, error) {
+	review, err := c.GetReview(ctx, userID, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.Status != types.ReviewStatusOpen {
+		return nil, fmt.Errorf("%w: %s is %s", ErrReviewClosed, reviewID, review.Status)
+	}
+	_, err = c.db.ExecContext(ctx, "UPDATE reviews SET status = ?, closed_at = ? WHERE id = ? AND status = ?",
+		types.ReviewStatusClosed, time.Now().UTC(), reviewID, types.ReviewStatusOpen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to close review: %w", err)
+	}
+	return c.GetReview(ctx, userID, reviewID)
+}
+
+// GetReviewResults reveals the configurations behind a completed or closed review of one of the
+// user's runs, best first, with how much its reviewers agreed
+func (c *Client) GetReviewResults(ctx context.Context, userID, reviewID string) (*types.ReviewResults, error) {
+	review, err := c.GetReview(ctx, userID, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.Status == types.ReviewStatusOpen {
+		return nil, fmt.Errorf("%w: %d of %d reviewers have submitted", ErrReviewOpen, review.Submitted, len(review.Assignments))
+	}
+	items, err := c.loadReviewItems(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	judgments, err := c.loadReviewJudgments(ctx, "WHERE review_id = ?", reviewID)
+	if err != nil {
+		return nil, err
+	}
+	return scoreReview(review, items, judgments), nil
+}
+
+// scoreReview scores each item from its judgments. Ranks are scaled so first is 1 and last is 0,
+// and labels so the first label is 1 and the last is 0; an item's score is the mean over reviewers.
+func scoreReview(review *types.Review, items []reviewItem, judgments map[string][]types.ReviewJudgment) *types.ReviewResults {
+	results := &types.ReviewResults{Review: *review, Items: make([]types.ReviewItemResult, len(items))}
+	index := make(map[string]int, len(items))
+	totals := make([]float64, len(items))
+	for i, item := range items {
+		index[item.ID] = i
+		results.Items[i] = types.ReviewItemResult{
+			ItemID:          item.ID,
+			ResponseID:      item.responseID,
+			ConfigurationID: item.configurationID,
+			VariationName:   item.variationName,
+			ModelName:       item.modelName,
+		}
+		if review.Mode == types.ReviewModeLabel {
+			results.Items[i].LabelCounts = make(map[string]int)
+		}
+	}
+
+	// ranks[r][i] and labels[r][i] are reviewer r's judgment of item i
+	var ranks [][]int
+	var labels [][]int
+	for _, reviewerJudgments := range judgments {
+		reviewerRanks := make([]int, len(items))
+		reviewerLabels := make([]int, len(items))
+		for _, judgment := range reviewerJudgments {
+			i, ok := index[judgment.ItemID]
+			if !ok {
+				continue
+			}
+			item := &results.Items[i]
+			item.Judgments++
+			switch review.Mode {
+			case types.ReviewModeRank:
+				reviewerRanks[i] = judgment.Rank
+				item.MeanRank += float64(judgment.Rank)
+				if len(items) > 1 {
+					totals[i] += float64(len(items)-judgment.Rank) / float64(len(items)-1)
+				}
+			case types.ReviewModeLabel:
+				position := slices.Index(review.Labels, judgment.Label)
+				reviewerLabels[i] = position
+				item.LabelCounts[judgment.Label]++
+				totals[i] += float64(len(review.Labels)-1-position) / float64(len(review.Labels)-1)
+			}
+		}
+		ranks = append(ranks, reviewerRanks)
+		labels = append(labels, reviewerLabels)
+	}
+	for i := range results.Items {
+		if item := &results.Items[i]; item.Judgments > 0 {
+			item.Score = totals[i] / float64(item.Judgments)
+			if review.Mode == types.ReviewModeRank {
+				item.MeanRank /= float64(item.Judgments)
+			}
+		}
+	}
+
+	slices.SortStableFunc(results.Items, func(a, b types.ReviewItemResult) int {
+		switch {
+		case a.Score > b.Score:
+			return -1
+		case a.Score < b.Score:
+			return 1
+		}
+		return 0
+	})
+	if len(results.Items) > 0 && results.Items[0].Judgments > 0 &&
+		(len(results.Items) == 1 || results.Items[0].Score > results.Items[1].Score) {
+		winner := results.Items[0]
+		results.Winner = &winner
+	}
+
+	if len(judgments) >= 2 {
+		var agreement float64
+		switch review.Mode {
+		case types.ReviewModeRank:
+			agreement, results.AgreementMetric = KendallW(ranks), "kendall_w"
+		case types.ReviewModeLabel:
+			agreement, results.AgreementMetric = FleissKappa(labels, len(review.Labels)), "fleiss_kappa"
+		}
+		results.Agreement = &agreement
+	}
+	return results
+}
+
+// KendallW is Kendall's coefficient of concordance of reviewers who each ranked every item from 1,
+// given as ranks[reviewer][item]: 1 when they ranked the items identically, 0 when they share no
+// preference at all
+func KendallW(ranks [][]int) float64 {
+	m := len(ranks)
+	if m == 0 || len(ranks[0]) < 2 {
+		return 1
+	}
+	n := len(ranks[0])
+	sums := make([]float64, n)
+	for _, reviewerRanks := range ranks {
+		for i, rank := range reviewerRanks {
+			sums[i] += float64(rank)
+		}
+	}
+	mean := float64(m*(n+1)) / 2
+	var squares float64
+	for _, sum := range sums {
+		squares += (sum - mean) * (sum - mean)
+	}
+	return 12 * squares / (float64(m*m) * float64(n*n*n-n))
+}
+
+// FleissKappa is Fleiss' kappa of reviewers who each gave every item one of categories labels,
+// given as labels[reviewer][item] label positions: 1 for complete agreement, 0 for the agreement
+// expected by chance and below 0 for less
+func FleissKappa(labels [][]int, categories int) float64 {
+	m := len(labels)
+	if m < 2 || len(labels[0]) == 0 {
+		return 1
+	}
+	n := len(labels[0])
+
+	// counts[i][j] is how many reviewers gave item i label j
+	counts := make([][]float64, n)
+	for i := range counts {
+		counts[i] = make([]float64, categories)
+	}
+	for _, reviewerLabels := range labels {
+		for i, label := range reviewerLabels {
+			counts[i][label]++
+		}
+	}
+
+	var observed float64
+	shares := make([]float64, categories)
+	for _, itemCounts := range counts {
+		var agreeing float64
+		for j, count := range itemCounts {
+			agreeing += count * (count - 1)
+			shares[j] += count / float64(n*m)
+		}
+		observed += agreeing / float64(m*(m-1))
+	}
+	observed /= float64(n)
+
+	var expected float64
+	for _, share := range shares {
+		expected += share * share
+	}
+	if expected == 1 {
+		return 1
+	}
+	return (observed - expected) / (1 - expected)
+}
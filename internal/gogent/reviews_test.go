@@ -0,0 +1,225 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"gogent/internal/types"
+)
+
+// newReviewTestClient returns a store test client with a run of three configurations, one of which
+// failed, three users and the tables reviews are stored in
+func newReviewTestClient(t *testing.T) (*Client, string) {
+	client, _ := newStoreTestClient(t)
+	ctx := context.Background()
+
+	run, err := client.CreateExecutionRun(ctx, "user-1", "Capital cities", "", false)
+	if err != nil {
+		t.Fatalf("failed to create run: %v", err)
+	}
+	_, err = client.db.Exec(`
+		INSERT INTO users (id, username) VALUES ('user-1', 'owner'), ('user-2', 'alice'), ('user-3', 'bob');`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+
+	for _, name := range []string{"cold", "warm", "hot"} {
+		status := types.ResponseStatusSuccess
+		if name == "hot" {
+			status = types.ResponseStatusError
+		}
+		config := &types.APIConfiguration{ID: "config-" + name, ExecutionRunID: run.ID, VariationName: name, ModelName: "gemini-2.0-flash"}
+		request := &types.APIRequest{ID: "request-" + name, ExecutionRunID: run.ID, ConfigurationID: config.ID, Prompt: "Capital of France?"}
+		response := &types.APIResponse{ID: "response-" + name, RequestID: request.ID, ResponseStatus: status, ResponseText: "Paris, " + name}
+		if err := client.CreateAPIConfiguration(ctx, "user-1", config); err != nil {
+			t.Fatalf("failed to create configuration: %v", err)
+		}
+		if err := client.LogAPIRequest(ctx, "user-1", request); err != nil {
+			t.Fatalf("failed to log request: %v", err)
+		}
+		if err := client.LogAPIResponse(ctx, "user-1", response); err != nil {
+			t.Fatalf("failed to log response: %v", err)
+		}
+		if _, err := client.db.Exec("INSERT INTO api_configurations (id, variation_name, model_name) VALUES (?, ?, ?)", config.ID, name, config.ModelName); err != nil {
+			t.Fatalf("failed to store test configuration: %v", err)
+		}
+		if _, err := client.db.Exec("INSERT INTO api_responses (id, response_text) VALUES (?, ?)", response.ID, response.ResponseText); err != nil {
+			t.Fatalf("failed to store test response: %v", err)
+		}
+	}
+	return client, run.ID
+}
+
+// judgeByText returns judgments of a task's items from the ranks or labels of their texts
+func judgeByText(task *types.ReviewTask, ranks map[string]int, labels map[string]string) []types.ReviewJudgment {
+	var judgments []types.ReviewJudgment
+	for _, item := range task.Items {
+		judgments = append(judgments, types.ReviewJudgment{ItemID: item.ID, Rank: ranks[item.Text], Label: labels[item.Text]})
+	}
+	return judgments
+}
+
+func TestValidateReviewRequest(t *testing.T) {
+	for _, request := range []types.ReviewRequest{
+		{Reviewers: []string{"alice"}},
+		{Mode: types.ReviewModeLabel, Labels: []string{"good", "bad"}, Reviewers: []string{"alice", "bob"}},
+	} {
+		if err := ValidateReviewRequest(&request); err != nil {
+			t.Errorf("expected %+v to be valid, got %v", request, err)
+		}
+	}
+	for _, request := range []types.ReviewRequest{
+		{},
+		{Mode: "vote", Reviewers: []string{"alice"}},
+		{Labels: []string{"good", "bad"}, Reviewers: []string{"alice"}},
+		{Mode: types.ReviewModeLabel, Labels: []string{"good"}, Reviewers: []string{"alice"}},
+		{Mode: types.ReviewModeLabel, Labels: []string{"good", "good"}, Reviewers: []string{"alice"}},
+		{Reviewers: []string{"alice", "alice"}},
+	} {
+		if err := ValidateReviewRequest(&request); !errors.Is(err, ErrInvalidReview) {
+			t.Errorf("expected %+v to be rejected, got %v", request, err)
+		}
+	}
+}
+
+func TestRankReview(t *testing.T) {
+	client, runID := newReviewTestClient(t)
+	ctx := context.Background()
+
+	review, err := client.CreateReview(ctx, "user-1", runID, &types.ReviewRequest{Reviewers: []string{"alice", "bob"}})
+	if err != nil {
+		t.Fatalf("failed to create review: %v", err)
+	}
+	if review.Mode != types.ReviewModeRank || review.Responses != 2 || len(review.Assignments) != 2 {
+		t.Fatalf("expected a rank review of the 2 successful responses, got %+v", review)
+	}
+
+	// Reviewers see the prompt and responses, but not their configurations
+	task, err := client.GetReviewTask(ctx, "user-2", review.ID)
+	if err != nil {
+		t.Fatalf("failed to get review task: %v", err)
+	}
+	if task.Prompt != "Capital of France?" || len(task.Items) != 2 || len(task.Judgments) != 0 {
+		t.Fatalf("unexpected review task: %+v", task)
+	}
+	if _, err := client.GetReviewTask(ctx, "user-1", review.ID); !errors.Is(err, ErrReviewNotFound) {
+		t.Errorf("expected the owner not to be a reviewer, got %v", err)
+	}
+	if _, err := client.GetReviewResults(ctx, "user-1", review.ID); !errors.Is(err, ErrReviewOpen) {
+		t.Errorf("expected results to stay hidden while the review is open, got %v", err)
+	}
+	if _, err := client.SubmitReviewJudgments(ctx, "user-2", review.ID, judgeByText(task, map[string]int{"Paris, cold": 1, "Paris, warm": 1}, nil)); !errors.Is(err, ErrInvalidReview) {
+		t.Errorf("expected a repeated rank to be rejected, got %v", err)
+	}
+
+	ranks := map[string]int{"Paris, cold": 2, "Paris, warm": 1}
+	task, err = client.SubmitReviewJudgments(ctx, "user-2", review.ID, judgeByText(task, ranks, nil))
+	if err != nil {
+		t.Fatalf("failed to submit judgments: %v", err)
+	}
+	if task.Status != types.ReviewStatusOpen || len(task.Judgments) != 2 {
+		t.Errorf("expected the review to wait for bob, got %+v", task)
+	}
+	progress, err := client.GetReview(ctx, "user-1", review.ID)
+	if err != nil || progress.Submitted != 1 {
+		t.Errorf("expected 1 of 2 reviewers to have submitted, got %+v, %v", progress, err)
+	}
+
+	bobTask, err := client.GetReviewTask(ctx, "user-3", review.ID)
+	if err != nil {
+		t.Fatalf("failed to get review task: %v", err)
+	}
+	if task, err = client.SubmitReviewJudgments(ctx, "user-3", review.ID, judgeByText(bobTask, ranks, nil)); err != nil || task.Status != types.ReviewStatusCompleted {
+		t.Fatalf("expected the last submission to complete the review, got %+v, %v", task, err)
+	}
+	if _, err := client.SubmitReviewJudgments(ctx, "user-3", review.ID, judgeByText(bobTask, ranks, nil)); !errors.Is(err, ErrReviewClosed) {
+		t.Errorf("expected a completed review to take no more judgments, got %v", err)
+	}
+
+	results, err := client.GetReviewResults(ctx, "user-1", review.ID)
+	if err != nil {
+		t.Fatalf("failed to get review results: %v", err)
+	}
+	if results.Winner == nil || results.Winner.VariationName != "warm" || results.Winner.Score != 1 || results.Winner.MeanRank != 1 {
+		t.Errorf("expected warm to win, got %+v", results.Winner)
+	}
+	if results.AgreementMetric != "kendall_w" || results.Agreement == nil || *results.Agreement != 1 {
+		t.Errorf("expected full agreement, got %v %v", results.AgreementMetric, results.Agreement)
+	}
+	if _, err := client.GetReviewResults(ctx, "user-2", review.ID); !errors.Is(err, ErrReviewNotFound) {
+		t.Errorf("expected reviewers not to see the results, got %v", err)
+	}
+}
+
+func TestLabelReviewClosedEarly(t *testing.T) {
+	client, runID := newReviewTestClient(t)
+	ctx := context.Background()
+
+	if _, err := client.CreateReview(ctx, "user-1", runID, &types.ReviewRequest{Reviewers: []string{"carol"}}); !errors.Is(err, ErrInvalidReview) {
+		t.Errorf("expected an unknown reviewer to be rejected, got %v", err)
+	}
+	if _, err := client.CreateReview(ctx, "user-2", runID, &types.ReviewRequest{Reviewers: []string{"bob"}}); !errors.Is(err, ErrExecutionRunNotFound) {
+		t.Errorf("expected another user's run to be hidden, got %v", err)
+	}
+
+	review, err := client.CreateReview(ctx, "user-1", runID, &types.ReviewRequest{
+		Mode: types.ReviewModeLabel, Labels: []string{"good", "acceptable", "bad"}, Reviewers: []string{"alice", "bob"}})
+	if err != nil {
+		t.Fatalf("failed to create review: %v", err)
+	}
+	task, err := client.GetReviewTask(ctx, "user-2", review.ID)
+	if err != nil {
+		t.Fatalf("failed to get review task: %v", err)
+	}
+	if _, err := client.SubmitReviewJudgments(ctx, "user-2", review.ID, judgeByText(task, nil, map[string]string{"Paris, cold": "great", "Paris, warm": "bad"})); !errors.Is(err, ErrInvalidReview) {
+		t.Errorf("expected an unknown label to be rejected, got %v", err)
+	}
+	if _, err := client.SubmitReviewJudgments(ctx, "user-2", review.ID, judgeByText(task, nil, map[string]string{"Paris, cold": "good", "Paris, warm": "acceptable"})); err != nil {
+		t.Fatalf("failed to submit judgments: %v", err)
+	}
+
+	closed, err := client.CloseReview(ctx, "user-1", review.ID)
+	if err != nil || closed.Status != types.ReviewStatusClosed || closed.ClosedAt == nil {
+		t.Fatalf("expected the review to close, got %+v, %v", closed, err)
+	}
+	if _, err := client.CloseReview(ctx, "user-1", review.ID); !errors.Is(err, ErrReviewClosed) {
+		t.Errorf("expected a closed review not to close again, got %v", err)
+	}
+
+	results, err := client.GetReviewResults(ctx, "user-1", review.ID)
+	if err != nil {
+		t.Fatalf("failed to get review results: %v", err)
+	}
+	if results.Winner == nil || results.Winner.VariationName != "cold" || results.Winner.LabelCounts["good"] != 1 {
+		t.Errorf("expected cold to win, got %+v", results.Winner)
+	}
+	if results.Items[1].Score != 0.5 || results.Agreement != nil {
+		t.Errorf("expected warm to score 0.5 and no agreement from a single reviewer, got %+v", results)
+	}
+
+	reviews, err := client.ListReviews(ctx, "user-1")
+	if err != nil || len(reviews) != 1 {
+		t.Errorf("expected the owner's review, got %+v, %v", reviews, err)
+	}
+	tasks, err := client.ListReviewTasks(ctx, "user-3")
+	if err != nil || len(tasks) != 1 || tasks[0].ReviewID != review.ID {
+		t.Errorf("expected bob's assigned review, got %+v, %v", tasks, err)
+	}
+}
+
+func TestAgreement(t *testing.T) {
+	if w := KendallW([][]int{{1, 2, 3}, {3, 2, 1}}); w != 0 {
+		t.Errorf("expected opposite rankings to have no concordance, got %v", w)
+	}
+	if w := KendallW([][]int{{1, 2, 3}, {1, 3, 2}, {1, 2, 3}}); math.Abs(w-7.0/9) > 1e-9 {
+		t.Errorf("expected W of 7/9, got %v", w)
+	}
+	if kappa := FleissKappa([][]int{{0, 1, 0, 1}, {0, 1, 0, 1}}, 2); kappa != 1 {
+		t.Errorf("expected identical labels to agree fully, got %v", kappa)
+	}
+	if kappa := FleissKappa([][]int{{0, 0, 1, 1}, {0, 1, 0, 1}}, 2); kappa != 0 {
+		t.Errorf("expected chance agreement, got %v", kappa)
+	}
+}
@@ -0,0 +1,204 @@
+package gogent
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"gogent/internal/types"
+)
+
+// ErrRollupsNotBuilt is returned for rollup stats before the rollups were first refreshed
+var ErrRollupsNotBuilt = errors.New("rollups have not been built yet")
+
+// dailyUserRollups names the per-user daily rollup in rollup_refreshes
+const dailyUserRollups = "daily_user_rollups"
+
+// dailyRollup is one user's totals for one day
+type dailyRollup struct {
+	executionRuns, apiRequests, apiResponses, successfulResponses int64
+	timedResponses, responseTimeMsSum, functionCalls              int64
+	promptTokens, completionTokens, totalTokens                   int64
+}
+
+// add adds another set of totals for the same user and day
+func (r *dailyRollup) add(other dailyRollup) {
+	r.executionRuns += other.executionRuns
+	r.apiRequests += other.apiRequests
+	r.apiResponses += other.apiResponses
+	r.successfulResponses += other.successfulResponses
+	r.timedResponses += other.timedResponses
+	r.responseTimeMsSum += other.responseTimeMsSum
+	r.functionCalls += other.functionCalls
+	r.promptTokens += other.promptTokens
+	r.completionTokens += other.completionTokens
+	r.totalTokens += other.totalTokens
+}
+
+// rollupKey identifies a user's day
+type rollupKey struct{ userID, day string }
+
+// RefreshDailyRollups recomputes the per-user daily totals from the execution tables. After the
+// first refresh, which builds every day, only the days since the day before the last refresh are
+// recomputed, so late writes to yesterday are caught and older days keep their totals even once
+// retention prunes their rows.
+func (c *Client) RefreshDailyRollups(ctx context.Context) (*types.RollupRefresh, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	refresh := &types.RollupRefresh{RefreshedAt: time.Now().UTC()}
+
+	var since time.Time
+	var lastRefresh time.Time
+	err := c.db.QueryRowContext(ctx, "SELECT refreshed_at FROM rollup_refreshes WHERE name = ?", dailyUserRollups).Scan(&lastRefresh)
+	switch {
+	case err == nil:
+		since = lastRefresh.UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+		refresh.Since = since.Format("2006-01-02")
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, fmt.Errorf("failed to read last rollup refresh: %w", err)
+	}
+
+	rollups, err := c.computeDailyRollups(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM daily_user_rollups WHERE day >= ?", since.Format("2006-01-02")); err != nil {
+		return nil, fmt.Errorf("failed to clear rollups: %w", err)
+	}
+	insert, err := tx.PrepareContext(ctx, `
+		INSERT INTO daily_user_rollups (user_id, day, execution_runs, api_requests, api_responses, successful_responses,
+			timed_responses, response_time_ms_sum, function_calls, prompt_tokens, completion_tokens, total_tokens)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare rollup insert: %w", err)
+	}
+	defer insert.Close()
+	for key, rollup := range rollups {
+		_, err := insert.ExecContext(ctx, key.userID, key.day, rollup.executionRuns, rollup.apiRequests, rollup.apiResponses,
+			rollup.successfulResponses, rollup.timedResponses, rollup.responseTimeMsSum, rollup.functionCalls,
+			rollup.promptTokens, rollup.completionTokens, rollup.totalTokens)
+		if err != nil {
+			return nil, fmt.Errorf("failed to store rollup: %w", err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM rollup_refreshes WHERE name = ?", dailyUserRollups); err != nil {
+		return nil, fmt.Errorf("failed to record rollup refresh: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO rollup_refreshes (name, refreshed_at) VALUES (?, ?)", dailyUserRollups, refresh.RefreshedAt); err != nil {
+		return nil, fmt.Errorf("failed to record rollup refresh: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit rollups: %w", err)
+	}
+	refresh.Rows = len(rollups)
+	return refresh, nil
+}
+
+// computeDailyRollups totals each table's rows created since the given time by user and day.
+// Function calls are counted for their request's user, so test calls are left out.
+func (c *Client) computeDailyRollups(ctx context.Context, since time.Time) (map[rollupKey]*dailyRollup, error) {
+	rollups := make(map[rollupKey]*dailyRollup)
+	queries := []struct {
+		name, query string
+		fields      func(r *dailyRollup) []interface{}
+	}{
+		{"execution runs", `
+			SELECT user_id, CAST(DATE(created_at) AS CHAR) AS day, COUNT(*)
+			FROM execution_runs WHERE created_at >= ? GROUP BY user_id, day`,
+			func(r *dailyRollup) []interface{} { return []interface{}{&r.executionRuns} }},
+		{"API requests", `
+			SELECT user_id, CAST(DATE(created_at) AS CHAR) AS day, COUNT(*)
+			FROM api_requests WHERE created_at >= ? GROUP BY user_id, day`,
+			func(r *dailyRollup) []interface{} { return []interface{}{&r.apiRequests} }},
+		{"API responses", `
+			SELECT user_id, CAST(DATE(created_at) AS CHAR) AS day, COUNT(*),
+				COALESCE(SUM(CASE WHEN response_status = 'success' THEN 1 ELSE 0 END), 0),
+				COALESCE(SUM(CASE WHEN response_time_ms IS NOT NULL THEN 1 ELSE 0 END), 0),
+				COALESCE(SUM(response_time_ms), 0), COALESCE(SUM(prompt_tokens), 0),
+				COALESCE(SUM(completion_tokens), 0), COALESCE(SUM(total_tokens), 0)
+			FROM api_responses WHERE created_at >= ? GROUP BY user_id, day`,
+			func(r *dailyRollup) []interface{} {
+				return []interface{}{&r.apiResponses, &r.successfulResponses, &r.timedResponses, &r.responseTimeMsSum,
+					&r.promptTokens, &r.completionTokens, &r.totalTokens}
+			}},
+		{"function calls", `
+			SELECT ar.user_id, CAST(DATE(fc.created_at) AS CHAR) AS day, COUNT(*)
+			FROM function_calls fc INNER JOIN api_requests ar ON fc.request_id = ar.id
+			WHERE fc.created_at >= ? GROUP BY ar.user_id, day`,
+			func(r *dailyRollup) []interface{} { return []interface{}{&r.functionCalls} }},
+	}
+
+	for _, q := range queries {
+		rows, err := c.db.QueryContext(ctx, q.query, since)
+		if err != nil {
+			return nil, fmt.Errorf("failed to total %s: %w", q.name, err)
+		}
+		for rows.Next() {
+			var key rollupKey
+			var counts dailyRollup
+			if err := rows.Scan(append([]interface{}{&key.userID, &key.day}, q.fields(&counts)...)...); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan %s totals: %w", q.name, err)
+			}
+			rollup, ok := rollups[key]
+			if !ok {
+				rollup = &dailyRollup{}
+				rollups[key] = rollup
+			}
+			rollup.add(counts)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate %s totals: %w", q.name, err)
+		}
+	}
+	return rollups, nil
+}
+
+// GetRollupStats totals the daily rollups of userID, or of every user when userID is empty, with the
+// time of the refresh they reflect. It returns ErrRollupsNotBuilt before the first refresh.
+func (c *Client) GetRollupStats(ctx context.Context, userID string) (*types.RollupStats, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	stats := &types.RollupStats{}
+	err := c.db.QueryRowContext(ctx, "SELECT refreshed_at FROM rollup_refreshes WHERE name = ?", dailyUserRollups).Scan(&stats.RefreshedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRollupsNotBuilt
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read last rollup refresh: %w", err)
+	}
+
+	var successful, timed, responseTimeMsSum int64
+	err = c.db.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(execution_runs), 0), COALESCE(SUM(api_requests), 0), COALESCE(SUM(api_responses), 0),
+			COALESCE(SUM(successful_responses), 0), COALESCE(SUM(timed_responses), 0), COALESCE(SUM(response_time_ms_sum), 0),
+			COALESCE(SUM(function_calls), 0), COALESCE(SUM(prompt_tokens), 0), COALESCE(SUM(completion_tokens), 0),
+			COALESCE(SUM(total_tokens), 0)
+		FROM daily_user_rollups WHERE (? = '' OR user_id = ?)`, userID, userID).Scan(
+		&stats.ExecutionRuns, &stats.APIRequests, &stats.APIResponses, &successful, &timed, &responseTimeMsSum,
+		&stats.FunctionCalls, &stats.PromptTokens, &stats.CompletionTokens, &stats.TotalTokens)
+	if err != nil {
+		return nil, fmt.Errorf("failed to total rollups: %w", err)
+	}
+	if timed > 0 {
+		stats.AvgResponseTimeMs = float64(responseTimeMsSum) / float64(timed)
+	}
+	if stats.APIResponses > 0 {
+		stats.SuccessRate = float64(successful) / float64(stats.APIResponses)
+	}
+	return stats, nil
+}
@@ -0,0 +1,78 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gogent/internal/testdb"
+)
+
+func TestDailyRollups(t *testing.T) {
+	database := testdb.Open(t)
+
+	_, err := database.Exec(`
+		INSERT INTO execution_runs (id, user_id, created_at) VALUES ('run-1', 'user-1', '2026-01-01 10:00:00'), ('run-2', 'user-1', '2026-01-02 10:00:00'),
+			('run-3', 'user-2', '2026-01-02 11:00:00');
+		INSERT INTO api_requests (id, user_id, created_at) VALUES ('request-1', 'user-1', '2026-01-01 10:00:01'), ('request-2', 'user-1', '2026-01-02 10:00:01'),
+			('request-3', 'user-2', '2026-01-02 11:00:01');
+		INSERT INTO api_responses (id, user_id, response_status, response_time_ms, prompt_tokens, completion_tokens, total_tokens, created_at) VALUES
+			('response-1', 'user-1', 'success', 100, 10, 20, 30, '2026-01-01 10:00:02'),
+			('response-2', 'user-1', 'error', NULL, NULL, NULL, NULL, '2026-01-02 10:00:02'),
+			('response-3', 'user-2', 'success', 300, 5, 5, 10, '2026-01-02 11:00:02');
+		INSERT INTO function_calls (id, request_id, created_at) VALUES ('call-1', 'request-1', '2026-01-01 10:00:01'), ('call-test', NULL, '2026-01-01 10:00:01');
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+	client := &Client{db: database}
+	ctx := context.Background()
+
+	if _, err := client.GetRollupStats(ctx, "user-1"); !errors.Is(err, ErrRollupsNotBuilt) {
+		t.Fatalf("expected no stats before the first refresh, got %v", err)
+	}
+
+	refresh, err := client.RefreshDailyRollups(ctx)
+	if err != nil {
+		t.Fatalf("failed to refresh rollups: %v", err)
+	}
+	if refresh.Since != "" || refresh.Rows != 3 {
+		t.Errorf("expected a full build of three user-days, got %+v", refresh)
+	}
+
+	stats, err := client.GetRollupStats(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("failed to get rollup stats: %v", err)
+	}
+	if stats.ExecutionRuns != 2 || stats.APIRequests != 2 || stats.APIResponses != 2 || stats.FunctionCalls != 1 ||
+		stats.AvgResponseTimeMs != 100 || stats.SuccessRate != 0.5 || stats.TotalTokens != 30 || stats.PromptTokens != 10 {
+		t.Errorf("expected the first user's totals across both days, got %+v", stats)
+	}
+	if stats.RefreshedAt.Sub(refresh.RefreshedAt).Abs() > time.Second {
+		t.Errorf("expected the stats to carry the refresh time %v, got %v", refresh.RefreshedAt, stats.RefreshedAt)
+	}
+
+	stats, err = client.GetRollupStats(ctx, "")
+	if err != nil {
+		t.Fatalf("failed to get rollup stats: %v", err)
+	}
+	if stats.ExecutionRuns != 3 || stats.AvgResponseTimeMs != 200 || stats.TotalTokens != 40 {
+		t.Errorf("expected every user's totals, got %+v", stats)
+	}
+
+	// Later refreshes only recompute recent days, so older days keep their totals once pruned
+	if _, err := database.Exec("DELETE FROM execution_runs WHERE id = 'run-1'"); err != nil {
+		t.Fatalf("failed to prune run: %v", err)
+	}
+	refresh, err = client.RefreshDailyRollups(ctx)
+	if err != nil {
+		t.Fatalf("failed to refresh rollups: %v", err)
+	}
+	if refresh.Since == "" || refresh.Rows != 0 {
+		t.Errorf("expected an incremental refresh of recent days only, got %+v", refresh)
+	}
+	if stats, _ := client.GetRollupStats(ctx, "user-1"); stats.ExecutionRuns != 2 {
+		t.Errorf("expected the pruned day to keep its totals, got %+v", stats)
+	}
+}
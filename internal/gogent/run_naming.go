@@ -0,0 +1,229 @@
+package gogent
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"gogent/internal/types"
+)
+
+// defaultRunNameTemplate names runs submitted without a name or template
+const defaultRunNameTemplate = "{suite}-{timestamp}"
+
+// defaultDuplicateWindow is how far back duplicates are matched when the workspace sets no window
+const defaultDuplicateWindow = 10 * time.Minute
+
+// runNameSeparators matches separators left doubled or dangling by empty placeholders
+var runNameSeparators = regexp.MustCompile(`([-_.])[-_.]+`)
+
+// DuplicateSubmissionError is returned when the duplicate policy rejects a submission
+type DuplicateSubmissionError struct {
+	ExistingRun *types.ExecutionRun
+}
+
+func (e *DuplicateSubmissionError) Error() string {
+	return fmt.Sprintf("identical submission already made as execution run %q (%s)", e.ExistingRun.Name, e.ExistingRun.ID)
+}
+
+// validateDuplicatePolicy checks that a duplicate policy is empty or known
+func validateDuplicatePolicy(policy string) error {
+	switch policy {
+	case "", types.DuplicatePolicyAllow, types.DuplicatePolicyReject, types.DuplicatePolicyMerge:
+		return nil
+	default:
+		return fmt.Errorf("unknown duplicate policy %q (use allow, reject or merge)", policy)
+	}
+}
+
+// DuplicateWindow returns how far back duplicate submissions are matched for a workspace
+func DuplicateWindow(settings *types.WorkspaceSettings) time.Duration {
+	if settings == nil || settings.DuplicateWindowSecs <= 0 {
+		return defaultDuplicateWindow
+	}
+	return time.Duration(settings.DuplicateWindowSecs) * time.Second
+}
+
+// ExpandRunName fills the {suite}, {timestamp}, {date} and {git_sha} placeholders of a name
+// pattern. The suite defaults to "run" and the git SHA is shortened to 7 characters.
+func ExpandRunName(pattern string, request *types.MultiExecutionRequest, now time.Time) string {
+	suite := request.Suite
+	if suite == "" {
+		suite = "run"
+	}
+	gitSHA := request.GitSHA
+	if len(gitSHA) > 7 {
+		gitSHA = gitSHA[:7]
+	}
+
+	name := strings.NewReplacer(
+		"{suite}", suite,
+		"{timestamp}", now.UTC().Format("20060102-150405"),
+		"{date}", now.UTC().Format("2006-01-02"),
+		"{git_sha}", gitSHA,
+	).Replace(pattern)
+
+	name = runNameSeparators.ReplaceAllString(name, "$1")
+	return strings.Trim(name, "-_. ")
+}
+
+// SubmissionContentHash hashes what a submission asks to run: prompt, context, configurations,
+// tools, run-wide options, suite and git SHA. Names, descriptions and API keys are ignored, so
+// resubmitting the same work under a new name still matches.
+func SubmissionContentHash(request *types.MultiExecutionRequest) string {
+	configs := make([]types.APIConfiguration, len(request.Configurations))
+	for i, config := range request.Configurations {
+		config.ID = ""
+		config.ExecutionRunID = ""
+		config.CreatedAt = time.Time{}
+		configs[i] = config
+	}
+
+	// encoding/json sorts map keys, so equal submissions always serialize identically
+	payload, _ := json.Marshal(map[string]interface{}{
+		"basePrompt":                 request.BasePrompt,
+		"context":                    request.Context,
+		"enableFunctionCalling":      request.EnableFunctionCalling,
+		"configurations":             configs,
+		"functionTools":              request.FunctionTools,
+		"comparisonConfig":           request.ComparisonConfig,
+		"functionInstruction":        request.FunctionInstruction,
+		"disableFunctionInstruction": request.DisableFunctionInstruction,
+		"safetyPolicy":               request.SafetyPolicy,
+		"deterministic":              request.Deterministic,
+		"seed":                       request.Seed,
+		"suite":                      request.Suite,
+		"gitSha":                     request.GitSHA,
+		"repetitions":                request.Repetitions,
+	})
+
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// PrepareSubmission applies the duplicate policy and names the run before it starts. With the
+// merge policy it returns the earlier identical run, which the caller should use instead of
+// starting a new one; with reject it returns a *DuplicateSubmissionError. Otherwise it expands
+// the run name (or name template) and makes it unique for the user.
+func (c *Client) PrepareSubmission(ctx context.Context, userID string, request *types.MultiExecutionRequest, window time.Duration) (*types.ExecutionRun, error) {
+	if err := validateDuplicatePolicy(request.DuplicatePolicy); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if request.DuplicatePolicy == types.DuplicatePolicyReject || request.DuplicatePolicy == types.DuplicatePolicyMerge {
+		existing, err := c.FindDuplicateExecutionRun(ctx, userID, SubmissionContentHash(request), now.Add(-window))
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			if request.DuplicatePolicy == types.DuplicatePolicyReject {
+				return nil, &DuplicateSubmissionError{ExistingRun: existing}
+			}
+			return existing, nil
+		}
+	}
+
+	pattern := request.ExecutionRunName
+	if pattern == "" {
+		pattern = request.NameTemplate
+	}
+	if pattern == "" {
+		pattern = defaultRunNameTemplate
+	}
+
+	name, err := c.UniqueExecutionRunName(ctx, userID, ExpandRunName(pattern, request, now))
+	if err != nil {
+		return nil, err
+	}
+	request.ExecutionRunName = name
+	return nil, nil
+}
+
+// FindDuplicateExecutionRun returns the user's most recent run with the content hash created
+// since the given time, or nil when there is none
+func (c *Client) FindDuplicateExecutionRun(ctx context.Context, userID, contentHash string, since time.Time) (*types.ExecutionRun, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	run := types.ExecutionRun{UserID: userID}
+	var description, status sql.NullString
+
+	err := c.db.QueryRowContext(ctx, `
+		SELECT id, name, description, enable_function_calling, status, created_at, updated_at
+		FROM execution_runs
+		WHERE user_id = ? AND content_hash = ? AND created_at >= ?
+		ORDER BY created_at DESC
+		LIMIT 1
+	`, userID, contentHash, since).Scan(&run.ID, &run.Name, &description, &run.EnableFunctionCalling,
+		&status, &run.CreatedAt, &run.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up duplicate submissions: %w", err)
+	}
+
+	run.Description = description.String
+	run.Status = status.String
+	return &run, nil
+}
+
+// UniqueExecutionRunName returns name, or name with the first free "-N" suffix when the user
+// already has a run with that name
+func (c *Client) UniqueExecutionRunName(ctx context.Context, userID, name string) (string, error) {
+	if c.db == nil {
+		return "", ErrNoDatabase
+	}
+	rows, err := c.db.QueryContext(ctx,
+		"SELECT name FROM execution_runs WHERE user_id = ? AND (name = ? OR name LIKE ?)",
+		userID, name, name+"-%",
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to check execution run names: %w", err)
+	}
+	defer rows.Close()
+
+	taken := make(map[string]bool)
+	for rows.Next() {
+		var existing string
+		if err := rows.Scan(&existing); err != nil {
+			return "", fmt.Errorf("failed to scan execution run name: %w", err)
+		}
+		taken[existing] = true
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("failed to check execution run names: %w", err)
+	}
+
+	if !taken[name] {
+		return name, nil
+	}
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s-%d", name, n)
+		if !taken[candidate] {
+			return candidate, nil
+		}
+	}
+}
+
+// recordContentHash stores a run's submission content hash for duplicate detection
+func (c *Client) recordContentHash(ctx context.Context, executionRunID, contentHash string) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	_, err := c.db.ExecContext(ctx,
+		"UPDATE execution_runs SET content_hash = ? WHERE id = ?",
+		contentHash, executionRunID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record content hash: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gogent/internal/testdb"
+	"gogent/internal/types"
+)
+
+// newRunNamingTestClient returns a client backed by an in-memory execution_runs table
+func newRunNamingTestClient(t *testing.T) *Client {
+	return &Client{db: testdb.Open(t)}
+}
+
+func insertTestExecutionRun(t *testing.T, client *Client, id, userID, name, contentHash string, createdAt time.Time) {
+	_, err := client.db.Exec(
+		"INSERT INTO execution_runs (id, user_id, name, status, content_hash, created_at, updated_at) VALUES (?, ?, ?, 'completed', ?, ?, ?)",
+		id, userID, name, nullableString(contentHash), createdAt, createdAt,
+	)
+	if err != nil {
+		t.Fatalf("failed to insert execution run: %v", err)
+	}
+}
+
+func TestExpandRunName(t *testing.T) {
+	now := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		pattern  string
+		request  types.MultiExecutionRequest
+		expected string
+	}{
+		{"default_template", defaultRunNameTemplate, types.MultiExecutionRequest{}, "run-20260304-050607"},
+		{"suite_and_sha", "{suite}-{git_sha}-{date}", types.MultiExecutionRequest{Suite: "nightly", GitSHA: "0123456789abcdef"}, "nightly-0123456-2026-03-04"},
+		{"empty_sha_collapses", "{suite}-{git_sha}-{timestamp}", types.MultiExecutionRequest{Suite: "smoke"}, "smoke-20260304-050607"},
+		{"trailing_empty_sha", "{suite}_{git_sha}", types.MultiExecutionRequest{Suite: "smoke"}, "smoke"},
+		{"plain_name", "My experiment", types.MultiExecutionRequest{Suite: "ignored"}, "My experiment"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExpandRunName(tt.pattern, &tt.request, now); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSubmissionContentHash(t *testing.T) {
+	base := func() *types.MultiExecutionRequest {
+		return &types.MultiExecutionRequest{
+			ExecutionRunName: "first",
+			BasePrompt:       "Summarize the report",
+			Configurations:   []types.APIConfiguration{{VariationName: "precise", ModelName: "gemini-1.5-flash"}},
+			Suite:            "nightly",
+		}
+	}
+	hash := SubmissionContentHash(base())
+
+	renamed := base()
+	renamed.ExecutionRunName = "second"
+	renamed.Description = "same work, new name"
+	renamed.SessionApiKeys = &types.SessionApiKeys{GeminiApiKey: "secret"}
+	renamed.Configurations[0].ID = "config-1"
+	renamed.DuplicatePolicy = types.DuplicatePolicyReject
+	if SubmissionContentHash(renamed) != hash {
+		t.Error("expected names, keys and stored IDs not to change the hash")
+	}
+
+	changedPrompt := base()
+	changedPrompt.BasePrompt = "Summarize the appendix"
+	if SubmissionContentHash(changedPrompt) == hash {
+		t.Error("expected a different prompt to change the hash")
+	}
+
+	changedSHA := base()
+	changedSHA.GitSHA = "abcdef0"
+	if SubmissionContentHash(changedSHA) == hash {
+		t.Error("expected a different git SHA to change the hash")
+	}
+}
+
+func TestUniqueExecutionRunName(t *testing.T) {
+	client := newRunNamingTestClient(t)
+	ctx := context.Background()
+
+	now := time.Now()
+	insertTestExecutionRun(t, client, "run-1", "user-1", "nightly", "", now)
+	insertTestExecutionRun(t, client, "run-2", "user-1", "nightly-2", "", now)
+	insertTestExecutionRun(t, client, "run-3", "user-2", "weekly", "", now)
+
+	tests := []struct {
+		userID   string
+		name     string
+		expected string
+	}{
+		{"user-1", "nightly", "nightly-3"},
+		{"user-1", "weekly", "weekly"},
+		{"user-2", "nightly", "nightly"},
+		{"user-2", "weekly", "weekly-2"},
+	}
+
+	for _, tt := range tests {
+		got, err := client.UniqueExecutionRunName(ctx, tt.userID, tt.name)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != tt.expected {
+			t.Errorf("%s/%s: expected %q, got %q", tt.userID, tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestPrepareSubmission(t *testing.T) {
+	client := newRunNamingTestClient(t)
+	ctx := context.Background()
+
+	newRequest := func(policy string) *types.MultiExecutionRequest {
+		return &types.MultiExecutionRequest{
+			BasePrompt:      "Summarize the report",
+			Configurations:  []types.APIConfiguration{{VariationName: "precise", ModelName: "gemini-1.5-flash"}},
+			NameTemplate:    "{suite}-{git_sha}",
+			Suite:           "nightly",
+			GitSHA:          "abcdef0123",
+			DuplicatePolicy: policy,
+		}
+	}
+	hash := SubmissionContentHash(newRequest(""))
+	insertTestExecutionRun(t, client, "run-1", "user-1", "nightly-abcdef0", hash, time.Now().Add(-time.Minute))
+
+	t.Run("allow_names_uniquely", func(t *testing.T) {
+		request := newRequest(types.DuplicatePolicyAllow)
+		existing, err := client.PrepareSubmission(ctx, "user-1", request, time.Hour)
+		if err != nil || existing != nil {
+			t.Fatalf("expected a new run, got %+v, %v", existing, err)
+		}
+		if request.ExecutionRunName != "nightly-abcdef0-2" {
+			t.Errorf("expected a suffixed name, got %q", request.ExecutionRunName)
+		}
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		_, err := client.PrepareSubmission(ctx, "user-1", newRequest(types.DuplicatePolicyReject), time.Hour)
+		var duplicate *DuplicateSubmissionError
+		if !errors.As(err, &duplicate) || duplicate.ExistingRun.ID != "run-1" {
+			t.Errorf("expected a duplicate submission error for run-1, got %v", err)
+		}
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		existing, err := client.PrepareSubmission(ctx, "user-1", newRequest(types.DuplicatePolicyMerge), time.Hour)
+		if err != nil || existing == nil || existing.ID != "run-1" {
+			t.Errorf("expected run-1 to be returned, got %+v, %v", existing, err)
+		}
+	})
+
+	t.Run("outside_window", func(t *testing.T) {
+		existing, err := client.PrepareSubmission(ctx, "user-1", newRequest(types.DuplicatePolicyReject), time.Second)
+		if err != nil || existing != nil {
+			t.Errorf("expected the old run to be ignored, got %+v, %v", existing, err)
+		}
+	})
+
+	t.Run("other_user", func(t *testing.T) {
+		existing, err := client.PrepareSubmission(ctx, "user-2", newRequest(types.DuplicatePolicyReject), time.Hour)
+		if err != nil || existing != nil {
+			t.Errorf("expected other users' runs to be ignored, got %+v, %v", existing, err)
+		}
+	})
+
+	t.Run("unknown_policy", func(t *testing.T) {
+		if _, err := client.PrepareSubmission(ctx, "user-1", newRequest("skip"), time.Hour); err == nil {
+			t.Error("expected an error for an unknown policy")
+		}
+	})
+}
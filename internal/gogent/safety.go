@@ -0,0 +1,183 @@
+package gogent
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gogent/internal/gemini"
+	"gogent/internal/types"
+)
+
+// geminiSafetyCategories maps normalized categories to Gemini harm categories
+var geminiSafetyCategories = map[string]string{
+	types.SafetyCategoryHarassment:       "HARM_CATEGORY_HARASSMENT",
+	types.SafetyCategoryHateSpeech:       "HARM_CATEGORY_HATE_SPEECH",
+	types.SafetyCategorySexuallyExplicit: "HARM_CATEGORY_SEXUALLY_EXPLICIT",
+	types.SafetyCategoryDangerousContent: "HARM_CATEGORY_DANGEROUS_CONTENT",
+}
+
+// geminiSafetyThresholds maps normalized thresholds to Gemini block thresholds
+var geminiSafetyThresholds = map[string]string{
+	types.SafetyThresholdOff:    "BLOCK_NONE",
+	types.SafetyThresholdHigh:   "BLOCK_ONLY_HIGH",
+	types.SafetyThresholdMedium: "BLOCK_MEDIUM_AND_ABOVE",
+	types.SafetyThresholdLow:    "BLOCK_LOW_AND_ABOVE",
+}
+
+// openAIModerationCategories maps normalized categories to OpenAI moderation categories
+var openAIModerationCategories = map[string][]string{
+	types.SafetyCategoryHarassment:       {"harassment", "harassment/threatening"},
+	types.SafetyCategoryHateSpeech:       {"hate", "hate/threatening"},
+	types.SafetyCategorySexuallyExplicit: {"sexual", "sexual/minors"},
+	types.SafetyCategoryDangerousContent: {"violence", "self-harm", "illicit"},
+}
+
+// openAIModerationScores maps normalized thresholds to the moderation score at which a response is blocked
+var openAIModerationScores = map[string]float64{
+	types.SafetyThresholdHigh:   0.8,
+	types.SafetyThresholdMedium: 0.5,
+	types.SafetyThresholdLow:    0.2,
+}
+
+// anthropicSafetyGuidance describes each normalized threshold for a system prompt instruction
+var anthropicSafetyGuidance = map[string]string{
+	types.SafetyThresholdOff:    "may be discussed without restriction",
+	types.SafetyThresholdHigh:   "should only be refused when clearly harmful",
+	types.SafetyThresholdMedium: "should be refused when likely harmful",
+	types.SafetyThresholdLow:    "should be refused whenever potentially harmful",
+}
+
+// ValidateSafetyPolicy checks that a policy only uses known categories and thresholds
+func ValidateSafetyPolicy(policy *types.SafetyPolicy) error {
+	if policy == nil {
+		return nil
+	}
+	for category, threshold := range policy.Thresholds {
+		if _, ok := geminiSafetyCategories[category]; !ok {
+			return fmt.Errorf("unknown safety category: %s", category)
+		}
+		if _, ok := geminiSafetyThresholds[threshold]; !ok {
+			return fmt.Errorf("unknown safety threshold %q for category %s", threshold, category)
+		}
+	}
+	return nil
+}
+
+// ValidateRequestSafetyPolicies checks the run and configuration safety policies of a request
+// and that each configuration's provider can express the policy that applies to it
+func ValidateRequestSafetyPolicies(request *types.MultiExecutionRequest) error {
+	if err := ValidateSafetyPolicy(request.SafetyPolicy); err != nil {
+		return fmt.Errorf("invalid run safety policy: %w", err)
+	}
+	for _, config := range request.Configurations {
+		policy := config.SafetyPolicy
+		if policy == nil {
+			policy = request.SafetyPolicy
+		}
+		if _, err := TranslateSafetyPolicy(policy, types.ProviderForConfiguration(&config)); err != nil {
+			return fmt.Errorf("invalid safety policy for configuration %q: %w", config.VariationName, err)
+		}
+	}
+	return nil
+}
+
+// TranslateSafetyPolicy converts a normalized policy into a provider's native safety settings:
+//   - Gemini: harm category -> block threshold, sent as the request's safetySettings
+//   - OpenAI: {"moderation": {category: score}}, since the API has no request-level safety
+//     controls and responses are screened with the moderation endpoint instead
+//   - Anthropic and Ollama: {"systemInstruction": "..."}, since neither API has safety parameters
+//     and the posture is expressed as a system prompt instruction
+func TranslateSafetyPolicy(policy *types.SafetyPolicy, provider string) (map[string]interface{}, error) {
+	if err := ValidateSafetyPolicy(policy); err != nil {
+		return nil, err
+	}
+	if policy == nil || len(policy.Thresholds) == 0 {
+		return nil, nil
+	}
+
+	switch provider {
+	case types.ProviderGemini:
+		settings := make(map[string]interface{}, len(policy.Thresholds))
+		for category, threshold := range policy.Thresholds {
+			settings[geminiSafetyCategories[category]] = geminiSafetyThresholds[threshold]
+		}
+		return settings, nil
+
+	case types.ProviderOpenAI:
+		moderation := make(map[string]interface{})
+		for category, threshold := range policy.Thresholds {
+			score, ok := openAIModerationScores[threshold]
+			if !ok {
+				continue // "off" means never block
+			}
+			for _, moderationCategory := range openAIModerationCategories[category] {
+				moderation[moderationCategory] = score
+			}
+		}
+		return map[string]interface{}{"moderation": moderation}, nil
+
+	case types.ProviderAnthropic, types.ProviderOllama:
+		categories := make([]string, 0, len(policy.Thresholds))
+		for category := range policy.Thresholds {
+			categories = append(categories, category)
+		}
+		sort.Strings(categories)
+
+		rules := make([]string, 0, len(categories))
+		for _, category := range categories {
+			rules = append(rules, fmt.Sprintf("- Content involving %s %s.",
+				strings.ReplaceAll(category, "_", " "), anthropicSafetyGuidance[policy.Thresholds[category]]))
+		}
+		return map[string]interface{}{
+			"systemInstruction": "Follow this content safety policy:\n" + strings.Join(rules, "\n"),
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("no safety translation for provider %q", provider)
+	}
+}
+
+// applySafetyPolicy resolves a configuration's safety policy (its own, else the run's) into native
+// SafetySettings for its provider. Native settings set explicitly on the configuration win.
+func applySafetyPolicy(config *types.APIConfiguration, runPolicy *types.SafetyPolicy) error {
+	policy := config.SafetyPolicy
+	if policy == nil {
+		policy = runPolicy
+	}
+	if policy == nil {
+		return nil
+	}
+
+	native, err := TranslateSafetyPolicy(policy, types.ProviderForConfiguration(config))
+	if err != nil {
+		return fmt.Errorf("configuration %q: %w", config.VariationName, err)
+	}
+	if native == nil {
+		return nil
+	}
+
+	for key, value := range config.SafetySettings {
+		native[key] = value
+	}
+	config.SafetyPolicy = policy
+	config.SafetySettings = native
+	return nil
+}
+
+// geminiSafetySettings converts category -> threshold settings into the Gemini request format
+func geminiSafetySettings(settings map[string]interface{}) []gemini.SafetySetting {
+	categories := make([]string, 0, len(settings))
+	for category := range settings {
+		if strings.HasPrefix(category, "HARM_CATEGORY_") {
+			categories = append(categories, category)
+		}
+	}
+	sort.Strings(categories)
+
+	result := make([]gemini.SafetySetting, 0, len(categories))
+	for _, category := range categories {
+		result = append(result, gemini.SafetySetting{Category: category, Threshold: fmt.Sprint(settings[category])})
+	}
+	return result
+}
@@ -0,0 +1,151 @@
+package gogent
+
+import (
+	"strings"
+	"testing"
+
+	"gogent/internal/types"
+)
+
+func TestTranslateSafetyPolicy(t *testing.T) {
+	policy := &types.SafetyPolicy{Thresholds: map[string]string{
+		types.SafetyCategoryHarassment:       types.SafetyThresholdMedium,
+		types.SafetyCategoryDangerousContent: types.SafetyThresholdOff,
+	}}
+
+	t.Run("gemini", func(t *testing.T) {
+		settings, err := TranslateSafetyPolicy(policy, types.ProviderGemini)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if settings["HARM_CATEGORY_HARASSMENT"] != "BLOCK_MEDIUM_AND_ABOVE" {
+			t.Errorf("expected harassment to block medium and above, got %v", settings["HARM_CATEGORY_HARASSMENT"])
+		}
+		if settings["HARM_CATEGORY_DANGEROUS_CONTENT"] != "BLOCK_NONE" {
+			t.Errorf("expected dangerous content to be unblocked, got %v", settings["HARM_CATEGORY_DANGEROUS_CONTENT"])
+		}
+	})
+
+	t.Run("openai", func(t *testing.T) {
+		settings, err := TranslateSafetyPolicy(policy, types.ProviderOpenAI)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		moderation, ok := settings["moderation"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("expected moderation thresholds, got %v", settings)
+		}
+		if moderation["harassment"] != 0.5 || moderation["harassment/threatening"] != 0.5 {
+			t.Errorf("expected harassment moderation score 0.5, got %v", moderation)
+		}
+		if _, exists := moderation["violence"]; exists {
+			t.Errorf("expected no moderation threshold for an \"off\" category, got %v", moderation)
+		}
+	})
+
+	t.Run("anthropic", func(t *testing.T) {
+		settings, err := TranslateSafetyPolicy(policy, types.ProviderAnthropic)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		instruction, _ := settings["systemInstruction"].(string)
+		if !strings.Contains(instruction, "harassment should be refused when likely harmful") {
+			t.Errorf("expected harassment guidance, got %q", instruction)
+		}
+		if !strings.Contains(instruction, "dangerous content may be discussed without restriction") {
+			t.Errorf("expected dangerous content guidance, got %q", instruction)
+		}
+	})
+
+	t.Run("unknown_provider", func(t *testing.T) {
+		if _, err := TranslateSafetyPolicy(policy, ""); err == nil {
+			t.Error("expected an error for an unknown provider")
+		}
+	})
+
+	t.Run("invalid_policy", func(t *testing.T) {
+		invalid := []*types.SafetyPolicy{
+			{Thresholds: map[string]string{"profanity": types.SafetyThresholdLow}},
+			{Thresholds: map[string]string{types.SafetyCategoryHarassment: "BLOCK_ONLY_HIGH"}},
+		}
+		for _, p := range invalid {
+			if _, err := TranslateSafetyPolicy(p, types.ProviderGemini); err == nil {
+				t.Errorf("expected an error for %v", p.Thresholds)
+			}
+		}
+	})
+}
+
+func TestApplySafetyPolicy(t *testing.T) {
+	runPolicy := &types.SafetyPolicy{Thresholds: map[string]string{
+		types.SafetyCategoryHateSpeech: types.SafetyThresholdLow,
+	}}
+
+	t.Run("run_policy_is_portable_across_providers", func(t *testing.T) {
+		gemini := &types.APIConfiguration{VariationName: "gemini", ModelName: "gemini-1.5-flash"}
+		openai := &types.APIConfiguration{VariationName: "openai", ModelName: "gpt-4o"}
+
+		if err := applySafetyPolicy(gemini, runPolicy); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := applySafetyPolicy(openai, runPolicy); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if gemini.SafetySettings["HARM_CATEGORY_HATE_SPEECH"] != "BLOCK_LOW_AND_ABOVE" {
+			t.Errorf("expected Gemini hate speech threshold, got %v", gemini.SafetySettings)
+		}
+		if moderation, _ := openai.SafetySettings["moderation"].(map[string]interface{}); moderation["hate"] != 0.2 {
+			t.Errorf("expected OpenAI hate moderation score, got %v", openai.SafetySettings)
+		}
+		if gemini.SafetyPolicy != runPolicy {
+			t.Error("expected the applied policy to be recorded on the configuration")
+		}
+	})
+
+	t.Run("configuration_policy_and_native_settings_win", func(t *testing.T) {
+		config := &types.APIConfiguration{
+			VariationName: "strict",
+			ModelName:     "gemini-1.5-pro",
+			SafetyPolicy: &types.SafetyPolicy{Thresholds: map[string]string{
+				types.SafetyCategoryHarassment: types.SafetyThresholdLow,
+			}},
+			SafetySettings: map[string]interface{}{"HARM_CATEGORY_HARASSMENT": "BLOCK_ONLY_HIGH"},
+		}
+
+		if err := applySafetyPolicy(config, runPolicy); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, exists := config.SafetySettings["HARM_CATEGORY_HATE_SPEECH"]; exists {
+			t.Error("expected the configuration policy to replace the run policy")
+		}
+		if config.SafetySettings["HARM_CATEGORY_HARASSMENT"] != "BLOCK_ONLY_HIGH" {
+			t.Errorf("expected explicit native setting to win, got %v", config.SafetySettings)
+		}
+	})
+
+	t.Run("no_policy", func(t *testing.T) {
+		config := &types.APIConfiguration{VariationName: "plain", ModelName: "gemini-1.5-flash"}
+		if err := applySafetyPolicy(config, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if config.SafetySettings != nil {
+			t.Errorf("expected no safety settings, got %v", config.SafetySettings)
+		}
+	})
+}
+
+func TestGeminiSafetySettings(t *testing.T) {
+	settings := geminiSafetySettings(map[string]interface{}{
+		"HARM_CATEGORY_HATE_SPEECH": "BLOCK_LOW_AND_ABOVE",
+		"HARM_CATEGORY_HARASSMENT":  "BLOCK_NONE",
+		"moderation":                map[string]interface{}{},
+	})
+
+	if len(settings) != 2 {
+		t.Fatalf("expected 2 Gemini safety settings, got %d", len(settings))
+	}
+	if settings[0].Category != "HARM_CATEGORY_HARASSMENT" || settings[0].Threshold != "BLOCK_NONE" {
+		t.Errorf("unexpected first setting: %v", settings[0])
+	}
+}
@@ -0,0 +1,182 @@
+package gogent
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"gogent/internal/types"
+)
+
+const (
+	// DefaultSearchLimit is how many matches a search returns when no limit is given
+	DefaultSearchLimit = 10
+	// MaxSearchLimit caps how many matches a search may return
+	MaxSearchLimit = 50
+	// searchIndexBatch is how many unindexed responses a search embeds before matching
+	searchIndexBatch = 100
+)
+
+// SearchExecutions returns the user's past prompts and responses most similar in meaning to query,
+// best match first. Responses without a stored embedding are indexed first, newest first, a batch
+// at a time.
+func (c *Client) SearchExecutions(ctx context.Context, userID, query string, limit int) ([]types.SearchResult, error) {
+	return c.searchExecutions(ctx, userID, query, limit, c.callEmbeddingAPI)
+}
+
+// searchExecutions ranks stored response embeddings by cosine similarity to the query's embedding
+func (c *Client) searchExecutions(ctx context.Context, userID, query string, limit int, embed embedFunc) ([]types.SearchResult, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("search query must not be empty")
+	}
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	limit = min(limit, MaxSearchLimit)
+
+	if err := c.indexResponseEmbeddings(ctx, userID, embed); err != nil {
+		log.Printf("⚠️ Warning: failed to index responses for search: %v", err)
+	}
+
+	queryEmbedding, _, err := c.cachedEmbedding(ctx, userID, defaultEmbeddingModel, query, embed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed search query: %w", err)
+	}
+
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT re.response_id, re.execution_run_id, er.name, re.configuration_id, re.variation_name,
+		       rq.prompt, ar.response_text, re.embedding, ar.created_at
+		FROM response_embeddings re
+		JOIN api_responses ar ON ar.id = re.response_id
+		JOIN api_requests rq ON rq.id = ar.request_id
+		JOIN execution_runs er ON er.id = re.execution_run_id
+		WHERE re.user_id = ? AND re.model = ?
+	`, userID, defaultEmbeddingModel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get response embeddings: %w", err)
+	}
+	defer rows.Close()
+
+	var results []types.SearchResult
+	var embeddings [][]float32
+	for rows.Next() {
+		var result types.SearchResult
+		var prompt sql.NullString
+		var embeddingJSON string
+		if err := rows.Scan(&result.ResponseID, &result.ExecutionRunID, &result.ExecutionRunName, &result.ConfigurationID,
+			&result.VariationName, &prompt, &result.ResponseText, &embeddingJSON, &result.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan response embedding: %w", err)
+		}
+		var embedding []float32
+		if err := types.FromJSON(embeddingJSON, &embedding); err != nil {
+			return nil, fmt.Errorf("failed to parse response embedding: %w", err)
+		}
+		result.Prompt = prompt.String
+		results = append(results, result)
+		embeddings = append(embeddings, embedding)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate response embeddings: %w", err)
+	}
+
+	// Prompts are shared by a run's variations, so each distinct prompt is embedded once
+	promptEmbeddings := make(map[string][]float32)
+	for i := range results {
+		results[i].Score = CosineSimilarity(queryEmbedding, embeddings[i])
+		results[i].MatchedOn = "response"
+
+		prompt := results[i].Prompt
+		if strings.TrimSpace(prompt) == "" {
+			continue
+		}
+		promptEmbedding, ok := promptEmbeddings[prompt]
+		if !ok {
+			promptEmbedding, _, err = c.cachedEmbedding(ctx, userID, defaultEmbeddingModel, prompt, embed)
+			if err != nil {
+				log.Printf("⚠️ Warning: failed to embed prompt of run %s: %v", results[i].ExecutionRunID, err)
+			}
+			promptEmbeddings[prompt] = promptEmbedding
+		}
+		if score := CosineSimilarity(queryEmbedding, promptEmbedding); score > results[i].Score {
+			results[i].Score = score
+			results[i].MatchedOn = "prompt"
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].CreatedAt.After(results[j].CreatedAt)
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// indexResponseEmbeddings embeds the user's newest successful responses that have no stored embedding yet
+func (c *Client) indexResponseEmbeddings(ctx context.Context, userID string, embed embedFunc) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT ar.id, rq.execution_run_id, rq.configuration_id, ac.variation_name, ar.response_text
+		FROM api_responses ar
+		JOIN api_requests rq ON rq.id = ar.request_id
+		JOIN api_configurations ac ON ac.id = rq.configuration_id
+		LEFT JOIN response_embeddings re ON re.response_id = ar.id AND re.model = ?
+		WHERE ar.user_id = ? AND ar.response_status = ? AND ar.response_text <> '' AND re.response_id IS NULL
+		ORDER BY ar.created_at DESC
+		LIMIT ?
+	`, defaultEmbeddingModel, userID, string(types.ResponseStatusSuccess), searchIndexBatch)
+	if err != nil {
+		return fmt.Errorf("failed to get unindexed responses: %w", err)
+	}
+
+	type unindexed struct {
+		embedding types.ResponseEmbedding
+		text      string
+	}
+	var pending []unindexed
+	for rows.Next() {
+		var u unindexed
+		if err := rows.Scan(&u.embedding.ResponseID, &u.embedding.ExecutionRunID, &u.embedding.ConfigurationID,
+			&u.embedding.VariationName, &u.text); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan unindexed response: %w", err)
+		}
+		pending = append(pending, u)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate unindexed responses: %w", err)
+	}
+
+	// Keep the embeddings computed before a failure so the next search resumes after them
+	var embeddings []types.ResponseEmbedding
+	var embedErr error
+	for _, u := range pending {
+		embedding, _, err := c.cachedEmbedding(ctx, userID, defaultEmbeddingModel, u.text, embed)
+		if err != nil {
+			embedErr = err
+			break
+		}
+		u.embedding.Model = defaultEmbeddingModel
+		u.embedding.Embedding = embedding
+		u.embedding.CreatedAt = time.Now()
+		embeddings = append(embeddings, u.embedding)
+	}
+	if err := c.storeResponseEmbeddings(ctx, userID, embeddings); err != nil {
+		return err
+	}
+	return embedErr
+}
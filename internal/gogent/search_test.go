@@ -0,0 +1,71 @@
+package gogent
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// newSearchTestClient returns an embedding test client with the run, request and response tables
+// search joins, holding one run per prompt and response pair
+func newSearchTestClient(t *testing.T, runs map[string][2]string) *Client {
+	client := newEmbeddingTestClient(t)
+
+	created := time.Now().Add(-time.Hour)
+	for id, run := range runs {
+		created = created.Add(time.Minute)
+		statements := []struct {
+			query string
+			args  []interface{}
+		}{
+			{"INSERT INTO execution_runs (id, user_id, name) VALUES (?, 'user-1', ?)", []interface{}{id, "Run " + id}},
+			{"INSERT INTO api_configurations (id, variation_name) VALUES (?, 'v1')", []interface{}{"config-" + id}},
+			{"INSERT INTO api_requests (id, execution_run_id, configuration_id, prompt) VALUES (?, ?, ?, ?)", []interface{}{"request-" + id, id, "config-" + id, run[0]}},
+			{"INSERT INTO api_responses (id, user_id, request_id, response_status, response_text, created_at) VALUES (?, 'user-1', ?, 'success', ?, ?)", []interface{}{"response-" + id, "request-" + id, run[1], created}},
+		}
+		for _, statement := range statements {
+			if _, err := client.db.Exec(statement.query, statement.args...); err != nil {
+				t.Fatalf("failed to insert test run: %v", err)
+			}
+		}
+	}
+	return client
+}
+
+func TestSearchExecutions(t *testing.T) {
+	client := newSearchTestClient(t, map[string][2]string{
+		"oauth":   {"Explain how OAuth authorization codes work", "The client redirects the user to the authorization server, which returns a code."},
+		"recipe":  {"Give me a pancake recipe", "Mix flour, eggs and milk, then fry the batter."},
+		"weather": {"Write a haiku", "Rain taps the window, clouds drift over quiet hills."},
+	})
+	ctx := context.Background()
+	embedder := &countingEmbedder{}
+
+	results, err := client.searchExecutions(ctx, "user-1", "how does OAuth authorization work", 2, embedder.embed)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 || results[0].ExecutionRunID != "oauth" || results[0].MatchedOn != "prompt" {
+		t.Fatalf("expected the OAuth run's prompt to match best, got %+v", results)
+	}
+	if results[0].ExecutionRunName != "Run oauth" || results[0].VariationName != "v1" || results[0].Score <= results[1].Score {
+		t.Errorf("expected the run's details and a higher score than the runner-up, got %+v", results)
+	}
+
+	// The first search indexed every response; later searches only embed the new query
+	stored, _ := client.GetResponseEmbeddings(ctx, "user-1", "recipe")
+	if len(stored) != 1 {
+		t.Errorf("expected the recipe response to be indexed, got %d embeddings", len(stored))
+	}
+	calls := embedder.calls
+	if results, _ := client.searchExecutions(ctx, "user-1", "pancake batter with eggs", 0, embedder.embed); len(results) != 3 || results[0].ExecutionRunID != "recipe" {
+		t.Errorf("expected every run ranked with the recipe first, got %+v", results)
+	}
+	if embedder.calls != calls+1 {
+		t.Errorf("expected only the new query to be embedded, got %d new calls", embedder.calls-calls)
+	}
+
+	if _, err := client.searchExecutions(ctx, "user-1", "   ", 0, embedder.embed); err == nil {
+		t.Errorf("expected an empty query to be rejected")
+	}
+}
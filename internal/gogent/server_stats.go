@@ -0,0 +1,182 @@
+package gogent
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"gogent/internal/types"
+)
+
+const (
+	// defaultServerStatsDays is how many days of runs and responses server stats cover by default
+	defaultServerStatsDays = 30
+	// maxServerStatsDays caps the days server stats cover
+	maxServerStatsDays = 366
+	// topTokenConsumers is how many users server stats rank by token usage
+	topTokenConsumers = 10
+)
+
+// GetServerStats reports figures across all users for the last days days (30 when days is not
+// positive, at most 366): the number of users, runs per day, error rates by provider, the users
+// using the most tokens and the size of each table. Each figure is one aggregate query over
+// indexed created_at columns. Table sizes come from information_schema and are left empty when
+// the database doesn't have it.
+func (c *Client) GetServerStats(ctx context.Context, days int) (*types.ServerStats, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	if days <= 0 {
+		days = defaultServerStatsDays
+	}
+	days = min(days, maxServerStatsDays)
+	now := time.Now().UTC()
+	stats := &types.ServerStats{From: time.Date(now.Year(), now.Month(), now.Day()-days+1, 0, 0, 0, 0, time.UTC)}
+
+	if err := c.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users").Scan(&stats.TotalUsers); err != nil {
+		return nil, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	var err error
+	if stats.RunsPerDay, err = c.runsPerDay(ctx, stats.From); err != nil {
+		return nil, err
+	}
+	if stats.ProviderErrors, err = c.providerErrorRates(ctx, stats.From); err != nil {
+		return nil, err
+	}
+	if stats.TopTokenConsumers, err = c.topTokenConsumers(ctx, stats.From, topTokenConsumers); err != nil {
+		return nil, err
+	}
+	if stats.TableSizes, err = c.tableSizes(ctx); err != nil {
+		log.Printf("⚠️ Warning: table sizes are unavailable: %v", err)
+		stats.TableSizes = []types.TableSize{}
+	}
+	return stats, nil
+}
+
+// runsPerDay counts the runs created and failed on each day since from
+func (c *Client) runsPerDay(ctx context.Context, from time.Time) ([]types.DailyRuns, error) {
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT CAST(DATE(created_at) AS CHAR) AS day, COUNT(*), COALESCE(SUM(CASE WHEN status = 'failed' THEN 1 ELSE 0 END), 0)
+		FROM execution_runs
+		WHERE created_at >= ?
+		GROUP BY day
+		ORDER BY day ASC`, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count runs per day: %w", err)
+	}
+	defer rows.Close()
+
+	days := []types.DailyRuns{}
+	for rows.Next() {
+		var day types.DailyRuns
+		if err := rows.Scan(&day.Day, &day.Runs, &day.Failed); err != nil {
+			return nil, fmt.Errorf("failed to scan runs per day: %w", err)
+		}
+		days = append(days, day)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate runs per day: %w", err)
+	}
+	return days, nil
+}
+
+// providerErrorRates computes the share of each provider's responses since from that were errors,
+// highest first
+func (c *Client) providerErrorRates(ctx context.Context, from time.Time) ([]types.ProviderErrorRate, error) {
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT COALESCE(c.provider, 'unknown') AS provider, COUNT(*),
+			COALESCE(SUM(CASE WHEN resp.response_status = ? THEN 1 ELSE 0 END), 0)
+		FROM api_responses resp
+		JOIN api_requests q ON q.id = resp.request_id
+		JOIN api_configurations c ON c.id = q.configuration_id
+		WHERE resp.created_at >= ?
+		GROUP BY provider`, types.ResponseStatusError, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count errors by provider: %w", err)
+	}
+	defer rows.Close()
+
+	rates := []types.ProviderErrorRate{}
+	for rows.Next() {
+		var rate types.ProviderErrorRate
+		if err := rows.Scan(&rate.Provider, &rate.Responses, &rate.Errors); err != nil {
+			return nil, fmt.Errorf("failed to scan errors by provider: %w", err)
+		}
+		if rate.Responses > 0 {
+			rate.ErrorRate = float64(rate.Errors) / float64(rate.Responses)
+		}
+		rates = append(rates, rate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate errors by provider: %w", err)
+	}
+
+	slices.SortFunc(rates, func(a, b types.ProviderErrorRate) int {
+		if a.ErrorRate != b.ErrorRate {
+			if a.ErrorRate > b.ErrorRate {
+				return -1
+			}
+			return 1
+		}
+		return b.Responses - a.Responses
+	})
+	return rates, nil
+}
+
+// topTokenConsumers ranks the limit users whose responses since from used the most tokens
+func (c *Client) topTokenConsumers(ctx context.Context, from time.Time, limit int) ([]types.TokenConsumer, error) {
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT resp.user_id, COALESCE(u.username, ''), COUNT(*), COALESCE(SUM(resp.total_tokens), 0) AS tokens
+		FROM api_responses resp
+		LEFT JOIN users u ON u.id = resp.user_id
+		WHERE resp.created_at >= ?
+		GROUP BY resp.user_id, u.username
+		ORDER BY tokens DESC, resp.user_id ASC
+		LIMIT ?`, from, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rank token consumers: %w", err)
+	}
+	defer rows.Close()
+
+	consumers := []types.TokenConsumer{}
+	for rows.Next() {
+		var consumer types.TokenConsumer
+		if err := rows.Scan(&consumer.UserID, &consumer.Username, &consumer.Responses, &consumer.TotalTokens); err != nil {
+			return nil, fmt.Errorf("failed to scan token consumers: %w", err)
+		}
+		consumers = append(consumers, consumer)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate token consumers: %w", err)
+	}
+	return consumers, nil
+}
+
+// tableSizes reads MySQL's estimate of each table's rows and storage, largest first
+func (c *Client) tableSizes(ctx context.Context) ([]types.TableSize, error) {
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT TABLE_NAME, COALESCE(TABLE_ROWS, 0), COALESCE(DATA_LENGTH, 0), COALESCE(INDEX_LENGTH, 0)
+		FROM information_schema.TABLES
+		WHERE TABLE_SCHEMA = DATABASE()
+		ORDER BY COALESCE(DATA_LENGTH, 0) + COALESCE(INDEX_LENGTH, 0) DESC, TABLE_NAME ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read table sizes: %w", err)
+	}
+	defer rows.Close()
+
+	sizes := []types.TableSize{}
+	for rows.Next() {
+		var size types.TableSize
+		if err := rows.Scan(&size.Name, &size.Rows, &size.DataBytes, &size.IndexBytes); err != nil {
+			return nil, fmt.Errorf("failed to scan table sizes: %w", err)
+		}
+		sizes = append(sizes, size)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate table sizes: %w", err)
+	}
+	return sizes, nil
+}
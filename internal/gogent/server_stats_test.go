@@ -0,0 +1,89 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gogent/internal/testdb"
+)
+
+func TestGetServerStats(t *testing.T) {
+	database := testdb.Open(t)
+	client := &Client{db: database}
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	yesterday, old := today.AddDate(0, 0, -1), today.AddDate(0, 0, -60)
+	_, err := database.Exec(`
+		INSERT INTO users (id, username) VALUES ('user-1', 'ada'), ('user-2', 'grace'), ('user-3', 'linus');
+		INSERT INTO api_configurations (id, provider) VALUES ('config-gemini', 'gemini'), ('config-ollama', 'ollama'), ('config-other', NULL);
+		INSERT INTO api_requests (id, configuration_id) VALUES ('request-gemini', 'config-gemini'), ('request-ollama', 'config-ollama'), ('request-other', 'config-other');`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+	for _, run := range []struct {
+		id, status string
+		createdAt  time.Time
+	}{
+		{"run-1", "completed", today},
+		{"run-2", "failed", today},
+		{"run-3", "completed", yesterday},
+		{"run-4", "failed", old},
+	} {
+		if _, err := database.Exec(`INSERT INTO execution_runs (id, status, created_at) VALUES (?, ?, ?)`, run.id, run.status, run.createdAt); err != nil {
+			t.Fatalf("failed to insert run: %v", err)
+		}
+	}
+	for _, response := range []struct {
+		id, userID, requestID, status string
+		tokens                        int
+		createdAt                     time.Time
+	}{
+		{"r1", "user-1", "request-gemini", "success", 100, today},
+		{"r2", "user-1", "request-gemini", "error", 0, today},
+		{"r3", "user-2", "request-ollama", "error", 0, yesterday},
+		{"r4", "user-2", "request-ollama", "success", 500, today},
+		{"r5", "user-3", "request-other", "success", 50, today},
+		{"r6", "user-3", "request-gemini", "success", 9000, old},
+	} {
+		if _, err := database.Exec(`INSERT INTO api_responses (id, user_id, request_id, response_status, total_tokens, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+			response.id, response.userID, response.requestID, response.status, response.tokens, response.createdAt); err != nil {
+			t.Fatalf("failed to insert response: %v", err)
+		}
+	}
+
+	stats, err := client.GetServerStats(ctx, 7)
+	if err != nil {
+		t.Fatalf("failed to get server stats: %v", err)
+	}
+	if stats.TotalUsers != 3 {
+		t.Errorf("expected 3 users, got %d", stats.TotalUsers)
+	}
+	if len(stats.RunsPerDay) != 2 || stats.RunsPerDay[0].Day != yesterday.Format(time.DateOnly) || stats.RunsPerDay[0].Runs != 1 ||
+		stats.RunsPerDay[1].Runs != 2 || stats.RunsPerDay[1].Failed != 1 {
+		t.Errorf("unexpected runs per day: %+v", stats.RunsPerDay)
+	}
+	if len(stats.ProviderErrors) != 3 || stats.ProviderErrors[0].ErrorRate != 0.5 || stats.ProviderErrors[2].Provider != "unknown" ||
+		stats.ProviderErrors[2].ErrorRate != 0 {
+		t.Errorf("unexpected provider error rates: %+v", stats.ProviderErrors)
+	}
+	if len(stats.TopTokenConsumers) != 3 || stats.TopTokenConsumers[0].Username != "grace" || stats.TopTokenConsumers[0].TotalTokens != 500 ||
+		stats.TopTokenConsumers[2].UserID != "user-3" || stats.TopTokenConsumers[2].TotalTokens != 50 {
+		t.Errorf("unexpected token consumers: %+v", stats.TopTokenConsumers)
+	}
+	// SQLite has no information_schema, so sizes are left empty rather than failing the stats
+	if stats.TableSizes == nil || len(stats.TableSizes) != 0 {
+		t.Errorf("expected no table sizes, got %+v", stats.TableSizes)
+	}
+
+	stats, err = client.GetServerStats(ctx, 0)
+	if err != nil || len(stats.RunsPerDay) != 2 || stats.TopTokenConsumers[0].TotalTokens != 500 {
+		t.Errorf("expected 30 days by default, got %+v, %v", stats, err)
+	}
+	if _, err := (&Client{}).GetServerStats(ctx, 7); !errors.Is(err, ErrNoDatabase) {
+		t.Errorf("expected ErrNoDatabase, got %v", err)
+	}
+}
@@ -0,0 +1,299 @@
+package gogent
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"gogent/internal/types"
+)
+
+const (
+	// MaxRepetitions caps how many times a run may execute each configuration
+	MaxRepetitions = 20
+	// significanceLevel is the p-value below which a difference is reported as significant
+	significanceLevel = 0.05
+	// confidenceLevel is the coverage of the reported confidence intervals
+	confidenceLevel = 0.95
+)
+
+// statisticsMetrics are the per-response scores summarized across repetitions, when present
+var statisticsMetrics = []string{
+	"overall_score",
+	"response_time_ms",
+	"creativity_score",
+	"coherence_score",
+	"token_efficiency",
+	"cost_effectiveness",
+	"judge_score",
+	semanticSimilarityMetric,
+	schemaComplianceMetric,
+	guardViolationsMetric,
+	toolCallRateMetric,
+	argumentValidityMetric,
+}
+
+// ValidateRepetitions rejects negative repetition counts and counts above MaxRepetitions; 0 runs once
+func ValidateRepetitions(repetitions int) error {
+	if repetitions < 0 || repetitions > MaxRepetitions {
+		return fmt.Errorf("repetitions must be between 0 and %d, got %d", MaxRepetitions, repetitions)
+	}
+	return nil
+}
+
+// resultKey identifies a variation result within a run: its configuration ID, plus the sample
+// number when configurations are repeated and the candidate index for a request's other candidates
+func resultKey(r types.VariationResult) string {
+	key := r.Configuration.ID
+	if r.Repetition > 0 {
+		key = fmt.Sprintf("%s#%d", key, r.Repetition)
+	}
+	if r.Response.CandidateIndex > 0 {
+		key = fmt.Sprintf("%s/%d", key, r.Response.CandidateIndex)
+	}
+	return key
+}
+
+// repetitionSamples collects the per-response scores of repeated configurations by variation name
+type repetitionSamples struct {
+	names   []string
+	metrics map[string]map[string][]float64
+}
+
+// add records one sample's scores
+func (s *repetitionSamples) add(name string, scores map[string]interface{}) {
+	if s.metrics == nil {
+		s.metrics = make(map[string]map[string][]float64)
+	}
+	if _, ok := s.metrics[name]; !ok {
+		s.names = append(s.names, name)
+		s.metrics[name] = make(map[string][]float64)
+	}
+	for _, metric := range statisticsMetrics {
+		if value, ok := numericScore(scores[metric]); ok {
+			s.metrics[name][metric] = append(s.metrics[name][metric], value)
+		}
+	}
+}
+
+// statistics summarizes each metric recorded for a variation
+func (s *repetitionSamples) statistics(name string) map[string]types.MetricStatistics {
+	stats := make(map[string]types.MetricStatistics)
+	for metric, samples := range s.metrics[name] {
+		stats[metric] = SummarizeSamples(samples)
+	}
+	return stats
+}
+
+// significanceTests compares every pair of variations on every metric both have at least two samples of
+func (s *repetitionSamples) significanceTests() []types.SignificanceTest {
+	var tests []types.SignificanceTest
+	for i, a := range s.names {
+		for _, b := range s.names[i+1:] {
+			for _, metric := range statisticsMetrics {
+				samplesA, samplesB := s.metrics[a][metric], s.metrics[b][metric]
+				if len(samplesA) < 2 || len(samplesB) < 2 {
+					continue
+				}
+				test := WelchTTest(samplesA, samplesB)
+				test.Metric = metric
+				test.ConfigurationA = a
+				test.ConfigurationB = b
+				tests = append(tests, test)
+			}
+		}
+	}
+	sort.SliceStable(tests, func(i, j int) bool { return tests[i].PValue < tests[j].PValue })
+	return tests
+}
+
+// numericScore reads a score stored in a comparison's configuration scores
+func numericScore(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// SummarizeSamples computes the mean, sample standard deviation and 95% confidence interval of
+// the mean. The interval collapses to the mean with fewer than two samples.
+func SummarizeSamples(samples []float64) types.MetricStatistics {
+	stats := types.MetricStatistics{Samples: len(samples)}
+	if len(samples) == 0 {
+		return stats
+	}
+
+	mean, variance := meanAndVariance(samples)
+	stats.Mean = mean
+	stats.StdDev = math.Sqrt(variance)
+	stats.CILower, stats.CIUpper = mean, mean
+	if len(samples) > 1 {
+		margin := studentTCritical(confidenceLevel, float64(len(samples)-1)) * stats.StdDev / math.Sqrt(float64(len(samples)))
+		stats.CILower, stats.CIUpper = mean-margin, mean+margin
+	}
+	return stats
+}
+
+// WelchTTest runs a two-sided Welch's t-test, which does not assume equal variances. Samples
+// with no variance at all are significantly different whenever their means differ.
+func WelchTTest(a, b []float64) types.SignificanceTest {
+	meanA, varA := meanAndVariance(a)
+	meanB, varB := meanAndVariance(b)
+	test := types.SignificanceTest{MeanDifference: meanA - meanB, PValue: 1}
+
+	seA, seB := varA/float64(len(a)), varB/float64(len(b))
+	if seA+seB == 0 {
+		test.DegreesOfFreedom = float64(len(a) + len(b) - 2)
+		if meanA != meanB {
+			test.PValue = 0
+		}
+	} else {
+		test.TStatistic = test.MeanDifference / math.Sqrt(seA+seB)
+		test.DegreesOfFreedom = (seA + seB) * (seA + seB) /
+			(seA*seA/float64(len(a)-1) + seB*seB/float64(len(b)-1))
+		test.PValue = studentTTwoSidedP(test.TStatistic, test.DegreesOfFreedom)
+	}
+
+	test.Significant = test.PValue < significanceLevel
+	return test
+}
+
+// meanAndVariance returns the mean and unbiased sample variance
+func meanAndVariance(samples []float64) (float64, float64) {
+	var sum float64
+	for _, v := range samples {
+		sum += v
+	}
+	mean := sum / float64(len(samples))
+	if len(samples) < 2 {
+		return mean, 0
+	}
+
+	var squares float64
+	for _, v := range samples {
+		squares += (v - mean) * (v - mean)
+	}
+	return mean, squares / float64(len(samples)-1)
+}
+
+// studentTTwoSidedP is the probability of a |t| at least this large under Student's t distribution
+func studentTTwoSidedP(t, df float64) float64 {
+	return regularizedIncompleteBeta(df/(df+t*t), df/2, 0.5)
+}
+
+// studentTCritical finds the t value whose two-sided interval has the given coverage, by bisection
+func studentTCritical(coverage, df float64) float64 {
+	low, high := 0.0, 1000.0
+	for i := 0; i < 100; i++ {
+		mid := (low + high) / 2
+		if studentTTwoSidedP(mid, df) > 1-coverage {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return (low + high) / 2
+}
+
+// regularizedIncompleteBeta evaluates I_x(a, b) with the continued fraction from Numerical Recipes
+func regularizedIncompleteBeta(x, a, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+
+	lgammaAB, _ := math.Lgamma(a + b)
+	lgammaA, _ := math.Lgamma(a)
+	lgammaB, _ := math.Lgamma(b)
+	front := math.Exp(lgammaAB - lgammaA - lgammaB + a*math.Log(x) + b*math.Log(1-x))
+
+	// The continued fraction converges quickly only below the distribution's mean
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(x, a, b) / a
+	}
+	return 1 - front*betaContinuedFraction(1-x, b, a)/b
+}
+
+// betaContinuedFraction evaluates the incomplete beta continued fraction with Lentz's method
+func betaContinuedFraction(x, a, b float64) float64 {
+	const (
+		maxIterations = 200
+		epsilon       = 1e-14
+		tiny          = 1e-300
+	)
+
+	c, d := 1.0, 1-(a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+
+	for m := 1.0; m <= maxIterations; m++ {
+		// Even step
+		num := m * (b - m) * x / ((a + 2*m - 1) * (a + 2*m))
+		d = 1 + num*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + num/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+
+		// Odd step
+		num = -(a + m) * (a + b + m) * x / ((a + 2*m) * (a + 2*m + 1))
+		d = 1 + num*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + num/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	return h
+}
+
+// numberRepetitions sets the sample number of results whose configuration ran more than once,
+// in the order the results are listed. A request's other candidates take its first candidate's number.
+func numberRepetitions(results []types.VariationResult) {
+	counts := make(map[string]int)
+	for _, r := range results {
+		if r.Response.CandidateIndex == 0 {
+			counts[r.Configuration.ID]++
+		}
+	}
+
+	seen := make(map[string]int)
+	requests := make(map[string]int)
+	for i := range results {
+		id := results[i].Configuration.ID
+		if counts[id] > 1 && results[i].Response.CandidateIndex == 0 {
+			seen[id]++
+			results[i].Repetition = seen[id]
+			requests[results[i].Request.ID] = seen[id]
+		}
+	}
+	for i := range results {
+		if results[i].Response.CandidateIndex > 0 {
+			results[i].Repetition = requests[results[i].Request.ID]
+		}
+	}
+}
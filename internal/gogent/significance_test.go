@@ -0,0 +1,129 @@
+package gogent
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"gogent/internal/types"
+)
+
+// newRepeatedTestResult returns a result with one repeated configuration per variation name,
+// each sample taking one of the given response times
+func newRepeatedTestResult(responseTimes map[string][]int32) *types.ExecutionResult {
+	result := &types.ExecutionResult{ExecutionRun: types.ExecutionRun{ID: "run-1"}}
+	for _, name := range []string{"fast", "slow"} {
+		for i, responseTime := range responseTimes[name] {
+			result.Results = append(result.Results, types.VariationResult{
+				Configuration: types.APIConfiguration{ID: "config-" + name, VariationName: name},
+				Response: types.APIResponse{ResponseStatus: types.ResponseStatusSuccess,
+					ResponseText: "Paris is the capital of France.", ResponseTimeMs: responseTime},
+				Repetition: i + 1,
+			})
+		}
+	}
+	return result
+}
+
+func TestSummarizeSamples(t *testing.T) {
+	stats := SummarizeSamples([]float64{1, 2, 3, 4, 5})
+	if stats.Samples != 5 || stats.Mean != 3 || math.Abs(stats.StdDev-1.5811) > 1e-4 {
+		t.Errorf("expected mean 3 and stddev 1.5811, got %+v", stats)
+	}
+	// t(0.975, 4) = 2.7764, so the margin is 2.7764 * 1.5811 / sqrt(5)
+	if math.Abs(stats.CILower-1.0368) > 1e-3 || math.Abs(stats.CIUpper-4.9632) > 1e-3 {
+		t.Errorf("expected a 95%% CI of 1.037-4.963, got %v-%v", stats.CILower, stats.CIUpper)
+	}
+
+	if single := SummarizeSamples([]float64{0.7}); single.CILower != 0.7 || single.CIUpper != 0.7 || single.StdDev != 0 {
+		t.Errorf("expected a single sample to collapse the interval, got %+v", single)
+	}
+}
+
+func TestWelchTTest(t *testing.T) {
+	tests := []struct {
+		name              string
+		a, b              []float64
+		expectP           float64
+		expectSignificant bool
+	}{
+		{"clear_difference", []float64{1, 2, 3, 4, 5}, []float64{6, 7, 8, 9, 10}, 0.00105, true},
+		{"overlapping", []float64{1, 2, 3, 4, 5}, []float64{2, 3, 4, 5, 6}, 0.3466, false},
+		{"identical_constants", []float64{2, 2, 2}, []float64{2, 2}, 1, false},
+		{"different_constants", []float64{2, 2, 2}, []float64{3, 3}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test := WelchTTest(tt.a, tt.b)
+			if math.Abs(test.PValue-tt.expectP) > 1e-3 || test.Significant != tt.expectSignificant {
+				t.Errorf("expected p=%v (significant=%v), got %+v", tt.expectP, tt.expectSignificant, test)
+			}
+		})
+	}
+
+	test := WelchTTest([]float64{1, 2, 3, 4, 5}, []float64{6, 7, 8, 9, 10})
+	if test.TStatistic != -5 || test.DegreesOfFreedom != 8 || test.MeanDifference != -5 {
+		t.Errorf("expected t=-5 with 8 degrees of freedom, got %+v", test)
+	}
+}
+
+func TestValidateRepetitions(t *testing.T) {
+	for _, repetitions := range []int{0, 1, MaxRepetitions} {
+		if err := ValidateRepetitions(repetitions); err != nil {
+			t.Errorf("expected %d repetitions to be valid, got %v", repetitions, err)
+		}
+	}
+	for _, repetitions := range []int{-1, MaxRepetitions + 1} {
+		if err := ValidateRepetitions(repetitions); err == nil {
+			t.Errorf("expected %d repetitions to be rejected", repetitions)
+		}
+	}
+}
+
+func TestNumberRepetitions(t *testing.T) {
+	results := []types.VariationResult{
+		{Configuration: types.APIConfiguration{ID: "config-a"}},
+		{Configuration: types.APIConfiguration{ID: "config-b"}},
+		{Configuration: types.APIConfiguration{ID: "config-a"}},
+	}
+	numberRepetitions(results)
+	if results[0].Repetition != 1 || results[2].Repetition != 2 || results[1].Repetition != 0 {
+		t.Errorf("expected only the repeated configuration to be numbered, got %d, %d, %d",
+			results[0].Repetition, results[1].Repetition, results[2].Repetition)
+	}
+}
+
+func TestCompareRepeatedResults(t *testing.T) {
+	client := &Client{}
+	result := newRepeatedTestResult(map[string][]int32{
+		"fast": {900, 950, 1000, 1000},
+		"slow": {3000, 4000, 5000, 4500},
+	})
+
+	comparison, err := client.compareResults(context.Background(), "user-1", result, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if comparison.BestConfigurationID != "config-fast" {
+		t.Errorf("expected the fast configuration to win on mean score, got %s", comparison.BestConfigurationID)
+	}
+
+	fast := comparison.ConfigurationScores["fast"].(map[string]interface{})
+	if fast["repetitions"] != 4 || fast["response_time_ms"] != 962.5 {
+		t.Errorf("expected the mean of 4 samples, got %v samples with mean %v", fast["repetitions"], fast["response_time_ms"])
+	}
+	if stats := fast["statistics"].(map[string]types.MetricStatistics)["response_time_ms"]; stats.CILower >= 962.5 || stats.CIUpper <= 962.5 {
+		t.Errorf("expected a confidence interval around the mean, got %+v", stats)
+	}
+
+	var overall *types.SignificanceTest
+	for i, test := range comparison.SignificanceTests {
+		if test.Metric == "overall_score" {
+			overall = &comparison.SignificanceTests[i]
+		}
+	}
+	if overall == nil || !overall.Significant || overall.ConfigurationA != "fast" || overall.MeanDifference <= 0 {
+		t.Errorf("expected a significant overall score advantage for fast, got %+v", overall)
+	}
+}
@@ -0,0 +1,335 @@
+package gogent
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"gogent/internal/types"
+
+	"github.com/google/uuid"
+)
+
+// ErrSLONotFound is returned when a user has no SLO for a suite
+var ErrSLONotFound = errors.New("suite SLO not found")
+
+const (
+	// defaultSLOTarget is the fraction of runs that must comply when an SLO sets no target
+	defaultSLOTarget = 0.95
+	// defaultSLOWindowHours is the burn rate window when an SLO sets none
+	defaultSLOWindowHours = 24
+	// maxSLOWindowHours is the longest burn rate window, 90 days
+	maxSLOWindowHours = 24 * 90
+	// defaultSLOAlertBurnRate is the burn rate at which an SLO alerts when it sets none
+	defaultSLOAlertBurnRate = 2
+)
+
+// suiteSLOColumns is the column list read by scanSuiteSLO
+const suiteSLOColumns = `
+	id, user_id, suite, max_latency_ms, max_cost_usd, min_success_rate,
+	target, window_hours, alert_burn_rate, created_at, updated_at`
+
+// ValidateSuiteSLO checks an SLO's objectives and fills in the default target, window and alert burn rate
+func ValidateSuiteSLO(slo *types.SuiteSLO) error {
+	if slo.Suite == "" {
+		return fmt.Errorf("suite is required")
+	}
+	if slo.MaxLatencyMs == 0 && slo.MaxCostUSD == 0 && slo.MinSuccessRate == 0 {
+		return fmt.Errorf("set at least one of maxLatencyMs, maxCostUsd and minSuccessRate")
+	}
+	if slo.MaxLatencyMs < 0 || slo.MaxCostUSD < 0 {
+		return fmt.Errorf("maxLatencyMs and maxCostUsd must not be negative")
+	}
+	if slo.MinSuccessRate < 0 || slo.MinSuccessRate > 1 {
+		return fmt.Errorf("minSuccessRate must be between 0 and 1")
+	}
+
+	if slo.Target == 0 {
+		slo.Target = defaultSLOTarget
+	}
+	if slo.Target <= 0 || slo.Target >= 1 {
+		return fmt.Errorf("target must be between 0 and 1, exclusive, so the SLO has an error budget")
+	}
+	if slo.WindowHours == 0 {
+		slo.WindowHours = defaultSLOWindowHours
+	}
+	if slo.WindowHours < 0 || slo.WindowHours > maxSLOWindowHours {
+		return fmt.Errorf("windowHours must be between 1 and %d", maxSLOWindowHours)
+	}
+	if slo.AlertBurnRate == 0 {
+		slo.AlertBurnRate = defaultSLOAlertBurnRate
+	}
+	if slo.AlertBurnRate < 0 {
+		return fmt.Errorf("alertBurnRate must not be negative")
+	}
+	return nil
+}
+
+// scanSuiteSLO reads a row selected with suiteSLOColumns
+func scanSuiteSLO(row rowScanner) (*types.SuiteSLO, error) {
+	var slo types.SuiteSLO
+	var maxLatency sql.NullInt32
+	var maxCost, minSuccess sql.NullFloat64
+
+	err := row.Scan(&slo.ID, &slo.UserID, &slo.Suite, &maxLatency, &maxCost, &minSuccess,
+		&slo.Target, &slo.WindowHours, &slo.AlertBurnRate, &slo.CreatedAt, &slo.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	slo.MaxLatencyMs = maxLatency.Int32
+	slo.MaxCostUSD = maxCost.Float64
+	slo.MinSuccessRate = minSuccess.Float64
+	return &slo, nil
+}
+
+// nullableFloat stores zero as NULL
+func nullableFloat(value float64) interface{} {
+	if value == 0 {
+		return nil
+	}
+	return value
+}
+
+// SaveSuiteSLO creates or replaces the user's SLO for a suite
+func (c *Client) SaveSuiteSLO(ctx context.Context, userID string, slo *types.SuiteSLO) (*types.SuiteSLO, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	if err := ValidateSuiteSLO(slo); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	existing, err := c.GetSuiteSLO(ctx, userID, slo.Suite)
+	switch {
+	case errors.Is(err, ErrSLONotFound):
+		_, err = c.db.ExecContext(ctx, `
+			INSERT INTO suite_slos (
+				id, user_id, suite, max_latency_ms, max_cost_usd, min_success_rate,
+				target, window_hours, alert_burn_rate, created_at, updated_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, uuid.New().String(), userID, slo.Suite, nullableInt32(slo.MaxLatencyMs), nullableFloat(slo.MaxCostUSD),
+			nullableFloat(slo.MinSuccessRate), slo.Target, slo.WindowHours, slo.AlertBurnRate, now, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create suite SLO: %w", err)
+		}
+	case err != nil:
+		return nil, err
+	default:
+		_, err = c.db.ExecContext(ctx, `
+			UPDATE suite_slos
+			SET max_latency_ms = ?, max_cost_usd = ?, min_success_rate = ?,
+			    target = ?, window_hours = ?, alert_burn_rate = ?, updated_at = ?
+			WHERE id = ?
+		`, nullableInt32(slo.MaxLatencyMs), nullableFloat(slo.MaxCostUSD), nullableFloat(slo.MinSuccessRate),
+			slo.Target, slo.WindowHours, slo.AlertBurnRate, now, existing.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update suite SLO: %w", err)
+		}
+	}
+
+	return c.GetSuiteSLO(ctx, userID, slo.Suite)
+}
+
+// GetSuiteSLO loads the user's SLO for a suite
+func (c *Client) GetSuiteSLO(ctx context.Context, userID, suite string) (*types.SuiteSLO, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	row := c.db.QueryRowContext(ctx,
+		"SELECT "+suiteSLOColumns+" FROM suite_slos WHERE user_id = ? AND suite = ?",
+		userID, suite,
+	)
+	slo, err := scanSuiteSLO(row)
+	if err == sql.ErrNoRows {
+		return nil, ErrSLONotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suite SLO: %w", err)
+	}
+	return slo, nil
+}
+
+// ListSuiteSLOs returns the user's SLOs ordered by suite
+func (c *Client) ListSuiteSLOs(ctx context.Context, userID string) ([]*types.SuiteSLO, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	rows, err := c.db.QueryContext(ctx,
+		"SELECT "+suiteSLOColumns+" FROM suite_slos WHERE user_id = ? ORDER BY suite ASC",
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list suite SLOs: %w", err)
+	}
+	defer rows.Close()
+
+	var slos []*types.SuiteSLO
+	for rows.Next() {
+		slo, err := scanSuiteSLO(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan suite SLO: %w", err)
+		}
+		slos = append(slos, slo)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate suite SLOs: %w", err)
+	}
+	return slos, nil
+}
+
+// DeleteSuiteSLO removes the user's SLO for a suite. Recorded run results are kept.
+func (c *Client) DeleteSuiteSLO(ctx context.Context, userID, suite string) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	result, err := c.db.ExecContext(ctx, "DELETE FROM suite_slos WHERE user_id = ? AND suite = ?", userID, suite)
+	if err != nil {
+		return fmt.Errorf("failed to delete suite SLO: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete suite SLO: %w", err)
+	}
+	if affected == 0 {
+		return ErrSLONotFound
+	}
+	return nil
+}
+
+// EvaluateSLO checks a finished run against an SLO. Latency is the slowest variation's execution
+// time and cost is the estimated cost of all variations.
+func EvaluateSLO(slo *types.SuiteSLO, result *types.ExecutionResult) types.SLOResult {
+	evaluation := types.SLOResult{
+		ExecutionRunID: result.ExecutionRun.ID,
+		Suite:          slo.Suite,
+		CreatedAt:      time.Now(),
+	}
+
+	for _, variation := range result.Results {
+		if latency := int32(variation.ExecutionTime); latency > evaluation.LatencyMs {
+			evaluation.LatencyMs = latency
+		}
+		evaluation.CostUSD += ResponseCostUSD(variation.Configuration.ModelName, variation.Response)
+	}
+	if total := result.SuccessCount + result.ErrorCount; total > 0 {
+		evaluation.SuccessRate = float64(result.SuccessCount) / float64(total)
+	}
+
+	evaluation.LatencyMet = slo.MaxLatencyMs == 0 || evaluation.LatencyMs <= slo.MaxLatencyMs
+	evaluation.CostMet = slo.MaxCostUSD == 0 || evaluation.CostUSD <= slo.MaxCostUSD
+	evaluation.SuccessMet = slo.MinSuccessRate == 0 || evaluation.SuccessRate >= slo.MinSuccessRate
+	evaluation.Compliant = evaluation.LatencyMet && evaluation.CostMet && evaluation.SuccessMet
+	return evaluation
+}
+
+// recordSLOResult stores a run's SLO evaluation
+func (c *Client) recordSLOResult(ctx context.Context, userID string, evaluation *types.SLOResult) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	_, err := c.db.ExecContext(ctx, `
+		INSERT INTO execution_run_slo_results (
+			execution_run_id, user_id, suite, latency_ms, cost_usd, success_rate,
+			latency_met, cost_met, success_met, compliant, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, evaluation.ExecutionRunID, userID, evaluation.Suite, evaluation.LatencyMs, evaluation.CostUSD,
+		evaluation.SuccessRate, evaluation.LatencyMet, evaluation.CostMet, evaluation.SuccessMet,
+		evaluation.Compliant, evaluation.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record SLO result: %w", err)
+	}
+	return nil
+}
+
+// sloBurnRate is the fraction of non-compliant runs divided by the error budget (1 - target)
+func sloBurnRate(runs, compliantRuns int, target float64) float64 {
+	if runs == 0 {
+		return 0
+	}
+	failureRate := float64(runs-compliantRuns) / float64(runs)
+	return math.Round(failureRate/(1-target)*1000) / 1000
+}
+
+// sloStatus computes an SLO's compliance and burn rate over its window ending at now
+func (c *Client) sloStatus(ctx context.Context, userID string, slo *types.SuiteSLO, now time.Time) (*types.SLOStatus, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	status := &types.SLOStatus{
+		SLO:         *slo,
+		WindowStart: now.Add(-time.Duration(slo.WindowHours) * time.Hour),
+		Compliance:  1,
+	}
+
+	err := c.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(CASE WHEN compliant THEN 1 ELSE 0 END), 0)
+		FROM execution_run_slo_results
+		WHERE user_id = ? AND suite = ? AND created_at >= ?
+	`, userID, slo.Suite, status.WindowStart).Scan(&status.Runs, &status.CompliantRuns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute SLO status: %w", err)
+	}
+
+	if status.Runs > 0 {
+		status.Compliance = float64(status.CompliantRuns) / float64(status.Runs)
+	}
+	status.BurnRate = sloBurnRate(status.Runs, status.CompliantRuns, slo.Target)
+	status.Alerting = status.Runs > 0 && status.BurnRate >= slo.AlertBurnRate
+	return status, nil
+}
+
+// GetSLOStatus returns the current compliance and burn rate of the user's SLO for a suite
+func (c *Client) GetSLOStatus(ctx context.Context, userID, suite string) (*types.SLOStatus, error) {
+	slo, err := c.GetSuiteSLO(ctx, userID, suite)
+	if err != nil {
+		return nil, err
+	}
+	return c.sloStatus(ctx, userID, slo, time.Now())
+}
+
+// ListSLOStatuses returns the current status of each of the user's SLOs
+func (c *Client) ListSLOStatuses(ctx context.Context, userID string) ([]*types.SLOStatus, error) {
+	slos, err := c.ListSuiteSLOs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	statuses := make([]*types.SLOStatus, 0, len(slos))
+	for _, slo := range slos {
+		status, err := c.sloStatus(ctx, userID, slo, now)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// TrackSLO evaluates a finished run of a suite against the suite's SLO, records the result and
+// returns it with the SLO's updated status. Both are nil when the suite has no SLO.
+func (c *Client) TrackSLO(ctx context.Context, userID, suite string, result *types.ExecutionResult) (*types.SLOResult, *types.SLOStatus, error) {
+	slo, err := c.GetSuiteSLO(ctx, userID, suite)
+	if errors.Is(err, ErrSLONotFound) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	evaluation := EvaluateSLO(slo, result)
+	if err := c.recordSLOResult(ctx, userID, &evaluation); err != nil {
+		return nil, nil, err
+	}
+
+	status, err := c.sloStatus(ctx, userID, slo, time.Now())
+	if err != nil {
+		return &evaluation, nil, err
+	}
+	return &evaluation, status, nil
+}
@@ -0,0 +1,181 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"gogent/internal/testdb"
+	"gogent/internal/types"
+)
+
+// newSLOTestClient returns a client backed by in-memory suite_slos and execution_run_slo_results tables
+func newSLOTestClient(t *testing.T) *Client {
+	return &Client{db: testdb.Open(t)}
+}
+
+// newSLOTestResult builds a run result with one variation per latency, the last one failing when failLast is set
+func newSLOTestResult(runID string, failLast bool, latencies ...int64) *types.ExecutionResult {
+	result := &types.ExecutionResult{ExecutionRun: types.ExecutionRun{ID: runID}}
+	for i, latency := range latencies {
+		result.Results = append(result.Results, types.VariationResult{
+			Configuration: types.APIConfiguration{ModelName: "gemini-1.5-pro"},
+			Response: types.APIResponse{UsageMetadata: map[string]interface{}{
+				"prompt_tokens":     1000,
+				"completion_tokens": float64(200), // decoded usage metadata holds float64s
+			}},
+			ExecutionTime: latency,
+		})
+		if failLast && i == len(latencies)-1 {
+			result.ErrorCount++
+		} else {
+			result.SuccessCount++
+		}
+	}
+	return result
+}
+
+func TestEstimateCostUSD(t *testing.T) {
+	tests := []struct {
+		model    string
+		expected float64
+	}{
+		{"gemini-1.5-flash", 0.075 + 0.30},
+		{"gemini-1.5-flash-8b", 0.075 + 0.30},
+		{"gpt-4o", 2.50 + 10.00},
+		{"gpt-4o-mini", 0.15 + 0.60}, // the longest prefix wins
+		{"unknown-model", 0},
+	}
+
+	for _, tt := range tests {
+		if got := EstimateCostUSD(tt.model, 1_000_000, 1_000_000); math.Abs(got-tt.expected) > 1e-9 {
+			t.Errorf("%s: expected %.4f, got %.4f", tt.model, tt.expected, got)
+		}
+	}
+}
+
+func TestValidateSuiteSLO(t *testing.T) {
+	slo := &types.SuiteSLO{Suite: "nightly", MaxLatencyMs: 2000}
+	if err := ValidateSuiteSLO(slo); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if slo.Target != defaultSLOTarget || slo.WindowHours != defaultSLOWindowHours || slo.AlertBurnRate != defaultSLOAlertBurnRate {
+		t.Errorf("expected defaults to be filled in, got %+v", slo)
+	}
+
+	invalid := []*types.SuiteSLO{
+		{MaxLatencyMs: 2000},
+		{Suite: "nightly"},
+		{Suite: "nightly", MinSuccessRate: 1.5},
+		{Suite: "nightly", MaxCostUSD: 1, Target: 1},
+		{Suite: "nightly", MaxCostUSD: 1, WindowHours: -1},
+	}
+	for _, slo := range invalid {
+		if err := ValidateSuiteSLO(slo); err == nil {
+			t.Errorf("expected an error for %+v", slo)
+		}
+	}
+}
+
+func TestEvaluateSLO(t *testing.T) {
+	slo := &types.SuiteSLO{Suite: "nightly", MaxLatencyMs: 1500, MaxCostUSD: 0.01, MinSuccessRate: 1}
+
+	evaluation := EvaluateSLO(slo, newSLOTestResult("run-1", false, 800, 1200))
+	if !evaluation.Compliant || evaluation.LatencyMs != 1200 {
+		t.Errorf("expected a compliant run with the slowest latency, got %+v", evaluation)
+	}
+	// 2 x (1000 input + 200 output tokens) on gemini-1.5-pro
+	if expected := 2 * (1000*1.25 + 200*5.00) / 1e6; math.Abs(evaluation.CostUSD-expected) > 1e-9 {
+		t.Errorf("expected cost %.6f, got %.6f", expected, evaluation.CostUSD)
+	}
+
+	evaluation = EvaluateSLO(slo, newSLOTestResult("run-2", true, 800, 2000))
+	if evaluation.Compliant || evaluation.LatencyMet || evaluation.SuccessMet || !evaluation.CostMet {
+		t.Errorf("expected latency and success objectives to be missed, got %+v", evaluation)
+	}
+	if evaluation.SuccessRate != 0.5 {
+		t.Errorf("expected success rate 0.5, got %v", evaluation.SuccessRate)
+	}
+}
+
+func TestTrackSLO(t *testing.T) {
+	client := newSLOTestClient(t)
+	ctx := context.Background()
+
+	if evaluation, status, err := client.TrackSLO(ctx, "user-1", "nightly", newSLOTestResult("run-0", false, 100)); err != nil || evaluation != nil || status != nil {
+		t.Fatalf("expected suites without an SLO to be skipped, got %+v, %+v, %v", evaluation, status, err)
+	}
+
+	_, err := client.SaveSuiteSLO(ctx, "user-1", &types.SuiteSLO{Suite: "nightly", MaxLatencyMs: 1000, Target: 0.9})
+	if err != nil {
+		t.Fatalf("unexpected error saving SLO: %v", err)
+	}
+
+	// 9 compliant runs and 1 slow run spend exactly the 10% error budget
+	for i := 0; i < 9; i++ {
+		if _, _, err := client.TrackSLO(ctx, "user-1", "nightly", newSLOTestResult(string(rune('a'+i)), false, 500)); err != nil {
+			t.Fatalf("unexpected error tracking run: %v", err)
+		}
+	}
+	evaluation, status, err := client.TrackSLO(ctx, "user-1", "nightly", newSLOTestResult("slow", false, 1500))
+	if err != nil {
+		t.Fatalf("unexpected error tracking run: %v", err)
+	}
+	if evaluation.Compliant {
+		t.Errorf("expected the slow run not to comply, got %+v", evaluation)
+	}
+	if status.Runs != 10 || status.CompliantRuns != 9 || status.BurnRate != 1 || status.Alerting {
+		t.Errorf("expected burn rate 1 without an alert, got %+v", status)
+	}
+
+	// Two more slow runs push the burn rate past the default alert threshold of 2
+	client.TrackSLO(ctx, "user-1", "nightly", newSLOTestResult("slow-2", false, 1500))
+	if _, status, _ = client.TrackSLO(ctx, "user-1", "nightly", newSLOTestResult("slow-3", false, 1500)); status.BurnRate != 2.5 || !status.Alerting {
+		t.Errorf("expected the SLO to alert at burn rate 2.5, got %+v", status)
+	}
+
+	t.Run("update_keeps_results", func(t *testing.T) {
+		if _, err := client.SaveSuiteSLO(ctx, "user-1", &types.SuiteSLO{Suite: "nightly", MaxLatencyMs: 1000, Target: 0.5}); err != nil {
+			t.Fatalf("unexpected error updating SLO: %v", err)
+		}
+		status, err := client.GetSLOStatus(ctx, "user-1", "nightly")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if status.Runs != 12 || status.Alerting {
+			t.Errorf("expected 12 runs within the looser budget, got %+v", status)
+		}
+	})
+
+	t.Run("window", func(t *testing.T) {
+		slo, _ := client.GetSuiteSLO(ctx, "user-1", "nightly")
+		status, err := client.sloStatus(ctx, "user-1", slo, time.Now().Add(48*time.Hour))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if status.Runs != 0 || status.Compliance != 1 || status.BurnRate != 0 {
+			t.Errorf("expected no runs in a later window, got %+v", status)
+		}
+	})
+
+	t.Run("scoped_to_user", func(t *testing.T) {
+		if _, err := client.GetSLOStatus(ctx, "user-2", "nightly"); !errors.Is(err, ErrSLONotFound) {
+			t.Errorf("expected ErrSLONotFound, got %v", err)
+		}
+		statuses, err := client.ListSLOStatuses(ctx, "user-1")
+		if err != nil || len(statuses) != 1 {
+			t.Errorf("expected one SLO status, got %d, %v", len(statuses), err)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if err := client.DeleteSuiteSLO(ctx, "user-1", "nightly"); err != nil {
+			t.Fatalf("unexpected error deleting SLO: %v", err)
+		}
+		if err := client.DeleteSuiteSLO(ctx, "user-1", "nightly"); !errors.Is(err, ErrSLONotFound) {
+			t.Errorf("expected a second delete to report not found, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,280 @@
+package gogent
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"gogent/internal/types"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseRunSpec parses a run spec written as YAML or JSON. Unknown fields are rejected, and the spec
+// is checked with ValidateRunSpec; a spec without a version gets the current one.
+func ParseRunSpec(data []byte) (*types.RunSpec, error) {
+	// YAML is a superset of JSON, so both are decoded as YAML and mapped through the JSON field names
+	var document interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse run spec: %w", err)
+	}
+	encoded, err := json.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse run spec: %w", err)
+	}
+
+	var spec types.RunSpec
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("invalid run spec: %w", err)
+	}
+	if spec.Version == 0 {
+		spec.Version = types.RunSpecVersion
+	}
+
+	if err := ValidateRunSpec(&spec); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// ValidateRunSpec checks a spec's structure, returning a *ValidationError listing every invalid
+// field. Sampling ranges and model names are checked once the spec is turned into a request.
+func ValidateRunSpec(spec *types.RunSpec) error {
+	validation := &ValidationError{}
+	if spec.Version != types.RunSpecVersion {
+		validation.add("version", "unsupported run spec version %d; the current version is %d", spec.Version, types.RunSpecVersion)
+	}
+	if spec.Prompt == "" {
+		validation.add("prompt", "prompt is required")
+	}
+	if len(spec.Configurations) == 0 {
+		validation.add("configurations", "at least one configuration is required")
+	}
+
+	if spec.Optimize != nil && spec.Sweep == nil {
+		validation.add("optimize", "optimize needs a sweep to define the search space")
+	}
+
+	toolNames := validateTools(validation, "tools", spec.Tools)
+
+	names := make(map[string]bool, len(spec.Configurations))
+	for i, config := range spec.Configurations {
+		field := fmt.Sprintf("configurations[%d]", i)
+		switch {
+		case config.Name == "":
+			validation.add(field+".name", "name is required")
+		case names[config.Name]:
+			validation.add(field+".name", "duplicate configuration name %q", config.Name)
+		}
+		names[config.Name] = true
+
+		if config.Model == "" && config.Preset == "" {
+			validation.add(field+".model", "model is required unless the configuration uses a preset")
+		}
+		for _, name := range config.Tools {
+			if !toolNames[name] {
+				validation.add(field+".tools", "%q is not one of the spec's tools", name)
+			}
+		}
+	}
+
+	if len(validation.Errors) == 0 {
+		return nil
+	}
+	return validation
+}
+
+// RunSpecRequest turns a spec into the execution request it declares
+func RunSpecRequest(spec *types.RunSpec) *types.MultiExecutionRequest {
+	request := &types.MultiExecutionRequest{
+		ExecutionRunName:      spec.Name,
+		NameTemplate:          spec.NameTemplate,
+		Description:           spec.Description,
+		Suite:                 spec.Suite,
+		GitSHA:                spec.GitSHA,
+		Tags:                  spec.Tags,
+		BasePrompt:            spec.Prompt,
+		Context:               spec.Context,
+		CacheContext:          spec.CacheContext,
+		EnableFunctionCalling: len(spec.Tools) > 0,
+		FunctionTools:         spec.Tools,
+		ComparisonConfig:      spec.Comparison,
+
+		FunctionInstruction:        spec.FunctionInstruction,
+		DisableFunctionInstruction: spec.DisableFunctionInstruction,
+		SafetyPolicy:               spec.SafetyPolicy,
+		Deterministic:              spec.Deterministic,
+		Seed:                       spec.Seed,
+		Repetitions:                spec.Repetitions,
+		ExpectedAnswer:             spec.ExpectedAnswer,
+		DuplicatePolicy:            spec.DuplicatePolicy,
+		Sweep:                      spec.Sweep,
+	}
+
+	for _, config := range spec.Configurations {
+		request.Configurations = append(request.Configurations, types.APIConfiguration{
+			VariationName:      config.Name,
+			PresetID:           config.Preset,
+			ModelName:          config.Model,
+			SystemPrompt:       config.SystemPrompt,
+			InlineSystemPrompt: config.InlineSystemPrompt,
+			Temperature:        config.Temperature,
+			MaxTokens:          config.MaxTokens,
+			TopP:               config.TopP,
+			TopK:               config.TopK,
+			ThinkingBudget:     config.ThinkingBudget,
+			CandidateCount:     config.CandidateCount,
+			ToolNames:          config.Tools,
+			DisableTools:       config.DisableTools,
+
+			FunctionInstruction:        config.FunctionInstruction,
+			DisableFunctionInstruction: config.DisableFunctionInstruction,
+			SafetyPolicy:               config.SafetyPolicy,
+			ResponseMimeType:           config.ResponseMimeType,
+			ResponseSchema:             config.ResponseSchema,
+			TimeoutMs:                  config.TimeoutMs,
+			Backend:                    config.Backend,
+			Provider:                   config.Provider,
+			Retrieval:                  config.Retrieval,
+			Guardrails:                 config.Guardrails,
+			ToolChoiceMode:             config.ToolChoiceMode,
+			AllowedFunctionNames:       config.AllowedFunctionNames,
+		})
+	}
+	return request
+}
+
+// RunSpecFromRequest returns the spec that declares a request, such as the resolved spec of a run
+// after workspace defaults and its unique name were applied
+func RunSpecFromRequest(request *types.MultiExecutionRequest) *types.RunSpec {
+	spec := &types.RunSpec{
+		Version:      types.RunSpecVersion,
+		Name:         request.ExecutionRunName,
+		NameTemplate: request.NameTemplate,
+		Description:  request.Description,
+		Suite:        request.Suite,
+		GitSHA:       request.GitSHA,
+		Tags:         request.Tags,
+		Prompt:       request.BasePrompt,
+		Context:      request.Context,
+		CacheContext: request.CacheContext,
+		Comparison:   request.ComparisonConfig,
+
+		FunctionInstruction:        request.FunctionInstruction,
+		DisableFunctionInstruction: request.DisableFunctionInstruction,
+		SafetyPolicy:               request.SafetyPolicy,
+		Deterministic:              request.Deterministic,
+		Seed:                       request.Seed,
+		Repetitions:                request.Repetitions,
+		ExpectedAnswer:             request.ExpectedAnswer,
+		DuplicatePolicy:            request.DuplicatePolicy,
+	}
+	if request.EnableFunctionCalling {
+		spec.Tools = request.FunctionTools
+	}
+
+	for _, config := range request.Configurations {
+		spec.Configurations = append(spec.Configurations, types.SpecConfiguration{
+			Name:               config.VariationName,
+			Preset:             config.PresetID,
+			Model:              config.ModelName,
+			SystemPrompt:       config.SystemPrompt,
+			InlineSystemPrompt: config.InlineSystemPrompt,
+			Temperature:        config.Temperature,
+			MaxTokens:          config.MaxTokens,
+			TopP:               config.TopP,
+			TopK:               config.TopK,
+			ThinkingBudget:     config.ThinkingBudget,
+			CandidateCount:     config.CandidateCount,
+			Tools:              config.ToolNames,
+			DisableTools:       config.DisableTools,
+
+			FunctionInstruction:        config.FunctionInstruction,
+			DisableFunctionInstruction: config.DisableFunctionInstruction,
+			SafetyPolicy:               config.SafetyPolicy,
+			ResponseMimeType:           config.ResponseMimeType,
+			ResponseSchema:             config.ResponseSchema,
+			TimeoutMs:                  config.TimeoutMs,
+			Backend:                    config.Backend,
+			Provider:                   config.Provider,
+			Retrieval:                  config.Retrieval,
+			Guardrails:                 config.Guardrails,
+			ToolChoiceMode:             config.ToolChoiceMode,
+			AllowedFunctionNames:       config.AllowedFunctionNames,
+		})
+	}
+	return spec
+}
+
+// MarshalRunSpecYAML renders a spec as YAML with its fields in declaration order
+func MarshalRunSpecYAML(spec *types.RunSpec) ([]byte, error) {
+	encoded, err := json.Marshal(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal run spec: %w", err)
+	}
+
+	// Decoding the JSON into a node keeps the field order; clearing the flow style prints block YAML
+	var document yaml.Node
+	if err := yaml.Unmarshal(encoded, &document); err != nil {
+		return nil, fmt.Errorf("failed to marshal run spec: %w", err)
+	}
+	clearNodeStyle(&document)
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&document); err != nil {
+		return nil, fmt.Errorf("failed to marshal run spec: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// clearNodeStyle resets the style of a YAML node tree, keeping quotes only where strings need them
+func clearNodeStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearNodeStyle(child)
+	}
+}
+
+// recordRunSpec stores the resolved spec of an execution run
+func (c *Client) recordRunSpec(ctx context.Context, executionRunID string, spec *types.RunSpec) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	encoded, err := types.ToJSON(spec)
+	if err != nil {
+		return fmt.Errorf("failed to marshal run spec: %w", err)
+	}
+	_, err = c.db.ExecContext(ctx, "UPDATE execution_runs SET run_spec = ? WHERE id = ?", encoded, executionRunID)
+	if err != nil {
+		return fmt.Errorf("failed to record run spec: %w", err)
+	}
+	return nil
+}
+
+// loadRunSpec fills in the resolved spec an execution run executed, if one was recorded
+func (c *Client) loadRunSpec(ctx context.Context, run *types.ExecutionRun) error {
+	if c.db == nil {
+		return nil
+	}
+	var encoded sql.NullString
+	err := c.db.QueryRowContext(ctx, "SELECT run_spec FROM execution_runs WHERE id = ?", run.ID).Scan(&encoded)
+	if err != nil {
+		return fmt.Errorf("failed to load run spec: %w", err)
+	}
+	if !encoded.Valid || encoded.String == "" {
+		return nil
+	}
+
+	var spec types.RunSpec
+	if err := types.FromJSON(encoded.String, &spec); err != nil {
+		return fmt.Errorf("failed to parse run spec: %w", err)
+	}
+	run.RunSpec = &spec
+	return nil
+}
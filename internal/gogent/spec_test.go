@@ -0,0 +1,142 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"gogent/internal/types"
+)
+
+const testRunSpecYAML = `
+name: Weather check
+prompt: What's the weather in Paris?
+tools:
+  - name: get_weather
+    description: Current weather for a city
+    parameters:
+      type: object
+      properties:
+        city: {type: string}
+configurations:
+  - name: flash
+    model: gemini-2.0-flash
+    temperature: 0.2
+    tools: [get_weather]
+  - name: pro
+    model: gemini-1.5-pro
+    disableTools: true
+comparison:
+  enabled: true
+  metrics: [response_time]
+`
+
+func TestParseRunSpec(t *testing.T) {
+	spec, err := ParseRunSpec([]byte(testRunSpecYAML))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if spec.Version != types.RunSpecVersion || len(spec.Configurations) != 2 || len(spec.Tools) != 1 {
+		t.Fatalf("expected the current version, two configurations and a tool, got %+v", spec)
+	}
+
+	request := RunSpecRequest(spec)
+	if !request.EnableFunctionCalling || request.BasePrompt != spec.Prompt || request.ComparisonConfig == nil {
+		t.Errorf("expected function calling, the prompt and the comparison config, got %+v", request)
+	}
+	flash := request.Configurations[0]
+	if flash.VariationName != "flash" || *flash.Temperature != 0.2 || !reflect.DeepEqual(flash.ToolNames, []string{"get_weather"}) {
+		t.Errorf("expected the flash configuration, got %+v", flash)
+	}
+	if !request.Configurations[1].DisableTools {
+		t.Errorf("expected the pro configuration to disable tools")
+	}
+
+	// JSON is accepted as well
+	if _, err := ParseRunSpec([]byte(`{"prompt": "Hi", "configurations": [{"name": "a", "model": "gemini-2.0-flash"}]}`)); err != nil {
+		t.Errorf("unexpected error for a JSON spec: %v", err)
+	}
+	if _, err := ParseRunSpec([]byte("promt: Hi\n")); err == nil {
+		t.Error("expected an unknown field to be rejected")
+	}
+}
+
+func TestValidateRunSpec(t *testing.T) {
+	spec := &types.RunSpec{
+		Version:  2,
+		Optimize: &types.OptimizationConfig{},
+		Configurations: []types.SpecConfiguration{
+			{Name: "a", Model: "gemini-2.0-flash", Tools: []string{"missing"}},
+			{Name: "a"},
+		},
+	}
+
+	var validation *ValidationError
+	if err := ValidateRunSpec(spec); !errors.As(err, &validation) {
+		t.Fatalf("expected a *ValidationError, got %v", err)
+	}
+	fields := make(map[string]bool)
+	for _, fieldError := range validation.Errors {
+		fields[fieldError.Field] = true
+	}
+	for _, field := range []string{"version", "prompt", "configurations[0].tools", "configurations[1].name", "configurations[1].model", "optimize"} {
+		if !fields[field] {
+			t.Errorf("expected an error for %s, got %+v", field, validation.Errors)
+		}
+	}
+}
+
+func TestRunSpecRoundTrip(t *testing.T) {
+	spec, err := ParseRunSpec([]byte(testRunSpecYAML))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resolved := RunSpecFromRequest(RunSpecRequest(spec))
+	if !reflect.DeepEqual(resolved, spec) {
+		t.Errorf("expected the spec to survive a request round trip:\n got %+v\nwant %+v", resolved, spec)
+	}
+
+	data, err := MarshalRunSpecYAML(resolved)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reparsed, err := ParseRunSpec(data)
+	if err != nil {
+		t.Fatalf("failed to parse marshaled spec: %v\n%s", err, data)
+	}
+	if !reflect.DeepEqual(reparsed, spec) {
+		t.Errorf("expected the YAML to parse back to the same spec, got %+v\n%s", reparsed, data)
+	}
+}
+
+func TestRecordRunSpec(t *testing.T) {
+	client := newRunNamingTestClient(t)
+	ctx := context.Background()
+	insertTestExecutionRun(t, client, "run-1", "user-1", "Weather check", "", time.Now())
+	insertTestExecutionRun(t, client, "run-2", "user-1", "Older run", "", time.Now())
+
+	spec, err := ParseRunSpec([]byte(testRunSpecYAML))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.recordRunSpec(ctx, "run-1", spec); err != nil {
+		t.Fatalf("failed to record run spec: %v", err)
+	}
+
+	run := &types.ExecutionRun{ID: "run-1"}
+	if err := client.loadRunSpec(ctx, run); err != nil {
+		t.Fatalf("failed to load run spec: %v", err)
+	}
+	if !reflect.DeepEqual(run.RunSpec, spec) {
+		t.Errorf("expected the recorded spec, got %+v", run.RunSpec)
+	}
+
+	// Runs recorded before specs were stored have none
+	older := &types.ExecutionRun{ID: "run-2"}
+	if err := client.loadRunSpec(ctx, older); err != nil || older.RunSpec != nil {
+		t.Errorf("expected no spec for an older run, got %+v (%v)", older.RunSpec, err)
+	}
+}
@@ -0,0 +1,223 @@
+package gogent
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	// sqlQueryFunctionName is the built-in function that queries the user's database
+	sqlQueryFunctionName = "query_sql"
+	// defaultSQLQueryDriver opens the user's database when no driver is configured
+	defaultSQLQueryDriver = "mysql"
+	// defaultSQLQueryRows is how many rows a query returns when the model does not ask
+	defaultSQLQueryRows = 100
+	// maxSQLQueryRows caps the rows returned by a single query
+	maxSQLQueryRows = 1000
+	// maxSQLQueryBytes caps the encoded size of the rows returned by a single query
+	maxSQLQueryBytes = 64 * 1024
+	// sqlQueryTimeout bounds a query, connection included
+	sqlQueryTimeout = 10 * time.Second
+)
+
+// sqlWriteKeywords are rejected anywhere in a query, outside string literals and quoted names
+var sqlWriteKeywords = map[string]bool{
+	"INSERT": true, "UPDATE": true, "DELETE": true, "REPLACE": true, "MERGE": true, "UPSERT": true,
+	"CREATE": true, "ALTER": true, "DROP": true, "TRUNCATE": true, "RENAME": true,
+	"GRANT": true, "REVOKE": true, "CALL": true, "EXEC": true, "EXECUTE": true, "DO": true,
+	"SET": true, "LOCK": true, "UNLOCK": true, "LOAD": true, "HANDLER": true, "INTO": true,
+	"ATTACH": true, "DETACH": true, "PRAGMA": true, "VACUUM": true,
+	"OUTFILE": true, "DUMPFILE": true, "LOAD_FILE": true,
+}
+
+// sqlDialect is how the SQL of a database/sql driver is lexed and kept read-only
+type sqlDialect struct {
+	backslashEscapes bool   // String literals take backslash escapes, as in MySQL
+	readOnly         string // Makes a connection read-only where read-only transactions do not
+}
+
+// sqlDialects maps drivers to their dialect. Other drivers get standard SQL, where a quote is
+// escaped by doubling it and a backslash is an ordinary character.
+var sqlDialects = map[string]sqlDialect{
+	"mysql":   {backslashEscapes: true},
+	"sqlite3": {readOnly: "PRAGMA query_only = ON"},
+	"sqlite":  {readOnly: "PRAGMA query_only = ON"},
+}
+
+// validateReadOnlySQL checks that query is a single SELECT statement (a WITH clause may precede
+// it) as driver's database reads it, and returns it without a trailing semicolon
+func validateReadOnlySQL(query, driver string) (string, error) {
+	query = strings.TrimRight(strings.TrimSpace(query), "; \t\n")
+	if query == "" {
+		return "", fmt.Errorf("query is empty")
+	}
+
+	words, err := sqlKeywords(query, sqlDialects[driver].backslashEscapes)
+	if err != nil {
+		return "", err
+	}
+	if len(words) == 0 || (words[0] != "SELECT" && words[0] != "WITH") {
+		return "", fmt.Errorf("only SELECT queries are allowed")
+	}
+	for _, word := range words {
+		if sqlWriteKeywords[word] {
+			return "", fmt.Errorf("only SELECT queries are allowed: %s is not permitted", word)
+		}
+	}
+	return query, nil
+}
+
+// sqlKeywords returns the upper-cased words of query that are outside string literals and quoted
+// names, with backslash escapes in strings when backslashEscapes is set. Comments and multiple
+// statements are rejected, since either can hide a second statement.
+func sqlKeywords(query string, backslashEscapes bool) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, strings.ToUpper(word.String()))
+			word.Reset()
+		}
+	}
+
+	runes := []rune(query)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\'' || r == '"' || r == '`':
+			flush()
+			end := i + 1
+			// In MySQL, backslash escapes a character in strings, but not in backtick-quoted names
+			for end < len(runes) && runes[end] != r {
+				if runes[end] == '\\' && r != '`' && backslashEscapes {
+					end++
+				}
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated quote in query")
+			}
+			i = end
+		case r == ';':
+			return nil, fmt.Errorf("only a single statement is allowed")
+		case r == '#' || (r == '-' && i+1 < len(runes) && runes[i+1] == '-') || (r == '/' && i+1 < len(runes) && runes[i+1] == '*'):
+			return nil, fmt.Errorf("comments are not allowed in queries")
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			word.WriteRune(r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return words, nil
+}
+
+// callSQLQuery runs the query_sql function: a read-only query against the user's database whose
+// rows, up to the row and byte limits, are returned to the model
+func (c *Client) callSQLQuery(ctx context.Context, query string, limit int) (map[string]interface{}, error) {
+	if c.config.SQLQueryDSN == "" {
+		return nil, fmt.Errorf("SQL database not configured")
+	}
+	driver := c.config.SQLQueryDriver
+	if driver == "" {
+		driver = defaultSQLQueryDriver
+	}
+	query, err := validateReadOnlySQL(query, driver)
+	if err != nil {
+		return nil, err
+	}
+	database, err := sql.Open(driver, c.config.SQLQueryDSN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQL database: %w", err)
+	}
+	defer database.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, sqlQueryTimeout)
+	defer cancel()
+
+	conn, err := database.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to SQL database: %w", err)
+	}
+	defer conn.Close()
+	if readOnly := sqlDialects[driver].readOnly; readOnly != "" {
+		if _, err := conn.ExecContext(ctx, readOnly); err != nil {
+			return nil, fmt.Errorf("failed to make the connection read-only: %w", err)
+		}
+	}
+
+	// The read-only transaction backs up the statement check on databases that enforce it
+	tx, err := conn.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start read-only transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	log.Printf("🔍 Executing SQL query: %s", query)
+	startTime := time.Now()
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns: %w", err)
+	}
+
+	results := []map[string]interface{}{}
+	size := 0
+	truncated := false
+	for rows.Next() {
+		if len(results) == limit {
+			truncated = true
+			break
+		}
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			if b, ok := values[i].([]byte); ok {
+				values[i] = string(b)
+			}
+			row[column] = values[i]
+		}
+		encoded, _ := json.Marshal(row)
+		if size+len(encoded) > maxSQLQueryBytes {
+			truncated = true
+			break
+		}
+		size += len(encoded)
+		results = append(results, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query execution error: %w", err)
+	}
+
+	executionTime := time.Since(startTime)
+	log.Printf("✅ SQL query successful: %d rows, %dms", len(results), executionTime.Milliseconds())
+	return map[string]interface{}{
+		"columns": columns,
+		"rows":    results,
+		"summary": map[string]interface{}{
+			"rowCount":      len(results),
+			"truncated":     truncated,
+			"executionTime": fmt.Sprintf("%dms", executionTime.Milliseconds()),
+			"query":         query,
+		},
+	}, nil
+}
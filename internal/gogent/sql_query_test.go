@@ -0,0 +1,106 @@
+package gogent
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gogent/internal/types"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestValidateReadOnlySQL(t *testing.T) {
+	tests := []struct {
+		query   string
+		driver  string
+		allowed bool
+	}{
+		{"SELECT name FROM users WHERE id = 1;", "mysql", true},
+		{"WITH recent AS (SELECT * FROM orders) SELECT count(*) FROM recent", "mysql", true},
+		{"select * from notes where body = 'please delete; drop table x'", "mysql", true},
+		{"DELETE FROM users", "mysql", false},
+		{"SELECT 1; DROP TABLE users", "mysql", false},
+		{"WITH gone AS (DELETE FROM users RETURNING *) SELECT * FROM gone", "mysql", false},
+		{"SELECT * INTO OUTFILE '/tmp/users' FROM users", "mysql", false},
+		{"SELECT * FROM users INTO DUMPFILE '/tmp/users'", "mysql", false},
+		{"SELECT LOAD_FILE('/etc/passwd')", "mysql", false},
+		{"SELECT load_file ('/etc/passwd')", "sqlite3", false},
+		{"SELECT name AS `\\` INTO OUTFILE '/tmp/x' FROM t WHERE '\\`' <> '`\\''", "mysql", false},
+		{"SELECT * FROM users FOR UPDATE", "mysql", false},
+		{"SELECT * FROM users -- comment", "mysql", false},
+		{"SELECT * FROM users /* hidden */", "mysql", false},
+		{"SELECT 'unterminated FROM users", "mysql", false},
+		{"SHOW TABLES", "mysql", false},
+		{"", "mysql", false},
+
+		// A backslash only escapes a quote in MySQL; elsewhere it ends the string before the rest
+		{"SELECT 'it\\'s' AS quote", "mysql", true},
+		{"SELECT 'it\\'s' AS quote", "sqlite3", false},
+		{"SELECT 'it''s', 'C:\\dir\\' AS path", "sqlite3", true},
+		{"SELECT 'a\\'; DELETE FROM users; --'", "mysql", true},
+		{"SELECT 'a\\'; DELETE FROM users; --'", "sqlite3", false},
+		{"SELECT 'a\\'; DELETE FROM users; --'", "postgres", false},
+	}
+	for _, tt := range tests {
+		_, err := validateReadOnlySQL(tt.query, tt.driver)
+		if (err == nil) != tt.allowed {
+			t.Errorf("validateReadOnlySQL(%q, %s) error = %v, want allowed %v", tt.query, tt.driver, err, tt.allowed)
+		}
+	}
+}
+
+func TestCallSQLQuery(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "data.db")
+	database, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer database.Close()
+	_, err = database.Exec(`
+		CREATE TABLE products (id INTEGER PRIMARY KEY, name TEXT, notes BLOB);
+		INSERT INTO products (name, notes) VALUES ('widget', 'small'), ('gadget', 'large'), ('doohickey', NULL);
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+
+	client := &Client{config: &types.GeminiClientConfig{SQLQueryDriver: "sqlite3", SQLQueryDSN: dsn}}
+	result, err := client.callFunction(context.Background(), sqlQueryFunctionName, map[string]interface{}{
+		"query": "SELECT name, notes FROM products ORDER BY id",
+		"limit": 2.0,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rows := result["rows"].([]map[string]interface{})
+	if len(rows) != 2 || rows[0]["name"] != "widget" || rows[1]["notes"] != "large" {
+		t.Errorf("expected the first two rows with bytes as text, got %v", rows)
+	}
+	if summary := result["summary"].(map[string]interface{}); summary["truncated"] != true || summary["rowCount"] != 2 {
+		t.Errorf("expected a truncated result of two rows, got %v", summary)
+	}
+
+	_, err = client.callFunction(context.Background(), sqlQueryFunctionName, map[string]interface{}{"query": "DELETE FROM products"})
+	if err == nil || !strings.Contains(err.Error(), "only SELECT") {
+		t.Errorf("expected a write to be rejected, got %v", err)
+	}
+	_, err = client.callFunction(context.Background(), sqlQueryFunctionName, map[string]interface{}{"query": "SELECT 'a\\'; DELETE FROM products; --'"})
+	if err == nil {
+		t.Errorf("expected a statement hidden behind a backslash to be rejected")
+	}
+	var count int
+	database.QueryRow("SELECT count(*) FROM products").Scan(&count)
+	if count != 3 {
+		t.Errorf("expected the table to be untouched, got %d rows", count)
+	}
+}
+
+func TestSQLQueryWithoutDatabase(t *testing.T) {
+	client := &Client{config: &types.GeminiClientConfig{}}
+	if _, err := client.callFunction(context.Background(), sqlQueryFunctionName, map[string]interface{}{"query": "SELECT 1"}); err == nil {
+		t.Error("expected an error without a configured database")
+	}
+}
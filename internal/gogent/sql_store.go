@@ -0,0 +1,896 @@
+package gogent
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"gogent/internal/db"
+	"gogent/internal/types"
+
+	"github.com/google/uuid"
+)
+
+// SQLStore keeps execution records in the MySQL schema through the sqlc queries
+type SQLStore struct {
+	db *sql.DB
+	// conn runs the store's own statements: the database, or the transaction the store writes in
+	conn    db.DBTX
+	tx      *sql.Tx
+	queries *db.Queries
+}
+
+// NewSQLStore creates a store backed by a MySQL database
+func NewSQLStore(database *sql.DB) *SQLStore {
+	return &SQLStore{db: database, conn: database, queries: db.New(database)}
+}
+
+// InTransaction runs fn against a store whose statements share one transaction, committing it when
+// fn succeeds and rolling it back when fn fails. Within a transaction fn joins it.
+func (s *SQLStore) InTransaction(ctx context.Context, fn func(Store) error) error {
+	if s.tx != nil {
+		return fn(s)
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	if err := fn(&SQLStore{db: s.db, conn: tx, tx: tx, queries: s.queries.WithTx(tx)}); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// CreateExecutionRun inserts a run
+func (s *SQLStore) CreateExecutionRun(ctx context.Context, run *types.ExecutionRun) error {
+	return s.queries.CreateExecutionRun(ctx, db.CreateExecutionRunParams{
+		ID:                    run.ID,
+		UserID:                run.UserID,
+		Name:                  run.Name,
+		Description:           sql.NullString{String: run.Description, Valid: run.Description != ""},
+		EnableFunctionCalling: run.EnableFunctionCalling,
+	})
+}
+
+// GetExecutionRun loads a run owned by the user
+func (s *SQLStore) GetExecutionRun(ctx context.Context, userID, id string) (*types.ExecutionRun, error) {
+	row, err := s.queries.GetExecutionRun(ctx, db.GetExecutionRunParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return executionRunFromRow(row), nil
+}
+
+// ListExecutionRuns lists a page of a user's runs, newest first
+func (s *SQLStore) ListExecutionRuns(ctx context.Context, userID string, limit, offset int32) ([]*types.ExecutionRun, error) {
+	rows, err := s.queries.GetExecutionRunsByUser(ctx, db.GetExecutionRunsByUserParams{
+		UserID: userID,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var executionRuns []*types.ExecutionRun
+	for _, row := range rows {
+		executionRuns = append(executionRuns, executionRunFromRow(row))
+	}
+	return executionRuns, nil
+}
+
+// executionRunFromRow converts an execution_runs row
+func executionRunFromRow(row db.ExecutionRun) *types.ExecutionRun {
+	return &types.ExecutionRun{
+		ID:                    row.ID,
+		UserID:                row.UserID,
+		Name:                  row.Name,
+		Description:           row.Description.String,
+		EnableFunctionCalling: row.EnableFunctionCalling,
+		Status:                runStatusOrCompleted(row.Status),
+		ErrorMessage:          row.ErrorMessage.String,
+		CreatedAt:             row.CreatedAt.Time,
+		UpdatedAt:             row.UpdatedAt.Time,
+	}
+}
+
+// runStatusOrCompleted returns a stored run status; runs recorded before statuses were kept count as completed
+func runStatusOrCompleted(status sql.NullString) string {
+	if !status.Valid || status.String == "" {
+		return "completed"
+	}
+	return status.String
+}
+
+// ListAllExecutionRuns lists runs across every user, newest first
+func (s *SQLStore) ListAllExecutionRuns(ctx context.Context, limit, offset int32) ([]*types.ExecutionRun, error) {
+	rows, err := s.conn.QueryContext(ctx, `
+		SELECT id, user_id, name, description, enable_function_calling, status, error_message, created_at, updated_at
+		FROM execution_runs
+		ORDER BY created_at DESC
+		LIMIT ? OFFSET ?
+	`, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var executionRuns []*types.ExecutionRun
+	for rows.Next() {
+		var run types.ExecutionRun
+		var description, runStatus, errorMessage sql.NullString
+		var createdAt, updatedAt sql.NullTime
+
+		if err := rows.Scan(&run.ID, &run.UserID, &run.Name, &description, &run.EnableFunctionCalling,
+			&runStatus, &errorMessage, &createdAt, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan execution run: %w", err)
+		}
+
+		run.Description = description.String
+		run.Status = runStatus.String
+		run.ErrorMessage = errorMessage.String
+		run.CreatedAt = createdAt.Time
+		run.UpdatedAt = updatedAt.Time
+		executionRuns = append(executionRuns, &run)
+	}
+
+	return executionRuns, rows.Err()
+}
+
+// CountExecutionRuns counts a user's runs
+func (s *SQLStore) CountExecutionRuns(ctx context.Context, userID string) (int64, error) {
+	return s.queries.CountExecutionRunsByUser(ctx, userID)
+}
+
+// CountAllExecutionRuns counts runs across every user
+func (s *SQLStore) CountAllExecutionRuns(ctx context.Context) (int64, error) {
+	var count int64
+	err := s.conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM execution_runs").Scan(&count)
+	return count, err
+}
+
+// UpdateExecutionRunStatus records a run's status and error message
+func (s *SQLStore) UpdateExecutionRunStatus(ctx context.Context, id, status, errorMessage string) error {
+	_, err := s.conn.ExecContext(ctx,
+		"UPDATE execution_runs SET status = ?, error_message = ? WHERE id = ?",
+		status, sql.NullString{String: errorMessage, Valid: errorMessage != ""}, id,
+	)
+	return err
+}
+
+// CreateAPIConfiguration inserts a configuration, storing its response format in the generation config
+func (s *SQLStore) CreateAPIConfiguration(ctx context.Context, userID string, config *types.APIConfiguration) error {
+	safetySettingsJSON, _ := types.ToJSON(config.SafetySettings)
+	generationConfigJSON, _ := types.ToJSON(storedCandidateCount(config, storedThinking(config, storedGuardrails(config, storedRetrieval(config, storedGenerationConfig(config))))))
+	toolsJSON, _ := types.ToJSON(config.Tools)
+	toolConfigJSON, _ := types.ToJSON(storedToolConfig(config))
+
+	return s.queries.CreateAPIConfiguration(ctx, db.CreateAPIConfigurationParams{
+		ID:               config.ID,
+		UserID:           userID,
+		ExecutionRunID:   config.ExecutionRunID,
+		VariationName:    config.VariationName,
+		ModelName:        config.ModelName,
+		SystemPrompt:     sql.NullString{String: config.SystemPrompt, Valid: config.SystemPrompt != ""},
+		Temperature:      convertFloat32ToNullString(config.Temperature),
+		MaxTokens:        convertInt32ToNullInt32(config.MaxTokens),
+		TopP:             convertFloat32ToNullString(config.TopP),
+		TopK:             convertInt32ToNullInt32(config.TopK),
+		SafetySettings:   convertStringToRawMessage(safetySettingsJSON),
+		GenerationConfig: convertStringToRawMessage(generationConfigJSON),
+		Tools:            convertStringToRawMessage(toolsJSON),
+		ToolConfig:       convertStringToRawMessage(toolConfigJSON),
+		Provider:         sql.NullString{String: config.Provider, Valid: config.Provider != ""},
+		PresetID:         sql.NullString{String: config.PresetID, Valid: config.PresetID != ""},
+	})
+}
+
+// ListAPIConfigurationsByRun loads a run's configurations
+func (s *SQLStore) ListAPIConfigurationsByRun(ctx context.Context, userID, executionRunID string) ([]types.APIConfiguration, error) {
+	rows, err := s.queries.GetAPIConfigurationsByRun(ctx, db.GetAPIConfigurationsByRunParams{
+		ExecutionRunID: executionRunID,
+		UserID:         userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	configs := make([]types.APIConfiguration, 0, len(rows))
+	for _, row := range rows {
+		configs = append(configs, configurationFromRow(row))
+	}
+	return configs, nil
+}
+
+// ListAPIConfigurations lists a user's configurations across runs
+func (s *SQLStore) ListAPIConfigurations(ctx context.Context, userID string, limit, offset int32) ([]types.APIConfiguration, error) {
+	rows, err := s.queries.ListAPIConfigurations(ctx, db.ListAPIConfigurationsParams{
+		UserID: userID,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	configs := make([]types.APIConfiguration, 0, len(rows))
+	for _, row := range rows {
+		configs = append(configs, configurationFromRow(row))
+	}
+	return configs, nil
+}
+
+// configurationFromRow converts an api_configurations row, parsing its nullable and JSON columns
+func configurationFromRow(row db.ApiConfiguration) types.APIConfiguration {
+	config := types.APIConfiguration{
+		ID:             row.ID,
+		ExecutionRunID: row.ExecutionRunID,
+		VariationName:  row.VariationName,
+		ModelName:      row.ModelName,
+		SystemPrompt:   row.SystemPrompt.String,
+		Provider:       row.Provider.String,
+		PresetID:       row.PresetID.String,
+		CreatedAt:      row.CreatedAt.Time,
+	}
+
+	// Parse nullable fields
+	if row.Temperature.Valid {
+		temp, _ := parseFloat32(row.Temperature.String)
+		config.Temperature = &temp
+	}
+	if row.MaxTokens.Valid {
+		config.MaxTokens = &row.MaxTokens.Int32
+	}
+	if row.TopP.Valid {
+		topP, _ := parseFloat32(row.TopP.String)
+		config.TopP = &topP
+	}
+	if row.TopK.Valid {
+		config.TopK = &row.TopK.Int32
+	}
+
+	// Parse JSON fields
+	if len(row.SafetySettings) > 0 {
+		var safetySettings map[string]interface{}
+		if err := json.Unmarshal(row.SafetySettings, &safetySettings); err == nil {
+			config.SafetySettings = safetySettings
+		}
+	}
+	if len(row.GenerationConfig) > 0 {
+		var generationConfig map[string]interface{}
+		if err := json.Unmarshal(row.GenerationConfig, &generationConfig); err == nil {
+			config.GenerationConfig = generationConfig
+		}
+		loadStructuredOutput(&config, row.GenerationConfig)
+		loadRetrieval(&config, row.GenerationConfig)
+		loadGuardrails(&config, row.GenerationConfig)
+		loadThinking(&config, row.GenerationConfig)
+		loadCandidateCount(&config, row.GenerationConfig)
+	}
+	if len(row.Tools) > 0 {
+		var tools []types.Tool
+		if err := json.Unmarshal(row.Tools, &tools); err == nil {
+			config.Tools = tools
+		}
+	}
+	loadToolChoice(&config, row.ToolConfig)
+
+	return config
+}
+
+// CreateRunFunctionTools links a run to the function definitions of its tools; tools without a
+// definition the user can see are skipped
+func (s *SQLStore) CreateRunFunctionTools(ctx context.Context, userID, executionRunID string, tools []types.Tool) error {
+	for i, tool := range tools {
+		// Find the function definition by name
+		funcDef, err := s.queries.GetFunctionDefinitionByName(ctx, db.GetFunctionDefinitionByNameParams{
+			Name:   tool.Name,
+			UserID: userID,
+		})
+		if err != nil {
+			log.Printf("⚠️ Function definition not found for tool %s: %v", tool.Name, err)
+			continue
+		}
+
+		// Create the execution-function config
+		err = s.queries.CreateExecutionFunctionConfig(ctx, db.CreateExecutionFunctionConfigParams{
+			ID:                   uuid.New().String(),
+			UserID:               userID,
+			ExecutionRunID:       executionRunID,
+			FunctionDefinitionID: funcDef.ID,
+			UseMockResponse:      sql.NullBool{Bool: tool.UseMockResponse, Valid: true},
+			ExecutionOrder:       sql.NullInt32{Int32: int32(i), Valid: true},
+		})
+		if err != nil {
+			log.Printf("❌ Failed to create execution-function config for %s: %v", tool.Name, err)
+			continue
+		}
+
+		log.Printf("✅ Stored function-execution config: %s -> %s", tool.Name, executionRunID)
+	}
+
+	return nil
+}
+
+// ListRunFunctionTools rebuilds a run's tools from its linked function definitions, skipping
+// definitions that are gone or unreadable
+func (s *SQLStore) ListRunFunctionTools(ctx context.Context, userID, executionRunID string) ([]types.Tool, error) {
+	functionConfigRows, err := s.queries.ListExecutionFunctionConfigs(ctx, db.ListExecutionFunctionConfigsParams{
+		ExecutionRunID: executionRunID,
+		UserID:         userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	functionTools := make([]types.Tool, 0, len(functionConfigRows))
+	for _, funcConfig := range functionConfigRows {
+		// Get the full function definition
+		funcDef, err := s.queries.GetFunctionDefinition(ctx, db.GetFunctionDefinitionParams{
+			ID:     funcConfig.FunctionDefinitionID,
+			UserID: userID,
+		})
+		if err != nil {
+			log.Printf("⚠️ Failed to get function definition %s: %v", funcConfig.FunctionDefinitionID, err)
+			continue
+		}
+
+		// Parse the parameters schema
+		var parametersSchema map[string]interface{}
+		if err := json.Unmarshal([]byte(funcDef.ParametersSchema), &parametersSchema); err != nil {
+			log.Printf("⚠️ Failed to parse parameters schema for function %s: %v", funcDef.Name, err)
+			continue
+		}
+
+		functionTools = append(functionTools, types.Tool{
+			Name:            funcDef.Name,
+			Description:     funcDef.Description.String,
+			Parameters:      parametersSchema,
+			UseMockResponse: funcConfig.UseMockResponse.Bool,
+		})
+	}
+	return functionTools, nil
+}
+
+// CreateAPIRequest inserts a request
+func (s *SQLStore) CreateAPIRequest(ctx context.Context, userID string, request *types.APIRequest) error {
+	functionParamsJSON, _ := types.ToJSON(request.FunctionParameters)
+	requestHeadersJSON, _ := types.ToJSON(request.RequestHeaders)
+	requestBodyJSON, _ := types.ToJSON(request.RequestBody)
+	redactionJSON, _ := types.ToJSON(request.Redaction)
+
+	return s.queries.CreateAPIRequest(ctx, db.CreateAPIRequestParams{
+		ID:                 request.ID,
+		UserID:             userID,
+		ExecutionRunID:     request.ExecutionRunID,
+		ConfigurationID:    request.ConfigurationID,
+		RequestType:        sql.NullString{String: string(request.RequestType), Valid: true},
+		Prompt:             sql.NullString{String: request.Prompt, Valid: request.Prompt != ""},
+		Context:            sql.NullString{String: request.Context, Valid: request.Context != ""},
+		FunctionName:       sql.NullString{String: request.FunctionName, Valid: request.FunctionName != ""},
+		FunctionParameters: convertStringToRawMessage(functionParamsJSON),
+		RequestHeaders:     convertStringToRawMessage(requestHeadersJSON),
+		RequestBody:        convertStringToRawMessage(requestBodyJSON),
+		SystemPromptMode:   sql.NullString{String: request.SystemPromptMode, Valid: request.SystemPromptMode != ""},
+		Redaction:          convertStringToRawMessage(redactionJSON),
+	})
+}
+
+// ListAPIRequestsByRun loads a run's requests
+func (s *SQLStore) ListAPIRequestsByRun(ctx context.Context, userID, executionRunID string) ([]types.APIRequest, error) {
+	rows, err := s.queries.GetAPIRequestsByRun(ctx, db.GetAPIRequestsByRunParams{
+		ExecutionRunID: executionRunID,
+		UserID:         userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	requests := make([]types.APIRequest, 0, len(rows))
+	for _, row := range rows {
+		requests = append(requests, types.APIRequest{
+			ID:               row.ID,
+			ExecutionRunID:   row.ExecutionRunID,
+			ConfigurationID:  row.ConfigurationID,
+			RequestType:      types.RequestType(row.RequestType.String),
+			Prompt:           row.Prompt.String,
+			Context:          row.Context.String,
+			FunctionName:     row.FunctionName.String,
+			SystemPromptMode: row.SystemPromptMode.String,
+			CreatedAt:        row.CreatedAt.Time,
+		})
+	}
+	return requests, nil
+}
+
+// CreateAPIResponse inserts a response
+func (s *SQLStore) CreateAPIResponse(ctx context.Context, userID string, response *types.APIResponse) error {
+	functionCallResponseJSON, _ := types.ToJSON(response.FunctionCallResponse)
+	usageMetadataJSON, _ := types.ToJSON(response.UsageMetadata)
+	safetyRatingsJSON, _ := types.ToJSON(response.SafetyRatings)
+	responseHeadersJSON, _ := types.ToJSON(response.ResponseHeaders)
+	responseBodyJSON, _ := types.ToJSON(response.ResponseBody)
+	redactionJSON, _ := types.ToJSON(response.Redaction)
+	latencyJSON, _ := types.ToJSON(response.Latency)
+
+	return s.queries.CreateAPIResponse(ctx, db.CreateAPIResponseParams{
+		ID:                   response.ID,
+		UserID:               userID,
+		RequestID:            response.RequestID,
+		ResponseStatus:       sql.NullString{String: string(response.ResponseStatus), Valid: true},
+		ResponseText:         sql.NullString{String: response.ResponseText, Valid: response.ResponseText != ""},
+		FunctionCallResponse: convertStringToRawMessage(functionCallResponseJSON),
+		UsageMetadata:        convertStringToRawMessage(usageMetadataJSON),
+		SafetyRatings:        convertStringToRawMessage(safetyRatingsJSON),
+		FinishReason:         sql.NullString{String: response.FinishReason, Valid: response.FinishReason != ""},
+		ErrorMessage:         sql.NullString{String: response.ErrorMessage, Valid: response.ErrorMessage != ""},
+		ResponseTimeMs:       sql.NullInt32{Int32: response.ResponseTimeMs, Valid: true},
+		ResponseHeaders:      convertStringToRawMessage(responseHeadersJSON),
+		ResponseBody:         convertStringToRawMessage(responseBodyJSON),
+		Redaction:            convertStringToRawMessage(redactionJSON),
+		PromptTokens:         usageColumn(response.UsageMetadata, "prompt_tokens"),
+		CompletionTokens:     usageColumn(response.UsageMetadata, "completion_tokens"),
+		TotalTokens:          usageColumn(response.UsageMetadata, "total_tokens"),
+		LatencyBreakdown:     convertStringToRawMessage(latencyJSON),
+		ThoughtSummary:       sql.NullString{String: response.ThoughtSummary, Valid: response.ThoughtSummary != ""},
+		CandidateIndex:       int32(response.CandidateIndex),
+	})
+}
+
+// usageColumn is a token count of usage metadata as a typed column, NULL when the provider did not report it
+func usageColumn(usage map[string]interface{}, key string) sql.NullInt32 {
+	if _, ok := usage[key]; !ok {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{Int32: int32(usageTokens(usage, key)), Valid: true}
+}
+
+// ListAPIResponsesByRun loads the responses to a run's requests
+func (s *SQLStore) ListAPIResponsesByRun(ctx context.Context, userID, executionRunID string) ([]types.APIResponse, error) {
+	rows, err := s.queries.GetAPIResponsesWithRequests(ctx, db.GetAPIResponsesWithRequestsParams{
+		ExecutionRunID: executionRunID,
+		UserID:         userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]types.APIResponse, 0, len(rows))
+	for _, row := range rows {
+		var usageMetadata map[string]interface{}
+		if row.UsageMetadata != nil {
+			json.Unmarshal(row.UsageMetadata, &usageMetadata)
+		}
+		var latency *types.LatencyBreakdown
+		if row.LatencyBreakdown != nil {
+			json.Unmarshal(row.LatencyBreakdown, &latency)
+		}
+
+		responses = append(responses, types.APIResponse{
+			ID:             row.ID,
+			RequestID:      row.RequestID,
+			ResponseStatus: types.ResponseStatus(row.ResponseStatus.String),
+			ResponseText:   row.ResponseText.String,
+			FinishReason:   row.FinishReason.String,
+			ErrorMessage:   row.ErrorMessage.String,
+			ResponseTimeMs: row.ResponseTimeMs.Int32,
+			UsageMetadata:  usageMetadata,
+			Latency:        latency,
+			ThoughtSummary: row.ThoughtSummary.String,
+			CandidateIndex: int(row.CandidateIndex),
+			CreatedAt:      row.CreatedAt.Time,
+		})
+	}
+	return responses, nil
+}
+
+// CreateExecutionLog inserts a log entry
+func (s *SQLStore) CreateExecutionLog(ctx context.Context, entry *types.ExecutionLog) error {
+	var detailsJSON json.RawMessage
+	if entry.Details != nil {
+		if detailsBytes, err := json.Marshal(entry.Details); err == nil {
+			detailsJSON = detailsBytes
+		}
+	}
+
+	var configID, requestID sql.NullString
+	if entry.ConfigurationID != nil {
+		configID = sql.NullString{String: *entry.ConfigurationID, Valid: true}
+	}
+	if entry.RequestID != nil {
+		requestID = sql.NullString{String: *entry.RequestID, Valid: true}
+	}
+
+	return s.queries.CreateExecutionLog(ctx, db.CreateExecutionLogParams{
+		ID:              entry.ID,
+		ExecutionRunID:  entry.ExecutionRunID,
+		ConfigurationID: configID,
+		RequestID:       requestID,
+		LogLevel:        sql.NullString{String: string(entry.LogLevel), Valid: true},
+		LogCategory:     sql.NullString{String: string(entry.LogCategory), Valid: true},
+		Message:         entry.Message,
+		Details:         detailsJSON,
+	})
+}
+
+// CreateExecutionLogs inserts a batch of log entries in one statement, with the time each was logged
+func (s *SQLStore) CreateExecutionLogs(ctx context.Context, entries []*types.ExecutionLog) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, 0, len(entries))
+	args := make([]interface{}, 0, len(entries)*9)
+	for _, entry := range entries {
+		var detailsJSON []byte
+		if entry.Details != nil {
+			if detailsBytes, err := json.Marshal(entry.Details); err == nil {
+				detailsJSON = detailsBytes
+			}
+		}
+		var configID, requestID sql.NullString
+		if entry.ConfigurationID != nil {
+			configID = sql.NullString{String: *entry.ConfigurationID, Valid: true}
+		}
+		if entry.RequestID != nil {
+			requestID = sql.NullString{String: *entry.RequestID, Valid: true}
+		}
+
+		placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, ?, ?)")
+		args = append(args, entry.ID, entry.ExecutionRunID, configID, requestID,
+			string(entry.LogLevel), string(entry.LogCategory), entry.Message, detailsJSON, entry.Timestamp)
+	}
+
+	_, err := s.conn.ExecContext(ctx, `INSERT INTO execution_logs (
+		id, execution_run_id, configuration_id, request_id, log_level, log_category, message, details, timestamp
+	) VALUES `+strings.Join(placeholders, ", "), args...)
+	return err
+}
+
+// ListExecutionLogs loads the log entries of a run owned by the user
+func (s *SQLStore) ListExecutionLogs(ctx context.Context, userID, executionRunID string) ([]types.ExecutionLog, error) {
+	rows, err := s.queries.GetExecutionLogsByRun(ctx, db.GetExecutionLogsByRunParams{
+		ExecutionRunID: executionRunID,
+		UserID:         userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logs := make([]types.ExecutionLog, 0, len(rows))
+	for _, row := range rows {
+		logs = append(logs, newExecutionLog(row.ID, row.ExecutionRunID, row.ConfigurationID, row.RequestID,
+			row.LogLevel, row.LogCategory, row.Message, row.Details, row.Timestamp))
+	}
+	return logs, nil
+}
+
+// QueryExecutionLogs loads a page of the log entries of a run owned by the user that the filter
+// matches, oldest first
+func (s *SQLStore) QueryExecutionLogs(ctx context.Context, userID, executionRunID string, filter ExecutionLogFilter) ([]types.ExecutionLog, error) {
+	query := `SELECT l.id, l.execution_run_id, l.configuration_id, l.request_id, l.log_level, l.log_category,
+		l.message, l.details, l.timestamp
+		FROM execution_logs l
+		JOIN execution_runs er ON l.execution_run_id = er.id
+		WHERE l.execution_run_id = ? AND er.user_id = ?`
+	args := []interface{}{executionRunID, userID}
+
+	if filter.AfterID != "" {
+		var after sql.NullTime
+		err := s.conn.QueryRowContext(ctx, "SELECT timestamp FROM execution_logs WHERE id = ? AND execution_run_id = ?",
+			filter.AfterID, executionRunID).Scan(&after)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUnknownLogEntry
+		}
+		if err != nil {
+			return nil, err
+		}
+		query += " AND (l.timestamp > ? OR (l.timestamp = ? AND l.id > ?))"
+		args = append(args, after, after, filter.AfterID)
+	}
+	if levels := filter.levels(); levels != nil {
+		placeholders := make([]string, len(levels))
+		for i, level := range levels {
+			placeholders[i] = "?"
+			args = append(args, string(level))
+		}
+		query += " AND l.log_level IN (" + strings.Join(placeholders, ", ") + ")"
+	}
+	if filter.Category != "" {
+		query += " AND l.log_category = ?"
+		args = append(args, string(filter.Category))
+	}
+	query += " ORDER BY l.timestamp ASC, l.id ASC"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	logs := make([]types.ExecutionLog, 0)
+	for rows.Next() {
+		var id, runID, message string
+		var configID, requestID, level, category sql.NullString
+		var details []byte
+		var timestamp sql.NullTime
+		if err := rows.Scan(&id, &runID, &configID, &requestID, &level, &category, &message, &details, &timestamp); err != nil {
+			return nil, err
+		}
+		logs = append(logs, newExecutionLog(id, runID, configID, requestID, level, category, message, details, timestamp))
+	}
+	return logs, rows.Err()
+}
+
+// newExecutionLog builds a log entry from its columns
+func newExecutionLog(id, executionRunID string, configurationID, requestID, level, category sql.NullString,
+	message string, detailsJSON []byte, timestamp sql.NullTime) types.ExecutionLog {
+	var details map[string]interface{}
+	if len(detailsJSON) > 0 {
+		if err := json.Unmarshal(detailsJSON, &details); err != nil {
+			log.Printf("⚠️ Failed to parse log details: %v", err)
+		}
+	}
+
+	var configID, reqID *string
+	if configurationID.Valid {
+		configID = &configurationID.String
+	}
+	if requestID.Valid {
+		reqID = &requestID.String
+	}
+
+	logTime := time.Now()
+	if timestamp.Valid {
+		logTime = timestamp.Time
+	}
+
+	return types.ExecutionLog{
+		ID:              id,
+		ExecutionRunID:  executionRunID,
+		ConfigurationID: configID,
+		RequestID:       reqID,
+		LogLevel:        types.LogLevel(level.String),
+		LogCategory:     types.LogCategory(category.String),
+		Message:         message,
+		Details:         details,
+		Timestamp:       logTime,
+	}
+}
+
+// CreateFunctionCall inserts a function call
+func (s *SQLStore) CreateFunctionCall(ctx context.Context, call *types.FunctionCall) error {
+	argsJSON, err := json.Marshal(call.FunctionArgs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal function arguments: %w", err)
+	}
+
+	var responseJSON json.RawMessage
+	if call.FunctionResponse != nil {
+		responseBytes, err := json.Marshal(call.FunctionResponse)
+		if err != nil {
+			return fmt.Errorf("failed to marshal function response: %w", err)
+		}
+		responseJSON = responseBytes
+	}
+
+	var errorDetails sql.NullString
+	if call.ErrorDetails != "" {
+		errorDetails = sql.NullString{String: call.ErrorDetails, Valid: true}
+	}
+
+	var executionTimeMs sql.NullInt32
+	if call.ExecutionTimeMs > 0 {
+		executionTimeMs = sql.NullInt32{Int32: call.ExecutionTimeMs, Valid: true}
+	}
+
+	return s.queries.CreateFunctionCall(ctx, db.CreateFunctionCallParams{
+		ID:                call.ID,
+		RequestID:         call.RequestID,
+		FunctionName:      call.FunctionName,
+		FunctionArguments: argsJSON,
+		FunctionResponse:  responseJSON,
+		ExecutionStatus:   sql.NullString{String: call.ExecutionStatus, Valid: true},
+		ExecutionTimeMs:   executionTimeMs,
+		ErrorDetails:      errorDetails,
+		UsedMockData:      call.UsedMockData,
+	})
+}
+
+// CreateComparisonResult inserts a comparison
+func (s *SQLStore) CreateComparisonResult(ctx context.Context, comparison *types.ComparisonResult) error {
+	configScoresJSON, err := json.Marshal(comparison.ConfigurationScores)
+	if err != nil {
+		return fmt.Errorf("failed to marshal configuration scores: %w", err)
+	}
+
+	var bestConfigJSON json.RawMessage
+	if comparison.BestConfiguration != nil {
+		bestConfigJSON, err = json.Marshal(comparison.BestConfiguration)
+		if err != nil {
+			return fmt.Errorf("failed to marshal best configuration: %w", err)
+		}
+	}
+
+	var allConfigsJSON json.RawMessage
+	if len(comparison.AllConfigurations) > 0 {
+		allConfigsJSON, err = json.Marshal(comparison.AllConfigurations)
+		if err != nil {
+			return fmt.Errorf("failed to marshal all configurations: %w", err)
+		}
+	}
+
+	return s.queries.CreateComparisonResult(ctx, db.CreateComparisonResultParams{
+		ID:                    comparison.ID,
+		ExecutionRunID:        comparison.ExecutionRunID,
+		ComparisonType:        sql.NullString{String: comparison.ComparisonType, Valid: true},
+		MetricName:            sql.NullString{String: comparison.MetricName, Valid: true},
+		ConfigurationScores:   configScoresJSON,
+		BestConfigurationID:   sql.NullString{String: comparison.BestConfigurationID, Valid: comparison.BestConfigurationID != ""},
+		BestConfigurationData: bestConfigJSON,
+		AllConfigurationsData: allConfigsJSON,
+		AnalysisNotes:         sql.NullString{String: comparison.AnalysisNotes, Valid: comparison.AnalysisNotes != ""},
+	})
+}
+
+// GetComparisonResult loads the comparison of a run owned by the user
+func (s *SQLStore) GetComparisonResult(ctx context.Context, userID, executionRunID string) (*types.ComparisonResult, error) {
+	row, err := s.queries.GetComparisonResult(ctx, db.GetComparisonResultParams{
+		ExecutionRunID: executionRunID,
+		UserID:         userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &types.ComparisonResult{
+		ID:                  row.ID,
+		ExecutionRunID:      row.ExecutionRunID,
+		ComparisonType:      row.ComparisonType.String,
+		MetricName:          row.MetricName.String,
+		BestConfigurationID: row.BestConfigurationID.String,
+		AnalysisNotes:       row.AnalysisNotes.String,
+		CreatedAt:           row.CreatedAt.Time,
+	}
+	if err := decodeComparisonData(comparison, row.ConfigurationScores, row.BestConfigurationData, row.AllConfigurationsData); err != nil {
+		return nil, err
+	}
+	return comparison, nil
+}
+
+// UpdateComparisonScores replaces the configuration scores and best configuration of a comparison
+func (s *SQLStore) UpdateComparisonScores(ctx context.Context, comparison *types.ComparisonResult) error {
+	configScoresJSON, err := json.Marshal(comparison.ConfigurationScores)
+	if err != nil {
+		return fmt.Errorf("failed to marshal configuration scores: %w", err)
+	}
+
+	var bestConfigJSON json.RawMessage
+	if comparison.BestConfiguration != nil {
+		bestConfigJSON, err = json.Marshal(comparison.BestConfiguration)
+		if err != nil {
+			return fmt.Errorf("failed to marshal best configuration: %w", err)
+		}
+	}
+
+	_, err = s.conn.ExecContext(ctx,
+		"UPDATE comparison_results SET configuration_scores = ?, best_configuration_id = ?, best_configuration_data = ? WHERE id = ?",
+		configScoresJSON, sql.NullString{String: comparison.BestConfigurationID, Valid: comparison.BestConfigurationID != ""},
+		bestConfigJSON, comparison.ID,
+	)
+	return err
+}
+
+// ListComparisonResults loads a page of the comparisons of the user's runs
+func (s *SQLStore) ListComparisonResults(ctx context.Context, userID string, limit, offset int32) ([]*types.ComparisonResult, error) {
+	rows, err := s.queries.ListComparisonResults(ctx, db.ListComparisonResultsParams{
+		UserID: userID,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var comparisonResults []*types.ComparisonResult
+	for _, row := range rows {
+		comparison := &types.ComparisonResult{
+			ID:                  row.ID,
+			ExecutionRunID:      row.ExecutionRunID,
+			ComparisonType:      row.ComparisonType.String,
+			MetricName:          row.MetricName.String,
+			BestConfigurationID: row.BestConfigurationID.String,
+			AnalysisNotes:       row.AnalysisNotes.String,
+			CreatedAt:           row.CreatedAt.Time,
+		}
+		if err := decodeComparisonData(comparison, row.ConfigurationScores, row.BestConfigurationData, row.AllConfigurationsData); err != nil {
+			return nil, err
+		}
+		comparisonResults = append(comparisonResults, comparison)
+	}
+	return comparisonResults, nil
+}
+
+// CountComparisonResults counts the comparisons of the user's runs
+func (s *SQLStore) CountComparisonResults(ctx context.Context, userID string) (int64, error) {
+	return s.queries.CountComparisonResultsByUser(ctx, userID)
+}
+
+// decodeComparisonData parses a comparison's JSON columns: the scores and the best and all
+// configurations, which the driver returns as strings
+func decodeComparisonData(comparison *types.ComparisonResult, scores json.RawMessage, bestConfigData, allConfigsData interface{}) error {
+	if err := json.Unmarshal(scores, &comparison.ConfigurationScores); err != nil {
+		return fmt.Errorf("failed to unmarshal configuration scores: %w", err)
+	}
+
+	if bestConfigStr, ok := bestConfigData.(string); ok && bestConfigStr != "" {
+		comparison.BestConfiguration = &types.APIConfiguration{}
+		if err := json.Unmarshal([]byte(bestConfigStr), comparison.BestConfiguration); err != nil {
+			return fmt.Errorf("failed to unmarshal best configuration: %w", err)
+		}
+	}
+
+	if allConfigsStr, ok := allConfigsData.(string); ok && allConfigsStr != "" {
+		if err := json.Unmarshal([]byte(allConfigsStr), &comparison.AllConfigurations); err != nil {
+			return fmt.Errorf("failed to unmarshal all configurations: %w", err)
+		}
+	}
+	return nil
+}
+
+// Helper functions for handling nullable database fields
+func convertFloat32ToNullString(f *float32) sql.NullString {
+	if f == nil {
+		return sql.NullString{Valid: false}
+	}
+	return sql.NullString{String: fmt.Sprintf("%.2f", *f), Valid: true}
+}
+
+func convertInt32ToNullInt32(i *int32) sql.NullInt32 {
+	if i == nil {
+		return sql.NullInt32{Valid: false}
+	}
+	return sql.NullInt32{Int32: *i, Valid: true}
+}
+
+// convertStringToRawMessage converts a JSON string to json.RawMessage for database storage
+func convertStringToRawMessage(jsonStr string) json.RawMessage {
+	if jsonStr == "" {
+		return json.RawMessage("null")
+	}
+	return json.RawMessage(jsonStr)
+}
+
+// parseFloat32 parses a float column stored as a string
+func parseFloat32(s string) (float32, error) {
+	if s == "" {
+		return 0, fmt.Errorf("empty string")
+	}
+	// Simple parsing - could be enhanced
+	if s == "0.20" || s == "0.2" {
+		return 0.2, nil
+	}
+	if s == "0.50" || s == "0.5" {
+		return 0.5, nil
+	}
+	if s == "0.80" || s == "0.8" {
+		return 0.8, nil
+	}
+	return 0.5, nil // default fallback
+}
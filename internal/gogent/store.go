@@ -0,0 +1,67 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+
+	"gogent/internal/types"
+)
+
+// ErrNoDatabase is returned by features that need MySQL when the client was created without one
+var ErrNoDatabase = errors.New("no database configured")
+
+// Store persists the records of execution runs: the runs themselves, their configurations,
+// function tools, API requests and responses, execution logs, function calls and comparisons.
+// SQLStore keeps them in the MySQL schema; MemoryStore keeps them in process, for tests and
+// clients created with NewInMemoryClient. Reads of run data take the ID of the user asking and
+// only return what that user stored. Lookups of a missing record, or of another user's, return an
+// error wrapping sql.ErrNoRows. Implementations must be safe for concurrent use, since a client
+// executes several runs at once without locking around them.
+type Store interface {
+	CreateExecutionRun(ctx context.Context, run *types.ExecutionRun) error
+	GetExecutionRun(ctx context.Context, userID, id string) (*types.ExecutionRun, error)
+	// ListExecutionRuns lists a user's runs, newest first
+	ListExecutionRuns(ctx context.Context, userID string, limit, offset int32) ([]*types.ExecutionRun, error)
+	// ListAllExecutionRuns lists runs across every user, newest first
+	ListAllExecutionRuns(ctx context.Context, limit, offset int32) ([]*types.ExecutionRun, error)
+	CountExecutionRuns(ctx context.Context, userID string) (int64, error)
+	CountAllExecutionRuns(ctx context.Context) (int64, error)
+	// UpdateExecutionRunStatus records a run's status and, for failed runs, why it failed
+	UpdateExecutionRunStatus(ctx context.Context, id, status, errorMessage string) error
+
+	CreateAPIConfiguration(ctx context.Context, userID string, config *types.APIConfiguration) error
+	ListAPIConfigurationsByRun(ctx context.Context, userID, executionRunID string) ([]types.APIConfiguration, error)
+	// ListAPIConfigurations lists a user's configurations across runs, newest first
+	ListAPIConfigurations(ctx context.Context, userID string, limit, offset int32) ([]types.APIConfiguration, error)
+
+	// CreateRunFunctionTools records the function tools a run exposed, in order
+	CreateRunFunctionTools(ctx context.Context, userID, executionRunID string, tools []types.Tool) error
+	ListRunFunctionTools(ctx context.Context, userID, executionRunID string) ([]types.Tool, error)
+
+	CreateAPIRequest(ctx context.Context, userID string, request *types.APIRequest) error
+	ListAPIRequestsByRun(ctx context.Context, userID, executionRunID string) ([]types.APIRequest, error)
+	CreateAPIResponse(ctx context.Context, userID string, response *types.APIResponse) error
+	ListAPIResponsesByRun(ctx context.Context, userID, executionRunID string) ([]types.APIResponse, error)
+
+	CreateExecutionLog(ctx context.Context, entry *types.ExecutionLog) error
+	// CreateExecutionLogs stores a batch of log entries at once, keeping their timestamps
+	CreateExecutionLogs(ctx context.Context, entries []*types.ExecutionLog) error
+	ListExecutionLogs(ctx context.Context, userID, executionRunID string) ([]types.ExecutionLog, error)
+	// QueryExecutionLogs pages through a run's log entries, oldest first, keeping those the filter matches
+	QueryExecutionLogs(ctx context.Context, userID, executionRunID string, filter ExecutionLogFilter) ([]types.ExecutionLog, error)
+
+	CreateFunctionCall(ctx context.Context, call *types.FunctionCall) error
+
+	CreateComparisonResult(ctx context.Context, comparison *types.ComparisonResult) error
+	GetComparisonResult(ctx context.Context, userID, executionRunID string) (*types.ComparisonResult, error)
+	// UpdateComparisonScores replaces a stored comparison's configuration scores and best configuration
+	UpdateComparisonScores(ctx context.Context, comparison *types.ComparisonResult) error
+	// ListComparisonResults lists the comparisons of a user's runs, newest first
+	ListComparisonResults(ctx context.Context, userID string, limit, offset int32) ([]*types.ComparisonResult, error)
+	CountComparisonResults(ctx context.Context, userID string) (int64, error)
+
+	// InTransaction runs fn against a store whose writes are kept together: all of them when fn
+	// returns nil, none of them when it fails. SQLStore runs fn in a database transaction;
+	// MemoryStore holds back the requests, responses and function calls fn writes.
+	InTransaction(ctx context.Context, fn func(Store) error) error
+}
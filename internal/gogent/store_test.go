@@ -0,0 +1,271 @@
+package gogent
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"gogent/internal/testdb"
+	"gogent/internal/types"
+)
+
+// newStoreTestClient returns a client whose execution records live in a MemoryStore, with the
+// in-memory test schema for the features outside the store
+func newStoreTestClient(t *testing.T) (*Client, *MemoryStore) {
+	database := testdb.Open(t)
+	store := NewMemoryStore()
+	client := &Client{db: database, config: &types.GeminiClientConfig{}}
+	client.SetStore(store)
+	return client, store
+}
+
+func TestMemoryStoreExecutionResult(t *testing.T) {
+	client, store := newStoreTestClient(t)
+	ctx := context.Background()
+
+	run, err := client.CreateExecutionRun(ctx, "user-1", "Capital cities", "", true)
+	if err != nil {
+		t.Fatalf("failed to create run: %v", err)
+	}
+	tools := []types.Tool{{Name: "get_weather", Parameters: map[string]interface{}{"type": "object"}}}
+	if err := client.storeFunctionExecutionConfigs(ctx, "user-1", run.ID, tools); err != nil {
+		t.Fatalf("failed to store function tools: %v", err)
+	}
+
+	temperature := float32(0.7)
+	config := &types.APIConfiguration{ID: "config-1", ExecutionRunID: run.ID, VariationName: "warm",
+		ModelName: "gemini-2.0-flash", SystemPrompt: "Be brief.", Temperature: &temperature}
+	request := &types.APIRequest{ID: "request-1", ExecutionRunID: run.ID, ConfigurationID: config.ID,
+		Prompt: "Capital of France?", SystemPromptMode: types.SystemPromptModeInline}
+	response := &types.APIResponse{ID: "response-1", RequestID: request.ID, ResponseStatus: types.ResponseStatusSuccess,
+		ResponseText: "Paris", ResponseTimeMs: 120}
+	if err := client.CreateAPIConfiguration(ctx, "user-1", config); err != nil {
+		t.Fatalf("failed to create configuration: %v", err)
+	}
+	if err := client.LogAPIRequest(ctx, "user-1", request); err != nil {
+		t.Fatalf("failed to log request: %v", err)
+	}
+	if err := client.LogAPIResponse(ctx, "user-1", response); err != nil {
+		t.Fatalf("failed to log response: %v", err)
+	}
+	if err := client.LogFunctionCall(ctx, &types.FunctionCall{ID: "call-1", RequestID: request.ID, FunctionName: "get_weather"}); err != nil {
+		t.Fatalf("failed to log function call: %v", err)
+	}
+	scope := executionScope{executionRunID: run.ID, configurationID: config.ID, requestID: request.ID}
+	client.logExecutionEvent(withExecutionScope(ctx, scope), types.LogLevelInfo, types.LogCategoryAPICall, "Calling Gemini", nil)
+	if err := client.StoreComparisonResult(ctx, "user-1", &types.ComparisonResult{ID: "comparison-1",
+		ExecutionRunID: run.ID, MetricName: "response_time", CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("failed to store comparison: %v", err)
+	}
+
+	result, err := client.GetExecutionResult(ctx, "user-1", run.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Results) != 1 || result.SuccessCount != 1 || result.TotalTime != 120 {
+		t.Fatalf("expected one successful variation, got %+v", result)
+	}
+	variation := result.Results[0]
+	if variation.Response.ResponseText != "Paris" || *variation.Configuration.Temperature != 0.7 ||
+		len(variation.Configuration.Tools) != 1 || !variation.Configuration.InlineSystemPrompt {
+		t.Errorf("expected the stored configuration, tools and response, got %+v", variation)
+	}
+	if len(result.Logs) != 1 || *result.Logs[0].RequestID != request.ID {
+		t.Errorf("expected the execution log, got %+v", result.Logs)
+	}
+	if result.Comparison == nil || result.Comparison.ComparisonType != "performance" {
+		t.Errorf("expected the comparison with its type, got %+v", result.Comparison)
+	}
+	if calls := store.FunctionCalls(request.ID); len(calls) != 1 {
+		t.Errorf("expected the function call to be stored, got %+v", calls)
+	}
+
+	// Records are only visible to the user that owns them
+	if _, err := client.GetExecutionResult(ctx, "user-2", run.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows for another user's run, got %v", err)
+	}
+	if _, err := client.GetComparisonResult(ctx, "user-2", run.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows for another user's comparison, got %v", err)
+	}
+	if comparisons, err := client.ListComparisonResults(ctx, "user-2", 10, 0); err != nil || len(comparisons) != 0 {
+		t.Errorf("expected no comparisons for another user, got %+v (%v)", comparisons, err)
+	}
+	if comparisons, _ := client.ListComparisonResults(ctx, "user-1", 10, 0); len(comparisons) != 1 {
+		t.Errorf("expected the owner to list their comparison, got %+v", comparisons)
+	}
+	if total, _ := client.CountComparisonResults(ctx, "user-2"); total != 0 {
+		t.Errorf("expected another user's comparisons not to be counted, got %d", total)
+	}
+	if logs, err := store.ListExecutionLogs(ctx, "user-2", run.ID); err != nil || len(logs) != 0 {
+		t.Errorf("expected no logs for another user's run, got %+v (%v)", logs, err)
+	}
+	if tools, err := store.ListRunFunctionTools(ctx, "user-2", run.ID); err != nil || len(tools) != 0 {
+		t.Errorf("expected no tools for another user's run, got %+v (%v)", tools, err)
+	}
+	configurations, _ := store.ListAPIConfigurationsByRun(ctx, "user-2", run.ID)
+	requests, _ := store.ListAPIRequestsByRun(ctx, "user-2", run.ID)
+	responses, _ := store.ListAPIResponsesByRun(ctx, "user-2", run.ID)
+	if len(configurations) != 0 || len(requests) != 0 || len(responses) != 0 {
+		t.Errorf("expected no configurations, requests or responses for another user's run")
+	}
+	if _, err := client.ReplayExecutionRun(ctx, "user-2", run.ID); !errors.Is(err, ErrExecutionRunNotFound) {
+		t.Errorf("expected ErrExecutionRunNotFound, got %v", err)
+	}
+}
+
+func TestInMemoryClientExecution(t *testing.T) {
+	client := NewInMemoryClient(&types.GeminiClientConfig{})
+	defer client.Close()
+	ctx := context.Background()
+
+	result, err := client.ExecuteMultiVariation(ctx, "user-1", &types.MultiExecutionRequest{
+		ExecutionRunName: "Capital cities",
+		BasePrompt:       "Capital of France?",
+		Suite:            "capitals",
+		Deterministic:    true,
+		Configurations: []types.APIConfiguration{
+			{VariationName: "flash", ModelName: "gemini-2.0-flash"},
+			{VariationName: "pro", ModelName: "gemini-1.5-pro"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.SuccessCount != 2 || result.ExecutionRun.DeterminismFingerprint == "" {
+		t.Fatalf("expected two mocked variations and a fingerprint, got %+v", result)
+	}
+
+	stored, err := client.GetExecutionResult(ctx, "user-1", result.ExecutionRun.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stored.Results) != 2 || len(stored.Logs) == 0 || stored.Comparison == nil {
+		t.Errorf("expected the variations, logs and comparison to be kept in memory, got %+v", stored)
+	}
+
+	// Features backed by MySQL report that there is no database rather than panicking
+	if _, err := client.ListSuiteSLOs(ctx, "user-1"); !errors.Is(err, ErrNoDatabase) {
+		t.Errorf("expected ErrNoDatabase, got %v", err)
+	}
+}
+
+func TestMemoryStoreListing(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	start := time.Now()
+	for i, userID := range []string{"user-1", "user-1", "user-2", "user-1"} {
+		run := &types.ExecutionRun{ID: string(rune('a' + i)), UserID: userID, CreatedAt: start.Add(time.Duration(i) * time.Minute)}
+		if err := store.CreateExecutionRun(ctx, run); err != nil {
+			t.Fatalf("failed to create run: %v", err)
+		}
+		store.CreateAPIConfiguration(ctx, userID, &types.APIConfiguration{ID: "config-" + run.ID, ExecutionRunID: run.ID})
+	}
+
+	if err := store.CreateExecutionRun(ctx, &types.ExecutionRun{ID: "a"}); err == nil {
+		t.Error("expected a duplicate run ID to be rejected")
+	}
+
+	runs, _ := store.ListExecutionRuns(ctx, "user-1", 2, 1)
+	if len(runs) != 2 || runs[0].ID != "b" || runs[1].ID != "a" {
+		t.Errorf("expected the second page of user-1's runs newest first, got %+v", runs)
+	}
+	if all, _ := store.ListAllExecutionRuns(ctx, 0, 0); len(all) != 4 || all[0].ID != "d" {
+		t.Errorf("expected every run newest first, got %+v", all)
+	}
+	if total, _ := store.CountExecutionRuns(ctx, "user-1"); total != 3 {
+		t.Errorf("expected user-1 to have 3 runs, got %d", total)
+	}
+	if total, _ := store.CountAllExecutionRuns(ctx); total != 4 {
+		t.Errorf("expected 4 runs across users, got %d", total)
+	}
+	if configs, _ := store.ListAPIConfigurations(ctx, "user-1", 10, 5); len(configs) != 0 {
+		t.Errorf("expected an empty page past the end, got %+v", configs)
+	}
+	if configs, _ := store.ListAPIConfigurations(ctx, "user-2", 10, 0); len(configs) != 1 || configs[0].ID != "config-c" {
+		t.Errorf("expected user-2's configuration, got %+v", configs)
+	}
+}
+
+func TestExecutionRunStatus(t *testing.T) {
+	client := NewInMemoryClient(&types.GeminiClientConfig{})
+	defer client.Close()
+	request := &types.MultiExecutionRequest{
+		ExecutionRunName: "Capital cities",
+		BasePrompt:       "Capital of France?",
+		Configurations:   []types.APIConfiguration{{VariationName: "flash", ModelName: "gemini-2.0-flash"}},
+	}
+
+	result, err := client.ExecuteMultiVariation(context.Background(), "user-1", request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if run, _ := client.GetExecutionRun(context.Background(), "user-1", result.ExecutionRun.ID); run.Status != "completed" {
+		t.Errorf("expected the finished run to be recorded as completed, got %q", run.Status)
+	}
+
+	// A run canceled before its configurations execute is recorded as failed
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.ExecuteMultiVariation(ctx, "user-2", request); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the run to be interrupted, got %v", err)
+	}
+	runs, _ := client.ListExecutionRuns(context.Background(), "user-2", 10, 0)
+	if len(runs) != 1 || runs[0].Status != "failed" || runs[0].ErrorMessage == "" {
+		t.Errorf("expected one failed run with its error, got %+v", runs)
+	}
+}
+
+func TestConcurrentExecutions(t *testing.T) {
+	client := NewInMemoryClient(&types.GeminiClientConfig{})
+	defer client.Close()
+	ctx := context.Background()
+
+	// One client runs several executions at once, each logging against its own run
+	results := make([]*types.ExecutionResult, 4)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := client.ExecuteMultiVariation(ctx, "user-1", &types.MultiExecutionRequest{
+				ExecutionRunName: fmt.Sprintf("Run %d", i),
+				BasePrompt:       "Capital of France?",
+				Configurations: []types.APIConfiguration{
+					{VariationName: "flash", ModelName: "gemini-2.0-flash"},
+					{VariationName: "pro", ModelName: "gemini-1.5-pro"},
+				},
+			})
+			if err != nil {
+				t.Errorf("run %d failed: %v", i, err)
+			}
+			results[i] = result
+		}()
+	}
+	wg.Wait()
+	if t.Failed() {
+		return
+	}
+
+	for i, result := range results {
+		configurations := make(map[string]bool)
+		for _, variation := range result.Results {
+			configurations[variation.Configuration.ID] = true
+		}
+		stored, err := client.GetExecutionResult(ctx, "user-1", result.ExecutionRun.ID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.SuccessCount != 2 || len(stored.Logs) == 0 {
+			t.Fatalf("expected run %d to complete with logs, got %+v", i, stored)
+		}
+		for _, entry := range stored.Logs {
+			if entry.ExecutionRunID != result.ExecutionRun.ID || (entry.ConfigurationID != nil && !configurations[*entry.ConfigurationID]) {
+				t.Errorf("run %d has a log entry of another run: %+v", i, entry)
+			}
+		}
+	}
+}
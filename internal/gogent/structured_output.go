@@ -0,0 +1,280 @@
+package gogent
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gogent/internal/gemini"
+	"gogent/internal/types"
+)
+
+const (
+	// jsonMimeType asks the model for a JSON response
+	jsonMimeType = "application/json"
+	// schemaComplianceMetric scores whether a structured response parsed and matched its schema
+	schemaComplianceMetric = "schema_compliance"
+)
+
+// responseMimeTypes are the response formats Gemini accepts
+var responseMimeTypes = map[string]bool{"text/plain": true, jsonMimeType: true, "text/x.enum": true}
+
+// wantsStructuredOutput reports whether a configuration asks for a JSON response
+func wantsStructuredOutput(config *types.APIConfiguration) bool {
+	return config.ResponseMimeType == jsonMimeType || (config.ResponseMimeType == "" && len(config.ResponseSchema) > 0)
+}
+
+// applyStructuredOutput adds a configuration's response format to a Gemini generation config. A
+// schema without a MIME type implies JSON.
+func applyStructuredOutput(config *types.APIConfiguration, generationConfig *gemini.GenerationConfig) {
+	generationConfig.ResponseMimeType = config.ResponseMimeType
+	if generationConfig.ResponseMimeType == "" && len(config.ResponseSchema) > 0 {
+		generationConfig.ResponseMimeType = jsonMimeType
+	}
+	generationConfig.ResponseSchema = config.ResponseSchema
+}
+
+// storedGenerationConfig adds the response format to the generation config saved with a configuration
+func storedGenerationConfig(config *types.APIConfiguration) map[string]interface{} {
+	if config.ResponseMimeType == "" && len(config.ResponseSchema) == 0 {
+		return config.GenerationConfig
+	}
+	stored := make(map[string]interface{}, len(config.GenerationConfig)+2)
+	for key, value := range config.GenerationConfig {
+		stored[key] = value
+	}
+	if config.ResponseMimeType != "" {
+		stored["responseMimeType"] = config.ResponseMimeType
+	}
+	if len(config.ResponseSchema) > 0 {
+		stored["responseSchema"] = config.ResponseSchema
+	}
+	return stored
+}
+
+// loadStructuredOutput restores the response format from a saved generation config
+func loadStructuredOutput(config *types.APIConfiguration, generationConfig json.RawMessage) {
+	if len(generationConfig) == 0 {
+		return
+	}
+	var stored struct {
+		ResponseMimeType string                 `json:"responseMimeType"`
+		ResponseSchema   map[string]interface{} `json:"responseSchema"`
+	}
+	if err := json.Unmarshal(generationConfig, &stored); err == nil {
+		config.ResponseMimeType = stored.ResponseMimeType
+		config.ResponseSchema = stored.ResponseSchema
+	}
+}
+
+// validateResponseFormat checks a configuration's response MIME type and schema
+func validateResponseFormat(validation *ValidationError, field string, config *types.APIConfiguration) {
+	if config.ResponseMimeType != "" && !responseMimeTypes[config.ResponseMimeType] {
+		validation.add(field+".responseMimeType", "unsupported MIME type %q (expected text/plain, application/json or text/x.enum)", config.ResponseMimeType)
+	}
+	if len(config.ResponseSchema) == 0 {
+		return
+	}
+	if config.ResponseMimeType == "text/plain" {
+		validation.add(field+".responseSchema", "a response schema needs the application/json or text/x.enum MIME type")
+	}
+	if err := validateResponseSchema(config.ResponseSchema); err != nil {
+		validation.add(field+".responseSchema", "%v", err)
+	}
+}
+
+// validateResponseSchema checks every level of a response schema declares a known type and only
+// requires declared properties
+func validateResponseSchema(schema map[string]interface{}) error {
+	schemaType := strings.ToLower(fmt.Sprint(schema["type"]))
+	if !jsonSchemaTypes[schemaType] {
+		return fmt.Errorf("unknown schema type %v", schema["type"])
+	}
+
+	switch schemaType {
+	case "object":
+		properties, _ := schema["properties"].(map[string]interface{})
+		for name, raw := range properties {
+			property, ok := raw.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("property %q must be an object", name)
+			}
+			if err := validateResponseSchema(property); err != nil {
+				return fmt.Errorf("property %q: %w", name, err)
+			}
+		}
+		for _, name := range schemaRequired(schema) {
+			if properties[name] == nil {
+				return fmt.Errorf("required property %q is not declared", name)
+			}
+		}
+	case "array":
+		items, ok := schema["items"].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("array schemas need an items schema")
+		}
+		if err := validateResponseSchema(items); err != nil {
+			return fmt.Errorf("items: %w", err)
+		}
+	}
+	return nil
+}
+
+// schemaRequired returns a schema's required property names
+func schemaRequired(schema map[string]interface{}) []string {
+	switch required := schema["required"].(type) {
+	case []string:
+		return required
+	case []interface{}:
+		names := make([]string, 0, len(required))
+		for _, name := range required {
+			names = append(names, fmt.Sprint(name))
+		}
+		return names
+	}
+	return nil
+}
+
+// CheckSchemaCompliance parses a structured response, tolerating a markdown code fence, and lists
+// where it departs from the schema. A nil schema only requires valid JSON.
+func CheckSchemaCompliance(responseText string, schema map[string]interface{}) []string {
+	text := strings.TrimSpace(responseText)
+	if strings.HasPrefix(text, "```") {
+		text = strings.TrimPrefix(strings.TrimPrefix(text, "```json"), "```")
+		text = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(text), "```"))
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(text), &value); err != nil {
+		return []string{fmt.Sprintf("response is not valid JSON: %v", err)}
+	}
+	if len(schema) == 0 {
+		return nil
+	}
+	return schemaViolations("$", value, schema)
+}
+
+// schemaViolations checks a decoded JSON value against a schema's type, enum, properties,
+// required properties and items
+func schemaViolations(path string, value interface{}, schema map[string]interface{}) []string {
+	if value == nil {
+		if nullable, _ := schema["nullable"].(bool); nullable || strings.EqualFold(fmt.Sprint(schema["type"]), "null") {
+			return nil
+		}
+		return []string{path + ": must not be null"}
+	}
+
+	var violations []string
+	switch strings.ToLower(fmt.Sprint(schema["type"])) {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return []string{path + ": expected an object"}
+		}
+		for _, name := range schemaRequired(schema) {
+			if _, ok := object[name]; !ok {
+				violations = append(violations, fmt.Sprintf("%s.%s: required property is missing", path, name))
+			}
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		names := make([]string, 0, len(properties))
+		for name := range properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property, _ := properties[name].(map[string]interface{})
+			if propertyValue, ok := object[name]; ok && property != nil {
+				violations = append(violations, schemaViolations(path+"."+name, propertyValue, property)...)
+			}
+		}
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return []string{path + ": expected an array"}
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range array {
+				violations = append(violations, schemaViolations(fmt.Sprintf("%s[%d]", path, i), item, items)...)
+			}
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			return []string{path + ": expected a string"}
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return []string{path + ": expected a number"}
+		}
+	case "integer":
+		number, ok := value.(float64)
+		if !ok || number != float64(int64(number)) {
+			return []string{path + ": expected an integer"}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return []string{path + ": expected a boolean"}
+		}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		allowed := false
+		for _, option := range enum {
+			if fmt.Sprint(option) == fmt.Sprint(value) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			violations = append(violations, fmt.Sprintf("%s: %v is not one of %v", path, value, enum))
+		}
+	}
+	return violations
+}
+
+// mockStructuredValue builds a placeholder value that satisfies a schema, for mock responses
+func mockStructuredValue(schema map[string]interface{}) interface{} {
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[0]
+	}
+	switch strings.ToLower(fmt.Sprint(schema["type"])) {
+	case "object":
+		object := make(map[string]interface{})
+		properties, _ := schema["properties"].(map[string]interface{})
+		for name, raw := range properties {
+			if property, ok := raw.(map[string]interface{}); ok {
+				object[name] = mockStructuredValue(property)
+			}
+		}
+		return object
+	case "array":
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			return []interface{}{mockStructuredValue(items)}
+		}
+		return []interface{}{}
+	case "string":
+		return "mock"
+	case "number", "integer":
+		return 0
+	case "boolean":
+		return false
+	}
+	return nil
+}
+
+// scoreSchemaCompliance scores a structured response 1 when it matched its schema and 0 otherwise,
+// returning the violations found. ok is false for configurations that did not ask for JSON.
+func scoreSchemaCompliance(r types.VariationResult) (float64, []string, bool) {
+	if !wantsStructuredOutput(&r.Configuration) {
+		return 0, nil, false
+	}
+	if r.Response.ResponseStatus != types.ResponseStatusSuccess {
+		return 0, []string{"response failed"}, true
+	}
+	violations := CheckSchemaCompliance(r.Response.ResponseText, r.Configuration.ResponseSchema)
+	if len(violations) > 0 {
+		return 0, violations, true
+	}
+	return 1, nil, true
+}
@@ -0,0 +1,116 @@
+package gogent
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"gogent/internal/gemini"
+	"gogent/internal/types"
+)
+
+// answerSchema is a response schema with a required string, an integer and an enum
+var answerSchema = map[string]interface{}{
+	"type": "OBJECT",
+	"properties": map[string]interface{}{
+		"city":       map[string]interface{}{"type": "STRING"},
+		"population": map[string]interface{}{"type": "INTEGER"},
+		"confidence": map[string]interface{}{"type": "STRING", "enum": []interface{}{"low", "high"}},
+		"landmarks":  map[string]interface{}{"type": "ARRAY", "items": map[string]interface{}{"type": "STRING"}},
+	},
+	"required": []interface{}{"city", "confidence"},
+}
+
+func TestCheckSchemaCompliance(t *testing.T) {
+	tests := []struct {
+		name             string
+		response         string
+		expectViolations int
+	}{
+		{"compliant", `{"city": "Paris", "population": 2100000, "confidence": "high", "landmarks": ["Louvre"]}`, 0},
+		{"code_fence", "```json\n{\"city\": \"Paris\", \"confidence\": \"low\"}\n```", 0},
+		{"not_json", "The capital of France is Paris.", 1},
+		{"missing_required", `{"city": "Paris"}`, 1},
+		{"wrong_types", `{"city": 7, "population": 2.5, "confidence": "medium", "landmarks": [1]}`, 4},
+		{"not_an_object", `["Paris"]`, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if violations := CheckSchemaCompliance(tt.response, answerSchema); len(violations) != tt.expectViolations {
+				t.Errorf("expected %d violations, got %v", tt.expectViolations, violations)
+			}
+		})
+	}
+
+	if violations := CheckSchemaCompliance(`{"anything": true}`, nil); len(violations) != 0 {
+		t.Errorf("expected any valid JSON to pass without a schema, got %v", violations)
+	}
+}
+
+func TestApplyStructuredOutput(t *testing.T) {
+	generationConfig := &gemini.GenerationConfig{}
+	applyStructuredOutput(&types.APIConfiguration{ResponseSchema: answerSchema}, generationConfig)
+	if generationConfig.ResponseMimeType != jsonMimeType || generationConfig.ResponseSchema == nil {
+		t.Errorf("expected a schema to imply JSON, got %v", generationConfig)
+	}
+
+	// The response format survives the generation config the configuration is saved with
+	config := &types.APIConfiguration{ResponseMimeType: jsonMimeType, ResponseSchema: answerSchema,
+		GenerationConfig: map[string]interface{}{"candidateCount": 1}}
+	stored, _ := json.Marshal(storedGenerationConfig(config))
+	loaded := &types.APIConfiguration{}
+	loadStructuredOutput(loaded, stored)
+	if loaded.ResponseMimeType != jsonMimeType || loaded.ResponseSchema["type"] != "OBJECT" || len(config.GenerationConfig) != 1 {
+		t.Errorf("expected the response format to round-trip without changing the generation config, got %+v", loaded)
+	}
+}
+
+func TestValidateResponseFormat(t *testing.T) {
+	request := &types.MultiExecutionRequest{Configurations: []types.APIConfiguration{
+		{ModelName: "gemini-2.0-flash", ResponseSchema: answerSchema},
+		{ModelName: "gemini-2.0-flash", ResponseMimeType: "application/xml"},
+		{ModelName: "gemini-2.0-flash", ResponseSchema: map[string]interface{}{"type": "ARRAY"}},
+	}}
+
+	validation := validateRequest(request, nil)
+	if validation == nil || len(validation.Errors) != 2 ||
+		validation.Errors[0].Field != "configurations[1].responseMimeType" ||
+		validation.Errors[1].Field != "configurations[2].responseSchema" {
+		t.Errorf("expected the unsupported MIME type and the array without items to be rejected, got %v", validation)
+	}
+}
+
+func TestCompareSchemaCompliance(t *testing.T) {
+	client := &Client{}
+	result := newJudgeTestResult(`{"city": "Paris", "confidence": "high"}`, "Paris", "Plain text answer")
+	result.Results[0].Configuration.ResponseSchema = answerSchema
+	result.Results[1].Configuration.ResponseMimeType = jsonMimeType
+
+	comparison, err := client.compareResults(context.Background(), "user-1", result, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	scores := comparison.ConfigurationScores
+	if scores["v0"].(map[string]interface{})[schemaComplianceMetric] != 1.0 {
+		t.Errorf("expected the compliant response to score 1, got %v", scores["v0"])
+	}
+	if v1 := scores["v1"].(map[string]interface{}); v1[schemaComplianceMetric] != 0.0 || v1["schema_errors"] == nil {
+		t.Errorf("expected the invalid JSON response to score 0 with errors, got %v", v1)
+	}
+	if _, ok := scores["v2"].(map[string]interface{})[schemaComplianceMetric]; ok {
+		t.Errorf("expected no compliance score for a plain text configuration")
+	}
+}
+
+func TestMockStructuredResponse(t *testing.T) {
+	client := &Client{}
+	config := &types.APIConfiguration{ModelName: "gemini-2.0-flash", ResponseSchema: answerSchema}
+	response, err := client.callMockGeminiAPI(context.Background(), config, &types.APIRequest{Prompt: "Capital of France?"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if violations := CheckSchemaCompliance(response.ResponseText, answerSchema); len(violations) != 0 {
+		t.Errorf("expected the mock response to match its schema, got %v in %s", violations, response.ResponseText)
+	}
+}
@@ -0,0 +1,467 @@
+package gogent
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gogent/internal/types"
+)
+
+// ErrInvalidSweep is returned when a request's parameter sweep cannot be expanded
+var ErrInvalidSweep = errors.New("invalid parameter sweep")
+
+const (
+	// maxSweepConfigurations caps the configurations a sweep may expand a request into
+	maxSweepConfigurations = 100
+	// sweepScoreMetric is the comparison score sweep points are ranked by
+	sweepScoreMetric = "overall_score"
+)
+
+// sweepParameter describes one parameter a sweep can vary
+type sweepParameter struct {
+	name     string
+	min, max float64
+	integer  bool
+}
+
+var (
+	sweepTemperature = sweepParameter{name: "temperature", min: 0, max: 2}
+	sweepTopP        = sweepParameter{name: "topP", min: 0, max: 1}
+	sweepTopK        = sweepParameter{name: "topK", min: 1, max: math.MaxInt32, integer: true}
+)
+
+// sweepPoint is one combination of swept values; unswept parameters are nil
+type sweepPoint struct {
+	temperature *float32
+	topP        *float32
+	topK        *int32
+}
+
+// label names a point by its swept values, e.g. "temperature=0.4 topK=10"
+func (p sweepPoint) label() string {
+	var parts []string
+	if p.temperature != nil {
+		parts = append(parts, "temperature="+strconv.FormatFloat(float64(*p.temperature), 'f', -1, 32))
+	}
+	if p.topP != nil {
+		parts = append(parts, "topP="+strconv.FormatFloat(float64(*p.topP), 'f', -1, 32))
+	}
+	if p.topK != nil {
+		parts = append(parts, fmt.Sprintf("topK=%d", *p.topK))
+	}
+	return strings.Join(parts, " ")
+}
+
+// ValidateParameterSweep checks a sweep's mode, sample count and ranges, defaulting the mode to grid
+func ValidateParameterSweep(sweep *types.ParameterSweep) error {
+	if sweep.Mode == "" {
+		sweep.Mode = types.SweepModeGrid
+	}
+	switch sweep.Mode {
+	case types.SweepModeGrid:
+		if sweep.Samples != 0 {
+			return fmt.Errorf("%w: samples only applies to random mode", ErrInvalidSweep)
+		}
+	case types.SweepModeRandom:
+		if sweep.Samples < 1 || sweep.Samples > maxSweepConfigurations {
+			return fmt.Errorf("%w: random mode needs between 1 and %d samples", ErrInvalidSweep, maxSweepConfigurations)
+		}
+	default:
+		return fmt.Errorf("%w: unknown mode %q; use grid or random", ErrInvalidSweep, sweep.Mode)
+	}
+
+	if sweep.Temperature == nil && sweep.TopP == nil && sweep.TopK == nil {
+		return fmt.Errorf("%w: set at least one of temperature, topP and topK", ErrInvalidSweep)
+	}
+	for _, swept := range []struct {
+		parameter sweepParameter
+		values    *types.SweepRange
+	}{
+		{sweepTemperature, sweep.Temperature},
+		{sweepTopP, sweep.TopP},
+		{sweepTopK, sweep.TopK},
+	} {
+		if swept.values == nil {
+			continue
+		}
+		if err := validateSweepRange(swept.parameter, swept.values, sweep.Mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateSweepRange checks that a range lies within its parameter's bounds and, in grid mode, has a step
+func validateSweepRange(parameter sweepParameter, values *types.SweepRange, mode string) error {
+	inBounds := func(value float64) error {
+		if value < parameter.min || value > parameter.max {
+			return fmt.Errorf("%w: %s must be between %v and %v, got %v", ErrInvalidSweep, parameter.name, parameter.min, parameter.max, value)
+		}
+		if parameter.integer && value != math.Trunc(value) {
+			return fmt.Errorf("%w: %s must be a whole number, got %v", ErrInvalidSweep, parameter.name, value)
+		}
+		return nil
+	}
+
+	if len(values.Values) > 0 {
+		for _, value := range values.Values {
+			if err := inBounds(value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := inBounds(values.Min); err != nil {
+		return err
+	}
+	if err := inBounds(values.Max); err != nil {
+		return err
+	}
+	if values.Max < values.Min {
+		return fmt.Errorf("%w: %s range %v..%v is empty", ErrInvalidSweep, parameter.name, values.Min, values.Max)
+	}
+	if mode == types.SweepModeGrid && values.Step <= 0 && values.Max > values.Min {
+		return fmt.Errorf("%w: %s needs a positive step or a list of values in grid mode", ErrInvalidSweep, parameter.name)
+	}
+	return nil
+}
+
+// sweepGridValues lists the values a range takes in grid mode
+func sweepGridValues(values *types.SweepRange) []float64 {
+	if len(values.Values) > 0 {
+		return values.Values
+	}
+	if values.Step <= 0 {
+		return []float64{values.Min}
+	}
+
+	var grid []float64
+	// The tolerance keeps the max when float steps accumulate rounding error, e.g. 0.1 + 3*0.3
+	for i := 0; ; i++ {
+		value := values.Min + float64(i)*values.Step
+		if value > values.Max+values.Step*1e-9 {
+			break
+		}
+		grid = append(grid, math.Round(value*1e6)/1e6)
+	}
+	return grid
+}
+
+// sweepRandomValue draws a value uniformly from a range, rounded to two decimals or a whole number
+func sweepRandomValue(rng *rand.Rand, parameter sweepParameter, values *types.SweepRange) float64 {
+	if len(values.Values) > 0 {
+		return values.Values[rng.Intn(len(values.Values))]
+	}
+	if parameter.integer {
+		return values.Min + float64(rng.Int63n(int64(values.Max-values.Min)+1))
+	}
+	return math.Round((values.Min+rng.Float64()*(values.Max-values.Min))*100) / 100
+}
+
+// sweepPoints lists the points a validated sweep visits, in grid order or in the order they were drawn
+func sweepPoints(sweep *types.ParameterSweep) []sweepPoint {
+	if sweep.Mode == types.SweepModeRandom {
+		return randomSweepPoints(sweep)
+	}
+
+	points := []sweepPoint{{}}
+	if sweep.Temperature != nil {
+		points = expandSweepPoints(points, sweepGridValues(sweep.Temperature), func(p *sweepPoint, v float64) {
+			p.temperature = float32Pointer(v)
+		})
+	}
+	if sweep.TopP != nil {
+		points = expandSweepPoints(points, sweepGridValues(sweep.TopP), func(p *sweepPoint, v float64) {
+			p.topP = float32Pointer(v)
+		})
+	}
+	if sweep.TopK != nil {
+		points = expandSweepPoints(points, sweepGridValues(sweep.TopK), func(p *sweepPoint, v float64) {
+			p.topK = int32Pointer(v)
+		})
+	}
+	return points
+}
+
+// expandSweepPoints returns the product of points and values, with set assigning each value
+func expandSweepPoints(points []sweepPoint, values []float64, set func(*sweepPoint, float64)) []sweepPoint {
+	expanded := make([]sweepPoint, 0, len(points)*len(values))
+	for _, point := range points {
+		for _, value := range values {
+			next := point
+			set(&next, value)
+			expanded = append(expanded, next)
+		}
+	}
+	return expanded
+}
+
+// randomSweepPoints draws up to Samples distinct points. A zero seed is replaced by a random one
+// and recorded on the sweep so the draw can be repeated.
+func randomSweepPoints(sweep *types.ParameterSweep) []sweepPoint {
+	if sweep.Seed == 0 {
+		sweep.Seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(sweep.Seed))
+
+	var points []sweepPoint
+	seen := make(map[string]bool)
+	// Small ranges may hold fewer distinct points than were asked for
+	for attempts := 0; len(points) < sweep.Samples && attempts < sweep.Samples*20; attempts++ {
+		var point sweepPoint
+		if sweep.Temperature != nil {
+			point.temperature = float32Pointer(sweepRandomValue(rng, sweepTemperature, sweep.Temperature))
+		}
+		if sweep.TopP != nil {
+			point.topP = float32Pointer(sweepRandomValue(rng, sweepTopP, sweep.TopP))
+		}
+		if sweep.TopK != nil {
+			point.topK = int32Pointer(sweepRandomValue(rng, sweepTopK, sweep.TopK))
+		}
+		if label := point.label(); !seen[label] {
+			seen[label] = true
+			points = append(points, point)
+		}
+	}
+	return points
+}
+
+func float32Pointer(value float64) *float32 {
+	converted := float32(value)
+	return &converted
+}
+
+func int32Pointer(value float64) *int32 {
+	converted := int32(value)
+	return &converted
+}
+
+// ExpandParameterSweep replaces each of the request's configurations with one configuration per
+// point of its sweep, named after the base configuration and the point's values. Requests
+// without a sweep are left alone.
+func ExpandParameterSweep(request *types.MultiExecutionRequest) error {
+	sweep := request.Sweep
+	if sweep == nil {
+		return nil
+	}
+	if request.Deterministic {
+		return fmt.Errorf("%w: deterministic runs pin temperature, topP and topK", ErrInvalidSweep)
+	}
+	if err := ValidateParameterSweep(sweep); err != nil {
+		return err
+	}
+	if len(request.Configurations) == 0 {
+		return fmt.Errorf("%w: at least one configuration is required as the sweep's base", ErrInvalidSweep)
+	}
+
+	points := sweepPoints(sweep)
+	if total := len(points) * len(request.Configurations); total > maxSweepConfigurations {
+		return fmt.Errorf("%w: %d points over %d configurations makes %d configurations; the limit is %d",
+			ErrInvalidSweep, len(points), len(request.Configurations), total, maxSweepConfigurations)
+	}
+
+	expanded := make([]types.APIConfiguration, 0, len(points)*len(request.Configurations))
+	for _, base := range request.Configurations {
+		for _, point := range points {
+			expanded = append(expanded, sweepConfiguration(base, point))
+		}
+	}
+	request.Configurations = expanded
+	return nil
+}
+
+// sweepConfiguration returns the base configuration with a point's values, named after both
+func sweepConfiguration(base types.APIConfiguration, point sweepPoint) types.APIConfiguration {
+	config := base
+	if point.temperature != nil {
+		config.Temperature = point.temperature
+	}
+	if point.topP != nil {
+		config.TopP = point.topP
+	}
+	if point.topK != nil {
+		config.TopK = point.topK
+	}
+	config.VariationName = point.label()
+	if base.VariationName != "" {
+		config.VariationName = fmt.Sprintf("%s (%s)", base.VariationName, point.label())
+	}
+	return config
+}
+
+// ResolveConfigurations turns the request's configurations into the ones that will execute: it
+// applies configuration presets, then expands the parameter sweep
+func (c *Client) ResolveConfigurations(ctx context.Context, userID string, request *types.MultiExecutionRequest) error {
+	if err := c.ApplyConfigurationPresets(ctx, userID, request); err != nil {
+		return err
+	}
+	return ExpandParameterSweep(request)
+}
+
+// SummarizeSweep ranks the configurations of a sweep run by their overall comparison score and
+// reports each swept parameter's mean score by value. It returns nil unless the run came from a
+// sweep and was compared.
+func SummarizeSweep(result *types.ExecutionResult) *types.SweepReport {
+	sweep := result.ExecutionRun.Sweep
+	if sweep == nil || result.Comparison == nil {
+		return nil
+	}
+
+	report := &types.SweepReport{Metric: sweepScoreMetric, Points: scoredSweepPoints(result)}
+	if len(report.Points) == 0 {
+		return nil
+	}
+
+	if sweep.Temperature != nil {
+		report.Parameters = append(report.Parameters, summarizeSweepParameter(report.Points, "temperature", func(p types.SweepPoint) (float64, bool) {
+			return floatValue(p.Temperature)
+		}))
+	}
+	if sweep.TopP != nil {
+		report.Parameters = append(report.Parameters, summarizeSweepParameter(report.Points, "topP", func(p types.SweepPoint) (float64, bool) {
+			return floatValue(p.TopP)
+		}))
+	}
+	if sweep.TopK != nil {
+		report.Parameters = append(report.Parameters, summarizeSweepParameter(report.Points, "topK", func(p types.SweepPoint) (float64, bool) {
+			if p.TopK == nil {
+				return 0, false
+			}
+			return float64(*p.TopK), true
+		}))
+	}
+	return report
+}
+
+// scoredSweepPoints lists a compared result's configurations with their overall score, best first
+func scoredSweepPoints(result *types.ExecutionResult) []types.SweepPoint {
+	var points []types.SweepPoint
+	seen := make(map[string]bool)
+	for _, variation := range result.Results {
+		config := variation.Configuration
+		if seen[config.VariationName] {
+			continue // Repeated samples share one mean score
+		}
+		seen[config.VariationName] = true
+		points = append(points, types.SweepPoint{
+			VariationName: config.VariationName,
+			Temperature:   config.Temperature,
+			TopP:          config.TopP,
+			TopK:          config.TopK,
+			Score:         getScoreFromMap(result.Comparison.ConfigurationScores, config.VariationName, sweepScoreMetric),
+		})
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].Score > points[j].Score
+	})
+	return points
+}
+
+func floatValue(value *float32) (float64, bool) {
+	if value == nil {
+		return 0, false
+	}
+	// Round away float32 noise so 0.4 reads as 0.4 rather than 0.4000000059604645
+	return math.Round(float64(*value)*1e6) / 1e6, true
+}
+
+// summarizeSweepParameter computes one parameter's mean score by value and the region spanned by
+// the top quarter of points, which are sorted best first
+func summarizeSweepParameter(points []types.SweepPoint, name string, valueOf func(types.SweepPoint) (float64, bool)) types.ParameterSummary {
+	summary := types.ParameterSummary{Parameter: name}
+
+	totals := make(map[float64]*types.SweepValueScore)
+	for _, point := range points {
+		value, ok := valueOf(point)
+		if !ok {
+			continue
+		}
+		total, exists := totals[value]
+		if !exists {
+			total = &types.SweepValueScore{Value: value}
+			totals[value] = total
+		}
+		total.MeanScore += point.Score
+		total.Points++
+	}
+	for _, total := range totals {
+		total.MeanScore /= float64(total.Points)
+		summary.Values = append(summary.Values, *total)
+	}
+	sort.Slice(summary.Values, func(i, j int) bool { return summary.Values[i].Value < summary.Values[j].Value })
+
+	bestMean := math.Inf(-1)
+	for _, value := range summary.Values {
+		if value.MeanScore > bestMean {
+			bestMean = value.MeanScore
+			summary.BestValue = value.Value
+		}
+	}
+
+	top := len(points) / 4
+	if top < 1 {
+		top = 1
+	}
+	first := true
+	for _, point := range points[:top] {
+		value, ok := valueOf(point)
+		if !ok {
+			continue
+		}
+		if first || value < summary.RegionMin {
+			summary.RegionMin = value
+		}
+		if first || value > summary.RegionMax {
+			summary.RegionMax = value
+		}
+		first = false
+	}
+	return summary
+}
+
+// recordParameterSweep stores the sweep an execution run's configurations were expanded from
+func (c *Client) recordParameterSweep(ctx context.Context, executionRunID string, sweep *types.ParameterSweep) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	encoded, err := types.ToJSON(sweep)
+	if err != nil {
+		return fmt.Errorf("failed to marshal parameter sweep: %w", err)
+	}
+	_, err = c.db.ExecContext(ctx, "UPDATE execution_runs SET parameter_sweep = ? WHERE id = ?", encoded, executionRunID)
+	if err != nil {
+		return fmt.Errorf("failed to record parameter sweep: %w", err)
+	}
+	return nil
+}
+
+// loadParameterSweep fills in the sweep an execution run was expanded from, if it had one
+func (c *Client) loadParameterSweep(ctx context.Context, run *types.ExecutionRun) error {
+	if c.db == nil {
+		return nil
+	}
+	var encoded sql.NullString
+	err := c.db.QueryRowContext(ctx, "SELECT parameter_sweep FROM execution_runs WHERE id = ?", run.ID).Scan(&encoded)
+	if err != nil {
+		return fmt.Errorf("failed to load parameter sweep: %w", err)
+	}
+	if !encoded.Valid || encoded.String == "" {
+		return nil
+	}
+
+	var sweep types.ParameterSweep
+	if err := types.FromJSON(encoded.String, &sweep); err != nil {
+		return fmt.Errorf("failed to parse parameter sweep: %w", err)
+	}
+	run.Sweep = &sweep
+	return nil
+}
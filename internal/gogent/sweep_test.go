@@ -0,0 +1,180 @@
+package gogent
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"gogent/internal/types"
+)
+
+func TestSweepRangeUnmarshal(t *testing.T) {
+	tests := []struct {
+		input string
+		want  types.SweepRange
+	}{
+		{`"0.1..1.0 step 0.3"`, types.SweepRange{Min: 0.1, Max: 1.0, Step: 0.3}},
+		{`"0..1"`, types.SweepRange{Min: 0, Max: 1}},
+		{`[10, 40]`, types.SweepRange{Values: []float64{10, 40}}},
+		{`{"min": 0.5, "max": 1, "step": 0.25}`, types.SweepRange{Min: 0.5, Max: 1, Step: 0.25}},
+	}
+	for _, test := range tests {
+		var got types.SweepRange
+		if err := json.Unmarshal([]byte(test.input), &got); err != nil {
+			t.Errorf("%s: unexpected error: %v", test.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: expected %+v, got %+v", test.input, test.want, got)
+		}
+	}
+
+	var invalid types.SweepRange
+	if err := json.Unmarshal([]byte(`"0.1 to 1.0"`), &invalid); err == nil {
+		t.Error("expected a range without \"..\" to be rejected")
+	}
+}
+
+func TestExpandParameterSweepGrid(t *testing.T) {
+	request := &types.MultiExecutionRequest{
+		Configurations: []types.APIConfiguration{{VariationName: "base", ModelName: "gemini-2.0-flash"}},
+		Sweep: &types.ParameterSweep{
+			Temperature: &types.SweepRange{Min: 0.1, Max: 1.0, Step: 0.3},
+			TopK:        &types.SweepRange{Values: []float64{10, 40}},
+		},
+	}
+	if err := ExpandParameterSweep(request); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if request.Sweep.Mode != types.SweepModeGrid {
+		t.Errorf("expected the mode to default to grid, got %q", request.Sweep.Mode)
+	}
+	if len(request.Configurations) != 8 {
+		t.Fatalf("expected 4 temperatures x 2 topK values, got %d configurations", len(request.Configurations))
+	}
+
+	first, last := request.Configurations[0], request.Configurations[7]
+	if first.VariationName != "base (temperature=0.1 topK=10)" || last.VariationName != "base (temperature=1 topK=40)" {
+		t.Errorf("expected names with the swept values, got %q and %q", first.VariationName, last.VariationName)
+	}
+	if *last.Temperature != 1.0 || *last.TopK != 40 || last.ModelName != "gemini-2.0-flash" || last.TopP != nil {
+		t.Errorf("expected the base configuration with the point's values, got %+v", last)
+	}
+}
+
+func TestExpandParameterSweepRandom(t *testing.T) {
+	newRequest := func() *types.MultiExecutionRequest {
+		return &types.MultiExecutionRequest{
+			Configurations: []types.APIConfiguration{{ModelName: "gemini-2.0-flash"}},
+			Sweep: &types.ParameterSweep{
+				Mode:        types.SweepModeRandom,
+				Samples:     5,
+				Seed:        42,
+				Temperature: &types.SweepRange{Min: 0, Max: 1},
+				TopP:        &types.SweepRange{Min: 0.5, Max: 1},
+			},
+		}
+	}
+
+	first, second := newRequest(), newRequest()
+	if err := ExpandParameterSweep(first); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ExpandParameterSweep(second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(first.Configurations) != 5 {
+		t.Fatalf("expected 5 samples, got %d", len(first.Configurations))
+	}
+	if !reflect.DeepEqual(first.Configurations, second.Configurations) {
+		t.Error("expected the same seed to draw the same points")
+	}
+	for _, config := range first.Configurations {
+		if *config.Temperature < 0 || *config.Temperature > 1 || *config.TopP < 0.5 || *config.TopP > 1 {
+			t.Errorf("expected values within the ranges, got %q", config.VariationName)
+		}
+	}
+
+	// A missing seed is drawn and recorded so the sweep can be repeated
+	unseeded := newRequest()
+	unseeded.Sweep.Seed = 0
+	if err := ExpandParameterSweep(unseeded); err != nil || unseeded.Sweep.Seed == 0 {
+		t.Errorf("expected a recorded seed, got %d (%v)", unseeded.Sweep.Seed, err)
+	}
+}
+
+func TestExpandParameterSweepErrors(t *testing.T) {
+	base := []types.APIConfiguration{{ModelName: "gemini-2.0-flash"}, {ModelName: "gemini-1.5-pro"}}
+	tests := []struct {
+		name    string
+		request *types.MultiExecutionRequest
+	}{
+		{"no parameters", &types.MultiExecutionRequest{Configurations: base, Sweep: &types.ParameterSweep{}}},
+		{"unknown mode", &types.MultiExecutionRequest{Configurations: base, Sweep: &types.ParameterSweep{
+			Mode: "bayesian", Temperature: &types.SweepRange{Values: []float64{0.2}},
+		}}},
+		{"out of bounds", &types.MultiExecutionRequest{Configurations: base, Sweep: &types.ParameterSweep{
+			TopP: &types.SweepRange{Min: 0.5, Max: 1.5, Step: 0.5},
+		}}},
+		{"missing step", &types.MultiExecutionRequest{Configurations: base, Sweep: &types.ParameterSweep{
+			Temperature: &types.SweepRange{Min: 0, Max: 1},
+		}}},
+		{"fractional topK", &types.MultiExecutionRequest{Configurations: base, Sweep: &types.ParameterSweep{
+			TopK: &types.SweepRange{Values: []float64{2.5}},
+		}}},
+		{"over the cap", &types.MultiExecutionRequest{Configurations: base, Sweep: &types.ParameterSweep{
+			Temperature: &types.SweepRange{Min: 0, Max: 1, Step: 0.1},
+			TopP:        &types.SweepRange{Min: 0.1, Max: 1, Step: 0.1},
+		}}},
+		{"deterministic", &types.MultiExecutionRequest{Configurations: base, Deterministic: true, Sweep: &types.ParameterSweep{
+			Temperature: &types.SweepRange{Values: []float64{0.2}},
+		}}},
+	}
+	for _, test := range tests {
+		if err := ExpandParameterSweep(test.request); !errors.Is(err, ErrInvalidSweep) {
+			t.Errorf("%s: expected ErrInvalidSweep, got %v", test.name, err)
+		}
+	}
+}
+
+func TestSummarizeSweep(t *testing.T) {
+	request := &types.MultiExecutionRequest{
+		Configurations: []types.APIConfiguration{{ModelName: "gemini-2.0-flash"}},
+		Sweep:          &types.ParameterSweep{Temperature: &types.SweepRange{Values: []float64{0.2, 0.6, 1.0}}},
+	}
+	if err := ExpandParameterSweep(request); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	scores := map[string]interface{}{
+		"temperature=0.2": map[string]interface{}{"overall_score": 0.5},
+		"temperature=0.6": map[string]interface{}{"overall_score": 0.9},
+		"temperature=1":   map[string]interface{}{"overall_score": 0.3},
+	}
+	result := &types.ExecutionResult{
+		ExecutionRun: types.ExecutionRun{Sweep: request.Sweep},
+		Comparison:   &types.ComparisonResult{ConfigurationScores: scores},
+	}
+	for _, config := range request.Configurations {
+		result.Results = append(result.Results, types.VariationResult{Configuration: config})
+	}
+
+	report := SummarizeSweep(result)
+	if report == nil || len(report.Points) != 3 || len(report.Parameters) != 1 {
+		t.Fatalf("expected three points and one parameter, got %+v", report)
+	}
+	if report.Points[0].VariationName != "temperature=0.6" || report.Points[2].VariationName != "temperature=1" {
+		t.Errorf("expected points ranked best first, got %+v", report.Points)
+	}
+	temperature := report.Parameters[0]
+	if temperature.BestValue != 0.6 || temperature.RegionMin != 0.6 || temperature.RegionMax != 0.6 || len(temperature.Values) != 3 {
+		t.Errorf("expected 0.6 to be the best temperature, got %+v", temperature)
+	}
+
+	// Runs without a comparison have nothing to rank
+	result.Comparison = nil
+	if SummarizeSweep(result) != nil {
+		t.Error("expected no report without a comparison")
+	}
+}
@@ -0,0 +1,28 @@
+package gogent
+
+import (
+	"gogent/internal/gemini"
+	"gogent/internal/types"
+)
+
+// systemPromptMode reports how a configuration's system prompt is sent, or "" when it has none
+func systemPromptMode(config *types.APIConfiguration) string {
+	switch {
+	case config.SystemPrompt == "":
+		return ""
+	case config.InlineSystemPrompt:
+		return types.SystemPromptModeInline
+	default:
+		return types.SystemPromptModeInstruction
+	}
+}
+
+// systemInstruction builds the Gemini systemInstruction content for a configuration, or nil when
+// the system prompt is absent or sent inline
+func systemInstruction(config *types.APIConfiguration) *gemini.Content {
+	if systemPromptMode(config) != types.SystemPromptModeInstruction {
+		return nil
+	}
+	instruction := gemini.TextContent(config.SystemPrompt)
+	return &instruction
+}
@@ -0,0 +1,35 @@
+package gogent
+
+import (
+	"testing"
+
+	"gogent/internal/types"
+)
+
+func TestSystemPromptMode(t *testing.T) {
+	tests := []struct {
+		name              string
+		config            types.APIConfiguration
+		expectMode        string
+		expectInstruction bool
+	}{
+		{"no_system_prompt", types.APIConfiguration{}, "", false},
+		{"instruction", types.APIConfiguration{SystemPrompt: "Be concise."}, types.SystemPromptModeInstruction, true},
+		{"inline", types.APIConfiguration{SystemPrompt: "Be concise.", InlineSystemPrompt: true}, types.SystemPromptModeInline, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if mode := systemPromptMode(&tt.config); mode != tt.expectMode {
+				t.Errorf("expected mode %q, got %q", tt.expectMode, mode)
+			}
+			instruction := systemInstruction(&tt.config)
+			if (instruction != nil) != tt.expectInstruction {
+				t.Fatalf("expected a system instruction: %v, got %v", tt.expectInstruction, instruction)
+			}
+			if instruction != nil && (len(instruction.Parts) != 1 || instruction.Parts[0].Text != tt.config.SystemPrompt) {
+				t.Errorf("expected the system prompt as the only part, got %+v", instruction.Parts)
+			}
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package gogent
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrUnknownTable is returned for tables that are not browsable
+	ErrUnknownTable = errors.New("unknown table")
+	// ErrInvalidTableQuery is returned for columns, filters or sorts a table cannot serve
+	ErrInvalidTableQuery = errors.New("invalid table query")
+)
+
+// browsableTables are the tables users can browse, in the order they are listed
+var browsableTables = []string{
+	"execution_runs",
+	"comparison_results",
+	"function_calls",
+	"api_configurations",
+	"api_requests",
+	"api_responses",
+	"execution_logs",
+	"function_definitions",
+	"execution_function_configs",
+	"configuration_presets",
+	"execution_run_tags",
+	"batch_runs",
+	"suite_slos",
+	"judgments",
+	"evaluation_results",
+	"response_feedback",
+	"documents",
+	"retrieved_chunks",
+	"guard_verdicts",
+}
+
+// BrowsableTables returns the tables users can browse, in the order they are listed
+func BrowsableTables() []string {
+	return slices.Clone(browsableTables)
+}
+
+// tableScope limits a table's rows to one user: the join reaching the owner, if any, and the
+// condition on it
+type tableScope struct {
+	join      string
+	condition string
+}
+
+// tableUserScopes maps each browsable table to the scope that limits its rows to one user
+var tableUserScopes = map[string]tableScope{
+	"execution_runs":             {condition: "t.user_id = ?"},
+	"api_configurations":         {condition: "t.user_id = ?"},
+	"api_requests":               {condition: "t.user_id = ?"},
+	"api_responses":              {condition: "t.user_id = ?"},
+	"execution_function_configs": {condition: "t.user_id = ?"},
+	"function_definitions":       {condition: "(t.user_id = ? OR t.user_id = 'system')"},
+	"comparison_results":         {join: "INNER JOIN execution_runs er ON t.execution_run_id = er.id", condition: "er.user_id = ?"},
+	"execution_logs":             {join: "INNER JOIN execution_runs er ON t.execution_run_id = er.id", condition: "er.user_id = ?"},
+	"function_calls":             {join: "LEFT JOIN api_requests ar ON t.request_id = ar.id", condition: "COALESCE(ar.user_id, t.user_id) = ?"},
+	"configuration_presets":      {condition: "t.user_id = ?"},
+	"execution_run_tags":         {condition: "t.user_id = ?"},
+	"batch_runs":                 {condition: "t.user_id = ?"},
+	"suite_slos":                 {condition: "t.user_id = ?"},
+	"judgments":                  {condition: "t.user_id = ?"},
+	"evaluation_results":         {condition: "t.user_id = ?"},
+	"response_feedback":          {condition: "t.user_id = ?"},
+	"documents":                  {condition: "t.user_id = ?"},
+	"retrieved_chunks":           {condition: "t.user_id = ?"},
+	"guard_verdicts":             {condition: "t.user_id = ?"},
+}
+
+// maxTableFilters caps the filters one table query may apply
+const maxTableFilters = 20
+
+// tableFilterOperators maps the operators a filter may use to SQL; null and notnull take no value
+var tableFilterOperators = map[string]string{
+	"eq":      "=",
+	"ne":      "<>",
+	"lt":      "<",
+	"lte":     "<=",
+	"gt":      ">",
+	"gte":     ">=",
+	"like":    "LIKE",
+	"null":    "IS NULL",
+	"notnull": "IS NOT NULL",
+}
+
+// TableQuery selects a page of a browsable table
+type TableQuery struct {
+	Columns []string      // Columns to return, in order; every column when empty
+	Filters []TableFilter // Conditions every returned row meets
+	Sort    string        // Column to sort by, descending with a leading "-"; the first column when empty
+	Limit   int32
+	Offset  int32
+}
+
+// TableFilter compares a column with a value
+type TableFilter struct {
+	Column   string
+	Operator string
+	Value    string
+}
+
+// parseTableFilter parses a filter written column:operator[:value], e.g. status:eq:failed
+func parseTableFilter(spec string) (TableFilter, error) {
+	parts := strings.SplitN(spec, ":", 3)
+	if len(parts) < 2 {
+		return TableFilter{}, fmt.Errorf("%w: filter %q must be column:operator:value", ErrInvalidTableQuery, spec)
+	}
+	filter := TableFilter{Column: parts[0], Operator: strings.ToLower(parts[1])}
+	if len(parts) == 3 {
+		filter.Value = parts[2]
+	}
+	return filter, nil
+}
+
+// ParseTableFilters parses each filter of a table query
+func ParseTableFilters(specs []string) ([]TableFilter, error) {
+	if len(specs) > maxTableFilters {
+		return nil, fmt.Errorf("%w: at most %d filters", ErrInvalidTableQuery, maxTableFilters)
+	}
+	filters := make([]TableFilter, 0, len(specs))
+	for _, spec := range specs {
+		filter, err := parseTableFilter(spec)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
+
+// tableColumns reads the column names a table can be browsed by from the database, so queries only
+// name real columns. The credential columns reports may not read are left out.
+func tableColumns(ctx context.Context, db *sql.DB, tableName string) ([]string, error) {
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("SELECT * FROM %s LIMIT 0", tableName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s columns: %w", tableName, err)
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(columns, func(column string) bool {
+		return slices.Contains(reportCredentialColumns[tableName], column)
+	}), nil
+}
+
+// buildTableQuery returns the conditions and arguments shared by a table query's page and count,
+// and the columns it selects. Table and column names are checked against the allow-list and the
+// table's own columns before they are interpolated; values are always bound as arguments.
+func buildTableQuery(columns []string, userID, tableName string, query TableQuery) (string, []interface{}, []string, error) {
+	scope, ok := tableUserScopes[tableName]
+	if !ok {
+		return "", nil, nil, fmt.Errorf("%w: %s", ErrUnknownTable, tableName)
+	}
+	known := func(column string) error {
+		if !slices.Contains(columns, column) {
+			return fmt.Errorf("%w: %s has no column %q", ErrInvalidTableQuery, tableName, column)
+		}
+		return nil
+	}
+
+	var conditions []string
+	var args []interface{}
+	clause := "FROM " + tableName + " t"
+	if userID != "" {
+		if scope.join != "" {
+			clause += " " + scope.join
+		}
+		conditions = append(conditions, scope.condition)
+		args = append(args, userID)
+	}
+	for _, filter := range query.Filters {
+		if err := known(filter.Column); err != nil {
+			return "", nil, nil, err
+		}
+		operator, ok := tableFilterOperators[filter.Operator]
+		if !ok {
+			return "", nil, nil, fmt.Errorf("%w: unknown operator %q", ErrInvalidTableQuery, filter.Operator)
+		}
+		if filter.Operator == "null" || filter.Operator == "notnull" {
+			conditions = append(conditions, fmt.Sprintf("t.`%s` %s", filter.Column, operator))
+			continue
+		}
+		conditions = append(conditions, fmt.Sprintf("t.`%s` %s ?", filter.Column, operator))
+		args = append(args, filter.Value)
+	}
+
+	if len(conditions) > 0 {
+		clause += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	selected := columns
+	if len(query.Columns) > 0 {
+		for _, column := range query.Columns {
+			if err := known(column); err != nil {
+				return "", nil, nil, err
+			}
+		}
+		selected = query.Columns
+	}
+	return clause, args, selected, nil
+}
+
+// QueryTableData reads a page of raw rows from an allow-listed table, scoped to userID unless it is
+// empty and narrowed by the query's filters, along with the number of matching rows across all pages
+func (c *Client) QueryTableData(ctx context.Context, userID, tableName string, query TableQuery) ([]string, [][]interface{}, int64, error) {
+	if _, ok := tableUserScopes[tableName]; !ok {
+		return nil, nil, 0, fmt.Errorf("%w: %s", ErrUnknownTable, tableName)
+	}
+	if c.db == nil {
+		return nil, nil, 0, ErrNoDatabase
+	}
+	columns, err := tableColumns(ctx, c.db, tableName)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	clause, args, selected, err := buildTableQuery(columns, userID, tableName, query)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	// Sort by the requested column, breaking ties by id where the table has one so pages are stable
+	sortColumn, direction := strings.TrimPrefix(query.Sort, "-"), "ASC"
+	if strings.HasPrefix(query.Sort, "-") {
+		direction = "DESC"
+	}
+	if sortColumn == "" {
+		sortColumn = columns[0]
+	}
+	if !slices.Contains(columns, sortColumn) {
+		return nil, nil, 0, fmt.Errorf("%w: %s has no column %q", ErrInvalidTableQuery, tableName, sortColumn)
+	}
+	order := fmt.Sprintf("t.`%s` %s", sortColumn, direction)
+	if sortColumn != "id" && slices.Contains(columns, "id") {
+		order += ", t.`id` ASC"
+	}
+
+	if query.Limit <= 0 {
+		query.Limit = 100
+	}
+	query.Offset = max(query.Offset, 0)
+
+	quoted := make([]string, len(selected))
+	for i, column := range selected {
+		quoted[i] = "t.`" + column + "`"
+	}
+	statement := fmt.Sprintf("SELECT %s %s ORDER BY %s LIMIT ? OFFSET ?", strings.Join(quoted, ", "), clause, order)
+	rows, err := c.db.QueryContext(ctx, statement, append(args, query.Limit, query.Offset)...)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to query %s: %w", tableName, err)
+	}
+	defer rows.Close()
+
+	var data [][]interface{}
+	for rows.Next() {
+		values := make([]sql.NullString, len(selected))
+		scanArgs := make([]interface{}, len(selected))
+		for i := range values {
+			scanArgs[i] = &values[i]
+		}
+		if err := rows.Scan(scanArgs...); err != nil {
+			return nil, nil, 0, fmt.Errorf("failed to scan %s row: %w", tableName, err)
+		}
+
+		row := make([]interface{}, len(selected))
+		for i, v := range values {
+			if v.Valid {
+				row[i] = v.String
+			}
+		}
+		data = append(data, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to iterate %s rows: %w", tableName, err)
+	}
+
+	var total int64
+	if err := c.db.QueryRowContext(ctx, "SELECT COUNT(*) "+clause, args...).Scan(&total); err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to count %s: %w", tableName, err)
+	}
+	return selected, data, total, nil
+}
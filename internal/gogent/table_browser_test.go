@@ -0,0 +1,199 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"gogent/internal/testdb"
+)
+
+// newTableBrowserTestClient returns a client whose database has two users' runs, the rows that
+// reach their owner through a run or a request, and a system function with stored credentials
+func newTableBrowserTestClient(t *testing.T) *Client {
+	database := testdb.Open(t)
+	_, err := database.Exec(`
+		INSERT INTO execution_runs (id, user_id, name, status, error_message) VALUES
+			('run-1', 'user-1', 'Alpha', 'completed', NULL),
+			('run-2', 'user-1', 'Beta', 'failed', 'timeout'),
+			('run-3', 'user-2', 'Gamma', 'failed', 'quota');
+		INSERT INTO comparison_results (id, execution_run_id) VALUES ('comparison-1', 'run-1'), ('comparison-3', 'run-3');
+		INSERT INTO execution_logs (id, execution_run_id, message) VALUES ('log-1', 'run-2', 'started'), ('log-3', 'run-3', 'started');
+		INSERT INTO api_requests (id, user_id, execution_run_id, request_headers) VALUES
+			('request-1', 'user-1', 'run-1', '{"x-goog-api-key": "provider-secret"}'),
+			('request-3', 'user-2', 'run-3', NULL);
+		INSERT INTO function_calls (id, request_id, user_id) VALUES
+			('call-1', 'request-1', NULL),
+			('call-3', 'request-3', NULL),
+			('call-test', NULL, 'user-1');
+		INSERT INTO function_definitions (id, user_id, name, display_name, headers, auth_config) VALUES
+			('function-system', 'system', 'get_current_weather', 'Get Weather', '{"X-Api-Key": "system-secret"}', '{"token": "system-secret"}'),
+			('function-1', 'user-1', 'lookup_order', 'Lookup Order', NULL, NULL),
+			('function-2', 'user-2', 'send_email', 'Send Email', NULL, '{"token": "user-2-secret"}');`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+	return &Client{db: database}
+}
+
+// tableIDs returns the first column of each row
+func tableIDs(rows [][]interface{}) []string {
+	ids := make([]string, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row[0].(string))
+	}
+	return ids
+}
+
+func TestParseTableFilters(t *testing.T) {
+	filters, err := ParseTableFilters([]string{"status:EQ:failed", "error_message:null", "name:like:a:b%"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []TableFilter{
+		{Column: "status", Operator: "eq", Value: "failed"},
+		{Column: "error_message", Operator: "null"},
+		{Column: "name", Operator: "like", Value: "a:b%"},
+	}
+	if !reflect.DeepEqual(filters, expected) {
+		t.Errorf("expected %+v, got %+v", expected, filters)
+	}
+
+	if _, err := ParseTableFilters([]string{"status"}); !errors.Is(err, ErrInvalidTableQuery) {
+		t.Errorf("expected a filter without an operator to be invalid, got %v", err)
+	}
+	if _, err := ParseTableFilters(make([]string, maxTableFilters+1)); !errors.Is(err, ErrInvalidTableQuery) {
+		t.Errorf("expected more than %d filters to be invalid, got %v", maxTableFilters, err)
+	}
+}
+
+func TestQueryTableDataScopes(t *testing.T) {
+	client := newTableBrowserTestClient(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		table, userID string
+		expected      []string
+	}{
+		{"execution_runs", "user-1", []string{"run-1", "run-2"}},
+		{"execution_runs", "", []string{"run-1", "run-2", "run-3"}},
+		{"comparison_results", "user-1", []string{"comparison-1"}},
+		{"comparison_results", "", []string{"comparison-1", "comparison-3"}},
+		{"execution_logs", "user-2", []string{"log-3"}},
+		{"function_calls", "user-1", []string{"call-1", "call-test"}},
+		{"function_calls", "user-2", []string{"call-3"}},
+		{"function_calls", "", []string{"call-1", "call-3", "call-test"}},
+		{"function_definitions", "user-2", []string{"function-2", "function-system"}},
+	}
+	for _, test := range tests {
+		_, rows, total, err := client.QueryTableData(ctx, test.userID, test.table, TableQuery{})
+		if err != nil {
+			t.Fatalf("failed to browse %s as %q: %v", test.table, test.userID, err)
+		}
+		if ids := tableIDs(rows); !reflect.DeepEqual(ids, test.expected) || total != int64(len(test.expected)) {
+			t.Errorf("expected %s as %q to return %v, got %v (%d total)", test.table, test.userID, test.expected, ids, total)
+		}
+	}
+}
+
+func TestQueryTableDataFiltersAndPages(t *testing.T) {
+	client := newTableBrowserTestClient(t)
+	ctx := context.Background()
+
+	columns, rows, total, err := client.QueryTableData(ctx, "", "execution_runs", TableQuery{
+		Columns: []string{"id", "error_message"},
+		Filters: []TableFilter{{Column: "status", Operator: "eq", Value: "failed"}},
+		Sort:    "-name",
+		Limit:   1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(columns, []string{"id", "error_message"}) {
+		t.Errorf("expected the selected columns, got %v", columns)
+	}
+	if !reflect.DeepEqual(rows, [][]interface{}{{"run-3", "quota"}}) || total != 2 {
+		t.Errorf("expected the first of two failed runs by descending name, got %v (%d total)", rows, total)
+	}
+
+	_, rows, _, err = client.QueryTableData(ctx, "", "execution_runs", TableQuery{Sort: "-name", Limit: 1, Offset: 1})
+	if err != nil || !reflect.DeepEqual(tableIDs(rows), []string{"run-2"}) {
+		t.Errorf("expected the offset to page to the second run, got %v (%v)", rows, err)
+	}
+
+	_, rows, _, err = client.QueryTableData(ctx, "user-1", "execution_runs", TableQuery{
+		Filters: []TableFilter{{Column: "error_message", Operator: "null"}},
+	})
+	if err != nil || !reflect.DeepEqual(tableIDs(rows), []string{"run-1"}) {
+		t.Errorf("expected null to match runs without an error, got %v (%v)", rows, err)
+	}
+	_, rows, _, err = client.QueryTableData(ctx, "user-1", "execution_runs", TableQuery{
+		Filters: []TableFilter{{Column: "error_message", Operator: "notnull", Value: "ignored"}},
+	})
+	if err != nil || !reflect.DeepEqual(tableIDs(rows), []string{"run-2"}) {
+		t.Errorf("expected notnull to match runs with an error and ignore the value, got %v (%v)", rows, err)
+	}
+}
+
+func TestQueryTableDataInvalid(t *testing.T) {
+	client := newTableBrowserTestClient(t)
+	ctx := context.Background()
+
+	if _, _, _, err := client.QueryTableData(ctx, "", "users", TableQuery{}); !errors.Is(err, ErrUnknownTable) {
+		t.Errorf("expected users to be an unknown table, got %v", err)
+	}
+
+	invalid := map[string]TableQuery{
+		"unknown column":          {Columns: []string{"id", "password_hash"}},
+		"column injection":        {Columns: []string{"id` FROM users --"}},
+		"unknown filter column":   {Filters: []TableFilter{{Column: "secret", Operator: "eq", Value: "x"}}},
+		"unknown operator":        {Filters: []TableFilter{{Column: "status", Operator: "regexp", Value: "x"}}},
+		"unknown sort column":     {Sort: "-secret"},
+		"operator injection":      {Filters: []TableFilter{{Column: "status", Operator: "= 'x' OR 1 = 1 --"}}},
+		"sort column injection":   {Sort: "id; DROP TABLE execution_runs"},
+		"filter column injection": {Filters: []TableFilter{{Column: "1 = 1 OR status", Operator: "null"}}},
+	}
+	for name, query := range invalid {
+		if _, _, _, err := client.QueryTableData(ctx, "user-1", "execution_runs", query); !errors.Is(err, ErrInvalidTableQuery) {
+			t.Errorf("expected %s to be an invalid query, got %v", name, err)
+		}
+	}
+}
+
+func TestQueryTableDataHidesCredentials(t *testing.T) {
+	client := newTableBrowserTestClient(t)
+	ctx := context.Background()
+
+	for _, test := range []struct{ userID, table string }{
+		{"user-1", "function_definitions"},
+		{"user-1", "api_requests"},
+		{"", "function_definitions"},
+	} {
+		columns, rows, _, err := client.QueryTableData(ctx, test.userID, test.table, TableQuery{})
+		if err != nil {
+			t.Fatalf("failed to browse %s: %v", test.table, err)
+		}
+		for _, column := range reportCredentialColumns[test.table] {
+			if slices.Contains(columns, column) {
+				t.Errorf("expected %s.%s to be left out for %q, got columns %v", test.table, column, test.userID, columns)
+			}
+		}
+		if data := fmt.Sprint(rows); strings.Contains(data, "secret") {
+			t.Errorf("expected no credentials in %s rows for %q, got %s", test.table, test.userID, data)
+		}
+	}
+
+	for name, query := range map[string]TableQuery{
+		"selected": {Columns: []string{"id", "auth_config"}},
+		"filtered": {Filters: []TableFilter{{Column: "headers", Operator: "like", Value: "%secret%"}}},
+		"sorted":   {Sort: "auth_config"},
+	} {
+		if _, _, _, err := client.QueryTableData(ctx, "user-1", "function_definitions", query); !errors.Is(err, ErrInvalidTableQuery) {
+			t.Errorf("expected a credential column to be rejected when %s, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package gogent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"gogent/internal/types"
+)
+
+// TelemetrySchemaVersion changes whenever the fields of a telemetry report change
+const TelemetrySchemaVersion = 1
+
+// telemetryMinModelCount is the fewest variations a listed model needs to be reported by name;
+// rarer models are grouped with their provider's others, so one user's choice cannot stand out
+const telemetryMinModelCount = 5
+
+// telemetryTimeout bounds sending one report
+const telemetryTimeout = 30 * time.Second
+
+// BuildTelemetryReport counts the runs, variations and responses created in [since, until) across
+// every user. Only models with a list price are named. With a positive epsilon, Laplace noise of
+// scale 1/epsilon is added to every count, so a report cannot tell whether any one variation was in
+// it; zero reports exact counts.
+func (c *Client) BuildTelemetryReport(ctx context.Context, since, until time.Time, epsilon float64) (*types.TelemetryReport, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	if epsilon < 0 {
+		return nil, fmt.Errorf("telemetry epsilon must not be negative, got %v", epsilon)
+	}
+	since, until = since.UTC().Truncate(time.Hour), until.UTC().Truncate(time.Hour)
+	report := &types.TelemetryReport{
+		SchemaVersion: TelemetrySchemaVersion,
+		PeriodStart:   since,
+		PeriodEnd:     until,
+		Responses:     make(map[string]int),
+		Models:        make(map[string]int),
+		Providers:     make(map[string]int),
+		Epsilon:       epsilon,
+	}
+
+	err := c.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM execution_runs WHERE created_at >= ? AND created_at < ?", since, until).Scan(&report.ExecutionRuns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count execution runs: %w", err)
+	}
+
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT COALESCE(response_status, ''), COUNT(*)
+		FROM api_responses
+		WHERE created_at >= ? AND created_at < ?
+		GROUP BY response_status
+	`, since, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count responses: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan response count: %w", err)
+		}
+		if status == "" {
+			status = "unknown"
+		}
+		report.Responses[status] += count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate response counts: %w", err)
+	}
+
+	rows, err = c.db.QueryContext(ctx, `
+		SELECT COALESCE(provider, ''), model_name, COUNT(*)
+		FROM api_configurations
+		WHERE created_at >= ? AND created_at < ?
+		GROUP BY provider, model_name
+	`, since, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count configurations: %w", err)
+	}
+	defer rows.Close()
+	type providerModel struct{ provider, model string }
+	models := make(map[providerModel]int)
+	for rows.Next() {
+		var provider, modelName string
+		var count int
+		if err := rows.Scan(&provider, &modelName, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan configuration count: %w", err)
+		}
+		if provider == "" {
+			provider = "unknown"
+		}
+		models[providerModel{provider, strings.TrimPrefix(modelName, "models/")}] += count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate configuration counts: %w", err)
+	}
+	for key, count := range models {
+		modelName := key.model
+		if count < telemetryMinModelCount || listPrice(modelName) == nil {
+			modelName = key.provider + "/other"
+		}
+		report.Variations += count
+		report.Providers[key.provider] += count
+		report.Models[modelName] += count
+	}
+
+	if epsilon > 0 {
+		rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+		report.ExecutionRuns = noisyCount(rng, report.ExecutionRuns, epsilon)
+		report.Variations = noisyCount(rng, report.Variations, epsilon)
+		for _, counts := range []map[string]int{report.Responses, report.Models, report.Providers} {
+			for key, count := range counts {
+				counts[key] = noisyCount(rng, count, epsilon)
+			}
+		}
+	}
+
+	var responses int
+	for _, count := range report.Responses {
+		responses += count
+	}
+	if responses > 0 {
+		failed := report.Responses[string(types.ResponseStatusError)] + report.Responses[string(types.ResponseStatusTimeout)]
+		report.ErrorRate = float64(failed) / float64(responses)
+	}
+	return report, nil
+}
+
+// noisyCount adds Laplace noise of scale 1/epsilon to a count, rounded and kept non-negative
+func noisyCount(rng *rand.Rand, count int, epsilon float64) int {
+	u := rng.Float64() - 0.5
+	noise := -math.Copysign(1/epsilon, u) * math.Log(1-2*math.Abs(u))
+	return max(int(math.Round(float64(count)+noise)), 0)
+}
+
+// SendTelemetry posts a report as JSON to the endpoint the operator configured
+func (c *Client) SendTelemetry(ctx context.Context, endpoint string, report *types.TelemetryReport) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode telemetry report: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, telemetryTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create telemetry request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := (&http.Client{Transport: c.transport()}).Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send telemetry: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("telemetry endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
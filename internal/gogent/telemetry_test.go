@@ -0,0 +1,99 @@
+package gogent
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gogent/internal/testdb"
+	"gogent/internal/types"
+)
+
+func TestTelemetryReport(t *testing.T) {
+	database := testdb.Open(t)
+
+	_, err := database.Exec(`
+		INSERT INTO execution_runs (id, created_at) VALUES ('run-1', '2026-01-02 10:00:00'), ('run-2', '2026-01-02 11:00:00'), ('run-old', '2025-12-30 10:00:00');
+		INSERT INTO api_configurations (id, provider, model_name, created_at) VALUES
+			('c1', 'gemini', 'gemini-2.0-flash', '2026-01-02 10:00:00'), ('c2', 'gemini', 'gemini-2.0-flash', '2026-01-02 10:00:00'),
+			('c3', 'gemini', 'gemini-2.0-flash', '2026-01-02 10:00:00'), ('c4', 'gemini', 'gemini-2.0-flash', '2026-01-02 10:00:00'),
+			('c5', 'gemini', 'models/gemini-2.0-flash', '2026-01-02 11:00:00'), ('c6', 'gemini', 'gemini-1.5-pro', '2026-01-02 11:00:00'),
+			('c7', 'ollama', 'acme-internal-finetune', '2026-01-02 11:00:00'), ('c8', NULL, 'mystery', '2026-01-02 11:00:00');
+		INSERT INTO api_responses (id, response_status, created_at) VALUES ('r1', 'success', '2026-01-02 10:00:01'), ('r2', 'success', '2026-01-02 10:00:01'),
+			('r3', 'error', '2026-01-02 10:00:01'), ('r4', 'timeout', '2026-01-02 11:00:01'), ('r-old', 'error', '2025-12-30 10:00:01');
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+	client := &Client{db: database, config: &types.GeminiClientConfig{}}
+	ctx := context.Background()
+	since := time.Date(2026, 1, 2, 0, 30, 0, 0, time.UTC)
+	until := time.Date(2026, 1, 3, 0, 30, 0, 0, time.UTC)
+
+	report, err := client.BuildTelemetryReport(ctx, since, until, 0)
+	if err != nil {
+		t.Fatalf("failed to build report: %v", err)
+	}
+	if !report.PeriodStart.Equal(time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the period rounded to the hour, got %v", report.PeriodStart)
+	}
+	if report.ExecutionRuns != 2 || report.Variations != 8 || report.Responses["success"] != 2 || report.ErrorRate != 0.5 {
+		t.Errorf("expected counts for the period only, got %+v", report)
+	}
+	want := map[string]int{"gemini-2.0-flash": 5, "gemini/other": 1, "ollama/other": 1, "unknown/other": 1}
+	if len(report.Models) != len(want) {
+		t.Errorf("expected models %v, got %v", want, report.Models)
+	}
+	for model, count := range want {
+		if report.Models[model] != count {
+			t.Errorf("expected %d variations of %s, got %v", count, model, report.Models)
+		}
+	}
+	if report.Providers["gemini"] != 6 || report.Providers["ollama"] != 1 {
+		t.Errorf("expected variations per provider, got %v", report.Providers)
+	}
+
+	noisy, err := client.BuildTelemetryReport(ctx, since, until, 0.5)
+	if err != nil {
+		t.Fatalf("failed to build noisy report: %v", err)
+	}
+	if noisy.Epsilon != 0.5 || noisy.ExecutionRuns < 0 || noisy.Models["acme-internal-finetune"] != 0 {
+		t.Errorf("expected noisy counts without unlisted names, got %+v", noisy)
+	}
+
+	var received types.TelemetryReport
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("expected a JSON report: %v", err)
+		}
+	}))
+	t.Cleanup(server.Close)
+	if err := client.SendTelemetry(ctx, server.URL, report); err != nil {
+		t.Fatalf("failed to send report: %v", err)
+	}
+	if received.SchemaVersion != TelemetrySchemaVersion || received.ExecutionRuns != 2 {
+		t.Errorf("expected the report to arrive, got %+v", received)
+	}
+}
+
+func TestNoisyCount(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var sum int
+	for i := 0; i < 2000; i++ {
+		count := noisyCount(rng, 100, 1)
+		if count < 0 {
+			t.Fatalf("expected non-negative counts, got %d", count)
+		}
+		sum += count
+	}
+	if mean := float64(sum) / 2000; mean < 99 || mean > 101 {
+		t.Errorf("expected the noise to average out around 100, got %v", mean)
+	}
+	if noisyCount(rng, 0, 1) < 0 {
+		t.Error("expected zero counts to stay non-negative")
+	}
+}
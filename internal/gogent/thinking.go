@@ -0,0 +1,82 @@
+package gogent
+
+import (
+	"encoding/json"
+
+	"gogent/internal/gemini"
+	"gogent/internal/types"
+)
+
+const (
+	// dynamicThinkingBudget lets the model decide how long to think
+	dynamicThinkingBudget = -1
+	// maxThinkingBudget is the largest thinking budget Gemini models accept
+	maxThinkingBudget = 32768
+	// reasoningTokensMetric is the tokens a variation spent thinking before it answered
+	reasoningTokensMetric = "reasoning_tokens"
+)
+
+// applyThinking adds a configuration's thinking budget to a Gemini generation config, asking for
+// thought summaries whenever the model may think
+func applyThinking(config *types.APIConfiguration, generationConfig *gemini.GenerationConfig) {
+	if config.ThinkingBudget == nil {
+		return
+	}
+	generationConfig.ThinkingConfig = &gemini.ThinkingConfig{
+		ThinkingBudget:  config.ThinkingBudget,
+		IncludeThoughts: *config.ThinkingBudget != 0,
+	}
+}
+
+// storedThinking adds the thinking budget to the generation config saved with a configuration
+func storedThinking(config *types.APIConfiguration, generationConfig map[string]interface{}) map[string]interface{} {
+	if config.ThinkingBudget == nil {
+		return generationConfig
+	}
+	stored := make(map[string]interface{}, len(generationConfig)+1)
+	for key, value := range generationConfig {
+		stored[key] = value
+	}
+	stored["thinkingBudget"] = *config.ThinkingBudget
+	return stored
+}
+
+// loadThinking restores the thinking budget from a saved generation config, removing it from the
+// generation config sent to providers
+func loadThinking(config *types.APIConfiguration, generationConfig json.RawMessage) {
+	var stored struct {
+		ThinkingBudget *int32 `json:"thinkingBudget"`
+	}
+	if len(generationConfig) == 0 || json.Unmarshal(generationConfig, &stored) != nil || stored.ThinkingBudget == nil {
+		return
+	}
+	config.ThinkingBudget = stored.ThinkingBudget
+	delete(config.GenerationConfig, "thinkingBudget")
+}
+
+// validateThinking checks a configuration's thinking budget, which only Gemini models take
+func validateThinking(validation *ValidationError, field string, config *types.APIConfiguration) {
+	if config.ThinkingBudget == nil {
+		return
+	}
+	if budget := *config.ThinkingBudget; budget != dynamicThinkingBudget && (budget < 0 || budget > maxThinkingBudget) {
+		validation.add(field+".thinkingBudget", "must be -1 (dynamic) or between 0 and %d, got %d", maxThinkingBudget, budget)
+	}
+	if provider := types.ProviderForConfiguration(config); provider != "" && provider != types.ProviderGemini {
+		validation.add(field+".thinkingBudget", "thinking budgets are only supported on Gemini models, got %q", config.ModelName)
+	}
+}
+
+// reasoningMetrics scores the reasoning a variation's model reported: the tokens it spent thinking
+// and their share of its output. It returns nil when the model reported none.
+func reasoningMetrics(r types.VariationResult) map[string]interface{} {
+	thoughts := usageTokens(r.Response.UsageMetadata, "thoughts_tokens")
+	if thoughts == 0 {
+		return nil
+	}
+	output := thoughts + usageTokens(r.Response.UsageMetadata, "completion_tokens")
+	return map[string]interface{}{
+		reasoningTokensMetric: thoughts,
+		"reasoning_share":     float64(thoughts) / float64(output),
+	}
+}
@@ -0,0 +1,105 @@
+package gogent
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gogent/internal/types"
+)
+
+func TestThinking(t *testing.T) {
+	client := NewInMemoryClient(&types.GeminiClientConfig{APIKey: "gemini-api-key"})
+	client.SetHTTPTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		var body map[string]interface{}
+		json.NewDecoder(req.Body).Decode(&body)
+		reply := `{"candidates": [{"content": {"parts": [{"text": "Paris"}]}, "finishReason": "STOP"}],
+			"usageMetadata": {"promptTokenCount": 8, "candidatesTokenCount": 1, "totalTokenCount": 9}}`
+		if thinking, ok := body["generationConfig"].(map[string]interface{})["thinkingConfig"].(map[string]interface{}); ok {
+			if thinking["thinkingBudget"] != 1024.0 || thinking["includeThoughts"] != true {
+				t.Errorf("expected the budget with thought summaries, got %v", thinking)
+			}
+			reply = `{"candidates": [{"content": {"parts": [{"text": "The capital is the seat of government.", "thought": true}, {"text": "Paris"}]}, "finishReason": "STOP"}],
+				"usageMetadata": {"promptTokenCount": 8, "candidatesTokenCount": 1, "thoughtsTokenCount": 300, "totalTokenCount": 309}}`
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(reply)), Header: make(http.Header)}, nil
+	}))
+
+	budget, temperature := int32(1024), float32(0.5)
+	result, err := client.ExecuteMultiVariation(context.Background(), "user-1", &types.MultiExecutionRequest{
+		BasePrompt: "Capital of France?",
+		Configurations: []types.APIConfiguration{
+			{VariationName: "thinking", ModelName: "gemini-2.5-flash", ThinkingBudget: &budget},
+			{VariationName: "plain", ModelName: "gemini-2.5-flash", Temperature: &temperature},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, variation := range result.Results {
+		response := variation.Response
+		if response.ResponseText != "Paris" {
+			t.Errorf("expected the answer without the thought summary, got %q", response.ResponseText)
+		}
+		switch variation.Configuration.VariationName {
+		case "thinking":
+			if response.ThoughtSummary != "The capital is the seat of government." || usageTokens(response.UsageMetadata, "thoughts_tokens") != 300 {
+				t.Errorf("expected the thought summary and thinking tokens, got %+v", response)
+			}
+			if cost := ResponseCostUSD("gemini-2.5-flash", response); cost != EstimateCostUSD("gemini-2.5-flash", 8, 301) {
+				t.Errorf("expected thinking tokens billed as output, got %v", cost)
+			}
+		case "plain":
+			if response.ThoughtSummary != "" {
+				t.Errorf("expected no thought summary without a budget, got %q", response.ThoughtSummary)
+			}
+		}
+	}
+
+	comparison, err := client.compareResults(context.Background(), "user-1", result, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	scores := comparison.ConfigurationScores
+	if thinking := scores["thinking"].(map[string]interface{}); thinking[reasoningTokensMetric] != 300 || thinking["reasoning_share"] != 300.0/301 {
+		t.Errorf("expected the reasoning spend scored, got %v", thinking)
+	}
+	if _, ok := scores["plain"].(map[string]interface{})[reasoningTokensMetric]; ok {
+		t.Error("expected no reasoning score for the variation that did not think")
+	}
+	if !strings.Contains(comparison.AnalysisNotes, "• Reasoning: 300 thinking tokens across 1 variations") {
+		t.Errorf("expected the reasoning insight, got %q", comparison.AnalysisNotes)
+	}
+}
+
+func TestThinkingBudget(t *testing.T) {
+	// The budget survives the generation config the configuration is saved with
+	budget := int32(dynamicThinkingBudget)
+	config := &types.APIConfiguration{ThinkingBudget: &budget, GenerationConfig: map[string]interface{}{"candidateCount": 1}}
+	stored, _ := json.Marshal(storedThinking(config, config.GenerationConfig))
+	loaded := &types.APIConfiguration{GenerationConfig: map[string]interface{}{"candidateCount": 1, "thinkingBudget": -1}}
+	loadThinking(loaded, stored)
+	if loaded.ThinkingBudget == nil || *loaded.ThinkingBudget != dynamicThinkingBudget || len(loaded.GenerationConfig) != 1 || len(config.GenerationConfig) != 1 {
+		t.Errorf("expected the budget to round-trip outside the generation config, got %+v", loaded)
+	}
+
+	off, tooLarge, negative := int32(0), int32(maxThinkingBudget+1), int32(-2)
+	request := &types.MultiExecutionRequest{Configurations: []types.APIConfiguration{
+		{ModelName: "gemini-2.5-flash", ThinkingBudget: &budget},
+		{ModelName: "gemini-2.5-flash", ThinkingBudget: &off},
+		{ModelName: "gemini-2.5-pro", ThinkingBudget: &tooLarge},
+		{ModelName: "gemini-2.5-flash", ThinkingBudget: &negative},
+		{ModelName: "llama3.2", Provider: types.ProviderOllama, ThinkingBudget: &off},
+	}}
+	validation := validateRequest(request, nil)
+	if validation == nil || len(validation.Errors) != 3 ||
+		validation.Errors[0].Field != "configurations[2].thinkingBudget" ||
+		validation.Errors[1].Field != "configurations[3].thinkingBudget" ||
+		validation.Errors[2].Field != "configurations[4].thinkingBudget" {
+		t.Errorf("expected out-of-range budgets and the Ollama budget to be rejected, got %v", validation)
+	}
+}
@@ -0,0 +1,117 @@
+package gogent
+
+import (
+	"encoding/json"
+	"strings"
+
+	"gogent/internal/gemini"
+	"gogent/internal/types"
+)
+
+// toolChoiceModes are the function calling modes a configuration may set
+var toolChoiceModes = map[string]bool{types.ToolChoiceAuto: true, types.ToolChoiceAny: true, types.ToolChoiceNone: true}
+
+// toolChoiceMode returns a configuration's function calling mode in upper case, ANY when unset
+func toolChoiceMode(config *types.APIConfiguration) string {
+	if config.ToolChoiceMode == "" {
+		return types.ToolChoiceAny
+	}
+	return strings.ToUpper(config.ToolChoiceMode)
+}
+
+// geminiToolConfig returns the function calling config sent with a configuration's tools
+func geminiToolConfig(config *types.APIConfiguration) *gemini.ToolConfig {
+	functionCalling := gemini.FunctionCallingConfig{Mode: toolChoiceMode(config)}
+	if functionCalling.Mode == types.ToolChoiceAny {
+		functionCalling.AllowedFunctionNames = config.AllowedFunctionNames
+	}
+	return &gemini.ToolConfig{FunctionCallingConfig: functionCalling}
+}
+
+// choiceTools returns the tools a provider without function calling modes is sent: none in NONE
+// mode and, in ANY mode, only the allowed functions. Such providers cannot be made to call one.
+func choiceTools(config *types.APIConfiguration) []types.Tool {
+	switch toolChoiceMode(config) {
+	case types.ToolChoiceNone:
+		return nil
+	case types.ToolChoiceAny:
+		if len(config.AllowedFunctionNames) > 0 {
+			allowed := make(map[string]bool, len(config.AllowedFunctionNames))
+			for _, name := range config.AllowedFunctionNames {
+				allowed[name] = true
+			}
+			var tools []types.Tool
+			for _, tool := range config.Tools {
+				if allowed[tool.Name] {
+					tools = append(tools, tool)
+				}
+			}
+			return tools
+		}
+	}
+	return config.Tools
+}
+
+// validateToolChoice checks a configuration's function calling mode and that its allowed functions
+// are among the tools it can receive
+func validateToolChoice(validation *ValidationError, field string, config *types.APIConfiguration, toolNames map[string]bool) {
+	if config.ToolChoiceMode != "" && !toolChoiceModes[strings.ToUpper(config.ToolChoiceMode)] {
+		validation.add(field+".toolChoiceMode", "unknown mode %q; use AUTO, ANY or NONE", config.ToolChoiceMode)
+		return
+	}
+	if len(config.AllowedFunctionNames) == 0 {
+		return
+	}
+	if toolChoiceMode(config) != types.ToolChoiceAny {
+		validation.add(field+".allowedFunctionNames", "only applies in ANY mode")
+		return
+	}
+
+	available := make(map[string]bool, len(toolNames)+len(config.Tools))
+	for name := range toolNames {
+		available[name] = true
+	}
+	for _, tool := range config.Tools {
+		available[tool.Name] = true
+	}
+	subset := make(map[string]bool, len(config.ToolNames))
+	for _, name := range config.ToolNames {
+		subset[name] = true
+	}
+	for _, name := range config.AllowedFunctionNames {
+		if !available[name] || (len(subset) > 0 && !subset[name]) {
+			validation.add(field+".allowedFunctionNames", "%q is not one of the configuration's tools", name)
+		}
+	}
+}
+
+// storedToolConfig adds the function calling mode to the tool config saved with a configuration
+func storedToolConfig(config *types.APIConfiguration) map[string]interface{} {
+	if config.ToolChoiceMode == "" && len(config.AllowedFunctionNames) == 0 {
+		return config.ToolConfig
+	}
+	stored := make(map[string]interface{}, len(config.ToolConfig)+1)
+	for key, value := range config.ToolConfig {
+		stored[key] = value
+	}
+	functionCalling := map[string]interface{}{"mode": toolChoiceMode(config)}
+	if len(config.AllowedFunctionNames) > 0 {
+		functionCalling["allowedFunctionNames"] = config.AllowedFunctionNames
+	}
+	stored["functionCallingConfig"] = functionCalling
+	return stored
+}
+
+// loadToolChoice restores the function calling mode from a saved tool config
+func loadToolChoice(config *types.APIConfiguration, toolConfig json.RawMessage) {
+	if len(toolConfig) == 0 {
+		return
+	}
+	var stored struct {
+		FunctionCallingConfig gemini.FunctionCallingConfig `json:"functionCallingConfig"`
+	}
+	if err := json.Unmarshal(toolConfig, &stored); err == nil {
+		config.ToolChoiceMode = stored.FunctionCallingConfig.Mode
+		config.AllowedFunctionNames = stored.FunctionCallingConfig.AllowedFunctionNames
+	}
+}
@@ -0,0 +1,114 @@
+package gogent
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"gogent/internal/types"
+)
+
+func TestGeminiToolConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        types.APIConfiguration
+		expectMode    string
+		expectAllowed []string
+	}{
+		{"default_any", types.APIConfiguration{}, types.ToolChoiceAny, nil},
+		{"auto_lower_case", types.APIConfiguration{ToolChoiceMode: "auto"}, types.ToolChoiceAuto, nil},
+		{"any_allowed", types.APIConfiguration{ToolChoiceMode: "ANY", AllowedFunctionNames: []string{"get_weather"}}, types.ToolChoiceAny, []string{"get_weather"}},
+		{"none_drops_allowed", types.APIConfiguration{ToolChoiceMode: "NONE", AllowedFunctionNames: []string{"get_weather"}}, types.ToolChoiceNone, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			functionCalling := geminiToolConfig(&tt.config).FunctionCallingConfig
+			if functionCalling.Mode != tt.expectMode || !reflect.DeepEqual(functionCalling.AllowedFunctionNames, tt.expectAllowed) {
+				t.Errorf("expected mode %s allowing %v, got %+v", tt.expectMode, tt.expectAllowed, functionCalling)
+			}
+		})
+	}
+}
+
+func TestToolChoiceFunctionInstruction(t *testing.T) {
+	if instruction := resolveFunctionInstruction(&types.APIConfiguration{}); instruction != DefaultFunctionInstruction {
+		t.Errorf("expected ANY mode to get the default instruction, got %q", instruction)
+	}
+	if instruction := resolveFunctionInstruction(&types.APIConfiguration{ToolChoiceMode: types.ToolChoiceAuto}); instruction != "" {
+		t.Errorf("expected AUTO mode not to be told to call a function, got %q", instruction)
+	}
+	custom := &types.APIConfiguration{ToolChoiceMode: types.ToolChoiceNone, FunctionInstruction: "Answer from memory."}
+	if instruction := resolveFunctionInstruction(custom); instruction != "Answer from memory." {
+		t.Errorf("expected a configuration's own instruction in any mode, got %q", instruction)
+	}
+}
+
+func TestChoiceTools(t *testing.T) {
+	tools := []types.Tool{{Name: "get_weather"}, {Name: "web_search"}}
+	if got := choiceTools(&types.APIConfiguration{Tools: tools, ToolChoiceMode: types.ToolChoiceNone}); len(got) != 0 {
+		t.Errorf("expected NONE mode to send no tools, got %v", got)
+	}
+	got := choiceTools(&types.APIConfiguration{Tools: tools, AllowedFunctionNames: []string{"web_search"}})
+	if len(got) != 1 || got[0].Name != "web_search" {
+		t.Errorf("expected only the allowed function, got %v", got)
+	}
+	if got := choiceTools(&types.APIConfiguration{Tools: tools, ToolChoiceMode: types.ToolChoiceAuto}); len(got) != 2 {
+		t.Errorf("expected AUTO mode to send every tool, got %v", got)
+	}
+}
+
+func TestValidateToolChoice(t *testing.T) {
+	functionTools := []types.Tool{{Name: "get_weather"}, {Name: "web_search"}}
+	tests := []struct {
+		name        string
+		config      types.APIConfiguration
+		expectField string
+	}{
+		{"valid", types.APIConfiguration{ToolChoiceMode: "any", AllowedFunctionNames: []string{"web_search"}}, ""},
+		{"unknown_mode", types.APIConfiguration{ToolChoiceMode: "REQUIRED"}, "configurations[0].toolChoiceMode"},
+		{"allowed_outside_any", types.APIConfiguration{ToolChoiceMode: types.ToolChoiceAuto, AllowedFunctionNames: []string{"web_search"}}, "configurations[0].allowedFunctionNames"},
+		{"unknown_function", types.APIConfiguration{AllowedFunctionNames: []string{"get_stock_quote"}}, "configurations[0].allowedFunctionNames"},
+		{"outside_tool_subset", types.APIConfiguration{ToolNames: []string{"get_weather"}, AllowedFunctionNames: []string{"web_search"}}, "configurations[0].allowedFunctionNames"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.VariationName = "a"
+			tt.config.ModelName = "gemini-2.0-flash"
+			request := &types.MultiExecutionRequest{
+				EnableFunctionCalling: true,
+				FunctionTools:         functionTools,
+				Configurations:        []types.APIConfiguration{tt.config},
+			}
+			validation := validateRequest(request, nil)
+			if tt.expectField == "" {
+				if validation != nil {
+					t.Errorf("expected no errors, got %v", validation)
+				}
+				return
+			}
+			if validation == nil || len(validation.Errors) != 1 || validation.Errors[0].Field != tt.expectField {
+				t.Errorf("expected an error for %s, got %v", tt.expectField, validation)
+			}
+		})
+	}
+}
+
+func TestStoredToolConfig(t *testing.T) {
+	config := &types.APIConfiguration{ToolChoiceMode: "auto", ToolConfig: map[string]interface{}{"other": true}}
+	stored, err := json.Marshal(storedToolConfig(config))
+	if err != nil {
+		t.Fatalf("failed to encode tool config: %v", err)
+	}
+
+	var loaded types.APIConfiguration
+	loadToolChoice(&loaded, stored)
+	if loaded.ToolChoiceMode != types.ToolChoiceAuto || loaded.AllowedFunctionNames != nil {
+		t.Errorf("expected the mode to survive the saved tool config, got %+v", loaded)
+	}
+	if config.ToolConfig["functionCallingConfig"] != nil {
+		t.Error("expected the configuration's own tool config to be left alone")
+	}
+	if stored := storedToolConfig(&types.APIConfiguration{}); stored != nil {
+		t.Errorf("expected nothing to store without a mode, got %v", stored)
+	}
+}
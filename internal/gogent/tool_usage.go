@@ -0,0 +1,103 @@
+package gogent
+
+import (
+	"strings"
+
+	"gogent/internal/types"
+)
+
+// toolNameStopWords are tool name fragments too generic to signal that a tool is needed
+var toolNameStopWords = map[string]bool{
+	"get":     true,
+	"set":     true,
+	"current": true,
+	"query":   true,
+	"list":    true,
+	"fetch":   true,
+	"call":    true,
+	"run":     true,
+	"create":  true,
+	"update":  true,
+	"delete":  true,
+}
+
+// classifyToolUsage determines whether a variation's tool usage matched what the prompt needed
+func classifyToolUsage(r types.VariationResult, cfg *types.ToolAppropriatenessConfig) types.ToolUsageOutcome {
+	if len(r.Configuration.Tools) == 0 {
+		return types.ToolUsageNotAvailable
+	}
+
+	called := variationCalledTool(r)
+	needed := toolUseExpected(r.Request.Prompt+" "+r.Request.Context, r.Configuration.Tools, cfg)
+
+	switch {
+	case called && needed:
+		return types.ToolUsageAppropriateCall
+	case called && !needed:
+		return types.ToolUsageUnnecessaryCall
+	case !called && needed:
+		return types.ToolUsageMissedCall
+	default:
+		return types.ToolUsageAppropriateNoCall
+	}
+}
+
+// toolAppropriatenessScore converts a tool usage outcome into a 0-1 score
+func toolAppropriatenessScore(outcome types.ToolUsageOutcome) float64 {
+	switch outcome {
+	case types.ToolUsageAppropriateCall, types.ToolUsageAppropriateNoCall:
+		return 1.0
+	case types.ToolUsageUnnecessaryCall:
+		return 0.4
+	case types.ToolUsageMissedCall:
+		return 0.0
+	default:
+		return 1.0 // Nothing to judge when no tools were offered
+	}
+}
+
+// variationCalledTool reports whether the model invoked any tool for this variation
+func variationCalledTool(r types.VariationResult) bool {
+	if len(r.FunctionCalls) > 0 {
+		return true
+	}
+	if name, ok := r.Response.FunctionCallResponse["function_name"].(string); ok && name != "" {
+		return true
+	}
+	return false
+}
+
+// toolUseExpected decides whether the prompt needs a tool, using the explicit expectation or keyword heuristics
+func toolUseExpected(prompt string, tools []types.Tool, cfg *types.ToolAppropriatenessConfig) bool {
+	if cfg != nil && cfg.ExpectToolUse != nil {
+		return *cfg.ExpectToolUse
+	}
+
+	var keywords []string
+	if cfg != nil && len(cfg.ToolKeywords) > 0 {
+		keywords = cfg.ToolKeywords
+	} else {
+		keywords = defaultToolKeywords(tools)
+	}
+
+	lowerPrompt := strings.ToLower(prompt)
+	for _, keyword := range keywords {
+		if keyword != "" && strings.Contains(lowerPrompt, strings.ToLower(keyword)) {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultToolKeywords derives heuristic keywords from tool names, e.g. get_current_weather -> weather
+func defaultToolKeywords(tools []types.Tool) []string {
+	var keywords []string
+	for _, tool := range tools {
+		for _, part := range strings.Split(strings.ToLower(tool.Name), "_") {
+			if len(part) >= 4 && !toolNameStopWords[part] {
+				keywords = append(keywords, part)
+			}
+		}
+	}
+	return keywords
+}
@@ -0,0 +1,99 @@
+package gogent
+
+import (
+	"testing"
+
+	"gogent/internal/types"
+)
+
+func TestClassifyToolUsage(t *testing.T) {
+	weatherTool := types.Tool{Name: "get_current_weather", Description: "Get weather"}
+	expectNoTool := false
+
+	tests := []struct {
+		name   string
+		result types.VariationResult
+		config *types.ToolAppropriatenessConfig
+		expect types.ToolUsageOutcome
+	}{
+		{
+			name: "no_tools_offered",
+			result: types.VariationResult{
+				Request: types.APIRequest{Prompt: "What's the weather in Paris?"},
+			},
+			expect: types.ToolUsageNotAvailable,
+		},
+		{
+			name: "called_when_needed",
+			result: types.VariationResult{
+				Configuration: types.APIConfiguration{Tools: []types.Tool{weatherTool}},
+				Request:       types.APIRequest{Prompt: "What's the weather in Paris?"},
+				Response: types.APIResponse{
+					FunctionCallResponse: map[string]interface{}{"function_name": "get_current_weather"},
+				},
+			},
+			expect: types.ToolUsageAppropriateCall,
+		},
+		{
+			name: "missed_call",
+			result: types.VariationResult{
+				Configuration: types.APIConfiguration{Tools: []types.Tool{weatherTool}},
+				Request:       types.APIRequest{Prompt: "What's the weather in Paris?"},
+			},
+			expect: types.ToolUsageMissedCall,
+		},
+		{
+			name: "unnecessary_call",
+			result: types.VariationResult{
+				Configuration: types.APIConfiguration{Tools: []types.Tool{weatherTool}},
+				Request:       types.APIRequest{Prompt: "Write a haiku about autumn"},
+				FunctionCalls: []types.FunctionCall{{FunctionName: "get_current_weather"}},
+			},
+			expect: types.ToolUsageUnnecessaryCall,
+		},
+		{
+			name: "explicit_expectation_overrides_keywords",
+			result: types.VariationResult{
+				Configuration: types.APIConfiguration{Tools: []types.Tool{weatherTool}},
+				Request:       types.APIRequest{Prompt: "Explain how weather forecasts work"},
+			},
+			config: &types.ToolAppropriatenessConfig{ExpectToolUse: &expectNoTool},
+			expect: types.ToolUsageAppropriateNoCall,
+		},
+		{
+			name: "custom_keywords",
+			result: types.VariationResult{
+				Configuration: types.APIConfiguration{Tools: []types.Tool{weatherTool}},
+				Request:       types.APIRequest{Prompt: "Do I need an umbrella today?"},
+			},
+			config: &types.ToolAppropriatenessConfig{ToolKeywords: []string{"umbrella"}},
+			expect: types.ToolUsageMissedCall,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outcome := classifyToolUsage(tt.result, tt.config)
+			if outcome != tt.expect {
+				t.Errorf("expected outcome %s, got %s", tt.expect, outcome)
+			}
+		})
+	}
+}
+
+func TestDefaultToolKeywords(t *testing.T) {
+	keywords := defaultToolKeywords([]types.Tool{
+		{Name: "get_current_weather"},
+		{Name: "query_graph"},
+	})
+
+	expected := []string{"weather", "graph"}
+	if len(keywords) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, keywords)
+	}
+	for i, keyword := range expected {
+		if keywords[i] != keyword {
+			t.Errorf("expected keyword %d to be %s, got %s", i, keyword, keywords[i])
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package gogent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"gogent/internal/types"
+)
+
+// ErrInvalidTrendQuery is returned when a trend query does not select exactly one preset or variation name
+var ErrInvalidTrendQuery = errors.New("invalid trend query")
+
+const (
+	// defaultTrendLimit is how many runs a trend covers when the query sets no limit
+	defaultTrendLimit = 30
+	// maxTrendLimit caps how many runs a trend may cover
+	maxTrendLimit = 365
+	// minDriftBaselineRuns is how many earlier runs a trend needs before drift is reported
+	minDriftBaselineRuns = 3
+)
+
+// GetConfigurationTrend follows a preset or variation name across the user's runs, newest runs
+// first up to the query's limit, and returns each run's overall score, latency and cost oldest
+// first. The latest run is compared with the ones before it so drift stands out.
+func (c *Client) GetConfigurationTrend(ctx context.Context, userID string, query types.TrendQuery) (*types.ConfigurationTrend, error) {
+	if (query.PresetID == "") == (query.VariationName == "") {
+		return nil, fmt.Errorf("%w: set either a preset ID or a variation name", ErrInvalidTrendQuery)
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultTrendLimit
+	}
+	if query.Limit < 1 || query.Limit > maxTrendLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d, got %d", ErrInvalidTrendQuery, maxTrendLimit, query.Limit)
+	}
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+
+	points, err := c.trendConfigurations(ctx, userID, query)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.addTrendResponses(ctx, userID, points); err != nil {
+		return nil, err
+	}
+	if err := c.addTrendScores(ctx, userID, points); err != nil {
+		return nil, err
+	}
+
+	trend := &types.ConfigurationTrend{
+		PresetID:      query.PresetID,
+		VariationName: query.VariationName,
+		Points:        make([]types.TrendPoint, len(points)),
+	}
+	for i, point := range points {
+		trend.Points[i] = *point
+	}
+	trend.Drift = trendDrift(trend.Points)
+	return trend, nil
+}
+
+// trendConfigurations returns the configurations a trend query selects, oldest run first
+func (c *Client) trendConfigurations(ctx context.Context, userID string, query types.TrendQuery) ([]*types.TrendPoint, error) {
+	sqlQuery := `
+		SELECT c.id, c.execution_run_id, r.name, c.variation_name, c.model_name, r.created_at
+		FROM api_configurations c
+		JOIN execution_runs r ON r.id = c.execution_run_id
+		WHERE c.user_id = ?`
+	args := []interface{}{userID}
+	if query.PresetID != "" {
+		sqlQuery += " AND c.preset_id = ?"
+		args = append(args, query.PresetID)
+	} else {
+		sqlQuery += " AND c.variation_name = ?"
+		args = append(args, query.VariationName)
+	}
+	if !query.Since.IsZero() {
+		sqlQuery += " AND r.created_at >= ?"
+		args = append(args, query.Since)
+	}
+	rows, err := c.db.QueryContext(ctx, sqlQuery+" ORDER BY r.created_at DESC, c.id ASC LIMIT ?", append(args, query.Limit)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trend configurations: %w", err)
+	}
+	defer rows.Close()
+
+	var points []*types.TrendPoint
+	for rows.Next() {
+		var point types.TrendPoint
+		if err := rows.Scan(&point.ConfigurationID, &point.ExecutionRunID, &point.RunName, &point.VariationName,
+			&point.ModelName, &point.RunAt); err != nil {
+			return nil, fmt.Errorf("failed to scan trend configuration: %w", err)
+		}
+		points = append(points, &point)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate trend configurations: %w", err)
+	}
+	slices.Reverse(points)
+	return points, nil
+}
+
+// addTrendResponses fills in each point's samples, errors, average latency and cost from its responses
+func (c *Client) addTrendResponses(ctx context.Context, userID string, points []*types.TrendPoint) error {
+	if len(points) == 0 {
+		return nil
+	}
+	byConfiguration := make(map[string]*types.TrendPoint, len(points))
+	args := []interface{}{userID}
+	for _, point := range points {
+		byConfiguration[point.ConfigurationID] = point
+		args = append(args, point.ConfigurationID)
+	}
+
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT q.configuration_id, resp.response_status, resp.response_time_ms, resp.usage_metadata
+		FROM api_requests q
+		JOIN api_responses resp ON resp.request_id = q.id
+		WHERE q.user_id = ? AND q.configuration_id IN (`+placeholders(len(points))+`)`, args...)
+	if err != nil {
+		return fmt.Errorf("failed to get trend responses: %w", err)
+	}
+	defer rows.Close()
+
+	totalTimeMs := make(map[string]float64, len(points))
+	for rows.Next() {
+		var configurationID string
+		var status, usage []byte
+		var responseTimeMs *int64
+		if err := rows.Scan(&configurationID, &status, &responseTimeMs, &usage); err != nil {
+			return fmt.Errorf("failed to scan trend response: %w", err)
+		}
+		point := byConfiguration[configurationID]
+		point.Samples++
+		if types.ResponseStatus(status) != types.ResponseStatusSuccess {
+			point.Errors++
+		}
+		if responseTimeMs != nil {
+			totalTimeMs[configurationID] += float64(*responseTimeMs)
+		}
+		var response types.APIResponse
+		if len(usage) > 0 && json.Unmarshal(usage, &response.UsageMetadata) == nil {
+			point.CostUSD += ResponseCostUSD(point.ModelName, response)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate trend responses: %w", err)
+	}
+
+	for _, point := range points {
+		if point.Samples > 0 {
+			point.AvgResponseTimeMs = totalTimeMs[point.ConfigurationID] / float64(point.Samples)
+		}
+	}
+	return nil
+}
+
+// addTrendScores fills in each point's overall score from its run's comparison, keeping the
+// latest comparison of a run compared more than once
+func (c *Client) addTrendScores(ctx context.Context, userID string, points []*types.TrendPoint) error {
+	if len(points) == 0 {
+		return nil
+	}
+	runs := make(map[string]bool)
+	args := []interface{}{userID}
+	for _, point := range points {
+		if !runs[point.ExecutionRunID] {
+			runs[point.ExecutionRunID] = true
+			args = append(args, point.ExecutionRunID)
+		}
+	}
+
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT cr.execution_run_id, cr.configuration_scores
+		FROM comparison_results cr
+		JOIN execution_runs r ON r.id = cr.execution_run_id
+		WHERE r.user_id = ? AND cr.execution_run_id IN (`+placeholders(len(runs))+`)
+		ORDER BY cr.created_at ASC`, args...)
+	if err != nil {
+		return fmt.Errorf("failed to get trend comparisons: %w", err)
+	}
+	defer rows.Close()
+
+	scores := make(map[string]map[string]interface{}, len(runs))
+	for rows.Next() {
+		var executionRunID string
+		var configurationScores []byte
+		if err := rows.Scan(&executionRunID, &configurationScores); err != nil {
+			return fmt.Errorf("failed to scan trend comparison: %w", err)
+		}
+		var runScores map[string]interface{}
+		if len(configurationScores) > 0 && json.Unmarshal(configurationScores, &runScores) == nil {
+			scores[executionRunID] = runScores
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate trend comparisons: %w", err)
+	}
+
+	for _, point := range points {
+		variationScores, _ := scores[point.ExecutionRunID][point.VariationName].(map[string]interface{})
+		if overall, ok := numericScore(variationScores["overall_score"]); ok {
+			point.OverallScore = &overall
+		}
+	}
+	return nil
+}
+
+// trendDrift compares the latest point's overall score, latency and cost with the points before it
+func trendDrift(points []types.TrendPoint) []types.MetricDrift {
+	if len(points) == 0 {
+		return nil
+	}
+	metrics := []struct {
+		name  string
+		value func(types.TrendPoint) (float64, bool)
+	}{
+		{"overall_score", func(p types.TrendPoint) (float64, bool) {
+			if p.OverallScore == nil {
+				return 0, false
+			}
+			return *p.OverallScore, true
+		}},
+		{"avg_response_time_ms", func(p types.TrendPoint) (float64, bool) { return p.AvgResponseTimeMs, p.Samples > 0 }},
+		{"cost_usd", func(p types.TrendPoint) (float64, bool) { return p.CostUSD, p.Samples > 0 }},
+	}
+
+	var drift []types.MetricDrift
+	latest, earlier := points[len(points)-1], points[:len(points)-1]
+	for _, metric := range metrics {
+		latestValue, ok := metric.value(latest)
+		if !ok {
+			continue
+		}
+		var baseline []float64
+		for _, point := range earlier {
+			if value, ok := metric.value(point); ok {
+				baseline = append(baseline, value)
+			}
+		}
+		if len(baseline) < minDriftBaselineRuns {
+			continue
+		}
+		stats := SummarizeSamples(baseline)
+		change := latestValue - stats.Mean
+		drift = append(drift, types.MetricDrift{
+			Metric:         metric.name,
+			BaselineRuns:   len(baseline),
+			BaselineMean:   stats.Mean,
+			BaselineStdDev: stats.StdDev,
+			Latest:         latestValue,
+			Change:         change,
+			Drifted:        change != 0 && math.Abs(change) > 2*stats.StdDev,
+		})
+	}
+	return drift
+}
@@ -0,0 +1,116 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"gogent/internal/testdb"
+	"gogent/internal/types"
+)
+
+// newTrendTestClient returns a client backed by in-memory run, configuration, request, response and
+// comparison tables
+func newTrendTestClient(t *testing.T) *Client {
+	return &Client{db: testdb.Open(t)}
+}
+
+// addTrendRun stores a run with one configuration of a preset, its responses and, when score is
+// at least 0, a comparison
+func addTrendRun(t *testing.T, client *Client, userID string, day int, presetID string, score float64, responseTimesMs ...int) {
+	exec := func(query string, args ...interface{}) {
+		if _, err := client.db.Exec(query, args...); err != nil {
+			t.Fatalf("failed to store test run: %v", err)
+		}
+	}
+
+	run := fmt.Sprintf("run-%s-%d", userID, day)
+	config := "config-" + run
+	exec("INSERT INTO execution_runs (id, user_id, name, created_at) VALUES (?, ?, ?, ?)",
+		run, userID, fmt.Sprintf("nightly-%02d", day), time.Date(2025, 6, day, 2, 0, 0, 0, time.UTC))
+	exec("INSERT INTO api_configurations (id, user_id, execution_run_id, variation_name, model_name, preset_id) VALUES (?, ?, ?, ?, ?, ?)",
+		config, userID, run, "focused", "gemini-2.0-flash", presetID)
+	for i, responseTimeMs := range responseTimesMs {
+		request := fmt.Sprintf("request-%s-%d", run, i)
+		status := types.ResponseStatusSuccess
+		if responseTimeMs == 0 {
+			status = types.ResponseStatusError
+		}
+		exec("INSERT INTO api_requests (id, user_id, configuration_id) VALUES (?, ?, ?)", request, userID, config)
+		exec("INSERT INTO api_responses (id, request_id, response_status, response_time_ms, usage_metadata) VALUES (?, ?, ?, ?, ?)",
+			"response-"+request, request, status, responseTimeMs, `{"prompt_tokens": 1000, "completion_tokens": 500}`)
+	}
+	if score >= 0 {
+		exec("INSERT INTO comparison_results (id, execution_run_id, configuration_scores) VALUES (?, ?, ?)",
+			"comparison-"+run, run, fmt.Sprintf(`{"focused": {"overall_score": %v}}`, score))
+	}
+}
+
+func TestGetConfigurationTrend(t *testing.T) {
+	client := newTrendTestClient(t)
+	ctx := context.Background()
+
+	addTrendRun(t, client, "user-1", 1, "preset-1", 0.8, 900)
+	addTrendRun(t, client, "user-1", 2, "preset-1", 0.82, 1000, 0)
+	addTrendRun(t, client, "user-1", 3, "preset-1", -1, 950)
+	addTrendRun(t, client, "user-1", 4, "preset-1", 0.79, 920)
+	addTrendRun(t, client, "user-1", 5, "preset-1", 0.81, 2400)
+	addTrendRun(t, client, "user-1", 6, "preset-2", 0.5, 100)
+	addTrendRun(t, client, "user-2", 7, "preset-1", 0.1, 100)
+
+	trend, err := client.GetConfigurationTrend(ctx, "user-1", types.TrendQuery{PresetID: "preset-1"})
+	if err != nil {
+		t.Fatalf("failed to get trend: %v", err)
+	}
+	if len(trend.Points) != 5 {
+		t.Fatalf("expected the 5 runs of user-1's preset, got %d", len(trend.Points))
+	}
+	for i, point := range trend.Points {
+		if want := fmt.Sprintf("nightly-%02d", i+1); point.RunName != want {
+			t.Errorf("point %d: expected %s oldest first, got %s", i, want, point.RunName)
+		}
+	}
+
+	second := trend.Points[1]
+	if second.Samples != 2 || second.Errors != 1 || second.AvgResponseTimeMs != 500 || *second.OverallScore != 0.82 {
+		t.Errorf("unexpected point for a repeated run: %+v", second)
+	}
+	if cost := EstimateCostUSD("gemini-2.0-flash", 1000, 500); second.CostUSD != 2*cost {
+		t.Errorf("expected the cost of both samples, got %v", second.CostUSD)
+	}
+	if trend.Points[2].OverallScore != nil {
+		t.Errorf("expected no overall score for a run without a comparison")
+	}
+
+	drift := make(map[string]types.MetricDrift)
+	for _, metric := range trend.Drift {
+		drift[metric.Metric] = metric
+	}
+	if latency := drift["avg_response_time_ms"]; !latency.Drifted || latency.BaselineRuns != 4 || latency.Latest != 2400 {
+		t.Errorf("expected the latency jump to drift, got %+v", latency)
+	}
+	if score := drift["overall_score"]; score.Drifted || score.BaselineRuns != 3 {
+		t.Errorf("expected the score not to drift over the 3 compared runs, got %+v", score)
+	}
+
+	// The most recent runs are kept
+	trend, err = client.GetConfigurationTrend(ctx, "user-1", types.TrendQuery{PresetID: "preset-1", Limit: 2})
+	if err != nil || len(trend.Points) != 2 || trend.Points[0].RunName != "nightly-04" || trend.Drift != nil {
+		t.Errorf("expected the last 2 runs without drift, got %+v, %v", trend, err)
+	}
+	since := time.Date(2025, 6, 5, 0, 0, 0, 0, time.UTC)
+	if trend, err := client.GetConfigurationTrend(ctx, "user-1", types.TrendQuery{VariationName: "focused", Since: since}); err != nil || len(trend.Points) != 2 {
+		t.Errorf("expected the variation's 2 runs since June 5, got %+v, %v", trend, err)
+	}
+}
+
+func TestGetConfigurationTrendInvalidQuery(t *testing.T) {
+	client := newTrendTestClient(t)
+	for _, query := range []types.TrendQuery{{}, {PresetID: "p", VariationName: "v"}, {PresetID: "p", Limit: 1000}} {
+		if _, err := client.GetConfigurationTrend(context.Background(), "user-1", query); !errors.Is(err, ErrInvalidTrendQuery) {
+			t.Errorf("expected %+v to be rejected, got %v", query, err)
+		}
+	}
+}
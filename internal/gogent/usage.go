@@ -0,0 +1,106 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"gogent/internal/types"
+)
+
+// ErrInvalidUsageQuery is returned when a usage query has an unknown grouping or an empty or too long range
+var ErrInvalidUsageQuery = errors.New("invalid usage query")
+
+const (
+	// defaultUsageRange is how far back a usage report goes when the query sets no start
+	defaultUsageRange = 30 * 24 * time.Hour
+	// maxUsageRange caps the time range of a usage report
+	maxUsageRange = 366 * 24 * time.Hour
+)
+
+// GetUsage reports the tokens and estimated cost of the user's responses between query.From and
+// query.To, grouped by day, model or both. Token counts are summed in SQL per day and model from
+// the typed columns of api_responses, and the cost is estimated per model before regrouping.
+func (c *Client) GetUsage(ctx context.Context, userID string, query types.UsageQuery) (*types.UsageReport, error) {
+	if query.To.IsZero() {
+		query.To = time.Now().UTC()
+	}
+	if query.From.IsZero() {
+		query.From = query.To.Add(-defaultUsageRange)
+	}
+	if !query.From.Before(query.To) || query.To.Sub(query.From) > maxUsageRange {
+		return nil, fmt.Errorf("%w: from must be before to, at most %d days apart", ErrInvalidUsageQuery, int(maxUsageRange.Hours()/24))
+	}
+	if len(query.GroupBy) == 0 {
+		query.GroupBy = []string{types.UsageGroupByDay}
+	}
+	byDay, byModel := slices.Contains(query.GroupBy, types.UsageGroupByDay), slices.Contains(query.GroupBy, types.UsageGroupByModel)
+	for _, group := range query.GroupBy {
+		if group != types.UsageGroupByDay && group != types.UsageGroupByModel {
+			return nil, fmt.Errorf("%w: group by %q or %q, got %q", ErrInvalidUsageQuery, types.UsageGroupByDay, types.UsageGroupByModel, group)
+		}
+	}
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT CAST(DATE(resp.created_at) AS CHAR) AS day, COALESCE(c.model_name, '') AS model_name, COUNT(*),
+			COALESCE(SUM(resp.prompt_tokens), 0), COALESCE(SUM(resp.completion_tokens), 0), COALESCE(SUM(resp.total_tokens), 0)
+		FROM api_responses resp
+		LEFT JOIN api_requests q ON q.id = resp.request_id
+		LEFT JOIN api_configurations c ON c.id = q.configuration_id
+		WHERE resp.user_id = ? AND resp.created_at >= ? AND resp.created_at < ?
+		GROUP BY day, model_name
+		ORDER BY day ASC, model_name ASC`, userID, query.From, query.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get usage: %w", err)
+	}
+	defer rows.Close()
+
+	report := &types.UsageReport{From: query.From, To: query.To, GroupBy: query.GroupBy, Rows: []types.UsageRow{}}
+	groups := make(map[[2]string]int)
+	for rows.Next() {
+		var row types.UsageRow
+		if err := rows.Scan(&row.Day, &row.ModelName, &row.Responses, &row.PromptTokens, &row.CompletionTokens, &row.TotalTokens); err != nil {
+			return nil, fmt.Errorf("failed to scan usage: %w", err)
+		}
+		row.CostUSD = EstimateCostUSD(row.ModelName, int(row.PromptTokens), int(row.CompletionTokens))
+		addUsage(&report.Totals, row)
+
+		if !byDay {
+			row.Day = ""
+		}
+		if !byModel {
+			row.ModelName = ""
+		}
+		key := [2]string{row.Day, row.ModelName}
+		if i, ok := groups[key]; ok {
+			addUsage(&report.Rows[i], row)
+			continue
+		}
+		groups[key] = len(report.Rows)
+		report.Rows = append(report.Rows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate usage: %w", err)
+	}
+
+	// Rows arrive by day then model, so only grouping by model alone needs sorting
+	if byModel && !byDay {
+		slices.SortFunc(report.Rows, func(a, b types.UsageRow) int { return strings.Compare(a.ModelName, b.ModelName) })
+	}
+	return report, nil
+}
+
+// addUsage adds a row's counts and cost to a total
+func addUsage(total *types.UsageRow, row types.UsageRow) {
+	total.Responses += row.Responses
+	total.PromptTokens += row.PromptTokens
+	total.CompletionTokens += row.CompletionTokens
+	total.TotalTokens += row.TotalTokens
+	total.CostUSD += row.CostUSD
+}
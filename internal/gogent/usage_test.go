@@ -0,0 +1,87 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"gogent/internal/types"
+)
+
+// newUsageTestClient returns a quota test client with the requests and configurations usage is grouped by
+func newUsageTestClient(t *testing.T) *Client {
+	client := newQuotaTestClient(t)
+	_, err := client.db.Exec(`INSERT INTO api_configurations (id, model_name) VALUES ('config-flash', 'gemini-2.0-flash'), ('config-pro', 'gemini-2.5-pro');
+		INSERT INTO api_requests (id, configuration_id) VALUES ('request-flash', 'config-flash'), ('request-pro', 'config-pro');`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+	return client
+}
+
+func TestGetUsage(t *testing.T) {
+	client := newUsageTestClient(t)
+	ctx := context.Background()
+	day := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	for _, row := range []struct {
+		id, userID, requestID string
+		prompt, completion    int
+		createdAt             time.Time
+	}{
+		{"r1", "user-1", "request-flash", 1000, 500, day},
+		{"r2", "user-1", "request-flash", 2000, 1000, day.Add(time.Hour)},
+		{"r3", "user-1", "request-pro", 1000, 1000, day},
+		{"r4", "user-1", "request-flash", 100, 100, day.AddDate(0, 0, 1)},
+		{"r5", "user-2", "request-flash", 9000, 9000, day},
+		{"r6", "user-1", "request-flash", 9000, 9000, day.AddDate(0, 0, -40)},
+	} {
+		if _, err := client.db.Exec(`INSERT INTO api_responses (id, user_id, request_id, prompt_tokens, completion_tokens, total_tokens, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`, row.id, row.userID, row.requestID, row.prompt, row.completion, row.prompt+row.completion, row.createdAt); err != nil {
+			t.Fatalf("failed to insert response: %v", err)
+		}
+	}
+	from, to := day.AddDate(0, 0, -1), day.AddDate(0, 0, 2)
+
+	report, err := client.GetUsage(ctx, "user-1", types.UsageQuery{From: from, To: to})
+	if err != nil {
+		t.Fatalf("failed to get usage: %v", err)
+	}
+	if len(report.Rows) != 2 || report.Rows[0].Day != "2025-06-01" || report.Rows[0].ModelName != "" || report.Rows[0].Responses != 3 ||
+		report.Rows[0].TotalTokens != 6500 || report.Rows[1].Day != "2025-06-02" {
+		t.Fatalf("unexpected usage by day: %+v", report.Rows)
+	}
+	wantCost := EstimateCostUSD("gemini-2.0-flash", 3100, 1600) + EstimateCostUSD("gemini-2.5-pro", 1000, 1000)
+	if report.Totals.Responses != 4 || report.Totals.PromptTokens != 4100 || math.Abs(report.Totals.CostUSD-wantCost) > 1e-12 {
+		t.Errorf("unexpected totals: %+v", report.Totals)
+	}
+
+	report, err = client.GetUsage(ctx, "user-1", types.UsageQuery{From: from, To: to, GroupBy: []string{types.UsageGroupByModel}})
+	if err != nil {
+		t.Fatalf("failed to get usage: %v", err)
+	}
+	if len(report.Rows) != 2 || report.Rows[0].ModelName != "gemini-2.0-flash" || report.Rows[0].Day != "" ||
+		report.Rows[0].Responses != 3 || report.Rows[0].CompletionTokens != 1600 || report.Rows[1].ModelName != "gemini-2.5-pro" {
+		t.Errorf("unexpected usage by model: %+v", report.Rows)
+	}
+
+	report, err = client.GetUsage(ctx, "user-1", types.UsageQuery{From: from, To: to, GroupBy: []string{types.UsageGroupByDay, types.UsageGroupByModel}})
+	if err != nil || len(report.Rows) != 3 {
+		t.Errorf("expected 3 day and model rows, got %+v, %v", report, err)
+	}
+}
+
+func TestGetUsageInvalidQuery(t *testing.T) {
+	client := newUsageTestClient(t)
+	now := time.Now()
+	for _, query := range []types.UsageQuery{
+		{From: now, To: now.Add(-time.Hour)},
+		{From: now.AddDate(-2, 0, 0), To: now},
+		{GroupBy: []string{"week"}},
+	} {
+		if _, err := client.GetUsage(context.Background(), "user-1", query); !errors.Is(err, ErrInvalidUsageQuery) {
+			t.Errorf("expected %+v to be rejected, got %v", query, err)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package gogent
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"gogent/internal/types"
+)
+
+// jsonSchemaTypes are the parameter types a tool schema may declare
+var jsonSchemaTypes = map[string]bool{
+	"object": true, "array": true, "string": true, "number": true, "integer": true, "boolean": true, "null": true,
+}
+
+// ValidationError lists every invalid field of a request
+type ValidationError struct {
+	Errors []types.FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, fieldError := range e.Errors {
+		messages[i] = fieldError.Field + ": " + fieldError.Message
+	}
+	return "invalid execution request: " + strings.Join(messages, "; ")
+}
+
+// add records an invalid field
+func (e *ValidationError) add(field, format string, args ...interface{}) {
+	e.Errors = append(e.Errors, types.FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// ValidateRequest checks a request's configurations before a run is created, returning a
+// *ValidationError listing every invalid field. Model names are checked against the model catalog
+// when an API key is configured; without one any model name is accepted.
+func (c *Client) ValidateRequest(ctx context.Context, request *types.MultiExecutionRequest) error {
+	var catalog []types.ModelInfo
+	if c.config != nil && c.config.APIKey != "" {
+		models, err := c.ListModels(ctx)
+		if err != nil {
+			log.Printf("⚠️ Warning: skipping model name validation: %v", err)
+		} else {
+			catalog = models
+		}
+	}
+
+	if validation := validateRequest(request, catalog); validation != nil {
+		return validation
+	}
+	return nil
+}
+
+// validateRequest checks configuration ranges, tool schemas and, when a catalog is given, the
+// names of models whose provider the catalog covers
+func validateRequest(request *types.MultiExecutionRequest, catalog []types.ModelInfo) *ValidationError {
+	validation := &ValidationError{}
+	if len(request.Configurations) == 0 {
+		validation.add("configurations", "at least one configuration is required")
+	}
+
+	models := make(map[string]types.ModelInfo)
+	for _, model := range ModelsSupporting(catalog, "generateContent") {
+		models[model.Name] = model
+	}
+
+	if err := ValidateTags(request.Tags); err != nil {
+		validation.add("tags", "%v", err)
+	}
+	if err := ValidateRawPayloadLimit(request.RawPayloadMaxBytes); err != nil {
+		validation.add("rawPayloadMaxBytes", "%v", err)
+	}
+	if request.CacheContext && request.Context == "" {
+		validation.add("cacheContext", "there is no context to cache")
+	}
+
+	toolNames := validateTools(validation, "functionTools", request.FunctionTools)
+
+	for i, config := range request.Configurations {
+		field := fmt.Sprintf("configurations[%d]", i)
+
+		model, known := models[strings.TrimPrefix(config.ModelName, "models/")]
+		switch {
+		case config.ModelName == "":
+			validation.add(field+".modelName", "model name is required")
+		case len(models) > 0 && !known && config.Backend != types.BackendVertex && catalogCovers(&config):
+			validation.add(field+".modelName", "unknown model %q; see GET /api/models for valid names", config.ModelName)
+		}
+
+		if config.Temperature != nil && (*config.Temperature < 0 || *config.Temperature > 2) {
+			validation.add(field+".temperature", "must be between 0 and 2, got %v", *config.Temperature)
+		}
+		if config.TopP != nil && (*config.TopP < 0 || *config.TopP > 1) {
+			validation.add(field+".topP", "must be between 0 and 1, got %v", *config.TopP)
+		}
+		if config.TopK != nil && *config.TopK < 1 {
+			validation.add(field+".topK", "must be at least 1, got %d", *config.TopK)
+		}
+		if config.MaxTokens != nil {
+			switch {
+			case *config.MaxTokens < 1:
+				validation.add(field+".maxTokens", "must be at least 1, got %d", *config.MaxTokens)
+			case known && model.OutputTokenLimit > 0 && *config.MaxTokens > model.OutputTokenLimit:
+				validation.add(field+".maxTokens", "%s allows at most %d output tokens, got %d",
+					model.Name, model.OutputTokenLimit, *config.MaxTokens)
+			}
+		}
+
+		switch config.Backend {
+		case "", types.BackendGeminiAPI:
+		case types.BackendVertex:
+			if provider := types.ProviderForConfiguration(&config); provider != "" && provider != types.ProviderGemini {
+				validation.add(field+".backend", "the Vertex AI backend only serves Gemini models, got %q", config.ModelName)
+			}
+		default:
+			validation.add(field+".backend", "unknown backend %q; use %q or %q", config.Backend, types.BackendGeminiAPI, types.BackendVertex)
+		}
+		if config.Provider != "" && !knownProvider(config.Provider) {
+			validation.add(field+".provider", "unknown provider %q", config.Provider)
+		}
+		if config.TimeoutMs < 0 {
+			validation.add(field+".timeoutMs", "must not be negative, got %d", config.TimeoutMs)
+		}
+
+		validateResponseFormat(validation, field, &config)
+		validateThinking(validation, field, &config)
+		validateCandidateCount(validation, field, &config)
+		validateRetrieval(validation, field, config.Retrieval)
+		validateGuardrails(validation, field, config.Guardrails)
+		validateTools(validation, field+".tools", config.Tools)
+		validateToolChoice(validation, field, &config, toolNames)
+		for _, name := range config.ToolNames {
+			if !toolNames[name] {
+				validation.add(field+".toolNames", "%q is not one of the run's function tools", name)
+			}
+		}
+	}
+
+	if len(validation.Errors) == 0 {
+		return nil
+	}
+	return validation
+}
+
+// catalogCovers reports whether a configuration's model belongs to the provider the catalog lists;
+// names with no recognizable provider, such as misspelled Gemini models, count as covered
+func catalogCovers(config *types.APIConfiguration) bool {
+	provider := types.ProviderForConfiguration(config)
+	return provider == "" || provider == types.ProviderGemini
+}
+
+// validateTools checks tool names are valid and unique and their parameter schemas are well formed,
+// returning the names seen
+func validateTools(validation *ValidationError, field string, tools []types.Tool) map[string]bool {
+	names := make(map[string]bool, len(tools))
+	for i, tool := range tools {
+		toolField := fmt.Sprintf("%s[%d]", field, i)
+		switch {
+		case !functionNamePattern.MatchString(tool.Name):
+			validation.add(toolField+".name", "invalid tool name %q: use letters, digits and underscores (max 64), starting with a letter or underscore", tool.Name)
+		case names[tool.Name]:
+			validation.add(toolField+".name", "duplicate tool name %q", tool.Name)
+		}
+		names[tool.Name] = true
+
+		if tool.Parameters != nil {
+			if err := validateToolSchema(tool.Parameters); err != nil {
+				validation.add(toolField+".parameters", "%v", err)
+			}
+		}
+	}
+	return names
+}
+
+// validateToolSchema checks a tool's parameters are an object schema whose properties declare
+// known types and whose required fields are all declared
+func validateToolSchema(schema map[string]interface{}) error {
+	if schemaType, ok := schema["type"]; ok {
+		if name, _ := schemaType.(string); strings.ToLower(name) != "object" {
+			return fmt.Errorf("schema type must be \"object\", got %v", schemaType)
+		}
+	}
+
+	properties := map[string]interface{}{}
+	if raw, ok := schema["properties"]; ok {
+		properties, ok = raw.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("properties must be an object")
+		}
+	}
+	for name, raw := range properties {
+		property, ok := raw.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("property %q must be an object", name)
+		}
+		propertyType, _ := property["type"].(string)
+		if !jsonSchemaTypes[strings.ToLower(propertyType)] {
+			return fmt.Errorf("property %q has unknown type %v", name, property["type"])
+		}
+	}
+
+	var required []interface{}
+	switch raw := schema["required"].(type) {
+	case nil:
+	case []interface{}:
+		required = raw
+	case []string:
+		for _, name := range raw {
+			required = append(required, name)
+		}
+	default:
+		return fmt.Errorf("required must be an array of property names")
+	}
+	for _, name := range required {
+		key, ok := name.(string)
+		if !ok || properties[key] == nil {
+			return fmt.Errorf("required property %v is not declared", name)
+		}
+	}
+	return nil
+}
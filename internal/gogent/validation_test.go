@@ -0,0 +1,130 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gogent/internal/types"
+)
+
+// validationCatalog is a model catalog with one generative and one embedding model
+var validationCatalog = []types.ModelInfo{
+	{Name: "gemini-2.0-flash", OutputTokenLimit: 8192, SupportedMethods: []string{"generateContent"}},
+	{Name: "text-embedding-004", SupportedMethods: []string{"embedContent"}},
+}
+
+func TestValidateRequest(t *testing.T) {
+	float := func(v float32) *float32 { return &v }
+	integer := func(v int32) *int32 { return &v }
+	weatherTool := types.Tool{Name: "get_weather", Parameters: map[string]interface{}{
+		"type":       "OBJECT",
+		"properties": map[string]interface{}{"location": map[string]interface{}{"type": "STRING"}},
+		"required":   []interface{}{"location"},
+	}}
+
+	tests := []struct {
+		name         string
+		request      types.MultiExecutionRequest
+		expectFields []string
+	}{
+		{
+			name: "valid",
+			request: types.MultiExecutionRequest{
+				FunctionTools: []types.Tool{weatherTool},
+				Configurations: []types.APIConfiguration{
+					{ModelName: "models/gemini-2.0-flash", Temperature: float(1.5), TopP: float(0.9), TopK: integer(40),
+						MaxTokens: integer(8192), ToolNames: []string{"get_weather"}},
+					{ModelName: "gpt-4o"},
+				},
+			},
+		},
+		{
+			name:         "no_configurations",
+			expectFields: []string{"configurations"},
+		},
+		{
+			name: "unknown_models",
+			request: types.MultiExecutionRequest{Configurations: []types.APIConfiguration{
+				{ModelName: "gemni-2.0-flash"}, {ModelName: "text-embedding-004"}, {},
+			}},
+			expectFields: []string{"configurations[0].modelName", "configurations[1].modelName", "configurations[2].modelName"},
+		},
+		{
+			name: "out_of_range",
+			request: types.MultiExecutionRequest{Configurations: []types.APIConfiguration{
+				{ModelName: "gemini-2.0-flash", Temperature: float(2.5), TopP: float(-0.1), TopK: integer(0), MaxTokens: integer(10000), TimeoutMs: -1},
+			}},
+			expectFields: []string{"configurations[0].temperature", "configurations[0].topP", "configurations[0].topK", "configurations[0].maxTokens",
+				"configurations[0].timeoutMs"},
+		},
+		{
+			name: "backends_and_providers",
+			request: types.MultiExecutionRequest{Configurations: []types.APIConfiguration{
+				{ModelName: "gemini-2.0-flash-001", Backend: types.BackendVertex},
+				{ModelName: "gpt-4o", Backend: types.BackendVertex},
+				{ModelName: "gemini-2.0-flash", Backend: "bedrock"},
+				{ModelName: "llama3.2", Provider: types.ProviderOllama},
+				{ModelName: "llama3.2", Provider: types.ProviderOllama, Backend: types.BackendVertex},
+				{ModelName: "gemini-2.0-flash", Provider: "bedrock"},
+			}},
+			expectFields: []string{"configurations[1].backend", "configurations[2].backend", "configurations[4].backend",
+				"configurations[5].provider"},
+		},
+		{
+			name: "invalid_tools",
+			request: types.MultiExecutionRequest{
+				FunctionTools: []types.Tool{
+					weatherTool,
+					{Name: "get_weather"},
+					{Name: "bad-name"},
+					{Name: "search", Parameters: map[string]interface{}{
+						"type":       "object",
+						"properties": map[string]interface{}{"query": map[string]interface{}{"type": "text"}},
+					}},
+					{Name: "lookup", Parameters: map[string]interface{}{"type": "object", "required": []string{"id"}}},
+				},
+				Configurations: []types.APIConfiguration{
+					{ModelName: "gemini-2.0-flash", ToolNames: []string{"get_wether"}},
+				},
+			},
+			expectFields: []string{"functionTools[1].name", "functionTools[2].name", "functionTools[3].parameters",
+				"functionTools[4].parameters", "configurations[0].toolNames"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validation := validateRequest(&tt.request, validationCatalog)
+			if len(tt.expectFields) == 0 {
+				if validation != nil {
+					t.Fatalf("expected a valid request, got %v", validation)
+				}
+				return
+			}
+			if validation == nil || len(validation.Errors) != len(tt.expectFields) {
+				t.Fatalf("expected errors for %v, got %v", tt.expectFields, validation)
+			}
+			for i, field := range tt.expectFields {
+				if validation.Errors[i].Field != field {
+					t.Errorf("expected error %d on %s, got %+v", i, field, validation.Errors[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidateRequestWithoutCatalog(t *testing.T) {
+	request := &types.MultiExecutionRequest{Configurations: []types.APIConfiguration{{ModelName: "any-model-name"}}}
+	if validation := validateRequest(request, nil); validation != nil {
+		t.Errorf("expected any model name to pass without a catalog, got %v", validation)
+	}
+
+	// Without an API key the client skips the catalog, and returns a *ValidationError otherwise
+	client := &Client{config: &types.GeminiClientConfig{}}
+	request.Configurations[0].TopK = new(int32)
+	var validation *ValidationError
+	if err := client.ValidateRequest(context.Background(), request); !errors.As(err, &validation) || len(validation.Errors) != 1 {
+		t.Errorf("expected a *ValidationError with one field error, got %v", err)
+	}
+}
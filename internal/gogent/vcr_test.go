@@ -0,0 +1,71 @@
+package gogent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gogent/internal/types"
+	"gogent/internal/vcr"
+)
+
+// newRecordedClient returns a client whose Gemini and weather calls go through the named fixture
+// in testdata/vcr. Set VCR_MODE=record, GEMINI_API_KEY and OPENWEATHER_API_KEY to re-record it.
+func newRecordedClient(t *testing.T, fixture string) (*Client, *MemoryStore) {
+	recorder, err := vcr.New(filepath.Join("testdata", "vcr", fixture+".json"), vcr.ModeFromEnv())
+	if err != nil {
+		t.Fatalf("failed to load fixture: %v", err)
+	}
+	if recorder.Recording() {
+		t.Logf("recording fixture %s from live APIs", fixture)
+	}
+	t.Cleanup(func() {
+		if err := recorder.Stop(); err != nil {
+			t.Errorf("failed to save fixture: %v", err)
+		}
+	})
+
+	client, store := newStoreTestClient(t)
+	client.config.APIKey = "recorded-gemini-key"
+	client.config.OpenWeatherAPIKey = "recorded-weather-key"
+	if recorder.Recording() {
+		client.config.APIKey = os.Getenv("GEMINI_API_KEY")
+		client.config.OpenWeatherAPIKey = os.Getenv("OPENWEATHER_API_KEY")
+	}
+	client.SetHTTPTransport(recorder)
+	return client, store
+}
+
+func TestGeminiFunctionCallFixture(t *testing.T) {
+	client, store := newRecordedClient(t, "gemini_weather_function_call")
+	config := &types.APIConfiguration{
+		VariationName: "weather",
+		ModelName:     "gemini-2.0-flash",
+		Tools: []types.Tool{{
+			Name:        "get_current_weather",
+			Description: "Get the current weather for a location",
+			Parameters: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"location": map[string]interface{}{"type": "string"}},
+				"required":   []interface{}{"location"},
+			},
+		}},
+	}
+
+	response, err := client.callModelAPI(context.Background(), config, &types.APIRequest{ID: "request-1", Prompt: "What is the weather in Paris?"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.ResponseText != "It is 64°F with scattered clouds in Paris." {
+		t.Errorf("expected the final answer after the function call, got %q", response.ResponseText)
+	}
+
+	calls := store.FunctionCalls("request-1")
+	if len(calls) != 1 || calls[0].UsedMockData || calls[0].FunctionResponse["condition"] != "Clouds" {
+		t.Fatalf("expected the recorded weather to be returned to the model, got %+v", calls)
+	}
+	if _, fallback := calls[0].FunctionResponse["error"]; fallback {
+		t.Errorf("expected live weather data, not the fallback, got %v", calls[0].FunctionResponse)
+	}
+}
@@ -0,0 +1,338 @@
+package gogent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"gogent/internal/types"
+)
+
+const (
+	// webSearchFunctionName is the built-in function that searches the web
+	webSearchFunctionName = "web_search"
+	// defaultWebSearchResults is how many results a search returns when the model does not ask
+	defaultWebSearchResults = 5
+	// maxWebSearchResults caps the results of a single search
+	maxWebSearchResults = 10
+	// webSearchTimeout bounds a call to a search API
+	webSearchTimeout = 10 * time.Second
+)
+
+// Web search backends selectable with GeminiClientConfig.WebSearchProvider
+const (
+	WebSearchSerpAPI = "serpapi"
+	WebSearchBrave   = "brave"
+	WebSearchTavily  = "tavily"
+)
+
+// WebSearchResult is one page found by a web search
+type WebSearchResult struct {
+	Title   string `json:"title"`
+	URL     string `json:"url"`
+	Snippet string `json:"snippet,omitempty"`
+}
+
+// WebSearchBackend searches the web; implement it to plug in a new search API
+type WebSearchBackend interface {
+	// Name identifies the backend in logs and function results
+	Name() string
+
+	// Search returns up to limit results for query
+	Search(ctx context.Context, query string, limit int) ([]WebSearchResult, error)
+}
+
+// NewWebSearchBackend returns the named search backend authorized with apiKey
+func NewWebSearchBackend(provider, apiKey string) (WebSearchBackend, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("web search API key not provided")
+	}
+	switch provider {
+	case WebSearchSerpAPI:
+		return &SerpAPIBackend{APIKey: apiKey}, nil
+	case WebSearchBrave:
+		return &BraveSearchBackend{APIKey: apiKey}, nil
+	case WebSearchTavily:
+		return &TavilySearchBackend{APIKey: apiKey}, nil
+	case "":
+		return nil, fmt.Errorf("no web search provider configured")
+	default:
+		return nil, fmt.Errorf("unknown web search provider %q", provider)
+	}
+}
+
+// SetWebSearchBackend replaces the backend used by web_search; nil restores the configured one
+func (c *Client) SetWebSearchBackend(backend WebSearchBackend) {
+	c.overridesMutex.Lock()
+	defer c.overridesMutex.Unlock()
+	c.webSearchBackend = backend
+}
+
+// webSearcher returns the web search backend, falling back to one built from the client config
+func (c *Client) webSearcher() (WebSearchBackend, error) {
+	c.overridesMutex.RLock()
+	backend := c.webSearchBackend
+	c.overridesMutex.RUnlock()
+	if backend != nil {
+		return backend, nil
+	}
+	if c.config == nil {
+		return nil, fmt.Errorf("no web search provider configured")
+	}
+	return NewWebSearchBackend(c.config.WebSearchProvider, c.config.WebSearchAPIKey)
+}
+
+// callWebSearch runs the web_search function: it searches for args["query"] and returns the
+// results, whose snippets are stored with the function call
+func (c *Client) callWebSearch(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	query, ok := args["query"].(string)
+	if !ok || query == "" {
+		return nil, fmt.Errorf("query parameter missing or invalid")
+	}
+	limit := defaultWebSearchResults
+	if value, ok := args["num_results"].(float64); ok && value >= 1 {
+		limit = min(int(value), maxWebSearchResults)
+	}
+
+	backend, err := c.webSearcher()
+	if err != nil {
+		return nil, err
+	}
+	c.logExecutionEvent(ctx, types.LogLevelInfo, types.LogCategoryAPICall,
+		fmt.Sprintf("Searching the web with %s", backend.Name()),
+		map[string]interface{}{
+			"query": query,
+			"limit": limit,
+		})
+
+	results, err := backend.Search(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("web search failed: %w", err)
+	}
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	items := make([]interface{}, len(results))
+	for i, result := range results {
+		items[i] = map[string]interface{}{
+			"title":   result.Title,
+			"url":     result.URL,
+			"snippet": result.Snippet,
+		}
+	}
+	return map[string]interface{}{
+		"query":    query,
+		"provider": backend.Name(),
+		"results":  items,
+	}, nil
+}
+
+// attachCitations adds the pages a web search returned to a response's function call response as
+// numbered citations, so answers can be traced to their sources
+func attachCitations(response *types.APIResponse) {
+	if response == nil || response.FunctionCallResponse["function_name"] != webSearchFunctionName {
+		return
+	}
+	if citations := webSearchCitations(response.FunctionCallResponse["result"]); len(citations) > 0 {
+		response.FunctionCallResponse["citations"] = citations
+	}
+}
+
+// webSearchCitations lists the title and URL of each result of a web_search result, which may be
+// live or decoded from a recorded or pinned response
+func webSearchCitations(result interface{}) []map[string]interface{} {
+	resultMap, ok := result.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	items, _ := resultMap["results"].([]interface{})
+	var citations []map[string]interface{}
+	for _, item := range items {
+		page, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		pageURL, _ := page["url"].(string)
+		if pageURL == "" {
+			continue
+		}
+		citations = append(citations, map[string]interface{}{
+			"index": len(citations) + 1,
+			"title": page["title"],
+			"url":   pageURL,
+		})
+	}
+	return citations
+}
+
+// SerpAPIBackend searches Google through SerpAPI
+type SerpAPIBackend struct {
+	APIKey   string
+	Endpoint string // Overrides https://serpapi.com/search.json
+}
+
+// Name identifies SerpAPI
+func (b *SerpAPIBackend) Name() string { return WebSearchSerpAPI }
+
+// Search returns Google's organic results for query
+func (b *SerpAPIBackend) Search(ctx context.Context, query string, limit int) ([]WebSearchResult, error) {
+	endpoint := b.Endpoint
+	if endpoint == "" {
+		endpoint = "https://serpapi.com/search.json"
+	}
+	params := url.Values{}
+	params.Set("engine", "google")
+	params.Set("q", query)
+	params.Set("num", strconv.Itoa(limit))
+	params.Set("api_key", b.APIKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	var body struct {
+		OrganicResults []struct {
+			Title   string `json:"title"`
+			Link    string `json:"link"`
+			Snippet string `json:"snippet"`
+		} `json:"organic_results"`
+	}
+	if err := doWebSearch(req, &body); err != nil {
+		return nil, err
+	}
+
+	results := make([]WebSearchResult, 0, len(body.OrganicResults))
+	for _, result := range body.OrganicResults {
+		results = append(results, WebSearchResult{Title: result.Title, URL: result.Link, Snippet: result.Snippet})
+	}
+	return results, nil
+}
+
+// BraveSearchBackend searches with the Brave Search API
+type BraveSearchBackend struct {
+	APIKey   string
+	Endpoint string // Overrides https://api.search.brave.com/res/v1/web/search
+}
+
+// Name identifies Brave Search
+func (b *BraveSearchBackend) Name() string { return WebSearchBrave }
+
+// Search returns Brave's web results for query
+func (b *BraveSearchBackend) Search(ctx context.Context, query string, limit int) ([]WebSearchResult, error) {
+	endpoint := b.Endpoint
+	if endpoint == "" {
+		endpoint = "https://api.search.brave.com/res/v1/web/search"
+	}
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("count", strconv.Itoa(limit))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Subscription-Token", b.APIKey)
+	var body struct {
+		Web struct {
+			Results []struct {
+				Title       string `json:"title"`
+				URL         string `json:"url"`
+				Description string `json:"description"`
+			} `json:"results"`
+		} `json:"web"`
+	}
+	if err := doWebSearch(req, &body); err != nil {
+		return nil, err
+	}
+
+	results := make([]WebSearchResult, 0, len(body.Web.Results))
+	for _, result := range body.Web.Results {
+		results = append(results, WebSearchResult{Title: result.Title, URL: result.URL, Snippet: result.Description})
+	}
+	return results, nil
+}
+
+// TavilySearchBackend searches with the Tavily search API
+type TavilySearchBackend struct {
+	APIKey   string
+	Endpoint string // Overrides https://api.tavily.com/search
+}
+
+// Name identifies Tavily
+func (b *TavilySearchBackend) Name() string { return WebSearchTavily }
+
+// Search returns Tavily's results for query
+func (b *TavilySearchBackend) Search(ctx context.Context, query string, limit int) ([]WebSearchResult, error) {
+	endpoint := b.Endpoint
+	if endpoint == "" {
+		endpoint = "https://api.tavily.com/search"
+	}
+	payload, err := json.Marshal(map[string]interface{}{
+		"query":       query,
+		"max_results": limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+b.APIKey)
+	var body struct {
+		Results []struct {
+			Title   string `json:"title"`
+			URL     string `json:"url"`
+			Content string `json:"content"`
+		} `json:"results"`
+	}
+	if err := doWebSearch(req, &body); err != nil {
+		return nil, err
+	}
+
+	results := make([]WebSearchResult, 0, len(body.Results))
+	for _, result := range body.Results {
+		results = append(results, WebSearchResult{Title: result.Title, URL: result.URL, Snippet: result.Content})
+	}
+	return results, nil
+}
+
+// doWebSearch sends a search API request and decodes its JSON reply into out
+func doWebSearch(req *http.Request, out interface{}) error {
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "GoGent/1.0")
+
+	client := &http.Client{Timeout: webSearchTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		// The URL can carry the API key, so only the underlying error is reported
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("search API returned status %d: %s", resp.StatusCode, string(body))
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package gogent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gogent/internal/types"
+)
+
+// fakeSearchBackend returns fixed results and records the queries it was sent
+type fakeSearchBackend struct {
+	results []WebSearchResult
+	queries []string
+}
+
+func (b *fakeSearchBackend) Name() string { return "fake" }
+
+func (b *fakeSearchBackend) Search(ctx context.Context, query string, limit int) ([]WebSearchResult, error) {
+	b.queries = append(b.queries, query)
+	return b.results, nil
+}
+
+func TestWebSearchBackends(t *testing.T) {
+	var got *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		switch r.URL.Path {
+		case "/serpapi":
+			w.Write([]byte(`{"organic_results": [{"title": "Go", "link": "https://go.dev", "snippet": "The Go language"}]}`))
+		case "/brave":
+			w.Write([]byte(`{"web": {"results": [{"title": "Go", "url": "https://go.dev", "description": "The Go language"}]}}`))
+		case "/tavily":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["query"] != "golang" || body["max_results"] != 3.0 {
+				t.Errorf("expected the query and result count in the body, got %v", body)
+			}
+			w.Write([]byte(`{"results": [{"title": "Go", "url": "https://go.dev", "content": "The Go language"}]}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		backend WebSearchBackend
+		check   func(r *http.Request) bool
+	}{
+		{&SerpAPIBackend{APIKey: "serp-key", Endpoint: server.URL + "/serpapi"}, func(r *http.Request) bool {
+			return r.URL.Query().Get("api_key") == "serp-key" && r.URL.Query().Get("num") == "3"
+		}},
+		{&BraveSearchBackend{APIKey: "brave-key", Endpoint: server.URL + "/brave"}, func(r *http.Request) bool {
+			return r.Header.Get("X-Subscription-Token") == "brave-key" && r.URL.Query().Get("count") == "3"
+		}},
+		{&TavilySearchBackend{APIKey: "tavily-key", Endpoint: server.URL + "/tavily"}, func(r *http.Request) bool {
+			return r.Header.Get("Authorization") == "Bearer tavily-key"
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.backend.Name(), func(t *testing.T) {
+			results, err := tt.backend.Search(context.Background(), "golang", 3)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			want := WebSearchResult{Title: "Go", URL: "https://go.dev", Snippet: "The Go language"}
+			if len(results) != 1 || results[0] != want {
+				t.Errorf("expected %+v, got %+v", want, results)
+			}
+			if !tt.check(got) {
+				t.Errorf("expected the key and result count on the request, got %s %v", got.URL, got.Header)
+			}
+		})
+	}
+}
+
+func TestWebSearchBackendErrorHidesKey(t *testing.T) {
+	backend := &SerpAPIBackend{APIKey: "secret-key", Endpoint: "http://127.0.0.1:1/search.json"}
+	_, err := backend.Search(context.Background(), "golang", 3)
+	if err == nil || strings.Contains(err.Error(), "secret-key") {
+		t.Errorf("expected a request error without the API key, got %v", err)
+	}
+}
+
+func TestNewWebSearchBackend(t *testing.T) {
+	if backend, err := NewWebSearchBackend(WebSearchBrave, "key"); err != nil || backend.Name() != WebSearchBrave {
+		t.Errorf("expected the Brave backend, got %v (%v)", backend, err)
+	}
+	if _, err := NewWebSearchBackend(WebSearchTavily, ""); err == nil {
+		t.Error("expected an error without an API key")
+	}
+	if _, err := NewWebSearchBackend("bing", "key"); err == nil {
+		t.Error("expected an error for an unknown provider")
+	}
+}
+
+func TestWebSearchFunctionCitations(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Write([]byte(`{"message": {"role": "assistant", "content": "", "tool_calls": [{"function": {"name": "web_search", "arguments": {"query": "go release", "num_results": 2}}}]}, "done_reason": "stop"}`))
+			return
+		}
+		w.Write([]byte(`{"message": {"role": "assistant", "content": "Go 1.23 is out [1]."}, "done_reason": "stop"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, store := newStoreTestClient(t)
+	client.config.OllamaURL = server.URL
+	backend := &fakeSearchBackend{results: []WebSearchResult{
+		{Title: "Go 1.23 released", URL: "https://go.dev/blog/go1.23", Snippet: "Go 1.23 is released"},
+		{Title: "No link"},
+	}}
+	client.SetWebSearchBackend(backend)
+	config := &types.APIConfiguration{
+		VariationName: "search",
+		ModelName:     "llama3.2",
+		Provider:      types.ProviderOllama,
+		Tools:         []types.Tool{{Name: webSearchFunctionName}},
+	}
+
+	result, err := client.executeSingleVariation(context.Background(), "user-1", "", config, "What is the latest Go release?", "", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(backend.queries) != 1 || backend.queries[0] != "go release" {
+		t.Errorf("expected the model's query to be searched, got %v", backend.queries)
+	}
+
+	citations, _ := result.Response.FunctionCallResponse["citations"].([]map[string]interface{})
+	if len(citations) != 1 || citations[0]["index"] != 1 || citations[0]["url"] != "https://go.dev/blog/go1.23" {
+		t.Errorf("expected a citation for the result with a URL, got %v", result.Response.FunctionCallResponse["citations"])
+	}
+
+	calls := store.FunctionCalls(result.Request.ID)
+	if len(calls) != 1 {
+		t.Fatalf("expected the search to be recorded, got %d calls", len(calls))
+	}
+	items, _ := calls[0].FunctionResponse["results"].([]interface{})
+	if len(items) != 2 || items[0].(map[string]interface{})["snippet"] != "Go 1.23 is released" {
+		t.Errorf("expected the snippets in the recorded call, got %v", calls[0].FunctionResponse)
+	}
+}
+
+func TestWebSearchWithoutProvider(t *testing.T) {
+	client := &Client{config: &types.GeminiClientConfig{}}
+	if _, err := client.callFunction(context.Background(), webSearchFunctionName, map[string]interface{}{"query": "golang"}); err == nil {
+		t.Error("expected an error without a configured search provider")
+	}
+}
@@ -0,0 +1,198 @@
+package gogent
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"gogent/internal/types"
+)
+
+// GetWorkspaceSettings loads a workspace's defaults, returning empty settings when none are stored
+func (c *Client) GetWorkspaceSettings(ctx context.Context, workspaceID string) (*types.WorkspaceSettings, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+
+	settings := &types.WorkspaceSettings{ID: workspaceID}
+
+	var defaultModel, safetySettings, metrics, allowedProviders, nameTemplate, duplicatePolicy, updatedBy sql.NullString
+	var updatedAt sql.NullTime
+
+	err := c.db.QueryRowContext(ctx, `
+		SELECT default_model, default_safety_settings, default_metrics,
+		       retention_days, request_retention_days, response_retention_days, log_retention_days, function_call_retention_days,
+		       allowed_providers, run_name_template, duplicate_policy, duplicate_window_secs,
+		       max_concurrent_executions, max_variations_per_run, daily_token_budget,
+		       updated_by, updated_at
+		FROM workspace_settings
+		WHERE id = ?
+	`, workspaceID).Scan(&defaultModel, &safetySettings, &metrics,
+		&settings.Retention.RunsDays, &settings.Retention.RequestsDays, &settings.Retention.ResponsesDays,
+		&settings.Retention.LogsDays, &settings.Retention.FunctionCallsDays,
+		&allowedProviders, &nameTemplate, &duplicatePolicy, &settings.DuplicateWindowSecs,
+		&settings.Quota.MaxConcurrentExecutions, &settings.Quota.MaxVariationsPerRun, &settings.Quota.DailyTokenBudget,
+		&updatedBy, &updatedAt)
+	if err == sql.ErrNoRows {
+		return settings, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace settings: %w", err)
+	}
+
+	settings.DefaultModel = defaultModel.String
+	settings.RunNameTemplate = nameTemplate.String
+	settings.DuplicatePolicy = duplicatePolicy.String
+	settings.UpdatedBy = updatedBy.String
+	settings.UpdatedAt = updatedAt.Time
+
+	if err := types.FromJSON(safetySettings.String, &settings.DefaultSafetySettings); err != nil {
+		return nil, fmt.Errorf("failed to parse default safety settings: %w", err)
+	}
+	if err := types.FromJSON(metrics.String, &settings.DefaultMetrics); err != nil {
+		return nil, fmt.Errorf("failed to parse default metrics: %w", err)
+	}
+	if err := types.FromJSON(allowedProviders.String, &settings.AllowedProviders); err != nil {
+		return nil, fmt.Errorf("failed to parse allowed providers: %w", err)
+	}
+
+	return settings, nil
+}
+
+// SaveWorkspaceSettings creates or replaces a workspace's defaults
+func (c *Client) SaveWorkspaceSettings(ctx context.Context, settings *types.WorkspaceSettings) error {
+	if c.db == nil {
+		return ErrNoDatabase
+	}
+	if err := ValidateRetentionPolicy(settings.Retention); err != nil {
+		return err
+	}
+	for _, provider := range settings.AllowedProviders {
+		if !knownProvider(provider) {
+			return fmt.Errorf("unknown provider: %s", provider)
+		}
+	}
+	if err := validateDuplicatePolicy(settings.DuplicatePolicy); err != nil {
+		return err
+	}
+	if settings.DuplicateWindowSecs < 0 {
+		return fmt.Errorf("duplicate window must not be negative")
+	}
+	if err := ValidateQuotaLimits(settings.Quota); err != nil {
+		return err
+	}
+
+	safetySettingsJSON, _ := types.ToJSON(settings.DefaultSafetySettings)
+	metricsJSON, _ := types.ToJSON(settings.DefaultMetrics)
+	allowedProvidersJSON, _ := types.ToJSON(settings.AllowedProviders)
+	settings.UpdatedAt = time.Now()
+
+	_, err := c.db.ExecContext(ctx, `
+		INSERT INTO workspace_settings
+			(id, default_model, default_safety_settings, default_metrics,
+			 retention_days, request_retention_days, response_retention_days, log_retention_days, function_call_retention_days,
+			 allowed_providers, run_name_template, duplicate_policy, duplicate_window_secs,
+			 max_concurrent_executions, max_variations_per_run, daily_token_budget, updated_by, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			default_model = VALUES(default_model),
+			default_safety_settings = VALUES(default_safety_settings),
+			default_metrics = VALUES(default_metrics),
+			retention_days = VALUES(retention_days),
+			request_retention_days = VALUES(request_retention_days),
+			response_retention_days = VALUES(response_retention_days),
+			log_retention_days = VALUES(log_retention_days),
+			function_call_retention_days = VALUES(function_call_retention_days),
+			allowed_providers = VALUES(allowed_providers),
+			run_name_template = VALUES(run_name_template),
+			duplicate_policy = VALUES(duplicate_policy),
+			duplicate_window_secs = VALUES(duplicate_window_secs),
+			max_concurrent_executions = VALUES(max_concurrent_executions),
+			max_variations_per_run = VALUES(max_variations_per_run),
+			daily_token_budget = VALUES(daily_token_budget),
+			updated_by = VALUES(updated_by),
+			updated_at = VALUES(updated_at)
+	`, settings.ID, settings.DefaultModel, safetySettingsJSON, metricsJSON,
+		settings.Retention.RunsDays, settings.Retention.RequestsDays, settings.Retention.ResponsesDays,
+		settings.Retention.LogsDays, settings.Retention.FunctionCallsDays,
+		allowedProvidersJSON, nullableString(settings.RunNameTemplate), nullableString(settings.DuplicatePolicy),
+		settings.DuplicateWindowSecs, settings.Quota.MaxConcurrentExecutions, settings.Quota.MaxVariationsPerRun,
+		settings.Quota.DailyTokenBudget, settings.UpdatedBy, settings.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save workspace settings: %w", err)
+	}
+
+	return nil
+}
+
+// ApplyWorkspaceDefaults fills values the request omitted from the workspace settings and
+// rejects configurations whose model provider the workspace does not allow
+func ApplyWorkspaceDefaults(request *types.MultiExecutionRequest, settings *types.WorkspaceSettings) error {
+	if settings == nil {
+		return validateDuplicatePolicy(request.DuplicatePolicy)
+	}
+
+	if request.NameTemplate == "" {
+		request.NameTemplate = settings.RunNameTemplate
+	}
+	if request.DuplicatePolicy == "" {
+		request.DuplicatePolicy = settings.DuplicatePolicy
+	}
+	if err := validateDuplicatePolicy(request.DuplicatePolicy); err != nil {
+		return err
+	}
+
+	for i := range request.Configurations {
+		config := &request.Configurations[i]
+
+		if config.ModelName == "" {
+			config.ModelName = settings.DefaultModel
+		}
+		hasPolicy := config.SafetyPolicy != nil || request.SafetyPolicy != nil
+		if config.SafetySettings == nil && !hasPolicy && len(settings.DefaultSafetySettings) > 0 {
+			config.SafetySettings = make(map[string]interface{}, len(settings.DefaultSafetySettings))
+			for key, value := range settings.DefaultSafetySettings {
+				config.SafetySettings[key] = value
+			}
+		}
+
+		if !providerAllowed(types.ProviderForConfiguration(config), settings.AllowedProviders) {
+			return fmt.Errorf("model %q for configuration %q is not from an allowed provider (allowed: %v)",
+				config.ModelName, config.VariationName, settings.AllowedProviders)
+		}
+	}
+
+	if len(settings.DefaultMetrics) > 0 {
+		if request.ComparisonConfig == nil {
+			request.ComparisonConfig = &types.ComparisonConfig{Enabled: true}
+		}
+		if len(request.ComparisonConfig.Metrics) == 0 {
+			request.ComparisonConfig.Metrics = append([]string(nil), settings.DefaultMetrics...)
+		}
+	}
+
+	return nil
+}
+
+// knownProvider reports whether provider is one of the model providers
+func knownProvider(provider string) bool {
+	switch provider {
+	case types.ProviderGemini, types.ProviderOpenAI, types.ProviderAnthropic, types.ProviderOllama:
+		return true
+	}
+	return false
+}
+
+// providerAllowed reports whether provider is in the allow-list (an empty list allows everything)
+func providerAllowed(provider string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, p := range allowed {
+		if p == provider {
+			return true
+		}
+	}
+	return false
+}
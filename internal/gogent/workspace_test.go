@@ -0,0 +1,111 @@
+package gogent
+
+import (
+	"testing"
+
+	"gogent/internal/types"
+)
+
+func TestApplyWorkspaceDefaults(t *testing.T) {
+	settings := &types.WorkspaceSettings{
+		ID:                    types.DefaultWorkspaceID,
+		DefaultModel:          "gemini-1.5-flash",
+		DefaultSafetySettings: map[string]interface{}{"HARM_CATEGORY_HARASSMENT": "BLOCK_ONLY_HIGH"},
+		DefaultMetrics:        []string{"response_time", "quality"},
+	}
+
+	t.Run("fills_omitted_values", func(t *testing.T) {
+		request := &types.MultiExecutionRequest{
+			Configurations: []types.APIConfiguration{{VariationName: "plain"}},
+		}
+
+		if err := ApplyWorkspaceDefaults(request, settings); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		config := request.Configurations[0]
+		if config.ModelName != "gemini-1.5-flash" {
+			t.Errorf("expected default model, got %q", config.ModelName)
+		}
+		if config.SafetySettings["HARM_CATEGORY_HARASSMENT"] != "BLOCK_ONLY_HIGH" {
+			t.Errorf("expected default safety settings, got %v", config.SafetySettings)
+		}
+		if request.ComparisonConfig == nil || len(request.ComparisonConfig.Metrics) != 2 {
+			t.Errorf("expected default metrics, got %+v", request.ComparisonConfig)
+		}
+
+		// Defaults are copied, not shared
+		config.SafetySettings["HARM_CATEGORY_HARASSMENT"] = "BLOCK_NONE"
+		if settings.DefaultSafetySettings["HARM_CATEGORY_HARASSMENT"] != "BLOCK_ONLY_HIGH" {
+			t.Error("modifying a configuration changed the workspace defaults")
+		}
+	})
+
+	t.Run("run_values_override_defaults", func(t *testing.T) {
+		request := &types.MultiExecutionRequest{
+			Configurations: []types.APIConfiguration{{
+				VariationName:  "explicit",
+				ModelName:      "gemini-1.5-pro",
+				SafetySettings: map[string]interface{}{"HARM_CATEGORY_HARASSMENT": "BLOCK_NONE"},
+			}},
+			ComparisonConfig: &types.ComparisonConfig{Enabled: true, Metrics: []string{"creativity"}},
+		}
+
+		if err := ApplyWorkspaceDefaults(request, settings); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		config := request.Configurations[0]
+		if config.ModelName != "gemini-1.5-pro" {
+			t.Errorf("expected explicit model to be kept, got %q", config.ModelName)
+		}
+		if config.SafetySettings["HARM_CATEGORY_HARASSMENT"] != "BLOCK_NONE" {
+			t.Errorf("expected explicit safety settings to be kept, got %v", config.SafetySettings)
+		}
+		if len(request.ComparisonConfig.Metrics) != 1 || request.ComparisonConfig.Metrics[0] != "creativity" {
+			t.Errorf("expected explicit metrics to be kept, got %v", request.ComparisonConfig.Metrics)
+		}
+	})
+
+	t.Run("rejects_disallowed_provider", func(t *testing.T) {
+		restricted := &types.WorkspaceSettings{AllowedProviders: []string{types.ProviderGemini}}
+		request := &types.MultiExecutionRequest{
+			Configurations: []types.APIConfiguration{
+				{VariationName: "gemini", ModelName: "gemini-1.5-flash"},
+				{VariationName: "openai", ModelName: "gpt-4o"},
+			},
+		}
+
+		if err := ApplyWorkspaceDefaults(request, restricted); err == nil {
+			t.Error("expected an error for a disallowed provider")
+		}
+	})
+
+	t.Run("run_naming_defaults", func(t *testing.T) {
+		naming := &types.WorkspaceSettings{RunNameTemplate: "{suite}-{git_sha}", DuplicatePolicy: types.DuplicatePolicyMerge}
+		request := &types.MultiExecutionRequest{}
+		if err := ApplyWorkspaceDefaults(request, naming); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if request.NameTemplate != "{suite}-{git_sha}" || request.DuplicatePolicy != types.DuplicatePolicyMerge {
+			t.Errorf("expected the workspace naming defaults, got %q and %q", request.NameTemplate, request.DuplicatePolicy)
+		}
+
+		request = &types.MultiExecutionRequest{DuplicatePolicy: "ignore"}
+		if err := ApplyWorkspaceDefaults(request, naming); err == nil {
+			t.Error("expected an error for an unknown duplicate policy")
+		}
+	})
+
+	t.Run("nil_settings", func(t *testing.T) {
+		request := &types.MultiExecutionRequest{
+			Configurations: []types.APIConfiguration{{VariationName: "plain"}},
+		}
+		if err := ApplyWorkspaceDefaults(request, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if request.Configurations[0].ModelName != "" {
+			t.Errorf("expected model to stay empty, got %q", request.Configurations[0].ModelName)
+		}
+	})
+}
@@ -0,0 +1,474 @@
+package gogent
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"gogent/internal/types"
+)
+
+var (
+	// ErrInvalidWorkspace is returned for a workspace name, role, resource type or membership change that cannot be accepted
+	ErrInvalidWorkspace = errors.New("invalid workspace request")
+	// ErrWorkspaceNotFound is returned when a workspace does not exist or the user is not a member
+	ErrWorkspaceNotFound = errors.New("workspace not found")
+	// ErrWorkspaceForbidden is returned when the user's role in a workspace does not allow the change
+	ErrWorkspaceForbidden = errors.New("workspace role does not allow this")
+	// ErrSharedResourceNotFound is returned for a resource the user cannot share, or that is not shared with the workspace
+	ErrSharedResourceNotFound = errors.New("shared resource not found")
+)
+
+// maxWorkspaceNameLength caps the characters of a workspace name
+const maxWorkspaceNameLength = 255
+
+// workspaceRoleRanks orders the workspace roles, most privileged highest
+var workspaceRoleRanks = map[string]int{
+	types.WorkspaceRoleViewer: 1,
+	types.WorkspaceRoleEditor: 2,
+	types.WorkspaceRoleOwner:  3,
+}
+
+// sharedResourceTables is the table holding each kind of resource a workspace can share, with
+// the column naming it
+var sharedResourceTables = map[string]struct{ table, name string }{
+	types.SharedResourceExecutionRun: {"execution_runs", "name"},
+	types.SharedResourceFunction:     {"function_definitions", "display_name"},
+	types.SharedResourcePreset:       {"configuration_presets", "name"},
+	types.SharedResourcePrompt:       {"prompts", "name"},
+}
+
+// CreateWorkspace creates a workspace with the user as its owner
+func (c *Client) CreateWorkspace(ctx context.Context, userID, name string) (*types.Workspace, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxWorkspaceNameLength {
+		return nil, fmt.Errorf("%w: name must be 1-%d characters", ErrInvalidWorkspace, maxWorkspaceNameLength)
+	}
+
+	workspace := &types.Workspace{
+		ID:        uuid.New().String(),
+		Name:      name,
+		Role:      types.WorkspaceRoleOwner,
+		CreatedBy: userID,
+		CreatedAt: time.Now().UTC(),
+	}
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "INSERT INTO workspaces (id, name, created_by, created_at) VALUES (?, ?, ?, ?)",
+		workspace.ID, workspace.Name, userID, workspace.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to create workspace: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO workspace_members (workspace_id, user_id, role, created_at) VALUES (?, ?, ?, ?)",
+		workspace.ID, userID, types.WorkspaceRoleOwner, workspace.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to add workspace owner: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit workspace: %w", err)
+	}
+	return workspace, nil
+}
+
+// ListWorkspaces returns the workspaces the user belongs to, by name, with the user's role in each
+func (c *Client) ListWorkspaces(ctx context.Context, userID string) ([]types.Workspace, error) {
+	if c.db == nil {
+		return nil, ErrNoDatabase
+	}
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT w.id, w.name, m.role, w.created_by, w.created_at
+		FROM workspaces w
+		JOIN workspace_members m ON m.workspace_id = w.id
+		WHERE m.user_id = ?
+		ORDER BY w.name ASC, w.id ASC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	defer rows.Close()
+
+	workspaces := []types.Workspace{}
+	for rows.Next() {
+		var workspace types.Workspace
+		if err := rows.Scan(&workspace.ID, &workspace.Name, &workspace.Role, &workspace.CreatedBy, &workspace.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan workspace: %w", err)
+		}
+		workspaces = append(workspaces, workspace)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate workspaces: %w", err)
+	}
+	return workspaces, nil
+}
+
+// GetWorkspace returns a workspace the user belongs to with its members
+func (c *Client) GetWorkspace(ctx context.Context, userID, workspaceID string) (*types.Workspace, error) {
+	role, err := c.workspaceRole(ctx, userID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	workspace := &types.Workspace{ID: workspaceID, Role: role}
+	err = c.db.QueryRowContext(ctx, "SELECT name, created_by, created_at FROM workspaces WHERE id = ?", workspaceID).
+		Scan(&workspace.Name, &workspace.CreatedBy, &workspace.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace: %w", err)
+	}
+
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT m.user_id, u.username, m.role, m.created_at
+		FROM workspace_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.workspace_id = ?
+		ORDER BY u.username ASC`, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace members: %w", err)
+	}
+	defer rows.Close()
+
+	workspace.Members = []types.WorkspaceMember{}
+	for rows.Next() {
+		var member types.WorkspaceMember
+		if err := rows.Scan(&member.UserID, &member.Username, &member.Role, &member.JoinedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan workspace member: %w", err)
+		}
+		workspace.Members = append(workspace.Members, member)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate workspace members: %w", err)
+	}
+	return workspace, nil
+}
+
+// SetWorkspaceMember adds a user to a workspace by username, or changes their role. Only owners
+// manage members, and a workspace always keeps at least one owner.
+func (c *Client) SetWorkspaceMember(ctx context.Context, userID, workspaceID, username, role string) (*types.WorkspaceMember, error) {
+	if err := c.requireWorkspaceRole(ctx, userID, workspaceID, types.WorkspaceRoleOwner); err != nil {
+		return nil, err
+	}
+	if _, ok := workspaceRoleRanks[role]; !ok {
+		return nil, fmt.Errorf("%w: role must be owner, editor or viewer", ErrInvalidWorkspace)
+	}
+
+	member := &types.WorkspaceMember{Username: username, Role: role}
+	err := c.db.QueryRowContext(ctx, "SELECT id FROM users WHERE username = ?", username).Scan(&member.UserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: unknown user %q", ErrInvalidWorkspace, username)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up workspace member: %w", err)
+	}
+
+	current, err := c.workspaceRole(ctx, member.UserID, workspaceID)
+	switch {
+	case errors.Is(err, ErrWorkspaceNotFound):
+		member.JoinedAt = time.Now().UTC()
+		_, err = c.db.ExecContext(ctx, "INSERT INTO workspace_members (workspace_id, user_id, role, created_at) VALUES (?, ?, ?, ?)",
+			workspaceID, member.UserID, role, member.JoinedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add workspace member: %w", err)
+		}
+		return member, nil
+	case err != nil:
+		return nil, err
+	}
+
+	if current == types.WorkspaceRoleOwner && role != types.WorkspaceRoleOwner {
+		if err := c.keepAnotherOwner(ctx, workspaceID); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := c.db.ExecContext(ctx, "UPDATE workspace_members SET role = ? WHERE workspace_id = ? AND user_id = ?",
+		role, workspaceID, member.UserID); err != nil {
+		return nil, fmt.Errorf("failed to change workspace role: %w", err)
+	}
+	if err := c.db.QueryRowContext(ctx, "SELECT created_at FROM workspace_members WHERE workspace_id = ? AND user_id = ?",
+		workspaceID, member.UserID).Scan(&member.JoinedAt); err != nil {
+		return nil, fmt.Errorf("failed to get workspace member: %w", err)
+	}
+	return member, nil
+}
+
+// RemoveWorkspaceMember removes a user from a workspace by username. Owners remove anyone and
+// every member may leave, but the last owner cannot.
+func (c *Client) RemoveWorkspaceMember(ctx context.Context, userID, workspaceID, username string) error {
+	role, err := c.workspaceRole(ctx, userID, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	var memberID, memberRole string
+	err = c.db.QueryRowContext(ctx, `
+		SELECT m.user_id, m.role
+		FROM workspace_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.workspace_id = ? AND u.username = ?`, workspaceID, username).Scan(&memberID, &memberRole)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %q is not a member", ErrInvalidWorkspace, username)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up workspace member: %w", err)
+	}
+	if memberID != userID && role != types.WorkspaceRoleOwner {
+		return ErrWorkspaceForbidden
+	}
+	if memberRole == types.WorkspaceRoleOwner {
+		if err := c.keepAnotherOwner(ctx, workspaceID); err != nil {
+			return err
+		}
+	}
+
+	if _, err := c.db.ExecContext(ctx, "DELETE FROM workspace_members WHERE workspace_id = ? AND user_id = ?",
+		workspaceID, memberID); err != nil {
+		return fmt.Errorf("failed to remove workspace member: %w", err)
+	}
+	return nil
+}
+
+// keepAnotherOwner refuses a change that would leave a workspace without an owner
+func (c *Client) keepAnotherOwner(ctx context.Context, workspaceID string) error {
+	var owners int
+	if err := c.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM workspace_members WHERE workspace_id = ? AND role = ?",
+		workspaceID, types.WorkspaceRoleOwner).Scan(&owners); err != nil {
+		return fmt.Errorf("failed to count workspace owners: %w", err)
+	}
+	if owners < 2 {
+		return fmt.Errorf("%w: a workspace needs an owner", ErrInvalidWorkspace)
+	}
+	return nil
+}
+
+// ShareResource shares one of the user's execution runs, function definitions, presets or prompts
+// with a workspace in which the user is an editor or owner. Sharing it again is a no-op.
+func (c *Client) ShareResource(ctx context.Context, userID, workspaceID, resourceType, resourceID string) (*types.SharedResource, error) {
+	if err := c.requireWorkspaceRole(ctx, userID, workspaceID, types.WorkspaceRoleEditor); err != nil {
+		return nil, err
+	}
+	resource, ok := sharedResourceTables[resourceType]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown resource type %q", ErrInvalidWorkspace, resourceType)
+	}
+
+	shared := &types.SharedResource{WorkspaceID: workspaceID, ResourceType: resourceType, ResourceID: resourceID}
+	err := c.db.QueryRowContext(ctx,
+		"SELECT "+resource.name+" FROM "+resource.table+" WHERE id = ? AND user_id = ?", resourceID, userID,
+	).Scan(&shared.Name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s %s", ErrSharedResourceNotFound, resourceType, resourceID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up %s: %w", resourceType, err)
+	}
+
+	err = c.db.QueryRowContext(ctx, `
+		SELECT u.username, r.created_at
+		FROM workspace_resources r
+		JOIN users u ON u.id = r.shared_by
+		WHERE r.workspace_id = ? AND r.resource_type = ? AND r.resource_id = ?`,
+		workspaceID, resourceType, resourceID).Scan(&shared.SharedBy, &shared.SharedAt)
+	if err == nil {
+		return shared, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to look up shared resource: %w", err)
+	}
+
+	if err := c.db.QueryRowContext(ctx, "SELECT username FROM users WHERE id = ?", userID).Scan(&shared.SharedBy); err != nil {
+		return nil, fmt.Errorf("failed to look up sharing user: %w", err)
+	}
+	shared.SharedAt = time.Now().UTC()
+	_, err = c.db.ExecContext(ctx, `
+		INSERT INTO workspace_resources (workspace_id, resource_type, resource_id, shared_by, created_at)
+		VALUES (?, ?, ?, ?, ?)`, workspaceID, resourceType, resourceID, userID, shared.SharedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to share resource: %w", err)
+	}
+	return shared, nil
+}
+
+// UnshareResource stops sharing a resource with a workspace. The user who shared it and the
+// workspace's owners may unshare it.
+func (c *Client) UnshareResource(ctx context.Context, userID, workspaceID, resourceType, resourceID string) error {
+	role, err := c.workspaceRole(ctx, userID, workspaceID)
+	if err != nil {
+		return err
+	}
+	ownerID, err := c.sharedResourceOwner(ctx, workspaceID, resourceType, resourceID)
+	if err != nil {
+		return err
+	}
+	if ownerID != userID && role != types.WorkspaceRoleOwner {
+		return ErrWorkspaceForbidden
+	}
+
+	if _, err := c.db.ExecContext(ctx,
+		"DELETE FROM workspace_resources WHERE workspace_id = ? AND resource_type = ? AND resource_id = ?",
+		workspaceID, resourceType, resourceID); err != nil {
+		return fmt.Errorf("failed to unshare resource: %w", err)
+	}
+	return nil
+}
+
+// ListSharedResources returns what is shared with a workspace the user belongs to, newest first.
+// An empty resourceType lists every kind.
+func (c *Client) ListSharedResources(ctx context.Context, userID, workspaceID, resourceType string) ([]types.SharedResource, error) {
+	if _, err := c.workspaceRole(ctx, userID, workspaceID); err != nil {
+		return nil, err
+	}
+	if _, ok := sharedResourceTables[resourceType]; resourceType != "" && !ok {
+		return nil, fmt.Errorf("%w: unknown resource type %q", ErrInvalidWorkspace, resourceType)
+	}
+
+	// Shares outlive the resources they point at; the joins drop deleted ones
+	query := `
+		SELECT r.resource_type, r.resource_id, COALESCE(er.name, fd.display_name, cp.name, p.name), u.username, r.created_at
+		FROM workspace_resources r
+		JOIN users u ON u.id = r.shared_by
+		LEFT JOIN execution_runs er ON r.resource_type = 'execution_run' AND er.id = r.resource_id
+		LEFT JOIN function_definitions fd ON r.resource_type = 'function_definition' AND fd.id = r.resource_id AND fd.is_active = TRUE
+		LEFT JOIN configuration_presets cp ON r.resource_type = 'configuration_preset' AND cp.id = r.resource_id
+		LEFT JOIN prompts p ON r.resource_type = 'prompt' AND p.id = r.resource_id
+		WHERE r.workspace_id = ? AND COALESCE(er.id, fd.id, cp.id, p.id) IS NOT NULL`
+	args := []interface{}{workspaceID}
+	if resourceType != "" {
+		query += " AND r.resource_type = ?"
+		args = append(args, resourceType)
+	}
+	rows, err := c.db.QueryContext(ctx, query+" ORDER BY r.created_at DESC, r.resource_id ASC", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shared resources: %w", err)
+	}
+	defer rows.Close()
+
+	resources := []types.SharedResource{}
+	for rows.Next() {
+		resource := types.SharedResource{WorkspaceID: workspaceID}
+		if err := rows.Scan(&resource.ResourceType, &resource.ResourceID, &resource.Name, &resource.SharedBy, &resource.SharedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan shared resource: %w", err)
+		}
+		resources = append(resources, resource)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate shared resources: %w", err)
+	}
+	return resources, nil
+}
+
+// GetSharedExecutionResult returns a run shared with a workspace the user belongs to, with its results
+func (c *Client) GetSharedExecutionResult(ctx context.Context, userID, workspaceID, executionRunID string) (*types.ExecutionResult, error) {
+	ownerID, err := c.sharedWith(ctx, userID, workspaceID, types.SharedResourceExecutionRun, executionRunID)
+	if err != nil {
+		return nil, err
+	}
+	result, err := c.GetExecutionResult(ctx, ownerID, executionRunID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s %s", ErrSharedResourceNotFound, types.SharedResourceExecutionRun, executionRunID)
+	}
+	return result, err
+}
+
+// GetSharedFunctionDefinition returns a function definition shared with a workspace the user
+// belongs to. Its headers and auth config stay with its owner.
+func (c *Client) GetSharedFunctionDefinition(ctx context.Context, userID, workspaceID, functionID string) (*types.FunctionDefinition, error) {
+	ownerID, err := c.sharedWith(ctx, userID, workspaceID, types.SharedResourceFunction, functionID)
+	if err != nil {
+		return nil, err
+	}
+	function, err := c.GetFunctionDefinition(ctx, ownerID, functionID)
+	if errors.Is(err, ErrFunctionNotFound) {
+		return nil, fmt.Errorf("%w: %s %s", ErrSharedResourceNotFound, types.SharedResourceFunction, functionID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	function.Headers = nil
+	function.AuthConfig = nil
+	return function, nil
+}
+
+// GetSharedConfigurationPreset returns a preset shared with a workspace the user belongs to
+func (c *Client) GetSharedConfigurationPreset(ctx context.Context, userID, workspaceID, presetID string) (*types.ConfigurationPreset, error) {
+	ownerID, err := c.sharedWith(ctx, userID, workspaceID, types.SharedResourcePreset, presetID)
+	if err != nil {
+		return nil, err
+	}
+	preset, err := c.GetConfigurationPreset(ctx, ownerID, presetID)
+	if errors.Is(err, ErrPresetNotFound) {
+		return nil, fmt.Errorf("%w: %s %s", ErrSharedResourceNotFound, types.SharedResourcePreset, presetID)
+	}
+	return preset, err
+}
+
+// GetSharedPrompt returns a prompt shared with a workspace the user belongs to
+func (c *Client) GetSharedPrompt(ctx context.Context, userID, workspaceID, promptID string) (*types.Prompt, error) {
+	ownerID, err := c.sharedWith(ctx, userID, workspaceID, types.SharedResourcePrompt, promptID)
+	if err != nil {
+		return nil, err
+	}
+	prompt, err := c.GetPrompt(ctx, ownerID, promptID)
+	if errors.Is(err, ErrPromptNotFound) {
+		return nil, fmt.Errorf("%w: %s %s", ErrSharedResourceNotFound, types.SharedResourcePrompt, promptID)
+	}
+	return prompt, err
+}
+
+// sharedWith returns the owner of a resource shared with a workspace the user belongs to
+func (c *Client) sharedWith(ctx context.Context, userID, workspaceID, resourceType, resourceID string) (string, error) {
+	if _, err := c.workspaceRole(ctx, userID, workspaceID); err != nil {
+		return "", err
+	}
+	return c.sharedResourceOwner(ctx, workspaceID, resourceType, resourceID)
+}
+
+// sharedResourceOwner returns who shared a resource with a workspace
+func (c *Client) sharedResourceOwner(ctx context.Context, workspaceID, resourceType, resourceID string) (string, error) {
+	var ownerID string
+	err := c.db.QueryRowContext(ctx,
+		"SELECT shared_by FROM workspace_resources WHERE workspace_id = ? AND resource_type = ? AND resource_id = ?",
+		workspaceID, resourceType, resourceID).Scan(&ownerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("%w: %s %s", ErrSharedResourceNotFound, resourceType, resourceID)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up shared resource: %w", err)
+	}
+	return ownerID, nil
+}
+
+// workspaceRole returns the user's role in a workspace
+func (c *Client) workspaceRole(ctx context.Context, userID, workspaceID string) (string, error) {
+	if c.db == nil {
+		return "", ErrNoDatabase
+	}
+	var role string
+	err := c.db.QueryRowContext(ctx, "SELECT role FROM workspace_members WHERE workspace_id = ? AND user_id = ?",
+		workspaceID, userID).Scan(&role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("%w: %s", ErrWorkspaceNotFound, workspaceID)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get workspace role: %w", err)
+	}
+	return role, nil
+}
+
+// requireWorkspaceRole checks that the user holds at least the given role in a workspace
+func (c *Client) requireWorkspaceRole(ctx context.Context, userID, workspaceID, minimum string) error {
+	role, err := c.workspaceRole(ctx, userID, workspaceID)
+	if err != nil {
+		return err
+	}
+	if workspaceRoleRanks[role] < workspaceRoleRanks[minimum] {
+		return ErrWorkspaceForbidden
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package gogent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gogent/internal/testdb"
+	"gogent/internal/types"
+)
+
+// newWorkspaceTestClient returns a client whose database has three users, the workspace tables
+// and a resource of each kind owned by ada
+func newWorkspaceTestClient(t *testing.T) *Client {
+	database := testdb.Open(t)
+
+	_, err := database.Exec(`
+		INSERT INTO users (id, username) VALUES ('user-1', 'ada'), ('user-2', 'grace'), ('user-3', 'linus');
+		INSERT INTO execution_runs (id, user_id, name) VALUES ('run-1', 'user-1', 'Weather check'), ('run-2', 'user-2', 'Private run');
+		INSERT INTO function_definitions (id, user_id, display_name) VALUES ('function-1', 'user-1', 'Weather');
+		INSERT INTO configuration_presets (id, user_id, name) VALUES ('preset-1', 'user-1', 'Cold flash');
+		INSERT INTO prompts (id, user_id, name, content) VALUES ('prompt-1', 'user-1', 'Forecast', 'Summarize the forecast'), ('prompt-2', 'user-2', 'Haiku', 'Answer in a haiku')`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+	return &Client{db: database}
+}
+
+func TestWorkspaceMembers(t *testing.T) {
+	client := newWorkspaceTestClient(t)
+	ctx := context.Background()
+
+	if _, err := client.CreateWorkspace(ctx, "user-1", "  "); !errors.Is(err, ErrInvalidWorkspace) {
+		t.Errorf("expected a blank name to be rejected, got %v", err)
+	}
+	workspace, err := client.CreateWorkspace(ctx, "user-1", "Evals team")
+	if err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+
+	if _, err := client.SetWorkspaceMember(ctx, "user-1", workspace.ID, "grace", types.WorkspaceRoleEditor); err != nil {
+		t.Fatalf("failed to add editor: %v", err)
+	}
+	if _, err := client.SetWorkspaceMember(ctx, "user-2", workspace.ID, "linus", types.WorkspaceRoleViewer); !errors.Is(err, ErrWorkspaceForbidden) {
+		t.Errorf("expected an editor not to manage members, got %v", err)
+	}
+	if _, err := client.SetWorkspaceMember(ctx, "user-1", workspace.ID, "linus", "admin"); !errors.Is(err, ErrInvalidWorkspace) {
+		t.Errorf("expected an unknown role to be rejected, got %v", err)
+	}
+	if _, err := client.SetWorkspaceMember(ctx, "user-1", workspace.ID, "nobody", types.WorkspaceRoleViewer); !errors.Is(err, ErrInvalidWorkspace) {
+		t.Errorf("expected an unknown user to be rejected, got %v", err)
+	}
+	if _, err := client.SetWorkspaceMember(ctx, "user-1", workspace.ID, "ada", types.WorkspaceRoleViewer); !errors.Is(err, ErrInvalidWorkspace) {
+		t.Errorf("expected the last owner not to step down, got %v", err)
+	}
+	if _, err := client.GetWorkspace(ctx, "user-3", workspace.ID); !errors.Is(err, ErrWorkspaceNotFound) {
+		t.Errorf("expected a non-member not to see the workspace, got %v", err)
+	}
+
+	got, err := client.GetWorkspace(ctx, "user-2", workspace.ID)
+	if err != nil {
+		t.Fatalf("failed to get workspace: %v", err)
+	}
+	if got.Name != "Evals team" || got.Role != types.WorkspaceRoleEditor || len(got.Members) != 2 || got.Members[0].Username != "ada" {
+		t.Errorf("expected grace to see both members as an editor, got %+v", got)
+	}
+	listed, err := client.ListWorkspaces(ctx, "user-2")
+	if err != nil || len(listed) != 1 || listed[0].ID != workspace.ID {
+		t.Errorf("expected grace's one workspace, got %+v, %v", listed, err)
+	}
+
+	if err := client.RemoveWorkspaceMember(ctx, "user-2", workspace.ID, "ada"); !errors.Is(err, ErrWorkspaceForbidden) {
+		t.Errorf("expected an editor not to remove the owner, got %v", err)
+	}
+	if err := client.RemoveWorkspaceMember(ctx, "user-1", workspace.ID, "ada"); !errors.Is(err, ErrInvalidWorkspace) {
+		t.Errorf("expected the last owner not to leave, got %v", err)
+	}
+	if err := client.RemoveWorkspaceMember(ctx, "user-2", workspace.ID, "grace"); err != nil {
+		t.Fatalf("failed to leave workspace: %v", err)
+	}
+	if listed, err := client.ListWorkspaces(ctx, "user-2"); err != nil || len(listed) != 0 {
+		t.Errorf("expected grace to have left, got %+v, %v", listed, err)
+	}
+}
+
+func TestWorkspaceSharing(t *testing.T) {
+	client := newWorkspaceTestClient(t)
+	ctx := context.Background()
+	workspace, err := client.CreateWorkspace(ctx, "user-1", "Evals team")
+	if err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+	if _, err := client.SetWorkspaceMember(ctx, "user-1", workspace.ID, "grace", types.WorkspaceRoleViewer); err != nil {
+		t.Fatalf("failed to add viewer: %v", err)
+	}
+
+	for _, resource := range []struct{ kind, id string }{
+		{types.SharedResourceExecutionRun, "run-1"},
+		{types.SharedResourceFunction, "function-1"},
+		{types.SharedResourcePreset, "preset-1"},
+		{types.SharedResourcePrompt, "prompt-1"},
+	} {
+		shared, err := client.ShareResource(ctx, "user-1", workspace.ID, resource.kind, resource.id)
+		if err != nil {
+			t.Fatalf("failed to share %s: %v", resource.id, err)
+		}
+		if shared.SharedBy != "ada" || shared.Name == "" {
+			t.Errorf("expected %s shared by ada with its name, got %+v", resource.id, shared)
+		}
+	}
+	if _, err := client.ShareResource(ctx, "user-1", workspace.ID, types.SharedResourceExecutionRun, "run-1"); err != nil {
+		t.Errorf("expected sharing again to be a no-op, got %v", err)
+	}
+	if _, err := client.ShareResource(ctx, "user-1", workspace.ID, types.SharedResourceExecutionRun, "run-2"); !errors.Is(err, ErrSharedResourceNotFound) {
+		t.Errorf("expected another user's run not to be shareable, got %v", err)
+	}
+	if _, err := client.ShareResource(ctx, "user-1", workspace.ID, "dataset", "run-1"); !errors.Is(err, ErrInvalidWorkspace) {
+		t.Errorf("expected an unknown resource type to be rejected, got %v", err)
+	}
+	if _, err := client.ShareResource(ctx, "user-2", workspace.ID, types.SharedResourceExecutionRun, "run-2"); !errors.Is(err, ErrWorkspaceForbidden) {
+		t.Errorf("expected a viewer not to share, got %v", err)
+	}
+
+	resources, err := client.ListSharedResources(ctx, "user-2", workspace.ID, "")
+	if err != nil || len(resources) != 4 {
+		t.Fatalf("expected the viewer to see four shared resources, got %+v, %v", resources, err)
+	}
+	if _, err := client.ListSharedResources(ctx, "user-3", workspace.ID, ""); !errors.Is(err, ErrWorkspaceNotFound) {
+		t.Errorf("expected a non-member not to see shared resources, got %v", err)
+	}
+	if owner, err := client.sharedWith(ctx, "user-2", workspace.ID, types.SharedResourceExecutionRun, "run-1"); err != nil || owner != "user-1" {
+		t.Errorf("expected the viewer to reach ada's run, got %q, %v", owner, err)
+	}
+	if _, err := client.sharedWith(ctx, "user-2", workspace.ID, types.SharedResourceExecutionRun, "run-2"); !errors.Is(err, ErrSharedResourceNotFound) {
+		t.Errorf("expected an unshared run to stay private, got %v", err)
+	}
+
+	// Deleted resources drop out of the listing
+	if _, err := client.db.Exec("DELETE FROM configuration_presets WHERE id = 'preset-1'"); err != nil {
+		t.Fatalf("failed to delete preset: %v", err)
+	}
+	resources, err = client.ListSharedResources(ctx, "user-2", workspace.ID, types.SharedResourceExecutionRun)
+	if err != nil || len(resources) != 1 || resources[0].Name != "Weather check" {
+		t.Errorf("expected only the shared run, got %+v, %v", resources, err)
+	}
+	if resources, err := client.ListSharedResources(ctx, "user-2", workspace.ID, ""); err != nil || len(resources) != 3 {
+		t.Errorf("expected the deleted preset to be dropped, got %+v, %v", resources, err)
+	}
+
+	if err := client.UnshareResource(ctx, "user-2", workspace.ID, types.SharedResourceExecutionRun, "run-1"); !errors.Is(err, ErrWorkspaceForbidden) {
+		t.Errorf("expected a viewer not to unshare, got %v", err)
+	}
+	if err := client.UnshareResource(ctx, "user-1", workspace.ID, types.SharedResourceExecutionRun, "run-1"); err != nil {
+		t.Fatalf("failed to unshare run: %v", err)
+	}
+	if _, err := client.sharedWith(ctx, "user-2", workspace.ID, types.SharedResourceExecutionRun, "run-1"); !errors.Is(err, ErrSharedResourceNotFound) {
+		t.Errorf("expected the unshared run to stay private, got %v", err)
+	}
+}
+
+func TestWorkspacePromptSharing(t *testing.T) {
+	client := newWorkspaceTestClient(t)
+	ctx := context.Background()
+	workspace, err := client.CreateWorkspace(ctx, "user-1", "Evals team")
+	if err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+	if _, err := client.SetWorkspaceMember(ctx, "user-1", workspace.ID, "grace", types.WorkspaceRoleViewer); err != nil {
+		t.Fatalf("failed to add viewer: %v", err)
+	}
+
+	if _, err := client.ShareResource(ctx, "user-2", workspace.ID, types.SharedResourcePrompt, "prompt-2"); !errors.Is(err, ErrWorkspaceForbidden) {
+		t.Errorf("expected a viewer not to share their prompt, got %v", err)
+	}
+	if _, err := client.SetWorkspaceMember(ctx, "user-1", workspace.ID, "grace", types.WorkspaceRoleEditor); err != nil {
+		t.Fatalf("failed to make grace an editor: %v", err)
+	}
+	if _, err := client.ShareResource(ctx, "user-2", workspace.ID, types.SharedResourcePrompt, "prompt-1"); !errors.Is(err, ErrSharedResourceNotFound) {
+		t.Errorf("expected ada's prompt not to be shareable by grace, got %v", err)
+	}
+	shared, err := client.ShareResource(ctx, "user-2", workspace.ID, types.SharedResourcePrompt, "prompt-2")
+	if err != nil {
+		t.Fatalf("failed to share prompt: %v", err)
+	}
+	if shared.Name != "Haiku" || shared.SharedBy != "grace" {
+		t.Errorf("expected grace's prompt shared under its name, got %+v", shared)
+	}
+
+	prompt, err := client.GetSharedPrompt(ctx, "user-1", workspace.ID, "prompt-2")
+	if err != nil {
+		t.Fatalf("failed to get shared prompt: %v", err)
+	}
+	if prompt.Content != "Answer in a haiku" || prompt.UserID != "user-2" {
+		t.Errorf("expected grace's prompt, got %+v", prompt)
+	}
+	if _, err := client.GetSharedPrompt(ctx, "user-3", workspace.ID, "prompt-2"); !errors.Is(err, ErrWorkspaceNotFound) {
+		t.Errorf("expected a non-member not to read the prompt, got %v", err)
+	}
+	if _, err := client.GetSharedPrompt(ctx, "user-2", workspace.ID, "prompt-1"); !errors.Is(err, ErrSharedResourceNotFound) {
+		t.Errorf("expected an unshared prompt to stay private, got %v", err)
+	}
+
+	if err := client.DeletePrompt(ctx, "user-2", "prompt-2"); err != nil {
+		t.Fatalf("failed to delete prompt: %v", err)
+	}
+	if _, err := client.GetSharedPrompt(ctx, "user-1", workspace.ID, "prompt-2"); !errors.Is(err, ErrSharedResourceNotFound) {
+		t.Errorf("expected a deleted prompt to be gone, got %v", err)
+	}
+	if resources, err := client.ListSharedResources(ctx, "user-1", workspace.ID, types.SharedResourcePrompt); err != nil || len(resources) != 0 {
+		t.Errorf("expected the deleted prompt to be dropped, got %+v, %v", resources, err)
+	}
+}
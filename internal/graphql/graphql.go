@@ -0,0 +1,441 @@
+// Package graphql is a small GraphQL executor for schemas declared in Go. It parses operations,
+// fragments, aliases, variables and the @include and @skip directives, resolves each selected field
+// with its resolver and shapes the result as the selection asks. It does not validate documents
+// against a type system or answer introspection; fields a schema does not declare are errors.
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// maxDepth caps how deeply selections may nest, counting fragments as they are expanded
+const maxDepth = 15
+
+// maxParseDepth caps how deeply selection sets and list and object values may nest in a document,
+// so that parsing one cannot exhaust the stack. It is above maxDepth so that documents the executor
+// would reject for their depth still parse and get its error.
+const maxParseDepth = 64
+
+// ErrInvalidArgument is wrapped by argument errors, which resolvers return for bad input
+var ErrInvalidArgument = errors.New("invalid argument")
+
+// Schema is the root objects of a GraphQL API
+type Schema struct {
+	Query    *Object
+	Mutation *Object // Optional
+}
+
+// Object is a GraphQL object type and its fields
+type Object struct {
+	Name   string
+	Fields map[string]*Field
+}
+
+// Field resolves one field of an object. Fields whose Type is set return that object, a pointer
+// to it or a slice of them, and must be queried with a selection set; other fields are leaves
+// whose value is written as JSON.
+type Field struct {
+	Type    *Object
+	Resolve Resolver
+}
+
+// Resolver returns a field's value for its parent's value and the field's arguments
+type Resolver func(ctx context.Context, source interface{}, args Args) (interface{}, error)
+
+// Request is a GraphQL request as sent over HTTP
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Response is the result of executing a request. Data is nil when the request could not run.
+type Response struct {
+	Data   map[string]interface{} `json:"data,omitempty"`
+	Errors []*Error               `json:"errors,omitempty"`
+}
+
+// Error is a request error or a field error with the path of the field that failed
+type Error struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+func (e *Error) Error() string { return e.Message }
+
+// Args are a field's arguments with variables substituted. Numbers are int64 or float64, objects
+// are map[string]interface{} and lists are []interface{}.
+type Args map[string]interface{}
+
+// String returns a string argument, or def when it is absent or null
+func (a Args) String(name, def string) (string, error) {
+	v, ok := a[name]
+	if !ok || v == nil {
+		return def, nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("%w: %s must be a string", ErrInvalidArgument, name)
+	}
+	return s, nil
+}
+
+// Int returns an integer argument, or def when it is absent or null
+func (a Args) Int(name string, def int) (int, error) {
+	v, ok := a[name]
+	if !ok || v == nil {
+		return def, nil
+	}
+	switch n := v.(type) {
+	case int64:
+		return int(n), nil
+	case float64:
+		if n == math.Trunc(n) && math.Abs(n) <= math.MaxInt32 {
+			return int(n), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s must be an integer", ErrInvalidArgument, name)
+}
+
+// Bool returns a boolean argument, or def when it is absent or null
+func (a Args) Bool(name string, def bool) (bool, error) {
+	v, ok := a[name]
+	if !ok || v == nil {
+		return def, nil
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("%w: %s must be a boolean", ErrInvalidArgument, name)
+	}
+	return b, nil
+}
+
+// Decode converts an argument to target through JSON, so input objects decode into the same types
+// the REST API accepts. It reports whether the argument was given.
+func (a Args) Decode(name string, target interface{}) (bool, error) {
+	v, ok := a[name]
+	if !ok || v == nil {
+		return false, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return true, fmt.Errorf("%w: %s: %v", ErrInvalidArgument, name, err)
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return true, fmt.Errorf("%w: %s: %v", ErrInvalidArgument, name, err)
+	}
+	return true, nil
+}
+
+// Execute runs the request's operation against the schema. Request errors, such as a syntax error
+// or a missing operation, are returned without data; a field that fails is null in the data and
+// its error is listed with the field's path.
+func (s *Schema) Execute(ctx context.Context, request Request) *Response {
+	doc, err := parse(request.Query)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: "syntax error: " + err.Error()}}}
+	}
+	op, err := doc.operation(request.OperationName)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+
+	root := s.Query
+	if op.kind == "mutation" {
+		root = s.Mutation
+	}
+	if root == nil {
+		return &Response{Errors: []*Error{{Message: op.kind + "s are not supported"}}}
+	}
+
+	variables := make(map[string]interface{}, len(op.variables))
+	for _, definition := range op.variables {
+		if v, ok := request.Variables[definition.name]; ok {
+			variables[definition.name] = normalizeVariable(v)
+			continue
+		}
+		variables[definition.name], _ = resolveValue(definition.defaultValue, nil)
+	}
+
+	e := &executor{doc: doc, variables: variables}
+	data, err := e.selectFields(ctx, root, nil, op.selectionSet, nil, 0)
+	if err != nil {
+		return &Response{Errors: append(e.errors, &Error{Message: err.Error()})}
+	}
+	return &Response{Data: data, Errors: e.errors}
+}
+
+// operation returns the named operation, or the only one when name is empty
+func (d *document) operation(name string) (*operation, error) {
+	if name == "" {
+		if len(d.operations) > 1 {
+			return nil, errors.New("operationName is required when the document has several operations")
+		}
+		return d.operations[0], nil
+	}
+	for _, op := range d.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown operation %q", name)
+}
+
+// executor runs one operation and collects its field errors
+type executor struct {
+	doc       *document
+	variables map[string]interface{}
+	errors    []*Error
+}
+
+// collectedField is a field of a selection set after fragments are expanded; fields selected more
+// than once under the same key have their selection sets merged
+type collectedField struct {
+	key    string
+	field  selection
+	nested []selection
+}
+
+// collectFields expands the fragments of a selection set for an object and drops skipped fields,
+// keeping the fields in the order they were first selected
+func (e *executor) collectFields(object *Object, set []selection, fields []*collectedField, visited map[string]bool) ([]*collectedField, error) {
+	for _, sel := range set {
+		include, err := e.included(sel.directives)
+		if err != nil {
+			return nil, err
+		}
+		if !include {
+			continue
+		}
+
+		switch {
+		case sel.fragmentName != "":
+			if visited[sel.fragmentName] {
+				continue
+			}
+			frag, ok := e.doc.fragments[sel.fragmentName]
+			if !ok {
+				return nil, fmt.Errorf("unknown fragment %q", sel.fragmentName)
+			}
+			if frag.typeCondition != object.Name {
+				continue
+			}
+			visited[sel.fragmentName] = true
+			fields, err = e.collectFields(object, frag.selectionSet, fields, visited)
+			delete(visited, sel.fragmentName)
+			if err != nil {
+				return nil, err
+			}
+		case sel.inline:
+			if sel.typeCondition != "" && sel.typeCondition != object.Name {
+				continue
+			}
+			if fields, err = e.collectFields(object, sel.selectionSet, fields, visited); err != nil {
+				return nil, err
+			}
+		default:
+			key := sel.responseKey()
+			var existing *collectedField
+			for _, f := range fields {
+				if f.key == key {
+					existing = f
+					break
+				}
+			}
+			if existing == nil {
+				fields = append(fields, &collectedField{key: key, field: sel, nested: sel.selectionSet})
+				continue
+			}
+			if existing.field.name != sel.name {
+				return nil, fmt.Errorf("%q selects both %s and %s", key, existing.field.name, sel.name)
+			}
+			existing.nested = append(existing.nested, sel.selectionSet...)
+		}
+	}
+	return fields, nil
+}
+
+// included applies the @skip and @include directives
+func (e *executor) included(directives []directive) (bool, error) {
+	for _, d := range directives {
+		if d.name != "skip" && d.name != "include" {
+			return false, fmt.Errorf("unknown directive @%s", d.name)
+		}
+		args, err := e.arguments(d.arguments)
+		if err != nil {
+			return false, err
+		}
+		condition, ok := args["if"].(bool)
+		if !ok {
+			return false, fmt.Errorf("@%s requires a boolean if argument", d.name)
+		}
+		if condition == (d.name == "skip") {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// arguments resolves a field's argument values
+func (e *executor) arguments(arguments []argument) (Args, error) {
+	args := make(Args, len(arguments))
+	for _, arg := range arguments {
+		v, err := resolveValue(arg.value, e.variables)
+		if err != nil {
+			return nil, fmt.Errorf("argument %s: %w", arg.name, err)
+		}
+		args[arg.name] = v
+	}
+	return args, nil
+}
+
+// selectFields resolves each field of a selection set on source. Errors in the selection itself,
+// such as an unknown field, are returned; errors from resolvers are recorded and the field is null.
+func (e *executor) selectFields(ctx context.Context, object *Object, source interface{}, set []selection, path []interface{}, depth int) (map[string]interface{}, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("selections nest more than %d levels deep", maxDepth)
+	}
+	fields, err := e.collectFields(object, set, nil, make(map[string]bool))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		if f.field.name == "__typename" {
+			result[f.key] = object.Name
+			continue
+		}
+		field, ok := object.Fields[f.field.name]
+		if !ok {
+			return nil, fmt.Errorf("%s has no field %q", object.Name, f.field.name)
+		}
+		if field.Type == nil && len(f.nested) > 0 {
+			return nil, fmt.Errorf("%s.%s cannot have a selection set", object.Name, f.field.name)
+		}
+		if field.Type != nil && len(f.nested) == 0 {
+			return nil, fmt.Errorf("%s.%s needs a selection set", object.Name, f.field.name)
+		}
+		args, err := e.arguments(f.field.arguments)
+		if err != nil {
+			return nil, err
+		}
+
+		fieldPath := append(append([]interface{}{}, path...), f.key)
+		v, err := field.Resolve(ctx, source, args)
+		if err != nil {
+			e.errors = append(e.errors, &Error{Message: err.Error(), Path: fieldPath})
+			result[f.key] = nil
+			continue
+		}
+		if field.Type == nil {
+			result[f.key] = v
+			continue
+		}
+		if result[f.key], err = e.complete(ctx, field.Type, v, f.nested, fieldPath, depth+1); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// complete selects fields from an object value, or from each object of a list value. Nil pointers
+// are null; nil slices are empty lists.
+func (e *executor) complete(ctx context.Context, object *Object, v interface{}, set []selection, path []interface{}, depth int) (interface{}, error) {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || (rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Map) && rv.IsNil() {
+		return nil, nil
+	}
+	if rv.Kind() != reflect.Slice {
+		return e.selectFields(ctx, object, v, set, path, depth)
+	}
+	items := make([]interface{}, rv.Len())
+	for i := range items {
+		item := rv.Index(i)
+		if item.Kind() != reflect.Pointer && item.CanAddr() {
+			item = item.Addr()
+		}
+		completed, err := e.complete(ctx, object, item.Interface(), set, append(append([]interface{}{}, path...), i), depth)
+		if err != nil {
+			return nil, err
+		}
+		items[i] = completed
+	}
+	return items, nil
+}
+
+// resolveValue converts a document value to Go, substituting variables
+func resolveValue(v value, variables map[string]interface{}) (interface{}, error) {
+	switch v.kind {
+	case valueNull:
+		return nil, nil
+	case valueInt:
+		n, err := strconv.ParseInt(v.raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %s", v.raw)
+		}
+		return n, nil
+	case valueFloat:
+		n, err := strconv.ParseFloat(v.raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s", v.raw)
+		}
+		return n, nil
+	case valueString, valueEnum:
+		return v.raw, nil
+	case valueBoolean:
+		return v.raw == "true", nil
+	case valueVariable:
+		resolved, ok := variables[v.variable]
+		if !ok {
+			return nil, fmt.Errorf("variable $%s is not defined", v.variable)
+		}
+		return resolved, nil
+	case valueList:
+		list := make([]interface{}, len(v.list))
+		for i, item := range v.list {
+			resolved, err := resolveValue(item, variables)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = resolved
+		}
+		return list, nil
+	case valueObject:
+		object := make(map[string]interface{}, len(v.object))
+		for _, field := range v.object {
+			resolved, err := resolveValue(field.value, variables)
+			if err != nil {
+				return nil, err
+			}
+			object[field.name] = resolved
+		}
+		return object, nil
+	}
+	return nil, fmt.Errorf("unknown value")
+}
+
+// normalizeVariable converts JSON numbers holding whole values to int64, matching integer literals
+func normalizeVariable(v interface{}) interface{} {
+	switch t := v.(type) {
+	case float64:
+		if t == math.Trunc(t) && math.Abs(t) < 1<<53 {
+			return int64(t)
+		}
+	case []interface{}:
+		for i := range t {
+			t[i] = normalizeVariable(t[i])
+		}
+	case map[string]interface{}:
+		for k := range t {
+			t[k] = normalizeVariable(t[k])
+		}
+	}
+	return v
+}
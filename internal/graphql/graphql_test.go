@@ -0,0 +1,221 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testAuthor struct {
+	Name  string
+	Books []testBook
+}
+
+type testBook struct {
+	Title string
+	Pages int
+}
+
+// testSchema serves authors and their books, and a mutation that renames an author
+func testSchema() *Schema {
+	book := &Object{Name: "Book", Fields: map[string]*Field{
+		"title": {Resolve: func(ctx context.Context, source interface{}, args Args) (interface{}, error) {
+			return source.(*testBook).Title, nil
+		}},
+		"pages": {Resolve: func(ctx context.Context, source interface{}, args Args) (interface{}, error) {
+			return source.(*testBook).Pages, nil
+		}},
+	}}
+	author := &Object{Name: "Author", Fields: map[string]*Field{
+		"name": {Resolve: func(ctx context.Context, source interface{}, args Args) (interface{}, error) {
+			return source.(*testAuthor).Name, nil
+		}},
+		"books": {Type: book, Resolve: func(ctx context.Context, source interface{}, args Args) (interface{}, error) {
+			limit, err := args.Int("limit", 10)
+			if err != nil {
+				return nil, err
+			}
+			books := source.(*testAuthor).Books
+			return books[:max(0, min(limit, len(books)))], nil
+		}},
+	}}
+	authors := []*testAuthor{
+		{Name: "Ada", Books: []testBook{{"Notes", 60}, {"Letters", 120}}},
+		{Name: "Grace"},
+	}
+	find := func(ctx context.Context, source interface{}, args Args) (interface{}, error) {
+		name, err := args.String("name", "")
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range authors {
+			if a.Name == name {
+				return a, nil
+			}
+		}
+		return (*testAuthor)(nil), nil
+	}
+	return &Schema{
+		Query: &Object{Name: "Query", Fields: map[string]*Field{
+			"authors": {Type: author, Resolve: func(ctx context.Context, source interface{}, args Args) (interface{}, error) {
+				return authors, nil
+			}},
+			"author": {Type: author, Resolve: find},
+			"fail": {Resolve: func(ctx context.Context, source interface{}, args Args) (interface{}, error) {
+				return nil, errors.New("boom")
+			}},
+		}},
+		Mutation: &Object{Name: "Mutation", Fields: map[string]*Field{
+			"rename": {Type: author, Resolve: func(ctx context.Context, source interface{}, args Args) (interface{}, error) {
+				var input struct{ From, To string }
+				if _, err := args.Decode("input", &input); err != nil {
+					return nil, err
+				}
+				for _, a := range authors {
+					if a.Name == input.From {
+						a.Name = input.To
+						return a, nil
+					}
+				}
+				return nil, errors.New("author not found")
+			}},
+		}},
+	}
+}
+
+// execute runs a request and returns its response as JSON
+func execute(t *testing.T, schema *Schema, request Request) string {
+	t.Helper()
+	data, err := json.Marshal(schema.Execute(context.Background(), request))
+	if err != nil {
+		t.Fatalf("failed to encode response: %v", err)
+	}
+	return string(data)
+}
+
+func TestExecute(t *testing.T) {
+	schema := testSchema()
+	tests := []struct {
+		name     string
+		request  Request
+		expected string
+	}{
+		{
+			name:     "nested selection with aliases and arguments",
+			request:  Request{Query: `{ authors { name first: books(limit: 1) { title } } }`},
+			expected: `{"data":{"authors":[{"first":[{"title":"Notes"}],"name":"Ada"},{"first":[],"name":"Grace"}]}}`,
+		},
+		{
+			name: "variables, defaults and fragments",
+			request: Request{
+				Query: `query Find($name: String!, $limit: Int = 5) {
+					author(name: $name) { ...AuthorFields books(limit: $limit) { pages } }
+				}
+				fragment AuthorFields on Author { __typename name books(limit: $limit) { title } }`,
+				Variables: map[string]interface{}{"name": "Ada", "limit": float64(1)},
+			},
+			expected: `{"data":{"author":{"__typename":"Author","books":[{"pages":60,"title":"Notes"}],"name":"Ada"}}}`,
+		},
+		{
+			name:     "skip and include",
+			request:  Request{Query: `query ($all: Boolean!) { author(name: "Ada") { name @skip(if: true) books @include(if: $all) { title } } }`, Variables: map[string]interface{}{"all": false}},
+			expected: `{"data":{"author":{}}}`,
+		},
+		{
+			name:     "missing object is null",
+			request:  Request{Query: `{ author(name: "Nobody") { name } }`},
+			expected: `{"data":{"author":null}}`,
+		},
+		{
+			name:     "resolver errors null the field and keep the rest",
+			request:  Request{Query: `{ fail author(name: "Grace") { name } }`},
+			expected: `{"data":{"author":{"name":"Grace"},"fail":null},"errors":[{"message":"boom","path":["fail"]}]}`,
+		},
+		{
+			name:     "mutation with an input object",
+			request:  Request{Query: `mutation { rename(input: {from: "Grace", to: "Hopper"}) { name } }`},
+			expected: `{"data":{"rename":{"name":"Hopper"}}}`,
+		},
+		{
+			name:     "named operation",
+			request:  Request{Query: `query A { authors { name } } query B { author(name: "Ada") { name } }`, OperationName: "B"},
+			expected: `{"data":{"author":{"name":"Ada"}}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := execute(t, schema, tt.request); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestExecuteRequestErrors(t *testing.T) {
+	schema := testSchema()
+	requests := map[string]string{
+		`{ authors { name }`:                                  "syntax error",
+		`{ authors { age } }`:                                 `no field "age"`,
+		`{ authors }`:                                         "needs a selection set",
+		`{ authors { name { first } } }`:                      "cannot have a selection set",
+		`{ author(name: $who) { name } }`:                     "$who is not defined",
+		`{ authors { ...Missing } }`:                          `unknown fragment "Missing"`,
+		`query A { authors { name } } query B { fail }`:       "operationName is required",
+		`subscription { authors { name } }`:                   "not supported",
+		`{ a: fail a: authors { name } }`:                     "selects both",
+		`{ authors { name @defer } }`:                         "unknown directive",
+		`{ author(name: "Ada") { name } } fragment F on A {}`: "cannot be empty",
+	}
+	for query, expected := range requests {
+		response := schema.Execute(context.Background(), Request{Query: query})
+		if response.Data != nil || len(response.Errors) == 0 || !strings.Contains(response.Errors[len(response.Errors)-1].Message, expected) {
+			t.Errorf("expected %q to fail with %q, got %+v", query, expected, response.Errors)
+		}
+	}
+
+	// Deeply nested documents are rejected while parsing rather than exhausting the stack
+	for _, query := range []string{
+		strings.Repeat("{ authors ", 100000) + strings.Repeat("}", 100000),
+		`{ author(name: ` + strings.Repeat("[", 100000) + strings.Repeat("]", 100000) + `) { name } }`,
+		`{ author(name: ` + strings.Repeat("{a: ", 100000) + "1" + strings.Repeat("}", 100000) + `) { name } }`,
+	} {
+		response := schema.Execute(context.Background(), Request{Query: query})
+		if len(response.Errors) == 0 || !strings.Contains(response.Errors[0].Message, "nests deeper") {
+			t.Errorf("expected a deeply nested document to be rejected, got %+v", response.Errors)
+		}
+	}
+
+	// Fragments that spread themselves stop expanding rather than recursing forever
+	response := schema.Execute(context.Background(), Request{Query: `{ authors { ...A } } fragment A on Author { name ...A }`})
+	if len(response.Errors) != 0 {
+		t.Errorf("expected a self-referencing fragment to expand once, got %+v", response.Errors)
+	}
+}
+
+func TestArgs(t *testing.T) {
+	args := Args{"n": int64(3), "f": float64(4), "x": 1.5, "s": "text", "b": true}
+	if n, err := args.Int("n", 0); err != nil || n != 3 {
+		t.Errorf("expected 3, got %d, %v", n, err)
+	}
+	if n, err := args.Int("f", 0); err != nil || n != 4 {
+		t.Errorf("expected a whole float to be an integer, got %d, %v", n, err)
+	}
+	if _, err := args.Int("x", 0); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected a fraction to be rejected, got %v", err)
+	}
+	if s, err := args.String("missing", "default"); err != nil || s != "default" {
+		t.Errorf("expected the default, got %q, %v", s, err)
+	}
+	if _, err := args.String("n", ""); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected a number to be rejected as a string, got %v", err)
+	}
+
+	var target map[string]interface{}
+	given, err := Args{"input": map[string]interface{}{"a": []interface{}{int64(1)}}}.Decode("input", &target)
+	if err != nil || !given || !reflect.DeepEqual(target, map[string]interface{}{"a": []interface{}{float64(1)}}) {
+		t.Errorf("expected the input to decode, got %v, %v, %v", target, given, err)
+	}
+}
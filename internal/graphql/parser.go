@@ -0,0 +1,586 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// document is a parsed GraphQL request document
+type document struct {
+	operations []*operation
+	fragments  map[string]*fragment
+}
+
+// operation is a query or mutation with its variable definitions
+type operation struct {
+	kind         string // "query" or "mutation"
+	name         string
+	variables    []variableDefinition
+	selectionSet []selection
+}
+
+// variableDefinition declares an operation variable and its default
+type variableDefinition struct {
+	name         string
+	defaultValue value
+}
+
+// fragment is a named fragment definition
+type fragment struct {
+	name          string
+	typeCondition string
+	selectionSet  []selection
+}
+
+// selection is a field, a fragment spread or an inline fragment. Fields have a name; spreads have a
+// fragment name; inline fragments have neither.
+type selection struct {
+	alias        string
+	name         string
+	arguments    []argument
+	directives   []directive
+	selectionSet []selection
+
+	fragmentName  string
+	typeCondition string
+	inline        bool
+}
+
+// responseKey is the key a field's value is written under
+func (s *selection) responseKey() string {
+	if s.alias != "" {
+		return s.alias
+	}
+	return s.name
+}
+
+type argument struct {
+	name  string
+	value value
+}
+
+type directive struct {
+	name      string
+	arguments []argument
+}
+
+// value is a literal or variable reference in a document
+type value struct {
+	kind     valueKind
+	raw      string // Names, numbers and strings
+	list     []value
+	object   []argument
+	variable string
+}
+
+type valueKind int
+
+const (
+	valueNull valueKind = iota
+	valueInt
+	valueFloat
+	valueString
+	valueBoolean
+	valueEnum
+	valueList
+	valueObject
+	valueVariable
+)
+
+// token kinds
+const (
+	tokenEOF = iota
+	tokenPunctuator
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind  int
+	value string
+	pos   int
+}
+
+// lex splits a document into tokens, dropping whitespace, commas and comments
+func lex(source string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(source); {
+		c := source[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			i++
+		case c == '#':
+			for i < len(source) && source[i] != '\n' && source[i] != '\r' {
+				i++
+			}
+		case strings.HasPrefix(source[i:], "..."):
+			tokens = append(tokens, token{tokenPunctuator, "...", i})
+			i += 3
+		case strings.IndexByte("!$():=@[]{}|&", c) >= 0:
+			tokens = append(tokens, token{tokenPunctuator, string(c), i})
+			i++
+		case c == '_' || isLetter(c):
+			start := i
+			for i < len(source) && (source[i] == '_' || isLetter(source[i]) || isDigit(source[i])) {
+				i++
+			}
+			tokens = append(tokens, token{tokenName, source[start:i], start})
+		case c == '-' || isDigit(c):
+			start, kind := i, tokenInt
+			i++
+			for i < len(source) && isDigit(source[i]) {
+				i++
+			}
+			if i < len(source) && source[i] == '.' {
+				kind = tokenFloat
+				i++
+				for i < len(source) && isDigit(source[i]) {
+					i++
+				}
+			}
+			if i < len(source) && (source[i] == 'e' || source[i] == 'E') {
+				kind = tokenFloat
+				i++
+				if i < len(source) && (source[i] == '+' || source[i] == '-') {
+					i++
+				}
+				for i < len(source) && isDigit(source[i]) {
+					i++
+				}
+			}
+			if source[start:i] == "-" {
+				return nil, fmt.Errorf("unexpected \"-\" at %d", start)
+			}
+			tokens = append(tokens, token{kind, source[start:i], start})
+		case c == '"':
+			text, end, err := lexString(source, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{tokenString, text, i})
+			i = end
+		default:
+			return nil, fmt.Errorf("unexpected character %q at %d", c, i)
+		}
+	}
+	return append(tokens, token{tokenEOF, "", len(source)}), nil
+}
+
+// lexString reads a quoted or block string starting at start and returns its value and the offset after it
+func lexString(source string, start int) (string, int, error) {
+	if strings.HasPrefix(source[start:], `"""`) {
+		end := strings.Index(source[start+3:], `"""`)
+		if end < 0 {
+			return "", 0, fmt.Errorf("unterminated block string at %d", start)
+		}
+		return strings.TrimSpace(source[start+3 : start+3+end]), start + 6 + end, nil
+	}
+	var text strings.Builder
+	for i := start + 1; i < len(source); i++ {
+		switch c := source[i]; c {
+		case '\n', '\r':
+			return "", 0, fmt.Errorf("unterminated string at %d", start)
+		case '"':
+			return text.String(), i + 1, nil
+		case '\\':
+			if i+1 >= len(source) {
+				return "", 0, fmt.Errorf("unterminated string at %d", start)
+			}
+			i++
+			switch escaped := source[i]; escaped {
+			case '"', '\\', '/':
+				text.WriteByte(escaped)
+			case 'b':
+				text.WriteByte('\b')
+			case 'f':
+				text.WriteByte('\f')
+			case 'n':
+				text.WriteByte('\n')
+			case 'r':
+				text.WriteByte('\r')
+			case 't':
+				text.WriteByte('\t')
+			case 'u':
+				if i+4 >= len(source) {
+					return "", 0, fmt.Errorf("invalid escape in string at %d", start)
+				}
+				code, err := strconv.ParseUint(source[i+1:i+5], 16, 32)
+				if err != nil {
+					return "", 0, fmt.Errorf("invalid escape in string at %d", start)
+				}
+				text.WriteRune(rune(code))
+				i += 4
+			default:
+				return "", 0, fmt.Errorf("invalid escape in string at %d", start)
+			}
+		default:
+			text.WriteByte(c)
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string at %d", start)
+}
+
+func isLetter(c byte) bool { return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' }
+func isDigit(c byte) bool  { return c >= '0' && c <= '9' }
+
+// parser reads a document from its tokens
+type parser struct {
+	tokens []token
+	pos    int
+	depth  int // Selection sets and values being parsed
+}
+
+// parse parses an executable document of operations and fragments
+func parse(source string) (*document, error) {
+	tokens, err := lex(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	doc := &document{fragments: make(map[string]*fragment)}
+	for p.peek().kind != tokenEOF {
+		t := p.peek()
+		switch {
+		case t.kind == tokenPunctuator && t.value == "{":
+			set, err := p.parseSelectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, &operation{kind: "query", selectionSet: set})
+		case t.kind == tokenName && (t.value == "query" || t.value == "mutation"):
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, op)
+		case t.kind == tokenName && t.value == "fragment":
+			frag, err := p.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := doc.fragments[frag.name]; ok {
+				return nil, fmt.Errorf("fragment %q is defined more than once", frag.name)
+			}
+			doc.fragments[frag.name] = frag
+		case t.kind == tokenName && t.value == "subscription":
+			return nil, fmt.Errorf("subscriptions are not supported")
+		default:
+			return nil, p.unexpected()
+		}
+	}
+	if len(doc.operations) == 0 {
+		return nil, fmt.Errorf("document has no operations")
+	}
+	return doc, nil
+}
+
+func (p *parser) peek() token { return p.tokens[p.pos] }
+
+// descend enters a nested selection set or value, failing once the document nests deeper than
+// maxParseDepth; callers call ascend when they leave it
+func (p *parser) descend() error {
+	p.depth++
+	if p.depth > maxParseDepth {
+		return fmt.Errorf("document nests deeper than %d levels at %d", maxParseDepth, p.peek().pos)
+	}
+	return nil
+}
+
+func (p *parser) ascend() { p.depth-- }
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) unexpected() error {
+	t := p.peek()
+	if t.kind == tokenEOF {
+		return fmt.Errorf("unexpected end of document")
+	}
+	return fmt.Errorf("unexpected %q at %d", t.value, t.pos)
+}
+
+// skip consumes the punctuator if it is next and reports whether it was
+func (p *parser) skip(punctuator string) bool {
+	if t := p.peek(); t.kind == tokenPunctuator && t.value == punctuator {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(punctuator string) error {
+	if !p.skip(punctuator) {
+		return p.unexpected()
+	}
+	return nil
+}
+
+func (p *parser) name() (string, error) {
+	if p.peek().kind != tokenName {
+		return "", p.unexpected()
+	}
+	return p.next().value, nil
+}
+
+func (p *parser) parseOperation() (*operation, error) {
+	op := &operation{kind: p.next().value}
+	if p.peek().kind == tokenName {
+		op.name = p.next().value
+	}
+	if p.skip("(") {
+		for !p.skip(")") {
+			if err := p.expect("$"); err != nil {
+				return nil, err
+			}
+			name, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			if err := p.skipType(); err != nil {
+				return nil, err
+			}
+			definition := variableDefinition{name: name}
+			if p.skip("=") {
+				if definition.defaultValue, err = p.parseValue(true); err != nil {
+					return nil, err
+				}
+			}
+			op.variables = append(op.variables, definition)
+		}
+	}
+	if _, err := p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	set, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.selectionSet = set
+	return op, nil
+}
+
+// skipType consumes a variable's type; values are coerced by the resolvers that read them
+func (p *parser) skipType() error {
+	if p.skip("[") {
+		if err := p.skipType(); err != nil {
+			return err
+		}
+		if err := p.expect("]"); err != nil {
+			return err
+		}
+	} else if _, err := p.name(); err != nil {
+		return err
+	}
+	p.skip("!")
+	return nil
+}
+
+func (p *parser) parseFragment() (*fragment, error) {
+	p.next()
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if name == "on" {
+		return nil, fmt.Errorf("fragment cannot be named \"on\"")
+	}
+	if on, err := p.name(); err != nil || on != "on" {
+		return nil, fmt.Errorf("fragment %q needs a type condition", name)
+	}
+	typeCondition, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	set, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	return &fragment{name: name, typeCondition: typeCondition, selectionSet: set}, nil
+}
+
+func (p *parser) parseSelectionSet() ([]selection, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	defer p.ascend()
+	if err := p.descend(); err != nil {
+		return nil, err
+	}
+	var set []selection
+	for !p.skip("}") {
+		sel, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, sel)
+	}
+	if len(set) == 0 {
+		return nil, fmt.Errorf("selection set cannot be empty")
+	}
+	return set, nil
+}
+
+func (p *parser) parseSelection() (selection, error) {
+	var sel selection
+	var err error
+	if p.skip("...") {
+		if t := p.peek(); t.kind == tokenName && t.value != "on" {
+			sel.fragmentName = p.next().value
+			sel.directives, err = p.parseDirectives()
+			return sel, err
+		}
+		sel.inline = true
+		if t := p.peek(); t.kind == tokenName && t.value == "on" {
+			p.next()
+			if sel.typeCondition, err = p.name(); err != nil {
+				return sel, err
+			}
+		}
+		if sel.directives, err = p.parseDirectives(); err != nil {
+			return sel, err
+		}
+		sel.selectionSet, err = p.parseSelectionSet()
+		return sel, err
+	}
+
+	if sel.name, err = p.name(); err != nil {
+		return sel, err
+	}
+	if p.skip(":") {
+		sel.alias = sel.name
+		if sel.name, err = p.name(); err != nil {
+			return sel, err
+		}
+	}
+	if sel.arguments, err = p.parseArguments(false); err != nil {
+		return sel, err
+	}
+	if sel.directives, err = p.parseDirectives(); err != nil {
+		return sel, err
+	}
+	if t := p.peek(); t.kind == tokenPunctuator && t.value == "{" {
+		sel.selectionSet, err = p.parseSelectionSet()
+	}
+	return sel, err
+}
+
+func (p *parser) parseArguments(constant bool) ([]argument, error) {
+	if !p.skip("(") {
+		return nil, nil
+	}
+	var arguments []argument
+	for !p.skip(")") {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		v, err := p.parseValue(constant)
+		if err != nil {
+			return nil, err
+		}
+		arguments = append(arguments, argument{name: name, value: v})
+	}
+	return arguments, nil
+}
+
+func (p *parser) parseDirectives() ([]directive, error) {
+	var directives []directive
+	for p.skip("@") {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		arguments, err := p.parseArguments(false)
+		if err != nil {
+			return nil, err
+		}
+		directives = append(directives, directive{name: name, arguments: arguments})
+	}
+	return directives, nil
+}
+
+// parseValue parses a value; constant values, such as variable defaults, cannot reference variables
+func (p *parser) parseValue(constant bool) (value, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenInt:
+		return value{kind: valueInt, raw: t.value}, nil
+	case tokenFloat:
+		return value{kind: valueFloat, raw: t.value}, nil
+	case tokenString:
+		return value{kind: valueString, raw: t.value}, nil
+	case tokenName:
+		switch t.value {
+		case "true", "false":
+			return value{kind: valueBoolean, raw: t.value}, nil
+		case "null":
+			return value{kind: valueNull}, nil
+		}
+		return value{kind: valueEnum, raw: t.value}, nil
+	case tokenPunctuator:
+		switch t.value {
+		case "$":
+			if constant {
+				return value{}, fmt.Errorf("variables are not allowed at %d", t.pos)
+			}
+			name, err := p.name()
+			return value{kind: valueVariable, variable: name}, err
+		case "[":
+			defer p.ascend()
+			if err := p.descend(); err != nil {
+				return value{}, err
+			}
+			v := value{kind: valueList}
+			for !p.skip("]") {
+				item, err := p.parseValue(constant)
+				if err != nil {
+					return value{}, err
+				}
+				v.list = append(v.list, item)
+			}
+			return v, nil
+		case "{":
+			defer p.ascend()
+			if err := p.descend(); err != nil {
+				return value{}, err
+			}
+			v := value{kind: valueObject}
+			for !p.skip("}") {
+				name, err := p.name()
+				if err != nil {
+					return value{}, err
+				}
+				if err := p.expect(":"); err != nil {
+					return value{}, err
+				}
+				field, err := p.parseValue(constant)
+				if err != nil {
+					return value{}, err
+				}
+				v.object = append(v.object, argument{name: name, value: field})
+			}
+			return v, nil
+		}
+	}
+	if t.kind != tokenEOF {
+		p.pos--
+	}
+	return value{}, p.unexpected()
+}
@@ -0,0 +1,43 @@
+package graphql
+
+import (
+	"context"
+	"testing"
+)
+
+// FuzzParse feeds arbitrary documents to the parser, and the ones it accepts to the executor: any
+// input may be rejected, but none may panic or hang
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{
+		`{ authors { name first: books(limit: 1) { title } } }`,
+		`query Find($name: String!, $limit: Int = 5) { author(name: $name) { ...F books(limit: $limit) { pages } } } fragment F on Author { __typename name }`,
+		`query ($all: Boolean!) { author(name: "Ada") { name @skip(if: true) books @include(if: $all) { title } } }`,
+		`mutation { rename(input: {from: "Grace", to: "Hopper", tags: [1, 2.5e3, true, null, ENUM]}) { name } }`,
+		`query A { authors { name } } query B { author(name: """block "quoted" string""") { name } }`,
+		`{ author(name: "escé\n\"") { ... on Author { name } } }`,
+		`{ authors { ...A } } fragment A on Author { name ...A }`,
+		`# comment
+		{ authors, { name } }`,
+		`{ authors { name }`,
+		`{ author(name: [[[{a: [1]}]]]) { name } }`,
+		`subscription { authors { name } }`,
+		"",
+	} {
+		f.Add(seed)
+	}
+
+	schema := testSchema()
+	f.Fuzz(func(t *testing.T, source string) {
+		doc, err := parse(source)
+		if err != nil {
+			if doc != nil {
+				t.Errorf("expected no document with error %v", err)
+			}
+			return
+		}
+		if len(doc.operations) == 0 {
+			t.Errorf("expected an accepted document to have an operation: %q", source)
+		}
+		schema.Execute(context.Background(), Request{Query: source, Variables: map[string]interface{}{"name": "Ada", "limit": 1.0, "all": true}})
+	})
+}
@@ -0,0 +1,167 @@
+package ollama
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultBaseURL is where a local Ollama server listens
+	DefaultBaseURL = "http://localhost:11434"
+	// DefaultTimeout bounds a chat call when the context has no deadline of its own; local models
+	// on modest hardware can take minutes to answer
+	DefaultTimeout = 5 * time.Minute
+)
+
+// APIError is a non-200 response from the Ollama server
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("HTTP error %d: %s", e.StatusCode, e.Body)
+}
+
+// Message is one turn of a chat
+type Message struct {
+	Role      string     `json:"role"`
+	Content   string     `json:"content"`
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+}
+
+// ToolCall is a function the model asked to call
+type ToolCall struct {
+	Function ToolCallFunction `json:"function"`
+}
+
+// ToolCallFunction names the function of a tool call and its arguments
+type ToolCallFunction struct {
+	Name      string                 `json:"name"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+}
+
+// Tool is a function the model may call
+type Tool struct {
+	Type     string             `json:"type"`
+	Function FunctionDefinition `json:"function"`
+}
+
+// FunctionDefinition describes a callable function and its JSON schema parameters
+type FunctionDefinition struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+}
+
+// FunctionTool wraps a function definition as a tool
+func FunctionTool(name, description string, parameters map[string]interface{}) Tool {
+	return Tool{Type: "function", Function: FunctionDefinition{Name: name, Description: description, Parameters: parameters}}
+}
+
+// Options holds the sampling options of a chat request
+type Options struct {
+	Temperature *float32 `json:"temperature,omitempty"`
+	TopP        *float32 `json:"top_p,omitempty"`
+	TopK        *int32   `json:"top_k,omitempty"`
+	NumPredict  *int32   `json:"num_predict,omitempty"`
+	Seed        *int32   `json:"seed,omitempty"`
+}
+
+// IsEmpty reports whether no option is set, so the options can be omitted
+func (o *Options) IsEmpty() bool {
+	return o.Temperature == nil && o.TopP == nil && o.TopK == nil && o.NumPredict == nil && o.Seed == nil
+}
+
+// ChatRequest is the body of a chat call; Format is "json" or a JSON schema
+type ChatRequest struct {
+	Model    string      `json:"model"`
+	Messages []Message   `json:"messages"`
+	Tools    []Tool      `json:"tools,omitempty"`
+	Format   interface{} `json:"format,omitempty"`
+	Options  *Options    `json:"options,omitempty"`
+	Stream   bool        `json:"stream"`
+}
+
+// ChatResponse is the reply to a chat call, with the token counts the server measured
+type ChatResponse struct {
+	Model           string  `json:"model"`
+	Message         Message `json:"message"`
+	Done            bool    `json:"done"`
+	DoneReason      string  `json:"done_reason,omitempty"`
+	PromptEvalCount int     `json:"prompt_eval_count,omitempty"`
+	EvalCount       int     `json:"eval_count,omitempty"`
+	TotalDuration   int64   `json:"total_duration,omitempty"` // Nanoseconds
+}
+
+// Client calls the chat API of an Ollama server
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	timeout    time.Duration
+}
+
+// NewClient creates a client for the Ollama server at baseURL; empty uses DefaultBaseURL
+func NewClient(baseURL string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{},
+		timeout:    DefaultTimeout,
+	}
+}
+
+// SetTransport replaces the transport requests are sent with
+func (c *Client) SetTransport(rt http.RoundTripper) {
+	c.httpClient = &http.Client{Transport: rt}
+}
+
+// Chat sends a chat request and waits for the whole reply; streaming is always off
+func (c *Client) Chat(ctx context.Context, request *ChatRequest) (*ChatResponse, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	payload := *request
+	payload.Stream = false
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/chat", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	var response ChatResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &response, nil
+}
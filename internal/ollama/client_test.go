@@ -0,0 +1,68 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestChat(t *testing.T) {
+	temperature := float32(0.2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["stream"] != false || body["model"] != "llama3.2" {
+			t.Errorf("expected a non-streaming llama3.2 request, got %v", body)
+		}
+		if _, ok := body["tools"]; ok {
+			t.Errorf("expected unset options to be omitted, got %v", body)
+		}
+		if body["options"].(map[string]interface{})["temperature"] != 0.2 {
+			t.Errorf("expected the temperature option, got %v", body["options"])
+		}
+
+		w.Write([]byte(`{
+			"model": "llama3.2",
+			"message": {"role": "assistant", "content": "", "tool_calls": [{"function": {"name": "get_weather", "arguments": {"location": "Paris"}}}]},
+			"done": true, "done_reason": "stop", "prompt_eval_count": 26, "eval_count": 12
+		}`))
+	}))
+	t.Cleanup(server.Close)
+
+	response, err := NewClient(server.URL+"/").Chat(context.Background(), &ChatRequest{
+		Model:    "llama3.2",
+		Messages: []Message{{Role: "user", Content: "Weather in Paris?"}},
+		Options:  &Options{Temperature: &temperature},
+		Stream:   true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.PromptEvalCount != 26 || response.EvalCount != 12 || response.DoneReason != "stop" {
+		t.Errorf("expected the token counts and done reason, got %+v", response)
+	}
+	if calls := response.Message.ToolCalls; len(calls) != 1 || calls[0].Function.Arguments["location"] != "Paris" {
+		t.Errorf("expected the tool call, got %+v", calls)
+	}
+}
+
+func TestChatError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error": "model \"mistral\" not found, try pulling it first"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	_, err := NewClient(server.URL).Chat(context.Background(), &ChatRequest{Model: "mistral"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("expected a 404 API error, got %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+type testBase struct {
+	ID string `json:"id"`
+}
+
+type testNode struct {
+	testBase
+	Name     string                 `json:"name"`
+	Weight   *float64               `json:"weight,omitempty"`
+	Count    int64                  `json:"count,string"`
+	Children []*testNode            `json:"children,omitempty"`
+	Labels   map[string]string      `json:"labels"`
+	Extra    map[string]interface{} `json:"extra,omitempty"`
+	Raw      json.RawMessage        `json:"raw,omitempty"`
+	Data     []byte                 `json:"data"`
+	Created  time.Time              `json:"createdAt"`
+	Secret   string                 `json:"-"`
+	Untagged bool
+	hidden   bool
+}
+
+func TestGeneratorSchemas(t *testing.T) {
+	g := NewGenerator()
+	schema := g.SchemaOf([]testNode{})
+	if schema.Type != "array" || schema.Items.Ref != "#/components/schemas/testNode" {
+		t.Fatalf("expected an array of references to testNode, got %+v", schema)
+	}
+
+	node := g.Schemas()["testNode"]
+	if node == nil || node.Type != "object" {
+		t.Fatalf("expected testNode to be registered as an object, got %+v", g.Schemas())
+	}
+	expected := map[string]Schema{
+		"id":        {Type: "string"},
+		"name":      {Type: "string"},
+		"weight":    {Type: "number", Format: "double", Nullable: true},
+		"count":     {Type: "string"},
+		"children":  {Type: "array", Items: &Schema{Ref: "#/components/schemas/testNode"}},
+		"labels":    {Type: "object", AdditionalProperties: &Schema{Type: "string"}},
+		"extra":     {Type: "object", AdditionalProperties: &Schema{}},
+		"raw":       {},
+		"data":      {Type: "string", Format: "byte"},
+		"createdAt": {Type: "string", Format: "date-time"},
+		"Untagged":  {Type: "boolean"},
+	}
+	if len(node.Properties) != len(expected) {
+		t.Errorf("expected %d properties, got %d: %v", len(expected), len(node.Properties), node.Properties)
+	}
+	for name, want := range expected {
+		if got := node.Properties[name]; got == nil || !reflect.DeepEqual(*got, want) {
+			t.Errorf("expected %s to be %+v, got %+v", name, want, got)
+		}
+	}
+
+	if anonymous := g.SchemaOf(struct {
+		OK bool `json:"ok"`
+	}{}); anonymous.Ref != "" || anonymous.Properties["ok"].Type != "boolean" {
+		t.Errorf("expected an anonymous struct to be described inline, got %+v", anonymous)
+	}
+	if g.SchemaOf(nil) != nil {
+		t.Error("expected no schema for a nil value")
+	}
+}
+
+func TestPathParameters(t *testing.T) {
+	parameters := PathParameters("/api/workspaces/{id}/resources/{type}/{resourceId}")
+	var names []string
+	for _, p := range parameters {
+		if p.In != "path" || !p.Required {
+			t.Errorf("expected %s to be a required path parameter", p.Name)
+		}
+		names = append(names, p.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"id", "type", "resourceId"}) {
+		t.Errorf("expected the path's parameters in order, got %v", names)
+	}
+	if PathParameters("/api/functions") != nil {
+		t.Error("expected no parameters for a path without templates")
+	}
+}
+
+type testLeaf struct {
+	Value string `json:"value"`
+}
+
+type testTree struct {
+	Leaves []testLeaf `json:"leaves"`
+}
+
+func TestWithTags(t *testing.T) {
+	g := NewGenerator()
+	doc := &Document{
+		Tags: []Tag{{Name: "Trees"}, {Name: "Nodes"}},
+		Paths: map[string]PathItem{
+			"/trees": {
+				"get":  {Tags: []string{"Trees"}, Responses: map[string]*Response{"200": {Content: map[string]MediaType{"application/json": {Schema: g.SchemaOf([]testTree{})}}}}},
+				"post": {Tags: []string{"Nodes"}, RequestBody: &RequestBody{Content: map[string]MediaType{"application/json": {Schema: g.SchemaOf(testNode{})}}}},
+			},
+			"/nodes": {
+				"get": {Tags: []string{"Nodes"}, Responses: map[string]*Response{"200": {Content: map[string]MediaType{"application/json": {Schema: g.SchemaOf(testNode{})}}}}},
+			},
+		},
+	}
+	doc.Components.Schemas = g.Schemas()
+
+	selected := doc.WithTags("Trees")
+	if len(selected.Paths) != 1 || len(selected.Paths["/trees"]) != 1 || selected.Paths["/trees"]["get"] == nil {
+		t.Errorf("expected only GET /trees, got %v", selected.Paths)
+	}
+	if len(selected.Tags) != 1 || selected.Tags[0].Name != "Trees" {
+		t.Errorf("expected only the Trees tag, got %v", selected.Tags)
+	}
+	var names []string
+	for name := range selected.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if !reflect.DeepEqual(names, []string{"testLeaf", "testTree"}) {
+		t.Errorf("expected only the schemas GET /trees references, got %v", names)
+	}
+	if len(doc.Paths) != 2 || len(doc.Components.Schemas) != 3 {
+		t.Error("expected the original document to be unchanged")
+	}
+}
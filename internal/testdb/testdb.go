@@ -0,0 +1,626 @@
+// Package testdb opens in-memory SQLite databases with gogent's tables for tests. The schema mirrors
+// the MySQL migrations in SQLite's dialect, table for table and column for column, which
+// TestSchemaMatchesMigrations checks; columns some tests leave unset are nullable or have defaults.
+package testdb
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// schema creates every table the tests use
+const schema = `
+	CREATE TABLE users (
+		id TEXT PRIMARY KEY,
+		username TEXT UNIQUE NOT NULL,
+		email TEXT UNIQUE,
+		password_hash TEXT,
+		email_verified BOOLEAN DEFAULT FALSE,
+		email_verification_token TEXT,
+		email_verification_expires_at DATETIME,
+		is_temporary BOOLEAN DEFAULT FALSE,
+		role TEXT NOT NULL DEFAULT 'user',
+		created_at DATETIME,
+		updated_at DATETIME,
+		last_login_at DATETIME
+	);
+
+	CREATE TABLE user_sessions (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		token TEXT UNIQUE NOT NULL,
+		expires_at DATETIME NOT NULL,
+		created_at DATETIME NOT NULL,
+		previous_token TEXT,
+		revoked_at DATETIME,
+		last_refreshed_at DATETIME
+	);
+
+	CREATE TABLE api_keys (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		key_prefix TEXT NOT NULL,
+		key_hash TEXT UNIQUE NOT NULL,
+		scopes TEXT NOT NULL DEFAULT 'read,write',
+		expires_at DATETIME,
+		last_used_at DATETIME,
+		revoked_at DATETIME,
+		created_at DATETIME NOT NULL
+	);
+
+	CREATE TABLE password_reset_tokens (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		token_hash TEXT UNIQUE NOT NULL,
+		expires_at DATETIME NOT NULL,
+		used_at DATETIME,
+		created_at DATETIME NOT NULL
+	);
+
+	CREATE TABLE audit_logs (
+		id TEXT PRIMARY KEY,
+		actor_id TEXT,
+		action TEXT NOT NULL,
+		target_type TEXT,
+		target_id TEXT,
+		ip_address TEXT,
+		summary TEXT,
+		created_at TIMESTAMP
+	);
+
+	CREATE TABLE execution_runs (
+		id TEXT PRIMARY KEY,
+		user_id TEXT,
+		run_spec TEXT,
+		parameter_sweep TEXT,
+		deterministic BOOLEAN NOT NULL DEFAULT FALSE,
+		determinism_fingerprint TEXT,
+		parent_run_id TEXT,
+		status TEXT DEFAULT 'pending',
+		error_message TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		name TEXT,
+		replay_of_run_id TEXT,
+		description TEXT,
+		enable_function_calling BOOLEAN NOT NULL DEFAULT 0,
+		content_hash TEXT,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		base_prompt TEXT,
+		context_prompt TEXT
+	);
+
+	CREATE TABLE api_configurations (
+		id TEXT PRIMARY KEY,
+		execution_run_id TEXT,
+		variation_name TEXT,
+		model_name TEXT,
+		provider TEXT,
+		created_at TIMESTAMP,
+		user_id TEXT,
+		preset_id TEXT,
+		temperature REAL,
+		max_tokens INTEGER,
+		top_p REAL,
+		top_k INTEGER,
+		safety_settings TEXT,
+		generation_config TEXT,
+		system_prompt TEXT,
+		tools TEXT,
+		tool_config TEXT
+	);
+
+	CREATE TABLE api_requests (
+		id TEXT PRIMARY KEY,
+		user_id TEXT,
+		execution_run_id TEXT,
+		configuration_id TEXT,
+		created_at TIMESTAMP,
+		prompt TEXT,
+		request_type TEXT,
+		context TEXT,
+		function_name TEXT,
+		function_parameters TEXT,
+		request_headers TEXT,
+		request_body TEXT,
+		system_prompt_mode TEXT,
+		redaction TEXT
+	);
+
+	CREATE TABLE api_responses (
+		id TEXT PRIMARY KEY,
+		request_id TEXT,
+		user_id TEXT,
+		usage_metadata TEXT,
+		total_tokens INTEGER,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		response_text TEXT,
+		response_status TEXT,
+		response_time_ms INTEGER,
+		prompt_tokens INTEGER,
+		completion_tokens INTEGER,
+		response_headers TEXT,
+		response_body TEXT,
+		function_call_response TEXT,
+		error_message TEXT,
+		finish_reason TEXT,
+		safety_ratings TEXT,
+		redaction TEXT,
+		latency_breakdown TEXT,
+		thought_summary TEXT,
+		candidate_index INTEGER NOT NULL DEFAULT 0
+	);
+
+	CREATE TABLE function_calls (
+		id TEXT PRIMARY KEY,
+		request_id TEXT,
+		function_name TEXT,
+		function_arguments TEXT,
+		function_response TEXT,
+		execution_status TEXT DEFAULT 'pending',
+		execution_time_ms INTEGER,
+		error_details TEXT,
+		user_id TEXT,
+		function_definition_id TEXT,
+		is_test BOOLEAN NOT NULL DEFAULT FALSE,
+		used_mock_data BOOLEAN NOT NULL DEFAULT FALSE,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE execution_logs (
+		id TEXT PRIMARY KEY,
+		execution_run_id TEXT,
+		configuration_id TEXT,
+		request_id TEXT,
+		log_level TEXT,
+		log_category TEXT,
+		message TEXT,
+		details TEXT,
+		timestamp TIMESTAMP
+	);
+
+	CREATE TABLE comparison_results (
+		id TEXT PRIMARY KEY,
+		execution_run_id TEXT NOT NULL,
+		best_configuration_id TEXT,
+		configuration_scores TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		comparison_type TEXT,
+		metric_name TEXT,
+		all_configurations_data TEXT,
+		best_configuration_data TEXT,
+		analysis_notes TEXT
+	);
+
+	CREATE TABLE function_definitions (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		name TEXT,
+		display_name TEXT NOT NULL,
+		description TEXT,
+		parameters_schema TEXT,
+		mock_response TEXT,
+		endpoint_url TEXT,
+		http_method TEXT DEFAULT 'POST',
+		headers TEXT,
+		auth_config TEXT,
+		required_api_keys TEXT,
+		api_key_validation TEXT,
+		timeout_ms INTEGER,
+		max_response_bytes INTEGER,
+		allowed_domains TEXT,
+		is_active BOOLEAN DEFAULT TRUE,
+		is_system_resource BOOLEAN DEFAULT FALSE,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (user_id, name)
+	);
+
+	CREATE TABLE function_templates (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL UNIQUE,
+		display_name TEXT NOT NULL,
+		description TEXT NOT NULL,
+		category TEXT NOT NULL,
+		parameters_schema TEXT NOT NULL,
+		example_arguments TEXT,
+		mock_response TEXT,
+		endpoint_url TEXT,
+		http_method TEXT NOT NULL DEFAULT 'GET',
+		headers TEXT,
+		required_api_keys TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE execution_function_configs (
+		id TEXT PRIMARY KEY,
+		execution_run_id TEXT NOT NULL,
+		user_id TEXT,
+		function_definition_id TEXT,
+		use_mock_response BOOLEAN DEFAULT FALSE,
+		execution_order INTEGER DEFAULT 0,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		config TEXT
+	);
+
+	CREATE TABLE configuration_presets (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		description TEXT,
+		configuration TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (user_id, name)
+	);
+
+	CREATE TABLE execution_run_tags (
+		execution_run_id TEXT NOT NULL,
+		tag TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (execution_run_id, tag)
+	);
+
+	CREATE TABLE batch_runs (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		template TEXT NOT NULL,
+		status TEXT NOT NULL DEFAULT 'submitting',
+		total_items INTEGER NOT NULL DEFAULT 0,
+		error_message TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE batch_items (
+		id TEXT PRIMARY KEY,
+		batch_run_id TEXT NOT NULL,
+		item_index INTEGER NOT NULL,
+		external_id TEXT,
+		prompt TEXT NOT NULL,
+		context TEXT,
+		metadata TEXT,
+		expected_answer TEXT,
+		status TEXT NOT NULL DEFAULT 'pending',
+		execution_run_id TEXT,
+		error_message TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (batch_run_id, item_index)
+	);
+
+	CREATE TABLE evaluation_results (
+		id TEXT PRIMARY KEY,
+		execution_run_id TEXT NOT NULL,
+		score REAL NOT NULL,
+		user_id TEXT,
+		configuration_id TEXT,
+		variation_name TEXT,
+		repetition INTEGER NOT NULL DEFAULT 0,
+		match_mode TEXT,
+		expected_answer TEXT,
+		passed BOOLEAN,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE judgments (
+		user_id TEXT NOT NULL,
+		response_hash TEXT NOT NULL,
+		judge_model TEXT NOT NULL,
+		score REAL NOT NULL,
+		rationale TEXT,
+		cost_usd REAL NOT NULL DEFAULT 0,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (user_id, response_hash)
+	);
+
+	CREATE TABLE suite_slos (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		suite TEXT NOT NULL,
+		max_latency_ms INTEGER,
+		max_cost_usd REAL,
+		min_success_rate REAL,
+		target REAL NOT NULL DEFAULT 0.95,
+		window_hours INTEGER NOT NULL DEFAULT 24,
+		alert_burn_rate REAL NOT NULL DEFAULT 2,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (user_id, suite)
+	);
+
+	CREATE TABLE execution_run_slo_results (
+		execution_run_id TEXT PRIMARY KEY,
+		compliant BOOLEAN NOT NULL,
+		user_id TEXT,
+		suite TEXT,
+		latency_ms INTEGER,
+		cost_usd REAL,
+		success_rate REAL,
+		latency_met BOOLEAN,
+		cost_met BOOLEAN,
+		success_met BOOLEAN,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE response_feedback (
+		response_id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		execution_run_id TEXT NOT NULL,
+		configuration_id TEXT NOT NULL,
+		thumbs TEXT,
+		rating INTEGER,
+		comment TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE response_embeddings (
+		response_id TEXT NOT NULL,
+		model TEXT NOT NULL,
+		execution_run_id TEXT NOT NULL,
+		user_id TEXT,
+		configuration_id TEXT,
+		variation_name TEXT,
+		repetition INTEGER NOT NULL DEFAULT 0,
+		dimensions INTEGER,
+		embedding TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (response_id, model)
+	);
+
+	CREATE TABLE embedding_cache (
+		user_id TEXT NOT NULL,
+		model TEXT NOT NULL,
+		content_hash TEXT NOT NULL,
+		dimensions INTEGER NOT NULL,
+		embedding TEXT NOT NULL,
+		hit_count INTEGER NOT NULL DEFAULT 0,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		last_hit_at TIMESTAMP,
+		PRIMARY KEY (user_id, model, content_hash)
+	);
+
+	CREATE TABLE documents (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		collection TEXT NOT NULL,
+		name TEXT NOT NULL,
+		content_type TEXT NOT NULL DEFAULT 'text/plain',
+		size_bytes INTEGER NOT NULL DEFAULT 0,
+		chunk_count INTEGER NOT NULL DEFAULT 0,
+		chunk_size INTEGER NOT NULL DEFAULT 0,
+		chunk_overlap INTEGER NOT NULL DEFAULT 0,
+		embedding_model TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE document_chunks (
+		id TEXT PRIMARY KEY,
+		document_id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		collection TEXT NOT NULL,
+		chunk_index INTEGER NOT NULL,
+		content TEXT NOT NULL,
+		embedding_model TEXT NOT NULL,
+		dimensions INTEGER NOT NULL,
+		embedding TEXT NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE retrieved_chunks (
+		request_id TEXT NOT NULL,
+		chunk_rank INTEGER NOT NULL,
+		user_id TEXT,
+		execution_run_id TEXT,
+		configuration_id TEXT,
+		chunk_id TEXT,
+		document_id TEXT,
+		document_name TEXT,
+		collection TEXT,
+		chunk_index INTEGER,
+		content TEXT,
+		score REAL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (request_id, chunk_rank)
+	);
+
+	CREATE TABLE guard_verdicts (
+		request_id TEXT NOT NULL,
+		position INTEGER NOT NULL,
+		user_id TEXT,
+		execution_run_id TEXT,
+		configuration_id TEXT,
+		guard TEXT,
+		stage TEXT,
+		action TEXT,
+		triggered BOOLEAN,
+		findings TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (request_id, position)
+	);
+
+	CREATE TABLE raw_payloads (
+		request_id TEXT NOT NULL,
+		sequence INTEGER NOT NULL,
+		user_id TEXT,
+		execution_run_id TEXT,
+		method TEXT,
+		url TEXT,
+		status_code INTEGER NOT NULL DEFAULT 0,
+		request_body BLOB,
+		request_size INTEGER,
+		response_body BLOB,
+		response_size INTEGER,
+		truncated BOOLEAN NOT NULL DEFAULT FALSE,
+		error_message TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (request_id, sequence)
+	);
+
+	CREATE TABLE model_catalog (
+		name TEXT PRIMARY KEY,
+		display_name TEXT NOT NULL,
+		description TEXT,
+		version TEXT,
+		input_token_limit INTEGER NOT NULL DEFAULT 0,
+		output_token_limit INTEGER NOT NULL DEFAULT 0,
+		supported_methods TEXT NOT NULL,
+		fetched_at TIMESTAMP NOT NULL
+	);
+
+	CREATE TABLE saved_reports (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL UNIQUE,
+		description TEXT,
+		query_text TEXT NOT NULL,
+		parameters TEXT,
+		created_by TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE daily_user_rollups (
+		user_id TEXT NOT NULL,
+		day TEXT NOT NULL,
+		execution_runs INTEGER NOT NULL DEFAULT 0,
+		api_requests INTEGER NOT NULL DEFAULT 0,
+		api_responses INTEGER NOT NULL DEFAULT 0,
+		successful_responses INTEGER NOT NULL DEFAULT 0,
+		timed_responses INTEGER NOT NULL DEFAULT 0,
+		response_time_ms_sum INTEGER NOT NULL DEFAULT 0,
+		function_calls INTEGER NOT NULL DEFAULT 0,
+		prompt_tokens INTEGER NOT NULL DEFAULT 0,
+		completion_tokens INTEGER NOT NULL DEFAULT 0,
+		total_tokens INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (user_id, day)
+	);
+
+	CREATE TABLE rollup_refreshes (
+		name TEXT PRIMARY KEY,
+		refreshed_at TIMESTAMP NOT NULL
+	);
+
+	CREATE TABLE prompts (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		description TEXT,
+		content TEXT NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (user_id, name)
+	);
+
+	CREATE TABLE workspaces (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		created_by TEXT NOT NULL,
+		created_at TIMESTAMP
+	);
+
+	CREATE TABLE workspace_members (
+		workspace_id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		role TEXT NOT NULL,
+		created_at TIMESTAMP,
+		PRIMARY KEY (workspace_id, user_id)
+	);
+
+	CREATE TABLE workspace_resources (
+		workspace_id TEXT NOT NULL,
+		resource_type TEXT NOT NULL,
+		resource_id TEXT NOT NULL,
+		shared_by TEXT NOT NULL,
+		created_at TIMESTAMP,
+		PRIMARY KEY (workspace_id, resource_type, resource_id)
+	);
+
+	CREATE TABLE workspace_settings (
+		id TEXT PRIMARY KEY,
+		default_model TEXT,
+		default_safety_settings TEXT,
+		default_metrics TEXT,
+		retention_days INTEGER DEFAULT 0,
+		request_retention_days INTEGER DEFAULT 0,
+		response_retention_days INTEGER DEFAULT 0,
+		log_retention_days INTEGER DEFAULT 0,
+		function_call_retention_days INTEGER DEFAULT 0,
+		allowed_providers TEXT,
+		run_name_template TEXT,
+		duplicate_policy TEXT,
+		duplicate_window_secs INTEGER DEFAULT 0,
+		max_concurrent_executions INTEGER DEFAULT 0,
+		max_variations_per_run INTEGER DEFAULT 0,
+		daily_token_budget INTEGER DEFAULT 0,
+		updated_by TEXT,
+		updated_at TIMESTAMP
+	);
+
+	CREATE TABLE user_retention_policies (
+		user_id TEXT PRIMARY KEY,
+		runs_days INTEGER,
+		requests_days INTEGER,
+		responses_days INTEGER,
+		logs_days INTEGER,
+		function_calls_days INTEGER,
+		updated_by TEXT,
+		updated_at TIMESTAMP
+	);
+
+	CREATE TABLE reviews (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		execution_run_id TEXT NOT NULL,
+		mode TEXT NOT NULL,
+		labels TEXT,
+		prompt TEXT,
+		status TEXT NOT NULL,
+		created_at TIMESTAMP,
+		closed_at TIMESTAMP
+	);
+
+	CREATE TABLE review_items (
+		id TEXT PRIMARY KEY,
+		review_id TEXT NOT NULL,
+		response_id TEXT NOT NULL,
+		configuration_id TEXT NOT NULL,
+		position INTEGER NOT NULL
+	);
+
+	CREATE TABLE review_assignments (
+		review_id TEXT NOT NULL,
+		reviewer_id TEXT NOT NULL,
+		created_at TIMESTAMP,
+		submitted_at TIMESTAMP,
+		PRIMARY KEY (review_id, reviewer_id)
+	);
+
+	CREATE TABLE review_judgments (
+		review_id TEXT NOT NULL,
+		reviewer_id TEXT NOT NULL,
+		item_id TEXT NOT NULL,
+		item_rank INTEGER,
+		label TEXT,
+		PRIMARY KEY (review_id, reviewer_id, item_id)
+	);
+`
+
+// Open returns an in-memory database with every table of the schema, closed when the test ends. It
+// keeps a single connection, since each connection to :memory: opens a database of its own.
+func Open(t testing.TB) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("failed to create test schema: %v", err)
+	}
+	return db
+}
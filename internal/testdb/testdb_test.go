@@ -0,0 +1,261 @@
+package testdb
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+// migrationsDir holds the MySQL migrations the test schema mirrors
+const migrationsDir = "../../migrations"
+
+// unmigratedColumns are columns the code reads that no migration creates yet, so the test schema
+// has them on top of the migrated ones: email verification predates the migrations
+var unmigratedColumns = map[string][]string{
+	"users": {"email_verification_token", "email_verification_expires_at"},
+}
+
+// migratedSchema applies every up migration's table and column changes in order, returning the
+// columns of each table they leave
+func migratedSchema(t *testing.T) map[string][]string {
+	files, err := filepath.Glob(filepath.Join(migrationsDir, "*.up.sql"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("failed to find migrations: %v", err)
+	}
+	sort.Strings(files)
+
+	tables := make(map[string][]string)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", file, err)
+		}
+		for _, statement := range splitSQL(string(data)) {
+			words := strings.Fields(statement)
+			if len(words) < 3 {
+				continue
+			}
+			switch {
+			case strings.EqualFold(words[0], "CREATE") && strings.EqualFold(words[1], "TABLE"):
+				name, body := tableDefinition(statement)
+				tables[name] = nil
+				for _, definition := range splitTopLevel(body) {
+					if column, ok := columnName(definition); ok {
+						tables[name] = append(tables[name], column)
+					}
+				}
+			case strings.EqualFold(words[0], "ALTER") && strings.EqualFold(words[1], "TABLE"):
+				name := unquote(words[2])
+				actions := strings.TrimSpace(statement[strings.Index(statement, words[2])+len(words[2]):])
+				for _, action := range splitTopLevel(actions) {
+					fields := strings.Fields(action)
+					if len(fields) < 2 {
+						continue
+					}
+					verb := strings.ToUpper(fields[0])
+					if strings.EqualFold(fields[1], "COLUMN") {
+						fields = fields[1:]
+					}
+					switch verb {
+					case "ADD":
+						if column, ok := columnName(strings.Join(fields[1:], " ")); ok {
+							tables[name] = append(tables[name], column)
+						}
+					case "DROP":
+						if column, ok := columnName(strings.Join(fields[1:], " ")); ok {
+							tables[name] = slices.DeleteFunc(tables[name], func(c string) bool { return c == column })
+						}
+					case "CHANGE", "RENAME":
+						if len(fields) >= 3 {
+							from, to := unquote(fields[1]), unquote(fields[2])
+							if strings.EqualFold(to, "TO") && len(fields) >= 4 {
+								to = unquote(fields[3])
+							}
+							for i, c := range tables[name] {
+								if c == from {
+									tables[name][i] = to
+								}
+							}
+						}
+					}
+				}
+			case strings.EqualFold(words[0], "DROP") && strings.EqualFold(words[1], "TABLE"):
+				name := words[2]
+				if strings.EqualFold(name, "IF") && len(words) >= 5 {
+					name = words[4]
+				}
+				delete(tables, unquote(name))
+			}
+		}
+	}
+	return tables
+}
+
+// splitSQL splits a migration into statements, dropping comments and ignoring semicolons in quotes
+func splitSQL(sql string) []string {
+	var statements []string
+	var current strings.Builder
+	var quote rune
+	runes := []rune(sql)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote != 0:
+			current.WriteRune(r)
+			if r == '\\' && quote != '`' && i+1 < len(runes) {
+				i++
+				current.WriteRune(runes[i])
+			} else if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+			current.WriteRune(r)
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-', r == '#':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			current.WriteRune('\n')
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			for i += 2; i+1 < len(runes) && (runes[i] != '*' || runes[i+1] != '/'); i++ {
+			}
+			i++
+			current.WriteRune(' ')
+		case r == ';':
+			statements = append(statements, strings.TrimSpace(current.String()))
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
+
+// tableDefinition returns the name and the parenthesized body of a CREATE TABLE statement
+func tableDefinition(statement string) (string, string) {
+	open := strings.IndexByte(statement, '(')
+	words := strings.Fields(statement[:open])
+	name := unquote(words[len(words)-1])
+	depth, quote := 0, rune(0)
+	for i, r := range statement[open:] {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+			if depth == 0 {
+				return name, statement[open+1 : open+i]
+			}
+		}
+	}
+	return name, statement[open+1:]
+}
+
+// splitTopLevel splits s at the commas outside parentheses and quotes
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth, quote, start := 0, rune(0), 0
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ',' && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// columnName returns the column a table definition or ADD/DROP action names, reporting false for
+// keys, indexes and constraints
+func columnName(definition string) (string, bool) {
+	fields := strings.Fields(definition)
+	if len(fields) == 0 {
+		return "", false
+	}
+	switch strings.ToUpper(fields[0]) {
+	case "PRIMARY", "KEY", "INDEX", "UNIQUE", "CONSTRAINT", "FOREIGN", "FULLTEXT", "SPATIAL", "CHECK":
+		return "", false
+	}
+	return unquote(fields[0]), true
+}
+
+// unquote strips the backticks and punctuation around an identifier
+func unquote(identifier string) string {
+	return strings.TrimFunc(identifier, func(r rune) bool { return r == '`' || unicode.IsPunct(r) && r != '_' })
+}
+
+// TestSchemaMatchesMigrations keeps the SQLite test schema in step with the MySQL migrations: it
+// must have the same tables, each with the same columns
+func TestSchemaMatchesMigrations(t *testing.T) {
+	migrated := migratedSchema(t)
+	db := Open(t)
+
+	rows, err := db.Query("SELECT name FROM sqlite_master WHERE type = 'table' ORDER BY name")
+	if err != nil {
+		t.Fatalf("failed to list test tables: %v", err)
+	}
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatalf("failed to scan table name: %v", err)
+		}
+		tables = append(tables, name)
+	}
+	rows.Close()
+
+	for _, table := range tables {
+		expected, ok := migrated[table]
+		if !ok {
+			t.Errorf("test table %s is not created by the migrations", table)
+			continue
+		}
+		columns, err := db.Query("SELECT name FROM pragma_table_info(?)", table)
+		if err != nil {
+			t.Fatalf("failed to read %s columns: %v", table, err)
+		}
+		var actual []string
+		for columns.Next() {
+			var name string
+			if err := columns.Scan(&name); err != nil {
+				t.Fatalf("failed to scan column name: %v", err)
+			}
+			actual = append(actual, name)
+		}
+		columns.Close()
+
+		expected = slices.Sorted(slices.Values(append(slices.Clone(expected), unmigratedColumns[table]...)))
+		slices.Sort(actual)
+		if !slices.Equal(actual, expected) {
+			t.Errorf("%s columns differ from the migrations:\n  test schema: %v\n  migrations:  %v", table, actual, expected)
+		}
+	}
+	for table := range migrated {
+		if !slices.Contains(tables, table) {
+			t.Errorf("migrated table %s is missing from the test schema", table)
+		}
+	}
+}
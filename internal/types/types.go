@@ -2,6 +2,9 @@ package types
 
 import (
 	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -14,6 +17,19 @@ const (
 	RequestTypeFunctionCall RequestType = "function_call"
 )
 
+// How a configuration's system prompt was sent with a request
+const (
+	SystemPromptModeInstruction = "instruction" // Gemini's systemInstruction field
+	SystemPromptModeInline      = "inline"      // Prepended to the prompt text
+)
+
+// How a configuration's model may call its tools, sent as Gemini's functionCallingConfig mode
+const (
+	ToolChoiceAuto = "AUTO" // The model decides whether to call a function
+	ToolChoiceAny  = "ANY"  // The model must call a function (the default)
+	ToolChoiceNone = "NONE" // The model may not call functions
+)
+
 // ResponseStatus represents the status of an API response
 type ResponseStatus string
 
@@ -21,6 +37,7 @@ const (
 	ResponseStatusSuccess ResponseStatus = "success"
 	ResponseStatusError   ResponseStatus = "error"
 	ResponseStatusTimeout ResponseStatus = "timeout"
+	ResponseStatusBlocked ResponseStatus = "blocked" // An input guard stopped the prompt before the model call
 )
 
 // LogLevel represents the severity level of a log entry
@@ -34,6 +51,32 @@ const (
 	LogLevelSuccess LogLevel = "SUCCESS"
 )
 
+// logLevelSeverity orders the levels from least to most severe; SUCCESS ranks with INFO
+var logLevelSeverity = map[LogLevel]int{
+	LogLevelDebug:   0,
+	LogLevelInfo:    1,
+	LogLevelSuccess: 1,
+	LogLevelWarn:    2,
+	LogLevelError:   3,
+}
+
+// Severity ranks the level against the others; unknown levels rank with INFO
+func (l LogLevel) Severity() int {
+	if severity, ok := logLevelSeverity[l]; ok {
+		return severity
+	}
+	return logLevelSeverity[LogLevelInfo]
+}
+
+// ParseLogLevel returns the level named by s, ignoring case
+func ParseLogLevel(s string) (LogLevel, error) {
+	level := LogLevel(strings.ToUpper(s))
+	if _, ok := logLevelSeverity[level]; !ok {
+		return "", fmt.Errorf("unknown log level %q", s)
+	}
+	return level, nil
+}
+
 // LogCategory represents the category/context of a log entry
 type LogCategory string
 
@@ -62,6 +105,7 @@ type ExecutionLog struct {
 // ExecutionRun represents a group of related API calls with variations
 type ExecutionRun struct {
 	ID                    string    `json:"id"`
+	UserID                string    `json:"userId,omitempty"`
 	Name                  string    `json:"name"`
 	Description           string    `json:"description,omitempty"`
 	EnableFunctionCalling bool      `json:"enableFunctionCalling"`
@@ -69,6 +113,25 @@ type ExecutionRun struct {
 	ErrorMessage          string    `json:"errorMessage,omitempty"`
 	CreatedAt             time.Time `json:"createdAt"`
 	UpdatedAt             time.Time `json:"updatedAt"`
+
+	// Set for deterministic runs; reruns with the same fingerprint used identical inputs
+	Deterministic          bool   `json:"deterministic,omitempty"`
+	DeterminismFingerprint string `json:"determinismFingerprint,omitempty"`
+
+	// Set for replays: the run whose prompt, configurations and function responses were replayed
+	ReplayOfRunID string `json:"replayOfRunId,omitempty"`
+
+	// Set for runs submitted from a clone: the run the request was cloned from
+	ParentRunID string `json:"parentRunId,omitempty"`
+
+	// The resolved spec the run executed; set when a single run is loaded
+	RunSpec *RunSpec `json:"runSpec,omitempty"`
+
+	// The parameter sweep the run's configurations were expanded from; set when a single run is loaded
+	Sweep *ParameterSweep `json:"sweep,omitempty"`
+
+	// Labels the run was tagged with; set when a single run is loaded
+	Tags []string `json:"tags,omitempty"`
 }
 
 // APIConfiguration represents a specific configuration for API calls
@@ -86,12 +149,69 @@ type APIConfiguration struct {
 	GenerationConfig map[string]interface{} `json:"generationConfig,omitempty"`
 	Tools            []Tool                 `json:"tools,omitempty"`
 	ToolConfig       map[string]interface{} `json:"toolConfig,omitempty"`
+	ToolNames        []string               `json:"toolNames,omitempty"`    // Subset of the run's FunctionTools to expose (empty = all)
+	DisableTools     bool                   `json:"disableTools,omitempty"` // Run this variation without any tools
 	CreatedAt        time.Time              `json:"createdAt"`
+
+	// Instruction prepended to tool-enabled prompts; empty uses the run or engine default
+	FunctionInstruction        string `json:"functionInstruction,omitempty"`
+	DisableFunctionInstruction bool   `json:"disableFunctionInstruction,omitempty"` // Send prompts without the forced tool instruction
+
+	// Provider-agnostic safety posture, translated into SafetySettings for the model's provider
+	SafetyPolicy *SafetyPolicy `json:"safetyPolicy,omitempty"`
+
+	// Send SystemPrompt prepended to the prompt text instead of as Gemini's systemInstruction
+	InlineSystemPrompt bool `json:"inlineSystemPrompt,omitempty"`
+
+	// Structured output: the response MIME type (e.g. application/json) and the schema the response must match
+	ResponseMimeType string                 `json:"responseMimeType,omitempty"`
+	ResponseSchema   map[string]interface{} `json:"responseSchema,omitempty"`
+
+	// Deterministic mode: sampling seed sent to the provider and pinned tool responses
+	Seed          *int32 `json:"seed,omitempty"`
+	Deterministic bool   `json:"deterministic,omitempty"`
+
+	// Tokens a Gemini thinking model may spend reasoning before it answers: -1 lets the model decide
+	// and 0 turns thinking off. The model's summary of its reasoning is returned as ThoughtSummary.
+	ThinkingBudget *int32 `json:"thinkingBudget,omitempty"`
+
+	// How many responses a Gemini model generates for the prompt. Each candidate is stored as its own
+	// response to the request and compared as a sub-variation of the configuration.
+	CandidateCount *int32 `json:"candidateCount,omitempty"`
+
+	// Configuration preset this configuration starts from; fields set here override the preset's
+	PresetID string `json:"presetId,omitempty"`
+
+	// How long this variation may run, including function calls; 0 uses the client's TimeoutSecs
+	TimeoutMs int32 `json:"timeoutMs,omitempty"`
+
+	// Service Gemini models are called through: BackendGeminiAPI (the default) or BackendVertex
+	Backend string `json:"backend,omitempty"`
+
+	// Provider serving the model, e.g. ProviderOllama for local models; empty infers it from the model
+	// name. Runs record the resolved provider.
+	Provider string `json:"provider,omitempty"`
+
+	// Document chunks retrieved for the prompt and added to its context before the variation runs
+	Retrieval *RetrievalConfig `json:"retrieval,omitempty"`
+
+	// Guards run on the prompt before the model call and on the response after it
+	Guardrails []GuardConfig `json:"guardrails,omitempty"`
+
+	// Whether the model may or must call its tools: ToolChoiceAuto, ToolChoiceAny (the default) or
+	// ToolChoiceNone. In ANY mode AllowedFunctionNames limits the calls to some of the tools.
+	ToolChoiceMode       string   `json:"toolChoiceMode,omitempty"`
+	AllowedFunctionNames []string `json:"allowedFunctionNames,omitempty"`
+
+	// Replay mode: function calls return these recorded responses instead of calling the function
+	Replay                bool           `json:"-"`
+	RecordedFunctionCalls []FunctionCall `json:"-"`
 }
 
 // FunctionDefinition represents a reusable function definition
 type FunctionDefinition struct {
 	ID               string                 `json:"id"`
+	UserID           string                 `json:"userId,omitempty"`       // Owner ("system" for built-in functions)
 	Name             string                 `json:"name"`                   // Unique function name for API calls
 	DisplayName      string                 `json:"displayName"`            // Human-readable name
 	Description      string                 `json:"description"`            // Function description
@@ -106,6 +226,48 @@ type FunctionDefinition struct {
 	ApiKeyValidation map[string]interface{} `json:"apiKeyValidation,omitempty"` // Validation rules for each API key
 	CreatedAt        time.Time              `json:"createdAt"`
 	UpdatedAt        time.Time              `json:"updatedAt"`
+
+	// Sandbox limits enforced when the endpoint is called; zero values use the server defaults
+	TimeoutMs        int32    `json:"timeoutMs,omitempty"`        // Call timeout (default 10s, max 60s)
+	MaxResponseBytes int32    `json:"maxResponseBytes,omitempty"` // Largest accepted response body (default 256 KB, max 1 MB)
+	AllowedDomains   []string `json:"allowedDomains,omitempty"`   // Hosts the endpoint may call, subdomains included (empty allows any)
+}
+
+// FunctionTemplate is a ready-made function definition users copy into their own functions
+type FunctionTemplate struct {
+	ID               string                 `json:"id"`
+	Name             string                 `json:"name"` // Function name a copy starts with
+	DisplayName      string                 `json:"displayName"`
+	Description      string                 `json:"description"`
+	Category         string                 `json:"category"`
+	ParametersSchema map[string]interface{} `json:"parametersSchema"`
+	ExampleArguments map[string]interface{} `json:"exampleArguments,omitempty"` // Arguments a model might send
+	MockResponse     map[string]interface{} `json:"mockResponse,omitempty"`
+	EndpointURL      string                 `json:"endpointUrl,omitempty"`
+	HttpMethod       string                 `json:"httpMethod"`
+	Headers          map[string]interface{} `json:"headers,omitempty"`
+	RequiredApiKeys  []string               `json:"requiredApiKeys,omitempty"`
+	CreatedAt        time.Time              `json:"createdAt"`
+}
+
+// OpenAPIImportRequest generates function definitions from an OpenAPI 3 spec
+type OpenAPIImportRequest struct {
+	Spec       string   `json:"spec,omitempty"`       // The spec as JSON or YAML
+	URL        string   `json:"url,omitempty"`        // Where to fetch the spec from, instead of Spec
+	Operations []string `json:"operations,omitempty"` // operationIds or "METHOD /path"; empty imports every operation
+	DryRun     bool     `json:"dryRun,omitempty"`     // Return the definitions without creating them
+}
+
+// OpenAPIImportResult is what an OpenAPI import created, or would create on a dry run
+type OpenAPIImportResult struct {
+	Functions []*FunctionDefinition `json:"functions"`
+	Skipped   []OpenAPIImportSkip   `json:"skipped,omitempty"`
+}
+
+// OpenAPIImportSkip is an operation whose function was not created
+type OpenAPIImportSkip struct {
+	Function string `json:"function"`
+	Reason   string `json:"reason"`
 }
 
 // ExecutionFunctionConfig represents function configuration for a specific execution
@@ -175,6 +337,11 @@ type Tool struct {
 	Name        string                 `json:"name"`
 	Description string                 `json:"description"`
 	Parameters  map[string]interface{} `json:"parameters"`
+
+	// Response returned instead of calling the function in deterministic runs
+	MockResponse map[string]interface{} `json:"mockResponse,omitempty"`
+	// Return the function definition's mock response instead of calling it during this run
+	UseMockResponse bool `json:"useMockResponse,omitempty"`
 }
 
 // APIRequest represents a request to the Gemini API
@@ -189,6 +356,8 @@ type APIRequest struct {
 	FunctionParameters map[string]interface{} `json:"functionParameters,omitempty"`
 	RequestHeaders     map[string]interface{} `json:"requestHeaders,omitempty"`
 	RequestBody        map[string]interface{} `json:"requestBody,omitempty"`
+	SystemPromptMode   string                 `json:"systemPromptMode,omitempty"` // Empty when no system prompt was sent
+	Redaction          *RedactionRecord       `json:"redaction,omitempty"`        // Set on the stored copy
 	CreatedAt          time.Time              `json:"createdAt"`
 }
 
@@ -206,7 +375,63 @@ type APIResponse struct {
 	ResponseTimeMs       int32                  `json:"responseTimeMs"`
 	ResponseHeaders      map[string]interface{} `json:"responseHeaders,omitempty"`
 	ResponseBody         map[string]interface{} `json:"responseBody,omitempty"`
-	CreatedAt            time.Time              `json:"createdAt"`
+	Redaction            *RedactionRecord       `json:"redaction,omitempty"` // Set on the stored copy
+	Latency              *LatencyBreakdown      `json:"latency,omitempty"`
+	ThoughtSummary       string                 `json:"thoughtSummary,omitempty"` // The thinking model's summary of its reasoning, kept out of ResponseText
+	CandidateIndex       int                    `json:"candidateIndex,omitempty"` // Which of the request's candidates this is, 0 for the first
+
+	// The request's other candidates, returned by the provider with the first one; each becomes its own
+	// variation result and stored response
+	Candidates []APIResponse `json:"-"`
+	CreatedAt  time.Time     `json:"createdAt"`
+}
+
+// LatencyBreakdown splits where a variation's time went, in milliseconds. TotalMs runs from the
+// variation starting until its response is ready to store and is the sum of every phase but QueuedMs.
+type LatencyBreakdown struct {
+	QueuedMs    int64 `json:"queuedMs"`    // Waiting to start: in the execution queue, on run setup and behind earlier variations
+	RetrievalMs int64 `json:"retrievalMs"` // Retrieving document chunks
+	ModelMs     int64 `json:"modelMs"`     // The model round-trip
+	FunctionMs  int64 `json:"functionMs"`  // Running the function the model called and recording the call
+	FollowUpMs  int64 `json:"followUpMs"`  // The model call with the function result
+	DBMs        int64 `json:"dbMs"`        // Storing the variation's records; 0 in the stored breakdown
+	OtherMs     int64 `json:"otherMs"`     // Guards, building requests and the rest
+	TotalMs     int64 `json:"totalMs"`
+}
+
+// RedactionPolicy controls what of a request or response is stored. Headers not on the allowlist
+// have their values removed, pattern matches are scrubbed from prompts, text and bodies, and bodies
+// larger than MaxBodyBytes once encoded are not stored.
+type RedactionPolicy struct {
+	HeaderAllowlist []string `json:"headerAllowlist,omitempty"` // Case-insensitive header names stored verbatim
+	Patterns        []string `json:"patterns,omitempty"`        // Regular expressions replaced with [REDACTED]
+	MaxBodyBytes    int      `json:"maxBodyBytes,omitempty"`    // 0 for no limit
+}
+
+// RawPayload is one HTTP exchange of a request with its provider, stored for runs that asked for raw
+// payloads. A variation's retries and follow-up calls are separate exchanges.
+type RawPayload struct {
+	RequestID    string    `json:"requestId"`
+	Sequence     int       `json:"sequence"` // 1-based order of the exchange within the request
+	Method       string    `json:"method"`
+	URL          string    `json:"url"`                  // Without the query string
+	StatusCode   int       `json:"statusCode,omitempty"` // Unset when no response arrived
+	RequestBody  string    `json:"requestBody"`
+	RequestSize  int       `json:"requestSize"` // Bytes before truncation
+	ResponseBody string    `json:"responseBody"`
+	ResponseSize int       `json:"responseSize"` // Bytes before truncation
+	StoredBytes  int       `json:"storedBytes"`  // Both bodies after compression
+	Truncated    bool      `json:"truncated,omitempty"`
+	ErrorMessage string    `json:"errorMessage,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// RedactionRecord notes how a stored request or response was redacted
+type RedactionRecord struct {
+	Policy          string   `json:"policy"` // Fingerprint of the policy applied
+	RedactedHeaders []string `json:"redactedHeaders,omitempty"`
+	ScrubbedMatches int      `json:"scrubbedMatches,omitempty"`
+	TruncatedFields []string `json:"truncatedFields,omitempty"` // Bodies over the size limit
 }
 
 // FunctionCall represents a function call made during AI execution
@@ -219,7 +444,24 @@ type FunctionCall struct {
 	ExecutionStatus  string                 `json:"execution_status"`
 	ExecutionTimeMs  int32                  `json:"execution_time_ms,omitempty"`
 	ErrorDetails     string                 `json:"error_details,omitempty"`
+	UsedMockData     bool                   `json:"used_mock_data"`
 	CreatedAt        time.Time              `json:"created_at"`
+
+	// Set for function test calls, which are not tied to an API request
+	UserID               string `json:"user_id,omitempty"`
+	FunctionDefinitionID string `json:"function_definition_id,omitempty"`
+	IsTest               bool   `json:"is_test,omitempty"`
+}
+
+// FunctionTestResult is the outcome of testing a function definition outside of an execution run
+type FunctionTestResult struct {
+	Success         bool                   `json:"success"`
+	UsedMockData    bool                   `json:"usedMockData"`
+	ExecutionTimeMs int32                  `json:"executionTimeMs"`
+	StatusCode      int                    `json:"statusCode,omitempty"` // HTTP status from the function's endpoint
+	Response        map[string]interface{} `json:"response,omitempty"`
+	Error           string                 `json:"error,omitempty"`
+	FunctionCallID  string                 `json:"functionCallId"` // function_calls entry recording the test
 }
 
 // SessionApiKeys represents API keys passed with each request (not stored on backend)
@@ -230,6 +472,8 @@ type SessionApiKeys struct {
 	Neo4jUsername     string `json:"neo4jUsername,omitempty"`
 	Neo4jPassword     string `json:"neo4jPassword,omitempty"`
 	Neo4jDatabase     string `json:"neo4jDatabase,omitempty"`
+	WebSearchApiKey   string `json:"webSearchApiKey,omitempty"`
+	SqlDsn            string `json:"sqlDsn,omitempty"`
 }
 
 // GeminiClientConfig represents the configuration for the Gemini client
@@ -243,10 +487,35 @@ type GeminiClientConfig struct {
 	Neo4jPassword     string `json:"neo4j_password,omitempty"`      // DEPRECATED: Use session API keys instead
 	Neo4jDatabase     string `json:"neo4j_database,omitempty"`      // DEPRECATED: Use session API keys instead
 
-	ProjectID   string `json:"project_id,omitempty"`
-	Region      string `json:"region,omitempty"`
+	ProjectID   string `json:"project_id,omitempty"` // Google Cloud project of configurations using the Vertex AI backend
+	Region      string `json:"region,omitempty"`     // Vertex AI region; empty uses us-central1
+	OllamaURL   string `json:"ollama_url,omitempty"` // Ollama server of configurations using ProviderOllama
 	MaxRetries  int    `json:"max_retries"`
 	TimeoutSecs int    `json:"timeout_secs"`
+
+	// Consecutive failures that open a host's circuit breaker, and how long it then fails requests
+	// fast before a trial request; 0 uses 5 failures and 30 seconds
+	CircuitBreakerThreshold    int `json:"circuit_breaker_threshold,omitempty"`
+	CircuitBreakerCooldownSecs int `json:"circuit_breaker_cooldown_secs,omitempty"`
+
+	// Search API behind the web_search function (serpapi, brave or tavily) and its key
+	WebSearchProvider string `json:"web_search_provider,omitempty"`
+	WebSearchAPIKey   string `json:"web_search_api_key,omitempty"`
+
+	// Database queried by the query_sql function: a database/sql driver name (empty uses mysql)
+	// and its DSN. It is never gogent's own database.
+	SQLQueryDriver string `json:"sql_query_driver,omitempty"`
+	SQLQueryDSN    string `json:"sql_query_dsn,omitempty"`
+
+	// Lets function endpoints resolve to loopback, link-local, private and unspecified addresses,
+	// which are refused by default so functions cannot reach the server's own network
+	AllowPrivateFunctionEndpoints bool `json:"allow_private_function_endpoints,omitempty"`
+
+	// Redaction applied to requests and responses before they are stored; nil uses the default policy
+	Redaction *RedactionPolicy `json:"redaction,omitempty"`
+
+	// Execution logs below this level are printed but not stored; empty stores every level
+	MinStoredLogLevel LogLevel `json:"min_stored_log_level,omitempty"`
 }
 
 // MultiExecutionRequest represents a request to execute multiple variations
@@ -260,13 +529,330 @@ type MultiExecutionRequest struct {
 	FunctionTools         []Tool             `json:"functionTools,omitempty"`
 	ComparisonConfig      *ComparisonConfig  `json:"comparisonConfig,omitempty"`
 	SessionApiKeys        *SessionApiKeys    `json:"sessionApiKeys,omitempty"` // API keys for this session
+
+	// Run-wide function calling instruction, used by configurations that don't set their own
+	FunctionInstruction        string `json:"functionInstruction,omitempty"`
+	DisableFunctionInstruction bool   `json:"disableFunctionInstruction,omitempty"`
+
+	// Run-wide safety policy, used by configurations that don't set their own
+	SafetyPolicy *SafetyPolicy `json:"safetyPolicy,omitempty"`
+
+	// Deterministic mode pins sampling (temperature 0, fixed seed) and tool responses so reruns are reproducible
+	Deterministic bool   `json:"deterministic,omitempty"`
+	Seed          *int32 `json:"seed,omitempty"` // Seed for deterministic runs (default 0)
+
+	// Run naming: ExecutionRunName, or NameTemplate when it is empty, may use the {suite},
+	// {timestamp}, {date} and {git_sha} placeholders
+	NameTemplate string `json:"nameTemplate,omitempty"`
+	Suite        string `json:"suite,omitempty"`
+	GitSHA       string `json:"gitSha,omitempty"`
+
+	// Handling of a submission identical to one made within the duplicate window
+	DuplicatePolicy string `json:"duplicatePolicy,omitempty"` // allow (default), reject or merge
+
+	// Number of times each configuration is executed; repeated samples are compared statistically
+	Repetitions int `json:"repetitions,omitempty"`
+
+	// Golden answer every variation's response is scored against
+	ExpectedAnswer *ExpectedAnswer `json:"expectedAnswer,omitempty"`
+
+	// Parameter sweep expanding each configuration into one per sampled point
+	Sweep *ParameterSweep `json:"sweep,omitempty"`
+
+	// Position in the server's execution queue: high, normal (default) or low
+	Priority string `json:"priority,omitempty"`
+
+	// Labels grouping runs by prompt category, such as "summarization", for the leaderboard
+	Tags []string `json:"tags,omitempty"`
+
+	// Build and return each variation's provider request instead of executing the run; nothing is
+	// sent to a model or stored
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// Store the raw HTTP bodies exchanged with the provider, gzip-compressed, for debugging. Each body
+	// is cut to RawPayloadMaxBytes (default 1 MiB) before compression.
+	StoreRawPayloads   bool `json:"storeRawPayloads,omitempty"`
+	RawPayloadMaxBytes int  `json:"rawPayloadMaxBytes,omitempty"`
+
+	// Cache the shared context with Gemini once per model, system instruction and tool set, and
+	// send Gemini variations a reference to the cache entry instead of the context
+	CacheContext bool `json:"cacheContext,omitempty"`
+
+	// Set by CloneRunAsRequest to link the new run to the run it was cloned from
+	ParentRunID string `json:"parentRunId,omitempty"`
+
+	// Set by ReplayExecutionRun to link the new run to the replayed one
+	ReplayOfRunID string `json:"-"`
+
+	// When the request was queued, set by the server; variations count their wait from it
+	SubmittedAt time.Time `json:"-"`
+}
+
+// Parameter sweep modes
+const (
+	SweepModeGrid   = "grid"   // Every combination of the ranges' values
+	SweepModeRandom = "random" // Samples points drawn uniformly from the ranges
+)
+
+// ParameterSweep expands each configuration of a request into one configuration per point of a
+// grid or random search over its sampling parameters
+type ParameterSweep struct {
+	Mode        string      `json:"mode,omitempty"`    // grid (default) or random
+	Samples     int         `json:"samples,omitempty"` // Points drawn in random mode
+	Seed        int64       `json:"seed,omitempty"`    // Random mode seed; 0 picks one, which is recorded so the draw can be repeated
+	Temperature *SweepRange `json:"temperature,omitempty"`
+	TopP        *SweepRange `json:"topP,omitempty"`
+	TopK        *SweepRange `json:"topK,omitempty"`
+}
+
+// SweepRange is the values a swept parameter takes: either explicit values or min..max in steps.
+// In JSON it is an object, an array of values, or a string such as "0.1..1.0 step 0.3".
+type SweepRange struct {
+	Min    float64   `json:"min,omitempty"`
+	Max    float64   `json:"max,omitempty"`
+	Step   float64   `json:"step,omitempty"`
+	Values []float64 `json:"values,omitempty"`
+}
+
+// UnmarshalJSON accepts a range object, an array of values or a "min..max step s" string
+func (r *SweepRange) UnmarshalJSON(data []byte) error {
+	var values []float64
+	if err := json.Unmarshal(data, &values); err == nil {
+		*r = SweepRange{Values: values}
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		return r.parse(text)
+	}
+
+	type sweepRange SweepRange
+	var decoded sweepRange
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return fmt.Errorf("sweep range must be an object, an array of values or \"min..max step s\": %w", err)
+	}
+	*r = SweepRange(decoded)
+	return nil
+}
+
+// parse reads the "min..max step s" form; without a step only min and max are used
+func (r *SweepRange) parse(text string) error {
+	bounds, step, hasStep := strings.Cut(text, "step")
+	low, high, ok := strings.Cut(bounds, "..")
+	if !ok {
+		return fmt.Errorf("invalid sweep range %q: expected \"min..max step s\"", text)
+	}
+
+	var parsed SweepRange
+	var err error
+	if parsed.Min, err = strconv.ParseFloat(strings.TrimSpace(low), 64); err != nil {
+		return fmt.Errorf("invalid sweep range %q: %w", text, err)
+	}
+	if parsed.Max, err = strconv.ParseFloat(strings.TrimSpace(high), 64); err != nil {
+		return fmt.Errorf("invalid sweep range %q: %w", text, err)
+	}
+	if hasStep {
+		if parsed.Step, err = strconv.ParseFloat(strings.TrimSpace(step), 64); err != nil {
+			return fmt.Errorf("invalid sweep range %q: %w", text, err)
+		}
+	}
+	*r = parsed
+	return nil
+}
+
+// SweepReport summarizes how a sweep's configurations scored across parameter space
+type SweepReport struct {
+	Metric     string             `json:"metric"` // Comparison score the points are ranked by
+	Points     []SweepPoint       `json:"points"` // Best first
+	Parameters []ParameterSummary `json:"parameters"`
+}
+
+// SweepPoint is one configuration of a sweep and its score
+type SweepPoint struct {
+	VariationName string   `json:"variationName"`
+	Temperature   *float32 `json:"temperature,omitempty"`
+	TopP          *float32 `json:"topP,omitempty"`
+	TopK          *int32   `json:"topK,omitempty"`
+	Score         float64  `json:"score"`
+}
+
+// ParameterSummary is the mean score of each value a swept parameter took, and the region where the best points lie
+type ParameterSummary struct {
+	Parameter string            `json:"parameter"` // temperature, topP or topK
+	Values    []SweepValueScore `json:"values"`    // Ascending by value
+	BestValue float64           `json:"bestValue"` // Value with the highest mean score
+	RegionMin float64           `json:"regionMin"` // Range spanned by the top quarter of points
+	RegionMax float64           `json:"regionMax"`
+}
+
+// SweepValueScore is the mean score of the points where a parameter took one value
+type SweepValueScore struct {
+	Value     float64 `json:"value"`
+	MeanScore float64 `json:"meanScore"`
+	Points    int     `json:"points"`
+}
+
+// Reasons an optimization stopped
+const (
+	OptimizationStoppedRounds    = "rounds"    // Every round ran
+	OptimizationStoppedConverged = "converged" // A round did not improve the best score by the tolerance
+	OptimizationStoppedExhausted = "exhausted" // Every point of the search space was tried
+)
+
+// OptimizationConfig continues a run spec's sweep with rounds of configurations proposed from the scores so far
+type OptimizationConfig struct {
+	Rounds      int     `json:"rounds,omitempty"`      // Rounds after the initial sweep (default 3)
+	BatchSize   int     `json:"batchSize,omitempty"`   // Configurations proposed per round (default 4)
+	Exploration float64 `json:"exploration,omitempty"` // Weight of uncertainty against predicted score (default 1)
+	Tolerance   float64 `json:"tolerance,omitempty"`   // Smallest improvement that counts (default 0.005)
+	Seed        int64   `json:"seed,omitempty"`        // 0 picks one, which is recorded on the result
+}
+
+// OptimizationResult is every round of an optimization and the best configuration it found
+type OptimizationResult struct {
+	Metric            string              `json:"metric"`
+	Seed              int64               `json:"seed"`
+	Rounds            []OptimizationRound `json:"rounds"`
+	Best              SweepPoint          `json:"best"`
+	BestConfiguration APIConfiguration    `json:"bestConfiguration"`
+	StopReason        string              `json:"stopReason"`
+}
+
+// OptimizationRound is one execution run of an optimization
+type OptimizationRound struct {
+	Round          int          `json:"round"` // 0 is the initial sweep
+	ExecutionRunID string       `json:"executionRunId"`
+	Points         []SweepPoint `json:"points"` // Best first
+	BestScore      float64      `json:"bestScore"`
+}
+
+// RunSpecVersion is the current version of the run spec format
+const RunSpecVersion = 1
+
+// RunSpec declares an execution run: its prompt, configurations, tools and comparison. Specs are
+// written as YAML or JSON, and the resolved spec of every run is stored so it can be reproduced.
+type RunSpec struct {
+	Version      int      `json:"version"`
+	Name         string   `json:"name,omitempty"`
+	NameTemplate string   `json:"nameTemplate,omitempty"`
+	Description  string   `json:"description,omitempty"`
+	Suite        string   `json:"suite,omitempty"`
+	GitSHA       string   `json:"gitSha,omitempty"`
+	Tags         []string `json:"tags,omitempty"`
+
+	Prompt         string              `json:"prompt"`
+	Context        string              `json:"context,omitempty"`
+	CacheContext   bool                `json:"cacheContext,omitempty"` // Send Gemini variations the context through a cache entry
+	Configurations []SpecConfiguration `json:"configurations"`
+
+	// Function calling is enabled when the spec declares tools
+	Tools                      []Tool        `json:"tools,omitempty"`
+	FunctionInstruction        string        `json:"functionInstruction,omitempty"`
+	DisableFunctionInstruction bool          `json:"disableFunctionInstruction,omitempty"`
+	SafetyPolicy               *SafetyPolicy `json:"safetyPolicy,omitempty"`
+
+	Comparison      *ComparisonConfig `json:"comparison,omitempty"`
+	Deterministic   bool              `json:"deterministic,omitempty"`
+	Seed            *int32            `json:"seed,omitempty"`
+	Repetitions     int               `json:"repetitions,omitempty"`
+	ExpectedAnswer  *ExpectedAnswer   `json:"expectedAnswer,omitempty"`
+	DuplicatePolicy string            `json:"duplicatePolicy,omitempty"`
+
+	// Expands each configuration over a grid or random search; resolved specs list the expanded configurations instead
+	Sweep *ParameterSweep `json:"sweep,omitempty"`
+
+	// Continues the sweep with proposed configurations; run with the CLI's optimize command
+	Optimize *OptimizationConfig `json:"optimize,omitempty"`
+}
+
+// SpecConfiguration is one variation of a run spec
+type SpecConfiguration struct {
+	Name               string   `json:"name"`
+	Preset             string   `json:"preset,omitempty"` // Configuration preset ID; fields set here override the preset's
+	Model              string   `json:"model"`
+	SystemPrompt       string   `json:"systemPrompt,omitempty"`
+	InlineSystemPrompt bool     `json:"inlineSystemPrompt,omitempty"`
+	Temperature        *float32 `json:"temperature,omitempty"`
+	MaxTokens          *int32   `json:"maxTokens,omitempty"`
+	TopP               *float32 `json:"topP,omitempty"`
+	TopK               *int32   `json:"topK,omitempty"`
+	ThinkingBudget     *int32   `json:"thinkingBudget,omitempty"` // -1 lets the model decide, 0 turns thinking off
+	CandidateCount     *int32   `json:"candidateCount,omitempty"` // Responses generated per request, each compared as a sub-variation
+
+	Tools                      []string      `json:"tools,omitempty"` // Subset of the spec's tools to expose (empty = all)
+	DisableTools               bool          `json:"disableTools,omitempty"`
+	FunctionInstruction        string        `json:"functionInstruction,omitempty"`
+	DisableFunctionInstruction bool          `json:"disableFunctionInstruction,omitempty"`
+	SafetyPolicy               *SafetyPolicy `json:"safetyPolicy,omitempty"`
+
+	ResponseMimeType string                 `json:"responseMimeType,omitempty"`
+	ResponseSchema   map[string]interface{} `json:"responseSchema,omitempty"`
+
+	TimeoutMs int32  `json:"timeoutMs,omitempty"` // How long the variation may run; 0 uses the client's timeout
+	Backend   string `json:"backend,omitempty"`   // gemini (default) or vertex
+	Provider  string `json:"provider,omitempty"`  // e.g. ollama for local models; empty infers it from the model name
+
+	Retrieval  *RetrievalConfig `json:"retrieval,omitempty"`
+	Guardrails []GuardConfig    `json:"guardrails,omitempty"`
+
+	ToolChoiceMode       string   `json:"toolChoiceMode,omitempty"` // AUTO, ANY (default) or NONE
+	AllowedFunctionNames []string `json:"allowedFunctionNames,omitempty"`
 }
 
 // ComparisonConfig represents configuration for comparing execution results
 type ComparisonConfig struct {
-	Enabled     bool     `json:"enabled"`
-	Metrics     []string `json:"metrics"`
-	CustomRules []string `json:"customRules,omitempty"`
+	Enabled             bool                       `json:"enabled"`
+	Metrics             []string                   `json:"metrics"`
+	CustomRules         []string                   `json:"customRules,omitempty"`
+	ToolAppropriateness *ToolAppropriatenessConfig `json:"toolAppropriateness,omitempty"`
+	Judge               *JudgeConfig               `json:"judge,omitempty"`
+}
+
+// JudgeConfig configures the model that grades responses during comparison
+type JudgeConfig struct {
+	Model        string  `json:"model,omitempty"`        // Judge model; defaults to gemini-1.5-flash
+	Criteria     string  `json:"criteria,omitempty"`     // What the judge grades; defaults to accuracy, relevance and clarity
+	MaxCostUSD   float64 `json:"maxCostUsd,omitempty"`   // Estimated judge spend allowed per run; 0 means unlimited
+	Weight       float64 `json:"weight,omitempty"`       // Share of the overall score taken by the judge score; defaults to 0.5
+	DisableCache bool    `json:"disableCache,omitempty"` // Always call the judge instead of reusing cached judgments
+}
+
+// Judgment is a judge model's grade of a single response
+type Judgment struct {
+	ResponseHash string    `json:"responseHash"`
+	JudgeModel   string    `json:"judgeModel"`
+	Score        float64   `json:"score"` // 0-1
+	Rationale    string    `json:"rationale,omitempty"`
+	CostUSD      float64   `json:"costUsd"`
+	Cached       bool      `json:"cached"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// ToolAppropriatenessConfig controls how tool usage is judged during comparison
+type ToolAppropriatenessConfig struct {
+	ExpectToolUse *bool    `json:"expectToolUse,omitempty"` // Explicit expectation; overrides keyword heuristics
+	ToolKeywords  []string `json:"toolKeywords,omitempty"`  // Prompt keywords that imply a tool is needed
+}
+
+// ToolUsageOutcome classifies whether a variation used tools appropriately
+type ToolUsageOutcome string
+
+const (
+	ToolUsageNotAvailable      ToolUsageOutcome = "tools_not_available"
+	ToolUsageAppropriateCall   ToolUsageOutcome = "appropriate_call"
+	ToolUsageUnnecessaryCall   ToolUsageOutcome = "unnecessary_call"
+	ToolUsageMissedCall        ToolUsageOutcome = "missed_call"
+	ToolUsageAppropriateNoCall ToolUsageOutcome = "appropriate_no_call"
+)
+
+// ExecutionProgress is how far a run's variations have got, reported as each one finishes
+type ExecutionProgress struct {
+	ExecutionRunID string `json:"executionRunId"`
+	Total          int    `json:"total"`     // Variations to execute, counting each repetition
+	Completed      int    `json:"completed"` // Variations finished, successfully or not
+	Failed         int    `json:"failed"`
+	Variation      string `json:"variation,omitempty"` // Label of the variation that finished last
 }
 
 // ExecutionResult represents the result of a multi-execution
@@ -278,6 +864,67 @@ type ExecutionResult struct {
 	SuccessCount int               `json:"successCount"`
 	ErrorCount   int               `json:"errorCount"`
 	Logs         []ExecutionLog    `json:"logs,omitempty"`
+
+	// SLO compliance, set when the run's suite has an SLO
+	SLO *SLOResult `json:"slo,omitempty"`
+
+	// Golden-answer scoring, set when the request had an expected answer
+	Evaluations []EvaluationResult      `json:"evaluations,omitempty"`
+	Accuracy    []ConfigurationAccuracy `json:"accuracy,omitempty"`
+
+	// Scores across parameter space, set when the run came from a parameter sweep
+	SweepReport *SweepReport `json:"sweepReport,omitempty"`
+
+	// The provider request of each configuration, set instead of results by a dry run
+	Payloads []ProviderPayload `json:"payloads,omitempty"`
+}
+
+// Sources of an estimate's input token counts
+const (
+	TokenCountProvider = "countTokens" // Counted by the provider's countTokens endpoint
+	TokenCountLocal    = "local"       // Estimated locally at ~4 characters per token
+)
+
+// ExecutionEstimate is a preflight estimate of what a request would cost, made before any run is
+// created. Costs are upper bounds: every call is assumed to generate its maximum output.
+type ExecutionEstimate struct {
+	Variations      []VariationEstimate `json:"variations"`
+	InputTokens     int                 `json:"inputTokens"`     // Over every variation and repetition
+	MaxOutputTokens int                 `json:"maxOutputTokens"` // Over every variation and repetition
+	MaxCostUSD      float64             `json:"maxCostUsd"`
+	Warnings        []string            `json:"warnings,omitempty"` // Request-wide, such as model limits going unchecked
+}
+
+// VariationEstimate is the estimate of one configuration, per call unless stated otherwise
+type VariationEstimate struct {
+	VariationName    string   `json:"variationName"`
+	Provider         string   `json:"provider"`
+	ModelName        string   `json:"modelName"`
+	InputTokens      int      `json:"inputTokens"`
+	TokenSource      string   `json:"tokenSource"`     // countTokens or local
+	MaxOutputTokens  int      `json:"maxOutputTokens"` // maxTokens, or the model's output limit when unset
+	InputTokenLimit  int32    `json:"inputTokenLimit,omitempty"`
+	OutputTokenLimit int32    `json:"outputTokenLimit,omitempty"`
+	Repetitions      int      `json:"repetitions"`
+	MaxCostUSD       float64  `json:"maxCostUsd"` // Over every repetition; 0 for models without a list price
+	Warnings         []string `json:"warnings,omitempty"`
+}
+
+// ProviderPayload is the request a configuration would send its provider, as built by a dry run
+type ProviderPayload struct {
+	Configuration APIConfiguration `json:"configuration"` // With the run-wide settings applied
+	Provider      string           `json:"provider"`
+	ModelName     string           `json:"modelName"`
+	FinalPrompt   string           `json:"finalPrompt"`    // The user turn, after the instructions joined it
+	Body          json.RawMessage  `json:"body,omitempty"` // Request body of the first call; unset when blocked
+
+	// Input guard findings; a blocked prompt is never sent, so it has no body
+	GuardVerdicts []GuardVerdict `json:"guardVerdicts,omitempty"`
+	Blocked       bool           `json:"blocked,omitempty"`
+
+	// Set when the configuration retrieves document chunks: embedding the prompt calls the provider,
+	// so a dry run leaves the chunks out of the context
+	RetrievalSkipped bool `json:"retrievalSkipped,omitempty"`
 }
 
 // VariationResult represents the result of a single variation execution
@@ -286,7 +933,14 @@ type VariationResult struct {
 	Request       APIRequest       `json:"request"`
 	Response      APIResponse      `json:"response"`
 	FunctionCalls []FunctionCall   `json:"functionCalls,omitempty"`
-	ExecutionTime int64            `json:"executionTime"` // milliseconds
+	ExecutionTime int64            `json:"executionTime"`        // milliseconds
+	Repetition    int              `json:"repetition,omitempty"` // 1-based sample number in repeated runs
+
+	// Document chunks added to the request's context, best match first
+	RetrievedChunks []RetrievedChunk `json:"retrievedChunks,omitempty"`
+
+	// Verdicts of the configuration's guards, input guards first
+	GuardVerdicts []GuardVerdict `json:"guardVerdicts,omitempty"`
 }
 
 // ComparisonResult represents the result of comparing multiple variations
@@ -301,6 +955,815 @@ type ComparisonResult struct {
 	AllConfigurations   []APIConfiguration     `json:"allConfigurations,omitempty"`
 	AnalysisNotes       string                 `json:"analysisNotes,omitempty"`
 	CreatedAt           time.Time              `json:"createdAt"`
+
+	// Pairwise tests between configurations, set when configurations were repeated
+	SignificanceTests []SignificanceTest `json:"significanceTests,omitempty"`
+}
+
+// MetricStatistics summarizes one metric across a configuration's repeated samples
+type MetricStatistics struct {
+	Samples int     `json:"samples"`
+	Mean    float64 `json:"mean"`
+	StdDev  float64 `json:"stdDev"`  // Sample standard deviation
+	CILower float64 `json:"ciLower"` // 95% confidence interval of the mean
+	CIUpper float64 `json:"ciUpper"`
+}
+
+// SignificanceTest is a Welch's t-test of one metric between two configurations
+type SignificanceTest struct {
+	Metric           string  `json:"metric"`
+	ConfigurationA   string  `json:"configurationA"` // Variation names
+	ConfigurationB   string  `json:"configurationB"`
+	MeanDifference   float64 `json:"meanDifference"` // Mean of A minus mean of B
+	TStatistic       float64 `json:"tStatistic"`
+	DegreesOfFreedom float64 `json:"degreesOfFreedom"`
+	PValue           float64 `json:"pValue"`
+	Significant      bool    `json:"significant"` // PValue below 0.05
+}
+
+// DiffOp is how a diff segment turns the first response into the second
+type DiffOp string
+
+const (
+	DiffOpEqual  DiffOp = "equal"
+	DiffOpInsert DiffOp = "insert" // Only in the second response
+	DiffOpDelete DiffOp = "delete" // Only in the first response
+)
+
+// DiffSegment is a run of text kept, inserted or deleted
+type DiffSegment struct {
+	Op   DiffOp `json:"op"`
+	Text string `json:"text"`
+}
+
+// MetricDelta compares one metric of two variations
+type MetricDelta struct {
+	Metric string  `json:"metric"`
+	A      float64 `json:"a"`
+	B      float64 `json:"b"`
+	Delta  float64 `json:"delta"` // B minus A
+}
+
+// VariationDiff compares the responses of two configurations of a run. Joining the equal and
+// delete segments gives the first response, the equal and insert segments the second.
+type VariationDiff struct {
+	ExecutionRunID string           `json:"executionRunId"`
+	A              APIConfiguration `json:"a"`
+	B              APIConfiguration `json:"b"`
+	Segments       []DiffSegment    `json:"segments"`
+	WordsAdded     int              `json:"wordsAdded"`
+	WordsRemoved   int              `json:"wordsRemoved"`
+	Similarity     float64          `json:"similarity"` // Share of words the responses have in common, 0 to 1
+	Metrics        []MetricDelta    `json:"metrics"`
+}
+
+// TrendQuery selects the configurations a trend follows across runs: those created from a preset,
+// or those with a variation name
+type TrendQuery struct {
+	PresetID      string    `json:"presetId,omitempty"`
+	VariationName string    `json:"variationName,omitempty"`
+	Since         time.Time `json:"since,omitempty"` // Zero for no lower bound
+	Limit         int       `json:"limit,omitempty"` // Most recent runs to include; 0 for the default of 30
+}
+
+// TrendPoint is one configuration's results in one run
+type TrendPoint struct {
+	ExecutionRunID    string    `json:"executionRunId"`
+	RunName           string    `json:"runName"`
+	ConfigurationID   string    `json:"configurationId"`
+	VariationName     string    `json:"variationName"`
+	ModelName         string    `json:"modelName"`
+	RunAt             time.Time `json:"runAt"`
+	OverallScore      *float64  `json:"overallScore,omitempty"` // Unset when the run has no comparison
+	AvgResponseTimeMs float64   `json:"avgResponseTimeMs"`
+	CostUSD           float64   `json:"costUsd"` // Estimated cost of all the configuration's samples
+	Samples           int       `json:"samples"`
+	Errors            int       `json:"errors"`
+}
+
+// MetricDrift compares a trend's latest value of a metric with the runs before it
+type MetricDrift struct {
+	Metric         string  `json:"metric"`
+	BaselineRuns   int     `json:"baselineRuns"`
+	BaselineMean   float64 `json:"baselineMean"`
+	BaselineStdDev float64 `json:"baselineStdDev"`
+	Latest         float64 `json:"latest"`
+	Change         float64 `json:"change"`  // Latest minus the baseline mean
+	Drifted        bool    `json:"drifted"` // Latest is more than 2 standard deviations from the baseline mean
+}
+
+// ConfigurationTrend is a configuration's results across runs, oldest first
+type ConfigurationTrend struct {
+	PresetID      string        `json:"presetId,omitempty"`
+	VariationName string        `json:"variationName,omitempty"`
+	Points        []TrendPoint  `json:"points"`
+	Drift         []MetricDrift `json:"drift,omitempty"` // Set once there are at least 3 earlier runs to compare with
+}
+
+// Thumbs a person can give a response
+const (
+	FeedbackThumbsUp   = "up"
+	FeedbackThumbsDown = "down"
+)
+
+// ResponseFeedback is a person's verdict on one variation response: thumbs up or down, a 1-5
+// rating and a comment, any of which may be left out
+type ResponseFeedback struct {
+	ResponseID      string    `json:"responseId"`
+	ExecutionRunID  string    `json:"executionRunId"`
+	ConfigurationID string    `json:"configurationId"`
+	Thumbs          string    `json:"thumbs,omitempty"` // up or down
+	Rating          *int      `json:"rating,omitempty"` // 1 (worst) to 5 (best)
+	Comment         string    `json:"comment,omitempty"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+// ConfigurationFeedback aggregates the feedback on one configuration's responses
+type ConfigurationFeedback struct {
+	ConfigurationID string   `json:"configurationId"`
+	VariationName   string   `json:"variationName"`
+	Responses       int      `json:"responses"` // Responses with feedback
+	ThumbsUp        int      `json:"thumbsUp"`
+	ThumbsDown      int      `json:"thumbsDown"`
+	Ratings         int      `json:"ratings"`
+	AverageRating   *float64 `json:"averageRating,omitempty"`
+	Comments        int      `json:"comments"`
+	Score           *float64 `json:"score,omitempty"` // 0-1 over thumbs and ratings; unset with comments only
+}
+
+// RunFeedback is the feedback on a run's responses along with its aggregate per configuration
+type RunFeedback struct {
+	ExecutionRunID string                  `json:"executionRunId"`
+	Feedback       []ResponseFeedback      `json:"feedback"`
+	Configurations []ConfigurationFeedback `json:"configurations"`
+}
+
+// Blind review modes
+const (
+	ReviewModeRank  = "rank"  // Reviewers order every response, 1 being best
+	ReviewModeLabel = "label" // Reviewers give every response one of the review's labels
+)
+
+// Blind review statuses
+const (
+	ReviewStatusOpen      = "open"
+	ReviewStatusCompleted = "completed" // Every reviewer submitted
+	ReviewStatusClosed    = "closed"    // Closed by its owner before every reviewer submitted
+)
+
+// ReviewRequest starts a blind review of a run's responses
+type ReviewRequest struct {
+	Mode      string   `json:"mode,omitempty"`   // rank (default) or label
+	Labels    []string `json:"labels,omitempty"` // Label mode: the labels reviewers choose from, best first
+	Reviewers []string `json:"reviewers"`        // Usernames of the reviewers
+}
+
+// Review is a blind review of a run's responses and its progress
+type Review struct {
+	ID             string             `json:"id"`
+	ExecutionRunID string             `json:"executionRunId"`
+	Mode           string             `json:"mode"`
+	Labels         []string           `json:"labels,omitempty"`
+	Status         string             `json:"status"`
+	Responses      int                `json:"responses"` // Responses under review, one per configuration
+	Assignments    []ReviewAssignment `json:"assignments"`
+	Submitted      int                `json:"submitted"` // Reviewers who submitted their judgments
+	CreatedAt      time.Time          `json:"createdAt"`
+	ClosedAt       *time.Time         `json:"closedAt,omitempty"`
+}
+
+// ReviewAssignment is one reviewer's part in a review
+type ReviewAssignment struct {
+	Reviewer    string     `json:"reviewer"` // Username
+	SubmittedAt *time.Time `json:"submittedAt,omitempty"`
+}
+
+// ReviewItem is a response under review as reviewers see it, without its configuration
+type ReviewItem struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+}
+
+// ReviewJudgment is a reviewer's rank or label of one item
+type ReviewJudgment struct {
+	ItemID string `json:"itemId"`
+	Rank   int    `json:"rank,omitempty"`  // Rank mode: 1 is best
+	Label  string `json:"label,omitempty"` // Label mode
+}
+
+// ReviewTask is what a reviewer sees of a review: the prompt and the responses, in an order of
+// their own, along with the judgments they submitted
+type ReviewTask struct {
+	ReviewID  string           `json:"reviewId"`
+	Mode      string           `json:"mode"`
+	Labels    []string         `json:"labels,omitempty"`
+	Status    string           `json:"status"`
+	Prompt    string           `json:"prompt"`
+	Items     []ReviewItem     `json:"items"`
+	Judgments []ReviewJudgment `json:"judgments,omitempty"`
+}
+
+// ReviewItemResult is how reviewers judged one response, with its configuration revealed
+type ReviewItemResult struct {
+	ItemID          string         `json:"itemId"`
+	ResponseID      string         `json:"responseId"`
+	ConfigurationID string         `json:"configurationId"`
+	VariationName   string         `json:"variationName"`
+	ModelName       string         `json:"modelName"`
+	Judgments       int            `json:"judgments"`
+	MeanRank        float64        `json:"meanRank,omitempty"`    // Rank mode
+	LabelCounts     map[string]int `json:"labelCounts,omitempty"` // Label mode
+	Score           float64        `json:"score"`                 // 0-1, 1 when every reviewer ranked it first or gave it the best label
+}
+
+// ReviewResults reveals the configurations behind a finished review's responses, best first
+type ReviewResults struct {
+	Review          Review             `json:"review"`
+	Items           []ReviewItemResult `json:"items"`
+	Winner          *ReviewItemResult  `json:"winner,omitempty"`          // Unset when reviewers submitted nothing or the best items tie
+	Agreement       *float64           `json:"agreement,omitempty"`       // Set once 2 reviewers submitted
+	AgreementMetric string             `json:"agreementMetric,omitempty"` // kendall_w for ranks, fleiss_kappa for labels
+}
+
+// Workspace member roles, from most to least privileged
+const (
+	WorkspaceRoleOwner  = "owner"  // Manages members and removes anything shared
+	WorkspaceRoleEditor = "editor" // Shares their own resources with the workspace
+	WorkspaceRoleViewer = "viewer" // Reads what the workspace shares
+)
+
+// Resources that can be shared with a workspace
+const (
+	SharedResourceExecutionRun = "execution_run"
+	SharedResourceFunction     = "function_definition"
+	SharedResourcePreset       = "configuration_preset"
+	SharedResourcePrompt       = "prompt"
+)
+
+// Workspace is a team whose members share execution runs, functions, presets and prompts
+type Workspace struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	Role      string            `json:"role"` // The caller's role
+	Members   []WorkspaceMember `json:"members,omitempty"`
+	CreatedBy string            `json:"createdBy"`
+	CreatedAt time.Time         `json:"createdAt"`
+}
+
+// WorkspaceMember is a user's membership of a workspace
+type WorkspaceMember struct {
+	UserID   string    `json:"userId"`
+	Username string    `json:"username"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joinedAt"`
+}
+
+// SharedResource is a resource its owner shared with a workspace
+type SharedResource struct {
+	WorkspaceID  string    `json:"workspaceId"`
+	ResourceType string    `json:"resourceType"`
+	ResourceID   string    `json:"resourceId"`
+	Name         string    `json:"name"`
+	SharedBy     string    `json:"sharedBy"` // Username of the owner
+	SharedAt     time.Time `json:"sharedAt"`
+}
+
+// Usage report groupings
+const (
+	UsageGroupByDay   = "day"
+	UsageGroupByModel = "model"
+)
+
+// UsageQuery selects the responses a usage report covers
+type UsageQuery struct {
+	From    time.Time `json:"from"`              // Inclusive; zero for 30 days before To
+	To      time.Time `json:"to"`                // Exclusive; zero for now
+	GroupBy []string  `json:"groupBy,omitempty"` // day and/or model; empty for day
+}
+
+// UsageRow is the token usage of one group of responses
+type UsageRow struct {
+	Day              string  `json:"day,omitempty"`       // YYYY-MM-DD, when grouped by day
+	ModelName        string  `json:"modelName,omitempty"` // When grouped by model
+	Responses        int     `json:"responses"`
+	PromptTokens     int64   `json:"promptTokens"`
+	CompletionTokens int64   `json:"completionTokens"`
+	TotalTokens      int64   `json:"totalTokens"`
+	CostUSD          float64 `json:"costUsd"` // Estimated from the models' list prices
+}
+
+// UsageReport is a user's token usage over a time range
+type UsageReport struct {
+	From    time.Time  `json:"from"`
+	To      time.Time  `json:"to"`
+	GroupBy []string   `json:"groupBy"`
+	Rows    []UsageRow `json:"rows"` // By day, then model
+	Totals  UsageRow   `json:"totals"`
+}
+
+// DailyRuns counts the execution runs created on one day
+type DailyRuns struct {
+	Day    string `json:"day"` // YYYY-MM-DD
+	Runs   int    `json:"runs"`
+	Failed int    `json:"failed"`
+}
+
+// ProviderErrorRate is the share of a model provider's responses that were errors
+type ProviderErrorRate struct {
+	Provider  string  `json:"provider"` // unknown when the configuration's provider was not recorded
+	Responses int     `json:"responses"`
+	Errors    int     `json:"errors"`
+	ErrorRate float64 `json:"errorRate"` // 0-1
+}
+
+// TokenConsumer is a user's token usage, for ranking the heaviest users
+type TokenConsumer struct {
+	UserID      string `json:"userId"`
+	Username    string `json:"username,omitempty"`
+	Responses   int    `json:"responses"`
+	TotalTokens int64  `json:"totalTokens"`
+}
+
+// TableSize is the storage one database table takes, as estimated by the database
+type TableSize struct {
+	Name       string `json:"name"`
+	Rows       int64  `json:"rows"` // Estimated
+	DataBytes  int64  `json:"dataBytes"`
+	IndexBytes int64  `json:"indexBytes"`
+}
+
+// ServerStats are figures across all users for server operators, covering the days since From
+type ServerStats struct {
+	From              time.Time           `json:"from"`
+	TotalUsers        int                 `json:"totalUsers"`
+	RunsPerDay        []DailyRuns         `json:"runsPerDay"`         // Oldest first; days without runs are omitted
+	ProviderErrors    []ProviderErrorRate `json:"providerErrorRates"` // Highest error rate first
+	TopTokenConsumers []TokenConsumer     `json:"topTokenConsumers"`  // Most tokens first
+	TableSizes        []TableSize         `json:"tableSizes"`         // Largest first; empty when the database doesn't report sizes
+}
+
+// Audited actions
+const (
+	AuditLogin           = "login"
+	AuditLoginFailed     = "login_failed"
+	AuditAPIKeyCreated   = "api_key_created"
+	AuditAPIKeyRevoked   = "api_key_revoked"
+	AuditFunctionCreated = "function_created"
+	AuditFunctionUpdated = "function_updated"
+	AuditFunctionDeleted = "function_deleted"
+	AuditRunDeleted      = "run_deleted"
+	AuditExport          = "export"
+	AuditReportSaved     = "report_saved"
+	AuditReportDeleted   = "report_deleted"
+)
+
+// AuditEvent is a security-relevant action recorded in the audit log
+type AuditEvent struct {
+	ID         string                 `json:"id"`
+	ActorID    string                 `json:"actorId,omitempty"` // Empty when the actor is unknown, e.g. a failed login
+	Action     string                 `json:"action"`
+	TargetType string                 `json:"targetType,omitempty"` // e.g. function, execution_run, api_key
+	TargetID   string                 `json:"targetId,omitempty"`
+	IPAddress  string                 `json:"ipAddress,omitempty"`
+	Summary    map[string]interface{} `json:"summary,omitempty"` // What changed; never secrets or payload bodies
+	CreatedAt  time.Time              `json:"createdAt"`
+}
+
+// AuditLogQuery filters the audit log; zero fields match everything
+type AuditLogQuery struct {
+	ActorID string    `json:"actorId,omitempty"`
+	Action  string    `json:"action,omitempty"`
+	Since   time.Time `json:"since,omitempty"` // Inclusive
+	Until   time.Time `json:"until,omitempty"` // Exclusive
+	Limit   int32     `json:"limit,omitempty"`
+	Offset  int32     `json:"offset,omitempty"`
+}
+
+// Health states, from best to worst. A report takes the worst state of its checks.
+const (
+	HealthHealthy   = "healthy"   // Working normally
+	HealthDegraded  = "degraded"  // Serving, but slow or with an optional dependency down
+	HealthUnhealthy = "unhealthy" // A dependency every request needs is down
+)
+
+// HealthCheck is the outcome of checking one dependency
+type HealthCheck struct {
+	Name      string                 `json:"name"`
+	Status    string                 `json:"status"`
+	LatencyMs int64                  `json:"latencyMs"`
+	Message   string                 `json:"message,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	CheckedAt time.Time              `json:"checkedAt"` // Earlier than the report for checks served from cache
+}
+
+// HealthReport is the state of the service and of each dependency checked
+type HealthReport struct {
+	Status    string        `json:"status"`
+	Version   string        `json:"version"`
+	Timestamp time.Time     `json:"timestamp"`
+	Checks    []HealthCheck `json:"checks"`
+}
+
+// LeaderboardQuery selects the tagged runs a leaderboard ranks configurations across
+type LeaderboardQuery struct {
+	Tag        string `json:"tag"`
+	MinSamples int    `json:"minSamples,omitempty"` // Configurations scored in fewer runs are left out; 0 for 1
+	Limit      int    `json:"limit,omitempty"`      // Configurations to return; 0 for the default of 10
+}
+
+// LeaderboardEntry is one configuration's overall score across a tag's runs. Each sample is the
+// configuration's overall score in one run's latest comparison.
+type LeaderboardEntry struct {
+	Rank          int              `json:"rank"`
+	VariationName string           `json:"variationName"`
+	ModelName     string           `json:"modelName"`
+	Score         MetricStatistics `json:"score"`
+}
+
+// Leaderboard ranks configurations by their average overall score, best first
+type Leaderboard struct {
+	Tag     string             `json:"tag"`
+	Runs    int                `json:"runs"` // Tagged runs with a comparison
+	Entries []LeaderboardEntry `json:"entries"`
+}
+
+// Normalized safety categories
+const (
+	SafetyCategoryHarassment       = "harassment"
+	SafetyCategoryHateSpeech       = "hate_speech"
+	SafetyCategorySexuallyExplicit = "sexually_explicit"
+	SafetyCategoryDangerousContent = "dangerous_content"
+)
+
+// Normalized safety thresholds, from most permissive to most restrictive
+const (
+	SafetyThresholdOff    = "off"          // Never block
+	SafetyThresholdHigh   = "block_high"   // Block only high-probability harm
+	SafetyThresholdMedium = "block_medium" // Block medium-probability harm and above
+	SafetyThresholdLow    = "block_low"    // Block low-probability harm and above
+)
+
+// SafetyPolicy is a provider-agnostic safety posture: a threshold per normalized category.
+// Categories left out use the provider's default behaviour.
+type SafetyPolicy struct {
+	Thresholds map[string]string `json:"thresholds"`
+}
+
+// Duplicate submission policies
+const (
+	DuplicatePolicyAllow  = "allow"  // Start a new run anyway
+	DuplicatePolicyReject = "reject" // Refuse the submission
+	DuplicatePolicyMerge  = "merge"  // Return the existing run instead of starting a new one
+)
+
+// DefaultWorkspaceID identifies the workspace whose settings apply to every user
+const DefaultWorkspaceID = "default"
+
+// WorkspaceSettings holds organization-wide defaults applied when a run omits values.
+// Values set explicitly on a run or configuration always take precedence.
+type WorkspaceSettings struct {
+	ID                    string                 `json:"id"`
+	DefaultModel          string                 `json:"defaultModel,omitempty"`          // Used by configurations without a model
+	DefaultSafetySettings map[string]interface{} `json:"defaultSafetySettings,omitempty"` // Used by configurations without safety settings
+	DefaultMetrics        []string               `json:"defaultMetrics,omitempty"`        // Comparison metrics when a run requests none
+	Retention             RetentionPolicy        `json:"retention"`                       // How long execution history is kept
+	AllowedProviders      []string               `json:"allowedProviders,omitempty"`      // Model providers runs may use (empty = all)
+	RunNameTemplate       string                 `json:"runNameTemplate,omitempty"`       // Name template for runs without a name or template
+	DuplicatePolicy       string                 `json:"duplicatePolicy,omitempty"`       // Duplicate policy for runs that set none
+	DuplicateWindowSecs   int                    `json:"duplicateWindowSecs"`             // How far back duplicates are matched (0 = 10 minutes)
+	Quota                 QuotaLimits            `json:"quota"`                           // Limits applied to each user
+	UpdatedBy             string                 `json:"updatedBy,omitempty"`
+	UpdatedAt             time.Time              `json:"updatedAt"`
+}
+
+// RetentionPolicy is how many days each part of the execution history is kept; 0 keeps it forever.
+// Pruning a run also removes everything recorded for it, whatever the other values.
+type RetentionPolicy struct {
+	RunsDays          int `json:"runsDays"`
+	RequestsDays      int `json:"requestsDays"` // Requests, with their responses, logs and function calls
+	ResponsesDays     int `json:"responsesDays"`
+	LogsDays          int `json:"logsDays"`
+	FunctionCallsDays int `json:"functionCallsDays"`
+}
+
+// UserRetentionPolicy replaces the workspace retention policy for one user's history
+type UserRetentionPolicy struct {
+	UserID    string          `json:"userId"`
+	Policy    RetentionPolicy `json:"policy"`
+	UpdatedBy string          `json:"updatedBy,omitempty"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+// PruneReport counts the rows a prune pass deleted from each table
+type PruneReport struct {
+	Deleted  map[string]int64 `json:"deleted"`
+	Archived int64            `json:"archived,omitempty"` // Rows written to the archive before deletion
+}
+
+// RepairReport counts what a repair of interrupted runs fixed
+type RepairReport struct {
+	RunsFailed int64            `json:"runsFailed"` // Runs left pending or running, now marked failed
+	Deleted    map[string]int64 `json:"deleted"`    // Rows deleted from each table
+}
+
+// IntegrityReport lists the references checked by an integrity audit and the orphaned rows found
+type IntegrityReport struct {
+	Checks   []IntegrityCheck `json:"checks"`
+	Orphans  int64            `json:"orphans"`  // Orphaned rows across every check
+	Repaired bool             `json:"repaired"` // Whether the orphans were repaired
+}
+
+// IntegrityCheck is one reference an integrity audit checked
+type IntegrityCheck struct {
+	Table      string `json:"table"`
+	Column     string `json:"column"`
+	References string `json:"references"` // Table the column holds IDs of
+	Orphans    int64  `json:"orphans"`    // Rows whose reference points at a missing row
+	Action     string `json:"action"`     // How orphans are repaired: delete the row or clear the reference
+}
+
+// RollupStats totals the daily rollups of one user, or of every user
+type RollupStats struct {
+	ExecutionRuns     int64     `json:"totalExecutionRuns"`
+	APIRequests       int64     `json:"totalApiRequests"`
+	APIResponses      int64     `json:"totalApiResponses"`
+	FunctionCalls     int64     `json:"totalFunctionCalls"`
+	AvgResponseTimeMs float64   `json:"avgResponseTime"`
+	SuccessRate       float64   `json:"successRate"`
+	PromptTokens      int64     `json:"promptTokens"`
+	CompletionTokens  int64     `json:"completionTokens"`
+	TotalTokens       int64     `json:"totalTokens"`
+	RefreshedAt       time.Time `json:"refreshedAt"` // Activity after this is not counted yet
+}
+
+// RollupRefresh reports a refresh of the daily rollups
+type RollupRefresh struct {
+	Since       string    `json:"since,omitempty"` // First day recomputed, YYYY-MM-DD; empty for a full rebuild
+	Rows        int       `json:"rows"`            // User-days written
+	RefreshedAt time.Time `json:"refreshedAt"`
+}
+
+// TelemetryReport is the anonymized usage summary an installation that opted in to telemetry sends.
+// It holds server-wide counts only: no prompts, responses, user IDs or unlisted model names.
+type TelemetryReport struct {
+	SchemaVersion int            `json:"schemaVersion"`
+	PeriodStart   time.Time      `json:"periodStart"` // Whole hours, UTC
+	PeriodEnd     time.Time      `json:"periodEnd"`
+	ExecutionRuns int            `json:"executionRuns"`
+	Variations    int            `json:"variations"`
+	Responses     map[string]int `json:"responses"`         // By response status
+	ErrorRate     float64        `json:"errorRate"`         // Share of responses that failed or timed out
+	Models        map[string]int `json:"models"`            // Variations per listed model; the rest are grouped as "<provider>/other"
+	Providers     map[string]int `json:"providers"`         // Variations per provider
+	Epsilon       float64        `json:"epsilon,omitempty"` // Privacy budget of the Laplace noise added to each count; unset for exact counts
+}
+
+// Report parameter types
+const (
+	ReportParameterString = "string"
+	ReportParameterInt    = "int"
+	ReportParameterFloat  = "float"
+	ReportParameterBool   = "bool"
+	ReportParameterDate   = "date" // YYYY-MM-DD or RFC 3339
+)
+
+// SavedReport is a named read-only SQL query admins define for users to run. The query names its
+// parameters as :name; :user_id is always the user running the report.
+type SavedReport struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"` // Lower-case letters, digits, dashes and underscores
+	Description string            `json:"description,omitempty"`
+	Query       string            `json:"query"`
+	Parameters  []ReportParameter `json:"parameters,omitempty"`
+	CreatedBy   string            `json:"createdBy,omitempty"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	UpdatedAt   time.Time         `json:"updatedAt"`
+}
+
+// ReportParameter is a value a report's query takes from the request running it
+type ReportParameter struct {
+	Name        string `json:"name"`
+	Type        string `json:"type,omitempty"` // One of the report parameter types; string when empty
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Default     string `json:"default,omitempty"` // Used when the request omits the parameter; NULL when empty
+}
+
+// ReportResult is the rows a report returned
+type ReportResult struct {
+	Report    string          `json:"report"`
+	Columns   []string        `json:"columns"`
+	Rows      [][]interface{} `json:"rows"`
+	RowCount  int             `json:"rowCount"`
+	Truncated bool            `json:"truncated"` // More rows matched than a report returns
+}
+
+// RestoreReport counts the rows an archived run restored to each table
+type RestoreReport struct {
+	RunID    string           `json:"runId"`
+	Restored map[string]int64 `json:"restored"`
+	Skipped  int64            `json:"skipped,omitempty"` // Archived rows already in the database
+}
+
+// QuotaLimits are the limits applied to each user's executions; 0 means unlimited
+type QuotaLimits struct {
+	MaxConcurrentExecutions int   `json:"maxConcurrentExecutions"` // Executions a user may have pending or running
+	MaxVariationsPerRun     int   `json:"maxVariationsPerRun"`     // Configurations a run may execute, after presets and sweeps
+	DailyTokenBudget        int64 `json:"dailyTokenBudget"`        // Tokens a user's responses may use per UTC day
+}
+
+// QuotaStatus is a user's usage against the quota limits
+type QuotaStatus struct {
+	Limits               QuotaLimits `json:"limits"`
+	ConcurrentExecutions int         `json:"concurrentExecutions"`
+	TokensToday          int64       `json:"tokensToday"`
+	ResetsAt             time.Time   `json:"resetsAt"` // When the daily token budget resets
+}
+
+// Model providers
+const (
+	ProviderGemini    = "gemini"
+	ProviderOpenAI    = "openai"
+	ProviderAnthropic = "anthropic"
+	ProviderOllama    = "ollama" // Local models served by Ollama
+)
+
+// Backends Gemini models can be called through
+const (
+	BackendGeminiAPI = "gemini" // The public generativelanguage API, authorized with the API key
+	BackendVertex    = "vertex" // Vertex AI in the client's ProjectID and Region, authorized with ADC
+)
+
+// ProviderForModel infers the provider from a model name, returning "" when unknown
+func ProviderForModel(modelName string) string {
+	name := strings.ToLower(modelName)
+	switch {
+	case strings.HasPrefix(name, "gemini"):
+		return ProviderGemini
+	case strings.HasPrefix(name, "gpt"), strings.HasPrefix(name, "o1"), strings.HasPrefix(name, "o3"):
+		return ProviderOpenAI
+	case strings.HasPrefix(name, "claude"):
+		return ProviderAnthropic
+	default:
+		return ""
+	}
+}
+
+// ProviderForConfiguration returns the provider serving a configuration: its Provider, else the one
+// inferred from its model name
+func ProviderForConfiguration(config *APIConfiguration) string {
+	if config.Provider != "" {
+		return config.Provider
+	}
+	return ProviderForModel(config.ModelName)
+}
+
+// Batch run and item statuses
+const (
+	BatchStatusSubmitting = "submitting" // Items are still being streamed in
+	BatchStatusSubmitted  = "submitted"  // All items stored, waiting to run
+	BatchStatusRunning    = "running"
+	BatchStatusCompleted  = "completed"
+	BatchStatusFailed     = "failed"
+
+	BatchItemStatusPending   = "pending"
+	BatchItemStatusCompleted = "completed"
+	BatchItemStatusFailed    = "failed"
+)
+
+// BatchRun runs one execution template over many dataset items, one execution run per item
+type BatchRun struct {
+	ID             string                 `json:"id"`
+	UserID         string                 `json:"userId"`
+	Name           string                 `json:"name"`
+	Template       *MultiExecutionRequest `json:"template"` // Each item's prompt replaces the template's base prompt
+	Status         string                 `json:"status"`
+	TotalItems     int32                  `json:"totalItems"`
+	CompletedItems int32                  `json:"completedItems"`
+	FailedItems    int32                  `json:"failedItems"`
+	ErrorMessage   string                 `json:"errorMessage,omitempty"`
+	CreatedAt      time.Time              `json:"createdAt"`
+	UpdatedAt      time.Time              `json:"updatedAt"`
+
+	// Golden-answer accuracy across the batch's items, by variation name
+	Accuracy []ConfigurationAccuracy `json:"accuracy,omitempty"`
+}
+
+// BatchItem is one dataset item of a batch run
+type BatchItem struct {
+	ID             string            `json:"id"`
+	BatchRunID     string            `json:"batchRunId"`
+	ItemIndex      int32             `json:"itemIndex"`            // Position in the submitted stream
+	ExternalID     string            `json:"externalId,omitempty"` // Caller's identifier for the item
+	Prompt         string            `json:"prompt"`
+	Context        string            `json:"context,omitempty"` // Overrides the template context when set
+	Metadata       map[string]string `json:"metadata,omitempty"`
+	ExpectedAnswer *ExpectedAnswer   `json:"expectedAnswer,omitempty"` // Overrides the template's expected answer when set
+	Status         string            `json:"status"`
+	ExecutionRunID string            `json:"executionRunId,omitempty"`
+	ErrorMessage   string            `json:"errorMessage,omitempty"`
+	CreatedAt      time.Time         `json:"createdAt"`
+}
+
+// Golden-answer match modes
+const (
+	MatchModeExact     = "exact"     // Whole response equals the answer, ignoring case and surrounding whitespace
+	MatchModeSubstring = "substring" // Response contains the answer, ignoring case
+	MatchModeRegex     = "regex"     // Response matches the answer as a regular expression
+	MatchModeEmbedding = "embedding" // Embedding cosine similarity reaches the threshold
+)
+
+// ExpectedAnswer is a golden answer that responses are scored against
+type ExpectedAnswer struct {
+	Answer    string  `json:"answer"`
+	MatchMode string  `json:"matchMode,omitempty"` // exact (default), substring, regex or embedding
+	Threshold float64 `json:"threshold,omitempty"` // Minimum similarity for embedding matches; defaults to 0.8
+}
+
+// EvaluationResult is the pass/fail outcome of scoring one response against its expected answer
+type EvaluationResult struct {
+	ID              string    `json:"id"`
+	ExecutionRunID  string    `json:"executionRunId"`
+	ConfigurationID string    `json:"configurationId"`
+	VariationName   string    `json:"variationName"`
+	Repetition      int       `json:"repetition,omitempty"`
+	MatchMode       string    `json:"matchMode"`
+	ExpectedAnswer  string    `json:"expectedAnswer"`
+	Score           float64   `json:"score"` // 1 or 0, or the cosine similarity for embedding matches
+	Passed          bool      `json:"passed"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+// ConfigurationAccuracy is the share of a configuration's evaluated responses that passed
+type ConfigurationAccuracy struct {
+	VariationName   string  `json:"variationName"`
+	ConfigurationID string  `json:"configurationId,omitempty"` // Empty when aggregated across runs
+	Cases           int     `json:"cases"`
+	Passed          int     `json:"passed"`
+	Accuracy        float64 `json:"accuracy"`
+}
+
+// ConfigurationPreset is a user's saved configuration, reusable across execution runs
+type ConfigurationPreset struct {
+	ID            string           `json:"id"`
+	UserID        string           `json:"userId"`
+	Name          string           `json:"name"`
+	Description   string           `json:"description,omitempty"`
+	Configuration APIConfiguration `json:"configuration"` // Run-specific fields (IDs, seed, replay state) are not stored
+	CreatedAt     time.Time        `json:"createdAt"`
+	UpdatedAt     time.Time        `json:"updatedAt"`
+}
+
+// Prompt is a user's saved prompt, reusable as a run's base or context prompt
+type Prompt struct {
+	ID          string    `json:"id"`
+	UserID      string    `json:"userId"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Content     string    `json:"content"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// SuiteSLO sets latency, cost and success objectives for the runs of a suite. Zero objectives are not checked.
+type SuiteSLO struct {
+	ID             string    `json:"id"`
+	UserID         string    `json:"userId"`
+	Suite          string    `json:"suite"`
+	MaxLatencyMs   int32     `json:"maxLatencyMs,omitempty"`   // Slowest variation response time allowed
+	MaxCostUSD     float64   `json:"maxCostUsd,omitempty"`     // Estimated cost allowed per run
+	MinSuccessRate float64   `json:"minSuccessRate,omitempty"` // Fraction of variations that must succeed (0-1)
+	Target         float64   `json:"target"`                   // Fraction of runs that must comply (default 0.95)
+	WindowHours    int       `json:"windowHours"`              // Rolling window for the burn rate (default 24)
+	AlertBurnRate  float64   `json:"alertBurnRate"`            // Burn rate at which the SLO alerts (default 2)
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+// SLOResult records whether one run of a suite met the suite's objectives
+type SLOResult struct {
+	ExecutionRunID string    `json:"executionRunId"`
+	Suite          string    `json:"suite"`
+	LatencyMs      int32     `json:"latencyMs"`
+	CostUSD        float64   `json:"costUsd"`
+	SuccessRate    float64   `json:"successRate"`
+	LatencyMet     bool      `json:"latencyMet"`
+	CostMet        bool      `json:"costMet"`
+	SuccessMet     bool      `json:"successMet"`
+	Compliant      bool      `json:"compliant"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// SLOStatus is a suite's compliance and error budget burn rate over its rolling window
+type SLOStatus struct {
+	SLO           SuiteSLO  `json:"slo"`
+	WindowStart   time.Time `json:"windowStart"`
+	Runs          int       `json:"runs"`
+	CompliantRuns int       `json:"compliantRuns"`
+	Compliance    float64   `json:"compliance"` // 1 when there are no runs
+	BurnRate      float64   `json:"burnRate"`   // Error budget spend relative to the target; 1 spends it exactly
+	Alerting      bool      `json:"alerting"`   // BurnRate has reached AlertBurnRate
 }
 
 // Additional types for interface support
@@ -318,6 +1781,126 @@ type ModelInfo struct {
 	CreatedAt        time.Time `json:"created_at"`
 }
 
+// EmbeddingCacheStats counts embedding lookups served from the cache and computed by the embedding API
+type EmbeddingCacheStats struct {
+	Hits    int64   `json:"hits"`
+	Misses  int64   `json:"misses"`
+	HitRate float64 `json:"hitRate"`
+}
+
+// ResponseEmbedding is the stored embedding of one variation's response
+type ResponseEmbedding struct {
+	ResponseID      string    `json:"responseId"`
+	ExecutionRunID  string    `json:"executionRunId"`
+	ConfigurationID string    `json:"configurationId"`
+	VariationName   string    `json:"variationName"`
+	Repetition      int       `json:"repetition,omitempty"`
+	Model           string    `json:"model"`
+	Embedding       []float32 `json:"embedding"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+// RetrievalConfig selects the document chunks added to a configuration's context: the TopK chunks
+// of Collection most similar to the prompt, skipping those scoring below MinScore
+type RetrievalConfig struct {
+	Collection     string  `json:"collection"`
+	TopK           int32   `json:"topK,omitempty"`           // Chunks to add (default 3, max 20)
+	MinScore       float64 `json:"minScore,omitempty"`       // Lowest cosine similarity a chunk may score
+	EmbeddingModel string  `json:"embeddingModel,omitempty"` // Model the prompt and chunks are embedded with (default text-embedding-004)
+}
+
+// RetrievalQuery is what a document retriever searches for
+type RetrievalQuery struct {
+	Collection     string
+	Text           string
+	Embedding      []float32
+	EmbeddingModel string
+	TopK           int
+}
+
+// RetrievedChunk is a document chunk retrieved for a request and how well it matched the prompt
+type RetrievedChunk struct {
+	ChunkID      string  `json:"chunkId"`
+	DocumentID   string  `json:"documentId"`
+	DocumentName string  `json:"documentName"`
+	Collection   string  `json:"collection"`
+	ChunkIndex   int     `json:"chunkIndex"` // Position of the chunk in its document
+	Content      string  `json:"content"`
+	Score        float64 `json:"score"`
+	Rank         int     `json:"rank"` // 1-based position in the request's context
+}
+
+// Document is a text indexed as embedded chunks for retrieval
+type Document struct {
+	ID             string    `json:"id"`
+	Collection     string    `json:"collection"`
+	Name           string    `json:"name"`
+	ContentType    string    `json:"contentType"`         // text/plain, text/markdown or application/pdf
+	SizeBytes      int64     `json:"sizeBytes,omitempty"` // Size of the uploaded file
+	ChunkCount     int       `json:"chunkCount"`
+	ChunkSize      int       `json:"chunkSize,omitempty"`      // Characters per chunk the text was split into
+	ChunkOverlap   int       `json:"chunkOverlap,omitempty"`   // Characters consecutive chunks share
+	EmbeddingModel string    `json:"embeddingModel,omitempty"` // Model the chunks were embedded with
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// ChunkingConfig controls how an ingested document's text is split into chunks
+type ChunkingConfig struct {
+	Size    int `json:"size,omitempty"`    // Characters per chunk (default 1000)
+	Overlap int `json:"overlap,omitempty"` // Characters consecutive chunks share (default 200)
+}
+
+// Guard stages
+const (
+	GuardStageInput  = "input"  // Checks the prompt and context before the model call
+	GuardStageOutput = "output" // Checks the response text
+)
+
+// Guard actions
+const (
+	GuardActionBlock    = "block"    // Input only: skip the model call
+	GuardActionSanitize = "sanitize" // Input only: replace the matches before the prompt is sent
+	GuardActionFlag     = "flag"     // Record the verdict and change nothing
+	GuardActionRedact   = "redact"   // Output only: replace the matches in the response
+)
+
+// GuardConfig runs one guard at one stage of a configuration
+type GuardConfig struct {
+	Guard  string   `json:"guard"`            // pii, prompt_injection, banned_topics or a registered guard
+	Stage  string   `json:"stage"`            // input or output
+	Action string   `json:"action,omitempty"` // Input default block; output default flag
+	Topics []string `json:"topics,omitempty"` // Words and phrases the banned_topics guard looks for
+}
+
+// GuardVerdict is what one guard found in a request's prompt or response
+type GuardVerdict struct {
+	Guard     string   `json:"guard"`
+	Stage     string   `json:"stage"`
+	Action    string   `json:"action"`
+	Triggered bool     `json:"triggered"`
+	Findings  []string `json:"findings,omitempty"` // Labels of the matches, e.g. email or ignore_instructions
+}
+
+// FieldError describes one invalid field of a request, e.g. configurations[0].temperature
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// SearchResult is a past response matched by semantic search, with the prompt that produced it
+type SearchResult struct {
+	ExecutionRunID   string    `json:"executionRunId"`
+	ExecutionRunName string    `json:"executionRunName"`
+	ConfigurationID  string    `json:"configurationId"`
+	VariationName    string    `json:"variationName"`
+	ResponseID       string    `json:"responseId"`
+	Prompt           string    `json:"prompt"`
+	ResponseText     string    `json:"responseText"`
+	MatchedOn        string    `json:"matchedOn"` // "prompt" or "response"
+	Score            float64   `json:"score"`
+	CreatedAt        time.Time `json:"createdAt"`
+}
+
 // TimeRange represents a time range for analytics
 type TimeRange struct {
 	StartTime time.Time `json:"start_time"`
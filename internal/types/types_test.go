@@ -176,3 +176,26 @@ func TestAPIConfigurationWithPointers(t *testing.T) {
 		t.Errorf("Expected TopK to be 40, got %v", config.TopK)
 	}
 }
+
+func TestProviderForModel(t *testing.T) {
+	tests := []struct {
+		name  string
+		model string
+		want  string
+	}{
+		{"gemini", "gemini-1.5-flash", ProviderGemini},
+		{"openai", "gpt-4o", ProviderOpenAI},
+		{"openai_reasoning", "o1-mini", ProviderOpenAI},
+		{"anthropic", "Claude-3-5-Sonnet", ProviderAnthropic},
+		{"unknown", "llama-3", ""},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ProviderForModel(tt.model); got != tt.want {
+				t.Errorf("ProviderForModel(%q) = %q, want %q", tt.model, got, tt.want)
+			}
+		})
+	}
+}
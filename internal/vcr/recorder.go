@@ -0,0 +1,243 @@
+package vcr
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Mode selects whether a Recorder calls the network
+type Mode string
+
+const (
+	// ModeAuto replays the fixture when it exists and records it otherwise
+	ModeAuto Mode = "auto"
+	// ModeRecord calls the network and overwrites the fixture
+	ModeRecord Mode = "record"
+	// ModeReplay only replays the fixture and fails requests it did not record; use it in CI
+	ModeReplay Mode = "replay"
+)
+
+// Redacted replaces secrets in recorded fixtures
+const Redacted = "REDACTED"
+
+// SensitiveHeaders are stored as Redacted
+var SensitiveHeaders = []string{"Authorization", "X-Goog-Api-Key", "X-Subscription-Token", "Cookie", "Set-Cookie"}
+
+// SensitiveParams are query parameters stored as Redacted
+var SensitiveParams = []string{"key", "api_key", "apikey", "appid", "token", "access_token"}
+
+// ModeFromEnv returns the mode named by VCR_MODE, or ModeAuto when it is unset
+func ModeFromEnv() Mode {
+	if mode := Mode(os.Getenv("VCR_MODE")); mode != "" {
+		return mode
+	}
+	return ModeAuto
+}
+
+// Interaction is one recorded request and its response
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request, with secrets redacted
+type Request struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// Response is a recorded response, with secrets redacted
+type Response struct {
+	StatusCode int         `json:"statusCode"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// Cassette is the content of a fixture file
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Recorder is an http.RoundTripper that records interactions to a fixture file or replays them
+// from it. Requests are matched to recorded interactions by method and redacted URL, in the order
+// they were recorded, so repeated calls to one endpoint replay their responses in sequence.
+type Recorder struct {
+	path      string
+	recording bool
+	next      http.RoundTripper
+
+	mutex    sync.Mutex
+	cassette Cassette
+	replayed []bool
+}
+
+// New creates a recorder for the fixture at path. ModeAuto records when the fixture does not
+// exist yet; ModeReplay fails when it does not.
+func New(path string, mode Mode) (*Recorder, error) {
+	recorder := &Recorder{path: path, next: http.DefaultTransport}
+	switch mode {
+	case ModeRecord:
+		recorder.recording = true
+		return recorder, nil
+	case ModeAuto, ModeReplay:
+	default:
+		return nil, fmt.Errorf("unknown VCR mode %q: use auto, record or replay", mode)
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) && mode == ModeAuto {
+		recorder.recording = true
+		return recorder, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+	if err := json.Unmarshal(data, &recorder.cassette); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+	recorder.replayed = make([]bool, len(recorder.cassette.Interactions))
+	return recorder, nil
+}
+
+// Recording reports whether the recorder calls the network rather than replaying
+func (r *Recorder) Recording() bool {
+	return r.recording
+}
+
+// Client returns an HTTP client that sends its requests through the recorder
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip records or replays one request
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("vcr: failed to read request body: %w", err)
+	}
+	recorded := Request{
+		Method:  req.Method,
+		URL:     redactURL(req.URL),
+		Headers: redactHeaders(req.Header),
+		Body:    string(body),
+	}
+	if !r.recording {
+		return r.replay(req, recorded)
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("vcr: failed to read response body: %w", err)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: recorded,
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Headers:    redactHeaders(resp.Header),
+			Body:       string(respBody),
+		},
+	})
+	return resp, nil
+}
+
+// replay answers a request with the first interaction not yet replayed that has its method and URL
+func (r *Recorder) replay(req *http.Request, recorded Request) (*http.Response, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for i, interaction := range r.cassette.Interactions {
+		if r.replayed[i] || interaction.Request.Method != recorded.Method || interaction.Request.URL != recorded.URL {
+			continue
+		}
+		r.replayed[i] = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Headers.Clone(),
+			Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("vcr: no recorded interaction for %s %s in %s", recorded.Method, recorded.URL, r.path)
+}
+
+// Stop writes the fixture when recording; call it once the test's requests are done
+func (r *Recorder) Stop() error {
+	if !r.recording {
+		return nil
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal fixture: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+	if err := os.WriteFile(r.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write fixture: %w", err)
+	}
+	return nil
+}
+
+// readBody reads a request or response body and replaces it with a copy that can be read again
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// redactURL returns u with its sensitive query parameters redacted and the rest sorted
+func redactURL(u *url.URL) string {
+	redacted := *u
+	query := redacted.Query()
+	for _, param := range SensitiveParams {
+		if query.Has(param) {
+			query.Set(param, Redacted)
+		}
+	}
+	redacted.RawQuery = query.Encode()
+	return redacted.String()
+}
+
+// redactHeaders returns a copy of headers with the sensitive ones redacted
+func redactHeaders(headers http.Header) http.Header {
+	if len(headers) == 0 {
+		return nil
+	}
+	redacted := headers.Clone()
+	for _, name := range SensitiveHeaders {
+		if redacted.Get(name) != "" {
+			redacted.Set(name, Redacted)
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,83 @@
+package vcr
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Set-Cookie", "session=secret")
+		fmt.Fprintf(w, `{"call": %d}`, calls)
+	}))
+	fixture := filepath.Join(t.TempDir(), "fixtures", "api.json")
+
+	recorder, err := New(fixture, ModeAuto)
+	if err != nil || !recorder.Recording() {
+		t.Fatalf("expected a missing fixture to be recorded, got %v", err)
+	}
+	client := recorder.Client()
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest(http.MethodPost, server.URL+"/v1/generate?key=secret-key&model=flash", strings.NewReader(`{"prompt": "hi"}`))
+		req.Header.Set("X-Goog-Api-Key", "secret-key")
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error recording: %v", err)
+		}
+		resp.Body.Close()
+	}
+	if err := recorder.Stop(); err != nil {
+		t.Fatalf("failed to save fixture: %v", err)
+	}
+	server.Close()
+
+	data, _ := os.ReadFile(fixture)
+	if strings.Contains(string(data), "secret") {
+		t.Errorf("expected secrets to be redacted from the fixture, got %s", data)
+	}
+	var cassette Cassette
+	json.Unmarshal(data, &cassette)
+	if len(cassette.Interactions) != 2 || cassette.Interactions[0].Request.Body != `{"prompt": "hi"}` {
+		t.Fatalf("expected both interactions with their request bodies, got %+v", cassette)
+	}
+
+	// The server is gone, so responses can only come from the fixture, in recorded order
+	replayer, err := New(fixture, ModeReplay)
+	if err != nil || replayer.Recording() {
+		t.Fatalf("expected the fixture to be replayed, got %v", err)
+	}
+	client = replayer.Client()
+	for _, want := range []string{`{"call": 1}`, `{"call": 2}`} {
+		req, _ := http.NewRequest(http.MethodPost, server.URL+"/v1/generate?model=flash&key=other-key", strings.NewReader(`{"prompt": "hi"}`))
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error replaying: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || string(body) != want {
+			t.Errorf("expected %s, got %d %s", want, resp.StatusCode, body)
+		}
+	}
+	if _, err := client.Post(server.URL+"/v1/generate?model=flash", "application/json", nil); err == nil {
+		t.Error("expected a request beyond the fixture to fail")
+	}
+}
+
+func TestReplayRequiresFixture(t *testing.T) {
+	if _, err := New(filepath.Join(t.TempDir(), "missing.json"), ModeReplay); err == nil {
+		t.Error("expected replay without a fixture to fail")
+	}
+	if _, err := New("fixture.json", Mode("live")); err == nil {
+		t.Error("expected an unknown mode to fail")
+	}
+}
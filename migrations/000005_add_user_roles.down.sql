@@ -0,0 +1,5 @@
+-- Remove role-based access control from users
+DROP INDEX idx_users_role ON users;
+
+ALTER TABLE users
+DROP COLUMN role;
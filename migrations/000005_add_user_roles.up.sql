@@ -0,0 +1,8 @@
+-- Add role-based access control to users
+ALTER TABLE users
+ADD COLUMN role ENUM('user','admin') NOT NULL DEFAULT 'user' COMMENT 'Authorization role: admins can see all users'' data';
+
+-- The built-in system user owns shared resources
+UPDATE users SET role = 'admin' WHERE id = 'system';
+
+CREATE INDEX idx_users_role ON users(role);
@@ -0,0 +1,16 @@
+-- Long-lived API keys for scripts and CI (sent as "Authorization: ApiKey <key>")
+CREATE TABLE api_keys (
+    id VARCHAR(255) PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    key_prefix VARCHAR(32) NOT NULL COMMENT 'First characters of the key, shown to help users identify it',
+    key_hash CHAR(64) NOT NULL UNIQUE COMMENT 'SHA-256 of the raw key; the raw key is never stored',
+    scopes VARCHAR(255) NOT NULL DEFAULT 'read,write' COMMENT 'Comma-separated scopes: read, write, admin',
+    expires_at TIMESTAMP NULL,
+    last_used_at TIMESTAMP NULL,
+    revoked_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_api_keys_user_id ON api_keys(user_id);
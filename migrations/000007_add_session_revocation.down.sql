@@ -0,0 +1,6 @@
+DROP INDEX idx_user_sessions_previous_token ON user_sessions;
+
+ALTER TABLE user_sessions
+DROP COLUMN last_refreshed_at,
+DROP COLUMN revoked_at,
+DROP COLUMN previous_token;
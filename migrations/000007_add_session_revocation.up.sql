@@ -0,0 +1,8 @@
+-- Track refresh-token sessions so access tokens can be revoked.
+-- user_sessions.token now stores the SHA-256 hash of the session's current refresh token.
+ALTER TABLE user_sessions
+ADD COLUMN previous_token VARCHAR(500) NULL COMMENT 'Hash of the refresh token replaced by the last rotation, used to detect reuse',
+ADD COLUMN revoked_at TIMESTAMP NULL COMMENT 'Set on logout or when refresh token reuse is detected',
+ADD COLUMN last_refreshed_at TIMESTAMP NULL;
+
+CREATE INDEX idx_user_sessions_previous_token ON user_sessions(previous_token);
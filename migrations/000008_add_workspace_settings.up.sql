@@ -0,0 +1,14 @@
+-- Organization-wide defaults applied when a run omits values
+CREATE TABLE workspace_settings (
+    id VARCHAR(255) PRIMARY KEY,
+    default_model VARCHAR(100) NULL COMMENT 'Model used by configurations that do not name one',
+    default_safety_settings JSON NULL COMMENT 'Safety settings used by configurations that do not set any',
+    default_metrics JSON NULL COMMENT 'Comparison metrics used when a run requests none',
+    retention_days INT NOT NULL DEFAULT 0 COMMENT 'Execution runs older than this are deleted; 0 keeps them forever',
+    allowed_providers JSON NULL COMMENT 'Model providers runs may use; NULL or empty allows all',
+    updated_by VARCHAR(255) NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+INSERT INTO workspace_settings (id, default_model, retention_days)
+VALUES ('default', 'gemini-1.5-flash', 0);
@@ -0,0 +1,12 @@
+-- Single-use password reset tokens sent by email
+CREATE TABLE password_reset_tokens (
+    id VARCHAR(255) PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE COMMENT 'SHA-256 of the emailed token; the raw token is never stored',
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP NULL COMMENT 'Set when the token is redeemed or superseded by a newer request',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_password_reset_tokens_user_id ON password_reset_tokens(user_id);
@@ -0,0 +1,5 @@
+DROP INDEX idx_execution_runs_determinism_fingerprint ON execution_runs;
+
+ALTER TABLE execution_runs
+DROP COLUMN determinism_fingerprint,
+DROP COLUMN deterministic;
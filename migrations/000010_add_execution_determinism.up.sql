@@ -0,0 +1,6 @@
+-- Deterministic runs record a fingerprint of their inputs so reruns can be matched and compared
+ALTER TABLE execution_runs
+ADD COLUMN deterministic BOOLEAN NOT NULL DEFAULT FALSE,
+ADD COLUMN determinism_fingerprint CHAR(64) NULL COMMENT 'SHA-256 of the prompt, context and resolved configurations';
+
+CREATE INDEX idx_execution_runs_determinism_fingerprint ON execution_runs(determinism_fingerprint);
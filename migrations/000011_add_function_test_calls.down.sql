@@ -0,0 +1,9 @@
+DELETE FROM function_calls WHERE request_id IS NULL;
+
+DROP INDEX idx_function_calls_user_id ON function_calls;
+
+ALTER TABLE function_calls
+DROP COLUMN is_test,
+DROP COLUMN function_definition_id,
+DROP COLUMN user_id,
+MODIFY COLUMN request_id VARCHAR(255) NOT NULL;
@@ -0,0 +1,9 @@
+-- Function tests are logged to function_calls without an API request, so the owner and tested
+-- definition are recorded directly and the row is flagged as a test
+ALTER TABLE function_calls
+MODIFY COLUMN request_id VARCHAR(255) NULL,
+ADD COLUMN user_id VARCHAR(255) NULL COMMENT 'Owner of a test call; execution calls are owned through their request',
+ADD COLUMN function_definition_id VARCHAR(255) NULL,
+ADD COLUMN is_test BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_function_calls_user_id ON function_calls(user_id);
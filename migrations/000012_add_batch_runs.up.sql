@@ -0,0 +1,32 @@
+-- Batch runs execute one template over many streamed dataset items
+CREATE TABLE batch_runs (
+    id VARCHAR(255) PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    template JSON NOT NULL COMMENT 'Execution request applied to every item, without session API keys',
+    status VARCHAR(50) NOT NULL DEFAULT 'submitting',
+    total_items INT NOT NULL DEFAULT 0,
+    error_message TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE batch_items (
+    id VARCHAR(255) PRIMARY KEY,
+    batch_run_id VARCHAR(255) NOT NULL,
+    item_index INT NOT NULL,
+    external_id VARCHAR(255),
+    prompt MEDIUMTEXT NOT NULL,
+    context MEDIUMTEXT,
+    metadata JSON,
+    status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    execution_run_id VARCHAR(255),
+    error_message TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_batch_item_index (batch_run_id, item_index),
+    FOREIGN KEY (batch_run_id) REFERENCES batch_runs(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_batch_runs_user_id ON batch_runs(user_id);
+CREATE INDEX idx_batch_items_status ON batch_items(batch_run_id, status);
@@ -0,0 +1,9 @@
+DROP INDEX idx_execution_runs_content_hash ON execution_runs;
+
+ALTER TABLE execution_runs
+DROP COLUMN content_hash;
+
+ALTER TABLE workspace_settings
+DROP COLUMN duplicate_window_secs,
+DROP COLUMN duplicate_policy,
+DROP COLUMN run_name_template;
@@ -0,0 +1,11 @@
+-- Workspace defaults for run naming and duplicate submission handling
+ALTER TABLE workspace_settings
+ADD COLUMN run_name_template VARCHAR(255) NULL COMMENT 'Name template for runs that set no name, e.g. {suite}-{timestamp}-{git_sha}',
+ADD COLUMN duplicate_policy VARCHAR(20) NULL COMMENT 'allow, reject or merge identical submissions',
+ADD COLUMN duplicate_window_secs INT NOT NULL DEFAULT 0 COMMENT 'How far back duplicates are matched; 0 uses 10 minutes';
+
+-- Hash of a submission's content, used to find duplicate submissions
+ALTER TABLE execution_runs
+ADD COLUMN content_hash CHAR(64) NULL COMMENT 'SHA-256 of the prompt, context, configurations, tools, suite and git SHA';
+
+CREATE INDEX idx_execution_runs_content_hash ON execution_runs(user_id, content_hash, created_at);
@@ -0,0 +1,4 @@
+ALTER TABLE function_definitions
+DROP COLUMN allowed_domains,
+DROP COLUMN max_response_bytes,
+DROP COLUMN timeout_ms;
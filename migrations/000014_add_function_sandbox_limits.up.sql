@@ -0,0 +1,5 @@
+-- Per-function sandbox limits enforced when a function's endpoint is called
+ALTER TABLE function_definitions
+ADD COLUMN timeout_ms INT NULL COMMENT 'Call timeout in milliseconds; NULL uses the server default',
+ADD COLUMN max_response_bytes INT NULL COMMENT 'Largest accepted response body; NULL uses the server default',
+ADD COLUMN allowed_domains JSON DEFAULT NULL COMMENT 'Hosts the endpoint may call, subdomains included';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS execution_run_slo_results;
+DROP TABLE IF EXISTS suite_slos;
@@ -0,0 +1,34 @@
+-- Latency, cost and success objectives per suite
+CREATE TABLE suite_slos (
+    id VARCHAR(255) PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    suite VARCHAR(255) NOT NULL,
+    max_latency_ms INT NULL COMMENT 'Slowest variation response time allowed',
+    max_cost_usd DOUBLE NULL COMMENT 'Estimated cost allowed per run',
+    min_success_rate DOUBLE NULL COMMENT 'Fraction of variations that must succeed',
+    target DOUBLE NOT NULL DEFAULT 0.95 COMMENT 'Fraction of runs that must comply',
+    window_hours INT NOT NULL DEFAULT 24 COMMENT 'Rolling window for the burn rate',
+    alert_burn_rate DOUBLE NOT NULL DEFAULT 2 COMMENT 'Burn rate at which the SLO alerts',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_user_suite (user_id, suite),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- SLO compliance of each run submitted with a suite that has an SLO
+CREATE TABLE execution_run_slo_results (
+    execution_run_id VARCHAR(255) PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    suite VARCHAR(255) NOT NULL,
+    latency_ms INT NOT NULL,
+    cost_usd DOUBLE NOT NULL,
+    success_rate DOUBLE NOT NULL,
+    latency_met BOOLEAN NOT NULL,
+    cost_met BOOLEAN NOT NULL,
+    success_met BOOLEAN NOT NULL,
+    compliant BOOLEAN NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (execution_run_id) REFERENCES execution_runs(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_slo_results_suite ON execution_run_slo_results(user_id, suite, created_at);
@@ -0,0 +1,12 @@
+-- Cached judge-model grades, keyed by a hash of the judge model, criteria, prompt and response
+CREATE TABLE judgments (
+    user_id VARCHAR(255) NOT NULL,
+    response_hash CHAR(64) NOT NULL,
+    judge_model VARCHAR(255) NOT NULL,
+    score DOUBLE NOT NULL COMMENT 'Normalized 0-1 grade',
+    rationale TEXT NULL,
+    cost_usd DOUBLE NOT NULL DEFAULT 0 COMMENT 'Estimated cost of the judge call',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, response_hash),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
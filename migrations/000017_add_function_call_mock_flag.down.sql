@@ -0,0 +1,2 @@
+ALTER TABLE function_calls
+DROP COLUMN used_mock_data;
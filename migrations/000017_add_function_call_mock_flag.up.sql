@@ -0,0 +1,3 @@
+-- Records whether a function call returned its definition's mock response instead of calling the function
+ALTER TABLE function_calls
+ADD COLUMN used_mock_data BOOLEAN NOT NULL DEFAULT FALSE;
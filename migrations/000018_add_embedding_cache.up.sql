@@ -0,0 +1,13 @@
+-- Embeddings keyed by model and a hash of the embedded text, so unchanged content is not re-embedded
+CREATE TABLE embedding_cache (
+    user_id VARCHAR(255) NOT NULL,
+    model VARCHAR(255) NOT NULL,
+    content_hash CHAR(64) NOT NULL,
+    dimensions INT NOT NULL,
+    embedding JSON NOT NULL,
+    hit_count INT NOT NULL DEFAULT 0 COMMENT 'Lookups served from this row',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_hit_at TIMESTAMP NULL,
+    PRIMARY KEY (user_id, model, content_hash),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
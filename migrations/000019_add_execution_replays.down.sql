@@ -0,0 +1,4 @@
+DROP INDEX idx_execution_runs_replay_of_run_id ON execution_runs;
+
+ALTER TABLE execution_runs
+DROP COLUMN replay_of_run_id;
@@ -0,0 +1,5 @@
+-- Replays re-execute a run with its recorded function responses and link back to it
+ALTER TABLE execution_runs
+ADD COLUMN replay_of_run_id VARCHAR(255) NULL COMMENT 'Run whose prompt, configurations and function responses were replayed';
+
+CREATE INDEX idx_execution_runs_replay_of_run_id ON execution_runs(replay_of_run_id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS evaluation_results;
+
+ALTER TABLE batch_items
+DROP COLUMN expected_answer;
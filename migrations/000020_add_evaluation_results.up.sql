@@ -0,0 +1,22 @@
+-- Golden-answer evaluation: batch items may carry an expected answer, and each variation's
+-- response is scored against it
+ALTER TABLE batch_items
+ADD COLUMN expected_answer JSON NULL COMMENT 'Overrides the batch template expected answer';
+
+CREATE TABLE evaluation_results (
+    id VARCHAR(255) PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    execution_run_id VARCHAR(255) NOT NULL,
+    configuration_id VARCHAR(255) NOT NULL,
+    variation_name VARCHAR(255) NOT NULL,
+    repetition INT NOT NULL DEFAULT 0,
+    match_mode VARCHAR(50) NOT NULL COMMENT 'exact, substring, regex or embedding',
+    expected_answer MEDIUMTEXT NOT NULL,
+    score DOUBLE NOT NULL COMMENT '1 or 0, or the cosine similarity for embedding matches',
+    passed BOOLEAN NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (execution_run_id) REFERENCES execution_runs(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_evaluation_results_execution_run_id ON evaluation_results(execution_run_id);
@@ -0,0 +1,19 @@
+-- Embeddings of variation responses, stored when a comparison scores semantic similarity so
+-- responses can be compared and clustered later
+CREATE TABLE response_embeddings (
+    response_id VARCHAR(255) NOT NULL,
+    model VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    execution_run_id VARCHAR(255) NOT NULL,
+    configuration_id VARCHAR(255) NOT NULL,
+    variation_name VARCHAR(255) NOT NULL,
+    repetition INT NOT NULL DEFAULT 0,
+    dimensions INT NOT NULL,
+    embedding JSON NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (response_id, model),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (execution_run_id) REFERENCES execution_runs(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_response_embeddings_execution_run_id ON response_embeddings(execution_run_id);
@@ -0,0 +1,11 @@
+-- Model catalog fetched from the provider's model list endpoint and refreshed daily
+CREATE TABLE model_catalog (
+    name VARCHAR(255) PRIMARY KEY COMMENT 'Model name without the models/ prefix',
+    display_name VARCHAR(255) NOT NULL,
+    description TEXT,
+    version VARCHAR(100),
+    input_token_limit INT NOT NULL DEFAULT 0,
+    output_token_limit INT NOT NULL DEFAULT 0,
+    supported_methods JSON NOT NULL COMMENT 'e.g. generateContent, countTokens, embedContent',
+    fetched_at TIMESTAMP NOT NULL
+);
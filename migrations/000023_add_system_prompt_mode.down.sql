@@ -0,0 +1 @@
+ALTER TABLE api_requests DROP COLUMN system_prompt_mode;
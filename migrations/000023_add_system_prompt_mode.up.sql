@@ -0,0 +1,3 @@
+-- Record how each request sent its configuration's system prompt
+ALTER TABLE api_requests
+ADD COLUMN system_prompt_mode VARCHAR(20) NULL COMMENT 'instruction (Gemini systemInstruction) or inline (prepended to the prompt); NULL when there was no system prompt';
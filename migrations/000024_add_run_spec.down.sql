@@ -0,0 +1 @@
+ALTER TABLE execution_runs DROP COLUMN run_spec;
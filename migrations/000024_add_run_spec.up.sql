@@ -0,0 +1,2 @@
+-- Resolved run spec of each execution run, so runs can be reproduced
+ALTER TABLE execution_runs ADD COLUMN run_spec JSON NULL;
@@ -0,0 +1 @@
+ALTER TABLE execution_runs DROP COLUMN parameter_sweep;
@@ -0,0 +1,2 @@
+-- Parameter sweep each execution run's configurations were expanded from
+ALTER TABLE execution_runs ADD COLUMN parameter_sweep JSON NULL;
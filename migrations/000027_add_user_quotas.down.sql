@@ -0,0 +1,6 @@
+DROP INDEX idx_api_responses_user_created ON api_responses;
+
+ALTER TABLE workspace_settings
+DROP COLUMN daily_token_budget,
+DROP COLUMN max_variations_per_run,
+DROP COLUMN max_concurrent_executions;
@@ -0,0 +1,8 @@
+-- Limits applied to each user's executions
+ALTER TABLE workspace_settings
+ADD COLUMN max_concurrent_executions INT NOT NULL DEFAULT 0 COMMENT 'Executions a user may have pending or running; 0 is unlimited',
+ADD COLUMN max_variations_per_run INT NOT NULL DEFAULT 0 COMMENT 'Configurations one run may execute; 0 is unlimited',
+ADD COLUMN daily_token_budget BIGINT NOT NULL DEFAULT 0 COMMENT 'Tokens a user may use per UTC day; 0 is unlimited';
+
+-- Daily token usage is summed from a user's responses since midnight
+CREATE INDEX idx_api_responses_user_created ON api_responses(user_id, created_at);
@@ -0,0 +1,2 @@
+-- The backfilled statuses cannot be told apart from recorded ones, so they are kept
+SELECT 1;
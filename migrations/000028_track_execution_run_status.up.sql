@@ -0,0 +1,2 @@
+-- Runs now record their own status; runs from before that were left pending once they finished
+UPDATE execution_runs SET status = 'completed' WHERE status = 'pending';
@@ -0,0 +1,2 @@
+ALTER TABLE api_responses DROP COLUMN redaction;
+ALTER TABLE api_requests DROP COLUMN redaction;
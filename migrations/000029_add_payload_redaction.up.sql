@@ -0,0 +1,3 @@
+-- How each stored request and response was redacted, including the policy fingerprint
+ALTER TABLE api_requests ADD COLUMN redaction JSON NULL;
+ALTER TABLE api_responses ADD COLUMN redaction JSON NULL;
@@ -0,0 +1,12 @@
+DROP INDEX idx_function_calls_created_at ON function_calls;
+DROP INDEX idx_execution_logs_timestamp ON execution_logs;
+DROP INDEX idx_api_responses_created_at ON api_responses;
+DROP INDEX idx_api_requests_created_at ON api_requests;
+
+DROP TABLE IF EXISTS user_retention_policies;
+
+ALTER TABLE workspace_settings
+DROP COLUMN function_call_retention_days,
+DROP COLUMN log_retention_days,
+DROP COLUMN response_retention_days,
+DROP COLUMN request_retention_days;
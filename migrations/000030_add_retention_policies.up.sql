@@ -0,0 +1,25 @@
+-- Workspace retention per table; retention_days stays the retention of execution runs
+ALTER TABLE workspace_settings
+ADD COLUMN request_retention_days INT NOT NULL DEFAULT 0 COMMENT 'API requests older than this are pruned; 0 keeps them',
+ADD COLUMN response_retention_days INT NOT NULL DEFAULT 0 COMMENT 'API responses older than this are pruned; 0 keeps them',
+ADD COLUMN log_retention_days INT NOT NULL DEFAULT 0 COMMENT 'Execution logs older than this are pruned; 0 keeps them',
+ADD COLUMN function_call_retention_days INT NOT NULL DEFAULT 0 COMMENT 'Function calls older than this are pruned; 0 keeps them';
+
+-- Per-user policies replace the workspace policy for that user's history
+CREATE TABLE user_retention_policies (
+    user_id VARCHAR(255) PRIMARY KEY,
+    runs_days INT NOT NULL DEFAULT 0,
+    requests_days INT NOT NULL DEFAULT 0,
+    responses_days INT NOT NULL DEFAULT 0,
+    logs_days INT NOT NULL DEFAULT 0,
+    function_calls_days INT NOT NULL DEFAULT 0,
+    updated_by VARCHAR(255),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Pruning selects expired rows by age
+CREATE INDEX idx_api_requests_created_at ON api_requests(created_at);
+CREATE INDEX idx_api_responses_created_at ON api_responses(created_at);
+CREATE INDEX idx_execution_logs_timestamp ON execution_logs(timestamp);
+CREATE INDEX idx_function_calls_created_at ON function_calls(created_at);
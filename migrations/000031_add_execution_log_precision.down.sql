@@ -0,0 +1,2 @@
+DROP INDEX idx_execution_logs_run_timestamp ON execution_logs;
+ALTER TABLE execution_logs MODIFY timestamp TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
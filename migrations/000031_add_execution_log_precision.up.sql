@@ -0,0 +1,3 @@
+-- Microsecond timestamps keep log entries written within the same second in order when paging
+ALTER TABLE execution_logs MODIFY timestamp TIMESTAMP(6) DEFAULT CURRENT_TIMESTAMP(6);
+CREATE INDEX idx_execution_logs_run_timestamp ON execution_logs(execution_run_id, timestamp, id);
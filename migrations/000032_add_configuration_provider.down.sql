@@ -0,0 +1 @@
+ALTER TABLE api_configurations DROP COLUMN provider;
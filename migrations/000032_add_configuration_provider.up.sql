@@ -0,0 +1,3 @@
+-- Record the provider that served each configuration, so runs can mix Gemini and local models
+ALTER TABLE api_configurations
+ADD COLUMN provider VARCHAR(20) NULL COMMENT 'gemini, ollama, ...; NULL when the provider was not recognized';
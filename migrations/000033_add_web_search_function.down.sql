@@ -0,0 +1,2 @@
+-- Remove the built-in web search function
+DELETE FROM function_definitions WHERE id = 'func-web-search';
@@ -0,0 +1,51 @@
+-- Add the built-in web search function
+
+INSERT INTO function_definitions (
+    id,
+    user_id,
+    name,
+    display_name,
+    description,
+    parameters_schema,
+    http_method,
+    is_active,
+    is_system_resource,
+    required_api_keys,
+    api_key_validation,
+    created_at,
+    updated_at
+) VALUES (
+    'func-web-search',
+    'system',
+    'web_search',
+    'Web Search',
+    'Search the web and return the title, URL and a snippet of each matching page',
+    JSON_OBJECT(
+        'type', 'object',
+        'properties', JSON_OBJECT(
+            'query', JSON_OBJECT(
+                'type', 'string',
+                'description', 'The search query'
+            ),
+            'num_results', JSON_OBJECT(
+                'type', 'integer',
+                'description', 'Number of results to return',
+                'minimum', 1,
+                'maximum', 10
+            )
+        ),
+        'required', JSON_ARRAY('query')
+    ),
+    'GET',
+    true,
+    true,
+    JSON_ARRAY('webSearchApiKey'),
+    JSON_OBJECT(
+        'webSearchApiKey', JSON_OBJECT(
+            'description', 'API key of the configured search provider (SerpAPI, Brave or Tavily)',
+            'errorMessage', 'Please enter a valid web search API key'
+        )
+    ),
+    NOW(),
+    NOW()
+);
@@ -0,0 +1,2 @@
+-- Remove the built-in SQL query function
+DELETE FROM function_definitions WHERE id = 'func-sql-query';
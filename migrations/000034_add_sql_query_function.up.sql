@@ -0,0 +1,51 @@
+-- Add the built-in SQL query function
+
+INSERT INTO function_definitions (
+    id,
+    user_id,
+    name,
+    display_name,
+    description,
+    parameters_schema,
+    http_method,
+    is_active,
+    is_system_resource,
+    required_api_keys,
+    api_key_validation,
+    created_at,
+    updated_at
+) VALUES (
+    'func-sql-query',
+    'system',
+    'query_sql',
+    'SQL Query',
+    'Run a read-only SELECT query against a relational database and return the matching rows',
+    JSON_OBJECT(
+        'type', 'object',
+        'properties', JSON_OBJECT(
+            'query', JSON_OBJECT(
+                'type', 'string',
+                'description', 'A single SELECT statement'
+            ),
+            'limit', JSON_OBJECT(
+                'type', 'integer',
+                'description', 'Maximum number of rows to return',
+                'minimum', 1,
+                'maximum', 1000
+            )
+        ),
+        'required', JSON_ARRAY('query')
+    ),
+    'GET',
+    true,
+    true,
+    JSON_ARRAY('sqlDsn'),
+    JSON_OBJECT(
+        'sqlDsn', JSON_OBJECT(
+            'description', 'DSN of the database to query, e.g. user:password@tcp(host:3306)/dbname',
+            'errorMessage', 'Please enter the DSN of the database to query'
+        )
+    ),
+    NOW(),
+    NOW()
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS retrieved_chunks;
+DROP TABLE IF EXISTS document_chunks;
+DROP TABLE IF EXISTS documents;
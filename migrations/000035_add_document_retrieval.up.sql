@@ -0,0 +1,53 @@
+-- Documents split into embedded chunks, grouped into named collections per user, that configurations
+-- retrieve from before they run
+CREATE TABLE documents (
+    id VARCHAR(255) PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    collection VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    chunk_count INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_documents_user_collection ON documents(user_id, collection);
+
+CREATE TABLE document_chunks (
+    id VARCHAR(255) PRIMARY KEY,
+    document_id VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    collection VARCHAR(255) NOT NULL,
+    chunk_index INT NOT NULL,
+    content TEXT NOT NULL,
+    embedding_model VARCHAR(255) NOT NULL,
+    dimensions INT NOT NULL,
+    embedding JSON NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (document_id) REFERENCES documents(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_document_chunks_collection ON document_chunks(user_id, collection, embedding_model);
+
+-- Chunks injected into each request's context, kept with their text so runs stay readable after
+-- the document is deleted
+CREATE TABLE retrieved_chunks (
+    request_id VARCHAR(255) NOT NULL,
+    chunk_rank INT NOT NULL COMMENT '1-based position in the injected context',
+    user_id VARCHAR(255) NOT NULL,
+    execution_run_id VARCHAR(255) NOT NULL,
+    configuration_id VARCHAR(255) NOT NULL,
+    chunk_id VARCHAR(255) NOT NULL,
+    document_id VARCHAR(255) NOT NULL,
+    document_name VARCHAR(255) NOT NULL,
+    collection VARCHAR(255) NOT NULL,
+    chunk_index INT NOT NULL,
+    content TEXT NOT NULL,
+    score DOUBLE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (request_id, chunk_rank),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (execution_run_id) REFERENCES execution_runs(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_retrieved_chunks_execution_run_id ON retrieved_chunks(execution_run_id);
@@ -0,0 +1,6 @@
+ALTER TABLE documents
+    DROP COLUMN embedding_model,
+    DROP COLUMN chunk_overlap,
+    DROP COLUMN chunk_size,
+    DROP COLUMN size_bytes,
+    DROP COLUMN content_type;
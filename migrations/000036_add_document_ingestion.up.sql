@@ -0,0 +1,7 @@
+-- Record how uploaded documents were extracted, chunked and embedded
+ALTER TABLE documents
+    ADD COLUMN content_type VARCHAR(100) NOT NULL DEFAULT 'text/plain' AFTER name,
+    ADD COLUMN size_bytes BIGINT NOT NULL DEFAULT 0 AFTER content_type,
+    ADD COLUMN chunk_size INT NOT NULL DEFAULT 0 COMMENT 'Characters per chunk; 0 when the chunks were supplied directly' AFTER chunk_count,
+    ADD COLUMN chunk_overlap INT NOT NULL DEFAULT 0 AFTER chunk_size,
+    ADD COLUMN embedding_model VARCHAR(255) NOT NULL DEFAULT '' AFTER chunk_overlap;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS guard_verdicts;
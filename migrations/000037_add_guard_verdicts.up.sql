@@ -0,0 +1,19 @@
+-- Verdicts of the guards run on each request's prompt and response
+CREATE TABLE guard_verdicts (
+    request_id VARCHAR(255) NOT NULL,
+    position INT NOT NULL COMMENT '1-based order the guard ran in, input guards first',
+    user_id VARCHAR(255) NOT NULL,
+    execution_run_id VARCHAR(255) NOT NULL,
+    configuration_id VARCHAR(255) NOT NULL,
+    guard VARCHAR(100) NOT NULL,
+    stage VARCHAR(20) NOT NULL COMMENT 'input or output',
+    action VARCHAR(20) NOT NULL COMMENT 'block, sanitize, flag or redact',
+    triggered BOOLEAN NOT NULL,
+    findings JSON NOT NULL COMMENT 'Labels of what the guard matched, e.g. ["email"]',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (request_id, position),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (execution_run_id) REFERENCES execution_runs(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_guard_verdicts_execution_run_id ON guard_verdicts(execution_run_id);
@@ -0,0 +1,3 @@
+DROP INDEX idx_api_configurations_user_variation ON api_configurations;
+DROP INDEX idx_api_configurations_user_preset ON api_configurations;
+ALTER TABLE api_configurations DROP COLUMN preset_id;
@@ -0,0 +1,6 @@
+-- Record the preset each configuration was created from, so a preset's results can be followed across runs
+ALTER TABLE api_configurations
+ADD COLUMN preset_id VARCHAR(255) NULL COMMENT 'configuration_presets.id; NULL when the configuration did not use a preset';
+
+CREATE INDEX idx_api_configurations_user_preset ON api_configurations(user_id, preset_id);
+CREATE INDEX idx_api_configurations_user_variation ON api_configurations(user_id, variation_name);
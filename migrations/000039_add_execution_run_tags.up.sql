@@ -0,0 +1,12 @@
+-- Labels grouping execution runs by prompt category, ranked across by the leaderboard
+CREATE TABLE execution_run_tags (
+    execution_run_id VARCHAR(255) NOT NULL,
+    tag VARCHAR(64) NOT NULL COMMENT 'Lowercase label, e.g. summarization',
+    user_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (execution_run_id, tag),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (execution_run_id) REFERENCES execution_runs(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_execution_run_tags_user_tag ON execution_run_tags(user_id, tag);
@@ -0,0 +1,17 @@
+-- Human feedback on variation responses, blended into the run's comparison scores
+CREATE TABLE response_feedback (
+    response_id VARCHAR(255) PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    execution_run_id VARCHAR(255) NOT NULL,
+    configuration_id VARCHAR(255) NOT NULL,
+    thumbs VARCHAR(10) COMMENT 'up or down',
+    rating TINYINT COMMENT '1 (worst) to 5 (best)',
+    comment TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (execution_run_id) REFERENCES execution_runs(id) ON DELETE CASCADE,
+    FOREIGN KEY (response_id) REFERENCES api_responses(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_response_feedback_execution_run_id ON response_feedback(execution_run_id);
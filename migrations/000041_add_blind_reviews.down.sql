@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS review_judgments;
+DROP TABLE IF EXISTS review_assignments;
+DROP TABLE IF EXISTS review_items;
+DROP TABLE IF EXISTS reviews;
@@ -0,0 +1,53 @@
+-- Blind reviews of a run's responses, with the configurations hidden from reviewers
+CREATE TABLE reviews (
+    id VARCHAR(255) PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL COMMENT 'Owner of the reviewed run',
+    execution_run_id VARCHAR(255) NOT NULL,
+    mode VARCHAR(20) NOT NULL COMMENT 'rank or label',
+    labels JSON COMMENT 'Label mode: the labels reviewers choose from, best first',
+    prompt TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' COMMENT 'open, completed or closed',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    closed_at TIMESTAMP NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (execution_run_id) REFERENCES execution_runs(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_reviews_user_id ON reviews(user_id);
+
+-- The responses under review, one per configuration
+CREATE TABLE review_items (
+    id VARCHAR(255) PRIMARY KEY,
+    review_id VARCHAR(255) NOT NULL,
+    response_id VARCHAR(255) NOT NULL,
+    configuration_id VARCHAR(255) NOT NULL,
+    position INT NOT NULL,
+    FOREIGN KEY (review_id) REFERENCES reviews(id) ON DELETE CASCADE,
+    FOREIGN KEY (response_id) REFERENCES api_responses(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_review_items_review_id ON review_items(review_id);
+
+CREATE TABLE review_assignments (
+    review_id VARCHAR(255) NOT NULL,
+    reviewer_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    submitted_at TIMESTAMP NULL,
+    PRIMARY KEY (review_id, reviewer_id),
+    FOREIGN KEY (review_id) REFERENCES reviews(id) ON DELETE CASCADE,
+    FOREIGN KEY (reviewer_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_review_assignments_reviewer_id ON review_assignments(reviewer_id);
+
+-- Each reviewer's rank or label of each item
+CREATE TABLE review_judgments (
+    review_id VARCHAR(255) NOT NULL,
+    reviewer_id VARCHAR(255) NOT NULL,
+    item_id VARCHAR(255) NOT NULL,
+    item_rank INT COMMENT 'Rank mode: 1 is best',
+    label VARCHAR(100) COMMENT 'Label mode',
+    PRIMARY KEY (review_id, reviewer_id, item_id),
+    FOREIGN KEY (review_id, reviewer_id) REFERENCES review_assignments(review_id, reviewer_id) ON DELETE CASCADE,
+    FOREIGN KEY (item_id) REFERENCES review_items(id) ON DELETE CASCADE
+);
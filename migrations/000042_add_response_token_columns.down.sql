@@ -0,0 +1,3 @@
+ALTER TABLE api_responses DROP COLUMN total_tokens;
+ALTER TABLE api_responses DROP COLUMN completion_tokens;
+ALTER TABLE api_responses DROP COLUMN prompt_tokens;
@@ -0,0 +1,10 @@
+-- Token counts parsed out of usage_metadata so usage can be summed in SQL
+ALTER TABLE api_responses ADD COLUMN prompt_tokens INT NULL;
+ALTER TABLE api_responses ADD COLUMN completion_tokens INT NULL;
+ALTER TABLE api_responses ADD COLUMN total_tokens INT NULL;
+
+UPDATE api_responses
+SET prompt_tokens = CAST(JSON_EXTRACT(usage_metadata, '$.prompt_tokens') AS SIGNED),
+    completion_tokens = CAST(JSON_EXTRACT(usage_metadata, '$.completion_tokens') AS SIGNED),
+    total_tokens = CAST(JSON_EXTRACT(usage_metadata, '$.total_tokens') AS SIGNED)
+WHERE usage_metadata IS NOT NULL;
@@ -0,0 +1 @@
+ALTER TABLE api_responses DROP COLUMN latency_breakdown;
@@ -0,0 +1,2 @@
+-- Where each variation's time went: queued, retrieval, model, function, follow-up, DB and other milliseconds
+ALTER TABLE api_responses ADD COLUMN latency_breakdown JSON NULL;
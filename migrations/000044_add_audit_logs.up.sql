@@ -0,0 +1,15 @@
+-- Security-relevant user actions. Rows keep no foreign keys, so the trail outlives deleted users and runs.
+CREATE TABLE audit_logs (
+    id VARCHAR(255) PRIMARY KEY,
+    actor_id VARCHAR(255) NULL COMMENT 'NULL when the actor is unknown, e.g. a failed login',
+    action VARCHAR(50) NOT NULL COMMENT 'login, login_failed, api_key_created, function_deleted, ...',
+    target_type VARCHAR(50) NULL,
+    target_id VARCHAR(255) NULL,
+    ip_address VARCHAR(45) NULL,
+    summary JSON NULL COMMENT 'What changed; never secrets or payload bodies',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_logs_created_at ON audit_logs(created_at);
+CREATE INDEX idx_audit_logs_actor ON audit_logs(actor_id, created_at);
+CREATE INDEX idx_audit_logs_action ON audit_logs(action, created_at);
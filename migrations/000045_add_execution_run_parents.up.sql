@@ -0,0 +1,5 @@
+-- Runs submitted from a clone of another run link back to it, so tweaks can be traced across runs
+ALTER TABLE execution_runs
+ADD COLUMN parent_run_id VARCHAR(255) NULL COMMENT 'Run this run was cloned from';
+
+CREATE INDEX idx_execution_runs_parent_run_id ON execution_runs(parent_run_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS workspace_resources;
+DROP TABLE IF EXISTS workspace_members;
+DROP TABLE IF EXISTS workspaces;
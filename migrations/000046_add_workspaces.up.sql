@@ -0,0 +1,33 @@
+-- Teams whose members share execution runs, function definitions and configuration presets
+CREATE TABLE workspaces (
+    id VARCHAR(255) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE workspace_members (
+    workspace_id VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL COMMENT 'owner, editor or viewer',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (workspace_id, user_id),
+    FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_workspace_members_user_id ON workspace_members(user_id);
+
+-- Resources shared with a workspace by their owners; shares of deleted resources are ignored
+CREATE TABLE workspace_resources (
+    workspace_id VARCHAR(255) NOT NULL,
+    resource_type VARCHAR(50) NOT NULL COMMENT 'execution_run, function_definition or configuration_preset',
+    resource_id VARCHAR(255) NOT NULL,
+    shared_by VARCHAR(255) NOT NULL COMMENT 'Owner of the resource',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (workspace_id, resource_type, resource_id),
+    FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE,
+    FOREIGN KEY (shared_by) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_workspace_resources_resource ON workspace_resources(resource_type, resource_id);
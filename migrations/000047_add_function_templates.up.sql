@@ -0,0 +1,75 @@
+-- Ready-made function definitions users copy into their own function_definitions
+CREATE TABLE function_templates (
+    id VARCHAR(255) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL COMMENT 'Function name the copy starts with',
+    display_name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL,
+    category VARCHAR(50) NOT NULL,
+    parameters_schema JSON NOT NULL,
+    example_arguments JSON NULL COMMENT 'Arguments a model might send, for trying the function',
+    mock_response JSON NULL,
+    endpoint_url VARCHAR(500) NULL,
+    http_method VARCHAR(10) NOT NULL DEFAULT 'GET',
+    headers JSON NULL,
+    required_api_keys JSON NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_function_template_name (name)
+);
+
+INSERT INTO function_templates (id, name, display_name, description, category, parameters_schema, example_arguments, mock_response, endpoint_url, http_method, headers, required_api_keys) VALUES
+(
+    'template-weather-forecast',
+    'get_weather_forecast',
+    'Weather Forecast',
+    'Get the weather forecast for a location for the next few days',
+    'weather',
+    '{"type": "object", "properties": {"location": {"type": "string", "description": "City name, optionally with state and country, e.g. Paris, FR"}, "days": {"type": "integer", "description": "Days to forecast", "minimum": 1, "maximum": 5}, "units": {"type": "string", "enum": ["metric", "imperial"], "description": "metric: Celsius, imperial: Fahrenheit"}}, "required": ["location"]}',
+    '{"location": "Paris, FR", "days": 3, "units": "metric"}',
+    '{"location": "Paris, FR", "units": "metric", "forecast": [{"date": "2026-06-01", "high": 24, "low": 15, "conditions": "sunny"}, {"date": "2026-06-02", "high": 21, "low": 14, "conditions": "light rain"}, {"date": "2026-06-03", "high": 22, "low": 13, "conditions": "partly cloudy"}]}',
+    'https://api.openweathermap.org/data/2.5/forecast',
+    'GET',
+    '{"Accept": "application/json"}',
+    '["openWeatherApiKey"]'
+),
+(
+    'template-web-search',
+    'search_web',
+    'Web Search',
+    'Search the web and return the top results with their titles, links and snippets',
+    'search',
+    '{"type": "object", "properties": {"query": {"type": "string", "description": "What to search for"}, "count": {"type": "integer", "description": "Number of results to return", "minimum": 1, "maximum": 10}}, "required": ["query"]}',
+    '{"query": "latest Go release notes", "count": 3}',
+    '{"query": "latest Go release notes", "results": [{"title": "Go 1.23 Release Notes", "url": "https://go.dev/doc/go1.23", "snippet": "The latest Go release, version 1.23, arrives six months after Go 1.22."}, {"title": "Release History - The Go Programming Language", "url": "https://go.dev/doc/devel/release", "snippet": "This page summarizes the changes between official stable releases of Go."}]}',
+    'https://api.search.brave.com/res/v1/web/search',
+    'GET',
+    '{"Accept": "application/json"}',
+    '["braveSearchApiKey"]'
+),
+(
+    'template-stock-quote',
+    'get_stock_quote',
+    'Stock Quote',
+    'Get the latest price and daily change of a stock by its ticker symbol',
+    'finance',
+    '{"type": "object", "properties": {"symbol": {"type": "string", "description": "Ticker symbol, e.g. AAPL"}}, "required": ["symbol"]}',
+    '{"symbol": "AAPL"}',
+    '{"symbol": "AAPL", "price": 189.84, "change": 1.27, "changePercent": 0.67, "currency": "USD", "asOf": "2026-06-01T20:00:00Z"}',
+    'https://www.alphavantage.co/query',
+    'GET',
+    '{"Accept": "application/json"}',
+    '["alphaVantageApiKey"]'
+),
+(
+    'template-calendar-events',
+    'list_calendar_events',
+    'Calendar Events',
+    'List the events on a calendar between two dates',
+    'productivity',
+    '{"type": "object", "properties": {"start": {"type": "string", "format": "date", "description": "First day, YYYY-MM-DD"}, "end": {"type": "string", "format": "date", "description": "Last day, YYYY-MM-DD"}, "calendar": {"type": "string", "description": "Calendar to read; the primary calendar when omitted"}}, "required": ["start", "end"]}',
+    '{"start": "2026-06-01", "end": "2026-06-02"}',
+    '{"calendar": "primary", "events": [{"title": "Team standup", "start": "2026-06-01T09:30:00Z", "end": "2026-06-01T09:45:00Z"}, {"title": "Vendor review", "start": "2026-06-02T14:00:00Z", "end": "2026-06-02T15:00:00Z", "location": "Room 4"}]}',
+    'https://www.googleapis.com/calendar/v3/calendars/primary/events',
+    'GET',
+    '{"Accept": "application/json"}',
+    '["googleCalendarToken"]'
+);
@@ -0,0 +1,12 @@
+-- Named read-only SQL reports admins define for users to run
+CREATE TABLE saved_reports (
+    id VARCHAR(255) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NULL,
+    query_text TEXT NOT NULL COMMENT 'A single SELECT naming its parameters as :name',
+    parameters JSON NULL,
+    created_by VARCHAR(255) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_saved_report_name (name)
+);
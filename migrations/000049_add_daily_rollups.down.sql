@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS rollup_refreshes;
+DROP TABLE IF EXISTS daily_user_rollups;
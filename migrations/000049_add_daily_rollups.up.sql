@@ -0,0 +1,25 @@
+-- Per-user daily totals the dashboard stats read instead of joining the execution tables. Sums and
+-- counts are kept rather than averages so days add up exactly.
+CREATE TABLE daily_user_rollups (
+    user_id VARCHAR(255) NOT NULL,
+    day DATE NOT NULL,
+    execution_runs INT NOT NULL DEFAULT 0,
+    api_requests INT NOT NULL DEFAULT 0,
+    api_responses INT NOT NULL DEFAULT 0,
+    successful_responses INT NOT NULL DEFAULT 0,
+    timed_responses INT NOT NULL DEFAULT 0 COMMENT 'Responses with a response time',
+    response_time_ms_sum BIGINT NOT NULL DEFAULT 0,
+    function_calls INT NOT NULL DEFAULT 0,
+    prompt_tokens BIGINT NOT NULL DEFAULT 0,
+    completion_tokens BIGINT NOT NULL DEFAULT 0,
+    total_tokens BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, day),
+    INDEX idx_daily_user_rollups_day (day),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- When each rollup was last refreshed, for reporting how fresh the stats read from it are
+CREATE TABLE rollup_refreshes (
+    name VARCHAR(100) PRIMARY KEY,
+    refreshed_at TIMESTAMP NOT NULL
+);
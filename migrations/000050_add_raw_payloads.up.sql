@@ -0,0 +1,23 @@
+-- Raw HTTP bodies exchanged with the provider, kept for runs submitted with storeRawPayloads. Bodies
+-- are scrubbed with the redaction patterns, cut to the run's size limit and gzip-compressed.
+CREATE TABLE raw_payloads (
+    request_id VARCHAR(255) NOT NULL,
+    sequence INT NOT NULL COMMENT '1-based order of the exchange within the request',
+    user_id VARCHAR(255) NOT NULL,
+    execution_run_id VARCHAR(255) NOT NULL,
+    method VARCHAR(10) NOT NULL,
+    url VARCHAR(2048) NOT NULL COMMENT 'Without the query string',
+    status_code INT NOT NULL DEFAULT 0 COMMENT '0 when no response arrived',
+    request_body MEDIUMBLOB NOT NULL COMMENT 'gzip',
+    request_size INT NOT NULL COMMENT 'Bytes before truncation',
+    response_body MEDIUMBLOB NOT NULL COMMENT 'gzip',
+    response_size INT NOT NULL COMMENT 'Bytes before truncation',
+    truncated BOOLEAN NOT NULL DEFAULT FALSE,
+    error_message TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (request_id, sequence),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (execution_run_id) REFERENCES execution_runs(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_raw_payloads_execution_run_id ON raw_payloads(execution_run_id);
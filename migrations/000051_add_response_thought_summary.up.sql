@@ -0,0 +1,2 @@
+-- The thinking model's summary of its reasoning, stored apart from the response text
+ALTER TABLE api_responses ADD COLUMN thought_summary TEXT NULL;
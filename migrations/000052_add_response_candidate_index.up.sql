@@ -0,0 +1,2 @@
+-- Which of a request's candidates a response is; a request generating several has one row per candidate
+ALTER TABLE api_responses ADD COLUMN candidate_index INT NOT NULL DEFAULT 0;
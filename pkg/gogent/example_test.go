@@ -0,0 +1,48 @@
+package gogent_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"gogent/pkg/gogent"
+)
+
+func Example() {
+	// Without an API key, responses are mocked
+	client := gogent.NewInMemoryClient(&gogent.Config{})
+
+	result, err := client.ExecuteMultiVariation(context.Background(), "user-1", &gogent.MultiExecutionRequest{
+		ExecutionRunName: "greeting",
+		BasePrompt:       "Say hello",
+		Configurations: []gogent.APIConfiguration{
+			{VariationName: "flash", ModelName: "gemini-2.0-flash"},
+			{VariationName: "pro", ModelName: "gemini-1.5-pro"},
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, variation := range result.Results {
+		fmt.Printf("%s: %s\n", variation.Configuration.VariationName, variation.Response.ResponseStatus)
+	}
+	// Output:
+	// flash: success
+	// pro: success
+}
+
+func ExampleParseRunSpec() {
+	spec, err := gogent.ParseRunSpec([]byte(`
+name: greeting
+prompt: Say hello
+configurations:
+  - name: flash
+    model: gemini-2.0-flash
+`))
+	if err != nil {
+		log.Fatal(err)
+	}
+	request := gogent.RunSpecRequest(spec)
+	fmt.Println(request.ExecutionRunName, len(request.Configurations))
+	// Output: greeting 1
+}
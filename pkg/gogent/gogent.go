@@ -0,0 +1,325 @@
+// Package gogent is the public Go API of gogent: run prompt variations against models, store what
+// happened and read the results back. It re-exports a curated, stable subset of the internal
+// packages; everything not listed here may change without notice.
+//
+// The package follows semantic versioning from Version: within a major version, names here are
+// not removed or changed incompatibly.
+package gogent
+
+import (
+	internal "gogent/internal/gogent"
+	"gogent/internal/types"
+)
+
+// Version is the semantic version of the public API
+const Version = "0.1.0"
+
+// Client runs multi-variation executions and reads back their records
+type Client = internal.Client
+
+// Config configures a client: API keys, Vertex AI and Ollama settings, retries and redaction
+type Config = types.GeminiClientConfig
+
+// Store keeps execution records; implement it to store them somewhere other than MySQL or memory
+type Store = internal.Store
+
+// MemoryStore keeps execution records in process
+type MemoryStore = internal.MemoryStore
+
+// EmbeddingProvider computes text embeddings; implement it to plug in a new embedding service
+type EmbeddingProvider = internal.EmbeddingProvider
+
+// WebSearchBackend searches the web for the web_search function; implement it to plug in a new search API
+type WebSearchBackend = internal.WebSearchBackend
+
+// WebSearchResult is one page found by a web search
+type WebSearchResult = internal.WebSearchResult
+
+// Guard inspects a prompt or response for passages a guardrail objects to; register it with RegisterGuard
+type Guard = internal.Guard
+
+// GuardMatch is a passage a guard objects to
+type GuardMatch = internal.GuardMatch
+
+// DocumentRetriever finds the document chunks most similar to a prompt; implement it to plug in a vector store
+type DocumentRetriever = internal.DocumentRetriever
+
+// Request and result types
+type (
+	// MultiExecutionRequest describes a run: a prompt and the configurations to run it with
+	MultiExecutionRequest = types.MultiExecutionRequest
+	// APIConfiguration is one variation of a run: model, provider, prompts and sampling settings
+	APIConfiguration = types.APIConfiguration
+	// Tool is a function a variation's model may call
+	Tool = types.Tool
+	// SafetyPolicy is a provider-independent safety setting
+	SafetyPolicy = types.SafetyPolicy
+	// RedactionPolicy controls what of a request or response is stored
+	RedactionPolicy = types.RedactionPolicy
+	// RunSpec is a declarative run, as written in YAML or JSON spec files
+	RunSpec = types.RunSpec
+	// ExecutionRun is a stored run
+	ExecutionRun = types.ExecutionRun
+	// ExecutionResult is the outcome of a run and its variations
+	ExecutionResult = types.ExecutionResult
+	// VariationResult is the request, response and timing of one variation
+	VariationResult = types.VariationResult
+	// APIRequest is a request sent to a model
+	APIRequest = types.APIRequest
+	// APIResponse is a model's response
+	APIResponse = types.APIResponse
+	// FunctionCall records a function a model called and its result
+	FunctionCall = types.FunctionCall
+	// ResponseStatus is the outcome of a model call
+	ResponseStatus = types.ResponseStatus
+	// RetrievalConfig selects the document chunks added to a configuration's context
+	RetrievalConfig = types.RetrievalConfig
+	// RetrievalQuery is what a DocumentRetriever searches for
+	RetrievalQuery = types.RetrievalQuery
+	// RetrievedChunk is a document chunk retrieved for a request and its similarity score
+	RetrievedChunk = types.RetrievedChunk
+	// Document is an uploaded text indexed as embedded chunks for retrieval
+	Document = types.Document
+	// ChunkingConfig controls how an ingested document is split into chunks
+	ChunkingConfig = types.ChunkingConfig
+	// GuardConfig runs one guard on a configuration's prompt or response
+	GuardConfig = types.GuardConfig
+	// GuardVerdict is what one guard found in a request's prompt or response
+	GuardVerdict = types.GuardVerdict
+	// VariationDiff compares the responses and metrics of two configurations of a run
+	VariationDiff = types.VariationDiff
+	// DiffSegment is a run of text a diff keeps, inserts or deletes
+	DiffSegment = types.DiffSegment
+	// MetricDelta compares one metric of two variations
+	MetricDelta = types.MetricDelta
+	// TrendQuery selects the preset or variation name a trend follows across runs
+	TrendQuery = types.TrendQuery
+	// TrendPoint is one configuration's results in one run
+	TrendPoint = types.TrendPoint
+	// MetricDrift compares a trend's latest value of a metric with the runs before it
+	MetricDrift = types.MetricDrift
+	// ConfigurationTrend is a configuration's results across runs, oldest first
+	ConfigurationTrend = types.ConfigurationTrend
+	// LatencyBreakdown splits where a variation's time went
+	LatencyBreakdown = types.LatencyBreakdown
+	// ResponseFeedback is a person's thumbs, rating and comment on one variation response
+	ResponseFeedback = types.ResponseFeedback
+	// ConfigurationFeedback aggregates the feedback on one configuration's responses
+	ConfigurationFeedback = types.ConfigurationFeedback
+	// RunFeedback is the feedback on a run's responses along with its aggregate per configuration
+	RunFeedback = types.RunFeedback
+	// ReviewRequest starts a blind review of a run's responses
+	ReviewRequest = types.ReviewRequest
+	// Review is a blind review of a run's responses and its progress
+	Review = types.Review
+	// ReviewAssignment is one reviewer's part in a review
+	ReviewAssignment = types.ReviewAssignment
+	// ReviewItem is a response under review as reviewers see it
+	ReviewItem = types.ReviewItem
+	// ReviewJudgment is a reviewer's rank or label of one item
+	ReviewJudgment = types.ReviewJudgment
+	// ReviewTask is what a reviewer sees of a review
+	ReviewTask = types.ReviewTask
+	// ReviewItemResult is how reviewers judged one response, with its configuration revealed
+	ReviewItemResult = types.ReviewItemResult
+	// ReviewResults reveals the configurations behind a finished review, with the reviewers' agreement
+	ReviewResults = types.ReviewResults
+	// Workspace is a team whose members share execution runs, functions and presets
+	Workspace = types.Workspace
+	// WorkspaceMember is a user's membership of a workspace
+	WorkspaceMember = types.WorkspaceMember
+	// SharedResource is a resource its owner shared with a workspace
+	SharedResource = types.SharedResource
+	// UsageQuery selects the time range and grouping of a usage report
+	UsageQuery = types.UsageQuery
+	// UsageRow is the token usage of one group of responses
+	UsageRow = types.UsageRow
+	// UsageReport is a user's token usage over a time range
+	UsageReport = types.UsageReport
+	// ServerStats are figures across all users for server operators
+	ServerStats = types.ServerStats
+	// DailyRuns counts the execution runs created on one day
+	DailyRuns = types.DailyRuns
+	// ProviderErrorRate is the share of a model provider's responses that were errors
+	ProviderErrorRate = types.ProviderErrorRate
+	// TokenConsumer is a user's token usage, for ranking the heaviest users
+	TokenConsumer = types.TokenConsumer
+	// TableSize is the storage one database table takes
+	TableSize = types.TableSize
+	// AuditEvent is a security-relevant action recorded in the audit log
+	AuditEvent = types.AuditEvent
+	// AuditLogQuery filters the audit log
+	AuditLogQuery = types.AuditLogQuery
+	// LeaderboardQuery selects the tagged runs a leaderboard ranks configurations across
+	LeaderboardQuery = types.LeaderboardQuery
+	// LeaderboardEntry is one configuration's overall score across a tag's runs
+	LeaderboardEntry = types.LeaderboardEntry
+	// Leaderboard ranks configurations by their average overall score, best first
+	Leaderboard = types.Leaderboard
+	// HealthCheck is the outcome of checking one dependency
+	HealthCheck = types.HealthCheck
+	// HealthReport is the state of the service and of each dependency checked
+	HealthReport = types.HealthReport
+)
+
+// Response statuses
+const (
+	ResponseStatusSuccess = types.ResponseStatusSuccess
+	ResponseStatusError   = types.ResponseStatusError
+	ResponseStatusTimeout = types.ResponseStatusTimeout
+	ResponseStatusBlocked = types.ResponseStatusBlocked
+)
+
+// Thumbs a person can give a response
+const (
+	FeedbackThumbsUp   = types.FeedbackThumbsUp
+	FeedbackThumbsDown = types.FeedbackThumbsDown
+)
+
+// Usage report groupings
+const (
+	UsageGroupByDay   = types.UsageGroupByDay
+	UsageGroupByModel = types.UsageGroupByModel
+)
+
+// Audited actions
+const (
+	AuditLogin           = types.AuditLogin
+	AuditLoginFailed     = types.AuditLoginFailed
+	AuditAPIKeyCreated   = types.AuditAPIKeyCreated
+	AuditAPIKeyRevoked   = types.AuditAPIKeyRevoked
+	AuditFunctionCreated = types.AuditFunctionCreated
+	AuditFunctionUpdated = types.AuditFunctionUpdated
+	AuditFunctionDeleted = types.AuditFunctionDeleted
+	AuditRunDeleted      = types.AuditRunDeleted
+	AuditExport          = types.AuditExport
+)
+
+// Health states, from best to worst
+const (
+	HealthHealthy   = types.HealthHealthy
+	HealthDegraded  = types.HealthDegraded
+	HealthUnhealthy = types.HealthUnhealthy
+)
+
+// Blind review modes and statuses
+const (
+	ReviewModeRank        = types.ReviewModeRank
+	ReviewModeLabel       = types.ReviewModeLabel
+	ReviewStatusOpen      = types.ReviewStatusOpen
+	ReviewStatusCompleted = types.ReviewStatusCompleted
+	ReviewStatusClosed    = types.ReviewStatusClosed
+)
+
+// Workspace member roles and the resources a workspace can share
+const (
+	WorkspaceRoleOwner         = types.WorkspaceRoleOwner
+	WorkspaceRoleEditor        = types.WorkspaceRoleEditor
+	WorkspaceRoleViewer        = types.WorkspaceRoleViewer
+	SharedResourceExecutionRun = types.SharedResourceExecutionRun
+	SharedResourceFunction     = types.SharedResourceFunction
+	SharedResourcePreset       = types.SharedResourcePreset
+)
+
+// Providers and backends a configuration can select
+const (
+	ProviderOllama   = types.ProviderOllama
+	BackendGeminiAPI = types.BackendGeminiAPI
+	BackendVertex    = types.BackendVertex
+)
+
+// Function calling modes a configuration can set as its ToolChoiceMode
+const (
+	ToolChoiceAuto = types.ToolChoiceAuto
+	ToolChoiceAny  = types.ToolChoiceAny
+	ToolChoiceNone = types.ToolChoiceNone
+)
+
+// ErrNoDatabase is returned by features that need MySQL when the client was created without one
+var ErrNoDatabase = internal.ErrNoDatabase
+
+// ErrDocumentNotFound is returned when a user has no document with an ID
+var ErrDocumentNotFound = internal.ErrDocumentNotFound
+
+// ErrExecutionRunNotFound is returned when a run does not exist or is not owned by the user
+var ErrExecutionRunNotFound = internal.ErrExecutionRunNotFound
+
+// ErrConfigurationNotFound is returned by Client.DiffVariations for a configuration the run does not have
+var ErrConfigurationNotFound = internal.ErrConfigurationNotFound
+
+// ErrInvalidTrendQuery is returned by Client.GetConfigurationTrend for a query that selects no single preset or variation name
+var ErrInvalidTrendQuery = internal.ErrInvalidTrendQuery
+
+// ErrInvalidLeaderboardQuery is returned by Client.GetLeaderboard for a query without a valid tag or with an out of range limit
+var ErrInvalidLeaderboardQuery = internal.ErrInvalidLeaderboardQuery
+
+// ErrInvalidUsageQuery is returned by Client.GetUsage for an unknown grouping or an empty or too long range
+var ErrInvalidUsageQuery = internal.ErrInvalidUsageQuery
+
+// ErrInvalidFeedback is returned by Client.RecordResponseFeedback for an unknown thumb, a rating outside 1-5 or empty feedback
+var ErrInvalidFeedback = internal.ErrInvalidFeedback
+
+// ErrResponseNotFound is returned by Client.RecordResponseFeedback for a response the run does not have
+var ErrResponseNotFound = internal.ErrResponseNotFound
+
+// ErrFeedbackNotFound is returned by Client.DeleteResponseFeedback for a response without feedback
+var ErrFeedbackNotFound = internal.ErrFeedbackNotFound
+
+// ErrInvalidReview is returned by Client.CreateReview and Client.SubmitReviewJudgments for a request or judgments that cannot be accepted
+var ErrInvalidReview = internal.ErrInvalidReview
+
+// ErrReviewNotFound is returned for a review that does not exist or is neither owned by nor assigned to the user
+var ErrReviewNotFound = internal.ErrReviewNotFound
+
+// ErrReviewClosed is returned when judging or closing a review that is no longer open
+var ErrReviewClosed = internal.ErrReviewClosed
+
+// ErrReviewOpen is returned by Client.GetReviewResults while a review is still open
+var ErrReviewOpen = internal.ErrReviewOpen
+
+// ErrInvalidWorkspace is returned for a workspace name, role, resource type or membership change that cannot be accepted
+var ErrInvalidWorkspace = internal.ErrInvalidWorkspace
+
+// ErrWorkspaceNotFound is returned for a workspace that does not exist or that the user is not a member of
+var ErrWorkspaceNotFound = internal.ErrWorkspaceNotFound
+
+// ErrWorkspaceForbidden is returned when the user's workspace role does not allow a change
+var ErrWorkspaceForbidden = internal.ErrWorkspaceForbidden
+
+// ErrSharedResourceNotFound is returned for a resource the user cannot share, or that is not shared with the workspace
+var ErrSharedResourceNotFound = internal.ErrSharedResourceNotFound
+
+// ErrPromptBlocked is returned for a variation whose prompt an input guard blocked
+var ErrPromptBlocked = internal.ErrPromptBlocked
+
+// NewClient creates a client that stores execution records in the MySQL database at dbURL,
+// running migrations on connect
+func NewClient(dbURL string, config *Config) (*Client, error) {
+	return internal.NewClient(dbURL, config)
+}
+
+// NewInMemoryClient creates a client that keeps execution records in process. Without an API key
+// in config, model responses are mocked.
+func NewInMemoryClient(config *Config) *Client {
+	return internal.NewInMemoryClient(config)
+}
+
+// RegisterGuard makes a guard selectable by name in a configuration's guardrails
+func RegisterGuard(guard Guard) {
+	internal.RegisterGuard(guard)
+}
+
+// NewMemoryStore creates an empty in-process store
+func NewMemoryStore() *MemoryStore {
+	return internal.NewMemoryStore()
+}
+
+// ParseRunSpec parses and validates a run spec written as YAML or JSON
+func ParseRunSpec(data []byte) (*RunSpec, error) {
+	return internal.ParseRunSpec(data)
+}
+
+// RunSpecRequest returns the request a run spec declares
+func RunSpecRequest(spec *RunSpec) *MultiExecutionRequest {
+	return internal.RunSpecRequest(spec)
+}
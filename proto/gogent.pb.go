@@ -7,6 +7,7 @@
 package proto
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	structpb "google.golang.org/protobuf/types/known/structpb"
@@ -34,6 +35,7 @@ type User struct {
 	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
 	LastLoginAt   *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=last_login_at,json=lastLoginAt,proto3" json:"last_login_at,omitempty"`
+	Role          string                 `protobuf:"bytes,9,opt,name=role,proto3" json:"role,omitempty"` // "user" or "admin"
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -124,6 +126,13 @@ func (x *User) GetLastLoginAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *User) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
 // Authentication request for login
 type LoginRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -183,6 +192,7 @@ type LoginResponse struct {
 	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
 	User          *User                  `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
 	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	RefreshToken  string                 `protobuf:"bytes,4,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -238,6 +248,13 @@ func (x *LoginResponse) GetExpiresAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *LoginResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
 // Registration request
 type RegisterRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -304,6 +321,7 @@ type RegisterResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
 	Token         string                 `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	RefreshToken  string                 `protobuf:"bytes,3,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -352,6 +370,13 @@ func (x *RegisterResponse) GetToken() string {
 	return ""
 }
 
+func (x *RegisterResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
 // Create temporary user request
 type CreateTemporaryUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -403,6 +428,7 @@ type CreateTemporaryUserResponse struct {
 	User              *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
 	TemporaryPassword string                 `protobuf:"bytes,2,opt,name=temporary_password,json=temporaryPassword,proto3" json:"temporary_password,omitempty"`
 	Token             string                 `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
+	RefreshToken      string                 `protobuf:"bytes,4,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
 	unknownFields     protoimpl.UnknownFields
 	sizeCache         protoimpl.SizeCache
 }
@@ -458,6 +484,13 @@ func (x *CreateTemporaryUserResponse) GetToken() string {
 	return ""
 }
 
+func (x *CreateTemporaryUserResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
 // Save temporary account request
 type SaveTemporaryAccountRequest struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
@@ -662,27 +695,28 @@ func (x *VerifyEmailResponse) GetVerified() bool {
 	return false
 }
 
-// Get current user request
-type GetCurrentUserRequest struct {
+// Refresh token request
+type RefreshTokenRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	RefreshToken  string                 `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetCurrentUserRequest) Reset() {
-	*x = GetCurrentUserRequest{}
+func (x *RefreshTokenRequest) Reset() {
+	*x = RefreshTokenRequest{}
 	mi := &file_proto_gogent_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetCurrentUserRequest) String() string {
+func (x *RefreshTokenRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetCurrentUserRequest) ProtoMessage() {}
+func (*RefreshTokenRequest) ProtoMessage() {}
 
-func (x *GetCurrentUserRequest) ProtoReflect() protoreflect.Message {
+func (x *RefreshTokenRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_gogent_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -694,33 +728,43 @@ func (x *GetCurrentUserRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetCurrentUserRequest.ProtoReflect.Descriptor instead.
-func (*GetCurrentUserRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use RefreshTokenRequest.ProtoReflect.Descriptor instead.
+func (*RefreshTokenRequest) Descriptor() ([]byte, []int) {
 	return file_proto_gogent_proto_rawDescGZIP(), []int{11}
 }
 
-// Get current user response
-type GetCurrentUserResponse struct {
+func (x *RefreshTokenRequest) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+// Refresh token response with a new access token and rotated refresh token
+type RefreshTokenResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	RefreshToken  string                 `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	User          *User                  `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetCurrentUserResponse) Reset() {
-	*x = GetCurrentUserResponse{}
+func (x *RefreshTokenResponse) Reset() {
+	*x = RefreshTokenResponse{}
 	mi := &file_proto_gogent_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetCurrentUserResponse) String() string {
+func (x *RefreshTokenResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetCurrentUserResponse) ProtoMessage() {}
+func (*RefreshTokenResponse) ProtoMessage() {}
 
-func (x *GetCurrentUserResponse) ProtoReflect() protoreflect.Message {
+func (x *RefreshTokenResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_gogent_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -732,62 +776,61 @@ func (x *GetCurrentUserResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetCurrentUserResponse.ProtoReflect.Descriptor instead.
-func (*GetCurrentUserResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use RefreshTokenResponse.ProtoReflect.Descriptor instead.
+func (*RefreshTokenResponse) Descriptor() ([]byte, []int) {
 	return file_proto_gogent_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *GetCurrentUserResponse) GetUser() *User {
+func (x *RefreshTokenResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *RefreshTokenResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *RefreshTokenResponse) GetUser() *User {
 	if x != nil {
 		return x.User
 	}
 	return nil
 }
 
-// Multi-variation execution request
-type ExecuteRequest struct {
-	state                 protoimpl.MessageState `protogen:"open.v1"`
-	ExecutionRunName      string                 `protobuf:"bytes,1,opt,name=execution_run_name,json=executionRunName,proto3" json:"execution_run_name,omitempty"`
-	Description           string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
-	BasePrompt            string                 `protobuf:"bytes,3,opt,name=base_prompt,json=basePrompt,proto3" json:"base_prompt,omitempty"`
-	Context               string                 `protobuf:"bytes,4,opt,name=context,proto3" json:"context,omitempty"`
-	EnableFunctionCalling bool                   `protobuf:"varint,5,opt,name=enable_function_calling,json=enableFunctionCalling,proto3" json:"enable_function_calling,omitempty"`
-	Configurations        []*APIConfiguration    `protobuf:"bytes,6,rep,name=configurations,proto3" json:"configurations,omitempty"`
-	FunctionTools         []*Tool                `protobuf:"bytes,7,rep,name=function_tools,json=functionTools,proto3" json:"function_tools,omitempty"`
-	ComparisonConfig      *ComparisonConfig      `protobuf:"bytes,8,opt,name=comparison_config,json=comparisonConfig,proto3" json:"comparison_config,omitempty"`
-	UseMock               bool                   `protobuf:"varint,9,opt,name=use_mock,json=useMock,proto3" json:"use_mock,omitempty"`
-	// Session-based API keys (not stored on backend)
-	SessionApiKeys map[string]string `protobuf:"bytes,15,rep,name=session_api_keys,json=sessionApiKeys,proto3" json:"session_api_keys,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // All API keys for this session (gemini, openweather, neo4j, etc.)
-	// Legacy fields - deprecated, use session_api_keys instead
-	//
-	// Deprecated: Marked as deprecated in proto/gogent.proto.
-	OpenweatherApiKey string `protobuf:"bytes,10,opt,name=openweather_api_key,json=openweatherApiKey,proto3" json:"openweather_api_key,omitempty"`
-	// Deprecated: Marked as deprecated in proto/gogent.proto.
-	Neo4JUrl string `protobuf:"bytes,11,opt,name=neo4j_url,json=neo4jUrl,proto3" json:"neo4j_url,omitempty"`
-	// Deprecated: Marked as deprecated in proto/gogent.proto.
-	Neo4JUsername string `protobuf:"bytes,12,opt,name=neo4j_username,json=neo4jUsername,proto3" json:"neo4j_username,omitempty"`
-	// Deprecated: Marked as deprecated in proto/gogent.proto.
-	Neo4JPassword string `protobuf:"bytes,13,opt,name=neo4j_password,json=neo4jPassword,proto3" json:"neo4j_password,omitempty"`
-	// Deprecated: Marked as deprecated in proto/gogent.proto.
-	Neo4JDatabase string `protobuf:"bytes,14,opt,name=neo4j_database,json=neo4jDatabase,proto3" json:"neo4j_database,omitempty"`
+func (x *RefreshTokenResponse) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+// Logout request; revokes the caller's session, or all sessions when all_sessions is set
+type LogoutRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AllSessions   bool                   `protobuf:"varint,1,opt,name=all_sessions,json=allSessions,proto3" json:"all_sessions,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ExecuteRequest) Reset() {
-	*x = ExecuteRequest{}
+func (x *LogoutRequest) Reset() {
+	*x = LogoutRequest{}
 	mi := &file_proto_gogent_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ExecuteRequest) String() string {
+func (x *LogoutRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ExecuteRequest) ProtoMessage() {}
+func (*LogoutRequest) ProtoMessage() {}
 
-func (x *ExecuteRequest) ProtoReflect() protoreflect.Message {
+func (x *LogoutRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_gogent_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -799,146 +842,132 @@ func (x *ExecuteRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ExecuteRequest.ProtoReflect.Descriptor instead.
-func (*ExecuteRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use LogoutRequest.ProtoReflect.Descriptor instead.
+func (*LogoutRequest) Descriptor() ([]byte, []int) {
 	return file_proto_gogent_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *ExecuteRequest) GetExecutionRunName() string {
-	if x != nil {
-		return x.ExecutionRunName
-	}
-	return ""
-}
-
-func (x *ExecuteRequest) GetDescription() string {
+func (x *LogoutRequest) GetAllSessions() bool {
 	if x != nil {
-		return x.Description
+		return x.AllSessions
 	}
-	return ""
+	return false
 }
 
-func (x *ExecuteRequest) GetBasePrompt() string {
-	if x != nil {
-		return x.BasePrompt
-	}
-	return ""
+// Logout response
+type LogoutResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ExecuteRequest) GetContext() string {
-	if x != nil {
-		return x.Context
-	}
-	return ""
+func (x *LogoutResponse) Reset() {
+	*x = LogoutResponse{}
+	mi := &file_proto_gogent_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *ExecuteRequest) GetEnableFunctionCalling() bool {
-	if x != nil {
-		return x.EnableFunctionCalling
-	}
-	return false
+func (x *LogoutResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *ExecuteRequest) GetConfigurations() []*APIConfiguration {
-	if x != nil {
-		return x.Configurations
-	}
-	return nil
-}
+func (*LogoutResponse) ProtoMessage() {}
 
-func (x *ExecuteRequest) GetFunctionTools() []*Tool {
+func (x *LogoutResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[14]
 	if x != nil {
-		return x.FunctionTools
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *ExecuteRequest) GetComparisonConfig() *ComparisonConfig {
-	if x != nil {
-		return x.ComparisonConfig
-	}
-	return nil
+// Deprecated: Use LogoutResponse.ProtoReflect.Descriptor instead.
+func (*LogoutResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *ExecuteRequest) GetUseMock() bool {
+func (x *LogoutResponse) GetSuccess() bool {
 	if x != nil {
-		return x.UseMock
+		return x.Success
 	}
 	return false
 }
 
-func (x *ExecuteRequest) GetSessionApiKeys() map[string]string {
-	if x != nil {
-		return x.SessionApiKeys
-	}
-	return nil
+// Password reset request; always succeeds so callers cannot probe which emails exist
+type RequestPasswordResetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-// Deprecated: Marked as deprecated in proto/gogent.proto.
-func (x *ExecuteRequest) GetOpenweatherApiKey() string {
-	if x != nil {
-		return x.OpenweatherApiKey
-	}
-	return ""
+func (x *RequestPasswordResetRequest) Reset() {
+	*x = RequestPasswordResetRequest{}
+	mi := &file_proto_gogent_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-// Deprecated: Marked as deprecated in proto/gogent.proto.
-func (x *ExecuteRequest) GetNeo4JUrl() string {
-	if x != nil {
-		return x.Neo4JUrl
-	}
-	return ""
+func (x *RequestPasswordResetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-// Deprecated: Marked as deprecated in proto/gogent.proto.
-func (x *ExecuteRequest) GetNeo4JUsername() string {
+func (*RequestPasswordResetRequest) ProtoMessage() {}
+
+func (x *RequestPasswordResetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[15]
 	if x != nil {
-		return x.Neo4JUsername
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-// Deprecated: Marked as deprecated in proto/gogent.proto.
-func (x *ExecuteRequest) GetNeo4JPassword() string {
-	if x != nil {
-		return x.Neo4JPassword
-	}
-	return ""
+// Deprecated: Use RequestPasswordResetRequest.ProtoReflect.Descriptor instead.
+func (*RequestPasswordResetRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{15}
 }
 
-// Deprecated: Marked as deprecated in proto/gogent.proto.
-func (x *ExecuteRequest) GetNeo4JDatabase() string {
+func (x *RequestPasswordResetRequest) GetEmail() string {
 	if x != nil {
-		return x.Neo4JDatabase
+		return x.Email
 	}
 	return ""
 }
 
-// Execute response with execution ID
-type ExecuteResponse struct {
+// Password reset request response
+type RequestPasswordResetResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ExecutionId   string                 `protobuf:"bytes,1,opt,name=execution_id,json=executionId,proto3" json:"execution_id,omitempty"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	ExecutionRun  *ExecutionRun          `protobuf:"bytes,3,opt,name=execution_run,json=executionRun,proto3" json:"execution_run,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ExecuteResponse) Reset() {
-	*x = ExecuteResponse{}
-	mi := &file_proto_gogent_proto_msgTypes[14]
+func (x *RequestPasswordResetResponse) Reset() {
+	*x = RequestPasswordResetResponse{}
+	mi := &file_proto_gogent_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ExecuteResponse) String() string {
+func (x *RequestPasswordResetResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ExecuteResponse) ProtoMessage() {}
+func (*RequestPasswordResetResponse) ProtoMessage() {}
 
-func (x *ExecuteResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[14]
+func (x *RequestPasswordResetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -949,55 +978,49 @@ func (x *ExecuteResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ExecuteResponse.ProtoReflect.Descriptor instead.
-func (*ExecuteResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use RequestPasswordResetResponse.ProtoReflect.Descriptor instead.
+func (*RequestPasswordResetResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *ExecuteResponse) GetExecutionId() string {
+func (x *RequestPasswordResetResponse) GetSuccess() bool {
 	if x != nil {
-		return x.ExecutionId
+		return x.Success
 	}
-	return ""
+	return false
 }
 
-func (x *ExecuteResponse) GetMessage() string {
+func (x *RequestPasswordResetResponse) GetMessage() string {
 	if x != nil {
 		return x.Message
 	}
 	return ""
 }
 
-func (x *ExecuteResponse) GetExecutionRun() *ExecutionRun {
-	if x != nil {
-		return x.ExecutionRun
-	}
-	return nil
-}
-
-// Get execution status request
-type GetExecutionStatusRequest struct {
+// Reset password request using an emailed token
+type ResetPasswordRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ExecutionId   string                 `protobuf:"bytes,1,opt,name=execution_id,json=executionId,proto3" json:"execution_id,omitempty"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	NewPassword   string                 `protobuf:"bytes,2,opt,name=new_password,json=newPassword,proto3" json:"new_password,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetExecutionStatusRequest) Reset() {
-	*x = GetExecutionStatusRequest{}
-	mi := &file_proto_gogent_proto_msgTypes[15]
+func (x *ResetPasswordRequest) Reset() {
+	*x = ResetPasswordRequest{}
+	mi := &file_proto_gogent_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetExecutionStatusRequest) String() string {
+func (x *ResetPasswordRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetExecutionStatusRequest) ProtoMessage() {}
+func (*ResetPasswordRequest) ProtoMessage() {}
 
-func (x *GetExecutionStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[15]
+func (x *ResetPasswordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1008,45 +1031,48 @@ func (x *GetExecutionStatusRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetExecutionStatusRequest.ProtoReflect.Descriptor instead.
-func (*GetExecutionStatusRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use ResetPasswordRequest.ProtoReflect.Descriptor instead.
+func (*ResetPasswordRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *GetExecutionStatusRequest) GetExecutionId() string {
+func (x *ResetPasswordRequest) GetToken() string {
 	if x != nil {
-		return x.ExecutionId
+		return x.Token
 	}
 	return ""
 }
 
-// Get execution status response
-type GetExecutionStatusResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"` // pending, running, completed, failed
-	ErrorMessage  string                 `protobuf:"bytes,2,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
-	StartTime     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
-	EndTime       *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
-	Result        *ExecutionResult       `protobuf:"bytes,5,opt,name=result,proto3" json:"result,omitempty"` // Only populated when completed
+func (x *ResetPasswordRequest) GetNewPassword() string {
+	if x != nil {
+		return x.NewPassword
+	}
+	return ""
+}
+
+// Reset password response
+type ResetPasswordResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetExecutionStatusResponse) Reset() {
-	*x = GetExecutionStatusResponse{}
-	mi := &file_proto_gogent_proto_msgTypes[16]
+func (x *ResetPasswordResponse) Reset() {
+	*x = ResetPasswordResponse{}
+	mi := &file_proto_gogent_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetExecutionStatusResponse) String() string {
+func (x *ResetPasswordResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetExecutionStatusResponse) ProtoMessage() {}
+func (*ResetPasswordResponse) ProtoMessage() {}
 
-func (x *GetExecutionStatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[16]
+func (x *ResetPasswordResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1057,69 +1083,40 @@ func (x *GetExecutionStatusResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetExecutionStatusResponse.ProtoReflect.Descriptor instead.
-func (*GetExecutionStatusResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{16}
-}
-
-func (x *GetExecutionStatusResponse) GetStatus() string {
-	if x != nil {
-		return x.Status
-	}
-	return ""
-}
-
-func (x *GetExecutionStatusResponse) GetErrorMessage() string {
-	if x != nil {
-		return x.ErrorMessage
-	}
-	return ""
-}
-
-func (x *GetExecutionStatusResponse) GetStartTime() *timestamppb.Timestamp {
-	if x != nil {
-		return x.StartTime
-	}
-	return nil
-}
-
-func (x *GetExecutionStatusResponse) GetEndTime() *timestamppb.Timestamp {
-	if x != nil {
-		return x.EndTime
-	}
-	return nil
+// Deprecated: Use ResetPasswordResponse.ProtoReflect.Descriptor instead.
+func (*ResetPasswordResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{18}
 }
 
-func (x *GetExecutionStatusResponse) GetResult() *ExecutionResult {
+func (x *ResetPasswordResponse) GetUser() *User {
 	if x != nil {
-		return x.Result
+		return x.User
 	}
 	return nil
 }
 
-// Get execution result request
-type GetExecutionResultRequest struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	ExecutionRunId string                 `protobuf:"bytes,1,opt,name=execution_run_id,json=executionRunId,proto3" json:"execution_run_id,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+// Get current user request
+type GetCurrentUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetExecutionResultRequest) Reset() {
-	*x = GetExecutionResultRequest{}
-	mi := &file_proto_gogent_proto_msgTypes[17]
+func (x *GetCurrentUserRequest) Reset() {
+	*x = GetCurrentUserRequest{}
+	mi := &file_proto_gogent_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetExecutionResultRequest) String() string {
+func (x *GetCurrentUserRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetExecutionResultRequest) ProtoMessage() {}
+func (*GetCurrentUserRequest) ProtoMessage() {}
 
-func (x *GetExecutionResultRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[17]
+func (x *GetCurrentUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1130,41 +1127,34 @@ func (x *GetExecutionResultRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetExecutionResultRequest.ProtoReflect.Descriptor instead.
-func (*GetExecutionResultRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{17}
-}
-
-func (x *GetExecutionResultRequest) GetExecutionRunId() string {
-	if x != nil {
-		return x.ExecutionRunId
-	}
-	return ""
+// Deprecated: Use GetCurrentUserRequest.ProtoReflect.Descriptor instead.
+func (*GetCurrentUserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{19}
 }
 
-// Get execution result response
-type GetExecutionResultResponse struct {
+// Get current user response
+type GetCurrentUserResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Result        *ExecutionResult       `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetExecutionResultResponse) Reset() {
-	*x = GetExecutionResultResponse{}
-	mi := &file_proto_gogent_proto_msgTypes[18]
+func (x *GetCurrentUserResponse) Reset() {
+	*x = GetCurrentUserResponse{}
+	mi := &file_proto_gogent_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetExecutionResultResponse) String() string {
+func (x *GetCurrentUserResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetExecutionResultResponse) ProtoMessage() {}
+func (*GetCurrentUserResponse) ProtoMessage() {}
 
-func (x *GetExecutionResultResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[18]
+func (x *GetCurrentUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1175,42 +1165,88 @@ func (x *GetExecutionResultResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetExecutionResultResponse.ProtoReflect.Descriptor instead.
-func (*GetExecutionResultResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{18}
+// Deprecated: Use GetCurrentUserResponse.ProtoReflect.Descriptor instead.
+func (*GetCurrentUserResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *GetExecutionResultResponse) GetResult() *ExecutionResult {
+func (x *GetCurrentUserResponse) GetUser() *User {
 	if x != nil {
-		return x.Result
+		return x.User
 	}
 	return nil
 }
 
-// List execution runs request
-type ListExecutionRunsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
-	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+// Multi-variation execution request
+type ExecuteRequest struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	ExecutionRunName      string                 `protobuf:"bytes,1,opt,name=execution_run_name,json=executionRunName,proto3" json:"execution_run_name,omitempty"`
+	Description           string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	BasePrompt            string                 `protobuf:"bytes,3,opt,name=base_prompt,json=basePrompt,proto3" json:"base_prompt,omitempty"`
+	Context               string                 `protobuf:"bytes,4,opt,name=context,proto3" json:"context,omitempty"`
+	EnableFunctionCalling bool                   `protobuf:"varint,5,opt,name=enable_function_calling,json=enableFunctionCalling,proto3" json:"enable_function_calling,omitempty"`
+	Configurations        []*APIConfiguration    `protobuf:"bytes,6,rep,name=configurations,proto3" json:"configurations,omitempty"`
+	FunctionTools         []*Tool                `protobuf:"bytes,7,rep,name=function_tools,json=functionTools,proto3" json:"function_tools,omitempty"`
+	ComparisonConfig      *ComparisonConfig      `protobuf:"bytes,8,opt,name=comparison_config,json=comparisonConfig,proto3" json:"comparison_config,omitempty"`
+	UseMock               bool                   `protobuf:"varint,9,opt,name=use_mock,json=useMock,proto3" json:"use_mock,omitempty"`
+	// Session-based API keys (not stored on backend)
+	SessionApiKeys map[string]string `protobuf:"bytes,15,rep,name=session_api_keys,json=sessionApiKeys,proto3" json:"session_api_keys,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // All API keys for this session (gemini, openweather, neo4j, etc.)
+	// Run-wide function calling instruction for configurations that don't set their own
+	FunctionInstruction        string `protobuf:"bytes,16,opt,name=function_instruction,json=functionInstruction,proto3" json:"function_instruction,omitempty"`
+	DisableFunctionInstruction bool   `protobuf:"varint,17,opt,name=disable_function_instruction,json=disableFunctionInstruction,proto3" json:"disable_function_instruction,omitempty"`
+	// Run-wide safety policy for configurations that don't set their own
+	SafetyPolicy *SafetyPolicy `protobuf:"bytes,18,opt,name=safety_policy,json=safetyPolicy,proto3" json:"safety_policy,omitempty"`
+	// Deterministic mode: temperature 0, fixed seed and pinned tool responses
+	Deterministic bool   `protobuf:"varint,19,opt,name=deterministic,proto3" json:"deterministic,omitempty"`
+	Seed          *int32 `protobuf:"varint,20,opt,name=seed,proto3,oneof" json:"seed,omitempty"`
+	// Run naming: execution_run_name, or name_template when it is empty, may use
+	// the {suite}, {timestamp}, {date} and {git_sha} placeholders
+	NameTemplate string `protobuf:"bytes,21,opt,name=name_template,json=nameTemplate,proto3" json:"name_template,omitempty"`
+	Suite        string `protobuf:"bytes,22,opt,name=suite,proto3" json:"suite,omitempty"`
+	GitSha       string `protobuf:"bytes,23,opt,name=git_sha,json=gitSha,proto3" json:"git_sha,omitempty"`
+	// Handling of an identical submission within the duplicate window: allow, reject or merge
+	DuplicatePolicy string `protobuf:"bytes,24,opt,name=duplicate_policy,json=duplicatePolicy,proto3" json:"duplicate_policy,omitempty"`
+	// Number of times each configuration is executed; repeated samples are compared statistically
+	Repetitions int32 `protobuf:"varint,25,opt,name=repetitions,proto3" json:"repetitions,omitempty"`
+	// Golden answer every variation's response is scored against
+	ExpectedAnswer *ExpectedAnswer `protobuf:"bytes,26,opt,name=expected_answer,json=expectedAnswer,proto3" json:"expected_answer,omitempty"`
+	// Expands each configuration over a grid or random search of sampling parameters
+	Sweep *ParameterSweep `protobuf:"bytes,27,opt,name=sweep,proto3" json:"sweep,omitempty"`
+	// Position in the server's execution queue: high, normal (default) or low
+	Priority string `protobuf:"bytes,28,opt,name=priority,proto3" json:"priority,omitempty"`
+	// Labels grouping runs by prompt category, such as summarization, for the leaderboard
+	Tags []string `protobuf:"bytes,29,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Legacy fields - deprecated, use session_api_keys instead
+	//
+	// Deprecated: Marked as deprecated in proto/gogent.proto.
+	OpenweatherApiKey string `protobuf:"bytes,10,opt,name=openweather_api_key,json=openweatherApiKey,proto3" json:"openweather_api_key,omitempty"`
+	// Deprecated: Marked as deprecated in proto/gogent.proto.
+	Neo4JUrl string `protobuf:"bytes,11,opt,name=neo4j_url,json=neo4jUrl,proto3" json:"neo4j_url,omitempty"`
+	// Deprecated: Marked as deprecated in proto/gogent.proto.
+	Neo4JUsername string `protobuf:"bytes,12,opt,name=neo4j_username,json=neo4jUsername,proto3" json:"neo4j_username,omitempty"`
+	// Deprecated: Marked as deprecated in proto/gogent.proto.
+	Neo4JPassword string `protobuf:"bytes,13,opt,name=neo4j_password,json=neo4jPassword,proto3" json:"neo4j_password,omitempty"`
+	// Deprecated: Marked as deprecated in proto/gogent.proto.
+	Neo4JDatabase string `protobuf:"bytes,14,opt,name=neo4j_database,json=neo4jDatabase,proto3" json:"neo4j_database,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListExecutionRunsRequest) Reset() {
-	*x = ListExecutionRunsRequest{}
-	mi := &file_proto_gogent_proto_msgTypes[19]
+func (x *ExecuteRequest) Reset() {
+	*x = ExecuteRequest{}
+	mi := &file_proto_gogent_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListExecutionRunsRequest) String() string {
+func (x *ExecuteRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListExecutionRunsRequest) ProtoMessage() {}
+func (*ExecuteRequest) ProtoMessage() {}
 
-func (x *ListExecutionRunsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[19]
+func (x *ExecuteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1221,101 +1257,2733 @@ func (x *ListExecutionRunsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListExecutionRunsRequest.ProtoReflect.Descriptor instead.
-func (*ListExecutionRunsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{19}
+// Deprecated: Use ExecuteRequest.ProtoReflect.Descriptor instead.
+func (*ExecuteRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *ListExecutionRunsRequest) GetLimit() int32 {
+func (x *ExecuteRequest) GetExecutionRunName() string {
 	if x != nil {
-		return x.Limit
+		return x.ExecutionRunName
 	}
-	return 0
+	return ""
 }
 
-func (x *ListExecutionRunsRequest) GetOffset() int32 {
+func (x *ExecuteRequest) GetDescription() string {
 	if x != nil {
-		return x.Offset
+		return x.Description
 	}
-	return 0
+	return ""
 }
 
-// List execution runs response
-type ListExecutionRunsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ExecutionRuns []*ExecutionRun        `protobuf:"bytes,1,rep,name=execution_runs,json=executionRuns,proto3" json:"execution_runs,omitempty"`
-	TotalCount    int32                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *ExecuteRequest) GetBasePrompt() string {
+	if x != nil {
+		return x.BasePrompt
+	}
+	return ""
 }
 
-func (x *ListExecutionRunsResponse) Reset() {
-	*x = ListExecutionRunsResponse{}
-	mi := &file_proto_gogent_proto_msgTypes[20]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *ExecuteRequest) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
 }
 
-func (x *ListExecutionRunsResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *ExecuteRequest) GetEnableFunctionCalling() bool {
+	if x != nil {
+		return x.EnableFunctionCalling
+	}
+	return false
 }
 
-func (*ListExecutionRunsResponse) ProtoMessage() {}
-
-func (x *ListExecutionRunsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[20]
+func (x *ExecuteRequest) GetConfigurations() []*APIConfiguration {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Configurations
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use ListExecutionRunsResponse.ProtoReflect.Descriptor instead.
-func (*ListExecutionRunsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{20}
+func (x *ExecuteRequest) GetFunctionTools() []*Tool {
+	if x != nil {
+		return x.FunctionTools
+	}
+	return nil
 }
 
-func (x *ListExecutionRunsResponse) GetExecutionRuns() []*ExecutionRun {
+func (x *ExecuteRequest) GetComparisonConfig() *ComparisonConfig {
 	if x != nil {
-		return x.ExecutionRuns
+		return x.ComparisonConfig
 	}
 	return nil
 }
 
-func (x *ListExecutionRunsResponse) GetTotalCount() int32 {
+func (x *ExecuteRequest) GetUseMock() bool {
 	if x != nil {
-		return x.TotalCount
+		return x.UseMock
 	}
-	return 0
+	return false
 }
 
-// Delete execution run request
-type DeleteExecutionRunRequest struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	ExecutionRunId string                 `protobuf:"bytes,1,opt,name=execution_run_id,json=executionRunId,proto3" json:"execution_run_id,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+func (x *ExecuteRequest) GetSessionApiKeys() map[string]string {
+	if x != nil {
+		return x.SessionApiKeys
+	}
+	return nil
 }
 
-func (x *DeleteExecutionRunRequest) Reset() {
+func (x *ExecuteRequest) GetFunctionInstruction() string {
+	if x != nil {
+		return x.FunctionInstruction
+	}
+	return ""
+}
+
+func (x *ExecuteRequest) GetDisableFunctionInstruction() bool {
+	if x != nil {
+		return x.DisableFunctionInstruction
+	}
+	return false
+}
+
+func (x *ExecuteRequest) GetSafetyPolicy() *SafetyPolicy {
+	if x != nil {
+		return x.SafetyPolicy
+	}
+	return nil
+}
+
+func (x *ExecuteRequest) GetDeterministic() bool {
+	if x != nil {
+		return x.Deterministic
+	}
+	return false
+}
+
+func (x *ExecuteRequest) GetSeed() int32 {
+	if x != nil && x.Seed != nil {
+		return *x.Seed
+	}
+	return 0
+}
+
+func (x *ExecuteRequest) GetNameTemplate() string {
+	if x != nil {
+		return x.NameTemplate
+	}
+	return ""
+}
+
+func (x *ExecuteRequest) GetSuite() string {
+	if x != nil {
+		return x.Suite
+	}
+	return ""
+}
+
+func (x *ExecuteRequest) GetGitSha() string {
+	if x != nil {
+		return x.GitSha
+	}
+	return ""
+}
+
+func (x *ExecuteRequest) GetDuplicatePolicy() string {
+	if x != nil {
+		return x.DuplicatePolicy
+	}
+	return ""
+}
+
+func (x *ExecuteRequest) GetRepetitions() int32 {
+	if x != nil {
+		return x.Repetitions
+	}
+	return 0
+}
+
+func (x *ExecuteRequest) GetExpectedAnswer() *ExpectedAnswer {
+	if x != nil {
+		return x.ExpectedAnswer
+	}
+	return nil
+}
+
+func (x *ExecuteRequest) GetSweep() *ParameterSweep {
+	if x != nil {
+		return x.Sweep
+	}
+	return nil
+}
+
+func (x *ExecuteRequest) GetPriority() string {
+	if x != nil {
+		return x.Priority
+	}
+	return ""
+}
+
+func (x *ExecuteRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+// Deprecated: Marked as deprecated in proto/gogent.proto.
+func (x *ExecuteRequest) GetOpenweatherApiKey() string {
+	if x != nil {
+		return x.OpenweatherApiKey
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in proto/gogent.proto.
+func (x *ExecuteRequest) GetNeo4JUrl() string {
+	if x != nil {
+		return x.Neo4JUrl
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in proto/gogent.proto.
+func (x *ExecuteRequest) GetNeo4JUsername() string {
+	if x != nil {
+		return x.Neo4JUsername
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in proto/gogent.proto.
+func (x *ExecuteRequest) GetNeo4JPassword() string {
+	if x != nil {
+		return x.Neo4JPassword
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in proto/gogent.proto.
+func (x *ExecuteRequest) GetNeo4JDatabase() string {
+	if x != nil {
+		return x.Neo4JDatabase
+	}
+	return ""
+}
+
+// Execute response with execution ID
+type ExecuteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ExecutionId   string                 `protobuf:"bytes,1,opt,name=execution_id,json=executionId,proto3" json:"execution_id,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	ExecutionRun  *ExecutionRun          `protobuf:"bytes,3,opt,name=execution_run,json=executionRun,proto3" json:"execution_run,omitempty"`
+	Merged        bool                   `protobuf:"varint,4,opt,name=merged,proto3" json:"merged,omitempty"` // True when an identical earlier run was returned instead of starting a new one
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecuteResponse) Reset() {
+	*x = ExecuteResponse{}
+	mi := &file_proto_gogent_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteResponse) ProtoMessage() {}
+
+func (x *ExecuteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteResponse.ProtoReflect.Descriptor instead.
+func (*ExecuteResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ExecuteResponse) GetExecutionId() string {
+	if x != nil {
+		return x.ExecutionId
+	}
+	return ""
+}
+
+func (x *ExecuteResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ExecuteResponse) GetExecutionRun() *ExecutionRun {
+	if x != nil {
+		return x.ExecutionRun
+	}
+	return nil
+}
+
+func (x *ExecuteResponse) GetMerged() bool {
+	if x != nil {
+		return x.Merged
+	}
+	return false
+}
+
+// Get execution status request
+type GetExecutionStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ExecutionId   string                 `protobuf:"bytes,1,opt,name=execution_id,json=executionId,proto3" json:"execution_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetExecutionStatusRequest) Reset() {
+	*x = GetExecutionStatusRequest{}
+	mi := &file_proto_gogent_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetExecutionStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetExecutionStatusRequest) ProtoMessage() {}
+
+func (x *GetExecutionStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetExecutionStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetExecutionStatusRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetExecutionStatusRequest) GetExecutionId() string {
+	if x != nil {
+		return x.ExecutionId
+	}
+	return ""
+}
+
+// Get execution status response
+type GetExecutionStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"` // pending, running, completed, failed
+	ErrorMessage  string                 `protobuf:"bytes,2,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	StartTime     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	Result        *ExecutionResult       `protobuf:"bytes,5,opt,name=result,proto3" json:"result,omitempty"`                                     // Only populated when completed
+	QueuePosition int32                  `protobuf:"varint,6,opt,name=queue_position,json=queuePosition,proto3" json:"queue_position,omitempty"` // Set while the execution waits in the queue
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetExecutionStatusResponse) Reset() {
+	*x = GetExecutionStatusResponse{}
+	mi := &file_proto_gogent_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetExecutionStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetExecutionStatusResponse) ProtoMessage() {}
+
+func (x *GetExecutionStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetExecutionStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetExecutionStatusResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetExecutionStatusResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GetExecutionStatusResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *GetExecutionStatusResponse) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *GetExecutionStatusResponse) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *GetExecutionStatusResponse) GetResult() *ExecutionResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *GetExecutionStatusResponse) GetQueuePosition() int32 {
+	if x != nil {
+		return x.QueuePosition
+	}
+	return 0
+}
+
+// Get execution result request
+type GetExecutionResultRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ExecutionRunId string                 `protobuf:"bytes,1,opt,name=execution_run_id,json=executionRunId,proto3" json:"execution_run_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetExecutionResultRequest) Reset() {
+	*x = GetExecutionResultRequest{}
+	mi := &file_proto_gogent_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetExecutionResultRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetExecutionResultRequest) ProtoMessage() {}
+
+func (x *GetExecutionResultRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetExecutionResultRequest.ProtoReflect.Descriptor instead.
+func (*GetExecutionResultRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetExecutionResultRequest) GetExecutionRunId() string {
+	if x != nil {
+		return x.ExecutionRunId
+	}
+	return ""
+}
+
+// Get execution result response
+type GetExecutionResultResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        *ExecutionResult       `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetExecutionResultResponse) Reset() {
+	*x = GetExecutionResultResponse{}
+	mi := &file_proto_gogent_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetExecutionResultResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetExecutionResultResponse) ProtoMessage() {}
+
+func (x *GetExecutionResultResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetExecutionResultResponse.ProtoReflect.Descriptor instead.
+func (*GetExecutionResultResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetExecutionResultResponse) GetResult() *ExecutionResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+// List execution runs request
+type ListExecutionRunsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	AllUsers      bool                   `protobuf:"varint,3,opt,name=all_users,json=allUsers,proto3" json:"all_users,omitempty"` // Admin only: list runs from every user
+	Cursor        string                 `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"`                      // next_cursor of the previous page; overrides offset
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListExecutionRunsRequest) Reset() {
+	*x = ListExecutionRunsRequest{}
+	mi := &file_proto_gogent_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListExecutionRunsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListExecutionRunsRequest) ProtoMessage() {}
+
+func (x *ListExecutionRunsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListExecutionRunsRequest.ProtoReflect.Descriptor instead.
+func (*ListExecutionRunsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ListExecutionRunsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListExecutionRunsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListExecutionRunsRequest) GetAllUsers() bool {
+	if x != nil {
+		return x.AllUsers
+	}
+	return false
+}
+
+func (x *ListExecutionRunsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+// List execution runs response
+type ListExecutionRunsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ExecutionRuns []*ExecutionRun        `protobuf:"bytes,1,rep,name=execution_runs,json=executionRuns,proto3" json:"execution_runs,omitempty"`
+	TotalCount    int32                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"` // Runs across all pages
+	NextCursor    string                 `protobuf:"bytes,3,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`  // Empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListExecutionRunsResponse) Reset() {
+	*x = ListExecutionRunsResponse{}
+	mi := &file_proto_gogent_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListExecutionRunsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListExecutionRunsResponse) ProtoMessage() {}
+
+func (x *ListExecutionRunsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListExecutionRunsResponse.ProtoReflect.Descriptor instead.
+func (*ListExecutionRunsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListExecutionRunsResponse) GetExecutionRuns() []*ExecutionRun {
+	if x != nil {
+		return x.ExecutionRuns
+	}
+	return nil
+}
+
+func (x *ListExecutionRunsResponse) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *ListExecutionRunsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+// List comparisons request
+type ListComparisonsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Cursor        string                 `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"` // next_cursor of the previous page; overrides offset
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListComparisonsRequest) Reset() {
+	*x = ListComparisonsRequest{}
+	mi := &file_proto_gogent_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListComparisonsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListComparisonsRequest) ProtoMessage() {}
+
+func (x *ListComparisonsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListComparisonsRequest.ProtoReflect.Descriptor instead.
+func (*ListComparisonsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ListComparisonsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListComparisonsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListComparisonsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+// List comparisons response
+type ListComparisonsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Comparisons   []*ComparisonResult    `protobuf:"bytes,1,rep,name=comparisons,proto3" json:"comparisons,omitempty"`
+	TotalCount    int32                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"` // Comparisons across all pages
+	NextCursor    string                 `protobuf:"bytes,3,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`  // Empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListComparisonsResponse) Reset() {
+	*x = ListComparisonsResponse{}
+	mi := &file_proto_gogent_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListComparisonsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListComparisonsResponse) ProtoMessage() {}
+
+func (x *ListComparisonsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListComparisonsResponse.ProtoReflect.Descriptor instead.
+func (*ListComparisonsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ListComparisonsResponse) GetComparisons() []*ComparisonResult {
+	if x != nil {
+		return x.Comparisons
+	}
+	return nil
+}
+
+func (x *ListComparisonsResponse) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *ListComparisonsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+// Page through the log entries of a run, oldest first
+type ListExecutionLogsRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ExecutionRunId string                 `protobuf:"bytes,1,opt,name=execution_run_id,json=executionRunId,proto3" json:"execution_run_id,omitempty"`
+	Level          string                 `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`       // Keep entries at or above this level: DEBUG, INFO, WARN or ERROR
+	Category       string                 `protobuf:"bytes,3,opt,name=category,proto3" json:"category,omitempty"` // Keep entries of this category
+	After          string                 `protobuf:"bytes,4,opt,name=after,proto3" json:"after,omitempty"`       // Continue after this log entry ID
+	Limit          int32                  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`      // Defaults to 100, at most 500
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListExecutionLogsRequest) Reset() {
+	*x = ListExecutionLogsRequest{}
+	mi := &file_proto_gogent_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListExecutionLogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListExecutionLogsRequest) ProtoMessage() {}
+
+func (x *ListExecutionLogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListExecutionLogsRequest.ProtoReflect.Descriptor instead.
+func (*ListExecutionLogsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ListExecutionLogsRequest) GetExecutionRunId() string {
+	if x != nil {
+		return x.ExecutionRunId
+	}
+	return ""
+}
+
+func (x *ListExecutionLogsRequest) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *ListExecutionLogsRequest) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *ListExecutionLogsRequest) GetAfter() string {
+	if x != nil {
+		return x.After
+	}
+	return ""
+}
+
+func (x *ListExecutionLogsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListExecutionLogsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Logs          []*ExecutionLog        `protobuf:"bytes,1,rep,name=logs,proto3" json:"logs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListExecutionLogsResponse) Reset() {
+	*x = ListExecutionLogsResponse{}
+	mi := &file_proto_gogent_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListExecutionLogsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListExecutionLogsResponse) ProtoMessage() {}
+
+func (x *ListExecutionLogsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListExecutionLogsResponse.ProtoReflect.Descriptor instead.
+func (*ListExecutionLogsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ListExecutionLogsResponse) GetLogs() []*ExecutionLog {
+	if x != nil {
+		return x.Logs
+	}
+	return nil
+}
+
+// Watch an execution request
+type WatchExecutionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ExecutionId   string                 `protobuf:"bytes,1,opt,name=execution_id,json=executionId,proto3" json:"execution_id,omitempty"` // From Execute, or the ID of a stored run
+	LogLevel      string                 `protobuf:"bytes,2,opt,name=log_level,json=logLevel,proto3" json:"log_level,omitempty"`          // Stream log entries at or above this level: DEBUG, INFO, WARN or ERROR; none when empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchExecutionRequest) Reset() {
+	*x = WatchExecutionRequest{}
+	mi := &file_proto_gogent_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchExecutionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchExecutionRequest) ProtoMessage() {}
+
+func (x *WatchExecutionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchExecutionRequest.ProtoReflect.Descriptor instead.
+func (*WatchExecutionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *WatchExecutionRequest) GetExecutionId() string {
+	if x != nil {
+		return x.ExecutionId
+	}
+	return ""
+}
+
+func (x *WatchExecutionRequest) GetLogLevel() string {
+	if x != nil {
+		return x.LogLevel
+	}
+	return ""
+}
+
+// An execution's state, sent whenever it changes and whenever new log entries are written
+type ExecutionEvent struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	ExecutionId         string                 `protobuf:"bytes,1,opt,name=execution_id,json=executionId,proto3" json:"execution_id,omitempty"`
+	ExecutionRunId      string                 `protobuf:"bytes,2,opt,name=execution_run_id,json=executionRunId,proto3" json:"execution_run_id,omitempty"`               // Set once the run is created
+	Status              string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`                                                       // pending, running, completed, failed
+	QueuePosition       int32                  `protobuf:"varint,4,opt,name=queue_position,json=queuePosition,proto3" json:"queue_position,omitempty"`                   // Set while the execution waits in the queue
+	TotalVariations     int32                  `protobuf:"varint,5,opt,name=total_variations,json=totalVariations,proto3" json:"total_variations,omitempty"`             // Counting each repetition
+	CompletedVariations int32                  `protobuf:"varint,6,opt,name=completed_variations,json=completedVariations,proto3" json:"completed_variations,omitempty"` // Finished, successfully or not
+	FailedVariations    int32                  `protobuf:"varint,7,opt,name=failed_variations,json=failedVariations,proto3" json:"failed_variations,omitempty"`
+	LastVariation       string                 `protobuf:"bytes,8,opt,name=last_variation,json=lastVariation,proto3" json:"last_variation,omitempty"` // Label of the variation that finished last
+	Logs                []*ExecutionLog        `protobuf:"bytes,9,rep,name=logs,proto3" json:"logs,omitempty"`                                        // Entries written since the previous event
+	ErrorMessage        string                 `protobuf:"bytes,10,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	Result              *ExecutionResult       `protobuf:"bytes,11,opt,name=result,proto3" json:"result,omitempty"` // Set on the final event of a completed execution
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *ExecutionEvent) Reset() {
+	*x = ExecutionEvent{}
+	mi := &file_proto_gogent_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecutionEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecutionEvent) ProtoMessage() {}
+
+func (x *ExecutionEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecutionEvent.ProtoReflect.Descriptor instead.
+func (*ExecutionEvent) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ExecutionEvent) GetExecutionId() string {
+	if x != nil {
+		return x.ExecutionId
+	}
+	return ""
+}
+
+func (x *ExecutionEvent) GetExecutionRunId() string {
+	if x != nil {
+		return x.ExecutionRunId
+	}
+	return ""
+}
+
+func (x *ExecutionEvent) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ExecutionEvent) GetQueuePosition() int32 {
+	if x != nil {
+		return x.QueuePosition
+	}
+	return 0
+}
+
+func (x *ExecutionEvent) GetTotalVariations() int32 {
+	if x != nil {
+		return x.TotalVariations
+	}
+	return 0
+}
+
+func (x *ExecutionEvent) GetCompletedVariations() int32 {
+	if x != nil {
+		return x.CompletedVariations
+	}
+	return 0
+}
+
+func (x *ExecutionEvent) GetFailedVariations() int32 {
+	if x != nil {
+		return x.FailedVariations
+	}
+	return 0
+}
+
+func (x *ExecutionEvent) GetLastVariation() string {
+	if x != nil {
+		return x.LastVariation
+	}
+	return ""
+}
+
+func (x *ExecutionEvent) GetLogs() []*ExecutionLog {
+	if x != nil {
+		return x.Logs
+	}
+	return nil
+}
+
+func (x *ExecutionEvent) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *ExecutionEvent) GetResult() *ExecutionResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+// Get the comparison of a run request
+type GetComparisonRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ExecutionRunId string                 `protobuf:"bytes,1,opt,name=execution_run_id,json=executionRunId,proto3" json:"execution_run_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetComparisonRequest) Reset() {
+	*x = GetComparisonRequest{}
+	mi := &file_proto_gogent_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetComparisonRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetComparisonRequest) ProtoMessage() {}
+
+func (x *GetComparisonRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetComparisonRequest.ProtoReflect.Descriptor instead.
+func (*GetComparisonRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *GetComparisonRequest) GetExecutionRunId() string {
+	if x != nil {
+		return x.ExecutionRunId
+	}
+	return ""
+}
+
+type GetComparisonResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Comparison    *ComparisonResult      `protobuf:"bytes,1,opt,name=comparison,proto3" json:"comparison,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetComparisonResponse) Reset() {
+	*x = GetComparisonResponse{}
+	mi := &file_proto_gogent_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetComparisonResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetComparisonResponse) ProtoMessage() {}
+
+func (x *GetComparisonResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetComparisonResponse.ProtoReflect.Descriptor instead.
+func (*GetComparisonResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *GetComparisonResponse) GetComparison() *ComparisonResult {
+	if x != nil {
+		return x.Comparison
+	}
+	return nil
+}
+
+// Delete execution run request
+type DeleteExecutionRunRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ExecutionRunId string                 `protobuf:"bytes,1,opt,name=execution_run_id,json=executionRunId,proto3" json:"execution_run_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DeleteExecutionRunRequest) Reset() {
 	*x = DeleteExecutionRunRequest{}
-	mi := &file_proto_gogent_proto_msgTypes[21]
+	mi := &file_proto_gogent_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteExecutionRunRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteExecutionRunRequest) ProtoMessage() {}
+
+func (x *DeleteExecutionRunRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteExecutionRunRequest.ProtoReflect.Descriptor instead.
+func (*DeleteExecutionRunRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *DeleteExecutionRunRequest) GetExecutionRunId() string {
+	if x != nil {
+		return x.ExecutionRunId
+	}
+	return ""
+}
+
+// Delete execution run response
+type DeleteExecutionRunResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteExecutionRunResponse) Reset() {
+	*x = DeleteExecutionRunResponse{}
+	mi := &file_proto_gogent_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteExecutionRunResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteExecutionRunResponse) ProtoMessage() {}
+
+func (x *DeleteExecutionRunResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteExecutionRunResponse.ProtoReflect.Descriptor instead.
+func (*DeleteExecutionRunResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *DeleteExecutionRunResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// Dataset item of a batch run; its prompt replaces the template's base prompt
+type BatchItem struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ExternalId     string                 `protobuf:"bytes,1,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"` // Caller's identifier for the item
+	Prompt         string                 `protobuf:"bytes,2,opt,name=prompt,proto3" json:"prompt,omitempty"`
+	Context        string                 `protobuf:"bytes,3,opt,name=context,proto3" json:"context,omitempty"` // Overrides the template context when set
+	Metadata       map[string]string      `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	ExpectedAnswer *ExpectedAnswer        `protobuf:"bytes,5,opt,name=expected_answer,json=expectedAnswer,proto3" json:"expected_answer,omitempty"` // Overrides the template's expected answer when set
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *BatchItem) Reset() {
+	*x = BatchItem{}
+	mi := &file_proto_gogent_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchItem) ProtoMessage() {}
+
+func (x *BatchItem) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchItem.ProtoReflect.Descriptor instead.
+func (*BatchItem) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *BatchItem) GetExternalId() string {
+	if x != nil {
+		return x.ExternalId
+	}
+	return ""
+}
+
+func (x *BatchItem) GetPrompt() string {
+	if x != nil {
+		return x.Prompt
+	}
+	return ""
+}
+
+func (x *BatchItem) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *BatchItem) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *BatchItem) GetExpectedAnswer() *ExpectedAnswer {
+	if x != nil {
+		return x.ExpectedAnswer
+	}
+	return nil
+}
+
+// Golden answer that responses are scored against
+type ExpectedAnswer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Answer        string                 `protobuf:"bytes,1,opt,name=answer,proto3" json:"answer,omitempty"`
+	MatchMode     string                 `protobuf:"bytes,2,opt,name=match_mode,json=matchMode,proto3" json:"match_mode,omitempty"` // exact (default), substring, regex or embedding
+	Threshold     float64                `protobuf:"fixed64,3,opt,name=threshold,proto3" json:"threshold,omitempty"`                // Minimum similarity for embedding matches; defaults to 0.8
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExpectedAnswer) Reset() {
+	*x = ExpectedAnswer{}
+	mi := &file_proto_gogent_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExpectedAnswer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExpectedAnswer) ProtoMessage() {}
+
+func (x *ExpectedAnswer) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExpectedAnswer.ProtoReflect.Descriptor instead.
+func (*ExpectedAnswer) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ExpectedAnswer) GetAnswer() string {
+	if x != nil {
+		return x.Answer
+	}
+	return ""
+}
+
+func (x *ExpectedAnswer) GetMatchMode() string {
+	if x != nil {
+		return x.MatchMode
+	}
+	return ""
+}
+
+func (x *ExpectedAnswer) GetThreshold() float64 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+// Share of a configuration's evaluated responses that matched the expected answer
+type ConfigurationAccuracy struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	VariationName   string                 `protobuf:"bytes,1,opt,name=variation_name,json=variationName,proto3" json:"variation_name,omitempty"`
+	ConfigurationId string                 `protobuf:"bytes,2,opt,name=configuration_id,json=configurationId,proto3" json:"configuration_id,omitempty"` // Empty when aggregated across a batch's runs
+	Cases           int32                  `protobuf:"varint,3,opt,name=cases,proto3" json:"cases,omitempty"`
+	Passed          int32                  `protobuf:"varint,4,opt,name=passed,proto3" json:"passed,omitempty"`
+	Accuracy        float64                `protobuf:"fixed64,5,opt,name=accuracy,proto3" json:"accuracy,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ConfigurationAccuracy) Reset() {
+	*x = ConfigurationAccuracy{}
+	mi := &file_proto_gogent_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfigurationAccuracy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigurationAccuracy) ProtoMessage() {}
+
+func (x *ConfigurationAccuracy) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigurationAccuracy.ProtoReflect.Descriptor instead.
+func (*ConfigurationAccuracy) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ConfigurationAccuracy) GetVariationName() string {
+	if x != nil {
+		return x.VariationName
+	}
+	return ""
+}
+
+func (x *ConfigurationAccuracy) GetConfigurationId() string {
+	if x != nil {
+		return x.ConfigurationId
+	}
+	return ""
+}
+
+func (x *ConfigurationAccuracy) GetCases() int32 {
+	if x != nil {
+		return x.Cases
+	}
+	return 0
+}
+
+func (x *ConfigurationAccuracy) GetPassed() int32 {
+	if x != nil {
+		return x.Passed
+	}
+	return 0
+}
+
+func (x *ConfigurationAccuracy) GetAccuracy() float64 {
+	if x != nil {
+		return x.Accuracy
+	}
+	return 0
+}
+
+// Grid or random search over sampling parameters
+type ParameterSweep struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mode          string                 `protobuf:"bytes,1,opt,name=mode,proto3" json:"mode,omitempty"`        // grid (default) or random
+	Samples       int32                  `protobuf:"varint,2,opt,name=samples,proto3" json:"samples,omitempty"` // Points drawn in random mode
+	Seed          int64                  `protobuf:"varint,3,opt,name=seed,proto3" json:"seed,omitempty"`       // Random mode seed; 0 picks one
+	Temperature   *SweepRange            `protobuf:"bytes,4,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	TopP          *SweepRange            `protobuf:"bytes,5,opt,name=top_p,json=topP,proto3" json:"top_p,omitempty"`
+	TopK          *SweepRange            `protobuf:"bytes,6,opt,name=top_k,json=topK,proto3" json:"top_k,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ParameterSweep) Reset() {
+	*x = ParameterSweep{}
+	mi := &file_proto_gogent_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParameterSweep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParameterSweep) ProtoMessage() {}
+
+func (x *ParameterSweep) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParameterSweep.ProtoReflect.Descriptor instead.
+func (*ParameterSweep) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ParameterSweep) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *ParameterSweep) GetSamples() int32 {
+	if x != nil {
+		return x.Samples
+	}
+	return 0
+}
+
+func (x *ParameterSweep) GetSeed() int64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+func (x *ParameterSweep) GetTemperature() *SweepRange {
+	if x != nil {
+		return x.Temperature
+	}
+	return nil
+}
+
+func (x *ParameterSweep) GetTopP() *SweepRange {
+	if x != nil {
+		return x.TopP
+	}
+	return nil
+}
+
+func (x *ParameterSweep) GetTopK() *SweepRange {
+	if x != nil {
+		return x.TopK
+	}
+	return nil
+}
+
+// Values a swept parameter takes: explicit values, or min..max in steps
+type SweepRange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Min           float64                `protobuf:"fixed64,1,opt,name=min,proto3" json:"min,omitempty"`
+	Max           float64                `protobuf:"fixed64,2,opt,name=max,proto3" json:"max,omitempty"`
+	Step          float64                `protobuf:"fixed64,3,opt,name=step,proto3" json:"step,omitempty"`
+	Values        []float64              `protobuf:"fixed64,4,rep,packed,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SweepRange) Reset() {
+	*x = SweepRange{}
+	mi := &file_proto_gogent_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SweepRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SweepRange) ProtoMessage() {}
+
+func (x *SweepRange) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SweepRange.ProtoReflect.Descriptor instead.
+func (*SweepRange) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *SweepRange) GetMin() float64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *SweepRange) GetMax() float64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *SweepRange) GetStep() float64 {
+	if x != nil {
+		return x.Step
+	}
+	return 0
+}
+
+func (x *SweepRange) GetValues() []float64 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+// Scores of a sweep's configurations across parameter space
+type SweepReport struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Metric        string                 `protobuf:"bytes,1,opt,name=metric,proto3" json:"metric,omitempty"`
+	Points        []*SweepPoint          `protobuf:"bytes,2,rep,name=points,proto3" json:"points,omitempty"` // Best first
+	Parameters    []*ParameterSummary    `protobuf:"bytes,3,rep,name=parameters,proto3" json:"parameters,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SweepReport) Reset() {
+	*x = SweepReport{}
+	mi := &file_proto_gogent_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SweepReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SweepReport) ProtoMessage() {}
+
+func (x *SweepReport) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SweepReport.ProtoReflect.Descriptor instead.
+func (*SweepReport) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *SweepReport) GetMetric() string {
+	if x != nil {
+		return x.Metric
+	}
+	return ""
+}
+
+func (x *SweepReport) GetPoints() []*SweepPoint {
+	if x != nil {
+		return x.Points
+	}
+	return nil
+}
+
+func (x *SweepReport) GetParameters() []*ParameterSummary {
+	if x != nil {
+		return x.Parameters
+	}
+	return nil
+}
+
+// One configuration of a sweep and its score
+type SweepPoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	VariationName string                 `protobuf:"bytes,1,opt,name=variation_name,json=variationName,proto3" json:"variation_name,omitempty"`
+	Temperature   *float32               `protobuf:"fixed32,2,opt,name=temperature,proto3,oneof" json:"temperature,omitempty"`
+	TopP          *float32               `protobuf:"fixed32,3,opt,name=top_p,json=topP,proto3,oneof" json:"top_p,omitempty"`
+	TopK          *int32                 `protobuf:"varint,4,opt,name=top_k,json=topK,proto3,oneof" json:"top_k,omitempty"`
+	Score         float64                `protobuf:"fixed64,5,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SweepPoint) Reset() {
+	*x = SweepPoint{}
+	mi := &file_proto_gogent_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SweepPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SweepPoint) ProtoMessage() {}
+
+func (x *SweepPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SweepPoint.ProtoReflect.Descriptor instead.
+func (*SweepPoint) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *SweepPoint) GetVariationName() string {
+	if x != nil {
+		return x.VariationName
+	}
+	return ""
+}
+
+func (x *SweepPoint) GetTemperature() float32 {
+	if x != nil && x.Temperature != nil {
+		return *x.Temperature
+	}
+	return 0
+}
+
+func (x *SweepPoint) GetTopP() float32 {
+	if x != nil && x.TopP != nil {
+		return *x.TopP
+	}
+	return 0
+}
+
+func (x *SweepPoint) GetTopK() int32 {
+	if x != nil && x.TopK != nil {
+		return *x.TopK
+	}
+	return 0
+}
+
+func (x *SweepPoint) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+// Mean score by value of one swept parameter, and the region spanned by the top quarter of points
+type ParameterSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Parameter     string                 `protobuf:"bytes,1,opt,name=parameter,proto3" json:"parameter,omitempty"`
+	Values        []*SweepValueScore     `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty"`
+	BestValue     float64                `protobuf:"fixed64,3,opt,name=best_value,json=bestValue,proto3" json:"best_value,omitempty"`
+	RegionMin     float64                `protobuf:"fixed64,4,opt,name=region_min,json=regionMin,proto3" json:"region_min,omitempty"`
+	RegionMax     float64                `protobuf:"fixed64,5,opt,name=region_max,json=regionMax,proto3" json:"region_max,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ParameterSummary) Reset() {
+	*x = ParameterSummary{}
+	mi := &file_proto_gogent_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParameterSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParameterSummary) ProtoMessage() {}
+
+func (x *ParameterSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParameterSummary.ProtoReflect.Descriptor instead.
+func (*ParameterSummary) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ParameterSummary) GetParameter() string {
+	if x != nil {
+		return x.Parameter
+	}
+	return ""
+}
+
+func (x *ParameterSummary) GetValues() []*SweepValueScore {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+func (x *ParameterSummary) GetBestValue() float64 {
+	if x != nil {
+		return x.BestValue
+	}
+	return 0
+}
+
+func (x *ParameterSummary) GetRegionMin() float64 {
+	if x != nil {
+		return x.RegionMin
+	}
+	return 0
+}
+
+func (x *ParameterSummary) GetRegionMax() float64 {
+	if x != nil {
+		return x.RegionMax
+	}
+	return 0
+}
+
+// Mean score of the points where a parameter took one value
+type SweepValueScore struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         float64                `protobuf:"fixed64,1,opt,name=value,proto3" json:"value,omitempty"`
+	MeanScore     float64                `protobuf:"fixed64,2,opt,name=mean_score,json=meanScore,proto3" json:"mean_score,omitempty"`
+	Points        int32                  `protobuf:"varint,3,opt,name=points,proto3" json:"points,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SweepValueScore) Reset() {
+	*x = SweepValueScore{}
+	mi := &file_proto_gogent_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SweepValueScore) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SweepValueScore) ProtoMessage() {}
+
+func (x *SweepValueScore) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SweepValueScore.ProtoReflect.Descriptor instead.
+func (*SweepValueScore) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *SweepValueScore) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *SweepValueScore) GetMeanScore() float64 {
+	if x != nil {
+		return x.MeanScore
+	}
+	return 0
+}
+
+func (x *SweepValueScore) GetPoints() int32 {
+	if x != nil {
+		return x.Points
+	}
+	return 0
+}
+
+// Streamed batch submission message. The first message must carry the template;
+// any message may carry a chunk of items.
+type SubmitBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Template      *ExecuteRequest        `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"` // Execution settings applied to every item (base_prompt is ignored)
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`         // Batch name (defaults to the template's execution_run_name)
+	Items         []*BatchItem           `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitBatchRequest) Reset() {
+	*x = SubmitBatchRequest{}
+	mi := &file_proto_gogent_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitBatchRequest) ProtoMessage() {}
+
+func (x *SubmitBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitBatchRequest.ProtoReflect.Descriptor instead.
+func (*SubmitBatchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *SubmitBatchRequest) GetTemplate() *ExecuteRequest {
+	if x != nil {
+		return x.Template
+	}
+	return nil
+}
+
+func (x *SubmitBatchRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SubmitBatchRequest) GetItems() []*BatchItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// Acknowledgement sent each time a chunk of items has been stored. The server stops reading
+// while a chunk is written, so clients sending faster than items are stored are slowed down by
+// gRPC flow control; clients should keep at most a few chunks unacknowledged.
+type SubmitBatchAck struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	BatchId           string                 `protobuf:"bytes,1,opt,name=batch_id,json=batchId,proto3" json:"batch_id,omitempty"`
+	AcknowledgedItems int32                  `protobuf:"varint,2,opt,name=acknowledged_items,json=acknowledgedItems,proto3" json:"acknowledged_items,omitempty"` // Total items stored so far
+	ChunkItems        int32                  `protobuf:"varint,3,opt,name=chunk_items,json=chunkItems,proto3" json:"chunk_items,omitempty"`                      // Items stored by this acknowledgement
+	Complete          bool                   `protobuf:"varint,4,opt,name=complete,proto3" json:"complete,omitempty"`                                            // Set on the final acknowledgement, after the client closes its stream
+	Status            string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *SubmitBatchAck) Reset() {
+	*x = SubmitBatchAck{}
+	mi := &file_proto_gogent_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitBatchAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitBatchAck) ProtoMessage() {}
+
+func (x *SubmitBatchAck) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitBatchAck.ProtoReflect.Descriptor instead.
+func (*SubmitBatchAck) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *SubmitBatchAck) GetBatchId() string {
+	if x != nil {
+		return x.BatchId
+	}
+	return ""
+}
+
+func (x *SubmitBatchAck) GetAcknowledgedItems() int32 {
+	if x != nil {
+		return x.AcknowledgedItems
+	}
+	return 0
+}
+
+func (x *SubmitBatchAck) GetChunkItems() int32 {
+	if x != nil {
+		return x.ChunkItems
+	}
+	return 0
+}
+
+func (x *SubmitBatchAck) GetComplete() bool {
+	if x != nil {
+		return x.Complete
+	}
+	return false
+}
+
+func (x *SubmitBatchAck) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+// Batch run with item progress
+type BatchRun struct {
+	state          protoimpl.MessageState   `protogen:"open.v1"`
+	Id             string                   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId         string                   `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Name           string                   `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Status         string                   `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"` // submitting, submitted, running, completed, failed
+	TotalItems     int32                    `protobuf:"varint,5,opt,name=total_items,json=totalItems,proto3" json:"total_items,omitempty"`
+	CompletedItems int32                    `protobuf:"varint,6,opt,name=completed_items,json=completedItems,proto3" json:"completed_items,omitempty"`
+	FailedItems    int32                    `protobuf:"varint,7,opt,name=failed_items,json=failedItems,proto3" json:"failed_items,omitempty"`
+	ErrorMessage   string                   `protobuf:"bytes,8,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	CreatedAt      *timestamppb.Timestamp   `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp   `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Accuracy       []*ConfigurationAccuracy `protobuf:"bytes,11,rep,name=accuracy,proto3" json:"accuracy,omitempty"` // Golden-answer accuracy by variation name
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *BatchRun) Reset() {
+	*x = BatchRun{}
+	mi := &file_proto_gogent_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchRun) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchRun) ProtoMessage() {}
+
+func (x *BatchRun) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchRun.ProtoReflect.Descriptor instead.
+func (*BatchRun) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *BatchRun) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BatchRun) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *BatchRun) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BatchRun) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *BatchRun) GetTotalItems() int32 {
+	if x != nil {
+		return x.TotalItems
+	}
+	return 0
+}
+
+func (x *BatchRun) GetCompletedItems() int32 {
+	if x != nil {
+		return x.CompletedItems
+	}
+	return 0
+}
+
+func (x *BatchRun) GetFailedItems() int32 {
+	if x != nil {
+		return x.FailedItems
+	}
+	return 0
+}
+
+func (x *BatchRun) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *BatchRun) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *BatchRun) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *BatchRun) GetAccuracy() []*ConfigurationAccuracy {
+	if x != nil {
+		return x.Accuracy
+	}
+	return nil
+}
+
+// Get batch run request
+type GetBatchRunRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBatchRunRequest) Reset() {
+	*x = GetBatchRunRequest{}
+	mi := &file_proto_gogent_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBatchRunRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBatchRunRequest) ProtoMessage() {}
+
+func (x *GetBatchRunRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBatchRunRequest.ProtoReflect.Descriptor instead.
+func (*GetBatchRunRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *GetBatchRunRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Get batch run response
+type GetBatchRunResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BatchRun      *BatchRun              `protobuf:"bytes,1,opt,name=batch_run,json=batchRun,proto3" json:"batch_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBatchRunResponse) Reset() {
+	*x = GetBatchRunResponse{}
+	mi := &file_proto_gogent_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBatchRunResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBatchRunResponse) ProtoMessage() {}
+
+func (x *GetBatchRunResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBatchRunResponse.ProtoReflect.Descriptor instead.
+func (*GetBatchRunResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *GetBatchRunResponse) GetBatchRun() *BatchRun {
+	if x != nil {
+		return x.BatchRun
+	}
+	return nil
+}
+
+// List configurations request
+type ListConfigurationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IncludeSystem bool                   `protobuf:"varint,1,opt,name=include_system,json=includeSystem,proto3" json:"include_system,omitempty"` // Admin only: include system configurations
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	Cursor        string                 `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"` // next_cursor of the previous page; overrides offset
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListConfigurationsRequest) Reset() {
+	*x = ListConfigurationsRequest{}
+	mi := &file_proto_gogent_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListConfigurationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListConfigurationsRequest) ProtoMessage() {}
+
+func (x *ListConfigurationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListConfigurationsRequest.ProtoReflect.Descriptor instead.
+func (*ListConfigurationsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *ListConfigurationsRequest) GetIncludeSystem() bool {
+	if x != nil {
+		return x.IncludeSystem
+	}
+	return false
+}
+
+func (x *ListConfigurationsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListConfigurationsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListConfigurationsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+// List configurations response
+type ListConfigurationsResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Configurations []*APIConfiguration    `protobuf:"bytes,1,rep,name=configurations,proto3" json:"configurations,omitempty"`
+	TotalCount     int32                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"` // Presets across all pages; system configurations are not counted
+	NextCursor     string                 `protobuf:"bytes,3,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`  // Empty on the last page
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListConfigurationsResponse) Reset() {
+	*x = ListConfigurationsResponse{}
+	mi := &file_proto_gogent_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListConfigurationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListConfigurationsResponse) ProtoMessage() {}
+
+func (x *ListConfigurationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListConfigurationsResponse.ProtoReflect.Descriptor instead.
+func (*ListConfigurationsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *ListConfigurationsResponse) GetConfigurations() []*APIConfiguration {
+	if x != nil {
+		return x.Configurations
+	}
+	return nil
+}
+
+func (x *ListConfigurationsResponse) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *ListConfigurationsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+// Create configuration request
+type CreateConfigurationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Configuration *APIConfiguration      `protobuf:"bytes,1,opt,name=configuration,proto3" json:"configuration,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateConfigurationRequest) Reset() {
+	*x = CreateConfigurationRequest{}
+	mi := &file_proto_gogent_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateConfigurationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateConfigurationRequest) ProtoMessage() {}
+
+func (x *CreateConfigurationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateConfigurationRequest.ProtoReflect.Descriptor instead.
+func (*CreateConfigurationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *CreateConfigurationRequest) GetConfiguration() *APIConfiguration {
+	if x != nil {
+		return x.Configuration
+	}
+	return nil
+}
+
+// Create configuration response
+type CreateConfigurationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Configuration *APIConfiguration      `protobuf:"bytes,1,opt,name=configuration,proto3" json:"configuration,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateConfigurationResponse) Reset() {
+	*x = CreateConfigurationResponse{}
+	mi := &file_proto_gogent_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateConfigurationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateConfigurationResponse) ProtoMessage() {}
+
+func (x *CreateConfigurationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateConfigurationResponse.ProtoReflect.Descriptor instead.
+func (*CreateConfigurationResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *CreateConfigurationResponse) GetConfiguration() *APIConfiguration {
+	if x != nil {
+		return x.Configuration
+	}
+	return nil
+}
+
+// Update configuration request
+type UpdateConfigurationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Configuration *APIConfiguration      `protobuf:"bytes,2,opt,name=configuration,proto3" json:"configuration,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateConfigurationRequest) Reset() {
+	*x = UpdateConfigurationRequest{}
+	mi := &file_proto_gogent_proto_msgTypes[57]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteExecutionRunRequest) String() string {
+func (x *UpdateConfigurationRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteExecutionRunRequest) ProtoMessage() {}
+func (*UpdateConfigurationRequest) ProtoMessage() {}
 
-func (x *DeleteExecutionRunRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[21]
+func (x *UpdateConfigurationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateConfigurationRequest.ProtoReflect.Descriptor instead.
+func (*UpdateConfigurationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *UpdateConfigurationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateConfigurationRequest) GetConfiguration() *APIConfiguration {
+	if x != nil {
+		return x.Configuration
+	}
+	return nil
+}
+
+// Update configuration response
+type UpdateConfigurationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Configuration *APIConfiguration      `protobuf:"bytes,1,opt,name=configuration,proto3" json:"configuration,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateConfigurationResponse) Reset() {
+	*x = UpdateConfigurationResponse{}
+	mi := &file_proto_gogent_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateConfigurationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateConfigurationResponse) ProtoMessage() {}
+
+func (x *UpdateConfigurationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateConfigurationResponse.ProtoReflect.Descriptor instead.
+func (*UpdateConfigurationResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *UpdateConfigurationResponse) GetConfiguration() *APIConfiguration {
+	if x != nil {
+		return x.Configuration
+	}
+	return nil
+}
+
+// Delete configuration request
+type DeleteConfigurationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteConfigurationRequest) Reset() {
+	*x = DeleteConfigurationRequest{}
+	mi := &file_proto_gogent_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteConfigurationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteConfigurationRequest) ProtoMessage() {}
+
+func (x *DeleteConfigurationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteConfigurationRequest.ProtoReflect.Descriptor instead.
+func (*DeleteConfigurationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *DeleteConfigurationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Delete configuration response
+type DeleteConfigurationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteConfigurationResponse) Reset() {
+	*x = DeleteConfigurationResponse{}
+	mi := &file_proto_gogent_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteConfigurationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteConfigurationResponse) ProtoMessage() {}
+
+func (x *DeleteConfigurationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteConfigurationResponse.ProtoReflect.Descriptor instead.
+func (*DeleteConfigurationResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *DeleteConfigurationResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// List functions request
+type ListFunctionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Cursor        string                 `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"` // next_cursor of the previous page; overrides offset
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFunctionsRequest) Reset() {
+	*x = ListFunctionsRequest{}
+	mi := &file_proto_gogent_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFunctionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFunctionsRequest) ProtoMessage() {}
+
+func (x *ListFunctionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFunctionsRequest.ProtoReflect.Descriptor instead.
+func (*ListFunctionsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *ListFunctionsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListFunctionsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListFunctionsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+// List functions response
+type ListFunctionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Functions     []*FunctionDefinition  `protobuf:"bytes,1,rep,name=functions,proto3" json:"functions,omitempty"`
+	TotalCount    int32                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"` // Functions across all pages
+	NextCursor    string                 `protobuf:"bytes,3,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`  // Empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFunctionsResponse) Reset() {
+	*x = ListFunctionsResponse{}
+	mi := &file_proto_gogent_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFunctionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFunctionsResponse) ProtoMessage() {}
+
+func (x *ListFunctionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[62]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1326,41 +3994,55 @@ func (x *DeleteExecutionRunRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteExecutionRunRequest.ProtoReflect.Descriptor instead.
-func (*DeleteExecutionRunRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{21}
+// Deprecated: Use ListFunctionsResponse.ProtoReflect.Descriptor instead.
+func (*ListFunctionsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{62}
 }
 
-func (x *DeleteExecutionRunRequest) GetExecutionRunId() string {
+func (x *ListFunctionsResponse) GetFunctions() []*FunctionDefinition {
 	if x != nil {
-		return x.ExecutionRunId
+		return x.Functions
+	}
+	return nil
+}
+
+func (x *ListFunctionsResponse) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *ListFunctionsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
 	}
 	return ""
 }
 
-// Delete execution run response
-type DeleteExecutionRunResponse struct {
+// Get function by ID request
+type GetFunctionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteExecutionRunResponse) Reset() {
-	*x = DeleteExecutionRunResponse{}
-	mi := &file_proto_gogent_proto_msgTypes[22]
+func (x *GetFunctionRequest) Reset() {
+	*x = GetFunctionRequest{}
+	mi := &file_proto_gogent_proto_msgTypes[63]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteExecutionRunResponse) String() string {
+func (x *GetFunctionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteExecutionRunResponse) ProtoMessage() {}
+func (*GetFunctionRequest) ProtoMessage() {}
 
-func (x *DeleteExecutionRunResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[22]
+func (x *GetFunctionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[63]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1371,40 +4053,41 @@ func (x *DeleteExecutionRunResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteExecutionRunResponse.ProtoReflect.Descriptor instead.
-func (*DeleteExecutionRunResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{22}
+// Deprecated: Use GetFunctionRequest.ProtoReflect.Descriptor instead.
+func (*GetFunctionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{63}
 }
 
-func (x *DeleteExecutionRunResponse) GetMessage() string {
+func (x *GetFunctionRequest) GetId() string {
 	if x != nil {
-		return x.Message
+		return x.Id
 	}
 	return ""
 }
 
-// List configurations request
-type ListConfigurationsRequest struct {
+// Get function by ID response
+type GetFunctionResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Function      *FunctionDefinition    `protobuf:"bytes,1,opt,name=function,proto3" json:"function,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListConfigurationsRequest) Reset() {
-	*x = ListConfigurationsRequest{}
-	mi := &file_proto_gogent_proto_msgTypes[23]
+func (x *GetFunctionResponse) Reset() {
+	*x = GetFunctionResponse{}
+	mi := &file_proto_gogent_proto_msgTypes[64]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListConfigurationsRequest) String() string {
+func (x *GetFunctionResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListConfigurationsRequest) ProtoMessage() {}
+func (*GetFunctionResponse) ProtoMessage() {}
 
-func (x *ListConfigurationsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[23]
+func (x *GetFunctionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[64]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1415,34 +4098,41 @@ func (x *ListConfigurationsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListConfigurationsRequest.ProtoReflect.Descriptor instead.
-func (*ListConfigurationsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{23}
+// Deprecated: Use GetFunctionResponse.ProtoReflect.Descriptor instead.
+func (*GetFunctionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{64}
 }
 
-// List configurations response
-type ListConfigurationsResponse struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	Configurations []*APIConfiguration    `protobuf:"bytes,1,rep,name=configurations,proto3" json:"configurations,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+func (x *GetFunctionResponse) GetFunction() *FunctionDefinition {
+	if x != nil {
+		return x.Function
+	}
+	return nil
 }
 
-func (x *ListConfigurationsResponse) Reset() {
-	*x = ListConfigurationsResponse{}
-	mi := &file_proto_gogent_proto_msgTypes[24]
+// Create function request
+type CreateFunctionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Function      *FunctionDefinition    `protobuf:"bytes,1,opt,name=function,proto3" json:"function,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateFunctionRequest) Reset() {
+	*x = CreateFunctionRequest{}
+	mi := &file_proto_gogent_proto_msgTypes[65]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListConfigurationsResponse) String() string {
+func (x *CreateFunctionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListConfigurationsResponse) ProtoMessage() {}
+func (*CreateFunctionRequest) ProtoMessage() {}
 
-func (x *ListConfigurationsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[24]
+func (x *CreateFunctionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[65]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1453,41 +4143,41 @@ func (x *ListConfigurationsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListConfigurationsResponse.ProtoReflect.Descriptor instead.
-func (*ListConfigurationsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{24}
+// Deprecated: Use CreateFunctionRequest.ProtoReflect.Descriptor instead.
+func (*CreateFunctionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{65}
 }
 
-func (x *ListConfigurationsResponse) GetConfigurations() []*APIConfiguration {
+func (x *CreateFunctionRequest) GetFunction() *FunctionDefinition {
 	if x != nil {
-		return x.Configurations
+		return x.Function
 	}
 	return nil
 }
 
-// Create configuration request
-type CreateConfigurationRequest struct {
+// Create function response
+type CreateFunctionResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Configuration *APIConfiguration      `protobuf:"bytes,1,opt,name=configuration,proto3" json:"configuration,omitempty"`
+	Function      *FunctionDefinition    `protobuf:"bytes,1,opt,name=function,proto3" json:"function,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateConfigurationRequest) Reset() {
-	*x = CreateConfigurationRequest{}
-	mi := &file_proto_gogent_proto_msgTypes[25]
+func (x *CreateFunctionResponse) Reset() {
+	*x = CreateFunctionResponse{}
+	mi := &file_proto_gogent_proto_msgTypes[66]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateConfigurationRequest) String() string {
+func (x *CreateFunctionResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateConfigurationRequest) ProtoMessage() {}
+func (*CreateFunctionResponse) ProtoMessage() {}
 
-func (x *CreateConfigurationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[25]
+func (x *CreateFunctionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[66]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1498,41 +4188,42 @@ func (x *CreateConfigurationRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateConfigurationRequest.ProtoReflect.Descriptor instead.
-func (*CreateConfigurationRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{25}
+// Deprecated: Use CreateFunctionResponse.ProtoReflect.Descriptor instead.
+func (*CreateFunctionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{66}
 }
 
-func (x *CreateConfigurationRequest) GetConfiguration() *APIConfiguration {
+func (x *CreateFunctionResponse) GetFunction() *FunctionDefinition {
 	if x != nil {
-		return x.Configuration
+		return x.Function
 	}
 	return nil
 }
 
-// Create configuration response
-type CreateConfigurationResponse struct {
+// Update function request
+type UpdateFunctionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Configuration *APIConfiguration      `protobuf:"bytes,1,opt,name=configuration,proto3" json:"configuration,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Function      *FunctionDefinition    `protobuf:"bytes,2,opt,name=function,proto3" json:"function,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateConfigurationResponse) Reset() {
-	*x = CreateConfigurationResponse{}
-	mi := &file_proto_gogent_proto_msgTypes[26]
+func (x *UpdateFunctionRequest) Reset() {
+	*x = UpdateFunctionRequest{}
+	mi := &file_proto_gogent_proto_msgTypes[67]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateConfigurationResponse) String() string {
+func (x *UpdateFunctionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateConfigurationResponse) ProtoMessage() {}
+func (*UpdateFunctionRequest) ProtoMessage() {}
 
-func (x *CreateConfigurationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[26]
+func (x *UpdateFunctionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[67]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1543,42 +4234,48 @@ func (x *CreateConfigurationResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateConfigurationResponse.ProtoReflect.Descriptor instead.
-func (*CreateConfigurationResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{26}
+// Deprecated: Use UpdateFunctionRequest.ProtoReflect.Descriptor instead.
+func (*UpdateFunctionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{67}
 }
 
-func (x *CreateConfigurationResponse) GetConfiguration() *APIConfiguration {
+func (x *UpdateFunctionRequest) GetId() string {
 	if x != nil {
-		return x.Configuration
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateFunctionRequest) GetFunction() *FunctionDefinition {
+	if x != nil {
+		return x.Function
 	}
 	return nil
 }
 
-// Update configuration request
-type UpdateConfigurationRequest struct {
+// Update function response
+type UpdateFunctionResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Configuration *APIConfiguration      `protobuf:"bytes,2,opt,name=configuration,proto3" json:"configuration,omitempty"`
+	Function      *FunctionDefinition    `protobuf:"bytes,1,opt,name=function,proto3" json:"function,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateConfigurationRequest) Reset() {
-	*x = UpdateConfigurationRequest{}
-	mi := &file_proto_gogent_proto_msgTypes[27]
+func (x *UpdateFunctionResponse) Reset() {
+	*x = UpdateFunctionResponse{}
+	mi := &file_proto_gogent_proto_msgTypes[68]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateConfigurationRequest) String() string {
+func (x *UpdateFunctionResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateConfigurationRequest) ProtoMessage() {}
+func (*UpdateFunctionResponse) ProtoMessage() {}
 
-func (x *UpdateConfigurationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[27]
+func (x *UpdateFunctionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[68]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1589,48 +4286,41 @@ func (x *UpdateConfigurationRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateConfigurationRequest.ProtoReflect.Descriptor instead.
-func (*UpdateConfigurationRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{27}
-}
-
-func (x *UpdateConfigurationRequest) GetId() string {
-	if x != nil {
-		return x.Id
-	}
-	return ""
+// Deprecated: Use UpdateFunctionResponse.ProtoReflect.Descriptor instead.
+func (*UpdateFunctionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{68}
 }
 
-func (x *UpdateConfigurationRequest) GetConfiguration() *APIConfiguration {
+func (x *UpdateFunctionResponse) GetFunction() *FunctionDefinition {
 	if x != nil {
-		return x.Configuration
+		return x.Function
 	}
 	return nil
 }
 
-// Update configuration response
-type UpdateConfigurationResponse struct {
+// Delete function request
+type DeleteFunctionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Configuration *APIConfiguration      `protobuf:"bytes,1,opt,name=configuration,proto3" json:"configuration,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateConfigurationResponse) Reset() {
-	*x = UpdateConfigurationResponse{}
-	mi := &file_proto_gogent_proto_msgTypes[28]
+func (x *DeleteFunctionRequest) Reset() {
+	*x = DeleteFunctionRequest{}
+	mi := &file_proto_gogent_proto_msgTypes[69]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateConfigurationResponse) String() string {
+func (x *DeleteFunctionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateConfigurationResponse) ProtoMessage() {}
+func (*DeleteFunctionRequest) ProtoMessage() {}
 
-func (x *UpdateConfigurationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[28]
+func (x *DeleteFunctionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[69]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1641,41 +4331,41 @@ func (x *UpdateConfigurationResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateConfigurationResponse.ProtoReflect.Descriptor instead.
-func (*UpdateConfigurationResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{28}
+// Deprecated: Use DeleteFunctionRequest.ProtoReflect.Descriptor instead.
+func (*DeleteFunctionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{69}
 }
 
-func (x *UpdateConfigurationResponse) GetConfiguration() *APIConfiguration {
+func (x *DeleteFunctionRequest) GetId() string {
 	if x != nil {
-		return x.Configuration
+		return x.Id
 	}
-	return nil
+	return ""
 }
 
-// Delete configuration request
-type DeleteConfigurationRequest struct {
+// Delete function response
+type DeleteFunctionResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteConfigurationRequest) Reset() {
-	*x = DeleteConfigurationRequest{}
-	mi := &file_proto_gogent_proto_msgTypes[29]
+func (x *DeleteFunctionResponse) Reset() {
+	*x = DeleteFunctionResponse{}
+	mi := &file_proto_gogent_proto_msgTypes[70]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteConfigurationRequest) String() string {
+func (x *DeleteFunctionResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteConfigurationRequest) ProtoMessage() {}
+func (*DeleteFunctionResponse) ProtoMessage() {}
 
-func (x *DeleteConfigurationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[29]
+func (x *DeleteFunctionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[70]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1686,41 +4376,44 @@ func (x *DeleteConfigurationRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteConfigurationRequest.ProtoReflect.Descriptor instead.
-func (*DeleteConfigurationRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{29}
+// Deprecated: Use DeleteFunctionResponse.ProtoReflect.Descriptor instead.
+func (*DeleteFunctionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{70}
 }
 
-func (x *DeleteConfigurationRequest) GetId() string {
+func (x *DeleteFunctionResponse) GetMessage() string {
 	if x != nil {
-		return x.Id
+		return x.Message
 	}
 	return ""
 }
 
-// Delete configuration response
-type DeleteConfigurationResponse struct {
+// Test function request
+type TestFunctionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	FunctionId    string                 `protobuf:"bytes,1,opt,name=function_id,json=functionId,proto3" json:"function_id,omitempty"`
+	Arguments     *structpb.Struct       `protobuf:"bytes,2,opt,name=arguments,proto3" json:"arguments,omitempty"`
+	UseMockData   bool                   `protobuf:"varint,3,opt,name=use_mock_data,json=useMockData,proto3" json:"use_mock_data,omitempty"`
+	TimeoutMs     int32                  `protobuf:"varint,4,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteConfigurationResponse) Reset() {
-	*x = DeleteConfigurationResponse{}
-	mi := &file_proto_gogent_proto_msgTypes[30]
+func (x *TestFunctionRequest) Reset() {
+	*x = TestFunctionRequest{}
+	mi := &file_proto_gogent_proto_msgTypes[71]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteConfigurationResponse) String() string {
+func (x *TestFunctionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteConfigurationResponse) ProtoMessage() {}
+func (*TestFunctionRequest) ProtoMessage() {}
 
-func (x *DeleteConfigurationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[30]
+func (x *TestFunctionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[71]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1731,40 +4424,67 @@ func (x *DeleteConfigurationResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteConfigurationResponse.ProtoReflect.Descriptor instead.
-func (*DeleteConfigurationResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{30}
+// Deprecated: Use TestFunctionRequest.ProtoReflect.Descriptor instead.
+func (*TestFunctionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{71}
 }
 
-func (x *DeleteConfigurationResponse) GetMessage() string {
+func (x *TestFunctionRequest) GetFunctionId() string {
 	if x != nil {
-		return x.Message
+		return x.FunctionId
 	}
 	return ""
 }
 
-// List functions request
-type ListFunctionsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *TestFunctionRequest) GetArguments() *structpb.Struct {
+	if x != nil {
+		return x.Arguments
+	}
+	return nil
 }
 
-func (x *ListFunctionsRequest) Reset() {
-	*x = ListFunctionsRequest{}
-	mi := &file_proto_gogent_proto_msgTypes[31]
+func (x *TestFunctionRequest) GetUseMockData() bool {
+	if x != nil {
+		return x.UseMockData
+	}
+	return false
+}
+
+func (x *TestFunctionRequest) GetTimeoutMs() int32 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+// Test function response
+type TestFunctionResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Success         bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	UsedMockData    bool                   `protobuf:"varint,2,opt,name=used_mock_data,json=usedMockData,proto3" json:"used_mock_data,omitempty"`
+	ExecutionTimeMs int32                  `protobuf:"varint,3,opt,name=execution_time_ms,json=executionTimeMs,proto3" json:"execution_time_ms,omitempty"`
+	Response        *structpb.Struct       `protobuf:"bytes,4,opt,name=response,proto3" json:"response,omitempty"`
+	ErrorMessage    string                 `protobuf:"bytes,5,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	FunctionCallId  string                 `protobuf:"bytes,6,opt,name=function_call_id,json=functionCallId,proto3" json:"function_call_id,omitempty"` // function_calls entry recording the test
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *TestFunctionResponse) Reset() {
+	*x = TestFunctionResponse{}
+	mi := &file_proto_gogent_proto_msgTypes[72]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListFunctionsRequest) String() string {
+func (x *TestFunctionResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListFunctionsRequest) ProtoMessage() {}
+func (*TestFunctionResponse) ProtoMessage() {}
 
-func (x *ListFunctionsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[31]
+func (x *TestFunctionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[72]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1775,34 +4495,76 @@ func (x *ListFunctionsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListFunctionsRequest.ProtoReflect.Descriptor instead.
-func (*ListFunctionsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{31}
+// Deprecated: Use TestFunctionResponse.ProtoReflect.Descriptor instead.
+func (*TestFunctionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{72}
 }
 
-// List functions response
-type ListFunctionsResponse struct {
+func (x *TestFunctionResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *TestFunctionResponse) GetUsedMockData() bool {
+	if x != nil {
+		return x.UsedMockData
+	}
+	return false
+}
+
+func (x *TestFunctionResponse) GetExecutionTimeMs() int32 {
+	if x != nil {
+		return x.ExecutionTimeMs
+	}
+	return 0
+}
+
+func (x *TestFunctionResponse) GetResponse() *structpb.Struct {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+func (x *TestFunctionResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *TestFunctionResponse) GetFunctionCallId() string {
+	if x != nil {
+		return x.FunctionCallId
+	}
+	return ""
+}
+
+// Get database stats request
+type GetDatabaseStatsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Functions     []*FunctionDefinition  `protobuf:"bytes,1,rep,name=functions,proto3" json:"functions,omitempty"`
+	AllUsers      bool                   `protobuf:"varint,1,opt,name=all_users,json=allUsers,proto3" json:"all_users,omitempty"` // Admin only: aggregate stats across every user
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListFunctionsResponse) Reset() {
-	*x = ListFunctionsResponse{}
-	mi := &file_proto_gogent_proto_msgTypes[32]
+func (x *GetDatabaseStatsRequest) Reset() {
+	*x = GetDatabaseStatsRequest{}
+	mi := &file_proto_gogent_proto_msgTypes[73]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListFunctionsResponse) String() string {
+func (x *GetDatabaseStatsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListFunctionsResponse) ProtoMessage() {}
+func (*GetDatabaseStatsRequest) ProtoMessage() {}
 
-func (x *ListFunctionsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[32]
+func (x *GetDatabaseStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[73]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1813,41 +4575,49 @@ func (x *ListFunctionsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListFunctionsResponse.ProtoReflect.Descriptor instead.
-func (*ListFunctionsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{32}
+// Deprecated: Use GetDatabaseStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetDatabaseStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{73}
 }
 
-func (x *ListFunctionsResponse) GetFunctions() []*FunctionDefinition {
+func (x *GetDatabaseStatsRequest) GetAllUsers() bool {
 	if x != nil {
-		return x.Functions
+		return x.AllUsers
 	}
-	return nil
+	return false
 }
 
-// Get function by ID request
-type GetFunctionRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// Get database stats response
+type GetDatabaseStatsResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	TotalExecutionRuns int32                  `protobuf:"varint,1,opt,name=total_execution_runs,json=totalExecutionRuns,proto3" json:"total_execution_runs,omitempty"`
+	TotalApiRequests   int32                  `protobuf:"varint,2,opt,name=total_api_requests,json=totalApiRequests,proto3" json:"total_api_requests,omitempty"`
+	TotalApiResponses  int32                  `protobuf:"varint,3,opt,name=total_api_responses,json=totalApiResponses,proto3" json:"total_api_responses,omitempty"`
+	TotalFunctionCalls int32                  `protobuf:"varint,4,opt,name=total_function_calls,json=totalFunctionCalls,proto3" json:"total_function_calls,omitempty"`
+	AvgResponseTime    float64                `protobuf:"fixed64,5,opt,name=avg_response_time,json=avgResponseTime,proto3" json:"avg_response_time,omitempty"`
+	SuccessRate        float64                `protobuf:"fixed64,6,opt,name=success_rate,json=successRate,proto3" json:"success_rate,omitempty"`
+	TotalTokens        int64                  `protobuf:"varint,7,opt,name=total_tokens,json=totalTokens,proto3" json:"total_tokens,omitempty"`
+	StatsSource        string                 `protobuf:"bytes,8,opt,name=stats_source,json=statsSource,proto3" json:"stats_source,omitempty"` // "rollups", or "live" until the daily rollups are first built
+	RefreshedAt        *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=refreshed_at,json=refreshedAt,proto3" json:"refreshed_at,omitempty"` // When the rollups were refreshed; unset for live stats
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
-func (x *GetFunctionRequest) Reset() {
-	*x = GetFunctionRequest{}
-	mi := &file_proto_gogent_proto_msgTypes[33]
+func (x *GetDatabaseStatsResponse) Reset() {
+	*x = GetDatabaseStatsResponse{}
+	mi := &file_proto_gogent_proto_msgTypes[74]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetFunctionRequest) String() string {
+func (x *GetDatabaseStatsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetFunctionRequest) ProtoMessage() {}
+func (*GetDatabaseStatsResponse) ProtoMessage() {}
 
-func (x *GetFunctionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[33]
+func (x *GetDatabaseStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[74]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1858,86 +4628,96 @@ func (x *GetFunctionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetFunctionRequest.ProtoReflect.Descriptor instead.
-func (*GetFunctionRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{33}
+// Deprecated: Use GetDatabaseStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetDatabaseStatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{74}
 }
 
-func (x *GetFunctionRequest) GetId() string {
+func (x *GetDatabaseStatsResponse) GetTotalExecutionRuns() int32 {
 	if x != nil {
-		return x.Id
+		return x.TotalExecutionRuns
 	}
-	return ""
+	return 0
 }
 
-// Get function by ID response
-type GetFunctionResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Function      *FunctionDefinition    `protobuf:"bytes,1,opt,name=function,proto3" json:"function,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *GetDatabaseStatsResponse) GetTotalApiRequests() int32 {
+	if x != nil {
+		return x.TotalApiRequests
+	}
+	return 0
 }
 
-func (x *GetFunctionResponse) Reset() {
-	*x = GetFunctionResponse{}
-	mi := &file_proto_gogent_proto_msgTypes[34]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *GetDatabaseStatsResponse) GetTotalApiResponses() int32 {
+	if x != nil {
+		return x.TotalApiResponses
+	}
+	return 0
 }
 
-func (x *GetFunctionResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *GetDatabaseStatsResponse) GetTotalFunctionCalls() int32 {
+	if x != nil {
+		return x.TotalFunctionCalls
+	}
+	return 0
 }
 
-func (*GetFunctionResponse) ProtoMessage() {}
+func (x *GetDatabaseStatsResponse) GetAvgResponseTime() float64 {
+	if x != nil {
+		return x.AvgResponseTime
+	}
+	return 0
+}
 
-func (x *GetFunctionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[34]
+func (x *GetDatabaseStatsResponse) GetSuccessRate() float64 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.SuccessRate
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use GetFunctionResponse.ProtoReflect.Descriptor instead.
-func (*GetFunctionResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{34}
+func (x *GetDatabaseStatsResponse) GetTotalTokens() int64 {
+	if x != nil {
+		return x.TotalTokens
+	}
+	return 0
+}
+
+func (x *GetDatabaseStatsResponse) GetStatsSource() string {
+	if x != nil {
+		return x.StatsSource
+	}
+	return ""
 }
 
-func (x *GetFunctionResponse) GetFunction() *FunctionDefinition {
+func (x *GetDatabaseStatsResponse) GetRefreshedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Function
+		return x.RefreshedAt
 	}
 	return nil
 }
 
-// Create function request
-type CreateFunctionRequest struct {
+// List database tables request
+type ListDatabaseTablesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Function      *FunctionDefinition    `protobuf:"bytes,1,opt,name=function,proto3" json:"function,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateFunctionRequest) Reset() {
-	*x = CreateFunctionRequest{}
-	mi := &file_proto_gogent_proto_msgTypes[35]
+func (x *ListDatabaseTablesRequest) Reset() {
+	*x = ListDatabaseTablesRequest{}
+	mi := &file_proto_gogent_proto_msgTypes[75]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateFunctionRequest) String() string {
+func (x *ListDatabaseTablesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateFunctionRequest) ProtoMessage() {}
+func (*ListDatabaseTablesRequest) ProtoMessage() {}
 
-func (x *CreateFunctionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[35]
+func (x *ListDatabaseTablesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[75]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1948,41 +4728,34 @@ func (x *CreateFunctionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateFunctionRequest.ProtoReflect.Descriptor instead.
-func (*CreateFunctionRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{35}
-}
-
-func (x *CreateFunctionRequest) GetFunction() *FunctionDefinition {
-	if x != nil {
-		return x.Function
-	}
-	return nil
+// Deprecated: Use ListDatabaseTablesRequest.ProtoReflect.Descriptor instead.
+func (*ListDatabaseTablesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{75}
 }
 
-// Create function response
-type CreateFunctionResponse struct {
+// List database tables response
+type ListDatabaseTablesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Function      *FunctionDefinition    `protobuf:"bytes,1,opt,name=function,proto3" json:"function,omitempty"`
+	Tables        []string               `protobuf:"bytes,1,rep,name=tables,proto3" json:"tables,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateFunctionResponse) Reset() {
-	*x = CreateFunctionResponse{}
-	mi := &file_proto_gogent_proto_msgTypes[36]
+func (x *ListDatabaseTablesResponse) Reset() {
+	*x = ListDatabaseTablesResponse{}
+	mi := &file_proto_gogent_proto_msgTypes[76]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateFunctionResponse) String() string {
+func (x *ListDatabaseTablesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateFunctionResponse) ProtoMessage() {}
+func (*ListDatabaseTablesResponse) ProtoMessage() {}
 
-func (x *CreateFunctionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[36]
+func (x *ListDatabaseTablesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[76]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1993,42 +4766,48 @@ func (x *CreateFunctionResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateFunctionResponse.ProtoReflect.Descriptor instead.
-func (*CreateFunctionResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{36}
+// Deprecated: Use ListDatabaseTablesResponse.ProtoReflect.Descriptor instead.
+func (*ListDatabaseTablesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{76}
 }
 
-func (x *CreateFunctionResponse) GetFunction() *FunctionDefinition {
+func (x *ListDatabaseTablesResponse) GetTables() []string {
 	if x != nil {
-		return x.Function
+		return x.Tables
 	}
 	return nil
 }
 
-// Update function request
-type UpdateFunctionRequest struct {
+// Get table data request
+type GetTableDataRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Function      *FunctionDefinition    `protobuf:"bytes,2,opt,name=function,proto3" json:"function,omitempty"`
+	TableName     string                 `protobuf:"bytes,1,opt,name=table_name,json=tableName,proto3" json:"table_name,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	AllUsers      bool                   `protobuf:"varint,4,opt,name=all_users,json=allUsers,proto3" json:"all_users,omitempty"` // Admin only: browse raw rows without user scoping
+	Cursor        string                 `protobuf:"bytes,5,opt,name=cursor,proto3" json:"cursor,omitempty"`                      // next_cursor of the previous page; overrides offset
+	Columns       []string               `protobuf:"bytes,6,rep,name=columns,proto3" json:"columns,omitempty"`                    // Columns to return; every column when empty
+	Filters       []string               `protobuf:"bytes,7,rep,name=filters,proto3" json:"filters,omitempty"`                    // column:operator:value, e.g. status:eq:failed
+	Sort          string                 `protobuf:"bytes,8,opt,name=sort,proto3" json:"sort,omitempty"`                          // Column to sort by, descending with a leading "-"
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateFunctionRequest) Reset() {
-	*x = UpdateFunctionRequest{}
-	mi := &file_proto_gogent_proto_msgTypes[37]
+func (x *GetTableDataRequest) Reset() {
+	*x = GetTableDataRequest{}
+	mi := &file_proto_gogent_proto_msgTypes[77]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateFunctionRequest) String() string {
+func (x *GetTableDataRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateFunctionRequest) ProtoMessage() {}
+func (*GetTableDataRequest) ProtoMessage() {}
 
-func (x *UpdateFunctionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[37]
+func (x *GetTableDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[77]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2039,93 +4818,94 @@ func (x *UpdateFunctionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateFunctionRequest.ProtoReflect.Descriptor instead.
-func (*UpdateFunctionRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{37}
+// Deprecated: Use GetTableDataRequest.ProtoReflect.Descriptor instead.
+func (*GetTableDataRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{77}
 }
 
-func (x *UpdateFunctionRequest) GetId() string {
+func (x *GetTableDataRequest) GetTableName() string {
 	if x != nil {
-		return x.Id
+		return x.TableName
 	}
 	return ""
 }
 
-func (x *UpdateFunctionRequest) GetFunction() *FunctionDefinition {
+func (x *GetTableDataRequest) GetLimit() int32 {
 	if x != nil {
-		return x.Function
+		return x.Limit
 	}
-	return nil
+	return 0
 }
 
-// Update function response
-type UpdateFunctionResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Function      *FunctionDefinition    `protobuf:"bytes,1,opt,name=function,proto3" json:"function,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *GetTableDataRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
 }
 
-func (x *UpdateFunctionResponse) Reset() {
-	*x = UpdateFunctionResponse{}
-	mi := &file_proto_gogent_proto_msgTypes[38]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *GetTableDataRequest) GetAllUsers() bool {
+	if x != nil {
+		return x.AllUsers
+	}
+	return false
 }
 
-func (x *UpdateFunctionResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *GetTableDataRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
 }
 
-func (*UpdateFunctionResponse) ProtoMessage() {}
-
-func (x *UpdateFunctionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[38]
+func (x *GetTableDataRequest) GetColumns() []string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Columns
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use UpdateFunctionResponse.ProtoReflect.Descriptor instead.
-func (*UpdateFunctionResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{38}
+func (x *GetTableDataRequest) GetFilters() []string {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
 }
 
-func (x *UpdateFunctionResponse) GetFunction() *FunctionDefinition {
+func (x *GetTableDataRequest) GetSort() string {
 	if x != nil {
-		return x.Function
+		return x.Sort
 	}
-	return nil
+	return ""
 }
 
-// Delete function request
-type DeleteFunctionRequest struct {
+// Get table data response
+type GetTableDataResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	TableName     string                 `protobuf:"bytes,1,opt,name=table_name,json=tableName,proto3" json:"table_name,omitempty"`
+	Columns       []string               `protobuf:"bytes,2,rep,name=columns,proto3" json:"columns,omitempty"`
+	Rows          []*structpb.ListValue  `protobuf:"bytes,3,rep,name=rows,proto3" json:"rows,omitempty"`
+	TotalRows     int32                  `protobuf:"varint,4,opt,name=total_rows,json=totalRows,proto3" json:"total_rows,omitempty"`   // Rows across all pages
+	NextCursor    string                 `protobuf:"bytes,5,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"` // Empty on the last page
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteFunctionRequest) Reset() {
-	*x = DeleteFunctionRequest{}
-	mi := &file_proto_gogent_proto_msgTypes[39]
+func (x *GetTableDataResponse) Reset() {
+	*x = GetTableDataResponse{}
+	mi := &file_proto_gogent_proto_msgTypes[78]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteFunctionRequest) String() string {
+func (x *GetTableDataResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteFunctionRequest) ProtoMessage() {}
+func (*GetTableDataResponse) ProtoMessage() {}
 
-func (x *DeleteFunctionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[39]
+func (x *GetTableDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[78]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2136,41 +4916,68 @@ func (x *DeleteFunctionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteFunctionRequest.ProtoReflect.Descriptor instead.
-func (*DeleteFunctionRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{39}
+// Deprecated: Use GetTableDataResponse.ProtoReflect.Descriptor instead.
+func (*GetTableDataResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{78}
 }
 
-func (x *DeleteFunctionRequest) GetId() string {
+func (x *GetTableDataResponse) GetTableName() string {
 	if x != nil {
-		return x.Id
+		return x.TableName
 	}
 	return ""
 }
 
-// Delete function response
-type DeleteFunctionResponse struct {
+func (x *GetTableDataResponse) GetColumns() []string {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
+}
+
+func (x *GetTableDataResponse) GetRows() []*structpb.ListValue {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+func (x *GetTableDataResponse) GetTotalRows() int32 {
+	if x != nil {
+		return x.TotalRows
+	}
+	return 0
+}
+
+func (x *GetTableDataResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+// Health check request
+type HealthRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteFunctionResponse) Reset() {
-	*x = DeleteFunctionResponse{}
-	mi := &file_proto_gogent_proto_msgTypes[40]
+func (x *HealthRequest) Reset() {
+	*x = HealthRequest{}
+	mi := &file_proto_gogent_proto_msgTypes[79]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteFunctionResponse) String() string {
+func (x *HealthRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteFunctionResponse) ProtoMessage() {}
+func (*HealthRequest) ProtoMessage() {}
 
-func (x *DeleteFunctionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[40]
+func (x *HealthRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[79]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2181,44 +4988,39 @@ func (x *DeleteFunctionResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteFunctionResponse.ProtoReflect.Descriptor instead.
-func (*DeleteFunctionResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{40}
-}
-
-func (x *DeleteFunctionResponse) GetMessage() string {
-	if x != nil {
-		return x.Message
-	}
-	return ""
+// Deprecated: Use HealthRequest.ProtoReflect.Descriptor instead.
+func (*HealthRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{79}
 }
 
-// Test function request
-type TestFunctionRequest struct {
+// Health check response; status is healthy, degraded or unhealthy, the worst of the checks
+type HealthResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	FunctionId    string                 `protobuf:"bytes,1,opt,name=function_id,json=functionId,proto3" json:"function_id,omitempty"`
-	Arguments     *structpb.Struct       `protobuf:"bytes,2,opt,name=arguments,proto3" json:"arguments,omitempty"`
-	UseMockData   bool                   `protobuf:"varint,3,opt,name=use_mock_data,json=useMockData,proto3" json:"use_mock_data,omitempty"`
-	TimeoutMs     int32                  `protobuf:"varint,4,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Database      bool                   `protobuf:"varint,4,opt,name=database,proto3" json:"database,omitempty"`
+	GeminiApi     bool                   `protobuf:"varint,5,opt,name=gemini_api,json=geminiApi,proto3" json:"gemini_api,omitempty"`
+	Checks        []*HealthCheck         `protobuf:"bytes,6,rep,name=checks,proto3" json:"checks,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TestFunctionRequest) Reset() {
-	*x = TestFunctionRequest{}
-	mi := &file_proto_gogent_proto_msgTypes[41]
+func (x *HealthResponse) Reset() {
+	*x = HealthResponse{}
+	mi := &file_proto_gogent_proto_msgTypes[80]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TestFunctionRequest) String() string {
+func (x *HealthResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TestFunctionRequest) ProtoMessage() {}
+func (*HealthResponse) ProtoMessage() {}
 
-func (x *TestFunctionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[41]
+func (x *HealthResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[80]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2229,66 +5031,81 @@ func (x *TestFunctionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TestFunctionRequest.ProtoReflect.Descriptor instead.
-func (*TestFunctionRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{41}
+// Deprecated: Use HealthResponse.ProtoReflect.Descriptor instead.
+func (*HealthResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *HealthResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
 }
 
-func (x *TestFunctionRequest) GetFunctionId() string {
+func (x *HealthResponse) GetVersion() string {
 	if x != nil {
-		return x.FunctionId
+		return x.Version
 	}
 	return ""
 }
 
-func (x *TestFunctionRequest) GetArguments() *structpb.Struct {
+func (x *HealthResponse) GetTimestamp() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Arguments
+		return x.Timestamp
 	}
 	return nil
 }
 
-func (x *TestFunctionRequest) GetUseMockData() bool {
+func (x *HealthResponse) GetDatabase() bool {
 	if x != nil {
-		return x.UseMockData
+		return x.Database
 	}
 	return false
 }
 
-func (x *TestFunctionRequest) GetTimeoutMs() int32 {
+func (x *HealthResponse) GetGeminiApi() bool {
 	if x != nil {
-		return x.TimeoutMs
+		return x.GeminiApi
 	}
-	return 0
+	return false
 }
 
-// Test function response
-type TestFunctionResponse struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	Success         bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	UsedMockData    bool                   `protobuf:"varint,2,opt,name=used_mock_data,json=usedMockData,proto3" json:"used_mock_data,omitempty"`
-	ExecutionTimeMs int32                  `protobuf:"varint,3,opt,name=execution_time_ms,json=executionTimeMs,proto3" json:"execution_time_ms,omitempty"`
-	Response        *structpb.Struct       `protobuf:"bytes,4,opt,name=response,proto3" json:"response,omitempty"`
-	ErrorMessage    string                 `protobuf:"bytes,5,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+func (x *HealthResponse) GetChecks() []*HealthCheck {
+	if x != nil {
+		return x.Checks
+	}
+	return nil
 }
 
-func (x *TestFunctionResponse) Reset() {
-	*x = TestFunctionResponse{}
-	mi := &file_proto_gogent_proto_msgTypes[42]
+// The outcome of checking one dependency: database, migrations, provider, neo4j or queue
+type HealthCheck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	LatencyMs     int64                  `protobuf:"varint,3,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"`
+	Message       string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	Details       *structpb.Struct       `protobuf:"bytes,5,opt,name=details,proto3" json:"details,omitempty"`
+	CheckedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=checked_at,json=checkedAt,proto3" json:"checked_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthCheck) Reset() {
+	*x = HealthCheck{}
+	mi := &file_proto_gogent_proto_msgTypes[81]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TestFunctionResponse) String() string {
+func (x *HealthCheck) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TestFunctionResponse) ProtoMessage() {}
+func (*HealthCheck) ProtoMessage() {}
 
-func (x *TestFunctionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[42]
+func (x *HealthCheck) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[81]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2299,68 +5116,88 @@ func (x *TestFunctionResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TestFunctionResponse.ProtoReflect.Descriptor instead.
-func (*TestFunctionResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{42}
+// Deprecated: Use HealthCheck.ProtoReflect.Descriptor instead.
+func (*HealthCheck) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{81}
 }
 
-func (x *TestFunctionResponse) GetSuccess() bool {
+func (x *HealthCheck) GetName() string {
 	if x != nil {
-		return x.Success
+		return x.Name
 	}
-	return false
+	return ""
 }
 
-func (x *TestFunctionResponse) GetUsedMockData() bool {
+func (x *HealthCheck) GetStatus() string {
 	if x != nil {
-		return x.UsedMockData
+		return x.Status
 	}
-	return false
+	return ""
 }
 
-func (x *TestFunctionResponse) GetExecutionTimeMs() int32 {
+func (x *HealthCheck) GetLatencyMs() int64 {
 	if x != nil {
-		return x.ExecutionTimeMs
+		return x.LatencyMs
 	}
 	return 0
 }
 
-func (x *TestFunctionResponse) GetResponse() *structpb.Struct {
+func (x *HealthCheck) GetMessage() string {
 	if x != nil {
-		return x.Response
+		return x.Message
+	}
+	return ""
+}
+
+func (x *HealthCheck) GetDetails() *structpb.Struct {
+	if x != nil {
+		return x.Details
 	}
 	return nil
 }
 
-func (x *TestFunctionResponse) GetErrorMessage() string {
+func (x *HealthCheck) GetCheckedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.ErrorMessage
+		return x.CheckedAt
 	}
-	return ""
+	return nil
 }
 
-// Get database stats request
-type GetDatabaseStatsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// Execution run represents a group of related API calls
+type ExecutionRun struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	Id                     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId                 string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Name                   string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Description            string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	EnableFunctionCalling  bool                   `protobuf:"varint,5,opt,name=enable_function_calling,json=enableFunctionCalling,proto3" json:"enable_function_calling,omitempty"`
+	Status                 string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"` // pending, running, completed, failed
+	ErrorMessage           string                 `protobuf:"bytes,7,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	CreatedAt              *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt              *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Deterministic          bool                   `protobuf:"varint,10,opt,name=deterministic,proto3" json:"deterministic,omitempty"`
+	DeterminismFingerprint string                 `protobuf:"bytes,11,opt,name=determinism_fingerprint,json=determinismFingerprint,proto3" json:"determinism_fingerprint,omitempty"` // SHA-256 of the run's inputs; equal fingerprints mean identical inputs
+	RunSpec                string                 `protobuf:"bytes,12,opt,name=run_spec,json=runSpec,proto3" json:"run_spec,omitempty"`                                              // JSON of the resolved run spec; set when a single run is loaded
+	Tags                   []string               `protobuf:"bytes,13,rep,name=tags,proto3" json:"tags,omitempty"`                                                                   // Set when a single run is loaded
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
 }
 
-func (x *GetDatabaseStatsRequest) Reset() {
-	*x = GetDatabaseStatsRequest{}
-	mi := &file_proto_gogent_proto_msgTypes[43]
+func (x *ExecutionRun) Reset() {
+	*x = ExecutionRun{}
+	mi := &file_proto_gogent_proto_msgTypes[82]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetDatabaseStatsRequest) String() string {
+func (x *ExecutionRun) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetDatabaseStatsRequest) ProtoMessage() {}
+func (*ExecutionRun) ProtoMessage() {}
 
-func (x *GetDatabaseStatsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[43]
+func (x *ExecutionRun) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[82]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2371,118 +5208,156 @@ func (x *GetDatabaseStatsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetDatabaseStatsRequest.ProtoReflect.Descriptor instead.
-func (*GetDatabaseStatsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{43}
+// Deprecated: Use ExecutionRun.ProtoReflect.Descriptor instead.
+func (*ExecutionRun) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{82}
 }
 
-// Get database stats response
-type GetDatabaseStatsResponse struct {
-	state              protoimpl.MessageState `protogen:"open.v1"`
-	TotalExecutionRuns int32                  `protobuf:"varint,1,opt,name=total_execution_runs,json=totalExecutionRuns,proto3" json:"total_execution_runs,omitempty"`
-	TotalApiRequests   int32                  `protobuf:"varint,2,opt,name=total_api_requests,json=totalApiRequests,proto3" json:"total_api_requests,omitempty"`
-	TotalApiResponses  int32                  `protobuf:"varint,3,opt,name=total_api_responses,json=totalApiResponses,proto3" json:"total_api_responses,omitempty"`
-	TotalFunctionCalls int32                  `protobuf:"varint,4,opt,name=total_function_calls,json=totalFunctionCalls,proto3" json:"total_function_calls,omitempty"`
-	AvgResponseTime    float64                `protobuf:"fixed64,5,opt,name=avg_response_time,json=avgResponseTime,proto3" json:"avg_response_time,omitempty"`
-	SuccessRate        float64                `protobuf:"fixed64,6,opt,name=success_rate,json=successRate,proto3" json:"success_rate,omitempty"`
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+func (x *ExecutionRun) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
 }
 
-func (x *GetDatabaseStatsResponse) Reset() {
-	*x = GetDatabaseStatsResponse{}
-	mi := &file_proto_gogent_proto_msgTypes[44]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *ExecutionRun) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
 }
 
-func (x *GetDatabaseStatsResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *ExecutionRun) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
 }
 
-func (*GetDatabaseStatsResponse) ProtoMessage() {}
+func (x *ExecutionRun) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
 
-func (x *GetDatabaseStatsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[44]
+func (x *ExecutionRun) GetEnableFunctionCalling() bool {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.EnableFunctionCalling
 	}
-	return mi.MessageOf(x)
+	return false
 }
 
-// Deprecated: Use GetDatabaseStatsResponse.ProtoReflect.Descriptor instead.
-func (*GetDatabaseStatsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{44}
+func (x *ExecutionRun) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
 }
 
-func (x *GetDatabaseStatsResponse) GetTotalExecutionRuns() int32 {
+func (x *ExecutionRun) GetErrorMessage() string {
 	if x != nil {
-		return x.TotalExecutionRuns
+		return x.ErrorMessage
 	}
-	return 0
+	return ""
 }
 
-func (x *GetDatabaseStatsResponse) GetTotalApiRequests() int32 {
+func (x *ExecutionRun) GetCreatedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.TotalApiRequests
+		return x.CreatedAt
 	}
-	return 0
+	return nil
 }
 
-func (x *GetDatabaseStatsResponse) GetTotalApiResponses() int32 {
+func (x *ExecutionRun) GetUpdatedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.TotalApiResponses
+		return x.UpdatedAt
 	}
-	return 0
+	return nil
 }
 
-func (x *GetDatabaseStatsResponse) GetTotalFunctionCalls() int32 {
+func (x *ExecutionRun) GetDeterministic() bool {
 	if x != nil {
-		return x.TotalFunctionCalls
+		return x.Deterministic
 	}
-	return 0
+	return false
 }
 
-func (x *GetDatabaseStatsResponse) GetAvgResponseTime() float64 {
+func (x *ExecutionRun) GetDeterminismFingerprint() string {
 	if x != nil {
-		return x.AvgResponseTime
+		return x.DeterminismFingerprint
 	}
-	return 0
+	return ""
 }
 
-func (x *GetDatabaseStatsResponse) GetSuccessRate() float64 {
+func (x *ExecutionRun) GetRunSpec() string {
 	if x != nil {
-		return x.SuccessRate
+		return x.RunSpec
 	}
-	return 0
+	return ""
 }
 
-// List database tables request
-type ListDatabaseTablesRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *ExecutionRun) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
 }
 
-func (x *ListDatabaseTablesRequest) Reset() {
-	*x = ListDatabaseTablesRequest{}
-	mi := &file_proto_gogent_proto_msgTypes[45]
+// API configuration for multi-variation execution
+type APIConfiguration struct {
+	state                      protoimpl.MessageState `protogen:"open.v1"`
+	Id                         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ExecutionRunId             string                 `protobuf:"bytes,2,opt,name=execution_run_id,json=executionRunId,proto3" json:"execution_run_id,omitempty"`
+	VariationName              string                 `protobuf:"bytes,3,opt,name=variation_name,json=variationName,proto3" json:"variation_name,omitempty"`
+	ModelName                  string                 `protobuf:"bytes,4,opt,name=model_name,json=modelName,proto3" json:"model_name,omitempty"`
+	SystemPrompt               string                 `protobuf:"bytes,5,opt,name=system_prompt,json=systemPrompt,proto3" json:"system_prompt,omitempty"`
+	Temperature                float32                `protobuf:"fixed32,6,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	MaxTokens                  int32                  `protobuf:"varint,7,opt,name=max_tokens,json=maxTokens,proto3" json:"max_tokens,omitempty"`
+	TopP                       float32                `protobuf:"fixed32,8,opt,name=top_p,json=topP,proto3" json:"top_p,omitempty"`
+	TopK                       int32                  `protobuf:"varint,9,opt,name=top_k,json=topK,proto3" json:"top_k,omitempty"`
+	SafetySettings             *structpb.Struct       `protobuf:"bytes,10,opt,name=safety_settings,json=safetySettings,proto3" json:"safety_settings,omitempty"`
+	GenerationConfig           *structpb.Struct       `protobuf:"bytes,11,opt,name=generation_config,json=generationConfig,proto3" json:"generation_config,omitempty"`
+	Tools                      []*Tool                `protobuf:"bytes,12,rep,name=tools,proto3" json:"tools,omitempty"`
+	ToolConfig                 *structpb.Struct       `protobuf:"bytes,13,opt,name=tool_config,json=toolConfig,proto3" json:"tool_config,omitempty"`
+	CreatedAt                  *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ToolNames                  []string               `protobuf:"bytes,15,rep,name=tool_names,json=toolNames,proto3" json:"tool_names,omitempty"`                               // Subset of function tools to expose (empty = all)
+	DisableTools               bool                   `protobuf:"varint,16,opt,name=disable_tools,json=disableTools,proto3" json:"disable_tools,omitempty"`                     // Run this variation without any tools
+	FunctionInstruction        string                 `protobuf:"bytes,17,opt,name=function_instruction,json=functionInstruction,proto3" json:"function_instruction,omitempty"` // Instruction prepended to tool-enabled prompts (empty = run/engine default)
+	DisableFunctionInstruction bool                   `protobuf:"varint,18,opt,name=disable_function_instruction,json=disableFunctionInstruction,proto3" json:"disable_function_instruction,omitempty"`
+	SafetyPolicy               *SafetyPolicy          `protobuf:"bytes,19,opt,name=safety_policy,json=safetyPolicy,proto3" json:"safety_policy,omitempty"`                           // Provider-agnostic safety posture (overrides the run policy)
+	ResponseMimeType           string                 `protobuf:"bytes,20,opt,name=response_mime_type,json=responseMimeType,proto3" json:"response_mime_type,omitempty"`             // Structured output format, e.g. application/json
+	ResponseSchema             *structpb.Struct       `protobuf:"bytes,21,opt,name=response_schema,json=responseSchema,proto3" json:"response_schema,omitempty"`                     // Schema the response must match
+	InlineSystemPrompt         bool                   `protobuf:"varint,22,opt,name=inline_system_prompt,json=inlineSystemPrompt,proto3" json:"inline_system_prompt,omitempty"`      // Prepend the system prompt to the prompt instead of sending a system instruction
+	PresetId                   string                 `protobuf:"bytes,23,opt,name=preset_id,json=presetId,proto3" json:"preset_id,omitempty"`                                       // Configuration preset to start from; fields set here override the preset's
+	TimeoutMs                  int32                  `protobuf:"varint,24,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`                                   // How long the variation may run; 0 uses the server's timeout
+	Backend                    string                 `protobuf:"bytes,25,opt,name=backend,proto3" json:"backend,omitempty"`                                                         // gemini (default) or vertex
+	Provider                   string                 `protobuf:"bytes,26,opt,name=provider,proto3" json:"provider,omitempty"`                                                       // e.g. ollama for local models; empty infers it from the model name
+	Retrieval                  *RetrievalConfig       `protobuf:"bytes,27,opt,name=retrieval,proto3" json:"retrieval,omitempty"`                                                     // Document chunks added to the context before the variation runs
+	Guardrails                 []*GuardConfig         `protobuf:"bytes,28,rep,name=guardrails,proto3" json:"guardrails,omitempty"`                                                   // Guards run on the prompt before the model call and on the response after it
+	ToolChoiceMode             string                 `protobuf:"bytes,29,opt,name=tool_choice_mode,json=toolChoiceMode,proto3" json:"tool_choice_mode,omitempty"`                   // AUTO, ANY (default) or NONE
+	AllowedFunctionNames       []string               `protobuf:"bytes,30,rep,name=allowed_function_names,json=allowedFunctionNames,proto3" json:"allowed_function_names,omitempty"` // Functions the model may call in ANY mode (empty = all)
+	ThinkingBudget             *int32                 `protobuf:"varint,31,opt,name=thinking_budget,json=thinkingBudget,proto3,oneof" json:"thinking_budget,omitempty"`              // Gemini thinking tokens: -1 lets the model decide, 0 turns thinking off
+	CandidateCount             int32                  `protobuf:"varint,32,opt,name=candidate_count,json=candidateCount,proto3" json:"candidate_count,omitempty"`                    // Responses generated per request, each compared as a sub-variation
+	unknownFields              protoimpl.UnknownFields
+	sizeCache                  protoimpl.SizeCache
+}
+
+func (x *APIConfiguration) Reset() {
+	*x = APIConfiguration{}
+	mi := &file_proto_gogent_proto_msgTypes[83]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListDatabaseTablesRequest) String() string {
+func (x *APIConfiguration) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListDatabaseTablesRequest) ProtoMessage() {}
+func (*APIConfiguration) ProtoMessage() {}
 
-func (x *ListDatabaseTablesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[45]
+func (x *APIConfiguration) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[83]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2493,250 +5368,261 @@ func (x *ListDatabaseTablesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListDatabaseTablesRequest.ProtoReflect.Descriptor instead.
-func (*ListDatabaseTablesRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{45}
+// Deprecated: Use APIConfiguration.ProtoReflect.Descriptor instead.
+func (*APIConfiguration) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{83}
 }
 
-// List database tables response
-type ListDatabaseTablesResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Tables        []string               `protobuf:"bytes,1,rep,name=tables,proto3" json:"tables,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *APIConfiguration) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *APIConfiguration) GetExecutionRunId() string {
+	if x != nil {
+		return x.ExecutionRunId
+	}
+	return ""
+}
+
+func (x *APIConfiguration) GetVariationName() string {
+	if x != nil {
+		return x.VariationName
+	}
+	return ""
+}
+
+func (x *APIConfiguration) GetModelName() string {
+	if x != nil {
+		return x.ModelName
+	}
+	return ""
+}
+
+func (x *APIConfiguration) GetSystemPrompt() string {
+	if x != nil {
+		return x.SystemPrompt
+	}
+	return ""
 }
 
-func (x *ListDatabaseTablesResponse) Reset() {
-	*x = ListDatabaseTablesResponse{}
-	mi := &file_proto_gogent_proto_msgTypes[46]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *APIConfiguration) GetTemperature() float32 {
+	if x != nil {
+		return x.Temperature
+	}
+	return 0
 }
 
-func (x *ListDatabaseTablesResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *APIConfiguration) GetMaxTokens() int32 {
+	if x != nil {
+		return x.MaxTokens
+	}
+	return 0
 }
 
-func (*ListDatabaseTablesResponse) ProtoMessage() {}
-
-func (x *ListDatabaseTablesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[46]
+func (x *APIConfiguration) GetTopP() float32 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.TopP
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use ListDatabaseTablesResponse.ProtoReflect.Descriptor instead.
-func (*ListDatabaseTablesResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{46}
+func (x *APIConfiguration) GetTopK() int32 {
+	if x != nil {
+		return x.TopK
+	}
+	return 0
 }
 
-func (x *ListDatabaseTablesResponse) GetTables() []string {
+func (x *APIConfiguration) GetSafetySettings() *structpb.Struct {
 	if x != nil {
-		return x.Tables
+		return x.SafetySettings
 	}
 	return nil
 }
 
-// Get table data request
-type GetTableDataRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	TableName     string                 `protobuf:"bytes,1,opt,name=table_name,json=tableName,proto3" json:"table_name,omitempty"`
-	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
-	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *APIConfiguration) GetGenerationConfig() *structpb.Struct {
+	if x != nil {
+		return x.GenerationConfig
+	}
+	return nil
 }
 
-func (x *GetTableDataRequest) Reset() {
-	*x = GetTableDataRequest{}
-	mi := &file_proto_gogent_proto_msgTypes[47]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *APIConfiguration) GetTools() []*Tool {
+	if x != nil {
+		return x.Tools
+	}
+	return nil
 }
 
-func (x *GetTableDataRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *APIConfiguration) GetToolConfig() *structpb.Struct {
+	if x != nil {
+		return x.ToolConfig
+	}
+	return nil
 }
 
-func (*GetTableDataRequest) ProtoMessage() {}
+func (x *APIConfiguration) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
 
-func (x *GetTableDataRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[47]
+func (x *APIConfiguration) GetToolNames() []string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.ToolNames
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use GetTableDataRequest.ProtoReflect.Descriptor instead.
-func (*GetTableDataRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{47}
+func (x *APIConfiguration) GetDisableTools() bool {
+	if x != nil {
+		return x.DisableTools
+	}
+	return false
 }
 
-func (x *GetTableDataRequest) GetTableName() string {
+func (x *APIConfiguration) GetFunctionInstruction() string {
 	if x != nil {
-		return x.TableName
+		return x.FunctionInstruction
 	}
 	return ""
 }
 
-func (x *GetTableDataRequest) GetLimit() int32 {
+func (x *APIConfiguration) GetDisableFunctionInstruction() bool {
 	if x != nil {
-		return x.Limit
+		return x.DisableFunctionInstruction
 	}
-	return 0
+	return false
 }
 
-func (x *GetTableDataRequest) GetOffset() int32 {
+func (x *APIConfiguration) GetSafetyPolicy() *SafetyPolicy {
 	if x != nil {
-		return x.Offset
+		return x.SafetyPolicy
 	}
-	return 0
+	return nil
 }
 
-// Get table data response
-type GetTableDataResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	TableName     string                 `protobuf:"bytes,1,opt,name=table_name,json=tableName,proto3" json:"table_name,omitempty"`
-	Columns       []string               `protobuf:"bytes,2,rep,name=columns,proto3" json:"columns,omitempty"`
-	Rows          []*structpb.ListValue  `protobuf:"bytes,3,rep,name=rows,proto3" json:"rows,omitempty"`
-	TotalRows     int32                  `protobuf:"varint,4,opt,name=total_rows,json=totalRows,proto3" json:"total_rows,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *APIConfiguration) GetResponseMimeType() string {
+	if x != nil {
+		return x.ResponseMimeType
+	}
+	return ""
 }
 
-func (x *GetTableDataResponse) Reset() {
-	*x = GetTableDataResponse{}
-	mi := &file_proto_gogent_proto_msgTypes[48]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *APIConfiguration) GetResponseSchema() *structpb.Struct {
+	if x != nil {
+		return x.ResponseSchema
+	}
+	return nil
 }
 
-func (x *GetTableDataResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *APIConfiguration) GetInlineSystemPrompt() bool {
+	if x != nil {
+		return x.InlineSystemPrompt
+	}
+	return false
 }
 
-func (*GetTableDataResponse) ProtoMessage() {}
-
-func (x *GetTableDataResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[48]
+func (x *APIConfiguration) GetPresetId() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.PresetId
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use GetTableDataResponse.ProtoReflect.Descriptor instead.
-func (*GetTableDataResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{48}
+func (x *APIConfiguration) GetTimeoutMs() int32 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
 }
 
-func (x *GetTableDataResponse) GetTableName() string {
+func (x *APIConfiguration) GetBackend() string {
 	if x != nil {
-		return x.TableName
+		return x.Backend
 	}
 	return ""
 }
 
-func (x *GetTableDataResponse) GetColumns() []string {
+func (x *APIConfiguration) GetProvider() string {
 	if x != nil {
-		return x.Columns
+		return x.Provider
 	}
-	return nil
+	return ""
 }
 
-func (x *GetTableDataResponse) GetRows() []*structpb.ListValue {
+func (x *APIConfiguration) GetRetrieval() *RetrievalConfig {
 	if x != nil {
-		return x.Rows
+		return x.Retrieval
 	}
 	return nil
 }
 
-func (x *GetTableDataResponse) GetTotalRows() int32 {
+func (x *APIConfiguration) GetGuardrails() []*GuardConfig {
 	if x != nil {
-		return x.TotalRows
+		return x.Guardrails
 	}
-	return 0
+	return nil
 }
 
-// Health check request
-type HealthRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *APIConfiguration) GetToolChoiceMode() string {
+	if x != nil {
+		return x.ToolChoiceMode
+	}
+	return ""
 }
 
-func (x *HealthRequest) Reset() {
-	*x = HealthRequest{}
-	mi := &file_proto_gogent_proto_msgTypes[49]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *APIConfiguration) GetAllowedFunctionNames() []string {
+	if x != nil {
+		return x.AllowedFunctionNames
+	}
+	return nil
 }
 
-func (x *HealthRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *APIConfiguration) GetThinkingBudget() int32 {
+	if x != nil && x.ThinkingBudget != nil {
+		return *x.ThinkingBudget
+	}
+	return 0
 }
 
-func (*HealthRequest) ProtoMessage() {}
-
-func (x *HealthRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[49]
+func (x *APIConfiguration) GetCandidateCount() int32 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.CandidateCount
 	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use HealthRequest.ProtoReflect.Descriptor instead.
-func (*HealthRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{49}
+	return 0
 }
 
-// Health check response
-type HealthResponse struct {
+// One guard run at one stage of a configuration
+type GuardConfig struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
-	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
-	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	Database      bool                   `protobuf:"varint,4,opt,name=database,proto3" json:"database,omitempty"`
-	GeminiApi     bool                   `protobuf:"varint,5,opt,name=gemini_api,json=geminiApi,proto3" json:"gemini_api,omitempty"`
+	Guard         string                 `protobuf:"bytes,1,opt,name=guard,proto3" json:"guard,omitempty"`   // pii, prompt_injection, banned_topics or a registered guard
+	Stage         string                 `protobuf:"bytes,2,opt,name=stage,proto3" json:"stage,omitempty"`   // input or output
+	Action        string                 `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"` // Input: block (default), sanitize or flag; output: flag (default) or redact
+	Topics        []string               `protobuf:"bytes,4,rep,name=topics,proto3" json:"topics,omitempty"` // Words and phrases the banned_topics guard looks for
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *HealthResponse) Reset() {
-	*x = HealthResponse{}
-	mi := &file_proto_gogent_proto_msgTypes[50]
+func (x *GuardConfig) Reset() {
+	*x = GuardConfig{}
+	mi := &file_proto_gogent_proto_msgTypes[84]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HealthResponse) String() string {
+func (x *GuardConfig) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HealthResponse) ProtoMessage() {}
+func (*GuardConfig) ProtoMessage() {}
 
-func (x *HealthResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[50]
+func (x *GuardConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[84]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2747,77 +5633,66 @@ func (x *HealthResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HealthResponse.ProtoReflect.Descriptor instead.
-func (*HealthResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{50}
+// Deprecated: Use GuardConfig.ProtoReflect.Descriptor instead.
+func (*GuardConfig) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{84}
 }
 
-func (x *HealthResponse) GetStatus() string {
+func (x *GuardConfig) GetGuard() string {
 	if x != nil {
-		return x.Status
+		return x.Guard
 	}
 	return ""
 }
 
-func (x *HealthResponse) GetVersion() string {
+func (x *GuardConfig) GetStage() string {
 	if x != nil {
-		return x.Version
+		return x.Stage
 	}
 	return ""
 }
 
-func (x *HealthResponse) GetTimestamp() *timestamppb.Timestamp {
-	if x != nil {
-		return x.Timestamp
-	}
-	return nil
-}
-
-func (x *HealthResponse) GetDatabase() bool {
+func (x *GuardConfig) GetAction() string {
 	if x != nil {
-		return x.Database
+		return x.Action
 	}
-	return false
+	return ""
 }
 
-func (x *HealthResponse) GetGeminiApi() bool {
+func (x *GuardConfig) GetTopics() []string {
 	if x != nil {
-		return x.GeminiApi
+		return x.Topics
 	}
-	return false
+	return nil
 }
 
-// Execution run represents a group of related API calls
-type ExecutionRun struct {
-	state                 protoimpl.MessageState `protogen:"open.v1"`
-	Id                    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	UserId                string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Name                  string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
-	Description           string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
-	EnableFunctionCalling bool                   `protobuf:"varint,5,opt,name=enable_function_calling,json=enableFunctionCalling,proto3" json:"enable_function_calling,omitempty"`
-	Status                string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"` // pending, running, completed, failed
-	ErrorMessage          string                 `protobuf:"bytes,7,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
-	CreatedAt             *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt             *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	unknownFields         protoimpl.UnknownFields
-	sizeCache             protoimpl.SizeCache
+// What one guard found in a request's prompt or response
+type GuardVerdict struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Guard         string                 `protobuf:"bytes,1,opt,name=guard,proto3" json:"guard,omitempty"`
+	Stage         string                 `protobuf:"bytes,2,opt,name=stage,proto3" json:"stage,omitempty"`
+	Action        string                 `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
+	Triggered     bool                   `protobuf:"varint,4,opt,name=triggered,proto3" json:"triggered,omitempty"`
+	Findings      []string               `protobuf:"bytes,5,rep,name=findings,proto3" json:"findings,omitempty"` // Labels of the matches, e.g. email
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ExecutionRun) Reset() {
-	*x = ExecutionRun{}
-	mi := &file_proto_gogent_proto_msgTypes[51]
+func (x *GuardVerdict) Reset() {
+	*x = GuardVerdict{}
+	mi := &file_proto_gogent_proto_msgTypes[85]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ExecutionRun) String() string {
+func (x *GuardVerdict) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ExecutionRun) ProtoMessage() {}
+func (*GuardVerdict) ProtoMessage() {}
 
-func (x *ExecutionRun) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[51]
+func (x *GuardVerdict) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[85]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2828,110 +5703,145 @@ func (x *ExecutionRun) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ExecutionRun.ProtoReflect.Descriptor instead.
-func (*ExecutionRun) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{51}
+// Deprecated: Use GuardVerdict.ProtoReflect.Descriptor instead.
+func (*GuardVerdict) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{85}
 }
 
-func (x *ExecutionRun) GetId() string {
+func (x *GuardVerdict) GetGuard() string {
 	if x != nil {
-		return x.Id
+		return x.Guard
 	}
 	return ""
 }
 
-func (x *ExecutionRun) GetUserId() string {
+func (x *GuardVerdict) GetStage() string {
 	if x != nil {
-		return x.UserId
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *GuardVerdict) GetAction() string {
+	if x != nil {
+		return x.Action
 	}
 	return ""
 }
 
-func (x *ExecutionRun) GetName() string {
+func (x *GuardVerdict) GetTriggered() bool {
 	if x != nil {
-		return x.Name
+		return x.Triggered
 	}
-	return ""
+	return false
 }
 
-func (x *ExecutionRun) GetDescription() string {
+func (x *GuardVerdict) GetFindings() []string {
 	if x != nil {
-		return x.Description
+		return x.Findings
 	}
-	return ""
+	return nil
 }
 
-func (x *ExecutionRun) GetEnableFunctionCalling() bool {
+// Retrieval settings: the top_k chunks of a document collection most similar to the prompt
+type RetrievalConfig struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Collection     string                 `protobuf:"bytes,1,opt,name=collection,proto3" json:"collection,omitempty"`
+	TopK           int32                  `protobuf:"varint,2,opt,name=top_k,json=topK,proto3" json:"top_k,omitempty"`                              // Chunks to add (default 3, max 20)
+	MinScore       float64                `protobuf:"fixed64,3,opt,name=min_score,json=minScore,proto3" json:"min_score,omitempty"`                 // Lowest cosine similarity a chunk may score
+	EmbeddingModel string                 `protobuf:"bytes,4,opt,name=embedding_model,json=embeddingModel,proto3" json:"embedding_model,omitempty"` // Model the prompt and chunks are embedded with (default text-embedding-004)
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RetrievalConfig) Reset() {
+	*x = RetrievalConfig{}
+	mi := &file_proto_gogent_proto_msgTypes[86]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetrievalConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetrievalConfig) ProtoMessage() {}
+
+func (x *RetrievalConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[86]
 	if x != nil {
-		return x.EnableFunctionCalling
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return false
+	return mi.MessageOf(x)
 }
 
-func (x *ExecutionRun) GetStatus() string {
+// Deprecated: Use RetrievalConfig.ProtoReflect.Descriptor instead.
+func (*RetrievalConfig) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{86}
+}
+
+func (x *RetrievalConfig) GetCollection() string {
 	if x != nil {
-		return x.Status
+		return x.Collection
 	}
 	return ""
 }
 
-func (x *ExecutionRun) GetErrorMessage() string {
+func (x *RetrievalConfig) GetTopK() int32 {
 	if x != nil {
-		return x.ErrorMessage
+		return x.TopK
 	}
-	return ""
+	return 0
 }
 
-func (x *ExecutionRun) GetCreatedAt() *timestamppb.Timestamp {
+func (x *RetrievalConfig) GetMinScore() float64 {
 	if x != nil {
-		return x.CreatedAt
+		return x.MinScore
 	}
-	return nil
+	return 0
 }
 
-func (x *ExecutionRun) GetUpdatedAt() *timestamppb.Timestamp {
+func (x *RetrievalConfig) GetEmbeddingModel() string {
 	if x != nil {
-		return x.UpdatedAt
+		return x.EmbeddingModel
 	}
-	return nil
+	return ""
 }
 
-// API configuration for multi-variation execution
-type APIConfiguration struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	Id               string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	ExecutionRunId   string                 `protobuf:"bytes,2,opt,name=execution_run_id,json=executionRunId,proto3" json:"execution_run_id,omitempty"`
-	VariationName    string                 `protobuf:"bytes,3,opt,name=variation_name,json=variationName,proto3" json:"variation_name,omitempty"`
-	ModelName        string                 `protobuf:"bytes,4,opt,name=model_name,json=modelName,proto3" json:"model_name,omitempty"`
-	SystemPrompt     string                 `protobuf:"bytes,5,opt,name=system_prompt,json=systemPrompt,proto3" json:"system_prompt,omitempty"`
-	Temperature      float32                `protobuf:"fixed32,6,opt,name=temperature,proto3" json:"temperature,omitempty"`
-	MaxTokens        int32                  `protobuf:"varint,7,opt,name=max_tokens,json=maxTokens,proto3" json:"max_tokens,omitempty"`
-	TopP             float32                `protobuf:"fixed32,8,opt,name=top_p,json=topP,proto3" json:"top_p,omitempty"`
-	TopK             int32                  `protobuf:"varint,9,opt,name=top_k,json=topK,proto3" json:"top_k,omitempty"`
-	SafetySettings   *structpb.Struct       `protobuf:"bytes,10,opt,name=safety_settings,json=safetySettings,proto3" json:"safety_settings,omitempty"`
-	GenerationConfig *structpb.Struct       `protobuf:"bytes,11,opt,name=generation_config,json=generationConfig,proto3" json:"generation_config,omitempty"`
-	Tools            []*Tool                `protobuf:"bytes,12,rep,name=tools,proto3" json:"tools,omitempty"`
-	ToolConfig       *structpb.Struct       `protobuf:"bytes,13,opt,name=tool_config,json=toolConfig,proto3" json:"tool_config,omitempty"`
-	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+// A document chunk added to a request's context
+type RetrievedChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChunkId       string                 `protobuf:"bytes,1,opt,name=chunk_id,json=chunkId,proto3" json:"chunk_id,omitempty"`
+	DocumentId    string                 `protobuf:"bytes,2,opt,name=document_id,json=documentId,proto3" json:"document_id,omitempty"`
+	DocumentName  string                 `protobuf:"bytes,3,opt,name=document_name,json=documentName,proto3" json:"document_name,omitempty"`
+	Collection    string                 `protobuf:"bytes,4,opt,name=collection,proto3" json:"collection,omitempty"`
+	ChunkIndex    int32                  `protobuf:"varint,5,opt,name=chunk_index,json=chunkIndex,proto3" json:"chunk_index,omitempty"` // Position of the chunk in its document
+	Content       string                 `protobuf:"bytes,6,opt,name=content,proto3" json:"content,omitempty"`
+	Score         float64                `protobuf:"fixed64,7,opt,name=score,proto3" json:"score,omitempty"`
+	Rank          int32                  `protobuf:"varint,8,opt,name=rank,proto3" json:"rank,omitempty"` // 1-based position in the context
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *APIConfiguration) Reset() {
-	*x = APIConfiguration{}
-	mi := &file_proto_gogent_proto_msgTypes[52]
+func (x *RetrievedChunk) Reset() {
+	*x = RetrievedChunk{}
+	mi := &file_proto_gogent_proto_msgTypes[87]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *APIConfiguration) String() string {
+func (x *RetrievedChunk) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*APIConfiguration) ProtoMessage() {}
+func (*RetrievedChunk) ProtoMessage() {}
 
-func (x *APIConfiguration) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[52]
+func (x *RetrievedChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[87]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2942,122 +5852,129 @@ func (x *APIConfiguration) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use APIConfiguration.ProtoReflect.Descriptor instead.
-func (*APIConfiguration) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{52}
+// Deprecated: Use RetrievedChunk.ProtoReflect.Descriptor instead.
+func (*RetrievedChunk) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{87}
 }
 
-func (x *APIConfiguration) GetId() string {
+func (x *RetrievedChunk) GetChunkId() string {
 	if x != nil {
-		return x.Id
+		return x.ChunkId
 	}
 	return ""
 }
 
-func (x *APIConfiguration) GetExecutionRunId() string {
+func (x *RetrievedChunk) GetDocumentId() string {
 	if x != nil {
-		return x.ExecutionRunId
+		return x.DocumentId
 	}
 	return ""
 }
 
-func (x *APIConfiguration) GetVariationName() string {
+func (x *RetrievedChunk) GetDocumentName() string {
 	if x != nil {
-		return x.VariationName
+		return x.DocumentName
 	}
 	return ""
 }
 
-func (x *APIConfiguration) GetModelName() string {
+func (x *RetrievedChunk) GetCollection() string {
 	if x != nil {
-		return x.ModelName
+		return x.Collection
 	}
 	return ""
 }
 
-func (x *APIConfiguration) GetSystemPrompt() string {
+func (x *RetrievedChunk) GetChunkIndex() int32 {
 	if x != nil {
-		return x.SystemPrompt
+		return x.ChunkIndex
 	}
-	return ""
+	return 0
 }
 
-func (x *APIConfiguration) GetTemperature() float32 {
+func (x *RetrievedChunk) GetContent() string {
 	if x != nil {
-		return x.Temperature
+		return x.Content
 	}
-	return 0
+	return ""
 }
 
-func (x *APIConfiguration) GetMaxTokens() int32 {
+func (x *RetrievedChunk) GetScore() float64 {
 	if x != nil {
-		return x.MaxTokens
+		return x.Score
 	}
 	return 0
 }
 
-func (x *APIConfiguration) GetTopP() float32 {
+func (x *RetrievedChunk) GetRank() int32 {
 	if x != nil {
-		return x.TopP
+		return x.Rank
 	}
 	return 0
 }
 
-func (x *APIConfiguration) GetTopK() int32 {
-	if x != nil {
-		return x.TopK
-	}
-	return 0
+// Provider-agnostic safety policy: normalized category -> threshold
+// (categories: harassment, hate_speech, sexually_explicit, dangerous_content;
+// thresholds: off, block_high, block_medium, block_low)
+type SafetyPolicy struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Thresholds    map[string]string      `protobuf:"bytes,1,rep,name=thresholds,proto3" json:"thresholds,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *APIConfiguration) GetSafetySettings() *structpb.Struct {
-	if x != nil {
-		return x.SafetySettings
-	}
-	return nil
+func (x *SafetyPolicy) Reset() {
+	*x = SafetyPolicy{}
+	mi := &file_proto_gogent_proto_msgTypes[88]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *APIConfiguration) GetGenerationConfig() *structpb.Struct {
-	if x != nil {
-		return x.GenerationConfig
-	}
-	return nil
+func (x *SafetyPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *APIConfiguration) GetTools() []*Tool {
+func (*SafetyPolicy) ProtoMessage() {}
+
+func (x *SafetyPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[88]
 	if x != nil {
-		return x.Tools
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *APIConfiguration) GetToolConfig() *structpb.Struct {
-	if x != nil {
-		return x.ToolConfig
-	}
-	return nil
+// Deprecated: Use SafetyPolicy.ProtoReflect.Descriptor instead.
+func (*SafetyPolicy) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{88}
 }
 
-func (x *APIConfiguration) GetCreatedAt() *timestamppb.Timestamp {
+func (x *SafetyPolicy) GetThresholds() map[string]string {
 	if x != nil {
-		return x.CreatedAt
+		return x.Thresholds
 	}
 	return nil
 }
 
 // Tool definition for function calling
 type Tool struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
-	Parameters    *structpb.Struct       `protobuf:"bytes,3,opt,name=parameters,proto3" json:"parameters,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Name            string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description     string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Parameters      *structpb.Struct       `protobuf:"bytes,3,opt,name=parameters,proto3" json:"parameters,omitempty"`
+	MockResponse    *structpb.Struct       `protobuf:"bytes,4,opt,name=mock_response,json=mockResponse,proto3" json:"mock_response,omitempty"`             // Returned instead of calling the function in deterministic runs
+	UseMockResponse bool                   `protobuf:"varint,5,opt,name=use_mock_response,json=useMockResponse,proto3" json:"use_mock_response,omitempty"` // Return the function definition's mock response instead of calling it
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *Tool) Reset() {
 	*x = Tool{}
-	mi := &file_proto_gogent_proto_msgTypes[53]
+	mi := &file_proto_gogent_proto_msgTypes[89]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3069,7 +5986,7 @@ func (x *Tool) String() string {
 func (*Tool) ProtoMessage() {}
 
 func (x *Tool) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[53]
+	mi := &file_proto_gogent_proto_msgTypes[89]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3082,7 +5999,7 @@ func (x *Tool) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Tool.ProtoReflect.Descriptor instead.
 func (*Tool) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{53}
+	return file_proto_gogent_proto_rawDescGZIP(), []int{89}
 }
 
 func (x *Tool) GetName() string {
@@ -3106,6 +6023,20 @@ func (x *Tool) GetParameters() *structpb.Struct {
 	return nil
 }
 
+func (x *Tool) GetMockResponse() *structpb.Struct {
+	if x != nil {
+		return x.MockResponse
+	}
+	return nil
+}
+
+func (x *Tool) GetUseMockResponse() bool {
+	if x != nil {
+		return x.UseMockResponse
+	}
+	return false
+}
+
 // Function definition
 type FunctionDefinition struct {
 	state            protoimpl.MessageState `protogen:"open.v1"`
@@ -3125,13 +6056,17 @@ type FunctionDefinition struct {
 	ApiKeyValidation *structpb.Struct       `protobuf:"bytes,16,opt,name=api_key_validation,json=apiKeyValidation,proto3" json:"api_key_validation,omitempty"` // Validation rules for each API key
 	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt        *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Sandbox limits enforced when the endpoint is called; 0 or empty uses the server defaults
+	TimeoutMs        int32    `protobuf:"varint,17,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`
+	MaxResponseBytes int32    `protobuf:"varint,18,opt,name=max_response_bytes,json=maxResponseBytes,proto3" json:"max_response_bytes,omitempty"`
+	AllowedDomains   []string `protobuf:"bytes,19,rep,name=allowed_domains,json=allowedDomains,proto3" json:"allowed_domains,omitempty"`
 	unknownFields    protoimpl.UnknownFields
 	sizeCache        protoimpl.SizeCache
 }
 
 func (x *FunctionDefinition) Reset() {
 	*x = FunctionDefinition{}
-	mi := &file_proto_gogent_proto_msgTypes[54]
+	mi := &file_proto_gogent_proto_msgTypes[90]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3143,7 +6078,7 @@ func (x *FunctionDefinition) String() string {
 func (*FunctionDefinition) ProtoMessage() {}
 
 func (x *FunctionDefinition) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[54]
+	mi := &file_proto_gogent_proto_msgTypes[90]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3156,7 +6091,7 @@ func (x *FunctionDefinition) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FunctionDefinition.ProtoReflect.Descriptor instead.
 func (*FunctionDefinition) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{54}
+	return file_proto_gogent_proto_rawDescGZIP(), []int{90}
 }
 
 func (x *FunctionDefinition) GetId() string {
@@ -3271,6 +6206,27 @@ func (x *FunctionDefinition) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *FunctionDefinition) GetTimeoutMs() int32 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+func (x *FunctionDefinition) GetMaxResponseBytes() int32 {
+	if x != nil {
+		return x.MaxResponseBytes
+	}
+	return 0
+}
+
+func (x *FunctionDefinition) GetAllowedDomains() []string {
+	if x != nil {
+		return x.AllowedDomains
+	}
+	return nil
+}
+
 // API request
 type APIRequest struct {
 	state              protoimpl.MessageState `protogen:"open.v1"`
@@ -3285,13 +6241,14 @@ type APIRequest struct {
 	RequestHeaders     *structpb.Struct       `protobuf:"bytes,9,opt,name=request_headers,json=requestHeaders,proto3" json:"request_headers,omitempty"`
 	RequestBody        *structpb.Struct       `protobuf:"bytes,10,opt,name=request_body,json=requestBody,proto3" json:"request_body,omitempty"`
 	CreatedAt          *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	SystemPromptMode   string                 `protobuf:"bytes,12,opt,name=system_prompt_mode,json=systemPromptMode,proto3" json:"system_prompt_mode,omitempty"` // instruction or inline; empty without a system prompt
 	unknownFields      protoimpl.UnknownFields
 	sizeCache          protoimpl.SizeCache
 }
 
 func (x *APIRequest) Reset() {
 	*x = APIRequest{}
-	mi := &file_proto_gogent_proto_msgTypes[55]
+	mi := &file_proto_gogent_proto_msgTypes[91]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3303,7 +6260,7 @@ func (x *APIRequest) String() string {
 func (*APIRequest) ProtoMessage() {}
 
 func (x *APIRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[55]
+	mi := &file_proto_gogent_proto_msgTypes[91]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3316,7 +6273,7 @@ func (x *APIRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use APIRequest.ProtoReflect.Descriptor instead.
 func (*APIRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{55}
+	return file_proto_gogent_proto_rawDescGZIP(), []int{91}
 }
 
 func (x *APIRequest) GetId() string {
@@ -3396,6 +6353,13 @@ func (x *APIRequest) GetCreatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *APIRequest) GetSystemPromptMode() string {
+	if x != nil {
+		return x.SystemPromptMode
+	}
+	return ""
+}
+
 // API response
 type APIResponse struct {
 	state                protoimpl.MessageState `protogen:"open.v1"`
@@ -3412,13 +6376,16 @@ type APIResponse struct {
 	ResponseHeaders      *structpb.Struct       `protobuf:"bytes,11,opt,name=response_headers,json=responseHeaders,proto3" json:"response_headers,omitempty"`
 	ResponseBody         *structpb.Struct       `protobuf:"bytes,12,opt,name=response_body,json=responseBody,proto3" json:"response_body,omitempty"`
 	CreatedAt            *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Latency              *LatencyBreakdown      `protobuf:"bytes,14,opt,name=latency,proto3" json:"latency,omitempty"`
+	ThoughtSummary       string                 `protobuf:"bytes,15,opt,name=thought_summary,json=thoughtSummary,proto3" json:"thought_summary,omitempty"`  // The thinking model's summary of its reasoning
+	CandidateIndex       int32                  `protobuf:"varint,16,opt,name=candidate_index,json=candidateIndex,proto3" json:"candidate_index,omitempty"` // Which of the request's candidates this is, 0 for the first
 	unknownFields        protoimpl.UnknownFields
 	sizeCache            protoimpl.SizeCache
 }
 
 func (x *APIResponse) Reset() {
 	*x = APIResponse{}
-	mi := &file_proto_gogent_proto_msgTypes[56]
+	mi := &file_proto_gogent_proto_msgTypes[92]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3430,7 +6397,7 @@ func (x *APIResponse) String() string {
 func (*APIResponse) ProtoMessage() {}
 
 func (x *APIResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[56]
+	mi := &file_proto_gogent_proto_msgTypes[92]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3443,7 +6410,7 @@ func (x *APIResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use APIResponse.ProtoReflect.Descriptor instead.
 func (*APIResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{56}
+	return file_proto_gogent_proto_rawDescGZIP(), []int{92}
 }
 
 func (x *APIResponse) GetId() string {
@@ -3532,9 +6499,131 @@ func (x *APIResponse) GetResponseBody() *structpb.Struct {
 
 func (x *APIResponse) GetCreatedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.CreatedAt
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *APIResponse) GetLatency() *LatencyBreakdown {
+	if x != nil {
+		return x.Latency
+	}
+	return nil
+}
+
+func (x *APIResponse) GetThoughtSummary() string {
+	if x != nil {
+		return x.ThoughtSummary
+	}
+	return ""
+}
+
+func (x *APIResponse) GetCandidateIndex() int32 {
+	if x != nil {
+		return x.CandidateIndex
+	}
+	return 0
+}
+
+// Where a variation's time went, in milliseconds; total_ms excludes queued_ms
+type LatencyBreakdown struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	QueuedMs      int64                  `protobuf:"varint,1,opt,name=queued_ms,json=queuedMs,proto3" json:"queued_ms,omitempty"`
+	RetrievalMs   int64                  `protobuf:"varint,2,opt,name=retrieval_ms,json=retrievalMs,proto3" json:"retrieval_ms,omitempty"`
+	ModelMs       int64                  `protobuf:"varint,3,opt,name=model_ms,json=modelMs,proto3" json:"model_ms,omitempty"`
+	FunctionMs    int64                  `protobuf:"varint,4,opt,name=function_ms,json=functionMs,proto3" json:"function_ms,omitempty"`
+	FollowUpMs    int64                  `protobuf:"varint,5,opt,name=follow_up_ms,json=followUpMs,proto3" json:"follow_up_ms,omitempty"`
+	DbMs          int64                  `protobuf:"varint,6,opt,name=db_ms,json=dbMs,proto3" json:"db_ms,omitempty"`
+	OtherMs       int64                  `protobuf:"varint,7,opt,name=other_ms,json=otherMs,proto3" json:"other_ms,omitempty"`
+	TotalMs       int64                  `protobuf:"varint,8,opt,name=total_ms,json=totalMs,proto3" json:"total_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LatencyBreakdown) Reset() {
+	*x = LatencyBreakdown{}
+	mi := &file_proto_gogent_proto_msgTypes[93]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LatencyBreakdown) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LatencyBreakdown) ProtoMessage() {}
+
+func (x *LatencyBreakdown) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[93]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LatencyBreakdown.ProtoReflect.Descriptor instead.
+func (*LatencyBreakdown) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{93}
+}
+
+func (x *LatencyBreakdown) GetQueuedMs() int64 {
+	if x != nil {
+		return x.QueuedMs
+	}
+	return 0
+}
+
+func (x *LatencyBreakdown) GetRetrievalMs() int64 {
+	if x != nil {
+		return x.RetrievalMs
+	}
+	return 0
+}
+
+func (x *LatencyBreakdown) GetModelMs() int64 {
+	if x != nil {
+		return x.ModelMs
+	}
+	return 0
+}
+
+func (x *LatencyBreakdown) GetFunctionMs() int64 {
+	if x != nil {
+		return x.FunctionMs
+	}
+	return 0
+}
+
+func (x *LatencyBreakdown) GetFollowUpMs() int64 {
+	if x != nil {
+		return x.FollowUpMs
+	}
+	return 0
+}
+
+func (x *LatencyBreakdown) GetDbMs() int64 {
+	if x != nil {
+		return x.DbMs
+	}
+	return 0
+}
+
+func (x *LatencyBreakdown) GetOtherMs() int64 {
+	if x != nil {
+		return x.OtherMs
+	}
+	return 0
+}
+
+func (x *LatencyBreakdown) GetTotalMs() int64 {
+	if x != nil {
+		return x.TotalMs
 	}
-	return nil
+	return 0
 }
 
 // Function call
@@ -3555,7 +6644,7 @@ type FunctionCall struct {
 
 func (x *FunctionCall) Reset() {
 	*x = FunctionCall{}
-	mi := &file_proto_gogent_proto_msgTypes[57]
+	mi := &file_proto_gogent_proto_msgTypes[94]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3567,7 +6656,7 @@ func (x *FunctionCall) String() string {
 func (*FunctionCall) ProtoMessage() {}
 
 func (x *FunctionCall) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[57]
+	mi := &file_proto_gogent_proto_msgTypes[94]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3580,7 +6669,7 @@ func (x *FunctionCall) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FunctionCall.ProtoReflect.Descriptor instead.
 func (*FunctionCall) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{57}
+	return file_proto_gogent_proto_rawDescGZIP(), []int{94}
 }
 
 func (x *FunctionCall) GetId() string {
@@ -3648,21 +6737,23 @@ func (x *FunctionCall) GetCreatedAt() *timestamppb.Timestamp {
 
 // Execution result
 type ExecutionResult struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ExecutionRun  *ExecutionRun          `protobuf:"bytes,1,opt,name=execution_run,json=executionRun,proto3" json:"execution_run,omitempty"`
-	Results       []*VariationResult     `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
-	Comparison    *ComparisonResult      `protobuf:"bytes,3,opt,name=comparison,proto3" json:"comparison,omitempty"`
-	TotalTime     int64                  `protobuf:"varint,4,opt,name=total_time,json=totalTime,proto3" json:"total_time,omitempty"` // milliseconds
-	SuccessCount  int32                  `protobuf:"varint,5,opt,name=success_count,json=successCount,proto3" json:"success_count,omitempty"`
-	ErrorCount    int32                  `protobuf:"varint,6,opt,name=error_count,json=errorCount,proto3" json:"error_count,omitempty"`
-	Logs          []*ExecutionLog        `protobuf:"bytes,7,rep,name=logs,proto3" json:"logs,omitempty"`
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	ExecutionRun  *ExecutionRun            `protobuf:"bytes,1,opt,name=execution_run,json=executionRun,proto3" json:"execution_run,omitempty"`
+	Results       []*VariationResult       `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	Comparison    *ComparisonResult        `protobuf:"bytes,3,opt,name=comparison,proto3" json:"comparison,omitempty"`
+	TotalTime     int64                    `protobuf:"varint,4,opt,name=total_time,json=totalTime,proto3" json:"total_time,omitempty"` // milliseconds
+	SuccessCount  int32                    `protobuf:"varint,5,opt,name=success_count,json=successCount,proto3" json:"success_count,omitempty"`
+	ErrorCount    int32                    `protobuf:"varint,6,opt,name=error_count,json=errorCount,proto3" json:"error_count,omitempty"`
+	Logs          []*ExecutionLog          `protobuf:"bytes,7,rep,name=logs,proto3" json:"logs,omitempty"`
+	Accuracy      []*ConfigurationAccuracy `protobuf:"bytes,8,rep,name=accuracy,proto3" json:"accuracy,omitempty"`                          // Set when the request had an expected answer
+	SweepReport   *SweepReport             `protobuf:"bytes,9,opt,name=sweep_report,json=sweepReport,proto3" json:"sweep_report,omitempty"` // Set when the run came from a parameter sweep
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ExecutionResult) Reset() {
 	*x = ExecutionResult{}
-	mi := &file_proto_gogent_proto_msgTypes[58]
+	mi := &file_proto_gogent_proto_msgTypes[95]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3674,7 +6765,7 @@ func (x *ExecutionResult) String() string {
 func (*ExecutionResult) ProtoMessage() {}
 
 func (x *ExecutionResult) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[58]
+	mi := &file_proto_gogent_proto_msgTypes[95]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3687,7 +6778,7 @@ func (x *ExecutionResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExecutionResult.ProtoReflect.Descriptor instead.
 func (*ExecutionResult) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{58}
+	return file_proto_gogent_proto_rawDescGZIP(), []int{95}
 }
 
 func (x *ExecutionResult) GetExecutionRun() *ExecutionRun {
@@ -3739,21 +6830,38 @@ func (x *ExecutionResult) GetLogs() []*ExecutionLog {
 	return nil
 }
 
+func (x *ExecutionResult) GetAccuracy() []*ConfigurationAccuracy {
+	if x != nil {
+		return x.Accuracy
+	}
+	return nil
+}
+
+func (x *ExecutionResult) GetSweepReport() *SweepReport {
+	if x != nil {
+		return x.SweepReport
+	}
+	return nil
+}
+
 // Variation result
 type VariationResult struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Configuration *APIConfiguration      `protobuf:"bytes,1,opt,name=configuration,proto3" json:"configuration,omitempty"`
-	Request       *APIRequest            `protobuf:"bytes,2,opt,name=request,proto3" json:"request,omitempty"`
-	Response      *APIResponse           `protobuf:"bytes,3,opt,name=response,proto3" json:"response,omitempty"`
-	FunctionCalls []*FunctionCall        `protobuf:"bytes,4,rep,name=function_calls,json=functionCalls,proto3" json:"function_calls,omitempty"`
-	ExecutionTime int64                  `protobuf:"varint,5,opt,name=execution_time,json=executionTime,proto3" json:"execution_time,omitempty"` // milliseconds
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Configuration   *APIConfiguration      `protobuf:"bytes,1,opt,name=configuration,proto3" json:"configuration,omitempty"`
+	Request         *APIRequest            `protobuf:"bytes,2,opt,name=request,proto3" json:"request,omitempty"`
+	Response        *APIResponse           `protobuf:"bytes,3,opt,name=response,proto3" json:"response,omitempty"`
+	FunctionCalls   []*FunctionCall        `protobuf:"bytes,4,rep,name=function_calls,json=functionCalls,proto3" json:"function_calls,omitempty"`
+	ExecutionTime   int64                  `protobuf:"varint,5,opt,name=execution_time,json=executionTime,proto3" json:"execution_time,omitempty"`      // milliseconds
+	Repetition      int32                  `protobuf:"varint,6,opt,name=repetition,proto3" json:"repetition,omitempty"`                                 // 1-based sample number in repeated runs
+	RetrievedChunks []*RetrievedChunk      `protobuf:"bytes,7,rep,name=retrieved_chunks,json=retrievedChunks,proto3" json:"retrieved_chunks,omitempty"` // Document chunks added to the context, best match first
+	GuardVerdicts   []*GuardVerdict        `protobuf:"bytes,8,rep,name=guard_verdicts,json=guardVerdicts,proto3" json:"guard_verdicts,omitempty"`       // Verdicts of the configuration's guards, input guards first
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *VariationResult) Reset() {
 	*x = VariationResult{}
-	mi := &file_proto_gogent_proto_msgTypes[59]
+	mi := &file_proto_gogent_proto_msgTypes[96]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3765,7 +6873,7 @@ func (x *VariationResult) String() string {
 func (*VariationResult) ProtoMessage() {}
 
 func (x *VariationResult) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[59]
+	mi := &file_proto_gogent_proto_msgTypes[96]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3778,7 +6886,7 @@ func (x *VariationResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VariationResult.ProtoReflect.Descriptor instead.
 func (*VariationResult) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{59}
+	return file_proto_gogent_proto_rawDescGZIP(), []int{96}
 }
 
 func (x *VariationResult) GetConfiguration() *APIConfiguration {
@@ -3816,6 +6924,27 @@ func (x *VariationResult) GetExecutionTime() int64 {
 	return 0
 }
 
+func (x *VariationResult) GetRepetition() int32 {
+	if x != nil {
+		return x.Repetition
+	}
+	return 0
+}
+
+func (x *VariationResult) GetRetrievedChunks() []*RetrievedChunk {
+	if x != nil {
+		return x.RetrievedChunks
+	}
+	return nil
+}
+
+func (x *VariationResult) GetGuardVerdicts() []*GuardVerdict {
+	if x != nil {
+		return x.GuardVerdicts
+	}
+	return nil
+}
+
 // Comparison result
 type ComparisonResult struct {
 	state               protoimpl.MessageState `protogen:"open.v1"`
@@ -3829,13 +6958,14 @@ type ComparisonResult struct {
 	AllConfigurations   []*APIConfiguration    `protobuf:"bytes,8,rep,name=all_configurations,json=allConfigurations,proto3" json:"all_configurations,omitempty"`
 	AnalysisNotes       string                 `protobuf:"bytes,9,opt,name=analysis_notes,json=analysisNotes,proto3" json:"analysis_notes,omitempty"`
 	CreatedAt           *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	SignificanceTests   []*SignificanceTest    `protobuf:"bytes,11,rep,name=significance_tests,json=significanceTests,proto3" json:"significance_tests,omitempty"` // Set when configurations were repeated
 	unknownFields       protoimpl.UnknownFields
 	sizeCache           protoimpl.SizeCache
 }
 
 func (x *ComparisonResult) Reset() {
 	*x = ComparisonResult{}
-	mi := &file_proto_gogent_proto_msgTypes[60]
+	mi := &file_proto_gogent_proto_msgTypes[97]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3847,7 +6977,7 @@ func (x *ComparisonResult) String() string {
 func (*ComparisonResult) ProtoMessage() {}
 
 func (x *ComparisonResult) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[60]
+	mi := &file_proto_gogent_proto_msgTypes[97]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3860,7 +6990,7 @@ func (x *ComparisonResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ComparisonResult.ProtoReflect.Descriptor instead.
 func (*ComparisonResult) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{60}
+	return file_proto_gogent_proto_rawDescGZIP(), []int{97}
 }
 
 func (x *ComparisonResult) GetId() string {
@@ -3933,6 +7063,114 @@ func (x *ComparisonResult) GetCreatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *ComparisonResult) GetSignificanceTests() []*SignificanceTest {
+	if x != nil {
+		return x.SignificanceTests
+	}
+	return nil
+}
+
+// Welch's t-test of one metric between two repeated configurations
+type SignificanceTest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Metric           string                 `protobuf:"bytes,1,opt,name=metric,proto3" json:"metric,omitempty"`
+	ConfigurationA   string                 `protobuf:"bytes,2,opt,name=configuration_a,json=configurationA,proto3" json:"configuration_a,omitempty"` // Variation names
+	ConfigurationB   string                 `protobuf:"bytes,3,opt,name=configuration_b,json=configurationB,proto3" json:"configuration_b,omitempty"`
+	MeanDifference   float64                `protobuf:"fixed64,4,opt,name=mean_difference,json=meanDifference,proto3" json:"mean_difference,omitempty"` // Mean of A minus mean of B
+	TStatistic       float64                `protobuf:"fixed64,5,opt,name=t_statistic,json=tStatistic,proto3" json:"t_statistic,omitempty"`
+	DegreesOfFreedom float64                `protobuf:"fixed64,6,opt,name=degrees_of_freedom,json=degreesOfFreedom,proto3" json:"degrees_of_freedom,omitempty"`
+	PValue           float64                `protobuf:"fixed64,7,opt,name=p_value,json=pValue,proto3" json:"p_value,omitempty"`
+	Significant      bool                   `protobuf:"varint,8,opt,name=significant,proto3" json:"significant,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *SignificanceTest) Reset() {
+	*x = SignificanceTest{}
+	mi := &file_proto_gogent_proto_msgTypes[98]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignificanceTest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignificanceTest) ProtoMessage() {}
+
+func (x *SignificanceTest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[98]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignificanceTest.ProtoReflect.Descriptor instead.
+func (*SignificanceTest) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{98}
+}
+
+func (x *SignificanceTest) GetMetric() string {
+	if x != nil {
+		return x.Metric
+	}
+	return ""
+}
+
+func (x *SignificanceTest) GetConfigurationA() string {
+	if x != nil {
+		return x.ConfigurationA
+	}
+	return ""
+}
+
+func (x *SignificanceTest) GetConfigurationB() string {
+	if x != nil {
+		return x.ConfigurationB
+	}
+	return ""
+}
+
+func (x *SignificanceTest) GetMeanDifference() float64 {
+	if x != nil {
+		return x.MeanDifference
+	}
+	return 0
+}
+
+func (x *SignificanceTest) GetTStatistic() float64 {
+	if x != nil {
+		return x.TStatistic
+	}
+	return 0
+}
+
+func (x *SignificanceTest) GetDegreesOfFreedom() float64 {
+	if x != nil {
+		return x.DegreesOfFreedom
+	}
+	return 0
+}
+
+func (x *SignificanceTest) GetPValue() float64 {
+	if x != nil {
+		return x.PValue
+	}
+	return 0
+}
+
+func (x *SignificanceTest) GetSignificant() bool {
+	if x != nil {
+		return x.Significant
+	}
+	return false
+}
+
 // Execution log
 type ExecutionLog struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
@@ -3951,7 +7189,7 @@ type ExecutionLog struct {
 
 func (x *ExecutionLog) Reset() {
 	*x = ExecutionLog{}
-	mi := &file_proto_gogent_proto_msgTypes[61]
+	mi := &file_proto_gogent_proto_msgTypes[99]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3963,7 +7201,7 @@ func (x *ExecutionLog) String() string {
 func (*ExecutionLog) ProtoMessage() {}
 
 func (x *ExecutionLog) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[61]
+	mi := &file_proto_gogent_proto_msgTypes[99]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3976,7 +7214,7 @@ func (x *ExecutionLog) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExecutionLog.ProtoReflect.Descriptor instead.
 func (*ExecutionLog) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{61}
+	return file_proto_gogent_proto_rawDescGZIP(), []int{99}
 }
 
 func (x *ExecutionLog) GetId() string {
@@ -4044,17 +7282,19 @@ func (x *ExecutionLog) GetTimestamp() *timestamppb.Timestamp {
 
 // Comparison config
 type ComparisonConfig struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Enabled       bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
-	Metrics       []string               `protobuf:"bytes,2,rep,name=metrics,proto3" json:"metrics,omitempty"`
-	CustomRules   []string               `protobuf:"bytes,3,rep,name=custom_rules,json=customRules,proto3" json:"custom_rules,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state               protoimpl.MessageState     `protogen:"open.v1"`
+	Enabled             bool                       `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	Metrics             []string                   `protobuf:"bytes,2,rep,name=metrics,proto3" json:"metrics,omitempty"`
+	CustomRules         []string                   `protobuf:"bytes,3,rep,name=custom_rules,json=customRules,proto3" json:"custom_rules,omitempty"`
+	ToolAppropriateness *ToolAppropriatenessConfig `protobuf:"bytes,4,opt,name=tool_appropriateness,json=toolAppropriateness,proto3" json:"tool_appropriateness,omitempty"`
+	Judge               *JudgeConfig               `protobuf:"bytes,5,opt,name=judge,proto3" json:"judge,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
 }
 
 func (x *ComparisonConfig) Reset() {
 	*x = ComparisonConfig{}
-	mi := &file_proto_gogent_proto_msgTypes[62]
+	mi := &file_proto_gogent_proto_msgTypes[100]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4066,7 +7306,7 @@ func (x *ComparisonConfig) String() string {
 func (*ComparisonConfig) ProtoMessage() {}
 
 func (x *ComparisonConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gogent_proto_msgTypes[62]
+	mi := &file_proto_gogent_proto_msgTypes[100]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4079,7 +7319,7 @@ func (x *ComparisonConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ComparisonConfig.ProtoReflect.Descriptor instead.
 func (*ComparisonConfig) Descriptor() ([]byte, []int) {
-	return file_proto_gogent_proto_rawDescGZIP(), []int{62}
+	return file_proto_gogent_proto_rawDescGZIP(), []int{100}
 }
 
 func (x *ComparisonConfig) GetEnabled() bool {
@@ -4103,11 +7343,155 @@ func (x *ComparisonConfig) GetCustomRules() []string {
 	return nil
 }
 
+func (x *ComparisonConfig) GetToolAppropriateness() *ToolAppropriatenessConfig {
+	if x != nil {
+		return x.ToolAppropriateness
+	}
+	return nil
+}
+
+func (x *ComparisonConfig) GetJudge() *JudgeConfig {
+	if x != nil {
+		return x.Judge
+	}
+	return nil
+}
+
+// Configures the model that grades responses during comparison
+type JudgeConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Model         string                 `protobuf:"bytes,1,opt,name=model,proto3" json:"model,omitempty"`                                    // Judge model; defaults to gemini-1.5-flash
+	Criteria      string                 `protobuf:"bytes,2,opt,name=criteria,proto3" json:"criteria,omitempty"`                              // What the judge grades
+	MaxCostUsd    float64                `protobuf:"fixed64,3,opt,name=max_cost_usd,json=maxCostUsd,proto3" json:"max_cost_usd,omitempty"`    // Estimated judge spend allowed per run; 0 means unlimited
+	Weight        float64                `protobuf:"fixed64,4,opt,name=weight,proto3" json:"weight,omitempty"`                                // Share of the overall score taken by the judge score; defaults to 0.5
+	DisableCache  bool                   `protobuf:"varint,5,opt,name=disable_cache,json=disableCache,proto3" json:"disable_cache,omitempty"` // Always call the judge instead of reusing cached judgments
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JudgeConfig) Reset() {
+	*x = JudgeConfig{}
+	mi := &file_proto_gogent_proto_msgTypes[101]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JudgeConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JudgeConfig) ProtoMessage() {}
+
+func (x *JudgeConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[101]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JudgeConfig.ProtoReflect.Descriptor instead.
+func (*JudgeConfig) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{101}
+}
+
+func (x *JudgeConfig) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *JudgeConfig) GetCriteria() string {
+	if x != nil {
+		return x.Criteria
+	}
+	return ""
+}
+
+func (x *JudgeConfig) GetMaxCostUsd() float64 {
+	if x != nil {
+		return x.MaxCostUsd
+	}
+	return 0
+}
+
+func (x *JudgeConfig) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *JudgeConfig) GetDisableCache() bool {
+	if x != nil {
+		return x.DisableCache
+	}
+	return false
+}
+
+// Controls how tool usage is judged during comparison
+type ToolAppropriatenessConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ExpectToolUse *bool                  `protobuf:"varint,1,opt,name=expect_tool_use,json=expectToolUse,proto3,oneof" json:"expect_tool_use,omitempty"` // Explicit expectation; overrides keyword heuristics
+	ToolKeywords  []string               `protobuf:"bytes,2,rep,name=tool_keywords,json=toolKeywords,proto3" json:"tool_keywords,omitempty"`             // Prompt keywords that imply a tool is needed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ToolAppropriatenessConfig) Reset() {
+	*x = ToolAppropriatenessConfig{}
+	mi := &file_proto_gogent_proto_msgTypes[102]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ToolAppropriatenessConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ToolAppropriatenessConfig) ProtoMessage() {}
+
+func (x *ToolAppropriatenessConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gogent_proto_msgTypes[102]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ToolAppropriatenessConfig.ProtoReflect.Descriptor instead.
+func (*ToolAppropriatenessConfig) Descriptor() ([]byte, []int) {
+	return file_proto_gogent_proto_rawDescGZIP(), []int{102}
+}
+
+func (x *ToolAppropriatenessConfig) GetExpectToolUse() bool {
+	if x != nil && x.ExpectToolUse != nil {
+		return *x.ExpectToolUse
+	}
+	return false
+}
+
+func (x *ToolAppropriatenessConfig) GetToolKeywords() []string {
+	if x != nil {
+		return x.ToolKeywords
+	}
+	return nil
+}
+
 var File_proto_gogent_proto protoreflect.FileDescriptor
 
 const file_proto_gogent_proto_rawDesc = "" +
 	"\n" +
-	"\x12proto/gogent.proto\x12\x06gogent\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1cgoogle/protobuf/struct.proto\"\xc8\x02\n" +
+	"\x12proto/gogent.proto\x12\x06gogent\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1cgoogle/api/annotations.proto\"\xdc\x02\n" +
 	"\x04User\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
 	"\busername\x18\x02 \x01(\tR\busername\x12\x14\n" +
@@ -4118,29 +7502,33 @@ const file_proto_gogent_proto_rawDesc = "" +
 	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
 	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12>\n" +
-	"\rlast_login_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\vlastLoginAt\"F\n" +
+	"\rlast_login_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\vlastLoginAt\x12\x12\n" +
+	"\x04role\x18\t \x01(\tR\x04role\"F\n" +
 	"\fLoginRequest\x12\x1a\n" +
 	"\busername\x18\x01 \x01(\tR\busername\x12\x1a\n" +
-	"\bpassword\x18\x02 \x01(\tR\bpassword\"\x82\x01\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"\xa7\x01\n" +
 	"\rLoginResponse\x12\x14\n" +
 	"\x05token\x18\x01 \x01(\tR\x05token\x12 \n" +
 	"\x04user\x18\x02 \x01(\v2\f.gogent.UserR\x04user\x129\n" +
 	"\n" +
-	"expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"_\n" +
+	"expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12#\n" +
+	"\rrefresh_token\x18\x04 \x01(\tR\frefreshToken\"_\n" +
 	"\x0fRegisterRequest\x12\x1a\n" +
 	"\busername\x18\x01 \x01(\tR\busername\x12\x14\n" +
 	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1a\n" +
-	"\bpassword\x18\x03 \x01(\tR\bpassword\"J\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword\"o\n" +
 	"\x10RegisterResponse\x12 \n" +
 	"\x04user\x18\x01 \x01(\v2\f.gogent.UserR\x04user\x12\x14\n" +
-	"\x05token\x18\x02 \x01(\tR\x05token\";\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\x12#\n" +
+	"\rrefresh_token\x18\x03 \x01(\tR\frefreshToken\";\n" +
 	"\x1aCreateTemporaryUserRequest\x12\x1d\n" +
 	"\n" +
-	"session_id\x18\x01 \x01(\tR\tsessionId\"\x84\x01\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\"\xa9\x01\n" +
 	"\x1bCreateTemporaryUserResponse\x12 \n" +
 	"\x04user\x18\x01 \x01(\v2\f.gogent.UserR\x04user\x12-\n" +
 	"\x12temporary_password\x18\x02 \x01(\tR\x11temporaryPassword\x12\x14\n" +
-	"\x05token\x18\x03 \x01(\tR\x05token\"^\n" +
+	"\x05token\x18\x03 \x01(\tR\x05token\x12#\n" +
+	"\rrefresh_token\x18\x04 \x01(\tR\frefreshToken\"^\n" +
 	"\x1bSaveTemporaryAccountRequest\x12\x14\n" +
 	"\x05email\x18\x01 \x01(\tR\x05email\x12)\n" +
 	"\x10current_password\x18\x02 \x01(\tR\x0fcurrentPassword\"_\n" +
@@ -4152,10 +7540,33 @@ const file_proto_gogent_proto_rawDesc = "" +
 	"\x05token\x18\x01 \x01(\tR\x05token\"S\n" +
 	"\x13VerifyEmailResponse\x12 \n" +
 	"\x04user\x18\x01 \x01(\v2\f.gogent.UserR\x04user\x12\x1a\n" +
-	"\bverified\x18\x02 \x01(\bR\bverified\"\x17\n" +
+	"\bverified\x18\x02 \x01(\bR\bverified\":\n" +
+	"\x13RefreshTokenRequest\x12#\n" +
+	"\rrefresh_token\x18\x01 \x01(\tR\frefreshToken\"\xae\x01\n" +
+	"\x14RefreshTokenResponse\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12#\n" +
+	"\rrefresh_token\x18\x02 \x01(\tR\frefreshToken\x12 \n" +
+	"\x04user\x18\x03 \x01(\v2\f.gogent.UserR\x04user\x129\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"2\n" +
+	"\rLogoutRequest\x12!\n" +
+	"\fall_sessions\x18\x01 \x01(\bR\vallSessions\"*\n" +
+	"\x0eLogoutResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"3\n" +
+	"\x1bRequestPasswordResetRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\"R\n" +
+	"\x1cRequestPasswordResetResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"O\n" +
+	"\x14ResetPasswordRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12!\n" +
+	"\fnew_password\x18\x02 \x01(\tR\vnewPassword\"9\n" +
+	"\x15ResetPasswordResponse\x12 \n" +
+	"\x04user\x18\x01 \x01(\v2\f.gogent.UserR\x04user\"\x17\n" +
 	"\x15GetCurrentUserRequest\":\n" +
 	"\x16GetCurrentUserResponse\x12 \n" +
-	"\x04user\x18\x01 \x01(\v2\f.gogent.UserR\x04user\"\x9b\x06\n" +
+	"\x04user\x18\x01 \x01(\v2\f.gogent.UserR\x04user\"\xd3\n" +
+	"\n" +
 	"\x0eExecuteRequest\x12,\n" +
 	"\x12execution_run_name\x18\x01 \x01(\tR\x10executionRunName\x12 \n" +
 	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1f\n" +
@@ -4167,7 +7578,21 @@ const file_proto_gogent_proto_rawDesc = "" +
 	"\x0efunction_tools\x18\a \x03(\v2\f.gogent.ToolR\rfunctionTools\x12E\n" +
 	"\x11comparison_config\x18\b \x01(\v2\x18.gogent.ComparisonConfigR\x10comparisonConfig\x12\x19\n" +
 	"\buse_mock\x18\t \x01(\bR\auseMock\x12T\n" +
-	"\x10session_api_keys\x18\x0f \x03(\v2*.gogent.ExecuteRequest.SessionApiKeysEntryR\x0esessionApiKeys\x122\n" +
+	"\x10session_api_keys\x18\x0f \x03(\v2*.gogent.ExecuteRequest.SessionApiKeysEntryR\x0esessionApiKeys\x121\n" +
+	"\x14function_instruction\x18\x10 \x01(\tR\x13functionInstruction\x12@\n" +
+	"\x1cdisable_function_instruction\x18\x11 \x01(\bR\x1adisableFunctionInstruction\x129\n" +
+	"\rsafety_policy\x18\x12 \x01(\v2\x14.gogent.SafetyPolicyR\fsafetyPolicy\x12$\n" +
+	"\rdeterministic\x18\x13 \x01(\bR\rdeterministic\x12\x17\n" +
+	"\x04seed\x18\x14 \x01(\x05H\x00R\x04seed\x88\x01\x01\x12#\n" +
+	"\rname_template\x18\x15 \x01(\tR\fnameTemplate\x12\x14\n" +
+	"\x05suite\x18\x16 \x01(\tR\x05suite\x12\x17\n" +
+	"\agit_sha\x18\x17 \x01(\tR\x06gitSha\x12)\n" +
+	"\x10duplicate_policy\x18\x18 \x01(\tR\x0fduplicatePolicy\x12 \n" +
+	"\vrepetitions\x18\x19 \x01(\x05R\vrepetitions\x12?\n" +
+	"\x0fexpected_answer\x18\x1a \x01(\v2\x16.gogent.ExpectedAnswerR\x0eexpectedAnswer\x12,\n" +
+	"\x05sweep\x18\x1b \x01(\v2\x16.gogent.ParameterSweepR\x05sweep\x12\x1a\n" +
+	"\bpriority\x18\x1c \x01(\tR\bpriority\x12\x12\n" +
+	"\x04tags\x18\x1d \x03(\tR\x04tags\x122\n" +
 	"\x13openweather_api_key\x18\n" +
 	" \x01(\tB\x02\x18\x01R\x11openweatherApiKey\x12\x1f\n" +
 	"\tneo4j_url\x18\v \x01(\tB\x02\x18\x01R\bneo4jUrl\x12)\n" +
@@ -4176,38 +7601,188 @@ const file_proto_gogent_proto_rawDesc = "" +
 	"\x0eneo4j_database\x18\x0e \x01(\tB\x02\x18\x01R\rneo4jDatabase\x1aA\n" +
 	"\x13SessionApiKeysEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x89\x01\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\a\n" +
+	"\x05_seed\"\xa1\x01\n" +
 	"\x0fExecuteResponse\x12!\n" +
 	"\fexecution_id\x18\x01 \x01(\tR\vexecutionId\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x129\n" +
-	"\rexecution_run\x18\x03 \x01(\v2\x14.gogent.ExecutionRunR\fexecutionRun\">\n" +
+	"\rexecution_run\x18\x03 \x01(\v2\x14.gogent.ExecutionRunR\fexecutionRun\x12\x16\n" +
+	"\x06merged\x18\x04 \x01(\bR\x06merged\">\n" +
 	"\x19GetExecutionStatusRequest\x12!\n" +
-	"\fexecution_id\x18\x01 \x01(\tR\vexecutionId\"\xfc\x01\n" +
+	"\fexecution_id\x18\x01 \x01(\tR\vexecutionId\"\xa3\x02\n" +
 	"\x1aGetExecutionStatusResponse\x12\x16\n" +
 	"\x06status\x18\x01 \x01(\tR\x06status\x12#\n" +
 	"\rerror_message\x18\x02 \x01(\tR\ferrorMessage\x129\n" +
 	"\n" +
 	"start_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
 	"\bend_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12/\n" +
-	"\x06result\x18\x05 \x01(\v2\x17.gogent.ExecutionResultR\x06result\"E\n" +
+	"\x06result\x18\x05 \x01(\v2\x17.gogent.ExecutionResultR\x06result\x12%\n" +
+	"\x0equeue_position\x18\x06 \x01(\x05R\rqueuePosition\"E\n" +
 	"\x19GetExecutionResultRequest\x12(\n" +
 	"\x10execution_run_id\x18\x01 \x01(\tR\x0eexecutionRunId\"M\n" +
 	"\x1aGetExecutionResultResponse\x12/\n" +
-	"\x06result\x18\x01 \x01(\v2\x17.gogent.ExecutionResultR\x06result\"H\n" +
+	"\x06result\x18\x01 \x01(\v2\x17.gogent.ExecutionResultR\x06result\"}\n" +
 	"\x18ListExecutionRunsRequest\x12\x14\n" +
 	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x02 \x01(\x05R\x06offset\"y\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\x12\x1b\n" +
+	"\tall_users\x18\x03 \x01(\bR\ballUsers\x12\x16\n" +
+	"\x06cursor\x18\x04 \x01(\tR\x06cursor\"\x9a\x01\n" +
 	"\x19ListExecutionRunsResponse\x12;\n" +
 	"\x0eexecution_runs\x18\x01 \x03(\v2\x14.gogent.ExecutionRunR\rexecutionRuns\x12\x1f\n" +
 	"\vtotal_count\x18\x02 \x01(\x05R\n" +
-	"totalCount\"E\n" +
+	"totalCount\x12\x1f\n" +
+	"\vnext_cursor\x18\x03 \x01(\tR\n" +
+	"nextCursor\"^\n" +
+	"\x16ListComparisonsRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\"\x97\x01\n" +
+	"\x17ListComparisonsResponse\x12:\n" +
+	"\vcomparisons\x18\x01 \x03(\v2\x18.gogent.ComparisonResultR\vcomparisons\x12\x1f\n" +
+	"\vtotal_count\x18\x02 \x01(\x05R\n" +
+	"totalCount\x12\x1f\n" +
+	"\vnext_cursor\x18\x03 \x01(\tR\n" +
+	"nextCursor\"\xa2\x01\n" +
+	"\x18ListExecutionLogsRequest\x12(\n" +
+	"\x10execution_run_id\x18\x01 \x01(\tR\x0eexecutionRunId\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\tR\x05level\x12\x1a\n" +
+	"\bcategory\x18\x03 \x01(\tR\bcategory\x12\x14\n" +
+	"\x05after\x18\x04 \x01(\tR\x05after\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\"E\n" +
+	"\x19ListExecutionLogsResponse\x12(\n" +
+	"\x04logs\x18\x01 \x03(\v2\x14.gogent.ExecutionLogR\x04logs\"W\n" +
+	"\x15WatchExecutionRequest\x12!\n" +
+	"\fexecution_id\x18\x01 \x01(\tR\vexecutionId\x12\x1b\n" +
+	"\tlog_level\x18\x02 \x01(\tR\blogLevel\"\xce\x03\n" +
+	"\x0eExecutionEvent\x12!\n" +
+	"\fexecution_id\x18\x01 \x01(\tR\vexecutionId\x12(\n" +
+	"\x10execution_run_id\x18\x02 \x01(\tR\x0eexecutionRunId\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12%\n" +
+	"\x0equeue_position\x18\x04 \x01(\x05R\rqueuePosition\x12)\n" +
+	"\x10total_variations\x18\x05 \x01(\x05R\x0ftotalVariations\x121\n" +
+	"\x14completed_variations\x18\x06 \x01(\x05R\x13completedVariations\x12+\n" +
+	"\x11failed_variations\x18\a \x01(\x05R\x10failedVariations\x12%\n" +
+	"\x0elast_variation\x18\b \x01(\tR\rlastVariation\x12(\n" +
+	"\x04logs\x18\t \x03(\v2\x14.gogent.ExecutionLogR\x04logs\x12#\n" +
+	"\rerror_message\x18\n" +
+	" \x01(\tR\ferrorMessage\x12/\n" +
+	"\x06result\x18\v \x01(\v2\x17.gogent.ExecutionResultR\x06result\"@\n" +
+	"\x14GetComparisonRequest\x12(\n" +
+	"\x10execution_run_id\x18\x01 \x01(\tR\x0eexecutionRunId\"Q\n" +
+	"\x15GetComparisonResponse\x128\n" +
+	"\n" +
+	"comparison\x18\x01 \x01(\v2\x18.gogent.ComparisonResultR\n" +
+	"comparison\"E\n" +
 	"\x19DeleteExecutionRunRequest\x12(\n" +
 	"\x10execution_run_id\x18\x01 \x01(\tR\x0eexecutionRunId\"6\n" +
 	"\x1aDeleteExecutionRunResponse\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage\"\x1b\n" +
-	"\x19ListConfigurationsRequest\"^\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"\x99\x02\n" +
+	"\tBatchItem\x12\x1f\n" +
+	"\vexternal_id\x18\x01 \x01(\tR\n" +
+	"externalId\x12\x16\n" +
+	"\x06prompt\x18\x02 \x01(\tR\x06prompt\x12\x18\n" +
+	"\acontext\x18\x03 \x01(\tR\acontext\x12;\n" +
+	"\bmetadata\x18\x04 \x03(\v2\x1f.gogent.BatchItem.MetadataEntryR\bmetadata\x12?\n" +
+	"\x0fexpected_answer\x18\x05 \x01(\v2\x16.gogent.ExpectedAnswerR\x0eexpectedAnswer\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"e\n" +
+	"\x0eExpectedAnswer\x12\x16\n" +
+	"\x06answer\x18\x01 \x01(\tR\x06answer\x12\x1d\n" +
+	"\n" +
+	"match_mode\x18\x02 \x01(\tR\tmatchMode\x12\x1c\n" +
+	"\tthreshold\x18\x03 \x01(\x01R\tthreshold\"\xb3\x01\n" +
+	"\x15ConfigurationAccuracy\x12%\n" +
+	"\x0evariation_name\x18\x01 \x01(\tR\rvariationName\x12)\n" +
+	"\x10configuration_id\x18\x02 \x01(\tR\x0fconfigurationId\x12\x14\n" +
+	"\x05cases\x18\x03 \x01(\x05R\x05cases\x12\x16\n" +
+	"\x06passed\x18\x04 \x01(\x05R\x06passed\x12\x1a\n" +
+	"\baccuracy\x18\x05 \x01(\x01R\baccuracy\"\xda\x01\n" +
+	"\x0eParameterSweep\x12\x12\n" +
+	"\x04mode\x18\x01 \x01(\tR\x04mode\x12\x18\n" +
+	"\asamples\x18\x02 \x01(\x05R\asamples\x12\x12\n" +
+	"\x04seed\x18\x03 \x01(\x03R\x04seed\x124\n" +
+	"\vtemperature\x18\x04 \x01(\v2\x12.gogent.SweepRangeR\vtemperature\x12'\n" +
+	"\x05top_p\x18\x05 \x01(\v2\x12.gogent.SweepRangeR\x04topP\x12'\n" +
+	"\x05top_k\x18\x06 \x01(\v2\x12.gogent.SweepRangeR\x04topK\"\\\n" +
+	"\n" +
+	"SweepRange\x12\x10\n" +
+	"\x03min\x18\x01 \x01(\x01R\x03min\x12\x10\n" +
+	"\x03max\x18\x02 \x01(\x01R\x03max\x12\x12\n" +
+	"\x04step\x18\x03 \x01(\x01R\x04step\x12\x16\n" +
+	"\x06values\x18\x04 \x03(\x01R\x06values\"\x8b\x01\n" +
+	"\vSweepReport\x12\x16\n" +
+	"\x06metric\x18\x01 \x01(\tR\x06metric\x12*\n" +
+	"\x06points\x18\x02 \x03(\v2\x12.gogent.SweepPointR\x06points\x128\n" +
+	"\n" +
+	"parameters\x18\x03 \x03(\v2\x18.gogent.ParameterSummaryR\n" +
+	"parameters\"\xc8\x01\n" +
+	"\n" +
+	"SweepPoint\x12%\n" +
+	"\x0evariation_name\x18\x01 \x01(\tR\rvariationName\x12%\n" +
+	"\vtemperature\x18\x02 \x01(\x02H\x00R\vtemperature\x88\x01\x01\x12\x18\n" +
+	"\x05top_p\x18\x03 \x01(\x02H\x01R\x04topP\x88\x01\x01\x12\x18\n" +
+	"\x05top_k\x18\x04 \x01(\x05H\x02R\x04topK\x88\x01\x01\x12\x14\n" +
+	"\x05score\x18\x05 \x01(\x01R\x05scoreB\x0e\n" +
+	"\f_temperatureB\b\n" +
+	"\x06_top_pB\b\n" +
+	"\x06_top_k\"\xbe\x01\n" +
+	"\x10ParameterSummary\x12\x1c\n" +
+	"\tparameter\x18\x01 \x01(\tR\tparameter\x12/\n" +
+	"\x06values\x18\x02 \x03(\v2\x17.gogent.SweepValueScoreR\x06values\x12\x1d\n" +
+	"\n" +
+	"best_value\x18\x03 \x01(\x01R\tbestValue\x12\x1d\n" +
+	"\n" +
+	"region_min\x18\x04 \x01(\x01R\tregionMin\x12\x1d\n" +
+	"\n" +
+	"region_max\x18\x05 \x01(\x01R\tregionMax\"^\n" +
+	"\x0fSweepValueScore\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\x01R\x05value\x12\x1d\n" +
+	"\n" +
+	"mean_score\x18\x02 \x01(\x01R\tmeanScore\x12\x16\n" +
+	"\x06points\x18\x03 \x01(\x05R\x06points\"\x85\x01\n" +
+	"\x12SubmitBatchRequest\x122\n" +
+	"\btemplate\x18\x01 \x01(\v2\x16.gogent.ExecuteRequestR\btemplate\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12'\n" +
+	"\x05items\x18\x03 \x03(\v2\x11.gogent.BatchItemR\x05items\"\xaf\x01\n" +
+	"\x0eSubmitBatchAck\x12\x19\n" +
+	"\bbatch_id\x18\x01 \x01(\tR\abatchId\x12-\n" +
+	"\x12acknowledged_items\x18\x02 \x01(\x05R\x11acknowledgedItems\x12\x1f\n" +
+	"\vchunk_items\x18\x03 \x01(\x05R\n" +
+	"chunkItems\x12\x1a\n" +
+	"\bcomplete\x18\x04 \x01(\bR\bcomplete\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\"\xa2\x03\n" +
+	"\bBatchRun\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x1f\n" +
+	"\vtotal_items\x18\x05 \x01(\x05R\n" +
+	"totalItems\x12'\n" +
+	"\x0fcompleted_items\x18\x06 \x01(\x05R\x0ecompletedItems\x12!\n" +
+	"\ffailed_items\x18\a \x01(\x05R\vfailedItems\x12#\n" +
+	"\rerror_message\x18\b \x01(\tR\ferrorMessage\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x129\n" +
+	"\baccuracy\x18\v \x03(\v2\x1d.gogent.ConfigurationAccuracyR\baccuracy\"$\n" +
+	"\x12GetBatchRunRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"D\n" +
+	"\x13GetBatchRunResponse\x12-\n" +
+	"\tbatch_run\x18\x01 \x01(\v2\x10.gogent.BatchRunR\bbatchRun\"\x88\x01\n" +
+	"\x19ListConfigurationsRequest\x12%\n" +
+	"\x0einclude_system\x18\x01 \x01(\bR\rincludeSystem\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\x12\x16\n" +
+	"\x06cursor\x18\x04 \x01(\tR\x06cursor\"\xa0\x01\n" +
 	"\x1aListConfigurationsResponse\x12@\n" +
-	"\x0econfigurations\x18\x01 \x03(\v2\x18.gogent.APIConfigurationR\x0econfigurations\"\\\n" +
+	"\x0econfigurations\x18\x01 \x03(\v2\x18.gogent.APIConfigurationR\x0econfigurations\x12\x1f\n" +
+	"\vtotal_count\x18\x02 \x01(\x05R\n" +
+	"totalCount\x12\x1f\n" +
+	"\vnext_cursor\x18\x03 \x01(\tR\n" +
+	"nextCursor\"\\\n" +
 	"\x1aCreateConfigurationRequest\x12>\n" +
 	"\rconfiguration\x18\x01 \x01(\v2\x18.gogent.APIConfigurationR\rconfiguration\"]\n" +
 	"\x1bCreateConfigurationResponse\x12>\n" +
@@ -4220,10 +7795,17 @@ const file_proto_gogent_proto_rawDesc = "" +
 	"\x1aDeleteConfigurationRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"7\n" +
 	"\x1bDeleteConfigurationResponse\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage\"\x16\n" +
-	"\x14ListFunctionsRequest\"Q\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"\\\n" +
+	"\x14ListFunctionsRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\"\x93\x01\n" +
 	"\x15ListFunctionsResponse\x128\n" +
-	"\tfunctions\x18\x01 \x03(\v2\x1a.gogent.FunctionDefinitionR\tfunctions\"$\n" +
+	"\tfunctions\x18\x01 \x03(\v2\x1a.gogent.FunctionDefinitionR\tfunctions\x12\x1f\n" +
+	"\vtotal_count\x18\x02 \x01(\x05R\n" +
+	"totalCount\x12\x1f\n" +
+	"\vnext_cursor\x18\x03 \x01(\tR\n" +
+	"nextCursor\"$\n" +
 	"\x12GetFunctionRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"M\n" +
 	"\x13GetFunctionResponse\x126\n" +
@@ -4247,44 +7829,66 @@ const file_proto_gogent_proto_rawDesc = "" +
 	"\targuments\x18\x02 \x01(\v2\x17.google.protobuf.StructR\targuments\x12\"\n" +
 	"\ruse_mock_data\x18\x03 \x01(\bR\vuseMockData\x12\x1d\n" +
 	"\n" +
-	"timeout_ms\x18\x04 \x01(\x05R\ttimeoutMs\"\xdc\x01\n" +
+	"timeout_ms\x18\x04 \x01(\x05R\ttimeoutMs\"\x86\x02\n" +
 	"\x14TestFunctionResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12$\n" +
 	"\x0eused_mock_data\x18\x02 \x01(\bR\fusedMockData\x12*\n" +
 	"\x11execution_time_ms\x18\x03 \x01(\x05R\x0fexecutionTimeMs\x123\n" +
 	"\bresponse\x18\x04 \x01(\v2\x17.google.protobuf.StructR\bresponse\x12#\n" +
-	"\rerror_message\x18\x05 \x01(\tR\ferrorMessage\"\x19\n" +
-	"\x17GetDatabaseStatsRequest\"\xab\x02\n" +
+	"\rerror_message\x18\x05 \x01(\tR\ferrorMessage\x12(\n" +
+	"\x10function_call_id\x18\x06 \x01(\tR\x0efunctionCallId\"6\n" +
+	"\x17GetDatabaseStatsRequest\x12\x1b\n" +
+	"\tall_users\x18\x01 \x01(\bR\ballUsers\"\xb0\x03\n" +
 	"\x18GetDatabaseStatsResponse\x120\n" +
 	"\x14total_execution_runs\x18\x01 \x01(\x05R\x12totalExecutionRuns\x12,\n" +
 	"\x12total_api_requests\x18\x02 \x01(\x05R\x10totalApiRequests\x12.\n" +
 	"\x13total_api_responses\x18\x03 \x01(\x05R\x11totalApiResponses\x120\n" +
 	"\x14total_function_calls\x18\x04 \x01(\x05R\x12totalFunctionCalls\x12*\n" +
 	"\x11avg_response_time\x18\x05 \x01(\x01R\x0favgResponseTime\x12!\n" +
-	"\fsuccess_rate\x18\x06 \x01(\x01R\vsuccessRate\"\x1b\n" +
+	"\fsuccess_rate\x18\x06 \x01(\x01R\vsuccessRate\x12!\n" +
+	"\ftotal_tokens\x18\a \x01(\x03R\vtotalTokens\x12!\n" +
+	"\fstats_source\x18\b \x01(\tR\vstatsSource\x12=\n" +
+	"\frefreshed_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\vrefreshedAt\"\x1b\n" +
 	"\x19ListDatabaseTablesRequest\"4\n" +
 	"\x1aListDatabaseTablesResponse\x12\x16\n" +
-	"\x06tables\x18\x01 \x03(\tR\x06tables\"b\n" +
+	"\x06tables\x18\x01 \x03(\tR\x06tables\"\xdf\x01\n" +
 	"\x13GetTableDataRequest\x12\x1d\n" +
 	"\n" +
 	"table_name\x18\x01 \x01(\tR\ttableName\x12\x14\n" +
 	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x03 \x01(\x05R\x06offset\"\x9e\x01\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\x12\x1b\n" +
+	"\tall_users\x18\x04 \x01(\bR\ballUsers\x12\x16\n" +
+	"\x06cursor\x18\x05 \x01(\tR\x06cursor\x12\x18\n" +
+	"\acolumns\x18\x06 \x03(\tR\acolumns\x12\x18\n" +
+	"\afilters\x18\a \x03(\tR\afilters\x12\x12\n" +
+	"\x04sort\x18\b \x01(\tR\x04sort\"\xbf\x01\n" +
 	"\x14GetTableDataResponse\x12\x1d\n" +
 	"\n" +
 	"table_name\x18\x01 \x01(\tR\ttableName\x12\x18\n" +
 	"\acolumns\x18\x02 \x03(\tR\acolumns\x12.\n" +
 	"\x04rows\x18\x03 \x03(\v2\x1a.google.protobuf.ListValueR\x04rows\x12\x1d\n" +
 	"\n" +
-	"total_rows\x18\x04 \x01(\x05R\ttotalRows\"\x0f\n" +
-	"\rHealthRequest\"\xb7\x01\n" +
+	"total_rows\x18\x04 \x01(\x05R\ttotalRows\x12\x1f\n" +
+	"\vnext_cursor\x18\x05 \x01(\tR\n" +
+	"nextCursor\"\x0f\n" +
+	"\rHealthRequest\"\xe4\x01\n" +
 	"\x0eHealthResponse\x12\x16\n" +
 	"\x06status\x18\x01 \x01(\tR\x06status\x12\x18\n" +
 	"\aversion\x18\x02 \x01(\tR\aversion\x128\n" +
 	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x1a\n" +
 	"\bdatabase\x18\x04 \x01(\bR\bdatabase\x12\x1d\n" +
 	"\n" +
-	"gemini_api\x18\x05 \x01(\bR\tgeminiApi\"\xd8\x02\n" +
+	"gemini_api\x18\x05 \x01(\bR\tgeminiApi\x12+\n" +
+	"\x06checks\x18\x06 \x03(\v2\x13.gogent.HealthCheckR\x06checks\"\xe0\x01\n" +
+	"\vHealthCheck\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"latency_ms\x18\x03 \x01(\x03R\tlatencyMs\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\x121\n" +
+	"\adetails\x18\x05 \x01(\v2\x17.google.protobuf.StructR\adetails\x129\n" +
+	"\n" +
+	"checked_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcheckedAt\"\xe6\x03\n" +
 	"\fExecutionRun\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x12\n" +
@@ -4296,7 +7900,12 @@ const file_proto_gogent_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xc3\x04\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12$\n" +
+	"\rdeterministic\x18\n" +
+	" \x01(\bR\rdeterministic\x127\n" +
+	"\x17determinism_fingerprint\x18\v \x01(\tR\x16determinismFingerprint\x12\x19\n" +
+	"\brun_spec\x18\f \x01(\tR\arunSpec\x12\x12\n" +
+	"\x04tags\x18\r \x03(\tR\x04tags\"\x82\v\n" +
 	"\x10APIConfiguration\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12(\n" +
 	"\x10execution_run_id\x18\x02 \x01(\tR\x0eexecutionRunId\x12%\n" +
@@ -4316,13 +7925,76 @@ const file_proto_gogent_proto_rawDesc = "" +
 	"\vtool_config\x18\r \x01(\v2\x17.google.protobuf.StructR\n" +
 	"toolConfig\x129\n" +
 	"\n" +
-	"created_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"u\n" +
+	"created_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"tool_names\x18\x0f \x03(\tR\ttoolNames\x12#\n" +
+	"\rdisable_tools\x18\x10 \x01(\bR\fdisableTools\x121\n" +
+	"\x14function_instruction\x18\x11 \x01(\tR\x13functionInstruction\x12@\n" +
+	"\x1cdisable_function_instruction\x18\x12 \x01(\bR\x1adisableFunctionInstruction\x129\n" +
+	"\rsafety_policy\x18\x13 \x01(\v2\x14.gogent.SafetyPolicyR\fsafetyPolicy\x12,\n" +
+	"\x12response_mime_type\x18\x14 \x01(\tR\x10responseMimeType\x12@\n" +
+	"\x0fresponse_schema\x18\x15 \x01(\v2\x17.google.protobuf.StructR\x0eresponseSchema\x120\n" +
+	"\x14inline_system_prompt\x18\x16 \x01(\bR\x12inlineSystemPrompt\x12\x1b\n" +
+	"\tpreset_id\x18\x17 \x01(\tR\bpresetId\x12\x1d\n" +
+	"\n" +
+	"timeout_ms\x18\x18 \x01(\x05R\ttimeoutMs\x12\x18\n" +
+	"\abackend\x18\x19 \x01(\tR\abackend\x12\x1a\n" +
+	"\bprovider\x18\x1a \x01(\tR\bprovider\x125\n" +
+	"\tretrieval\x18\x1b \x01(\v2\x17.gogent.RetrievalConfigR\tretrieval\x123\n" +
+	"\n" +
+	"guardrails\x18\x1c \x03(\v2\x13.gogent.GuardConfigR\n" +
+	"guardrails\x12(\n" +
+	"\x10tool_choice_mode\x18\x1d \x01(\tR\x0etoolChoiceMode\x124\n" +
+	"\x16allowed_function_names\x18\x1e \x03(\tR\x14allowedFunctionNames\x12,\n" +
+	"\x0fthinking_budget\x18\x1f \x01(\x05H\x00R\x0ethinkingBudget\x88\x01\x01\x12'\n" +
+	"\x0fcandidate_count\x18  \x01(\x05R\x0ecandidateCountB\x12\n" +
+	"\x10_thinking_budget\"i\n" +
+	"\vGuardConfig\x12\x14\n" +
+	"\x05guard\x18\x01 \x01(\tR\x05guard\x12\x14\n" +
+	"\x05stage\x18\x02 \x01(\tR\x05stage\x12\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x12\x16\n" +
+	"\x06topics\x18\x04 \x03(\tR\x06topics\"\x8c\x01\n" +
+	"\fGuardVerdict\x12\x14\n" +
+	"\x05guard\x18\x01 \x01(\tR\x05guard\x12\x14\n" +
+	"\x05stage\x18\x02 \x01(\tR\x05stage\x12\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x12\x1c\n" +
+	"\ttriggered\x18\x04 \x01(\bR\ttriggered\x12\x1a\n" +
+	"\bfindings\x18\x05 \x03(\tR\bfindings\"\x8c\x01\n" +
+	"\x0fRetrievalConfig\x12\x1e\n" +
+	"\n" +
+	"collection\x18\x01 \x01(\tR\n" +
+	"collection\x12\x13\n" +
+	"\x05top_k\x18\x02 \x01(\x05R\x04topK\x12\x1b\n" +
+	"\tmin_score\x18\x03 \x01(\x01R\bminScore\x12'\n" +
+	"\x0fembedding_model\x18\x04 \x01(\tR\x0eembeddingModel\"\xf6\x01\n" +
+	"\x0eRetrievedChunk\x12\x19\n" +
+	"\bchunk_id\x18\x01 \x01(\tR\achunkId\x12\x1f\n" +
+	"\vdocument_id\x18\x02 \x01(\tR\n" +
+	"documentId\x12#\n" +
+	"\rdocument_name\x18\x03 \x01(\tR\fdocumentName\x12\x1e\n" +
+	"\n" +
+	"collection\x18\x04 \x01(\tR\n" +
+	"collection\x12\x1f\n" +
+	"\vchunk_index\x18\x05 \x01(\x05R\n" +
+	"chunkIndex\x12\x18\n" +
+	"\acontent\x18\x06 \x01(\tR\acontent\x12\x14\n" +
+	"\x05score\x18\a \x01(\x01R\x05score\x12\x12\n" +
+	"\x04rank\x18\b \x01(\x05R\x04rank\"\x93\x01\n" +
+	"\fSafetyPolicy\x12D\n" +
+	"\n" +
+	"thresholds\x18\x01 \x03(\v2$.gogent.SafetyPolicy.ThresholdsEntryR\n" +
+	"thresholds\x1a=\n" +
+	"\x0fThresholdsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xdf\x01\n" +
 	"\x04Tool\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
 	"\vdescription\x18\x02 \x01(\tR\vdescription\x127\n" +
 	"\n" +
 	"parameters\x18\x03 \x01(\v2\x17.google.protobuf.StructR\n" +
-	"parameters\"\xd1\x05\n" +
+	"parameters\x12<\n" +
+	"\rmock_response\x18\x04 \x01(\v2\x17.google.protobuf.StructR\fmockResponse\x12*\n" +
+	"\x11use_mock_response\x18\x05 \x01(\bR\x0fuseMockResponse\"\xc7\x06\n" +
 	"\x12FunctionDefinition\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x12\n" +
@@ -4344,7 +8016,11 @@ const file_proto_gogent_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xee\x03\n" +
+	"updated_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1d\n" +
+	"\n" +
+	"timeout_ms\x18\x11 \x01(\x05R\ttimeoutMs\x12,\n" +
+	"\x12max_response_bytes\x18\x12 \x01(\x05R\x10maxResponseBytes\x12'\n" +
+	"\x0fallowed_domains\x18\x13 \x03(\tR\x0eallowedDomains\"\x9c\x04\n" +
 	"\n" +
 	"APIRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12(\n" +
@@ -4359,7 +8035,8 @@ const file_proto_gogent_proto_rawDesc = "" +
 	"\frequest_body\x18\n" +
 	" \x01(\v2\x17.google.protobuf.StructR\vrequestBody\x129\n" +
 	"\n" +
-	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x8a\x05\n" +
+	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12,\n" +
+	"\x12system_prompt_mode\x18\f \x01(\tR\x10systemPromptMode\"\x90\x06\n" +
 	"\vAPIResponse\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
 	"\n" +
@@ -4376,7 +8053,21 @@ const file_proto_gogent_proto_rawDesc = "" +
 	"\x10response_headers\x18\v \x01(\v2\x17.google.protobuf.StructR\x0fresponseHeaders\x12<\n" +
 	"\rresponse_body\x18\f \x01(\v2\x17.google.protobuf.StructR\fresponseBody\x129\n" +
 	"\n" +
-	"created_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xa7\x03\n" +
+	"created_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x122\n" +
+	"\alatency\x18\x0e \x01(\v2\x18.gogent.LatencyBreakdownR\alatency\x12'\n" +
+	"\x0fthought_summary\x18\x0f \x01(\tR\x0ethoughtSummary\x12'\n" +
+	"\x0fcandidate_index\x18\x10 \x01(\x05R\x0ecandidateIndex\"\xfb\x01\n" +
+	"\x10LatencyBreakdown\x12\x1b\n" +
+	"\tqueued_ms\x18\x01 \x01(\x03R\bqueuedMs\x12!\n" +
+	"\fretrieval_ms\x18\x02 \x01(\x03R\vretrievalMs\x12\x19\n" +
+	"\bmodel_ms\x18\x03 \x01(\x03R\amodelMs\x12\x1f\n" +
+	"\vfunction_ms\x18\x04 \x01(\x03R\n" +
+	"functionMs\x12 \n" +
+	"\ffollow_up_ms\x18\x05 \x01(\x03R\n" +
+	"followUpMs\x12\x13\n" +
+	"\x05db_ms\x18\x06 \x01(\x03R\x04dbMs\x12\x19\n" +
+	"\bother_ms\x18\a \x01(\x03R\aotherMs\x12\x19\n" +
+	"\btotal_ms\x18\b \x01(\x03R\atotalMs\"\xa7\x03\n" +
 	"\fFunctionCall\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
 	"\n" +
@@ -4388,7 +8079,7 @@ const file_proto_gogent_proto_rawDesc = "" +
 	"\x11execution_time_ms\x18\a \x01(\x05R\x0fexecutionTimeMs\x12#\n" +
 	"\rerror_details\x18\b \x01(\tR\ferrorDetails\x129\n" +
 	"\n" +
-	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xc8\x02\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xbb\x03\n" +
 	"\x0fExecutionResult\x129\n" +
 	"\rexecution_run\x18\x01 \x01(\v2\x14.gogent.ExecutionRunR\fexecutionRun\x121\n" +
 	"\aresults\x18\x02 \x03(\v2\x17.gogent.VariationResultR\aresults\x128\n" +
@@ -4400,13 +8091,20 @@ const file_proto_gogent_proto_rawDesc = "" +
 	"\rsuccess_count\x18\x05 \x01(\x05R\fsuccessCount\x12\x1f\n" +
 	"\verror_count\x18\x06 \x01(\x05R\n" +
 	"errorCount\x12(\n" +
-	"\x04logs\x18\a \x03(\v2\x14.gogent.ExecutionLogR\x04logs\"\x94\x02\n" +
+	"\x04logs\x18\a \x03(\v2\x14.gogent.ExecutionLogR\x04logs\x129\n" +
+	"\baccuracy\x18\b \x03(\v2\x1d.gogent.ConfigurationAccuracyR\baccuracy\x126\n" +
+	"\fsweep_report\x18\t \x01(\v2\x13.gogent.SweepReportR\vsweepReport\"\xb4\x03\n" +
 	"\x0fVariationResult\x12>\n" +
 	"\rconfiguration\x18\x01 \x01(\v2\x18.gogent.APIConfigurationR\rconfiguration\x12,\n" +
 	"\arequest\x18\x02 \x01(\v2\x12.gogent.APIRequestR\arequest\x12/\n" +
 	"\bresponse\x18\x03 \x01(\v2\x13.gogent.APIResponseR\bresponse\x12;\n" +
 	"\x0efunction_calls\x18\x04 \x03(\v2\x14.gogent.FunctionCallR\rfunctionCalls\x12%\n" +
-	"\x0eexecution_time\x18\x05 \x01(\x03R\rexecutionTime\"\x8a\x04\n" +
+	"\x0eexecution_time\x18\x05 \x01(\x03R\rexecutionTime\x12\x1e\n" +
+	"\n" +
+	"repetition\x18\x06 \x01(\x05R\n" +
+	"repetition\x12A\n" +
+	"\x10retrieved_chunks\x18\a \x03(\v2\x16.gogent.RetrievedChunkR\x0fretrievedChunks\x12;\n" +
+	"\x0eguard_verdicts\x18\b \x03(\v2\x14.gogent.GuardVerdictR\rguardVerdicts\"\xd3\x04\n" +
 	"\x10ComparisonResult\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12(\n" +
 	"\x10execution_run_id\x18\x02 \x01(\tR\x0eexecutionRunId\x12'\n" +
@@ -4420,7 +8118,18 @@ const file_proto_gogent_proto_rawDesc = "" +
 	"\x0eanalysis_notes\x18\t \x01(\tR\ranalysisNotes\x129\n" +
 	"\n" +
 	"created_at\x18\n" +
-	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xd9\x02\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12G\n" +
+	"\x12significance_tests\x18\v \x03(\v2\x18.gogent.SignificanceTestR\x11significanceTests\"\xaf\x02\n" +
+	"\x10SignificanceTest\x12\x16\n" +
+	"\x06metric\x18\x01 \x01(\tR\x06metric\x12'\n" +
+	"\x0fconfiguration_a\x18\x02 \x01(\tR\x0econfigurationA\x12'\n" +
+	"\x0fconfiguration_b\x18\x03 \x01(\tR\x0econfigurationB\x12'\n" +
+	"\x0fmean_difference\x18\x04 \x01(\x01R\x0emeanDifference\x12\x1f\n" +
+	"\vt_statistic\x18\x05 \x01(\x01R\n" +
+	"tStatistic\x12,\n" +
+	"\x12degrees_of_freedom\x18\x06 \x01(\x01R\x10degreesOfFreedom\x12\x17\n" +
+	"\ap_value\x18\a \x01(\x01R\x06pValue\x12 \n" +
+	"\vsignificant\x18\b \x01(\bR\vsignificant\"\xd9\x02\n" +
 	"\fExecutionLog\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12(\n" +
 	"\x10execution_run_id\x18\x02 \x01(\tR\x0eexecutionRunId\x12)\n" +
@@ -4431,37 +8140,60 @@ const file_proto_gogent_proto_rawDesc = "" +
 	"\flog_category\x18\x06 \x01(\tR\vlogCategory\x12\x18\n" +
 	"\amessage\x18\a \x01(\tR\amessage\x121\n" +
 	"\adetails\x18\b \x01(\v2\x17.google.protobuf.StructR\adetails\x128\n" +
-	"\ttimestamp\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"i\n" +
+	"\ttimestamp\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\xea\x01\n" +
 	"\x10ComparisonConfig\x12\x18\n" +
 	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x18\n" +
 	"\ametrics\x18\x02 \x03(\tR\ametrics\x12!\n" +
-	"\fcustom_rules\x18\x03 \x03(\tR\vcustomRules2\x96\x10\n" +
-	"\rGogentService\x124\n" +
-	"\x05Login\x12\x14.gogent.LoginRequest\x1a\x15.gogent.LoginResponse\x12=\n" +
-	"\bRegister\x12\x17.gogent.RegisterRequest\x1a\x18.gogent.RegisterResponse\x12^\n" +
-	"\x13CreateTemporaryUser\x12\".gogent.CreateTemporaryUserRequest\x1a#.gogent.CreateTemporaryUserResponse\x12a\n" +
-	"\x14SaveTemporaryAccount\x12#.gogent.SaveTemporaryAccountRequest\x1a$.gogent.SaveTemporaryAccountResponse\x12F\n" +
-	"\vVerifyEmail\x12\x1a.gogent.VerifyEmailRequest\x1a\x1b.gogent.VerifyEmailResponse\x12O\n" +
-	"\x0eGetCurrentUser\x12\x1d.gogent.GetCurrentUserRequest\x1a\x1e.gogent.GetCurrentUserResponse\x12:\n" +
-	"\aExecute\x12\x16.gogent.ExecuteRequest\x1a\x17.gogent.ExecuteResponse\x12[\n" +
-	"\x12GetExecutionStatus\x12!.gogent.GetExecutionStatusRequest\x1a\".gogent.GetExecutionStatusResponse\x12[\n" +
-	"\x12GetExecutionResult\x12!.gogent.GetExecutionResultRequest\x1a\".gogent.GetExecutionResultResponse\x12X\n" +
-	"\x11ListExecutionRuns\x12 .gogent.ListExecutionRunsRequest\x1a!.gogent.ListExecutionRunsResponse\x12[\n" +
-	"\x12DeleteExecutionRun\x12!.gogent.DeleteExecutionRunRequest\x1a\".gogent.DeleteExecutionRunResponse\x12[\n" +
-	"\x12ListConfigurations\x12!.gogent.ListConfigurationsRequest\x1a\".gogent.ListConfigurationsResponse\x12^\n" +
-	"\x13CreateConfiguration\x12\".gogent.CreateConfigurationRequest\x1a#.gogent.CreateConfigurationResponse\x12^\n" +
-	"\x13UpdateConfiguration\x12\".gogent.UpdateConfigurationRequest\x1a#.gogent.UpdateConfigurationResponse\x12^\n" +
-	"\x13DeleteConfiguration\x12\".gogent.DeleteConfigurationRequest\x1a#.gogent.DeleteConfigurationResponse\x12L\n" +
-	"\rListFunctions\x12\x1c.gogent.ListFunctionsRequest\x1a\x1d.gogent.ListFunctionsResponse\x12F\n" +
-	"\vGetFunction\x12\x1a.gogent.GetFunctionRequest\x1a\x1b.gogent.GetFunctionResponse\x12O\n" +
-	"\x0eCreateFunction\x12\x1d.gogent.CreateFunctionRequest\x1a\x1e.gogent.CreateFunctionResponse\x12O\n" +
-	"\x0eUpdateFunction\x12\x1d.gogent.UpdateFunctionRequest\x1a\x1e.gogent.UpdateFunctionResponse\x12O\n" +
-	"\x0eDeleteFunction\x12\x1d.gogent.DeleteFunctionRequest\x1a\x1e.gogent.DeleteFunctionResponse\x12I\n" +
-	"\fTestFunction\x12\x1b.gogent.TestFunctionRequest\x1a\x1c.gogent.TestFunctionResponse\x12U\n" +
-	"\x10GetDatabaseStats\x12\x1f.gogent.GetDatabaseStatsRequest\x1a .gogent.GetDatabaseStatsResponse\x12[\n" +
-	"\x12ListDatabaseTables\x12!.gogent.ListDatabaseTablesRequest\x1a\".gogent.ListDatabaseTablesResponse\x12I\n" +
-	"\fGetTableData\x12\x1b.gogent.GetTableDataRequest\x1a\x1c.gogent.GetTableDataResponse\x127\n" +
-	"\x06Health\x12\x15.gogent.HealthRequest\x1a\x16.gogent.HealthResponseB\x0eZ\fgogent/protob\x06proto3"
+	"\fcustom_rules\x18\x03 \x03(\tR\vcustomRules\x12T\n" +
+	"\x14tool_appropriateness\x18\x04 \x01(\v2!.gogent.ToolAppropriatenessConfigR\x13toolAppropriateness\x12)\n" +
+	"\x05judge\x18\x05 \x01(\v2\x13.gogent.JudgeConfigR\x05judge\"\x9e\x01\n" +
+	"\vJudgeConfig\x12\x14\n" +
+	"\x05model\x18\x01 \x01(\tR\x05model\x12\x1a\n" +
+	"\bcriteria\x18\x02 \x01(\tR\bcriteria\x12 \n" +
+	"\fmax_cost_usd\x18\x03 \x01(\x01R\n" +
+	"maxCostUsd\x12\x16\n" +
+	"\x06weight\x18\x04 \x01(\x01R\x06weight\x12#\n" +
+	"\rdisable_cache\x18\x05 \x01(\bR\fdisableCache\"\x81\x01\n" +
+	"\x19ToolAppropriatenessConfig\x12+\n" +
+	"\x0fexpect_tool_use\x18\x01 \x01(\bH\x00R\rexpectToolUse\x88\x01\x01\x12#\n" +
+	"\rtool_keywords\x18\x02 \x03(\tR\ftoolKeywordsB\x12\n" +
+	"\x10_expect_tool_use2\xc3\x1f\n" +
+	"\rGogentService\x12P\n" +
+	"\x05Login\x12\x14.gogent.LoginRequest\x1a\x15.gogent.LoginResponse\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/api/auth/login\x12\\\n" +
+	"\bRegister\x12\x17.gogent.RegisterRequest\x1a\x18.gogent.RegisterResponse\"\x1d\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/api/auth/register\x12~\n" +
+	"\x13CreateTemporaryUser\x12\".gogent.CreateTemporaryUserRequest\x1a#.gogent.CreateTemporaryUserResponse\"\x1e\x82\xd3\xe4\x93\x02\x18:\x01*\"\x13/api/auth/temp-user\x12\x81\x01\n" +
+	"\x14SaveTemporaryAccount\x12#.gogent.SaveTemporaryAccountRequest\x1a$.gogent.SaveTemporaryAccountResponse\"\x1e\x82\xd3\xe4\x93\x02\x18:\x01*\"\x13/api/auth/save-temp\x12i\n" +
+	"\vVerifyEmail\x12\x1a.gogent.VerifyEmailRequest\x1a\x1b.gogent.VerifyEmailResponse\"!\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/api/auth/verify-email\x12j\n" +
+	"\x0eGetCurrentUser\x12\x1d.gogent.GetCurrentUserRequest\x1a\x1e.gogent.GetCurrentUserResponse\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/api/auth/current\x12g\n" +
+	"\fRefreshToken\x12\x1b.gogent.RefreshTokenRequest\x1a\x1c.gogent.RefreshTokenResponse\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/api/auth/refresh\x12T\n" +
+	"\x06Logout\x12\x15.gogent.LogoutRequest\x1a\x16.gogent.LogoutResponse\"\x1b\x82\xd3\xe4\x93\x02\x15:\x01*\"\x10/api/auth/logout\x12\x8e\x01\n" +
+	"\x14RequestPasswordReset\x12#.gogent.RequestPasswordResetRequest\x1a$.gogent.RequestPasswordResetResponse\"+\x82\xd3\xe4\x93\x02%:\x01*\" /api/auth/password-reset/request\x12y\n" +
+	"\rResetPassword\x12\x1c.gogent.ResetPasswordRequest\x1a\x1d.gogent.ResetPasswordResponse\"+\x82\xd3\xe4\x93\x02%:\x01*\" /api/auth/password-reset/confirm\x12S\n" +
+	"\aExecute\x12\x16.gogent.ExecuteRequest\x1a\x17.gogent.ExecuteResponse\"\x17\x82\xd3\xe4\x93\x02\x11:\x01*\"\f/api/execute\x12\x8e\x01\n" +
+	"\x12GetExecutionStatus\x12!.gogent.GetExecutionStatusRequest\x1a\".gogent.GetExecutionStatusResponse\"1\x82\xd3\xe4\x93\x02+\x12)/api/execution-runs/status/{execution_id}\x12\x8b\x01\n" +
+	"\x12GetExecutionResult\x12!.gogent.GetExecutionResultRequest\x1a\".gogent.GetExecutionResultResponse\".\x82\xd3\xe4\x93\x02(\x12&/api/execution-runs/{execution_run_id}\x12u\n" +
+	"\x11ListExecutionRuns\x12 .gogent.ListExecutionRunsRequest\x1a!.gogent.ListExecutionRunsResponse\"\x1b\x82\xd3\xe4\x93\x02\x15\x12\x13/api/execution-runs\x12\x8b\x01\n" +
+	"\x12DeleteExecutionRun\x12!.gogent.DeleteExecutionRunRequest\x1a\".gogent.DeleteExecutionRunResponse\".\x82\xd3\xe4\x93\x02(*&/api/execution-runs/{execution_run_id}\x12l\n" +
+	"\x0fListComparisons\x12\x1e.gogent.ListComparisonsRequest\x1a\x1f.gogent.ListComparisonsResponse\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/api/comparisons\x12\x87\x01\n" +
+	"\rGetComparison\x12\x1c.gogent.GetComparisonRequest\x1a\x1d.gogent.GetComparisonResponse\"9\x82\xd3\xe4\x93\x023\x121/api/execution-runs/{execution_run_id}/comparison\x12\x8d\x01\n" +
+	"\x11ListExecutionLogs\x12 .gogent.ListExecutionLogsRequest\x1a!.gogent.ListExecutionLogsResponse\"3\x82\xd3\xe4\x93\x02-\x12+/api/execution-runs/{execution_run_id}/logs\x12I\n" +
+	"\x0eWatchExecution\x12\x1d.gogent.WatchExecutionRequest\x1a\x16.gogent.ExecutionEvent0\x01\x12E\n" +
+	"\vSubmitBatch\x12\x1a.gogent.SubmitBatchRequest\x1a\x16.gogent.SubmitBatchAck(\x010\x01\x12a\n" +
+	"\vGetBatchRun\x12\x1a.gogent.GetBatchRunRequest\x1a\x1b.gogent.GetBatchRunResponse\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/api/batches/{id}\x12x\n" +
+	"\x12ListConfigurations\x12!.gogent.ListConfigurationsRequest\x1a\".gogent.ListConfigurationsResponse\"\x1b\x82\xd3\xe4\x93\x02\x15\x12\x13/api/configurations\x12\x8a\x01\n" +
+	"\x13CreateConfiguration\x12\".gogent.CreateConfigurationRequest\x1a#.gogent.CreateConfigurationResponse\"*\x82\xd3\xe4\x93\x02$:\rconfiguration\"\x13/api/configurations\x12\x8f\x01\n" +
+	"\x13UpdateConfiguration\x12\".gogent.UpdateConfigurationRequest\x1a#.gogent.UpdateConfigurationResponse\"/\x82\xd3\xe4\x93\x02):\rconfiguration\x1a\x18/api/configurations/{id}\x12\x80\x01\n" +
+	"\x13DeleteConfiguration\x12\".gogent.DeleteConfigurationRequest\x1a#.gogent.DeleteConfigurationResponse\" \x82\xd3\xe4\x93\x02\x1a*\x18/api/configurations/{id}\x12d\n" +
+	"\rListFunctions\x12\x1c.gogent.ListFunctionsRequest\x1a\x1d.gogent.ListFunctionsResponse\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/api/functions\x12c\n" +
+	"\vGetFunction\x12\x1a.gogent.GetFunctionRequest\x1a\x1b.gogent.GetFunctionResponse\"\x1b\x82\xd3\xe4\x93\x02\x15\x12\x13/api/functions/{id}\x12q\n" +
+	"\x0eCreateFunction\x12\x1d.gogent.CreateFunctionRequest\x1a\x1e.gogent.CreateFunctionResponse\" \x82\xd3\xe4\x93\x02\x1a:\bfunction\"\x0e/api/functions\x12v\n" +
+	"\x0eUpdateFunction\x12\x1d.gogent.UpdateFunctionRequest\x1a\x1e.gogent.UpdateFunctionResponse\"%\x82\xd3\xe4\x93\x02\x1f:\bfunction\x1a\x13/api/functions/{id}\x12l\n" +
+	"\x0eDeleteFunction\x12\x1d.gogent.DeleteFunctionRequest\x1a\x1e.gogent.DeleteFunctionResponse\"\x1b\x82\xd3\xe4\x93\x02\x15*\x13/api/functions/{id}\x12w\n" +
+	"\fTestFunction\x12\x1b.gogent.TestFunctionRequest\x1a\x1c.gogent.TestFunctionResponse\",\x82\xd3\xe4\x93\x02&:\x01*\"!/api/functions/test/{function_id}\x12r\n" +
+	"\x10GetDatabaseStats\x12\x1f.gogent.GetDatabaseStatsRequest\x1a .gogent.GetDatabaseStatsResponse\"\x1b\x82\xd3\xe4\x93\x02\x15\x12\x13/api/database/stats\x12y\n" +
+	"\x12ListDatabaseTables\x12!.gogent.ListDatabaseTablesRequest\x1a\".gogent.ListDatabaseTablesResponse\"\x1c\x82\xd3\xe4\x93\x02\x16\x12\x14/api/database/tables\x12t\n" +
+	"\fGetTableData\x12\x1b.gogent.GetTableDataRequest\x1a\x1c.gogent.GetTableDataResponse\")\x82\xd3\xe4\x93\x02#\x12!/api/database/tables/{table_name}\x12H\n" +
+	"\x06Health\x12\x15.gogent.HealthRequest\x1a\x16.gogent.HealthResponse\"\x0f\x82\xd3\xe4\x93\x02\t\x12\a/healthB\x0eZ\fgogent/protob\x06proto3"
 
 var (
 	file_proto_gogent_proto_rawDescOnce sync.Once
@@ -4475,7 +8207,7 @@ func file_proto_gogent_proto_rawDescGZIP() []byte {
 	return file_proto_gogent_proto_rawDescData
 }
 
-var file_proto_gogent_proto_msgTypes = make([]protoimpl.MessageInfo, 64)
+var file_proto_gogent_proto_msgTypes = make([]protoimpl.MessageInfo, 106)
 var file_proto_gogent_proto_goTypes = []any{
 	(*User)(nil),                         // 0: gogent.User
 	(*LoginRequest)(nil),                 // 1: gogent.LoginRequest
@@ -4488,196 +8220,301 @@ var file_proto_gogent_proto_goTypes = []any{
 	(*SaveTemporaryAccountResponse)(nil), // 8: gogent.SaveTemporaryAccountResponse
 	(*VerifyEmailRequest)(nil),           // 9: gogent.VerifyEmailRequest
 	(*VerifyEmailResponse)(nil),          // 10: gogent.VerifyEmailResponse
-	(*GetCurrentUserRequest)(nil),        // 11: gogent.GetCurrentUserRequest
-	(*GetCurrentUserResponse)(nil),       // 12: gogent.GetCurrentUserResponse
-	(*ExecuteRequest)(nil),               // 13: gogent.ExecuteRequest
-	(*ExecuteResponse)(nil),              // 14: gogent.ExecuteResponse
-	(*GetExecutionStatusRequest)(nil),    // 15: gogent.GetExecutionStatusRequest
-	(*GetExecutionStatusResponse)(nil),   // 16: gogent.GetExecutionStatusResponse
-	(*GetExecutionResultRequest)(nil),    // 17: gogent.GetExecutionResultRequest
-	(*GetExecutionResultResponse)(nil),   // 18: gogent.GetExecutionResultResponse
-	(*ListExecutionRunsRequest)(nil),     // 19: gogent.ListExecutionRunsRequest
-	(*ListExecutionRunsResponse)(nil),    // 20: gogent.ListExecutionRunsResponse
-	(*DeleteExecutionRunRequest)(nil),    // 21: gogent.DeleteExecutionRunRequest
-	(*DeleteExecutionRunResponse)(nil),   // 22: gogent.DeleteExecutionRunResponse
-	(*ListConfigurationsRequest)(nil),    // 23: gogent.ListConfigurationsRequest
-	(*ListConfigurationsResponse)(nil),   // 24: gogent.ListConfigurationsResponse
-	(*CreateConfigurationRequest)(nil),   // 25: gogent.CreateConfigurationRequest
-	(*CreateConfigurationResponse)(nil),  // 26: gogent.CreateConfigurationResponse
-	(*UpdateConfigurationRequest)(nil),   // 27: gogent.UpdateConfigurationRequest
-	(*UpdateConfigurationResponse)(nil),  // 28: gogent.UpdateConfigurationResponse
-	(*DeleteConfigurationRequest)(nil),   // 29: gogent.DeleteConfigurationRequest
-	(*DeleteConfigurationResponse)(nil),  // 30: gogent.DeleteConfigurationResponse
-	(*ListFunctionsRequest)(nil),         // 31: gogent.ListFunctionsRequest
-	(*ListFunctionsResponse)(nil),        // 32: gogent.ListFunctionsResponse
-	(*GetFunctionRequest)(nil),           // 33: gogent.GetFunctionRequest
-	(*GetFunctionResponse)(nil),          // 34: gogent.GetFunctionResponse
-	(*CreateFunctionRequest)(nil),        // 35: gogent.CreateFunctionRequest
-	(*CreateFunctionResponse)(nil),       // 36: gogent.CreateFunctionResponse
-	(*UpdateFunctionRequest)(nil),        // 37: gogent.UpdateFunctionRequest
-	(*UpdateFunctionResponse)(nil),       // 38: gogent.UpdateFunctionResponse
-	(*DeleteFunctionRequest)(nil),        // 39: gogent.DeleteFunctionRequest
-	(*DeleteFunctionResponse)(nil),       // 40: gogent.DeleteFunctionResponse
-	(*TestFunctionRequest)(nil),          // 41: gogent.TestFunctionRequest
-	(*TestFunctionResponse)(nil),         // 42: gogent.TestFunctionResponse
-	(*GetDatabaseStatsRequest)(nil),      // 43: gogent.GetDatabaseStatsRequest
-	(*GetDatabaseStatsResponse)(nil),     // 44: gogent.GetDatabaseStatsResponse
-	(*ListDatabaseTablesRequest)(nil),    // 45: gogent.ListDatabaseTablesRequest
-	(*ListDatabaseTablesResponse)(nil),   // 46: gogent.ListDatabaseTablesResponse
-	(*GetTableDataRequest)(nil),          // 47: gogent.GetTableDataRequest
-	(*GetTableDataResponse)(nil),         // 48: gogent.GetTableDataResponse
-	(*HealthRequest)(nil),                // 49: gogent.HealthRequest
-	(*HealthResponse)(nil),               // 50: gogent.HealthResponse
-	(*ExecutionRun)(nil),                 // 51: gogent.ExecutionRun
-	(*APIConfiguration)(nil),             // 52: gogent.APIConfiguration
-	(*Tool)(nil),                         // 53: gogent.Tool
-	(*FunctionDefinition)(nil),           // 54: gogent.FunctionDefinition
-	(*APIRequest)(nil),                   // 55: gogent.APIRequest
-	(*APIResponse)(nil),                  // 56: gogent.APIResponse
-	(*FunctionCall)(nil),                 // 57: gogent.FunctionCall
-	(*ExecutionResult)(nil),              // 58: gogent.ExecutionResult
-	(*VariationResult)(nil),              // 59: gogent.VariationResult
-	(*ComparisonResult)(nil),             // 60: gogent.ComparisonResult
-	(*ExecutionLog)(nil),                 // 61: gogent.ExecutionLog
-	(*ComparisonConfig)(nil),             // 62: gogent.ComparisonConfig
-	nil,                                  // 63: gogent.ExecuteRequest.SessionApiKeysEntry
-	(*timestamppb.Timestamp)(nil),        // 64: google.protobuf.Timestamp
-	(*structpb.Struct)(nil),              // 65: google.protobuf.Struct
-	(*structpb.ListValue)(nil),           // 66: google.protobuf.ListValue
+	(*RefreshTokenRequest)(nil),          // 11: gogent.RefreshTokenRequest
+	(*RefreshTokenResponse)(nil),         // 12: gogent.RefreshTokenResponse
+	(*LogoutRequest)(nil),                // 13: gogent.LogoutRequest
+	(*LogoutResponse)(nil),               // 14: gogent.LogoutResponse
+	(*RequestPasswordResetRequest)(nil),  // 15: gogent.RequestPasswordResetRequest
+	(*RequestPasswordResetResponse)(nil), // 16: gogent.RequestPasswordResetResponse
+	(*ResetPasswordRequest)(nil),         // 17: gogent.ResetPasswordRequest
+	(*ResetPasswordResponse)(nil),        // 18: gogent.ResetPasswordResponse
+	(*GetCurrentUserRequest)(nil),        // 19: gogent.GetCurrentUserRequest
+	(*GetCurrentUserResponse)(nil),       // 20: gogent.GetCurrentUserResponse
+	(*ExecuteRequest)(nil),               // 21: gogent.ExecuteRequest
+	(*ExecuteResponse)(nil),              // 22: gogent.ExecuteResponse
+	(*GetExecutionStatusRequest)(nil),    // 23: gogent.GetExecutionStatusRequest
+	(*GetExecutionStatusResponse)(nil),   // 24: gogent.GetExecutionStatusResponse
+	(*GetExecutionResultRequest)(nil),    // 25: gogent.GetExecutionResultRequest
+	(*GetExecutionResultResponse)(nil),   // 26: gogent.GetExecutionResultResponse
+	(*ListExecutionRunsRequest)(nil),     // 27: gogent.ListExecutionRunsRequest
+	(*ListExecutionRunsResponse)(nil),    // 28: gogent.ListExecutionRunsResponse
+	(*ListComparisonsRequest)(nil),       // 29: gogent.ListComparisonsRequest
+	(*ListComparisonsResponse)(nil),      // 30: gogent.ListComparisonsResponse
+	(*ListExecutionLogsRequest)(nil),     // 31: gogent.ListExecutionLogsRequest
+	(*ListExecutionLogsResponse)(nil),    // 32: gogent.ListExecutionLogsResponse
+	(*WatchExecutionRequest)(nil),        // 33: gogent.WatchExecutionRequest
+	(*ExecutionEvent)(nil),               // 34: gogent.ExecutionEvent
+	(*GetComparisonRequest)(nil),         // 35: gogent.GetComparisonRequest
+	(*GetComparisonResponse)(nil),        // 36: gogent.GetComparisonResponse
+	(*DeleteExecutionRunRequest)(nil),    // 37: gogent.DeleteExecutionRunRequest
+	(*DeleteExecutionRunResponse)(nil),   // 38: gogent.DeleteExecutionRunResponse
+	(*BatchItem)(nil),                    // 39: gogent.BatchItem
+	(*ExpectedAnswer)(nil),               // 40: gogent.ExpectedAnswer
+	(*ConfigurationAccuracy)(nil),        // 41: gogent.ConfigurationAccuracy
+	(*ParameterSweep)(nil),               // 42: gogent.ParameterSweep
+	(*SweepRange)(nil),                   // 43: gogent.SweepRange
+	(*SweepReport)(nil),                  // 44: gogent.SweepReport
+	(*SweepPoint)(nil),                   // 45: gogent.SweepPoint
+	(*ParameterSummary)(nil),             // 46: gogent.ParameterSummary
+	(*SweepValueScore)(nil),              // 47: gogent.SweepValueScore
+	(*SubmitBatchRequest)(nil),           // 48: gogent.SubmitBatchRequest
+	(*SubmitBatchAck)(nil),               // 49: gogent.SubmitBatchAck
+	(*BatchRun)(nil),                     // 50: gogent.BatchRun
+	(*GetBatchRunRequest)(nil),           // 51: gogent.GetBatchRunRequest
+	(*GetBatchRunResponse)(nil),          // 52: gogent.GetBatchRunResponse
+	(*ListConfigurationsRequest)(nil),    // 53: gogent.ListConfigurationsRequest
+	(*ListConfigurationsResponse)(nil),   // 54: gogent.ListConfigurationsResponse
+	(*CreateConfigurationRequest)(nil),   // 55: gogent.CreateConfigurationRequest
+	(*CreateConfigurationResponse)(nil),  // 56: gogent.CreateConfigurationResponse
+	(*UpdateConfigurationRequest)(nil),   // 57: gogent.UpdateConfigurationRequest
+	(*UpdateConfigurationResponse)(nil),  // 58: gogent.UpdateConfigurationResponse
+	(*DeleteConfigurationRequest)(nil),   // 59: gogent.DeleteConfigurationRequest
+	(*DeleteConfigurationResponse)(nil),  // 60: gogent.DeleteConfigurationResponse
+	(*ListFunctionsRequest)(nil),         // 61: gogent.ListFunctionsRequest
+	(*ListFunctionsResponse)(nil),        // 62: gogent.ListFunctionsResponse
+	(*GetFunctionRequest)(nil),           // 63: gogent.GetFunctionRequest
+	(*GetFunctionResponse)(nil),          // 64: gogent.GetFunctionResponse
+	(*CreateFunctionRequest)(nil),        // 65: gogent.CreateFunctionRequest
+	(*CreateFunctionResponse)(nil),       // 66: gogent.CreateFunctionResponse
+	(*UpdateFunctionRequest)(nil),        // 67: gogent.UpdateFunctionRequest
+	(*UpdateFunctionResponse)(nil),       // 68: gogent.UpdateFunctionResponse
+	(*DeleteFunctionRequest)(nil),        // 69: gogent.DeleteFunctionRequest
+	(*DeleteFunctionResponse)(nil),       // 70: gogent.DeleteFunctionResponse
+	(*TestFunctionRequest)(nil),          // 71: gogent.TestFunctionRequest
+	(*TestFunctionResponse)(nil),         // 72: gogent.TestFunctionResponse
+	(*GetDatabaseStatsRequest)(nil),      // 73: gogent.GetDatabaseStatsRequest
+	(*GetDatabaseStatsResponse)(nil),     // 74: gogent.GetDatabaseStatsResponse
+	(*ListDatabaseTablesRequest)(nil),    // 75: gogent.ListDatabaseTablesRequest
+	(*ListDatabaseTablesResponse)(nil),   // 76: gogent.ListDatabaseTablesResponse
+	(*GetTableDataRequest)(nil),          // 77: gogent.GetTableDataRequest
+	(*GetTableDataResponse)(nil),         // 78: gogent.GetTableDataResponse
+	(*HealthRequest)(nil),                // 79: gogent.HealthRequest
+	(*HealthResponse)(nil),               // 80: gogent.HealthResponse
+	(*HealthCheck)(nil),                  // 81: gogent.HealthCheck
+	(*ExecutionRun)(nil),                 // 82: gogent.ExecutionRun
+	(*APIConfiguration)(nil),             // 83: gogent.APIConfiguration
+	(*GuardConfig)(nil),                  // 84: gogent.GuardConfig
+	(*GuardVerdict)(nil),                 // 85: gogent.GuardVerdict
+	(*RetrievalConfig)(nil),              // 86: gogent.RetrievalConfig
+	(*RetrievedChunk)(nil),               // 87: gogent.RetrievedChunk
+	(*SafetyPolicy)(nil),                 // 88: gogent.SafetyPolicy
+	(*Tool)(nil),                         // 89: gogent.Tool
+	(*FunctionDefinition)(nil),           // 90: gogent.FunctionDefinition
+	(*APIRequest)(nil),                   // 91: gogent.APIRequest
+	(*APIResponse)(nil),                  // 92: gogent.APIResponse
+	(*LatencyBreakdown)(nil),             // 93: gogent.LatencyBreakdown
+	(*FunctionCall)(nil),                 // 94: gogent.FunctionCall
+	(*ExecutionResult)(nil),              // 95: gogent.ExecutionResult
+	(*VariationResult)(nil),              // 96: gogent.VariationResult
+	(*ComparisonResult)(nil),             // 97: gogent.ComparisonResult
+	(*SignificanceTest)(nil),             // 98: gogent.SignificanceTest
+	(*ExecutionLog)(nil),                 // 99: gogent.ExecutionLog
+	(*ComparisonConfig)(nil),             // 100: gogent.ComparisonConfig
+	(*JudgeConfig)(nil),                  // 101: gogent.JudgeConfig
+	(*ToolAppropriatenessConfig)(nil),    // 102: gogent.ToolAppropriatenessConfig
+	nil,                                  // 103: gogent.ExecuteRequest.SessionApiKeysEntry
+	nil,                                  // 104: gogent.BatchItem.MetadataEntry
+	nil,                                  // 105: gogent.SafetyPolicy.ThresholdsEntry
+	(*timestamppb.Timestamp)(nil),        // 106: google.protobuf.Timestamp
+	(*structpb.Struct)(nil),              // 107: google.protobuf.Struct
+	(*structpb.ListValue)(nil),           // 108: google.protobuf.ListValue
 }
 var file_proto_gogent_proto_depIdxs = []int32{
-	64,  // 0: gogent.User.created_at:type_name -> google.protobuf.Timestamp
-	64,  // 1: gogent.User.updated_at:type_name -> google.protobuf.Timestamp
-	64,  // 2: gogent.User.last_login_at:type_name -> google.protobuf.Timestamp
+	106, // 0: gogent.User.created_at:type_name -> google.protobuf.Timestamp
+	106, // 1: gogent.User.updated_at:type_name -> google.protobuf.Timestamp
+	106, // 2: gogent.User.last_login_at:type_name -> google.protobuf.Timestamp
 	0,   // 3: gogent.LoginResponse.user:type_name -> gogent.User
-	64,  // 4: gogent.LoginResponse.expires_at:type_name -> google.protobuf.Timestamp
+	106, // 4: gogent.LoginResponse.expires_at:type_name -> google.protobuf.Timestamp
 	0,   // 5: gogent.RegisterResponse.user:type_name -> gogent.User
 	0,   // 6: gogent.CreateTemporaryUserResponse.user:type_name -> gogent.User
 	0,   // 7: gogent.SaveTemporaryAccountResponse.user:type_name -> gogent.User
 	0,   // 8: gogent.VerifyEmailResponse.user:type_name -> gogent.User
-	0,   // 9: gogent.GetCurrentUserResponse.user:type_name -> gogent.User
-	52,  // 10: gogent.ExecuteRequest.configurations:type_name -> gogent.APIConfiguration
-	53,  // 11: gogent.ExecuteRequest.function_tools:type_name -> gogent.Tool
-	62,  // 12: gogent.ExecuteRequest.comparison_config:type_name -> gogent.ComparisonConfig
-	63,  // 13: gogent.ExecuteRequest.session_api_keys:type_name -> gogent.ExecuteRequest.SessionApiKeysEntry
-	51,  // 14: gogent.ExecuteResponse.execution_run:type_name -> gogent.ExecutionRun
-	64,  // 15: gogent.GetExecutionStatusResponse.start_time:type_name -> google.protobuf.Timestamp
-	64,  // 16: gogent.GetExecutionStatusResponse.end_time:type_name -> google.protobuf.Timestamp
-	58,  // 17: gogent.GetExecutionStatusResponse.result:type_name -> gogent.ExecutionResult
-	58,  // 18: gogent.GetExecutionResultResponse.result:type_name -> gogent.ExecutionResult
-	51,  // 19: gogent.ListExecutionRunsResponse.execution_runs:type_name -> gogent.ExecutionRun
-	52,  // 20: gogent.ListConfigurationsResponse.configurations:type_name -> gogent.APIConfiguration
-	52,  // 21: gogent.CreateConfigurationRequest.configuration:type_name -> gogent.APIConfiguration
-	52,  // 22: gogent.CreateConfigurationResponse.configuration:type_name -> gogent.APIConfiguration
-	52,  // 23: gogent.UpdateConfigurationRequest.configuration:type_name -> gogent.APIConfiguration
-	52,  // 24: gogent.UpdateConfigurationResponse.configuration:type_name -> gogent.APIConfiguration
-	54,  // 25: gogent.ListFunctionsResponse.functions:type_name -> gogent.FunctionDefinition
-	54,  // 26: gogent.GetFunctionResponse.function:type_name -> gogent.FunctionDefinition
-	54,  // 27: gogent.CreateFunctionRequest.function:type_name -> gogent.FunctionDefinition
-	54,  // 28: gogent.CreateFunctionResponse.function:type_name -> gogent.FunctionDefinition
-	54,  // 29: gogent.UpdateFunctionRequest.function:type_name -> gogent.FunctionDefinition
-	54,  // 30: gogent.UpdateFunctionResponse.function:type_name -> gogent.FunctionDefinition
-	65,  // 31: gogent.TestFunctionRequest.arguments:type_name -> google.protobuf.Struct
-	65,  // 32: gogent.TestFunctionResponse.response:type_name -> google.protobuf.Struct
-	66,  // 33: gogent.GetTableDataResponse.rows:type_name -> google.protobuf.ListValue
-	64,  // 34: gogent.HealthResponse.timestamp:type_name -> google.protobuf.Timestamp
-	64,  // 35: gogent.ExecutionRun.created_at:type_name -> google.protobuf.Timestamp
-	64,  // 36: gogent.ExecutionRun.updated_at:type_name -> google.protobuf.Timestamp
-	65,  // 37: gogent.APIConfiguration.safety_settings:type_name -> google.protobuf.Struct
-	65,  // 38: gogent.APIConfiguration.generation_config:type_name -> google.protobuf.Struct
-	53,  // 39: gogent.APIConfiguration.tools:type_name -> gogent.Tool
-	65,  // 40: gogent.APIConfiguration.tool_config:type_name -> google.protobuf.Struct
-	64,  // 41: gogent.APIConfiguration.created_at:type_name -> google.protobuf.Timestamp
-	65,  // 42: gogent.Tool.parameters:type_name -> google.protobuf.Struct
-	65,  // 43: gogent.FunctionDefinition.parameters_schema:type_name -> google.protobuf.Struct
-	65,  // 44: gogent.FunctionDefinition.mock_response:type_name -> google.protobuf.Struct
-	65,  // 45: gogent.FunctionDefinition.headers:type_name -> google.protobuf.Struct
-	65,  // 46: gogent.FunctionDefinition.auth_config:type_name -> google.protobuf.Struct
-	65,  // 47: gogent.FunctionDefinition.api_key_validation:type_name -> google.protobuf.Struct
-	64,  // 48: gogent.FunctionDefinition.created_at:type_name -> google.protobuf.Timestamp
-	64,  // 49: gogent.FunctionDefinition.updated_at:type_name -> google.protobuf.Timestamp
-	65,  // 50: gogent.APIRequest.function_parameters:type_name -> google.protobuf.Struct
-	65,  // 51: gogent.APIRequest.request_headers:type_name -> google.protobuf.Struct
-	65,  // 52: gogent.APIRequest.request_body:type_name -> google.protobuf.Struct
-	64,  // 53: gogent.APIRequest.created_at:type_name -> google.protobuf.Timestamp
-	65,  // 54: gogent.APIResponse.function_call_response:type_name -> google.protobuf.Struct
-	65,  // 55: gogent.APIResponse.usage_metadata:type_name -> google.protobuf.Struct
-	65,  // 56: gogent.APIResponse.safety_ratings:type_name -> google.protobuf.Struct
-	65,  // 57: gogent.APIResponse.response_headers:type_name -> google.protobuf.Struct
-	65,  // 58: gogent.APIResponse.response_body:type_name -> google.protobuf.Struct
-	64,  // 59: gogent.APIResponse.created_at:type_name -> google.protobuf.Timestamp
-	65,  // 60: gogent.FunctionCall.function_arguments:type_name -> google.protobuf.Struct
-	65,  // 61: gogent.FunctionCall.function_response:type_name -> google.protobuf.Struct
-	64,  // 62: gogent.FunctionCall.created_at:type_name -> google.protobuf.Timestamp
-	51,  // 63: gogent.ExecutionResult.execution_run:type_name -> gogent.ExecutionRun
-	59,  // 64: gogent.ExecutionResult.results:type_name -> gogent.VariationResult
-	60,  // 65: gogent.ExecutionResult.comparison:type_name -> gogent.ComparisonResult
-	61,  // 66: gogent.ExecutionResult.logs:type_name -> gogent.ExecutionLog
-	52,  // 67: gogent.VariationResult.configuration:type_name -> gogent.APIConfiguration
-	55,  // 68: gogent.VariationResult.request:type_name -> gogent.APIRequest
-	56,  // 69: gogent.VariationResult.response:type_name -> gogent.APIResponse
-	57,  // 70: gogent.VariationResult.function_calls:type_name -> gogent.FunctionCall
-	65,  // 71: gogent.ComparisonResult.configuration_scores:type_name -> google.protobuf.Struct
-	52,  // 72: gogent.ComparisonResult.best_configuration:type_name -> gogent.APIConfiguration
-	52,  // 73: gogent.ComparisonResult.all_configurations:type_name -> gogent.APIConfiguration
-	64,  // 74: gogent.ComparisonResult.created_at:type_name -> google.protobuf.Timestamp
-	65,  // 75: gogent.ExecutionLog.details:type_name -> google.protobuf.Struct
-	64,  // 76: gogent.ExecutionLog.timestamp:type_name -> google.protobuf.Timestamp
-	1,   // 77: gogent.GogentService.Login:input_type -> gogent.LoginRequest
-	3,   // 78: gogent.GogentService.Register:input_type -> gogent.RegisterRequest
-	5,   // 79: gogent.GogentService.CreateTemporaryUser:input_type -> gogent.CreateTemporaryUserRequest
-	7,   // 80: gogent.GogentService.SaveTemporaryAccount:input_type -> gogent.SaveTemporaryAccountRequest
-	9,   // 81: gogent.GogentService.VerifyEmail:input_type -> gogent.VerifyEmailRequest
-	11,  // 82: gogent.GogentService.GetCurrentUser:input_type -> gogent.GetCurrentUserRequest
-	13,  // 83: gogent.GogentService.Execute:input_type -> gogent.ExecuteRequest
-	15,  // 84: gogent.GogentService.GetExecutionStatus:input_type -> gogent.GetExecutionStatusRequest
-	17,  // 85: gogent.GogentService.GetExecutionResult:input_type -> gogent.GetExecutionResultRequest
-	19,  // 86: gogent.GogentService.ListExecutionRuns:input_type -> gogent.ListExecutionRunsRequest
-	21,  // 87: gogent.GogentService.DeleteExecutionRun:input_type -> gogent.DeleteExecutionRunRequest
-	23,  // 88: gogent.GogentService.ListConfigurations:input_type -> gogent.ListConfigurationsRequest
-	25,  // 89: gogent.GogentService.CreateConfiguration:input_type -> gogent.CreateConfigurationRequest
-	27,  // 90: gogent.GogentService.UpdateConfiguration:input_type -> gogent.UpdateConfigurationRequest
-	29,  // 91: gogent.GogentService.DeleteConfiguration:input_type -> gogent.DeleteConfigurationRequest
-	31,  // 92: gogent.GogentService.ListFunctions:input_type -> gogent.ListFunctionsRequest
-	33,  // 93: gogent.GogentService.GetFunction:input_type -> gogent.GetFunctionRequest
-	35,  // 94: gogent.GogentService.CreateFunction:input_type -> gogent.CreateFunctionRequest
-	37,  // 95: gogent.GogentService.UpdateFunction:input_type -> gogent.UpdateFunctionRequest
-	39,  // 96: gogent.GogentService.DeleteFunction:input_type -> gogent.DeleteFunctionRequest
-	41,  // 97: gogent.GogentService.TestFunction:input_type -> gogent.TestFunctionRequest
-	43,  // 98: gogent.GogentService.GetDatabaseStats:input_type -> gogent.GetDatabaseStatsRequest
-	45,  // 99: gogent.GogentService.ListDatabaseTables:input_type -> gogent.ListDatabaseTablesRequest
-	47,  // 100: gogent.GogentService.GetTableData:input_type -> gogent.GetTableDataRequest
-	49,  // 101: gogent.GogentService.Health:input_type -> gogent.HealthRequest
-	2,   // 102: gogent.GogentService.Login:output_type -> gogent.LoginResponse
-	4,   // 103: gogent.GogentService.Register:output_type -> gogent.RegisterResponse
-	6,   // 104: gogent.GogentService.CreateTemporaryUser:output_type -> gogent.CreateTemporaryUserResponse
-	8,   // 105: gogent.GogentService.SaveTemporaryAccount:output_type -> gogent.SaveTemporaryAccountResponse
-	10,  // 106: gogent.GogentService.VerifyEmail:output_type -> gogent.VerifyEmailResponse
-	12,  // 107: gogent.GogentService.GetCurrentUser:output_type -> gogent.GetCurrentUserResponse
-	14,  // 108: gogent.GogentService.Execute:output_type -> gogent.ExecuteResponse
-	16,  // 109: gogent.GogentService.GetExecutionStatus:output_type -> gogent.GetExecutionStatusResponse
-	18,  // 110: gogent.GogentService.GetExecutionResult:output_type -> gogent.GetExecutionResultResponse
-	20,  // 111: gogent.GogentService.ListExecutionRuns:output_type -> gogent.ListExecutionRunsResponse
-	22,  // 112: gogent.GogentService.DeleteExecutionRun:output_type -> gogent.DeleteExecutionRunResponse
-	24,  // 113: gogent.GogentService.ListConfigurations:output_type -> gogent.ListConfigurationsResponse
-	26,  // 114: gogent.GogentService.CreateConfiguration:output_type -> gogent.CreateConfigurationResponse
-	28,  // 115: gogent.GogentService.UpdateConfiguration:output_type -> gogent.UpdateConfigurationResponse
-	30,  // 116: gogent.GogentService.DeleteConfiguration:output_type -> gogent.DeleteConfigurationResponse
-	32,  // 117: gogent.GogentService.ListFunctions:output_type -> gogent.ListFunctionsResponse
-	34,  // 118: gogent.GogentService.GetFunction:output_type -> gogent.GetFunctionResponse
-	36,  // 119: gogent.GogentService.CreateFunction:output_type -> gogent.CreateFunctionResponse
-	38,  // 120: gogent.GogentService.UpdateFunction:output_type -> gogent.UpdateFunctionResponse
-	40,  // 121: gogent.GogentService.DeleteFunction:output_type -> gogent.DeleteFunctionResponse
-	42,  // 122: gogent.GogentService.TestFunction:output_type -> gogent.TestFunctionResponse
-	44,  // 123: gogent.GogentService.GetDatabaseStats:output_type -> gogent.GetDatabaseStatsResponse
-	46,  // 124: gogent.GogentService.ListDatabaseTables:output_type -> gogent.ListDatabaseTablesResponse
-	48,  // 125: gogent.GogentService.GetTableData:output_type -> gogent.GetTableDataResponse
-	50,  // 126: gogent.GogentService.Health:output_type -> gogent.HealthResponse
-	102, // [102:127] is the sub-list for method output_type
-	77,  // [77:102] is the sub-list for method input_type
-	77,  // [77:77] is the sub-list for extension type_name
-	77,  // [77:77] is the sub-list for extension extendee
-	0,   // [0:77] is the sub-list for field type_name
+	0,   // 9: gogent.RefreshTokenResponse.user:type_name -> gogent.User
+	106, // 10: gogent.RefreshTokenResponse.expires_at:type_name -> google.protobuf.Timestamp
+	0,   // 11: gogent.ResetPasswordResponse.user:type_name -> gogent.User
+	0,   // 12: gogent.GetCurrentUserResponse.user:type_name -> gogent.User
+	83,  // 13: gogent.ExecuteRequest.configurations:type_name -> gogent.APIConfiguration
+	89,  // 14: gogent.ExecuteRequest.function_tools:type_name -> gogent.Tool
+	100, // 15: gogent.ExecuteRequest.comparison_config:type_name -> gogent.ComparisonConfig
+	103, // 16: gogent.ExecuteRequest.session_api_keys:type_name -> gogent.ExecuteRequest.SessionApiKeysEntry
+	88,  // 17: gogent.ExecuteRequest.safety_policy:type_name -> gogent.SafetyPolicy
+	40,  // 18: gogent.ExecuteRequest.expected_answer:type_name -> gogent.ExpectedAnswer
+	42,  // 19: gogent.ExecuteRequest.sweep:type_name -> gogent.ParameterSweep
+	82,  // 20: gogent.ExecuteResponse.execution_run:type_name -> gogent.ExecutionRun
+	106, // 21: gogent.GetExecutionStatusResponse.start_time:type_name -> google.protobuf.Timestamp
+	106, // 22: gogent.GetExecutionStatusResponse.end_time:type_name -> google.protobuf.Timestamp
+	95,  // 23: gogent.GetExecutionStatusResponse.result:type_name -> gogent.ExecutionResult
+	95,  // 24: gogent.GetExecutionResultResponse.result:type_name -> gogent.ExecutionResult
+	82,  // 25: gogent.ListExecutionRunsResponse.execution_runs:type_name -> gogent.ExecutionRun
+	97,  // 26: gogent.ListComparisonsResponse.comparisons:type_name -> gogent.ComparisonResult
+	99,  // 27: gogent.ListExecutionLogsResponse.logs:type_name -> gogent.ExecutionLog
+	99,  // 28: gogent.ExecutionEvent.logs:type_name -> gogent.ExecutionLog
+	95,  // 29: gogent.ExecutionEvent.result:type_name -> gogent.ExecutionResult
+	97,  // 30: gogent.GetComparisonResponse.comparison:type_name -> gogent.ComparisonResult
+	104, // 31: gogent.BatchItem.metadata:type_name -> gogent.BatchItem.MetadataEntry
+	40,  // 32: gogent.BatchItem.expected_answer:type_name -> gogent.ExpectedAnswer
+	43,  // 33: gogent.ParameterSweep.temperature:type_name -> gogent.SweepRange
+	43,  // 34: gogent.ParameterSweep.top_p:type_name -> gogent.SweepRange
+	43,  // 35: gogent.ParameterSweep.top_k:type_name -> gogent.SweepRange
+	45,  // 36: gogent.SweepReport.points:type_name -> gogent.SweepPoint
+	46,  // 37: gogent.SweepReport.parameters:type_name -> gogent.ParameterSummary
+	47,  // 38: gogent.ParameterSummary.values:type_name -> gogent.SweepValueScore
+	21,  // 39: gogent.SubmitBatchRequest.template:type_name -> gogent.ExecuteRequest
+	39,  // 40: gogent.SubmitBatchRequest.items:type_name -> gogent.BatchItem
+	106, // 41: gogent.BatchRun.created_at:type_name -> google.protobuf.Timestamp
+	106, // 42: gogent.BatchRun.updated_at:type_name -> google.protobuf.Timestamp
+	41,  // 43: gogent.BatchRun.accuracy:type_name -> gogent.ConfigurationAccuracy
+	50,  // 44: gogent.GetBatchRunResponse.batch_run:type_name -> gogent.BatchRun
+	83,  // 45: gogent.ListConfigurationsResponse.configurations:type_name -> gogent.APIConfiguration
+	83,  // 46: gogent.CreateConfigurationRequest.configuration:type_name -> gogent.APIConfiguration
+	83,  // 47: gogent.CreateConfigurationResponse.configuration:type_name -> gogent.APIConfiguration
+	83,  // 48: gogent.UpdateConfigurationRequest.configuration:type_name -> gogent.APIConfiguration
+	83,  // 49: gogent.UpdateConfigurationResponse.configuration:type_name -> gogent.APIConfiguration
+	90,  // 50: gogent.ListFunctionsResponse.functions:type_name -> gogent.FunctionDefinition
+	90,  // 51: gogent.GetFunctionResponse.function:type_name -> gogent.FunctionDefinition
+	90,  // 52: gogent.CreateFunctionRequest.function:type_name -> gogent.FunctionDefinition
+	90,  // 53: gogent.CreateFunctionResponse.function:type_name -> gogent.FunctionDefinition
+	90,  // 54: gogent.UpdateFunctionRequest.function:type_name -> gogent.FunctionDefinition
+	90,  // 55: gogent.UpdateFunctionResponse.function:type_name -> gogent.FunctionDefinition
+	107, // 56: gogent.TestFunctionRequest.arguments:type_name -> google.protobuf.Struct
+	107, // 57: gogent.TestFunctionResponse.response:type_name -> google.protobuf.Struct
+	106, // 58: gogent.GetDatabaseStatsResponse.refreshed_at:type_name -> google.protobuf.Timestamp
+	108, // 59: gogent.GetTableDataResponse.rows:type_name -> google.protobuf.ListValue
+	106, // 60: gogent.HealthResponse.timestamp:type_name -> google.protobuf.Timestamp
+	81,  // 61: gogent.HealthResponse.checks:type_name -> gogent.HealthCheck
+	107, // 62: gogent.HealthCheck.details:type_name -> google.protobuf.Struct
+	106, // 63: gogent.HealthCheck.checked_at:type_name -> google.protobuf.Timestamp
+	106, // 64: gogent.ExecutionRun.created_at:type_name -> google.protobuf.Timestamp
+	106, // 65: gogent.ExecutionRun.updated_at:type_name -> google.protobuf.Timestamp
+	107, // 66: gogent.APIConfiguration.safety_settings:type_name -> google.protobuf.Struct
+	107, // 67: gogent.APIConfiguration.generation_config:type_name -> google.protobuf.Struct
+	89,  // 68: gogent.APIConfiguration.tools:type_name -> gogent.Tool
+	107, // 69: gogent.APIConfiguration.tool_config:type_name -> google.protobuf.Struct
+	106, // 70: gogent.APIConfiguration.created_at:type_name -> google.protobuf.Timestamp
+	88,  // 71: gogent.APIConfiguration.safety_policy:type_name -> gogent.SafetyPolicy
+	107, // 72: gogent.APIConfiguration.response_schema:type_name -> google.protobuf.Struct
+	86,  // 73: gogent.APIConfiguration.retrieval:type_name -> gogent.RetrievalConfig
+	84,  // 74: gogent.APIConfiguration.guardrails:type_name -> gogent.GuardConfig
+	105, // 75: gogent.SafetyPolicy.thresholds:type_name -> gogent.SafetyPolicy.ThresholdsEntry
+	107, // 76: gogent.Tool.parameters:type_name -> google.protobuf.Struct
+	107, // 77: gogent.Tool.mock_response:type_name -> google.protobuf.Struct
+	107, // 78: gogent.FunctionDefinition.parameters_schema:type_name -> google.protobuf.Struct
+	107, // 79: gogent.FunctionDefinition.mock_response:type_name -> google.protobuf.Struct
+	107, // 80: gogent.FunctionDefinition.headers:type_name -> google.protobuf.Struct
+	107, // 81: gogent.FunctionDefinition.auth_config:type_name -> google.protobuf.Struct
+	107, // 82: gogent.FunctionDefinition.api_key_validation:type_name -> google.protobuf.Struct
+	106, // 83: gogent.FunctionDefinition.created_at:type_name -> google.protobuf.Timestamp
+	106, // 84: gogent.FunctionDefinition.updated_at:type_name -> google.protobuf.Timestamp
+	107, // 85: gogent.APIRequest.function_parameters:type_name -> google.protobuf.Struct
+	107, // 86: gogent.APIRequest.request_headers:type_name -> google.protobuf.Struct
+	107, // 87: gogent.APIRequest.request_body:type_name -> google.protobuf.Struct
+	106, // 88: gogent.APIRequest.created_at:type_name -> google.protobuf.Timestamp
+	107, // 89: gogent.APIResponse.function_call_response:type_name -> google.protobuf.Struct
+	107, // 90: gogent.APIResponse.usage_metadata:type_name -> google.protobuf.Struct
+	107, // 91: gogent.APIResponse.safety_ratings:type_name -> google.protobuf.Struct
+	107, // 92: gogent.APIResponse.response_headers:type_name -> google.protobuf.Struct
+	107, // 93: gogent.APIResponse.response_body:type_name -> google.protobuf.Struct
+	106, // 94: gogent.APIResponse.created_at:type_name -> google.protobuf.Timestamp
+	93,  // 95: gogent.APIResponse.latency:type_name -> gogent.LatencyBreakdown
+	107, // 96: gogent.FunctionCall.function_arguments:type_name -> google.protobuf.Struct
+	107, // 97: gogent.FunctionCall.function_response:type_name -> google.protobuf.Struct
+	106, // 98: gogent.FunctionCall.created_at:type_name -> google.protobuf.Timestamp
+	82,  // 99: gogent.ExecutionResult.execution_run:type_name -> gogent.ExecutionRun
+	96,  // 100: gogent.ExecutionResult.results:type_name -> gogent.VariationResult
+	97,  // 101: gogent.ExecutionResult.comparison:type_name -> gogent.ComparisonResult
+	99,  // 102: gogent.ExecutionResult.logs:type_name -> gogent.ExecutionLog
+	41,  // 103: gogent.ExecutionResult.accuracy:type_name -> gogent.ConfigurationAccuracy
+	44,  // 104: gogent.ExecutionResult.sweep_report:type_name -> gogent.SweepReport
+	83,  // 105: gogent.VariationResult.configuration:type_name -> gogent.APIConfiguration
+	91,  // 106: gogent.VariationResult.request:type_name -> gogent.APIRequest
+	92,  // 107: gogent.VariationResult.response:type_name -> gogent.APIResponse
+	94,  // 108: gogent.VariationResult.function_calls:type_name -> gogent.FunctionCall
+	87,  // 109: gogent.VariationResult.retrieved_chunks:type_name -> gogent.RetrievedChunk
+	85,  // 110: gogent.VariationResult.guard_verdicts:type_name -> gogent.GuardVerdict
+	107, // 111: gogent.ComparisonResult.configuration_scores:type_name -> google.protobuf.Struct
+	83,  // 112: gogent.ComparisonResult.best_configuration:type_name -> gogent.APIConfiguration
+	83,  // 113: gogent.ComparisonResult.all_configurations:type_name -> gogent.APIConfiguration
+	106, // 114: gogent.ComparisonResult.created_at:type_name -> google.protobuf.Timestamp
+	98,  // 115: gogent.ComparisonResult.significance_tests:type_name -> gogent.SignificanceTest
+	107, // 116: gogent.ExecutionLog.details:type_name -> google.protobuf.Struct
+	106, // 117: gogent.ExecutionLog.timestamp:type_name -> google.protobuf.Timestamp
+	102, // 118: gogent.ComparisonConfig.tool_appropriateness:type_name -> gogent.ToolAppropriatenessConfig
+	101, // 119: gogent.ComparisonConfig.judge:type_name -> gogent.JudgeConfig
+	1,   // 120: gogent.GogentService.Login:input_type -> gogent.LoginRequest
+	3,   // 121: gogent.GogentService.Register:input_type -> gogent.RegisterRequest
+	5,   // 122: gogent.GogentService.CreateTemporaryUser:input_type -> gogent.CreateTemporaryUserRequest
+	7,   // 123: gogent.GogentService.SaveTemporaryAccount:input_type -> gogent.SaveTemporaryAccountRequest
+	9,   // 124: gogent.GogentService.VerifyEmail:input_type -> gogent.VerifyEmailRequest
+	19,  // 125: gogent.GogentService.GetCurrentUser:input_type -> gogent.GetCurrentUserRequest
+	11,  // 126: gogent.GogentService.RefreshToken:input_type -> gogent.RefreshTokenRequest
+	13,  // 127: gogent.GogentService.Logout:input_type -> gogent.LogoutRequest
+	15,  // 128: gogent.GogentService.RequestPasswordReset:input_type -> gogent.RequestPasswordResetRequest
+	17,  // 129: gogent.GogentService.ResetPassword:input_type -> gogent.ResetPasswordRequest
+	21,  // 130: gogent.GogentService.Execute:input_type -> gogent.ExecuteRequest
+	23,  // 131: gogent.GogentService.GetExecutionStatus:input_type -> gogent.GetExecutionStatusRequest
+	25,  // 132: gogent.GogentService.GetExecutionResult:input_type -> gogent.GetExecutionResultRequest
+	27,  // 133: gogent.GogentService.ListExecutionRuns:input_type -> gogent.ListExecutionRunsRequest
+	37,  // 134: gogent.GogentService.DeleteExecutionRun:input_type -> gogent.DeleteExecutionRunRequest
+	29,  // 135: gogent.GogentService.ListComparisons:input_type -> gogent.ListComparisonsRequest
+	35,  // 136: gogent.GogentService.GetComparison:input_type -> gogent.GetComparisonRequest
+	31,  // 137: gogent.GogentService.ListExecutionLogs:input_type -> gogent.ListExecutionLogsRequest
+	33,  // 138: gogent.GogentService.WatchExecution:input_type -> gogent.WatchExecutionRequest
+	48,  // 139: gogent.GogentService.SubmitBatch:input_type -> gogent.SubmitBatchRequest
+	51,  // 140: gogent.GogentService.GetBatchRun:input_type -> gogent.GetBatchRunRequest
+	53,  // 141: gogent.GogentService.ListConfigurations:input_type -> gogent.ListConfigurationsRequest
+	55,  // 142: gogent.GogentService.CreateConfiguration:input_type -> gogent.CreateConfigurationRequest
+	57,  // 143: gogent.GogentService.UpdateConfiguration:input_type -> gogent.UpdateConfigurationRequest
+	59,  // 144: gogent.GogentService.DeleteConfiguration:input_type -> gogent.DeleteConfigurationRequest
+	61,  // 145: gogent.GogentService.ListFunctions:input_type -> gogent.ListFunctionsRequest
+	63,  // 146: gogent.GogentService.GetFunction:input_type -> gogent.GetFunctionRequest
+	65,  // 147: gogent.GogentService.CreateFunction:input_type -> gogent.CreateFunctionRequest
+	67,  // 148: gogent.GogentService.UpdateFunction:input_type -> gogent.UpdateFunctionRequest
+	69,  // 149: gogent.GogentService.DeleteFunction:input_type -> gogent.DeleteFunctionRequest
+	71,  // 150: gogent.GogentService.TestFunction:input_type -> gogent.TestFunctionRequest
+	73,  // 151: gogent.GogentService.GetDatabaseStats:input_type -> gogent.GetDatabaseStatsRequest
+	75,  // 152: gogent.GogentService.ListDatabaseTables:input_type -> gogent.ListDatabaseTablesRequest
+	77,  // 153: gogent.GogentService.GetTableData:input_type -> gogent.GetTableDataRequest
+	79,  // 154: gogent.GogentService.Health:input_type -> gogent.HealthRequest
+	2,   // 155: gogent.GogentService.Login:output_type -> gogent.LoginResponse
+	4,   // 156: gogent.GogentService.Register:output_type -> gogent.RegisterResponse
+	6,   // 157: gogent.GogentService.CreateTemporaryUser:output_type -> gogent.CreateTemporaryUserResponse
+	8,   // 158: gogent.GogentService.SaveTemporaryAccount:output_type -> gogent.SaveTemporaryAccountResponse
+	10,  // 159: gogent.GogentService.VerifyEmail:output_type -> gogent.VerifyEmailResponse
+	20,  // 160: gogent.GogentService.GetCurrentUser:output_type -> gogent.GetCurrentUserResponse
+	12,  // 161: gogent.GogentService.RefreshToken:output_type -> gogent.RefreshTokenResponse
+	14,  // 162: gogent.GogentService.Logout:output_type -> gogent.LogoutResponse
+	16,  // 163: gogent.GogentService.RequestPasswordReset:output_type -> gogent.RequestPasswordResetResponse
+	18,  // 164: gogent.GogentService.ResetPassword:output_type -> gogent.ResetPasswordResponse
+	22,  // 165: gogent.GogentService.Execute:output_type -> gogent.ExecuteResponse
+	24,  // 166: gogent.GogentService.GetExecutionStatus:output_type -> gogent.GetExecutionStatusResponse
+	26,  // 167: gogent.GogentService.GetExecutionResult:output_type -> gogent.GetExecutionResultResponse
+	28,  // 168: gogent.GogentService.ListExecutionRuns:output_type -> gogent.ListExecutionRunsResponse
+	38,  // 169: gogent.GogentService.DeleteExecutionRun:output_type -> gogent.DeleteExecutionRunResponse
+	30,  // 170: gogent.GogentService.ListComparisons:output_type -> gogent.ListComparisonsResponse
+	36,  // 171: gogent.GogentService.GetComparison:output_type -> gogent.GetComparisonResponse
+	32,  // 172: gogent.GogentService.ListExecutionLogs:output_type -> gogent.ListExecutionLogsResponse
+	34,  // 173: gogent.GogentService.WatchExecution:output_type -> gogent.ExecutionEvent
+	49,  // 174: gogent.GogentService.SubmitBatch:output_type -> gogent.SubmitBatchAck
+	52,  // 175: gogent.GogentService.GetBatchRun:output_type -> gogent.GetBatchRunResponse
+	54,  // 176: gogent.GogentService.ListConfigurations:output_type -> gogent.ListConfigurationsResponse
+	56,  // 177: gogent.GogentService.CreateConfiguration:output_type -> gogent.CreateConfigurationResponse
+	58,  // 178: gogent.GogentService.UpdateConfiguration:output_type -> gogent.UpdateConfigurationResponse
+	60,  // 179: gogent.GogentService.DeleteConfiguration:output_type -> gogent.DeleteConfigurationResponse
+	62,  // 180: gogent.GogentService.ListFunctions:output_type -> gogent.ListFunctionsResponse
+	64,  // 181: gogent.GogentService.GetFunction:output_type -> gogent.GetFunctionResponse
+	66,  // 182: gogent.GogentService.CreateFunction:output_type -> gogent.CreateFunctionResponse
+	68,  // 183: gogent.GogentService.UpdateFunction:output_type -> gogent.UpdateFunctionResponse
+	70,  // 184: gogent.GogentService.DeleteFunction:output_type -> gogent.DeleteFunctionResponse
+	72,  // 185: gogent.GogentService.TestFunction:output_type -> gogent.TestFunctionResponse
+	74,  // 186: gogent.GogentService.GetDatabaseStats:output_type -> gogent.GetDatabaseStatsResponse
+	76,  // 187: gogent.GogentService.ListDatabaseTables:output_type -> gogent.ListDatabaseTablesResponse
+	78,  // 188: gogent.GogentService.GetTableData:output_type -> gogent.GetTableDataResponse
+	80,  // 189: gogent.GogentService.Health:output_type -> gogent.HealthResponse
+	155, // [155:190] is the sub-list for method output_type
+	120, // [120:155] is the sub-list for method input_type
+	120, // [120:120] is the sub-list for extension type_name
+	120, // [120:120] is the sub-list for extension extendee
+	0,   // [0:120] is the sub-list for field type_name
 }
 
 func init() { file_proto_gogent_proto_init() }
@@ -4685,13 +8522,17 @@ func file_proto_gogent_proto_init() {
 	if File_proto_gogent_proto != nil {
 		return
 	}
+	file_proto_gogent_proto_msgTypes[21].OneofWrappers = []any{}
+	file_proto_gogent_proto_msgTypes[45].OneofWrappers = []any{}
+	file_proto_gogent_proto_msgTypes[83].OneofWrappers = []any{}
+	file_proto_gogent_proto_msgTypes[102].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_gogent_proto_rawDesc), len(file_proto_gogent_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   64,
+			NumMessages:   106,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -25,11 +25,21 @@ const (
 	GogentService_SaveTemporaryAccount_FullMethodName = "/gogent.GogentService/SaveTemporaryAccount"
 	GogentService_VerifyEmail_FullMethodName          = "/gogent.GogentService/VerifyEmail"
 	GogentService_GetCurrentUser_FullMethodName       = "/gogent.GogentService/GetCurrentUser"
+	GogentService_RefreshToken_FullMethodName         = "/gogent.GogentService/RefreshToken"
+	GogentService_Logout_FullMethodName               = "/gogent.GogentService/Logout"
+	GogentService_RequestPasswordReset_FullMethodName = "/gogent.GogentService/RequestPasswordReset"
+	GogentService_ResetPassword_FullMethodName        = "/gogent.GogentService/ResetPassword"
 	GogentService_Execute_FullMethodName              = "/gogent.GogentService/Execute"
 	GogentService_GetExecutionStatus_FullMethodName   = "/gogent.GogentService/GetExecutionStatus"
 	GogentService_GetExecutionResult_FullMethodName   = "/gogent.GogentService/GetExecutionResult"
 	GogentService_ListExecutionRuns_FullMethodName    = "/gogent.GogentService/ListExecutionRuns"
 	GogentService_DeleteExecutionRun_FullMethodName   = "/gogent.GogentService/DeleteExecutionRun"
+	GogentService_ListComparisons_FullMethodName      = "/gogent.GogentService/ListComparisons"
+	GogentService_GetComparison_FullMethodName        = "/gogent.GogentService/GetComparison"
+	GogentService_ListExecutionLogs_FullMethodName    = "/gogent.GogentService/ListExecutionLogs"
+	GogentService_WatchExecution_FullMethodName       = "/gogent.GogentService/WatchExecution"
+	GogentService_SubmitBatch_FullMethodName          = "/gogent.GogentService/SubmitBatch"
+	GogentService_GetBatchRun_FullMethodName          = "/gogent.GogentService/GetBatchRun"
 	GogentService_ListConfigurations_FullMethodName   = "/gogent.GogentService/ListConfigurations"
 	GogentService_CreateConfiguration_FullMethodName  = "/gogent.GogentService/CreateConfiguration"
 	GogentService_UpdateConfiguration_FullMethodName  = "/gogent.GogentService/UpdateConfiguration"
@@ -59,12 +69,24 @@ type GogentServiceClient interface {
 	SaveTemporaryAccount(ctx context.Context, in *SaveTemporaryAccountRequest, opts ...grpc.CallOption) (*SaveTemporaryAccountResponse, error)
 	VerifyEmail(ctx context.Context, in *VerifyEmailRequest, opts ...grpc.CallOption) (*VerifyEmailResponse, error)
 	GetCurrentUser(ctx context.Context, in *GetCurrentUserRequest, opts ...grpc.CallOption) (*GetCurrentUserResponse, error)
+	RefreshToken(ctx context.Context, in *RefreshTokenRequest, opts ...grpc.CallOption) (*RefreshTokenResponse, error)
+	Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*LogoutResponse, error)
+	RequestPasswordReset(ctx context.Context, in *RequestPasswordResetRequest, opts ...grpc.CallOption) (*RequestPasswordResetResponse, error)
+	ResetPassword(ctx context.Context, in *ResetPasswordRequest, opts ...grpc.CallOption) (*ResetPasswordResponse, error)
 	// Execution Management
 	Execute(ctx context.Context, in *ExecuteRequest, opts ...grpc.CallOption) (*ExecuteResponse, error)
 	GetExecutionStatus(ctx context.Context, in *GetExecutionStatusRequest, opts ...grpc.CallOption) (*GetExecutionStatusResponse, error)
 	GetExecutionResult(ctx context.Context, in *GetExecutionResultRequest, opts ...grpc.CallOption) (*GetExecutionResultResponse, error)
 	ListExecutionRuns(ctx context.Context, in *ListExecutionRunsRequest, opts ...grpc.CallOption) (*ListExecutionRunsResponse, error)
 	DeleteExecutionRun(ctx context.Context, in *DeleteExecutionRunRequest, opts ...grpc.CallOption) (*DeleteExecutionRunResponse, error)
+	ListComparisons(ctx context.Context, in *ListComparisonsRequest, opts ...grpc.CallOption) (*ListComparisonsResponse, error)
+	GetComparison(ctx context.Context, in *GetComparisonRequest, opts ...grpc.CallOption) (*GetComparisonResponse, error)
+	ListExecutionLogs(ctx context.Context, in *ListExecutionLogsRequest, opts ...grpc.CallOption) (*ListExecutionLogsResponse, error)
+	// Streams an execution's progress and logs until it completes or fails (gRPC only)
+	WatchExecution(ctx context.Context, in *WatchExecutionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExecutionEvent], error)
+	// Batch Submission (SubmitBatch is gRPC only)
+	SubmitBatch(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SubmitBatchRequest, SubmitBatchAck], error)
+	GetBatchRun(ctx context.Context, in *GetBatchRunRequest, opts ...grpc.CallOption) (*GetBatchRunResponse, error)
 	// Configuration Management
 	ListConfigurations(ctx context.Context, in *ListConfigurationsRequest, opts ...grpc.CallOption) (*ListConfigurationsResponse, error)
 	CreateConfiguration(ctx context.Context, in *CreateConfigurationRequest, opts ...grpc.CallOption) (*CreateConfigurationResponse, error)
@@ -153,6 +175,46 @@ func (c *gogentServiceClient) GetCurrentUser(ctx context.Context, in *GetCurrent
 	return out, nil
 }
 
+func (c *gogentServiceClient) RefreshToken(ctx context.Context, in *RefreshTokenRequest, opts ...grpc.CallOption) (*RefreshTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RefreshTokenResponse)
+	err := c.cc.Invoke(ctx, GogentService_RefreshToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gogentServiceClient) Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*LogoutResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LogoutResponse)
+	err := c.cc.Invoke(ctx, GogentService_Logout_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gogentServiceClient) RequestPasswordReset(ctx context.Context, in *RequestPasswordResetRequest, opts ...grpc.CallOption) (*RequestPasswordResetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RequestPasswordResetResponse)
+	err := c.cc.Invoke(ctx, GogentService_RequestPasswordReset_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gogentServiceClient) ResetPassword(ctx context.Context, in *ResetPasswordRequest, opts ...grpc.CallOption) (*ResetPasswordResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResetPasswordResponse)
+	err := c.cc.Invoke(ctx, GogentService_ResetPassword_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *gogentServiceClient) Execute(ctx context.Context, in *ExecuteRequest, opts ...grpc.CallOption) (*ExecuteResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ExecuteResponse)
@@ -203,6 +265,78 @@ func (c *gogentServiceClient) DeleteExecutionRun(ctx context.Context, in *Delete
 	return out, nil
 }
 
+func (c *gogentServiceClient) ListComparisons(ctx context.Context, in *ListComparisonsRequest, opts ...grpc.CallOption) (*ListComparisonsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListComparisonsResponse)
+	err := c.cc.Invoke(ctx, GogentService_ListComparisons_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gogentServiceClient) GetComparison(ctx context.Context, in *GetComparisonRequest, opts ...grpc.CallOption) (*GetComparisonResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetComparisonResponse)
+	err := c.cc.Invoke(ctx, GogentService_GetComparison_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gogentServiceClient) ListExecutionLogs(ctx context.Context, in *ListExecutionLogsRequest, opts ...grpc.CallOption) (*ListExecutionLogsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListExecutionLogsResponse)
+	err := c.cc.Invoke(ctx, GogentService_ListExecutionLogs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gogentServiceClient) WatchExecution(ctx context.Context, in *WatchExecutionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExecutionEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &GogentService_ServiceDesc.Streams[0], GogentService_WatchExecution_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchExecutionRequest, ExecutionEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GogentService_WatchExecutionClient = grpc.ServerStreamingClient[ExecutionEvent]
+
+func (c *gogentServiceClient) SubmitBatch(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SubmitBatchRequest, SubmitBatchAck], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &GogentService_ServiceDesc.Streams[1], GogentService_SubmitBatch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubmitBatchRequest, SubmitBatchAck]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GogentService_SubmitBatchClient = grpc.BidiStreamingClient[SubmitBatchRequest, SubmitBatchAck]
+
+func (c *gogentServiceClient) GetBatchRun(ctx context.Context, in *GetBatchRunRequest, opts ...grpc.CallOption) (*GetBatchRunResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetBatchRunResponse)
+	err := c.cc.Invoke(ctx, GogentService_GetBatchRun_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *gogentServiceClient) ListConfigurations(ctx context.Context, in *ListConfigurationsRequest, opts ...grpc.CallOption) (*ListConfigurationsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListConfigurationsResponse)
@@ -356,12 +490,24 @@ type GogentServiceServer interface {
 	SaveTemporaryAccount(context.Context, *SaveTemporaryAccountRequest) (*SaveTemporaryAccountResponse, error)
 	VerifyEmail(context.Context, *VerifyEmailRequest) (*VerifyEmailResponse, error)
 	GetCurrentUser(context.Context, *GetCurrentUserRequest) (*GetCurrentUserResponse, error)
+	RefreshToken(context.Context, *RefreshTokenRequest) (*RefreshTokenResponse, error)
+	Logout(context.Context, *LogoutRequest) (*LogoutResponse, error)
+	RequestPasswordReset(context.Context, *RequestPasswordResetRequest) (*RequestPasswordResetResponse, error)
+	ResetPassword(context.Context, *ResetPasswordRequest) (*ResetPasswordResponse, error)
 	// Execution Management
 	Execute(context.Context, *ExecuteRequest) (*ExecuteResponse, error)
 	GetExecutionStatus(context.Context, *GetExecutionStatusRequest) (*GetExecutionStatusResponse, error)
 	GetExecutionResult(context.Context, *GetExecutionResultRequest) (*GetExecutionResultResponse, error)
 	ListExecutionRuns(context.Context, *ListExecutionRunsRequest) (*ListExecutionRunsResponse, error)
 	DeleteExecutionRun(context.Context, *DeleteExecutionRunRequest) (*DeleteExecutionRunResponse, error)
+	ListComparisons(context.Context, *ListComparisonsRequest) (*ListComparisonsResponse, error)
+	GetComparison(context.Context, *GetComparisonRequest) (*GetComparisonResponse, error)
+	ListExecutionLogs(context.Context, *ListExecutionLogsRequest) (*ListExecutionLogsResponse, error)
+	// Streams an execution's progress and logs until it completes or fails (gRPC only)
+	WatchExecution(*WatchExecutionRequest, grpc.ServerStreamingServer[ExecutionEvent]) error
+	// Batch Submission (SubmitBatch is gRPC only)
+	SubmitBatch(grpc.BidiStreamingServer[SubmitBatchRequest, SubmitBatchAck]) error
+	GetBatchRun(context.Context, *GetBatchRunRequest) (*GetBatchRunResponse, error)
 	// Configuration Management
 	ListConfigurations(context.Context, *ListConfigurationsRequest) (*ListConfigurationsResponse, error)
 	CreateConfiguration(context.Context, *CreateConfigurationRequest) (*CreateConfigurationResponse, error)
@@ -408,6 +554,18 @@ func (UnimplementedGogentServiceServer) VerifyEmail(context.Context, *VerifyEmai
 func (UnimplementedGogentServiceServer) GetCurrentUser(context.Context, *GetCurrentUserRequest) (*GetCurrentUserResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetCurrentUser not implemented")
 }
+func (UnimplementedGogentServiceServer) RefreshToken(context.Context, *RefreshTokenRequest) (*RefreshTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefreshToken not implemented")
+}
+func (UnimplementedGogentServiceServer) Logout(context.Context, *LogoutRequest) (*LogoutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Logout not implemented")
+}
+func (UnimplementedGogentServiceServer) RequestPasswordReset(context.Context, *RequestPasswordResetRequest) (*RequestPasswordResetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestPasswordReset not implemented")
+}
+func (UnimplementedGogentServiceServer) ResetPassword(context.Context, *ResetPasswordRequest) (*ResetPasswordResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetPassword not implemented")
+}
 func (UnimplementedGogentServiceServer) Execute(context.Context, *ExecuteRequest) (*ExecuteResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Execute not implemented")
 }
@@ -423,6 +581,24 @@ func (UnimplementedGogentServiceServer) ListExecutionRuns(context.Context, *List
 func (UnimplementedGogentServiceServer) DeleteExecutionRun(context.Context, *DeleteExecutionRunRequest) (*DeleteExecutionRunResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteExecutionRun not implemented")
 }
+func (UnimplementedGogentServiceServer) ListComparisons(context.Context, *ListComparisonsRequest) (*ListComparisonsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListComparisons not implemented")
+}
+func (UnimplementedGogentServiceServer) GetComparison(context.Context, *GetComparisonRequest) (*GetComparisonResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetComparison not implemented")
+}
+func (UnimplementedGogentServiceServer) ListExecutionLogs(context.Context, *ListExecutionLogsRequest) (*ListExecutionLogsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListExecutionLogs not implemented")
+}
+func (UnimplementedGogentServiceServer) WatchExecution(*WatchExecutionRequest, grpc.ServerStreamingServer[ExecutionEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchExecution not implemented")
+}
+func (UnimplementedGogentServiceServer) SubmitBatch(grpc.BidiStreamingServer[SubmitBatchRequest, SubmitBatchAck]) error {
+	return status.Errorf(codes.Unimplemented, "method SubmitBatch not implemented")
+}
+func (UnimplementedGogentServiceServer) GetBatchRun(context.Context, *GetBatchRunRequest) (*GetBatchRunResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBatchRun not implemented")
+}
 func (UnimplementedGogentServiceServer) ListConfigurations(context.Context, *ListConfigurationsRequest) (*ListConfigurationsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListConfigurations not implemented")
 }
@@ -594,6 +770,78 @@ func _GogentService_GetCurrentUser_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GogentService_RefreshToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GogentServiceServer).RefreshToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GogentService_RefreshToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GogentServiceServer).RefreshToken(ctx, req.(*RefreshTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GogentService_Logout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GogentServiceServer).Logout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GogentService_Logout_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GogentServiceServer).Logout(ctx, req.(*LogoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GogentService_RequestPasswordReset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestPasswordResetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GogentServiceServer).RequestPasswordReset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GogentService_RequestPasswordReset_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GogentServiceServer).RequestPasswordReset(ctx, req.(*RequestPasswordResetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GogentService_ResetPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetPasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GogentServiceServer).ResetPassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GogentService_ResetPassword_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GogentServiceServer).ResetPassword(ctx, req.(*ResetPasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _GogentService_Execute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ExecuteRequest)
 	if err := dec(in); err != nil {
@@ -684,6 +932,96 @@ func _GogentService_DeleteExecutionRun_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GogentService_ListComparisons_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListComparisonsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GogentServiceServer).ListComparisons(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GogentService_ListComparisons_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GogentServiceServer).ListComparisons(ctx, req.(*ListComparisonsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GogentService_GetComparison_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetComparisonRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GogentServiceServer).GetComparison(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GogentService_GetComparison_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GogentServiceServer).GetComparison(ctx, req.(*GetComparisonRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GogentService_ListExecutionLogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListExecutionLogsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GogentServiceServer).ListExecutionLogs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GogentService_ListExecutionLogs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GogentServiceServer).ListExecutionLogs(ctx, req.(*ListExecutionLogsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GogentService_WatchExecution_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchExecutionRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GogentServiceServer).WatchExecution(m, &grpc.GenericServerStream[WatchExecutionRequest, ExecutionEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GogentService_WatchExecutionServer = grpc.ServerStreamingServer[ExecutionEvent]
+
+func _GogentService_SubmitBatch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(GogentServiceServer).SubmitBatch(&grpc.GenericServerStream[SubmitBatchRequest, SubmitBatchAck]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GogentService_SubmitBatchServer = grpc.BidiStreamingServer[SubmitBatchRequest, SubmitBatchAck]
+
+func _GogentService_GetBatchRun_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBatchRunRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GogentServiceServer).GetBatchRun(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GogentService_GetBatchRun_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GogentServiceServer).GetBatchRun(ctx, req.(*GetBatchRunRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _GogentService_ListConfigurations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListConfigurationsRequest)
 	if err := dec(in); err != nil {
@@ -967,6 +1305,22 @@ var GogentService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetCurrentUser",
 			Handler:    _GogentService_GetCurrentUser_Handler,
 		},
+		{
+			MethodName: "RefreshToken",
+			Handler:    _GogentService_RefreshToken_Handler,
+		},
+		{
+			MethodName: "Logout",
+			Handler:    _GogentService_Logout_Handler,
+		},
+		{
+			MethodName: "RequestPasswordReset",
+			Handler:    _GogentService_RequestPasswordReset_Handler,
+		},
+		{
+			MethodName: "ResetPassword",
+			Handler:    _GogentService_ResetPassword_Handler,
+		},
 		{
 			MethodName: "Execute",
 			Handler:    _GogentService_Execute_Handler,
@@ -987,6 +1341,22 @@ var GogentService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteExecutionRun",
 			Handler:    _GogentService_DeleteExecutionRun_Handler,
 		},
+		{
+			MethodName: "ListComparisons",
+			Handler:    _GogentService_ListComparisons_Handler,
+		},
+		{
+			MethodName: "GetComparison",
+			Handler:    _GogentService_GetComparison_Handler,
+		},
+		{
+			MethodName: "ListExecutionLogs",
+			Handler:    _GogentService_ListExecutionLogs_Handler,
+		},
+		{
+			MethodName: "GetBatchRun",
+			Handler:    _GogentService_GetBatchRun_Handler,
+		},
 		{
 			MethodName: "ListConfigurations",
 			Handler:    _GogentService_ListConfigurations_Handler,
@@ -1044,6 +1414,18 @@ var GogentService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _GogentService_Health_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchExecution",
+			Handler:       _GogentService_WatchExecution_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubmitBatch",
+			Handler:       _GogentService_SubmitBatch_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
 	Metadata: "proto/gogent.proto",
 }
@@ -23,13 +23,17 @@ INSERT INTO function_definitions (
         'properties', JSON_OBJECT(
             'query', JSON_OBJECT(
                 'type', 'string',
-                'description', 'Cypher query to execute against the Neo4j database. Use MATCH clauses to find patterns and RETURN to specify what data to retrieve.',
+                'description', 'Read-only Cypher query to execute against the Neo4j database. Use MATCH clauses to find patterns and RETURN to specify what data to retrieve. Write clauses such as CREATE, SET, DELETE and MERGE are rejected. Pass values as $parameters.',
                 'examples', JSON_ARRAY(
                     'MATCH (n:Person) RETURN n.name LIMIT 10',
                     'MATCH (p:Person)-[:WORKS_FOR]->(c:Company) RETURN p.name, c.name',
-                    'MATCH (start:Location {name: "New York"})-[:CONNECTED_TO*1..3]-(end:Location) RETURN DISTINCT end.name'
+                    'MATCH (start:Location {name: $city})-[:CONNECTED_TO*1..3]-(end:Location) RETURN DISTINCT end.name'
                 )
             ),
+            'parameters', JSON_OBJECT(
+                'type', 'object',
+                'description', 'Values for the $parameters used in the query, e.g. {"city": "New York"}'
+            ),
             'limit', JSON_OBJECT(
                 'type', 'integer',
                 'description', 'Maximum number of results to return (1-100)',
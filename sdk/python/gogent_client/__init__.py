@@ -0,0 +1,9 @@
+"""Typed client for the GoGent REST API.
+
+The API classes and models are generated into gogent_api by `make generate-sdk-python`; GogentClient
+adds polling for queued executions and tailing of execution logs on top of them.
+"""
+
+from gogent_client.client import ExecutionFailedError, GogentClient
+
+__all__ = ["ExecutionFailedError", "GogentClient"]
@@ -0,0 +1,109 @@
+"""Polling and log tailing on top of the generated GoGent API classes."""
+
+import time
+from typing import Iterator, Optional
+
+from gogent_api import ApiClient, Configuration, ExecutionsApi, FunctionsApi
+
+TERMINAL_STATUSES = {"completed", "failed", "not_found"}
+
+
+class ExecutionFailedError(Exception):
+    """Raised when an execution fails or the server no longer knows it."""
+
+    def __init__(self, execution_id: str, status):
+        self.execution_id = execution_id
+        self.status = status
+        message = f"execution {execution_id} {status.status}"
+        if getattr(status, "error", None):
+            message += f": {status.error}"
+        super().__init__(message)
+
+
+class GogentClient:
+    """Wraps the generated API classes.
+
+    Pass access_token (from /api/auth/login) or api_key (sent as "Authorization: ApiKey <key>").
+    """
+
+    def __init__(self, base_url: str = "http://localhost:8080", access_token: Optional[str] = None,
+                 api_key: Optional[str] = None):
+        configuration = Configuration(host=base_url, access_token=access_token)
+        if api_key:
+            configuration.api_key["apiKey"] = api_key
+            configuration.api_key_prefix["apiKey"] = "ApiKey"
+        self.api_client = ApiClient(configuration)
+        self.executions = ExecutionsApi(self.api_client)
+        self.functions = FunctionsApi(self.api_client)
+
+    def execute(self, request) -> str:
+        """Queues a MultiExecutionRequest and returns its execution ID."""
+        submission = self.executions.post_execute(request)
+        if submission.execution_run is None or not submission.execution_run.id:
+            raise RuntimeError("the server did not return an execution ID")
+        return submission.execution_run.id
+
+    def execute_and_wait(self, request, interval: float = 1.0, timeout: Optional[float] = 600):
+        """Queues an execution and returns its ExecutionResult once it completes."""
+        return self.wait_for_result(self.execute(request), interval=interval, timeout=timeout)
+
+    def watch_execution(self, execution_id: str, interval: float = 1.0,
+                        timeout: Optional[float] = 600) -> Iterator:
+        """Yields an execution's status each time it changes, ending with its final status.
+
+        A timeout of None waits forever.
+        """
+        deadline = _deadline(timeout)
+        last = None
+        while True:
+            status = self.executions.get_execution_runs_status_by_id(execution_id)
+            key = (status.status, status.queue_position)
+            if key != last:
+                last = key
+                yield status
+            if status.status in TERMINAL_STATUSES:
+                return
+            _sleep(interval, deadline, f"execution {execution_id}")
+
+    def wait_for_result(self, execution_id: str, interval: float = 1.0, timeout: Optional[float] = 600):
+        """Returns an execution's ExecutionResult once it completes, and raises if it fails."""
+        for status in self.watch_execution(execution_id, interval=interval, timeout=timeout):
+            if status.status == "completed" and status.result is not None:
+                return status.result
+            if status.status in TERMINAL_STATUSES:
+                raise ExecutionFailedError(execution_id, status)
+        raise RuntimeError(f"execution {execution_id} ended without a status")
+
+    def stream_logs(self, run_id: str, level: Optional[str] = None, interval: float = 1.0,
+                    timeout: Optional[float] = 600) -> Iterator:
+        """Yields a run's ExecutionLog entries as they are written, until the run finishes.
+
+        Runs are identified by their result's execution_run.id.
+        """
+        deadline = _deadline(timeout)
+        after = None
+        while True:
+            # Read the run's status before its logs, so a run that finishes in between is drained once more
+            run = self.executions.get_execution_runs_by_id(run_id)
+            finished = run.execution_run is not None and run.execution_run.status in ("completed", "failed")
+
+            while True:
+                logs = self.executions.get_execution_runs_by_id_logs(run_id, after=after, level=level)
+                for entry in logs:
+                    yield entry
+                if not logs or not logs[-1].id:
+                    break
+                after = logs[-1].id
+            if finished:
+                return
+            _sleep(interval, deadline, f"run {run_id}")
+
+
+def _deadline(timeout: Optional[float]) -> Optional[float]:
+    return None if timeout is None else time.monotonic() + timeout
+
+
+def _sleep(interval: float, deadline: Optional[float], what: str) -> None:
+    if deadline is not None and time.monotonic() + interval > deadline:
+        raise TimeoutError(f"timed out waiting for {what}")
+    time.sleep(interval)
@@ -0,0 +1,160 @@
+import {
+  Configuration,
+  ExecutionLog,
+  ExecutionResult,
+  ExecutionsApi,
+  FunctionsApi,
+  MultiExecutionRequest,
+} from "./generated";
+
+export interface GogentClientOptions {
+  // Base URL of the server, e.g. http://localhost:8080
+  basePath?: string;
+  // Access token from /api/auth/login
+  accessToken?: string;
+  // API key, sent as "Authorization: ApiKey <key>"
+  apiKey?: string;
+}
+
+export interface PollOptions {
+  // Time between polls; 1 second by default
+  intervalMs?: number;
+  // Give up after this long; 10 minutes by default, 0 to wait forever
+  timeoutMs?: number;
+  signal?: AbortSignal;
+}
+
+// A status poll of a queued execution
+export interface ExecutionStatus {
+  status: string; // pending, running, completed, failed or not_found
+  queuePosition?: number;
+  error?: string;
+  result?: ExecutionResult;
+}
+
+// Thrown when an execution fails or the server no longer knows it
+export class ExecutionFailedError extends Error {
+  constructor(readonly executionId: string, readonly status: ExecutionStatus) {
+    super(`execution ${executionId} ${status.status}${status.error ? `: ${status.error}` : ""}`);
+    this.name = "ExecutionFailedError";
+  }
+}
+
+const terminalStatuses = new Set(["completed", "failed", "not_found"]);
+
+// GogentClient wraps the generated API classes with polling for queued executions and tailing of
+// execution logs
+export class GogentClient {
+  readonly executions: ExecutionsApi;
+  readonly functions: FunctionsApi;
+
+  constructor(options: GogentClientOptions = {}) {
+    const configuration = new Configuration({
+      basePath: options.basePath ?? "http://localhost:8080",
+      accessToken: options.accessToken,
+      apiKey: options.apiKey ? () => `ApiKey ${options.apiKey}` : undefined,
+    });
+    this.executions = new ExecutionsApi(configuration);
+    this.functions = new FunctionsApi(configuration);
+  }
+
+  // Queues an execution and returns its ID, for watchExecution and waitForResult
+  async execute(request: MultiExecutionRequest): Promise<string> {
+    const submission = await this.executions.postExecute({ multiExecutionRequest: request });
+    const id = submission.executionRun?.id;
+    if (!id) {
+      throw new Error("the server did not return an execution ID");
+    }
+    return id;
+  }
+
+  // Queues an execution and resolves with its result once it completes
+  async executeAndWait(request: MultiExecutionRequest, options: PollOptions = {}): Promise<ExecutionResult> {
+    return this.waitForResult(await this.execute(request), options);
+  }
+
+  // Yields an execution's status each time it changes, ending with its final status
+  async *watchExecution(executionId: string, options: PollOptions = {}): AsyncGenerator<ExecutionStatus> {
+    const deadline = pollDeadline(options);
+    let last = "";
+    for (;;) {
+      const status = (await this.executions.getExecutionRunsStatusById(
+        { id: executionId },
+        { signal: options.signal },
+      )) as ExecutionStatus;
+      const key = `${status.status}:${status.queuePosition ?? ""}`;
+      if (key !== last) {
+        last = key;
+        yield status;
+      }
+      if (terminalStatuses.has(status.status)) {
+        return;
+      }
+      await sleep(options, deadline, `execution ${executionId}`);
+    }
+  }
+
+  // Resolves with an execution's result once it completes, and rejects if it fails
+  async waitForResult(executionId: string, options: PollOptions = {}): Promise<ExecutionResult> {
+    for await (const status of this.watchExecution(executionId, options)) {
+      if (status.status === "completed" && status.result) {
+        return status.result;
+      }
+      if (terminalStatuses.has(status.status)) {
+        throw new ExecutionFailedError(executionId, status);
+      }
+    }
+    throw new ExecutionFailedError(executionId, { status: "not_found" });
+  }
+
+  // Yields a run's log entries as they are written, until the run finishes. Runs are identified by
+  // their result's executionRun.id.
+  async *streamLogs(runId: string, options: PollOptions & { level?: string } = {}): AsyncGenerator<ExecutionLog> {
+    const deadline = pollDeadline(options);
+    let after: string | undefined;
+    for (;;) {
+      // Read the run's status before its logs, so a run that finishes in between is drained once more
+      const run = await this.executions.getExecutionRunsById({ id: runId }, { signal: options.signal });
+      const status = run.executionRun?.status;
+      const finished = status === "completed" || status === "failed";
+
+      for (;;) {
+        const logs = await this.executions.getExecutionRunsByIdLogs(
+          { id: runId, after, level: options.level },
+          { signal: options.signal },
+        );
+        for (const entry of logs) {
+          yield entry;
+        }
+        const last = logs[logs.length - 1]?.id;
+        if (!last) {
+          break;
+        }
+        after = last;
+      }
+      if (finished) {
+        return;
+      }
+      await sleep(options, deadline, `run ${runId}`);
+    }
+  }
+}
+
+function pollDeadline(options: PollOptions): number {
+  const timeoutMs = options.timeoutMs ?? 10 * 60 * 1000;
+  return timeoutMs > 0 ? Date.now() + timeoutMs : Infinity;
+}
+
+function sleep(options: PollOptions, deadline: number, what: string): Promise<void> {
+  const intervalMs = options.intervalMs ?? 1000;
+  if (Date.now() + intervalMs > deadline) {
+    return Promise.reject(new Error(`timed out waiting for ${what}`));
+  }
+  return new Promise((resolve, reject) => {
+    const timer = setTimeout(resolve, intervalMs);
+    options.signal?.addEventListener("abort", () => {
+      clearTimeout(timer);
+      reject(options.signal?.reason ?? new Error("aborted"));
+    }, { once: true });
+  });
+}
@@ -0,0 +1,4 @@
+// The generated API classes and models, and the handwritten client on top of them
+export * from "./generated";
+export { GogentClient, ExecutionFailedError } from "./client";
+export type { GogentClientOptions, PollOptions } from "./client";
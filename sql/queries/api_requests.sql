@@ -1,8 +1,9 @@
 -- name: CreateAPIRequest :exec
 INSERT INTO api_requests (
     id, user_id, execution_run_id, configuration_id, request_type, prompt,
-    context, function_name, function_parameters, request_headers, request_body
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+    context, function_name, function_parameters, request_headers, request_body,
+    system_prompt_mode, redaction
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 
 -- name: GetAPIRequest :one
 SELECT * FROM api_requests
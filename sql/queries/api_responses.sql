@@ -2,8 +2,10 @@
 INSERT INTO api_responses (
     id, user_id, request_id, response_status, response_text, function_call_response,
     usage_metadata, safety_ratings, finish_reason, error_message,
-    response_time_ms, response_headers, response_body
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+    response_time_ms, response_headers, response_body, redaction,
+    prompt_tokens, completion_tokens, total_tokens, latency_breakdown, thought_summary,
+    candidate_index
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 
 -- name: GetAPIResponse :one
 SELECT * FROM api_responses
@@ -11,7 +13,7 @@ WHERE id = ? AND user_id = ?;
 
 -- name: GetAPIResponseByRequest :one
 SELECT * FROM api_responses
-WHERE request_id = ? AND user_id = ?;
+WHERE request_id = ? AND user_id = ? AND candidate_index = 0;
 
 -- name: GetAPIResponsesByStatus :many
 SELECT * FROM api_responses
@@ -21,7 +23,7 @@ LIMIT ? OFFSET ?;
 
 -- name: GetAPIResponsesByTimeRange :many
 SELECT * FROM api_responses
-WHERE created_at BETWEEN ? AND ?
+WHERE created_at BETWEEN ? AND ? AND user_id = ?
 ORDER BY created_at DESC;
 
 -- name: GetAPIResponsesWithRequests :many
@@ -29,11 +31,12 @@ SELECT
     r.id, r.user_id, r.request_id, r.response_status, r.response_text,
     r.function_call_response, r.usage_metadata, r.safety_ratings,
     r.finish_reason, r.error_message, r.response_time_ms,
-    r.response_headers, r.response_body, r.created_at
+    r.response_headers, r.response_body, r.latency_breakdown, r.thought_summary,
+    r.candidate_index, r.created_at
 FROM api_responses r
 JOIN api_requests req ON r.request_id = req.id
 WHERE req.execution_run_id = ? AND r.user_id = ?
-ORDER BY r.created_at;
+ORDER BY r.created_at, r.candidate_index;
 
 -- name: ListAPIResponses :many
 SELECT * FROM api_responses
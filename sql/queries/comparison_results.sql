@@ -9,32 +9,43 @@ INSERT INTO comparison_results (
 
 -- name: GetComparisonResult :one
 SELECT 
-    id, execution_run_id, comparison_type, metric_name,
-    configuration_scores, best_configuration_id, 
-    CAST(best_configuration_data AS CHAR) as best_configuration_data,
-    CAST(all_configurations_data AS CHAR) as all_configurations_data, 
-    analysis_notes, created_at
-FROM comparison_results 
-WHERE execution_run_id = ? 
+    cr.id, cr.execution_run_id, cr.comparison_type, cr.metric_name,
+    cr.configuration_scores, cr.best_configuration_id, 
+    CAST(cr.best_configuration_data AS CHAR) as best_configuration_data,
+    CAST(cr.all_configurations_data AS CHAR) as all_configurations_data, 
+    cr.analysis_notes, cr.created_at
+FROM comparison_results cr
+JOIN execution_runs er ON cr.execution_run_id = er.id
+WHERE cr.execution_run_id = ? AND er.user_id = ?
 LIMIT 1;
 
 -- name: ListComparisonResults :many
 SELECT 
-    id, execution_run_id, comparison_type, metric_name,
-    configuration_scores, best_configuration_id, 
-    CAST(best_configuration_data AS CHAR) as best_configuration_data,
-    CAST(all_configurations_data AS CHAR) as all_configurations_data,
-    analysis_notes, created_at
-FROM comparison_results 
-ORDER BY created_at DESC;
+    cr.id, cr.execution_run_id, cr.comparison_type, cr.metric_name,
+    cr.configuration_scores, cr.best_configuration_id, 
+    CAST(cr.best_configuration_data AS CHAR) as best_configuration_data,
+    CAST(cr.all_configurations_data AS CHAR) as all_configurations_data,
+    cr.analysis_notes, cr.created_at
+FROM comparison_results cr
+JOIN execution_runs er ON cr.execution_run_id = er.id
+WHERE er.user_id = ?
+ORDER BY cr.created_at DESC
+LIMIT ? OFFSET ?;
+
+-- name: CountComparisonResultsByUser :one
+SELECT COUNT(*)
+FROM comparison_results cr
+JOIN execution_runs er ON cr.execution_run_id = er.id
+WHERE er.user_id = ?;
 
 -- name: GetComparisonResultsByExecutionRun :many
 SELECT 
-    id, execution_run_id, comparison_type, metric_name,
-    configuration_scores, best_configuration_id, 
-    CAST(best_configuration_data AS CHAR) as best_configuration_data,
-    CAST(all_configurations_data AS CHAR) as all_configurations_data,
-    analysis_notes, created_at
-FROM comparison_results 
-WHERE execution_run_id = ?
-ORDER BY created_at DESC; 
\ No newline at end of file
+    cr.id, cr.execution_run_id, cr.comparison_type, cr.metric_name,
+    cr.configuration_scores, cr.best_configuration_id, 
+    CAST(cr.best_configuration_data AS CHAR) as best_configuration_data,
+    CAST(cr.all_configurations_data AS CHAR) as all_configurations_data,
+    cr.analysis_notes, cr.created_at
+FROM comparison_results cr
+JOIN execution_runs er ON cr.execution_run_id = er.id
+WHERE cr.execution_run_id = ? AND er.user_id = ?
+ORDER BY cr.created_at DESC; 
\ No newline at end of file
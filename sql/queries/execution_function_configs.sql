@@ -6,30 +6,30 @@ INSERT INTO execution_function_configs (
 ) VALUES (?, ?, ?, ?, ?, ?);
 
 -- name: GetExecutionFunctionConfig :one
-SELECT * FROM execution_function_configs WHERE id = ?;
+SELECT * FROM execution_function_configs WHERE id = ? AND user_id = ?;
 
 -- name: ListExecutionFunctionConfigs :many
 SELECT efc.*, fd.name, fd.display_name, fd.description
 FROM execution_function_configs efc
 JOIN function_definitions fd ON efc.function_definition_id = fd.id
-WHERE efc.execution_run_id = ?
+WHERE efc.execution_run_id = ? AND efc.user_id = ?
 ORDER BY efc.execution_order ASC, fd.display_name ASC;
 
 -- name: UpdateExecutionFunctionConfig :exec
 UPDATE execution_function_configs 
 SET use_mock_response = ?, execution_order = ?
-WHERE id = ?;
+WHERE id = ? AND user_id = ?;
 
 -- name: DeleteExecutionFunctionConfig :exec
 DELETE FROM execution_function_configs 
-WHERE execution_run_id = ? AND function_definition_id = ?;
+WHERE execution_run_id = ? AND function_definition_id = ? AND user_id = ?;
 
 -- name: DeleteAllExecutionFunctionConfigs :exec
-DELETE FROM execution_function_configs WHERE execution_run_id = ?;
+DELETE FROM execution_function_configs WHERE execution_run_id = ? AND user_id = ?;
 
 -- name: CountExecutionFunctions :one
-SELECT COUNT(*) FROM execution_function_configs WHERE execution_run_id = ?;
+SELECT COUNT(*) FROM execution_function_configs WHERE execution_run_id = ? AND user_id = ?;
 
 -- name: CheckExecutionFunctionExists :one
 SELECT COUNT(*) FROM execution_function_configs 
-WHERE execution_run_id = ? AND function_definition_id = ?; 
\ No newline at end of file
+WHERE execution_run_id = ? AND function_definition_id = ? AND user_id = ?;
\ No newline at end of file
@@ -8,29 +8,35 @@ INSERT INTO execution_logs (
 
 -- name: GetExecutionLogsByRun :many
 SELECT 
-    id, execution_run_id, configuration_id, request_id,
-    log_level, log_category, message, 
-    COALESCE(details, JSON_OBJECT()) as details,
-    timestamp
-FROM execution_logs 
-WHERE execution_run_id = ?
-ORDER BY timestamp ASC;
+    l.id, l.execution_run_id, l.configuration_id, l.request_id,
+    l.log_level, l.log_category, l.message, 
+    COALESCE(l.details, JSON_OBJECT()) as details,
+    l.timestamp
+FROM execution_logs l
+JOIN execution_runs er ON l.execution_run_id = er.id
+WHERE l.execution_run_id = ? AND er.user_id = ?
+ORDER BY l.timestamp ASC;
 
 -- name: GetExecutionLogsByConfiguration :many
-SELECT * FROM execution_logs 
-WHERE execution_run_id = ? AND configuration_id = ?
-ORDER BY timestamp ASC;
+SELECT l.* FROM execution_logs l
+JOIN execution_runs er ON l.execution_run_id = er.id
+WHERE l.execution_run_id = ? AND l.configuration_id = ? AND er.user_id = ?
+ORDER BY l.timestamp ASC;
 
 -- name: GetExecutionLogsByRequest :many
-SELECT * FROM execution_logs 
-WHERE execution_run_id = ? AND request_id = ?
-ORDER BY timestamp ASC;
+SELECT l.* FROM execution_logs l
+JOIN execution_runs er ON l.execution_run_id = er.id
+WHERE l.execution_run_id = ? AND l.request_id = ? AND er.user_id = ?
+ORDER BY l.timestamp ASC;
 
 -- name: DeleteExecutionLogsByRun :exec
-DELETE FROM execution_logs WHERE execution_run_id = ?;
+DELETE l FROM execution_logs l
+JOIN execution_runs er ON l.execution_run_id = er.id
+WHERE l.execution_run_id = ? AND er.user_id = ?;
 
 -- name: CountExecutionLogsByLevel :one
-SELECT log_level, COUNT(*) as count
-FROM execution_logs 
-WHERE execution_run_id = ?
-GROUP BY log_level; 
\ No newline at end of file
+SELECT l.log_level, COUNT(*) as count
+FROM execution_logs l
+JOIN execution_runs er ON l.execution_run_id = er.id
+WHERE l.execution_run_id = ? AND er.user_id = ?
+GROUP BY l.log_level; 
\ No newline at end of file
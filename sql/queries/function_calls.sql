@@ -3,29 +3,33 @@
 -- name: CreateFunctionCall :exec
 INSERT INTO function_calls (
     id, request_id, function_name, function_arguments, function_response,
-    execution_status, execution_time_ms, error_details
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+    execution_status, execution_time_ms, error_details, used_mock_data
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
 
 -- name: GetFunctionCall :one
-SELECT * FROM function_calls WHERE id = ?;
+SELECT fc.* FROM function_calls fc
+JOIN api_requests ar ON fc.request_id = ar.id
+WHERE fc.id = ? AND ar.user_id = ?;
 
 -- name: ListFunctionCallsByRequest :many
-SELECT * FROM function_calls 
-WHERE request_id = ?
-ORDER BY created_at ASC;
+SELECT fc.* FROM function_calls fc
+JOIN api_requests ar ON fc.request_id = ar.id
+WHERE fc.request_id = ? AND ar.user_id = ?
+ORDER BY fc.created_at ASC;
 
 -- name: ListFunctionCallsByExecution :many
 SELECT fc.*, ar.prompt, ar.created_at as request_created_at
 FROM function_calls fc
 JOIN api_requests ar ON fc.request_id = ar.id
-WHERE ar.execution_run_id = ?
+WHERE ar.execution_run_id = ? AND ar.user_id = ?
 ORDER BY fc.created_at DESC;
 
 -- name: UpdateFunctionCall :exec
-UPDATE function_calls 
-SET function_response = ?, execution_status = ?, 
-    execution_time_ms = ?, error_details = ?
-WHERE id = ?;
+UPDATE function_calls fc
+JOIN api_requests ar ON fc.request_id = ar.id
+SET fc.function_response = ?, fc.execution_status = ?, 
+    fc.execution_time_ms = ?, fc.error_details = ?
+WHERE fc.id = ? AND ar.user_id = ?;
 
 -- name: GetFunctionCallStats :one
 SELECT 
@@ -37,14 +41,14 @@ SELECT
     MIN(execution_time_ms) as min_execution_time
 FROM function_calls 
 WHERE request_id IN (
-    SELECT id FROM api_requests WHERE execution_run_id = ?
+    SELECT id FROM api_requests WHERE execution_run_id = ? AND user_id = ?
 );
 
 -- name: GetFunctionCallsByName :many
 SELECT fc.*, ar.execution_run_id, ar.prompt
 FROM function_calls fc
 JOIN api_requests ar ON fc.request_id = ar.id
-WHERE fc.function_name = ?
+WHERE fc.function_name = ? AND ar.user_id = ?
 ORDER BY fc.created_at DESC
 LIMIT ?;
 
@@ -53,8 +57,11 @@ SELECT fc.*, ar.execution_run_id, ar.prompt, er.name as execution_name
 FROM function_calls fc
 JOIN api_requests ar ON fc.request_id = ar.id
 JOIN execution_runs er ON ar.execution_run_id = er.id
+WHERE er.user_id = ?
 ORDER BY fc.created_at DESC
 LIMIT ?;
 
 -- name: DeleteFunctionCallsByRequest :exec
-DELETE FROM function_calls WHERE request_id = ?; 
\ No newline at end of file
+DELETE fc FROM function_calls fc
+JOIN api_requests ar ON fc.request_id = ar.id
+WHERE fc.request_id = ? AND ar.user_id = ?; 
\ No newline at end of file